@@ -353,6 +353,126 @@ func (c *clientImpl) ListDynamicConfig(
 	return c.client.ListDynamicConfig(ctx, request, opts...)
 }
 
+func (c *clientImpl) UnloadTaskList(
+	ctx context.Context,
+	request *types.AdminUnloadTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.UnloadTaskList(ctx, request, opts...)
+}
+
+func (c *clientImpl) GetTaskListDrainStatus(
+	ctx context.Context,
+	request *types.AdminGetTaskListDrainStatusRequest,
+	opts ...yarpc.CallOption,
+) (*types.GetTaskListDrainStatusResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.GetTaskListDrainStatus(ctx, request, opts...)
+}
+
+func (c *clientImpl) PauseTaskList(
+	ctx context.Context,
+	request *types.AdminPauseTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.PauseTaskList(ctx, request, opts...)
+}
+
+func (c *clientImpl) ResumeTaskList(
+	ctx context.Context,
+	request *types.AdminResumeTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.ResumeTaskList(ctx, request, opts...)
+}
+
+func (c *clientImpl) ListDynamicConfigChanges(
+	ctx context.Context,
+	request *types.ListDynamicConfigChangesRequest,
+	opts ...yarpc.CallOption,
+) (*types.ListDynamicConfigChangesResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.ListDynamicConfigChanges(ctx, request, opts...)
+}
+
+func (c *clientImpl) GetDomainUsage(
+	ctx context.Context,
+	request *types.AdminGetDomainUsageRequest,
+	opts ...yarpc.CallOption,
+) (*types.AdminGetDomainUsageResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.GetDomainUsage(ctx, request, opts...)
+}
+
+func (c *clientImpl) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+	opts ...yarpc.CallOption,
+) (*types.InvalidateCachesResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.InvalidateCaches(ctx, request, opts...)
+}
+
+func (c *clientImpl) ListEffectiveDynamicConfig(
+	ctx context.Context,
+	request *types.ListEffectiveDynamicConfigRequest,
+	opts ...yarpc.CallOption,
+) (*types.ListEffectiveDynamicConfigResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.ListEffectiveDynamicConfig(ctx, request, opts...)
+}
+
+func (c *clientImpl) DescribeShardOwnership(
+	ctx context.Context,
+	request *types.DescribeShardOwnershipRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeShardOwnershipResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DescribeShardOwnership(ctx, request, opts...)
+}
+
+func (c *clientImpl) DescribePersistenceLatencyHeatmap(
+	ctx context.Context,
+	request *types.DescribePersistenceLatencyHeatmapRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DescribePersistenceLatencyHeatmap(ctx, request, opts...)
+}
+
+func (c *clientImpl) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeWatchdogResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DescribeWatchdog(ctx, request, opts...)
+}
+
+func (c *clientImpl) DescribeDomainSLO(
+	ctx context.Context,
+	request *types.DescribeDomainSLORequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeDomainSLOResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DescribeDomainSLO(ctx, request, opts...)
+}
+
 func (c *clientImpl) createContext(parent context.Context) (context.Context, context.CancelFunc) {
 	if parent == nil {
 		return context.WithTimeout(context.Background(), c.timeout)
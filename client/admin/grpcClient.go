@@ -177,3 +177,51 @@ func (g grpcClient) ListDynamicConfig(ctx context.Context, request *types.ListDy
 	response, err := g.c.ListDynamicConfig(ctx, proto.FromListDynamicConfigRequest(request), opts...)
 	return proto.ToListDynamicConfigResponse(response), proto.ToError(err)
 }
+
+func (g grpcClient) UnloadTaskList(ctx context.Context, request *types.AdminUnloadTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (g grpcClient) GetTaskListDrainStatus(ctx context.Context, request *types.AdminGetTaskListDrainStatusRequest, opts ...yarpc.CallOption) (*types.GetTaskListDrainStatusResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) PauseTaskList(ctx context.Context, request *types.AdminPauseTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (g grpcClient) ResumeTaskList(ctx context.Context, request *types.AdminResumeTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (g grpcClient) ListDynamicConfigChanges(ctx context.Context, request *types.ListDynamicConfigChangesRequest, opts ...yarpc.CallOption) (*types.ListDynamicConfigChangesResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) GetDomainUsage(ctx context.Context, request *types.AdminGetDomainUsageRequest, opts ...yarpc.CallOption) (*types.AdminGetDomainUsageResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) InvalidateCaches(ctx context.Context, request *types.InvalidateCachesRequest, opts ...yarpc.CallOption) (*types.InvalidateCachesResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) ListEffectiveDynamicConfig(ctx context.Context, request *types.ListEffectiveDynamicConfigRequest, opts ...yarpc.CallOption) (*types.ListEffectiveDynamicConfigResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) DescribeShardOwnership(ctx context.Context, request *types.DescribeShardOwnershipRequest, opts ...yarpc.CallOption) (*types.DescribeShardOwnershipResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) DescribePersistenceLatencyHeatmap(ctx context.Context, request *types.DescribePersistenceLatencyHeatmapRequest, opts ...yarpc.CallOption) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) DescribeWatchdog(ctx context.Context, request *types.DescribeWatchdogRequest, opts ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) DescribeDomainSLO(ctx context.Context, request *types.DescribeDomainSLORequest, opts ...yarpc.CallOption) (*types.DescribeDomainSLOResponse, error) {
+	return nil, errJSONOnly
+}
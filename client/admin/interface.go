@@ -60,4 +60,16 @@ type Client interface {
 	ListDynamicConfig(context.Context, *types.ListDynamicConfigRequest, ...yarpc.CallOption) (*types.ListDynamicConfigResponse, error)
 	DeleteWorkflow(context.Context, *types.AdminDeleteWorkflowRequest, ...yarpc.CallOption) (*types.AdminDeleteWorkflowResponse, error)
 	MaintainCorruptWorkflow(context.Context, *types.AdminMaintainWorkflowRequest, ...yarpc.CallOption) (*types.AdminMaintainWorkflowResponse, error)
+	UnloadTaskList(context.Context, *types.AdminUnloadTaskListRequest, ...yarpc.CallOption) error
+	GetTaskListDrainStatus(context.Context, *types.AdminGetTaskListDrainStatusRequest, ...yarpc.CallOption) (*types.GetTaskListDrainStatusResponse, error)
+	PauseTaskList(context.Context, *types.AdminPauseTaskListRequest, ...yarpc.CallOption) error
+	ResumeTaskList(context.Context, *types.AdminResumeTaskListRequest, ...yarpc.CallOption) error
+	ListDynamicConfigChanges(context.Context, *types.ListDynamicConfigChangesRequest, ...yarpc.CallOption) (*types.ListDynamicConfigChangesResponse, error)
+	GetDomainUsage(context.Context, *types.AdminGetDomainUsageRequest, ...yarpc.CallOption) (*types.AdminGetDomainUsageResponse, error)
+	InvalidateCaches(context.Context, *types.InvalidateCachesRequest, ...yarpc.CallOption) (*types.InvalidateCachesResponse, error)
+	ListEffectiveDynamicConfig(context.Context, *types.ListEffectiveDynamicConfigRequest, ...yarpc.CallOption) (*types.ListEffectiveDynamicConfigResponse, error)
+	DescribeShardOwnership(context.Context, *types.DescribeShardOwnershipRequest, ...yarpc.CallOption) (*types.DescribeShardOwnershipResponse, error)
+	DescribePersistenceLatencyHeatmap(context.Context, *types.DescribePersistenceLatencyHeatmapRequest, ...yarpc.CallOption) (*types.DescribePersistenceLatencyHeatmapResponse, error)
+	DescribeWatchdog(context.Context, *types.DescribeWatchdogRequest, ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error)
+	DescribeDomainSLO(context.Context, *types.DescribeDomainSLORequest, ...yarpc.CallOption) (*types.DescribeDomainSLOResponse, error)
 }
@@ -157,6 +157,26 @@ func (mr *MockClientMockRecorder) DescribeCluster(arg0 interface{}, arg1 ...inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCluster", reflect.TypeOf((*MockClient)(nil).DescribeCluster), varargs...)
 }
 
+// DescribeDomainSLO mocks base method.
+func (m *MockClient) DescribeDomainSLO(arg0 context.Context, arg1 *types.DescribeDomainSLORequest, arg2 ...yarpc.CallOption) (*types.DescribeDomainSLOResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDomainSLO", varargs...)
+	ret0, _ := ret[0].(*types.DescribeDomainSLOResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDomainSLO indicates an expected call of DescribeDomainSLO.
+func (mr *MockClientMockRecorder) DescribeDomainSLO(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDomainSLO", reflect.TypeOf((*MockClient)(nil).DescribeDomainSLO), varargs...)
+}
+
 // DescribeHistoryHost mocks base method.
 func (m *MockClient) DescribeHistoryHost(arg0 context.Context, arg1 *types.DescribeHistoryHostRequest, arg2 ...yarpc.CallOption) (*types.DescribeHistoryHostResponse, error) {
 	m.ctrl.T.Helper()
@@ -177,6 +197,26 @@ func (mr *MockClientMockRecorder) DescribeHistoryHost(arg0, arg1 interface{}, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeHistoryHost", reflect.TypeOf((*MockClient)(nil).DescribeHistoryHost), varargs...)
 }
 
+// DescribePersistenceLatencyHeatmap mocks base method.
+func (m *MockClient) DescribePersistenceLatencyHeatmap(arg0 context.Context, arg1 *types.DescribePersistenceLatencyHeatmapRequest, arg2 ...yarpc.CallOption) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribePersistenceLatencyHeatmap", varargs...)
+	ret0, _ := ret[0].(*types.DescribePersistenceLatencyHeatmapResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribePersistenceLatencyHeatmap indicates an expected call of DescribePersistenceLatencyHeatmap.
+func (mr *MockClientMockRecorder) DescribePersistenceLatencyHeatmap(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribePersistenceLatencyHeatmap", reflect.TypeOf((*MockClient)(nil).DescribePersistenceLatencyHeatmap), varargs...)
+}
+
 // DescribeQueue mocks base method.
 func (m *MockClient) DescribeQueue(arg0 context.Context, arg1 *types.DescribeQueueRequest, arg2 ...yarpc.CallOption) (*types.DescribeQueueResponse, error) {
 	m.ctrl.T.Helper()
@@ -217,6 +257,46 @@ func (mr *MockClientMockRecorder) DescribeShardDistribution(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeShardDistribution", reflect.TypeOf((*MockClient)(nil).DescribeShardDistribution), varargs...)
 }
 
+// DescribeShardOwnership mocks base method.
+func (m *MockClient) DescribeShardOwnership(arg0 context.Context, arg1 *types.DescribeShardOwnershipRequest, arg2 ...yarpc.CallOption) (*types.DescribeShardOwnershipResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeShardOwnership", varargs...)
+	ret0, _ := ret[0].(*types.DescribeShardOwnershipResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeShardOwnership indicates an expected call of DescribeShardOwnership.
+func (mr *MockClientMockRecorder) DescribeShardOwnership(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeShardOwnership", reflect.TypeOf((*MockClient)(nil).DescribeShardOwnership), varargs...)
+}
+
+// DescribeWatchdog mocks base method.
+func (m *MockClient) DescribeWatchdog(arg0 context.Context, arg1 *types.DescribeWatchdogRequest, arg2 ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeWatchdog", varargs...)
+	ret0, _ := ret[0].(*types.DescribeWatchdogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeWatchdog indicates an expected call of DescribeWatchdog.
+func (mr *MockClientMockRecorder) DescribeWatchdog(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWatchdog", reflect.TypeOf((*MockClient)(nil).DescribeWatchdog), varargs...)
+}
+
 // DescribeWorkflowExecution mocks base method.
 func (m *MockClient) DescribeWorkflowExecution(arg0 context.Context, arg1 *types.AdminDescribeWorkflowExecutionRequest, arg2 ...yarpc.CallOption) (*types.AdminDescribeWorkflowExecutionResponse, error) {
 	m.ctrl.T.Helper()
@@ -297,6 +377,26 @@ func (mr *MockClientMockRecorder) GetDomainReplicationMessages(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomainReplicationMessages", reflect.TypeOf((*MockClient)(nil).GetDomainReplicationMessages), varargs...)
 }
 
+// GetDomainUsage mocks base method.
+func (m *MockClient) GetDomainUsage(arg0 context.Context, arg1 *types.AdminGetDomainUsageRequest, arg2 ...yarpc.CallOption) (*types.AdminGetDomainUsageResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetDomainUsage", varargs...)
+	ret0, _ := ret[0].(*types.AdminGetDomainUsageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDomainUsage indicates an expected call of GetDomainUsage.
+func (mr *MockClientMockRecorder) GetDomainUsage(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomainUsage", reflect.TypeOf((*MockClient)(nil).GetDomainUsage), varargs...)
+}
+
 // GetDynamicConfig mocks base method.
 func (m *MockClient) GetDynamicConfig(arg0 context.Context, arg1 *types.GetDynamicConfigRequest, arg2 ...yarpc.CallOption) (*types.GetDynamicConfigResponse, error) {
 	m.ctrl.T.Helper()
@@ -337,6 +437,26 @@ func (mr *MockClientMockRecorder) GetReplicationMessages(arg0, arg1 interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationMessages", reflect.TypeOf((*MockClient)(nil).GetReplicationMessages), varargs...)
 }
 
+// GetTaskListDrainStatus mocks base method.
+func (m *MockClient) GetTaskListDrainStatus(arg0 context.Context, arg1 *types.AdminGetTaskListDrainStatusRequest, arg2 ...yarpc.CallOption) (*types.GetTaskListDrainStatusResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetTaskListDrainStatus", varargs...)
+	ret0, _ := ret[0].(*types.GetTaskListDrainStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskListDrainStatus indicates an expected call of GetTaskListDrainStatus.
+func (mr *MockClientMockRecorder) GetTaskListDrainStatus(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskListDrainStatus", reflect.TypeOf((*MockClient)(nil).GetTaskListDrainStatus), varargs...)
+}
+
 // GetWorkflowExecutionRawHistoryV2 mocks base method.
 func (m *MockClient) GetWorkflowExecutionRawHistoryV2(arg0 context.Context, arg1 *types.GetWorkflowExecutionRawHistoryV2Request, arg2 ...yarpc.CallOption) (*types.GetWorkflowExecutionRawHistoryV2Response, error) {
 	m.ctrl.T.Helper()
@@ -357,6 +477,26 @@ func (mr *MockClientMockRecorder) GetWorkflowExecutionRawHistoryV2(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowExecutionRawHistoryV2", reflect.TypeOf((*MockClient)(nil).GetWorkflowExecutionRawHistoryV2), varargs...)
 }
 
+// InvalidateCaches mocks base method.
+func (m *MockClient) InvalidateCaches(arg0 context.Context, arg1 *types.InvalidateCachesRequest, arg2 ...yarpc.CallOption) (*types.InvalidateCachesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InvalidateCaches", varargs...)
+	ret0, _ := ret[0].(*types.InvalidateCachesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvalidateCaches indicates an expected call of InvalidateCaches.
+func (mr *MockClientMockRecorder) InvalidateCaches(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCaches", reflect.TypeOf((*MockClient)(nil).InvalidateCaches), varargs...)
+}
+
 // ListDynamicConfig mocks base method.
 func (m *MockClient) ListDynamicConfig(arg0 context.Context, arg1 *types.ListDynamicConfigRequest, arg2 ...yarpc.CallOption) (*types.ListDynamicConfigResponse, error) {
 	m.ctrl.T.Helper()
@@ -377,6 +517,46 @@ func (mr *MockClientMockRecorder) ListDynamicConfig(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDynamicConfig", reflect.TypeOf((*MockClient)(nil).ListDynamicConfig), varargs...)
 }
 
+// ListDynamicConfigChanges mocks base method.
+func (m *MockClient) ListDynamicConfigChanges(arg0 context.Context, arg1 *types.ListDynamicConfigChangesRequest, arg2 ...yarpc.CallOption) (*types.ListDynamicConfigChangesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListDynamicConfigChanges", varargs...)
+	ret0, _ := ret[0].(*types.ListDynamicConfigChangesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDynamicConfigChanges indicates an expected call of ListDynamicConfigChanges.
+func (mr *MockClientMockRecorder) ListDynamicConfigChanges(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDynamicConfigChanges", reflect.TypeOf((*MockClient)(nil).ListDynamicConfigChanges), varargs...)
+}
+
+// ListEffectiveDynamicConfig mocks base method.
+func (m *MockClient) ListEffectiveDynamicConfig(arg0 context.Context, arg1 *types.ListEffectiveDynamicConfigRequest, arg2 ...yarpc.CallOption) (*types.ListEffectiveDynamicConfigResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListEffectiveDynamicConfig", varargs...)
+	ret0, _ := ret[0].(*types.ListEffectiveDynamicConfigResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEffectiveDynamicConfig indicates an expected call of ListEffectiveDynamicConfig.
+func (mr *MockClientMockRecorder) ListEffectiveDynamicConfig(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEffectiveDynamicConfig", reflect.TypeOf((*MockClient)(nil).ListEffectiveDynamicConfig), varargs...)
+}
+
 // MaintainCorruptWorkflow mocks base method.
 func (m *MockClient) MaintainCorruptWorkflow(arg0 context.Context, arg1 *types.AdminMaintainWorkflowRequest, arg2 ...yarpc.CallOption) (*types.AdminMaintainWorkflowResponse, error) {
 	m.ctrl.T.Helper()
@@ -417,6 +597,25 @@ func (mr *MockClientMockRecorder) MergeDLQMessages(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeDLQMessages", reflect.TypeOf((*MockClient)(nil).MergeDLQMessages), varargs...)
 }
 
+// PauseTaskList mocks base method.
+func (m *MockClient) PauseTaskList(arg0 context.Context, arg1 *types.AdminPauseTaskListRequest, arg2 ...yarpc.CallOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PauseTaskList", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseTaskList indicates an expected call of PauseTaskList.
+func (mr *MockClientMockRecorder) PauseTaskList(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseTaskList", reflect.TypeOf((*MockClient)(nil).PauseTaskList), varargs...)
+}
+
 // PurgeDLQMessages mocks base method.
 func (m *MockClient) PurgeDLQMessages(arg0 context.Context, arg1 *types.PurgeDLQMessagesRequest, arg2 ...yarpc.CallOption) error {
 	m.ctrl.T.Helper()
@@ -590,6 +789,44 @@ func (mr *MockClientMockRecorder) RestoreDynamicConfig(arg0, arg1 interface{}, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDynamicConfig", reflect.TypeOf((*MockClient)(nil).RestoreDynamicConfig), varargs...)
 }
 
+// ResumeTaskList mocks base method.
+func (m *MockClient) ResumeTaskList(arg0 context.Context, arg1 *types.AdminResumeTaskListRequest, arg2 ...yarpc.CallOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResumeTaskList", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeTaskList indicates an expected call of ResumeTaskList.
+func (mr *MockClientMockRecorder) ResumeTaskList(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeTaskList", reflect.TypeOf((*MockClient)(nil).ResumeTaskList), varargs...)
+}
+
+// UnloadTaskList mocks base method.
+func (m *MockClient) UnloadTaskList(arg0 context.Context, arg1 *types.AdminUnloadTaskListRequest, arg2 ...yarpc.CallOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UnloadTaskList", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnloadTaskList indicates an expected call of UnloadTaskList.
+func (mr *MockClientMockRecorder) UnloadTaskList(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadTaskList", reflect.TypeOf((*MockClient)(nil).UnloadTaskList), varargs...)
+}
+
 // UpdateDynamicConfig mocks base method.
 func (m *MockClient) UpdateDynamicConfig(arg0 context.Context, arg1 *types.UpdateDynamicConfigRequest, arg2 ...yarpc.CallOption) error {
 	m.ctrl.T.Helper()
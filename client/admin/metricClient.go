@@ -529,3 +529,207 @@ func (c *metricClient) ListDynamicConfig(
 	}
 	return resp, err
 }
+
+func (c *metricClient) UnloadTaskList(
+	ctx context.Context,
+	request *types.AdminUnloadTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	c.metricsClient.IncCounter(metrics.AdminClientUnloadTaskListScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientUnloadTaskListScope, metrics.CadenceClientLatency)
+	err := c.client.UnloadTaskList(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientUnloadTaskListScope, metrics.CadenceClientFailures)
+	}
+	return err
+}
+
+func (c *metricClient) GetTaskListDrainStatus(
+	ctx context.Context,
+	request *types.AdminGetTaskListDrainStatusRequest,
+	opts ...yarpc.CallOption,
+) (*types.GetTaskListDrainStatusResponse, error) {
+	c.metricsClient.IncCounter(metrics.AdminClientGetTaskListDrainStatusScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientGetTaskListDrainStatusScope, metrics.CadenceClientLatency)
+	resp, err := c.client.GetTaskListDrainStatus(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientGetTaskListDrainStatusScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) PauseTaskList(
+	ctx context.Context,
+	request *types.AdminPauseTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	c.metricsClient.IncCounter(metrics.AdminClientPauseTaskListScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientPauseTaskListScope, metrics.CadenceClientLatency)
+	err := c.client.PauseTaskList(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientPauseTaskListScope, metrics.CadenceClientFailures)
+	}
+	return err
+}
+
+func (c *metricClient) ResumeTaskList(
+	ctx context.Context,
+	request *types.AdminResumeTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	c.metricsClient.IncCounter(metrics.AdminClientResumeTaskListScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientResumeTaskListScope, metrics.CadenceClientLatency)
+	err := c.client.ResumeTaskList(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientResumeTaskListScope, metrics.CadenceClientFailures)
+	}
+	return err
+}
+
+func (c *metricClient) ListDynamicConfigChanges(
+	ctx context.Context,
+	request *types.ListDynamicConfigChangesRequest,
+	opts ...yarpc.CallOption,
+) (*types.ListDynamicConfigChangesResponse, error) {
+	c.metricsClient.IncCounter(metrics.AdminClientListDynamicConfigChangesScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientListDynamicConfigChangesScope, metrics.CadenceClientLatency)
+	resp, err := c.client.ListDynamicConfigChanges(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientListDynamicConfigChangesScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) GetDomainUsage(
+	ctx context.Context,
+	request *types.AdminGetDomainUsageRequest,
+	opts ...yarpc.CallOption,
+) (*types.AdminGetDomainUsageResponse, error) {
+	c.metricsClient.IncCounter(metrics.AdminClientGetDomainUsageScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientGetDomainUsageScope, metrics.CadenceClientLatency)
+	resp, err := c.client.GetDomainUsage(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientGetDomainUsageScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+	opts ...yarpc.CallOption,
+) (*types.InvalidateCachesResponse, error) {
+	c.metricsClient.IncCounter(metrics.AdminClientInvalidateCachesScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientInvalidateCachesScope, metrics.CadenceClientLatency)
+	resp, err := c.client.InvalidateCaches(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientInvalidateCachesScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) ListEffectiveDynamicConfig(
+	ctx context.Context,
+	request *types.ListEffectiveDynamicConfigRequest,
+	opts ...yarpc.CallOption,
+) (*types.ListEffectiveDynamicConfigResponse, error) {
+	c.metricsClient.IncCounter(metrics.AdminClientListEffectiveDynamicConfigScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientListEffectiveDynamicConfigScope, metrics.CadenceClientLatency)
+	resp, err := c.client.ListEffectiveDynamicConfig(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientListEffectiveDynamicConfigScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) DescribeShardOwnership(
+	ctx context.Context,
+	request *types.DescribeShardOwnershipRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeShardOwnershipResponse, error) {
+	c.metricsClient.IncCounter(metrics.AdminClientDescribeShardOwnershipScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientDescribeShardOwnershipScope, metrics.CadenceClientLatency)
+	resp, err := c.client.DescribeShardOwnership(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientDescribeShardOwnershipScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) DescribePersistenceLatencyHeatmap(
+	ctx context.Context,
+	request *types.DescribePersistenceLatencyHeatmapRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	c.metricsClient.IncCounter(metrics.AdminClientDescribePersistenceLatencyHeatmapScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientDescribePersistenceLatencyHeatmapScope, metrics.CadenceClientLatency)
+	resp, err := c.client.DescribePersistenceLatencyHeatmap(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientDescribePersistenceLatencyHeatmapScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeWatchdogResponse, error) {
+	c.metricsClient.IncCounter(metrics.AdminClientDescribeWatchdogScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientDescribeWatchdogScope, metrics.CadenceClientLatency)
+	resp, err := c.client.DescribeWatchdog(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientDescribeWatchdogScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) DescribeDomainSLO(
+	ctx context.Context,
+	request *types.DescribeDomainSLORequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeDomainSLOResponse, error) {
+	c.metricsClient.IncCounter(metrics.AdminClientDescribeDomainSLOScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.AdminClientDescribeDomainSLOScope, metrics.CadenceClientLatency)
+	resp, err := c.client.DescribeDomainSLO(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.AdminClientDescribeDomainSLOScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
@@ -443,3 +443,171 @@ func (c *retryableClient) ListDynamicConfig(
 	err := c.throttleRetry.Do(ctx, op)
 	return resp, err
 }
+
+func (c *retryableClient) UnloadTaskList(
+	ctx context.Context,
+	request *types.AdminUnloadTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	op := func() error {
+		return c.client.UnloadTaskList(ctx, request, opts...)
+	}
+	return c.throttleRetry.Do(ctx, op)
+}
+
+func (c *retryableClient) GetTaskListDrainStatus(
+	ctx context.Context,
+	request *types.AdminGetTaskListDrainStatusRequest,
+	opts ...yarpc.CallOption,
+) (*types.GetTaskListDrainStatusResponse, error) {
+	var resp *types.GetTaskListDrainStatusResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.GetTaskListDrainStatus(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) PauseTaskList(
+	ctx context.Context,
+	request *types.AdminPauseTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	op := func() error {
+		return c.client.PauseTaskList(ctx, request, opts...)
+	}
+	return c.throttleRetry.Do(ctx, op)
+}
+
+func (c *retryableClient) ResumeTaskList(
+	ctx context.Context,
+	request *types.AdminResumeTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	op := func() error {
+		return c.client.ResumeTaskList(ctx, request, opts...)
+	}
+	return c.throttleRetry.Do(ctx, op)
+}
+
+func (c *retryableClient) ListDynamicConfigChanges(
+	ctx context.Context,
+	request *types.ListDynamicConfigChangesRequest,
+	opts ...yarpc.CallOption,
+) (*types.ListDynamicConfigChangesResponse, error) {
+	var resp *types.ListDynamicConfigChangesResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ListDynamicConfigChanges(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) GetDomainUsage(
+	ctx context.Context,
+	request *types.AdminGetDomainUsageRequest,
+	opts ...yarpc.CallOption,
+) (*types.AdminGetDomainUsageResponse, error) {
+	var resp *types.AdminGetDomainUsageResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.GetDomainUsage(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+	opts ...yarpc.CallOption,
+) (*types.InvalidateCachesResponse, error) {
+	var resp *types.InvalidateCachesResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.InvalidateCaches(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) ListEffectiveDynamicConfig(
+	ctx context.Context,
+	request *types.ListEffectiveDynamicConfigRequest,
+	opts ...yarpc.CallOption,
+) (*types.ListEffectiveDynamicConfigResponse, error) {
+	var resp *types.ListEffectiveDynamicConfigResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.ListEffectiveDynamicConfig(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) DescribeShardOwnership(
+	ctx context.Context,
+	request *types.DescribeShardOwnershipRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeShardOwnershipResponse, error) {
+	var resp *types.DescribeShardOwnershipResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribeShardOwnership(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) DescribePersistenceLatencyHeatmap(
+	ctx context.Context,
+	request *types.DescribePersistenceLatencyHeatmapRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	var resp *types.DescribePersistenceLatencyHeatmapResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribePersistenceLatencyHeatmap(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeWatchdogResponse, error) {
+	var resp *types.DescribeWatchdogResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribeWatchdog(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) DescribeDomainSLO(
+	ctx context.Context,
+	request *types.DescribeDomainSLORequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeDomainSLOResponse, error) {
+	var resp *types.DescribeDomainSLOResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribeDomainSLO(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
@@ -177,3 +177,51 @@ func (t thriftClient) ListDynamicConfig(ctx context.Context, request *types.List
 	response, err := t.c.ListDynamicConfig(ctx, thrift.FromListDynamicConfigRequest(request), opts...)
 	return thrift.ToListDynamicConfigResponse(response), thrift.ToError(err)
 }
+
+func (t thriftClient) UnloadTaskList(ctx context.Context, request *types.AdminUnloadTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (t thriftClient) GetTaskListDrainStatus(ctx context.Context, request *types.AdminGetTaskListDrainStatusRequest, opts ...yarpc.CallOption) (*types.GetTaskListDrainStatusResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) PauseTaskList(ctx context.Context, request *types.AdminPauseTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (t thriftClient) ResumeTaskList(ctx context.Context, request *types.AdminResumeTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (t thriftClient) ListDynamicConfigChanges(ctx context.Context, request *types.ListDynamicConfigChangesRequest, opts ...yarpc.CallOption) (*types.ListDynamicConfigChangesResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) GetDomainUsage(ctx context.Context, request *types.AdminGetDomainUsageRequest, opts ...yarpc.CallOption) (*types.AdminGetDomainUsageResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) InvalidateCaches(ctx context.Context, request *types.InvalidateCachesRequest, opts ...yarpc.CallOption) (*types.InvalidateCachesResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) ListEffectiveDynamicConfig(ctx context.Context, request *types.ListEffectiveDynamicConfigRequest, opts ...yarpc.CallOption) (*types.ListEffectiveDynamicConfigResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) DescribeShardOwnership(ctx context.Context, request *types.DescribeShardOwnershipRequest, opts ...yarpc.CallOption) (*types.DescribeShardOwnershipResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) DescribePersistenceLatencyHeatmap(ctx context.Context, request *types.DescribePersistenceLatencyHeatmapRequest, opts ...yarpc.CallOption) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) DescribeWatchdog(ctx context.Context, request *types.DescribeWatchdogRequest, opts ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) DescribeDomainSLO(ctx context.Context, request *types.DescribeDomainSLORequest, opts ...yarpc.CallOption) (*types.DescribeDomainSLOResponse, error) {
+	return nil, errJSONOnly
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"go.uber.org/yarpc/api/transport"
+	yarpcjson "go.uber.org/yarpc/encoding/json"
 
 	"github.com/uber/cadence/.gen/go/admin/adminserviceclient"
 	"github.com/uber/cadence/.gen/go/cadence/workflowserviceclient"
@@ -44,6 +45,7 @@ import (
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/membership"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/resharding"
 	"github.com/uber/cadence/common/rpc"
 	"github.com/uber/cadence/common/service"
 )
@@ -65,12 +67,12 @@ type (
 	DomainIDToNameFunc func(string) (string, error)
 
 	rpcClientFactory struct {
-		rpcFactory            common.RPCFactory
-		resolver              membership.Resolver
-		metricsClient         metrics.Client
-		dynConfig             *dynamicconfig.Collection
-		numberOfHistoryShards int
-		logger                log.Logger
+		rpcFactory         common.RPCFactory
+		resolver           membership.Resolver
+		metricsClient      metrics.Client
+		dynConfig          *dynamicconfig.Collection
+		historyShardRouter resharding.Router
+		logger             log.Logger
 	}
 )
 
@@ -80,16 +82,16 @@ func NewRPCClientFactory(
 	resolver membership.Resolver,
 	metricsClient metrics.Client,
 	dc *dynamicconfig.Collection,
-	numberOfHistoryShards int,
+	historyShardRouter resharding.Router,
 	logger log.Logger,
 ) Factory {
 	return &rpcClientFactory{
-		rpcFactory:            rpcFactory,
-		resolver:              resolver,
-		metricsClient:         metricsClient,
-		dynConfig:             dc,
-		numberOfHistoryShards: numberOfHistoryShards,
-		logger:                logger,
+		rpcFactory:         rpcFactory,
+		resolver:           resolver,
+		metricsClient:      metricsClient,
+		dynConfig:          dc,
+		historyShardRouter: historyShardRouter,
+		logger:             logger,
 	}
 }
 
@@ -112,11 +114,11 @@ func (cf *rpcClientFactory) NewHistoryClientWithTimeout(timeout time.Duration) (
 	} else {
 		rawClient = history.NewThriftClient(historyserviceclient.New(outboundConfig))
 	}
+	rawClient = history.NewJSONClient(yarpcjson.New(outboundConfig), rawClient)
 
-	peerResolver := history.NewPeerResolver(cf.numberOfHistoryShards, cf.resolver, namedPort)
+	peerResolver := history.NewPeerResolver(cf.historyShardRouter, cf.resolver, namedPort)
 
 	client := history.NewClient(
-		cf.numberOfHistoryShards,
 		cf.rpcFactory.GetMaxMessageSize(),
 		timeout,
 		rawClient,
@@ -146,6 +148,7 @@ func (cf *rpcClientFactory) NewMatchingClientWithTimeout(
 	} else {
 		rawClient = matching.NewThriftClient(matchingserviceclient.New(outboundConfig))
 	}
+	rawClient = matching.NewJSONClient(yarpcjson.New(outboundConfig), rawClient)
 
 	peerResolver := matching.NewPeerResolver(cf.resolver, namedPort)
 
@@ -154,7 +157,7 @@ func (cf *rpcClientFactory) NewMatchingClientWithTimeout(
 		longPollTimeout,
 		rawClient,
 		peerResolver,
-		matching.NewLoadBalancer(domainIDToName, cf.dynConfig),
+		matching.NewLoadBalancer(domainIDToName, peerResolver, cf.dynConfig),
 	)
 	if errorRate := cf.dynConfig.GetFloat64Property(dynamicconfig.MatchingErrorInjectionRate)(); errorRate != 0 {
 		client = matching.NewErrorInjectionClient(client, errorRate, cf.logger)
@@ -177,6 +180,7 @@ func (cf *rpcClientFactory) NewAdminClientWithTimeoutAndConfig(
 	} else {
 		client = admin.NewThriftClient(adminserviceclient.New(config))
 	}
+	client = admin.NewJSONClient(yarpcjson.New(config), client)
 
 	client = admin.NewClient(timeout, largeTimeout, client)
 	if errorRate := cf.dynConfig.GetFloat64Property(dynamicconfig.AdminErrorInjectionRate)(); errorRate != 0 {
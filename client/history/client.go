@@ -46,7 +46,6 @@ const (
 
 type (
 	clientImpl struct {
-		numberOfShards    int
 		rpcMaxSizeInBytes int // This value currently only used in GetReplicationMessage API
 		tokenSerializer   common.TaskTokenSerializer
 		timeout           time.Duration
@@ -64,7 +63,6 @@ type (
 
 // NewClient creates a new history service TChannel client
 func NewClient(
-	numberOfShards int,
 	rpcMaxSizeInBytes int,
 	timeout time.Duration,
 	client Client,
@@ -72,7 +70,6 @@ func NewClient(
 	logger log.Logger,
 ) Client {
 	return &clientImpl{
-		numberOfShards:    numberOfShards,
 		rpcMaxSizeInBytes: rpcMaxSizeInBytes,
 		tokenSerializer:   common.NewJSONTaskTokenSerializer(),
 		timeout:           timeout,
@@ -189,6 +186,62 @@ func (c *clientImpl) DescribeHistoryHost(
 	return response, nil
 }
 
+func (c *clientImpl) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+	opts ...yarpc.CallOption,
+) (*types.InvalidateCachesResponse, error) {
+	peer, err := c.peerResolver.FromHostAddress(request.GetHostAddress())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.InvalidateCaches(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
+func (c *clientImpl) DescribeShardOwnership(
+	ctx context.Context,
+	request *types.DescribeShardOwnershipRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeShardOwnershipResponse, error) {
+	peer, err := c.peerResolver.FromHostAddress(request.GetHostAddress())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DescribeShardOwnership(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
+func (c *clientImpl) DescribePersistenceLatencyHeatmap(
+	ctx context.Context,
+	request *types.DescribePersistenceLatencyHeatmapRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	peer, err := c.peerResolver.FromHostAddress(request.GetHostAddress())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DescribePersistenceLatencyHeatmap(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
+func (c *clientImpl) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeWatchdogResponse, error) {
+	peer, err := c.peerResolver.FromHostAddress(request.GetHostAddress())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DescribeWatchdog(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
 func (c *clientImpl) RemoveTask(
 	ctx context.Context,
 	request *types.RemoveTaskRequest,
@@ -48,6 +48,22 @@ func (g grpcClient) DescribeHistoryHost(ctx context.Context, request *types.Desc
 	return proto.ToHistoryDescribeHistoryHostResponse(response), proto.ToError(err)
 }
 
+func (g grpcClient) InvalidateCaches(ctx context.Context, request *types.InvalidateCachesRequest, opts ...yarpc.CallOption) (*types.InvalidateCachesResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) DescribeShardOwnership(ctx context.Context, request *types.DescribeShardOwnershipRequest, opts ...yarpc.CallOption) (*types.DescribeShardOwnershipResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) DescribePersistenceLatencyHeatmap(ctx context.Context, request *types.DescribePersistenceLatencyHeatmapRequest, opts ...yarpc.CallOption) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) DescribeWatchdog(ctx context.Context, request *types.DescribeWatchdogRequest, opts ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error) {
+	return nil, errJSONOnly
+}
+
 func (g grpcClient) DescribeMutableState(ctx context.Context, request *types.DescribeMutableStateRequest, opts ...yarpc.CallOption) (*types.DescribeMutableStateResponse, error) {
 	response, err := g.c.DescribeMutableState(ctx, proto.FromHistoryDescribeMutableStateRequest(request), opts...)
 	return proto.ToHistoryDescribeMutableStateResponse(response), proto.ToError(err)
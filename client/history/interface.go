@@ -34,6 +34,10 @@ import (
 type Client interface {
 	CloseShard(context.Context, *types.CloseShardRequest, ...yarpc.CallOption) error
 	DescribeHistoryHost(context.Context, *types.DescribeHistoryHostRequest, ...yarpc.CallOption) (*types.DescribeHistoryHostResponse, error)
+	InvalidateCaches(context.Context, *types.InvalidateCachesRequest, ...yarpc.CallOption) (*types.InvalidateCachesResponse, error)
+	DescribeShardOwnership(context.Context, *types.DescribeShardOwnershipRequest, ...yarpc.CallOption) (*types.DescribeShardOwnershipResponse, error)
+	DescribePersistenceLatencyHeatmap(context.Context, *types.DescribePersistenceLatencyHeatmapRequest, ...yarpc.CallOption) (*types.DescribePersistenceLatencyHeatmapResponse, error)
+	DescribeWatchdog(context.Context, *types.DescribeWatchdogRequest, ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error)
 	DescribeMutableState(context.Context, *types.DescribeMutableStateRequest, ...yarpc.CallOption) (*types.DescribeMutableStateResponse, error)
 	DescribeQueue(context.Context, *types.DescribeQueueRequest, ...yarpc.CallOption) (*types.DescribeQueueResponse, error)
 	DescribeWorkflowExecution(context.Context, *types.HistoryDescribeWorkflowExecutionRequest, ...yarpc.CallOption) (*types.DescribeWorkflowExecutionResponse, error)
@@ -138,6 +138,26 @@ func (mr *MockClientMockRecorder) DescribeMutableState(arg0, arg1 interface{}, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMutableState", reflect.TypeOf((*MockClient)(nil).DescribeMutableState), varargs...)
 }
 
+// DescribePersistenceLatencyHeatmap mocks base method.
+func (m *MockClient) DescribePersistenceLatencyHeatmap(arg0 context.Context, arg1 *types.DescribePersistenceLatencyHeatmapRequest, arg2 ...yarpc.CallOption) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribePersistenceLatencyHeatmap", varargs...)
+	ret0, _ := ret[0].(*types.DescribePersistenceLatencyHeatmapResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribePersistenceLatencyHeatmap indicates an expected call of DescribePersistenceLatencyHeatmap.
+func (mr *MockClientMockRecorder) DescribePersistenceLatencyHeatmap(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribePersistenceLatencyHeatmap", reflect.TypeOf((*MockClient)(nil).DescribePersistenceLatencyHeatmap), varargs...)
+}
+
 // DescribeQueue mocks base method.
 func (m *MockClient) DescribeQueue(arg0 context.Context, arg1 *types.DescribeQueueRequest, arg2 ...yarpc.CallOption) (*types.DescribeQueueResponse, error) {
 	m.ctrl.T.Helper()
@@ -158,6 +178,46 @@ func (mr *MockClientMockRecorder) DescribeQueue(arg0, arg1 interface{}, arg2 ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeQueue", reflect.TypeOf((*MockClient)(nil).DescribeQueue), varargs...)
 }
 
+// DescribeShardOwnership mocks base method.
+func (m *MockClient) DescribeShardOwnership(arg0 context.Context, arg1 *types.DescribeShardOwnershipRequest, arg2 ...yarpc.CallOption) (*types.DescribeShardOwnershipResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeShardOwnership", varargs...)
+	ret0, _ := ret[0].(*types.DescribeShardOwnershipResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeShardOwnership indicates an expected call of DescribeShardOwnership.
+func (mr *MockClientMockRecorder) DescribeShardOwnership(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeShardOwnership", reflect.TypeOf((*MockClient)(nil).DescribeShardOwnership), varargs...)
+}
+
+// DescribeWatchdog mocks base method.
+func (m *MockClient) DescribeWatchdog(arg0 context.Context, arg1 *types.DescribeWatchdogRequest, arg2 ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeWatchdog", varargs...)
+	ret0, _ := ret[0].(*types.DescribeWatchdogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeWatchdog indicates an expected call of DescribeWatchdog.
+func (mr *MockClientMockRecorder) DescribeWatchdog(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWatchdog", reflect.TypeOf((*MockClient)(nil).DescribeWatchdog), varargs...)
+}
+
 // DescribeWorkflowExecution mocks base method.
 func (m *MockClient) DescribeWorkflowExecution(arg0 context.Context, arg1 *types.HistoryDescribeWorkflowExecutionRequest, arg2 ...yarpc.CallOption) (*types.DescribeWorkflowExecutionResponse, error) {
 	m.ctrl.T.Helper()
@@ -278,6 +338,26 @@ func (mr *MockClientMockRecorder) GetReplicationMessages(arg0, arg1 interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationMessages", reflect.TypeOf((*MockClient)(nil).GetReplicationMessages), varargs...)
 }
 
+// InvalidateCaches mocks base method.
+func (m *MockClient) InvalidateCaches(arg0 context.Context, arg1 *types.InvalidateCachesRequest, arg2 ...yarpc.CallOption) (*types.InvalidateCachesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InvalidateCaches", varargs...)
+	ret0, _ := ret[0].(*types.InvalidateCachesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvalidateCaches indicates an expected call of InvalidateCaches.
+func (mr *MockClientMockRecorder) InvalidateCaches(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCaches", reflect.TypeOf((*MockClient)(nil).InvalidateCaches), varargs...)
+}
+
 // MergeDLQMessages mocks base method.
 func (m *MockClient) MergeDLQMessages(arg0 context.Context, arg1 *types.MergeDLQMessagesRequest, arg2 ...yarpc.CallOption) (*types.MergeDLQMessagesResponse, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+
+	"go.uber.org/yarpc"
+	yarpcjson "go.uber.org/yarpc/encoding/json"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/types/mapper/json"
+)
+
+// The procedures of the history APIs which are not in the history IDL yet, they are served with the json encoding
+const (
+	InvalidateCachesProcedure                  = "HistoryService::InvalidateCaches"
+	DescribeShardOwnershipProcedure            = "HistoryService::DescribeShardOwnership"
+	DescribePersistenceLatencyHeatmapProcedure = "HistoryService::DescribePersistenceLatencyHeatmap"
+	DescribeWatchdogProcedure                  = "HistoryService::DescribeWatchdog"
+)
+
+// errJSONOnly is returned by the thrift and grpc clients for the APIs which are only served with the json encoding
+var errJSONOnly = &types.BadRequestError{Message: "Feature only supported with the json encoding"}
+
+type jsonClient struct {
+	Client
+
+	c yarpcjson.Client
+}
+
+// NewJSONClient creates a new instance of Client which calls the APIs that are not in the history IDL yet with the
+// json encoding, the other APIs are called with the given client
+func NewJSONClient(c yarpcjson.Client, client Client) Client {
+	return jsonClient{
+		Client: client,
+		c:      c,
+	}
+}
+
+func (j jsonClient) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+	opts ...yarpc.CallOption,
+) (*types.InvalidateCachesResponse, error) {
+	var response types.InvalidateCachesResponse
+	if err := j.c.Call(ctx, InvalidateCachesProcedure, request, &response, opts...); err != nil {
+		return nil, json.ToError(err)
+	}
+	return &response, nil
+}
+
+func (j jsonClient) DescribeShardOwnership(
+	ctx context.Context,
+	request *types.DescribeShardOwnershipRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeShardOwnershipResponse, error) {
+	var response types.DescribeShardOwnershipResponse
+	if err := j.c.Call(ctx, DescribeShardOwnershipProcedure, request, &response, opts...); err != nil {
+		return nil, json.ToError(err)
+	}
+	return &response, nil
+}
+
+func (j jsonClient) DescribePersistenceLatencyHeatmap(
+	ctx context.Context,
+	request *types.DescribePersistenceLatencyHeatmapRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	var response types.DescribePersistenceLatencyHeatmapResponse
+	if err := j.c.Call(ctx, DescribePersistenceLatencyHeatmapProcedure, request, &response, opts...); err != nil {
+		return nil, json.ToError(err)
+	}
+	return &response, nil
+}
+
+func (j jsonClient) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeWatchdogResponse, error) {
+	var response types.DescribeWatchdogResponse
+	if err := j.c.Call(ctx, DescribeWatchdogProcedure, request, &response, opts...); err != nil {
+		return nil, json.ToError(err)
+	}
+	return &response, nil
+}
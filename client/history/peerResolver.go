@@ -23,6 +23,7 @@ package history
 import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/resharding"
 	"github.com/uber/cadence/common/service"
 )
 
@@ -30,33 +31,33 @@ import (
 // Those are deployed instances of Cadence history services that participate in the cluster ring.
 // The resulting peer is simply an address of form ip:port where RPC calls can be routed to.
 type PeerResolver struct {
-	numberOfShards int
-	resolver       membership.Resolver
-	namedPort      string // grpc or tchannel, depends on yarpc configuration
+	router    resharding.Router
+	resolver  membership.Resolver
+	namedPort string // grpc or tchannel, depends on yarpc configuration
 }
 
 // NewPeerResolver creates a new history peer resolver.
-func NewPeerResolver(numberOfShards int, resolver membership.Resolver, namedPort string) PeerResolver {
+func NewPeerResolver(router resharding.Router, resolver membership.Resolver, namedPort string) PeerResolver {
 	return PeerResolver{
-		numberOfShards: numberOfShards,
-		resolver:       resolver,
-		namedPort:      namedPort,
+		router:    router,
+		resolver:  resolver,
+		namedPort: namedPort,
 	}
 }
 
 // FromWorkflowID resolves the history peer responsible for a given workflowID.
-// WorkflowID is converted to logical shardID using a consistent hash function.
+// WorkflowID is converted to logical shardID using the history shard router.
 // FromShardID is used for further resolving.
 func (pr PeerResolver) FromWorkflowID(workflowID string) (string, error) {
-	shardID := common.WorkflowIDToHistoryShard(workflowID, pr.numberOfShards)
+	shardID := pr.router.WorkflowIDToShard(workflowID)
 	return pr.FromShardID(shardID)
 }
 
 // FromDomainID resolves the history peer responsible for a given domainID.
-// DomainID is converted to logical shardID using a consistent hash function.
+// DomainID is converted to logical shardID using the history shard router.
 // FromShardID is used for further resolving.
 func (pr PeerResolver) FromDomainID(domainID string) (string, error) {
-	shardID := common.DomainIDToHistoryShard(domainID, pr.numberOfShards)
+	shardID := pr.router.DomainIDToShard(domainID)
 	return pr.FromShardID(shardID)
 }
 
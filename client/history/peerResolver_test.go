@@ -29,6 +29,7 @@ import (
 
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/resharding"
 	"github.com/uber/cadence/common/service"
 )
 
@@ -63,7 +64,7 @@ func TestPeerResolver(t *testing.T) {
 
 	serviceResolver.EXPECT().Lookup(service.History, string(rune(11))).Return(membership.HostInfo{}, assert.AnError)
 
-	r := NewPeerResolver(numShards, serviceResolver, membership.PortTchannel)
+	r := NewPeerResolver(resharding.NewStaticRouter(numShards), serviceResolver, membership.PortTchannel)
 
 	peer, err := r.FromDomainID("domainID")
 	assert.NoError(t, err)
@@ -85,6 +85,66 @@ func (c *retryableClient) DescribeHistoryHost(
 	return resp, err
 }
 
+func (c *retryableClient) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+	opts ...yarpc.CallOption,
+) (*types.InvalidateCachesResponse, error) {
+	var resp *types.InvalidateCachesResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.InvalidateCaches(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) DescribeShardOwnership(
+	ctx context.Context,
+	request *types.DescribeShardOwnershipRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeShardOwnershipResponse, error) {
+	var resp *types.DescribeShardOwnershipResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribeShardOwnership(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) DescribePersistenceLatencyHeatmap(
+	ctx context.Context,
+	request *types.DescribePersistenceLatencyHeatmapRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	var resp *types.DescribePersistenceLatencyHeatmapResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribePersistenceLatencyHeatmap(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeWatchdogResponse, error) {
+	var resp *types.DescribeWatchdogResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribeWatchdog(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
 func (c *retryableClient) CloseShard(
 	ctx context.Context,
 	request *types.CloseShardRequest,
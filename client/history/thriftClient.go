@@ -49,6 +49,22 @@ func (t thriftClient) DescribeHistoryHost(ctx context.Context, request *types.De
 	return thrift.ToDescribeHistoryHostResponse(response), thrift.ToError(err)
 }
 
+func (t thriftClient) InvalidateCaches(ctx context.Context, request *types.InvalidateCachesRequest, opts ...yarpc.CallOption) (*types.InvalidateCachesResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) DescribeShardOwnership(ctx context.Context, request *types.DescribeShardOwnershipRequest, opts ...yarpc.CallOption) (*types.DescribeShardOwnershipResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) DescribePersistenceLatencyHeatmap(ctx context.Context, request *types.DescribePersistenceLatencyHeatmapRequest, opts ...yarpc.CallOption) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) DescribeWatchdog(ctx context.Context, request *types.DescribeWatchdogRequest, opts ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error) {
+	return nil, errJSONOnly
+}
+
 func (t thriftClient) DescribeMutableState(ctx context.Context, request *types.DescribeMutableStateRequest, opts ...yarpc.CallOption) (*types.DescribeMutableStateResponse, error) {
 	response, err := t.c.DescribeMutableState(ctx, thrift.FromDescribeMutableStateRequest(request), opts...)
 	return thrift.ToDescribeMutableStateResponse(response), thrift.ToError(err)
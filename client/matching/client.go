@@ -26,6 +26,7 @@ import (
 
 	"go.uber.org/yarpc"
 
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/future"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/types"
@@ -125,6 +126,7 @@ func (c *clientImpl) PollForActivityTask(
 	}
 	ctx, cancel := c.createLongPollContext(ctx)
 	defer cancel()
+	opts = withInboundIsolationGroup(ctx, opts)
 	return c.client.PollForActivityTask(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
 }
 
@@ -146,6 +148,7 @@ func (c *clientImpl) PollForDecisionTask(
 	}
 	ctx, cancel := c.createLongPollContext(ctx)
 	defer cancel()
+	opts = withInboundIsolationGroup(ctx, opts)
 	return c.client.PollForDecisionTask(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
 }
 
@@ -226,6 +229,104 @@ func (c *clientImpl) ListTaskListPartitions(
 	return c.client.ListTaskListPartitions(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
 }
 
+func (c *clientImpl) UnloadTaskList(
+	ctx context.Context,
+	request *types.MatchingUnloadTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	peer, err := c.peerResolver.FromTaskList(request.TaskList.GetName())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.UnloadTaskList(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
+func (c *clientImpl) GetTaskListDrainStatus(
+	ctx context.Context,
+	request *types.MatchingGetTaskListDrainStatusRequest,
+	opts ...yarpc.CallOption,
+) (*types.GetTaskListDrainStatusResponse, error) {
+	peer, err := c.peerResolver.FromTaskList(request.TaskList.GetName())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.GetTaskListDrainStatus(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
+func (c *clientImpl) PauseTaskList(
+	ctx context.Context,
+	request *types.MatchingPauseTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	peer, err := c.peerResolver.FromTaskList(request.TaskList.GetName())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.PauseTaskList(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
+func (c *clientImpl) ResumeTaskList(
+	ctx context.Context,
+	request *types.MatchingResumeTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	peer, err := c.peerResolver.FromTaskList(request.TaskList.GetName())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.ResumeTaskList(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
+func (c *clientImpl) RecordActivityTaskFinished(
+	ctx context.Context,
+	request *types.MatchingRecordActivityTaskFinishedRequest,
+	opts ...yarpc.CallOption,
+) error {
+	peer, err := c.peerResolver.FromTaskList(request.TaskList.GetName())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.RecordActivityTaskFinished(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
+func (c *clientImpl) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+	opts ...yarpc.CallOption,
+) (*types.InvalidateCachesResponse, error) {
+	peer, err := c.peerResolver.FromHostAddress(request.GetHostAddress())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.InvalidateCaches(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
+func (c *clientImpl) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeWatchdogResponse, error) {
+	peer, err := c.peerResolver.FromHostAddress(request.GetHostAddress())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DescribeWatchdog(ctx, request, append(opts, yarpc.WithShardKey(peer))...)
+}
+
 func (c *clientImpl) GetTaskListsByDomain(
 	ctx context.Context,
 	request *types.GetTaskListsByDomainRequest,
@@ -254,14 +355,14 @@ func (c *clientImpl) GetTaskListsByDomain(
 			if _, ok := decisionTaskListMap[name]; !ok {
 				decisionTaskListMap[name] = tl
 			} else {
-				decisionTaskListMap[name].Pollers = append(decisionTaskListMap[name].Pollers, tl.GetPollers()...)
+				mergeTaskList(decisionTaskListMap[name], tl)
 			}
 		}
 		for name, tl := range resp.GetActivityTaskListMap() {
 			if _, ok := activityTaskListMap[name]; !ok {
 				activityTaskListMap[name] = tl
 			} else {
-				activityTaskListMap[name].Pollers = append(activityTaskListMap[name].Pollers, tl.GetPollers()...)
+				mergeTaskList(activityTaskListMap[name], tl)
 			}
 		}
 	}
@@ -272,6 +373,30 @@ func (c *clientImpl) GetTaskListsByDomain(
 	}, nil
 }
 
+// mergeTaskList adds the pollers and the backlog hint of the partitions of a task list loaded by another host,
+// a poller polling partitions on several hosts is listed once
+func mergeTaskList(taskList *types.DescribeTaskListResponse, other *types.DescribeTaskListResponse) {
+	for _, poller := range other.GetPollers() {
+		found := false
+		for _, existing := range taskList.Pollers {
+			if existing.GetIdentity() == poller.GetIdentity() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			taskList.Pollers = append(taskList.Pollers, poller)
+		}
+	}
+	if other.GetTaskListStatus() == nil {
+		return
+	}
+	if taskList.TaskListStatus == nil {
+		taskList.TaskListStatus = &types.TaskListStatus{}
+	}
+	taskList.TaskListStatus.BacklogCountHint += other.GetTaskListStatus().GetBacklogCountHint()
+}
+
 func (c *clientImpl) createContext(
 	parent context.Context,
 ) (context.Context, context.CancelFunc) {
@@ -289,3 +414,16 @@ func (c *clientImpl) createLongPollContext(
 	}
 	return context.WithTimeout(parent, c.longPollTimeout)
 }
+
+// withInboundIsolationGroup propagates the isolation group of the inbound call being handled,
+// e.g. a poll request from a worker or a poll forwarded between partitions, to matching
+func withInboundIsolationGroup(ctx context.Context, opts []yarpc.CallOption) []yarpc.CallOption {
+	call := yarpc.CallFromContext(ctx)
+	if call == nil {
+		return opts
+	}
+	if isolationGroup := call.Header(common.IsolationGroupHeaderName); isolationGroup != "" {
+		opts = append(opts, yarpc.WithHeader(common.IsolationGroupHeaderName, isolationGroup))
+	}
+	return opts
+}
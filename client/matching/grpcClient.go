@@ -87,3 +87,31 @@ func (g grpcClient) RespondQueryTaskCompleted(ctx context.Context, request *type
 	_, err := g.c.RespondQueryTaskCompleted(ctx, proto.FromMatchingRespondQueryTaskCompletedRequest(request), opts...)
 	return proto.ToError(err)
 }
+
+func (g grpcClient) UnloadTaskList(ctx context.Context, request *types.MatchingUnloadTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (g grpcClient) GetTaskListDrainStatus(ctx context.Context, request *types.MatchingGetTaskListDrainStatusRequest, opts ...yarpc.CallOption) (*types.GetTaskListDrainStatusResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) PauseTaskList(ctx context.Context, request *types.MatchingPauseTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (g grpcClient) ResumeTaskList(ctx context.Context, request *types.MatchingResumeTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (g grpcClient) RecordActivityTaskFinished(ctx context.Context, request *types.MatchingRecordActivityTaskFinishedRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (g grpcClient) InvalidateCaches(ctx context.Context, request *types.InvalidateCachesRequest, opts ...yarpc.CallOption) (*types.InvalidateCachesResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (g grpcClient) DescribeWatchdog(ctx context.Context, request *types.DescribeWatchdogRequest, opts ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error) {
+	return nil, errJSONOnly
+}
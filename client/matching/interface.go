@@ -42,4 +42,11 @@ type Client interface {
 	PollForDecisionTask(context.Context, *types.MatchingPollForDecisionTaskRequest, ...yarpc.CallOption) (*types.MatchingPollForDecisionTaskResponse, error)
 	QueryWorkflow(context.Context, *types.MatchingQueryWorkflowRequest, ...yarpc.CallOption) (*types.QueryWorkflowResponse, error)
 	RespondQueryTaskCompleted(context.Context, *types.MatchingRespondQueryTaskCompletedRequest, ...yarpc.CallOption) error
+	UnloadTaskList(context.Context, *types.MatchingUnloadTaskListRequest, ...yarpc.CallOption) error
+	GetTaskListDrainStatus(context.Context, *types.MatchingGetTaskListDrainStatusRequest, ...yarpc.CallOption) (*types.GetTaskListDrainStatusResponse, error)
+	PauseTaskList(context.Context, *types.MatchingPauseTaskListRequest, ...yarpc.CallOption) error
+	ResumeTaskList(context.Context, *types.MatchingResumeTaskListRequest, ...yarpc.CallOption) error
+	RecordActivityTaskFinished(context.Context, *types.MatchingRecordActivityTaskFinishedRequest, ...yarpc.CallOption) error
+	InvalidateCaches(context.Context, *types.InvalidateCachesRequest, ...yarpc.CallOption) (*types.InvalidateCachesResponse, error)
+	DescribeWatchdog(context.Context, *types.DescribeWatchdogRequest, ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error)
 }
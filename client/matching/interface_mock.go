@@ -136,6 +136,46 @@ func (mr *MockClientMockRecorder) DescribeTaskList(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTaskList", reflect.TypeOf((*MockClient)(nil).DescribeTaskList), varargs...)
 }
 
+// DescribeWatchdog mocks base method.
+func (m *MockClient) DescribeWatchdog(arg0 context.Context, arg1 *types.DescribeWatchdogRequest, arg2 ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeWatchdog", varargs...)
+	ret0, _ := ret[0].(*types.DescribeWatchdogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeWatchdog indicates an expected call of DescribeWatchdog.
+func (mr *MockClientMockRecorder) DescribeWatchdog(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWatchdog", reflect.TypeOf((*MockClient)(nil).DescribeWatchdog), varargs...)
+}
+
+// GetTaskListDrainStatus mocks base method.
+func (m *MockClient) GetTaskListDrainStatus(arg0 context.Context, arg1 *types.MatchingGetTaskListDrainStatusRequest, arg2 ...yarpc.CallOption) (*types.GetTaskListDrainStatusResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetTaskListDrainStatus", varargs...)
+	ret0, _ := ret[0].(*types.GetTaskListDrainStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskListDrainStatus indicates an expected call of GetTaskListDrainStatus.
+func (mr *MockClientMockRecorder) GetTaskListDrainStatus(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskListDrainStatus", reflect.TypeOf((*MockClient)(nil).GetTaskListDrainStatus), varargs...)
+}
+
 // GetTaskListsByDomain mocks base method.
 func (m *MockClient) GetTaskListsByDomain(arg0 context.Context, arg1 *types.GetTaskListsByDomainRequest, arg2 ...yarpc.CallOption) (*types.GetTaskListsByDomainResponse, error) {
 	m.ctrl.T.Helper()
@@ -156,6 +196,26 @@ func (mr *MockClientMockRecorder) GetTaskListsByDomain(arg0, arg1 interface{}, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskListsByDomain", reflect.TypeOf((*MockClient)(nil).GetTaskListsByDomain), varargs...)
 }
 
+// InvalidateCaches mocks base method.
+func (m *MockClient) InvalidateCaches(arg0 context.Context, arg1 *types.InvalidateCachesRequest, arg2 ...yarpc.CallOption) (*types.InvalidateCachesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InvalidateCaches", varargs...)
+	ret0, _ := ret[0].(*types.InvalidateCachesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvalidateCaches indicates an expected call of InvalidateCaches.
+func (mr *MockClientMockRecorder) InvalidateCaches(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCaches", reflect.TypeOf((*MockClient)(nil).InvalidateCaches), varargs...)
+}
+
 // ListTaskListPartitions mocks base method.
 func (m *MockClient) ListTaskListPartitions(arg0 context.Context, arg1 *types.MatchingListTaskListPartitionsRequest, arg2 ...yarpc.CallOption) (*types.ListTaskListPartitionsResponse, error) {
 	m.ctrl.T.Helper()
@@ -176,6 +236,25 @@ func (mr *MockClientMockRecorder) ListTaskListPartitions(arg0, arg1 interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskListPartitions", reflect.TypeOf((*MockClient)(nil).ListTaskListPartitions), varargs...)
 }
 
+// PauseTaskList mocks base method.
+func (m *MockClient) PauseTaskList(arg0 context.Context, arg1 *types.MatchingPauseTaskListRequest, arg2 ...yarpc.CallOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PauseTaskList", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseTaskList indicates an expected call of PauseTaskList.
+func (mr *MockClientMockRecorder) PauseTaskList(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseTaskList", reflect.TypeOf((*MockClient)(nil).PauseTaskList), varargs...)
+}
+
 // PollForActivityTask mocks base method.
 func (m *MockClient) PollForActivityTask(arg0 context.Context, arg1 *types.MatchingPollForActivityTaskRequest, arg2 ...yarpc.CallOption) (*types.PollForActivityTaskResponse, error) {
 	m.ctrl.T.Helper()
@@ -236,6 +315,25 @@ func (mr *MockClientMockRecorder) QueryWorkflow(arg0, arg1 interface{}, arg2 ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryWorkflow", reflect.TypeOf((*MockClient)(nil).QueryWorkflow), varargs...)
 }
 
+// RecordActivityTaskFinished mocks base method.
+func (m *MockClient) RecordActivityTaskFinished(arg0 context.Context, arg1 *types.MatchingRecordActivityTaskFinishedRequest, arg2 ...yarpc.CallOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RecordActivityTaskFinished", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordActivityTaskFinished indicates an expected call of RecordActivityTaskFinished.
+func (mr *MockClientMockRecorder) RecordActivityTaskFinished(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordActivityTaskFinished", reflect.TypeOf((*MockClient)(nil).RecordActivityTaskFinished), varargs...)
+}
+
 // RespondQueryTaskCompleted mocks base method.
 func (m *MockClient) RespondQueryTaskCompleted(arg0 context.Context, arg1 *types.MatchingRespondQueryTaskCompletedRequest, arg2 ...yarpc.CallOption) error {
 	m.ctrl.T.Helper()
@@ -254,3 +352,41 @@ func (mr *MockClientMockRecorder) RespondQueryTaskCompleted(arg0, arg1 interface
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondQueryTaskCompleted", reflect.TypeOf((*MockClient)(nil).RespondQueryTaskCompleted), varargs...)
 }
+
+// ResumeTaskList mocks base method.
+func (m *MockClient) ResumeTaskList(arg0 context.Context, arg1 *types.MatchingResumeTaskListRequest, arg2 ...yarpc.CallOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResumeTaskList", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeTaskList indicates an expected call of ResumeTaskList.
+func (mr *MockClientMockRecorder) ResumeTaskList(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeTaskList", reflect.TypeOf((*MockClient)(nil).ResumeTaskList), varargs...)
+}
+
+// UnloadTaskList mocks base method.
+func (m *MockClient) UnloadTaskList(arg0 context.Context, arg1 *types.MatchingUnloadTaskListRequest, arg2 ...yarpc.CallOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UnloadTaskList", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnloadTaskList indicates an expected call of UnloadTaskList.
+func (mr *MockClientMockRecorder) UnloadTaskList(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadTaskList", reflect.TypeOf((*MockClient)(nil).UnloadTaskList), varargs...)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+
+	"go.uber.org/yarpc"
+	yarpcjson "go.uber.org/yarpc/encoding/json"
+
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/types/mapper/json"
+)
+
+// The procedures of the matching APIs which are not in the matching IDL yet, they are served with the json encoding
+const (
+	UnloadTaskListProcedure             = "MatchingService::UnloadTaskList"
+	GetTaskListDrainStatusProcedure     = "MatchingService::GetTaskListDrainStatus"
+	PauseTaskListProcedure              = "MatchingService::PauseTaskList"
+	ResumeTaskListProcedure             = "MatchingService::ResumeTaskList"
+	RecordActivityTaskFinishedProcedure = "MatchingService::RecordActivityTaskFinished"
+	InvalidateCachesProcedure           = "MatchingService::InvalidateCaches"
+	DescribeWatchdogProcedure           = "MatchingService::DescribeWatchdog"
+)
+
+// errJSONOnly is returned by the thrift and grpc clients for the APIs which are only served with the json encoding
+var errJSONOnly = &types.BadRequestError{Message: "Feature only supported with the json encoding"}
+
+type jsonClient struct {
+	Client
+
+	c yarpcjson.Client
+}
+
+// NewJSONClient creates a new instance of Client which calls the APIs that are not in the matching IDL yet with the
+// json encoding, the other APIs are called with the given client
+func NewJSONClient(c yarpcjson.Client, client Client) Client {
+	return jsonClient{
+		Client: client,
+		c:      c,
+	}
+}
+
+func (j jsonClient) UnloadTaskList(
+	ctx context.Context,
+	request *types.MatchingUnloadTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	err := j.c.Call(ctx, UnloadTaskListProcedure, request, &struct{}{}, opts...)
+	return json.ToError(err)
+}
+
+func (j jsonClient) GetTaskListDrainStatus(
+	ctx context.Context,
+	request *types.MatchingGetTaskListDrainStatusRequest,
+	opts ...yarpc.CallOption,
+) (*types.GetTaskListDrainStatusResponse, error) {
+	var response types.GetTaskListDrainStatusResponse
+	if err := j.c.Call(ctx, GetTaskListDrainStatusProcedure, request, &response, opts...); err != nil {
+		return nil, json.ToError(err)
+	}
+	return &response, nil
+}
+
+func (j jsonClient) PauseTaskList(
+	ctx context.Context,
+	request *types.MatchingPauseTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	err := j.c.Call(ctx, PauseTaskListProcedure, request, &struct{}{}, opts...)
+	return json.ToError(err)
+}
+
+func (j jsonClient) ResumeTaskList(
+	ctx context.Context,
+	request *types.MatchingResumeTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	err := j.c.Call(ctx, ResumeTaskListProcedure, request, &struct{}{}, opts...)
+	return json.ToError(err)
+}
+
+func (j jsonClient) RecordActivityTaskFinished(
+	ctx context.Context,
+	request *types.MatchingRecordActivityTaskFinishedRequest,
+	opts ...yarpc.CallOption,
+) error {
+	err := j.c.Call(ctx, RecordActivityTaskFinishedProcedure, request, &struct{}{}, opts...)
+	return json.ToError(err)
+}
+
+func (j jsonClient) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+	opts ...yarpc.CallOption,
+) (*types.InvalidateCachesResponse, error) {
+	var response types.InvalidateCachesResponse
+	if err := j.c.Call(ctx, InvalidateCachesProcedure, request, &response, opts...); err != nil {
+		return nil, json.ToError(err)
+	}
+	return &response, nil
+}
+
+func (j jsonClient) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeWatchdogResponse, error) {
+	var response types.DescribeWatchdogResponse
+	if err := j.c.Call(ctx, DescribeWatchdogProcedure, request, &response, opts...); err != nil {
+		return nil, json.ToError(err)
+	}
+	return &response, nil
+}
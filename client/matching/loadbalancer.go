@@ -62,20 +62,27 @@ type (
 	defaultLoadBalancer struct {
 		nReadPartitions  dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 		nWritePartitions dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		zoneAware        dynamicconfig.BoolPropertyFnWithTaskListInfoFilters
 		domainIDToName   func(string) (string, error)
+		peerResolver     PeerResolver
 	}
 )
 
 // NewLoadBalancer returns an instance of matching load balancer that
-// can help distribute api calls across task list partitions
+// can help distribute api calls across task list partitions, preferring
+// the partitions owned by the matching hosts in the zone of this host
+// when zone aware routing is enabled
 func NewLoadBalancer(
 	domainIDToName func(string) (string, error),
+	peerResolver PeerResolver,
 	dc *dynamicconfig.Collection,
 ) LoadBalancer {
 	return &defaultLoadBalancer{
 		domainIDToName:   domainIDToName,
+		peerResolver:     peerResolver,
 		nReadPartitions:  dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingNumTasklistReadPartitions),
 		nWritePartitions: dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingNumTasklistWritePartitions),
+		zoneAware:        dc.GetBoolPropertyFilteredByTaskListInfo(dynamicconfig.MatchingEnableZoneAwareRouting),
 	}
 }
 
@@ -124,10 +131,24 @@ func (lb *defaultLoadBalancer) pickPartition(
 		return taskList.GetName()
 	}
 
-	p := rand.Intn(n)
-	if p == 0 {
-		return taskList.GetName()
+	if n > 1 && lb.zoneAware(domainName, taskList.GetName(), taskListType) {
+		partitions := make([]string, n)
+		for p := range partitions {
+			partitions[p] = partitionName(taskList.GetName(), p)
+		}
+		// the partitions are picked among all of them if none is owned in this zone
+		if local := lb.peerResolver.LocalZoneTaskLists(partitions); len(local) > 0 {
+			return local[rand.Intn(len(local))]
+		}
 	}
 
-	return fmt.Sprintf("%v%v/%v", common.ReservedTaskListPrefix, taskList.GetName(), p)
+	return partitionName(taskList.GetName(), rand.Intn(n))
+}
+
+// partitionName returns the name of the p-th partition of the task list, the root partition being the task list itself
+func partitionName(taskListName string, p int) string {
+	if p == 0 {
+		return taskListName
+	}
+	return fmt.Sprintf("%v%v/%v", common.ReservedTaskListPrefix, taskListName, p)
 }
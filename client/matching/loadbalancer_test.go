@@ -0,0 +1,68 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestLoadBalancer_ZoneAware(t *testing.T) {
+	controller := gomock.NewController(t)
+	serviceResolver := membership.NewMockResolver(controller)
+	serviceResolver.EXPECT().WhoAmI().Return(membership.NewHostInfo("self:1234").WithZone("zone-a"), nil).AnyTimes()
+	serviceResolver.EXPECT().Lookup(service.Matching, "tl").Return(membership.NewHostInfo("host0:1234").WithZone("zone-b"), nil).AnyTimes()
+	serviceResolver.EXPECT().Lookup(service.Matching, "/__cadence_sys/tl/1").Return(membership.NewHostInfo("host1:1234").WithZone("zone-a"), nil).AnyTimes()
+	serviceResolver.EXPECT().Lookup(service.Matching, "/__cadence_sys/tl/2").Return(membership.NewHostInfo("host2:1234").WithZone("zone-b"), nil).AnyTimes()
+
+	client := dynamicconfig.NewInMemoryClient()
+	assert.NoError(t, client.UpdateValue(dynamicconfig.MatchingNumTasklistReadPartitions, 3))
+	assert.NoError(t, client.UpdateValue(dynamicconfig.MatchingNumTasklistWritePartitions, 3))
+	lb := NewLoadBalancer(
+		func(string) (string, error) { return "domain", nil },
+		NewPeerResolver(serviceResolver, membership.PortGRPC),
+		dynamicconfig.NewCollection(client, loggerimpl.NewNopLogger()),
+	)
+	taskList := types.TaskList{Name: "tl"}
+
+	picked := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		picked[lb.PickReadPartition("domainID", taskList, 0, "")] = true
+	}
+	assert.Len(t, picked, 3, "all the partitions are picked when zone aware routing is disabled")
+
+	assert.NoError(t, client.UpdateValue(dynamicconfig.MatchingEnableZoneAwareRouting, true))
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, "/__cadence_sys/tl/1", lb.PickReadPartition("domainID", taskList, 0, ""))
+		assert.Equal(t, "/__cadence_sys/tl/1", lb.PickWritePartition("domainID", taskList, 0, ""))
+	}
+	assert.Equal(t, "tl", lb.PickReadPartition("domainID", taskList, 0, "/__cadence_sys/tl/2"), "forwarded calls are not balanced")
+}
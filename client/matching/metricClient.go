@@ -242,6 +242,126 @@ func (c *metricClient) ListTaskListPartitions(
 	return resp, err
 }
 
+func (c *metricClient) UnloadTaskList(
+	ctx context.Context,
+	request *types.MatchingUnloadTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	c.metricsClient.IncCounter(metrics.MatchingClientUnloadTaskListScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientUnloadTaskListScope, metrics.CadenceClientLatency)
+	err := c.client.UnloadTaskList(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientUnloadTaskListScope, metrics.CadenceClientFailures)
+	}
+
+	return err
+}
+
+func (c *metricClient) GetTaskListDrainStatus(
+	ctx context.Context,
+	request *types.MatchingGetTaskListDrainStatusRequest,
+	opts ...yarpc.CallOption,
+) (*types.GetTaskListDrainStatusResponse, error) {
+	c.metricsClient.IncCounter(metrics.MatchingClientGetTaskListDrainStatusScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientGetTaskListDrainStatusScope, metrics.CadenceClientLatency)
+	resp, err := c.client.GetTaskListDrainStatus(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientGetTaskListDrainStatusScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) PauseTaskList(
+	ctx context.Context,
+	request *types.MatchingPauseTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	c.metricsClient.IncCounter(metrics.MatchingClientPauseTaskListScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientPauseTaskListScope, metrics.CadenceClientLatency)
+	err := c.client.PauseTaskList(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientPauseTaskListScope, metrics.CadenceClientFailures)
+	}
+	return err
+}
+
+func (c *metricClient) ResumeTaskList(
+	ctx context.Context,
+	request *types.MatchingResumeTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	c.metricsClient.IncCounter(metrics.MatchingClientResumeTaskListScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientResumeTaskListScope, metrics.CadenceClientLatency)
+	err := c.client.ResumeTaskList(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientResumeTaskListScope, metrics.CadenceClientFailures)
+	}
+	return err
+}
+
+func (c *metricClient) RecordActivityTaskFinished(
+	ctx context.Context,
+	request *types.MatchingRecordActivityTaskFinishedRequest,
+	opts ...yarpc.CallOption,
+) error {
+	c.metricsClient.IncCounter(metrics.MatchingClientRecordActivityTaskFinishedScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientRecordActivityTaskFinishedScope, metrics.CadenceClientLatency)
+	err := c.client.RecordActivityTaskFinished(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientRecordActivityTaskFinishedScope, metrics.CadenceClientFailures)
+	}
+	return err
+}
+
+func (c *metricClient) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+	opts ...yarpc.CallOption,
+) (*types.InvalidateCachesResponse, error) {
+	c.metricsClient.IncCounter(metrics.MatchingClientInvalidateCachesScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientInvalidateCachesScope, metrics.CadenceClientLatency)
+	resp, err := c.client.InvalidateCaches(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientInvalidateCachesScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
+func (c *metricClient) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeWatchdogResponse, error) {
+	c.metricsClient.IncCounter(metrics.MatchingClientDescribeWatchdogScope, metrics.CadenceClientRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientDescribeWatchdogScope, metrics.CadenceClientLatency)
+	resp, err := c.client.DescribeWatchdog(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientDescribeWatchdogScope, metrics.CadenceClientFailures)
+	}
+	return resp, err
+}
+
 func (c *metricClient) GetTaskListsByDomain(
 	ctx context.Context,
 	request *types.GetTaskListsByDomainRequest,
@@ -55,6 +55,24 @@ func (pr PeerResolver) FromTaskList(taskListName string) (string, error) {
 	return peer, common.ToServiceTransientError(err)
 }
 
+// LocalZoneTaskLists returns the given task lists whose matching peer is in the zone of this host.
+// None is returned when the zone of this host is unknown.
+func (pr PeerResolver) LocalZoneTaskLists(taskListNames []string) []string {
+	self, err := pr.resolver.WhoAmI()
+	if err != nil || self.Zone() == "" {
+		return nil
+	}
+
+	var res []string
+	for _, taskListName := range taskListNames {
+		host, err := pr.resolver.Lookup(service.Matching, taskListName)
+		if err == nil && host.Zone() == self.Zone() {
+			res = append(res, taskListName)
+		}
+	}
+	return res
+}
+
 // GetAllPeers returns all matching service peers in the cluster ring.
 func (pr PeerResolver) GetAllPeers() ([]string, error) {
 	hosts, err := pr.resolver.Members(service.Matching)
@@ -85,3 +85,20 @@ func TestPeerResolver(t *testing.T) {
 	assert.Equal(t, []string{"tasklistHost:1244", "tasklistHost2:1245"}, peers)
 
 }
+
+func TestPeerResolver_LocalZoneTaskLists(t *testing.T) {
+	controller := gomock.NewController(t)
+	serviceResolver := membership.NewMockResolver(controller)
+	r := NewPeerResolver(serviceResolver, membership.PortGRPC)
+
+	serviceResolver.EXPECT().WhoAmI().Return(membership.NewHostInfo("self:1234").WithZone("zone-a"), nil)
+	serviceResolver.EXPECT().Lookup(service.Matching, "taskListA").Return(membership.NewHostInfo("hostA:1234").WithZone("zone-a"), nil)
+	serviceResolver.EXPECT().Lookup(service.Matching, "taskListB").Return(membership.NewHostInfo("hostB:1234").WithZone("zone-b"), nil)
+	serviceResolver.EXPECT().Lookup(service.Matching, "taskListC").Return(membership.NewHostInfo("hostC:1234"), nil)
+	serviceResolver.EXPECT().Lookup(service.Matching, "invalid").Return(membership.HostInfo{}, assert.AnError)
+	assert.Equal(t, []string{"taskListA"}, r.LocalZoneTaskLists([]string{"taskListA", "taskListB", "taskListC", "invalid"}))
+
+	// no task list is local when the zone of this host is unknown
+	serviceResolver.EXPECT().WhoAmI().Return(membership.NewHostInfo("self:1234"), nil)
+	assert.Empty(t, r.LocalZoneTaskLists([]string{"taskListA"}))
+}
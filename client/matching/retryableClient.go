@@ -187,6 +187,97 @@ func (c *retryableClient) ListTaskListPartitions(
 	return resp, err
 }
 
+func (c *retryableClient) UnloadTaskList(
+	ctx context.Context,
+	request *types.MatchingUnloadTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+
+	op := func() error {
+		return c.client.UnloadTaskList(ctx, request, opts...)
+	}
+
+	return c.throttleRetry.Do(ctx, op)
+}
+
+func (c *retryableClient) GetTaskListDrainStatus(
+	ctx context.Context,
+	request *types.MatchingGetTaskListDrainStatusRequest,
+	opts ...yarpc.CallOption,
+) (*types.GetTaskListDrainStatusResponse, error) {
+	var resp *types.GetTaskListDrainStatusResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.GetTaskListDrainStatus(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) PauseTaskList(
+	ctx context.Context,
+	request *types.MatchingPauseTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	op := func() error {
+		return c.client.PauseTaskList(ctx, request, opts...)
+	}
+	return c.throttleRetry.Do(ctx, op)
+}
+
+func (c *retryableClient) ResumeTaskList(
+	ctx context.Context,
+	request *types.MatchingResumeTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	op := func() error {
+		return c.client.ResumeTaskList(ctx, request, opts...)
+	}
+	return c.throttleRetry.Do(ctx, op)
+}
+
+func (c *retryableClient) RecordActivityTaskFinished(
+	ctx context.Context,
+	request *types.MatchingRecordActivityTaskFinishedRequest,
+	opts ...yarpc.CallOption,
+) error {
+	op := func() error {
+		return c.client.RecordActivityTaskFinished(ctx, request, opts...)
+	}
+	return c.throttleRetry.Do(ctx, op)
+}
+
+func (c *retryableClient) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+	opts ...yarpc.CallOption,
+) (*types.InvalidateCachesResponse, error) {
+	var resp *types.InvalidateCachesResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.InvalidateCaches(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
+func (c *retryableClient) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+	opts ...yarpc.CallOption,
+) (*types.DescribeWatchdogResponse, error) {
+	var resp *types.DescribeWatchdogResponse
+	op := func() error {
+		var err error
+		resp, err = c.client.DescribeWatchdog(ctx, request, opts...)
+		return err
+	}
+	err := c.throttleRetry.Do(ctx, op)
+	return resp, err
+}
+
 func (c *retryableClient) GetTaskListsByDomain(
 	ctx context.Context,
 	request *types.GetTaskListsByDomainRequest,
@@ -128,3 +128,35 @@ func (t thriftClient) RespondQueryTaskCompleted(
 	err := t.c.RespondQueryTaskCompleted(ctx, thrift.FromMatchingRespondQueryTaskCompletedRequest(request), opts...)
 	return thrift.ToError(err)
 }
+
+func (t thriftClient) UnloadTaskList(
+	ctx context.Context,
+	request *types.MatchingUnloadTaskListRequest,
+	opts ...yarpc.CallOption,
+) error {
+	return errJSONOnly
+}
+
+func (t thriftClient) GetTaskListDrainStatus(ctx context.Context, request *types.MatchingGetTaskListDrainStatusRequest, opts ...yarpc.CallOption) (*types.GetTaskListDrainStatusResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) PauseTaskList(ctx context.Context, request *types.MatchingPauseTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (t thriftClient) ResumeTaskList(ctx context.Context, request *types.MatchingResumeTaskListRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (t thriftClient) RecordActivityTaskFinished(ctx context.Context, request *types.MatchingRecordActivityTaskFinishedRequest, opts ...yarpc.CallOption) error {
+	return errJSONOnly
+}
+
+func (t thriftClient) InvalidateCaches(ctx context.Context, request *types.InvalidateCachesRequest, opts ...yarpc.CallOption) (*types.InvalidateCachesResponse, error) {
+	return nil, errJSONOnly
+}
+
+func (t thriftClient) DescribeWatchdog(ctx context.Context, request *types.DescribeWatchdogRequest, opts ...yarpc.CallOption) (*types.DescribeWatchdogResponse, error) {
+	return nil, errJSONOnly
+}
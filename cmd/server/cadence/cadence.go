@@ -34,6 +34,8 @@ import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/client"
 	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/secret"
 	"github.com/uber/cadence/common/service"
 	"github.com/uber/cadence/tools/cassandra"
 	"github.com/uber/cadence/tools/sql"
@@ -44,6 +46,11 @@ var validServices = service.ShortNames(service.List)
 
 // startHandler is the handler for the cli start command
 func startHandler(c *cli.Context) {
+	if c.Bool("dev") {
+		devHandler(c)
+		return
+	}
+
 	env := getEnvironment(c)
 	zone := getZone(c)
 	configDir := getConfigDir(c)
@@ -56,6 +63,9 @@ func startHandler(c *cli.Context) {
 	if err != nil {
 		log.Fatal("Config file corrupted.", err)
 	}
+	if cfg.Zone == "" {
+		cfg.Zone = zone
+	}
 	if cfg.Log.Level == "debug" {
 		log.Printf("config=\n%v\n", cfg.String())
 	}
@@ -65,6 +75,23 @@ func startHandler(c *cli.Context) {
 		cfg.DynamicConfig.FileBased.Filepath = constructPathIfNeed(rootDir, cfg.DynamicConfig.FileBased.Filepath)
 	}
 
+	var daemons []common.Daemon
+	if cfg.Secrets.Vault != nil {
+		zapLogger, err := cfg.Log.NewZapLogger()
+		if err != nil {
+			log.Fatal("failed to create the zap logger, err: ", err.Error())
+		}
+		vaultProvider, err := secret.NewVaultProvider(cfg.Secrets.Vault, loggerimpl.NewLogger(zapLogger))
+		if err != nil {
+			log.Fatalf("failed to create vault secret provider: %v", err)
+		}
+		if err := cfg.ResolveSecrets(map[string]config.SecretProvider{config.SecretSchemeVault: vaultProvider}); err != nil {
+			log.Fatalf("failed to resolve secrets: %v", err)
+		}
+		daemons = append(daemons, vaultProvider)
+		vaultProvider.Start()
+	}
+
 	if err := cfg.ValidateAndFillDefaults(); err != nil {
 		log.Fatalf("config validation failed: %v", err)
 	}
@@ -77,22 +104,26 @@ func startHandler(c *cli.Context) {
 		log.Fatal("sql schema version compatibility check failed: ", err)
 	}
 
-	var daemons []common.Daemon
 	services := getServices(c)
-	sigc := make(chan os.Signal, 1)
-	signal.Notify(sigc, syscall.SIGTERM, syscall.SIGINT)
 	for _, svc := range services {
 		server := newServer(svc, &cfg)
 		daemons = append(daemons, server)
 		server.Start()
 	}
 
+	waitForShutdown(daemons)
+	os.Exit(0)
+}
+
+// waitForShutdown blocks until the process is asked to terminate, and then stops the daemons
+func waitForShutdown(daemons []common.Daemon) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGTERM, syscall.SIGINT)
 	<-sigc
 	log.Println("Received SIGTERM signal, initiating shutdown.")
 	for _, daemon := range daemons {
 		daemon.Stop()
 	}
-	os.Exit(0)
 }
 
 func getEnvironment(c *cli.Context) string {
@@ -210,6 +241,18 @@ func BuildCLI(releaseVersion string, gitRevision string) *cli.App {
 					Value: strings.Join(validServices, ","),
 					Usage: "list of services to start",
 				},
+				cli.BoolFlag{
+					Name:  "dev",
+					Usage: "start all the services in one process with sqlite persistence, no config file is needed",
+				},
+				cli.StringFlag{
+					Name:  "dev-data-dir",
+					Usage: "directory of the sqlite databases in dev mode, a temporary directory removed on shutdown if empty",
+				},
+				cli.IntFlag{
+					Name:  "dev-ui-port",
+					Usage: "port of the web UI served in dev mode, the UI is disabled if 0",
+				},
 			},
 			Action: func(c *cli.Context) {
 				startHandler(c)
@@ -25,6 +25,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	apiv1 "github.com/uber/cadence-idl/go/proto/api/v1"
+
+	"github.com/uber/cadence/common/persistence/sql/sqlplugin/sqlite"
 )
 
 type CadenceSuite struct {
@@ -54,3 +57,26 @@ func (s *CadenceSuite) TestPath() {
 	s.Equal("foo/bar", constructPathIfNeed("foo", "bar"))
 	s.Equal("/bar", constructPathIfNeed("foo", "/bar"))
 }
+
+func (s *CadenceSuite) TestDevConfig() {
+	cfg := newDevConfig("/tmp/cadence-dev")
+	s.NoError(cfg.ValidateAndFillDefaults())
+	for _, svc := range validServices {
+		_, err := cfg.GetServiceConfig(svc)
+		s.NoError(err)
+	}
+	for _, store := range []string{cfg.Persistence.DefaultStore, cfg.Persistence.VisibilityStore} {
+		sqlCfg := cfg.Persistence.DataStores[store].SQL
+		s.Equal(sqlite.PluginName, sqlCfg.PluginName)
+		s.Equal("/tmp/cadence-dev", sqlCfg.ConnectAddr)
+	}
+	s.False(cfg.Persistence.IsAdvancedVisibilityConfigExist())
+	s.Equal("127.0.0.1:7833", cfg.PublicClient.HostPort)
+}
+
+func (s *CadenceSuite) TestDevUIEventType() {
+	s.Equal("WorkflowExecutionStarted", eventType(&apiv1.HistoryEvent{
+		Attributes: &apiv1.HistoryEvent_WorkflowExecutionStartedEventAttributes{},
+	}))
+	s.Equal("", eventType(&apiv1.HistoryEvent{}))
+}
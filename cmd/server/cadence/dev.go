@@ -0,0 +1,160 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cadence
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/peerprovider/memprovider"
+	"github.com/uber/cadence/common/persistence/sql/sqlplugin/sqlite"
+	"github.com/uber/cadence/common/service"
+)
+
+const (
+	devClusterName      = "cluster0"
+	devNumHistoryShards = 4
+	devFrontendGRPCPort = 7833
+)
+
+// devHandler is the handler for the cli start command in dev mode, it runs all the services in this process with
+// the data stored in sqlite databases and without any config file, Kafka or ElasticSearch
+func devHandler(c *cli.Context) {
+	dataDir := c.String("dev-data-dir")
+	ephemeral := dataDir == ""
+	if ephemeral {
+		dir, err := ioutil.TempDir("", "cadence-dev")
+		if err != nil {
+			log.Fatalf("failed to create data dir: %v", err)
+		}
+		dataDir = dir
+	} else if err := os.MkdirAll(dataDir, 0755); err != nil {
+		log.Fatalf("failed to create data dir: %v", err)
+	}
+
+	cfg := newDevConfig(dataDir)
+	if err := cfg.ValidateAndFillDefaults(); err != nil {
+		log.Fatalf("config validation failed: %v", err)
+	}
+
+	memberships := memprovider.NewRegistry()
+	var daemons []common.Daemon
+	for _, svc := range validServices {
+		server := &server{
+			cfg:         cfg,
+			name:        svc,
+			doneC:       make(chan struct{}),
+			memberships: memberships,
+		}
+		daemons = append(daemons, server)
+		server.Start()
+	}
+
+	frontendAddress := fmt.Sprintf("127.0.0.1:%v", devFrontendGRPCPort)
+	if port := c.Int("dev-ui-port"); port > 0 {
+		ui := newDevUI(fmt.Sprintf("127.0.0.1:%v", port), frontendAddress)
+		daemons = append(daemons, ui)
+		ui.Start()
+		log.Printf("Cadence dev UI listening on http://127.0.0.1:%v\n", port)
+	}
+	log.Printf("Cadence dev server started; frontend=%v,dataDir=%v\n", frontendAddress, dataDir)
+
+	waitForShutdown(daemons)
+	if ephemeral {
+		os.RemoveAll(dataDir)
+	}
+	os.Exit(0)
+}
+
+// newDevConfig returns the config of a single host cluster whose services discover each other in memory
+func newDevConfig(dataDir string) *config.Config {
+	services := map[string]config.Service{}
+	for i, svc := range []string{service.Frontend, service.History, service.Matching} {
+		services[service.ShortName(svc)] = config.Service{
+			RPC: config.RPC{
+				Port:            uint16(7933 + i),
+				GRPCPort:        uint16(devFrontendGRPCPort + i),
+				BindOnLocalHost: true,
+				GRPCMaxMsgSize:  33554432,
+			},
+		}
+	}
+	services[service.ShortName(service.Worker)] = config.Service{
+		RPC: config.RPC{
+			Port:            7939,
+			BindOnLocalHost: true,
+		},
+	}
+
+	return &config.Config{
+		Persistence: config.Persistence{
+			DefaultStore:     "sqlite-default",
+			VisibilityStore:  "sqlite-visibility",
+			NumHistoryShards: devNumHistoryShards,
+			DataStores: map[string]config.DataStore{
+				"sqlite-default": {
+					SQL: &config.SQL{
+						PluginName:   sqlite.PluginName,
+						DatabaseName: "cadence",
+						ConnectAddr:  dataDir,
+					},
+				},
+				"sqlite-visibility": {
+					SQL: &config.SQL{
+						PluginName:   sqlite.PluginName,
+						DatabaseName: "cadence_visibility",
+						ConnectAddr:  dataDir,
+					},
+				},
+			},
+		},
+		Log: config.Logger{
+			Stdout: true,
+			Level:  "info",
+		},
+		Services: services,
+		ClusterGroupMetadata: &config.ClusterGroupMetadata{
+			FailoverVersionIncrement: 10,
+			PrimaryClusterName:       devClusterName,
+			CurrentClusterName:       devClusterName,
+			ClusterGroup: map[string]config.ClusterInformation{
+				devClusterName: {
+					Enabled:      true,
+					RPCName:      service.Frontend,
+					RPCAddress:   fmt.Sprintf("127.0.0.1:%v", devFrontendGRPCPort),
+					RPCTransport: "grpc",
+				},
+			},
+		},
+		DynamicConfig: config.DynamicConfig{
+			Client: dynamicconfig.NopClient,
+		},
+	}
+}
@@ -0,0 +1,277 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cadence
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	apiv1 "github.com/uber/cadence-idl/go/proto/api/v1"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/transport/grpc"
+
+	"github.com/uber/cadence/common/service"
+)
+
+const (
+	devUIPageSize       = 100
+	devUIRequestTimeout = 10 * time.Second
+)
+
+type (
+	// devUI serves a read-only web view of the domains and workflows of a dev server
+	devUI struct {
+		server     *http.Server
+		dispatcher *yarpc.Dispatcher
+		domains    apiv1.DomainAPIYARPCClient
+		visibility apiv1.VisibilityAPIYARPCClient
+		workflows  apiv1.WorkflowAPIYARPCClient
+	}
+
+	devUIWorkflows struct {
+		Domain string
+		Open   devUIExecutions
+		Closed devUIExecutions
+	}
+
+	devUIExecutions struct {
+		Domain     string
+		Executions []*apiv1.WorkflowExecutionInfo
+	}
+
+	devUIHistory struct {
+		Domain    string
+		Execution *apiv1.WorkflowExecution
+		Events    []*apiv1.HistoryEvent
+	}
+)
+
+var devUITemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"timestamp": formatTimestamp,
+	"eventType": eventType,
+	"details":   eventDetails,
+}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html><head><title>Cadence dev server</title>
+<style>body{font-family:sans-serif}table{border-collapse:collapse}td,th{border:1px solid #ccc;padding:4px 8px;text-align:left;vertical-align:top}pre{margin:0}</style>
+</head><body><h2><a href="/">Cadence dev server</a></h2>{{end}}
+{{define "footer"}}</body></html>{{end}}
+
+{{define "domains"}}{{template "header"}}
+<h3>Domains</h3>
+<table><tr><th>Name</th><th>Status</th><th>Description</th></tr>
+{{range .}}<tr><td><a href="/workflows?domain={{.Name}}">{{.Name}}</a></td><td>{{.Status}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>
+{{template "footer"}}{{end}}
+
+{{define "executions"}}<table><tr><th>Workflow ID</th><th>Run ID</th><th>Type</th><th>Start time</th><th>Close time</th><th>Status</th></tr>
+{{range .Executions}}<tr><td>{{.WorkflowExecution.WorkflowId}}</td>
+<td><a href="/history?domain={{$.Domain}}&workflow_id={{.WorkflowExecution.WorkflowId}}&run_id={{.WorkflowExecution.RunId}}">{{.WorkflowExecution.RunId}}</a></td>
+<td>{{.Type.Name}}</td><td>{{timestamp .StartTime}}</td><td>{{timestamp .CloseTime}}</td><td>{{if .CloseTime}}{{.CloseStatus}}{{else}}OPEN{{end}}</td></tr>
+{{end}}</table>{{end}}
+
+{{define "workflows"}}{{template "header"}}
+<h3>Open workflows of {{.Domain}}</h3>
+{{template "executions" .Open}}
+<h3>Closed workflows of {{.Domain}}</h3>
+{{template "executions" .Closed}}
+{{template "footer"}}{{end}}
+
+{{define "history"}}{{template "header"}}
+<h3>History of {{.Execution.WorkflowId}} / {{.Execution.RunId}}</h3>
+<table><tr><th>ID</th><th>Time</th><th>Type</th><th>Details</th></tr>
+{{range .Events}}<tr><td>{{.EventId}}</td><td>{{timestamp .EventTime}}</td><td>{{eventType .}}</td><td><pre>{{details .}}</pre></td></tr>
+{{end}}</table>
+{{template "footer"}}{{end}}
+`))
+
+// newDevUI creates the web UI listening on the address, it calls the frontend at frontendAddress over gRPC
+func newDevUI(address string, frontendAddress string) *devUI {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{
+		Name: "cadence-dev-ui",
+		Outbounds: yarpc.Outbounds{
+			service.Frontend: {Unary: grpc.NewTransport().NewSingleOutbound(frontendAddress)},
+		},
+	})
+	clientConfig := dispatcher.ClientConfig(service.Frontend)
+	ui := &devUI{
+		dispatcher: dispatcher,
+		domains:    apiv1.NewDomainAPIYARPCClient(clientConfig),
+		visibility: apiv1.NewVisibilityAPIYARPCClient(clientConfig),
+		workflows:  apiv1.NewWorkflowAPIYARPCClient(clientConfig),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", ui.listDomains)
+	mux.HandleFunc("/workflows", ui.listWorkflows)
+	mux.HandleFunc("/history", ui.getHistory)
+	ui.server = &http.Server{Addr: address, Handler: mux}
+	return ui
+}
+
+// Start starts serving the UI
+func (u *devUI) Start() {
+	if err := u.dispatcher.Start(); err != nil {
+		log.Fatalf("failed to start dev UI dispatcher: %v", err)
+	}
+	go func() {
+		if err := u.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("dev UI stopped serving: %v\n", err)
+		}
+	}()
+}
+
+// Stop stops serving the UI
+func (u *devUI) Stop() {
+	u.server.Close()
+	u.dispatcher.Stop()
+}
+
+func (u *devUI) listDomains(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), devUIRequestTimeout)
+	defer cancel()
+
+	resp, err := u.domains.ListDomains(ctx, &apiv1.ListDomainsRequest{PageSize: devUIPageSize})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	u.render(w, "domains", resp.GetDomains())
+}
+
+func (u *devUI) listWorkflows(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), devUIRequestTimeout)
+	defer cancel()
+
+	domain := r.URL.Query().Get("domain")
+	filter := &apiv1.StartTimeFilter{
+		EarliestTime: &types.Timestamp{},
+		LatestTime:   types.TimestampNow(),
+	}
+	open, err := u.visibility.ListOpenWorkflowExecutions(ctx, &apiv1.ListOpenWorkflowExecutionsRequest{
+		Domain:          domain,
+		PageSize:        devUIPageSize,
+		StartTimeFilter: filter,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	closed, err := u.visibility.ListClosedWorkflowExecutions(ctx, &apiv1.ListClosedWorkflowExecutionsRequest{
+		Domain:          domain,
+		PageSize:        devUIPageSize,
+		StartTimeFilter: filter,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	u.render(w, "workflows", &devUIWorkflows{
+		Domain: domain,
+		Open:   devUIExecutions{Domain: domain, Executions: open.GetExecutions()},
+		Closed: devUIExecutions{Domain: domain, Executions: closed.GetExecutions()},
+	})
+}
+
+func (u *devUI) getHistory(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), devUIRequestTimeout)
+	defer cancel()
+
+	query := r.URL.Query()
+	history := &devUIHistory{
+		Domain: query.Get("domain"),
+		Execution: &apiv1.WorkflowExecution{
+			WorkflowId: query.Get("workflow_id"),
+			RunId:      query.Get("run_id"),
+		},
+	}
+	var token []byte
+	for {
+		resp, err := u.workflows.GetWorkflowExecutionHistory(ctx, &apiv1.GetWorkflowExecutionHistoryRequest{
+			Domain:            history.Domain,
+			WorkflowExecution: history.Execution,
+			PageSize:          devUIPageSize,
+			NextPageToken:     token,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		history.Events = append(history.Events, resp.GetHistory().GetEvents()...)
+		if token = resp.GetNextPageToken(); len(token) == 0 {
+			break
+		}
+	}
+	u.render(w, "history", history)
+}
+
+func (u *devUI) render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := devUITemplates.ExecuteTemplate(w, name, data); err != nil {
+		log.Printf("failed to render dev UI page %v: %v\n", name, err)
+	}
+}
+
+func formatTimestamp(ts *types.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	t, err := types.TimestampFromProto(ts)
+	if err != nil {
+		return ""
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+// eventType returns the type of the event from the name of its attributes, e.g. WorkflowExecutionStarted
+func eventType(event *apiv1.HistoryEvent) string {
+	if event.Attributes == nil {
+		return ""
+	}
+	name := reflect.TypeOf(event.Attributes).Elem().Name()
+	name = strings.TrimPrefix(name, "HistoryEvent_")
+	return strings.TrimSuffix(name, "EventAttributes")
+}
+
+func eventDetails(event *apiv1.HistoryEvent) string {
+	if event.Attributes == nil {
+		return ""
+	}
+	attributes := reflect.ValueOf(event.Attributes).Elem().Field(0).Interface()
+	details, err := json.MarshalIndent(attributes, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(details)
+}
@@ -22,43 +22,90 @@ package cadence
 
 import (
 	"log"
+	"net/http"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"go.uber.org/cadence/.gen/go/cadence/workflowserviceclient"
 	"go.uber.org/cadence/compatibility"
+	"go.uber.org/yarpc"
+	"go.uber.org/zap"
 
 	apiv1 "github.com/uber/cadence-idl/go/proto/api/v1"
 
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/archiver"
 	"github.com/uber/cadence/common/archiver/provider"
+	"github.com/uber/cadence/common/authorization"
 	"github.com/uber/cadence/common/blobstore/filestore"
+	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/crash"
+	"github.com/uber/cadence/common/diagnostics"
 	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/dynamicconfig/configstore"
+	"github.com/uber/cadence/common/dynamicconfig/remote"
 	"github.com/uber/cadence/common/elasticsearch"
+	"github.com/uber/cadence/common/health"
+	"github.com/uber/cadence/common/loadshedding"
 	"github.com/uber/cadence/common/log/loggerimpl"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/membership"
 	"github.com/uber/cadence/common/messaging/kafka"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/peerprovider/etcdprovider"
+	"github.com/uber/cadence/common/peerprovider/k8sprovider"
+	"github.com/uber/cadence/common/peerprovider/memprovider"
 	"github.com/uber/cadence/common/peerprovider/ringpopprovider"
 	"github.com/uber/cadence/common/resource"
 	"github.com/uber/cadence/common/rpc"
 	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/tracing"
 	"github.com/uber/cadence/service/frontend"
 	"github.com/uber/cadence/service/history"
 	"github.com/uber/cadence/service/matching"
 	"github.com/uber/cadence/service/worker"
 )
 
+// setGlobalTracer guards the global tracer which is shared by all services of the process
+var setGlobalTracer sync.Once
+
+// healthRegistry holds the health checks of all services of the process, they are served on the pprof server
+// and on the dedicated health listener if it is configured
+var (
+	healthRegistry        = health.NewRegistry()
+	registerHealthHandler sync.Once
+)
+
+// cacheRegistry holds the in-memory caches of all services of the process, they are introspected on the pprof server
+var (
+	cacheRegistry        = cache.NewRegistry()
+	registerCacheHandler sync.Once
+)
+
+// diagnosticsCollector collects profiles and verbose logs of all services of the process, it is served on the pprof server
+var (
+	diagnosticsCollector       = diagnostics.NewCollector()
+	registerDiagnosticsHandler sync.Once
+)
+
+// crashReporter reports the panics captured by all services of the process, it is created by the first service started
+var (
+	crashReporter    *crash.Reporter
+	setCrashReporter sync.Once
+)
+
 type (
 	server struct {
 		name   string
 		cfg    *config.Config
 		doneC  chan struct{}
 		daemon common.Daemon
+		// memberships is set when all the services run in this process and discover each other in memory
+		memberships *memprovider.Registry
 	}
 )
 
@@ -110,9 +157,11 @@ func (s *server) startService() common.Daemon {
 	if err != nil {
 		log.Fatal("failed to create the zap logger, err: ", err.Error())
 	}
+	zapLogger = zapLogger.WithOptions(zap.WrapCore(diagnosticsCollector.WrapCore))
 	params.Logger = loggerimpl.NewLogger(zapLogger).WithTags(tag.Service(params.Name))
 
 	params.PersistenceConfig = s.cfg.Persistence
+	params.MetricScope = svcCfg.Metrics.NewScope(params.Logger, params.Name)
 
 	err = nil
 	if s.cfg.DynamicConfig.Client == "" {
@@ -131,6 +180,24 @@ func (s *server) startService() common.Daemon {
 		case dynamicconfig.FileBasedClient:
 			params.Logger.Info("initialising File Based dynamic config client")
 			params.DynamicConfig, err = dynamicconfig.NewFileBasedClient(&s.cfg.DynamicConfig.FileBased, params.Logger, s.doneC)
+		case dynamicconfig.S3Client:
+			params.Logger.Info("initialising S3 dynamic config client")
+			var source dynamicconfig.RemoteSource
+			if source, err = remote.NewS3Source(&s.cfg.DynamicConfig.S3); err == nil {
+				params.DynamicConfig, err = dynamicconfig.NewRemoteClient(source, &s.cfg.DynamicConfig.S3.RemoteClientConfig, params.Logger, params.MetricScope, s.doneC)
+			}
+		case dynamicconfig.EtcdClient:
+			params.Logger.Info("initialising etcd dynamic config client")
+			var source dynamicconfig.RemoteSource
+			if source, err = remote.NewEtcdSource(&s.cfg.DynamicConfig.Etcd); err == nil {
+				params.DynamicConfig, err = dynamicconfig.NewRemoteClient(source, &s.cfg.DynamicConfig.Etcd.RemoteClientConfig, params.Logger, params.MetricScope, s.doneC)
+			}
+		case dynamicconfig.ConsulClient:
+			params.Logger.Info("initialising Consul dynamic config client")
+			var source dynamicconfig.RemoteSource
+			if source, err = remote.NewConsulSource(&s.cfg.DynamicConfig.Consul); err == nil {
+				params.DynamicConfig, err = dynamicconfig.NewRemoteClient(source, &s.cfg.DynamicConfig.Consul.RemoteClientConfig, params.Logger, params.MetricScope, s.doneC)
+			}
 		default:
 			params.Logger.Info("initialising NOP dynamic config client")
 			params.DynamicConfig = dynamicconfig.NewNopClient()
@@ -148,33 +215,135 @@ func (s *server) startService() common.Daemon {
 		params.Logger,
 		dynamicconfig.ClusterNameFilter(clusterGroupMetadata.CurrentClusterName),
 	)
+	registerHealthHandler.Do(func() {
+		healthHandler := health.NewHandler(healthRegistry)
+		http.Handle(health.LivenessPath, healthHandler)
+		http.Handle(health.ReadinessPath, healthHandler)
+		if s.cfg.Health.HostPort != "" {
+			if err := health.NewServer(s.cfg.Health.HostPort, healthRegistry, params.Logger).Start(); err != nil {
+				log.Fatalf("error starting health server: %v", err)
+			}
+		}
+	})
+	params.HealthRegistry = healthRegistry
+	registerCacheHandler.Do(func() {
+		http.Handle(cache.IntrospectionHandlerPath, cache.NewIntrospectionHandler(cacheRegistry))
+	})
+	params.CacheRegistry = cacheRegistry
+	registerDiagnosticsHandler.Do(func() {
+		// admin tokens are required even though the pprof server only listens on localhost,
+		// the diagnostics cannot be collected unless the OAuth authorizer is enabled
+		var validateToken func(string) error
+		if s.cfg.Authorization.OAuthAuthorizer.Enable {
+			if validateToken, err = authorization.NewAdminTokenValidator(s.cfg.Authorization.OAuthAuthorizer); err != nil {
+				log.Fatalf("error creating diagnostics token validator: %v", err)
+			}
+		}
+		http.Handle(diagnostics.HandlerPath, diagnostics.NewHandler(diagnosticsCollector, validateToken))
+	})
+
+	tracer, err := tracing.NewTracer(&s.cfg.Tracing, params.Name, params.Logger, s.doneC)
+	if err != nil {
+		log.Fatalf("error creating tracer: %v", err)
+	}
+	// global tracer is only used to start traces which are not part of an inbound request
+	setGlobalTracer.Do(func() {
+		opentracing.SetGlobalTracer(tracer)
+	})
+
+	tagValuesLimit := dc.GetIntProperty(dynamicconfig.MetricsTagValuesLimit)
+	params.MetricsClient = metrics.NewClient(
+		params.MetricScope,
+		service.GetMetricsServiceIdx(params.Name, params.Logger),
+		metrics.WithTagValuesLimit(func() int { return tagValuesLimit() }),
+	)
 
-	params.MetricScope = svcCfg.Metrics.NewScope(params.Logger, params.Name)
+	setCrashReporter.Do(func() {
+		sink, err := crash.NewSink(&s.cfg.CrashReport, &s.cfg.Blobstore)
+		if err != nil {
+			log.Printf("failed to create crash report sink, crash reports will only be logged: %v", err)
+		}
+		host, _ := os.Hostname()
+		crashReporter = crash.NewReporter(sink, params.MetricsClient, params.Logger, host)
+		crash.Install(crashReporter)
+	})
 
-	rpcParams, err := rpc.NewParams(params.Name, s.cfg, dc)
+	rpcParams, err := rpc.NewParams(params.Name, s.cfg, dc, params.Logger, params.MetricsClient)
 	if err != nil {
 		log.Fatalf("error creating rpc factory params: %v", err)
 	}
+	// the shed requests are rejected outermost, before any work is done for them, and the requests are tracked
+	// innermost, once the other middleware have annotated the context
+	loadMonitor := loadshedding.NewMonitor(loadshedding.NewConfig(dc), params.MetricsClient, params.Logger)
+	rpcParams.InboundMiddleware.Unary = yarpc.UnaryInboundMiddleware(
+		&rpc.LoadSheddingMiddleware{Monitor: loadMonitor},
+		rpcParams.InboundMiddleware.Unary,
+		&rpc.CrashContextMiddleware{Reporter: crashReporter},
+	)
+	params.LoadMonitor = loadMonitor
+	rpcParams.Tracer = tracer
+	params.SLOTracker = rpcParams.SLOTracker
 	rpcParams.OutboundsBuilder = rpc.CombineOutbounds(
 		rpcParams.OutboundsBuilder,
-		rpc.NewCrossDCOutbounds(clusterGroupMetadata.ClusterGroup, rpc.NewDNSPeerChooserFactory(s.cfg.PublicClient.RefreshInterval, params.Logger)),
+		rpc.NewCrossDCOutbounds(clusterGroupMetadata.ClusterGroup, rpc.NewDNSPeerChooserFactory(
+			s.cfg.PublicClient.RefreshInterval,
+			dc.GetIntProperty(dynamicconfig.GRPCPeerSubsetSize)(),
+			params.Logger,
+		)),
 	)
 	rpcFactory := rpc.NewFactory(params.Logger, rpcParams)
 	params.RPCFactory = rpcFactory
 
-	peerProvider, err := ringpopprovider.New(
-		params.Name,
-		&s.cfg.Ringpop,
-		rpcFactory.GetChannel(),
-		membership.PortMap{
-			membership.PortGRPC:     svcCfg.RPC.GRPCPort,
-			membership.PortTchannel: svcCfg.RPC.Port,
-		},
-		params.Logger,
-	)
-
+	adminRPCParams, err := rpc.NewAdminParams(params.Name, s.cfg, rpcParams)
 	if err != nil {
-		log.Fatalf("ringpop provider failed: %v", err)
+		log.Fatalf("error creating admin rpc factory params: %v", err)
+	}
+	if adminRPCParams != nil {
+		params.AdminRPCFactory = rpc.NewAdminFactory(params.Logger, *adminRPCParams)
+		params.AdminAuthorizationConfig = svcCfg.AdminRPC.Authorization
+	}
+
+	portMap := membership.PortMap{
+		membership.PortGRPC:     svcCfg.RPC.GRPCPort,
+		membership.PortTchannel: svcCfg.RPC.Port,
+	}
+	var peerProvider membership.PeerProvider
+	if s.memberships != nil {
+		self := membership.NewDetailedHostInfo(rpcParams.TChannelAddress, rpcParams.TChannelAddress, portMap).WithZone(s.cfg.Zone)
+		peerProvider = s.memberships.NewProvider(params.Name, self)
+	} else if s.cfg.KubernetesMembership != nil {
+		peerProvider, err = k8sprovider.New(
+			params.Name,
+			s.cfg.KubernetesMembership,
+			portMap,
+			params.Logger,
+		)
+		if err != nil {
+			log.Fatalf("kubernetes provider failed: %v", err)
+		}
+	} else if s.cfg.EtcdMembership != nil {
+		peerProvider, err = etcdprovider.New(
+			params.Name,
+			s.cfg.EtcdMembership,
+			portMap,
+			s.cfg.Zone,
+			params.Logger,
+		)
+		if err != nil {
+			log.Fatalf("etcd provider failed: %v", err)
+		}
+	} else {
+		peerProvider, err = ringpopprovider.New(
+			params.Name,
+			&s.cfg.Ringpop,
+			rpcFactory.GetChannel(),
+			portMap,
+			s.cfg.Zone,
+			params.Logger,
+		)
+		if err != nil {
+			log.Fatalf("ringpop provider failed: %v", err)
+		}
 	}
 
 	params.MembershipResolver, err = membership.NewResolver(
@@ -188,8 +357,6 @@ func (s *server) startService() common.Daemon {
 
 	params.ClusterRedirectionPolicy = s.cfg.ClusterGroupMetadata.ClusterRedirectionPolicy
 
-	params.MetricsClient = metrics.NewClient(params.MetricScope, service.GetMetricsServiceIdx(params.Name, params.Logger))
-
 	params.ClusterMetadata = cluster.NewMetadata(
 		clusterGroupMetadata.FailoverVersionIncrement,
 		clusterGroupMetadata.PrimaryClusterName,
@@ -258,6 +425,16 @@ func (s *server) startService() common.Daemon {
 	params.PersistenceConfig.TransactionSizeLimit = dc.GetIntProperty(dynamicconfig.TransactionSizeLimit)
 	params.PersistenceConfig.ErrorInjectionRate = dc.GetFloat64Property(dynamicconfig.PersistenceErrorInjectionRate)
 	params.AuthorizationConfig = s.cfg.Authorization
+	if s.cfg.TaskTokenSigning != nil {
+		signingKeys, err := s.cfg.TaskTokenSigning.GetSigningKeys()
+		if err != nil {
+			log.Fatalf("error loading task token signing keys: %v", err)
+		}
+		params.TaskTokenSerializer, err = common.NewSignedTaskTokenSerializer(signingKeys, time.Now)
+		if err != nil {
+			log.Fatalf("error creating task token serializer: %v", err)
+		}
+	}
 	params.BlobstoreClient, err = filestore.NewFilestoreClient(s.cfg.Blobstore.Filestore)
 	if err != nil {
 		log.Printf("failed to create file blobstore client, will continue startup without it: %v", err)
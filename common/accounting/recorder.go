@@ -0,0 +1,122 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package accounting tracks the resources consumed by each domain, so that the usage of a multi-tenant cluster
+// can be charged back to the domain owners. The usage is aggregated in memory by the Recorder and periodically
+// persisted by the reporter as one record per host and interval.
+package accounting
+
+import (
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	// Recorder aggregates the resources consumed by domains in memory until they are reported
+	Recorder interface {
+		persistence.UsageRecorder
+		// Drain returns the usage recorded since the last drain by domain name
+		Drain() map[string]*persistence.DomainUsage
+	}
+
+	recorderImpl struct {
+		enabled dynamicconfig.BoolPropertyFn
+
+		sync.Mutex
+		usage map[string]*persistence.DomainUsage
+	}
+
+	noopRecorder struct{}
+)
+
+var _ Recorder = (*recorderImpl)(nil)
+
+// NewRecorder creates a recorder, usage is only recorded while enabled returns true
+func NewRecorder(enabled dynamicconfig.BoolPropertyFn) Recorder {
+	return &recorderImpl{
+		enabled: enabled,
+		usage:   make(map[string]*persistence.DomainUsage),
+	}
+}
+
+// NewNoopRecorder creates a recorder which discards the usage
+func NewNoopRecorder() Recorder {
+	return noopRecorder{}
+}
+
+func (r *recorderImpl) RecordUsage(domainName string, usage persistence.DomainUsage) {
+	if domainName == "" || !r.enabled() {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	domainUsage, ok := r.usage[domainName]
+	if !ok {
+		domainUsage = &persistence.DomainUsage{}
+		r.usage[domainName] = domainUsage
+	}
+	domainUsage.Add(&usage)
+}
+
+func (r *recorderImpl) Drain() map[string]*persistence.DomainUsage {
+	r.Lock()
+	defer r.Unlock()
+
+	usage := r.usage
+	r.usage = make(map[string]*persistence.DomainUsage, len(usage))
+	return usage
+}
+
+func (noopRecorder) RecordUsage(string, persistence.DomainUsage) {}
+
+func (noopRecorder) Drain() map[string]*persistence.DomainUsage {
+	return nil
+}
+
+// AggregateUsage sums the usage of the records by domain, only the records which
+// ended after start and not after end are included
+func AggregateUsage(
+	records []*persistence.DomainUsageRecord,
+	start time.Time,
+	end time.Time,
+) map[string]*persistence.DomainUsage {
+	result := make(map[string]*persistence.DomainUsage)
+	for _, record := range records {
+		if !record.IntervalEnd.After(start) || record.IntervalEnd.After(end) {
+			continue
+		}
+		for domainName, usage := range record.Domains {
+			total, ok := result[domainName]
+			if !ok {
+				total = &persistence.DomainUsage{}
+				result[domainName] = total
+			}
+			total.Add(usage)
+		}
+	}
+	return result
+}
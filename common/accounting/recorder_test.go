@@ -0,0 +1,88 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package accounting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/persistence"
+)
+
+func TestRecorder(t *testing.T) {
+	enabled := true
+	recorder := NewRecorder(func(...dynamicconfig.FilterOption) bool { return enabled })
+
+	recorder.RecordUsage("domain-a", persistence.DomainUsage{Actions: 1, PayloadBytes: 10})
+	recorder.RecordUsage("domain-a", persistence.DomainUsage{Actions: 1, PersistenceWriteUnits: 2})
+	recorder.RecordUsage("domain-b", persistence.DomainUsage{TaskDispatches: 1})
+	recorder.RecordUsage("", persistence.DomainUsage{Actions: 1})
+	enabled = false
+	recorder.RecordUsage("domain-b", persistence.DomainUsage{TaskDispatches: 1})
+
+	assert.Equal(t, map[string]*persistence.DomainUsage{
+		"domain-a": {Actions: 2, PayloadBytes: 10, PersistenceWriteUnits: 2},
+		"domain-b": {TaskDispatches: 1},
+	}, recorder.Drain())
+	assert.Empty(t, recorder.Drain())
+}
+
+func TestNoopRecorder(t *testing.T) {
+	recorder := NewNoopRecorder()
+	recorder.RecordUsage("domain-a", persistence.DomainUsage{Actions: 1})
+	assert.Empty(t, recorder.Drain())
+}
+
+func TestAggregateUsage(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	records := []*persistence.DomainUsageRecord{
+		{
+			IntervalEnd: start,
+			Domains:     map[string]*persistence.DomainUsage{"domain-a": {Actions: 100}},
+		},
+		{
+			IntervalEnd: start.Add(time.Minute),
+			Domains: map[string]*persistence.DomainUsage{
+				"domain-a": {Actions: 1, PersistenceReadUnits: 3},
+				"domain-b": {TaskDispatches: 2},
+			},
+		},
+		{
+			IntervalEnd: end,
+			Domains:     map[string]*persistence.DomainUsage{"domain-a": {Actions: 2, PayloadBytes: 5}},
+		},
+		{
+			IntervalEnd: end.Add(time.Minute),
+			Domains:     map[string]*persistence.DomainUsage{"domain-b": {TaskDispatches: 100}},
+		},
+	}
+
+	assert.Equal(t, map[string]*persistence.DomainUsage{
+		"domain-a": {Actions: 3, PersistenceReadUnits: 3, PayloadBytes: 5},
+		"domain-b": {TaskDispatches: 2},
+	}, AggregateUsage(records, start, end))
+}
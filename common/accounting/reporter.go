@@ -0,0 +1,142 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package accounting
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/persistence"
+)
+
+const reportTimeout = 10 * time.Second
+
+type (
+	reporter struct {
+		status      int32
+		recorder    Recorder
+		store       persistence.ConfigStoreManager
+		serviceName string
+		interval    dynamicconfig.DurationPropertyFn
+		retention   dynamicconfig.DurationPropertyFn
+		timeSource  clock.TimeSource
+		logger      log.Logger
+
+		intervalStart time.Time
+		shutdownC     chan struct{}
+		shutdownWG    sync.WaitGroup
+	}
+)
+
+// NewReporter creates a daemon which persists the usage aggregated by the recorder at every interval
+func NewReporter(
+	recorder Recorder,
+	store persistence.ConfigStoreManager,
+	serviceName string,
+	interval dynamicconfig.DurationPropertyFn,
+	retention dynamicconfig.DurationPropertyFn,
+	timeSource clock.TimeSource,
+	logger log.Logger,
+) common.Daemon {
+	return &reporter{
+		status:      common.DaemonStatusInitialized,
+		recorder:    recorder,
+		store:       store,
+		serviceName: serviceName,
+		interval:    interval,
+		retention:   retention,
+		timeSource:  timeSource,
+		logger:      logger,
+		shutdownC:   make(chan struct{}),
+	}
+}
+
+func (r *reporter) Start() {
+	if !atomic.CompareAndSwapInt32(&r.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	r.intervalStart = r.timeSource.Now()
+	r.shutdownWG.Add(1)
+	go r.reportLoop()
+}
+
+// Stop reports the usage of the ongoing interval before returning
+func (r *reporter) Stop() {
+	if !atomic.CompareAndSwapInt32(&r.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+
+	close(r.shutdownC)
+	r.shutdownWG.Wait()
+	r.report()
+}
+
+func (r *reporter) reportLoop() {
+	defer r.shutdownWG.Done()
+
+	timer := time.NewTimer(r.interval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			r.report()
+			timer.Reset(r.interval())
+		case <-r.shutdownC:
+			return
+		}
+	}
+}
+
+func (r *reporter) report() {
+	now := r.timeSource.Now()
+	usage := r.recorder.Drain()
+	record := &persistence.DomainUsageRecord{
+		Service:       r.serviceName,
+		IntervalStart: r.intervalStart,
+		IntervalEnd:   now,
+		Domains:       usage,
+	}
+	r.intervalStart = now
+	if len(usage) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+	if err := r.store.RecordDomainUsage(ctx, &persistence.RecordDomainUsageRequest{
+		Record: record,
+		TTL:    r.retention(),
+	}); err != nil {
+		// the usage of the interval is dropped rather than merged into the next one, so that records
+		// always cover the interval they are reported for
+		r.logger.Error("Failed to record domain usage", tag.Error(err), tag.Number(int64(len(usage))))
+	}
+}
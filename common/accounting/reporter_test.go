@@ -0,0 +1,79 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package accounting
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/persistence"
+)
+
+func TestReporterStopReportsOngoingInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := persistence.NewMockConfigStoreManager(ctrl)
+	timeSource := clock.NewEventTimeSource()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeSource.Update(start)
+
+	recorder := NewRecorder(dynamicconfig.GetBoolPropertyFn(true))
+	reporter := NewReporter(recorder, store, "frontend", dynamicconfig.GetDurationPropertyFn(time.Hour), dynamicconfig.GetDurationPropertyFn(time.Hour*24), timeSource, log.NewNoop())
+	reporter.Start()
+
+	recorder.RecordUsage("domain-a", persistence.DomainUsage{Actions: 1})
+	timeSource.Update(start.Add(time.Minute))
+
+	store.EXPECT().RecordDomainUsage(gomock.Any(), &persistence.RecordDomainUsageRequest{
+		Record: &persistence.DomainUsageRecord{
+			Service:       "frontend",
+			IntervalStart: start,
+			IntervalEnd:   start.Add(time.Minute),
+			Domains:       map[string]*persistence.DomainUsage{"domain-a": {Actions: 1}},
+		},
+		TTL: time.Hour * 24,
+	}).Return(errors.New("failed")).Times(1)
+	reporter.Stop()
+}
+
+func TestReporterSkipsEmptyInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := persistence.NewMockConfigStoreManager(ctrl)
+	recorder := NewRecorder(dynamicconfig.GetBoolPropertyFn(true))
+	reporter := NewReporter(recorder, store, "history", dynamicconfig.GetDurationPropertyFn(time.Millisecond), dynamicconfig.GetDurationPropertyFn(time.Hour*24), clock.NewRealTimeSource(), log.NewNoop())
+	reporter.Start()
+	time.Sleep(10 * time.Millisecond)
+	reporter.Stop()
+
+	assert.Empty(t, recorder.Drain())
+}
@@ -0,0 +1,302 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/yarpc"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/persistence"
+)
+
+const (
+	// APIKeyAllDomains is the domain scope of the API keys which can access all the domains
+	APIKeyAllDomains = "*"
+
+	defaultAPIKeyRefreshInterval = 10 * time.Second
+	apiKeyUpdateMaxAttempts      = 5
+	apiKeySeparator              = "."
+)
+
+type apiKeyAuthority struct {
+	store           persistence.ConfigStoreManager
+	next            Authorizer
+	refreshInterval time.Duration
+	timeSource      clock.TimeSource
+	log             log.Logger
+
+	sync.Mutex
+	keys        map[string]*persistence.APIKey
+	refreshTime time.Time
+}
+
+// NewAPIKeyAuthorizer creates an authority authorizing the requests with an API key against the keys of the config store,
+// the requests without an API key are authorized by the next authorizer
+func NewAPIKeyAuthorizer(
+	authorizationCfg config.APIKeys,
+	store persistence.ConfigStoreManager,
+	next Authorizer,
+	timeSource clock.TimeSource,
+	log log.Logger,
+) Authorizer {
+	refreshInterval := authorizationCfg.RefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = defaultAPIKeyRefreshInterval
+	}
+	return &apiKeyAuthority{
+		store:           store,
+		next:            next,
+		refreshInterval: refreshInterval,
+		timeSource:      timeSource,
+		log:             log,
+	}
+}
+
+// Authorize validates the API key of the request, if any, and checks the domain and permission are in its scope
+func (a *apiKeyAuthority) Authorize(
+	ctx context.Context,
+	attributes *Attributes,
+) (Result, error) {
+	apiKey := yarpc.CallFromContext(ctx).Header(common.APIKeyHeaderName)
+	if apiKey == "" {
+		return a.next.Authorize(ctx, attributes)
+	}
+
+	key, err := a.getKey(ctx, apiKey)
+	if err != nil {
+		return Result{Decision: DecisionDeny}, err
+	}
+	if err := validateAPIKey(key, attributes, a.timeSource.Now()); err != nil {
+		a.log.Debug("request is not authorized", tag.Error(err))
+		return Result{Decision: DecisionDeny}, nil
+	}
+	return Result{Decision: DecisionAllow}, nil
+}
+
+// getKey returns the key matching the secret, or nil if there is none
+func (a *apiKeyAuthority) getKey(ctx context.Context, apiKey string) (*persistence.APIKey, error) {
+	separator := strings.Index(apiKey, apiKeySeparator)
+	if separator < 0 {
+		return nil, nil
+	}
+	id, secret := apiKey[:separator], apiKey[separator+1:]
+
+	a.Lock()
+	defer a.Unlock()
+
+	if now := a.timeSource.Now(); now.Sub(a.refreshTime) >= a.refreshInterval {
+		resp, err := a.store.FetchAPIKeys(ctx)
+		switch {
+		case err == nil:
+			a.keys = make(map[string]*persistence.APIKey)
+			if resp.Snapshot != nil {
+				for _, key := range resp.Snapshot.Keys {
+					a.keys[key.ID] = key
+				}
+			}
+			a.refreshTime = now
+		case a.keys == nil:
+			return nil, err
+		default:
+			// the keys are refreshed again on the next request, the previous keys are used meanwhile
+			a.log.Warn("failed to refresh API keys", tag.Error(err))
+		}
+	}
+
+	key, ok := a.keys[id]
+	if !ok || subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashAPIKeySecret(secret))) != 1 {
+		return nil, nil
+	}
+	return key, nil
+}
+
+func validateAPIKey(key *persistence.APIKey, attributes *Attributes, now time.Time) error {
+	if key == nil {
+		return fmt.Errorf("API key is invalid")
+	}
+	if !key.RevokedTime.IsZero() {
+		return fmt.Errorf("API key %v is revoked", key.ID)
+	}
+	if !key.ExpiryTime.IsZero() && !now.Before(key.ExpiryTime) {
+		return fmt.Errorf("API key %v is expired", key.ID)
+	}
+
+	inDomains := false
+	for _, domain := range key.Domains {
+		if domain == APIKeyAllDomains || (domain == attributes.DomainName && domain != "") {
+			inDomains = true
+			break
+		}
+	}
+	if !inDomains {
+		return fmt.Errorf("API key %v doesn't have access to domain %q", key.ID, attributes.DomainName)
+	}
+
+	// the read APIs can be called with the write permission, and all of them with the admin permission
+	for _, permission := range key.Permissions {
+		if p := NewPermission(permission); p == attributes.Permission || p == PermissionAdmin ||
+			(p == PermissionWrite && attributes.Permission == PermissionRead) {
+			return nil
+		}
+	}
+	return fmt.Errorf("API key %v doesn't have permission for %v API", key.ID, attributes.APIName)
+}
+
+// IssueAPIKey issues a new API key scoped to the domains and permissions, a zero ttl never expires.
+// The returned API key is the only copy of its secret.
+func IssueAPIKey(
+	ctx context.Context,
+	store persistence.ConfigStoreManager,
+	name string,
+	domains []string,
+	permissions []string,
+	ttl time.Duration,
+	now time.Time,
+) (string, *persistence.APIKey, error) {
+	if len(domains) == 0 {
+		return "", nil, fmt.Errorf("API key must have at least one domain")
+	}
+	if len(permissions) == 0 {
+		return "", nil, fmt.Errorf("API key must have at least one permission")
+	}
+	for _, permission := range permissions {
+		if NewPermission(permission) < 0 {
+			return "", nil, fmt.Errorf("invalid permission %q, must be one of read, write or admin", permission)
+		}
+	}
+
+	id, err := randomString(8, hex.EncodeToString)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return "", nil, err
+	}
+	key := &persistence.APIKey{
+		ID:          id,
+		Name:        name,
+		Hash:        hashAPIKeySecret(secret),
+		Domains:     domains,
+		Permissions: permissions,
+		CreatedTime: now,
+	}
+	if ttl > 0 {
+		key.ExpiryTime = now.Add(ttl)
+	}
+
+	err = updateAPIKeys(ctx, store, func(keys []*persistence.APIKey) ([]*persistence.APIKey, error) {
+		return append(keys, key), nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return id + apiKeySeparator + secret, key, nil
+}
+
+// RevokeAPIKey revokes the API key, the frontend hosts deny it once they refreshed their keys
+func RevokeAPIKey(
+	ctx context.Context,
+	store persistence.ConfigStoreManager,
+	id string,
+	now time.Time,
+) error {
+	return updateAPIKeys(ctx, store, func(keys []*persistence.APIKey) ([]*persistence.APIKey, error) {
+		for i, key := range keys {
+			if key.ID == id {
+				// the fetched snapshot is not updated in place, as it may be shared with its readers
+				revoked := *key
+				if revoked.RevokedTime.IsZero() {
+					revoked.RevokedTime = now
+				}
+				keys = append([]*persistence.APIKey{}, keys...)
+				keys[i] = &revoked
+				return keys, nil
+			}
+		}
+		return nil, fmt.Errorf("API key %v doesn't exist", id)
+	})
+}
+
+// updateAPIKeys writes the next snapshot of the keys, the update is retried when a concurrent update wrote it first
+func updateAPIKeys(
+	ctx context.Context,
+	store persistence.ConfigStoreManager,
+	update func([]*persistence.APIKey) ([]*persistence.APIKey, error),
+) error {
+	var err error
+	for attempt := 0; attempt < apiKeyUpdateMaxAttempts; attempt++ {
+		var resp *persistence.FetchAPIKeysResponse
+		var keys []*persistence.APIKey
+		resp, err = store.FetchAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+		snapshot := resp.Snapshot
+		if snapshot == nil {
+			snapshot = &persistence.APIKeySnapshot{}
+		}
+		keys, err = update(snapshot.Keys)
+		if err != nil {
+			return err
+		}
+		err = store.UpdateAPIKeys(ctx, &persistence.UpdateAPIKeysRequest{
+			Snapshot: &persistence.APIKeySnapshot{
+				Version: snapshot.Version + 1,
+				Keys:    keys,
+			},
+		})
+		if _, ok := err.(*persistence.ConditionFailedError); !ok {
+			return err
+		}
+	}
+	return err
+}
+
+func hashAPIKeySecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func randomString(size int, encode func([]byte) string) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encode(b), nil
+}
@@ -0,0 +1,140 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/encoding"
+	"go.uber.org/yarpc/api/transport"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/persistence"
+)
+
+// inMemoryAPIKeyStore keeps the latest snapshot of the API keys
+type inMemoryAPIKeyStore struct {
+	persistence.ConfigStoreManager
+	snapshot *persistence.APIKeySnapshot
+	fetchErr error
+	fetches  int
+}
+
+func (s *inMemoryAPIKeyStore) FetchAPIKeys(_ context.Context) (*persistence.FetchAPIKeysResponse, error) {
+	s.fetches++
+	if s.fetchErr != nil {
+		return nil, s.fetchErr
+	}
+	return &persistence.FetchAPIKeysResponse{Snapshot: s.snapshot}, nil
+}
+
+func (s *inMemoryAPIKeyStore) UpdateAPIKeys(_ context.Context, request *persistence.UpdateAPIKeysRequest) error {
+	if s.snapshot != nil && request.Snapshot.Version <= s.snapshot.Version {
+		return &persistence.ConditionFailedError{}
+	}
+	s.snapshot = request.Snapshot
+	return nil
+}
+
+func newAPIKeyTestContext(t *testing.T, apiKey string) context.Context {
+	ctx, call := encoding.NewInboundCall(context.Background())
+	require.NoError(t, call.ReadFromRequest(&transport.Request{
+		Headers: transport.NewHeaders().With(common.APIKeyHeaderName, apiKey),
+	}))
+	return ctx
+}
+
+func TestAPIKeyAuthorizer(t *testing.T) {
+	store := &inMemoryAPIKeyStore{}
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	authorizer := NewAPIKeyAuthorizer(config.APIKeys{RefreshInterval: time.Minute}, store, &nopAuthority{}, timeSource, loggerimpl.NewNopLogger())
+
+	readKey, _, err := IssueAPIKey(context.Background(), store, "reader", []string{"test-domain"}, []string{"read"}, time.Hour, timeSource.Now())
+	require.NoError(t, err)
+	writeKey, key, err := IssueAPIKey(context.Background(), store, "writer", []string{APIKeyAllDomains}, []string{"write"}, 0, timeSource.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), store.snapshot.Version)
+	assert.NotContains(t, key.Hash, writeKey[len(key.ID)+1:])
+
+	for _, tc := range []struct {
+		apiKey     string
+		domain     string
+		permission Permission
+		expected   Decision
+	}{
+		{"", "test-domain", PermissionAdmin, DecisionAllow},
+		{readKey, "test-domain", PermissionRead, DecisionAllow},
+		{readKey, "test-domain", PermissionWrite, DecisionDeny},
+		{readKey, "other-domain", PermissionRead, DecisionDeny},
+		{readKey, "", PermissionRead, DecisionDeny},
+		{readKey + "x", "test-domain", PermissionRead, DecisionDeny},
+		{"invalid", "test-domain", PermissionRead, DecisionDeny},
+		{writeKey, "other-domain", PermissionRead, DecisionAllow},
+		{writeKey, "other-domain", PermissionWrite, DecisionAllow},
+		{writeKey, "", PermissionAdmin, DecisionDeny},
+	} {
+		result, err := authorizer.Authorize(newAPIKeyTestContext(t, tc.apiKey), &Attributes{DomainName: tc.domain, Permission: tc.permission})
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, result.Decision, "%+v", tc)
+	}
+	// the keys were fetched twice to be issued, and once by the authorizer
+	assert.Equal(t, 3, store.fetches)
+
+	// the revocation is applied once the keys are refreshed, the previous keys are kept while the store is unavailable
+	require.NoError(t, RevokeAPIKey(context.Background(), store, key.ID, timeSource.Now()))
+	store.fetchErr = errors.New("unavailable")
+	timeSource.Update(timeSource.Now().Add(time.Minute))
+	result, err := authorizer.Authorize(newAPIKeyTestContext(t, writeKey), &Attributes{DomainName: "test-domain", Permission: PermissionWrite})
+	require.NoError(t, err)
+	assert.Equal(t, DecisionAllow, result.Decision)
+
+	store.fetchErr = nil
+	timeSource.Update(timeSource.Now().Add(time.Minute))
+	result, err = authorizer.Authorize(newAPIKeyTestContext(t, writeKey), &Attributes{DomainName: "test-domain", Permission: PermissionWrite})
+	require.NoError(t, err)
+	assert.Equal(t, DecisionDeny, result.Decision)
+
+	// the read key expires after an hour
+	timeSource.Update(timeSource.Now().Add(time.Hour))
+	result, err = authorizer.Authorize(newAPIKeyTestContext(t, readKey), &Attributes{DomainName: "test-domain", Permission: PermissionRead})
+	require.NoError(t, err)
+	assert.Equal(t, DecisionDeny, result.Decision)
+}
+
+func TestIssueAPIKey_Invalid(t *testing.T) {
+	store := &inMemoryAPIKeyStore{}
+	_, _, err := IssueAPIKey(context.Background(), store, "test", nil, []string{"read"}, 0, time.Now())
+	assert.Error(t, err)
+	_, _, err = IssueAPIKey(context.Background(), store, "test", []string{"test-domain"}, []string{"delete"}, 0, time.Now())
+	assert.Error(t, err)
+	assert.Error(t, RevokeAPIKey(context.Background(), store, "unknown", time.Now()))
+	assert.Nil(t, store.snapshot)
+}
@@ -58,6 +58,8 @@ type (
 		WorkflowType *types.WorkflowType
 		TaskList     *types.TaskList
 		Permission   Permission
+		// RequestBody is the request of the API, for the authorizers deciding on the target workflow
+		RequestBody interface{}
 	}
 
 	// Result is result from authority.
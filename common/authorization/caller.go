@@ -0,0 +1,74 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/cristalhq/jwt/v3"
+	"go.uber.org/yarpc"
+
+	"github.com/uber/cadence/common"
+)
+
+const (
+	callerPrefixAPIKey = "apikey:"
+	callerPrefixJWT    = "jwt:"
+)
+
+// GetCallerIdentity returns the identity of the caller from the credentials of the request, i.e. the ID of its
+// API key or the subject of its JWT token, or an empty string if the request has no credentials.
+// The credentials are not verified here, they are verified by the authorizers before the request is handled.
+func GetCallerIdentity(ctx context.Context) string {
+	call := yarpc.CallFromContext(ctx)
+	if apiKey := call.Header(common.APIKeyHeaderName); apiKey != "" {
+		separator := strings.Index(apiKey, apiKeySeparator)
+		if separator <= 0 {
+			return ""
+		}
+		return callerPrefixAPIKey + apiKey[:separator]
+	}
+
+	token := call.Header(common.AuthorizationTokenHeaderName)
+	if token == "" {
+		return ""
+	}
+	parsed, err := jwt.ParseString(token)
+	if err != nil {
+		return ""
+	}
+	var claims JWTClaims
+	if err := json.Unmarshal(parsed.RawClaims(), &claims); err != nil {
+		return ""
+	}
+	switch {
+	case claims.Sub != "":
+		return callerPrefixJWT + claims.Sub
+	case claims.Name != "":
+		return callerPrefixJWT + claims.Name
+	default:
+		return ""
+	}
+}
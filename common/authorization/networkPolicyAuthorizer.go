@@ -0,0 +1,137 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"net"
+
+	"github.com/uber/tchannel-go"
+	"google.golang.org/grpc/peer"
+
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+)
+
+// permissionAll is the key of the allowlists applying to all the permissions
+const permissionAll Permission = 0
+
+type networkPolicyAuthority struct {
+	allowlists map[string]map[Permission][]*net.IPNet
+	next       Authorizer
+	log        log.Logger
+}
+
+// NewNetworkPolicyAuthorizer creates an authority rejecting the requests whose source address is not in the
+// allowlist of their domain and permission, the other requests are authorized by the next authorizer
+func NewNetworkPolicyAuthorizer(
+	networkPolicyCfg config.NetworkPolicy,
+	next Authorizer,
+	log log.Logger,
+) (Authorizer, error) {
+	allowlists := make(map[string]map[Permission][]*net.IPNet, len(networkPolicyCfg.Allowlists))
+	for domain, allowlist := range networkPolicyCfg.Allowlists {
+		allowlists[domain] = make(map[Permission][]*net.IPNet, len(allowlist))
+		for category, addresses := range allowlist {
+			permission := permissionAll
+			if category != config.NetworkPolicyWildcard {
+				permission = NewPermission(category)
+			}
+			networks := make([]*net.IPNet, 0, len(addresses))
+			for _, address := range addresses {
+				network, err := config.ParseNetworkAddress(address)
+				if err != nil {
+					return nil, err
+				}
+				networks = append(networks, network)
+			}
+			allowlists[domain][permission] = networks
+		}
+	}
+	return &networkPolicyAuthority{
+		allowlists: allowlists,
+		next:       next,
+		log:        log,
+	}, nil
+}
+
+// Authorize checks the source address of the request is allowed before authorizing it with the next authorizer
+func (a *networkPolicyAuthority) Authorize(
+	ctx context.Context,
+	attributes *Attributes,
+) (Result, error) {
+	networks, ok := a.getAllowlist(attributes.DomainName, attributes.Permission)
+	if !ok {
+		return a.next.Authorize(ctx, attributes)
+	}
+
+	address := getSourceAddress(ctx)
+	if ip := net.ParseIP(address); ip != nil {
+		for _, network := range networks {
+			if network.Contains(ip) {
+				return a.next.Authorize(ctx, attributes)
+			}
+		}
+	}
+
+	a.log.Warn("request is rejected by the network policy",
+		tag.Address(address),
+		tag.APIName(attributes.APIName),
+		tag.WorkflowDomainName(attributes.DomainName),
+		tag.Caller(GetCallerIdentity(ctx)),
+	)
+	return Result{Decision: DecisionDeny}, nil
+}
+
+// getAllowlist returns the allowlist of the domain for the permission, falling back to its allowlist for all the
+// permissions, then to the allowlists of all the domains
+func (a *networkPolicyAuthority) getAllowlist(domain string, permission Permission) ([]*net.IPNet, bool) {
+	for _, d := range []string{domain, config.NetworkPolicyWildcard} {
+		allowlist, ok := a.allowlists[d]
+		if !ok {
+			continue
+		}
+		for _, p := range []Permission{permission, permissionAll} {
+			if networks, ok := allowlist[p]; ok {
+				return networks, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// getSourceAddress returns the IP of the caller, or an empty string if it is unknown
+func getSourceAddress(ctx context.Context) string {
+	var hostPort string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		hostPort = p.Addr.String()
+	} else if call := tchannel.CurrentCall(ctx); call != nil {
+		hostPort = call.RemotePeer().HostPort
+	}
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return hostPort
+	}
+	return host
+}
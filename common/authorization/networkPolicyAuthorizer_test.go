@@ -0,0 +1,149 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/peer"
+
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log/loggerimpl"
+)
+
+func newPeerTestContext(address string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(address), Port: 7833},
+	})
+}
+
+func TestNetworkPolicyAuthorizer(t *testing.T) {
+	authorizer, err := NewNetworkPolicyAuthorizer(config.NetworkPolicy{
+		Enable: true,
+		Allowlists: map[string]config.NetworkAllowlist{
+			"*":            {"admin": {"10.0.0.0/8"}},
+			"test-domain":  {"*": {"192.168.1.0/24"}, "write": {"192.168.2.10"}},
+			"other-domain": {"read": {"2001:db8::/32"}},
+		},
+	}, &nopAuthority{}, loggerimpl.NewNopLogger())
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		address    string
+		domain     string
+		permission Permission
+		decision   Decision
+	}{
+		"domain category allowed": {
+			address:    "192.168.2.10",
+			domain:     "test-domain",
+			permission: PermissionWrite,
+			decision:   DecisionAllow,
+		},
+		"domain category takes precedence over domain wildcard": {
+			address:    "192.168.1.10",
+			domain:     "test-domain",
+			permission: PermissionWrite,
+			decision:   DecisionDeny,
+		},
+		"domain wildcard allowed": {
+			address:    "192.168.1.10",
+			domain:     "test-domain",
+			permission: PermissionRead,
+			decision:   DecisionAllow,
+		},
+		"domain wildcard takes precedence over all domains": {
+			address:    "10.0.0.1",
+			domain:     "test-domain",
+			permission: PermissionAdmin,
+			decision:   DecisionDeny,
+		},
+		"all domains allowed": {
+			address:    "10.0.0.1",
+			domain:     "other-domain",
+			permission: PermissionAdmin,
+			decision:   DecisionAllow,
+		},
+		"all domains applies to APIs without domain": {
+			address:    "172.16.0.1",
+			permission: PermissionAdmin,
+			decision:   DecisionDeny,
+		},
+		"IPv6 allowed": {
+			address:    "2001:db8::1",
+			domain:     "other-domain",
+			permission: PermissionRead,
+			decision:   DecisionAllow,
+		},
+		"IPv6 rejected": {
+			address:    "2001:db9::1",
+			domain:     "other-domain",
+			permission: PermissionRead,
+			decision:   DecisionDeny,
+		},
+		"no allowlist": {
+			address:    "172.16.0.1",
+			domain:     "other-domain",
+			permission: PermissionWrite,
+			decision:   DecisionAllow,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := authorizer.Authorize(newPeerTestContext(test.address), &Attributes{
+				APIName:    "TestAPI",
+				DomainName: test.domain,
+				Permission: test.permission,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, test.decision, result.Decision)
+		})
+	}
+
+	// the requests of unknown source address are rejected if an allowlist applies
+	result, err := authorizer.Authorize(context.Background(), &Attributes{DomainName: "test-domain", Permission: PermissionRead})
+	require.NoError(t, err)
+	assert.Equal(t, DecisionDeny, result.Decision)
+}
+
+func TestNetworkPolicyAuthorizerDelegates(t *testing.T) {
+	authorizer, err := NewNetworkPolicyAuthorizer(config.NetworkPolicy{
+		Enable:     true,
+		Allowlists: map[string]config.NetworkAllowlist{"*": {"*": {"10.0.0.0/8"}}},
+	}, &denyAuthority{}, loggerimpl.NewNopLogger())
+	require.NoError(t, err)
+
+	result, err := authorizer.Authorize(newPeerTestContext("10.0.0.1"), &Attributes{DomainName: "test-domain", Permission: PermissionRead})
+	require.NoError(t, err)
+	assert.Equal(t, DecisionDeny, result.Decision)
+}
+
+type denyAuthority struct{}
+
+func (a *denyAuthority) Authorize(_ context.Context, _ *Attributes) (Result, error) {
+	return Result{Decision: DecisionDeny}, nil
+}
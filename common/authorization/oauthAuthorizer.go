@@ -74,6 +74,39 @@ func NewOAuthAuthorizer(
 	}, nil
 }
 
+// NewAdminTokenValidator creates a validator for requests which are not served through yarpc,
+// the validator accepts only unexpired tokens with the admin claim
+func NewAdminTokenValidator(authorizationCfg config.OAuthAuthorizer) (func(token string) error, error) {
+	publicKey, err := common.LoadRSAPublicKey(authorizationCfg.JwtCredentials.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	a := &oauthAuthority{
+		authorizationCfg: authorizationCfg,
+		publicKey:        publicKey,
+	}
+	return func(token string) error {
+		if token == "" {
+			return fmt.Errorf("token is not set")
+		}
+		verifier, err := a.getVerifier()
+		if err != nil {
+			return err
+		}
+		claims, err := a.parseToken(token, verifier)
+		if err != nil {
+			return err
+		}
+		if err := a.validateTTL(claims); err != nil {
+			return err
+		}
+		if !claims.Admin {
+			return fmt.Errorf("token doesn't have the admin permission")
+		}
+		return nil
+	}, nil
+}
+
 // Authorize defines the logic to verify get claims from token
 func (a *oauthAuthority) Authorize(
 	ctx context.Context,
@@ -0,0 +1,224 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package authorization
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cristalhq/jwt/v3"
+	"go.uber.org/yarpc"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+)
+
+const defaultOPATimeout = time.Second
+
+type (
+	opaAuthority struct {
+		authorizationCfg config.OPAAuthorizer
+		log              log.Logger
+		client           *http.Client
+		// token verifies the JWT of the callers, nil if the claims are not part of the input
+		token *oauthAuthority
+	}
+
+	// OPAInput is the input of the OPA policy
+	OPAInput struct {
+		// Caller is the name of the service making the request
+		Caller string `json:"caller"`
+		// Claims are the claims of the verified JWT of the caller, if any
+		Claims       map[string]interface{} `json:"claims,omitempty"`
+		API          string                 `json:"api"`
+		Domain       string                 `json:"domain,omitempty"`
+		Permission   string                 `json:"permission"`
+		WorkflowType string                 `json:"workflowType,omitempty"`
+		TaskList     string                 `json:"taskList,omitempty"`
+		// Request is the request of the API, so that the policy can decide on the target workflow
+		Request interface{} `json:"request,omitempty"`
+	}
+
+	opaRequest struct {
+		Input *OPAInput `json:"input"`
+	}
+
+	opaResponse struct {
+		Result json.RawMessage `json:"result"`
+	}
+)
+
+// NewOPAAuthorizer creates an authority delegating the decisions to an Open Policy Agent
+func NewOPAAuthorizer(
+	authorizationCfg config.OPAAuthorizer,
+	log log.Logger,
+) (Authorizer, error) {
+	timeout := authorizationCfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultOPATimeout
+	}
+	a := &opaAuthority{
+		authorizationCfg: authorizationCfg,
+		log:              log,
+		client:           &http.Client{Timeout: timeout},
+	}
+	if authorizationCfg.JwtCredentials != nil {
+		publicKey, err := common.LoadRSAPublicKey(authorizationCfg.JwtCredentials.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		a.token = &oauthAuthority{
+			authorizationCfg: config.OAuthAuthorizer{
+				JwtCredentials: *authorizationCfg.JwtCredentials,
+				MaxJwtTTL:      authorizationCfg.MaxJwtTTL,
+			},
+			publicKey: publicKey,
+		}
+	}
+	return a, nil
+}
+
+// Authorize queries the policy with the input built from the call and the attributes,
+// the request is denied if the policy cannot be queried
+func (a *opaAuthority) Authorize(
+	ctx context.Context,
+	attributes *Attributes,
+) (Result, error) {
+	input := &OPAInput{
+		API:        attributes.APIName,
+		Domain:     attributes.DomainName,
+		Permission: permissionName(attributes.Permission),
+		Request:    attributes.RequestBody,
+	}
+	if attributes.WorkflowType != nil {
+		input.WorkflowType = attributes.WorkflowType.GetName()
+	}
+	if attributes.TaskList != nil {
+		input.TaskList = attributes.TaskList.GetName()
+	}
+
+	if call := yarpc.CallFromContext(ctx); call != nil {
+		input.Caller = call.Caller()
+		if token := call.Header(common.AuthorizationTokenHeaderName); token != "" && a.token != nil {
+			claims, err := a.verifyClaims(token)
+			if err != nil {
+				a.log.Debug("request is not authorized", tag.Error(err))
+				return Result{Decision: DecisionDeny}, nil
+			}
+			input.Claims = claims
+		}
+	}
+
+	allowed, err := a.query(ctx, input)
+	if err != nil {
+		return Result{Decision: DecisionDeny}, err
+	}
+	if !allowed {
+		a.log.Debug("request is not authorized", tag.Error(fmt.Errorf("denied by policy for %v API", attributes.APIName)))
+		return Result{Decision: DecisionDeny}, nil
+	}
+	return Result{Decision: DecisionAllow}, nil
+}
+
+func (a *opaAuthority) verifyClaims(token string) (map[string]interface{}, error) {
+	verifier, err := a.token.getVerifier()
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := jwt.ParseAndVerifyString(token, verifier)
+	if err != nil {
+		return nil, err
+	}
+	var claims JWTClaims
+	if err := json.Unmarshal(parsed.RawClaims(), &claims); err != nil {
+		return nil, err
+	}
+	if err := a.token.validateTTL(&claims); err != nil {
+		return nil, err
+	}
+	var rawClaims map[string]interface{}
+	if err := json.Unmarshal(parsed.RawClaims(), &rawClaims); err != nil {
+		return nil, err
+	}
+	return rawClaims, nil
+}
+
+func (a *opaAuthority) query(ctx context.Context, input *OPAInput) (bool, error) {
+	body, err := json.Marshal(&opaRequest{Input: input})
+	if err != nil {
+		return false, err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, a.authorizationCfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := a.client.Do(request)
+	if err != nil {
+		return false, fmt.Errorf("failed to query OPA: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to query OPA: %v", response.Status)
+	}
+
+	var decision opaResponse
+	if err := json.NewDecoder(response.Body).Decode(&decision); err != nil {
+		return false, fmt.Errorf("failed to decode OPA decision: %v", err)
+	}
+	// an undefined decision, e.g. no rule matched and there is no default, denies the request
+	if len(decision.Result) == 0 {
+		return false, nil
+	}
+	var allowed bool
+	if err := json.Unmarshal(decision.Result, &allowed); err == nil {
+		return allowed, nil
+	}
+	var result struct {
+		Allow bool `json:"allow"`
+	}
+	if err := json.Unmarshal(decision.Result, &result); err != nil {
+		return false, fmt.Errorf("failed to decode OPA decision: %v", err)
+	}
+	return result.Allow, nil
+}
+
+func permissionName(permission Permission) string {
+	switch permission {
+	case PermissionRead:
+		return "read"
+	case PermissionWrite:
+		return "write"
+	case PermissionAdmin:
+		return "admin"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backoff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+const (
+	// maxCalendarExclusionSkips bounds the fires skipped because of exclusions when looking for the next one
+	maxCalendarExclusionSkips = 10000
+)
+
+var calendarParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+type (
+	// CalendarScheduleSpec is the calendar based schedule of a cron workflow, it is set as the cron schedule
+	// of the workflow in JSON format, e.g.
+	//   {"calendars": [{"hour": "9-17", "dayOfWeek": "1-5"}], "exclude": [{"month": "12", "dayOfMonth": "25"}], "timezone": "America/New_York"}
+	// The schedule fires at the union of its calendars and intervals, unless the time matches one of its exclusions.
+	CalendarScheduleSpec struct {
+		Calendars []CalendarSpec `json:"calendars,omitempty"`
+		Intervals []IntervalSpec `json:"intervals,omitempty"`
+		Exclude   []CalendarSpec `json:"exclude,omitempty"`
+		Timezone  string         `json:"timezone,omitempty"`
+	}
+
+	// CalendarSpec matches times with the cron syntax for each field, an empty field
+	// defaults to "0" for seconds, minutes and hours of calendars and to "*" otherwise
+	CalendarSpec struct {
+		Second     string `json:"second,omitempty"`
+		Minute     string `json:"minute,omitempty"`
+		Hour       string `json:"hour,omitempty"`
+		DayOfMonth string `json:"dayOfMonth,omitempty"`
+		Month      string `json:"month,omitempty"`
+		DayOfWeek  string `json:"dayOfWeek,omitempty"`
+	}
+
+	// IntervalSpec fires every given duration since the unix epoch, shifted by the offset
+	IntervalSpec struct {
+		Every  string `json:"every"`
+		Offset string `json:"offset,omitempty"`
+	}
+
+	// CalendarSchedule is the cron.Schedule of a CalendarScheduleSpec
+	CalendarSchedule struct {
+		calendars []cron.Schedule
+		intervals []intervalSchedule
+		exclude   []cron.Schedule
+		location  *time.Location
+	}
+
+	intervalSchedule struct {
+		everySeconds  int64
+		offsetSeconds int64
+	}
+)
+
+// IsCalendarSchedule returns whether the cron schedule of a workflow is a calendar based schedule
+func IsCalendarSchedule(cronSchedule string) bool {
+	return strings.HasPrefix(strings.TrimSpace(cronSchedule), "{")
+}
+
+// ParseCalendarSchedule parses a calendar based schedule in JSON format
+func ParseCalendarSchedule(spec string) (*CalendarSchedule, error) {
+	var scheduleSpec CalendarScheduleSpec
+	decoder := json.NewDecoder(strings.NewReader(spec))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&scheduleSpec); err != nil {
+		return nil, err
+	}
+	if len(scheduleSpec.Calendars) == 0 && len(scheduleSpec.Intervals) == 0 {
+		return nil, fmt.Errorf("at least one calendar or interval is required")
+	}
+
+	schedule := &CalendarSchedule{location: time.UTC}
+	if scheduleSpec.Timezone != "" {
+		location, err := time.LoadLocation(scheduleSpec.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %v", scheduleSpec.Timezone, err)
+		}
+		schedule.location = location
+	}
+	for _, calendar := range scheduleSpec.Calendars {
+		sched, err := calendar.parse("0")
+		if err != nil {
+			return nil, err
+		}
+		schedule.calendars = append(schedule.calendars, sched)
+	}
+	for _, interval := range scheduleSpec.Intervals {
+		sched, err := interval.parse()
+		if err != nil {
+			return nil, err
+		}
+		schedule.intervals = append(schedule.intervals, sched)
+	}
+	for _, calendar := range scheduleSpec.Exclude {
+		sched, err := calendar.parse("*")
+		if err != nil {
+			return nil, err
+		}
+		schedule.exclude = append(schedule.exclude, sched)
+	}
+	return schedule, nil
+}
+
+// Next returns the next fire of the schedule later than the given time, or the zero time if there is none
+func (s *CalendarSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.location)
+	for i := 0; i < maxCalendarExclusionSkips; i++ {
+		next := s.nextFire(t)
+		if next.IsZero() || !s.isExcluded(next) {
+			return next
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+func (s *CalendarSchedule) nextFire(t time.Time) time.Time {
+	var next time.Time
+	for _, calendar := range s.calendars {
+		if fire := calendar.Next(t); !fire.IsZero() && (next.IsZero() || fire.Before(next)) {
+			next = fire
+		}
+	}
+	for _, interval := range s.intervals {
+		if fire := interval.next(t).In(s.location); next.IsZero() || fire.Before(next) {
+			next = fire
+		}
+	}
+	return next
+}
+
+func (s *CalendarSchedule) isExcluded(t time.Time) bool {
+	for _, exclude := range s.exclude {
+		// cron schedules fire on whole seconds, so t matches if it is the next fire after the second before
+		if exclude.Next(t.Add(-time.Second)).Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c CalendarSpec) parse(defaultTimeOfDay string) (cron.Schedule, error) {
+	fields := []string{
+		withDefault(c.Second, defaultTimeOfDay),
+		withDefault(c.Minute, defaultTimeOfDay),
+		withDefault(c.Hour, defaultTimeOfDay),
+		withDefault(c.DayOfMonth, "*"),
+		withDefault(c.Month, "*"),
+		withDefault(c.DayOfWeek, "*"),
+	}
+	sched, err := calendarParser.Parse(strings.Join(fields, " "))
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar %+v: %v", c, err)
+	}
+	return sched, nil
+}
+
+func (i IntervalSpec) parse() (intervalSchedule, error) {
+	every, err := time.ParseDuration(i.Every)
+	if err != nil || every < time.Second || every%time.Second != 0 {
+		return intervalSchedule{}, fmt.Errorf("invalid interval %q, it must be a whole number of seconds", i.Every)
+	}
+	var offset time.Duration
+	if i.Offset != "" {
+		offset, err = time.ParseDuration(i.Offset)
+		if err != nil || offset < 0 || offset >= every || offset%time.Second != 0 {
+			return intervalSchedule{}, fmt.Errorf("invalid interval offset %q, it must be a whole number of seconds less than the interval", i.Offset)
+		}
+	}
+	return intervalSchedule{
+		everySeconds:  int64(every / time.Second),
+		offsetSeconds: int64(offset / time.Second),
+	}, nil
+}
+
+func (i intervalSchedule) next(t time.Time) time.Time {
+	// unix seconds can be negative, so round down towards minus infinity
+	seconds := t.Unix() - i.offsetSeconds
+	periods := seconds / i.everySeconds
+	if seconds%i.everySeconds < 0 {
+		periods--
+	}
+	return time.Unix((periods+1)*i.everySeconds+i.offsetSeconds, 0)
+}
+
+func withDefault(value string, defaultValue string) string {
+	if strings.TrimSpace(value) == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backoff
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarSchedule(t *testing.T) {
+	var calendarTests = []struct {
+		spec     string
+		from     string
+		expected []string
+	}{
+		{
+			spec:     `{"calendars": [{"second": "*/20", "minute": "*", "hour": "*"}]}`,
+			from:     "2018-12-17T08:00:05Z",
+			expected: []string{"2018-12-17T08:00:20Z", "2018-12-17T08:00:40Z", "2018-12-17T08:01:00Z"},
+		},
+		{
+			// calendars default to midnight
+			spec:     `{"calendars": [{"dayOfWeek": "1-5"}]}`,
+			from:     "2018-12-20T08:00:00Z",
+			expected: []string{"2018-12-21T00:00:00Z", "2018-12-24T00:00:00Z"},
+		},
+		{
+			// union of calendars
+			spec:     `{"calendars": [{"hour": "9"}, {"hour": "17", "minute": "30"}]}`,
+			from:     "2018-12-17T08:00:00Z",
+			expected: []string{"2018-12-17T09:00:00Z", "2018-12-17T17:30:00Z", "2018-12-18T09:00:00Z"},
+		},
+		{
+			// union of calendars and intervals
+			spec:     `{"calendars": [{"hour": "9"}], "intervals": [{"every": "1h", "offset": "30m"}]}`,
+			from:     "2018-12-17T08:00:00Z",
+			expected: []string{"2018-12-17T08:30:00Z", "2018-12-17T09:00:00Z", "2018-12-17T09:30:00Z"},
+		},
+		{
+			spec:     `{"intervals": [{"every": "90s"}]}`,
+			from:     "2018-12-17T08:00:00Z",
+			expected: []string{"2018-12-17T08:01:30Z", "2018-12-17T08:03:00Z"},
+		},
+		{
+			// exclusions match all times of day by default
+			spec:     `{"calendars": [{"hour": "12"}], "exclude": [{"month": "12", "dayOfMonth": "24-26"}]}`,
+			from:     "2018-12-23T13:00:00Z",
+			expected: []string{"2018-12-27T12:00:00Z", "2018-12-28T12:00:00Z"},
+		},
+		{
+			spec:     `{"calendars": [{"hour": "9"}], "timezone": "America/New_York"}`,
+			from:     "2018-12-17T08:00:00Z",
+			expected: []string{"2018-12-17T14:00:00Z", "2018-12-18T14:00:00Z"},
+		},
+		{
+			// daylight saving time
+			spec:     `{"calendars": [{"hour": "9"}], "timezone": "America/New_York"}`,
+			from:     "2019-03-09T15:00:00Z",
+			expected: []string{"2019-03-10T13:00:00Z", "2019-03-11T13:00:00Z"},
+		},
+	}
+	for idx, tt := range calendarTests {
+		t.Run(strconv.Itoa(idx), func(t *testing.T) {
+			sched, err := ValidateSchedule(tt.spec)
+			require.NoError(t, err)
+			next, err := time.Parse(time.RFC3339, tt.from)
+			require.NoError(t, err)
+			for _, expected := range tt.expected {
+				expectedTime, err := time.Parse(time.RFC3339, expected)
+				require.NoError(t, err)
+				next = sched.Next(next)
+				assert.True(t, expectedTime.Equal(next), "expected %v, got %v", expectedTime, next.UTC())
+			}
+		})
+	}
+}
+
+func TestCalendarSchedule_Invalid(t *testing.T) {
+	var invalidSpecs = []string{
+		`{}`,
+		`{"calendars": []}`,
+		`{"calendars": [{"hour": "25"}]}`,
+		`{"calendars": [{"hour": "9"}], "timezone": "Mars/Olympus_Mons"}`,
+		`{"calendars": [{"hour": "9"}], "unknown": true}`,
+		`{"intervals": [{"every": "500ms"}]}`,
+		`{"intervals": [{"every": "1h", "offset": "1h"}]}`,
+		`{"intervals": [{"every": "1h", "offset": "-1m"}]}`,
+		`{"calendars": [{"hour": "9"}], "exclude": [{}]}`,
+		`{"calendars": [{"month": "2", "dayOfMonth": "30"}]}`,
+		`{"calendars": [`,
+	}
+	for idx, spec := range invalidSpecs {
+		t.Run(strconv.Itoa(idx), func(t *testing.T) {
+			_, err := ValidateSchedule(spec)
+			require.ErrorContains(t, err, "Invalid CronSchedule")
+		})
+	}
+}
+
+func TestCalendarSchedule_Backoff(t *testing.T) {
+	sched, err := ValidateSchedule(`{"calendars": [{"minute": "*/15", "hour": "*"}]}`)
+	require.NoError(t, err)
+	start, _ := time.Parse(time.RFC3339, "2018-12-17T08:00:00Z")
+	end, _ := time.Parse(time.RFC3339, "2018-12-17T08:20:00Z")
+	backoff, err := GetBackoffForNextSchedule(sched, start, end, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, backoff)
+
+	backoffSeconds, err := GetBackoffForNextScheduleInSeconds(`{"intervals": [{"every": "45s"}]}`, time.Time{}, time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int32(45), backoffSeconds)
+}
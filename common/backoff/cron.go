@@ -26,6 +26,7 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/dgryski/go-farm"
 	"github.com/robfig/cron"
 
 	"github.com/uber/cadence/common/types"
@@ -34,9 +35,72 @@ import (
 // NoBackoff is used to represent backoff when no cron backoff is needed
 const NoBackoff = time.Duration(-1)
 
+// CronOverlapPolicyHeader is the header of the start request of a cron workflow which sets its overlap policy
+const CronOverlapPolicyHeader = "cadence-cron-overlap-policy"
+
+// CronOverlapPolicy is what a cron workflow does with the fires of its schedule happening while a run is still open
+type CronOverlapPolicy string
+
+const (
+	// CronOverlapPolicySkip skips the fires, the next run starts at the first fire after the run closes. It is the default.
+	CronOverlapPolicySkip CronOverlapPolicy = "skip"
+	// CronOverlapPolicyBufferOne starts the next run as soon as the run closes if at least one fire was missed
+	CronOverlapPolicyBufferOne CronOverlapPolicy = "buffer-one"
+	// CronOverlapPolicyCancelRunning closes the run at the next fire, and starts a new one right away
+	CronOverlapPolicyCancelRunning CronOverlapPolicy = "cancel-running"
+
+	// cronOverlapPolicyAllowAll would start a run at every fire even if the previous runs are still open,
+	// it is rejected since a workflow can only have one open run
+	cronOverlapPolicyAllowAll CronOverlapPolicy = "allow-all"
+)
+
+// GetCronOverlapPolicy returns the overlap policy set in the header of the start request of a cron workflow
+func GetCronOverlapPolicy(header *types.Header) CronOverlapPolicy {
+	if header == nil {
+		return CronOverlapPolicySkip
+	}
+	switch policy := CronOverlapPolicy(header.Fields[CronOverlapPolicyHeader]); policy {
+	case CronOverlapPolicyBufferOne, CronOverlapPolicyCancelRunning:
+		return policy
+	default:
+		return CronOverlapPolicySkip
+	}
+}
+
+// ValidateCronOverlapPolicy validates the overlap policy set in the header of the start request of a cron workflow
+func ValidateCronOverlapPolicy(header *types.Header) error {
+	if header == nil {
+		return nil
+	}
+	value, ok := header.Fields[CronOverlapPolicyHeader]
+	if !ok {
+		return nil
+	}
+	switch CronOverlapPolicy(value) {
+	case CronOverlapPolicySkip, CronOverlapPolicyBufferOne, CronOverlapPolicyCancelRunning:
+		return nil
+	case cronOverlapPolicyAllowAll:
+		return &types.BadRequestError{
+			Message: fmt.Sprintf("Cron overlap policy %q is not supported, a cron workflow can only have one open run, use %v or %v instead",
+				value, CronOverlapPolicyBufferOne, CronOverlapPolicyCancelRunning),
+		}
+	default:
+		return &types.BadRequestError{
+			Message: fmt.Sprintf("Invalid cron overlap policy %q, expected one of %v, %v or %v",
+				value, CronOverlapPolicySkip, CronOverlapPolicyBufferOne, CronOverlapPolicyCancelRunning),
+		}
+	}
+}
+
 // ValidateSchedule validates a cron schedule spec
 func ValidateSchedule(cronSchedule string) (cron.Schedule, error) {
-	sched, err := cron.ParseStandard(cronSchedule)
+	var sched cron.Schedule
+	var err error
+	if IsCalendarSchedule(cronSchedule) {
+		sched, err = ParseCalendarSchedule(cronSchedule)
+	} else {
+		sched, err = cron.ParseStandard(cronSchedule)
+	}
 	if err != nil {
 		return nil, &types.BadRequestError{
 			Message: fmt.Sprintf("Invalid CronSchedule, failed to parse: %q, err: %v", cronSchedule, err),
@@ -81,12 +145,56 @@ func GetBackoffForNextSchedule(
 	backoffInterval := nextScheduleTime.Sub(closeUTCTime)
 	roundedInterval := time.Second * time.Duration(math.Ceil(backoffInterval.Seconds()))
 
-	var jitter time.Duration
-	if jitterStartSeconds > 0 {
-		jitter = time.Duration(rand.Int31n(jitterStartSeconds+1)) * time.Second
+	return roundedInterval + jitter(jitterStartSeconds), nil
+}
+
+// GetBackoffForNextScheduleWithOverlapPolicy calculates the backoff time for the next run like
+// GetBackoffForNextSchedule, except that with the buffer-one policy the next run starts as soon as
+// the run closes if its schedule fired while it was running
+func GetBackoffForNextScheduleWithOverlapPolicy(
+	sched cron.Schedule,
+	startTime time.Time,
+	closeTime time.Time,
+	jitterStartSeconds int32,
+	policy CronOverlapPolicy,
+) (time.Duration, error) {
+	if policy == CronOverlapPolicyBufferOne {
+		missedScheduleTime := sched.Next(startTime.In(time.UTC))
+		if !missedScheduleTime.IsZero() && missedScheduleTime.Before(closeTime.In(time.UTC)) {
+			return jitter(jitterStartSeconds), nil
+		}
 	}
+	return GetBackoffForNextSchedule(sched, startTime, closeTime, jitterStartSeconds)
+}
+
+// GetSmoothingOffset returns the offset within the smoothing window by which the run of a cron workflow
+// firing at fireTime is delayed, the offset is stable for a given workflow so that the runs of workflows
+// sharing the same schedule are spread over the window. The window is capped to the time until the
+// following fire so that no fire is skipped.
+func GetSmoothingOffset(
+	sched cron.Schedule,
+	fireTime time.Time,
+	workflowID string,
+	window time.Duration,
+) time.Duration {
+	if window < time.Second {
+		return 0
+	}
+	if nextFireTime := sched.Next(fireTime); !nextFireTime.IsZero() && nextFireTime.Sub(fireTime) < window {
+		window = nextFireTime.Sub(fireTime)
+	}
+	windowSeconds := uint32(window / time.Second)
+	if windowSeconds == 0 {
+		return 0
+	}
+	return time.Duration(farm.Fingerprint32([]byte(workflowID))%windowSeconds) * time.Second
+}
 
-	return roundedInterval + jitter, nil
+func jitter(jitterStartSeconds int32) time.Duration {
+	if jitterStartSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int31n(jitterStartSeconds+1)) * time.Second
 }
 
 // GetBackoffForNextScheduleInSeconds calculates the backoff time in seconds for the
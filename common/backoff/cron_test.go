@@ -30,6 +30,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/types"
 )
 
 func TestCron(t *testing.T) {
@@ -157,3 +159,80 @@ func TestCronWithJitterStart(t *testing.T) {
 		})
 	}
 }
+
+func TestCronWithOverlapPolicy(t *testing.T) {
+	var overlapTests = []struct {
+		cron      string
+		startTime string
+		endTime   string
+		policy    CronOverlapPolicy
+		result    time.Duration
+	}{
+		{"0 * * * *", "2018-12-17T08:00:00+00:00", "2018-12-17T08:30:00+00:00", CronOverlapPolicySkip, time.Minute * 30},
+		{"0 * * * *", "2018-12-17T08:00:00+00:00", "2018-12-17T08:30:00+00:00", CronOverlapPolicyBufferOne, time.Minute * 30},
+		{"0 * * * *", "2018-12-17T08:00:00+00:00", "2018-12-17T09:30:00+00:00", CronOverlapPolicySkip, time.Minute * 30},
+		{"0 * * * *", "2018-12-17T08:00:00+00:00", "2018-12-17T09:30:00+00:00", CronOverlapPolicyBufferOne, 0},
+		{"0 * * * *", "2018-12-17T08:00:00+00:00", "2018-12-17T11:30:00+00:00", CronOverlapPolicyBufferOne, 0},
+		{"0 * * * *", "2018-12-17T08:00:00+00:00", "2018-12-17T09:30:00+00:00", CronOverlapPolicyCancelRunning, time.Minute * 30},
+	}
+	for idx, tt := range overlapTests {
+		t.Run(strconv.Itoa(idx), func(t *testing.T) {
+			start, _ := time.Parse(time.RFC3339, tt.startTime)
+			end, _ := time.Parse(time.RFC3339, tt.endTime)
+			sched, err := ValidateSchedule(tt.cron)
+			require.NoError(t, err)
+			backoff, err := GetBackoffForNextScheduleWithOverlapPolicy(sched, start, end, 0, tt.policy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.result, backoff)
+		})
+	}
+}
+
+func TestCronOverlapPolicy(t *testing.T) {
+	header := func(value string) *types.Header {
+		return &types.Header{Fields: map[string][]byte{CronOverlapPolicyHeader: []byte(value)}}
+	}
+
+	assert.Equal(t, CronOverlapPolicySkip, GetCronOverlapPolicy(nil))
+	assert.Equal(t, CronOverlapPolicySkip, GetCronOverlapPolicy(&types.Header{}))
+	assert.Equal(t, CronOverlapPolicySkip, GetCronOverlapPolicy(header("unknown")))
+	assert.Equal(t, CronOverlapPolicyBufferOne, GetCronOverlapPolicy(header("buffer-one")))
+	assert.Equal(t, CronOverlapPolicyCancelRunning, GetCronOverlapPolicy(header("cancel-running")))
+
+	assert.NoError(t, ValidateCronOverlapPolicy(nil))
+	assert.NoError(t, ValidateCronOverlapPolicy(&types.Header{}))
+	assert.NoError(t, ValidateCronOverlapPolicy(header("skip")))
+	assert.NoError(t, ValidateCronOverlapPolicy(header("buffer-one")))
+	assert.NoError(t, ValidateCronOverlapPolicy(header("cancel-running")))
+	assert.IsType(t, &types.BadRequestError{}, ValidateCronOverlapPolicy(header("unknown")))
+	err := ValidateCronOverlapPolicy(header("allow-all"))
+	assert.IsType(t, &types.BadRequestError{}, err)
+	assert.Contains(t, err.Error(), "only have one open run")
+}
+
+func TestCronSmoothingOffset(t *testing.T) {
+	sched, err := ValidateSchedule("0 * * * *")
+	require.NoError(t, err)
+	fireTime, _ := time.Parse(time.RFC3339, "2018-12-17T09:00:00+00:00")
+
+	assert.Equal(t, time.Duration(0), GetSmoothingOffset(sched, fireTime, "workflow-id", 0))
+	assert.Equal(t, time.Duration(0), GetSmoothingOffset(sched, fireTime, "workflow-id", time.Millisecond*500))
+
+	// offsets are stable for a workflow and spread over the window
+	offsets := make(map[time.Duration]struct{})
+	for i := 0; i < 100; i++ {
+		workflowID := "workflow-id-" + strconv.Itoa(i)
+		offset := GetSmoothingOffset(sched, fireTime, workflowID, time.Minute*10)
+		assert.Equal(t, offset, GetSmoothingOffset(sched, fireTime, workflowID, time.Minute*10))
+		assert.True(t, offset >= 0 && offset < time.Minute*10)
+		assert.Equal(t, time.Duration(0), offset%time.Second)
+		offsets[offset] = struct{}{}
+	}
+	assert.True(t, len(offsets) > 50)
+
+	// the window is capped to the time until the following fire
+	for i := 0; i < 100; i++ {
+		offset := GetSmoothingOffset(sched, fireTime, "workflow-id-"+strconv.Itoa(i), time.Hour*5)
+		assert.True(t, offset >= 0 && offset < time.Hour)
+	}
+}
@@ -24,6 +24,7 @@ package cache
 
 import (
 	"context"
+	"fmt"
 	"hash/fnv"
 	"sort"
 	"strconv"
@@ -35,6 +36,7 @@ import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/cluster"
+	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/errors"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
@@ -119,6 +121,16 @@ type (
 		callbackLock     sync.Mutex
 		prepareCallbacks map[int]PrepareCallbackFn
 		callbacks        map[int]CallbackFn
+
+		// the entries are copied on every refresh, so their accesses are tracked by domain ID, see Describe
+		hits       int64
+		misses     int64
+		accessByID sync.Map
+	}
+
+	domainAccess struct {
+		hits       int64
+		lastAccess int64
 	}
 
 	// DomainCacheEntries is DomainCacheEntry slice
@@ -138,9 +150,13 @@ type (
 		failoverEndTime             *int64
 		notificationVersion         int64
 		initialized                 bool
+		// loadTime is when the content of the entry was last loaded from the database
+		loadTime time.Time
 	}
 )
 
+var _ Introspector = (*domainCache)(nil)
+
 // NewDomainCache creates a new instance of cache for holding onto domain information to reduce the load on persistence
 func NewDomainCache(
 	domainManager persistence.DomainManager,
@@ -545,6 +561,9 @@ func (c *domainCache) updateIDToDomainCache(
 
 	// initialized will be true when the entry contains valid data
 	triggerCallback := entry.initialized && record.notificationVersion > entry.notificationVersion
+	if !entry.initialized || record.notificationVersion != entry.notificationVersion {
+		entry.loadTime = c.timeSource.Now()
+	}
 
 	entry.info = record.info
 	entry.config = record.config
@@ -570,6 +589,7 @@ func (c *domainCache) getDomain(
 	if cacheHit {
 		return c.getDomainByID(id, true)
 	}
+	atomic.AddInt64(&c.misses, 1)
 
 	if err := c.checkDomainExists(name, ""); err != nil {
 		return nil, err
@@ -602,6 +622,7 @@ func (c *domainCache) getDomainByID(
 	var result *DomainCacheEntry
 	entry, cacheHit := c.cacheByID.Load().(Cache).Get(id).(*DomainCacheEntry)
 	if cacheHit {
+		c.recordAccess(id)
 		entry.mu.RLock()
 		result = entry
 		if deepCopy {
@@ -610,6 +631,7 @@ func (c *domainCache) getDomainByID(
 		entry.mu.RUnlock()
 		return result, nil
 	}
+	atomic.AddInt64(&c.misses, 1)
 
 	if err := c.checkDomainExists("", id); err != nil {
 		return nil, err
@@ -644,6 +666,85 @@ func (c *domainCache) getDomainByID(
 	return nil, &types.InternalServiceError{Message: "domainCache encounter case where domain exists but cannot be loaded"}
 }
 
+func (c *domainCache) recordAccess(id string) {
+	atomic.AddInt64(&c.hits, 1)
+	access, ok := c.accessByID.Load(id)
+	if !ok {
+		access, _ = c.accessByID.LoadOrStore(id, &domainAccess{})
+	}
+	atomic.AddInt64(&access.(*domainAccess).hits, 1)
+	atomic.StoreInt64(&access.(*domainAccess).lastAccess, c.timeSource.Now().UnixNano())
+}
+
+// Describe returns the stats of the domain cache and its most read domains, the age of a domain
+// is the time since its content was last loaded from the database
+func (c *domainCache) Describe(maxEntries int) *Description {
+	now := c.timeSource.Now()
+	domains := c.GetAllDomain()
+	description := &Description{
+		Size:    len(domains),
+		Hits:    atomic.LoadInt64(&c.hits),
+		Misses:  atomic.LoadInt64(&c.misses),
+		Entries: make([]EntryDescription, 0, len(domains)),
+	}
+	description.HitRate = hitRate(description.Hits, description.Misses)
+	for id, domain := range domains {
+		entry := EntryDescription{
+			Key: domainCacheKey(domain.info.Name, id),
+			Age: now.Sub(domain.loadTime),
+		}
+		entry.Idle = entry.Age
+		if access, ok := c.accessByID.Load(id); ok {
+			entry.Hits = atomic.LoadInt64(&access.(*domainAccess).hits)
+			entry.Idle = now.Sub(time.Unix(0, atomic.LoadInt64(&access.(*domainAccess).lastAccess)))
+		}
+		description.Entries = append(description.Entries, entry)
+	}
+	SortEntryDescriptions(description.Entries)
+	if maxEntries >= 0 && len(description.Entries) > maxEntries {
+		description.Entries = description.Entries[:maxEntries]
+	}
+	return description
+}
+
+// Invalidate removes the domains whose name or ID contains match and reloads them from the database right away.
+// The domain change callbacks are not triggered for the reloaded domains.
+func (c *domainCache) Invalidate(match string) int {
+	c.refreshLock.Lock()
+	defer c.refreshLock.Unlock()
+
+	newCacheNameToID := newDomainCache()
+	newCacheByID := newDomainCache()
+	invalidated := 0
+	for id, domain := range c.GetAllDomain() {
+		if strings.Contains(domainCacheKey(domain.info.Name, id), match) {
+			invalidated++
+			continue
+		}
+		newCacheNameToID.Put(domain.info.Name, id)
+		newCacheByID.Put(id, domain)
+	}
+	if invalidated == 0 {
+		return 0
+	}
+
+	c.callbackLock.Lock()
+	c.cacheByID.Store(newCacheByID)
+	c.cacheNameToID.Store(newCacheNameToID)
+	c.callbackLock.Unlock()
+
+	c.lastRefreshTime = time.Time{}
+	if err := c.refreshDomainsLocked(); err != nil {
+		// the invalidated domains are loaded on their next access or refresh
+		c.logger.Warn("Failed to reload invalidated domains", tag.Error(err))
+	}
+	return invalidated
+}
+
+func domainCacheKey(name string, id string) string {
+	return fmt.Sprintf("%v (%v)", name, id)
+}
+
 func (c *domainCache) triggerDomainChangePrepareCallbackLocked() {
 	sw := c.scope.StartTimer(metrics.DomainCachePrepareCallbacksLatency)
 	defer sw.Stop()
@@ -733,6 +834,7 @@ func (entry *DomainCacheEntry) duplicate() *DomainCacheEntry {
 	result.failoverEndTime = entry.failoverEndTime
 	result.notificationVersion = entry.notificationVersion
 	result.initialized = entry.initialized
+	result.loadTime = entry.loadTime
 	return result
 }
 
@@ -866,6 +968,39 @@ var SampleRetentionKey = "sample_retention_days"
 // SampleRateKey is key to specify sample rate
 var SampleRateKey = "sample_retention_rate"
 
+// ExecutionRetentionKey is the memo key to specify the retention in days of a workflow execution at start time
+var ExecutionRetentionKey = "cadence-retention-days"
+
+// GetExecutionRetentionDays returns retention in days for given workflow execution,
+// the one specified in its memo if any, otherwise the one of the domain
+func (entry *DomainCacheEntry) GetExecutionRetentionDays(
+	workflowID string,
+	memo map[string][]byte,
+) int32 {
+
+	if retentionDays, ok := ParseExecutionRetentionDays(memo); ok {
+		return retentionDays
+	}
+	return entry.GetRetentionDays(workflowID)
+}
+
+// ParseExecutionRetentionDays returns the retention in days specified in the memo of a workflow execution,
+// the value can be either a JSON number or a JSON string
+func ParseExecutionRetentionDays(
+	memo map[string][]byte,
+) (int32, bool) {
+
+	value, ok := memo[ExecutionRetentionKey]
+	if !ok {
+		return 0, false
+	}
+	retentionDays, err := strconv.ParseInt(strings.Trim(strings.TrimSpace(string(value)), `"`), 10, 32)
+	if err != nil || retentionDays <= 0 {
+		return 0, false
+	}
+	return int32(retentionDays), true
+}
+
 // GetRetentionDays returns retention in days for given workflow
 func (entry *DomainCacheEntry) GetRetentionDays(
 	workflowID string,
@@ -936,3 +1071,15 @@ func GetActiveDomainByID(cache DomainCache, currentCluster string, domainID stri
 
 	return domain, nil
 }
+
+// ResourcePoolByDomainID returns a function which returns the resource pool of a domain from its ID, the domains
+// which are not found are not pinned to any pool
+func ResourcePoolByDomainID(cache DomainCache, resourcePool dynamicconfig.StringPropertyFnWithDomainFilter) func(string) string {
+	return func(domainID string) string {
+		domainName, err := cache.GetDomainName(domainID)
+		if err != nil {
+			return ""
+		}
+		return resourcePool(domainName)
+	}
+}
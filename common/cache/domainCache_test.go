@@ -193,6 +193,53 @@ func (s *domainCacheSuite) TestListDomain() {
 	}, allDomains)
 }
 
+func (s *domainCacheSuite) TestDescribeAndInvalidate() {
+	newRecord := func(name string, notificationVersion int64) *persistence.GetDomainResponse {
+		return &persistence.GetDomainResponse{
+			Info:   &persistence.DomainInfo{ID: uuid.New(), Name: name, Data: make(map[string]string)},
+			Config: &persistence.DomainConfig{Retention: 1},
+			ReplicationConfig: &persistence.DomainReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters: []*persistence.ClusterReplicationConfig{
+					{ClusterName: cluster.TestCurrentClusterName},
+				},
+			},
+			NotificationVersion: notificationVersion,
+		}
+	}
+	domainRecord1 := newRecord("some domain", 0)
+	domainRecord2 := newRecord("other domain", 1)
+
+	s.metadataMgr.On("GetMetadata", mock.Anything).Return(&persistence.GetMetadataResponse{NotificationVersion: 2}, nil)
+	s.metadataMgr.On("ListDomains", mock.Anything, mock.Anything).Return(&persistence.ListDomainsResponse{
+		Domains: []*persistence.GetDomainResponse{domainRecord1, domainRecord2},
+	}, nil).Twice()
+
+	s.Nil(s.domainCache.refreshDomains())
+	_, err := s.domainCache.GetDomainByID(domainRecord1.Info.ID)
+	s.Nil(err)
+	_, err = s.domainCache.GetDomainByID(domainRecord1.Info.ID)
+	s.Nil(err)
+	_, err = s.domainCache.GetDomainByID(domainRecord2.Info.ID)
+	s.Nil(err)
+
+	description := s.domainCache.Describe(10)
+	s.Equal(2, description.Size)
+	s.Equal(int64(3), description.Hits)
+	s.Len(description.Entries, 2)
+	s.Equal(domainCacheKey(domainRecord1.Info.Name, domainRecord1.Info.ID), description.Entries[0].Key)
+	s.Equal(int64(2), description.Entries[0].Hits)
+	s.Equal(int64(1), description.Entries[1].Hits)
+
+	// the invalidated domain is reloaded right away
+	s.Equal(1, s.domainCache.Invalidate("some domain"))
+	s.Equal(0, s.domainCache.Invalidate("no such domain"))
+	entry, err := s.domainCache.GetDomainByID(domainRecord1.Info.ID)
+	s.Nil(err)
+	s.Equal(domainRecord1.Info.Name, entry.GetInfo().Name)
+	s.Len(s.domainCache.GetAllDomain(), 2)
+}
+
 func (s *domainCacheSuite) TestGetDomain_NonLoaded_GetByName() {
 	domainNotificationVersion := int64(999999) // make this notification version really large for test
 	s.metadataMgr.On("GetMetadata", mock.Anything).Return(&persistence.GetMetadataResponse{NotificationVersion: domainNotificationVersion}, nil)
@@ -527,6 +574,8 @@ func (s *domainCacheSuite) TestUpdateCache_TriggerCallBack() {
 	}, nil).Once()
 
 	s.domainCache.timeSource.(*clock.EventTimeSource).Update(s.now.Add(domainCacheMinRefreshInterval))
+	entry1New.loadTime = s.domainCache.timeSource.Now()
+	entry2New.loadTime = s.domainCache.timeSource.Now()
 	s.Nil(s.domainCache.refreshDomains())
 
 	// the order matters here: the record 2 got updated first, thus with a lower notification version
@@ -607,6 +656,7 @@ func (s *domainCacheSuite) buildEntryFromRecord(record *persistence.GetDomainRes
 	newEntry.failoverNotificationVersion = record.FailoverNotificationVersion
 	newEntry.notificationVersion = record.NotificationVersion
 	newEntry.initialized = true
+	newEntry.loadTime = s.domainCache.timeSource.Now()
 	return newEntry
 }
 
@@ -649,6 +699,26 @@ func Test_GetRetentionDays(t *testing.T) {
 	require.Equal(t, int32(30), rd)
 }
 
+func Test_GetExecutionRetentionDays(t *testing.T) {
+	d := &DomainCacheEntry{
+		info: &persistence.DomainInfo{
+			Data: make(map[string]string),
+		},
+		config: &persistence.DomainConfig{
+			Retention: 7,
+		},
+	}
+
+	wid := uuid.New()
+	require.Equal(t, int32(7), d.GetExecutionRetentionDays(wid, nil))
+	require.Equal(t, int32(7), d.GetExecutionRetentionDays(wid, map[string][]byte{"key": []byte("value")}))
+	require.Equal(t, int32(30), d.GetExecutionRetentionDays(wid, map[string][]byte{ExecutionRetentionKey: []byte("30")}))
+	require.Equal(t, int32(30), d.GetExecutionRetentionDays(wid, map[string][]byte{ExecutionRetentionKey: []byte(`"30"`)}))
+	require.Equal(t, int32(3), d.GetExecutionRetentionDays(wid, map[string][]byte{ExecutionRetentionKey: []byte("3")}))
+	require.Equal(t, int32(7), d.GetExecutionRetentionDays(wid, map[string][]byte{ExecutionRetentionKey: []byte("0")})) // fallback to domain retention
+	require.Equal(t, int32(7), d.GetExecutionRetentionDays(wid, map[string][]byte{ExecutionRetentionKey: []byte("invalid-value")}))
+}
+
 func Test_IsSampledForLongerRetentionEnabled(t *testing.T) {
 	d := &DomainCacheEntry{
 		info: &persistence.DomainInfo{
@@ -0,0 +1,275 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/types"
+)
+
+// IntrospectionHandlerPath is the path of the endpoint describing the caches of the host, they are
+// invalidated through the InvalidateCaches admin API which requires authorization
+const IntrospectionHandlerPath = "/debug/caches"
+
+type (
+	// Introspector describes the content of a cache and invalidates its entries, to diagnose the caches serving stale entries
+	Introspector interface {
+		// Describe returns the stats of the cache and its hottest entries, up to maxEntries or all of them if negative
+		Describe(maxEntries int) *Description
+		// Invalidate removes the entries whose key contains match, so that they are reloaded on their next access,
+		// and returns the number of entries removed
+		Invalidate(match string) int
+	}
+
+	// Description is a snapshot of the stats and content of a cache
+	Description struct {
+		Name    string             `json:"name"`
+		Size    int                `json:"size"`
+		Hits    int64              `json:"hits"`
+		Misses  int64              `json:"misses"`
+		HitRate float64            `json:"hitRate"`
+		Entries []EntryDescription `json:"entries"`
+	}
+
+	// EntryDescription describes an entry of a cache
+	EntryDescription struct {
+		Key  string `json:"key"`
+		Hits int64  `json:"hits"`
+		// Age is the time since the entry was loaded into the cache
+		Age time.Duration `json:"age"`
+		// Idle is the time since the entry was last read from the cache, it is the age if the entry was never read
+		Idle time.Duration `json:"idle"`
+	}
+
+	// Registry holds the introspectors of the caches of the services of a host, by name
+	Registry struct {
+		sync.RWMutex
+		introspectors map[string]Introspector
+	}
+
+	introspectionHandler struct {
+		registry *Registry
+	}
+)
+
+var _ Introspector = (*lru)(nil)
+
+// Describe returns the stats of the cache and its most read entries, up to maxEntries
+func (c *lru) Describe(maxEntries int) *Description {
+	now := time.Now()
+
+	c.mut.Lock()
+	description := &Description{
+		Size:    len(c.byKey),
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: make([]EntryDescription, 0, len(c.byKey)),
+	}
+	for element := c.byAccess.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*entryImpl)
+		description.Entries = append(description.Entries, describeEntry(entry, now))
+	}
+	c.mut.Unlock()
+
+	description.HitRate = hitRate(description.Hits, description.Misses)
+	SortEntryDescriptions(description.Entries)
+	if maxEntries >= 0 && len(description.Entries) > maxEntries {
+		description.Entries = description.Entries[:maxEntries]
+	}
+	return description
+}
+
+// Invalidate deletes the entries whose formatted key contains match, the pinned entries in use are skipped
+func (c *lru) Invalidate(match string) int {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	invalidated := 0
+	for element := c.byAccess.Front(); element != nil; {
+		next := element.Next()
+		entry := element.Value.(*entryImpl)
+		if entry.refCount == 0 && strings.Contains(FormatKey(entry.key), match) {
+			c.deleteInternal(element)
+			invalidated++
+		}
+		element = next
+	}
+	return invalidated
+}
+
+func describeEntry(entry *entryImpl, now time.Time) EntryDescription {
+	description := EntryDescription{
+		Key:  FormatKey(entry.key),
+		Hits: entry.hits,
+		Age:  now.Sub(entry.addTime),
+	}
+	description.Idle = description.Age
+	if !entry.lastAccess.IsZero() {
+		description.Idle = now.Sub(entry.lastAccess)
+	}
+	return description
+}
+
+// FormatKey formats the key of a cache entry, as matched by Introspector.Invalidate
+func FormatKey(key interface{}) string {
+	if s, ok := key.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%+v", key)
+}
+
+// SortEntryDescriptions sorts entries by decreasing hits, then by key
+func SortEntryDescriptions(entries []EntryDescription) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Hits != entries[j].Hits {
+			return entries[i].Hits > entries[j].Hits
+		}
+		return entries[i].Key < entries[j].Key
+	})
+}
+
+func hitRate(hits int64, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// NewRegistry creates a new registry of cache introspectors
+func NewRegistry() *Registry {
+	return &Registry{introspectors: make(map[string]Introspector)}
+}
+
+// Add adds the introspector of a cache to the registry, replacing the one previously added with the same name
+func (r *Registry) Add(name string, introspector Introspector) {
+	if r == nil || introspector == nil {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.introspectors[name] = introspector
+}
+
+// Remove removes the introspector of a cache from the registry
+func (r *Registry) Remove(name string) {
+	if r == nil {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	delete(r.introspectors, name)
+}
+
+// Describe describes the caches whose name starts with prefix, ordered by name
+func (r *Registry) Describe(prefix string, maxEntries int) []*Description {
+	introspectors := r.list(prefix)
+	descriptions := make([]*Description, 0, len(introspectors))
+	for name, introspector := range introspectors {
+		description := introspector.Describe(maxEntries)
+		description.Name = name
+		descriptions = append(descriptions, description)
+	}
+	sort.Slice(descriptions, func(i, j int) bool {
+		return descriptions[i].Name < descriptions[j].Name
+	})
+	return descriptions
+}
+
+// Invalidate invalidates the entries matching in the caches whose name starts with prefix,
+// and returns the number of entries invalidated by cache
+func (r *Registry) Invalidate(prefix string, match string) map[string]int {
+	invalidated := make(map[string]int)
+	for name, introspector := range r.list(prefix) {
+		invalidated[name] = introspector.Invalidate(match)
+	}
+	return invalidated
+}
+
+// InvalidateCaches serves the InvalidateCaches APIs of the services with the caches of the registry
+func InvalidateCaches(registry *Registry, request *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error) {
+	if request.GetMatch() == "" {
+		return nil, &types.BadRequestError{Message: "Match is required to invalidate cache entries."}
+	}
+	invalidated := make(map[string]int32)
+	for name, count := range registry.Invalidate(request.GetCacheNamePrefix(), request.GetMatch()) {
+		invalidated[name] = int32(count)
+	}
+	return &types.InvalidateCachesResponse{Invalidated: invalidated}, nil
+}
+
+func (r *Registry) list(prefix string) map[string]Introspector {
+	if r == nil {
+		return nil
+	}
+	r.RLock()
+	defer r.RUnlock()
+	introspectors := make(map[string]Introspector)
+	for name, introspector := range r.introspectors {
+		if strings.HasPrefix(name, prefix) {
+			introspectors[name] = introspector
+		}
+	}
+	return introspectors
+}
+
+// NewIntrospectionHandler creates the http handler of the read-only cache introspection endpoint.
+// GET describes the caches, it can be limited to the caches whose name starts with the name query parameter.
+// The number of entries described per cache is set with the top query parameter, 10 by default.
+func NewIntrospectionHandler(registry *Registry) http.Handler {
+	return &introspectionHandler{registry: registry}
+}
+
+func (h *introspectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	maxEntries := 10
+	if value := query.Get("top"); value != "" {
+		var err error
+		if maxEntries, err = strconv.Atoi(value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	body, err := json.MarshalIndent(h.registry.Describe(query.Get("name"), maxEntries), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body) //nolint:errcheck
+}
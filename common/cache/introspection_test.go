@@ -0,0 +1,153 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cache
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestLRUDescribe(t *testing.T) {
+	cache := New(&Options{MaxCount: 5})
+	cache.Put("A", "Foo")
+	cache.Put("B", "Bar")
+	cache.Put("C", "Cid")
+	cache.Get("B")
+	cache.Get("B")
+	cache.Get("C")
+	cache.Get("D")
+
+	description := cache.(Introspector).Describe(2)
+	assert.Equal(t, 3, description.Size)
+	assert.Equal(t, int64(3), description.Hits)
+	assert.Equal(t, int64(1), description.Misses)
+	assert.Equal(t, 0.75, description.HitRate)
+	require.Len(t, description.Entries, 2)
+	assert.Equal(t, "B", description.Entries[0].Key)
+	assert.Equal(t, int64(2), description.Entries[0].Hits)
+	assert.Equal(t, "C", description.Entries[1].Key)
+	assert.Equal(t, int64(1), description.Entries[1].Hits)
+
+	assert.Len(t, cache.(Introspector).Describe(-1).Entries, 3)
+}
+
+func TestLRUInvalidate(t *testing.T) {
+	cache := New(&Options{MaxCount: 5, Pin: true})
+	cache.PutIfNotExist(keyType{dummyString: "workflow-1"}, "Foo") //nolint:errcheck
+	cache.PutIfNotExist(keyType{dummyString: "workflow-2"}, "Bar") //nolint:errcheck
+	cache.Release(keyType{dummyString: "workflow-2"})
+	cache.PutIfNotExist(keyType{dummyString: "other"}, "Cid") //nolint:errcheck
+	cache.Release(keyType{dummyString: "other"})
+
+	// workflow-1 is pinned and kept
+	assert.Equal(t, 1, cache.(Introspector).Invalidate("workflow"))
+	assert.Equal(t, 2, cache.Size())
+	assert.Nil(t, cache.Get(keyType{dummyString: "workflow-2"}))
+
+	cache.Release(keyType{dummyString: "workflow-1"})
+	assert.Equal(t, 1, cache.(Introspector).Invalidate("workflow"))
+	assert.Equal(t, 1, cache.Size())
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	frontendCache := New(&Options{MaxCount: 5})
+	frontendCache.Put("domain-a", "Foo")
+	historyCache := New(&Options{MaxCount: 5})
+	historyCache.Put("domain-a", "Bar")
+	historyCache.Put("domain-b", "Cid")
+	registry.Add("frontend/domain", frontendCache.(Introspector))
+	registry.Add("history/domain", historyCache.(Introspector))
+
+	descriptions := registry.Describe("", 10)
+	require.Len(t, descriptions, 2)
+	assert.Equal(t, "frontend/domain", descriptions[0].Name)
+	assert.Equal(t, "history/domain", descriptions[1].Name)
+
+	assert.Equal(t, map[string]int{"history/domain": 1}, registry.Invalidate("history", "domain-a"))
+	assert.Equal(t, 1, historyCache.Size())
+	assert.Equal(t, 1, frontendCache.Size())
+
+	registry.Remove("history/domain")
+	assert.Len(t, registry.Describe("", 10), 1)
+
+	var nilRegistry *Registry
+	nilRegistry.Add("frontend/domain", frontendCache.(Introspector))
+	nilRegistry.Remove("frontend/domain")
+	assert.Empty(t, nilRegistry.Describe("", 10))
+}
+
+func TestIntrospectionHandler(t *testing.T) {
+	registry := NewRegistry()
+	cache := New(&Options{MaxCount: 5})
+	cache.Put("domain-a", "Foo")
+	cache.Put("domain-b", "Bar")
+	registry.Add("frontend/domain", cache.(Introspector))
+	handler := NewIntrospectionHandler(registry)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, IntrospectionHandlerPath+"?name=frontend&top=1", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var descriptions []*Description
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &descriptions))
+	require.Len(t, descriptions, 1)
+	assert.Equal(t, 2, descriptions[0].Size)
+	assert.Len(t, descriptions[0].Entries, 1)
+
+	// invalidation is only served by the InvalidateCaches admin API
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, IntrospectionHandlerPath+"?match=domain-a", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	assert.Equal(t, 2, cache.Size())
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, IntrospectionHandlerPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestInvalidateCaches(t *testing.T) {
+	registry := NewRegistry()
+	cache := New(&Options{MaxCount: 5})
+	cache.Put("domain-a", "Foo")
+	cache.Put("domain-b", "Bar")
+	registry.Add("frontend/domain", cache.(Introspector))
+
+	_, err := InvalidateCaches(registry, &types.InvalidateCachesRequest{})
+	assert.IsType(t, &types.BadRequestError{}, err)
+
+	resp, err := InvalidateCaches(registry, &types.InvalidateCachesRequest{CacheNamePrefix: "history", Match: "domain-a"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Invalidated)
+
+	resp, err = InvalidateCaches(registry, &types.InvalidateCachesRequest{CacheNamePrefix: "frontend", Match: "domain-a"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int32{"frontend/domain": 1}, resp.Invalidated)
+	assert.Equal(t, 1, cache.Size())
+}
@@ -50,6 +50,8 @@ type (
 		currSize    uint64
 		sizeByKey   map[interface{}]uint64
 		isSizeBased bool
+		hits        int64
+		misses      int64
 	}
 
 	iteratorImpl struct {
@@ -63,6 +65,10 @@ type (
 		createTime time.Time
 		value      interface{}
 		refCount   int
+		// addTime, hits and lastAccess are only used to describe the cache, see Introspector
+		addTime    time.Time
+		hits       int64
+		lastAccess time.Time
 	}
 )
 
@@ -171,20 +177,26 @@ func (c *lru) Get(key interface{}) interface{} {
 
 	element := c.byKey[key]
 	if element == nil {
+		c.misses++
 		return nil
 	}
 
 	entry := element.Value.(*entryImpl)
 
-	if c.isEntryExpired(entry, time.Now()) {
+	now := time.Now()
+	if c.isEntryExpired(entry, now) {
 		// Entry has expired
 		c.deleteInternal(element)
+		c.misses++
 		return nil
 	}
 
 	if c.pin {
 		entry.refCount++
 	}
+	c.hits++
+	entry.hits++
+	entry.lastAccess = now
 	c.byAccess.MoveToFront(element)
 	return entry.value
 }
@@ -276,8 +288,9 @@ func (c *lru) putInternal(key interface{}, value interface{}, allowUpdate bool)
 	}
 
 	entry := &entryImpl{
-		key:   key,
-		value: value,
+		key:     key,
+		value:   value,
+		addTime: time.Now(),
 	}
 
 	if c.pin {
@@ -0,0 +1,418 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package certificate loads the certificates used for TLS from files and reloads them when the files change,
+// so that certificates can be rotated without restarting the services.
+package certificate
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+)
+
+const (
+	reportInterval = time.Minute
+)
+
+// ReloadCheckInterval is the minimum interval between two checks of the files of a certificate for changes,
+// the files are checked on TLS handshakes and when the expiry of the certificates is reported
+var ReloadCheckInterval = 10 * time.Second
+
+type (
+	// KeyPair is a certificate and its private key loaded from files, they are reloaded when the files change
+	KeyPair struct {
+		*watcher
+		certificate atomic.Value // *tls.Certificate
+	}
+
+	// Pool is a pool of CA certificates loaded from files, they are reloaded when the files change
+	Pool struct {
+		*watcher
+		pool atomic.Value // *x509.CertPool
+	}
+
+	// Expiry is the time the certificates loaded from a file expire, the earliest one for a bundle of certificates
+	Expiry struct {
+		File     string
+		NotAfter time.Time
+		// ReloadError is the error of the last reload of the file, the previously loaded certificates are still used
+		ReloadError error
+	}
+
+	// Reporter periodically checks the loaded certificates for changes and emits the time left before they expire
+	Reporter struct {
+		status     int32
+		scope      tally.Scope
+		logger     log.Logger
+		shutdownCh chan struct{}
+	}
+
+	// watcher reloads files when their modification time changes, at most once per ReloadCheckInterval
+	watcher struct {
+		sync.Mutex
+		files     []string
+		modTimes  []time.Time
+		lastCheck time.Time
+		notAfter  map[string]time.Time
+		reloadErr error
+		load      func() (map[string]time.Time, error)
+	}
+)
+
+// the key pairs and pools are shared by the TLS configs using the same files, so that each file is watched once
+var loaded = struct {
+	sync.Mutex
+	keyPairs map[string]*KeyPair
+	pools    map[string]*Pool
+}{
+	keyPairs: make(map[string]*KeyPair),
+	pools:    make(map[string]*Pool),
+}
+
+// LoadKeyPair loads a certificate and its private key, the key pair already loaded from the same files is reused
+func LoadKeyPair(certFile string, keyFile string) (*KeyPair, error) {
+	key := certFile + "," + keyFile
+	loaded.Lock()
+	defer loaded.Unlock()
+
+	if keyPair, ok := loaded.keyPairs[key]; ok {
+		return keyPair, nil
+	}
+
+	keyPair := &KeyPair{}
+	keyPair.watcher = newWatcher([]string{certFile, keyFile}, func() (map[string]time.Time, error) {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+		if err != nil {
+			return nil, err
+		}
+		certificate.Leaf = leaf
+		keyPair.certificate.Store(&certificate)
+		return map[string]time.Time{certFile: leaf.NotAfter}, nil
+	})
+	if err := keyPair.init(); err != nil {
+		return nil, err
+	}
+	loaded.keyPairs[key] = keyPair
+	return keyPair, nil
+}
+
+// Certificate returns the current certificate of the key pair
+func (k *KeyPair) Certificate() *tls.Certificate {
+	k.reloadIfChanged()
+	return k.certificate.Load().(*tls.Certificate)
+}
+
+// GetCertificate returns the current certificate of the key pair, it is used as tls.Config.GetCertificate
+func (k *KeyPair) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return k.Certificate(), nil
+}
+
+// GetClientCertificate returns the current certificate of the key pair, it is used as tls.Config.GetClientCertificate
+func (k *KeyPair) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return k.Certificate(), nil
+}
+
+// LoadPool loads a pool of CA certificates, the pool already loaded from the same files is reused
+func LoadPool(files []string) (*Pool, error) {
+	key := strings.Join(files, ",")
+	loaded.Lock()
+	defer loaded.Unlock()
+
+	if pool, ok := loaded.pools[key]; ok {
+		return pool, nil
+	}
+
+	pool := &Pool{}
+	pool.watcher = newWatcher(files, func() (map[string]time.Time, error) {
+		certPool := x509.NewCertPool()
+		notAfter := make(map[string]time.Time, len(files))
+		for _, file := range files {
+			certificates, err := readCertificates(file)
+			if err != nil {
+				return nil, err
+			}
+			for _, certificate := range certificates {
+				certPool.AddCert(certificate)
+				if expiry, ok := notAfter[file]; !ok || certificate.NotAfter.Before(expiry) {
+					notAfter[file] = certificate.NotAfter
+				}
+			}
+		}
+		pool.pool.Store(certPool)
+		return notAfter, nil
+	})
+	if err := pool.init(); err != nil {
+		return nil, err
+	}
+	loaded.pools[key] = pool
+	return pool, nil
+}
+
+// CertPool returns the current CA certificates of the pool
+func (p *Pool) CertPool() *x509.CertPool {
+	p.reloadIfChanged()
+	return p.pool.Load().(*x509.CertPool)
+}
+
+// VerifyConnection verifies the certificate chain and the host name of a server against the current CA certificates
+// of the pool, it is used as tls.Config.VerifyConnection by the clients, with tls.Config.InsecureSkipVerify set
+// to skip the verification against tls.Config.RootCAs which cannot be reloaded.
+func (p *Pool) VerifyConnection(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no certificate presented by the server")
+	}
+	return p.verify(state.PeerCertificates, x509.VerifyOptions{
+		DNSName: state.ServerName,
+	})
+}
+
+func (p *Pool) verify(certificates []*x509.Certificate, options x509.VerifyOptions) error {
+	options.Roots = p.CertPool()
+	options.Intermediates = x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		options.Intermediates.AddCert(certificate)
+	}
+	_, err := certificates[0].Verify(options)
+	return err
+}
+
+// VerifyClientConnection verifies the certificate chain of a client against the current CA certificates of the pool,
+// it is used as tls.Config.VerifyConnection by the servers, with tls.Config.ClientAuth set to tls.RequireAnyClientCert
+// to skip the verification against tls.Config.ClientCAs which cannot be reloaded.
+func (p *Pool) VerifyClientConnection(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no certificate presented by the client")
+	}
+	return p.verify(state.PeerCertificates, x509.VerifyOptions{
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+}
+
+// Expiries checks the loaded certificates for changes and returns when they expire, sorted by file
+func Expiries() []Expiry {
+	loaded.Lock()
+	watchers := make([]*watcher, 0, len(loaded.keyPairs)+len(loaded.pools))
+	for _, keyPair := range loaded.keyPairs {
+		watchers = append(watchers, keyPair.watcher)
+	}
+	for _, pool := range loaded.pools {
+		watchers = append(watchers, pool.watcher)
+	}
+	loaded.Unlock()
+
+	expiries := make(map[string]Expiry)
+	for _, watcher := range watchers {
+		watcher.reloadIfChanged()
+		watcher.Lock()
+		for file, notAfter := range watcher.notAfter {
+			expiries[file] = Expiry{File: file, NotAfter: notAfter, ReloadError: watcher.reloadErr}
+		}
+		watcher.Unlock()
+	}
+
+	result := make([]Expiry, 0, len(expiries))
+	for _, expiry := range expiries {
+		result = append(result, expiry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].File < result[j].File
+	})
+	return result
+}
+
+// NewReporter creates a reporter of the time left before the loaded certificates expire
+func NewReporter(scope tally.Scope, logger log.Logger) *Reporter {
+	return &Reporter{
+		status:     common.DaemonStatusInitialized,
+		scope:      scope,
+		logger:     logger,
+		shutdownCh: make(chan struct{}),
+	}
+}
+
+// Start starts the reporter
+func (r *Reporter) Start() {
+	if !atomic.CompareAndSwapInt32(&r.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+	go r.reportLoop()
+}
+
+// Stop stops the reporter
+func (r *Reporter) Stop() {
+	if !atomic.CompareAndSwapInt32(&r.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+	close(r.shutdownCh)
+}
+
+func (r *Reporter) reportLoop() {
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+
+	r.report()
+	for {
+		select {
+		case <-ticker.C:
+			r.report()
+		case <-r.shutdownCh:
+			return
+		}
+	}
+}
+
+func (r *Reporter) report() {
+	for _, expiry := range Expiries() {
+		certificateTag := metrics.CertificateTag(expiry.File)
+		r.scope.Tagged(map[string]string{certificateTag.Key(): certificateTag.Value()}).
+			Gauge(metrics.TLSCertificateExpiryGauge).
+			Update(time.Until(expiry.NotAfter).Seconds())
+		if expiry.ReloadError != nil {
+			r.logger.Warn("Failed to reload certificate, the previous one is still used",
+				tag.Value(expiry.File), tag.Error(expiry.ReloadError))
+		}
+	}
+}
+
+func newWatcher(files []string, load func() (map[string]time.Time, error)) *watcher {
+	return &watcher{
+		files: files,
+		load:  load,
+	}
+}
+
+func (w *watcher) init() error {
+	notAfter, err := w.load()
+	if err != nil {
+		return err
+	}
+	modTimes, err := statFiles(w.files)
+	if err != nil {
+		return err
+	}
+	w.modTimes = modTimes
+	w.notAfter = notAfter
+	w.lastCheck = time.Now()
+	return nil
+}
+
+// reloadIfChanged reloads the files if any of them was modified since they were loaded. The files can be
+// written one after the other during a rotation, a failed reload is retried on the next check.
+func (w *watcher) reloadIfChanged() {
+	w.Lock()
+	defer w.Unlock()
+
+	now := time.Now()
+	if now.Sub(w.lastCheck) < ReloadCheckInterval {
+		return
+	}
+	w.lastCheck = now
+
+	modTimes, err := statFiles(w.files)
+	if err != nil {
+		w.reloadErr = err
+		return
+	}
+	if equalTimes(modTimes, w.modTimes) {
+		return
+	}
+	notAfter, err := w.load()
+	if err != nil {
+		w.reloadErr = err
+		return
+	}
+	w.modTimes = modTimes
+	w.notAfter = notAfter
+	w.reloadErr = nil
+}
+
+func statFiles(files []string) ([]time.Time, error) {
+	modTimes := make([]time.Time, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+	return modTimes, nil
+}
+
+func equalTimes(a []time.Time, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func readCertificates(file string) ([]*x509.Certificate, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var certificates []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate in %v: %v", file, err)
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no certificates found in %v", file)
+	}
+	return certificates, nil
+}
@@ -0,0 +1,230 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+)
+
+// writes is the number of files written by the tests
+var writes int
+
+type testCA struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, notAfter time.Time) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{certificate: certificate, key: key}
+}
+
+func (ca *testCA) writeCertificate(t *testing.T, file string) {
+	writePEM(t, file, "CERTIFICATE", ca.certificate.Raw)
+}
+
+// writeKeyPair writes a certificate for the host signed by the CA and its key
+func (ca *testCA) writeKeyPair(t *testing.T, certFile string, keyFile string, host string, notAfter time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+}
+
+func writePEM(t *testing.T, file string, blockType string, content []byte) {
+	require.NoError(t, ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: content}), 0600))
+	// make sure the modification time changes even on file systems with a coarse resolution
+	writes++
+	modTime := time.Now().Add(time.Duration(writes) * time.Second)
+	require.NoError(t, os.Chtimes(file, modTime, modTime))
+}
+
+func setReloadCheckInterval(t *testing.T, interval time.Duration) {
+	previous := ReloadCheckInterval
+	ReloadCheckInterval = interval
+	t.Cleanup(func() {
+		ReloadCheckInterval = previous
+	})
+}
+
+func TestKeyPairReload(t *testing.T) {
+	setReloadCheckInterval(t, 0)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	ca := newTestCA(t, time.Now().Add(24*time.Hour))
+	firstExpiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	ca.writeKeyPair(t, certFile, keyFile, "localhost", firstExpiry)
+
+	keyPair, err := LoadKeyPair(certFile, keyFile)
+	require.NoError(t, err)
+	assert.True(t, firstExpiry.Equal(keyPair.Certificate().Leaf.NotAfter))
+
+	sameKeyPair, err := LoadKeyPair(certFile, keyFile)
+	require.NoError(t, err)
+	assert.Same(t, keyPair, sameKeyPair)
+
+	// the certificate is rotated
+	secondExpiry := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	ca.writeKeyPair(t, certFile, keyFile, "localhost", secondExpiry)
+	certificate, err := keyPair.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.True(t, secondExpiry.Equal(certificate.Leaf.NotAfter))
+
+	// the previous certificate is kept when the new one is invalid
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("invalid"), 0600))
+	certificate, err = keyPair.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.True(t, secondExpiry.Equal(certificate.Leaf.NotAfter))
+
+	var expiry *Expiry
+	for _, e := range Expiries() {
+		if e.File == certFile {
+			e := e
+			expiry = &e
+		}
+	}
+	require.NotNil(t, expiry)
+	assert.True(t, secondExpiry.Equal(expiry.NotAfter))
+	assert.Error(t, expiry.ReloadError)
+}
+
+func TestKeyPairNotReloadedWithinInterval(t *testing.T) {
+	setReloadCheckInterval(t, time.Hour)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	ca := newTestCA(t, time.Now().Add(24*time.Hour))
+	firstExpiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	ca.writeKeyPair(t, certFile, keyFile, "localhost", firstExpiry)
+
+	keyPair, err := LoadKeyPair(certFile, keyFile)
+	require.NoError(t, err)
+	ca.writeKeyPair(t, certFile, keyFile, "localhost", time.Now().Add(2*time.Hour))
+	assert.True(t, firstExpiry.Equal(keyPair.Certificate().Leaf.NotAfter))
+}
+
+func TestLoadKeyPairError(t *testing.T) {
+	dir := t.TempDir()
+	_, err := LoadKeyPair(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"))
+	assert.Error(t, err)
+}
+
+func TestPoolReloadAndVerify(t *testing.T) {
+	setReloadCheckInterval(t, 0)
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	oldCA := newTestCA(t, time.Now().Add(24*time.Hour))
+	newCA := newTestCA(t, time.Now().Add(48*time.Hour))
+	oldCA.writeCertificate(t, caFile)
+	newCA.writeKeyPair(t, certFile, keyFile, "localhost", time.Now().Add(time.Hour))
+
+	pool, err := LoadPool([]string{caFile})
+	require.NoError(t, err)
+	keyPair, err := LoadKeyPair(certFile, keyFile)
+	require.NoError(t, err)
+	state := tls.ConnectionState{
+		ServerName:       "localhost",
+		PeerCertificates: []*x509.Certificate{keyPair.Certificate().Leaf},
+	}
+	assert.Error(t, pool.VerifyConnection(state))
+	assert.Error(t, pool.VerifyClientConnection(state))
+
+	// the CA is rotated
+	newCA.writeCertificate(t, caFile)
+	assert.NoError(t, pool.VerifyConnection(state))
+	assert.NoError(t, pool.VerifyClientConnection(state))
+
+	state.ServerName = "otherhost"
+	assert.Error(t, pool.VerifyConnection(state))
+	assert.Error(t, pool.VerifyConnection(tls.ConnectionState{ServerName: "localhost"}))
+}
+
+func TestLoadPoolError(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caFile, []byte("not a certificate"), 0600))
+	_, err := LoadPool([]string{caFile})
+	assert.Error(t, err)
+}
+
+func TestReporter(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	ca := newTestCA(t, time.Now().Add(24*time.Hour))
+	ca.writeKeyPair(t, certFile, keyFile, "localhost", time.Now().Add(time.Hour))
+	_, err := LoadKeyPair(certFile, keyFile)
+	require.NoError(t, err)
+
+	scope := tally.NewTestScope("test", nil)
+	reporter := NewReporter(scope, loggerimpl.NewNopLogger())
+	reporter.report()
+
+	gauge, ok := scope.Snapshot().Gauges()["test.tls_certificate_expiry_seconds+certificate="+certFile]
+	require.True(t, ok)
+	assert.InDelta(t, time.Hour.Seconds(), gauge.Value(), time.Minute.Seconds())
+}
@@ -0,0 +1,107 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package compression compresses data stored at rest, along uncompressed metadata describing the data.
+package compression
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// CodecZstd compresses with zstd
+	CodecZstd = "zstd"
+
+	frameVersion = 1
+
+	// maxDecompressedSize guards against corrupted frames claiming a huge size,
+	// it is way above the transaction size limit of a history event batch
+	maxDecompressedSize = 256 * 1024 * 1024
+)
+
+var (
+	errMalformedFrame = errors.New("malformed compression frame")
+
+	initOnce sync.Once
+	initErr  error
+	// the zstd encoder and decoder can be used concurrently by EncodeAll and DecodeAll
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+)
+
+// IsSupportedCodec returns whether data can be compressed with the codec
+func IsSupportedCodec(codec string) bool {
+	return codec == CodecZstd
+}
+
+// Compress compresses the data with zstd. The metadata is stored uncompressed in the frame:
+// version (1 byte) | metadata length (2 bytes) | metadata | zstd frame
+func Compress(metadata []byte, data []byte) ([]byte, error) {
+	if err := initCodec(); err != nil {
+		return nil, err
+	}
+	if len(metadata) > 1<<16-1 {
+		return nil, fmt.Errorf("compression metadata of %v bytes is too long", len(metadata))
+	}
+
+	header := make([]byte, 3, 3+len(metadata))
+	header[0] = frameVersion
+	binary.BigEndian.PutUint16(header[1:], uint16(len(metadata)))
+	header = append(header, metadata...)
+	return encoder.EncodeAll(data, header), nil
+}
+
+// Decompress decompresses the data returned by Compress, along its metadata
+func Decompress(frame []byte) (metadata []byte, data []byte, err error) {
+	if err := initCodec(); err != nil {
+		return nil, nil, err
+	}
+	if len(frame) < 3 || frame[0] != frameVersion {
+		return nil, nil, errMalformedFrame
+	}
+	length := int(binary.BigEndian.Uint16(frame[1:]))
+	if len(frame) < 3+length {
+		return nil, nil, errMalformedFrame
+	}
+
+	data, err = decoder.DecodeAll(frame[3+length:], nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return frame[3 : 3+length], data, nil
+}
+
+func initCodec() error {
+	initOnce.Do(func() {
+		encoder, initErr = zstd.NewWriter(nil)
+		if initErr != nil {
+			return
+		}
+		decoder, initErr = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedSize))
+	})
+	return initErr
+}
@@ -0,0 +1,64 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressDecompress(t *testing.T) {
+	data := bytes.Repeat([]byte("history event "), 1000)
+
+	frame, err := Compress([]byte("thriftrw"), data)
+	require.NoError(t, err)
+	assert.Less(t, len(frame), len(data))
+
+	metadata, decompressed, err := Decompress(frame)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("thriftrw"), metadata)
+	assert.Equal(t, data, decompressed)
+}
+
+func TestDecompress_MalformedFrame(t *testing.T) {
+	for name, frame := range map[string][]byte{
+		"empty":             nil,
+		"unknown version":   {2, 0, 0},
+		"truncated header":  {frameVersion, 0, 10, 'a'},
+		"corrupted payload": {frameVersion, 0, 0, 'a', 'b', 'c'},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := Decompress(frame)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestIsSupportedCodec(t *testing.T) {
+	assert.True(t, IsSupportedCodec(CodecZstd))
+	assert.False(t, IsSupportedCodec(""))
+	assert.False(t, IsSupportedCodec("lz4"))
+}
@@ -22,13 +22,24 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/cristalhq/jwt/v3"
 )
 
+// NetworkPolicyWildcard is the domain and the API category of the network allowlists applying to all of them
+const NetworkPolicyWildcard = "*"
+
 // Validate validates the persistence config
 func (a *Authorization) Validate() error {
-	if a.OAuthAuthorizer.Enable && a.NoopAuthorizer.Enable {
+	enabled := 0
+	for _, enable := range []bool{a.OAuthAuthorizer.Enable, a.NoopAuthorizer.Enable, a.OPAAuthorizer.Enable} {
+		if enable {
+			enabled++
+		}
+	}
+	if enabled > 1 {
 		return fmt.Errorf("[AuthorizationConfig] More than one authorizer is enabled")
 	}
 
@@ -38,6 +49,76 @@ func (a *Authorization) Validate() error {
 		}
 	}
 
+	if a.OPAAuthorizer.Enable {
+		if opaError := a.validateOPA(); opaError != nil {
+			return opaError
+		}
+	}
+
+	if a.NetworkPolicy.Enable {
+		if networkPolicyError := a.validateNetworkPolicy(); networkPolicyError != nil {
+			return networkPolicyError
+		}
+	}
+
+	return nil
+}
+
+func (a *Authorization) validateNetworkPolicy() error {
+	for domain, allowlist := range a.NetworkPolicy.Allowlists {
+		if domain == "" {
+			return fmt.Errorf("[NetworkPolicyConfig] Domain can't be empty")
+		}
+		for category, addresses := range allowlist {
+			switch category {
+			case NetworkPolicyWildcard, "read", "write", "admin":
+			default:
+				return fmt.Errorf("[NetworkPolicyConfig] Invalid API category %q of domain %v, must be one of read, write, admin or *", category, domain)
+			}
+			for _, address := range addresses {
+				if _, err := ParseNetworkAddress(address); err != nil {
+					return fmt.Errorf("[NetworkPolicyConfig] Invalid address of domain %v: %v", domain, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ParseNetworkAddress parses an IP or a CIDR of a network allowlist, an IP is the network of this single address
+func ParseNetworkAddress(address string) (*net.IPNet, error) {
+	if strings.Contains(address, "/") {
+		_, network, err := net.ParseCIDR(address)
+		return network, err
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", address)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+func (a *Authorization) validateOPA() error {
+	opaConfig := a.OPAAuthorizer
+
+	if opaConfig.URL == "" {
+		return fmt.Errorf("[OPAConfig] URL can't be empty")
+	}
+	if opaConfig.JwtCredentials == nil {
+		return nil
+	}
+	if opaConfig.MaxJwtTTL <= 0 {
+		return fmt.Errorf("[OPAConfig] MaxTTL must be greater than 0")
+	}
+	if opaConfig.JwtCredentials.PublicKey == "" {
+		return fmt.Errorf("[OPAConfig] PublicKey can't be empty")
+	}
+	if opaConfig.JwtCredentials.Algorithm != jwt.RS256.String() {
+		return fmt.Errorf("[OPAConfig] The only supported Algorithm is RS256")
+	}
 	return nil
 }
 
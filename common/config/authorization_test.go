@@ -112,3 +112,74 @@ func TestCorrectValidation(t *testing.T) {
 	err := cfg.Validate()
 	assert.NoError(t, err)
 }
+
+func TestOPAValidation(t *testing.T) {
+	cfg := Authorization{
+		OAuthAuthorizer: OAuthAuthorizer{
+			Enable: true,
+		},
+		OPAAuthorizer: OPAAuthorizer{
+			Enable: true,
+		},
+	}
+	assert.EqualError(t, cfg.Validate(), "[AuthorizationConfig] More than one authorizer is enabled")
+
+	cfg = Authorization{
+		OPAAuthorizer: OPAAuthorizer{
+			Enable: true,
+		},
+	}
+	assert.EqualError(t, cfg.Validate(), "[OPAConfig] URL can't be empty")
+
+	cfg.OPAAuthorizer.URL = "http://localhost:8181/v1/data/cadence/authz/allow"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.OPAAuthorizer.JwtCredentials = &JwtCredentials{
+		Algorithm: "RS256",
+	}
+	cfg.OPAAuthorizer.MaxJwtTTL = 1000000
+	assert.EqualError(t, cfg.Validate(), "[OPAConfig] PublicKey can't be empty")
+
+	cfg.OPAAuthorizer.JwtCredentials.PublicKey = "public"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestNetworkPolicyValidation(t *testing.T) {
+	cfg := Authorization{
+		NetworkPolicy: NetworkPolicy{
+			Enable: true,
+			Allowlists: map[string]NetworkAllowlist{
+				"*":           {"admin": {"10.0.0.0/8"}},
+				"test-domain": {"*": {"192.168.1.10", "fd00::/8"}, "write": {"2001:db8::1"}},
+			},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.NetworkPolicy.Allowlists["test-domain"]["poll"] = []string{"10.0.0.1"}
+	assert.EqualError(t, cfg.Validate(), `[NetworkPolicyConfig] Invalid API category "poll" of domain test-domain, must be one of read, write, admin or *`)
+	delete(cfg.NetworkPolicy.Allowlists["test-domain"], "poll")
+
+	cfg.NetworkPolicy.Allowlists["test-domain"]["read"] = []string{"10.0.0.256"}
+	assert.EqualError(t, cfg.Validate(), `[NetworkPolicyConfig] Invalid address of domain test-domain: invalid IP address "10.0.0.256"`)
+
+	cfg.NetworkPolicy.Allowlists["test-domain"]["read"] = []string{"10.0.0.0/33"}
+	assert.Error(t, cfg.Validate())
+
+	cfg.NetworkPolicy.Enable = false
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestParseNetworkAddress(t *testing.T) {
+	network, err := ParseNetworkAddress("192.168.1.10")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.10/32", network.String())
+
+	network, err = ParseNetworkAddress("2001:db8::1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::1/128", network.String())
+
+	network, err = ParseNetworkAddress("10.1.2.3/8")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", network.String())
+}
@@ -32,6 +32,9 @@ import (
 
 	"github.com/uber/cadence/common/dynamicconfig"
 	c "github.com/uber/cadence/common/dynamicconfig/configstore/config"
+	"github.com/uber/cadence/common/dynamicconfig/remote"
+	"github.com/uber/cadence/common/peerprovider/etcdprovider"
+	"github.com/uber/cadence/common/peerprovider/k8sprovider"
 	"github.com/uber/cadence/common/peerprovider/ringpopprovider"
 	"github.com/uber/cadence/common/service"
 )
@@ -41,6 +44,13 @@ type (
 	Config struct {
 		// Ringpop is the ringpop related configuration
 		Ringpop ringpopprovider.Config `yaml:"ringpop"`
+		// KubernetesMembership resolves the membership from the Kubernetes API instead of ringpop if set
+		KubernetesMembership *k8sprovider.Config `yaml:"kubernetesMembership"`
+		// EtcdMembership keeps the membership in etcd instead of ringpop if set
+		EtcdMembership *etcdprovider.Config `yaml:"etcdMembership"`
+		// Zone is the availability zone of the hosts, advertised in the membership so that the requests are routed
+		// to the hosts of the same zone when possible. It is the zone the config is loaded for by default.
+		Zone string `yaml:"zone"`
 		// Persistence contains the configuration for cadence datastores
 		Persistence Persistence `yaml:"persistence"`
 		// Log is the logging config
@@ -75,6 +85,16 @@ type (
 		Authorization Authorization `yaml:"authorization"`
 		// HeaderForwardingRules defines which inbound headers to include or exclude on outbound calls
 		HeaderForwardingRules []HeaderRule `yaml:"headerForwardingRules"`
+		// Tracing is the config for tracing requests across services
+		Tracing Tracing `yaml:"tracing"`
+		// CrashReport is the config for the reports written when a panic is captured
+		CrashReport CrashReport `yaml:"crashReport"`
+		// Secrets is the config of the providers resolving the secret references of the config
+		Secrets Secrets `yaml:"secrets"`
+		// TaskTokenSigning is the config of the signing of the task tokens, the task tokens are not signed if nil
+		TaskTokenSigning *TaskTokenSigning `yaml:"taskTokenSigning"`
+		// Health is the config of the listener of the health endpoints, which is shared by all services of the process
+		Health Health `yaml:"health"`
 	}
 
 	HeaderRule struct {
@@ -85,12 +105,20 @@ type (
 	Authorization struct {
 		OAuthAuthorizer OAuthAuthorizer `yaml:"oauthAuthorizer"`
 		NoopAuthorizer  NoopAuthorizer  `yaml:"noopAuthorizer"`
+		OPAAuthorizer   OPAAuthorizer   `yaml:"opaAuthorizer"`
+		// APIKeys authorizes the requests with an API key, the other requests are authorized by the enabled authorizer
+		APIKeys APIKeys `yaml:"apiKeys"`
+		// NetworkPolicy rejects the requests from the source addresses which are not allowed, before authorizing them
+		NetworkPolicy NetworkPolicy `yaml:"networkPolicy"`
 	}
 
 	DynamicConfig struct {
 		Client      string                              `yaml:"client"`
 		ConfigStore c.ClientConfig                      `yaml:"configstore"`
 		FileBased   dynamicconfig.FileBasedClientConfig `yaml:"filebased"`
+		S3          remote.S3Config                     `yaml:"s3"`
+		Etcd        remote.EtcdConfig                   `yaml:"etcd"`
+		Consul      remote.ConsulConfig                 `yaml:"consul"`
 	}
 
 	NoopAuthorizer struct {
@@ -105,6 +133,42 @@ type (
 		MaxJwtTTL int64 `yaml:"maxJwtTTL"`
 	}
 
+	// OPAAuthorizer delegates the authorization decisions to an Open Policy Agent, the input of the policy is the
+	// caller, its JWT claims, the API, the domain, the permission and the request of the API
+	OPAAuthorizer struct {
+		Enable bool `yaml:"enable"`
+		// URL is the endpoint of the decision in the data API, e.g. http://localhost:8181/v1/data/cadence/authz/allow.
+		// The decision is either a boolean, or an object with a boolean allow field
+		URL string `yaml:"url"`
+		// Timeout of the decision requests, 1s by default
+		Timeout time.Duration `yaml:"timeout"`
+		// JwtCredentials verifies the JWT of the callers, their claims are not part of the input if not set
+		JwtCredentials *JwtCredentials `yaml:"jwtCredentials"`
+		// Max of TTL in the claim
+		MaxJwtTTL int64 `yaml:"maxJwtTTL"`
+	}
+
+	// APIKeys is the config of the API keys issued with the admin CLI, the keys are stored in the config store
+	APIKeys struct {
+		Enable bool `yaml:"enable"`
+		// RefreshInterval is how often the keys are reloaded from the config store, 10s by default
+		RefreshInterval time.Duration `yaml:"refreshInterval"`
+	}
+
+	// NetworkPolicy restricts the source addresses of the requests per domain and API category. The address is the
+	// peer address of the gRPC requests, and the host port advertised by the callers of the TChannel requests.
+	NetworkPolicy struct {
+		Enable bool `yaml:"enable"`
+		// Allowlists maps a domain name to the allowlists of its API categories, the * domain applies to the domains
+		// without an allowlist for the category of the request, and to the APIs without a domain.
+		// The requests without a matching allowlist are allowed.
+		Allowlists map[string]NetworkAllowlist `yaml:"allowlists"`
+	}
+
+	// NetworkAllowlist maps an API category, i.e. the read, write or admin permission of the API or * for all
+	// of them, to the IPs and CIDRs allowed to call it
+	NetworkAllowlist map[string][]string
+
 	JwtCredentials struct {
 		// support: RS256 (RSA using SHA256)
 		Algorithm string `yaml:"algorithm"`
@@ -120,6 +184,24 @@ type (
 		Metrics Metrics `yaml:"metrics"`
 		// PProf is the PProf configuration
 		PProf PProf `yaml:"pprof"`
+		// AdminRPC is the optional dedicated listener of the admin APIs, it is only used by the frontend
+		AdminRPC *AdminRPC `yaml:"adminRpc"`
+	}
+
+	// AdminRPC contains the config of the dedicated listener of the admin APIs. When it is set, the admin APIs
+	// are only served on this listener, so the clusters replicating from this cluster must be configured with it.
+	AdminRPC struct {
+		// GRPCPort is the port on which the admin gRPC listener will bind to
+		GRPCPort uint16 `yaml:"grpcPort"`
+		// BindOnLocalHost is true if localhost is the bind address
+		BindOnLocalHost bool `yaml:"bindOnLocalHost"`
+		// BindOnIP can be used to bind the admin listener on specific ip (eg. `0.0.0.0`),
+		// mutually exclusive with `BindOnLocalHost` option
+		BindOnIP string `yaml:"bindOnIP"`
+		// TLS allows configuring optional TLS/SSL authentication on the admin listener
+		TLS TLS `yaml:"tls"`
+		// Authorization overrides the authorization of the admin APIs, the global authorization is used if nil
+		Authorization *Authorization `yaml:"authorization"`
 	}
 
 	// PProf contains the rpc config items
@@ -187,6 +269,8 @@ type (
 		// TODO: move dynamic config out of static config
 		// ErrorInjectionRate is the the rate for injecting random error
 		ErrorInjectionRate dynamicconfig.FloatPropertyFn `yaml:"-" json:"-"`
+		// Encryption is the config of the encryption of the history at rest, the history is not encrypted if nil
+		Encryption *Encryption `yaml:"encryption"`
 	}
 
 	// DataStore is the configuration for a single datastore
@@ -388,6 +472,8 @@ type (
 		// For summary, default objectives are defined in https://github.com/uber-go/tally/blob/137973e539cd3589f904c23d0b3a28c579fd0ae4/prometheus/reporter.go#L70
 		// You can customize the buckets/objectives if the default is not good enough.
 		Prometheus *prometheus.Configuration `yaml:"prometheus"`
+		// OTLP is the configuration for pushing metrics to an OpenTelemetry collector
+		OTLP *OTLPMetrics `yaml:"otlp"`
 		// Tags is the set of key-value pairs to be reported
 		// as part of every metric
 		Tags map[string]string `yaml:"tags"`
@@ -397,6 +483,18 @@ type (
 		ReportingInterval time.Duration `yaml:"reportingInterval"` // defaults to 1s
 	}
 
+	// OTLPMetrics contains the config items for pushing metrics with the OpenTelemetry protocol (OTLP) over http
+	OTLPMetrics struct {
+		// Endpoint is the base url of the OTLP http receiver, ex: http://localhost:4318
+		Endpoint string `yaml:"endpoint" validate:"nonzero"`
+		// Headers are added to every push request, ex: for authentication
+		Headers map[string]string `yaml:"headers"`
+		// PushInterval is the interval the metrics are pushed at, 10s by default
+		PushInterval time.Duration `yaml:"pushInterval"`
+		// TLS is used to connect to the endpoint
+		TLS TLS `yaml:"tls"`
+	}
+
 	// Statsd contains the config items for statsd metrics reporter
 	Statsd struct {
 		// The host and port of the statsd server
@@ -533,10 +631,38 @@ func (c *Config) validate() error {
 	if err := c.Archival.Validate(&c.DomainDefaults.Archival); err != nil {
 		return err
 	}
+	for name, service := range c.Services {
+		if service.AdminRPC == nil {
+			continue
+		}
+		if err := service.AdminRPC.Validate(); err != nil {
+			return fmt.Errorf("service %s: %v", name, err)
+		}
+	}
+
+	if c.TaskTokenSigning != nil {
+		if err := c.TaskTokenSigning.Validate(); err != nil {
+			return err
+		}
+	}
 
 	return c.Authorization.Validate()
 }
 
+// Validate validates the admin listener config
+func (a *AdminRPC) Validate() error {
+	if a.GRPCPort == 0 {
+		return fmt.Errorf("[AdminRPCConfig] grpcPort can't be empty")
+	}
+	if a.BindOnLocalHost && len(a.BindOnIP) > 0 {
+		return fmt.Errorf("[AdminRPCConfig] bindOnLocalHost and bindOnIP are mutually exclusive")
+	}
+	if a.Authorization != nil {
+		return a.Authorization.Validate()
+	}
+	return nil
+}
+
 func (c *Config) fillDefaults() {
 	c.Persistence.FillDefaults()
 
@@ -228,3 +228,50 @@ func TestGetServiceConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, svc)
 }
+
+func TestAdminRPCValidation(t *testing.T) {
+	tests := map[string]struct {
+		adminRPC AdminRPC
+		err      string
+	}{
+		"valid": {
+			adminRPC: AdminRPC{GRPCPort: 7934, BindOnLocalHost: true},
+		},
+		"missing port": {
+			adminRPC: AdminRPC{BindOnLocalHost: true},
+			err:      "[AdminRPCConfig] grpcPort can't be empty",
+		},
+		"conflicting bind options": {
+			adminRPC: AdminRPC{GRPCPort: 7934, BindOnLocalHost: true, BindOnIP: "1.2.3.4"},
+			err:      "[AdminRPCConfig] bindOnLocalHost and bindOnIP are mutually exclusive",
+		},
+		"invalid authorization": {
+			adminRPC: AdminRPC{GRPCPort: 7934, Authorization: &Authorization{
+				NoopAuthorizer:  NoopAuthorizer{Enable: true},
+				OAuthAuthorizer: OAuthAuthorizer{Enable: true},
+			}},
+			err: "[AuthorizationConfig] More than one authorizer is enabled",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.adminRPC.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestTaskTokenSigningValidation(t *testing.T) {
+	key := "MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE="
+	assert.NoError(t, (&TaskTokenSigning{Keys: map[string]string{"k1": key}, ActiveKeyID: "k1"}).Validate())
+	assert.EqualError(t, (&TaskTokenSigning{Keys: map[string]string{"k1": key}, ActiveKeyID: "k2"}).Validate(),
+		"[TaskTokenSigningConfig] active key k2 is not found")
+	assert.EqualError(t, (&TaskTokenSigning{Keys: map[string]string{"k1": "c2hvcnQ="}, ActiveKeyID: "k1"}).Validate(),
+		"[TaskTokenSigningConfig] key k1 must have at least 32 bytes")
+	assert.Error(t, (&TaskTokenSigning{Keys: map[string]string{"k1": "not base64"}, ActiveKeyID: "k1"}).Validate())
+}
@@ -0,0 +1,77 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import "time"
+
+const (
+	// EncryptionProviderAWSKMS generates the data keys with AWS KMS
+	EncryptionProviderAWSKMS = "awskms"
+	// EncryptionProviderVault generates the data keys with the transit secrets engine of Vault
+	EncryptionProviderVault = "vault"
+	// EncryptionProviderLocal generates the data keys with master keys set in the config, it is meant for development
+	EncryptionProviderLocal = "local"
+)
+
+type (
+	// Encryption is the config of the encryption of the history of the domains at rest. The domains are encrypted with
+	// the KMS key set by the system.historyEncryptionKeyID dynamic config, with data keys generated by the provider.
+	Encryption struct {
+		// Provider is the KMS generating and decrypting the data keys, one of awskms, vault or local
+		Provider string `yaml:"provider"`
+		// AWSKMS is the config of the awskms provider
+		AWSKMS AWSKMSEncryption `yaml:"awskms"`
+		// Vault is the config of the vault provider
+		Vault VaultEncryption `yaml:"vault"`
+		// Local is the config of the local provider
+		Local LocalEncryption `yaml:"local"`
+		// DataKeyTTL is how long a data key encrypts the history of a KMS key before a new one is generated, 1h by default
+		DataKeyTTL time.Duration `yaml:"dataKeyTTL"`
+		// DataKeyCacheSize is the number of decrypted data keys cached to read the history, 1000 by default
+		DataKeyCacheSize int `yaml:"dataKeyCacheSize"`
+	}
+
+	// AWSKMSEncryption is the config of the AWS KMS provider, the credentials are read from the environment
+	AWSKMSEncryption struct {
+		Region string `yaml:"region"`
+		// Endpoint overrides the endpoint of KMS, e.g. for a VPC endpoint
+		Endpoint string `yaml:"endpoint"`
+	}
+
+	// VaultEncryption is the config of the Vault transit secrets engine provider
+	VaultEncryption struct {
+		Address string `yaml:"address"`
+		// MountPath is the path the transit secrets engine is mounted at, transit by default
+		MountPath string `yaml:"mountPath"`
+		// Token authenticates to Vault, it is read from TokenFile if set instead
+		Token     string `yaml:"token"`
+		TokenFile string `yaml:"tokenFile"`
+		TLS       TLS    `yaml:"tls"`
+	}
+
+	// LocalEncryption is the config of the local provider
+	LocalEncryption struct {
+		// Keys are the base64 encoded 256 bits master keys by ID
+		Keys map[string]string `yaml:"keys"`
+	}
+)
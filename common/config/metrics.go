@@ -21,6 +21,7 @@
 package config
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/cactus/go-statsd-client/statsd"
@@ -32,6 +33,7 @@ import (
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
+	otlpreporter "github.com/uber/cadence/common/metrics/tally/otlp"
 	mprom "github.com/uber/cadence/common/metrics/tally/prometheus"
 	statsdreporter "github.com/uber/cadence/common/metrics/tally/statsd"
 )
@@ -89,6 +91,12 @@ func (c *Metrics) NewScope(logger log.Logger, service string) tally.Scope {
 		}
 		rootScope = c.newPrometheusScope(logger)
 	}
+	if c.OTLP != nil {
+		if rootScope != tally.NoopScope {
+			logger.Fatal("error creating metric reporter: cannot have more than one types of metric configuration")
+		}
+		rootScope = c.newOTLPScope(logger, service)
+	}
 	rootScope = rootScope.Tagged(map[string]string{metrics.CadenceServiceTagName: service})
 	return rootScope
 }
@@ -165,3 +173,37 @@ func (c *Metrics) newPrometheusScope(logger log.Logger) tally.Scope {
 	scope, _ := tally.NewRootScope(scopeOpts, c.ReportingInterval)
 	return scope
 }
+
+// newOTLPScope returns a new scope which pushes the metrics to an OpenTelemetry collector
+func (c *Metrics) newOTLPScope(logger log.Logger, service string) tally.Scope {
+	tlsConfig, err := c.OTLP.TLS.ToTLSConfig()
+	if err != nil {
+		logger.Fatal("error creating otlp reporter tls config", tag.Error(err))
+	}
+	client := &http.Client{}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
+	}
+	reporter, err := otlpreporter.NewReporter(otlpreporter.Options{
+		Endpoint:     c.OTLP.Endpoint,
+		Headers:      c.OTLP.Headers,
+		ServiceName:  service,
+		PushInterval: c.OTLP.PushInterval,
+		HTTPClient:   client,
+		OnError: func(err error) {
+			logger.Warn("error in otlp reporter", tag.Error(err))
+		},
+	})
+	if err != nil {
+		logger.Fatal("error creating otlp reporter", tag.Error(err))
+	}
+	scopeOpts := tally.ScopeOptions{
+		Tags:     c.Tags,
+		Reporter: reporter,
+		Prefix:   c.Prefix,
+	}
+	scope, _ := tally.NewRootScope(scopeOpts, c.ReportingInterval)
+	return scope
+}
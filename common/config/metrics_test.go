@@ -82,6 +82,15 @@ func (s *MetricsSuite) TestPrometheus() {
 	s.NotNil(scope)
 }
 
+func (s *MetricsSuite) TestOTLP() {
+	config := new(Metrics)
+	config.OTLP = &OTLPMetrics{
+		Endpoint: "http://127.0.0.1:4318",
+	}
+	scope := config.NewScope(loggerimpl.NewNopLogger(), "test")
+	s.NotNil(scope)
+}
+
 func (s *MetricsSuite) TestNoop() {
 	config := &Metrics{}
 	scope := config.NewScope(loggerimpl.NewNopLogger(), "test")
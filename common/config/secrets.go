@@ -0,0 +1,163 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	// SecretSchemeVault is the scheme of the references to the secrets stored in Vault
+	SecretSchemeVault = "vault"
+
+	secretSchemeSeparator = ":"
+)
+
+type (
+	// Secrets is the config of the providers resolving the secret references of the config
+	Secrets struct {
+		// Vault is the config of the provider of the secrets stored in HashiCorp Vault
+		Vault *VaultSecrets `yaml:"vault"`
+	}
+
+	// VaultSecrets is the config of the provider of the secrets stored in HashiCorp Vault
+	VaultSecrets struct {
+		Address string `yaml:"address"`
+		// Token authenticates to Vault, it is read from TokenFile if set instead
+		Token     string `yaml:"token"`
+		TokenFile string `yaml:"tokenFile"`
+		TLS       TLS    `yaml:"tls"`
+	}
+
+	// SecretProvider resolves the references to the secrets of a scheme
+	SecretProvider interface {
+		// GetSecret returns the value of the secret, the reference is the part after the scheme
+		GetSecret(reference string) (string, error)
+	}
+)
+
+// ResolveSecrets replaces the secret references of the credentials of the datastores and of the TLS keys and
+// certificates with the values given by the providers of their scheme. A secret reference has the form
+// <scheme>:<reference>, e.g. vault:secret/data/cadence/sql#password, the other values are left as is.
+// The TLS keys and certificates are written to private temporary files, as TLS is configured with file paths.
+func (c *Config) ResolveSecrets(providers map[string]SecretProvider) error {
+	resolver := &secretResolver{providers: providers}
+	for name, ds := range c.Persistence.DataStores {
+		for _, nosql := range []*NoSQL{ds.Cassandra, ds.NoSQL} {
+			if nosql == nil {
+				continue
+			}
+			resolver.resolve(&nosql.User)
+			resolver.resolve(&nosql.Password)
+			resolver.resolveTLS(nosql.TLS)
+		}
+		if ds.SQL != nil {
+			resolver.resolve(&ds.SQL.User)
+			resolver.resolve(&ds.SQL.Password)
+			for i := range ds.SQL.MultipleDatabasesConfig {
+				resolver.resolve(&ds.SQL.MultipleDatabasesConfig[i].User)
+				resolver.resolve(&ds.SQL.MultipleDatabasesConfig[i].Password)
+			}
+			resolver.resolveTLS(ds.SQL.TLS)
+		}
+		if ds.ElasticSearch != nil {
+			resolver.resolve(&ds.ElasticSearch.Username)
+			resolver.resolve(&ds.ElasticSearch.Password)
+			resolver.resolveTLS(&ds.ElasticSearch.TLS)
+		}
+		if resolver.err != nil {
+			return fmt.Errorf("datastore %v: %v", name, resolver.err)
+		}
+	}
+	for name, service := range c.Services {
+		resolver.resolveTLS(&service.RPC.TLS)
+		if service.AdminRPC != nil {
+			resolver.resolveTLS(&service.AdminRPC.TLS)
+		}
+		if resolver.err != nil {
+			return fmt.Errorf("service %v: %v", name, resolver.err)
+		}
+		c.Services[name] = service
+	}
+	return nil
+}
+
+type secretResolver struct {
+	providers map[string]SecretProvider
+	err       error
+}
+
+// resolve replaces the value with the secret it references, the first error is kept
+func (r *secretResolver) resolve(value *string) bool {
+	if r.err != nil {
+		return false
+	}
+	separator := strings.Index(*value, secretSchemeSeparator)
+	if separator < 0 {
+		return false
+	}
+	provider, ok := r.providers[(*value)[:separator]]
+	if !ok {
+		return false
+	}
+	secret, err := provider.GetSecret((*value)[separator+1:])
+	if err != nil {
+		r.err = fmt.Errorf("resolve secret %v: %v", *value, err)
+		return false
+	}
+	*value = secret
+	return true
+}
+
+func (r *secretResolver) resolveTLS(tls *TLS) {
+	if tls == nil {
+		return
+	}
+	r.resolveFile(&tls.CertFile)
+	r.resolveFile(&tls.KeyFile)
+	r.resolveFile(&tls.CaFile)
+	for i := range tls.CaFiles {
+		r.resolveFile(&tls.CaFiles[i])
+	}
+}
+
+// resolveFile replaces the path with the path of a private file containing the secret it references
+func (r *secretResolver) resolveFile(path *string) {
+	if !r.resolve(path) {
+		return
+	}
+	file, err := ioutil.TempFile("", "cadence-secret-")
+	if err != nil {
+		r.err = err
+		return
+	}
+	// the temporary files are only readable by the owner
+	defer file.Close()
+	if _, err := file.WriteString(*path); err != nil {
+		r.err = err
+		return
+	}
+	*path = file.Name()
+}
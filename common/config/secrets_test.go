@@ -0,0 +1,115 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mapSecretProvider map[string]string
+
+func (p mapSecretProvider) GetSecret(reference string) (string, error) {
+	value, ok := p[reference]
+	if !ok {
+		return "", fmt.Errorf("secret not found")
+	}
+	return value, nil
+}
+
+func TestResolveSecrets(t *testing.T) {
+	cfg := &Config{
+		Persistence: Persistence{
+			DataStores: map[string]DataStore{
+				"default": {
+					NoSQL: &NoSQL{User: "cassandra", Password: "vault:cassandra#password"},
+				},
+				"sql": {
+					SQL: &SQL{
+						User:     "vault:database/creds/cadence#username",
+						Password: "vault:database/creds/cadence#password",
+						TLS:      &TLS{KeyFile: "vault:tls#key", CertFile: "/etc/cadence/cert.pem"},
+					},
+				},
+				"es": {
+					ElasticSearch: &ElasticSearchConfig{Username: "vault:es#username", Password: "vault:es#password"},
+				},
+			},
+		},
+		Services: map[string]Service{
+			"frontend": {RPC: RPC{TLS: TLS{CaFiles: []string{"vault:tls#ca"}}}},
+		},
+	}
+	provider := mapSecretProvider{
+		"cassandra#password":              "cassandra-password",
+		"database/creds/cadence#username": "sql-user",
+		"database/creds/cadence#password": "sql-password",
+		"es#username":                     "es-user",
+		"es#password":                     "es-password",
+		"tls#key":                         "key-pem",
+		"tls#ca":                          "ca-pem",
+	}
+
+	require.NoError(t, cfg.ResolveSecrets(map[string]SecretProvider{SecretSchemeVault: provider}))
+
+	assert.Equal(t, "cassandra", cfg.Persistence.DataStores["default"].NoSQL.User)
+	assert.Equal(t, "cassandra-password", cfg.Persistence.DataStores["default"].NoSQL.Password)
+	sql := cfg.Persistence.DataStores["sql"].SQL
+	assert.Equal(t, "sql-user", sql.User)
+	assert.Equal(t, "sql-password", sql.Password)
+	assert.Equal(t, "/etc/cadence/cert.pem", sql.TLS.CertFile)
+	assertSecretFile(t, "key-pem", sql.TLS.KeyFile)
+	assert.Equal(t, "es-user", cfg.Persistence.DataStores["es"].ElasticSearch.Username)
+	assert.Equal(t, "es-password", cfg.Persistence.DataStores["es"].ElasticSearch.Password)
+	assertSecretFile(t, "ca-pem", cfg.Services["frontend"].RPC.TLS.CaFiles[0])
+}
+
+func TestResolveSecrets_Error(t *testing.T) {
+	cfg := &Config{
+		Persistence: Persistence{
+			DataStores: map[string]DataStore{
+				"default": {
+					NoSQL: &NoSQL{Password: "vault:unknown#password"},
+				},
+			},
+		},
+	}
+
+	err := cfg.ResolveSecrets(map[string]SecretProvider{SecretSchemeVault: mapSecretProvider{}})
+	assert.EqualError(t, err, "datastore default: resolve secret vault:unknown#password: secret not found")
+}
+
+func assertSecretFile(t *testing.T, expected string, path string) {
+	defer os.Remove(path)
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
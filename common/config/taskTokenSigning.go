@@ -0,0 +1,78 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/uber/cadence/common"
+)
+
+// TaskTokenSigning is the config of the signing of the task tokens returned to the workers. The keys are rotated by
+// adding a new key, making it active once all the hosts know it, then removing the previous key once its tokens expired.
+// The clusters a domain is replicated to must share the keys, as the requests of the workers can be forwarded to them.
+type TaskTokenSigning struct {
+	// Keys are the base64 encoded HMAC keys by ID, of at least 32 bytes
+	Keys map[string]string `yaml:"keys"`
+	// ActiveKeyID is the ID of the key signing the new tokens
+	ActiveKeyID string `yaml:"activeKeyID"`
+	// TTL is the validity of the tokens, they don't expire if it is 0. It must be longer than the activities,
+	// as their token is used to heartbeat and complete them.
+	TTL time.Duration `yaml:"ttl"`
+	// RequireSignature rejects the unsigned tokens, it must only be enabled once the unsigned tokens are not in use anymore
+	RequireSignature bool `yaml:"requireSignature"`
+}
+
+const minTaskTokenSigningKeySize = 32
+
+// Validate validates the task token signing config
+func (t *TaskTokenSigning) Validate() error {
+	_, err := t.GetSigningKeys()
+	return err
+}
+
+// GetSigningKeys returns the decoded signing keys
+func (t *TaskTokenSigning) GetSigningKeys() (*common.TaskTokenSigningKeys, error) {
+	keys := make(map[string][]byte, len(t.Keys))
+	for id, encoded := range t.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("[TaskTokenSigningConfig] key %v is not base64 encoded: %v", id, err)
+		}
+		if len(key) < minTaskTokenSigningKeySize {
+			return nil, fmt.Errorf("[TaskTokenSigningConfig] key %v must have at least %v bytes", id, minTaskTokenSigningKeySize)
+		}
+		keys[id] = key
+	}
+	if _, ok := keys[t.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("[TaskTokenSigningConfig] active key %v is not found", t.ActiveKeyID)
+	}
+	return &common.TaskTokenSigningKeys{
+		Keys:             keys,
+		ActiveKeyID:      t.ActiveKeyID,
+		TTL:              t.TTL,
+		RequireSignature: t.RequireSignature,
+	}, nil
+}
@@ -22,8 +22,8 @@ package config
 
 import (
 	"crypto/tls"
-	"crypto/x509"
-	"io/ioutil"
+
+	"github.com/uber/cadence/common/certificate"
 )
 
 type (
@@ -58,10 +58,39 @@ type (
 	}
 )
 
-// ToTLSConfig converts Cadence TLS config to crypto/tls.Config
+// ToTLSConfig converts Cadence TLS config to crypto/tls.Config for the client side of connections.
+// The certificates are reloaded when their files change, so that they can be rotated without a restart.
 func (config TLS) ToTLSConfig() (*tls.Config, error) {
+	tlsConfig, caPool, err := config.toTLSConfig()
+	if tlsConfig == nil || err != nil {
+		return nil, err
+	}
+	if caPool != nil && config.EnableHostVerification {
+		// RootCAs cannot be reloaded, the servers are verified against the current CA certs instead
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = caPool.VerifyConnection
+	}
+	return tlsConfig, nil
+}
+
+// ToServerTLSConfig converts Cadence TLS config to crypto/tls.Config for the server side of connections.
+// The certificates are reloaded when their files change, so that they can be rotated without a restart.
+func (config TLS) ToServerTLSConfig() (*tls.Config, error) {
+	tlsConfig, caPool, err := config.toTLSConfig()
+	if tlsConfig == nil || err != nil {
+		return nil, err
+	}
+	if caPool != nil && config.RequireClientAuth {
+		// ClientCAs cannot be reloaded, the clients are verified against the current CA certs instead
+		tlsConfig.ClientAuth = tls.RequireAnyClientCert
+		tlsConfig.VerifyConnection = caPool.VerifyClientConnection
+	}
+	return tlsConfig, nil
+}
+
+func (config TLS) toTLSConfig() (*tls.Config, *certificate.Pool, error) {
 	if !config.Enabled {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	// Setup base TLS config
@@ -81,16 +110,13 @@ func (config TLS) ToTLSConfig() (*tls.Config, error) {
 		caFiles = append(caFiles, config.CaFile)
 	}
 
+	var caPool *certificate.Pool
 	if len(caFiles) > 0 {
-		caCertPool := x509.NewCertPool()
-		for _, caFile := range caFiles {
-			caCert, err := ioutil.ReadFile(caFile)
-			if err != nil {
-				return nil, err
-			}
-			caCertPool.AppendCertsFromPEM(caCert)
+		var err error
+		if caPool, err = certificate.LoadPool(caFiles); err != nil {
+			return nil, nil, err
 		}
-		tlsConfig.RootCAs = caCertPool
+		tlsConfig.RootCAs = caPool.CertPool()
 	}
 
 	// Enable mutual TLS
@@ -101,12 +127,13 @@ func (config TLS) ToTLSConfig() (*tls.Config, error) {
 
 	// Load client cert
 	if config.CertFile != "" && config.KeyFile != "" {
-		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		keyPair, err := certificate.LoadKeyPair(config.CertFile, config.KeyFile)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		tlsConfig.Certificates = []tls.Certificate{cert}
+		tlsConfig.GetCertificate = keyPair.GetCertificate
+		tlsConfig.GetClientCertificate = keyPair.GetClientCertificate
 	}
 
-	return tlsConfig, nil
+	return tlsConfig, caPool, nil
 }
@@ -0,0 +1,143 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfigMutualAuthentication(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey := writeTestCertificate(t, dir, "ca", nil, nil)
+	writeTestCertificate(t, dir, "server", caCert, caKey)
+	writeTestCertificate(t, dir, "client", caCert, caKey)
+	otherCACert, otherCAKey := writeTestCertificate(t, dir, "other-ca", nil, nil)
+	writeTestCertificate(t, dir, "other-client", otherCACert, otherCAKey)
+
+	serverConfig, err := TLS{
+		Enabled:                true,
+		CertFile:               filepath.Join(dir, "server.pem"),
+		KeyFile:                filepath.Join(dir, "server-key.pem"),
+		CaFile:                 filepath.Join(dir, "ca.pem"),
+		EnableHostVerification: true,
+		RequireClientAuth:      true,
+	}.ToServerTLSConfig()
+	require.NoError(t, err)
+
+	clientConfig := func(name string) *tls.Config {
+		config, err := TLS{
+			Enabled:                true,
+			CertFile:               filepath.Join(dir, name+".pem"),
+			KeyFile:                filepath.Join(dir, name+"-key.pem"),
+			CaFile:                 filepath.Join(dir, "ca.pem"),
+			EnableHostVerification: true,
+			ServerName:             "localhost",
+		}.ToTLSConfig()
+		require.NoError(t, err)
+		return config
+	}
+
+	assert.NoError(t, handshake(t, serverConfig, clientConfig("client")))
+	assert.Error(t, handshake(t, serverConfig, clientConfig("other-client")))
+
+	disabled, err := TLS{}.ToTLSConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, disabled)
+}
+
+// writeTestCertificate writes the certificate and key of name, signed by the parent or self signed if nil
+func writeTestCertificate(
+	t *testing.T,
+	dir string,
+	name string,
+	parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey,
+) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	} else {
+		template.DNSNames = []string{"localhost"}
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+"-key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate, key
+}
+
+func handshake(t *testing.T, serverConfig *tls.Config, clientConfig *tls.Config) error {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		serverErr <- conn.(*tls.Conn).Handshake()
+	}()
+
+	client, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	// the client completes its handshake before the server verifies its certificate
+	return <-serverErr
+}
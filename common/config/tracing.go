@@ -0,0 +1,45 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package config
+
+import "time"
+
+type (
+	// Tracing is the config for tracing requests across services and exporting the spans
+	// with the OpenTelemetry protocol (OTLP) over http
+	Tracing struct {
+		Enabled bool `yaml:"enabled"`
+		// Endpoint is the base url of the OTLP http receiver, ex: http://localhost:4318
+		Endpoint string `yaml:"endpoint"`
+		// Headers are added to every export request, ex: for authentication
+		Headers map[string]string `yaml:"headers"`
+		// SampleRate is the fraction of traces which are exported, between 0 and 1
+		SampleRate float64 `yaml:"sampleRate"`
+		// BatchSize is the max number of spans exported in a request, 512 by default
+		BatchSize int `yaml:"batchSize"`
+		// FlushInterval is the max time spans are buffered before they are exported, 5s by default
+		FlushInterval time.Duration `yaml:"flushInterval"`
+		// TLS is used to connect to the endpoint
+		TLS TLS `yaml:"tls"`
+	}
+)
@@ -66,6 +66,10 @@ const (
 	EncodingTypeUnknown  EncodingType = "unknow"
 	EncodingTypeEmpty    EncodingType = ""
 	EncodingTypeProto    EncodingType = "proto3"
+	// EncodingTypeEncrypted is the encoding of the blobs encrypted at rest, the envelope carries the inner encoding
+	EncodingTypeEncrypted EncodingType = "encrypted"
+	// EncodingTypeCompressed is the encoding of the blobs compressed at rest, the frame carries the inner encoding
+	EncodingTypeCompressed EncodingType = "compressed"
 )
 
 type (
@@ -0,0 +1,245 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package crash writes a report of the panics captured by the services of a process, with the dump of all goroutines,
+// the requests recently handled by the host and the build info, so that panics can be investigated after the fact.
+package crash
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common/blobstore"
+	"github.com/uber/cadence/common/blobstore/filestore"
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/slowrequest"
+)
+
+const (
+	// recentRequestsSize is the number of most recent inbound requests included in a report
+	recentRequestsSize = 100
+	// writeTimeout bounds the time spent writing a report to the sink
+	writeTimeout = 10 * time.Second
+)
+
+type (
+	// Reporter writes a report of each panic captured by the services of the process to its sink,
+	// and keeps track of the recent inbound requests to include them in the reports
+	Reporter struct {
+		sink          blobstore.Client
+		metricsClient metrics.Client
+		logger        log.Logger
+		host          string
+
+		sync.Mutex
+		requests [recentRequestsSize]*trackedRequest
+		next     int
+	}
+
+	trackedRequest struct {
+		api       string
+		caller    string
+		start     time.Time
+		breakdown *slowrequest.Breakdown
+		done      int32
+	}
+
+	// Report describes a captured panic
+	Report struct {
+		Time       time.Time `json:"time"`
+		Host       string    `json:"host"`
+		Error      string    `json:"error"`
+		StackTrace string    `json:"stackTrace"`
+		// Goroutines is the dump of all the goroutines of the process when the panic was captured
+		Goroutines string `json:"goroutines"`
+		// Requests are the recent inbound requests of the host, the most recent first
+		Requests []Request `json:"requests"`
+		Build    Build     `json:"build"`
+	}
+
+	// Request is an inbound request recently handled by the host
+	Request struct {
+		API        string    `json:"api"`
+		Caller     string    `json:"caller"`
+		Domain     string    `json:"domain,omitempty"`
+		WorkflowID string    `json:"workflowId,omitempty"`
+		Start      time.Time `json:"start"`
+		InFlight   bool      `json:"inFlight"`
+	}
+
+	// Build describes the binary which panicked
+	Build struct {
+		Version   string `json:"version"`
+		Revision  string `json:"revision"`
+		Branch    string `json:"branch"`
+		BuildDate string `json:"buildDate"`
+		GoVersion string `json:"goVersion"`
+	}
+)
+
+// NewSink returns the sink the reports are written to according to the config, or nil if the reports are only logged
+func NewSink(cfg *config.CrashReport, blobstoreCfg *config.Blobstore) (blobstore.Client, error) {
+	switch {
+	case cfg.UseBlobstore:
+		if blobstoreCfg.Filestore == nil {
+			return nil, errors.New("crash reports are written to the blobstore but no blobstore is configured")
+		}
+		return filestore.NewFilestoreClient(blobstoreCfg.Filestore)
+	case cfg.Directory != "":
+		return filestore.NewFilestoreClient(&config.FileBlobstore{OutputDirectory: cfg.Directory})
+	default:
+		return nil, nil
+	}
+}
+
+// NewReporter creates a reporter, reports are only logged if sink is nil
+func NewReporter(sink blobstore.Client, metricsClient metrics.Client, logger log.Logger, host string) *Reporter {
+	return &Reporter{
+		sink:          sink,
+		metricsClient: metricsClient,
+		logger:        logger,
+		host:          host,
+	}
+}
+
+// Install makes the reporter report the panics captured by log.CapturePanic
+func Install(reporter *Reporter) {
+	log.SetPanicHandler(reporter.Report)
+}
+
+// TrackRequest records an inbound request as recently handled, the returned function must be called once it is done.
+// The domain and workflow of the request are read from the latency breakdown of the context, if any.
+func (r *Reporter) TrackRequest(ctx context.Context, api string, caller string) func() {
+	request := &trackedRequest{
+		api:       api,
+		caller:    caller,
+		start:     time.Now(),
+		breakdown: slowrequest.FromContext(ctx),
+	}
+
+	r.Lock()
+	r.requests[r.next] = request
+	r.next = (r.next + 1) % recentRequestsSize
+	r.Unlock()
+
+	return func() {
+		atomic.StoreInt32(&request.done, 1)
+	}
+}
+
+// Report writes the report of a captured panic to the sink, it is a log.PanicHandler
+func (r *Reporter) Report(err error, stackTrace string) {
+	scope := r.metricsClient.Scope(metrics.CrashReportScope)
+	scope.IncCounter(metrics.PanicsCapturedCounter)
+
+	report := &Report{
+		Time:       time.Now(),
+		Host:       r.host,
+		Error:      err.Error(),
+		StackTrace: stackTrace,
+		Goroutines: dumpGoroutines(),
+		Requests:   r.recentRequests(),
+		Build: Build{
+			Version:   metrics.ReleaseVersion,
+			Revision:  metrics.Revision,
+			Branch:    metrics.Branch,
+			BuildDate: metrics.BuildDate,
+			GoVersion: runtime.Version(),
+		},
+	}
+	if r.sink == nil {
+		return
+	}
+
+	key := fmt.Sprintf("crash-%v-%v.json", r.host, report.Time.UnixNano())
+	if err := r.write(key, report); err != nil {
+		scope.IncCounter(metrics.CrashReportFailures)
+		r.logger.Error("Failed to write crash report", tag.Key(key), tag.Error(err))
+		return
+	}
+	r.logger.Info("Crash report written", tag.Key(key))
+}
+
+func (r *Reporter) write(key string, report *Report) error {
+	body, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	_, err = r.sink.Put(ctx, &blobstore.PutRequest{
+		Key: key,
+		Blob: blobstore.Blob{
+			Tags: map[string]string{"host": r.host},
+			Body: body,
+		},
+	})
+	return err
+}
+
+// recentRequests returns the recent requests, the most recent first
+func (r *Reporter) recentRequests() []Request {
+	r.Lock()
+	tracked := make([]*trackedRequest, 0, recentRequestsSize)
+	for i := 1; i <= recentRequestsSize; i++ {
+		if request := r.requests[(r.next-i+recentRequestsSize)%recentRequestsSize]; request != nil {
+			tracked = append(tracked, request)
+		}
+	}
+	r.Unlock()
+
+	requests := make([]Request, 0, len(tracked))
+	for _, request := range tracked {
+		report := Request{
+			API:      request.api,
+			Caller:   request.caller,
+			Start:    request.start,
+			InFlight: atomic.LoadInt32(&request.done) == 0,
+		}
+		if request.breakdown != nil {
+			report.Domain, report.WorkflowID = request.breakdown.Workflow()
+		}
+		requests = append(requests, report)
+	}
+	return requests
+}
+
+func dumpGoroutines() string {
+	var buf bytes.Buffer
+	// debug=2 prints the goroutines in the same format as an unrecovered panic
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		return fmt.Sprintf("failed to dump goroutines: %v", err)
+	}
+	return buf.String()
+}
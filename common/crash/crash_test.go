@@ -0,0 +1,121 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package crash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/blobstore"
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/slowrequest"
+)
+
+func TestReporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestReporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sink, err := NewSink(&config.CrashReport{Directory: dir}, &config.Blobstore{})
+	require.NoError(t, err)
+	testScope := tally.NewTestScope("test", nil)
+	reporter := NewReporter(sink, metrics.NewClient(testScope, metrics.Frontend), loggerimpl.NewNopLogger(), "test-host")
+	Install(reporter)
+	defer log.SetPanicHandler(nil)
+
+	ctx, _ := slowrequest.NewContext(context.Background())
+	slowrequest.SetWorkflow(ctx, "test-domain", "test-workflow")
+	done := reporter.TrackRequest(ctx, "StartWorkflowExecution", "test-caller")
+	done()
+	reporter.TrackRequest(context.Background(), "DescribeDomain", "test-caller")
+
+	func() {
+		defer func() { log.CapturePanic(recover(), loggerimpl.NewNopLogger(), &err) }()
+		panic(errors.New("test panic"))
+	}()
+	assert.EqualError(t, err, "test panic")
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var key string
+	for _, file := range files {
+		if file.Name()[0] != '.' {
+			key = file.Name()
+		}
+	}
+	require.NotEmpty(t, key)
+	response, err := sink.Get(context.Background(), &blobstore.GetRequest{Key: key})
+	require.NoError(t, err)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(response.Blob.Body, &report))
+	assert.Equal(t, "test-host", report.Host)
+	assert.Equal(t, "test panic", report.Error)
+	assert.Contains(t, report.StackTrace, "TestReporter")
+	assert.Contains(t, report.Goroutines, "goroutine")
+	assert.Equal(t, metrics.Revision, report.Build.Revision)
+	require.Len(t, report.Requests, 2)
+	assert.Equal(t, "DescribeDomain", report.Requests[0].API)
+	assert.True(t, report.Requests[0].InFlight)
+	assert.Empty(t, report.Requests[0].Domain)
+	assert.Equal(t, "StartWorkflowExecution", report.Requests[1].API)
+	assert.False(t, report.Requests[1].InFlight)
+	assert.Equal(t, "test-domain", report.Requests[1].Domain)
+	assert.Equal(t, "test-workflow", report.Requests[1].WorkflowID)
+
+	counters := testScope.Snapshot().Counters()
+	assert.Equal(t, int64(1), counters["test.panics_captured+operation=CrashReport"].Value())
+}
+
+func TestReporterRecentRequestsWrapAround(t *testing.T) {
+	reporter := NewReporter(nil, metrics.NewNoopMetricsClient(), loggerimpl.NewNopLogger(), "test-host")
+	for i := 0; i < recentRequestsSize+10; i++ {
+		reporter.TrackRequest(context.Background(), "DescribeDomain", "test-caller")()
+	}
+	reporter.TrackRequest(context.Background(), "StartWorkflowExecution", "test-caller")
+
+	requests := reporter.recentRequests()
+	assert.Len(t, requests, recentRequestsSize)
+	assert.Equal(t, "StartWorkflowExecution", requests[0].API)
+	assert.Equal(t, "DescribeDomain", requests[1].API)
+}
+
+func TestNewSink(t *testing.T) {
+	sink, err := NewSink(&config.CrashReport{}, &config.Blobstore{})
+	assert.NoError(t, err)
+	assert.Nil(t, sink, "reports are only logged without a sink")
+
+	_, err = NewSink(&config.CrashReport{UseBlobstore: true}, &config.Blobstore{})
+	assert.Error(t, err)
+}
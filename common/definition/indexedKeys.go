@@ -43,6 +43,9 @@ const (
 	CustomDomain    = "CustomDomain" // to support batch workflow
 	Operator        = "Operator"     // to support batch workflow
 
+	// HistorySizeWarning flags the executions whose history crossed the size or event count warn limits
+	HistorySizeWarning = "HistorySizeWarning"
+
 	CustomStringField    = "CustomStringField"
 	CustomKeywordField   = "CustomKeywordField"
 	CustomIntField       = "CustomIntField"
@@ -75,6 +78,7 @@ func createDefaultIndexedKeys() map[string]interface{} {
 		BinaryChecksums:      types.IndexedValueTypeKeyword,
 		CustomDomain:         types.IndexedValueTypeString,
 		Operator:             types.IndexedValueTypeString,
+		HistorySizeWarning:   types.IndexedValueTypeBool,
 	}
 	for k, v := range systemIndexedKeys {
 		defaultIndexedKeys[k] = v
@@ -0,0 +1,325 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package diagnostics collects profiles and verbose logs of a running host for a bounded duration,
+// so production issues can be debugged without restarting the host with different flags
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// ProfileCPU is the cpu profile collected over the duration
+	ProfileCPU = "cpu"
+	// ProfileTrace is the execution trace collected over the duration
+	ProfileTrace = "trace"
+	// ProfileMutex is the mutex contention profile, mutex profiling is only enabled for the duration
+	ProfileMutex = "mutex"
+	// ProfileBlock is the blocking profile, block profiling is only enabled for the duration
+	ProfileBlock = "block"
+	// ProfileHeap is a snapshot of the heap taken at the end of the duration
+	ProfileHeap = "heap"
+	// ProfileAllocs is a snapshot of the past allocations taken at the end of the duration
+	ProfileAllocs = "allocs"
+	// ProfileGoroutine is a snapshot of the goroutines taken at the end of the duration
+	ProfileGoroutine = "goroutine"
+	// ProfileThreadCreate is a snapshot of the thread creations taken at the end of the duration
+	ProfileThreadCreate = "threadcreate"
+
+	// DefaultDuration is the duration of a collection if none is requested
+	DefaultDuration = 30 * time.Second
+	// MaxDuration bounds the duration of a collection, mutex and block profiling are not free
+	MaxDuration = 5 * time.Minute
+
+	// mutexProfileFraction reports on average 1/n of the mutex contention events
+	mutexProfileFraction = 5
+	// blockProfileRate samples on average one blocking event per rate nanoseconds spent blocked
+	blockProfileRate = 10000
+	// maxLogBytes bounds the verbose logs kept in memory for a collection
+	maxLogBytes = 64 * 1024 * 1024
+
+	infoFile = "info.json"
+	logsFile = "logs.json"
+)
+
+// ErrBusy is returned if a collection is requested while another one is in progress
+var ErrBusy = errors.New("another diagnostics collection is in progress")
+
+var allProfiles = []string{
+	ProfileCPU,
+	ProfileTrace,
+	ProfileMutex,
+	ProfileBlock,
+	ProfileHeap,
+	ProfileAllocs,
+	ProfileGoroutine,
+	ProfileThreadCreate,
+}
+
+type (
+	// Options configures a collection
+	Options struct {
+		// Duration of the collection, DefaultDuration is used if it is zero
+		Duration time.Duration
+		// Profiles to collect, all profiles are collected if it is empty
+		Profiles []string
+		// VerboseLogging captures the logs of all levels, including debug, for the duration
+		VerboseLogging bool
+	}
+
+	// Collector runs diagnostics collections, one at a time
+	Collector struct {
+		sync.Mutex
+		collecting bool
+		// logs is set for the duration of a collection with verbose logging
+		logs *logBuffer
+		now  func() time.Time
+	}
+
+	// Info describes a collection, it is written as info.json to the archive
+	Info struct {
+		Host           string
+		Start          time.Time
+		End            time.Time
+		Profiles       []string
+		VerboseLogging bool
+		GoVersion      string
+		NumGoroutine   int
+		NumCPU         int
+		LogsTruncated  bool `json:",omitempty"`
+	}
+
+	logBuffer struct {
+		sync.Mutex
+		buf       bytes.Buffer
+		truncated bool
+	}
+)
+
+// NewCollector creates a new diagnostics collector
+func NewCollector() *Collector {
+	return &Collector{now: time.Now}
+}
+
+// Collect enables the requested profiles and verbose logging for the duration of the collection,
+// and writes them as a zip archive to w. It returns ErrBusy if another collection is in progress.
+func (c *Collector) Collect(ctx context.Context, opts Options, w io.Writer) error {
+	profiles, err := validateOptions(&opts)
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	if c.collecting {
+		c.Unlock()
+		return ErrBusy
+	}
+	c.collecting = true
+	c.Unlock()
+	defer func() {
+		c.Lock()
+		c.collecting = false
+		c.logs = nil
+		c.Unlock()
+	}()
+
+	info := Info{
+		Start:          c.now(),
+		Profiles:       opts.Profiles,
+		VerboseLogging: opts.VerboseLogging,
+		GoVersion:      runtime.Version(),
+		NumCPU:         runtime.NumCPU(),
+	}
+	info.Host, _ = os.Hostname()
+
+	var cpuProfile, execTrace bytes.Buffer
+	cpuStarted, traceStarted := false, false
+	stopRecording := func() {
+		c.Lock()
+		c.logs = nil
+		c.Unlock()
+		if cpuStarted {
+			pprof.StopCPUProfile()
+		}
+		if traceStarted {
+			trace.Stop()
+		}
+	}
+	if profiles[ProfileCPU] {
+		if err := pprof.StartCPUProfile(&cpuProfile); err != nil {
+			return fmt.Errorf("failed to start cpu profile: %v", err)
+		}
+		cpuStarted = true
+	}
+	if profiles[ProfileTrace] {
+		if err := trace.Start(&execTrace); err != nil {
+			stopRecording()
+			return fmt.Errorf("failed to start execution trace: %v", err)
+		}
+		traceStarted = true
+	}
+	if profiles[ProfileMutex] {
+		previous := runtime.SetMutexProfileFraction(mutexProfileFraction)
+		defer runtime.SetMutexProfileFraction(previous)
+	}
+	if profiles[ProfileBlock] {
+		// the block profile rate cannot be read back, it is disabled by default
+		runtime.SetBlockProfileRate(blockProfileRate)
+		defer runtime.SetBlockProfileRate(0)
+	}
+	var logs *logBuffer
+	if opts.VerboseLogging {
+		logs = &logBuffer{}
+		c.Lock()
+		c.logs = logs
+		c.Unlock()
+	}
+
+	timer := time.NewTimer(opts.Duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		stopRecording()
+		return ctx.Err()
+	case <-timer.C:
+	}
+	stopRecording()
+	info.End = c.now()
+	info.NumGoroutine = runtime.NumGoroutine()
+
+	archive := zip.NewWriter(w)
+	if profiles[ProfileCPU] {
+		if err := writeFile(archive, ProfileCPU+".pprof", cpuProfile.Bytes()); err != nil {
+			return err
+		}
+	}
+	if profiles[ProfileTrace] {
+		if err := writeFile(archive, ProfileTrace+".out", execTrace.Bytes()); err != nil {
+			return err
+		}
+	}
+	for _, name := range opts.Profiles {
+		if name == ProfileCPU || name == ProfileTrace {
+			continue
+		}
+		var profile bytes.Buffer
+		if err := pprof.Lookup(name).WriteTo(&profile, 0); err != nil {
+			return fmt.Errorf("failed to write %v profile: %v", name, err)
+		}
+		if err := writeFile(archive, name+".pprof", profile.Bytes()); err != nil {
+			return err
+		}
+	}
+	if logs != nil {
+		logs.Lock()
+		info.LogsTruncated = logs.truncated
+		err := writeFile(archive, logsFile, logs.buf.Bytes())
+		logs.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	infoData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFile(archive, infoFile, infoData); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// WrapCore tees the log entries of core into the collections with verbose logging, it is meant to be used with zap.WrapCore
+func (c *Collector) WrapCore(core zapcore.Core) zapcore.Core {
+	return zapcore.NewTee(core, &captureCore{collector: c, encoder: newLogEncoder()})
+}
+
+func (c *Collector) activeLogs() *logBuffer {
+	c.Lock()
+	defer c.Unlock()
+	return c.logs
+}
+
+func (b *logBuffer) write(data []byte) {
+	b.Lock()
+	defer b.Unlock()
+	if b.buf.Len()+len(data) > maxLogBytes {
+		b.truncated = true
+		return
+	}
+	b.buf.Write(data) //nolint:errcheck
+}
+
+func validateOptions(opts *Options) (map[string]bool, error) {
+	if opts.Duration == 0 {
+		opts.Duration = DefaultDuration
+	}
+	if opts.Duration < 0 || opts.Duration > MaxDuration {
+		return nil, fmt.Errorf("duration must be between 0 and %v, got %v", MaxDuration, opts.Duration)
+	}
+	if len(opts.Profiles) == 0 {
+		opts.Profiles = allProfiles
+	}
+
+	known := make(map[string]bool, len(allProfiles))
+	for _, name := range allProfiles {
+		known[name] = true
+	}
+	profiles := make(map[string]bool, len(opts.Profiles))
+	for _, name := range opts.Profiles {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown profile %v, supported profiles are %v", name, allProfiles)
+		}
+		profiles[name] = true
+	}
+	opts.Profiles = make([]string, 0, len(profiles))
+	for name := range profiles {
+		opts.Profiles = append(opts.Profiles, name)
+	}
+	sort.Strings(opts.Profiles)
+	return profiles, nil
+}
+
+func writeFile(archive *zip.Writer, name string, data []byte) error {
+	f, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
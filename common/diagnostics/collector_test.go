@@ -0,0 +1,137 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCollect(t *testing.T) {
+	collector := NewCollector()
+	logger := zap.NewNop().WithOptions(zap.WrapCore(collector.WrapCore)).With(zap.String("service", "test"))
+
+	logger.Debug("before collection")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for collector.activeLogs() == nil {
+			time.Sleep(time.Millisecond)
+		}
+		logger.Debug("during collection")
+	}()
+
+	var archive bytes.Buffer
+	err := collector.Collect(context.Background(), Options{Duration: 100 * time.Millisecond, VerboseLogging: true}, &archive)
+	require.NoError(t, err)
+	<-done
+	logger.Debug("after collection")
+
+	files := readArchive(t, archive.Bytes())
+	for _, name := range []string{"cpu.pprof", "trace.out", "mutex.pprof", "block.pprof", "heap.pprof", "allocs.pprof", "goroutine.pprof", "threadcreate.pprof", infoFile, logsFile} {
+		assert.Contains(t, files, name)
+	}
+
+	logs := string(files[logsFile])
+	assert.Contains(t, logs, `"msg":"during collection"`)
+	assert.Contains(t, logs, `"service":"test"`)
+	assert.NotContains(t, logs, "before collection")
+	assert.NotContains(t, logs, "after collection")
+
+	var info Info
+	require.NoError(t, json.Unmarshal(files[infoFile], &info))
+	assert.ElementsMatch(t, allProfiles, info.Profiles)
+	assert.True(t, info.VerboseLogging)
+	assert.True(t, info.End.Sub(info.Start) >= 100*time.Millisecond)
+}
+
+func TestCollect_SelectedProfiles(t *testing.T) {
+	collector := NewCollector()
+
+	var archive bytes.Buffer
+	err := collector.Collect(context.Background(), Options{Duration: time.Millisecond, Profiles: []string{ProfileMutex, ProfileHeap, ProfileMutex}}, &archive)
+	require.NoError(t, err)
+
+	files := readArchive(t, archive.Bytes())
+	assert.Len(t, files, 3)
+	assert.Contains(t, files, "mutex.pprof")
+	assert.Contains(t, files, "heap.pprof")
+	assert.Contains(t, files, infoFile)
+}
+
+func TestCollect_InvalidOptions(t *testing.T) {
+	collector := NewCollector()
+
+	err := collector.Collect(context.Background(), Options{Duration: MaxDuration + time.Second}, ioutil.Discard)
+	assert.EqualError(t, err, "duration must be between 0 and 5m0s, got 5m1s")
+
+	err = collector.Collect(context.Background(), Options{Duration: time.Millisecond, Profiles: []string{"unknown"}}, ioutil.Discard)
+	assert.Error(t, err)
+}
+
+func TestCollect_Busy(t *testing.T) {
+	collector := NewCollector()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errC := make(chan error)
+	go func() {
+		errC <- collector.Collect(ctx, Options{Duration: time.Minute, Profiles: []string{ProfileHeap}}, ioutil.Discard)
+	}()
+	require.Eventually(t, func() bool {
+		collector.Lock()
+		defer collector.Unlock()
+		return collector.collecting
+	}, time.Second, time.Millisecond)
+
+	err := collector.Collect(context.Background(), Options{Duration: time.Millisecond}, ioutil.Discard)
+	assert.Equal(t, ErrBusy, err)
+
+	// cancelling the collection in progress frees the collector
+	cancel()
+	assert.Equal(t, context.Canceled, <-errC)
+	err = collector.Collect(context.Background(), Options{Duration: time.Millisecond, Profiles: []string{ProfileHeap}}, ioutil.Discard)
+	assert.NoError(t, err)
+}
+
+func readArchive(t *testing.T, data []byte) map[string][]byte {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = ioutil.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+	}
+	return files
+}
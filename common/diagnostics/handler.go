@@ -0,0 +1,114 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package diagnostics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uber/cadence/common"
+)
+
+// HandlerPath is the path of the diagnostics endpoint. A collection is requested with
+// ?seconds=30&profiles=cpu,mutex&verbose=true and the collected artifacts are returned as a zip archive
+const HandlerPath = "/debug/diagnostics"
+
+type handler struct {
+	collector     *Collector
+	validateToken func(token string) error
+}
+
+// NewHandler creates the http handler of the diagnostics endpoint, requests must carry a token in the
+// authorization header which is accepted by validateToken. All the requests are rejected if it is nil,
+// collections expose the logs and memory of the process so they are never served without authorization
+func NewHandler(collector *Collector, validateToken func(token string) error) http.Handler {
+	return &handler{
+		collector:     collector,
+		validateToken: validateToken,
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.validateToken == nil {
+		http.Error(w, "request is not authorized: diagnostics require the OAuth authorizer to be enabled", http.StatusUnauthorized)
+		return
+	}
+	if err := h.validateToken(r.Header.Get(common.AuthorizationTokenHeaderName)); err != nil {
+		http.Error(w, "request is not authorized: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	opts, err := parseOptions(r)
+	if err == nil {
+		_, err = validateOptions(&opts)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// the archive is buffered so failures of the collection can still be reported with the status code
+	var archive bytes.Buffer
+	if err := h.collector.Collect(r.Context(), opts, &archive); err != nil {
+		switch {
+		case errors.Is(err, ErrBusy):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=diagnostics-%v.zip", time.Now().Unix()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive.Bytes()) //nolint:errcheck
+}
+
+func parseOptions(r *http.Request) (Options, error) {
+	var opts Options
+	query := r.URL.Query()
+	if param := query.Get("seconds"); param != "" {
+		seconds, err := strconv.Atoi(param)
+		if err != nil {
+			return opts, fmt.Errorf("invalid seconds %v: %v", param, err)
+		}
+		opts.Duration = time.Duration(seconds) * time.Second
+	}
+	if param := query.Get("profiles"); param != "" {
+		opts.Profiles = strings.Split(param, ",")
+	}
+	if param := query.Get("verbose"); param != "" {
+		verbose, err := strconv.ParseBool(param)
+		if err != nil {
+			return opts, fmt.Errorf("invalid verbose %v: %v", param, err)
+		}
+		opts.VerboseLogging = verbose
+	}
+	return opts, nil
+}
@@ -0,0 +1,130 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package diagnostics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common"
+)
+
+func TestHandler(t *testing.T) {
+	validateToken := func(token string) error {
+		if token != "admin" {
+			return errors.New("not an admin")
+		}
+		return nil
+	}
+	handler := NewHandler(NewCollector(), validateToken)
+
+	tests := map[string]struct {
+		query          string
+		token          string
+		expectedStatus int
+	}{
+		"missing token": {
+			query:          "?seconds=1",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"invalid token": {
+			query:          "?seconds=1",
+			token:          "reader",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"invalid seconds": {
+			query:          "?seconds=abc",
+			token:          "admin",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"invalid verbose": {
+			query:          "?verbose=maybe",
+			token:          "admin",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown profile": {
+			query:          "?seconds=1&profiles=unknown",
+			token:          "admin",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"duration too long": {
+			query:          "?seconds=3600",
+			token:          "admin",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"success": {
+			query:          "?seconds=1&profiles=heap,goroutine&verbose=true",
+			token:          "admin",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, HandlerPath+test.query, nil)
+			if test.token != "" {
+				r.Header.Set(common.AuthorizationTokenHeaderName, test.token)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			assert.Equal(t, test.expectedStatus, w.Code, w.Body.String())
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+				files := readArchive(t, w.Body.Bytes())
+				assert.Contains(t, files, "heap.pprof")
+				assert.Contains(t, files, "goroutine.pprof")
+				assert.Contains(t, files, logsFile)
+			}
+		})
+	}
+}
+
+func TestHandler_Busy(t *testing.T) {
+	collector := NewCollector()
+	collector.collecting = true
+	handler := NewHandler(collector, func(string) error { return nil })
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, HandlerPath, nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestHandler_NoTokenValidator(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, HandlerPath+"?seconds=1", nil)
+	r.Header.Set(common.AuthorizationTokenHeaderName, "admin")
+	w := httptest.NewRecorder()
+	NewHandler(NewCollector(), nil).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestParseOptions(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, HandlerPath+"?seconds=10&profiles=cpu,block&verbose=1", nil)
+	opts, err := parseOptions(r)
+	assert.NoError(t, err)
+	assert.Equal(t, Options{Duration: 10 * time.Second, Profiles: []string{ProfileCPU, ProfileBlock}, VerboseLogging: true}, opts)
+}
@@ -31,11 +31,15 @@ import (
 const (
 	ConfigStoreClient = "configstore"
 	FileBasedClient   = "filebased"
+	S3Client          = "s3"
+	EtcdClient        = "etcd"
+	ConsulClient      = "consul"
 	InMemoryClient    = "memory"
 	NopClient         = "nop"
 )
 
-// Client allows fetching values from a dynamic configuration system NOTE: This does not have async
+// Client allows fetching values from a dynamic configuration system. Clients can implement UpdateNotifier
+// to support subscriptions on a Collection. NOTE: This does not have async
 // options right now. In the interest of keeping it minimal, we can add when requirement arises.
 type Client interface {
 	GetValue(name Key) (interface{}, error)
@@ -54,6 +58,22 @@ type Client interface {
 	ListValue(name Key) ([]*types.DynamicConfigEntry, error)
 }
 
+// AuditedClient is implemented by clients which record who changed a value in a durable change history.
+// Callers which know the identity behind a change should prefer it over UpdateValue and RestoreValue.
+type AuditedClient interface {
+	UpdateValueWithIdentity(name Key, value interface{}, identity string) error
+	RestoreValueWithIdentity(name Key, filters map[Filter]interface{}, identity string) error
+	ListChanges(request *types.ListDynamicConfigChangesRequest) (*types.ListDynamicConfigChangesResponse, error)
+}
+
+// ConditionalClient is implemented by clients which can update a value from its current value without losing
+// a concurrent change to it, e.g. to change the value of a single filter while keeping the values of the others.
+type ConditionalClient interface {
+	// UpdateValueFromCurrent replaces the values of the key with the values update returns for its current values,
+	// update can be called again with the latest values when they were changed concurrently
+	UpdateValueFromCurrent(name Key, update func(current []*types.DynamicConfigValue) ([]*types.DynamicConfigValue, error), identity string) error
+}
+
 var NotFoundError = &types.EntityNotExistsError{
 	Message: "unable to find key",
 }
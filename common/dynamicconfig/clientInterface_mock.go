@@ -235,3 +235,106 @@ func (mr *MockClientMockRecorder) UpdateValue(name, value interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateValue", reflect.TypeOf((*MockClient)(nil).UpdateValue), name, value)
 }
+
+// MockAuditedClient is a mock of AuditedClient interface.
+type MockAuditedClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditedClientMockRecorder
+}
+
+// MockAuditedClientMockRecorder is the mock recorder for MockAuditedClient.
+type MockAuditedClientMockRecorder struct {
+	mock *MockAuditedClient
+}
+
+// NewMockAuditedClient creates a new mock instance.
+func NewMockAuditedClient(ctrl *gomock.Controller) *MockAuditedClient {
+	mock := &MockAuditedClient{ctrl: ctrl}
+	mock.recorder = &MockAuditedClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditedClient) EXPECT() *MockAuditedClientMockRecorder {
+	return m.recorder
+}
+
+// ListChanges mocks base method.
+func (m *MockAuditedClient) ListChanges(request *types.ListDynamicConfigChangesRequest) (*types.ListDynamicConfigChangesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChanges", request)
+	ret0, _ := ret[0].(*types.ListDynamicConfigChangesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChanges indicates an expected call of ListChanges.
+func (mr *MockAuditedClientMockRecorder) ListChanges(request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChanges", reflect.TypeOf((*MockAuditedClient)(nil).ListChanges), request)
+}
+
+// RestoreValueWithIdentity mocks base method.
+func (m *MockAuditedClient) RestoreValueWithIdentity(name Key, filters map[Filter]interface{}, identity string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreValueWithIdentity", name, filters, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreValueWithIdentity indicates an expected call of RestoreValueWithIdentity.
+func (mr *MockAuditedClientMockRecorder) RestoreValueWithIdentity(name, filters, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreValueWithIdentity", reflect.TypeOf((*MockAuditedClient)(nil).RestoreValueWithIdentity), name, filters, identity)
+}
+
+// UpdateValueWithIdentity mocks base method.
+func (m *MockAuditedClient) UpdateValueWithIdentity(name Key, value interface{}, identity string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateValueWithIdentity", name, value, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateValueWithIdentity indicates an expected call of UpdateValueWithIdentity.
+func (mr *MockAuditedClientMockRecorder) UpdateValueWithIdentity(name, value, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateValueWithIdentity", reflect.TypeOf((*MockAuditedClient)(nil).UpdateValueWithIdentity), name, value, identity)
+}
+
+// MockConditionalClient is a mock of ConditionalClient interface.
+type MockConditionalClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockConditionalClientMockRecorder
+}
+
+// MockConditionalClientMockRecorder is the mock recorder for MockConditionalClient.
+type MockConditionalClientMockRecorder struct {
+	mock *MockConditionalClient
+}
+
+// NewMockConditionalClient creates a new mock instance.
+func NewMockConditionalClient(ctrl *gomock.Controller) *MockConditionalClient {
+	mock := &MockConditionalClient{ctrl: ctrl}
+	mock.recorder = &MockConditionalClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConditionalClient) EXPECT() *MockConditionalClientMockRecorder {
+	return m.recorder
+}
+
+// UpdateValueFromCurrent mocks base method.
+func (m *MockConditionalClient) UpdateValueFromCurrent(name Key, update func([]*types.DynamicConfigValue) ([]*types.DynamicConfigValue, error), identity string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateValueFromCurrent", name, update, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateValueFromCurrent indicates an expected call of UpdateValueFromCurrent.
+func (mr *MockConditionalClientMockRecorder) UpdateValueFromCurrent(name, update, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateValueFromCurrent", reflect.TypeOf((*MockConditionalClient)(nil).UpdateValueFromCurrent), name, update, identity)
+}
@@ -62,6 +62,11 @@ type Collection struct {
 	logKeys       *sync.Map // map of config Keys for logging to capture changes
 	errCount      int64
 	filterOptions []FilterOption
+
+	subscriptionsInit sync.Once
+	subscriptionsLock sync.Mutex
+	subscriptions     map[*subscription]struct{}
+	refreshLock       sync.Mutex
 }
 
 func (c *Collection) logError(
@@ -122,6 +127,9 @@ type IntPropertyFnWithShardIDFilter func(shardID int) int
 // FloatPropertyFn is a wrapper to get float property from dynamic config
 type FloatPropertyFn func(opts ...FilterOption) float64
 
+// FloatPropertyFnWithDomainFilter is a wrapper to get float property from dynamic config with domain as filter
+type FloatPropertyFnWithDomainFilter func(domain string) float64
+
 // FloatPropertyFnWithShardIDFilter is a wrapper to get float property from dynamic config with shardID as filter
 type FloatPropertyFnWithShardIDFilter func(shardID int) float64
 
@@ -315,6 +323,23 @@ func (c *Collection) GetFloat64Property(key FloatKey) FloatPropertyFn {
 	}
 }
 
+// GetFloat64PropertyFilteredByDomain gets property with domain filter and asserts that it's a float64
+func (c *Collection) GetFloat64PropertyFilteredByDomain(key FloatKey) FloatPropertyFnWithDomainFilter {
+	return func(domain string) float64 {
+		filters := c.toFilterMap(DomainFilter(domain))
+		val, err := c.client.GetFloatValue(
+			key,
+			filters,
+		)
+		if err != nil {
+			c.logError(key, filters, err)
+			return key.DefaultFloat()
+		}
+		c.logValue(key, filters, val, key.DefaultValue(), float64CompareEquals)
+		return val
+	}
+}
+
 // GetFloat64PropertyFilteredByShardID gets property with shardID filter and asserts that it's a float64
 func (c *Collection) GetFloat64PropertyFilteredByShardID(key FloatKey) FloatPropertyFnWithShardIDFilter {
 	return func(shardID int) float64 {
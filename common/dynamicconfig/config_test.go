@@ -100,6 +100,15 @@ func (s *configSuite) TestGetFloat64Property() {
 	s.Equal(0.01, value())
 }
 
+func (s *configSuite) TestGetFloat64PropertyFilteredByDomain() {
+	key := TestGetFloat64PropertyFilteredByDomainKey
+	domain := "testDomain"
+	value := s.cln.GetFloat64PropertyFilteredByDomain(key)
+	s.Equal(key.DefaultFloat(), value(domain))
+	s.client.SetValue(key, 0.01)
+	s.Equal(0.01, value(domain))
+}
+
 func (s *configSuite) TestGetBoolProperty() {
 	key := TestGetBoolPropertyKey
 	value := s.cln.GetBoolProperty(key)
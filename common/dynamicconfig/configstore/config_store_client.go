@@ -36,10 +36,14 @@ import (
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/persistence/nosql"
+	"github.com/uber/cadence/common/persistence/sql"
 	"github.com/uber/cadence/common/types"
 )
 
 var _ dc.Client = (*configStoreClient)(nil)
+var _ dc.AuditedClient = (*configStoreClient)(nil)
+var _ dc.ConditionalClient = (*configStoreClient)(nil)
+var _ dc.MatchedValueClient = (*configStoreClient)(nil)
 
 const (
 	configStoreMinPollInterval = time.Second * 2
@@ -53,6 +57,8 @@ var defaultConfigValues = &csc.ClientConfig{
 }
 
 type configStoreClient struct {
+	dc.UpdateBroadcaster
+
 	values             atomic.Value
 	lastUpdatedTime    time.Time
 	config             *csc.ClientConfig
@@ -78,16 +84,12 @@ func NewConfigStoreClient(clientCfg *csc.ClientConfig, persistenceCfg *config.Pe
 		return nil, errors.New("default persistence config missing")
 	}
 
-	if store.NoSQL == nil {
-		return nil, errors.New("NoSQL struct is nil")
-	}
-
 	if err := validateClientConfig(clientCfg); err != nil {
 		logger.Warn("invalid ClientConfig values, using default values")
 		clientCfg = defaultConfigValues
 	}
 
-	client, err := newConfigStoreClient(clientCfg, store.NoSQL, logger, doneCh)
+	client, err := newConfigStoreClient(clientCfg, &store, logger, doneCh)
 	if err != nil {
 		return nil, err
 	}
@@ -98,8 +100,8 @@ func NewConfigStoreClient(clientCfg *csc.ClientConfig, persistenceCfg *config.Pe
 	return client, nil
 }
 
-func newConfigStoreClient(clientCfg *csc.ClientConfig, persistenceCfg *config.NoSQL, logger log.Logger, doneCh chan struct{}) (*configStoreClient, error) {
-	store, err := nosql.NewNoSQLConfigStore(*persistenceCfg, logger, nil)
+func newConfigStoreClient(clientCfg *csc.ClientConfig, persistenceCfg *config.DataStore, logger log.Logger, doneCh chan struct{}) (*configStoreClient, error) {
+	store, err := newConfigStore(persistenceCfg, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -114,6 +116,21 @@ func newConfigStoreClient(clientCfg *csc.ClientConfig, persistenceCfg *config.No
 	return client, nil
 }
 
+func newConfigStore(persistenceCfg *config.DataStore, logger log.Logger) (persistence.ConfigStore, error) {
+	switch {
+	case persistenceCfg.NoSQL != nil:
+		return nosql.NewNoSQLConfigStore(*persistenceCfg.NoSQL, logger, nil)
+	case persistenceCfg.SQL != nil:
+		db, err := sql.NewSQLDB(persistenceCfg.SQL)
+		if err != nil {
+			return nil, err
+		}
+		return sql.NewSQLConfigStore(db, logger)
+	default:
+		return nil, errors.New("both NoSQL and SQL structs are nil")
+	}
+}
+
 func (csc *configStoreClient) startUpdate() error {
 	if err := csc.update(); err != nil {
 		return err
@@ -251,14 +268,24 @@ func (csc *configStoreClient) GetListValue(name dc.ListKey, filters map[dc.Filte
 }
 
 func (csc *configStoreClient) UpdateValue(name dc.Key, value interface{}) error {
+	return csc.UpdateValueWithIdentity(name, value, "")
+}
+
+// UpdateValueWithIdentity is UpdateValue which records the identity in the change history
+func (csc *configStoreClient) UpdateValueWithIdentity(name dc.Key, value interface{}, identity string) error {
 	dcValues, ok := value.([]*types.DynamicConfigValue)
 	if !ok && dcValues != nil {
 		return errors.New("invalid value")
 	}
-	return csc.updateValue(name, dcValues, csc.config.UpdateRetryAttempts)
+	return csc.updateValue(name, dcValues, identity, csc.config.UpdateRetryAttempts)
 }
 
 func (csc *configStoreClient) RestoreValue(name dc.Key, filters map[dc.Filter]interface{}) error {
+	return csc.RestoreValueWithIdentity(name, filters, "")
+}
+
+// RestoreValueWithIdentity is RestoreValue which records the identity in the change history
+func (csc *configStoreClient) RestoreValueWithIdentity(name dc.Key, filters map[dc.Filter]interface{}, identity string) error {
 	//if empty filter provided, update fallback value.
 	//if u want to remove entire entry, just do update value with empty
 	loaded := csc.values.Load()
@@ -291,7 +318,7 @@ func (csc *configStoreClient) RestoreValue(name dc.Key, filters map[dc.Filter]in
 		}
 	}
 
-	return csc.updateValue(name, newValues, csc.config.UpdateRetryAttempts)
+	return csc.updateValue(name, newValues, identity, csc.config.UpdateRetryAttempts)
 }
 
 func (csc *configStoreClient) ListValue(name dc.Key) ([]*types.DynamicConfigEntry, error) {
@@ -329,15 +356,59 @@ func (csc *configStoreClient) ListValue(name dc.Key) ([]*types.DynamicConfigEntr
 	return resList, nil
 }
 
-func (csc *configStoreClient) updateValue(name dc.Key, dcValues []*types.DynamicConfigValue, retryAttempts int) error {
+// ListChanges lists a page of the change history of dynamic config values, from the latest change to the oldest
+func (csc *configStoreClient) ListChanges(request *types.ListDynamicConfigChangesRequest) (*types.ListDynamicConfigChangesResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), csc.config.FetchTimeout)
+	defer cancel()
+
+	resp, err := csc.configStoreManager.ListDynamicConfigChanges(ctx, &persistence.ListDynamicConfigChangesRequest{
+		MaxVersion: request.GetMaxVersion(),
+		PageSize:   int(request.GetPageSize()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*types.DynamicConfigChange, 0, len(resp.Changes))
+	for _, change := range resp.Changes {
+		changes = append(changes, &types.DynamicConfigChange{
+			Version:   change.Version,
+			Timestamp: change.Timestamp.UnixNano(),
+			Identity:  change.Identity,
+			Name:      change.Name,
+			OldValues: change.OldValues,
+			NewValues: change.NewValues,
+		})
+	}
+	return &types.ListDynamicConfigChangesResponse{Changes: changes}, nil
+}
+
+// UpdateValueFromCurrent replaces the values of the key with the values update returns for its current values.
+// The update is conditional on the version of the whole config, so update is called again with the latest
+// values of the key when it was changed concurrently, instead of overwriting the change.
+func (csc *configStoreClient) UpdateValueFromCurrent(
+	name dc.Key,
+	update func(current []*types.DynamicConfigValue) ([]*types.DynamicConfigValue, error),
+	identity string,
+) error {
+	return csc.updateValueFromCurrent(name, update, identity, csc.config.UpdateRetryAttempts)
+}
+
+func (csc *configStoreClient) updateValue(name dc.Key, dcValues []*types.DynamicConfigValue, identity string, retryAttempts int) error {
 	//since values are not unique, no way to know if you are trying to update a specific value
 	//or if you want to add another of the same value with different filters.
 	//UpdateValue will replace everything associated with dc key.
-	for _, dcValue := range dcValues {
-		if err := validateKeyDataBlobPair(name, dcValue.Value); err != nil {
-			return err
-		}
-	}
+	return csc.updateValueFromCurrent(name, func([]*types.DynamicConfigValue) ([]*types.DynamicConfigValue, error) {
+		return dcValues, nil
+	}, identity, retryAttempts)
+}
+
+func (csc *configStoreClient) updateValueFromCurrent(
+	name dc.Key,
+	update func(current []*types.DynamicConfigValue) ([]*types.DynamicConfigValue, error),
+	identity string,
+	retryAttempts int,
+) error {
 	loaded := csc.values.Load()
 	var currentCached cacheEntry
 	if loaded == nil {
@@ -351,9 +422,33 @@ func (csc *configStoreClient) updateValue(name dc.Key, dcValues []*types.Dynamic
 	}
 
 	keyName := name.String()
-	var newEntries []*types.DynamicConfigEntry
-
 	existingEntry, entryExists := currentCached.dcEntries[keyName]
+	var currentValues []*types.DynamicConfigValue
+	if entryExists {
+		currentValues = copyDynamicConfigEntry(existingEntry).Values
+	}
+	dcValues, err := update(currentValues)
+	if err != nil {
+		return err
+	}
+	for _, dcValue := range dcValues {
+		if err := validateKeyDataBlobPair(name, dcValue.Value); err != nil {
+			return err
+		}
+	}
+	if err := validateValues(currentCached.dcEntries, name, dcValues); err != nil {
+		return &types.BadRequestError{Message: err.Error()}
+	}
+
+	var newEntries []*types.DynamicConfigEntry
+	change := &persistence.DynamicConfigChangeRecord{
+		Identity:  identity,
+		Name:      keyName,
+		NewValues: dcValues,
+	}
+	if entryExists {
+		change.OldValues = existingEntry.Values
+	}
 
 	if dcValues == nil || len(dcValues) == 0 {
 		newEntries = make([]*types.DynamicConfigEntry, 0, len(currentCached.dcEntries))
@@ -401,10 +496,11 @@ func (csc *configStoreClient) updateValue(name dc.Key, dcValues []*types.Dynamic
 	ctx, cancel := context.WithTimeout(context.Background(), csc.config.UpdateTimeout)
 	defer cancel()
 
-	err := csc.configStoreManager.UpdateDynamicConfig(
+	err = csc.configStoreManager.UpdateDynamicConfig(
 		ctx,
 		&persistence.UpdateDynamicConfigRequest{
 			Snapshot: newSnapshot,
+			Change:   change,
 		},
 	)
 
@@ -420,7 +516,7 @@ func (csc *configStoreClient) updateValue(name dc.Key, dcValues []*types.Dynamic
 				if err != nil {
 					return err
 				}
-				return csc.updateValue(name, dcValues, retryAttempts-1)
+				return csc.updateValueFromCurrent(name, update, identity, retryAttempts-1)
 			}
 
 			if retryAttempts == 0 {
@@ -432,6 +528,48 @@ func (csc *configStoreClient) updateValue(name dc.Key, dcValues []*types.Dynamic
 	}
 }
 
+// validateValues checks the new values of the key against its constraints and the invariants with other keys
+func validateValues(dcEntries map[string]*types.DynamicConfigEntry, name dc.Key, dcValues []*types.DynamicConfigValue) error {
+	candidates := make(map[string]*types.DynamicConfigEntry, len(dcEntries)+1)
+	for keyName, entry := range dcEntries {
+		candidates[keyName] = entry
+	}
+	candidates[name.String()] = &types.DynamicConfigEntry{
+		Name:   name.String(),
+		Values: dcValues,
+	}
+
+	for _, dcValue := range dcValues {
+		value, err := convertFromDataBlob(dcValue.Value)
+		if err != nil {
+			return err
+		}
+		if err := dc.ValidateValue(name, value); err != nil {
+			return err
+		}
+
+		filters := make(map[dc.Filter]interface{}, len(dcValue.Filters))
+		for _, filter := range dcValue.Filters {
+			filterValue, err := convertFromDataBlob(filter.Value)
+			if err != nil {
+				return err
+			}
+			filters[dc.ParseFilter(filter.Name)] = filterValue
+		}
+		if err := dc.ValidateSchedule(filters); err != nil {
+			return fmt.Errorf("invalid schedule of %v: %v", name.String(), err)
+		}
+		err = dc.ValidateInvariants(name, func(key dc.Key) (interface{}, error) {
+			value, _ := resolveValue(candidates, key, filters, key.DefaultValue())
+			return value, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func copyDynamicConfigEntry(entry *types.DynamicConfigEntry) *types.DynamicConfigEntry {
 	if entry == nil {
 		return nil
@@ -527,68 +665,167 @@ func (csc *configStoreClient) storeValues(snapshot *persistence.DynamicConfigSna
 	} else {
 		dcEntryMap = make(map[string]*types.DynamicConfigEntry)
 		for _, entry := range snapshot.Values.Entries {
-			dcEntryMap[entry.Name] = entry
+			dcEntryMap[entry.Name] = csc.dropInvalidValues(entry)
 		}
 	}
 
+	previous, loaded := csc.values.Load().(cacheEntry)
 	csc.values.Store(cacheEntry{
 		cacheVersion:  snapshot.Version,
 		schemaVersion: snapshot.Values.SchemaVersion,
 		dcEntries:     dcEntryMap,
 	})
 	csc.logger.Debug("Updated dynamic config")
+	if !loaded || previous.cacheVersion != snapshot.Version {
+		csc.NotifyUpdate()
+		csc.NotifyUpdateAt(scheduleChangeTimes(dcEntryMap))
+	}
 	return nil
 }
 
+func scheduleChangeTimes(dcEntries map[string]*types.DynamicConfigEntry) []time.Time {
+	var changeTimes []time.Time
+	for _, entry := range dcEntries {
+		for _, dcValue := range entry.Values {
+			filters := make(map[dc.Filter]interface{}, len(dcValue.Filters))
+			for _, filter := range dcValue.Filters {
+				filters[dc.ParseFilter(filter.Name)], _ = convertFromDataBlob(filter.Value)
+			}
+			changeTimes = append(changeTimes, dc.ScheduleChangeTimes(filters)...)
+		}
+	}
+	return changeTimes
+}
+
+// dropInvalidValues removes the values violating the constraints of the key, so that the default value is
+// used instead. Such values can only be written before the constraint was declared, updates are validated.
+func (csc *configStoreClient) dropInvalidValues(entry *types.DynamicConfigEntry) *types.DynamicConfigEntry {
+	key, err := dc.GetKeyFromKeyName(entry.Name)
+	if err != nil {
+		return entry
+	}
+	validValues := make([]*types.DynamicConfigValue, 0, len(entry.Values))
+	for _, dcValue := range entry.Values {
+		// values of the wrong type are kept, they are rejected when read
+		if validateKeyDataBlobPair(key, dcValue.Value) == nil {
+			value, _ := convertFromDataBlob(dcValue.Value)
+			if err := dc.ValidateValue(key, value); err != nil {
+				csc.logger.Error("Ignoring invalid dynamic config value", tag.Key(entry.Name), tag.Error(err))
+				continue
+			}
+		}
+		validValues = append(validValues, dcValue)
+	}
+	if len(validValues) == len(entry.Values) {
+		return entry
+	}
+	return &types.DynamicConfigEntry{
+		Name:   entry.Name,
+		Values: validValues,
+	}
+}
+
 func (csc *configStoreClient) getValueWithFilters(key dc.Key, filters map[dc.Filter]interface{}, defaultValue interface{}) (interface{}, error) {
-	keyName := key.String()
 	loaded := csc.values.Load()
 	if loaded == nil {
 		return defaultValue, nil
 	}
-	cached := loaded.(cacheEntry)
-	found := false
+	return resolveValue(loaded.(cacheEntry).dcEntries, key, filters, defaultValue)
+}
+
+// GetValueWithMatchedFilters returns the value for the filters and the filters of the value in effect
+func (csc *configStoreClient) GetValueWithMatchedFilters(name dc.Key, filters map[dc.Filter]interface{}) (interface{}, map[dc.Filter]interface{}, error) {
+	loaded := csc.values.Load()
+	if loaded == nil {
+		return name.DefaultValue(), nil, dc.NotFoundError
+	}
+	dcValue := resolveDynamicConfigValue(loaded.(cacheEntry).dcEntries, name, filters)
+	if dcValue == nil {
+		return name.DefaultValue(), nil, dc.NotFoundError
+	}
+	value, err := convertFromDataBlob(dcValue.Value)
+	if err != nil {
+		return name.DefaultValue(), nil, err
+	}
+	matched := make(map[dc.Filter]interface{}, len(dcValue.Filters))
+	for _, filter := range dcValue.Filters {
+		matched[dc.ParseFilter(filter.Name)], _ = convertFromDataBlob(filter.Value)
+	}
+	return value, matched, nil
+}
 
-	if entry, ok := cached.dcEntries[keyName]; ok && entry != nil {
+func resolveValue(
+	dcEntries map[string]*types.DynamicConfigEntry,
+	key dc.Key,
+	filters map[dc.Filter]interface{},
+	defaultValue interface{},
+) (interface{}, error) {
+	dcValue := resolveDynamicConfigValue(dcEntries, key, filters)
+	if dcValue == nil {
+		return defaultValue, dc.NotFoundError
+	}
+	return convertFromDataBlob(dcValue.Value)
+}
+
+// resolveDynamicConfigValue returns the first value matching the filters, or the last valid value
+// without any filters if none matches
+func resolveDynamicConfigValue(
+	dcEntries map[string]*types.DynamicConfigEntry,
+	key dc.Key,
+	filters map[dc.Filter]interface{},
+) *types.DynamicConfigValue {
+	var fallback *types.DynamicConfigValue
+	if entry, ok := dcEntries[key.String()]; ok && entry != nil {
 		for _, dcValue := range entry.Values {
 			if len(dcValue.Filters) == 0 {
-				parsedVal, err := convertFromDataBlob(dcValue.Value)
-
-				if err == nil {
-					defaultValue = parsedVal
-					found = true
+				if _, err := convertFromDataBlob(dcValue.Value); err == nil {
+					fallback = dcValue
 				}
 				continue
 			}
 
 			if matchFilters(dcValue, filters) {
-				return convertFromDataBlob(dcValue.Value)
+				return dcValue
 			}
 		}
 	}
-	if found {
-		return defaultValue, nil
-	}
-	return defaultValue, dc.NotFoundError
+	return fallback
 }
 
+// matchFilters returns true if the filters of the value match the filters or any subsets
+// and the current time is within the schedule of the value
 func matchFilters(dcValue *types.DynamicConfigValue, filters map[dc.Filter]interface{}) bool {
-	if len(dcValue.Filters) > len(filters) {
-		return false
-	}
-
+	var schedule map[dc.Filter]interface{}
 	for _, valueFilter := range dcValue.Filters {
 		filterKey := dc.ParseFilter(valueFilter.Name)
+		if dc.IsScheduleFilter(filterKey) {
+			if schedule == nil {
+				schedule = make(map[dc.Filter]interface{}, 2)
+			}
+			schedule[filterKey], _ = convertFromDataBlob(valueFilter.Value)
+			continue
+		}
 		if filters[filterKey] == nil {
 			return false
 		}
 
 		requestValue, err := convertFromDataBlob(valueFilter.Value)
-		if err != nil || filters[filterKey] != requestValue {
+		if err != nil || !filterValueEquals(filters[filterKey], requestValue) {
 			return false
 		}
 	}
-	return true
+	return schedule == nil || dc.MatchSchedule(schedule, time.Now())
+}
+
+// filterValueEquals compares a filter value provided by the caller with the one stored in the config store.
+// Stored values are json decoded, so all numbers (ex: task type) are float64 while callers use ints.
+func filterValueEquals(filterValue interface{}, storedValue interface{}) bool {
+	if storedFloat, ok := storedValue.(float64); ok {
+		if filterInt, ok := filterValue.(int); ok {
+			return float64(filterInt) == storedFloat
+		}
+	}
+	return filterValue == storedValue
 }
 
 func validateClientConfig(config *csc.ClientConfig) error {
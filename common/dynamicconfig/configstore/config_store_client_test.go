@@ -306,8 +306,10 @@ func (s *configStoreClientSuite) SetupTest() {
 			FetchTimeout:        time.Second * 1,
 			UpdateTimeout:       time.Second * 1,
 		},
-		&config.NoSQL{
-			PluginName: "cassandra",
+		&config.DataStore{
+			NoSQL: &config.NoSQL{
+				PluginName: "cassandra",
+			},
 		}, log.NewNoop(), s.doneCh)
 	s.Require().NoError(err)
 
@@ -672,6 +674,32 @@ func (s *configStoreClientSuite) TestMatchFilters() {
 			},
 			matched: false,
 		},
+		{
+			v: &types.DynamicConfigValue{
+				Value: nil,
+				Filters: []*types.DynamicConfigFilter{
+					{
+						Name: "taskListName",
+						Value: &types.DataBlob{
+							EncodingType: types.EncodingTypeJSON.Ptr(),
+							Data:         jsonMarshalHelper("sample-task-list"),
+						},
+					},
+					{
+						Name: "taskType",
+						Value: &types.DataBlob{
+							EncodingType: types.EncodingTypeJSON.Ptr(),
+							Data:         jsonMarshalHelper(1),
+						},
+					},
+				},
+			},
+			filters: map[dc.Filter]interface{}{
+				dc.TaskListName: "sample-task-list",
+				dc.TaskType:     1,
+			},
+			matched: true,
+		},
 	}
 
 	for index, tc := range testCases {
@@ -769,6 +797,91 @@ func (s *configStoreClientSuite) TestUpdateValue_SuccessNewKey() {
 	s.NoError(err)
 }
 
+func (s *configStoreClientSuite) TestUpdateValueWithIdentity_RecordsChange() {
+	defaultTestSetup(s)
+
+	oldValues := snapshot1.Values.Entries[0].Values
+	values := []*types.DynamicConfigValue{
+		{
+			Value: &types.DataBlob{
+				EncodingType: types.EncodingTypeJSON.Ptr(),
+				Data:         jsonMarshalHelper(true),
+			},
+			Filters: nil,
+		},
+	}
+
+	s.mockManager.EXPECT().
+		UpdateDynamicConfig(gomock.Any(), EqSnapshotVersion(2)).
+		DoAndReturn(func(_ context.Context, request *p.UpdateDynamicConfigRequest) error {
+			s.NotNil(request.Change)
+			s.Equal("tester", request.Change.Identity)
+			s.Equal(dc.TestGetBoolPropertyKey.String(), request.Change.Name)
+			s.Equal(oldValues, request.Change.OldValues)
+			s.Equal(values, request.Change.NewValues)
+			return nil
+		}).Times(1)
+
+	err := s.client.UpdateValueWithIdentity(dc.TestGetBoolPropertyKey, values, "tester")
+	s.NoError(err)
+}
+
+func (s *configStoreClientSuite) TestUpdateValue_ConstraintViolation() {
+	defaultTestSetup(s)
+
+	values := []*types.DynamicConfigValue{
+		{
+			Value: &types.DataBlob{
+				EncodingType: types.EncodingTypeJSON.Ptr(),
+				Data:         jsonMarshalHelper(0),
+			},
+		},
+	}
+	err := s.client.UpdateValue(dc.MatchingNumTasklistReadPartitions, values)
+	s.IsType(&types.BadRequestError{}, err)
+
+	// write partitions larger than the default read partitions for a domain
+	values = []*types.DynamicConfigValue{
+		{
+			Value: &types.DataBlob{
+				EncodingType: types.EncodingTypeJSON.Ptr(),
+				Data:         jsonMarshalHelper(4),
+			},
+			Filters: []*types.DynamicConfigFilter{
+				{
+					Name: dc.DomainName.String(),
+					Value: &types.DataBlob{
+						EncodingType: types.EncodingTypeJSON.Ptr(),
+						Data:         jsonMarshalHelper("samples-domain"),
+					},
+				},
+			},
+		},
+	}
+	err = s.client.UpdateValue(dc.MatchingNumTasklistWritePartitions, values)
+	s.IsType(&types.BadRequestError{}, err)
+	s.Contains(err.Error(), "matching.numTasklistWritePartitions must not be larger")
+}
+
+func (s *configStoreClientSuite) TestListChanges() {
+	timestamp := time.Now()
+	s.mockManager.EXPECT().
+		ListDynamicConfigChanges(gomock.Any(), &p.ListDynamicConfigChangesRequest{MaxVersion: 5, PageSize: 2}).
+		Return(&p.ListDynamicConfigChangesResponse{
+			Changes: []*p.DynamicConfigChangeRecord{
+				{Version: 5, Timestamp: timestamp, Identity: "tester", Name: dc.TestGetBoolPropertyKey.String()},
+			},
+		}, nil).Times(1)
+
+	resp, err := s.client.ListChanges(&types.ListDynamicConfigChangesRequest{MaxVersion: 5, PageSize: 2})
+	s.NoError(err)
+	s.Equal(&types.ListDynamicConfigChangesResponse{
+		Changes: []*types.DynamicConfigChange{
+			{Version: 5, Timestamp: timestamp.UnixNano(), Identity: "tester", Name: dc.TestGetBoolPropertyKey.String()},
+		},
+	}, resp)
+}
+
 func (s *configStoreClientSuite) TestUpdateValue_RetrySuccess() {
 	s.mockManager.EXPECT().
 		UpdateDynamicConfig(gomock.Any(), EqSnapshotVersion(2)).
@@ -802,6 +915,67 @@ func (s *configStoreClientSuite) TestUpdateValue_RetryFailure() {
 	s.Error(err)
 }
 
+func (s *configStoreClientSuite) TestUpdateValueFromCurrent_RetryWithLatestValues() {
+	intValue := func(value int) *types.DynamicConfigValue {
+		return &types.DynamicConfigValue{
+			Value: &types.DataBlob{
+				EncodingType: types.EncodingTypeJSON.Ptr(),
+				Data:         jsonMarshalHelper(value),
+			},
+			Filters: []*types.DynamicConfigFilter{
+				{
+					Name: dc.DomainName.String(),
+					Value: &types.DataBlob{
+						EncodingType: types.EncodingTypeJSON.Ptr(),
+						Data:         jsonMarshalHelper(fmt.Sprintf("domain-%v", value)),
+					},
+				},
+			},
+		}
+	}
+	snapshot := func(version int64, values ...*types.DynamicConfigValue) *p.FetchDynamicConfigResponse {
+		return &p.FetchDynamicConfigResponse{
+			Snapshot: &p.DynamicConfigSnapshot{
+				Version: version,
+				Values: &types.DynamicConfigBlob{
+					SchemaVersion: 1,
+					Entries:       []*types.DynamicConfigEntry{{Name: dc.TestGetIntPropertyKey.String(), Values: values}},
+				},
+			},
+		}
+	}
+
+	// the value of domain-2 is written concurrently after the values are loaded
+	gomock.InOrder(
+		s.mockManager.EXPECT().FetchDynamicConfig(gomock.Any()).Return(snapshot(1, intValue(1)), nil),
+		s.mockManager.EXPECT().UpdateDynamicConfig(gomock.Any(), EqSnapshotVersion(2)).Return(&p.ConditionFailedError{}),
+		s.mockManager.EXPECT().FetchDynamicConfig(gomock.Any()).Return(snapshot(2, intValue(1), intValue(2)), nil),
+		s.mockManager.EXPECT().UpdateDynamicConfig(gomock.Any(), EqSnapshotVersion(3)).DoAndReturn(
+			func(_ context.Context, request *p.UpdateDynamicConfigRequest) error {
+				s.Equal([]*types.DynamicConfigValue{intValue(1), intValue(2), intValue(3)}, request.Snapshot.Values.Entries[0].Values)
+				s.Equal("tester", request.Change.Identity)
+				return nil
+			}),
+	)
+	s.NoError(s.client.update())
+
+	err := s.client.UpdateValueFromCurrent(dc.TestGetIntPropertyKey, func(current []*types.DynamicConfigValue) ([]*types.DynamicConfigValue, error) {
+		return append(current, intValue(3)), nil
+	}, "tester")
+	s.NoError(err)
+}
+
+func (s *configStoreClientSuite) TestUpdateValueFromCurrent_UpdateError() {
+	defaultTestSetup(s)
+
+	updateErr := errors.New("update error")
+	err := s.client.UpdateValueFromCurrent(dc.TestGetBoolPropertyKey, func(current []*types.DynamicConfigValue) ([]*types.DynamicConfigValue, error) {
+		s.Equal(snapshot1.Values.Entries[0].Values, current)
+		return nil, updateErr
+	}, "tester")
+	s.Equal(updateErr, err)
+}
+
 func (s *configStoreClientSuite) TestUpdateValue_Timeout() {
 	defaultTestSetup(s)
 	s.mockManager.EXPECT().
@@ -916,6 +1090,31 @@ func (s *configStoreClientSuite) TestListValues_EmptyCache() {
 	s.Nil(val)
 }
 
+func TestMatchFilters_Schedule(t *testing.T) {
+	blob := func(v interface{}) *types.DataBlob {
+		return &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: jsonMarshalHelper(v)}
+	}
+	valueWithSchedule := func(from, until time.Time) *types.DynamicConfigValue {
+		return &types.DynamicConfigValue{
+			Value: blob(1000),
+			Filters: []*types.DynamicConfigFilter{
+				{Name: dc.DomainName.String(), Value: blob("samples-domain")},
+				{Name: dc.ActiveFrom.String(), Value: blob(from.Format(time.RFC3339))},
+				{Name: dc.ActiveUntil.String(), Value: blob(until.Format(time.RFC3339))},
+			},
+		}
+	}
+	filters := map[dc.Filter]interface{}{dc.DomainName: "samples-domain"}
+	now := time.Now()
+
+	require.True(t, matchFilters(valueWithSchedule(now.Add(-time.Hour), now.Add(time.Hour)), filters))
+	require.False(t, matchFilters(valueWithSchedule(now.Add(time.Hour), now.Add(2*time.Hour)), filters))
+	require.False(t, matchFilters(valueWithSchedule(now.Add(-time.Hour), now.Add(time.Hour)), map[dc.Filter]interface{}{dc.DomainName: "other-domain"}))
+
+	err := validateValues(nil, dc.FrontendMaxDomainUserRPSPerInstance, []*types.DynamicConfigValue{valueWithSchedule(now, now.Add(-time.Hour))})
+	require.Error(t, err)
+}
+
 func jsonMarshalHelper(v interface{}) []byte {
 	data, _ := json.Marshal(v)
 	return data
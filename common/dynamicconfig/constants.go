@@ -97,7 +97,7 @@ func ListAllProductionKeys() []Key {
 	for i := TestGetBoolPropertyFilteredByTaskListInfoKey + 1; i < LastBoolKey; i++ {
 		result = append(result, i)
 	}
-	for i := TestGetFloat64PropertyKey + 1; i < LastFloatKey; i++ {
+	for i := TestGetFloat64PropertyFilteredByDomainKey + 1; i < LastFloatKey; i++ {
 		result = append(result, i)
 	}
 	for i := TestGetStringPropertyKey + 1; i < LastStringKey; i++ {
@@ -345,12 +345,26 @@ const (
 	// KeyName: limit.pendingActivityCount.error
 	// Value type: Int
 	// Default value: 1024
+	// Allowed filters: DomainName
 	PendingActivitiesCountLimitError
 	// PendingActivitiesCountLimitWarn is the limit of how many activities a workflow can have before a warning is logged
 	// KeyName: limit.pendingActivityCount.warn
 	// Value type: Int
 	// Default value: 512
+	// Allowed filters: DomainName
 	PendingActivitiesCountLimitWarn
+	// PendingChildExecutionsCountLimitError is the limit of how many pending child workflows a workflow can have at a point in time
+	// KeyName: limit.pendingChildExecutionCount.error
+	// Value type: Int
+	// Default value: 1024
+	// Allowed filters: DomainName
+	PendingChildExecutionsCountLimitError
+	// PendingChildExecutionsCountLimitWarn is the limit of how many pending child workflows a workflow can have before a warning is emitted
+	// KeyName: limit.pendingChildExecutionCount.warn
+	// Value type: Int
+	// Default value: 512
+	// Allowed filters: DomainName
+	PendingChildExecutionsCountLimitWarn
 	// DomainNameMaxLength is the length limit for domain name
 	// KeyName: limit.domainNameLength
 	// Value type: Int
@@ -524,6 +538,24 @@ const (
 	// Default value: UnlimitedRPS
 	// Allowed filters: DomainName
 	FrontendGlobalDomainVisibilityRPS
+	// FrontendMaxCallerUserRPSPerInstance is workflow rate limit per second of each authenticated caller of a domain, 0 means no limit
+	// KeyName: frontend.callerrps
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: DomainName
+	FrontendMaxCallerUserRPSPerInstance
+	// FrontendMaxCallerWorkerRPSPerInstance is background-processing workflow rate limit per second of each authenticated caller of a domain, 0 means no limit
+	// KeyName: frontend.callerWorkerrps
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: DomainName
+	FrontendMaxCallerWorkerRPSPerInstance
+	// FrontendMaxCallerVisibilityRPSPerInstance is the List*WorkflowExecutions request rate limit per second of each authenticated caller of a domain, 0 means no limit
+	// KeyName: frontend.callerVisibilityrps
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: DomainName
+	FrontendMaxCallerVisibilityRPSPerInstance
 	// FrontendDecisionResultCountLimit is max number of decisions per RespondDecisionTaskCompleted request
 	// KeyName: frontend.decisionResultCountLimit
 	// Value type: Int
@@ -623,6 +655,12 @@ const (
 	// Default value: 1000
 	// Allowed filters: DomainName,TasklistName,TasklistType
 	MatchingGetTasksBatchSize
+	// MatchingMaxGetTasksBatchSize is the maximum batch size to fetch from the task buffer when the adaptive task read is enabled
+	// KeyName: matching.maxGetTasksBatchSize
+	// Value type: Int
+	// Default value: 5000
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingMaxGetTasksBatchSize
 	// MatchingOutstandingTaskAppendsThreshold is the threshold for outstanding task appends
 	// KeyName: matching.outstandingTaskAppendsThreshold
 	// Value type: Int
@@ -641,6 +679,39 @@ const (
 	// Default value: 100
 	// Allowed filters: DomainName,TasklistName,TasklistType
 	MatchingMaxTaskDeleteBatchSize
+	// MatchingCompletedTaskDeleteBatchSize is the number of tasks completed above a stuck ack level which are buffered
+	// before they are deleted one by one, they are deleted on the next flush interval otherwise
+	// KeyName: matching.completedTaskDeleteBatchSize
+	// Value type: Int
+	// Default value: 100
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingCompletedTaskDeleteBatchSize
+	// MatchingPartitionScaleUpRPS is the rate of tasks added to or polled from the root partition of a task list
+	// above which a partition is added when the partition auto scaling is enabled
+	// KeyName: matching.partitionScaleUpRPS
+	// Value type: Int
+	// Default value: 1000
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingPartitionScaleUpRPS
+	// MatchingPartitionScaleDownRPS is the rate of tasks added to or polled from the root partition of a task list,
+	// once projected on one partition less, below which a partition is removed when the partition auto scaling is enabled
+	// KeyName: matching.partitionScaleDownRPS
+	// Value type: Int
+	// Default value: 250
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingPartitionScaleDownRPS
+	// MatchingMinTaskListPartitions is the min number of partitions the partition auto scaling scales a task list down to
+	// KeyName: matching.minTaskListPartitions
+	// Value type: Int
+	// Default value: 1
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingMinTaskListPartitions
+	// MatchingMaxTaskListPartitions is the max number of partitions the partition auto scaling scales a task list up to
+	// KeyName: matching.maxTaskListPartitions
+	// Value type: Int
+	// Default value: 8
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingMaxTaskListPartitions
 	// MatchingThrottledLogRPS is the rate limit on number of log messages emitted per second for throttled logger
 	// KeyName: matching.throttledLogRPS
 	// Value type: Int
@@ -683,6 +754,26 @@ const (
 	// Default value: 20
 	// Allowed filters: DomainName,TasklistName,TasklistType
 	MatchingForwarderMaxChildrenPerNode
+	// MatchingTaskDispatchRPS is the max rate at which tasks are dispatched from a task list, overriding the rate provided by pollers
+	// KeyName: matching.taskDispatchRPS
+	// Value type: Int
+	// Default value: 0 (no override, the rate provided by pollers is used)
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingTaskDispatchRPS
+	// MatchingTaskListMaxConcurrentActivities is the max number of activities of an activity task list which are running at once,
+	// an activity is running from being dispatched to a worker until the worker responds with its result or its start to close
+	// timeout expires. The limit is divided across the task list partitions
+	// KeyName: matching.taskListMaxConcurrentActivities
+	// Value type: Int
+	// Default value: 0 (no limit)
+	// Allowed filters: DomainName,TasklistName
+	MatchingTaskListMaxConcurrentActivities
+	// MatchingPollerHistoryMaxSize is the max number of pollers whose info is kept by a task list, it is read when the task list is loaded
+	// KeyName: matching.pollerHistoryMaxSize
+	// Value type: Int
+	// Default value: 5000
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingPollerHistoryMaxSize
 
 	// key for history
 
@@ -764,6 +855,12 @@ const (
 	// Default value: 1
 	// Allowed filters: N/A
 	AcquireShardConcurrency
+	// ShardHandoffMaxConcurrency is the max number of shards a history host hands off concurrently when shutting down
+	// KeyName: history.shardHandoffMaxConcurrency
+	// Value type: Int
+	// Default value: 10
+	// Allowed filters: N/A
+	ShardHandoffMaxConcurrency
 	// TaskProcessRPS is the task processing rate per second for each domain
 	// KeyName: history.taskProcessRPS
 	// Value type: Int
@@ -1021,6 +1118,18 @@ const (
 	// Default value: 10000
 	// Allowed filters: DomainName
 	MaximumSignalsPerExecution
+	// WorkflowIDSignalRPS is the max number of signals per second a single workflow ID can receive
+	// KeyName: history.workflowIDSignalRPS
+	// Value type: Int
+	// Default value: 0 (no limit)
+	// Allowed filters: DomainName
+	WorkflowIDSignalRPS
+	// WorkflowIDEventsRPS is the max number of history events per second a single workflow ID can generate from its decisions
+	// KeyName: history.workflowIDEventsRPS
+	// Value type: Int
+	// Default value: 0 (no limit)
+	// Allowed filters: DomainName
+	WorkflowIDEventsRPS
 	// NumArchiveSystemWorkflows is key for number of archive system workflows running in total
 	// KeyName: history.numArchiveSystemWorkflows
 	// Value type: Int
@@ -1124,6 +1233,12 @@ const (
 	// Default value: 0
 	// Allowed filters: DomainName
 	MaxActivityCountDispatchByDomain
+	// MaxActivityCountLocalDispatchByDomain max # of activity tasks requested for local dispatch that are returned to the worker in the RespondDecisionTaskCompleted response. Activities beyond it are scheduled through matching
+	// KeyName: history.maxActivityCountLocalDispatchByDomain
+	// Value type: Int
+	// Default value: 1
+	// Allowed filters: DomainName
+	MaxActivityCountLocalDispatchByDomain
 
 	// key for history replication
 
@@ -1341,6 +1456,61 @@ const (
 	// Default value: 1 (no jittering)
 	WorkflowDeletionJitterRange
 
+	// FeatureFlagRolloutPercentage is the percentage of domains or workflows a feature flag is enabled for
+	// KeyName: system.featureFlagRolloutPercentage
+	// Value type: Int
+	// Default value: 0 (the feature is disabled)
+	// Allowed filters: FeatureFlagName,DomainName
+	FeatureFlagRolloutPercentage
+
+	// WatchdogWindowSize is the number of samples the resource leak watchdog looks at to detect monotonic growth
+	// KeyName: system.watchdogWindowSize
+	// Value type: Int
+	// Default value: 30
+	// Allowed filters: N/A
+	WatchdogWindowSize
+	// LoadSheddingMemoryThreshold is the heap size in bytes above which a host sheds load, 0 disables the memory monitor
+	// KeyName: system.loadSheddingMemoryThreshold
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: N/A
+	LoadSheddingMemoryThreshold
+	// LoadSheddingGoroutineThreshold is the number of goroutines above which a host sheds load, 0 disables the goroutine monitor
+	// KeyName: system.loadSheddingGoroutineThreshold
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: N/A
+	LoadSheddingGoroutineThreshold
+	// HistoryLoadSheddingTaskRPS is the rate the queue tasks of a history host are processed at once its load shedding
+	// throttles the task processing
+	// KeyName: history.loadSheddingTaskRPS
+	// Value type: Int
+	// Default value: 100
+	// Allowed filters: N/A
+	HistoryLoadSheddingTaskRPS
+	// GRPCConnectionsPerPeer is the number of gRPC connections a host keeps to every history and matching peer it calls,
+	// the calls to a peer are spread over the connections which are available
+	// KeyName: system.grpcConnectionsPerPeer
+	// Value type: Int
+	// Default value: 1
+	// Allowed filters: N/A
+	GRPCConnectionsPerPeer
+	// GRPCPeerSubsetSize is the number of peers of a remote cluster a host sends its cross cluster calls to, the subset
+	// is re-picked on every DNS refresh to replace the peers which are unavailable, 0 sends the calls to all the peers
+	// KeyName: system.grpcPeerSubsetSize
+	// Value type: Int
+	// Default value: 0
+	// Allowed filters: N/A
+	GRPCPeerSubsetSize
+
+	// MetricsTagValuesLimit is the max number of distinct task list and workflow type tag values reported per metrics scope,
+	// the values beyond the limit are reported as _other_
+	// KeyName: system.metricsTagValuesLimit
+	// Value type: Int
+	// Default value: 10000
+	// Allowed filters: N/A
+	MetricsTagValuesLimit
+
 	// LastIntKey must be the last one in this const group
 	LastIntKey
 )
@@ -1471,12 +1641,82 @@ const (
 	// Default value: true
 	// Allowed filters: DomainName,TasklistName,TasklistType
 	MatchingEnableSyncMatch
+	// MatchingEnableAdaptiveTaskRead is to enable growing the batch size to fetch from the task buffer when the backlog
+	// is deep and pollers are waiting for tasks, and backing off exponentially on consecutive empty reads
+	// KeyName: matching.enableAdaptiveTaskRead
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingEnableAdaptiveTaskRead
+	// MatchingEnablePartitionAutoScaling is to enable adjusting the number of read and write partitions of a task list
+	// from the load of its root partition, the partition counts are written to the dynamic config store
+	// KeyName: matching.enablePartitionAutoScaling
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingEnablePartitionAutoScaling
+	// MatchingTaskListDrainMode is to reject the new tasks of a task list while its pollers consume the backlog,
+	// the task list is drained once all its partitions have no task left
+	// KeyName: matching.taskListDrainMode
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingTaskListDrainMode
+	// MatchingEnableStandbyTaskBuffering is to keep the tasks of a standby domain in the task list backlog instead of
+	// loading them for dispatch, the backlog is released when the domain fails over to the current cluster
+	// KeyName: matching.enableStandbyTaskBuffering
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName
+	MatchingEnableStandbyTaskBuffering
+	// MatchingEnableExpiredTaskDeadLetter is to write the tasks that expired in the task list backlog to the dead letter
+	// queue for post-mortem analysis instead of dropping them
+	// KeyName: matching.enableExpiredTaskDeadLetter
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName
+	MatchingEnableExpiredTaskDeadLetter
+	// MatchingEnableWorkflowFairDispatch is to dispatch the backlog of a task list in round robin across the workflows
+	// of its tasks, so that a workflow with many tasks cannot hold back the other workflows
+	// KeyName: matching.enableWorkflowFairDispatch
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingEnableWorkflowFairDispatch
+	// MatchingEnableTaskListHandoff is whether the matching hosts hand their task lists off to the new owners when
+	// shutting down, the task lists with pollers or a backlog are loaded by the new owners right away
+	// KeyName: matching.enableTaskListHandoff
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	MatchingEnableTaskListHandoff
+	// MatchingEnableQueryPollerReservation is to hold the dispatch of the task list backlog while a query task waits
+	// for a poller, so that queries are matched with the next poller even when the task list has a large backlog
+	// KeyName: matching.enableQueryPollerReservation
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingEnableQueryPollerReservation
+	// MatchingEnableEphemeralTaskList is to never persist the tasks of the normal task lists, their tasks are only sync
+	// matched and fail when no poller picks them up. It is read once when the task list is loaded
+	// KeyName: matching.enableEphemeralTaskList
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingEnableEphemeralTaskList
 	// MatchingEnableTaskInfoLogByDomainID is enables info level logs for decision/activity task based on the request domainID
 	// KeyName: matching.enableTaskInfoLogByDomainID
 	// Value type: Bool
 	// Default value: false
 	// Allowed filters: DomainID
 	MatchingEnableTaskInfoLogByDomainID
+	// MatchingEnableZoneAwareRouting is to send the tasks and polls of a task list to the partitions owned by the matching
+	// hosts in the zone of the caller, when there are some, so that they don't cross zones
+	// KeyName: matching.enableZoneAwareRouting
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingEnableZoneAwareRouting
 
 	// key for history
 
@@ -1510,6 +1750,12 @@ const (
 	// Default value: false
 	// Allowed filters: DomainID
 	QueueProcessorEnableStuckTaskSplitByDomainID
+	// QueueProcessorEnableLowPrioritySplitQueueByDomainID is indicates whether tasks of a domain loaded by processing queues split from the default level should be processed at low priority
+	// KeyName: history.queueProcessorEnableLowPrioritySplitQueueByDomainID
+	// Value type: Bool
+	// Default value: true
+	// Allowed filters: DomainID
+	QueueProcessorEnableLowPrioritySplitQueueByDomainID
 	// QueueProcessorEnablePersistQueueStates is indicates whether processing queue states should be persisted
 	// KeyName: history.queueProcessorEnablePersistQueueStates
 	// Value type: Bool
@@ -1546,6 +1792,21 @@ const (
 	// Default value: false
 	// Allowed filters: DomainID
 	EnableDropStuckTaskByDomainID
+	// EnableShardHandoff is whether the history hosts hand their shards off to the new owners when shutting down,
+	// within the HistoryShutdownDrainDuration
+	// KeyName: history.enableShardHandoff
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	EnableShardHandoff
+	// TaskSchedulerEnableFairScheduling is whether the tasks which the host level task scheduler cannot take right away
+	// are scheduled round robin across shards and domains by another host level scheduler, instead of by a scheduler
+	// per shard. It only takes effect when the host starts
+	// KeyName: history.taskSchedulerEnableFairScheduling
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	TaskSchedulerEnableFairScheduling
 	// EnableConsistentQuery indicates if consistent query is enabled for the cluster
 	// KeyName: history.EnableConsistentQuery
 	// Value type: Bool
@@ -1558,6 +1819,13 @@ const (
 	// Default value: false
 	// Allowed filters: DomainName
 	EnableConsistentQueryByDomain
+	// EnableConsistentQueryCatchUp is whether a strongly consistent query on a global domain first fetches the events
+	// which are not replicated yet from the cluster which last updated the workflow, when it was not updated since failover
+	// KeyName: history.enableConsistentQueryCatchUp
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName
+	EnableConsistentQueryCatchUp
 	// EnableCrossClusterOperations indicates if cross cluster operations can be scheduled for a domain
 	// KeyName: history.enableCrossClusterOperations
 	// Value type: Bool
@@ -1588,6 +1856,13 @@ const (
 	// Default value: true
 	// Allowed filters: DomainID, WorkflowID
 	EnableReplicationTaskGeneration
+	// EnableReplicationDLQAutoRepair is whether the history hosts retry the replication tasks in their DLQ in the
+	// background, with an exponential backoff
+	// KeyName: history.enableReplicationDLQAutoRepair
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	EnableReplicationDLQAutoRepair
 	// UseNewInitialFailoverVersion is a switch to issue a failover version based on the minFailoverVersion
 	// rather than the default initialFailoverVersion. USed as a per-domain migration switch
 	// KeyName: history.useNewInitialFailoverVersion
@@ -1798,6 +2073,45 @@ const (
 
 	EnableCassandraAllConsistencyLevelDelete
 
+	// EnableUsageAccounting is whether the resources consumed by domains are recorded and reported
+	// KeyName: system.enableUsageAccounting
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	EnableUsageAccounting
+
+	// EnableLoadShedding is whether a host rejects its low priority requests, then throttles its task processing,
+	// once its CPU, memory or goroutines go over their threshold
+	// KeyName: system.enableLoadShedding
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: N/A
+	EnableLoadShedding
+
+	// EnableHistorySizeWarningSearchAttribute is whether executions over the history size or count warn limits are flagged
+	// with the HistorySizeWarning search attribute, so they can be found via advanced visibility
+	// KeyName: history.enableHistorySizeWarningSearchAttribute
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName
+	EnableHistorySizeWarningSearchAttribute
+
+	// EnablePendingCountLimitFailure is whether the workflows over the error limit of pending activities or pending
+	// child workflows are failed when their next decision task completes
+	// KeyName: limit.pendingCount.failWorkflow
+	// Value type: Bool
+	// Default value: false
+	// Allowed filters: DomainName
+	EnablePendingCountLimitFailure
+
+	// EnablePersistenceLatencyHeatmap is whether the latency of persistence operations is collected by shard on each host,
+	// to be served as a heatmap on the pprof server
+	// KeyName: system.enablePersistenceLatencyHeatmap
+	// Value type: Bool
+	// Default value: true
+	// Allowed filters: N/A
+	EnablePersistenceLatencyHeatmap
+
 	// LastBoolKey must be the last one in this const group
 	LastBoolKey
 )
@@ -1807,6 +2121,7 @@ const (
 
 	// key for tests
 	TestGetFloat64PropertyKey
+	TestGetFloat64PropertyFilteredByDomainKey
 
 	// key for common & admin
 
@@ -1846,6 +2161,12 @@ const (
 	// Default value: 0
 	// Allowed filters: N/A
 	MatchingErrorInjectionRate
+	// MatchingExpiredTaskLogSampleRate is the rate of the expired tasks to log when they are skipped by the task reader
+	// KeyName: matching.expiredTaskLogSampleRate
+	// Value type: Float64
+	// Default value: 0
+	// Allowed filters: DomainName
+	MatchingExpiredTaskLogSampleRate
 
 	// key for history
 
@@ -1992,6 +2313,41 @@ const (
 	// TODO: https://github.com/uber/cadence/issues/3861
 	WorkerBlobIntegrityCheckProbability
 
+	// WatchdogGrowthThreshold is the relative growth over the window, beyond the growth of the load, above which
+	// a resource is suspected to leak
+	// KeyName: system.watchdogGrowthThreshold
+	// Value type: Float64
+	// Default value: 0.5
+	// Allowed filters: N/A
+	WatchdogGrowthThreshold
+	// LoadSheddingCPUThreshold is the fraction of the CPUs usable by the process above which a host sheds load
+	// KeyName: system.loadSheddingCPUThreshold
+	// Value type: Float64
+	// Default value: 0.9
+	// Allowed filters: N/A
+	LoadSheddingCPUThreshold
+	// RPCPayloadSizeWarnRatio is the ratio of the transport message size limit above which the payloads sent
+	// by a host are logged as oversize, 0 disables the warnings
+	// KeyName: system.rpcPayloadSizeWarnRatio
+	// Value type: Float64
+	// Default value: 0.8
+	// Allowed filters: N/A
+	RPCPayloadSizeWarnRatio
+	// FrontendSLOAvailabilityTarget is the fraction of the frontend requests of a domain expected not to fail
+	// with a server error, e.g. 0.999, 0 disables the availability SLO of the domain
+	// KeyName: frontend.sloAvailabilityTarget
+	// Value type: Float64
+	// Default value: 0
+	// Allowed filters: DomainName
+	FrontendSLOAvailabilityTarget
+	// FrontendSLOLatencyTarget is the fraction of the frontend requests of a domain expected to be served within
+	// FrontendSLOLatencyThreshold, e.g. 0.95 for a p95 objective, 0 disables the latency SLO of the domain
+	// KeyName: frontend.sloLatencyTarget
+	// Value type: Float64
+	// Default value: 0
+	// Allowed filters: DomainName
+	FrontendSLOLatencyTarget
+
 	// LastFloatKey must be the last one in this const group
 	LastFloatKey
 )
@@ -2060,6 +2416,68 @@ const (
 	// Default value: ""
 	ESAnalyzerWorkflowTypeMetricDomains
 
+	// FrontendProberDomain is the domain the frontend prober starts its synthetic workflows in, it should be a local domain
+	// KeyName: frontend.proberDomain
+	// Value type: String
+	// Default value: cadence-prober
+	// Allowed filters: N/A
+	FrontendProberDomain
+
+	// HistoryEncryptionKeyID is the ID of the KMS key the history of the domain is encrypted with at rest,
+	// the history is not encrypted if empty. The history written with previous keys can still be read after a change.
+	// KeyName: system.historyEncryptionKeyID
+	// Value type: String
+	// Default value: ""
+	// Allowed filters: DomainName
+	HistoryEncryptionKeyID
+	// HistoryCompressionCodec is the codec the history of the domain is compressed with at rest, the history is not
+	// compressed if empty. The history written with another codec, or uncompressed, can still be read after a change.
+	// KeyName: system.historyCompressionCodec
+	// Value type: String enum: "" or "zstd"
+	// Default value: ""
+	// Allowed filters: DomainName
+	HistoryCompressionCodec
+	// DomainResourcePool is the resource pool the domain is pinned to, the domains of a pool have their own rate limit
+	// budget on the history and matching hosts and their own task scheduler on the history hosts, the domains which are
+	// not pinned share the capacity of the hosts
+	// KeyName: system.domainResourcePool
+	// Value type: String
+	// Default value: ""
+	// Allowed filters: DomainName
+	DomainResourcePool
+
+	// PayloadRedaction is how the payloads of the domain are redacted before they reach logs and error responses,
+	// one of none, mask or hash. Hashed values can still be correlated without being revealed.
+	// KeyName: system.payloadRedaction
+	// Value type: String
+	// Default value: none
+	// Allowed filters: DomainName
+	PayloadRedaction
+
+	// SearchAttributeRedaction is how the search attribute values of the domain are redacted before they reach logs
+	// and error responses, one of none, mask or hash
+	// KeyName: system.searchAttributeRedaction
+	// Value type: String
+	// Default value: none
+	// Allowed filters: DomainName
+	SearchAttributeRedaction
+
+	// IdentityRedaction is how the identities of the callers of the domain are redacted before they reach logs
+	// and error responses, one of none, mask or hash
+	// KeyName: system.identityRedaction
+	// Value type: String
+	// Default value: none
+	// Allowed filters: DomainName
+	IdentityRedaction
+
+	// MatchingDomainIsolationGroup is the isolation group (e.g. zone) the decision and activity tasks of the domain
+	// are matched with the pollers of first, it has to be one of system.allIsolationGroups
+	// KeyName: matching.domainIsolationGroup
+	// Value type: String
+	// Default value: ""
+	// Allowed filters: DomainName
+	MatchingDomainIsolationGroup
+
 	// LastStringKey must be the last one in this const group
 	LastStringKey
 )
@@ -2091,18 +2509,52 @@ const (
 	// Allowed filters: N/A
 	DomainFailoverRefreshInterval
 
-	// MatchingLongPollExpirationInterval is the long poll expiration interval in the matching service
+	// MatchingLongPollExpirationInterval is the long poll expiration interval in the matching service.
+	// A poll returns an empty response earlier when its task list is unloaded, e.g. for being idle.
 	// KeyName: matching.longPollExpirationInterval
 	// Value type: Duration
 	// Default value: time.Minute
 	// Allowed filters: DomainName,TasklistName,TasklistType
 	MatchingLongPollExpirationInterval
+	// MatchingIsolationGroupSpilloverDelay is how long a task added with an isolation group waits for a poller
+	// of the same isolation group before it is offered to the pollers of the other groups
+	// KeyName: matching.isolationGroupSpilloverDelay
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingIsolationGroupSpilloverDelay
 	// MatchingUpdateAckInterval is the interval for update ack
 	// KeyName: matching.updateAckInterval
 	// Value type: Duration
 	// Default value: 1m (1*time.Minute)
 	// Allowed filters: DomainName,TasklistName,TasklistType
 	MatchingUpdateAckInterval
+	// MatchingMaxEmptyTaskReadBackoff is the maximum backoff between consecutive empty reads from the task buffer
+	// when the adaptive task read is enabled
+	// KeyName: matching.maxEmptyTaskReadBackoff
+	// Value type: Duration
+	// Default value: 5s (5*time.Second)
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingMaxEmptyTaskReadBackoff
+	// MatchingTaskDeleteFlushInterval is the max time completed tasks wait to be deleted when there are
+	// fewer of them than the delete batch size
+	// KeyName: matching.taskDeleteFlushInterval
+	// Value type: Duration
+	// Default value: 1s (time.Second)
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingTaskDeleteFlushInterval
+	// MatchingPartitionScaleInterval is the interval at which the partition auto scaling evaluates the load of a task list
+	// KeyName: matching.partitionScaleInterval
+	// Value type: Duration
+	// Default value: 1m (time.Minute)
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingPartitionScaleInterval
+	// MatchingPollerHistoryTTL is the time the info of a poller is kept by a task list after its last poll, it is read when the task list is loaded
+	// KeyName: matching.pollerHistoryTTL
+	// Value type: Duration
+	// Default value: 5m (5*time.Minute)
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingPollerHistoryTTL
 	// MatchingIdleTasklistCheckInterval is the IdleTasklistCheckInterval
 	// KeyName: matching.idleTasklistCheckInterval
 	// Value type: Duration
@@ -2127,6 +2579,13 @@ const (
 	// Default value: 100ms
 	// Allowed filters: DomainName
 	MatchingActivityTaskSyncMatchWaitTime
+	// MatchingEphemeralTaskSyncMatchWaitTime is the amount of time the tasks of ephemeral task lists wait to be sync matched
+	// before failing, as these tasks are never persisted
+	// KeyName: matching.ephemeralTaskSyncMatchWaitTime
+	// Value type: Duration
+	// Default value: 100ms
+	// Allowed filters: DomainName,TasklistName,TasklistType
+	MatchingEphemeralTaskSyncMatchWaitTime
 
 	// HistoryLongPollExpirationInterval is the long poll expiration interval in the history service
 	// KeyName: history.longPollExpirationInterval
@@ -2194,6 +2653,13 @@ const (
 	// Default value: 3m (3*time.Minute)
 	// Allowed filters: DomainID
 	StandbyTaskReReplicationContextTimeout
+	// ConsistentQueryCatchUpTimeout is the context timeout for fetching the events of a strongly consistent query
+	// from the cluster which last updated the workflow
+	// KeyName: history.consistentQueryCatchUpTimeout
+	// Value type: Duration
+	// Default value: 5s (5*time.Second)
+	// Allowed filters: DomainID
+	ConsistentQueryCatchUpTimeout
 	// ResurrectionCheckMinDelay is the minimal timer processing delay before scanning history to see
 	// if there's a resurrected timer/activity
 	// KeyName: history.resurrectionCheckMinDelay
@@ -2384,6 +2850,13 @@ const (
 	// Default value: time.Minute*5
 	// Allowed filters: DomainName
 	NormalDecisionScheduleToStartTimeout
+	// CronSmoothingWindow is the window over which the runs of cron workflows firing at the same time are spread,
+	// each workflow gets a stable offset within the window so that they don't all start at the same time
+	// KeyName: history.cronSmoothingWindow
+	// Value type: Duration
+	// Default value: 0 (disabled)
+	// Allowed filters: DomainName
+	CronSmoothingWindow
 	// NotifyFailoverMarkerInterval is determines the frequency to notify failover marker
 	// KeyName: history.NotifyFailoverMarkerInterval
 	// Value type: Duration
@@ -2456,6 +2929,19 @@ const (
 	// Default value: 5s (5* time.Second)
 	// Allowed filters: ShardID
 	ReplicationTaskProcessorStartWait
+	// ReplicationDLQAutoRepairInterval is the interval at which the replication DLQ of a shard is scanned,
+	// and the first backoff of a DLQ task which can't be repaired
+	// KeyName: history.replicationDLQAutoRepairInterval
+	// Value type: Duration
+	// Default value: 5m (5* time.Minute)
+	// Allowed filters: N/A
+	ReplicationDLQAutoRepairInterval
+	// ReplicationDLQAutoRepairMaxBackoff is the max backoff of a replication DLQ task which can't be repaired
+	// KeyName: history.replicationDLQAutoRepairMaxBackoff
+	// Value type: Duration
+	// Default value: 6h (6* time.Hour)
+	// Allowed filters: N/A
+	ReplicationDLQAutoRepairMaxBackoff
 	// WorkerESProcessorFlushInterval is flush interval for esProcessor
 	// KeyName: worker.ESProcessorFlushInterval
 	// Value type: Duration
@@ -2485,6 +2971,84 @@ const (
 	// Default value: 30 minutes
 	ESAnalyzerBufferWaitTime
 
+	// UsageAccountingReportInterval is the interval the usage of domains is aggregated over and reported at
+	// KeyName: system.usageAccountingReportInterval
+	// Value type: Duration
+	// Default value: 1m
+	// Allowed filters: N/A
+	UsageAccountingReportInterval
+
+	// UsageAccountingRetention is how long the reported usage of domains is kept
+	// KeyName: system.usageAccountingRetention
+	// Value type: Duration
+	// Default value: 720h (30 days)
+	// Allowed filters: N/A
+	UsageAccountingRetention
+
+	// ReshardingRefreshInterval is the interval at which the services reload the state of the history shard count resharding
+	// KeyName: system.reshardingRefreshInterval
+	// Value type: Duration
+	// Default value: 10s
+	// Allowed filters: N/A
+	ReshardingRefreshInterval
+
+	// SlowRequestLogThreshold is the latency above which an inbound request is logged with its latency breakdown, 0 disables the log
+	// KeyName: system.slowRequestLogThreshold
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: APIName
+	SlowRequestLogThreshold
+
+	// WatchdogSampleInterval is the interval the resource leak watchdog samples the resources of a host at, 0 disables it
+	// KeyName: system.watchdogSampleInterval
+	// Value type: Duration
+	// Default value: 1m
+	// Allowed filters: N/A
+	WatchdogSampleInterval
+
+	// LoadSheddingSampleInterval is the interval the load shedding samples the CPU, memory and goroutines of a host at
+	// KeyName: system.loadSheddingSampleInterval
+	// Value type: Duration
+	// Default value: 1s
+	// Allowed filters: N/A
+	LoadSheddingSampleInterval
+
+	// GRPCKeepAliveTime is the idle time after which the gRPC connections to the history and matching peers are pinged,
+	// 0 disables the keepalive, gRPC servers reject pings more frequent than 5m while there is no pending call
+	// KeyName: system.grpcKeepAliveTime
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: N/A
+	GRPCKeepAliveTime
+	// GRPCKeepAliveTimeout is the time a gRPC connection waits for the answer to a keepalive ping before it is closed
+	// and redialed
+	// KeyName: system.grpcKeepAliveTimeout
+	// Value type: Duration
+	// Default value: 20s
+	// Allowed filters: N/A
+	GRPCKeepAliveTimeout
+	// GRPCIdleConnectionTimeout is how long the gRPC connections to a history or matching peer are kept after its
+	// last call
+	// KeyName: system.grpcIdleConnectionTimeout
+	// Value type: Duration
+	// Default value: 10m
+	// Allowed filters: N/A
+	GRPCIdleConnectionTimeout
+
+	// FrontendProberInterval is the interval the frontend prober exercises the workflow APIs at, 0 disables the prober
+	// KeyName: frontend.proberInterval
+	// Value type: Duration
+	// Default value: 0
+	// Allowed filters: N/A
+	FrontendProberInterval
+	// FrontendSLOLatencyThreshold is the latency the frontend requests of a domain are expected to be served within,
+	// long polls are not accounted, see FrontendSLOLatencyTarget
+	// KeyName: frontend.sloLatencyThreshold
+	// Value type: Duration
+	// Default value: 1s
+	// Allowed filters: DomainName
+	FrontendSLOLatencyThreshold
+
 	// LastDurationKey must be the last one in this const group
 	LastDurationKey
 )
@@ -2503,6 +3067,36 @@ const (
 	// Default value: nil
 	// Allowed filters: N/A
 	RequiredDomainDataKeys
+	// DomainLogRPS is the max number of log messages per second emitted for a domain by a host, keyed by log level (debug, info, warn, error).
+	// Levels which are not set are not throttled and fatal logs are never throttled
+	// KeyName: system.domainLogRPS
+	// Value type: Map
+	// Default value: nil
+	// Allowed filters: DomainName
+	DomainLogRPS
+	// HistoryResourcePoolRPS is the max number of requests per second a history host serves for the domains of a
+	// resource pool, keyed by pool. The pools which are not set have the budget of the domains which are not pinned
+	// KeyName: history.resourcePoolRPS
+	// Value type: Map
+	// Default value: nil
+	// Allowed filters: N/A
+	HistoryResourcePoolRPS
+	// HistoryResourcePoolTaskWorkerCount is the number of workers of the task scheduler of a resource pool on a history
+	// host, keyed by pool. The pools which are not set have as many workers as the scheduler of the domains which are
+	// not pinned
+	// KeyName: history.resourcePoolTaskWorkerCount
+	// Value type: Map
+	// Default value: nil
+	// Allowed filters: N/A
+	HistoryResourcePoolTaskWorkerCount
+	// MatchingResourcePoolRPS is the max number of user and of worker requests per second a matching host serves for
+	// the domains of a resource pool, keyed by pool. The pools which are not set have the budget of the domains which
+	// are not pinned
+	// KeyName: matching.resourcePoolRPS
+	// Value type: Map
+	// Default value: nil
+	// Allowed filters: N/A
+	MatchingResourcePoolRPS
 
 	// key for frontend
 
@@ -2521,6 +3115,13 @@ const (
 	// Default value: please see common.ConvertIntMapToDynamicConfigMapProperty(DefaultTaskPriorityWeight) in code base
 	// Allowed filters: N/A
 	TaskSchedulerRoundRobinWeights
+	// TaskSchedulerDomainWeights is the number of tasks of a domain in a shard which the fair task scheduler dispatches
+	// in a row, keyed by domain name. The domains which are not set have a weight of 1
+	// KeyName: history.taskSchedulerDomainWeights
+	// Value type: Map
+	// Default value: nil
+	// Allowed filters: N/A
+	TaskSchedulerDomainWeights
 	// QueueProcessorPendingTaskSplitThreshold is the threshold for the number of pending tasks per domain
 	// KeyName: history.queueProcessorPendingTaskSplitThreshold
 	// Value type: Map
@@ -2552,6 +3153,13 @@ const (
 	// Default value: forward all headers.  (this is a problematic value, and it will be changing as we reduce to a list of known values)
 	HeaderForwardingRules
 
+	// AllIsolationGroups is the list of the isolation groups (e.g. zones) of the cluster. Matching only matches
+	// tasks with the pollers of their isolation group for these groups, the other groups are ignored
+	// KeyName: system.allIsolationGroups
+	// Value type: []string
+	// Default value: empty
+	AllIsolationGroups
+
 	LastListKey
 )
 
@@ -2631,6 +3239,16 @@ var IntKeys = map[IntKey]DynamicInt{
 		Description:  "PendingActivitiesCountLimitWarn is the limit of how many activities a workflow can have before a warning is logged",
 		DefaultValue: 512,
 	},
+	PendingChildExecutionsCountLimitError: DynamicInt{
+		KeyName:      "limit.pendingChildExecutionCount.error",
+		Description:  "PendingChildExecutionsCountLimitError is the limit of how many pending child workflows a workflow can have at a point in time",
+		DefaultValue: 1024,
+	},
+	PendingChildExecutionsCountLimitWarn: DynamicInt{
+		KeyName:      "limit.pendingChildExecutionCount.warn",
+		Description:  "PendingChildExecutionsCountLimitWarn is the limit of how many pending child workflows a workflow can have before a warning is emitted",
+		DefaultValue: 512,
+	},
 	DomainNameMaxLength: DynamicInt{
 		KeyName:      "limit.domainNameLength",
 		Description:  "DomainNameMaxLength is the length limit for domain name",
@@ -2773,6 +3391,21 @@ var IntKeys = map[IntKey]DynamicInt{
 		Description:  "FrontendGlobalDomainVisibilityRPS is the per-domain List*WorkflowExecutions request rate limit per second",
 		DefaultValue: UnlimitedRPS,
 	},
+	FrontendMaxCallerUserRPSPerInstance: DynamicInt{
+		KeyName:      "frontend.callerrps",
+		Description:  "FrontendMaxCallerUserRPSPerInstance is workflow rate limit per second of each authenticated caller of a domain, 0 means no limit",
+		DefaultValue: 0,
+	},
+	FrontendMaxCallerWorkerRPSPerInstance: DynamicInt{
+		KeyName:      "frontend.callerWorkerrps",
+		Description:  "FrontendMaxCallerWorkerRPSPerInstance is background-processing workflow rate limit per second of each authenticated caller of a domain, 0 means no limit",
+		DefaultValue: 0,
+	},
+	FrontendMaxCallerVisibilityRPSPerInstance: DynamicInt{
+		KeyName:      "frontend.callerVisibilityrps",
+		Description:  "FrontendMaxCallerVisibilityRPSPerInstance is the List*WorkflowExecutions request rate limit per second of each authenticated caller of a domain, 0 means no limit",
+		DefaultValue: 0,
+	},
 	FrontendDecisionResultCountLimit: DynamicInt{
 		KeyName:      "frontend.decisionResultCountLimit",
 		Description:  "FrontendDecisionResultCountLimit is max number of decisions per RespondDecisionTaskCompleted request",
@@ -2853,6 +3486,11 @@ var IntKeys = map[IntKey]DynamicInt{
 		Description:  "MatchingGetTasksBatchSize is the maximum batch size to fetch from the task buffer",
 		DefaultValue: 1000,
 	},
+	MatchingMaxGetTasksBatchSize: DynamicInt{
+		KeyName:      "matching.maxGetTasksBatchSize",
+		Description:  "MatchingMaxGetTasksBatchSize is the maximum batch size to fetch from the task buffer when the adaptive task read is enabled",
+		DefaultValue: 5000,
+	},
 	MatchingOutstandingTaskAppendsThreshold: DynamicInt{
 		KeyName:      "matching.outstandingTaskAppendsThreshold",
 		Description:  "MatchingOutstandingTaskAppendsThreshold is the threshold for outstanding task appends",
@@ -2868,6 +3506,31 @@ var IntKeys = map[IntKey]DynamicInt{
 		Description:  "MatchingMaxTaskDeleteBatchSize is the max batch size for range deletion of tasks",
 		DefaultValue: 100,
 	},
+	MatchingCompletedTaskDeleteBatchSize: DynamicInt{
+		KeyName:      "matching.completedTaskDeleteBatchSize",
+		Description:  "MatchingCompletedTaskDeleteBatchSize is the number of tasks completed above a stuck ack level which are buffered before they are deleted one by one, they are deleted on the next flush interval otherwise",
+		DefaultValue: 100,
+	},
+	MatchingPartitionScaleUpRPS: DynamicInt{
+		KeyName:      "matching.partitionScaleUpRPS",
+		Description:  "MatchingPartitionScaleUpRPS is the rate of tasks added to or polled from the root partition of a task list above which a partition is added when the partition auto scaling is enabled",
+		DefaultValue: 1000,
+	},
+	MatchingPartitionScaleDownRPS: DynamicInt{
+		KeyName:      "matching.partitionScaleDownRPS",
+		Description:  "MatchingPartitionScaleDownRPS is the rate of tasks added to or polled from the root partition of a task list, once projected on one partition less, below which a partition is removed when the partition auto scaling is enabled",
+		DefaultValue: 250,
+	},
+	MatchingMinTaskListPartitions: DynamicInt{
+		KeyName:      "matching.minTaskListPartitions",
+		Description:  "MatchingMinTaskListPartitions is the min number of partitions the partition auto scaling scales a task list down to",
+		DefaultValue: 1,
+	},
+	MatchingMaxTaskListPartitions: DynamicInt{
+		KeyName:      "matching.maxTaskListPartitions",
+		Description:  "MatchingMaxTaskListPartitions is the max number of partitions the partition auto scaling scales a task list up to",
+		DefaultValue: 8,
+	},
 	MatchingThrottledLogRPS: DynamicInt{
 		KeyName:      "matching.throttledLogRPS",
 		Description:  "MatchingThrottledLogRPS is the rate limit on number of log messages emitted per second for throttled logger",
@@ -2903,6 +3566,21 @@ var IntKeys = map[IntKey]DynamicInt{
 		Description:  "MatchingForwarderMaxChildrenPerNode is the max number of children per node in the task list partition tree",
 		DefaultValue: 20,
 	},
+	MatchingTaskDispatchRPS: DynamicInt{
+		KeyName:      "matching.taskDispatchRPS",
+		Description:  "MatchingTaskDispatchRPS is the max rate at which tasks are dispatched from a task list, overriding the rate provided by pollers",
+		DefaultValue: 0,
+	},
+	MatchingPollerHistoryMaxSize: DynamicInt{
+		KeyName:      "matching.pollerHistoryMaxSize",
+		Description:  "MatchingPollerHistoryMaxSize is the max number of pollers whose info is kept by a task list, it is read when the task list is loaded",
+		DefaultValue: 5000,
+	},
+	MatchingTaskListMaxConcurrentActivities: DynamicInt{
+		KeyName:      "matching.taskListMaxConcurrentActivities",
+		Description:  "MatchingTaskListMaxConcurrentActivities is the max number of activities of an activity task list which are running at once, an activity is running from being dispatched to a worker until the worker responds with its result or its start to close timeout expires. The limit is divided across the task list partitions",
+		DefaultValue: 0,
+	},
 	HistoryRPS: DynamicInt{
 		KeyName:      "history.rps",
 		Description:  "HistoryRPS is request rate per second for each history host",
@@ -2968,6 +3646,11 @@ var IntKeys = map[IntKey]DynamicInt{
 		Description:  "AcquireShardConcurrency is number of goroutines that can be used to acquire shards in the shard controller.",
 		DefaultValue: 1,
 	},
+	ShardHandoffMaxConcurrency: DynamicInt{
+		KeyName:      "history.shardHandoffMaxConcurrency",
+		Description:  "ShardHandoffMaxConcurrency is the max number of shards a history host hands off concurrently when shutting down",
+		DefaultValue: 10,
+	},
 	TaskProcessRPS: DynamicInt{
 		KeyName:      "history.taskProcessRPS",
 		Description:  "TaskProcessRPS is the task processing rate per second for each domain",
@@ -3173,6 +3856,16 @@ var IntKeys = map[IntKey]DynamicInt{
 		Description:  "MaximumSignalsPerExecution is max number of signals supported by single execution",
 		DefaultValue: 10000, // 10K signals should big enough given workflow execution has 200K history lengh limit. It needs to be non-zero to protect continueAsNew from infinit loop
 	},
+	WorkflowIDSignalRPS: DynamicInt{
+		KeyName:      "history.workflowIDSignalRPS",
+		Description:  "WorkflowIDSignalRPS is the max number of signals per second a single workflow ID can receive, 0 means no limit",
+		DefaultValue: 0,
+	},
+	WorkflowIDEventsRPS: DynamicInt{
+		KeyName:      "history.workflowIDEventsRPS",
+		Description:  "WorkflowIDEventsRPS is the max number of history events per second a single workflow ID can generate from its decisions, 0 means no limit",
+		DefaultValue: 0,
+	},
 	NumArchiveSystemWorkflows: DynamicInt{
 		KeyName:      "history.numArchiveSystemWorkflows",
 		Description:  "NumArchiveSystemWorkflows is key for number of archive system workflows running in total",
@@ -3258,6 +3951,11 @@ var IntKeys = map[IntKey]DynamicInt{
 		Description:  "MaxActivityCountDispatchByDomain max # of activity tasks to dispatch to matching before creating transfer tasks. This is an performance optimization to skip activity scheduling efforts.",
 		DefaultValue: 0,
 	},
+	MaxActivityCountLocalDispatchByDomain: DynamicInt{
+		KeyName:      "history.maxActivityCountLocalDispatchByDomain",
+		Description:  "MaxActivityCountLocalDispatchByDomain max # of activity tasks requested for local dispatch that are returned to the worker in the RespondDecisionTaskCompleted response. Activities beyond it are scheduled through matching",
+		DefaultValue: 1,
+	},
 	ReplicationTaskFetcherParallelism: DynamicInt{
 		KeyName:      "history.ReplicationTaskFetcherParallelism",
 		Description:  "ReplicationTaskFetcherParallelism determines how many go routines we spin up for fetching tasks",
@@ -3438,6 +4136,46 @@ var IntKeys = map[IntKey]DynamicInt{
 		Description:  "WorkflowDeletionJitterRange defines the duration in minutes for workflow close tasks jittering",
 		DefaultValue: 60,
 	},
+	FeatureFlagRolloutPercentage: DynamicInt{
+		KeyName:      "system.featureFlagRolloutPercentage",
+		Description:  "FeatureFlagRolloutPercentage is the percentage of domains or workflows a feature flag is enabled for",
+		DefaultValue: 0,
+	},
+	MetricsTagValuesLimit: DynamicInt{
+		KeyName:      "system.metricsTagValuesLimit",
+		Description:  "MetricsTagValuesLimit is the max number of distinct task list and workflow type tag values reported per metrics scope, the values beyond the limit are reported as _other_",
+		DefaultValue: 10000,
+	},
+	WatchdogWindowSize: DynamicInt{
+		KeyName:      "system.watchdogWindowSize",
+		Description:  "WatchdogWindowSize is the number of samples the resource leak watchdog looks at to detect monotonic growth",
+		DefaultValue: 30,
+	},
+	LoadSheddingMemoryThreshold: DynamicInt{
+		KeyName:      "system.loadSheddingMemoryThreshold",
+		Description:  "LoadSheddingMemoryThreshold is the heap size in bytes above which a host sheds load, 0 disables the memory monitor",
+		DefaultValue: 0,
+	},
+	LoadSheddingGoroutineThreshold: DynamicInt{
+		KeyName:      "system.loadSheddingGoroutineThreshold",
+		Description:  "LoadSheddingGoroutineThreshold is the number of goroutines above which a host sheds load, 0 disables the goroutine monitor",
+		DefaultValue: 0,
+	},
+	HistoryLoadSheddingTaskRPS: DynamicInt{
+		KeyName:      "history.loadSheddingTaskRPS",
+		Description:  "HistoryLoadSheddingTaskRPS is the rate the queue tasks of a history host are processed at once its load shedding throttles the task processing",
+		DefaultValue: 100,
+	},
+	GRPCConnectionsPerPeer: DynamicInt{
+		KeyName:      "system.grpcConnectionsPerPeer",
+		Description:  "GRPCConnectionsPerPeer is the number of gRPC connections a host keeps to every history and matching peer it calls, the calls to a peer are spread over the connections which are available",
+		DefaultValue: 1,
+	},
+	GRPCPeerSubsetSize: DynamicInt{
+		KeyName:      "system.grpcPeerSubsetSize",
+		Description:  "GRPCPeerSubsetSize is the number of peers of a remote cluster a host sends its cross cluster calls to, the subset is re-picked on every DNS refresh to replace the peers which are unavailable, 0 sends the calls to all the peers",
+		DefaultValue: 0,
+	},
 }
 
 var BoolKeys = map[BoolKey]DynamicBool{
@@ -3551,11 +4289,61 @@ var BoolKeys = map[BoolKey]DynamicBool{
 		Description:  "MatchingEnableSyncMatch is to enable sync match",
 		DefaultValue: true,
 	},
+	MatchingEnableAdaptiveTaskRead: DynamicBool{
+		KeyName:      "matching.enableAdaptiveTaskRead",
+		Description:  "MatchingEnableAdaptiveTaskRead is to enable growing the batch size to fetch from the task buffer when the backlog is deep and pollers are waiting for tasks, and backing off exponentially on consecutive empty reads",
+		DefaultValue: false,
+	},
+	MatchingEnablePartitionAutoScaling: DynamicBool{
+		KeyName:      "matching.enablePartitionAutoScaling",
+		Description:  "MatchingEnablePartitionAutoScaling is to enable adjusting the number of read and write partitions of a task list from the load of its root partition, the partition counts are written to the dynamic config store",
+		DefaultValue: false,
+	},
+	MatchingTaskListDrainMode: DynamicBool{
+		KeyName:      "matching.taskListDrainMode",
+		Description:  "MatchingTaskListDrainMode is to reject the new tasks of a task list while its pollers consume the backlog, the task list is drained once all its partitions have no task left",
+		DefaultValue: false,
+	},
+	MatchingEnableStandbyTaskBuffering: DynamicBool{
+		KeyName:      "matching.enableStandbyTaskBuffering",
+		Description:  "MatchingEnableStandbyTaskBuffering is to keep the tasks of a standby domain in the task list backlog instead of loading them for dispatch, the backlog is released when the domain fails over to the current cluster",
+		DefaultValue: false,
+	},
+	MatchingEnableExpiredTaskDeadLetter: DynamicBool{
+		KeyName:      "matching.enableExpiredTaskDeadLetter",
+		Description:  "MatchingEnableExpiredTaskDeadLetter is to write the tasks that expired in the task list backlog to the dead letter queue for post-mortem analysis instead of dropping them",
+		DefaultValue: false,
+	},
+	MatchingEnableWorkflowFairDispatch: DynamicBool{
+		KeyName:      "matching.enableWorkflowFairDispatch",
+		Description:  "MatchingEnableWorkflowFairDispatch is to dispatch the backlog of a task list in round robin across the workflows of its tasks, so that a workflow with many tasks cannot hold back the other workflows",
+		DefaultValue: false,
+	},
+	MatchingEnableTaskListHandoff: DynamicBool{
+		KeyName:      "matching.enableTaskListHandoff",
+		Description:  "MatchingEnableTaskListHandoff is whether the matching hosts hand their task lists off to the new owners when shutting down, the task lists with pollers or a backlog are loaded by the new owners right away",
+		DefaultValue: false,
+	},
+	MatchingEnableQueryPollerReservation: DynamicBool{
+		KeyName:      "matching.enableQueryPollerReservation",
+		Description:  "MatchingEnableQueryPollerReservation is to hold the dispatch of the task list backlog while a query task waits for a poller, so that queries are matched with the next poller even when the task list has a large backlog",
+		DefaultValue: false,
+	},
+	MatchingEnableEphemeralTaskList: DynamicBool{
+		KeyName:      "matching.enableEphemeralTaskList",
+		Description:  "MatchingEnableEphemeralTaskList is to never persist the tasks of the normal task lists, their tasks are only sync matched and fail when no poller picks them up. It is read once when the task list is loaded",
+		DefaultValue: false,
+	},
 	MatchingEnableTaskInfoLogByDomainID: DynamicBool{
 		KeyName:      "matching.enableTaskInfoLogByDomainID",
 		Description:  "MatchingEnableTaskInfoLogByDomainID is enables info level logs for decision/activity task based on the request domainID",
 		DefaultValue: false,
 	},
+	MatchingEnableZoneAwareRouting: DynamicBool{
+		KeyName:      "matching.enableZoneAwareRouting",
+		Description:  "MatchingEnableZoneAwareRouting is to send the tasks and polls of a task list to the partitions owned by the matching hosts in the zone of the caller, when there are some, so that they don't cross zones",
+		DefaultValue: false,
+	},
 	EventsCacheGlobalEnable: DynamicBool{
 		KeyName:      "history.eventsCacheGlobalEnable",
 		Description:  "EventsCacheGlobalEnable is enables global cache over all history shards",
@@ -3581,6 +4369,11 @@ var BoolKeys = map[BoolKey]DynamicBool{
 		Description:  "QueueProcessorEnableStuckTaskSplitByDomainID is indicates whether stuck task split policy should be enabled",
 		DefaultValue: false,
 	},
+	QueueProcessorEnableLowPrioritySplitQueueByDomainID: DynamicBool{
+		KeyName:      "history.queueProcessorEnableLowPrioritySplitQueueByDomainID",
+		Description:  "QueueProcessorEnableLowPrioritySplitQueueByDomainID is indicates whether tasks of a domain loaded by processing queues split from the default level should be processed at low priority",
+		DefaultValue: true,
+	},
 	QueueProcessorEnablePersistQueueStates: DynamicBool{
 		KeyName:      "history.queueProcessorEnablePersistQueueStates",
 		Description:  "QueueProcessorEnablePersistQueueStates is indicates whether processing queue states should be persisted",
@@ -3611,6 +4404,16 @@ var BoolKeys = map[BoolKey]DynamicBool{
 		Description:  "EnableDropStuckTaskByDomainID is whether stuck timer/transfer task should be dropped for a domain",
 		DefaultValue: false,
 	},
+	EnableShardHandoff: DynamicBool{
+		KeyName:      "history.enableShardHandoff",
+		Description:  "EnableShardHandoff is whether the history hosts hand their shards off to the new owners when shutting down, within the HistoryShutdownDrainDuration",
+		DefaultValue: false,
+	},
+	TaskSchedulerEnableFairScheduling: DynamicBool{
+		KeyName:      "history.taskSchedulerEnableFairScheduling",
+		Description:  "TaskSchedulerEnableFairScheduling is whether the tasks which the host level task scheduler cannot take right away are scheduled round robin across shards and domains by another host level scheduler, instead of by a scheduler per shard. It only takes effect when the host starts",
+		DefaultValue: false,
+	},
 	EnableConsistentQuery: DynamicBool{
 		KeyName:      "history.EnableConsistentQuery",
 		Description:  "EnableConsistentQuery indicates if consistent query is enabled for the cluster",
@@ -3621,6 +4424,11 @@ var BoolKeys = map[BoolKey]DynamicBool{
 		Description:  "EnableConsistentQueryByDomain indicates if consistent query is enabled for a domain",
 		DefaultValue: false,
 	},
+	EnableConsistentQueryCatchUp: DynamicBool{
+		KeyName:      "history.enableConsistentQueryCatchUp",
+		Description:  "EnableConsistentQueryCatchUp is whether a strongly consistent query on a global domain first fetches the events which are not replicated yet from the cluster which last updated the workflow, when it was not updated since failover",
+		DefaultValue: false,
+	},
 	EnableCrossClusterOperations: DynamicBool{
 		KeyName:      "history.enableCrossClusterOperations",
 		Description:  "EnableCrossClusterOperations indicates if cross cluster operations can be scheduled for a domain",
@@ -3646,6 +4454,11 @@ var BoolKeys = map[BoolKey]DynamicBool{
 		Description:  "EnableReplicationTaskGeneration is the flag to control replication generation",
 		DefaultValue: true,
 	},
+	EnableReplicationDLQAutoRepair: DynamicBool{
+		KeyName:      "history.enableReplicationDLQAutoRepair",
+		Description:  "EnableReplicationDLQAutoRepair is whether the history hosts retry the replication tasks in their DLQ in the background, with an exponential backoff",
+		DefaultValue: false,
+	},
 	UseNewInitialFailoverVersion: DynamicBool{
 		KeyName:      "history.useNewInitialFailoverVersion",
 		Description:  "use the minInitialFailover version",
@@ -3821,6 +4634,31 @@ var BoolKeys = map[BoolKey]DynamicBool{
 		Description:  "Uses all consistency level for Cassandra delete operations",
 		DefaultValue: false,
 	},
+	EnableUsageAccounting: DynamicBool{
+		KeyName:      "system.enableUsageAccounting",
+		Description:  "EnableUsageAccounting is whether the resources consumed by domains are recorded and reported",
+		DefaultValue: false,
+	},
+	EnableLoadShedding: DynamicBool{
+		KeyName:      "system.enableLoadShedding",
+		Description:  "EnableLoadShedding is whether a host rejects its low priority requests, then throttles its task processing, once its CPU, memory or goroutines go over their threshold",
+		DefaultValue: false,
+	},
+	EnableHistorySizeWarningSearchAttribute: DynamicBool{
+		KeyName:      "history.enableHistorySizeWarningSearchAttribute",
+		Description:  "EnableHistorySizeWarningSearchAttribute is whether executions over the history size or count warn limits are flagged with the HistorySizeWarning search attribute",
+		DefaultValue: false,
+	},
+	EnablePendingCountLimitFailure: DynamicBool{
+		KeyName:      "limit.pendingCount.failWorkflow",
+		Description:  "EnablePendingCountLimitFailure is whether the workflows over the error limit of pending activities or pending child workflows are failed when their next decision task completes",
+		DefaultValue: false,
+	},
+	EnablePersistenceLatencyHeatmap: DynamicBool{
+		KeyName:      "system.enablePersistenceLatencyHeatmap",
+		Description:  "EnablePersistenceLatencyHeatmap is whether the latency of persistence operations is collected by shard on each host, to be served as a heatmap on the pprof server",
+		DefaultValue: true,
+	},
 }
 
 var FloatKeys = map[FloatKey]DynamicFloat{
@@ -3829,6 +4667,11 @@ var FloatKeys = map[FloatKey]DynamicFloat{
 		Description:  "",
 		DefaultValue: 0,
 	},
+	TestGetFloat64PropertyFilteredByDomainKey: DynamicFloat{
+		KeyName:      "testGetFloat64PropertyFilteredByDomainKey",
+		Description:  "",
+		DefaultValue: 0,
+	},
 	PersistenceErrorInjectionRate: DynamicFloat{
 		KeyName:      "system.persistenceErrorInjectionRate",
 		Description:  "PersistenceErrorInjectionRate is rate for injecting random error in persistence",
@@ -3854,6 +4697,11 @@ var FloatKeys = map[FloatKey]DynamicFloat{
 		Description:  "MatchingErrorInjectionRate is rate for injecting random error in matching client",
 		DefaultValue: 0,
 	},
+	MatchingExpiredTaskLogSampleRate: DynamicFloat{
+		KeyName:      "matching.expiredTaskLogSampleRate",
+		Description:  "MatchingExpiredTaskLogSampleRate is the rate of the expired tasks to log when they are skipped by the task reader",
+		DefaultValue: 0,
+	},
 	TaskRedispatchIntervalJitterCoefficient: DynamicFloat{
 		KeyName:      "history.taskRedispatchIntervalJitterCoefficient",
 		Description:  "TaskRedispatchIntervalJitterCoefficient is the task redispatch interval jitter coefficient",
@@ -3974,6 +4822,31 @@ var FloatKeys = map[FloatKey]DynamicFloat{
 		Description:  "WorkerBlobIntegrityCheckProbability controls the probability of running an integrity check for any given archival",
 		DefaultValue: 0.002,
 	},
+	WatchdogGrowthThreshold: DynamicFloat{
+		KeyName:      "system.watchdogGrowthThreshold",
+		Description:  "WatchdogGrowthThreshold is the relative growth over the window, beyond the growth of the load, above which a resource is suspected to leak",
+		DefaultValue: 0.5,
+	},
+	LoadSheddingCPUThreshold: DynamicFloat{
+		KeyName:      "system.loadSheddingCPUThreshold",
+		Description:  "LoadSheddingCPUThreshold is the fraction of the CPUs usable by the process above which a host sheds load",
+		DefaultValue: 0.9,
+	},
+	RPCPayloadSizeWarnRatio: DynamicFloat{
+		KeyName:      "system.rpcPayloadSizeWarnRatio",
+		Description:  "RPCPayloadSizeWarnRatio is the ratio of the transport message size limit above which the payloads sent by a host are logged as oversize, 0 disables the warnings",
+		DefaultValue: 0.8,
+	},
+	FrontendSLOAvailabilityTarget: DynamicFloat{
+		KeyName:      "frontend.sloAvailabilityTarget",
+		Description:  "FrontendSLOAvailabilityTarget is the fraction of the frontend requests of a domain expected not to fail with a server error, e.g. 0.999, 0 disables the availability SLO of the domain",
+		DefaultValue: 0,
+	},
+	FrontendSLOLatencyTarget: DynamicFloat{
+		KeyName:      "frontend.sloLatencyTarget",
+		Description:  "FrontendSLOLatencyTarget is the fraction of the frontend requests of a domain expected to be served within FrontendSLOLatencyThreshold, e.g. 0.95 for a p95 objective, 0 disables the latency SLO of the domain",
+		DefaultValue: 0,
+	},
 }
 
 var StringKeys = map[StringKey]DynamicString{
@@ -4032,6 +4905,46 @@ var StringKeys = map[StringKey]DynamicString{
 		Description:  "ESAnalyzerWorkflowDurationWarnThresholds defines the domains we want to emit wf version metrics on",
 		DefaultValue: "",
 	},
+	FrontendProberDomain: DynamicString{
+		KeyName:      "frontend.proberDomain",
+		Description:  "FrontendProberDomain is the domain the frontend prober starts its synthetic workflows in, it should be a local domain",
+		DefaultValue: "cadence-prober",
+	},
+	HistoryEncryptionKeyID: DynamicString{
+		KeyName:      "system.historyEncryptionKeyID",
+		Description:  "HistoryEncryptionKeyID is the ID of the KMS key the history of the domain is encrypted with at rest, the history is not encrypted if empty",
+		DefaultValue: "",
+	},
+	HistoryCompressionCodec: DynamicString{
+		KeyName:      "system.historyCompressionCodec",
+		Description:  "HistoryCompressionCodec is the codec the history of the domain is compressed with at rest, either zstd or empty for no compression",
+		DefaultValue: "",
+	},
+	DomainResourcePool: DynamicString{
+		KeyName:      "system.domainResourcePool",
+		Description:  "DomainResourcePool is the resource pool the domain is pinned to, the domains of a pool have their own rate limit budget on the history and matching hosts and their own task scheduler on the history hosts, the domains which are not pinned share the capacity of the hosts",
+		DefaultValue: "",
+	},
+	PayloadRedaction: DynamicString{
+		KeyName:      "system.payloadRedaction",
+		Description:  "PayloadRedaction is how the payloads of the domain are redacted before they reach logs and error responses, one of none, mask or hash",
+		DefaultValue: "none",
+	},
+	SearchAttributeRedaction: DynamicString{
+		KeyName:      "system.searchAttributeRedaction",
+		Description:  "SearchAttributeRedaction is how the search attribute values of the domain are redacted before they reach logs and error responses, one of none, mask or hash",
+		DefaultValue: "none",
+	},
+	IdentityRedaction: DynamicString{
+		KeyName:      "system.identityRedaction",
+		Description:  "IdentityRedaction is how the identities of the callers of the domain are redacted before they reach logs and error responses, one of none, mask or hash",
+		DefaultValue: "none",
+	},
+	MatchingDomainIsolationGroup: DynamicString{
+		KeyName:      "matching.domainIsolationGroup",
+		Description:  "MatchingDomainIsolationGroup is the isolation group (e.g. zone) the decision and activity tasks of the domain are matched with the pollers of first, it has to be one of system.allIsolationGroups",
+		DefaultValue: "",
+	},
 }
 
 var DurationKeys = map[DurationKey]DynamicDuration{
@@ -4070,11 +4983,36 @@ var DurationKeys = map[DurationKey]DynamicDuration{
 		Description:  "MatchingLongPollExpirationInterval is the long poll expiration interval in the matching service",
 		DefaultValue: time.Minute,
 	},
+	MatchingIsolationGroupSpilloverDelay: DynamicDuration{
+		KeyName:      "matching.isolationGroupSpilloverDelay",
+		Description:  "MatchingIsolationGroupSpilloverDelay is how long a task added with an isolation group waits for a poller of the same isolation group before it is offered to the pollers of the other groups",
+		DefaultValue: 0,
+	},
 	MatchingUpdateAckInterval: DynamicDuration{
 		KeyName:      "matching.updateAckInterval",
 		Description:  "MatchingUpdateAckInterval is the interval for update ack",
 		DefaultValue: time.Minute,
 	},
+	MatchingMaxEmptyTaskReadBackoff: DynamicDuration{
+		KeyName:      "matching.maxEmptyTaskReadBackoff",
+		Description:  "MatchingMaxEmptyTaskReadBackoff is the maximum backoff between consecutive empty reads from the task buffer when the adaptive task read is enabled",
+		DefaultValue: 5 * time.Second,
+	},
+	MatchingTaskDeleteFlushInterval: DynamicDuration{
+		KeyName:      "matching.taskDeleteFlushInterval",
+		Description:  "MatchingTaskDeleteFlushInterval is the max time completed tasks wait to be deleted when there are fewer of them than the delete batch size",
+		DefaultValue: time.Second,
+	},
+	MatchingPollerHistoryTTL: DynamicDuration{
+		KeyName:      "matching.pollerHistoryTTL",
+		Description:  "MatchingPollerHistoryTTL is the time the info of a poller is kept by a task list after its last poll, it is read when the task list is loaded",
+		DefaultValue: 5 * time.Minute,
+	},
+	MatchingPartitionScaleInterval: DynamicDuration{
+		KeyName:      "matching.partitionScaleInterval",
+		Description:  "MatchingPartitionScaleInterval is the interval at which the partition auto scaling evaluates the load of a task list",
+		DefaultValue: time.Minute,
+	},
 	MatchingIdleTasklistCheckInterval: DynamicDuration{
 		KeyName:      "matching.idleTasklistCheckInterval",
 		Description:  "MatchingIdleTasklistCheckInterval is the IdleTasklistCheckInterval",
@@ -4095,6 +5033,11 @@ var DurationKeys = map[DurationKey]DynamicDuration{
 		Description:  "MatchingActivityTaskSyncMatchWaitTime is the amount of time activity task will wait to be sync matched",
 		DefaultValue: time.Millisecond * 50,
 	},
+	MatchingEphemeralTaskSyncMatchWaitTime: DynamicDuration{
+		KeyName:      "matching.ephemeralTaskSyncMatchWaitTime",
+		Description:  "MatchingEphemeralTaskSyncMatchWaitTime is the amount of time the tasks of ephemeral task lists wait to be sync matched before failing, as these tasks are never persisted",
+		DefaultValue: time.Millisecond * 100,
+	},
 	HistoryLongPollExpirationInterval: DynamicDuration{
 		KeyName:      "history.longPollExpirationInterval",
 		Description:  "HistoryLongPollExpirationInterval is the long poll expiration interval in the history service",
@@ -4150,6 +5093,11 @@ var DurationKeys = map[DurationKey]DynamicDuration{
 		Description:  "StandbyTaskReReplicationContextTimeout is the context timeout for standby task re-replication",
 		DefaultValue: time.Minute * 3,
 	},
+	ConsistentQueryCatchUpTimeout: DynamicDuration{
+		KeyName:      "history.consistentQueryCatchUpTimeout",
+		Description:  "ConsistentQueryCatchUpTimeout is the context timeout for fetching the events of a strongly consistent query from the cluster which last updated the workflow",
+		DefaultValue: time.Second * 5,
+	},
 	ResurrectionCheckMinDelay: DynamicDuration{
 		KeyName:      "history.resurrectionCheckMinDelay",
 		Description:  "ResurrectionCheckMinDelay is the minimal timer processing delay before scanning history to see if there's a resurrected timer/activity",
@@ -4300,6 +5248,11 @@ var DurationKeys = map[DurationKey]DynamicDuration{
 		Description:  "NormalDecisionScheduleToStartTimeout is scheduleToStart timeout duration for normal (non-sticky) decision task",
 		DefaultValue: time.Minute * 5,
 	},
+	CronSmoothingWindow: DynamicDuration{
+		KeyName:      "history.cronSmoothingWindow",
+		Description:  "CronSmoothingWindow is the window over which the runs of cron workflows firing at the same time are spread, each workflow gets a stable offset within the window so that they don't all start at the same time",
+		DefaultValue: 0,
+	},
 	NotifyFailoverMarkerInterval: DynamicDuration{
 		KeyName:      "history.NotifyFailoverMarkerInterval",
 		Description:  "NotifyFailoverMarkerInterval is determines the frequency to notify failover marker",
@@ -4360,6 +5313,16 @@ var DurationKeys = map[DurationKey]DynamicDuration{
 		Description:  "ReplicationTaskProcessorStartWait is the wait time before each task processing batch",
 		DefaultValue: time.Second * 5,
 	},
+	ReplicationDLQAutoRepairInterval: DynamicDuration{
+		KeyName:      "history.replicationDLQAutoRepairInterval",
+		Description:  "ReplicationDLQAutoRepairInterval is the interval at which the replication DLQ of a shard is scanned, and the first backoff of a DLQ task which can't be repaired",
+		DefaultValue: time.Minute * 5,
+	},
+	ReplicationDLQAutoRepairMaxBackoff: DynamicDuration{
+		KeyName:      "history.replicationDLQAutoRepairMaxBackoff",
+		Description:  "ReplicationDLQAutoRepairMaxBackoff is the max backoff of a replication DLQ task which can't be repaired",
+		DefaultValue: time.Hour * 6,
+	},
 	WorkerESProcessorFlushInterval: DynamicDuration{
 		KeyName:      "worker.ESProcessorFlushInterval",
 		Description:  "WorkerESProcessorFlushInterval is flush interval for esProcessor",
@@ -4385,6 +5348,61 @@ var DurationKeys = map[DurationKey]DynamicDuration{
 		Description:  "ESAnalyzerBufferWaitTime controls min time required to consider a worklow stuck",
 		DefaultValue: time.Minute * 30,
 	},
+	UsageAccountingReportInterval: DynamicDuration{
+		KeyName:      "system.usageAccountingReportInterval",
+		Description:  "UsageAccountingReportInterval is the interval the usage of domains is aggregated over and reported at",
+		DefaultValue: time.Minute,
+	},
+	UsageAccountingRetention: DynamicDuration{
+		KeyName:      "system.usageAccountingRetention",
+		Description:  "UsageAccountingRetention is how long the reported usage of domains is kept",
+		DefaultValue: time.Hour * 24 * 30,
+	},
+	ReshardingRefreshInterval: DynamicDuration{
+		KeyName:      "system.reshardingRefreshInterval",
+		Description:  "ReshardingRefreshInterval is the interval at which the services reload the state of the history shard count resharding",
+		DefaultValue: 10 * time.Second,
+	},
+	SlowRequestLogThreshold: DynamicDuration{
+		KeyName:      "system.slowRequestLogThreshold",
+		Description:  "SlowRequestLogThreshold is the latency above which an inbound request is logged with its latency breakdown, 0 disables the log",
+		DefaultValue: 0,
+	},
+	WatchdogSampleInterval: DynamicDuration{
+		KeyName:      "system.watchdogSampleInterval",
+		Description:  "WatchdogSampleInterval is the interval the resource leak watchdog samples the resources of a host at, 0 disables it",
+		DefaultValue: time.Minute,
+	},
+	LoadSheddingSampleInterval: DynamicDuration{
+		KeyName:      "system.loadSheddingSampleInterval",
+		Description:  "LoadSheddingSampleInterval is the interval the load shedding samples the CPU, memory and goroutines of a host at",
+		DefaultValue: time.Second,
+	},
+	GRPCKeepAliveTime: DynamicDuration{
+		KeyName:      "system.grpcKeepAliveTime",
+		Description:  "GRPCKeepAliveTime is the idle time after which the gRPC connections to the history and matching peers are pinged, 0 disables the keepalive, gRPC servers reject pings more frequent than 5m while there is no pending call",
+		DefaultValue: 0,
+	},
+	GRPCKeepAliveTimeout: DynamicDuration{
+		KeyName:      "system.grpcKeepAliveTimeout",
+		Description:  "GRPCKeepAliveTimeout is the time a gRPC connection waits for the answer to a keepalive ping before it is closed and redialed",
+		DefaultValue: time.Second * 20,
+	},
+	GRPCIdleConnectionTimeout: DynamicDuration{
+		KeyName:      "system.grpcIdleConnectionTimeout",
+		Description:  "GRPCIdleConnectionTimeout is how long the gRPC connections to a history or matching peer are kept after its last call",
+		DefaultValue: time.Minute * 10,
+	},
+	FrontendProberInterval: DynamicDuration{
+		KeyName:      "frontend.proberInterval",
+		Description:  "FrontendProberInterval is the interval the frontend prober exercises the workflow APIs at, 0 disables the prober",
+		DefaultValue: 0,
+	},
+	FrontendSLOLatencyThreshold: DynamicDuration{
+		KeyName:      "frontend.sloLatencyThreshold",
+		Description:  "FrontendSLOLatencyThreshold is the latency the frontend requests of a domain are expected to be served within, long polls are not accounted, see FrontendSLOLatencyTarget",
+		DefaultValue: time.Second,
+	},
 }
 
 var MapKeys = map[MapKey]DynamicMap{
@@ -4398,6 +5416,26 @@ var MapKeys = map[MapKey]DynamicMap{
 		Description:  "RequiredDomainDataKeys is the key for the list of data keys required in domain registration",
 		DefaultValue: nil,
 	},
+	DomainLogRPS: DynamicMap{
+		KeyName:      "system.domainLogRPS",
+		Description:  "DomainLogRPS is the max number of log messages per second emitted for a domain by a host, keyed by log level (debug, info, warn, error). Levels which are not set are not throttled and fatal logs are never throttled",
+		DefaultValue: nil,
+	},
+	HistoryResourcePoolRPS: DynamicMap{
+		KeyName:      "history.resourcePoolRPS",
+		Description:  "HistoryResourcePoolRPS is the max number of requests per second a history host serves for the domains of a resource pool, keyed by pool. The pools which are not set have the budget of the domains which are not pinned",
+		DefaultValue: nil,
+	},
+	HistoryResourcePoolTaskWorkerCount: DynamicMap{
+		KeyName:      "history.resourcePoolTaskWorkerCount",
+		Description:  "HistoryResourcePoolTaskWorkerCount is the number of workers of the task scheduler of a resource pool on a history host, keyed by pool. The pools which are not set have as many workers as the scheduler of the domains which are not pinned",
+		DefaultValue: nil,
+	},
+	MatchingResourcePoolRPS: DynamicMap{
+		KeyName:      "matching.resourcePoolRPS",
+		Description:  "MatchingResourcePoolRPS is the max number of user and of worker requests per second a matching host serves for the domains of a resource pool, keyed by pool. The pools which are not set have the budget of the domains which are not pinned",
+		DefaultValue: nil,
+	},
 	ValidSearchAttributes: DynamicMap{
 		KeyName:      "frontend.validSearchAttributes",
 		Description:  "ValidSearchAttributes is legal indexed keys that can be used in list APIs. When overriding, ensure to include the existing default attributes of the current release",
@@ -4412,6 +5450,11 @@ var MapKeys = map[MapKey]DynamicMap{
 			common.GetTaskPriority(common.LowPriorityClass, common.DefaultPrioritySubclass):     5,
 		}),
 	},
+	TaskSchedulerDomainWeights: DynamicMap{
+		KeyName:      "history.taskSchedulerDomainWeights",
+		Description:  "TaskSchedulerDomainWeights is the number of tasks of a domain in a shard which the fair task scheduler dispatches in a row, keyed by domain name. The domains which are not set have a weight of 1",
+		DefaultValue: nil,
+	},
 	QueueProcessorPendingTaskSplitThreshold: DynamicMap{
 		KeyName:      "history.queueProcessorPendingTaskSplitThreshold",
 		Description:  "QueueProcessorPendingTaskSplitThreshold is the threshold for the number of pending tasks per domain",
@@ -4439,6 +5482,11 @@ var ListKeys = map[ListKey]DynamicList{
 			},
 		},
 	},
+	AllIsolationGroups: {
+		KeyName:      "system.allIsolationGroups",
+		Description:  "AllIsolationGroups is the list of the isolation groups (e.g. zones) of the cluster. Matching only matches tasks with the pollers of their isolation group for these groups, the other groups are ignored",
+		DefaultValue: []interface{}{},
+	},
 }
 
 var _keyNames map[string]Key
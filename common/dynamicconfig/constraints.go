@@ -0,0 +1,281 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+type (
+	// Constraint restricts the values accepted by a dynamic config key, on top of its value type
+	Constraint interface {
+		Validate(value interface{}) error
+	}
+
+	// Invariant is a relation between dynamic config keys which must hold
+	// for the values resolved with the same filters
+	Invariant struct {
+		Keys        []Key
+		Description string
+		// Check receives the typed values of Keys, in the same order
+		Check func(values []interface{}) bool
+	}
+
+	intRange struct {
+		min int
+		max int
+	}
+
+	floatRange struct {
+		min float64
+		max float64
+	}
+
+	durationRange struct {
+		min time.Duration
+		max time.Duration
+	}
+
+	stringEnum struct {
+		allowed []string
+	}
+)
+
+var _constraints = map[Key]Constraint{
+	FrontendUserRPS:                      IntAtLeast(0),
+	FrontendMaxDomainUserRPSPerInstance:  IntAtLeast(0),
+	FrontendGlobalDomainUserRPS:          IntAtLeast(0),
+	MatchingUserRPS:                      IntAtLeast(0),
+	HistoryRPS:                           IntAtLeast(0),
+	MatchingNumTasklistWritePartitions:   IntRange(1, 1000),
+	MatchingNumTasklistReadPartitions:    IntRange(1, 1000),
+	MatchingForwarderMaxOutstandingPolls: IntAtLeast(0),
+	MatchingForwarderMaxOutstandingTasks: IntAtLeast(0),
+	MatchingForwarderMaxRatePerSecond:    IntAtLeast(0),
+	MatchingForwarderMaxChildrenPerNode:  IntAtLeast(1),
+	MatchingTaskDispatchRPS:              IntAtLeast(0),
+	TaskSchedulerType:                    IntRange(1, 2),
+	MatchingLongPollExpirationInterval:   DurationRange(time.Second, 10*time.Minute),
+	HistoryLongPollExpirationInterval:    DurationRange(time.Second, 10*time.Minute),
+	AdvancedVisibilityWritingMode:        OneOf("on", "off", "dual"),
+	HistoryArchivalStatus:                OneOf("enabled", "disabled"),
+	VisibilityArchivalStatus:             OneOf("enabled", "disabled"),
+	FeatureFlagRolloutPercentage:         IntRange(0, 100),
+}
+
+var _invariants = []Invariant{
+	{
+		Keys:        []Key{MatchingNumTasklistWritePartitions, MatchingNumTasklistReadPartitions},
+		Description: "matching.numTasklistWritePartitions must not be larger than matching.numTasklistReadPartitions, otherwise tasks written to the extra partitions are never polled",
+		Check: func(values []interface{}) bool {
+			return values[0].(int) <= values[1].(int)
+		},
+	},
+	{
+		Keys:        []Key{MatchingForwarderMaxRatePerSecond, MatchingTaskDispatchRPS},
+		Description: "matching.forwarderMaxRatePerSecond must not be larger than matching.taskDispatchRPS when the dispatch rate is overridden",
+		Check: func(values []interface{}) bool {
+			dispatchRPS := values[1].(int)
+			return dispatchRPS <= 0 || values[0].(int) <= dispatchRPS
+		},
+	},
+}
+
+// IntRange returns a constraint accepting int values in [min, max]
+func IntRange(min, max int) Constraint {
+	return &intRange{min: min, max: max}
+}
+
+// IntAtLeast returns a constraint accepting int values no less than min
+func IntAtLeast(min int) Constraint {
+	return IntRange(min, math.MaxInt)
+}
+
+// FloatRange returns a constraint accepting float values in [min, max]
+func FloatRange(min, max float64) Constraint {
+	return &floatRange{min: min, max: max}
+}
+
+// DurationRange returns a constraint accepting durations in [min, max]
+func DurationRange(min, max time.Duration) Constraint {
+	return &durationRange{min: min, max: max}
+}
+
+// OneOf returns a constraint accepting only the given string values
+func OneOf(allowed ...string) Constraint {
+	return &stringEnum{allowed: allowed}
+}
+
+// GetConstraint returns the constraint of the key, if any
+func GetConstraint(key Key) (Constraint, bool) {
+	c, ok := _constraints[key]
+	return c, ok
+}
+
+// ValidateValue checks that a raw value, as decoded from a config source, is valid for the key
+func ValidateValue(key Key, value interface{}) error {
+	typedValue, err := convertRawValue(key, value)
+	if err != nil {
+		return fmt.Errorf("invalid value %v for dynamic config %v: %v", value, key, err)
+	}
+	if c, ok := _constraints[key]; ok {
+		if err := c.Validate(typedValue); err != nil {
+			return fmt.Errorf("invalid value %v for dynamic config %v: %v", value, key, err)
+		}
+	}
+	return nil
+}
+
+// ValidateInvariants checks the invariants involving the key. resolve returns the raw value
+// of a key for the filters being validated, which is the default value if none is set.
+func ValidateInvariants(key Key, resolve func(Key) (interface{}, error)) error {
+	for _, invariant := range _invariants {
+		if !invariant.involves(key) {
+			continue
+		}
+		values := make([]interface{}, len(invariant.Keys))
+		for i, k := range invariant.Keys {
+			value, err := resolve(k)
+			if err != nil {
+				return err
+			}
+			if values[i], err = convertRawValue(k, value); err != nil {
+				return fmt.Errorf("invalid value %v for dynamic config %v: %v", value, k, err)
+			}
+		}
+		if !invariant.Check(values) {
+			return fmt.Errorf("invalid value for dynamic config %v: %v", key, invariant.Description)
+		}
+	}
+	return nil
+}
+
+func (i Invariant) involves(key Key) bool {
+	for _, k := range i.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// convertRawValue converts a value decoded from yaml or json to the type of the key.
+// Numbers may be decoded as either int or float64 and durations as strings.
+func convertRawValue(key Key, value interface{}) (interface{}, error) {
+	switch key.(type) {
+	case IntKey:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) {
+				return int(v), nil
+			}
+		}
+	case FloatKey:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		}
+	case BoolKey:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case StringKey:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case DurationKey:
+		switch v := value.(type) {
+		case time.Duration:
+			return v, nil
+		case string:
+			return time.ParseDuration(v)
+		}
+	case MapKey:
+		if v, ok := value.(map[string]interface{}); ok {
+			return v, nil
+		}
+	case ListKey:
+		if v, ok := value.([]interface{}); ok {
+			return v, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown key type: %T", key)
+	}
+	return nil, fmt.Errorf("value type %T does not match key type %T", value, key)
+}
+
+func (c *intRange) Validate(value interface{}) error {
+	v, ok := value.(int)
+	if !ok {
+		return fmt.Errorf("value type %T is not int", value)
+	}
+	if v < c.min || v > c.max {
+		if c.max == math.MaxInt {
+			return fmt.Errorf("value must be at least %v", c.min)
+		}
+		return fmt.Errorf("value must be between %v and %v", c.min, c.max)
+	}
+	return nil
+}
+
+func (c *floatRange) Validate(value interface{}) error {
+	v, ok := value.(float64)
+	if !ok {
+		return fmt.Errorf("value type %T is not float64", value)
+	}
+	if v < c.min || v > c.max {
+		return fmt.Errorf("value must be between %v and %v", c.min, c.max)
+	}
+	return nil
+}
+
+func (c *durationRange) Validate(value interface{}) error {
+	v, ok := value.(time.Duration)
+	if !ok {
+		return fmt.Errorf("value type %T is not duration", value)
+	}
+	if v < c.min || v > c.max {
+		return fmt.Errorf("value must be between %v and %v", c.min, c.max)
+	}
+	return nil
+}
+
+func (c *stringEnum) Validate(value interface{}) error {
+	v, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("value type %T is not string", value)
+	}
+	for _, allowed := range c.allowed {
+		if v == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("value must be one of [%v]", strings.Join(c.allowed, ", "))
+}
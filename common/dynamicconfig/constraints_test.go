@@ -0,0 +1,110 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log"
+)
+
+func TestValidateValue(t *testing.T) {
+	tests := map[string]struct {
+		key     Key
+		value   interface{}
+		wantErr bool
+	}{
+		"int in range":              {key: MatchingNumTasklistReadPartitions, value: 4},
+		"int decoded from json":     {key: MatchingNumTasklistReadPartitions, value: float64(4)},
+		"int below min":             {key: MatchingNumTasklistReadPartitions, value: 0, wantErr: true},
+		"int above max":             {key: MatchingNumTasklistReadPartitions, value: 1001, wantErr: true},
+		"fractional int":            {key: MatchingNumTasklistReadPartitions, value: 1.5, wantErr: true},
+		"negative rps":              {key: FrontendUserRPS, value: -1, wantErr: true},
+		"duration string":           {key: MatchingLongPollExpirationInterval, value: "30s"},
+		"duration value":            {key: MatchingLongPollExpirationInterval, value: time.Minute},
+		"duration out of range":     {key: MatchingLongPollExpirationInterval, value: "1ms", wantErr: true},
+		"invalid duration":          {key: MatchingLongPollExpirationInterval, value: "1 minute", wantErr: true},
+		"allowed enum":              {key: AdvancedVisibilityWritingMode, value: "dual"},
+		"unknown enum":              {key: AdvancedVisibilityWritingMode, value: "both", wantErr: true},
+		"wrong type":                {key: AdvancedVisibilityWritingMode, value: true, wantErr: true},
+		"key without constraint":    {key: EnableVisibilitySampling, value: false},
+		"float accepts int":         {key: TestGetFloat64PropertyKey, value: 1},
+		"wrong type, no constraint": {key: EnableVisibilitySampling, value: "false", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateValue(tt.key, tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateInvariants(t *testing.T) {
+	values := map[Key]interface{}{
+		MatchingNumTasklistWritePartitions: 4,
+		MatchingNumTasklistReadPartitions:  2,
+		MatchingForwarderMaxRatePerSecond:  10,
+		MatchingTaskDispatchRPS:            0,
+	}
+	resolve := func(k Key) (interface{}, error) {
+		return values[k], nil
+	}
+
+	err := ValidateInvariants(MatchingNumTasklistWritePartitions, resolve)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "matching.numTasklistWritePartitions must not be larger")
+
+	// dispatch rps is not overridden
+	assert.NoError(t, ValidateInvariants(MatchingForwarderMaxRatePerSecond, resolve))
+	values[MatchingTaskDispatchRPS] = 5
+	assert.Error(t, ValidateInvariants(MatchingTaskDispatchRPS, resolve))
+	values[MatchingTaskDispatchRPS] = 20
+	assert.NoError(t, ValidateInvariants(MatchingTaskDispatchRPS, resolve))
+
+	// keys without invariants are not resolved
+	assert.NoError(t, ValidateInvariants(EnableVisibilitySampling, nil))
+}
+
+func TestShippedConfigsAreValid(t *testing.T) {
+	files, err := filepath.Glob("../../config/dynamicconfig/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		doneCh := make(chan struct{})
+		_, err := NewFileBasedClient(&FileBasedClientConfig{
+			Filepath:     file,
+			PollInterval: time.Second * 5,
+		}, log.NewNoop(), doneCh)
+		assert.NoError(t, err, file)
+		close(doneCh)
+	}
+}
@@ -56,6 +56,8 @@ type FileBasedClientConfig struct {
 }
 
 type fileBasedClient struct {
+	UpdateBroadcaster
+
 	values          atomic.Value
 	lastUpdatedTime time.Time
 	config          *FileBasedClientConfig
@@ -204,6 +206,9 @@ func (fc *fileBasedClient) UpdateValue(name Key, value interface{}) error {
 	if err := ValidateKeyValuePair(name, value); err != nil {
 		return err
 	}
+	if err := ValidateValue(name, value); err != nil {
+		return err
+	}
 	keyName := name.String()
 	currentValues := make(map[string][]*constrainedValue)
 
@@ -279,46 +284,122 @@ func (fc *fileBasedClient) storeValues(newValues map[string][]*constrainedValue)
 			}
 		}
 	}
+	if err := fc.validateValues(newValues); err != nil {
+		return err
+	}
 
 	fc.values.Store(newValues)
 	fc.logger.Info("Updated dynamic config")
+	fc.NotifyUpdate()
+
+	var changeTimes []time.Time
+	for _, constrainedValues := range newValues {
+		for _, cv := range constrainedValues {
+			changeTimes = append(changeTimes, ScheduleChangeTimes(cv.filters())...)
+		}
+	}
+	fc.NotifyUpdateAt(changeTimes)
 	return nil
 }
 
 func (fc *fileBasedClient) getValueWithFilters(key Key, filters map[Filter]interface{}, defaultValue interface{}) (interface{}, error) {
-	keyName := key.String()
-	values := fc.values.Load().(map[string][]*constrainedValue)
-	found := false
-	for _, constrainedValue := range values[keyName] {
-		if len(constrainedValue.Constraints) == 0 {
+	return resolveValue(fc.values.Load().(map[string][]*constrainedValue), key, filters, defaultValue)
+}
+
+// GetValueWithMatchedFilters returns the value for the filters and the constraints of the value in effect
+func (fc *fileBasedClient) GetValueWithMatchedFilters(name Key, filters map[Filter]interface{}) (interface{}, map[Filter]interface{}, error) {
+	cv := resolveConstrainedValue(fc.values.Load().(map[string][]*constrainedValue), name, filters)
+	if cv == nil {
+		return name.DefaultValue(), nil, NotFoundError
+	}
+	return cv.Value, cv.filters(), nil
+}
+
+func resolveValue(values map[string][]*constrainedValue, key Key, filters map[Filter]interface{}, defaultValue interface{}) (interface{}, error) {
+	cv := resolveConstrainedValue(values, key, filters)
+	if cv == nil {
+		return defaultValue, NotFoundError
+	}
+	return cv.Value, nil
+}
+
+// resolveConstrainedValue returns the first value matching the filters, or the value without any
+// constraints if none matches
+func resolveConstrainedValue(values map[string][]*constrainedValue, key Key, filters map[Filter]interface{}) *constrainedValue {
+	var fallback *constrainedValue
+	for _, cv := range values[key.String()] {
+		if len(cv.Constraints) == 0 {
 			// special handling for default value (value without any constraints)
-			defaultValue = constrainedValue.Value
-			found = true
+			fallback = cv
 			continue
 		}
-		if match(constrainedValue, filters) {
-			return constrainedValue.Value, nil
+		if match(cv, filters) {
+			return cv
 		}
 	}
-	if !found {
-		return defaultValue, NotFoundError
+	return fallback
+}
+
+// validateValues checks the values of known keys against their constraints and invariants,
+// so that an invalid config is rejected as a whole instead of being partially applied.
+// Values of the wrong type are only logged, they are rejected when read and the default value is used.
+func (fc *fileBasedClient) validateValues(values map[string][]*constrainedValue) error {
+	for keyName, constrainedValues := range values {
+		key, err := GetKeyFromKeyName(keyName)
+		if err != nil {
+			// unknown keys are never read, so they are not validated
+			continue
+		}
+		for _, cv := range constrainedValues {
+			if _, err := convertRawValue(key, cv.Value); err != nil {
+				fc.logger.Warn("Invalid dynamic config value type", tag.Key(keyName), tag.Error(err))
+				continue
+			}
+			if err := ValidateValue(key, cv.Value); err != nil {
+				return err
+			}
+			filters := cv.filters()
+			if err := ValidateSchedule(filters); err != nil {
+				return fmt.Errorf("invalid schedule of %v: %v", keyName, err)
+			}
+			err := ValidateInvariants(key, func(k Key) (interface{}, error) {
+				value, _ := resolveValue(values, k, filters, k.DefaultValue())
+				if _, err := convertRawValue(k, value); err != nil {
+					return k.DefaultValue(), nil
+				}
+				return value, nil
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
-	return defaultValue, nil
+	return nil
 }
 
 // match will return true if the constraints matches the filters or any subsets
+// and the current time is within the schedule of the value
 func match(v *constrainedValue, filters map[Filter]interface{}) bool {
-	if len(v.Constraints) > len(filters) {
-		return false
-	}
-
+	scheduled := false
 	for constrain, constrainedValue := range v.Constraints {
 		constrainKey := ParseFilter(constrain)
+		if IsScheduleFilter(constrainKey) {
+			scheduled = true
+			continue
+		}
 		if filters[constrainKey] == nil || filters[constrainKey] != constrainedValue {
 			return false
 		}
 	}
-	return true
+	return !scheduled || MatchSchedule(v.filters(), time.Now())
+}
+
+func (cv *constrainedValue) filters() map[Filter]interface{} {
+	filters := make(map[Filter]interface{}, len(cv.Constraints))
+	for name, value := range cv.Constraints {
+		filters[ParseFilter(name)] = value
+	}
+	return filters
 }
 
 func convertKeyTypeToString(v interface{}) (interface{}, error) {
@@ -61,6 +61,28 @@ func (s *fileBasedClientSuite) SetupTest() {
 	s.Assertions = require.New(s.T())
 }
 
+func (s *fileBasedClientSuite) TestStoreValues_ConstraintViolation() {
+	fc := s.client.(*fileBasedClient)
+	err := fc.storeValues(map[string][]*constrainedValue{
+		MatchingNumTasklistReadPartitions.String(): {{Value: 0}},
+	})
+	s.Error(err)
+
+	err = fc.storeValues(map[string][]*constrainedValue{
+		MatchingNumTasklistReadPartitions.String(): {{Value: 2}},
+		MatchingNumTasklistWritePartitions.String(): {
+			{Value: 2},
+			{Value: 4, Constraints: map[string]interface{}{"domainName": "samples-domain"}},
+		},
+	})
+	s.Error(err)
+
+	// the previous values are kept
+	v, err := s.client.GetValue(TestGetBoolPropertyKey)
+	s.NoError(err)
+	s.Equal(false, v)
+}
+
 func (s *fileBasedClientSuite) TestGetValue() {
 	v, err := s.client.GetValue(TestGetBoolPropertyKey)
 	s.NoError(err)
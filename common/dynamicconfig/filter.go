@@ -24,7 +24,7 @@ package dynamicconfig
 type Filter int
 
 func (f Filter) String() string {
-	if f <= UnknownFilter || f > WorkflowID {
+	if f <= UnknownFilter || f >= LastFilterTypeForTest {
 		return filters[UnknownFilter]
 	}
 	return filters[f]
@@ -48,6 +48,14 @@ func ParseFilter(filterName string) Filter {
 		return WorkflowID
 	case "workflowType":
 		return WorkflowType
+	case "featureFlag":
+		return FeatureFlagName
+	case "activeFrom":
+		return ActiveFrom
+	case "activeUntil":
+		return ActiveUntil
+	case "apiName":
+		return APIName
 	default:
 		return UnknownFilter
 	}
@@ -63,6 +71,10 @@ var filters = []string{
 	"clusterName",
 	"workflowID",
 	"workflowType",
+	"featureFlag",
+	"activeFrom",
+	"activeUntil",
+	"apiName",
 }
 
 const (
@@ -83,6 +95,14 @@ const (
 	WorkflowID
 	// WorkflowType is the workflow type name
 	WorkflowType
+	// FeatureFlagName is the name of a feature flag
+	FeatureFlagName
+	// ActiveFrom is the RFC3339 time a value is in effect from, it is matched against the current time
+	ActiveFrom
+	// ActiveUntil is the RFC3339 time a value is in effect until, it is matched against the current time
+	ActiveUntil
+	// APIName is the name of an RPC method of a service, e.g. StartWorkflowExecution
+	APIName
 
 	// LastFilterTypeForTest must be the last one in this const group for testing purpose
 	LastFilterTypeForTest
@@ -146,3 +166,17 @@ func WorkflowTypeFilter(name string) FilterOption {
 		filterMap[WorkflowType] = name
 	}
 }
+
+// FeatureFlagFilter filters by feature flag name
+func FeatureFlagFilter(name string) FilterOption {
+	return func(filterMap map[Filter]interface{}) {
+		filterMap[FeatureFlagName] = name
+	}
+}
+
+// APINameFilter filters by the name of an RPC method
+func APINameFilter(name string) FilterOption {
+	return func(filterMap map[Filter]interface{}) {
+		filterMap[APIName] = name
+	}
+}
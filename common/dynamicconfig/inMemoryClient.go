@@ -30,6 +30,7 @@ import (
 
 type inMemoryClient struct {
 	sync.RWMutex
+	UpdateBroadcaster
 
 	globalValues map[Key]interface{}
 }
@@ -43,9 +44,10 @@ func NewInMemoryClient() Client {
 
 func (mc *inMemoryClient) SetValue(key Key, value interface{}) {
 	mc.Lock()
-	defer mc.Unlock()
-
 	mc.globalValues[key] = value
+	mc.Unlock()
+
+	mc.NotifyUpdate()
 }
 
 func (mc *inMemoryClient) GetValue(key Key) (interface{}, error) {
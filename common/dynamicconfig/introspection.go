@@ -0,0 +1,126 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"sort"
+	"time"
+)
+
+type (
+	// MatchedValueClient is implemented by clients able to report which value is in effect for a set of filters
+	MatchedValueClient interface {
+		// GetValueWithMatchedFilters returns the value for the filters and the filters of the value in effect.
+		// It returns NotFoundError if no value applies and the default value is in effect.
+		GetValueWithMatchedFilters(name Key, filters map[Filter]interface{}) (interface{}, map[Filter]interface{}, error)
+	}
+
+	// EffectiveValue describes the value of a key in effect for a set of filters
+	EffectiveValue struct {
+		Name         string      `json:"name"`
+		DefaultValue interface{} `json:"defaultValue"`
+		Value        interface{} `json:"value"`
+		IsDefault    bool        `json:"isDefault"`
+		// MatchedFilters are the filters of the value in effect, nil if unknown or if the default is in effect
+		MatchedFilters map[string]interface{} `json:"matchedFilters,omitempty"`
+		Error          string                 `json:"error,omitempty"`
+	}
+)
+
+var _ MatchedValueClient = (*fileBasedClient)(nil)
+
+// EffectiveValues resolves every production key for the filters, including the filters of the collection
+func (c *Collection) EffectiveValues(opts ...FilterOption) []*EffectiveValue {
+	return c.effectiveValues(c.toFilterMap(opts...))
+}
+
+// EffectiveValuesWithFilters is EffectiveValues for filters which are only known at runtime, e.g. received by an API.
+// The filters of the collection are added to a copy of the filters.
+func (c *Collection) EffectiveValuesWithFilters(filters map[Filter]interface{}) []*EffectiveValue {
+	return c.effectiveValues(c.toFilterMap(func(filterMap map[Filter]interface{}) {
+		for filter, value := range filters {
+			filterMap[filter] = value
+		}
+	}))
+}
+
+func (c *Collection) effectiveValues(filters map[Filter]interface{}) []*EffectiveValue {
+	keys := ListAllProductionKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	result := make([]*EffectiveValue, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, c.effectiveValue(key, filters))
+	}
+	return result
+}
+
+func (c *Collection) effectiveValue(key Key, filters map[Filter]interface{}) *EffectiveValue {
+	ev := &EffectiveValue{
+		Name:         key.String(),
+		DefaultValue: printableValue(key.DefaultValue()),
+	}
+
+	var value interface{}
+	var matched map[Filter]interface{}
+	var err error
+	if client, ok := c.client.(MatchedValueClient); ok {
+		value, matched, err = client.GetValueWithMatchedFilters(key, filters)
+	} else {
+		value, err = c.client.GetValueWithFilters(key, filters)
+	}
+
+	if err != nil {
+		if err != NotFoundError {
+			ev.Error = err.Error()
+		}
+		ev.Value = ev.DefaultValue
+		ev.IsDefault = true
+		return ev
+	}
+	if validationErr := ValidateValue(key, value); validationErr != nil {
+		// the typed getters reject such values and fall back to the default value
+		ev.Error = validationErr.Error()
+		ev.Value = ev.DefaultValue
+		ev.IsDefault = true
+		return ev
+	}
+
+	ev.Value = printableValue(value)
+	if matched != nil {
+		ev.MatchedFilters = make(map[string]interface{}, len(matched))
+		for filter, filterValue := range matched {
+			ev.MatchedFilters[filter.String()] = filterValue
+		}
+	}
+	return ev
+}
+
+func printableValue(value interface{}) interface{} {
+	if d, ok := value.(time.Duration); ok {
+		return d.String()
+	}
+	return value
+}
@@ -0,0 +1,96 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log"
+)
+
+func newTestEffectiveValuesCollection(t *testing.T) *Collection {
+	client := &fileBasedClient{logger: log.NewNoop()}
+	err := client.storeValues(map[string][]*constrainedValue{
+		MatchingNumTasklistReadPartitions.String(): {
+			{Value: 2},
+			{Value: 4, Constraints: map[string]interface{}{"domainName": "samples-domain", "taskListName": "tl"}},
+		},
+		MatchingLongPollExpirationInterval.String(): {
+			{Value: "not a duration"},
+		},
+	})
+	require.NoError(t, err)
+	return NewCollection(client, log.NewNoop(), ClusterNameFilter("cluster0"))
+}
+
+func TestEffectiveValues(t *testing.T) {
+	collection := newTestEffectiveValuesCollection(t)
+
+	values := map[string]*EffectiveValue{}
+	for _, value := range collection.EffectiveValues(DomainFilter("samples-domain"), TaskListFilter("tl")) {
+		values[value.Name] = value
+	}
+	assert.Len(t, values, len(ListAllProductionKeys()))
+
+	readPartitions := values[MatchingNumTasklistReadPartitions.String()]
+	assert.Equal(t, 4, readPartitions.Value)
+	assert.Equal(t, 1, readPartitions.DefaultValue)
+	assert.False(t, readPartitions.IsDefault)
+	assert.Equal(t, map[string]interface{}{"domainName": "samples-domain", "taskListName": "tl"}, readPartitions.MatchedFilters)
+
+	writePartitions := values[MatchingNumTasklistWritePartitions.String()]
+	assert.True(t, writePartitions.IsDefault)
+	assert.Equal(t, 1, writePartitions.Value)
+	assert.Empty(t, writePartitions.Error)
+
+	longPoll := values[MatchingLongPollExpirationInterval.String()]
+	assert.True(t, longPoll.IsDefault)
+	assert.Equal(t, "1m0s", longPoll.Value)
+	assert.NotEmpty(t, longPoll.Error)
+
+	// a different task list falls back to the value without filters
+	for _, value := range collection.EffectiveValues(DomainFilter("samples-domain"), TaskListFilter("other")) {
+		if value.Name == MatchingNumTasklistReadPartitions.String() {
+			assert.Equal(t, 2, value.Value)
+			assert.Empty(t, value.MatchedFilters)
+			assert.False(t, value.IsDefault)
+		}
+	}
+}
+
+func TestEffectiveValuesWithFilters(t *testing.T) {
+	collection := newTestEffectiveValuesCollection(t)
+	filters := map[Filter]interface{}{DomainName: "samples-domain", TaskListName: "tl"}
+
+	for _, value := range collection.EffectiveValuesWithFilters(filters) {
+		if value.Name == MatchingNumTasklistReadPartitions.String() {
+			assert.Equal(t, 4, value.Value)
+			assert.Equal(t, map[string]interface{}{"domainName": "samples-domain", "taskListName": "tl"}, value.MatchedFilters)
+		}
+	}
+	// the filters of the collection are not added to the given filters
+	assert.Equal(t, map[Filter]interface{}{DomainName: "samples-domain", TaskListName: "tl"}, filters)
+}
@@ -0,0 +1,182 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+)
+
+var _ dynamicconfig.RemoteSource = (*consulSource)(nil)
+var _ dynamicconfig.RemoteWatcher = (*consulSource)(nil)
+
+const (
+	consulKVPath      = "/v1/kv/"
+	consulIndexHeader = "X-Consul-Index"
+	consulTokenHeader = "X-Consul-Token"
+	consulDefaultWait = 5 * time.Minute
+)
+
+type (
+	// ConsulConfig is the config for the Consul based dynamic config client.
+	// The config is read from a single key of the Consul KV store which holds a document in the same yaml format
+	// as the file based client. Changes are watched with blocking queries.
+	ConsulConfig struct {
+		dynamicconfig.RemoteClientConfig `yaml:",inline"`
+
+		Address    string `yaml:"address"`
+		Key        string `yaml:"key"`
+		Datacenter string `yaml:"datacenter"`
+		// Token is the ACL token, if ACLs are enabled in Consul
+		Token string `yaml:"token"`
+		// WatchWaitTime is the max time a blocking query waits for a change, 5m by default
+		WatchWaitTime time.Duration `yaml:"watchWaitTime"`
+		TLS           TLSConfig     `yaml:"tls"`
+	}
+
+	consulSource struct {
+		httpClient *http.Client
+		address    string
+		key        string
+		datacenter string
+		token      string
+		waitTime   time.Duration
+	}
+)
+
+// NewConsulSource creates a RemoteSource which reads the dynamic config from a Consul key and watches it for changes
+func NewConsulSource(config *ConsulConfig) (dynamicconfig.RemoteSource, error) {
+	if config == nil || config.Address == "" || config.Key == "" {
+		return nil, errors.New("address and key must be set for Consul dynamic config client")
+	}
+	httpClient, err := newHTTPClient(config.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TLS config for Consul dynamic config client: %v", err)
+	}
+	address := config.Address
+	if !strings.Contains(address, "://") {
+		scheme := "http://"
+		if config.TLS.Enabled {
+			scheme = "https://"
+		}
+		address = scheme + address
+	}
+	source := newConsulSource(httpClient, address, config.Key)
+	source.datacenter = config.Datacenter
+	source.token = config.Token
+	if config.WatchWaitTime > 0 {
+		source.waitTime = config.WatchWaitTime
+	}
+	return source, nil
+}
+
+func newConsulSource(httpClient *http.Client, address string, key string) *consulSource {
+	return &consulSource{
+		httpClient: httpClient,
+		address:    strings.TrimSuffix(address, "/"),
+		key:        strings.TrimPrefix(key, "/"),
+		waitTime:   consulDefaultWait,
+	}
+}
+
+func (s *consulSource) Fetch(ctx context.Context) ([]byte, error) {
+	content, _, err := s.get(ctx, 0)
+	return content, err
+}
+
+// Watch uses blocking queries, which return as soon as the modify index of the key is larger than the index
+// of the query, or after the wait time passed
+func (s *consulSource) Watch(ctx context.Context, changeCh chan<- struct{}) error {
+	var index uint64
+	for {
+		_, newIndex, err := s.get(ctx, index)
+		if err != nil {
+			return err
+		}
+		if newIndex != index {
+			select {
+			case changeCh <- struct{}{}:
+			default:
+				// a reload is already pending
+			}
+		}
+		if newIndex < index {
+			// the index went backwards, e.g. after the key was deleted and recreated, so start over
+			newIndex = 0
+		}
+		index = newIndex
+	}
+}
+
+// get reads the key, blocking until its modify index is larger than index if index is not 0
+func (s *consulSource) get(ctx context.Context, index uint64) ([]byte, uint64, error) {
+	query := url.Values{}
+	query.Set("raw", "")
+	if s.datacenter != "" {
+		query.Set("dc", s.datacenter)
+	}
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", fmt.Sprintf("%vs", int(s.waitTime.Seconds())))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.address+consulKVPath+s.key+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if s.token != "" {
+		req.Header.Set(consulTokenHeader, s.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, 0, fmt.Errorf("consul key %v not found", s.key)
+	default:
+		return nil, 0, fmt.Errorf("consul request for key %v failed with status %v", s.key, resp.Status)
+	}
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	newIndex, err := strconv.ParseUint(resp.Header.Get(consulIndexHeader), 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid consul index %q: %v", resp.Header.Get(consulIndexHeader), err)
+	}
+	return content, newIndex, nil
+}
@@ -0,0 +1,124 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulSource_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, consulKVPath+"cadence/dynamicconfig", r.URL.Path)
+		require.Equal(t, "dc1", r.URL.Query().Get("dc"))
+		require.Equal(t, "acl-token", r.Header.Get(consulTokenHeader))
+		w.Header().Set(consulIndexHeader, "7")
+		fmt.Fprint(w, "content")
+	}))
+	defer server.Close()
+
+	source, err := NewConsulSource(&ConsulConfig{
+		Address:    server.URL,
+		Key:        "/cadence/dynamicconfig",
+		Datacenter: "dc1",
+		Token:      "acl-token",
+	})
+	require.NoError(t, err)
+	content, err := source.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []byte("content"), content)
+}
+
+func TestConsulSource_FetchKeyNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	source := newConsulSource(http.DefaultClient, server.URL, "cadence/dynamicconfig")
+	_, err := source.Fetch(context.Background())
+	require.Error(t, err)
+}
+
+func TestConsulSource_Watch(t *testing.T) {
+	indexes := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		index := r.URL.Query().Get("index")
+		indexes <- index
+		switch index {
+		case "":
+			w.Header().Set(consulIndexHeader, "5")
+		case "5":
+			require.Equal(t, "1s", r.URL.Query().Get("wait"))
+			w.Header().Set(consulIndexHeader, "6")
+		default:
+			<-r.Context().Done()
+			return
+		}
+		fmt.Fprint(w, "content")
+	}))
+	defer server.Close()
+
+	source := newConsulSource(http.DefaultClient, server.URL, "cadence/dynamicconfig")
+	source.waitTime = time.Second
+	ctx, cancel := context.WithCancel(context.Background())
+	changeCh := make(chan struct{}, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- source.Watch(ctx, changeCh)
+	}()
+
+	for _, expected := range []string{"", "5", "6"} {
+		select {
+		case index := <-indexes:
+			require.Equal(t, expected, index)
+		case <-time.After(time.Second * 5):
+			require.Fail(t, "no blocking query received")
+		}
+	}
+	select {
+	case <-changeCh:
+	default:
+		require.Fail(t, "no change notification received")
+	}
+	cancel()
+	require.Error(t, <-errCh)
+}
+
+func TestNewConsulSource_InvalidConfig(t *testing.T) {
+	_, err := NewConsulSource(&ConsulConfig{Key: "cadence/dynamicconfig"})
+	require.Error(t, err)
+
+	_, err = NewConsulSource(&ConsulConfig{
+		Address: "localhost:8500",
+		Key:     "cadence/dynamicconfig",
+		TLS:     TLSConfig{Enabled: true, CaFile: "/does/not/exist"},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,299 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+)
+
+var _ dynamicconfig.RemoteSource = (*etcdSource)(nil)
+var _ dynamicconfig.RemoteWatcher = (*etcdSource)(nil)
+
+const (
+	etcdRangePath        = "/v3/kv/range"
+	etcdWatchPath        = "/v3/watch"
+	etcdAuthenticatePath = "/v3/auth/authenticate"
+)
+
+type (
+	// EtcdConfig is the config for the etcd based dynamic config client.
+	// The config is read from a single key which holds a document in the same yaml format as the file based client.
+	// The client talks to the JSON gateway that etcd v3 serves on its client port.
+	EtcdConfig struct {
+		dynamicconfig.RemoteClientConfig `yaml:",inline"`
+
+		Endpoints []string `yaml:"endpoints"`
+		Key       string   `yaml:"key"`
+		// Username and Password are used to authenticate if auth is enabled in etcd
+		Username string    `yaml:"username"`
+		Password string    `yaml:"password"`
+		TLS      TLSConfig `yaml:"tls"`
+	}
+
+	etcdSource struct {
+		httpClient *http.Client
+		endpoints  []string
+		key        string
+		// index of the endpoint to use next, rotated on failures
+		endpointIdx int32
+
+		username string
+		password string
+		// tokenLock guards the auth token, which is fetched lazily and again after it expired
+		tokenLock sync.Mutex
+		token     string
+	}
+
+	etcdKeyValue struct {
+		Value string `json:"value"`
+	}
+
+	etcdAuthenticateResponse struct {
+		Token string `json:"token"`
+	}
+
+	etcdRangeResponse struct {
+		Kvs []etcdKeyValue `json:"kvs"`
+	}
+
+	etcdWatchResponse struct {
+		Result struct {
+			Created  bool              `json:"created"`
+			Canceled bool              `json:"canceled"`
+			Events   []json.RawMessage `json:"events"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+)
+
+// NewEtcdSource creates a RemoteSource which reads the dynamic config from an etcd key and watches it for changes
+func NewEtcdSource(config *EtcdConfig) (dynamicconfig.RemoteSource, error) {
+	if config == nil || len(config.Endpoints) == 0 || config.Key == "" {
+		return nil, errors.New("endpoints and key must be set for etcd dynamic config client")
+	}
+	httpClient, err := newHTTPClient(config.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TLS config for etcd dynamic config client: %v", err)
+	}
+	source := newEtcdSource(httpClient, config.Endpoints, config.Key)
+	source.username = config.Username
+	source.password = config.Password
+	return source, nil
+}
+
+func newEtcdSource(httpClient *http.Client, endpoints []string, key string) *etcdSource {
+	trimmed := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		trimmed = append(trimmed, strings.TrimSuffix(endpoint, "/"))
+	}
+	return &etcdSource{
+		httpClient: httpClient,
+		endpoints:  trimmed,
+		key:        key,
+	}
+}
+
+func (s *etcdSource) Fetch(ctx context.Context) ([]byte, error) {
+	var lastErr error
+	for range s.endpoints {
+		content, err := s.fetchFrom(ctx, s.currentEndpoint())
+		if err == nil {
+			return content, nil
+		}
+		lastErr = err
+		s.rotateEndpoint()
+	}
+	return nil, lastErr
+}
+
+func (s *etcdSource) Watch(ctx context.Context, changeCh chan<- struct{}) error {
+	endpoint := s.currentEndpoint()
+	resp, err := s.post(ctx, endpoint, etcdWatchPath, map[string]interface{}{
+		"create_request": map[string]interface{}{
+			"key": s.encodedKey(),
+		},
+	})
+	if err != nil {
+		s.rotateEndpoint()
+		return err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var watchResp etcdWatchResponse
+		if err := decoder.Decode(&watchResp); err != nil {
+			if err == io.EOF {
+				return errors.New("etcd watch stream closed")
+			}
+			return err
+		}
+		if watchResp.Error != nil {
+			return fmt.Errorf("etcd watch failed: %v", watchResp.Error.Message)
+		}
+		if watchResp.Result.Canceled {
+			return errors.New("etcd watch canceled")
+		}
+		if len(watchResp.Result.Events) == 0 {
+			continue
+		}
+		select {
+		case changeCh <- struct{}{}:
+		default:
+			// a reload is already pending
+		}
+	}
+}
+
+func (s *etcdSource) fetchFrom(ctx context.Context, endpoint string) ([]byte, error) {
+	resp, err := s.post(ctx, endpoint, etcdRangePath, map[string]interface{}{
+		"key": s.encodedKey(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rangeResp etcdRangeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rangeResp); err != nil {
+		return nil, err
+	}
+	if len(rangeResp.Kvs) == 0 {
+		return nil, fmt.Errorf("etcd key %v not found", s.key)
+	}
+	return base64.StdEncoding.DecodeString(rangeResp.Kvs[0].Value)
+}
+
+func (s *etcdSource) post(ctx context.Context, endpoint string, path string, body interface{}) (*http.Response, error) {
+	url := endpoint + path
+	token, err := s.getToken(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.doPost(ctx, url, body, token)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && token != "" {
+		// the token expired, authenticate again
+		resp.Body.Close()
+		s.resetToken(token)
+		if token, err = s.getToken(ctx, endpoint); err != nil {
+			return nil, err
+		}
+		if resp, err = s.doPost(ctx, url, body, token); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("etcd request to %v failed with status %v", url, resp.Status)
+	}
+	return resp, nil
+}
+
+func (s *etcdSource) doPost(ctx context.Context, url string, body interface{}, token string) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	return s.httpClient.Do(req)
+}
+
+// getToken returns the auth token, authenticating against the endpoint if there is none yet.
+// Without credentials no token is used.
+func (s *etcdSource) getToken(ctx context.Context, endpoint string) (string, error) {
+	if s.username == "" {
+		return "", nil
+	}
+	s.tokenLock.Lock()
+	defer s.tokenLock.Unlock()
+	if s.token != "" {
+		return s.token, nil
+	}
+
+	resp, err := s.doPost(ctx, endpoint+etcdAuthenticatePath, map[string]string{
+		"name":     s.username,
+		"password": s.password,
+	}, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("etcd authentication failed with status %v", resp.Status)
+	}
+	var authResp etcdAuthenticateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		return "", err
+	}
+	if authResp.Token == "" {
+		return "", errors.New("etcd authentication returned no token")
+	}
+	s.token = authResp.Token
+	return s.token, nil
+}
+
+// resetToken drops the token unless another request already replaced it
+func (s *etcdSource) resetToken(token string) {
+	s.tokenLock.Lock()
+	defer s.tokenLock.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+func (s *etcdSource) encodedKey() string {
+	return base64.StdEncoding.EncodeToString([]byte(s.key))
+}
+
+func (s *etcdSource) currentEndpoint() string {
+	idx := atomic.LoadInt32(&s.endpointIdx)
+	return s.endpoints[int(idx)%len(s.endpoints)]
+}
+
+func (s *etcdSource) rotateEndpoint() {
+	atomic.AddInt32(&s.endpointIdx, 1)
+}
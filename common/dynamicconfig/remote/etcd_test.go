@@ -0,0 +1,137 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package remote
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEtcdSource_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, etcdRangePath, r.URL.Path)
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, base64.StdEncoding.EncodeToString([]byte("cadence/dynamicconfig")), req["key"])
+
+		fmt.Fprintf(w, `{"kvs":[{"value":%q}]}`, base64.StdEncoding.EncodeToString([]byte("content")))
+	}))
+	defer server.Close()
+
+	source := newEtcdSource(http.DefaultClient, []string{"http://127.0.0.1:0", server.URL + "/"}, "cadence/dynamicconfig")
+	content, err := source.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []byte("content"), content)
+
+	// the failed endpoint is skipped afterwards
+	require.Equal(t, server.URL, source.currentEndpoint())
+}
+
+func TestEtcdSource_FetchKeyNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"header":{}}`)
+	}))
+	defer server.Close()
+
+	source := newEtcdSource(http.DefaultClient, []string{server.URL}, "cadence/dynamicconfig")
+	_, err := source.Fetch(context.Background())
+	require.Error(t, err)
+}
+
+func TestEtcdSource_Watch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, etcdWatchPath, r.URL.Path)
+		fmt.Fprintln(w, `{"result":{"created":true}}`)
+		fmt.Fprintln(w, `{"result":{"events":[{"kv":{}}]}}`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	source := newEtcdSource(http.DefaultClient, []string{server.URL}, "cadence/dynamicconfig")
+	ctx, cancel := context.WithCancel(context.Background())
+	changeCh := make(chan struct{}, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- source.Watch(ctx, changeCh)
+	}()
+
+	select {
+	case <-changeCh:
+	case <-time.After(time.Second * 5):
+		require.Fail(t, "no change notification received")
+	}
+	cancel()
+	require.Error(t, <-errCh)
+}
+
+func TestNewEtcdSource_InvalidConfig(t *testing.T) {
+	_, err := NewEtcdSource(&EtcdConfig{Key: "cadence/dynamicconfig"})
+	require.Error(t, err)
+}
+
+func TestEtcdSource_Auth(t *testing.T) {
+	authCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == etcdAuthenticatePath {
+			var req map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			require.Equal(t, map[string]string{"name": "user", "password": "secret"}, req)
+			authCount++
+			fmt.Fprintf(w, `{"token":"token-%v"}`, authCount)
+			return
+		}
+		// the first token is treated as expired
+		if r.Header.Get("Authorization") != "token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"kvs":[{"value":%q}]}`, base64.StdEncoding.EncodeToString([]byte("content")))
+	}))
+	defer server.Close()
+
+	source, err := NewEtcdSource(&EtcdConfig{
+		Endpoints: []string{server.URL},
+		Key:       "cadence/dynamicconfig",
+		Username:  "user",
+		Password:  "secret",
+	})
+	require.NoError(t, err)
+	content, err := source.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []byte("content"), content)
+	require.Equal(t, 2, authCount)
+
+	// the token is reused
+	_, err = source.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, authCount)
+}
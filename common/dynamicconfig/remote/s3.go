@@ -0,0 +1,113 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+)
+
+var _ dynamicconfig.RemoteSource = (*s3Source)(nil)
+
+type (
+	// S3Config is the config for the S3 based dynamic config client.
+	// The config is read from a single object which uses the same yaml format as the file based client.
+	S3Config struct {
+		dynamicconfig.RemoteClientConfig `yaml:",inline"`
+
+		Region           string  `yaml:"region"`
+		Endpoint         *string `yaml:"endpoint"`
+		S3ForcePathStyle bool    `yaml:"s3ForcePathStyle"`
+		Bucket           string  `yaml:"bucket"`
+		Key              string  `yaml:"key"`
+	}
+
+	s3Source struct {
+		s3cli  s3iface.S3API
+		bucket string
+		key    string
+
+		// etag and content of the last fetched version of the object,
+		// so that unchanged objects are not downloaded again
+		etag    *string
+		content []byte
+	}
+)
+
+// NewS3Source creates a RemoteSource which reads the dynamic config from an S3 object
+func NewS3Source(config *S3Config) (dynamicconfig.RemoteSource, error) {
+	if config == nil || config.Bucket == "" || config.Key == "" {
+		return nil, errors.New("bucket and key must be set for S3 dynamic config client")
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:         config.Endpoint,
+		Region:           aws.String(config.Region),
+		S3ForcePathStyle: aws.Bool(config.S3ForcePathStyle),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newS3Source(s3.New(sess), config.Bucket, config.Key), nil
+}
+
+func newS3Source(s3cli s3iface.S3API, bucket string, key string) *s3Source {
+	return &s3Source{
+		s3cli:  s3cli,
+		bucket: bucket,
+		key:    key,
+	}
+}
+
+func (s *s3Source) Fetch(ctx context.Context) ([]byte, error) {
+	resp, err := s.s3cli.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.key),
+		IfNoneMatch: s.etag,
+	})
+	if err != nil {
+		if reqErr, ok := err.(awserr.RequestFailure); ok && reqErr.StatusCode() == http.StatusNotModified {
+			return s.content, nil
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.etag = resp.ETag
+	s.content = content
+	return content, nil
+}
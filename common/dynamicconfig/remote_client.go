@@ -0,0 +1,220 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uber-go/tally"
+	"gopkg.in/yaml.v2"
+
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/types"
+)
+
+var _ Client = (*remoteClient)(nil)
+
+const (
+	remoteMinPollInterval   = time.Second * 5
+	remoteWatchRetryBackoff = time.Second * 5
+
+	remoteLastSuccessfulLoadMetric = "dynamic_config_last_successful_load"
+	remoteLoadErrorsMetric         = "dynamic_config_load_errors"
+)
+
+type (
+	// RemoteSource provides the content of a dynamic config document stored in a remote backend.
+	// The document uses the same yaml format as the file based client.
+	RemoteSource interface {
+		// Fetch returns the current content of the document
+		Fetch(ctx context.Context) ([]byte, error)
+	}
+
+	// RemoteWatcher can optionally be implemented by a RemoteSource that is able to notify about changes,
+	// so that they are picked up without waiting for the next poll
+	RemoteWatcher interface {
+		// Watch blocks until ctx is done or the watch fails, and sends to changeCh whenever the document may have changed
+		Watch(ctx context.Context, changeCh chan<- struct{}) error
+	}
+
+	// RemoteClientConfig is the config shared by all remote dynamic config clients
+	RemoteClientConfig struct {
+		PollInterval time.Duration `yaml:"pollInterval"`
+		FetchTimeout time.Duration `yaml:"fetchTimeout"`
+	}
+
+	remoteClient struct {
+		*fileBasedClient
+
+		source   RemoteSource
+		config   *RemoteClientConfig
+		checksum []byte
+
+		lastSuccessfulLoad tally.Gauge
+		loadErrors         tally.Counter
+	}
+)
+
+// NewRemoteClient creates a dynamic config client which reads the config from a remote source.
+// The source is polled periodically, and watched in addition if it implements RemoteWatcher.
+// A new version of the config is only swapped in after it has been fetched and decoded successfully.
+func NewRemoteClient(
+	source RemoteSource,
+	config *RemoteClientConfig,
+	logger log.Logger,
+	scope tally.Scope,
+	doneCh chan struct{},
+) (Client, error) {
+	if err := validateRemoteClientConfig(config); err != nil {
+		return nil, err
+	}
+	if scope == nil {
+		scope = tally.NoopScope
+	}
+
+	client := &remoteClient{
+		fileBasedClient: &fileBasedClient{
+			doneCh: doneCh,
+			logger: logger,
+		},
+		source:             source,
+		config:             config,
+		lastSuccessfulLoad: scope.Gauge(remoteLastSuccessfulLoadMetric),
+		loadErrors:         scope.Counter(remoteLoadErrorsMetric),
+	}
+	if err := client.update(); err != nil {
+		return nil, err
+	}
+
+	changeCh := make(chan struct{}, 1)
+	if watcher, ok := source.(RemoteWatcher); ok {
+		go client.watch(watcher, changeCh)
+	}
+	go func() {
+		ticker := time.NewTicker(config.PollInterval)
+		for {
+			select {
+			case <-ticker.C:
+			case <-changeCh:
+			case <-doneCh:
+				ticker.Stop()
+				return
+			}
+			if err := client.update(); err != nil {
+				client.logger.Error("Failed to update dynamic config", tag.Error(err))
+			}
+		}
+	}()
+	return client, nil
+}
+
+func (rc *remoteClient) UpdateValue(name Key, value interface{}) error {
+	return errors.New("not supported for remote client")
+}
+
+func (rc *remoteClient) RestoreValue(name Key, filters map[Filter]interface{}) error {
+	return errors.New("not supported for remote client")
+}
+
+func (rc *remoteClient) ListValue(name Key) ([]*types.DynamicConfigEntry, error) {
+	return nil, errors.New("not supported for remote client")
+}
+
+// update is only called from a single goroutine at a time, so checksum needs no locking
+func (rc *remoteClient) update() error {
+	if err := rc.load(); err != nil {
+		rc.loadErrors.Inc(1)
+		return err
+	}
+	rc.lastUpdatedTime = time.Now()
+	rc.lastSuccessfulLoad.Update(float64(rc.lastUpdatedTime.Unix()))
+	return nil
+}
+
+func (rc *remoteClient) load() error {
+	ctx, cancel := context.WithTimeout(context.Background(), rc.config.FetchTimeout)
+	defer cancel()
+
+	content, err := rc.source.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch dynamic config: %v", err)
+	}
+
+	checksum := sha256.Sum256(content)
+	if bytes.Equal(checksum[:], rc.checksum) {
+		return nil
+	}
+
+	newValues := make(map[string][]*constrainedValue)
+	if err := yaml.Unmarshal(content, newValues); err != nil {
+		return fmt.Errorf("failed to decode dynamic config %v", err)
+	}
+	if err := rc.storeValues(newValues); err != nil {
+		return err
+	}
+	rc.checksum = checksum[:]
+	return nil
+}
+
+func (rc *remoteClient) watch(watcher RemoteWatcher, changeCh chan<- struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-rc.doneCh
+		cancel()
+	}()
+
+	for {
+		err := watcher.Watch(ctx, changeCh)
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		rc.logger.Warn("Dynamic config watch stopped, restarting", tag.Error(err))
+
+		select {
+		case <-time.After(remoteWatchRetryBackoff):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func validateRemoteClientConfig(config *RemoteClientConfig) error {
+	if config == nil {
+		return errors.New("no config found for remote dynamic config client")
+	}
+	if config.PollInterval < remoteMinPollInterval {
+		return fmt.Errorf("poll interval should be at least %v", remoteMinPollInterval)
+	}
+	if config.FetchTimeout <= 0 {
+		return errors.New("FetchTimeout must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/log"
+)
+
+type (
+	remoteClientSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		source *fakeRemoteSource
+		scope  tally.TestScope
+		client Client
+		doneCh chan struct{}
+	}
+
+	fakeRemoteSource struct {
+		sync.Mutex
+		content  []byte
+		err      error
+		changeCh chan<- struct{}
+		watching chan struct{}
+	}
+)
+
+func TestRemoteClientSuite(t *testing.T) {
+	s := new(remoteClientSuite)
+	suite.Run(t, s)
+}
+
+func (s *remoteClientSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.source = &fakeRemoteSource{
+		content:  []byte("testGetIntPropertyKey:\n- value: 10\n  constraints: {}\n"),
+		watching: make(chan struct{}),
+	}
+	s.scope = tally.NewTestScope("", nil)
+	s.doneCh = make(chan struct{})
+
+	var err error
+	s.client, err = NewRemoteClient(s.source, &RemoteClientConfig{
+		PollInterval: time.Minute,
+		FetchTimeout: time.Second,
+	}, log.NewNoop(), s.scope, s.doneCh)
+	s.NoError(err)
+}
+
+func (s *remoteClientSuite) TearDownTest() {
+	close(s.doneCh)
+}
+
+func (s *remoteClientSuite) TestInitialLoad() {
+	v, err := s.client.GetIntValue(TestGetIntPropertyKey, nil)
+	s.NoError(err)
+	s.Equal(10, v)
+	s.NotZero(s.scope.Snapshot().Gauges()[remoteLastSuccessfulLoadMetric+"+"].Value())
+}
+
+func (s *remoteClientSuite) TestReloadOnChange() {
+	s.source.set([]byte("testGetIntPropertyKey:\n- value: 20\n  constraints: {}\n"), nil)
+
+	s.Eventually(func() bool {
+		v, err := s.client.GetIntValue(TestGetIntPropertyKey, nil)
+		return err == nil && v == 20
+	}, time.Second*5, time.Millisecond*10)
+}
+
+func (s *remoteClientSuite) TestInvalidContentKeepsPreviousValues() {
+	s.source.set([]byte("testGetIntPropertyKey: [invalid"), nil)
+
+	s.Eventually(func() bool {
+		counter, ok := s.scope.Snapshot().Counters()[remoteLoadErrorsMetric+"+"]
+		return ok && counter.Value() > 0
+	}, time.Second*5, time.Millisecond*10)
+
+	v, err := s.client.GetIntValue(TestGetIntPropertyKey, nil)
+	s.NoError(err)
+	s.Equal(10, v)
+}
+
+func (s *remoteClientSuite) TestFetchError() {
+	s.source.set(nil, errors.New("unavailable"))
+
+	s.Eventually(func() bool {
+		counter, ok := s.scope.Snapshot().Counters()[remoteLoadErrorsMetric+"+"]
+		return ok && counter.Value() > 0
+	}, time.Second*5, time.Millisecond*10)
+
+	v, err := s.client.GetIntValue(TestGetIntPropertyKey, nil)
+	s.NoError(err)
+	s.Equal(10, v)
+}
+
+func (s *remoteClientSuite) TestUpdateNotSupported() {
+	s.Error(s.client.UpdateValue(TestGetIntPropertyKey, 1))
+}
+
+func TestNewRemoteClient_InvalidConfig(t *testing.T) {
+	_, err := NewRemoteClient(&fakeRemoteSource{watching: make(chan struct{})}, &RemoteClientConfig{
+		PollInterval: time.Second,
+		FetchTimeout: time.Second,
+	}, log.NewNoop(), nil, make(chan struct{}))
+	require.Error(t, err)
+}
+
+func (f *fakeRemoteSource) Fetch(ctx context.Context) ([]byte, error) {
+	f.Lock()
+	defer f.Unlock()
+	return f.content, f.err
+}
+
+func (f *fakeRemoteSource) Watch(ctx context.Context, changeCh chan<- struct{}) error {
+	f.Lock()
+	f.changeCh = changeCh
+	f.Unlock()
+	close(f.watching)
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (f *fakeRemoteSource) set(content []byte, err error) {
+	<-f.watching
+
+	f.Lock()
+	defer f.Unlock()
+	f.content = content
+	f.err = err
+	f.changeCh <- struct{}{}
+}
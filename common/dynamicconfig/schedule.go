@@ -0,0 +1,106 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"fmt"
+	"time"
+)
+
+// Values can be scheduled with the ActiveFrom and ActiveUntil filters. Unlike other filters they are not provided
+// by callers, instead they match while the current time is within the window, so a value is only in effect during
+// it (ex: a higher rps limit of a domain during a planned migration).
+
+// IsScheduleFilter returns whether the filter is matched against the current time
+func IsScheduleFilter(filter Filter) bool {
+	return filter == ActiveFrom || filter == ActiveUntil
+}
+
+// MatchSchedule returns whether the current time is within the window of the schedule filters, other filters are ignored.
+// Invalid times never match, so that a broken schedule does not keep a value in effect forever.
+func MatchSchedule(filters map[Filter]interface{}, now time.Time) bool {
+	for filter, value := range filters {
+		if !IsScheduleFilter(filter) {
+			continue
+		}
+		t, err := parseScheduleTime(value)
+		if err != nil {
+			return false
+		}
+		if filter == ActiveFrom && now.Before(t) {
+			return false
+		}
+		if filter == ActiveUntil && !now.Before(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateSchedule checks that the schedule filters are valid times and describe a non empty window
+func ValidateSchedule(filters map[Filter]interface{}) error {
+	var from, until time.Time
+	for filter, value := range filters {
+		if !IsScheduleFilter(filter) {
+			continue
+		}
+		t, err := parseScheduleTime(value)
+		if err != nil {
+			return fmt.Errorf("invalid %v: %v", filter, err)
+		}
+		if filter == ActiveFrom {
+			from = t
+		} else {
+			until = t
+		}
+	}
+	if !from.IsZero() && !until.IsZero() && !from.Before(until) {
+		return fmt.Errorf("%v %v must be before %v %v", ActiveFrom, from.Format(time.RFC3339), ActiveUntil, until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ScheduleChangeTimes returns the times at which the schedule filters start or stop matching
+func ScheduleChangeTimes(filters map[Filter]interface{}) []time.Time {
+	var times []time.Time
+	for filter, value := range filters {
+		if !IsScheduleFilter(filter) {
+			continue
+		}
+		if t, err := parseScheduleTime(value); err == nil {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+func parseScheduleTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return time.Parse(time.RFC3339, v)
+	default:
+		return time.Time{}, fmt.Errorf("%v is not a RFC3339 time", value)
+	}
+}
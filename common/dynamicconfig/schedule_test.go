@@ -0,0 +1,130 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log"
+)
+
+func TestMatchSchedule(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		filters  map[Filter]interface{}
+		expected bool
+	}{
+		"no schedule": {
+			filters:  map[Filter]interface{}{DomainName: "domain"},
+			expected: true,
+		},
+		"within window": {
+			filters:  map[Filter]interface{}{ActiveFrom: "2022-05-01T10:00:00Z", ActiveUntil: "2022-05-01T14:00:00Z"},
+			expected: true,
+		},
+		"before window": {
+			filters:  map[Filter]interface{}{ActiveFrom: "2022-05-01T13:00:00Z"},
+			expected: false,
+		},
+		"after window": {
+			filters:  map[Filter]interface{}{ActiveUntil: "2022-05-01T12:00:00Z"},
+			expected: false,
+		},
+		"time value": {
+			filters:  map[Filter]interface{}{ActiveFrom: now},
+			expected: true,
+		},
+		"invalid time": {
+			filters:  map[Filter]interface{}{ActiveUntil: "tomorrow"},
+			expected: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MatchSchedule(tc.filters, now))
+		})
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	assert.NoError(t, ValidateSchedule(map[Filter]interface{}{DomainName: "domain"}))
+	assert.NoError(t, ValidateSchedule(map[Filter]interface{}{ActiveFrom: "2022-05-01T10:00:00Z", ActiveUntil: "2022-05-01T14:00:00+02:00"}))
+	assert.Error(t, ValidateSchedule(map[Filter]interface{}{ActiveFrom: "2022-05-01T10:00:00Z", ActiveUntil: "2022-05-01T10:00:00Z"}))
+	assert.Error(t, ValidateSchedule(map[Filter]interface{}{ActiveFrom: 10}))
+}
+
+func TestScheduledValues_FileBasedClient(t *testing.T) {
+	format := func(t time.Time) string { return t.UTC().Format(time.RFC3339) }
+	now := time.Now()
+	client := &fileBasedClient{logger: log.NewNoop()}
+	require.NoError(t, client.storeValues(map[string][]*constrainedValue{
+		FrontendMaxDomainUserRPSPerInstance.String(): {
+			{Value: 500, Constraints: map[string]interface{}{"domainName": "past", "activeUntil": format(now.Add(-time.Hour))}},
+			{Value: 1000, Constraints: map[string]interface{}{"domainName": "current", "activeFrom": format(now.Add(-time.Hour)), "activeUntil": format(now.Add(time.Hour))}},
+			{Value: 2000, Constraints: map[string]interface{}{"domainName": "future", "activeFrom": format(now.Add(time.Hour))}},
+			{Value: 100},
+		},
+	}))
+	collection := NewCollection(client, log.NewNoop())
+	rps := collection.GetIntPropertyFilteredByDomain(FrontendMaxDomainUserRPSPerInstance)
+
+	assert.Equal(t, 100, rps("past"))
+	assert.Equal(t, 1000, rps("current"))
+	assert.Equal(t, 100, rps("future"))
+
+	err := client.storeValues(map[string][]*constrainedValue{
+		FrontendMaxDomainUserRPSPerInstance.String(): {
+			{Value: 1000, Constraints: map[string]interface{}{"activeFrom": format(now), "activeUntil": format(now.Add(-time.Hour))}},
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestNotifyUpdateAt(t *testing.T) {
+	b := &UpdateBroadcaster{}
+	notified := make(chan struct{}, 10)
+	b.AddUpdateListener(func() { notified <- struct{}{} })
+
+	now := time.Now()
+	b.NotifyUpdateAt([]time.Time{now.Add(-time.Second), now.Add(50 * time.Millisecond), now.Add(100 * time.Millisecond)})
+	for i := 0; i < 2; i++ {
+		select {
+		case <-notified:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "no scheduled notification")
+		}
+	}
+
+	// a new schedule replaces the pending one
+	b.NotifyUpdateAt([]time.Time{time.Now().Add(50 * time.Millisecond)})
+	b.NotifyUpdateAt(nil)
+	select {
+	case <-notified:
+		require.Fail(t, "unexpected notification")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,201 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+type (
+	// UpdateNotifier is implemented by clients which can tell when their values may have changed.
+	// Subscriptions on a Collection are only re-evaluated for clients implementing it.
+	UpdateNotifier interface {
+		// AddUpdateListener registers a listener called after every update of the values
+		AddUpdateListener(listener func())
+	}
+
+	// UpdateBroadcaster implements UpdateNotifier, it is meant to be embedded into clients.
+	// The zero value is ready to use.
+	UpdateBroadcaster struct {
+		lock      sync.RWMutex
+		listeners []func()
+		// timer of the next scheduled notification, scheduleGen invalidates timers which already fired
+		timer       *time.Timer
+		scheduleGen int64
+	}
+
+	subscription struct {
+		property  PropertyFn
+		callback  func(interface{})
+		lastValue interface{}
+	}
+)
+
+var _ UpdateNotifier = (*UpdateBroadcaster)(nil)
+
+// AddUpdateListener registers a listener called after every update of the values
+func (b *UpdateBroadcaster) AddUpdateListener(listener func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.listeners = append(b.listeners, listener)
+}
+
+// NotifyUpdate calls all registered listeners, it must be called after the new values are visible to readers
+func (b *UpdateBroadcaster) NotifyUpdate() {
+	b.lock.RLock()
+	listeners := b.listeners
+	b.lock.RUnlock()
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+// NotifyUpdateAt schedules notifications at the times in the future, so that listeners can re-evaluate values
+// which are scheduled to take or lose effect at them. It replaces the previously scheduled notifications.
+func (b *UpdateBroadcaster) NotifyUpdateAt(times []time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.scheduleLocked(times)
+}
+
+func (b *UpdateBroadcaster) scheduleLocked(times []time.Time) {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.scheduleGen++
+
+	now := time.Now()
+	var next time.Time
+	remaining := make([]time.Time, 0, len(times))
+	for _, t := range times {
+		if !t.After(now) {
+			continue
+		}
+		remaining = append(remaining, t)
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	if next.IsZero() {
+		return
+	}
+
+	gen := b.scheduleGen
+	b.timer = time.AfterFunc(next.Sub(now), func() {
+		b.NotifyUpdate()
+
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		if b.scheduleGen == gen {
+			b.scheduleLocked(remaining)
+		}
+	})
+}
+
+// Subscribe registers a callback which is called with the new value of the property whenever it changes.
+// The property is evaluated on every update of the underlying client, so it can use any key and filters, e.g.
+// a property function from a service config. The callback is not called for the value at subscription time and
+// it must not block, as it runs on the goroutine updating the client. The returned function cancels the subscription.
+func (c *Collection) Subscribe(property PropertyFn, callback func(interface{})) func() {
+	c.subscriptionsInit.Do(func() {
+		if notifier, ok := c.client.(UpdateNotifier); ok {
+			notifier.AddUpdateListener(c.refreshSubscriptions)
+		}
+	})
+
+	sub := &subscription{
+		property:  property,
+		callback:  callback,
+		lastValue: property(),
+	}
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+	if c.subscriptions == nil {
+		c.subscriptions = make(map[*subscription]struct{})
+	}
+	c.subscriptions[sub] = struct{}{}
+
+	return func() {
+		c.subscriptionsLock.Lock()
+		defer c.subscriptionsLock.Unlock()
+		delete(c.subscriptions, sub)
+	}
+}
+
+// SubscribeIntProperty is Subscribe for int properties
+func (c *Collection) SubscribeIntProperty(property func() int, callback func(int)) func() {
+	return c.Subscribe(
+		func() interface{} { return property() },
+		func(value interface{}) { callback(value.(int)) },
+	)
+}
+
+// SubscribeFloatProperty is Subscribe for float properties
+func (c *Collection) SubscribeFloatProperty(property func() float64, callback func(float64)) func() {
+	return c.Subscribe(
+		func() interface{} { return property() },
+		func(value interface{}) { callback(value.(float64)) },
+	)
+}
+
+// SubscribeBoolProperty is Subscribe for bool properties
+func (c *Collection) SubscribeBoolProperty(property func() bool, callback func(bool)) func() {
+	return c.Subscribe(
+		func() interface{} { return property() },
+		func(value interface{}) { callback(value.(bool)) },
+	)
+}
+
+// SubscribeDurationProperty is Subscribe for duration properties
+func (c *Collection) SubscribeDurationProperty(property func() time.Duration, callback func(time.Duration)) func() {
+	return c.Subscribe(
+		func() interface{} { return property() },
+		func(value interface{}) { callback(value.(time.Duration)) },
+	)
+}
+
+// refreshSubscriptions re-evaluates all subscribed properties and calls the callbacks of the changed ones.
+// Clients notify from a single goroutine, refreshLock only guards against misbehaving ones.
+func (c *Collection) refreshSubscriptions() {
+	c.refreshLock.Lock()
+	defer c.refreshLock.Unlock()
+
+	c.subscriptionsLock.Lock()
+	subs := make([]*subscription, 0, len(c.subscriptions))
+	for sub := range c.subscriptions {
+		subs = append(subs, sub)
+	}
+	c.subscriptionsLock.Unlock()
+
+	for _, sub := range subs {
+		value := sub.property()
+		if reflect.DeepEqual(value, sub.lastValue) {
+			continue
+		}
+		sub.lastValue = value
+		sub.callback(value)
+	}
+}
@@ -0,0 +1,78 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log"
+)
+
+func TestSubscribe(t *testing.T) {
+	client := NewInMemoryClient()
+	collection := NewCollection(client, log.NewNoop())
+	property := collection.GetIntProperty(MatchingTaskDispatchRPS)
+
+	var values []int
+	cancel := collection.SubscribeIntProperty(func() int { return property() }, func(value int) {
+		values = append(values, value)
+	})
+
+	require.NoError(t, client.UpdateValue(MatchingTaskDispatchRPS, 10))
+	// unchanged values are not delivered again
+	require.NoError(t, client.UpdateValue(MatchingTaskDispatchRPS, 10))
+	require.NoError(t, client.UpdateValue(MatchingForwarderMaxRatePerSecond, 5))
+	require.NoError(t, client.UpdateValue(MatchingTaskDispatchRPS, 20))
+	assert.Equal(t, []int{10, 20}, values)
+
+	cancel()
+	require.NoError(t, client.UpdateValue(MatchingTaskDispatchRPS, 30))
+	assert.Equal(t, []int{10, 20}, values)
+}
+
+func TestSubscribe_FileBasedClient(t *testing.T) {
+	client := &fileBasedClient{logger: log.NewNoop()}
+	require.NoError(t, client.storeValues(map[string][]*constrainedValue{}))
+	collection := NewCollection(client, log.NewNoop())
+	property := collection.GetIntPropertyFilteredByTaskListInfo(MatchingNumTasklistReadPartitions)
+
+	var values []int
+	collection.SubscribeIntProperty(func() int { return property("domain", "tl", 0) }, func(value int) {
+		values = append(values, value)
+	})
+
+	require.NoError(t, client.storeValues(map[string][]*constrainedValue{
+		MatchingNumTasklistReadPartitions.String(): {
+			{Value: 4, Constraints: map[string]interface{}{"domainName": "domain", "taskListName": "tl"}},
+		},
+	}))
+	require.NoError(t, client.storeValues(map[string][]*constrainedValue{
+		MatchingNumTasklistReadPartitions.String(): {
+			{Value: 4, Constraints: map[string]interface{}{"domainName": "domain", "taskListName": "other"}},
+		},
+	}))
+	assert.Equal(t, []int{4, 1}, values)
+}
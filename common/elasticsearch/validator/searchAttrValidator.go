@@ -28,12 +28,14 @@ import (
 	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/redaction"
 	"github.com/uber/cadence/common/types"
 )
 
 // SearchAttributesValidator is used to validate search attributes
 type SearchAttributesValidator struct {
-	logger log.Logger
+	logger   log.Logger
+	redactor redaction.Redactor
 
 	enableQueryAttributeValidation    dynamicconfig.BoolPropertyFn
 	validSearchAttributes             dynamicconfig.MapPropertyFn
@@ -45,6 +47,7 @@ type SearchAttributesValidator struct {
 // NewSearchAttributesValidator create SearchAttributesValidator
 func NewSearchAttributesValidator(
 	logger log.Logger,
+	redactor redaction.Redactor,
 	enableQueryAttributeValidation dynamicconfig.BoolPropertyFn,
 	validSearchAttributes dynamicconfig.MapPropertyFn,
 	searchAttributesNumberOfKeysLimit dynamicconfig.IntPropertyFnWithDomainFilter,
@@ -53,6 +56,7 @@ func NewSearchAttributesValidator(
 ) *SearchAttributesValidator {
 	return &SearchAttributesValidator{
 		logger:                            logger,
+		redactor:                          redactor,
 		enableQueryAttributeValidation:    enableQueryAttributeValidation,
 		validSearchAttributes:             validSearchAttributes,
 		searchAttributesNumberOfKeysLimit: searchAttributesNumberOfKeysLimit,
@@ -94,9 +98,10 @@ func (sv *SearchAttributesValidator) ValidateSearchAttributes(input *types.Searc
 			}
 			// verify: value has the correct type
 			if !sv.isValidSearchAttributesValue(validAttr, key, val) {
-				sv.logger.WithTags(tag.ESKey(key), tag.ESValue(val), tag.WorkflowDomainName(domain)).
+				redactedVal := sv.redactor.Redact(domain, redaction.FieldSearchAttribute, val)
+				sv.logger.WithTags(tag.ESKey(key), tag.ESValue([]byte(redactedVal)), tag.WorkflowDomainName(domain)).
 					Error("invalid search attribute value")
-				return &types.BadRequestError{Message: fmt.Sprintf("%s is not a valid search attribute value for key %s", val, key)}
+				return &types.BadRequestError{Message: fmt.Sprintf("%s is not a valid search attribute value for key %s", redactedVal, key)}
 			}
 		}
 		// verify: key is not system reserved
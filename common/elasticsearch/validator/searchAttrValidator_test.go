@@ -28,6 +28,7 @@ import (
 	"github.com/uber/cadence/common/definition"
 	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/redaction"
 	"github.com/uber/cadence/common/types"
 )
 
@@ -46,6 +47,7 @@ func (s *searchAttributesValidatorSuite) TestValidateSearchAttributes() {
 	sizeOfTotalLimit := 20
 
 	validator := NewSearchAttributesValidator(log.NewNoop(),
+		redaction.NewNopRedactor(),
 		dynamicconfig.GetBoolPropertyFn(true),
 		dynamicconfig.GetMapPropertyFn(definition.GetDefaultIndexedKeys()),
 		dynamicconfig.GetIntPropertyFilteredByDomain(numOfKeysLimit),
@@ -120,3 +122,23 @@ func (s *searchAttributesValidatorSuite) TestValidateSearchAttributes() {
 	err = validator.ValidateSearchAttributes(attr, domain)
 	s.Equal(`total size 44 exceed limit`, err.Error())
 }
+
+func (s *searchAttributesValidatorSuite) TestValidateSearchAttributes_RedactsValue() {
+	dcClient := dynamicconfig.NewInMemoryClient()
+	s.NoError(dcClient.UpdateValue(dynamicconfig.SearchAttributeRedaction, redaction.PolicyMask))
+	validator := NewSearchAttributesValidator(log.NewNoop(),
+		redaction.NewPolicyRedactor(dynamicconfig.NewCollection(dcClient, log.NewNoop())),
+		dynamicconfig.GetBoolPropertyFn(true),
+		dynamicconfig.GetMapPropertyFn(definition.GetDefaultIndexedKeys()),
+		dynamicconfig.GetIntPropertyFilteredByDomain(2),
+		dynamicconfig.GetIntPropertyFilteredByDomain(5),
+		dynamicconfig.GetIntPropertyFilteredByDomain(20))
+
+	attr := &types.SearchAttributes{
+		IndexedFields: map[string][]byte{
+			"CustomBoolField": []byte(`123`),
+		},
+	}
+	err := validator.ValidateSearchAttributes(attr, "domain")
+	s.Equal(`<redacted> is not a valid search attribute value for key CustomBoolField`, err.Error())
+}
@@ -0,0 +1,83 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package encryption
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+
+	"github.com/uber/cadence/common/config"
+)
+
+type awsKMSProvider struct {
+	client kmsiface.KMSAPI
+}
+
+// NewAWSKMSProvider creates a provider generating the data keys with AWS KMS,
+// the rotation of the KMS keys is transparent as the encrypted data keys reference the version of the key
+func NewAWSKMSProvider(cfg *config.AWSKMSEncryption) (KeyProvider, error) {
+	awsConfig := &aws.Config{
+		Region: aws.String(cfg.Region),
+	}
+	if cfg.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(cfg.Endpoint)
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return newAWSKMSProvider(kms.New(sess)), nil
+}
+
+func newAWSKMSProvider(client kmsiface.KMSAPI) KeyProvider {
+	return &awsKMSProvider{client: client}
+}
+
+func (p *awsKMSProvider) GenerateDataKey(ctx context.Context, keyID string) (*DataKey, error) {
+	output, err := p.client.GenerateDataKeyWithContext(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(keyID),
+		KeySpec: aws.String(kms.DataKeySpecAes256),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &DataKey{
+		Plaintext: output.Plaintext,
+		Encrypted: output.CiphertextBlob,
+	}, nil
+}
+
+func (p *awsKMSProvider) DecryptDataKey(ctx context.Context, keyID string, encryptedKey []byte) ([]byte, error) {
+	output, err := p.client.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(keyID),
+		CiphertextBlob: encryptedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Plaintext, nil
+}
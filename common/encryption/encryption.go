@@ -0,0 +1,304 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package encryption encrypts data with data keys generated and protected by a KMS, i.e. envelope encryption,
+// so that the data of a tenant can only be read as long as its KMS key is available.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/config"
+)
+
+const (
+	envelopeVersion = 1
+
+	defaultDataKeyTTL       = time.Hour
+	defaultDataKeyCacheSize = 1000
+)
+
+var errMalformedEnvelope = errors.New("malformed encryption envelope")
+
+type (
+	// KeyProvider generates data keys protected by a KMS key and decrypts them
+	KeyProvider interface {
+		// GenerateDataKey returns a new 256 bits data key, in plaintext and encrypted with the KMS key
+		GenerateDataKey(ctx context.Context, keyID string) (*DataKey, error)
+		// DecryptDataKey decrypts a data key encrypted with the KMS key
+		DecryptDataKey(ctx context.Context, keyID string, encryptedKey []byte) ([]byte, error)
+	}
+
+	// DataKey is a data key in plaintext and encrypted with a KMS key
+	DataKey struct {
+		Plaintext []byte
+		Encrypted []byte
+	}
+
+	// Encryptor encrypts data with data keys protected by KMS keys. The data keys are reused to encrypt
+	// for a limited time, and the decrypted data keys are cached, so that the KMS is rarely called.
+	Encryptor interface {
+		// Encrypt encrypts the plaintext with a data key of the KMS key,
+		// the metadata is stored in plaintext along the ciphertext but cannot be tampered with
+		Encrypt(ctx context.Context, keyID string, metadata []byte, plaintext []byte) ([]byte, error)
+		// Decrypt decrypts a ciphertext returned by Encrypt, with the KMS key it was encrypted with
+		Decrypt(ctx context.Context, ciphertext []byte) (metadata []byte, plaintext []byte, err error)
+	}
+
+	encryptorImpl struct {
+		provider   KeyProvider
+		timeSource clock.TimeSource
+		ttl        time.Duration
+		cacheSize  int
+
+		sync.Mutex
+		// encryptionKeys are the current data keys by KMS key ID
+		encryptionKeys map[string]*encryptionKey
+		// decryptionKeys are the decrypted data keys by encrypted data key
+		decryptionKeys map[string]cipher.AEAD
+	}
+
+	encryptionKey struct {
+		aead      cipher.AEAD
+		encrypted []byte
+		expiry    time.Time
+	}
+
+	// envelope is the format of a ciphertext:
+	// version (1 byte) | key ID length (2 bytes) | key ID | encrypted data key length (2 bytes) | encrypted data key |
+	// metadata length (2 bytes) | metadata | nonce | data encrypted with AES-GCM, the header up to the nonce is authenticated
+	envelope struct {
+		keyID        string
+		encryptedKey []byte
+		metadata     []byte
+		nonce        []byte
+		data         []byte
+		header       []byte
+	}
+)
+
+// NewEncryptor creates an encryptor using the KMS provider of the config
+func NewEncryptor(cfg *config.Encryption) (Encryptor, error) {
+	var provider KeyProvider
+	var err error
+	switch cfg.Provider {
+	case config.EncryptionProviderAWSKMS:
+		provider, err = NewAWSKMSProvider(&cfg.AWSKMS)
+	case config.EncryptionProviderVault:
+		provider, err = NewVaultProvider(&cfg.Vault)
+	case config.EncryptionProviderLocal:
+		provider, err = NewLocalProvider(&cfg.Local)
+	default:
+		return nil, fmt.Errorf("unknown encryption provider %q", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptorWithProvider(provider, cfg.DataKeyTTL, cfg.DataKeyCacheSize, clock.NewRealTimeSource()), nil
+}
+
+// NewEncryptorWithProvider creates an encryptor using the provider, the defaults are used for non positive values
+func NewEncryptorWithProvider(provider KeyProvider, ttl time.Duration, cacheSize int, timeSource clock.TimeSource) Encryptor {
+	if ttl <= 0 {
+		ttl = defaultDataKeyTTL
+	}
+	if cacheSize <= 0 {
+		cacheSize = defaultDataKeyCacheSize
+	}
+	return &encryptorImpl{
+		provider:       provider,
+		timeSource:     timeSource,
+		ttl:            ttl,
+		cacheSize:      cacheSize,
+		encryptionKeys: make(map[string]*encryptionKey),
+		decryptionKeys: make(map[string]cipher.AEAD),
+	}
+}
+
+func (e *encryptorImpl) Encrypt(ctx context.Context, keyID string, metadata []byte, plaintext []byte) ([]byte, error) {
+	key, err := e.getEncryptionKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header, err := encodeHeader(keyID, key.encrypted, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+key.aead.Overhead())
+	ciphertext = append(ciphertext, header...)
+	ciphertext = append(ciphertext, nonce...)
+	return key.aead.Seal(ciphertext, nonce, plaintext, header), nil
+}
+
+func (e *encryptorImpl) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, []byte, error) {
+	envelope, err := decodeEnvelope(ciphertext)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := e.getDecryptionKey(ctx, envelope.keyID, envelope.encryptedKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(envelope.nonce) != aead.NonceSize() {
+		return nil, nil, errMalformedEnvelope
+	}
+	plaintext, err := aead.Open(nil, envelope.nonce, envelope.data, envelope.header)
+	if err != nil {
+		return nil, nil, err
+	}
+	return envelope.metadata, plaintext, nil
+}
+
+func (e *encryptorImpl) getEncryptionKey(ctx context.Context, keyID string) (*encryptionKey, error) {
+	now := e.timeSource.Now()
+	e.Lock()
+	key, ok := e.encryptionKeys[keyID]
+	e.Unlock()
+	if ok && now.Before(key.expiry) {
+		return key, nil
+	}
+
+	// concurrent callers can generate a data key each, the last one is kept
+	dataKey, err := e.provider.GenerateDataKey(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key of %v: %v", keyID, err)
+	}
+	aead, err := newAEAD(dataKey.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	key = &encryptionKey{
+		aead:      aead,
+		encrypted: dataKey.Encrypted,
+		expiry:    now.Add(e.ttl),
+	}
+
+	e.Lock()
+	e.encryptionKeys[keyID] = key
+	e.cacheDecryptionKeyLocked(dataKey.Encrypted, aead)
+	e.Unlock()
+	return key, nil
+}
+
+func (e *encryptorImpl) getDecryptionKey(ctx context.Context, keyID string, encryptedKey []byte) (cipher.AEAD, error) {
+	e.Lock()
+	aead, ok := e.decryptionKeys[string(encryptedKey)]
+	e.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	plaintext, err := e.provider.DecryptDataKey(ctx, keyID, encryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key of %v: %v", keyID, err)
+	}
+	aead, err = newAEAD(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Lock()
+	e.cacheDecryptionKeyLocked(encryptedKey, aead)
+	e.Unlock()
+	return aead, nil
+}
+
+func (e *encryptorImpl) cacheDecryptionKeyLocked(encryptedKey []byte, aead cipher.AEAD) {
+	if len(e.decryptionKeys) >= e.cacheSize {
+		// evict an arbitrary key, the keys in use are decrypted again on their next use
+		for key := range e.decryptionKeys {
+			delete(e.decryptionKeys, key)
+			break
+		}
+	}
+	e.decryptionKeys[string(encryptedKey)] = aead
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encodeHeader(keyID string, encryptedKey []byte, metadata []byte) ([]byte, error) {
+	header := []byte{envelopeVersion}
+	for _, field := range [][]byte{[]byte(keyID), encryptedKey, metadata} {
+		if len(field) > 1<<16-1 {
+			return nil, fmt.Errorf("encryption envelope field of %v bytes is too long", len(field))
+		}
+		length := make([]byte, 2)
+		binary.BigEndian.PutUint16(length, uint16(len(field)))
+		header = append(header, length...)
+		header = append(header, field...)
+	}
+	return header, nil
+}
+
+func decodeEnvelope(ciphertext []byte) (*envelope, error) {
+	if len(ciphertext) == 0 || ciphertext[0] != envelopeVersion {
+		return nil, errMalformedEnvelope
+	}
+	offset := 1
+	fields := make([][]byte, 3)
+	for i := range fields {
+		if len(ciphertext) < offset+2 {
+			return nil, errMalformedEnvelope
+		}
+		length := int(binary.BigEndian.Uint16(ciphertext[offset:]))
+		offset += 2
+		if len(ciphertext) < offset+length {
+			return nil, errMalformedEnvelope
+		}
+		fields[i] = ciphertext[offset : offset+length]
+		offset += length
+	}
+
+	// the nonce of AES-GCM is 12 bytes
+	const nonceSize = 12
+	if len(ciphertext) < offset+nonceSize {
+		return nil, errMalformedEnvelope
+	}
+	return &envelope{
+		keyID:        string(fields[0]),
+		encryptedKey: fields[1],
+		metadata:     fields[2],
+		header:       ciphertext[:offset],
+		nonce:        ciphertext[offset : offset+nonceSize],
+		data:         ciphertext[offset+nonceSize:],
+	}, nil
+}
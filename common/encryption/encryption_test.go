@@ -0,0 +1,194 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/config"
+)
+
+type countingProvider struct {
+	KeyProvider
+	generated int
+	decrypted int
+}
+
+func (p *countingProvider) GenerateDataKey(ctx context.Context, keyID string) (*DataKey, error) {
+	p.generated++
+	return p.KeyProvider.GenerateDataKey(ctx, keyID)
+}
+
+func (p *countingProvider) DecryptDataKey(ctx context.Context, keyID string, encryptedKey []byte) ([]byte, error) {
+	p.decrypted++
+	return p.KeyProvider.DecryptDataKey(ctx, keyID, encryptedKey)
+}
+
+func newTestProvider(t *testing.T) *countingProvider {
+	provider, err := NewLocalProvider(&config.LocalEncryption{
+		Keys: map[string]string{
+			"key1": base64.StdEncoding.EncodeToString(make([]byte, dataKeySize)),
+		},
+	})
+	require.NoError(t, err)
+	return &countingProvider{KeyProvider: provider}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	provider := newTestProvider(t)
+	encryptor := NewEncryptorWithProvider(provider, 0, 0, clock.NewRealTimeSource())
+
+	ciphertext, err := encryptor.Encrypt(context.Background(), "key1", []byte("thriftrw"), []byte("history"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "history")
+
+	metadata, plaintext, err := encryptor.Decrypt(context.Background(), ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "thriftrw", string(metadata))
+	assert.Equal(t, "history", string(plaintext))
+
+	// the data key is cached for both encryption and decryption
+	_, err = encryptor.Encrypt(context.Background(), "key1", nil, []byte("history"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, provider.generated)
+	assert.Equal(t, 0, provider.decrypted)
+
+	// a new encryptor decrypts the data key once
+	encryptor = NewEncryptorWithProvider(provider, 0, 0, clock.NewRealTimeSource())
+	for i := 0; i < 2; i++ {
+		_, plaintext, err = encryptor.Decrypt(context.Background(), ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, "history", string(plaintext))
+	}
+	assert.Equal(t, 1, provider.decrypted)
+
+	_, err = encryptor.Encrypt(context.Background(), "unknown", nil, []byte("history"))
+	assert.Error(t, err)
+}
+
+func TestEncrypt_DataKeyRotation(t *testing.T) {
+	provider := newTestProvider(t)
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	encryptor := NewEncryptorWithProvider(provider, time.Minute, 0, timeSource)
+
+	first, err := encryptor.Encrypt(context.Background(), "key1", nil, []byte("history"))
+	require.NoError(t, err)
+	timeSource.Update(timeSource.Now().Add(30 * time.Second))
+	_, err = encryptor.Encrypt(context.Background(), "key1", nil, []byte("history"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, provider.generated)
+
+	timeSource.Update(timeSource.Now().Add(time.Minute))
+	second, err := encryptor.Encrypt(context.Background(), "key1", nil, []byte("history"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.generated)
+
+	// the data encrypted with the previous data key can still be decrypted
+	for _, ciphertext := range [][]byte{first, second} {
+		_, plaintext, err := encryptor.Decrypt(context.Background(), ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, "history", string(plaintext))
+	}
+}
+
+func TestDecrypt_Tampered(t *testing.T) {
+	encryptor := NewEncryptorWithProvider(newTestProvider(t), 0, 0, clock.NewRealTimeSource())
+	ciphertext, err := encryptor.Encrypt(context.Background(), "key1", []byte("json"), []byte("history"))
+	require.NoError(t, err)
+
+	// the metadata is authenticated
+	tampered := append([]byte{}, ciphertext...)
+	metadataOffset := len(ciphertext) - len("history") - 16 - 12 - len("json")
+	tampered[metadataOffset] = 'x'
+	_, _, err = encryptor.Decrypt(context.Background(), tampered)
+	assert.Error(t, err)
+
+	tampered = append([]byte{}, ciphertext...)
+	tampered[len(tampered)-1] ^= 1
+	_, _, err = encryptor.Decrypt(context.Background(), tampered)
+	assert.Error(t, err)
+
+	for _, malformed := range [][]byte{nil, {2}, {envelopeVersion, 0, 10}, ciphertext[:len(ciphertext)-len("history")-16-1]} {
+		_, _, err = encryptor.Decrypt(context.Background(), malformed)
+		assert.Equal(t, errMalformedEnvelope, err)
+	}
+}
+
+func TestNewEncryptor(t *testing.T) {
+	_, err := NewEncryptor(&config.Encryption{Provider: "unknown"})
+	assert.Error(t, err)
+	_, err = NewEncryptor(&config.Encryption{Provider: config.EncryptionProviderVault})
+	assert.Error(t, err)
+	_, err = NewEncryptor(&config.Encryption{
+		Provider: config.EncryptionProviderLocal,
+		Local:    config.LocalEncryption{Keys: map[string]string{"key1": "c2hvcnQ="}},
+	})
+	assert.Error(t, err)
+}
+
+func TestVaultProvider(t *testing.T) {
+	dataKey := make([]byte, dataKeySize)
+	dataKey[0] = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/transit/datakey/plaintext/key1":
+			w.Write([]byte(`{"data":{"plaintext":"` + base64.StdEncoding.EncodeToString(dataKey) + `","ciphertext":"vault:v1:encrypted"}}`))
+		case "/v1/transit/decrypt/key1":
+			w.Write([]byte(`{"data":{"plaintext":"` + base64.StdEncoding.EncodeToString(dataKey) + `"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultProvider(&config.VaultEncryption{Address: server.URL, Token: "token"})
+	require.NoError(t, err)
+	key, err := provider.GenerateDataKey(context.Background(), "key1")
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, key.Plaintext)
+	assert.Equal(t, "vault:v1:encrypted", string(key.Encrypted))
+
+	plaintext, err := provider.DecryptDataKey(context.Background(), "key1", key.Encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, plaintext)
+
+	provider, err = NewVaultProvider(&config.VaultEncryption{Address: server.URL, Token: "invalid"})
+	require.NoError(t, err)
+	_, err = provider.GenerateDataKey(context.Background(), "key1")
+	assert.EqualError(t, err, "vault 403 Forbidden: permission denied")
+}
@@ -0,0 +1,96 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package encryption
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/uber/cadence/common/config"
+)
+
+const dataKeySize = 32
+
+type localProvider struct {
+	masterKeys map[string][]byte
+}
+
+// NewLocalProvider creates a provider protecting the data keys with master keys set in the config,
+// it does not depend on an external KMS and is meant for development and tests
+func NewLocalProvider(cfg *config.LocalEncryption) (KeyProvider, error) {
+	masterKeys := make(map[string][]byte, len(cfg.Keys))
+	for keyID, encodedKey := range cfg.Keys {
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key %v: %v", keyID, err)
+		}
+		if len(key) != dataKeySize {
+			return nil, fmt.Errorf("master key %v must be %v bytes", keyID, dataKeySize)
+		}
+		masterKeys[keyID] = key
+	}
+	return &localProvider{masterKeys: masterKeys}, nil
+}
+
+func (p *localProvider) GenerateDataKey(_ context.Context, keyID string) (*DataKey, error) {
+	aead, err := p.masterKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, dataKeySize)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &DataKey{
+		Plaintext: plaintext,
+		Encrypted: aead.Seal(nonce, nonce, plaintext, []byte(keyID)),
+	}, nil
+}
+
+func (p *localProvider) DecryptDataKey(_ context.Context, keyID string, encryptedKey []byte) ([]byte, error) {
+	aead, err := p.masterKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedKey) < aead.NonceSize() {
+		return nil, errors.New("malformed data key")
+	}
+	nonce, ciphertext := encryptedKey[:aead.NonceSize()], encryptedKey[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(keyID))
+}
+
+func (p *localProvider) masterKey(keyID string) (cipher.AEAD, error) {
+	key, ok := p.masterKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown master key %v", keyID)
+	}
+	return newAEAD(key)
+}
@@ -0,0 +1,147 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/uber/cadence/common/config"
+)
+
+const defaultVaultMountPath = "transit"
+
+type (
+	vaultProvider struct {
+		address   string
+		mountPath string
+		token     string
+		tokenFile string
+		client    *http.Client
+	}
+
+	vaultResponse struct {
+		Data struct {
+			Plaintext  string `json:"plaintext"`
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+		Errors []string `json:"errors"`
+	}
+)
+
+// NewVaultProvider creates a provider generating the data keys with the transit secrets engine of Vault,
+// the rotation of the transit keys is transparent as the encrypted data keys reference the version of the key
+func NewVaultProvider(cfg *config.VaultEncryption) (KeyProvider, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("vault address must be set")
+	}
+	client := http.DefaultClient
+	tlsConfig, err := cfg.TLS.ToTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client = &http.Client{Transport: transport}
+	}
+	mountPath := cfg.MountPath
+	if mountPath == "" {
+		mountPath = defaultVaultMountPath
+	}
+	return &vaultProvider{
+		address:   strings.TrimSuffix(cfg.Address, "/"),
+		mountPath: strings.Trim(mountPath, "/"),
+		token:     cfg.Token,
+		tokenFile: cfg.TokenFile,
+		client:    client,
+	}, nil
+}
+
+func (p *vaultProvider) GenerateDataKey(ctx context.Context, keyID string) (*DataKey, error) {
+	response, err := p.call(ctx, "datakey/plaintext/"+keyID, map[string]string{"bits": "256"})
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return &DataKey{
+		Plaintext: plaintext,
+		Encrypted: []byte(response.Data.Ciphertext),
+	}, nil
+}
+
+func (p *vaultProvider) DecryptDataKey(ctx context.Context, keyID string, encryptedKey []byte) ([]byte, error) {
+	response, err := p.call(ctx, "decrypt/"+keyID, map[string]string{"ciphertext": string(encryptedKey)})
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Data.Plaintext)
+}
+
+func (p *vaultProvider) call(ctx context.Context, path string, body map[string]string) (*vaultResponse, error) {
+	token := p.token
+	if p.tokenFile != "" {
+		// the token file is read on every call, so that the token can be renewed by an agent
+		content, err := ioutil.ReadFile(p.tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(content))
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%v/v1/%v/%v", p.address, p.mountPath, path)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Vault-Token", token)
+	request.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := p.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	var response vaultResponse
+	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("vault %v: %v", httpResponse.Status, err)
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault %v: %v", httpResponse.Status, strings.Join(response.Errors, ", "))
+	}
+	return &response, nil
+}
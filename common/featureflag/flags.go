@@ -0,0 +1,77 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package featureflag gates features behind named flags which are gradually rolled out through dynamic config.
+//
+// The rollout percentage of a flag is stored in system.featureFlagRolloutPercentage with the featureFlag filter,
+// optionally narrowed down to a domain with the domainName filter. Domains and workflows are bucketed by a hash of
+// the flag name and their name or ID, so raising the percentage only ever adds domains or workflows to a rollout
+// and each flag is rolled out to a different set of them.
+package featureflag
+
+import (
+	"github.com/dgryski/go-farm"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+)
+
+type (
+	// Flag is the name of a feature flag
+	Flag string
+
+	// Flags evaluates feature flags against their rollout in dynamic config
+	Flags struct {
+		rolloutPercentage dynamicconfig.IntPropertyFn
+	}
+)
+
+const bucketCount = 100
+
+// NewFlags creates a new Flags
+func NewFlags(dc *dynamicconfig.Collection) *Flags {
+	return &Flags{
+		rolloutPercentage: dc.GetIntProperty(dynamicconfig.FeatureFlagRolloutPercentage),
+	}
+}
+
+// IsEnabledForDomain returns whether the flag is enabled for the domain, the domain name is the bucketing key
+func (f *Flags) IsEnabledForDomain(flag Flag, domain string) bool {
+	return bucket(flag, domain) < f.RolloutPercentage(flag, domain)
+}
+
+// IsEnabledForWorkflow returns whether the flag is enabled for a workflow of the domain, the workflow ID is
+// the bucketing key. Runs of a workflow ID always get the same result.
+func (f *Flags) IsEnabledForWorkflow(flag Flag, domain string, workflowID string) bool {
+	return bucket(flag, domain+"/"+workflowID) < f.RolloutPercentage(flag, domain)
+}
+
+// RolloutPercentage returns the percentage of buckets the flag is enabled for in the domain
+func (f *Flags) RolloutPercentage(flag Flag, domain string) int {
+	return f.rolloutPercentage(
+		dynamicconfig.FeatureFlagFilter(string(flag)),
+		dynamicconfig.DomainFilter(domain),
+	)
+}
+
+func bucket(flag Flag, key string) int {
+	return int(farm.Fingerprint32([]byte(string(flag)+":"+key)) % bucketCount)
+}
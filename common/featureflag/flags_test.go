@@ -0,0 +1,115 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package featureflag
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+)
+
+const testConfig = `
+system.featureFlagRolloutPercentage:
+- value: 100
+  constraints:
+    featureFlag: everywhere
+- value: 30
+  constraints:
+    featureFlag: partial
+    domainName: samples-domain
+- value: 100
+  constraints:
+    featureFlag: partial
+    domainName: full-domain
+`
+
+func newTestFlags(t *testing.T) *Flags {
+	dir, err := ioutil.TempDir("", "featureflag")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(testConfig), 0644))
+
+	doneCh := make(chan struct{})
+	t.Cleanup(func() { close(doneCh) })
+	client, err := dynamicconfig.NewFileBasedClient(&dynamicconfig.FileBasedClientConfig{
+		Filepath:     path,
+		PollInterval: time.Minute,
+	}, log.NewNoop(), doneCh)
+	require.NoError(t, err)
+	return NewFlags(dynamicconfig.NewCollection(client, log.NewNoop()))
+}
+
+func TestRolloutPercentage(t *testing.T) {
+	flags := newTestFlags(t)
+
+	assert.Equal(t, 100, flags.RolloutPercentage("everywhere", "samples-domain"))
+	assert.Equal(t, 30, flags.RolloutPercentage("partial", "samples-domain"))
+	assert.Equal(t, 100, flags.RolloutPercentage("partial", "full-domain"))
+	assert.Equal(t, 0, flags.RolloutPercentage("partial", "other-domain"))
+	assert.Equal(t, 0, flags.RolloutPercentage("unknown", "samples-domain"))
+}
+
+func TestIsEnabled(t *testing.T) {
+	flags := newTestFlags(t)
+
+	assert.True(t, flags.IsEnabledForDomain("everywhere", "any-domain"))
+	assert.True(t, flags.IsEnabledForWorkflow("partial", "full-domain", "wid"))
+	assert.False(t, flags.IsEnabledForDomain("unknown", "samples-domain"))
+	assert.False(t, flags.IsEnabledForWorkflow("partial", "other-domain", "wid"))
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		workflowID := fmt.Sprintf("wid-%v", i)
+		result := flags.IsEnabledForWorkflow("partial", "samples-domain", workflowID)
+		// bucketing is sticky
+		assert.Equal(t, result, flags.IsEnabledForWorkflow("partial", "samples-domain", workflowID))
+		if result {
+			enabled++
+		}
+	}
+	assert.InDelta(t, 300, enabled, 60)
+}
+
+func TestBucket(t *testing.T) {
+	differentBuckets := false
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("domain-%v", i)
+		b := bucket("flag-a", key)
+		assert.True(t, b >= 0 && b < bucketCount)
+		if b != bucket("flag-b", key) {
+			differentBuckets = true
+		}
+	}
+	// each flag is rolled out to a different set of domains
+	assert.True(t, differentBuckets)
+}
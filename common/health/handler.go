@@ -0,0 +1,91 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const (
+	// LivenessPath is the path of the liveness endpoint, it succeeds as long as the process serves requests
+	LivenessPath = "/health/live"
+	// ReadinessPath is the path of the readiness endpoint, it reports the health of the services of the host
+	// by dependency and fails if any of them is down. It can be narrowed down with ?service=history,matching
+	ReadinessPath = "/health/ready"
+)
+
+type handler struct {
+	registry *Registry
+}
+
+// NewHandler creates the http handler of the liveness and readiness endpoints
+func NewHandler(registry *Registry) http.Handler {
+	return &handler{registry: registry}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case LivenessPath:
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok")) //nolint:errcheck
+	case ReadinessPath:
+		h.serveReadiness(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *handler) serveReadiness(w http.ResponseWriter, r *http.Request) {
+	var services []string
+	if param := r.URL.Query().Get("service"); param != "" {
+		registered := make(map[string]bool)
+		for _, service := range h.registry.Services() {
+			registered[service] = true
+		}
+		for _, service := range strings.Split(param, ",") {
+			if !registered[service] {
+				http.Error(w, "unknown service "+service, http.StatusNotFound)
+				return
+			}
+			services = append(services, service)
+		}
+	}
+
+	result := h.registry.Check(r.Context(), services...)
+	body, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	// a degraded host keeps receiving traffic, only a host which is down is taken out of rotation
+	if result.Status == StatusDown {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.Write(body) //nolint:errcheck
+}
@@ -0,0 +1,66 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("frontend", "elasticsearch", staticCheck(StatusDegraded))
+	registry.Register("history", "persistence", staticCheck(StatusDown))
+	handler := NewHandler(registry)
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, serve(LivenessPath).Code)
+	assert.Equal(t, http.StatusNotFound, serve(ReadinessPath+"?service=matching").Code)
+
+	recorder := serve(ReadinessPath)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	recorder = serve(ReadinessPath + "?service=frontend")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "degraded", body["status"])
+	assert.Equal(t, map[string]interface{}{
+		"frontend": map[string]interface{}{
+			"status": "degraded",
+			"dependencies": map[string]interface{}{
+				"elasticsearch": map[string]interface{}{"status": "degraded"},
+			},
+		},
+	}, body["services"])
+}
@@ -0,0 +1,239 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package health reports the readiness of the services of a host broken down by the dependencies they need,
+// so that load balancers and Kubernetes probes can tell a degraded host, which still serves requests,
+// from a host which is down.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusUp is the status of a dependency working as expected
+	StatusUp Status = iota
+	// StatusDegraded is the status of a dependency which is slow or whose failure only impacts some requests
+	StatusDegraded
+	// StatusDown is the status of a dependency the service can't serve requests without
+	StatusDown
+)
+
+const checkTimeout = 5 * time.Second
+
+type (
+	// Status is the health status of a dependency, a service or a host
+	Status int
+
+	// Result is the result of a health check
+	Result struct {
+		Status  Status `json:"status"`
+		Message string `json:"message,omitempty"`
+	}
+
+	// Check reports the health of a dependency
+	Check func(ctx context.Context) Result
+
+	// ServiceHealth is the health of a service broken down by dependency
+	ServiceHealth struct {
+		Status       Status            `json:"status"`
+		Dependencies map[string]Result `json:"dependencies"`
+	}
+
+	// HostHealth is the health of the services running in the host
+	HostHealth struct {
+		Status   Status                    `json:"status"`
+		Services map[string]*ServiceHealth `json:"services"`
+	}
+
+	// Registry holds the health checks of the services running in the host.
+	// A nil registry is valid and ignores the checks registered.
+	Registry struct {
+		sync.RWMutex
+		checks map[string]map[string]Check
+	}
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusUp:
+		return "up"
+	case StatusDegraded:
+		return "degraded"
+	case StatusDown:
+		return "down"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		checks: make(map[string]map[string]Check),
+	}
+}
+
+// Register adds the check of a dependency to the service, replacing the previous check of the dependency if any
+func (r *Registry) Register(service string, dependency string, check Check) {
+	if r == nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	checks, ok := r.checks[service]
+	if !ok {
+		checks = make(map[string]Check)
+		r.checks[service] = checks
+	}
+	checks[dependency] = check
+}
+
+// Deregister removes all the checks of the service
+func (r *Registry) Deregister(service string) {
+	if r == nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.checks, service)
+}
+
+// Services returns the names of the services with registered checks
+func (r *Registry) Services() []string {
+	if r == nil {
+		return nil
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+
+	services := make([]string, 0, len(r.checks))
+	for service := range r.checks {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// Check runs the checks of the given services concurrently, or of all services if none is given.
+// A check not completing within its timeout reports its dependency as down.
+func (r *Registry) Check(ctx context.Context, services ...string) *HostHealth {
+	result := &HostHealth{
+		Status:   StatusUp,
+		Services: make(map[string]*ServiceHealth),
+	}
+	if r == nil {
+		return result
+	}
+	if len(services) == 0 {
+		services = r.Services()
+	}
+
+	r.RLock()
+	type pendingCheck struct {
+		service    string
+		dependency string
+		check      Check
+	}
+	var pending []pendingCheck
+	for _, service := range services {
+		result.Services[service] = &ServiceHealth{
+			Status:       StatusUp,
+			Dependencies: make(map[string]Result),
+		}
+		for dependency, check := range r.checks[service] {
+			pending = append(pending, pendingCheck{service: service, dependency: dependency, check: check})
+		}
+	}
+	r.RUnlock()
+
+	results := make([]Result, len(pending))
+	var wg sync.WaitGroup
+	for i := range pending {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, pending[i].check)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, check := range pending {
+		serviceHealth := result.Services[check.service]
+		serviceHealth.Dependencies[check.dependency] = results[i]
+		serviceHealth.Status = worst(serviceHealth.Status, results[i].Status)
+		result.Status = worst(result.Status, results[i].Status)
+	}
+	return result
+}
+
+func runCheck(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	resultC := make(chan Result, 1)
+	go func() {
+		resultC <- check(ctx)
+	}()
+	select {
+	case result := <-resultC:
+		return result
+	case <-ctx.Done():
+		return Result{Status: StatusDown, Message: "health check timed out"}
+	}
+}
+
+// NewPingCheck creates a check calling ping, the dependency is reported with failureStatus if ping fails and
+// as degraded if it takes longer than degradedLatency
+func NewPingCheck(ping func(ctx context.Context) error, failureStatus Status, degradedLatency time.Duration) Check {
+	return func(ctx context.Context) Result {
+		start := time.Now()
+		if err := ping(ctx); err != nil {
+			return Result{Status: failureStatus, Message: err.Error()}
+		}
+		if latency := time.Since(start); latency > degradedLatency {
+			return Result{Status: StatusDegraded, Message: fmt.Sprintf("slow response: %v", latency)}
+		}
+		return Result{Status: StatusUp}
+	}
+}
+
+func worst(a Status, b Status) Status {
+	if a > b {
+		return a
+	}
+	return b
+}
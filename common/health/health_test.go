@@ -0,0 +1,92 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func staticCheck(status Status) Check {
+	return func(ctx context.Context) Result {
+		return Result{Status: status}
+	}
+}
+
+func TestRegistryCheck(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("frontend", "persistence", staticCheck(StatusUp))
+	registry.Register("frontend", "elasticsearch", staticCheck(StatusDegraded))
+	registry.Register("history", "persistence", staticCheck(StatusUp))
+	assert.Equal(t, []string{"frontend", "history"}, registry.Services())
+
+	result := registry.Check(context.Background())
+	assert.Equal(t, StatusDegraded, result.Status)
+	assert.Equal(t, StatusDegraded, result.Services["frontend"].Status)
+	assert.Equal(t, StatusUp, result.Services["history"].Status)
+	assert.Len(t, result.Services["frontend"].Dependencies, 2)
+
+	registry.Register("history", "shards", staticCheck(StatusDown))
+	result = registry.Check(context.Background(), "history")
+	assert.Equal(t, StatusDown, result.Status)
+	assert.Len(t, result.Services, 1)
+
+	registry.Deregister("history")
+	assert.Equal(t, []string{"frontend"}, registry.Services())
+}
+
+func TestNilRegistry(t *testing.T) {
+	var registry *Registry
+	registry.Register("frontend", "persistence", staticCheck(StatusDown))
+	registry.Deregister("frontend")
+	assert.Empty(t, registry.Services())
+	assert.Equal(t, StatusUp, registry.Check(context.Background()).Status)
+}
+
+func TestPingCheck(t *testing.T) {
+	check := NewPingCheck(func(ctx context.Context) error { return nil }, StatusDown, time.Second)
+	assert.Equal(t, Result{Status: StatusUp}, check(context.Background()))
+
+	check = NewPingCheck(func(ctx context.Context) error { return errors.New("unreachable") }, StatusDown, time.Second)
+	assert.Equal(t, Result{Status: StatusDown, Message: "unreachable"}, check(context.Background()))
+
+	check = NewPingCheck(func(ctx context.Context) error {
+		time.Sleep(2 * time.Millisecond)
+		return nil
+	}, StatusDown, time.Millisecond)
+	assert.Equal(t, StatusDegraded, check(context.Background()).Status)
+}
+
+func TestCheckCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := runCheck(ctx, func(ctx context.Context) Result {
+		time.Sleep(time.Second)
+		return Result{Status: StatusUp}
+	})
+	assert.Equal(t, StatusDown, result.Status)
+}
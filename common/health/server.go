@@ -0,0 +1,87 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package health
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+)
+
+// Server serves the liveness and readiness endpoints on a dedicated listener, so that they can be
+// probed from other hosts without exposing the pprof server
+type Server struct {
+	hostPort string
+	registry *Registry
+	logger   log.Logger
+
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewServer creates a server of the endpoints of the registry listening on hostPort
+func NewServer(hostPort string, registry *Registry, logger log.Logger) *Server {
+	return &Server{
+		hostPort: hostPort,
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+// Start listens on the configured address and serves the endpoints in the background,
+// it only fails if the address can't be listened on
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.hostPort)
+	if err != nil {
+		return err
+	}
+
+	handler := NewHandler(s.registry)
+	mux := http.NewServeMux()
+	mux.Handle(LivenessPath, handler)
+	mux.Handle(ReadinessPath, handler)
+	s.listener = listener
+	s.server = &http.Server{Handler: mux}
+
+	s.logger.Info("Health endpoints listen on", tag.Address(listener.Addr().String()))
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("Health endpoints stopped serving", tag.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Stop closes the listener and the open connections
+func (s *Server) Stop() {
+	if s.server != nil {
+		s.server.Close() //nolint:errcheck
+	}
+}
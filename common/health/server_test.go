@@ -0,0 +1,54 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package health
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log"
+)
+
+func TestServer(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("history", "persistence", staticCheck(StatusDown))
+	server := NewServer("127.0.0.1:0", registry, log.NewNoop())
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	get := func(path string) int {
+		resp, err := http.Get("http://" + server.Addr().String() + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusOK, get(LivenessPath))
+	assert.Equal(t, http.StatusServiceUnavailable, get(ReadinessPath))
+	// only the health endpoints are served on the dedicated listener
+	assert.Equal(t, http.StatusNotFound, get("/debug/pprof/"))
+
+	assert.Error(t, NewServer(server.Addr().String(), registry, log.NewNoop()).Start())
+}
@@ -0,0 +1,208 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package heatmap collects the latency of persistence operations by shard over a sliding window on each host,
+// so that hot shards can be pinpointed during incidents without external tooling.
+package heatmap
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/persistence"
+)
+
+const (
+	// the window is a ring buffer of slots, the slot of the oldest minute is reused for the current one
+	slotDuration = time.Minute
+	numSlots     = 15
+	// MaxWindow is the longest window the latency is kept for
+	MaxWindow = numSlots * slotDuration
+
+	// latencies are bucketed by powers of two from 1ms up to 16s, the last bucket holds the slower operations
+	numLatencyBuckets = 16
+	minLatencyBound   = time.Millisecond
+)
+
+type (
+	// Collector records the latency of persistence operations by shard and operation
+	Collector interface {
+		persistence.LatencyRecorder
+		// Snapshot returns the heatmap of the operations completed within the window, up to MaxWindow
+		Snapshot(window time.Duration) *Snapshot
+	}
+
+	// Snapshot is the heatmap of a host, cells are sorted by decreasing p99 latency
+	Snapshot struct {
+		Start time.Time `json:"start"`
+		End   time.Time `json:"end"`
+		Cells []Cell    `json:"cells"`
+	}
+
+	// Cell summarizes the latency of an operation on a shard
+	Cell struct {
+		ShardID   int           `json:"shardId"`
+		Operation string        `json:"operation"`
+		Count     int64         `json:"count"`
+		P99       time.Duration `json:"p99"`
+	}
+
+	collectorImpl struct {
+		enabled    dynamicconfig.BoolPropertyFn
+		timeSource clock.TimeSource
+
+		sync.Mutex
+		slots [numSlots]slot
+	}
+
+	slot struct {
+		start time.Time
+		cells map[cellKey]*latencyHistogram
+	}
+
+	cellKey struct {
+		shardID   int
+		operation string
+	}
+
+	latencyHistogram [numLatencyBuckets]int64
+)
+
+var _ Collector = (*collectorImpl)(nil)
+
+// NewCollector creates a collector, latency is only recorded while enabled returns true
+func NewCollector(enabled dynamicconfig.BoolPropertyFn, timeSource clock.TimeSource) Collector {
+	return &collectorImpl{
+		enabled:    enabled,
+		timeSource: timeSource,
+	}
+}
+
+func (c *collectorImpl) RecordLatency(shardID int, operation string, latency time.Duration) {
+	if !c.enabled() {
+		return
+	}
+
+	start := c.timeSource.Now().Truncate(slotDuration)
+
+	c.Lock()
+	defer c.Unlock()
+
+	s := &c.slots[start.Unix()/int64(slotDuration/time.Second)%numSlots]
+	if !s.start.Equal(start) {
+		s.start = start
+		s.cells = make(map[cellKey]*latencyHistogram)
+	}
+	key := cellKey{shardID: shardID, operation: operation}
+	histogram, ok := s.cells[key]
+	if !ok {
+		histogram = &latencyHistogram{}
+		s.cells[key] = histogram
+	}
+	histogram[latencyBucket(latency)]++
+}
+
+func (c *collectorImpl) Snapshot(window time.Duration) *Snapshot {
+	if window <= 0 || window > MaxWindow {
+		window = MaxWindow
+	}
+	end := c.timeSource.Now()
+	start := end.Add(-window)
+
+	c.Lock()
+	merged := make(map[cellKey]*latencyHistogram)
+	for i := range c.slots {
+		s := &c.slots[i]
+		if s.cells == nil || !s.start.Add(slotDuration).After(start) || s.start.After(end) {
+			continue
+		}
+		for key, histogram := range s.cells {
+			total, ok := merged[key]
+			if !ok {
+				total = &latencyHistogram{}
+				merged[key] = total
+			}
+			for bucket, count := range histogram {
+				total[bucket] += count
+			}
+		}
+	}
+	c.Unlock()
+
+	snapshot := &Snapshot{
+		Start: start,
+		End:   end,
+		Cells: make([]Cell, 0, len(merged)),
+	}
+	for key, histogram := range merged {
+		count, p99 := histogram.summarize()
+		snapshot.Cells = append(snapshot.Cells, Cell{
+			ShardID:   key.shardID,
+			Operation: key.operation,
+			Count:     count,
+			P99:       p99,
+		})
+	}
+	sort.Slice(snapshot.Cells, func(i, j int) bool {
+		a, b := snapshot.Cells[i], snapshot.Cells[j]
+		if a.P99 != b.P99 {
+			return a.P99 > b.P99
+		}
+		if a.ShardID != b.ShardID {
+			return a.ShardID < b.ShardID
+		}
+		return a.Operation < b.Operation
+	})
+	return snapshot
+}
+
+// summarize returns the number of operations and the upper bound of the bucket of their 99th percentile
+func (h *latencyHistogram) summarize() (int64, time.Duration) {
+	var count int64
+	for _, c := range h {
+		count += c
+	}
+	threshold := count - count/100
+	var seen int64
+	for bucket, c := range h {
+		seen += c
+		if seen >= threshold {
+			return count, latencyBound(bucket)
+		}
+	}
+	return count, latencyBound(numLatencyBuckets - 1)
+}
+
+func latencyBucket(latency time.Duration) int {
+	bucket := 0
+	for bound := minLatencyBound; latency > bound && bucket < numLatencyBuckets-1; bound *= 2 {
+		bucket++
+	}
+	return bucket
+}
+
+func latencyBound(bucket int) time.Duration {
+	return minLatencyBound << uint(bucket)
+}
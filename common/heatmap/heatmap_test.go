@@ -0,0 +1,75 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package heatmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+)
+
+func TestCollectorSnapshot(t *testing.T) {
+	timeSource := clock.NewEventTimeSource()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeSource.Update(start)
+	collector := NewCollector(dynamicconfig.GetBoolPropertyFn(true), timeSource)
+
+	for i := 0; i < 99; i++ {
+		collector.RecordLatency(1, "GetWorkflowExecution", time.Millisecond)
+	}
+	collector.RecordLatency(1, "GetWorkflowExecution", time.Second)
+	collector.RecordLatency(2, "UpdateWorkflowExecution", 300*time.Millisecond)
+	timeSource.Update(start.Add(5 * time.Minute))
+	collector.RecordLatency(2, "UpdateWorkflowExecution", 3*time.Millisecond)
+
+	snapshot := collector.Snapshot(0)
+	assert.Equal(t, start.Add(5*time.Minute).Add(-MaxWindow), snapshot.Start)
+	assert.Equal(t, []Cell{
+		{ShardID: 2, Operation: "UpdateWorkflowExecution", Count: 2, P99: 512 * time.Millisecond},
+		{ShardID: 1, Operation: "GetWorkflowExecution", Count: 100, P99: time.Millisecond},
+	}, snapshot.Cells)
+
+	snapshot = collector.Snapshot(time.Minute)
+	assert.Equal(t, []Cell{
+		{ShardID: 2, Operation: "UpdateWorkflowExecution", Count: 1, P99: 4 * time.Millisecond},
+	}, snapshot.Cells)
+
+	// the slots of the first minute are reused once they fall out of the window
+	timeSource.Update(start.Add(MaxWindow))
+	collector.RecordLatency(3, "GetWorkflowExecution", 10*time.Millisecond)
+	snapshot = collector.Snapshot(MaxWindow)
+	assert.Equal(t, []Cell{
+		{ShardID: 3, Operation: "GetWorkflowExecution", Count: 1, P99: 16 * time.Millisecond},
+		{ShardID: 2, Operation: "UpdateWorkflowExecution", Count: 1, P99: 4 * time.Millisecond},
+	}, snapshot.Cells)
+}
+
+func TestCollectorDisabled(t *testing.T) {
+	collector := NewCollector(dynamicconfig.GetBoolPropertyFn(false), clock.NewRealTimeSource())
+	collector.RecordLatency(1, "GetWorkflowExecution", time.Second)
+	assert.Empty(t, collector.Snapshot(MaxWindow).Cells)
+}
@@ -0,0 +1,261 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package loadshedding monitors the CPU, memory and goroutines of a host, and sheds its load gradually once one of
+// them goes over its threshold: the lowest priority requests are rejected first, then the task processing is throttled
+package loadshedding
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+// escalationStep is the pressure above the threshold each level starts at, so a host at 0.9 CPU with a 0.9 threshold
+// sheds its low priority requests, and throttles its tasks from 0.99 CPU
+const escalationStep = 0.05
+
+const (
+	// LevelNone doesn't shed any load
+	LevelNone Level = iota
+	// LevelShedLow rejects the low priority requests
+	LevelShedLow
+	// LevelShedMedium rejects the low and medium priority requests
+	LevelShedMedium
+	// LevelThrottleTasks rejects the low and medium priority requests, and throttles the task processing
+	LevelThrottleTasks
+)
+
+type (
+	// Level is how much load a host sheds
+	Level int
+
+	// Config is the config of the load shedding
+	Config struct {
+		Enabled            dynamicconfig.BoolPropertyFn
+		SampleInterval     dynamicconfig.DurationPropertyFn
+		CPUThreshold       dynamicconfig.FloatPropertyFn
+		MemoryThreshold    dynamicconfig.IntPropertyFn
+		GoroutineThreshold dynamicconfig.IntPropertyFn
+	}
+
+	// Monitor periodically samples the usage of the host, and sets the level of load shedding from the resource under
+	// the highest pressure, the pressure being the usage of a resource over its threshold
+	Monitor struct {
+		status        int32
+		config        *Config
+		metricsClient metrics.Client
+		logger        log.Logger
+		sampler       sampler
+
+		level      int32
+		shutdownC  chan struct{}
+		shutdownWG sync.WaitGroup
+	}
+)
+
+// NewConfig creates the load shedding config from dynamic config
+func NewConfig(dc *dynamicconfig.Collection) *Config {
+	return &Config{
+		Enabled:            dc.GetBoolProperty(dynamicconfig.EnableLoadShedding),
+		SampleInterval:     dc.GetDurationProperty(dynamicconfig.LoadSheddingSampleInterval),
+		CPUThreshold:       dc.GetFloat64Property(dynamicconfig.LoadSheddingCPUThreshold),
+		MemoryThreshold:    dc.GetIntProperty(dynamicconfig.LoadSheddingMemoryThreshold),
+		GoroutineThreshold: dc.GetIntProperty(dynamicconfig.LoadSheddingGoroutineThreshold),
+	}
+}
+
+// NewMonitor creates a monitor of the usage of the host
+func NewMonitor(
+	config *Config,
+	metricsClient metrics.Client,
+	logger log.Logger,
+) *Monitor {
+	return &Monitor{
+		status:        common.DaemonStatusInitialized,
+		config:        config,
+		metricsClient: metricsClient,
+		logger:        logger,
+		sampler:       newRuntimeSampler(),
+		shutdownC:     make(chan struct{}),
+	}
+}
+
+// Start starts sampling the usage of the host
+func (m *Monitor) Start() {
+	if !atomic.CompareAndSwapInt32(&m.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	m.shutdownWG.Add(1)
+	go m.sampleLoop()
+}
+
+// Stop stops sampling the usage of the host, the load isn't shed anymore
+func (m *Monitor) Stop() {
+	if !atomic.CompareAndSwapInt32(&m.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+
+	close(m.shutdownC)
+	m.shutdownWG.Wait()
+	atomic.StoreInt32(&m.level, int32(LevelNone))
+}
+
+// Level returns the current level of load shedding, a nil monitor doesn't shed any load
+func (m *Monitor) Level() Level {
+	if m == nil {
+		return LevelNone
+	}
+	return Level(atomic.LoadInt32(&m.level))
+}
+
+// Allow returns a retryable error if the requests of the API are shed at the current level
+func (m *Monitor) Allow(api string) error {
+	if !m.Level().sheds(APIPriority(api)) {
+		return nil
+	}
+	m.metricsClient.Scope(metrics.LoadSheddingScope, metrics.APINameTag(api)).IncCounter(metrics.LoadSheddingRejectedCounter)
+	return &types.ServiceBusyError{
+		Message: fmt.Sprintf("Host is overloaded, %v requests are shed until its load decreases, retry later.", api),
+	}
+}
+
+// ThrottleTasks returns whether the task processing is throttled at the current level
+func (m *Monitor) ThrottleTasks() bool {
+	return m.Level() >= LevelThrottleTasks
+}
+
+func (m *Monitor) sampleLoop() {
+	defer m.shutdownWG.Done()
+
+	timer := time.NewTimer(m.config.SampleInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			m.sample()
+			timer.Reset(m.config.SampleInterval())
+		case <-m.shutdownC:
+			return
+		}
+	}
+}
+
+func (m *Monitor) sample() {
+	// the CPU usage is sampled even while disabled, so it is accurate as soon as the shedding is enabled
+	u := m.sampler.sample()
+	if !m.config.Enabled() {
+		m.setLevel(LevelNone, 0, "")
+		return
+	}
+
+	pressure, resource := m.pressure(u)
+	current := m.Level()
+	level := levelOf(pressure)
+	if level < current {
+		// the level is lowered one step per sample, so that the shedding doesn't flap with the load it removes
+		level = current - 1
+	}
+	m.setLevel(level, pressure, resource)
+}
+
+// pressure returns the highest usage of a resource over its threshold, and the name of the resource
+func (m *Monitor) pressure(u usage) (float64, string) {
+	pressure, resource := 0.0, ""
+	check := func(name string, value float64, threshold float64) {
+		if threshold <= 0 {
+			return
+		}
+		if p := value / threshold; p > pressure {
+			pressure, resource = p, name
+		}
+	}
+	check("cpu", u.cpu, m.config.CPUThreshold())
+	check("memory", float64(u.memory), float64(m.config.MemoryThreshold()))
+	check("goroutines", float64(u.goroutines), float64(m.config.GoroutineThreshold()))
+	return pressure, resource
+}
+
+func (m *Monitor) setLevel(level Level, pressure float64, resource string) {
+	scope := m.metricsClient.Scope(metrics.LoadSheddingScope)
+	scope.UpdateGauge(metrics.LoadSheddingLevelGauge, float64(level))
+	scope.UpdateGauge(metrics.LoadSheddingPressureGauge, pressure)
+
+	previous := Level(atomic.SwapInt32(&m.level, int32(level)))
+	if previous != level {
+		m.logger.Warn("Load shedding level changed",
+			tag.Value(level.String()),
+			tag.Name(resource),
+			tag.Number(int64(math.Round(pressure*100))),
+		)
+	}
+}
+
+func levelOf(pressure float64) Level {
+	switch {
+	case pressure < 1:
+		return LevelNone
+	case pressure < 1+escalationStep:
+		return LevelShedLow
+	case pressure < 1+2*escalationStep:
+		return LevelShedMedium
+	default:
+		return LevelThrottleTasks
+	}
+}
+
+// sheds returns whether the requests of a priority are rejected at the level
+func (l Level) sheds(priority Priority) bool {
+	switch l {
+	case LevelNone:
+		return false
+	case LevelShedLow:
+		return priority == PriorityLow
+	default:
+		return priority <= PriorityMedium
+	}
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelNone:
+		return "none"
+	case LevelShedLow:
+		return "shed-low"
+	case LevelShedMedium:
+		return "shed-medium"
+	case LevelThrottleTasks:
+		return "throttle-tasks"
+	default:
+		return fmt.Sprintf("level-%d", int(l))
+	}
+}
@@ -0,0 +1,149 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package loadshedding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+type fakeSampler struct {
+	usage usage
+}
+
+func (s *fakeSampler) sample() usage {
+	return s.usage
+}
+
+func newTestMonitor(enabled bool) (*Monitor, *fakeSampler) {
+	sampler := &fakeSampler{}
+	monitor := NewMonitor(
+		&Config{
+			Enabled:            dynamicconfig.GetBoolPropertyFn(enabled),
+			SampleInterval:     dynamicconfig.GetDurationPropertyFn(0),
+			CPUThreshold:       dynamicconfig.GetFloatPropertyFn(0.8),
+			MemoryThreshold:    dynamicconfig.GetIntPropertyFn(1000),
+			GoroutineThreshold: dynamicconfig.GetIntPropertyFn(0),
+		},
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		log.NewNoop(),
+	)
+	monitor.sampler = sampler
+	return monitor, sampler
+}
+
+func TestLevelOf(t *testing.T) {
+	assert.Equal(t, LevelNone, levelOf(0))
+	assert.Equal(t, LevelNone, levelOf(0.99))
+	assert.Equal(t, LevelShedLow, levelOf(1))
+	assert.Equal(t, LevelShedLow, levelOf(1.04))
+	assert.Equal(t, LevelShedMedium, levelOf(1.05))
+	assert.Equal(t, LevelShedMedium, levelOf(1.09))
+	assert.Equal(t, LevelThrottleTasks, levelOf(1.1))
+	assert.Equal(t, LevelThrottleTasks, levelOf(3))
+}
+
+func TestLevelSheds(t *testing.T) {
+	assert.False(t, LevelNone.sheds(PriorityLow))
+	assert.True(t, LevelShedLow.sheds(PriorityLow))
+	assert.False(t, LevelShedLow.sheds(PriorityMedium))
+	assert.True(t, LevelShedMedium.sheds(PriorityMedium))
+	assert.False(t, LevelShedMedium.sheds(PriorityHigh))
+	assert.True(t, LevelThrottleTasks.sheds(PriorityMedium))
+	assert.False(t, LevelThrottleTasks.sheds(PriorityHigh))
+}
+
+func TestMonitor_Sample_HighestPressure(t *testing.T) {
+	monitor, sampler := newTestMonitor(true)
+
+	sampler.usage = usage{cpu: 0.4, memory: 1020, goroutines: 1000000}
+	monitor.sample()
+	assert.Equal(t, LevelShedLow, monitor.Level())
+
+	sampler.usage = usage{cpu: 0.9, memory: 1020}
+	monitor.sample()
+	assert.Equal(t, LevelThrottleTasks, monitor.Level())
+	assert.True(t, monitor.ThrottleTasks())
+}
+
+func TestMonitor_Sample_LowersOneStepAtATime(t *testing.T) {
+	monitor, sampler := newTestMonitor(true)
+
+	sampler.usage = usage{memory: 2000}
+	monitor.sample()
+	assert.Equal(t, LevelThrottleTasks, monitor.Level())
+
+	sampler.usage = usage{}
+	monitor.sample()
+	assert.Equal(t, LevelShedMedium, monitor.Level())
+	monitor.sample()
+	assert.Equal(t, LevelShedLow, monitor.Level())
+	monitor.sample()
+	assert.Equal(t, LevelNone, monitor.Level())
+}
+
+func TestMonitor_Sample_Disabled(t *testing.T) {
+	monitor, sampler := newTestMonitor(false)
+
+	sampler.usage = usage{cpu: 1, memory: 2000}
+	monitor.sample()
+	assert.Equal(t, LevelNone, monitor.Level())
+	assert.NoError(t, monitor.Allow("DescribeCluster"))
+}
+
+func TestMonitor_Allow(t *testing.T) {
+	monitor, _ := newTestMonitor(true)
+
+	monitor.setLevel(LevelShedLow, 1, "cpu")
+	err := monitor.Allow("ListOpenWorkflowExecutions")
+	assert.IsType(t, &types.ServiceBusyError{}, err)
+	assert.NoError(t, monitor.Allow("StartWorkflowExecution"))
+	assert.NoError(t, monitor.Allow("PollForDecisionTask"))
+
+	monitor.setLevel(LevelShedMedium, 1.05, "cpu")
+	assert.IsType(t, &types.ServiceBusyError{}, monitor.Allow("StartWorkflowExecution"))
+	assert.NoError(t, monitor.Allow("PollForDecisionTask"))
+	assert.False(t, monitor.ThrottleTasks())
+}
+
+func TestMonitor_Nil(t *testing.T) {
+	var monitor *Monitor
+	assert.Equal(t, LevelNone, monitor.Level())
+	assert.NoError(t, monitor.Allow("ListOpenWorkflowExecutions"))
+	assert.False(t, monitor.ThrottleTasks())
+}
+
+func TestMonitor_StartStop(t *testing.T) {
+	monitor, _ := newTestMonitor(true)
+	monitor.Start()
+	monitor.setLevel(LevelShedLow, 1, "cpu")
+	monitor.Stop()
+	assert.Equal(t, LevelNone, monitor.Level())
+}
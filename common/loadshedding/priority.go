@@ -0,0 +1,94 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package loadshedding
+
+const (
+	// PriorityLow is the priority of the requests which only read the state of the cluster, such as the visibility
+	// APIs, they are rejected first
+	PriorityLow Priority = iota + 1
+	// PriorityMedium is the priority of the requests which aren't classified otherwise, such as starting or signaling
+	// workflows
+	PriorityMedium
+	// PriorityHigh is the priority of the requests making the existing workflows progress, such as polling and
+	// completing tasks, they are never rejected as they are what brings the load down
+	PriorityHigh
+)
+
+type (
+	// Priority is the priority of the requests of an API
+	Priority int
+)
+
+var apiPriorities = map[string]Priority{
+	// visibility and introspection APIs
+	"ListOpenWorkflowExecutions":     PriorityLow,
+	"ListClosedWorkflowExecutions":   PriorityLow,
+	"ListWorkflowExecutions":         PriorityLow,
+	"ListArchivedWorkflowExecutions": PriorityLow,
+	"ScanWorkflowExecutions":         PriorityLow,
+	"CountWorkflowExecutions":        PriorityLow,
+	"GetSearchAttributes":            PriorityLow,
+	"ListDomains":                    PriorityLow,
+	"DescribeTaskList":               PriorityLow,
+	"ListTaskListPartitions":         PriorityLow,
+	"GetTaskListsByDomain":           PriorityLow,
+	"DescribeHistoryHost":            PriorityLow,
+	"DescribeShardDistribution":      PriorityLow,
+	"DescribeCluster":                PriorityLow,
+	"DescribeMutableState":           PriorityLow,
+	"ReadDLQMessages":                PriorityLow,
+	"CountDLQMessages":               PriorityLow,
+
+	// worker APIs
+	"PollForDecisionTask":              PriorityHigh,
+	"PollForActivityTask":              PriorityHigh,
+	"RespondDecisionTaskCompleted":     PriorityHigh,
+	"RespondDecisionTaskFailed":        PriorityHigh,
+	"RespondActivityTaskCompleted":     PriorityHigh,
+	"RespondActivityTaskCompletedByID": PriorityHigh,
+	"RespondActivityTaskFailed":        PriorityHigh,
+	"RespondActivityTaskFailedByID":    PriorityHigh,
+	"RespondActivityTaskCanceled":      PriorityHigh,
+	"RespondActivityTaskCanceledByID":  PriorityHigh,
+	"RecordActivityTaskHeartbeat":      PriorityHigh,
+	"RecordActivityTaskHeartbeatByID":  PriorityHigh,
+	"RespondQueryTaskCompleted":        PriorityHigh,
+	"ResetStickyTaskList":              PriorityHigh,
+
+	// APIs between the services making the tasks progress, and health checks
+	"RecordActivityTaskStarted":     PriorityHigh,
+	"RecordDecisionTaskStarted":     PriorityHigh,
+	"AddActivityTask":               PriorityHigh,
+	"AddDecisionTask":               PriorityHigh,
+	"ScheduleDecisionTask":          PriorityHigh,
+	"RecordChildExecutionCompleted": PriorityHigh,
+	"Health":                        PriorityHigh,
+}
+
+// APIPriority returns the priority of the requests of an API, the API is the method name of a procedure
+func APIPriority(api string) Priority {
+	if priority, ok := apiPriorities[api]; ok {
+		return priority
+	}
+	return PriorityMedium
+}
@@ -0,0 +1,83 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package loadshedding
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"syscall"
+	"time"
+)
+
+// heapObjectsMetric is the runtime metric of the memory occupied by the live and not yet swept heap objects
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+type (
+	// usage is the usage of the resources of the host at a point in time
+	usage struct {
+		// cpu is the fraction of the CPUs usable by the process it used since the previous sample
+		cpu        float64
+		memory     int64
+		goroutines int
+	}
+
+	sampler interface {
+		sample() usage
+	}
+
+	runtimeSampler struct {
+		lastTime time.Time
+		lastCPU  time.Duration
+	}
+)
+
+func newRuntimeSampler() *runtimeSampler {
+	return &runtimeSampler{lastTime: time.Now(), lastCPU: processCPUTime()}
+}
+
+func (s *runtimeSampler) sample() usage {
+	now := time.Now()
+	cpuTime := processCPUTime()
+	u := usage{goroutines: runtime.NumGoroutine()}
+	if elapsed := now.Sub(s.lastTime); elapsed > 0 {
+		u.cpu = float64(cpuTime-s.lastCPU) / float64(elapsed) / float64(runtime.GOMAXPROCS(0))
+	}
+	s.lastTime, s.lastCPU = now, cpuTime
+
+	// unlike runtime.ReadMemStats, reading the runtime metrics doesn't stop the world
+	heap := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(heap)
+	if heap[0].Value.Kind() == metrics.KindUint64 {
+		u.memory = int64(heap[0].Value.Uint64())
+	}
+	return u
+}
+
+// processCPUTime returns the user and system CPU time used by the process
+func processCPUTime() time.Duration {
+	var rusage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &rusage); err != nil {
+		return 0
+	}
+	return time.Duration(rusage.Utime.Nano() + rusage.Stime.Nano())
+}
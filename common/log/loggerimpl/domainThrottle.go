@@ -0,0 +1,197 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package loggerimpl
+
+import (
+	"time"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/quotas"
+)
+
+type (
+	domainThrottledLogger struct {
+		log        log.Logger
+		rps        dynamicconfig.MapPropertyFn
+		domainName func(domainID string) (string, error)
+		limiters   map[string]*quotas.Collection
+
+		// domain of the tags the logger was created with
+		domainTags domainTags
+	}
+
+	domainTags struct {
+		name string
+		id   string
+	}
+)
+
+var _ log.Logger = (*domainThrottledLogger)(nil)
+
+const (
+	skipForDomainThrottledLogger = 4
+	// domainLogRPSTTL is the interval after which raised limits are applied, lowered limits are applied immediately
+	domainLogRPSTTL = time.Minute
+)
+
+const (
+	logLevelDebug = "debug"
+	logLevelInfo  = "info"
+	logLevelWarn  = "warn"
+	logLevelError = "error"
+)
+
+var (
+	domainNameTagKey = tagKey(tag.WorkflowDomainName(""))
+	domainIDTagKey   = tagKey(tag.WorkflowDomainID(""))
+)
+
+// NewDomainThrottledLogger returns an implementation of logger that throttles the log messages
+// of each domain separately, so that a domain emitting lots of logs doesn't drown out the logs of other domains.
+// The domain of a message is taken from the domain name or domain id tag, domain ids are resolved to
+// names with the given function. The limits are looked up per domain and keyed by log level.
+//
+// Messages without domain and Fatal logs are always emitted without any throttling
+func NewDomainThrottledLogger(
+	logger log.Logger,
+	rps dynamicconfig.MapPropertyFn,
+	domainName func(domainID string) (string, error),
+) log.Logger {
+	if lg, ok := logger.(*loggerImpl); ok {
+		logger = &loggerImpl{
+			zapLogger: lg.zapLogger,
+			skip:      skipForDomainThrottledLogger,
+		}
+	}
+
+	tl := &domainThrottledLogger{
+		log:        logger,
+		rps:        rps,
+		domainName: domainName,
+		limiters:   make(map[string]*quotas.Collection),
+	}
+	for _, level := range []string{logLevelDebug, logLevelInfo, logLevelWarn, logLevelError} {
+		level := level
+		tl.limiters[level] = quotas.NewCollection(func(domain string) quotas.Limiter {
+			rps := float64(tl.levelRPS(domain, level))
+			return quotas.NewRateLimiter(&rps, domainLogRPSTTL, 1)
+		})
+	}
+	return tl
+}
+
+func (tl *domainThrottledLogger) Debug(msg string, tags ...tag.Tag) {
+	if tl.allow(logLevelDebug, tags) {
+		tl.log.Debug(msg, tags...)
+	}
+}
+
+func (tl *domainThrottledLogger) Info(msg string, tags ...tag.Tag) {
+	if tl.allow(logLevelInfo, tags) {
+		tl.log.Info(msg, tags...)
+	}
+}
+
+func (tl *domainThrottledLogger) Warn(msg string, tags ...tag.Tag) {
+	if tl.allow(logLevelWarn, tags) {
+		tl.log.Warn(msg, tags...)
+	}
+}
+
+func (tl *domainThrottledLogger) Error(msg string, tags ...tag.Tag) {
+	if tl.allow(logLevelError, tags) {
+		tl.log.Error(msg, tags...)
+	}
+}
+
+func (tl *domainThrottledLogger) Fatal(msg string, tags ...tag.Tag) {
+	tl.log.Fatal(msg, tags...)
+}
+
+// Return a logger with the specified key-value pairs set, to be included in a subsequent normal logging call
+func (tl *domainThrottledLogger) WithTags(tags ...tag.Tag) log.Logger {
+	return &domainThrottledLogger{
+		log:        tl.log.WithTags(tags...),
+		rps:        tl.rps,
+		domainName: tl.domainName,
+		limiters:   tl.limiters,
+		domainTags: tl.domainTags.with(tags),
+	}
+}
+
+func (tl *domainThrottledLogger) allow(level string, tags []tag.Tag) bool {
+	domain := tl.domain(tags)
+	if domain == "" {
+		return true
+	}
+	rps := float64(tl.levelRPS(domain, level))
+	if rps <= 0 {
+		return true
+	}
+	limiter := tl.limiters[level].For(domain).(*quotas.RateLimiter)
+	limiter.UpdateMaxDispatch(&rps)
+	return limiter.Allow()
+}
+
+func (tl *domainThrottledLogger) domain(tags []tag.Tag) string {
+	domain := tl.domainTags.with(tags)
+	if domain.name != "" || domain.id == "" || tl.domainName == nil {
+		return domain.name
+	}
+	name, err := tl.domainName(domain.id)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// levelRPS returns the log rate limit of a domain for a level, zero means the level is not throttled
+func (tl *domainThrottledLogger) levelRPS(domain string, level string) int {
+	switch rps := tl.rps(dynamicconfig.DomainFilter(domain))[level].(type) {
+	case int:
+		return rps
+	case float64:
+		return int(rps)
+	default:
+		return 0
+	}
+}
+
+func (d domainTags) with(tags []tag.Tag) domainTags {
+	for _, t := range tags {
+		field := t.Field()
+		switch field.Key {
+		case domainNameTagKey:
+			d.name = field.String
+		case domainIDTagKey:
+			d.id = field.String
+		}
+	}
+	return d
+}
+
+func tagKey(t tag.Tag) string {
+	return t.Field().Key
+}
@@ -0,0 +1,97 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package loggerimpl
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/tag"
+)
+
+func TestDomainThrottledLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	rps := func(opts ...dynamicconfig.FilterOption) map[string]interface{} {
+		filters := make(map[dynamicconfig.Filter]interface{})
+		for _, opt := range opts {
+			opt(filters)
+		}
+		if filters[dynamicconfig.DomainName] == "noisy-domain" {
+			return map[string]interface{}{"error": 1, "info": float64(1)}
+		}
+		return nil
+	}
+	domainName := func(domainID string) (string, error) {
+		if domainID == "noisy-domain-id" {
+			return "noisy-domain", nil
+		}
+		return "", errors.New("domain not found")
+	}
+	logger := NewDomainThrottledLogger(NewLogger(zap.New(core)), rps, domainName)
+
+	for i := 0; i < 10; i++ {
+		logger.Error("noisy error", tag.WorkflowDomainName("noisy-domain"))
+		logger.WithTags(tag.WorkflowDomainID("noisy-domain-id")).Info("noisy info")
+		logger.Warn("noisy warn", tag.WorkflowDomainName("noisy-domain"))
+		logger.Error("quiet error", tag.WorkflowDomainName("quiet-domain"))
+		logger.Error("unknown domain error", tag.WorkflowDomainID("unknown-domain-id"))
+		logger.Error("error without domain")
+	}
+
+	count := func(msg string) int {
+		return logs.FilterMessage(msg).Len()
+	}
+	// only the levels limited for the noisy domain are throttled
+	assert.Equal(t, 1, count("noisy error"))
+	assert.Equal(t, 1, count("noisy info"))
+	assert.Equal(t, 10, count("noisy warn"))
+	assert.Equal(t, 10, count("quiet error"))
+	assert.Equal(t, 10, count("unknown domain error"))
+	assert.Equal(t, 10, count("error without domain"))
+}
+
+func TestDomainThrottledLogger_CallAt(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	rps := func(opts ...dynamicconfig.FilterOption) map[string]interface{} {
+		return nil
+	}
+	logger := NewDomainThrottledLogger(NewLogger(zap.New(core)), rps, nil)
+
+	preCaller := caller(1)
+	logger.WithTags(tag.WorkflowDomainName("domain")).Info("test info")
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 1)
+	sps := strings.Split(preCaller, ":")
+	par, err := strconv.Atoi(sps[1])
+	assert.Nil(t, err)
+	assert.Equal(t, fmt.Sprintf("domainThrottle_test.go:%v", par+1), entries[0].ContextMap()[tag.LoggingCallAtKey])
+}
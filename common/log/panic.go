@@ -23,10 +23,22 @@ package log
 import (
 	"fmt"
 	"runtime/debug"
+	"sync/atomic"
 
 	"github.com/uber/cadence/common/log/tag"
 )
 
+// PanicHandler is notified of a panic captured by CapturePanic, with the stack trace of the goroutine which panicked
+type PanicHandler func(err error, stackTrace string)
+
+// panicHandler holds the PanicHandler of the process, it is shared by all services of the process
+var panicHandler atomic.Value
+
+// SetPanicHandler sets the handler notified of the panics captured by CapturePanic, in addition to them being logged
+func SetPanicHandler(handler PanicHandler) {
+	panicHandler.Store(handler)
+}
+
 // CapturePanic is used to capture panic, it will log the panic and also return the error through pointer.
 // If the panic value is not error then a default error is returned
 // We have to use pointer is because in golang: "recover return nil if was not called directly by a deferred function."
@@ -44,6 +56,9 @@ func CapturePanic(errPanic interface{}, logger Logger, retError *error) {
 		st := string(debug.Stack())
 
 		logger.Error("Panic is captured", tag.SysStackTrace(st), tag.Error(err))
+		if handler, ok := panicHandler.Load().(PanicHandler); ok && handler != nil {
+			handler(err, st)
+		}
 
 		if retError != nil {
 			*retError = err
@@ -303,6 +303,16 @@ func WorkflowEventCount(eventCount int) Tag {
 	return newInt("wf-event-count", eventCount)
 }
 
+// WorkflowPendingActivityCount returns tag for PendingActivityCount
+func WorkflowPendingActivityCount(pendingActivityCount int) Tag {
+	return newInt("wf-pending-activity-count", pendingActivityCount)
+}
+
+// WorkflowPendingChildCount returns tag for PendingChildCount
+func WorkflowPendingChildCount(pendingChildCount int) Tag {
+	return newInt("wf-pending-child-count", pendingChildCount)
+}
+
 func WorkflowEventType(eventType string) Tag {
 	return newStringTag("wf-event-type", eventType)
 }
@@ -457,6 +467,46 @@ func Bool(b bool) Tag {
 	return newBoolTag("bool", b)
 }
 
+// APIName returns tag for the name of an RPC method
+func APIName(name string) Tag {
+	return newStringTag("api-name", name)
+}
+
+// Caller returns tag for the caller of an RPC
+func Caller(caller string) Tag {
+	return newStringTag("caller", caller)
+}
+
+// Latency returns tag for Latency
+func Latency(latency time.Duration) Tag {
+	return newDurationTag("latency", latency)
+}
+
+// LatencyThreshold returns tag for LatencyThreshold
+func LatencyThreshold(threshold time.Duration) Tag {
+	return newDurationTag("latency-threshold", threshold)
+}
+
+// DependencyLatency returns tag for the total time spent calling a dependency
+func DependencyLatency(dependency string, latency time.Duration) Tag {
+	return newDurationTag(dependency+"-latency", latency)
+}
+
+// DependencyCalls returns tag for the number of calls made to a dependency
+func DependencyCalls(dependency string, calls int64) Tag {
+	return newInt64(dependency+"-calls", calls)
+}
+
+// PayloadSize returns tag for the size in bytes of an RPC payload
+func PayloadSize(size int) Tag {
+	return newInt("payload-size", size)
+}
+
+// PayloadSizeLimit returns tag for the size limit in bytes of an RPC payload
+func PayloadSizeLimit(limit int) Tag {
+	return newInt("payload-size-limit", limit)
+}
+
 /* Tags for logging manual access */
 
 // RequestCaller returns tag for caller (the name of the service making this request)
@@ -132,6 +132,7 @@ var (
 	ComponentCrossClusterTaskFetcher    = component("cross-cluster-task-fetcher")
 	ComponentShardScanner               = component("shardscanner-scanner")
 	ComponentShardFixer                 = component("shardscanner-fixer")
+	ComponentProber                     = component("prober")
 )
 
 // Pre-defined values for TagSysLifecycle
@@ -219,6 +220,7 @@ var (
 	StoreOperationCompleteTasksLessThan = storeOperation("complete-tasks-less-than")
 	StoreOperationLeaseTaskList         = storeOperation("lease-task-list")
 	StoreOperationUpdateTaskList        = storeOperation("update-task-list")
+	StoreOperationGetTaskList           = storeOperation("get-task-list")
 	StoreOperationListTaskList          = storeOperation("list-task-list")
 	StoreOperationDeleteTaskList        = storeOperation("delete-task-list")
 	StoreOperationStopTaskList          = storeOperation("stop-task-list")
@@ -269,8 +271,15 @@ var (
 	StoreOperationGetDLQSize                 = storeOperation("get-dlq-size")
 	StoreOperationDeleteMessageFromDLQ       = storeOperation("delete-message-from-dlq")
 
-	StoreOperationFetchDynamicConfig  = storeOperation("fetch-dynamic-config")
-	StoreOperationUpdateDynamicConfig = storeOperation("update-dynamic-config")
+	StoreOperationFetchDynamicConfig       = storeOperation("fetch-dynamic-config")
+	StoreOperationUpdateDynamicConfig      = storeOperation("update-dynamic-config")
+	StoreOperationListDynamicConfigChanges = storeOperation("list-dynamic-config-changes")
+	StoreOperationRecordDomainUsage        = storeOperation("record-domain-usage")
+	StoreOperationListDomainUsage          = storeOperation("list-domain-usage")
+	StoreOperationFetchAPIKeys             = storeOperation("fetch-api-keys")
+	StoreOperationUpdateAPIKeys            = storeOperation("update-api-keys")
+	StoreOperationFetchResharding          = storeOperation("fetch-resharding")
+	StoreOperationUpdateResharding         = storeOperation("update-resharding")
 )
 
 // Pre-defined values for TagSysClientOperation
@@ -301,6 +310,18 @@ var (
 	AdminClientOperationUpdateDynamicConfig               = clientOperation("admin-update-dynamic-config")
 	AdminClientOperationRestoreDynamicConfig              = clientOperation("admin-restore-dynamic-config")
 	AdminClientOperationListDynamicConfig                 = clientOperation("admin-list-dynamic-config")
+	AdminClientOperationUnloadTaskList                    = clientOperation("admin-unload-task-list")
+	AdminClientOperationGetTaskListDrainStatus            = clientOperation("admin-get-task-list-drain-status")
+	AdminClientOperationPauseTaskList                     = clientOperation("admin-pause-task-list")
+	AdminClientOperationResumeTaskList                    = clientOperation("admin-resume-task-list")
+	AdminClientOperationListDynamicConfigChanges          = clientOperation("admin-list-dynamic-config-changes")
+	AdminClientOperationGetDomainUsage                    = clientOperation("admin-get-domain-usage")
+	AdminClientOperationInvalidateCaches                  = clientOperation("admin-invalidate-caches")
+	AdminClientOperationListEffectiveDynamicConfig        = clientOperation("admin-list-effective-dynamic-config")
+	AdminClientOperationDescribeShardOwnership            = clientOperation("admin-describe-shard-ownership")
+	AdminClientOperationDescribePersistenceLatencyHeatmap = clientOperation("admin-describe-persistence-latency-heatmap")
+	AdminClientOperationDescribeWatchdog                  = clientOperation("admin-describe-watchdog")
+	AdminClientOperationDescribeDomainSLO                 = clientOperation("admin-describe-domain-slo")
 	AdminDeleteWorkflow                                   = clientOperation("admin-delete-workflow")
 	MaintainCorruptWorkflow                               = clientOperation("maintain-corrupt-workflow")
 
@@ -348,6 +369,10 @@ var (
 
 	HistoryClientOperationStartWorkflowExecution            = clientOperation("history-start-wf-execution")
 	HistoryClientOperationDescribeHistoryHost               = clientOperation("history-describe-history-host")
+	HistoryClientOperationInvalidateCaches                  = clientOperation("history-invalidate-caches")
+	HistoryClientOperationDescribeShardOwnership            = clientOperation("history-describe-shard-ownership")
+	HistoryClientOperationDescribePersistenceLatencyHeatmap = clientOperation("history-describe-persistence-latency-heatmap")
+	HistoryClientOperationDescribeWatchdog                  = clientOperation("history-describe-watchdog")
 	HistoryClientOperationCloseShard                        = clientOperation("history-close-shard")
 	HistoryClientOperationResetQueue                        = clientOperation("history-reset-queue")
 	HistoryClientOperationDescribeQueue                     = clientOperation("history-describe-queue")
@@ -390,16 +415,23 @@ var (
 	HistoryClientOperationRespondCrossClusterTasksCompleted = clientOperation("history-respond-cross-cluster-tasks-completed")
 	HistoryClientOperationGetFailoverInfo                   = clientOperation("history-get-failover-info")
 
-	MatchingClientOperationAddActivityTask        = clientOperation("matching-add-activity-task")
-	MatchingClientOperationAddDecisionTask        = clientOperation("matching-add-decision-task")
-	MatchingClientOperationPollForActivityTask    = clientOperation("matching-poll-for-activity-task")
-	MatchingClientOperationPollForDecisionTask    = clientOperation("matching-poll-for-decision-task")
-	MatchingClientOperationQueryWorkflow          = clientOperation("matching-query-wf")
-	MatchingClientOperationQueryTaskCompleted     = clientOperation("matching-query-task-completed")
-	MatchingClientOperationCancelOutstandingPoll  = clientOperation("matching-cancel-outstanding-poll")
-	MatchingClientOperationDescribeTaskList       = clientOperation("matching-describe-task-list")
-	MatchingClientOperationListTaskListPartitions = clientOperation("matching-list-task-list-partitions")
-	MatchingClientOperationGetTaskListsByDomain   = clientOperation("get-task-list-for-domain")
+	MatchingClientOperationAddActivityTask            = clientOperation("matching-add-activity-task")
+	MatchingClientOperationAddDecisionTask            = clientOperation("matching-add-decision-task")
+	MatchingClientOperationPollForActivityTask        = clientOperation("matching-poll-for-activity-task")
+	MatchingClientOperationPollForDecisionTask        = clientOperation("matching-poll-for-decision-task")
+	MatchingClientOperationQueryWorkflow              = clientOperation("matching-query-wf")
+	MatchingClientOperationQueryTaskCompleted         = clientOperation("matching-query-task-completed")
+	MatchingClientOperationCancelOutstandingPoll      = clientOperation("matching-cancel-outstanding-poll")
+	MatchingClientOperationDescribeTaskList           = clientOperation("matching-describe-task-list")
+	MatchingClientOperationListTaskListPartitions     = clientOperation("matching-list-task-list-partitions")
+	MatchingClientOperationGetTaskListsByDomain       = clientOperation("get-task-list-for-domain")
+	MatchingClientOperationUnloadTaskList             = clientOperation("matching-unload-task-list")
+	MatchingClientOperationGetTaskListDrainStatus     = clientOperation("matching-get-task-list-drain-status")
+	MatchingClientOperationPauseTaskList              = clientOperation("matching-pause-task-list")
+	MatchingClientOperationResumeTaskList             = clientOperation("matching-resume-task-list")
+	MatchingClientOperationRecordActivityTaskFinished = clientOperation("matching-record-activity-task-finished")
+	MatchingClientOperationInvalidateCaches           = clientOperation("matching-invalidate-caches")
+	MatchingClientOperationDescribeWatchdog           = clientOperation("matching-describe-watchdog")
 )
 
 // Pre-defined values for TagIDType
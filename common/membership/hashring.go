@@ -234,6 +234,8 @@ func (r *ring) refresh() error {
 	r.members.Lock()
 	defer r.members.Unlock()
 	newMembersMap, changed := r.compareMembers(members)
+	// the metadata of the members, e.g. their zone, is kept up to date even when the ring doesn't change
+	r.members.keys = newMembersMap
 	if !changed {
 		return nil
 	}
@@ -242,7 +244,6 @@ func (r *ring) refresh() error {
 	for _, member := range members {
 		ring.AddMembers(member)
 	}
-	r.members.keys = newMembersMap
 	r.members.refreshed = time.Now()
 	r.value.Store(ring)
 	r.logger.Info("refreshed ring members", tag.Value(members))
@@ -25,6 +25,8 @@ package membership
 import (
 	"fmt"
 	"net"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -32,6 +34,9 @@ import (
 const (
 	PortTchannel = "tchannel"
 	PortGRPC     = "grpc"
+
+	// ZoneLabel is the label of the availability zone of a host, in the membership metadata
+	ZoneLabel = "zone"
 )
 
 // PortMap is a map of port names to port numbers.
@@ -43,6 +48,7 @@ type HostInfo struct {
 	ip       string // @todo should we set this to net.IP ?
 	identity string
 	portMap  PortMap // ports host is listening to
+	zone     string  // availability zone of the host, empty if unknown
 }
 
 // NewHostInfo creates a new HostInfo instance
@@ -74,6 +80,17 @@ func NewDetailedHostInfo(addr string, identity string, portMap PortMap) HostInfo
 	}
 }
 
+// WithZone returns a copy of the host info in the availability zone
+func (hi HostInfo) WithZone(zone string) HostInfo {
+	hi.zone = zone
+	return hi
+}
+
+// Zone returns the availability zone of the host, empty if unknown
+func (hi HostInfo) Zone() string {
+	return hi.zone
+}
+
 // GetAddress returns the ip:port address
 func (hi HostInfo) GetAddress() string {
 	return hi.addr
@@ -133,5 +150,28 @@ func (hi HostInfo) SetLabel(key string, value string) {
 
 // String will return a human-readable host details
 func (hi HostInfo) String() string {
-	return fmt.Sprintf("addr: %s, identity: %s, portMap: %s", hi.addr, hi.identity, hi.portMap)
+	return fmt.Sprintf("addr: %s, identity: %s, portMap: %s, zone: %s", hi.addr, hi.identity, hi.portMap, hi.zone)
+}
+
+// DiffHosts returns the addresses added, updated and removed from the previous hosts to the current ones
+func DiffHosts(previous, current []HostInfo) (added, updated, removed []string) {
+	previousByAddress := make(map[string]HostInfo, len(previous))
+	for _, host := range previous {
+		previousByAddress[host.GetAddress()] = host
+	}
+	for _, host := range current {
+		previousHost, ok := previousByAddress[host.GetAddress()]
+		switch {
+		case !ok:
+			added = append(added, host.GetAddress())
+		case !reflect.DeepEqual(previousHost, host):
+			updated = append(updated, host.GetAddress())
+		}
+		delete(previousByAddress, host.GetAddress())
+	}
+	for address := range previousByAddress {
+		removed = append(removed, address)
+	}
+	sort.Strings(removed)
+	return added, updated, removed
 }
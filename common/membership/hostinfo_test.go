@@ -56,3 +56,26 @@ func TestBelongs(t *testing.T) {
 	assert.False(t, belongs, "portmap has no such port, should return empty without an error")
 	assert.NoError(t, err)
 }
+
+func TestDiffHosts(t *testing.T) {
+	previous := []HostInfo{
+		NewDetailedHostInfo("127.0.0.1:1234", "", PortMap{PortGRPC: 3333}),
+		NewDetailedHostInfo("127.0.0.2:1234", "", PortMap{PortGRPC: 3333}),
+		NewDetailedHostInfo("127.0.0.3:1234", "", PortMap{PortGRPC: 3333}),
+	}
+	current := []HostInfo{
+		NewDetailedHostInfo("127.0.0.1:1234", "", PortMap{PortGRPC: 3333}),
+		NewDetailedHostInfo("127.0.0.2:1234", "", PortMap{PortGRPC: 4444}),
+		NewDetailedHostInfo("127.0.0.4:1234", "", PortMap{PortGRPC: 3333}),
+	}
+
+	added, updated, removed := DiffHosts(previous, current)
+	assert.Equal(t, []string{"127.0.0.4:1234"}, added)
+	assert.Equal(t, []string{"127.0.0.2:1234"}, updated)
+	assert.Equal(t, []string{"127.0.0.3:1234"}, removed)
+
+	added, updated, removed = DiffHosts(current, current)
+	assert.Empty(t, added)
+	assert.Empty(t, updated)
+	assert.Empty(t, removed)
+}
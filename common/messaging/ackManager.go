@@ -22,6 +22,7 @@ package messaging
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"go.uber.org/atomic"
@@ -30,15 +31,28 @@ import (
 	"github.com/uber/cadence/common/log/tag"
 )
 
-type ackManager struct {
-	sync.RWMutex
-	outstandingMessages map[int64]bool // key->itemID, value->(true for acked/completed, false->for non acked)
-	readLevel           int64          // Maximum itemID inserted into outstandingMessages
-	ackLevel            int64          // Maximum itemID below which all messages are acked
-	backlogCounter      atomic.Int64
-	logIncontinuousErr  bool // emit error for itemID being incontinuous when consuming for potential bugs
-	logger              log.Logger
-}
+type (
+	// ackManager tracks the acked items above the ack level as ranges, one range for each gap between
+	// the items which are not acked yet, so that an item which is never acked only holds back the ack level
+	// while the items acked after it are still known and can be cleaned up with GetCompletedItems
+	ackManager struct {
+		sync.RWMutex
+		pending            []int64    // itemIDs read but not acked yet, in increasing order
+		ackedRanges        []ackRange // acked itemIDs above ackLevel, in increasing order
+		completed          []int64    // acked itemIDs above ackLevel which are not removed yet, in increasing order
+		readLevel          int64      // Maximum itemID read
+		ackLevel           int64      // Maximum itemID below which all messages are acked
+		backlogCounter     atomic.Int64
+		logIncontinuousErr bool // emit error for itemID being incontinuous when consuming for potential bugs
+		logger             log.Logger
+	}
+
+	// ackRange is the lowest and the highest acked itemIDs between two pending items
+	ackRange struct {
+		start int64
+		end   int64
+	}
+)
 
 // NewAckManager returns a AckManager without monitoring the itemIDs continousness.
 // For example, our internal matching task queue doesn't guarantee it.
@@ -56,11 +70,10 @@ func NewContinuousAckManager(logger log.Logger) AckManager {
 
 func newAckManager(logIncontinuousErr bool, logger log.Logger) AckManager {
 	return &ackManager{
-		logger:              logger,
-		outstandingMessages: make(map[int64]bool),
-		readLevel:           -1,
-		ackLevel:            -1,
-		logIncontinuousErr:  logIncontinuousErr,
+		logger:             logger,
+		readLevel:          -1,
+		ackLevel:           -1,
+		logIncontinuousErr: logIncontinuousErr,
 	}
 }
 
@@ -73,9 +86,14 @@ func (m *ackManager) ReadItem(itemID int64) error {
 	if m.readLevel >= itemID {
 		return fmt.Errorf("next item ID is less than or equal to current read level. itemID %d, readLevel %d", itemID, m.readLevel)
 	}
-	if _, ok := m.outstandingMessages[itemID]; ok {
+	_, isPending := searchItem(m.pending, itemID)
+	_, isCompleted := searchItem(m.completed, itemID)
+	if isPending || isCompleted {
 		return fmt.Errorf("already present in outstanding items but hasn't added itemID:%d", itemID)
 	}
+	if m.logIncontinuousErr && m.readLevel != -1 && itemID != m.readLevel+1 {
+		m.logger.Error("potential bug, an item is probably skipped when adding", tag.TaskID(m.readLevel+1))
+	}
 	m.readLevel = itemID
 	if m.ackLevel == -1 {
 		// because of ordering, the first itemID is the minimum to ack
@@ -84,39 +102,64 @@ func (m *ackManager) ReadItem(itemID int64) error {
 			tag.TaskID(itemID),
 		)
 	}
-	m.outstandingMessages[itemID] = false // true is for acked
+	m.pending = insertItem(m.pending, itemID)
 	return nil
 }
 
 func (m *ackManager) AckItem(itemID int64) (ackLevel int64) {
 	m.Lock()
 	defer m.Unlock()
-	if completed, ok := m.outstandingMessages[itemID]; ok && !completed {
-		m.outstandingMessages[itemID] = true
-		m.backlogCounter.Dec()
-	} else {
+	idx, ok := searchItem(m.pending, itemID)
+	if !ok {
 		m.logger.Warn("Duplicated completion for item",
 			tag.TaskID(itemID))
+		return m.ackLevel
 	}
-
-	// Update ackLevel
-	for current := m.ackLevel + 1; current <= m.readLevel; current++ {
-		if acked, ok := m.outstandingMessages[current]; ok {
-			if acked {
-				m.ackLevel = current
-				delete(m.outstandingMessages, current)
-			} else {
-				return m.ackLevel
-			}
-		} else {
-			if m.logIncontinuousErr {
-				m.logger.Error("potential bug, an item is probably skipped when adding", tag.TaskID(current))
-			}
-		}
+	m.pending = append(m.pending[:idx], m.pending[idx+1:]...)
+	m.backlogCounter.Dec()
+	if itemID <= m.ackLevel {
+		return m.ackLevel
 	}
+
+	m.completed = insertItem(m.completed, itemID)
+	m.addAckedItemLocked(itemID)
+	m.updateAckLevelLocked()
 	return m.ackLevel
 }
 
+// addAckedItemLocked merges the acked item with the ranges next to it which are not separated from it by a pending item
+func (m *ackManager) addAckedItemLocked(itemID int64) {
+	idx := sort.Search(len(m.ackedRanges), func(i int) bool { return m.ackedRanges[i].start > itemID })
+	merged := ackRange{start: itemID, end: itemID}
+	if idx > 0 && !m.hasPendingBetweenLocked(m.ackedRanges[idx-1].end, itemID) {
+		idx--
+		merged.start = m.ackedRanges[idx].start
+		m.ackedRanges = append(m.ackedRanges[:idx], m.ackedRanges[idx+1:]...)
+	}
+	if idx < len(m.ackedRanges) && !m.hasPendingBetweenLocked(itemID, m.ackedRanges[idx].start) {
+		merged.end = m.ackedRanges[idx].end
+		m.ackedRanges = append(m.ackedRanges[:idx], m.ackedRanges[idx+1:]...)
+	}
+	m.ackedRanges = append(m.ackedRanges, ackRange{})
+	copy(m.ackedRanges[idx+1:], m.ackedRanges[idx:])
+	m.ackedRanges[idx] = merged
+}
+
+// updateAckLevelLocked moves the ack level to the end of the first acked range unless a pending item precedes it
+func (m *ackManager) updateAckLevelLocked() {
+	if len(m.ackedRanges) == 0 || m.hasPendingBetweenLocked(m.ackLevel, m.ackedRanges[0].start) {
+		return
+	}
+	m.ackLevel = m.ackedRanges[0].end
+	m.ackedRanges = m.ackedRanges[1:]
+	m.completed = m.completed[sort.Search(len(m.completed), func(i int) bool { return m.completed[i] > m.ackLevel }):]
+}
+
+func (m *ackManager) hasPendingBetweenLocked(low int64, high int64) bool {
+	idx := sort.Search(len(m.pending), func(i int) bool { return m.pending[i] > low })
+	return idx < len(m.pending) && m.pending[idx] < high
+}
+
 func (m *ackManager) GetReadLevel() int64 {
 	m.RLock()
 	defer m.RUnlock()
@@ -140,6 +183,15 @@ func (m *ackManager) SetAckLevel(ackLevel int64) {
 	defer m.Unlock()
 	if ackLevel > m.ackLevel {
 		m.ackLevel = ackLevel
+		for len(m.ackedRanges) > 0 && m.ackedRanges[0].start <= ackLevel {
+			if m.ackedRanges[0].end > ackLevel {
+				m.ackedRanges[0].start = ackLevel + 1
+				break
+			}
+			m.ackedRanges = m.ackedRanges[1:]
+		}
+		m.completed = m.completed[sort.Search(len(m.completed), func(i int) bool { return m.completed[i] > ackLevel }):]
+		m.updateAckLevelLocked()
 	}
 	if ackLevel > m.readLevel {
 		m.readLevel = ackLevel
@@ -149,3 +201,43 @@ func (m *ackManager) SetAckLevel(ackLevel int64) {
 func (m *ackManager) GetBacklogCount() int64 {
 	return m.backlogCounter.Load()
 }
+
+func (m *ackManager) GetCompletedItems(maxCount int) []int64 {
+	m.RLock()
+	defer m.RUnlock()
+	count := len(m.completed)
+	if maxCount < count {
+		count = maxCount
+	}
+	items := make([]int64, count)
+	copy(items, m.completed)
+	return items
+}
+
+func (m *ackManager) RemoveCompletedItems(itemIDs []int64) {
+	m.Lock()
+	defer m.Unlock()
+	for _, itemID := range itemIDs {
+		if idx, ok := searchItem(m.completed, itemID); ok {
+			m.completed = append(m.completed[:idx], m.completed[idx+1:]...)
+		}
+	}
+}
+
+// searchItem returns the index of the item in the sorted items, or the index where it would be inserted
+func searchItem(items []int64, itemID int64) (int, bool) {
+	idx := sort.Search(len(items), func(i int) bool { return items[i] >= itemID })
+	return idx, idx < len(items) && items[idx] == itemID
+}
+
+func insertItem(items []int64, itemID int64) []int64 {
+	idx, _ := searchItem(items, itemID)
+	if idx == len(items) {
+		// items are mostly read in order
+		return append(items, itemID)
+	}
+	items = append(items, 0)
+	copy(items[idx+1:], items[idx:])
+	items[idx] = itemID
+	return items
+}
@@ -84,3 +84,62 @@ func TestAckManager(t *testing.T) {
 	m.SetReadLevel(t5)
 	assert.EqualValues(t, t5, m.GetReadLevel())
 }
+
+func TestAckManager_OutstandingItemHoldsBackAckLevelOnly(t *testing.T) {
+	m := NewAckManager(loggerimpl.NewNopLogger())
+	for _, itemID := range []int64{10, 12, 13, 15, 16, 18} {
+		assert.NoError(t, m.ReadItem(itemID))
+	}
+	assert.EqualValues(t, 9, m.GetAckLevel())
+	assert.EqualValues(t, 6, m.GetBacklogCount())
+
+	// 10 is acked, 12 is stuck and the items after it are acked out of order
+	assert.EqualValues(t, 10, m.AckItem(10))
+	for _, itemID := range []int64{16, 13, 18, 15} {
+		assert.EqualValues(t, 10, m.AckItem(itemID))
+	}
+	assert.EqualValues(t, 1, m.GetBacklogCount())
+	assert.Equal(t, []int64{13, 15}, m.GetCompletedItems(2))
+	assert.Equal(t, []int64{13, 15, 16, 18}, m.GetCompletedItems(10))
+
+	m.RemoveCompletedItems([]int64{13, 15, 16})
+	assert.Equal(t, []int64{18}, m.GetCompletedItems(10))
+
+	// acking the stuck item moves the ack level past the items acked after it, even the removed ones
+	assert.EqualValues(t, 18, m.AckItem(12))
+	assert.Empty(t, m.GetCompletedItems(10))
+	assert.EqualValues(t, 0, m.GetBacklogCount())
+
+	// duplicated acks don't move the ack level
+	assert.EqualValues(t, 18, m.AckItem(12))
+	assert.EqualValues(t, 0, m.GetBacklogCount())
+}
+
+func TestAckManager_AckedRangesAreSeparatedByOutstandingItems(t *testing.T) {
+	m := NewAckManager(loggerimpl.NewNopLogger())
+	for itemID := int64(1); itemID <= 9; itemID++ {
+		assert.NoError(t, m.ReadItem(itemID))
+	}
+	for _, itemID := range []int64{2, 3, 5, 7, 8, 9} {
+		assert.EqualValues(t, 0, m.AckItem(itemID))
+	}
+	assert.EqualValues(t, 3, m.AckItem(1))
+	assert.Equal(t, []int64{5, 7, 8, 9}, m.GetCompletedItems(10))
+	assert.EqualValues(t, 5, m.AckItem(4))
+	assert.EqualValues(t, 9, m.AckItem(6))
+	assert.Empty(t, m.GetCompletedItems(10))
+}
+
+func TestAckManager_SetAckLevelDropsCompletedItems(t *testing.T) {
+	m := NewAckManager(loggerimpl.NewNopLogger())
+	for itemID := int64(1); itemID <= 5; itemID++ {
+		assert.NoError(t, m.ReadItem(itemID))
+	}
+	for _, itemID := range []int64{2, 3, 5} {
+		assert.EqualValues(t, 0, m.AckItem(itemID))
+	}
+	m.SetAckLevel(2)
+	assert.EqualValues(t, 3, m.GetAckLevel())
+	assert.Equal(t, []int64{5}, m.GetCompletedItems(10))
+	assert.Error(t, m.ReadItem(5))
+}
@@ -82,5 +82,10 @@ type (
 		SetAckLevel(ackLevel int64)
 		// GetBacklogCount return the of items that are waiting for ack
 		GetBacklogCount() int64
+		// GetCompletedItems returns up to maxCount acked items above the ack level in increasing order,
+		// they are held back by an item read before them which is not acked yet
+		GetCompletedItems(maxCount int) []int64
+		// RemoveCompletedItems stops returning the items from GetCompletedItems, e.g. once they are cleaned up
+		RemoveCompletedItems(itemIDs []int64)
 	}
 )
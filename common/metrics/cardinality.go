@@ -0,0 +1,110 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package metrics
+
+import (
+	"sync"
+)
+
+// otherValue is the tag value the distinct values beyond the cardinality limit are collapsed into
+const otherValue = "_other_"
+
+// limitedTags are the tags whose values are created by users, the number of their distinct values is unbounded
+var limitedTags = map[string]bool{
+	taskList:     true,
+	workflowType: true,
+}
+
+type (
+	// ClientOption configures the metrics client
+	ClientOption func(*ClientImpl)
+
+	// cardinalityLimiter tracks the distinct values of the limited tags by scope, and collapses
+	// the values seen after the limit is reached into otherValue
+	cardinalityLimiter struct {
+		limit func() int
+
+		sync.RWMutex
+		values map[cardinalityKey]map[string]struct{}
+	}
+
+	cardinalityKey struct {
+		scope  int
+		tagKey string
+	}
+)
+
+// WithTagValuesLimit limits the number of distinct task list and workflow type tag values reported per scope,
+// the values beyond the limit are reported as _other_. The limit is read on every new value, zero or less disables it.
+// Values which were already reported keep being reported if the limit is lowered.
+func WithTagValuesLimit(limit func() int) ClientOption {
+	return func(m *ClientImpl) {
+		m.limiter = newCardinalityLimiter(limit)
+	}
+}
+
+func newCardinalityLimiter(limit func() int) *cardinalityLimiter {
+	return &cardinalityLimiter{
+		limit:  limit,
+		values: make(map[cardinalityKey]map[string]struct{}),
+	}
+}
+
+// tag returns the tag to report for the scope, with the value collapsed into otherValue if the limit is reached
+func (l *cardinalityLimiter) tag(scope int, tag Tag) Tag {
+	if l == nil || !limitedTags[tag.Key()] {
+		return tag
+	}
+	value := tag.Value()
+	if value == unknownValue || value == allValue {
+		return tag
+	}
+
+	key := cardinalityKey{scope: scope, tagKey: tag.Key()}
+	l.RLock()
+	_, ok := l.values[key][value]
+	l.RUnlock()
+	if ok {
+		return tag
+	}
+
+	limit := l.limit()
+	if limit <= 0 {
+		return tag
+	}
+	l.Lock()
+	defer l.Unlock()
+	values, ok := l.values[key]
+	if !ok {
+		values = make(map[string]struct{})
+		l.values[key] = values
+	}
+	if _, ok := values[value]; ok {
+		return tag
+	}
+	if len(values) >= limit {
+		return simpleMetric{key: tag.Key(), value: otherValue}
+	}
+	values[value] = struct{}{}
+	return tag
+}
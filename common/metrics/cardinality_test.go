@@ -0,0 +1,82 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber-go/tally"
+)
+
+func TestTagValuesLimit(t *testing.T) {
+	limit := 2
+	scope := tally.NewTestScope("", nil)
+	client := NewClient(scope, Matching, WithTagValuesLimit(func() int { return limit }))
+
+	for _, name := range []string{"tl1", "tl2", "tl3", "tl1", "tl4"} {
+		client.Scope(MatchingPollForDecisionTaskScope, DomainTag("domain"), TaskListTag(name)).IncCounter(CadenceRequests)
+	}
+	// the limit is tracked per scope
+	client.Scope(MatchingPollForActivityTaskScope, TaskListTag("tl3")).IncCounter(CadenceRequests)
+	// unknown values are not counted
+	client.Scope(MatchingPollForDecisionTaskScope, TaskListUnknownTag()).IncCounter(CadenceRequests)
+
+	counts := counterValuesByTag(scope, taskList)
+	assert.Equal(t, map[string]int64{
+		"tl1":        2,
+		"tl2":        1,
+		otherValue:   2,
+		"tl3":        1,
+		unknownValue: 1,
+	}, counts)
+
+	// values which were already reported are kept when the limit is lowered, and new values are accepted when it is raised
+	limit = 1
+	client.Scope(MatchingPollForDecisionTaskScope, TaskListTag("tl2")).IncCounter(CadenceRequests)
+	limit = 3
+	client.Scope(MatchingPollForDecisionTaskScope, TaskListTag("tl4")).IncCounter(CadenceRequests)
+	counts = counterValuesByTag(scope, taskList)
+	assert.Equal(t, int64(2), counts["tl2"])
+	assert.Equal(t, int64(1), counts["tl4"])
+}
+
+func TestTagValuesLimit_Disabled(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	client := NewClient(scope, Matching, WithTagValuesLimit(func() int { return 0 }))
+
+	for _, name := range []string{"wf1", "wf2", "wf3"} {
+		client.Scope(MatchingPollForDecisionTaskScope).Tagged(WorkflowTypeTag(name)).IncCounter(CadenceRequests)
+	}
+	assert.Len(t, counterValuesByTag(scope, workflowType), 3)
+}
+
+func counterValuesByTag(scope tally.TestScope, tagKey string) map[string]int64 {
+	counts := make(map[string]int64)
+	for _, counter := range scope.Snapshot().Counters() {
+		if value, ok := counter.Tags()[tagKey]; ok {
+			counts[value] += counter.Value()
+		}
+	}
+	return counts
+}
@@ -33,13 +33,14 @@ type ClientImpl struct {
 	childScopes map[int]tally.Scope
 	metricDefs  map[int]metricDefinition
 	serviceIdx  ServiceIdx
+	limiter     *cardinalityLimiter
 }
 
 // NewClient creates and returns a new instance of
 // Client implementation
 // reporter holds the common tags for the service
 // serviceIdx indicates the service type in (InputhostIndex, ... StorageIndex)
-func NewClient(scope tally.Scope, serviceIdx ServiceIdx) Client {
+func NewClient(scope tally.Scope, serviceIdx ServiceIdx, opts ...ClientOption) Client {
 	totalScopes := len(ScopeDefs[Common]) + len(ScopeDefs[serviceIdx])
 	metricsClient := &ClientImpl{
 		parentScope: scope,
@@ -47,6 +48,9 @@ func NewClient(scope tally.Scope, serviceIdx ServiceIdx) Client {
 		metricDefs:  getMetricDefs(serviceIdx),
 		serviceIdx:  serviceIdx,
 	}
+	for _, opt := range opts {
+		opt(metricsClient)
+	}
 
 	for idx, def := range ScopeDefs[Common] {
 		scopeTags := map[string]string{
@@ -111,7 +115,7 @@ func (m *ClientImpl) UpdateGauge(scopeIdx int, gaugeIdx int, value float64) {
 // information to the metrics emitted
 func (m *ClientImpl) Scope(scopeIdx int, tags ...Tag) Scope {
 	scope := m.childScopes[scopeIdx]
-	return newMetricsScope(scope, scope, m.metricDefs, false).Tagged(tags...)
+	return newMetricsScope(scope, scope, m.metricDefs, false, scopeIdx, m.limiter).Tagged(tags...)
 }
 
 func (m *ClientImpl) getBuckets(id int) tally.Buckets {
@@ -113,11 +113,14 @@ const (
 	MemoryStackGauge     = "memory_stack"
 	NumGCCounter         = "memory_num_gc"
 	GcPauseMsTimer       = "memory_gc_pause_ms"
+
+	TLSCertificateExpiryGauge = "tls_certificate_expiry_seconds"
 )
 
 // ServiceMetrics are types for common service base metrics
 var ServiceMetrics = map[MetricName]MetricType{
-	RestartCount: Counter,
+	RestartCount:              Counter,
+	TLSCertificateExpiryGauge: Gauge,
 }
 
 // GoRuntimeMetrics represent the runtime stats from go runtime
@@ -215,6 +218,8 @@ const (
 	PersistenceLeaseTaskListScope
 	// PersistenceUpdateTaskListScope tracks PersistenceUpdateTaskListScope calls made by service to persistence layer
 	PersistenceUpdateTaskListScope
+	// PersistenceGetTaskListScope is the metric scope for persistence.TaskManager.GetTaskList API
+	PersistenceGetTaskListScope
 	// PersistenceListTaskListScope is the metric scope for persistence.TaskManager.ListTaskList API
 	PersistenceListTaskListScope
 	// PersistenceDeleteTaskListScope is the metric scope for persistence.TaskManager.DeleteTaskList API
@@ -301,10 +306,32 @@ const (
 	PersistenceFetchDynamicConfigScope
 	// PersistenceUpdateDynamicConfigScope tracks UpdateDynamicConfig calls made by service to persistence layer
 	PersistenceUpdateDynamicConfigScope
+	// PersistenceListDynamicConfigChangesScope tracks ListDynamicConfigChanges calls made by service to persistence layer
+	PersistenceListDynamicConfigChangesScope
+	// PersistenceRecordDomainUsageScope tracks RecordDomainUsage calls made by service to persistence layer
+	PersistenceRecordDomainUsageScope
+	// PersistenceListDomainUsageScope tracks ListDomainUsage calls made by service to persistence layer
+	PersistenceListDomainUsageScope
+	// PersistenceFetchAPIKeysScope tracks FetchAPIKeys calls made by service to persistence layer
+	PersistenceFetchAPIKeysScope
+	// PersistenceUpdateAPIKeysScope tracks UpdateAPIKeys calls made by service to persistence layer
+	PersistenceUpdateAPIKeysScope
+	// PersistenceFetchReshardingScope tracks FetchResharding calls made by service to persistence layer
+	PersistenceFetchReshardingScope
+	// PersistenceUpdateReshardingScope tracks UpdateResharding calls made by service to persistence layer
+	PersistenceUpdateReshardingScope
 	// HistoryClientStartWorkflowExecutionScope tracks RPC calls to history service
 	HistoryClientStartWorkflowExecutionScope
 	// HistoryClientDescribeHistoryHostScope tracks RPC calls to history service
 	HistoryClientDescribeHistoryHostScope
+	// HistoryClientInvalidateCachesScope tracks RPC calls to history service
+	HistoryClientInvalidateCachesScope
+	// HistoryClientDescribeShardOwnershipScope tracks RPC calls to history service
+	HistoryClientDescribeShardOwnershipScope
+	// HistoryClientDescribePersistenceLatencyHeatmapScope tracks RPC calls to history service
+	HistoryClientDescribePersistenceLatencyHeatmapScope
+	// HistoryClientDescribeWatchdogScope tracks RPC calls to history service
+	HistoryClientDescribeWatchdogScope
 	// HistoryClientRemoveTaskScope tracks RPC calls to history service
 	HistoryClientRemoveTaskScope
 	// HistoryClientCloseShardScope tracks RPC calls to history service
@@ -407,6 +434,20 @@ const (
 	MatchingClientListTaskListPartitionsScope
 	// MatchingClientGetTaskListsByDomainScope tracks RPC calls to matching service
 	MatchingClientGetTaskListsByDomainScope
+	// MatchingClientUnloadTaskListScope tracks RPC calls to matching service
+	MatchingClientUnloadTaskListScope
+	// MatchingClientGetTaskListDrainStatusScope tracks RPC calls to matching service
+	MatchingClientGetTaskListDrainStatusScope
+	// MatchingClientPauseTaskListScope tracks RPC calls to matching service
+	MatchingClientPauseTaskListScope
+	// MatchingClientResumeTaskListScope tracks RPC calls to matching service
+	MatchingClientResumeTaskListScope
+	// MatchingClientRecordActivityTaskFinishedScope tracks RPC calls to matching service
+	MatchingClientRecordActivityTaskFinishedScope
+	// MatchingClientInvalidateCachesScope tracks RPC calls to matching service
+	MatchingClientInvalidateCachesScope
+	// MatchingClientDescribeWatchdogScope tracks RPC calls to matching service
+	MatchingClientDescribeWatchdogScope
 	// FrontendClientDeprecateDomainScope tracks RPC calls to frontend service
 	FrontendClientDeprecateDomainScope
 	// FrontendClientDescribeDomainScope tracks RPC calls to frontend service
@@ -543,6 +584,30 @@ const (
 	AdminClientRestoreDynamicConfigScope
 	// AdminClientListDynamicConfigScope tracks RPC calls to admin service
 	AdminClientListDynamicConfigScope
+	// AdminClientUnloadTaskListScope tracks RPC calls to admin service
+	AdminClientUnloadTaskListScope
+	// AdminClientGetTaskListDrainStatusScope tracks RPC calls to admin service
+	AdminClientGetTaskListDrainStatusScope
+	// AdminClientPauseTaskListScope tracks RPC calls to admin service
+	AdminClientPauseTaskListScope
+	// AdminClientResumeTaskListScope tracks RPC calls to admin service
+	AdminClientResumeTaskListScope
+	// AdminClientListDynamicConfigChangesScope tracks RPC calls to admin service
+	AdminClientListDynamicConfigChangesScope
+	// AdminClientGetDomainUsageScope tracks RPC calls to admin service
+	AdminClientGetDomainUsageScope
+	// AdminClientInvalidateCachesScope tracks RPC calls to admin service
+	AdminClientInvalidateCachesScope
+	// AdminClientListEffectiveDynamicConfigScope tracks RPC calls to admin service
+	AdminClientListEffectiveDynamicConfigScope
+	// AdminClientDescribeShardOwnershipScope tracks RPC calls to admin service
+	AdminClientDescribeShardOwnershipScope
+	// AdminClientDescribePersistenceLatencyHeatmapScope tracks RPC calls to admin service
+	AdminClientDescribePersistenceLatencyHeatmapScope
+	// AdminClientDescribeWatchdogScope tracks RPC calls to admin service
+	AdminClientDescribeWatchdogScope
+	// AdminClientDescribeDomainSLOScope tracks RPC calls to admin service
+	AdminClientDescribeDomainSLOScope
 	// DCRedirectionDeprecateDomainScope tracks RPC calls for dc redirection
 	DCRedirectionDeprecateDomainScope
 	// DCRedirectionDescribeDomainScope tracks RPC calls for dc redirection
@@ -733,6 +798,16 @@ const (
 	DomainReplicationQueueScope
 	// ClusterMetadataScope is used for the cluster metadata
 	ClusterMetadataScope
+	// WatchdogScope is used by the resource leak watchdog
+	WatchdogScope
+	// RPCPayloadScope is used for the payload sizes of inbound and outbound RPCs
+	RPCPayloadScope
+	// SLOScope is used for the SLOs of the frontend requests of each domain
+	SLOScope
+	// CrashReportScope is used for the reports of the panics captured by a host
+	CrashReportScope
+	// LoadSheddingScope is used by the load shedding of a host
+	LoadSheddingScope
 
 	NumCommonScopes
 )
@@ -795,6 +870,30 @@ const (
 	AdminDeleteWorkflowScope
 	// MaintainCorruptWorkflowScope is the metric scope for admin.MaintainCorruptWorkflow
 	MaintainCorruptWorkflowScope
+	// AdminUnloadTaskListScope is the metric scope for admin.UnloadTaskList
+	AdminUnloadTaskListScope
+	// AdminGetTaskListDrainStatusScope is the metric scope for admin.GetTaskListDrainStatus
+	AdminGetTaskListDrainStatusScope
+	// AdminPauseTaskListScope is the metric scope for admin.PauseTaskList
+	AdminPauseTaskListScope
+	// AdminResumeTaskListScope is the metric scope for admin.ResumeTaskList
+	AdminResumeTaskListScope
+	// AdminListDynamicConfigChangesScope is the metric scope for admin.ListDynamicConfigChanges
+	AdminListDynamicConfigChangesScope
+	// AdminGetDomainUsageScope is the metric scope for admin.GetDomainUsage
+	AdminGetDomainUsageScope
+	// AdminInvalidateCachesScope is the metric scope for admin.InvalidateCaches
+	AdminInvalidateCachesScope
+	// AdminListEffectiveDynamicConfigScope is the metric scope for admin.ListEffectiveDynamicConfig
+	AdminListEffectiveDynamicConfigScope
+	// AdminDescribeShardOwnershipScope is the metric scope for admin.DescribeShardOwnership
+	AdminDescribeShardOwnershipScope
+	// AdminDescribePersistenceLatencyHeatmapScope is the metric scope for admin.DescribePersistenceLatencyHeatmap
+	AdminDescribePersistenceLatencyHeatmapScope
+	// AdminDescribeWatchdogScope is the metric scope for admin.DescribeWatchdog
+	AdminDescribeWatchdogScope
+	// AdminDescribeDomainSLOScope is the metric scope for admin.DescribeDomainSLO
+	AdminDescribeDomainSLOScope
 
 	NumAdminScopes
 )
@@ -885,6 +984,8 @@ const (
 	FrontendResetWorkflowExecutionScope
 	// FrontendGetSearchAttributesScope is the metric scope for frontend.GetSearchAttributes
 	FrontendGetSearchAttributesScope
+	// FrontendProberScope is the metric scope for the synthetic requests of the frontend prober
+	FrontendProberScope
 
 	NumFrontendScopes
 )
@@ -1181,6 +1282,18 @@ const (
 	MatchingListTaskListPartitionsScope
 	// MatchingGetTaskListsByDomainScope tracks GetTaskListsByDomain API calls received by service
 	MatchingGetTaskListsByDomainScope
+	// MatchingUnloadTaskListScope tracks UnloadTaskList API calls received by service
+	MatchingUnloadTaskListScope
+	// MatchingGetTaskListDrainStatusScope tracks GetTaskListDrainStatus API calls received by service
+	MatchingGetTaskListDrainStatusScope
+	// MatchingPauseTaskListScope tracks PauseTaskList API calls received by service
+	MatchingPauseTaskListScope
+	// MatchingResumeTaskListScope tracks ResumeTaskList API calls received by service
+	MatchingResumeTaskListScope
+	// MatchingRecordActivityTaskFinishedScope tracks RecordActivityTaskFinished API calls received by service
+	MatchingRecordActivityTaskFinishedScope
+	// MatchingTaskListExpiredTasksScope is the metrics scope for tasks that expired before being dispatched
+	MatchingTaskListExpiredTasksScope
 
 	NumMatchingScopes
 )
@@ -1274,6 +1387,7 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceGetOrphanTasksScope:                                 {operation: "GetOrphanTasks"},
 		PersistenceLeaseTaskListScope:                                  {operation: "LeaseTaskList"},
 		PersistenceUpdateTaskListScope:                                 {operation: "UpdateTaskList"},
+		PersistenceGetTaskListScope:                                    {operation: "GetTaskList"},
 		PersistenceListTaskListScope:                                   {operation: "ListTaskList"},
 		PersistenceDeleteTaskListScope:                                 {operation: "DeleteTaskList"},
 		PersistenceAppendHistoryEventsScope:                            {operation: "AppendHistoryEvents"},
@@ -1324,11 +1438,22 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		PersistenceGetDLQSizeScope:                                     {operation: "GetDLQSize"},
 		PersistenceFetchDynamicConfigScope:                             {operation: "FetchDynamicConfig"},
 		PersistenceUpdateDynamicConfigScope:                            {operation: "UpdateDynamicConfig"},
+		PersistenceListDynamicConfigChangesScope:                       {operation: "ListDynamicConfigChanges"},
+		PersistenceRecordDomainUsageScope:                              {operation: "RecordDomainUsage"},
+		PersistenceListDomainUsageScope:                                {operation: "ListDomainUsage"},
+		PersistenceFetchAPIKeysScope:                                   {operation: "FetchAPIKeys"},
+		PersistenceUpdateAPIKeysScope:                                  {operation: "UpdateAPIKeys"},
+		PersistenceFetchReshardingScope:                                {operation: "FetchResharding"},
+		PersistenceUpdateReshardingScope:                               {operation: "UpdateResharding"},
 
 		ClusterMetadataArchivalConfigScope: {operation: "ArchivalConfig"},
 
 		HistoryClientStartWorkflowExecutionScope:              {operation: "HistoryClientStartWorkflowExecution", tags: map[string]string{CadenceRoleTagName: HistoryClientRoleTagValue}},
 		HistoryClientDescribeHistoryHostScope:                 {operation: "HistoryClientDescribeHistoryHost", tags: map[string]string{CadenceRoleTagName: HistoryClientRoleTagValue}},
+		HistoryClientInvalidateCachesScope:                    {operation: "HistoryClientInvalidateCaches", tags: map[string]string{CadenceRoleTagName: HistoryClientRoleTagValue}},
+		HistoryClientDescribeShardOwnershipScope:              {operation: "HistoryClientDescribeShardOwnership", tags: map[string]string{CadenceRoleTagName: HistoryClientRoleTagValue}},
+		HistoryClientDescribePersistenceLatencyHeatmapScope:   {operation: "HistoryClientDescribePersistenceLatencyHeatmap", tags: map[string]string{CadenceRoleTagName: HistoryClientRoleTagValue}},
+		HistoryClientDescribeWatchdogScope:                    {operation: "HistoryClientDescribeWatchdog", tags: map[string]string{CadenceRoleTagName: HistoryClientRoleTagValue}},
 		HistoryClientRemoveTaskScope:                          {operation: "HistoryClientRemoveTask", tags: map[string]string{CadenceRoleTagName: HistoryClientRoleTagValue}},
 		HistoryClientCloseShardScope:                          {operation: "HistoryClientCloseShard", tags: map[string]string{CadenceRoleTagName: HistoryClientRoleTagValue}},
 		HistoryClientResetQueueScope:                          {operation: "HistoryClientResetQueue", tags: map[string]string{CadenceRoleTagName: HistoryClientRoleTagValue}},
@@ -1380,6 +1505,13 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		MatchingClientDescribeTaskListScope:                   {operation: "MatchingClientDescribeTaskList", tags: map[string]string{CadenceRoleTagName: MatchingClientRoleTagValue}},
 		MatchingClientListTaskListPartitionsScope:             {operation: "MatchingClientListTaskListPartitions", tags: map[string]string{CadenceRoleTagName: MatchingClientRoleTagValue}},
 		MatchingClientGetTaskListsByDomainScope:               {operation: "MatchingClientGetTaskListsByDomain", tags: map[string]string{CadenceRoleTagName: MatchingClientRoleTagValue}},
+		MatchingClientUnloadTaskListScope:                     {operation: "MatchingClientUnloadTaskList", tags: map[string]string{CadenceRoleTagName: MatchingClientRoleTagValue}},
+		MatchingClientGetTaskListDrainStatusScope:             {operation: "MatchingClientGetTaskListDrainStatus", tags: map[string]string{CadenceRoleTagName: MatchingClientRoleTagValue}},
+		MatchingClientPauseTaskListScope:                      {operation: "MatchingClientPauseTaskList", tags: map[string]string{CadenceRoleTagName: MatchingClientRoleTagValue}},
+		MatchingClientResumeTaskListScope:                     {operation: "MatchingClientResumeTaskList", tags: map[string]string{CadenceRoleTagName: MatchingClientRoleTagValue}},
+		MatchingClientRecordActivityTaskFinishedScope:         {operation: "MatchingClientRecordActivityTaskFinished", tags: map[string]string{CadenceRoleTagName: MatchingClientRoleTagValue}},
+		MatchingClientInvalidateCachesScope:                   {operation: "MatchingClientInvalidateCaches", tags: map[string]string{CadenceRoleTagName: MatchingClientRoleTagValue}},
+		MatchingClientDescribeWatchdogScope:                   {operation: "MatchingClientDescribeWatchdog", tags: map[string]string{CadenceRoleTagName: MatchingClientRoleTagValue}},
 		FrontendClientDeprecateDomainScope:                    {operation: "FrontendClientDeprecateDomain", tags: map[string]string{CadenceRoleTagName: FrontendClientRoleTagValue}},
 		FrontendClientDescribeDomainScope:                     {operation: "FrontendClientDescribeDomain", tags: map[string]string{CadenceRoleTagName: FrontendClientRoleTagValue}},
 		FrontendClientDescribeTaskListScope:                   {operation: "FrontendClientDescribeTaskList", tags: map[string]string{CadenceRoleTagName: FrontendClientRoleTagValue}},
@@ -1448,6 +1580,18 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		AdminClientUpdateDynamicConfigScope:                   {operation: "AdminClientUpdateDynamicConfigScope", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
 		AdminClientRestoreDynamicConfigScope:                  {operation: "AdminClientRestoreDynamicConfigScope", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
 		AdminClientListDynamicConfigScope:                     {operation: "AdminClientListDynamicConfigScope", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientUnloadTaskListScope:                        {operation: "AdminClientUnloadTaskList", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientGetTaskListDrainStatusScope:                {operation: "AdminClientGetTaskListDrainStatus", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientPauseTaskListScope:                         {operation: "AdminClientPauseTaskList", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientResumeTaskListScope:                        {operation: "AdminClientResumeTaskList", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientListDynamicConfigChangesScope:              {operation: "AdminClientListDynamicConfigChanges", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientGetDomainUsageScope:                        {operation: "AdminClientGetDomainUsage", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientInvalidateCachesScope:                      {operation: "AdminClientInvalidateCaches", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientListEffectiveDynamicConfigScope:            {operation: "AdminClientListEffectiveDynamicConfig", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientDescribeShardOwnershipScope:                {operation: "AdminClientDescribeShardOwnership", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientDescribePersistenceLatencyHeatmapScope:     {operation: "AdminClientDescribePersistenceLatencyHeatmap", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientDescribeWatchdogScope:                      {operation: "AdminClientDescribeWatchdog", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
+		AdminClientDescribeDomainSLOScope:                     {operation: "AdminClientDescribeDomainSLO", tags: map[string]string{CadenceRoleTagName: AdminClientRoleTagValue}},
 		DCRedirectionDeprecateDomainScope:                     {operation: "DCRedirectionDeprecateDomain", tags: map[string]string{CadenceRoleTagName: DCRedirectionRoleTagValue}},
 		DCRedirectionDescribeDomainScope:                      {operation: "DCRedirectionDescribeDomain", tags: map[string]string{CadenceRoleTagName: DCRedirectionRoleTagValue}},
 		DCRedirectionDescribeTaskListScope:                    {operation: "DCRedirectionDescribeTaskList", tags: map[string]string{CadenceRoleTagName: DCRedirectionRoleTagValue}},
@@ -1535,6 +1679,11 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		DomainFailoverScope:         {operation: "DomainFailover"},
 		DomainReplicationQueueScope: {operation: "DomainReplicationQueue"},
 		ClusterMetadataScope:        {operation: "ClusterMetadata"},
+		WatchdogScope:               {operation: "Watchdog"},
+		RPCPayloadScope:             {operation: "RPCPayload"},
+		SLOScope:                    {operation: "SLO"},
+		CrashReportScope:            {operation: "CrashReport"},
+		LoadSheddingScope:           {operation: "LoadShedding"},
 	},
 	// Frontend Scope Names
 	Frontend: {
@@ -1567,6 +1716,18 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		AdminListDynamicConfigScope:                 {operation: "AdminListDynamicConfig"},
 		AdminDeleteWorkflowScope:                    {operation: "AdminDeleteWorkflow"},
 		MaintainCorruptWorkflowScope:                {operation: "MaintainCorruptWorkflow"},
+		AdminUnloadTaskListScope:                    {operation: "AdminUnloadTaskList"},
+		AdminGetTaskListDrainStatusScope:            {operation: "AdminGetTaskListDrainStatus"},
+		AdminPauseTaskListScope:                     {operation: "AdminPauseTaskList"},
+		AdminResumeTaskListScope:                    {operation: "AdminResumeTaskList"},
+		AdminListDynamicConfigChangesScope:          {operation: "AdminListDynamicConfigChanges"},
+		AdminGetDomainUsageScope:                    {operation: "AdminGetDomainUsage"},
+		AdminInvalidateCachesScope:                  {operation: "AdminInvalidateCaches"},
+		AdminListEffectiveDynamicConfigScope:        {operation: "AdminListEffectiveDynamicConfig"},
+		AdminDescribeShardOwnershipScope:            {operation: "AdminDescribeShardOwnership"},
+		AdminDescribePersistenceLatencyHeatmapScope: {operation: "AdminDescribePersistenceLatencyHeatmap"},
+		AdminDescribeWatchdogScope:                  {operation: "AdminDescribeWatchdog"},
+		AdminDescribeDomainSLOScope:                 {operation: "AdminDescribeDomainSLO"},
 
 		FrontendRestartWorkflowExecutionScope:           {operation: "RestartWorkflowExecution"},
 		FrontendStartWorkflowExecutionScope:             {operation: "StartWorkflowExecution"},
@@ -1610,6 +1771,7 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		FrontendDescribeTaskListScope:                   {operation: "DescribeTaskList"},
 		FrontendResetStickyTaskListScope:                {operation: "ResetStickyTaskList"},
 		FrontendGetSearchAttributesScope:                {operation: "GetSearchAttributes"},
+		FrontendProberScope:                             {operation: "Prober"},
 	},
 	// History Scope Names
 	History: {
@@ -1747,17 +1909,23 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 	},
 	// Matching Scope Names
 	Matching: {
-		MatchingPollForDecisionTaskScope:       {operation: "PollForDecisionTask"},
-		MatchingPollForActivityTaskScope:       {operation: "PollForActivityTask"},
-		MatchingAddActivityTaskScope:           {operation: "AddActivityTask"},
-		MatchingAddDecisionTaskScope:           {operation: "AddDecisionTask"},
-		MatchingTaskListMgrScope:               {operation: "TaskListMgr"},
-		MatchingQueryWorkflowScope:             {operation: "QueryWorkflow"},
-		MatchingRespondQueryTaskCompletedScope: {operation: "RespondQueryTaskCompleted"},
-		MatchingCancelOutstandingPollScope:     {operation: "CancelOutstandingPoll"},
-		MatchingDescribeTaskListScope:          {operation: "DescribeTaskList"},
-		MatchingListTaskListPartitionsScope:    {operation: "ListTaskListPartitions"},
-		MatchingGetTaskListsByDomainScope:      {operation: "GetTaskListsByDomain"},
+		MatchingPollForDecisionTaskScope:        {operation: "PollForDecisionTask"},
+		MatchingPollForActivityTaskScope:        {operation: "PollForActivityTask"},
+		MatchingAddActivityTaskScope:            {operation: "AddActivityTask"},
+		MatchingAddDecisionTaskScope:            {operation: "AddDecisionTask"},
+		MatchingTaskListMgrScope:                {operation: "TaskListMgr"},
+		MatchingQueryWorkflowScope:              {operation: "QueryWorkflow"},
+		MatchingRespondQueryTaskCompletedScope:  {operation: "RespondQueryTaskCompleted"},
+		MatchingCancelOutstandingPollScope:      {operation: "CancelOutstandingPoll"},
+		MatchingDescribeTaskListScope:           {operation: "DescribeTaskList"},
+		MatchingListTaskListPartitionsScope:     {operation: "ListTaskListPartitions"},
+		MatchingGetTaskListsByDomainScope:       {operation: "GetTaskListsByDomain"},
+		MatchingUnloadTaskListScope:             {operation: "UnloadTaskList"},
+		MatchingGetTaskListDrainStatusScope:     {operation: "GetTaskListDrainStatus"},
+		MatchingPauseTaskListScope:              {operation: "PauseTaskList"},
+		MatchingResumeTaskListScope:             {operation: "ResumeTaskList"},
+		MatchingRecordActivityTaskFinishedScope: {operation: "RecordActivityTaskFinished"},
+		MatchingTaskListExpiredTasksScope:       {operation: "TaskListExpiredTasks"},
 	},
 	// Worker Scope Names
 	Worker: {
@@ -1972,6 +2140,29 @@ const (
 	ParentClosePolicyProcessorSuccess
 	ParentClosePolicyProcessorFailures
 
+	WatchdogResourceGauge
+	WatchdogLeakSuspectGauge
+
+	LoadSheddingLevelGauge
+	LoadSheddingPressureGauge
+	LoadSheddingRejectedCounter
+	LoadSheddingThrottledTaskCounter
+
+	RPCInboundRequestSize
+	RPCInboundResponseSize
+	RPCOutboundRequestSize
+	RPCOutboundResponseSize
+	RPCOversizePayloadCounter
+	ProberRequests
+	ProberFailures
+	ProberLatency
+	SLOAvailabilityShortBurnRateGauge
+	SLOAvailabilityLongBurnRateGauge
+	SLOLatencyShortBurnRateGauge
+	SLOLatencyLongBurnRateGauge
+	PanicsCapturedCounter
+	CrashReportFailures
+
 	NumCommonMetrics // Needs to be last on this list for iota numbering
 )
 
@@ -2090,6 +2281,9 @@ const (
 	ShardItemCreatedCounter
 	ShardItemRemovedCounter
 	ShardItemAcquisitionLatency
+	ShardHandoffCounter
+	ShardHandoffFailedCounter
+	ShardHandoffDrainLatency
 	ShardInfoReplicationPendingTasksTimer
 	ShardInfoTransferActivePendingTasksTimer
 	ShardInfoTransferStandbyPendingTasksTimer
@@ -2110,6 +2304,8 @@ const (
 	SyncShardFromRemoteFailure
 	MembershipChangedCounter
 	NumShardsGauge
+	ShardImbalanceGauge
+	ShardStolenCounter
 	GetEngineForShardErrorCounter
 	GetEngineForShardLatency
 	RemoveEngineForShardLatency
@@ -2161,6 +2357,7 @@ const (
 	DeleteRequestCancelInfoCount
 	WorkflowRetryBackoffTimerCount
 	WorkflowCronBackoffTimerCount
+	WorkflowCronOverlapTimerCount
 	WorkflowCleanupDeleteCount
 	WorkflowCleanupArchiveCount
 	WorkflowCleanupNopCount
@@ -2208,6 +2405,9 @@ const (
 	ReplicationDLQProbeFailed
 	ReplicationDLQSize
 	ReplicationDLQValidationFailed
+	ReplicationDLQDomainSize
+	ReplicationDLQRepairedCounter
+	ReplicationDLQRepairFailedCounter
 	GetReplicationMessagesForShardLatency
 	GetDLQReplicationMessagesLatency
 	EventReapplySkippedCount
@@ -2222,6 +2422,7 @@ const (
 	DecisionTaskQueryLatency
 	ConsistentQueryTimeoutCount
 	QueryBeforeFirstDecisionCount
+	QueryReplicationCatchUpCount
 	QueryBufferExceededCount
 	QueryRegistryInvalidStateCount
 	WorkerNotSupportsConsistentQueryCount
@@ -2240,6 +2441,12 @@ const (
 	HistoryFailoverCallbackCount
 	WorkflowVersionCount
 	WorkflowTypeCount
+	HistorySizeWarnLimitExceededCounter
+	HistoryCountWarnLimitExceededCounter
+	PendingActivityCountWarnLimitExceededCounter
+	PendingChildExecutionCountWarnLimitExceededCounter
+	PendingCountErrorLimitExceededCounter
+	HistorySizeWarningFlaggedCounter
 
 	NumHistoryMetrics
 )
@@ -2257,6 +2464,8 @@ const (
 	BufferThrottlePerTaskListCounter
 	SyncMatchLatencyPerTaskList
 	AsyncMatchLatencyPerTaskList
+	ScheduleToStartSyncPerTaskList
+	ScheduleToStartBufferedPerTaskList
 	ExpiredTasksPerTaskListCounter
 	ForwardedPerTaskListCounter
 	ForwardTaskCallsPerTaskList
@@ -2274,8 +2483,21 @@ const (
 	RemoteToRemoteMatchPerTaskListCounter
 	PollerPerTaskListCounter
 	TaskListManagersGauge
+	TaskListHandoffCounter
+	TaskListHandoffFailedCounter
 	TaskLagPerTaskListGauge
 	TaskBacklogPerTaskListGauge
+	PollersWaitingPerTaskListGauge
+	IsolationGroupMatchPerTaskListCounter
+	IsolationSpilloverPerTaskListCounter
+	GetTasksBatchSizePerTaskListGauge
+	EmptyTaskReadBackoffPerTaskListCounter
+	ExpiredTaskAgePerTaskList
+	ExpiredTasksDeadLetteredPerTaskListCounter
+	ExpiredTasksDeadLetterFailuresPerTaskListCounter
+	LocalSyncMatchLatencyPerTaskList
+	ForwardedSyncMatchLatencyPerTaskList
+	BacklogDispatchLatencyPerTaskList
 
 	NumMatchingMetrics
 )
@@ -2576,6 +2798,26 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		DomainReplicationQueueSizeErrorCount: {metricName: "domain_replication_queue_failed", metricType: Counter},
 		ParentClosePolicyProcessorSuccess:    {metricName: "parent_close_policy_processor_requests", metricType: Counter},
 		ParentClosePolicyProcessorFailures:   {metricName: "parent_close_policy_processor_errors", metricType: Counter},
+		WatchdogResourceGauge:                {metricName: "watchdog_resource", metricType: Gauge},
+		WatchdogLeakSuspectGauge:             {metricName: "watchdog_leak_suspect", metricType: Gauge},
+		LoadSheddingLevelGauge:               {metricName: "load_shedding_level", metricType: Gauge},
+		LoadSheddingPressureGauge:            {metricName: "load_shedding_pressure", metricType: Gauge},
+		LoadSheddingRejectedCounter:          {metricName: "load_shedding_rejected", metricType: Counter},
+		LoadSheddingThrottledTaskCounter:     {metricName: "load_shedding_throttled_tasks", metricType: Counter},
+		RPCInboundRequestSize:                {metricName: "rpc_inbound_request_size", metricType: Histogram, buckets: PayloadSizeBuckets},
+		RPCInboundResponseSize:               {metricName: "rpc_inbound_response_size", metricType: Histogram, buckets: PayloadSizeBuckets},
+		RPCOutboundRequestSize:               {metricName: "rpc_outbound_request_size", metricType: Histogram, buckets: PayloadSizeBuckets},
+		RPCOutboundResponseSize:              {metricName: "rpc_outbound_response_size", metricType: Histogram, buckets: PayloadSizeBuckets},
+		RPCOversizePayloadCounter:            {metricName: "rpc_oversize_payload", metricType: Counter},
+		ProberRequests:                       {metricName: "prober_requests", metricType: Counter},
+		ProberFailures:                       {metricName: "prober_errors", metricType: Counter},
+		ProberLatency:                        {metricName: "prober_latency", metricType: Timer},
+		SLOAvailabilityShortBurnRateGauge:    {metricName: "slo_availability_burn_rate_short", metricType: Gauge},
+		SLOAvailabilityLongBurnRateGauge:     {metricName: "slo_availability_burn_rate_long", metricType: Gauge},
+		SLOLatencyShortBurnRateGauge:         {metricName: "slo_latency_burn_rate_short", metricType: Gauge},
+		SLOLatencyLongBurnRateGauge:          {metricName: "slo_latency_burn_rate_long", metricType: Gauge},
+		PanicsCapturedCounter:                {metricName: "panics_captured", metricType: Counter},
+		CrashReportFailures:                  {metricName: "crash_report_errors", metricType: Counter},
 	},
 	History: {
 		TaskRequests:             {metricName: "task_requests", metricType: Counter},
@@ -2686,6 +2928,9 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		ShardItemCreatedCounter:                                      {metricName: "sharditem_created_count", metricType: Counter},
 		ShardItemRemovedCounter:                                      {metricName: "sharditem_removed_count", metricType: Counter},
 		ShardItemAcquisitionLatency:                                  {metricName: "sharditem_acquisition_latency", metricType: Timer},
+		ShardHandoffCounter:                                          {metricName: "shard_handoff_count", metricType: Counter},
+		ShardHandoffFailedCounter:                                    {metricName: "shard_handoff_failed_count", metricType: Counter},
+		ShardHandoffDrainLatency:                                     {metricName: "shard_handoff_drain_latency", metricType: Timer},
 		ShardInfoReplicationPendingTasksTimer:                        {metricName: "shardinfo_replication_pending_task", metricType: Timer},
 		ShardInfoTransferActivePendingTasksTimer:                     {metricName: "shardinfo_transfer_active_pending_task", metricType: Timer},
 		ShardInfoTransferStandbyPendingTasksTimer:                    {metricName: "shardinfo_transfer_standby_pending_task", metricType: Timer},
@@ -2706,6 +2951,8 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		SyncShardFromRemoteFailure:                                   {metricName: "syncshard_remote_failed", metricType: Counter},
 		MembershipChangedCounter:                                     {metricName: "membership_changed_count", metricType: Counter},
 		NumShardsGauge:                                               {metricName: "numshards_gauge", metricType: Gauge},
+		ShardImbalanceGauge:                                          {metricName: "shard_imbalance_gauge", metricType: Gauge},
+		ShardStolenCounter:                                           {metricName: "shard_stolen_count", metricType: Counter},
 		GetEngineForShardErrorCounter:                                {metricName: "get_engine_for_shard_errors", metricType: Counter},
 		GetEngineForShardLatency:                                     {metricName: "get_engine_for_shard_latency", metricType: Timer},
 		RemoveEngineForShardLatency:                                  {metricName: "remove_engine_for_shard_latency", metricType: Timer},
@@ -2753,6 +3000,7 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		DeleteRequestCancelInfoCount:                                 {metricName: "delete_request_cancel_info", metricType: Timer},
 		WorkflowRetryBackoffTimerCount:                               {metricName: "workflow_retry_backoff_timer", metricType: Counter},
 		WorkflowCronBackoffTimerCount:                                {metricName: "workflow_cron_backoff_timer", metricType: Counter},
+		WorkflowCronOverlapTimerCount:                                {metricName: "workflow_cron_overlap_timer", metricType: Counter},
 		WorkflowCleanupDeleteCount:                                   {metricName: "workflow_cleanup_delete", metricType: Counter},
 		WorkflowCleanupArchiveCount:                                  {metricName: "workflow_cleanup_archive", metricType: Counter},
 		WorkflowCleanupNopCount:                                      {metricName: "workflow_cleanup_nop", metricType: Counter},
@@ -2800,6 +3048,9 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		ReplicationDLQProbeFailed:                                    {metricName: "replication_dlq_probe_failed", metricType: Counter},
 		ReplicationDLQSize:                                           {metricName: "replication_dlq_size", metricType: Gauge},
 		ReplicationDLQValidationFailed:                               {metricName: "replication_dlq_validation_failed", metricType: Counter},
+		ReplicationDLQDomainSize:                                     {metricName: "replication_dlq_domain_size", metricType: Gauge},
+		ReplicationDLQRepairedCounter:                                {metricName: "replication_dlq_repaired", metricType: Counter},
+		ReplicationDLQRepairFailedCounter:                            {metricName: "replication_dlq_repair_failed", metricType: Counter},
 		GetReplicationMessagesForShardLatency:                        {metricName: "get_replication_messages_for_shard", metricType: Timer},
 		GetDLQReplicationMessagesLatency:                             {metricName: "get_dlq_replication_messages", metricType: Timer},
 		EventReapplySkippedCount:                                     {metricName: "event_reapply_skipped_count", metricType: Counter},
@@ -2814,6 +3065,7 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		DecisionTaskQueryLatency:                                     {metricName: "decision_task_query_latency", metricType: Timer},
 		ConsistentQueryTimeoutCount:                                  {metricName: "consistent_query_timeout", metricType: Counter},
 		QueryBeforeFirstDecisionCount:                                {metricName: "query_before_first_decision", metricType: Counter},
+		QueryReplicationCatchUpCount:                                 {metricName: "query_replication_catch_up", metricType: Counter},
 		QueryBufferExceededCount:                                     {metricName: "query_buffer_exceeded", metricType: Counter},
 		QueryRegistryInvalidStateCount:                               {metricName: "query_registry_invalid_state", metricType: Counter},
 		WorkerNotSupportsConsistentQueryCount:                        {metricName: "worker_not_supports_consistent_query", metricType: Counter},
@@ -2836,38 +3088,59 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		ReplicationTasksCount:                                        {metricName: "replication_tasks_count", metricType: Timer},
 		WorkflowVersionCount:                                         {metricName: "workflow_version_count", metricType: Gauge},
 		WorkflowTypeCount:                                            {metricName: "workflow_type_count", metricType: Gauge},
+		HistorySizeWarnLimitExceededCounter:                          {metricName: "history_size_warn_limit_exceeded", metricType: Counter},
+		HistoryCountWarnLimitExceededCounter:                         {metricName: "history_count_warn_limit_exceeded", metricType: Counter},
+		PendingActivityCountWarnLimitExceededCounter:                 {metricName: "pending_activity_count_warn_limit_exceeded", metricType: Counter},
+		PendingChildExecutionCountWarnLimitExceededCounter:           {metricName: "pending_child_execution_count_warn_limit_exceeded", metricType: Counter},
+		PendingCountErrorLimitExceededCounter:                        {metricName: "pending_count_error_limit_exceeded", metricType: Counter},
+		HistorySizeWarningFlaggedCounter:                             {metricName: "history_size_warning_flagged", metricType: Counter},
 	},
 	Matching: {
-		PollSuccessPerTaskListCounter:            {metricName: "poll_success_per_tl", metricRollupName: "poll_success"},
-		PollTimeoutPerTaskListCounter:            {metricName: "poll_timeouts_per_tl", metricRollupName: "poll_timeouts"},
-		PollSuccessWithSyncPerTaskListCounter:    {metricName: "poll_success_sync_per_tl", metricRollupName: "poll_success_sync"},
-		LeaseRequestPerTaskListCounter:           {metricName: "lease_requests_per_tl", metricRollupName: "lease_requests"},
-		LeaseFailurePerTaskListCounter:           {metricName: "lease_failures_per_tl", metricRollupName: "lease_failures"},
-		ConditionFailedErrorPerTaskListCounter:   {metricName: "condition_failed_errors_per_tl", metricRollupName: "condition_failed_errors"},
-		RespondQueryTaskFailedPerTaskListCounter: {metricName: "respond_query_failed_per_tl", metricRollupName: "respond_query_failed"},
-		SyncThrottlePerTaskListCounter:           {metricName: "sync_throttle_count_per_tl", metricRollupName: "sync_throttle_count"},
-		BufferThrottlePerTaskListCounter:         {metricName: "buffer_throttle_count_per_tl", metricRollupName: "buffer_throttle_count"},
-		ExpiredTasksPerTaskListCounter:           {metricName: "tasks_expired_per_tl", metricRollupName: "tasks_expired"},
-		ForwardedPerTaskListCounter:              {metricName: "forwarded_per_tl", metricRollupName: "forwarded"},
-		ForwardTaskCallsPerTaskList:              {metricName: "forward_task_calls_per_tl", metricRollupName: "forward_task_calls"},
-		ForwardTaskErrorsPerTaskList:             {metricName: "forward_task_errors_per_tl", metricRollupName: "forward_task_errors"},
-		ForwardQueryCallsPerTaskList:             {metricName: "forward_query_calls_per_tl", metricRollupName: "forward_query_calls"},
-		ForwardQueryErrorsPerTaskList:            {metricName: "forward_query_errors_per_tl", metricRollupName: "forward_query_errors"},
-		ForwardPollCallsPerTaskList:              {metricName: "forward_poll_calls_per_tl", metricRollupName: "forward_poll_calls"},
-		ForwardPollErrorsPerTaskList:             {metricName: "forward_poll_errors_per_tl", metricRollupName: "forward_poll_errors"},
-		SyncMatchLatencyPerTaskList:              {metricName: "syncmatch_latency_per_tl", metricRollupName: "syncmatch_latency", metricType: Timer},
-		AsyncMatchLatencyPerTaskList:             {metricName: "asyncmatch_latency_per_tl", metricRollupName: "asyncmatch_latency", metricType: Timer},
-		ForwardTaskLatencyPerTaskList:            {metricName: "forward_task_latency_per_tl", metricRollupName: "forward_task_latency"},
-		ForwardQueryLatencyPerTaskList:           {metricName: "forward_query_latency_per_tl", metricRollupName: "forward_query_latency"},
-		ForwardPollLatencyPerTaskList:            {metricName: "forward_poll_latency_per_tl", metricRollupName: "forward_poll_latency"},
-		LocalToLocalMatchPerTaskListCounter:      {metricName: "local_to_local_matches_per_tl", metricRollupName: "local_to_local_matches"},
-		LocalToRemoteMatchPerTaskListCounter:     {metricName: "local_to_remote_matches_per_tl", metricRollupName: "local_to_remote_matches"},
-		RemoteToLocalMatchPerTaskListCounter:     {metricName: "remote_to_local_matches_per_tl", metricRollupName: "remote_to_local_matches"},
-		RemoteToRemoteMatchPerTaskListCounter:    {metricName: "remote_to_remote_matches_per_tl", metricRollupName: "remote_to_remote_matches"},
-		PollerPerTaskListCounter:                 {metricName: "poller_count_per_tl", metricRollupName: "poller_count"},
-		TaskListManagersGauge:                    {metricName: "tasklist_managers", metricType: Gauge},
-		TaskLagPerTaskListGauge:                  {metricName: "task_lag_per_tl", metricType: Gauge},
-		TaskBacklogPerTaskListGauge:              {metricName: "task_backlog_per_tl", metricType: Gauge},
+		PollSuccessPerTaskListCounter:                    {metricName: "poll_success_per_tl", metricRollupName: "poll_success"},
+		PollTimeoutPerTaskListCounter:                    {metricName: "poll_timeouts_per_tl", metricRollupName: "poll_timeouts"},
+		PollSuccessWithSyncPerTaskListCounter:            {metricName: "poll_success_sync_per_tl", metricRollupName: "poll_success_sync"},
+		LeaseRequestPerTaskListCounter:                   {metricName: "lease_requests_per_tl", metricRollupName: "lease_requests"},
+		LeaseFailurePerTaskListCounter:                   {metricName: "lease_failures_per_tl", metricRollupName: "lease_failures"},
+		ConditionFailedErrorPerTaskListCounter:           {metricName: "condition_failed_errors_per_tl", metricRollupName: "condition_failed_errors"},
+		RespondQueryTaskFailedPerTaskListCounter:         {metricName: "respond_query_failed_per_tl", metricRollupName: "respond_query_failed"},
+		SyncThrottlePerTaskListCounter:                   {metricName: "sync_throttle_count_per_tl", metricRollupName: "sync_throttle_count"},
+		BufferThrottlePerTaskListCounter:                 {metricName: "buffer_throttle_count_per_tl", metricRollupName: "buffer_throttle_count"},
+		ExpiredTasksPerTaskListCounter:                   {metricName: "tasks_expired_per_tl", metricRollupName: "tasks_expired"},
+		ForwardedPerTaskListCounter:                      {metricName: "forwarded_per_tl", metricRollupName: "forwarded"},
+		ForwardTaskCallsPerTaskList:                      {metricName: "forward_task_calls_per_tl", metricRollupName: "forward_task_calls"},
+		ForwardTaskErrorsPerTaskList:                     {metricName: "forward_task_errors_per_tl", metricRollupName: "forward_task_errors"},
+		ForwardQueryCallsPerTaskList:                     {metricName: "forward_query_calls_per_tl", metricRollupName: "forward_query_calls"},
+		ForwardQueryErrorsPerTaskList:                    {metricName: "forward_query_errors_per_tl", metricRollupName: "forward_query_errors"},
+		ForwardPollCallsPerTaskList:                      {metricName: "forward_poll_calls_per_tl", metricRollupName: "forward_poll_calls"},
+		ForwardPollErrorsPerTaskList:                     {metricName: "forward_poll_errors_per_tl", metricRollupName: "forward_poll_errors"},
+		SyncMatchLatencyPerTaskList:                      {metricName: "syncmatch_latency_per_tl", metricRollupName: "syncmatch_latency", metricType: Timer},
+		AsyncMatchLatencyPerTaskList:                     {metricName: "asyncmatch_latency_per_tl", metricRollupName: "asyncmatch_latency", metricType: Timer},
+		ScheduleToStartSyncPerTaskList:                   {metricName: "schedule_to_start_sync_per_tl", metricRollupName: "schedule_to_start_sync", metricType: Histogram, buckets: ScheduleToStartLatencyBuckets},
+		ScheduleToStartBufferedPerTaskList:               {metricName: "schedule_to_start_buffered_per_tl", metricRollupName: "schedule_to_start_buffered", metricType: Histogram, buckets: ScheduleToStartLatencyBuckets},
+		ForwardTaskLatencyPerTaskList:                    {metricName: "forward_task_latency_per_tl", metricRollupName: "forward_task_latency"},
+		ForwardQueryLatencyPerTaskList:                   {metricName: "forward_query_latency_per_tl", metricRollupName: "forward_query_latency"},
+		ForwardPollLatencyPerTaskList:                    {metricName: "forward_poll_latency_per_tl", metricRollupName: "forward_poll_latency"},
+		LocalToLocalMatchPerTaskListCounter:              {metricName: "local_to_local_matches_per_tl", metricRollupName: "local_to_local_matches"},
+		LocalToRemoteMatchPerTaskListCounter:             {metricName: "local_to_remote_matches_per_tl", metricRollupName: "local_to_remote_matches"},
+		RemoteToLocalMatchPerTaskListCounter:             {metricName: "remote_to_local_matches_per_tl", metricRollupName: "remote_to_local_matches"},
+		RemoteToRemoteMatchPerTaskListCounter:            {metricName: "remote_to_remote_matches_per_tl", metricRollupName: "remote_to_remote_matches"},
+		PollerPerTaskListCounter:                         {metricName: "poller_count_per_tl", metricRollupName: "poller_count"},
+		TaskListManagersGauge:                            {metricName: "tasklist_managers", metricType: Gauge},
+		TaskListHandoffCounter:                           {metricName: "tasklist_handoff_count", metricType: Counter},
+		TaskListHandoffFailedCounter:                     {metricName: "tasklist_handoff_failed_count", metricType: Counter},
+		TaskLagPerTaskListGauge:                          {metricName: "task_lag_per_tl", metricType: Gauge},
+		TaskBacklogPerTaskListGauge:                      {metricName: "task_backlog_per_tl", metricType: Gauge},
+		PollersWaitingPerTaskListGauge:                   {metricName: "pollers_waiting_per_tl", metricType: Gauge},
+		IsolationGroupMatchPerTaskListCounter:            {metricName: "isolation_group_matches_per_tl", metricRollupName: "isolation_group_matches"},
+		IsolationSpilloverPerTaskListCounter:             {metricName: "isolation_spillovers_per_tl", metricRollupName: "isolation_spillovers"},
+		GetTasksBatchSizePerTaskListGauge:                {metricName: "get_tasks_batch_size_per_tl", metricType: Gauge},
+		EmptyTaskReadBackoffPerTaskListCounter:           {metricName: "empty_task_read_backoff_per_tl", metricRollupName: "empty_task_read_backoff"},
+		ExpiredTaskAgePerTaskList:                        {metricName: "expired_task_age_per_tl", metricRollupName: "expired_task_age", metricType: Timer},
+		ExpiredTasksDeadLetteredPerTaskListCounter:       {metricName: "tasks_expired_dead_lettered_per_tl", metricRollupName: "tasks_expired_dead_lettered"},
+		ExpiredTasksDeadLetterFailuresPerTaskListCounter: {metricName: "tasks_expired_dead_letter_failures_per_tl", metricRollupName: "tasks_expired_dead_letter_failures"},
+		LocalSyncMatchLatencyPerTaskList:                 {metricName: "local_syncmatch_latency_per_tl", metricRollupName: "local_syncmatch_latency", metricType: Histogram, buckets: ScheduleToStartLatencyBuckets},
+		ForwardedSyncMatchLatencyPerTaskList:             {metricName: "forwarded_syncmatch_latency_per_tl", metricRollupName: "forwarded_syncmatch_latency", metricType: Histogram, buckets: ScheduleToStartLatencyBuckets},
+		BacklogDispatchLatencyPerTaskList:                {metricName: "backlog_dispatch_latency_per_tl", metricRollupName: "backlog_dispatch_latency", metricType: Histogram, buckets: ScheduleToStartLatencyBuckets},
 	},
 	Worker: {
 		ReplicatorMessages:                            {metricName: "replicator_messages"},
@@ -3011,6 +3284,54 @@ var PersistenceLatencyBuckets = tally.DurationBuckets([]time.Duration{
 	60 * time.Second,
 })
 
+// ScheduleToStartLatencyBuckets contains duration buckets for measuring the time tasks wait in matching until they are dispatched,
+// buffered tasks may wait for hours if the workers of a task list are down
+var ScheduleToStartLatencyBuckets = tally.DurationBuckets([]time.Duration{
+	1 * time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	20 * time.Second,
+	30 * time.Second,
+	1 * time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	20 * time.Minute,
+	30 * time.Minute,
+	1 * time.Hour,
+	2 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+})
+
+// PayloadSizeBuckets contains byte buckets for measuring the size of RPC payloads, up to the largest transport limits
+var PayloadSizeBuckets = tally.ValueBuckets([]float64{
+	1 << 10,
+	4 << 10,
+	16 << 10,
+	64 << 10,
+	256 << 10,
+	512 << 10,
+	1 << 20,
+	2 << 20,
+	4 << 20,
+	8 << 20,
+	16 << 20,
+	32 << 20,
+	64 << 20,
+})
+
 // ErrorClass is an enum to help with classifying SLA vs. non-SLA errors (SLA = "service level agreement")
 type ErrorClass uint8
 
@@ -3032,3 +3353,11 @@ func (mn MetricName) Empty() bool {
 func (mn MetricName) String() string {
 	return string(mn)
 }
+
+// OperationName returns the operation tag of a scope defined in the common scopes or the scopes of the service
+func OperationName(serviceIdx ServiceIdx, scope int) string {
+	if def, ok := ScopeDefs[Common][scope]; ok {
+		return def.operation
+	}
+	return ScopeDefs[serviceIdx][scope].operation
+}
@@ -31,6 +31,8 @@ type metricsScope struct {
 	rootScope      tally.Scope
 	defs           map[int]metricDefinition
 	isDomainTagged bool
+	scopeIdx       int
+	limiter        *cardinalityLimiter
 }
 
 func newMetricsScope(
@@ -38,12 +40,16 @@ func newMetricsScope(
 	scope tally.Scope,
 	defs map[int]metricDefinition,
 	isDomain bool,
+	scopeIdx int,
+	limiter *cardinalityLimiter,
 ) Scope {
 	return &metricsScope{
 		scope:          scope,
 		rootScope:      rootScope,
 		defs:           defs,
 		isDomainTagged: isDomain,
+		scopeIdx:       scopeIdx,
+		limiter:        limiter,
 	}
 }
 
@@ -126,9 +132,10 @@ func (m *metricsScope) Tagged(tags ...Tag) Scope {
 		if isDomainTagged(tag) {
 			domainTagged = true
 		}
+		tag = m.limiter.tag(m.scopeIdx, tag)
 		tagMap[tag.Key()] = tag.Value()
 	}
-	return newMetricsScope(m.rootScope, m.scope.Tagged(tagMap), m.defs, domainTagged)
+	return newMetricsScope(m.rootScope, m.scope.Tagged(tagMap), m.defs, domainTagged, m.scopeIdx, m.limiter)
 }
 
 func (m *metricsScope) getBuckets(id int) tally.Buckets {
@@ -41,6 +41,8 @@ const (
 	activeCluster          = "active_cluster"
 	taskList               = "tasklist"
 	taskListType           = "tasklistType"
+	taskListKind           = "tasklistKind"
+	taskListPartition      = "tasklistPartition"
 	workflowType           = "workflowType"
 	activityType           = "activityType"
 	decisionType           = "decisionType"
@@ -53,6 +55,9 @@ const (
 	signalName             = "signalName"
 	workflowVersion        = "workflow_version"
 	shardID                = "shard_id"
+	watchdogResource       = "watchdog_resource"
+	apiName                = "api_name"
+	certificate            = "certificate"
 
 	allValue     = "all"
 	unknownValue = "_unknown_"
@@ -152,6 +157,32 @@ func TaskListTypeTag(value string) Tag {
 	return metricWithUnknown(taskListType, value)
 }
 
+// TaskListKindTag returns a new task list kind tag.
+func TaskListKindTag(value string) Tag {
+	return metricWithUnknown(taskListKind, value)
+}
+
+// TaskListPartitionTag returns a tag telling the root partition of a task list from its child partitions.
+func TaskListPartitionTag(isRoot bool) Tag {
+	var value string
+	if isRoot {
+		value = "root"
+	} else {
+		value = "child"
+	}
+	return simpleMetric{key: taskListPartition, value: value}
+}
+
+// WatchdogResourceTag returns a new tag for the resource tracked by the leak watchdog.
+func WatchdogResourceTag(value string) Tag {
+	return simpleMetric{key: watchdogResource, value: value}
+}
+
+// APINameTag returns a new tag for the name of an RPC API.
+func APINameTag(value string) Tag {
+	return metricWithUnknown(apiName, value)
+}
+
 // WorkflowTypeTag returns a new workflow type tag.
 func WorkflowTypeTag(value string) Tag {
 	return metricWithUnknown(workflowType, value)
@@ -211,3 +242,8 @@ func SignalNameAllTag() Tag {
 func WorkflowVersionTag(value string) Tag {
 	return metricWithUnknown(workflowVersion, value)
 }
+
+// CertificateTag returns a new Certificate tag, the value is the file the certificate is loaded from
+func CertificateTag(file string) Tag {
+	return simpleMetric{key: certificate, value: file}
+}
@@ -0,0 +1,217 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package otlp
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+type (
+	exportRequest struct {
+		ResourceMetrics []resourceMetrics `json:"resourceMetrics"`
+	}
+
+	resourceMetrics struct {
+		Resource     resource       `json:"resource"`
+		ScopeMetrics []scopeMetrics `json:"scopeMetrics"`
+	}
+
+	resource struct {
+		Attributes []attribute `json:"attributes"`
+	}
+
+	scopeMetrics struct {
+		Scope   scope    `json:"scope"`
+		Metrics []metric `json:"metrics"`
+	}
+
+	scope struct {
+		Name string `json:"name"`
+	}
+
+	metric struct {
+		Name      string         `json:"name"`
+		Unit      string         `json:"unit,omitempty"`
+		Sum       *sum           `json:"sum,omitempty"`
+		Gauge     *gaugeData     `json:"gauge,omitempty"`
+		Histogram *histogramData `json:"histogram,omitempty"`
+	}
+
+	sum struct {
+		DataPoints             []numberDataPoint `json:"dataPoints"`
+		AggregationTemporality int               `json:"aggregationTemporality"`
+		IsMonotonic            bool              `json:"isMonotonic"`
+	}
+
+	gaugeData struct {
+		DataPoints []numberDataPoint `json:"dataPoints"`
+	}
+
+	histogramData struct {
+		DataPoints             []histogramDataPoint `json:"dataPoints"`
+		AggregationTemporality int                  `json:"aggregationTemporality"`
+	}
+
+	numberDataPoint struct {
+		Attributes        []attribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string      `json:"startTimeUnixNano,omitempty"`
+		TimeUnixNano      string      `json:"timeUnixNano"`
+		AsInt             *string     `json:"asInt,omitempty"`
+		AsDouble          *float64    `json:"asDouble,omitempty"`
+	}
+
+	histogramDataPoint struct {
+		Attributes        []attribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string      `json:"startTimeUnixNano"`
+		TimeUnixNano      string      `json:"timeUnixNano"`
+		Count             string      `json:"count"`
+		Sum               *float64    `json:"sum,omitempty"`
+		BucketCounts      []string    `json:"bucketCounts"`
+		ExplicitBounds    []float64   `json:"explicitBounds"`
+	}
+
+	attribute struct {
+		Key   string         `json:"key"`
+		Value attributeValue `json:"value"`
+	}
+
+	attributeValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+// newExportRequest builds the request from the aggregated metrics, it must be called with the lock held.
+// Data points of the same metric are grouped, which is required by some OTLP receivers.
+func (r *reporter) newExportRequest(now time.Time) *exportRequest {
+	startTime := formatTime(r.start)
+	timestamp := formatTime(now)
+	metricsByName := make(map[string]*metric)
+	getMetric := func(name string) *metric {
+		m, ok := metricsByName[name]
+		if !ok {
+			m = &metric{Name: name}
+			metricsByName[name] = m
+		}
+		return m
+	}
+
+	for _, c := range r.counters {
+		m := getMetric(c.name)
+		if m.Sum == nil {
+			m.Sum = &sum{AggregationTemporality: aggregationTemporalityCumulative, IsMonotonic: true}
+		}
+		value := strconv.FormatInt(c.value, 10)
+		m.Sum.DataPoints = append(m.Sum.DataPoints, numberDataPoint{
+			Attributes:        toAttributes(c.tags),
+			StartTimeUnixNano: startTime,
+			TimeUnixNano:      timestamp,
+			AsInt:             &value,
+		})
+	}
+	for _, g := range r.gauges {
+		m := getMetric(g.name)
+		if m.Gauge == nil {
+			m.Gauge = &gaugeData{}
+		}
+		value := g.value
+		m.Gauge.DataPoints = append(m.Gauge.DataPoints, numberDataPoint{
+			Attributes:   toAttributes(g.tags),
+			TimeUnixNano: timestamp,
+			AsDouble:     &value,
+		})
+	}
+	for _, h := range r.histograms {
+		m := getMetric(h.name)
+		if m.Histogram == nil {
+			m.Histogram = &histogramData{AggregationTemporality: aggregationTemporalityCumulative}
+			m.Unit = h.unit
+		}
+		counts := make([]string, 0, len(h.counts))
+		for _, count := range h.counts {
+			counts = append(counts, formatUint(count))
+		}
+		point := histogramDataPoint{
+			Attributes:        toAttributes(h.tags),
+			StartTimeUnixNano: startTime,
+			TimeUnixNano:      timestamp,
+			Count:             formatUint(h.count),
+			BucketCounts:      counts,
+			ExplicitBounds:    h.bounds,
+		}
+		// the sum is only known for timers, histograms are reported as bucket counts by tally
+		if h.sum > 0 {
+			total := h.sum
+			point.Sum = &total
+		}
+		m.Histogram.DataPoints = append(m.Histogram.DataPoints, point)
+	}
+
+	names := make([]string, 0, len(metricsByName))
+	for name := range metricsByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metrics := make([]metric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, *metricsByName[name])
+	}
+
+	var resourceAttributes []attribute
+	if r.options.ServiceName != "" {
+		resourceAttributes = append(resourceAttributes, newAttribute("service.name", r.options.ServiceName))
+	}
+	return &exportRequest{
+		ResourceMetrics: []resourceMetrics{{
+			Resource: resource{Attributes: resourceAttributes},
+			ScopeMetrics: []scopeMetrics{{
+				Scope:   scope{Name: instrumentationScope},
+				Metrics: metrics,
+			}},
+		}},
+	}
+}
+
+func toAttributes(tags map[string]string) []attribute {
+	attributes := make([]attribute, 0, len(tags))
+	for k, v := range tags {
+		attributes = append(attributes, newAttribute(k, v))
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Key < attributes[j].Key
+	})
+	return attributes
+}
+
+func newAttribute(key, value string) attribute {
+	return attribute{Key: key, Value: attributeValue{StringValue: value}}
+}
+
+func formatTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func formatUint(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
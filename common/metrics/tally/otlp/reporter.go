@@ -0,0 +1,331 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package otlp implements a tally reporter which pushes the metrics to an OpenTelemetry collector
+// with the OpenTelemetry protocol (OTLP) over http
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber-go/tally"
+
+	mprom "github.com/uber/cadence/common/metrics/tally/prometheus"
+)
+
+const (
+	metricsPath = "/v1/metrics"
+
+	defaultPushInterval = 10 * time.Second
+	pushTimeout         = 10 * time.Second
+
+	// values are reported as running totals since the reporter was created
+	aggregationTemporalityCumulative = 2
+	unitSeconds                      = "s"
+	instrumentationScope             = "github.com/uber/cadence"
+)
+
+type (
+	// Options are the options of the OTLP reporter
+	Options struct {
+		// Endpoint is the base url of the OTLP http receiver, ex: http://localhost:4318
+		Endpoint string
+		// Headers are added to every push request
+		Headers map[string]string
+		// ServiceName is reported as the service.name resource attribute
+		ServiceName string
+		// PushInterval is the interval the metrics are pushed at, 10s by default
+		PushInterval time.Duration
+		// HTTPClient is used to push the metrics, http.DefaultClient is used if nil
+		HTTPClient *http.Client
+		// OnError is called when pushing the metrics fails
+		OnError func(err error)
+	}
+
+	reporter struct {
+		options Options
+		url     string
+		start   time.Time
+		pushing int32
+
+		sync.Mutex
+		lastPush   time.Time
+		counters   map[string]*counter
+		gauges     map[string]*gauge
+		histograms map[string]*histogram
+	}
+
+	counter struct {
+		name  string
+		tags  map[string]string
+		value int64
+	}
+
+	gauge struct {
+		name  string
+		tags  map[string]string
+		value float64
+	}
+
+	histogram struct {
+		name   string
+		tags   map[string]string
+		unit   string
+		bounds []float64
+		// counts has one more item than bounds for the values above the highest bound
+		counts []uint64
+		count  uint64
+		sum    float64
+	}
+)
+
+var _ tally.StatsReporter = (*reporter)(nil)
+
+// timerBounds are the histogram bounds in seconds which timers are aggregated into
+var timerBounds = func() []float64 {
+	var bounds []float64
+	for _, objective := range mprom.DefaultHistogramBuckets() {
+		bounds = append(bounds, objective.Upper)
+	}
+	return bounds
+}()
+
+// NewReporter creates a tally reporter which aggregates the metrics in memory and pushes them
+// to an OTLP http receiver. Counters are reported as cumulative sums, gauges with their last value
+// and timers and histograms as cumulative histograms.
+func NewReporter(options Options) (tally.StatsReporter, error) {
+	if options.Endpoint == "" {
+		return nil, errors.New("endpoint must be set to push metrics")
+	}
+	if options.PushInterval <= 0 {
+		options.PushInterval = defaultPushInterval
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+	if options.OnError == nil {
+		options.OnError = func(error) {}
+	}
+	now := time.Now()
+	return &reporter{
+		options:    options,
+		url:        strings.TrimSuffix(options.Endpoint, "/") + metricsPath,
+		start:      now,
+		lastPush:   now,
+		counters:   make(map[string]*counter),
+		gauges:     make(map[string]*gauge),
+		histograms: make(map[string]*histogram),
+	}, nil
+}
+
+func (r *reporter) ReportCounter(name string, tags map[string]string, value int64) {
+	r.Lock()
+	defer r.Unlock()
+
+	key := metricKey(name, tags)
+	c, ok := r.counters[key]
+	if !ok {
+		c = &counter{name: name, tags: tags}
+		r.counters[key] = c
+	}
+	c.value += value
+}
+
+func (r *reporter) ReportGauge(name string, tags map[string]string, value float64) {
+	r.Lock()
+	defer r.Unlock()
+
+	key := metricKey(name, tags)
+	g, ok := r.gauges[key]
+	if !ok {
+		g = &gauge{name: name, tags: tags}
+		r.gauges[key] = g
+	}
+	g.value = value
+}
+
+func (r *reporter) ReportTimer(name string, tags map[string]string, interval time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+
+	h := r.getHistogram(name, tags, timerBounds, unitSeconds)
+	seconds := interval.Seconds()
+	h.counts[sort.SearchFloat64s(h.bounds, seconds)]++
+	h.count++
+	h.sum += seconds
+}
+
+func (r *reporter) ReportHistogramValueSamples(
+	name string,
+	tags map[string]string,
+	buckets tally.Buckets,
+	bucketLowerBound,
+	bucketUpperBound float64,
+	samples int64,
+) {
+	r.reportHistogramSamples(name, tags, buckets.AsValues(), "", bucketUpperBound, samples)
+}
+
+func (r *reporter) ReportHistogramDurationSamples(
+	name string,
+	tags map[string]string,
+	buckets tally.Buckets,
+	bucketLowerBound,
+	bucketUpperBound time.Duration,
+	samples int64,
+) {
+	durations := buckets.AsDurations()
+	bounds := make([]float64, 0, len(durations))
+	for _, d := range durations {
+		bounds = append(bounds, d.Seconds())
+	}
+	upper := math.MaxFloat64
+	if bucketUpperBound != time.Duration(math.MaxInt64) {
+		upper = bucketUpperBound.Seconds()
+	}
+	r.reportHistogramSamples(name, tags, bounds, unitSeconds, upper, samples)
+}
+
+func (r *reporter) reportHistogramSamples(
+	name string,
+	tags map[string]string,
+	bounds []float64,
+	unit string,
+	bucketUpperBound float64,
+	samples int64,
+) {
+	r.Lock()
+	defer r.Unlock()
+
+	h := r.getHistogram(name, tags, bounds, unit)
+	// the bucket above the highest bound has the max value as upper bound
+	h.counts[sort.SearchFloat64s(h.bounds, bucketUpperBound)] += uint64(samples)
+	h.count += uint64(samples)
+}
+
+func (r *reporter) getHistogram(name string, tags map[string]string, bounds []float64, unit string) *histogram {
+	key := metricKey(name, tags)
+	h, ok := r.histograms[key]
+	if !ok {
+		h = &histogram{
+			name:   name,
+			tags:   tags,
+			unit:   unit,
+			bounds: bounds,
+			counts: make([]uint64, len(bounds)+1),
+		}
+		r.histograms[key] = h
+	}
+	return h
+}
+
+func (r *reporter) Capabilities() tally.Capabilities {
+	return r
+}
+
+func (r *reporter) Reporting() bool {
+	return true
+}
+
+func (r *reporter) Tagging() bool {
+	return true
+}
+
+// Flush is called by tally after every report, the metrics are pushed once the push interval elapsed.
+// Pushing is done in the background so that it doesn't block the reporting of the scope.
+func (r *reporter) Flush() {
+	now := time.Now()
+	r.Lock()
+	if now.Sub(r.lastPush) < r.options.PushInterval {
+		r.Unlock()
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&r.pushing, 0, 1) {
+		// values are cumulative, so they are pushed with the next push
+		r.Unlock()
+		return
+	}
+	r.lastPush = now
+	request := r.newExportRequest(now)
+	r.Unlock()
+
+	go func() {
+		defer atomic.StoreInt32(&r.pushing, 0)
+		if err := r.push(request); err != nil {
+			r.options.OnError(err)
+		}
+	}()
+}
+
+func (r *reporter) push(request *exportRequest) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range r.options.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := r.options.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to push metrics: %v", resp.Status)
+	}
+	return nil
+}
+
+func metricKey(name string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buffer strings.Builder
+	buffer.WriteString(name)
+	for _, k := range keys {
+		buffer.WriteString("," + k + "=" + tags[k])
+	}
+	return buffer.String()
+}
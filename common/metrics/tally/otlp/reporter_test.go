@@ -0,0 +1,136 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package otlp
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestNewReporter_EndpointRequired(t *testing.T) {
+	_, err := NewReporter(Options{})
+	require.Error(t, err)
+}
+
+func TestReporter_Push(t *testing.T) {
+	requestC := make(chan *exportRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, metricsPath, r.URL.Path)
+		require.Equal(t, "secret", r.Header.Get("Authorization"))
+
+		var request exportRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requestC <- &request
+	}))
+	defer server.Close()
+
+	r, err := NewReporter(Options{
+		Endpoint:     server.URL,
+		Headers:      map[string]string{"Authorization": "secret"},
+		ServiceName:  "cadence-history",
+		PushInterval: time.Nanosecond,
+	})
+	require.NoError(t, err)
+
+	tags := map[string]string{"operation": "StartWorkflowExecution", "domain": "test-domain"}
+	r.ReportCounter("cadence_requests", tags, 2)
+	r.ReportCounter("cadence_requests", tags, 3)
+	r.ReportGauge("shards", nil, 10)
+	r.ReportGauge("shards", nil, 12)
+	r.ReportTimer("cadence_latency", tags, 3*time.Millisecond)
+	r.ReportTimer("cadence_latency", tags, time.Minute)
+	buckets := tally.ValueBuckets{10, 100}
+	r.ReportHistogramValueSamples("history_size", nil, buckets, 10, 100, 4)
+	r.ReportHistogramValueSamples("history_size", nil, buckets, 100, math.MaxFloat64, 0)
+	r.Flush()
+
+	var request *exportRequest
+	select {
+	case request = <-requestC:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "metrics were not pushed")
+	}
+
+	require.Len(t, request.ResourceMetrics, 1)
+	require.Equal(t, []attribute{newAttribute("service.name", "cadence-history")}, request.ResourceMetrics[0].Resource.Attributes)
+	metrics := request.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 4)
+
+	latency := metrics[0]
+	require.Equal(t, "cadence_latency", latency.Name)
+	require.Equal(t, unitSeconds, latency.Unit)
+	require.Len(t, latency.Histogram.DataPoints, 1)
+	point := latency.Histogram.DataPoints[0]
+	require.Equal(t, "2", point.Count)
+	require.Equal(t, timerBounds, point.ExplicitBounds)
+	require.Len(t, point.BucketCounts, len(timerBounds)+1)
+	require.Equal(t, "1", point.BucketCounts[len(timerBounds)])
+	require.InDelta(t, 60.003, *point.Sum, 0.0001)
+	require.Equal(t, []attribute{
+		newAttribute("domain", "test-domain"),
+		newAttribute("operation", "StartWorkflowExecution"),
+	}, point.Attributes)
+
+	requests := metrics[1]
+	require.Equal(t, "cadence_requests", requests.Name)
+	require.True(t, requests.Sum.IsMonotonic)
+	require.Equal(t, aggregationTemporalityCumulative, requests.Sum.AggregationTemporality)
+	require.Equal(t, "5", *requests.Sum.DataPoints[0].AsInt)
+
+	historySize := metrics[2]
+	require.Equal(t, "history_size", historySize.Name)
+	require.Equal(t, []float64{10, 100}, historySize.Histogram.DataPoints[0].ExplicitBounds)
+	require.Equal(t, []string{"0", "4", "0"}, historySize.Histogram.DataPoints[0].BucketCounts)
+	require.Nil(t, historySize.Histogram.DataPoints[0].Sum)
+
+	shards := metrics[3]
+	require.Equal(t, "shards", shards.Name)
+	require.Equal(t, 12.0, *shards.Gauge.DataPoints[0].AsDouble)
+}
+
+func TestReporter_PushInterval(t *testing.T) {
+	pushed := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushed <- struct{}{}
+	}))
+	defer server.Close()
+
+	r, err := NewReporter(Options{
+		Endpoint:     server.URL,
+		PushInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	r.ReportCounter("cadence_requests", nil, 1)
+	r.Flush()
+	r.Flush()
+
+	time.Sleep(100 * time.Millisecond)
+	require.Empty(t, pushed)
+}
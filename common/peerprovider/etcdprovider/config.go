@@ -0,0 +1,113 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package etcdprovider
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPrefix         = "/cadence/membership"
+	defaultLeaseTTL       = 10 * time.Second
+	defaultRequestTimeout = 5 * time.Second
+	minLeaseTTL           = 2 * time.Second
+)
+
+// Config contains the config of the membership kept in etcd. Every host puts its key under the prefix with a
+// lease it keeps alive, so that the key is deleted by etcd once the host is gone, and watches the prefix to see
+// the other hosts. The endpoints are the ones of the etcd gRPC gateway, which etcd serves on its client URLs.
+type Config struct {
+	// Endpoints are the client URLs of the etcd cluster, e.g. http://etcd-0:2379
+	Endpoints []string `yaml:"endpoints"`
+	// Prefix of the keys of the hosts, /cadence/membership by default
+	Prefix string `yaml:"prefix"`
+	// Address is the IP other hosts reach this host on, the IP the hostname resolves to by default
+	Address string `yaml:"address"`
+	// LeaseTTL is how long the key of a host outlives it when it isn't stopped gracefully, 10s by default
+	LeaseTTL time.Duration `yaml:"leaseTTL"`
+	// RequestTimeout is the timeout of the requests to etcd, other than the watches, 5s by default
+	RequestTimeout time.Duration `yaml:"requestTimeout"`
+	// CAFile is the CA of the etcd server when its client URLs are https
+	CAFile string `yaml:"caFile"`
+	// CertFile and KeyFile are the client certificate of this host when etcd requires client authentication
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+}
+
+func (c *Config) validate() error {
+	if len(c.Endpoints) == 0 {
+		return fmt.Errorf("etcd membership config missing `endpoints` param")
+	}
+	for i, endpoint := range c.Endpoints {
+		if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+			return fmt.Errorf("etcd membership config has invalid endpoint %q, expected an http or https URL", endpoint)
+		}
+		c.Endpoints[i] = strings.TrimSuffix(endpoint, "/")
+	}
+
+	if c.Prefix == "" {
+		c.Prefix = defaultPrefix
+	}
+	c.Prefix = strings.TrimSuffix(c.Prefix, "/")
+	if c.Address == "" {
+		c.Address = hostIP()
+	}
+	if net.ParseIP(c.Address) == nil {
+		return fmt.Errorf("etcd membership config has invalid address %q, set `address`", c.Address)
+	}
+	if c.LeaseTTL <= 0 {
+		c.LeaseTTL = defaultLeaseTTL
+	}
+	if c.LeaseTTL < minLeaseTTL {
+		return fmt.Errorf("etcd membership config `leaseTTL` must be at least %v", minLeaseTTL)
+	}
+	if c.RequestTimeout <= 0 {
+		c.RequestTimeout = defaultRequestTimeout
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("etcd membership config must set both `certFile` and `keyFile`")
+	}
+	return nil
+}
+
+// hostIP returns the first IP the hostname resolves to which isn't a loopback one, if any
+func hostIP() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	ips, err := net.LookupIP(hostname)
+	if err != nil {
+		return ""
+	}
+	for _, ip := range ips {
+		if !ip.IsLoopback() {
+			return ip.String()
+		}
+	}
+	return ""
+}
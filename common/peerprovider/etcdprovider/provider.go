@@ -0,0 +1,622 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package etcdprovider
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/membership"
+)
+
+const (
+	eventTypeDelete = "DELETE"
+
+	watchRetryInterval = time.Second
+)
+
+type (
+	// Provider resolves the members of the cadence services from the keys the hosts keep alive in etcd
+	Provider struct {
+		status     int32
+		service    string
+		config     *Config
+		client     *http.Client
+		portmap    membership.PortMap
+		zone       string
+		logger     log.Logger
+		ctx        context.Context
+		cancel     context.CancelFunc
+		shutdownWG sync.WaitGroup
+		endpoint   int32
+
+		mu          sync.RWMutex
+		leaseID     int64
+		revision    int64
+		hosts       map[string]membership.HostInfo
+		members     map[string][]membership.HostInfo
+		evicted     bool
+		subscribers map[string]chan<- *membership.ChangedEvent
+	}
+
+	// member is the value of the key of a host
+	member struct {
+		Address string             `json:"address"`
+		Ports   membership.PortMap `json:"ports"`
+		Zone    string             `json:"zone,omitempty"`
+	}
+
+	// the types below are the JSON mapping of the etcd v3 API served by the gRPC gateway, whose int64
+	// fields are strings and bytes fields are base64
+	responseHeader struct {
+		Revision int64 `json:"revision,string"`
+	}
+
+	keyValue struct {
+		Key   []byte `json:"key"`
+		Value []byte `json:"value"`
+	}
+
+	leaseGrantRequest struct {
+		TTL int64 `json:"TTL,string"`
+	}
+
+	leaseGrantResponse struct {
+		ID    int64  `json:"ID,string"`
+		TTL   int64  `json:"TTL,string"`
+		Error string `json:"error"`
+	}
+
+	leaseRequest struct {
+		ID int64 `json:"ID,string"`
+	}
+
+	leaseKeepAliveResponse struct {
+		Result struct {
+			TTL int64 `json:"TTL,string"`
+		} `json:"result"`
+	}
+
+	putRequest struct {
+		Key   []byte `json:"key"`
+		Value []byte `json:"value"`
+		Lease int64  `json:"lease,string"`
+	}
+
+	rangeRequest struct {
+		Key      []byte `json:"key"`
+		RangeEnd []byte `json:"range_end"`
+	}
+
+	rangeResponse struct {
+		Header responseHeader `json:"header"`
+		Kvs    []keyValue     `json:"kvs"`
+	}
+
+	watchRequest struct {
+		CreateRequest watchCreateRequest `json:"create_request"`
+	}
+
+	watchCreateRequest struct {
+		Key           []byte `json:"key"`
+		RangeEnd      []byte `json:"range_end"`
+		StartRevision int64  `json:"start_revision,string"`
+	}
+
+	watchResponse struct {
+		Result *struct {
+			Header          responseHeader `json:"header"`
+			Canceled        bool           `json:"canceled"`
+			CancelReason    string         `json:"cancel_reason"`
+			CompactRevision int64          `json:"compact_revision,string"`
+			Events          []struct {
+				Type string   `json:"type"`
+				Kv   keyValue `json:"kv"`
+			} `json:"events"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+)
+
+var _ membership.PeerProvider = (*Provider)(nil)
+
+// New creates a peer provider keeping the membership in etcd
+func New(
+	service string,
+	config *Config,
+	portMap membership.PortMap,
+	zone string,
+	logger log.Logger,
+) (*Provider, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.CAFile != "" || config.CertFile != "" {
+		tlsConfig := &tls.Config{}
+		if config.CAFile != "" {
+			ca, err := ioutil.ReadFile(config.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("reading etcd CA: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return nil, fmt.Errorf("invalid etcd CA %v", config.CAFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		if config.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("loading etcd client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	// the client has no timeout as the watches are long lived, the other requests time out on their context
+	return NewEtcdProvider(service, config, &http.Client{Transport: transport}, portMap, zone, logger), nil
+}
+
+// NewEtcdProvider sets up the etcd based peer provider with a validated config
+func NewEtcdProvider(
+	service string,
+	config *Config,
+	client *http.Client,
+	portMap membership.PortMap,
+	zone string,
+	logger log.Logger,
+) *Provider {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Provider{
+		status:      common.DaemonStatusInitialized,
+		service:     service,
+		config:      config,
+		client:      client,
+		portmap:     portMap,
+		zone:        zone,
+		logger:      logger,
+		ctx:         ctx,
+		cancel:      cancel,
+		hosts:       map[string]membership.HostInfo{},
+		members:     map[string][]membership.HostInfo{},
+		subscribers: map[string]chan<- *membership.ChangedEvent{},
+	}
+}
+
+// Start registers this host, lists the hosts of the services, and keeps watching them in background
+func (p *Provider) Start() {
+	if !atomic.CompareAndSwapInt32(
+		&p.status,
+		common.DaemonStatusInitialized,
+		common.DaemonStatusStarted,
+	) {
+		return
+	}
+
+	// failures are retried by the loops, so that the host comes up while etcd is unavailable
+	if err := p.register(); err != nil {
+		p.logger.Warn("failed to register host in etcd", tag.Error(err))
+	}
+	if err := p.resync(); err != nil {
+		p.logger.Warn("failed to list etcd members", tag.Error(err))
+	}
+
+	p.shutdownWG.Add(2)
+	go p.keepAliveLoop()
+	go p.watchLoop()
+}
+
+// Stop stops watching the hosts and revokes the lease of this host, which removes it from the members
+func (p *Provider) Stop() {
+	if !atomic.CompareAndSwapInt32(
+		&p.status,
+		common.DaemonStatusStarted,
+		common.DaemonStatusStopped,
+	) {
+		return
+	}
+
+	p.cancel()
+	if success := common.AwaitWaitGroup(&p.shutdownWG, time.Minute); !success {
+		p.logger.Warn("etcd peer provider timed out on shutdown.")
+	}
+	if err := p.revoke(); err != nil {
+		p.logger.Warn("failed to revoke etcd lease", tag.Error(err))
+	}
+}
+
+// SelfEvict revokes the lease of this host, so that every host removes it from the members, and stops
+// registering it again
+func (p *Provider) SelfEvict() error {
+	p.mu.Lock()
+	p.evicted = true
+	p.mu.Unlock()
+
+	p.update()
+	return p.revoke()
+}
+
+// GetMembers returns the hosts of the service, as of the last event seen from etcd
+func (p *Provider) GetMembers(service string) ([]membership.HostInfo, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	members := p.members[service]
+	res := make([]membership.HostInfo, len(members))
+	copy(res, members)
+	return res, nil
+}
+
+// WhoAmI returns the address of this host
+func (p *Provider) WhoAmI() (membership.HostInfo, error) {
+	return membership.NewDetailedHostInfo(p.selfAddress(), "", p.portmap).WithZone(p.zone), nil
+}
+
+// Subscribe allows to be subscribed for ring changes
+func (p *Provider) Subscribe(name string, notifyChannel chan<- *membership.ChangedEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.subscribers[name]
+	if ok {
+		return fmt.Errorf("%q already subscribed to etcd provider", name)
+	}
+
+	p.subscribers[name] = notifyChannel
+	return nil
+}
+
+func (p *Provider) selfAddress() string {
+	return net.JoinHostPort(p.config.Address, strconv.Itoa(int(p.portmap[membership.PortTchannel])))
+}
+
+func (p *Provider) selfKey() string {
+	return p.config.Prefix + "/" + p.service + "/" + p.selfAddress()
+}
+
+// keysRange returns the range of the keys of all the hosts, which are the ones starting with the prefix
+func (p *Provider) keysRange() (key []byte, rangeEnd []byte) {
+	key = []byte(p.config.Prefix + "/")
+	rangeEnd = []byte(p.config.Prefix + "0") // '0' follows '/'
+	return key, rangeEnd
+}
+
+// register grants a lease and puts the key of this host with it
+func (p *Provider) register() error {
+	var lease leaseGrantResponse
+	if err := p.call(p.ctx, "/v3/lease/grant", leaseGrantRequest{TTL: int64(p.config.LeaseTTL / time.Second)}, &lease); err != nil {
+		return fmt.Errorf("granting lease: %w", err)
+	}
+	if lease.ID == 0 {
+		return fmt.Errorf("granting lease: %v", lease.Error)
+	}
+
+	value, err := json.Marshal(member{Address: p.selfAddress(), Ports: p.portmap, Zone: p.zone})
+	if err != nil {
+		return err
+	}
+	if err := p.call(p.ctx, "/v3/kv/put", putRequest{Key: []byte(p.selfKey()), Value: value, Lease: lease.ID}, &struct{}{}); err != nil {
+		return fmt.Errorf("putting key: %w", err)
+	}
+
+	p.mu.Lock()
+	p.leaseID = lease.ID
+	p.mu.Unlock()
+	p.logger.Info("registered host in etcd", tag.Key(p.selfKey()), tag.Value(lease.ID))
+	return nil
+}
+
+// revoke revokes the lease of this host, which deletes its key
+func (p *Provider) revoke() error {
+	p.mu.Lock()
+	leaseID := p.leaseID
+	p.leaseID = 0
+	p.mu.Unlock()
+
+	if leaseID == 0 {
+		return nil
+	}
+	// the lease is revoked once the provider is stopped, so the request outlives its context
+	return p.call(context.Background(), "/v3/lease/revoke", leaseRequest{ID: leaseID}, &struct{}{})
+}
+
+func (p *Provider) keepAliveLoop() {
+	defer p.shutdownWG.Done()
+
+	ticker := time.NewTicker(p.config.LeaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.keepAlive()
+		}
+	}
+}
+
+// keepAlive renews the lease of this host, and registers it again if the lease expired, e.g. because etcd
+// was unreachable for longer than the TTL
+func (p *Provider) keepAlive() {
+	p.mu.RLock()
+	leaseID, evicted := p.leaseID, p.evicted
+	p.mu.RUnlock()
+
+	if evicted {
+		return
+	}
+	if leaseID != 0 {
+		var resp leaseKeepAliveResponse
+		if err := p.call(p.ctx, "/v3/lease/keepalive", leaseRequest{ID: leaseID}, &resp); err != nil {
+			p.logger.Warn("failed to keep etcd lease alive", tag.Error(err))
+			return
+		}
+		if resp.Result.TTL > 0 {
+			return
+		}
+		p.logger.Warn("etcd lease expired, registering host again")
+	}
+	if err := p.register(); err != nil {
+		p.logger.Warn("failed to register host in etcd", tag.Error(err))
+	}
+}
+
+// watchLoop watches the keys of the hosts from the revision they were listed at. The keys are listed again
+// whenever the watch breaks, as the events since then may be lost, e.g. if the revision was compacted.
+func (p *Provider) watchLoop() {
+	defer p.shutdownWG.Done()
+
+	for {
+		err := p.watch()
+		if p.ctx.Err() != nil {
+			return
+		}
+		p.logger.Warn("etcd watch failed", tag.Error(err))
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+		if err := p.resync(); err != nil {
+			p.logger.Warn("failed to list etcd members", tag.Error(err))
+		}
+	}
+}
+
+func (p *Provider) watch() error {
+	p.mu.RLock()
+	revision := p.revision
+	p.mu.RUnlock()
+
+	key, rangeEnd := p.keysRange()
+	resp, err := p.post(p.ctx, "/v3/watch", watchRequest{
+		CreateRequest: watchCreateRequest{Key: key, RangeEnd: rangeEnd, StartRevision: revision + 1},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var msg watchResponse
+		if err := decoder.Decode(&msg); err != nil {
+			return err
+		}
+		if msg.Error != nil {
+			return errors.New(msg.Error.Message)
+		}
+		if msg.Result == nil {
+			continue
+		}
+		if msg.Result.Canceled {
+			return fmt.Errorf("watch canceled: %v, compacted at revision %v", msg.Result.CancelReason, msg.Result.CompactRevision)
+		}
+		if len(msg.Result.Events) == 0 {
+			continue
+		}
+
+		p.mu.Lock()
+		for _, event := range msg.Result.Events {
+			key := string(event.Kv.Key)
+			if event.Type == eventTypeDelete {
+				delete(p.hosts, key)
+				continue
+			}
+			if host, ok := p.parseHost(event.Kv); ok {
+				p.hosts[key] = host
+			}
+		}
+		p.revision = msg.Result.Header.Revision
+		p.mu.Unlock()
+
+		p.update()
+	}
+}
+
+// resync lists the keys of all the hosts
+func (p *Provider) resync() error {
+	key, rangeEnd := p.keysRange()
+	var resp rangeResponse
+	if err := p.call(p.ctx, "/v3/kv/range", rangeRequest{Key: key, RangeEnd: rangeEnd}, &resp); err != nil {
+		return err
+	}
+
+	hosts := make(map[string]membership.HostInfo, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		if host, ok := p.parseHost(kv); ok {
+			hosts[string(kv.Key)] = host
+		}
+	}
+
+	p.mu.Lock()
+	p.hosts = hosts
+	p.revision = resp.Header.Revision
+	p.mu.Unlock()
+
+	p.update()
+	return nil
+}
+
+func (p *Provider) parseHost(kv keyValue) (membership.HostInfo, bool) {
+	var m member
+	if err := json.Unmarshal(kv.Value, &m); err != nil || m.Address == "" {
+		p.logger.Warn("invalid etcd member", tag.Key(string(kv.Key)), tag.Error(err))
+		return membership.HostInfo{}, false
+	}
+	return membership.NewDetailedHostInfo(m.Address, "", m.Ports).WithZone(m.Zone), true
+}
+
+// serviceOf returns the service of the key of a host, which is the segment following the prefix
+func (p *Provider) serviceOf(key string) string {
+	service := strings.TrimPrefix(key, p.config.Prefix+"/")
+	if i := strings.Index(service, "/"); i >= 0 {
+		return service[:i]
+	}
+	return service
+}
+
+// update groups the hosts by service and notifies the subscribers of the changes
+func (p *Provider) update() {
+	p.mu.Lock()
+	members := make(map[string][]membership.HostInfo)
+	for key, host := range p.hosts {
+		if p.evicted && host.GetAddress() == p.selfAddress() {
+			continue
+		}
+		service := p.serviceOf(key)
+		members[service] = append(members[service], host)
+	}
+
+	change := &membership.ChangedEvent{}
+	for service, hosts := range members {
+		sort.Slice(hosts, func(i, j int) bool {
+			return hosts[i].GetAddress() < hosts[j].GetAddress()
+		})
+		added, updated, removed := membership.DiffHosts(p.members[service], hosts)
+		change.HostsAdded = append(change.HostsAdded, added...)
+		change.HostsUpdated = append(change.HostsUpdated, updated...)
+		change.HostsRemoved = append(change.HostsRemoved, removed...)
+	}
+	for service, hosts := range p.members {
+		if _, ok := members[service]; ok {
+			continue
+		}
+		_, _, removed := membership.DiffHosts(hosts, nil)
+		change.HostsRemoved = append(change.HostsRemoved, removed...)
+	}
+	p.members = members
+	p.mu.Unlock()
+
+	if len(change.HostsAdded) > 0 || len(change.HostsUpdated) > 0 || len(change.HostsRemoved) > 0 {
+		p.logger.Info("etcd members changed",
+			tag.Value(change),
+		)
+		p.notify(change)
+	}
+}
+
+func (p *Provider) notify(change *membership.ChangedEvent) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for name, ch := range p.subscribers {
+		select {
+		case ch <- change:
+		default:
+			p.logger.Error("Failed to send listener notification, channel full", tag.Subscriber(name))
+		}
+	}
+}
+
+// call sends a request to etcd and decodes its response
+func (p *Provider) call(ctx context.Context, path string, request, response interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, p.config.RequestTimeout)
+	defer cancel()
+
+	resp, err := p.post(ctx, path, request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+// post sends the request to the endpoints in turn, starting from the last one which answered, until one of
+// them answers it successfully
+func (p *Provider) post(ctx context.Context, path string, request interface{}) (*http.Response, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for range p.config.Endpoints {
+		current := atomic.LoadInt32(&p.endpoint)
+		endpoint := p.config.Endpoints[int(current)%len(p.config.Endpoints)]
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := p.client.Do(req)
+		if err == nil && resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		if err == nil {
+			msg, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			err = fmt.Errorf("%v%v: %v %s", endpoint, path, resp.Status, bytes.TrimSpace(msg))
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+		atomic.CompareAndSwapInt32(&p.endpoint, current, (current+1)%int32(len(p.config.Endpoints)))
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,389 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package etcdprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/service"
+)
+
+type (
+	// fakeEtcd serves the subset of the etcd gRPC gateway used by the provider
+	fakeEtcd struct {
+		sync.Mutex
+		revision  int64
+		nextLease int64
+		kvs       map[string]fakeKeyValue
+		leases    map[int64]bool
+		watchers  map[chan []fakeEvent]struct{}
+	}
+
+	fakeKeyValue struct {
+		value []byte
+		lease int64
+	}
+
+	fakeEvent struct {
+		Type string   `json:"type,omitempty"`
+		Kv   keyValue `json:"kv"`
+	}
+)
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{
+		kvs:      map[string]fakeKeyValue{},
+		leases:   map[int64]bool{},
+		watchers: map[chan []fakeEvent]struct{}{},
+	}
+}
+
+func (s *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/v3/lease/grant":
+		s.Lock()
+		s.nextLease++
+		s.leases[s.nextLease] = true
+		writeJSON(w, leaseGrantResponse{ID: s.nextLease, TTL: 10})
+		s.Unlock()
+	case "/v3/lease/keepalive":
+		var req leaseRequest
+		readJSON(r, &req)
+		var resp leaseKeepAliveResponse
+		s.Lock()
+		if s.leases[req.ID] {
+			resp.Result.TTL = 10
+		}
+		s.Unlock()
+		writeJSON(w, resp)
+	case "/v3/lease/revoke":
+		var req leaseRequest
+		readJSON(r, &req)
+		s.expire(req.ID)
+		writeJSON(w, struct{}{})
+	case "/v3/kv/put":
+		var req putRequest
+		readJSON(r, &req)
+		s.Lock()
+		if !s.leases[req.Lease] {
+			s.Unlock()
+			http.Error(w, `{"error":"etcdserver: requested lease not found"}`, http.StatusNotFound)
+			return
+		}
+		s.kvs[string(req.Key)] = fakeKeyValue{value: req.Value, lease: req.Lease}
+		s.publish(fakeEvent{Kv: keyValue{Key: req.Key, Value: req.Value}})
+		s.Unlock()
+		writeJSON(w, struct{}{})
+	case "/v3/kv/range":
+		s.Lock()
+		resp := rangeResponse{Header: responseHeader{Revision: s.revision}}
+		for key, kv := range s.kvs {
+			resp.Kvs = append(resp.Kvs, keyValue{Key: []byte(key), Value: kv.value})
+		}
+		s.Unlock()
+		writeJSON(w, resp)
+	case "/v3/watch":
+		s.watch(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeEtcd) watch(w http.ResponseWriter, r *http.Request) {
+	var req watchRequest
+	readJSON(r, &req)
+
+	ch := make(chan []fakeEvent, 100)
+	s.Lock()
+	s.watchers[ch] = struct{}{}
+	revision := s.revision
+	s.Unlock()
+	defer func() {
+		s.Lock()
+		delete(s.watchers, ch)
+		s.Unlock()
+	}()
+
+	if req.CreateRequest.StartRevision <= revision {
+		fmt.Fprintf(w, `{"result":{"canceled":true,"compact_revision":"%v"}}`, revision)
+		return
+	}
+	fmt.Fprint(w, `{"result":{"created":true}}`)
+	w.(http.Flusher).Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case events, ok := <-ch:
+			if !ok {
+				return
+			}
+			s.Lock()
+			revision := s.revision
+			s.Unlock()
+			writeJSON(w, map[string]interface{}{
+				"result": map[string]interface{}{
+					"header": responseHeader{Revision: revision},
+					"events": events,
+				},
+			})
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+// expire deletes the lease and its keys
+func (s *fakeEtcd) expire(leaseID int64) {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.leases, leaseID)
+	for key, kv := range s.kvs {
+		if kv.lease == leaseID {
+			delete(s.kvs, key)
+			s.publish(fakeEvent{Type: eventTypeDelete, Kv: keyValue{Key: []byte(key)}})
+		}
+	}
+}
+
+// breakWatches ends the watches, as if the revision they watch from was compacted
+func (s *fakeEtcd) breakWatches() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.revision += 10
+	for ch := range s.watchers {
+		close(ch)
+		delete(s.watchers, ch)
+	}
+}
+
+func (s *fakeEtcd) publish(event fakeEvent) {
+	s.revision++
+	for ch := range s.watchers {
+		ch <- []fakeEvent{event}
+	}
+}
+
+func (s *fakeEtcd) leaseOf(key string) int64 {
+	s.Lock()
+	defer s.Unlock()
+	return s.kvs[key].lease
+}
+
+func readJSON(r *http.Request, v interface{}) {
+	_ = json.NewDecoder(r.Body).Decode(v)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestProvider(t *testing.T, server *httptest.Server, name string, address string) *Provider {
+	config := &Config{
+		Endpoints: []string{"http://127.0.0.1:1", server.URL},
+		Address:   address,
+		LeaseTTL:  time.Hour,
+	}
+	require.NoError(t, config.validate())
+
+	provider := NewEtcdProvider(
+		name,
+		config,
+		server.Client(),
+		membership.PortMap{membership.PortTchannel: 7933, membership.PortGRPC: 7833},
+		"zone-"+address,
+		loggerimpl.NewNopLogger(),
+	)
+	t.Cleanup(provider.Stop)
+	return provider
+}
+
+func awaitChange(t *testing.T, ch chan *membership.ChangedEvent) *membership.ChangedEvent {
+	select {
+	case change := <-ch:
+		return change
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no membership change")
+		return nil
+	}
+}
+
+func TestProvider(t *testing.T) {
+	etcd := newFakeEtcd()
+	server := httptest.NewServer(etcd)
+	t.Cleanup(server.Close)
+
+	history := newTestProvider(t, server, service.History, "10.0.0.1")
+	changes := make(chan *membership.ChangedEvent, 10)
+	require.NoError(t, history.Subscribe("test", changes))
+	assert.Error(t, history.Subscribe("test", changes))
+
+	history.Start()
+	assert.Equal(t, []string{"10.0.0.1:7933"}, awaitChange(t, changes).HostsAdded)
+
+	self, err := history.WhoAmI()
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:7933", self.GetAddress())
+	grpcAddress, err := self.GetNamedAddress(membership.PortGRPC)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:7833", grpcAddress)
+	assert.Equal(t, "zone-10.0.0.1", self.Zone())
+
+	matching := newTestProvider(t, server, service.Matching, "10.0.0.2")
+	matching.Start()
+	assert.Equal(t, []string{"10.0.0.2:7933"}, awaitChange(t, changes).HostsAdded)
+
+	members, err := history.GetMembers(service.Matching)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "10.0.0.2:7933", members[0].GetAddress())
+	grpcAddress, err = members[0].GetNamedAddress(membership.PortGRPC)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.2:7833", grpcAddress)
+	assert.Equal(t, "zone-10.0.0.2", members[0].Zone())
+
+	members, err = matching.GetMembers(service.History)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "10.0.0.1:7933", members[0].GetAddress())
+
+	matching.Stop()
+	assert.Equal(t, []string{"10.0.0.2:7933"}, awaitChange(t, changes).HostsRemoved)
+	members, err = history.GetMembers(service.Matching)
+	require.NoError(t, err)
+	assert.Empty(t, members)
+}
+
+func TestProvider_LeaseExpired(t *testing.T) {
+	etcd := newFakeEtcd()
+	server := httptest.NewServer(etcd)
+	t.Cleanup(server.Close)
+
+	provider := newTestProvider(t, server, service.History, "10.0.0.1")
+	changes := make(chan *membership.ChangedEvent, 10)
+	require.NoError(t, provider.Subscribe("test", changes))
+	provider.Start()
+	awaitChange(t, changes)
+
+	leaseID := etcd.leaseOf(provider.selfKey())
+	require.NotZero(t, leaseID)
+	provider.keepAlive()
+	assert.Equal(t, leaseID, etcd.leaseOf(provider.selfKey()), "lease is kept alive")
+
+	etcd.expire(leaseID)
+	assert.Equal(t, []string{"10.0.0.1:7933"}, awaitChange(t, changes).HostsRemoved)
+
+	provider.keepAlive()
+	assert.Equal(t, []string{"10.0.0.1:7933"}, awaitChange(t, changes).HostsAdded)
+	assert.NotEqual(t, leaseID, etcd.leaseOf(provider.selfKey()), "host is registered with a new lease")
+}
+
+func TestProvider_SelfEvict(t *testing.T) {
+	etcd := newFakeEtcd()
+	server := httptest.NewServer(etcd)
+	t.Cleanup(server.Close)
+
+	evicted := newTestProvider(t, server, service.History, "10.0.0.1")
+	evicted.Start()
+	other := newTestProvider(t, server, service.History, "10.0.0.2")
+	changes := make(chan *membership.ChangedEvent, 10)
+	require.NoError(t, other.Subscribe("test", changes))
+	other.Start()
+	awaitChange(t, changes)
+
+	members, err := other.GetMembers(service.History)
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	require.NoError(t, evicted.SelfEvict())
+	assert.Equal(t, []string{"10.0.0.1:7933"}, awaitChange(t, changes).HostsRemoved)
+
+	for _, provider := range []*Provider{evicted, other} {
+		members, err := provider.GetMembers(service.History)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		assert.Equal(t, "10.0.0.2:7933", members[0].GetAddress())
+	}
+
+	evicted.keepAlive()
+	assert.Zero(t, etcd.leaseOf(evicted.selfKey()), "evicted host isn't registered again")
+}
+
+func TestProvider_WatchBroken(t *testing.T) {
+	etcd := newFakeEtcd()
+	server := httptest.NewServer(etcd)
+	t.Cleanup(server.Close)
+
+	provider := newTestProvider(t, server, service.History, "10.0.0.1")
+	changes := make(chan *membership.ChangedEvent, 10)
+	require.NoError(t, provider.Subscribe("test", changes))
+	provider.Start()
+	awaitChange(t, changes)
+
+	etcd.breakWatches()
+	other := newTestProvider(t, server, service.History, "10.0.0.2")
+	other.Start()
+
+	// the key put while the watch was broken is listed when watching again
+	assert.Equal(t, []string{"10.0.0.2:7933"}, awaitChange(t, changes).HostsAdded)
+}
+
+func TestProvider_EtcdUnavailable(t *testing.T) {
+	etcd := newFakeEtcd()
+	server := httptest.NewServer(etcd)
+	server.Close()
+
+	provider := newTestProvider(t, server, service.History, "10.0.0.1")
+	provider.Start()
+
+	members, err := provider.GetMembers(service.History)
+	require.NoError(t, err)
+	assert.Empty(t, members)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	config := &Config{Endpoints: []string{"http://etcd:2379/"}, Address: "10.0.0.1"}
+	require.NoError(t, config.validate())
+	assert.Equal(t, []string{"http://etcd:2379"}, config.Endpoints)
+	assert.Equal(t, defaultPrefix, config.Prefix)
+	assert.Equal(t, defaultLeaseTTL, config.LeaseTTL)
+	assert.Equal(t, defaultRequestTimeout, config.RequestTimeout)
+
+	assert.Error(t, (&Config{Address: "10.0.0.1"}).validate())
+	assert.Error(t, (&Config{Endpoints: []string{"etcd:2379"}, Address: "10.0.0.1"}).validate())
+	assert.Error(t, (&Config{Endpoints: []string{"http://etcd:2379"}, Address: "etcd"}).validate())
+	assert.Error(t, (&Config{Endpoints: []string{"http://etcd:2379"}, Address: "10.0.0.1", LeaseTTL: time.Second}).validate())
+	assert.Error(t, (&Config{Endpoints: []string{"http://etcd:2379"}, Address: "10.0.0.1", CertFile: "cert.pem"}).validate())
+}
@@ -0,0 +1,110 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package k8sprovider
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRefreshInterval = 5 * time.Second
+	defaultRequestTimeout  = 10 * time.Second
+
+	serviceAccountDir    = "/var/run/secrets/kubernetes.io/serviceaccount"
+	podIPEnv             = "POD_IP"
+	apiServerHostEnv     = "KUBERNETES_SERVICE_HOST"
+	apiServerPortEnv     = "KUBERNETES_SERVICE_PORT"
+	defaultAPIServerHost = "kubernetes.default.svc"
+)
+
+// Config contains the config of the membership resolved from the Kubernetes API. The peers of a service are
+// the ready endpoints of the Kubernetes service selecting its pods, whose ports must be named tchannel and grpc.
+// The default values are the ones of a pod running with a service account allowed to list the endpointslices.
+type Config struct {
+	// Services maps the cadence services, e.g. history, to the names of their Kubernetes services
+	Services map[string]string `yaml:"services"`
+	// Namespace of the Kubernetes services, the namespace of the pod by default
+	Namespace string `yaml:"namespace"`
+	// PodIP is the IP of this pod in the endpoints, the POD_IP environment variable by default
+	PodIP string `yaml:"podIP"`
+	// APIServer is the URL of the Kubernetes API server, the in cluster URL by default
+	APIServer string `yaml:"apiServer"`
+	// TokenFile is the bearer token of the requests to the API server, the service account token by default.
+	// It is read before every request, so that the rotated tokens are used.
+	TokenFile string `yaml:"tokenFile"`
+	// CAFile is the CA of the API server, the service account CA by default
+	CAFile string `yaml:"caFile"`
+	// RefreshInterval is how often the endpoints are listed, 5s by default
+	RefreshInterval time.Duration `yaml:"refreshInterval"`
+}
+
+func (c *Config) validate() error {
+	if len(c.Services) == 0 {
+		return fmt.Errorf("kubernetes membership config missing `services` param")
+	}
+	for service, k8sService := range c.Services {
+		if k8sService == "" {
+			return fmt.Errorf("kubernetes membership config missing the Kubernetes service of %v", service)
+		}
+	}
+
+	if c.Namespace == "" {
+		namespace, err := ioutil.ReadFile(serviceAccountDir + "/namespace")
+		if err != nil {
+			return fmt.Errorf("kubernetes membership config missing `namespace` param: %w", err)
+		}
+		c.Namespace = strings.TrimSpace(string(namespace))
+	}
+	if c.PodIP == "" {
+		c.PodIP = os.Getenv(podIPEnv)
+	}
+	if net.ParseIP(c.PodIP) == nil {
+		return fmt.Errorf("kubernetes membership config has invalid pod IP %q, set `podIP` or the %v environment variable", c.PodIP, podIPEnv)
+	}
+	if c.APIServer == "" {
+		host := os.Getenv(apiServerHostEnv)
+		if host == "" {
+			host = defaultAPIServerHost
+		}
+		port := os.Getenv(apiServerPortEnv)
+		if port == "" {
+			port = "443"
+		}
+		c.APIServer = "https://" + net.JoinHostPort(host, port)
+	}
+	if c.TokenFile == "" {
+		c.TokenFile = serviceAccountDir + "/token"
+	}
+	if c.CAFile == "" && strings.HasPrefix(c.APIServer, "https://") {
+		c.CAFile = serviceAccountDir + "/ca.crt"
+	}
+	if c.RefreshInterval <= 0 {
+		c.RefreshInterval = defaultRefreshInterval
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package k8sprovider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/service"
+)
+
+const serviceNameLabel = "kubernetes.io/service-name"
+
+type (
+	// Provider resolves the members of the cadence services from the endpoints of their Kubernetes services
+	Provider struct {
+		status     int32
+		service    string
+		config     *Config
+		client     *http.Client
+		portmap    membership.PortMap
+		logger     log.Logger
+		shutdownCh chan struct{}
+		shutdownWG sync.WaitGroup
+
+		mu          sync.RWMutex
+		members     map[string][]membership.HostInfo
+		evicted     bool
+		subscribers map[string]chan<- *membership.ChangedEvent
+	}
+
+	endpointSliceList struct {
+		Items []endpointSlice `json:"items"`
+	}
+
+	endpointSlice struct {
+		Ports     []endpointPort `json:"ports"`
+		Endpoints []endpoint     `json:"endpoints"`
+	}
+
+	endpointPort struct {
+		Name *string `json:"name"`
+		Port *int32  `json:"port"`
+	}
+
+	endpoint struct {
+		Addresses  []string           `json:"addresses"`
+		Conditions endpointConditions `json:"conditions"`
+		Zone       *string            `json:"zone"`
+	}
+
+	endpointConditions struct {
+		Ready *bool `json:"ready"`
+	}
+)
+
+var _ membership.PeerProvider = (*Provider)(nil)
+
+// New creates a peer provider listing the endpointslices of the Kubernetes services
+func New(
+	service string,
+	config *Config,
+	portMap membership.PortMap,
+	logger log.Logger,
+) (*Provider, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.CAFile != "" {
+		ca, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading Kubernetes API server CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid Kubernetes API server CA %v", config.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return NewKubernetesProvider(service, config, &http.Client{Transport: transport, Timeout: defaultRequestTimeout}, portMap, logger), nil
+}
+
+// NewKubernetesProvider sets up the Kubernetes based peer provider with a validated config
+func NewKubernetesProvider(
+	service string,
+	config *Config,
+	client *http.Client,
+	portMap membership.PortMap,
+	logger log.Logger,
+) *Provider {
+	return &Provider{
+		status:      common.DaemonStatusInitialized,
+		service:     service,
+		config:      config,
+		client:      client,
+		portmap:     portMap,
+		logger:      logger,
+		shutdownCh:  make(chan struct{}),
+		members:     map[string][]membership.HostInfo{},
+		subscribers: map[string]chan<- *membership.ChangedEvent{},
+	}
+}
+
+// Start lists the endpoints of the services, and keeps refreshing them in background
+func (p *Provider) Start() {
+	if !atomic.CompareAndSwapInt32(
+		&p.status,
+		common.DaemonStatusInitialized,
+		common.DaemonStatusStarted,
+	) {
+		return
+	}
+
+	p.refresh()
+
+	p.shutdownWG.Add(1)
+	go p.refreshLoop()
+}
+
+// Stop stops refreshing the endpoints
+func (p *Provider) Stop() {
+	if !atomic.CompareAndSwapInt32(
+		&p.status,
+		common.DaemonStatusStarted,
+		common.DaemonStatusStopped,
+	) {
+		return
+	}
+
+	close(p.shutdownCh)
+	if success := common.AwaitWaitGroup(&p.shutdownWG, time.Minute); !success {
+		p.logger.Warn("kubernetes peer provider timed out on shutdown.")
+	}
+}
+
+// SelfEvict removes this pod from the members seen by this host. The other hosts stop seeing it once it isn't
+// ready anymore, which Kubernetes does as soon as the pod is terminating.
+func (p *Provider) SelfEvict() error {
+	p.mu.Lock()
+	p.evicted = true
+	p.mu.Unlock()
+
+	p.notify(&membership.ChangedEvent{HostsRemoved: []string{p.selfAddress()}})
+	return nil
+}
+
+// GetMembers returns the ready endpoints of the service, as of the last successful refresh
+func (p *Provider) GetMembers(service string) ([]membership.HostInfo, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	members := p.members[service]
+	res := make([]membership.HostInfo, 0, len(members))
+	for _, member := range members {
+		if p.evicted && member.GetAddress() == p.selfAddress() {
+			continue
+		}
+		res = append(res, member)
+	}
+	return res, nil
+}
+
+// WhoAmI returns the address of this pod, and its zone once its endpoint is listed
+func (p *Provider) WhoAmI() (membership.HostInfo, error) {
+	return membership.NewDetailedHostInfo(p.selfAddress(), "", p.portmap).WithZone(p.selfZone()), nil
+}
+
+// Subscribe allows to be subscribed for ring changes
+func (p *Provider) Subscribe(name string, notifyChannel chan<- *membership.ChangedEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.subscribers[name]
+	if ok {
+		return fmt.Errorf("%q already subscribed to kubernetes provider", name)
+	}
+
+	p.subscribers[name] = notifyChannel
+	return nil
+}
+
+func (p *Provider) selfAddress() string {
+	return net.JoinHostPort(p.config.PodIP, strconv.Itoa(int(p.portmap[membership.PortTchannel])))
+}
+
+// selfZone returns the zone of the endpoint of this pod, which Kubernetes sets from the topology labels of its node
+func (p *Provider) selfZone() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, member := range p.members[p.service] {
+		if member.GetAddress() == p.selfAddress() {
+			return member.Zone()
+		}
+	}
+	return ""
+}
+
+func (p *Provider) refreshLoop() {
+	defer p.shutdownWG.Done()
+
+	ticker := time.NewTicker(p.config.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.shutdownCh:
+			return
+		case <-ticker.C:
+			p.refresh()
+		}
+	}
+}
+
+// refresh lists the endpoints of every service and notifies the subscribers of the changes. The members of a
+// service are kept as is if its endpoints can't be listed, so that the API server being unavailable doesn't
+// empty the rings.
+func (p *Provider) refresh() {
+	change := &membership.ChangedEvent{}
+	for shortName, k8sService := range p.config.Services {
+		name := service.FullName(shortName)
+		members, err := p.listMembers(k8sService)
+		if err != nil {
+			p.logger.Warn("failed to list kubernetes endpoints", tag.Service(name), tag.Error(err))
+			continue
+		}
+
+		p.mu.Lock()
+		added, updated, removed := membership.DiffHosts(p.members[name], members)
+		p.members[name] = members
+		p.mu.Unlock()
+
+		change.HostsAdded = append(change.HostsAdded, added...)
+		change.HostsUpdated = append(change.HostsUpdated, updated...)
+		change.HostsRemoved = append(change.HostsRemoved, removed...)
+	}
+
+	if len(change.HostsAdded) > 0 || len(change.HostsUpdated) > 0 || len(change.HostsRemoved) > 0 {
+		p.logger.Info("kubernetes endpoints changed",
+			tag.Value(change),
+		)
+		p.notify(change)
+	}
+}
+
+func (p *Provider) notify(change *membership.ChangedEvent) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for name, ch := range p.subscribers {
+		select {
+		case ch <- change:
+		default:
+			p.logger.Error("Failed to send listener notification, channel full", tag.Subscriber(name))
+		}
+	}
+}
+
+// listMembers returns the ready endpoints of the Kubernetes service, sorted by address
+func (p *Provider) listMembers(k8sService string) ([]membership.HostInfo, error) {
+	slices, err := p.listEndpointSlices(k8sService)
+	if err != nil {
+		return nil, err
+	}
+
+	byAddress := make(map[string]membership.HostInfo)
+	for _, slice := range slices.Items {
+		portMap := make(membership.PortMap)
+		for _, port := range slice.Ports {
+			if port.Name != nil && port.Port != nil {
+				portMap[*port.Name] = uint16(*port.Port)
+			}
+		}
+		tchannelPort, ok := portMap[membership.PortTchannel]
+		if !ok {
+			p.logger.Warn("kubernetes endpoints have no tchannel port", tag.Service(k8sService))
+			continue
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			// a nil ready condition is unknown, which must be interpreted as ready
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			var zone string
+			if endpoint.Zone != nil {
+				zone = *endpoint.Zone
+			}
+			for _, ip := range endpoint.Addresses {
+				address := net.JoinHostPort(ip, strconv.Itoa(int(tchannelPort)))
+				byAddress[address] = membership.NewDetailedHostInfo(address, "", portMap).WithZone(zone)
+			}
+		}
+	}
+
+	members := make([]membership.HostInfo, 0, len(byAddress))
+	for _, member := range byAddress {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].GetAddress() < members[j].GetAddress()
+	})
+	return members, nil
+}
+
+func (p *Provider) listEndpointSlices(k8sService string) (*endpointSliceList, error) {
+	u := fmt.Sprintf("%v/apis/discovery.k8s.io/v1/namespaces/%v/endpointslices?labelSelector=%v",
+		strings.TrimSuffix(p.config.APIServer, "/"),
+		url.PathEscape(p.config.Namespace),
+		url.QueryEscape(serviceNameLabel+"="+k8sService),
+	)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	token, err := ioutil.ReadFile(p.config.TokenFile)
+	switch {
+	case err == nil:
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("reading Kubernetes token: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("listing endpointslices of %v: %v %s", k8sService, resp.Status, body)
+	}
+
+	var slices endpointSliceList
+	if err := json.NewDecoder(resp.Body).Decode(&slices); err != nil {
+		return nil, fmt.Errorf("decoding endpointslices of %v: %w", k8sService, err)
+	}
+	return &slices, nil
+}
@@ -0,0 +1,180 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package k8sprovider
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/service"
+)
+
+type fakeAPIServer struct {
+	sync.Mutex
+	slices map[string]string
+	fail   bool
+}
+
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.fail || r.URL.Path != "/apis/discovery.k8s.io/v1/namespaces/cadence/endpointslices" ||
+		r.Header.Get("Authorization") != "Bearer test-token" {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, s.slices[r.URL.Query().Get("labelSelector")])
+}
+
+func (s *fakeAPIServer) setSlices(k8sService string, slices string) {
+	s.Lock()
+	defer s.Unlock()
+	s.slices[serviceNameLabel+"="+k8sService] = slices
+}
+
+func newTestProvider(t *testing.T, apiServer *fakeAPIServer) *Provider {
+	server := httptest.NewServer(apiServer)
+	t.Cleanup(server.Close)
+
+	tokenFile := t.TempDir() + "/token"
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("test-token\n"), 0600))
+
+	config := &Config{
+		Services:        map[string]string{"history": "cadence-history-headless"},
+		Namespace:       "cadence",
+		PodIP:           "10.0.0.1",
+		APIServer:       server.URL,
+		TokenFile:       tokenFile,
+		RefreshInterval: time.Hour,
+	}
+	require.NoError(t, config.validate())
+	return NewKubernetesProvider(
+		service.History,
+		config,
+		server.Client(),
+		membership.PortMap{membership.PortTchannel: 7934, membership.PortGRPC: 7834},
+		loggerimpl.NewNopLogger(),
+	)
+}
+
+func TestProvider(t *testing.T) {
+	apiServer := &fakeAPIServer{slices: map[string]string{}}
+	apiServer.setSlices("cadence-history-headless", `{"items": [
+		{
+			"ports": [{"name": "tchannel", "port": 7934}, {"name": "grpc", "port": 7834}],
+			"endpoints": [
+				{"addresses": ["10.0.0.1"], "conditions": {"ready": true}, "zone": "us-east-1a"},
+				{"addresses": ["10.0.0.2"], "conditions": {}},
+				{"addresses": ["10.0.0.3"], "conditions": {"ready": false, "terminating": true}}
+			]
+		},
+		{
+			"ports": [{"name": "tchannel", "port": 7934}, {"name": "grpc", "port": 7834}],
+			"endpoints": [{"addresses": ["10.0.0.2"], "conditions": {"ready": true}}]
+		}
+	]}`)
+	provider := newTestProvider(t, apiServer)
+	changes := make(chan *membership.ChangedEvent, 10)
+	require.NoError(t, provider.Subscribe("test", changes))
+	require.Error(t, provider.Subscribe("test", changes))
+
+	provider.Start()
+	defer provider.Stop()
+
+	assertMembers := func(expected ...string) {
+		members, err := provider.GetMembers(service.History)
+		require.NoError(t, err)
+		var addresses []string
+		for _, member := range members {
+			addresses = append(addresses, member.GetAddress())
+			grpcAddress, err := member.GetNamedAddress(membership.PortGRPC)
+			require.NoError(t, err)
+			assert.Equal(t, member.GetAddress()[:len(member.GetAddress())-4]+"7834", grpcAddress)
+		}
+		assert.Equal(t, expected, addresses)
+	}
+	assertMembers("10.0.0.1:7934", "10.0.0.2:7934")
+	assert.Equal(t, &membership.ChangedEvent{HostsAdded: []string{"10.0.0.1:7934", "10.0.0.2:7934"}}, <-changes)
+
+	self, err := provider.WhoAmI()
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:7934", self.GetAddress())
+	assert.Equal(t, "us-east-1a", self.Zone())
+
+	// a pod replaced by another one
+	apiServer.setSlices("cadence-history-headless", `{"items": [{
+		"ports": [{"name": "tchannel", "port": 7934}, {"name": "grpc", "port": 7834}],
+		"endpoints": [
+			{"addresses": ["10.0.0.1"], "conditions": {"ready": true}, "zone": "us-east-1a"},
+			{"addresses": ["10.0.0.4"], "conditions": {"ready": true}}
+		]
+	}]}`)
+	provider.refresh()
+	assertMembers("10.0.0.1:7934", "10.0.0.4:7934")
+	assert.Equal(t, &membership.ChangedEvent{HostsAdded: []string{"10.0.0.4:7934"}, HostsRemoved: []string{"10.0.0.2:7934"}}, <-changes)
+
+	// the members are kept while the API server is unavailable
+	apiServer.Lock()
+	apiServer.fail = true
+	apiServer.Unlock()
+	provider.refresh()
+	assertMembers("10.0.0.1:7934", "10.0.0.4:7934")
+	assert.Empty(t, changes)
+
+	require.NoError(t, provider.SelfEvict())
+	assertMembers("10.0.0.4:7934")
+	assert.Equal(t, &membership.ChangedEvent{HostsRemoved: []string{"10.0.0.1:7934"}}, <-changes)
+
+	members, err := provider.GetMembers(service.Matching)
+	require.NoError(t, err)
+	assert.Empty(t, members)
+}
+
+func TestConfigValidation(t *testing.T) {
+	config := &Config{Namespace: "cadence", PodIP: "10.0.0.1"}
+	assert.EqualError(t, config.validate(), "kubernetes membership config missing `services` param")
+
+	config.Services = map[string]string{"history": ""}
+	assert.EqualError(t, config.validate(), "kubernetes membership config missing the Kubernetes service of history")
+
+	config.Services = map[string]string{"history": "cadence-history"}
+	config.PodIP = "invalid"
+	assert.Error(t, config.validate())
+
+	config.PodIP = "10.0.0.1"
+	require.NoError(t, config.validate())
+	assert.Equal(t, defaultRefreshInterval, config.RefreshInterval)
+	assert.Equal(t, serviceAccountDir+"/token", config.TokenFile)
+	assert.Equal(t, serviceAccountDir+"/ca.crt", config.CAFile)
+}
@@ -0,0 +1,200 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memprovider
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/membership"
+)
+
+type (
+	// Registry holds the hosts of the services running in this process, it is shared by their providers
+	Registry struct {
+		mu        sync.RWMutex
+		members   map[string][]membership.HostInfo
+		providers []*Provider
+	}
+
+	// Provider resolves the members of the services from the registry of the process, it is meant for
+	// running all the services in a single process where there are no other hosts to discover
+	Provider struct {
+		status   int32
+		service  string
+		self     membership.HostInfo
+		registry *Registry
+
+		mu          sync.RWMutex
+		subscribers map[string]chan<- *membership.ChangedEvent
+	}
+)
+
+var _ membership.PeerProvider = (*Provider)(nil)
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		members: map[string][]membership.HostInfo{},
+	}
+}
+
+// NewProvider creates a peer provider for the host of the service, the host joins the registry once the
+// provider is started
+func (r *Registry) NewProvider(service string, self membership.HostInfo) *Provider {
+	p := &Provider{
+		status:      common.DaemonStatusInitialized,
+		service:     service,
+		self:        self,
+		registry:    r,
+		subscribers: map[string]chan<- *membership.ChangedEvent{},
+	}
+
+	r.mu.Lock()
+	r.providers = append(r.providers, p)
+	r.mu.Unlock()
+	return p
+}
+
+// Start adds the host to the members of its service
+func (p *Provider) Start() {
+	if !atomic.CompareAndSwapInt32(
+		&p.status,
+		common.DaemonStatusInitialized,
+		common.DaemonStatusStarted,
+	) {
+		return
+	}
+
+	p.registry.add(p.service, p.self)
+}
+
+// Stop removes the host from the members of its service
+func (p *Provider) Stop() {
+	if !atomic.CompareAndSwapInt32(
+		&p.status,
+		common.DaemonStatusStarted,
+		common.DaemonStatusStopped,
+	) {
+		return
+	}
+
+	p.registry.remove(p.service, p.self)
+}
+
+// SelfEvict removes the host from the members of its service
+func (p *Provider) SelfEvict() error {
+	p.registry.remove(p.service, p.self)
+	return nil
+}
+
+// GetMembers returns the hosts of the service which are currently registered
+func (p *Provider) GetMembers(service string) ([]membership.HostInfo, error) {
+	return p.registry.getMembers(service), nil
+}
+
+// WhoAmI returns the host of this provider
+func (p *Provider) WhoAmI() (membership.HostInfo, error) {
+	return p.self, nil
+}
+
+// Subscribe allows to be subscribed for ring changes
+func (p *Provider) Subscribe(name string, notifyChannel chan<- *membership.ChangedEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.subscribers[name]
+	if ok {
+		return fmt.Errorf("%q already subscribed to in-memory provider", name)
+	}
+
+	p.subscribers[name] = notifyChannel
+	return nil
+}
+
+func (p *Provider) notify(change *membership.ChangedEvent) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, ch := range p.subscribers {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
+
+func (r *Registry) add(service string, host membership.HostInfo) {
+	r.mu.Lock()
+	for _, member := range r.members[service] {
+		if member.GetAddress() == host.GetAddress() {
+			r.mu.Unlock()
+			return
+		}
+	}
+	r.members[service] = append(r.members[service], host)
+	providers := r.providers
+	r.mu.Unlock()
+
+	change := &membership.ChangedEvent{HostsAdded: []string{host.GetAddress()}}
+	for _, p := range providers {
+		p.notify(change)
+	}
+}
+
+func (r *Registry) remove(service string, host membership.HostInfo) {
+	r.mu.Lock()
+	members := r.members[service]
+	found := false
+	remaining := make([]membership.HostInfo, 0, len(members))
+	for _, member := range members {
+		if member.GetAddress() == host.GetAddress() {
+			found = true
+			continue
+		}
+		remaining = append(remaining, member)
+	}
+	r.members[service] = remaining
+	providers := r.providers
+	r.mu.Unlock()
+
+	if !found {
+		return
+	}
+	change := &membership.ChangedEvent{HostsRemoved: []string{host.GetAddress()}}
+	for _, p := range providers {
+		p.notify(change)
+	}
+}
+
+func (r *Registry) getMembers(service string) []membership.HostInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := r.members[service]
+	res := make([]membership.HostInfo, len(members))
+	copy(res, members)
+	return res
+}
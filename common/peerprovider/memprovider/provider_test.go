@@ -0,0 +1,72 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package memprovider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/service"
+)
+
+func TestProvider(t *testing.T) {
+	registry := NewRegistry()
+	frontendHost := membership.NewDetailedHostInfo("127.0.0.1:7933", "127.0.0.1:7933", membership.PortMap{membership.PortTchannel: 7933})
+	historyHost := membership.NewDetailedHostInfo("127.0.0.1:7934", "127.0.0.1:7934", membership.PortMap{membership.PortTchannel: 7934})
+	frontend := registry.NewProvider(service.Frontend, frontendHost)
+	history := registry.NewProvider(service.History, historyHost)
+
+	events := make(chan *membership.ChangedEvent, 10)
+	require.NoError(t, frontend.Subscribe("test", events))
+	require.Error(t, frontend.Subscribe("test", events))
+
+	frontend.Start()
+	history.Start()
+	defer frontend.Stop()
+
+	self, err := frontend.WhoAmI()
+	require.NoError(t, err)
+	require.Equal(t, frontendHost, self)
+
+	members, err := frontend.GetMembers(service.History)
+	require.NoError(t, err)
+	require.Equal(t, []membership.HostInfo{historyHost}, members)
+	members, err = history.GetMembers(service.Frontend)
+	require.NoError(t, err)
+	require.Equal(t, []membership.HostInfo{frontendHost}, members)
+
+	require.Equal(t, []string{"127.0.0.1:7933"}, (<-events).HostsAdded)
+	require.Equal(t, []string{"127.0.0.1:7934"}, (<-events).HostsAdded)
+
+	require.NoError(t, history.SelfEvict())
+	require.Equal(t, []string{"127.0.0.1:7934"}, (<-events).HostsRemoved)
+	members, err = frontend.GetMembers(service.History)
+	require.NoError(t, err)
+	require.Empty(t, members)
+
+	// stopping an evicted host doesn't notify again
+	history.Stop()
+	require.Empty(t, events)
+}
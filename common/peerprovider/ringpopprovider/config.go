@@ -59,6 +59,7 @@ const (
 
 const (
 	defaultMaxJoinDuration = 10 * time.Second
+	defaultResolveInterval = 30 * time.Second
 )
 
 // Config contains the ringpop config items
@@ -73,6 +74,10 @@ type Config struct {
 	BootstrapFile string `yaml:"bootstrapFile"`
 	// MaxJoinDuration is the max wait time to join the ring
 	MaxJoinDuration time.Duration `yaml:"maxJoinDuration"`
+	// ResolveInterval is how often the bootstrap hosts are resolved again after joining the ring, so that this host
+	// rejoins the ring of the seeds once it doesn't see any of them anymore. It is 30s by default for the dns and
+	// dns-srv modes, and disabled by default for the other modes. A negative interval disables it.
+	ResolveInterval time.Duration `yaml:"resolveInterval"`
 	// Custom discovery provider, cannot be specified through yaml
 	DiscoveryProvider discovery.DiscoverProvider `yaml:"-"`
 }
@@ -86,6 +91,11 @@ func (rpConfig *Config) validate() error {
 		rpConfig.MaxJoinDuration = defaultMaxJoinDuration
 	}
 
+	if rpConfig.ResolveInterval == 0 &&
+		(rpConfig.BootstrapMode == BootstrapModeDNS || rpConfig.BootstrapMode == BootstrapModeDNSSRV) {
+		rpConfig.ResolveInterval = defaultResolveInterval
+	}
+
 	return validateBootstrapMode(rpConfig)
 }
 
@@ -58,6 +58,17 @@ func (s *RingpopSuite) TestHostsMode() {
 	s.Equal(time.Second*30, cfg.MaxJoinDuration)
 	err = cfg.validate()
 	s.Nil(err)
+	s.Zero(cfg.ResolveInterval)
+}
+
+func (s *RingpopSuite) TestDNSModeResolveInterval() {
+	cfg := Config{Name: "test", BootstrapMode: BootstrapModeDNSSRV, BootstrapHosts: []string{"_cadence._tcp.example.com"}}
+	s.Nil(cfg.validate())
+	s.Equal(defaultResolveInterval, cfg.ResolveInterval)
+
+	cfg = Config{Name: "test", BootstrapMode: BootstrapModeDNS, BootstrapHosts: []string{"example.com:7933"}, ResolveInterval: -1}
+	s.Nil(cfg.validate())
+	s.Equal(time.Duration(-1), cfg.ResolveInterval)
 }
 
 func (s *RingpopSuite) TestFileMode() {
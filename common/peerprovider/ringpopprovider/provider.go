@@ -25,9 +25,12 @@ package ringpopprovider
 import (
 	"fmt"
 	"net"
+	"reflect"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"go.uber.org/yarpc/transport/tchannel"
 
@@ -48,12 +51,22 @@ type (
 	Provider struct {
 		status      int32
 		service     string
-		ringpop     *ringpop.Ringpop
 		bootParams  *swim.BootstrapOptions
 		logger      log.Logger
 		portmap     membership.PortMap
+		zone        string
 		mu          sync.RWMutex
 		subscribers map[string]chan<- *membership.ChangedEvent
+
+		// the ringpop instance is replaced when this host rejoins the ring of the seeds
+		ringpopMu sync.RWMutex
+		ringpop   *ringpop.Ringpop
+
+		// newRingpop creates the ringpop instance to rejoin with, rejoining is disabled if nil
+		newRingpop      func() (*ringpop.Ringpop, error)
+		resolveInterval time.Duration
+		shutdownCh      chan struct{}
+		shutdownWG      sync.WaitGroup
 	}
 )
 
@@ -70,6 +83,7 @@ func New(
 	config *Config,
 	channel tchannel.Channel,
 	portMap membership.PortMap,
+	zone string,
 	logger log.Logger,
 ) (*Provider, error) {
 	if err := config.validate(); err != nil {
@@ -86,12 +100,24 @@ func New(
 		DiscoverProvider: discoveryProvider,
 	}
 
-	rp, err := ringpop.New(config.Name, ringpop.Channel(channel.(*tcg.Channel)))
+	newRingpop := func() (*ringpop.Ringpop, error) {
+		rp, err := ringpop.New(config.Name, ringpop.Channel(channel.(*tcg.Channel)))
+		if err != nil {
+			return nil, fmt.Errorf("ringpop instance creation: %w", err)
+		}
+		return rp, nil
+	}
+	rp, err := newRingpop()
 	if err != nil {
-		return nil, fmt.Errorf("ringpop instance creation: %w", err)
+		return nil, err
 	}
 
-	return NewRingpopProvider(service, rp, portMap, bootstrapOpts, logger), nil
+	provider := NewRingpopProvider(service, rp, portMap, zone, bootstrapOpts, logger)
+	if config.ResolveInterval > 0 {
+		provider.newRingpop = newRingpop
+		provider.resolveInterval = config.ResolveInterval
+	}
+	return provider, nil
 }
 
 // NewRingpopProvider sets up ringpop based peer provider
@@ -99,6 +125,7 @@ func NewRingpopProvider(
 	service string,
 	rp *ringpop.Ringpop,
 	portMap membership.PortMap,
+	zone string,
 	bootstrapOpts *swim.BootstrapOptions,
 	logger log.Logger,
 ) *Provider {
@@ -108,8 +135,10 @@ func NewRingpopProvider(
 		bootParams:  bootstrapOpts,
 		logger:      logger,
 		portmap:     portMap,
+		zone:        zone,
 		ringpop:     rp,
 		subscribers: map[string]chan<- *membership.ChangedEvent{},
+		shutdownCh:  make(chan struct{}),
 	}
 }
 
@@ -123,29 +152,148 @@ func (r *Provider) Start() {
 		return
 	}
 
-	_, err := r.ringpop.Bootstrap(r.bootParams)
-	if err != nil {
+	if err := r.bootstrap(r.getRingpop()); err != nil {
 		r.logger.Fatal("unable to bootstrap ringpop", tag.Error(err))
 	}
 
+	if r.newRingpop != nil && r.bootParams.DiscoverProvider != nil {
+		r.shutdownWG.Add(1)
+		go r.resolveLoop()
+	}
+}
+
+// bootstrap joins the ring with the ringpop instance, and labels this host with its service, ports and zone
+func (r *Provider) bootstrap(rp *ringpop.Ringpop) error {
+	_, err := rp.Bootstrap(r.bootParams)
+	if err != nil {
+		return err
+	}
+
 	// Get updates from ringpop ring
-	r.ringpop.AddListener(r)
+	rp.AddListener(r)
 
-	labels, err := r.ringpop.Labels()
+	labels, err := rp.Labels()
 	if err != nil {
-		r.logger.Fatal("unable to get ring pop labels", tag.Error(err))
+		return fmt.Errorf("unable to get ring pop labels: %w", err)
 	}
 
 	// set port labels
 	for name, port := range r.portmap {
 		if err = labels.Set(name, strconv.Itoa(int(port))); err != nil {
-			r.logger.Fatal("unable to set port label", tag.Error(err))
+			return fmt.Errorf("unable to set port label: %w", err)
+		}
+	}
+
+	if r.zone != "" {
+		if err = labels.Set(membership.ZoneLabel, r.zone); err != nil {
+			return fmt.Errorf("unable to set zone label: %w", err)
 		}
 	}
 
 	if err = labels.Set(roleKey, r.service); err != nil {
-		r.logger.Fatal("unable to set ringpop role label", tag.Error(err))
+		return fmt.Errorf("unable to set ringpop role label: %w", err)
+	}
+	return nil
+}
+
+// resolveLoop resolves the bootstrap hosts periodically, and rejoins the ring of the seeds when they change and
+// this host doesn't see any of them anymore, e.g. once all the seeds it joined with have been replaced
+func (r *Provider) resolveLoop() {
+	defer r.shutdownWG.Done()
+
+	ticker := time.NewTicker(r.resolveInterval)
+	defer ticker.Stop()
+
+	var seeds []string
+	for {
+		select {
+		case <-r.shutdownCh:
+			return
+		case <-ticker.C:
+		}
+
+		hosts, err := r.bootParams.DiscoverProvider.Hosts()
+		if err != nil {
+			r.logger.Warn("unable to resolve ringpop bootstrap hosts", tag.Error(err))
+			continue
+		}
+		sort.Strings(hosts)
+		if seeds != nil && reflect.DeepEqual(seeds, hosts) {
+			continue
+		}
+		seeds = hosts
+
+		rejoin, err := r.isPartitionedFrom(hosts)
+		if err != nil {
+			r.logger.Warn("unable to compare ringpop members with bootstrap hosts", tag.Error(err))
+			continue
+		}
+		if !rejoin {
+			continue
+		}
+
+		r.logger.Warn("ringpop members don't include any bootstrap host, rejoining", tag.Value(hosts))
+		if err := r.rejoin(); err != nil {
+			r.logger.Error("unable to rejoin ringpop", tag.Error(err))
+			// the seeds are compared again on the next tick, so that rejoining is retried
+			seeds = nil
+		}
+	}
+}
+
+// isPartitionedFrom returns whether none of the hosts, other than this one, is a reachable member of the ring
+func (r *Provider) isPartitionedFrom(hosts []string) (bool, error) {
+	rp := r.getRingpop()
+	if !rp.Ready() {
+		// a previous attempt to rejoin failed
+		return true, nil
+	}
+	self, err := rp.WhoAmI()
+	if err != nil {
+		return false, err
+	}
+	members, err := rp.GetReachableMembers()
+	if err != nil {
+		return false, err
+	}
+	reachable := make(map[string]struct{}, len(members))
+	for _, member := range members {
+		reachable[member] = struct{}{}
+	}
+
+	others := 0
+	for _, host := range hosts {
+		if host == self {
+			continue
+		}
+		if _, ok := reachable[host]; ok {
+			return false, nil
+		}
+		others++
 	}
+	return others > 0, nil
+}
+
+// rejoin replaces the ringpop instance by a new one bootstrapped with the current bootstrap hosts
+func (r *Provider) rejoin() error {
+	rp, err := r.newRingpop()
+	if err != nil {
+		return err
+	}
+
+	// the members are unknown until the new instance is bootstrapped, meanwhile the rings keep their current members
+	r.ringpopMu.Lock()
+	r.ringpop.Destroy()
+	r.ringpop = rp
+	r.ringpopMu.Unlock()
+
+	return r.bootstrap(rp)
+}
+
+func (r *Provider) getRingpop() *ringpop.Ringpop {
+	r.ringpopMu.RLock()
+	defer r.ringpopMu.RUnlock()
+	return r.ringpop
 }
 
 // HandleEvent handles updates from ringpop
@@ -181,7 +329,7 @@ func (r *Provider) HandleEvent(
 }
 
 func (r *Provider) SelfEvict() error {
-	return r.ringpop.SelfEvict()
+	return r.getRingpop().SelfEvict()
 }
 
 // GetMembers returns all hosts with a specified role value
@@ -232,11 +380,12 @@ func (r *Provider) GetMembers(service string) ([]membership.HostInfo, error) {
 			}
 		}
 
-		res = append(res, membership.NewDetailedHostInfo(member.GetAddress(), member.Identity(), portMap))
+		zone, _ := member.Label(membership.ZoneLabel)
+		res = append(res, membership.NewDetailedHostInfo(member.GetAddress(), member.Identity(), portMap).WithZone(zone))
 
 		return true
 	}
-	_, err := r.ringpop.GetReachableMembers(memberData)
+	_, err := r.getRingpop().GetReachableMembers(memberData)
 	if err != nil {
 		return nil, fmt.Errorf("ringpop get members: %w", err)
 	}
@@ -246,12 +395,13 @@ func (r *Provider) GetMembers(service string) ([]membership.HostInfo, error) {
 
 // WhoAmI returns address of this instance
 func (r *Provider) WhoAmI() (membership.HostInfo, error) {
-	address, err := r.ringpop.WhoAmI()
+	rp := r.getRingpop()
+	address, err := rp.WhoAmI()
 	if err != nil {
 		return membership.HostInfo{}, fmt.Errorf("ringpop doesn't know Who Am I: %w", err)
 	}
 
-	labels, err := r.ringpop.Labels()
+	labels, err := rp.Labels()
 	if err != nil {
 		return membership.HostInfo{}, fmt.Errorf("getting ringpop labels: %w", err)
 	}
@@ -264,7 +414,7 @@ func (r *Provider) WhoAmI() (membership.HostInfo, error) {
 		hostIdentity = rpIdentity
 	}
 
-	return membership.NewDetailedHostInfo(address, hostIdentity, r.portmap), nil
+	return membership.NewDetailedHostInfo(address, hostIdentity, r.portmap).WithZone(r.zone), nil
 }
 
 // Stop stops ringpop
@@ -277,7 +427,12 @@ func (r *Provider) Stop() {
 		return
 	}
 
-	r.ringpop.Destroy()
+	close(r.shutdownCh)
+	if success := common.AwaitWaitGroup(&r.shutdownWG, time.Minute); !success {
+		r.logger.Warn("ringpop provider timed out on shutdown.")
+	}
+
+	r.getRingpop().Destroy()
 }
 
 // Subscribe allows to be subscribed for ring changes
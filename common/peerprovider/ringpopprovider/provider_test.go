@@ -100,7 +100,7 @@ func NewTestRingpopCluster(ringPopApp string, size int, ipAddr string, seed stri
 			return nil
 		}
 
-		NewRingpopProvider(ringPopApp, ringPop, membership.PortMap{}, bOptions, logger)
+		NewRingpopProvider(ringPopApp, ringPop, membership.PortMap{}, "", bOptions, logger)
 
 	}
 	return cluster
@@ -0,0 +1,135 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ringpopprovider
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/tchannel-go"
+
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/membership"
+)
+
+type mutableHostsProvider struct {
+	sync.Mutex
+	hosts []string
+}
+
+func (p *mutableHostsProvider) Hosts() ([]string, error) {
+	p.Lock()
+	defer p.Unlock()
+	return append([]string(nil), p.hosts...), nil
+}
+
+func (p *mutableHostsProvider) setHosts(hosts ...string) {
+	p.Lock()
+	defer p.Unlock()
+	p.hosts = hosts
+}
+
+func newTestProvider(t *testing.T, resolveInterval time.Duration) (*Provider, *mutableHostsProvider, string) {
+	channel, err := tchannel.NewChannel("ringpop-test", nil)
+	require.NoError(t, err)
+	require.NoError(t, channel.ListenAndServe("127.0.0.1:0"))
+	t.Cleanup(channel.Close)
+	address := channel.PeerInfo().HostPort
+
+	discoveryProvider := &mutableHostsProvider{hosts: []string{address}}
+	provider, err := New(
+		"test-service",
+		&Config{
+			Name:              "ringpop-test",
+			BootstrapMode:     BootstrapModeCustom,
+			DiscoveryProvider: discoveryProvider,
+			MaxJoinDuration:   2 * time.Second,
+			ResolveInterval:   resolveInterval,
+		},
+		channel,
+		membership.PortMap{},
+		"",
+		loggerimpl.NewNopLogger(),
+	)
+	require.NoError(t, err)
+	return provider, discoveryProvider, address
+}
+
+func getMemberAddresses(t *testing.T, provider *Provider) []string {
+	members, err := provider.GetMembers("test-service")
+	if err != nil {
+		return nil
+	}
+	var addresses []string
+	for _, member := range members {
+		addresses = append(addresses, member.GetAddress())
+	}
+	return addresses
+}
+
+func TestProviderRejoinsReplacedSeeds(t *testing.T) {
+	provider, discoveryProvider, address := newTestProvider(t, 50*time.Millisecond)
+	provider.Start()
+	defer provider.Stop()
+
+	// another ring whose seed is not known by the first one
+	otherProvider, _, otherAddress := newTestProvider(t, 0)
+	otherProvider.Start()
+	defer otherProvider.Stop()
+
+	assert.Equal(t, []string{address}, getMemberAddresses(t, provider))
+	assert.Equal(t, []string{otherAddress}, getMemberAddresses(t, otherProvider))
+
+	// the seeds are replaced by the host of the other ring
+	discoveryProvider.setHosts(otherAddress)
+	assert.Eventually(t, func() bool {
+		return len(getMemberAddresses(t, provider)) == 2 && len(getMemberAddresses(t, otherProvider)) == 2
+	}, 10*time.Second, 50*time.Millisecond)
+
+	self, err := provider.WhoAmI()
+	require.NoError(t, err)
+	assert.Equal(t, address, self.GetAddress())
+}
+
+func TestProviderDoesNotRejoinReachableSeeds(t *testing.T) {
+	provider, discoveryProvider, address := newTestProvider(t, 50*time.Millisecond)
+	provider.Start()
+	defer provider.Stop()
+
+	otherProvider, otherDiscoveryProvider, otherAddress := newTestProvider(t, 0)
+	otherDiscoveryProvider.setHosts(address)
+	otherProvider.Start()
+	defer otherProvider.Stop()
+	assert.Eventually(t, func() bool {
+		return len(getMemberAddresses(t, provider)) == 2
+	}, 10*time.Second, 50*time.Millisecond)
+	rp := provider.getRingpop()
+
+	// one of the new seeds is a member of the ring
+	discoveryProvider.setHosts(otherAddress, "127.0.0.1:1")
+	time.Sleep(200 * time.Millisecond)
+	assert.True(t, rp == provider.getRingpop())
+}
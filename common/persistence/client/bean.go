@@ -50,6 +50,9 @@ type (
 		GetDomainReplicationQueueManager() persistence.QueueManager
 		SetDomainReplicationQueueManager(persistence.QueueManager)
 
+		GetMatchingExpiredTaskQueueManager() persistence.QueueManager
+		SetMatchingExpiredTaskQueueManager(persistence.QueueManager)
+
 		GetShardManager() persistence.ShardManager
 		SetShardManager(persistence.ShardManager)
 
@@ -69,6 +72,7 @@ type (
 		taskManager                   persistence.TaskManager
 		visibilityManager             persistence.VisibilityManager
 		domainReplicationQueueManager persistence.QueueManager
+		expiredTaskQueueManager       persistence.QueueManager
 		shardManager                  persistence.ShardManager
 		historyManager                persistence.HistoryManager
 		configStoreManager            persistence.ConfigStoreManager
@@ -115,6 +119,11 @@ func NewBeanFromFactory(
 		return nil, err
 	}
 
+	expiredTaskQueue, err := factory.NewMatchingExpiredTaskQueueManager()
+	if err != nil {
+		return nil, err
+	}
+
 	shardMgr, err := factory.NewShardManager()
 	if err != nil {
 		return nil, err
@@ -140,6 +149,7 @@ func NewBeanFromFactory(
 		taskMgr,
 		visibilityMgr,
 		domainReplicationQueue,
+		expiredTaskQueue,
 		shardMgr,
 		historyMgr,
 		configStoreMgr,
@@ -153,6 +163,7 @@ func NewBean(
 	taskManager persistence.TaskManager,
 	visibilityManager persistence.VisibilityManager,
 	domainReplicationQueueManager persistence.QueueManager,
+	expiredTaskQueueManager persistence.QueueManager,
 	shardManager persistence.ShardManager,
 	historyManager persistence.HistoryManager,
 	configStoreManager persistence.ConfigStoreManager,
@@ -163,6 +174,7 @@ func NewBean(
 		taskManager:                   taskManager,
 		visibilityManager:             visibilityManager,
 		domainReplicationQueueManager: domainReplicationQueueManager,
+		expiredTaskQueueManager:       expiredTaskQueueManager,
 		shardManager:                  shardManager,
 		historyManager:                historyManager,
 		configStoreManager:            configStoreManager,
@@ -252,6 +264,26 @@ func (s *BeanImpl) SetDomainReplicationQueueManager(
 	s.domainReplicationQueueManager = domainReplicationQueueManager
 }
 
+// GetMatchingExpiredTaskQueueManager gets matching expired task QueueManager
+func (s *BeanImpl) GetMatchingExpiredTaskQueueManager() persistence.QueueManager {
+
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.expiredTaskQueueManager
+}
+
+// SetMatchingExpiredTaskQueueManager sets matching expired task QueueManager
+func (s *BeanImpl) SetMatchingExpiredTaskQueueManager(
+	expiredTaskQueueManager persistence.QueueManager,
+) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.expiredTaskQueueManager = expiredTaskQueueManager
+}
+
 // GetShardManager get ShardManager
 func (s *BeanImpl) GetShardManager() persistence.ShardManager {
 
@@ -367,6 +399,7 @@ func (s *BeanImpl) Close() {
 		s.visibilityManager.Close()
 	}
 	s.domainReplicationQueueManager.Close()
+	s.expiredTaskQueueManager.Close()
 	s.shardManager.Close()
 	s.historyManager.Close()
 	s.executionManagerFactory.Close()
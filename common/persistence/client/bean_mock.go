@@ -140,6 +140,20 @@ func (mr *MockBeanMockRecorder) GetHistoryManager() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistoryManager", reflect.TypeOf((*MockBean)(nil).GetHistoryManager))
 }
 
+// GetMatchingExpiredTaskQueueManager mocks base method.
+func (m *MockBean) GetMatchingExpiredTaskQueueManager() persistence.QueueManager {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMatchingExpiredTaskQueueManager")
+	ret0, _ := ret[0].(persistence.QueueManager)
+	return ret0
+}
+
+// GetMatchingExpiredTaskQueueManager indicates an expected call of GetMatchingExpiredTaskQueueManager.
+func (mr *MockBeanMockRecorder) GetMatchingExpiredTaskQueueManager() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMatchingExpiredTaskQueueManager", reflect.TypeOf((*MockBean)(nil).GetMatchingExpiredTaskQueueManager))
+}
+
 // GetShardManager mocks base method.
 func (m *MockBean) GetShardManager() persistence.ShardManager {
 	m.ctrl.T.Helper()
@@ -242,6 +256,18 @@ func (mr *MockBeanMockRecorder) SetHistoryManager(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHistoryManager", reflect.TypeOf((*MockBean)(nil).SetHistoryManager), arg0)
 }
 
+// SetMatchingExpiredTaskQueueManager mocks base method.
+func (m *MockBean) SetMatchingExpiredTaskQueueManager(arg0 persistence.QueueManager) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetMatchingExpiredTaskQueueManager", arg0)
+}
+
+// SetMatchingExpiredTaskQueueManager indicates an expected call of SetMatchingExpiredTaskQueueManager.
+func (mr *MockBeanMockRecorder) SetMatchingExpiredTaskQueueManager(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMatchingExpiredTaskQueueManager", reflect.TypeOf((*MockBean)(nil).SetMatchingExpiredTaskQueueManager), arg0)
+}
+
 // SetShardManager mocks base method.
 func (m *MockBean) SetShardManager(arg0 persistence.ShardManager) {
 	m.ctrl.T.Helper()
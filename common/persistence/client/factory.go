@@ -25,7 +25,9 @@ import (
 
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/dynamicconfig"
 	es "github.com/uber/cadence/common/elasticsearch"
+	"github.com/uber/cadence/common/encryption"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/messaging"
@@ -60,6 +62,8 @@ type (
 		NewVisibilityManager(params *Params, serviceConfig *service.Config) (p.VisibilityManager, error)
 		// NewDomainReplicationQueueManager returns a new queue for domain replication
 		NewDomainReplicationQueueManager() (p.QueueManager, error)
+		// NewMatchingExpiredTaskQueueManager returns a new queue for tasks that expired in matching
+		NewMatchingExpiredTaskQueueManager() (p.QueueManager, error)
 		// NewConfigStoreManager returns a new config store manager
 		NewConfigStoreManager() (p.ConfigStoreManager, error)
 	}
@@ -94,12 +98,14 @@ type (
 	}
 	factoryImpl struct {
 		sync.RWMutex
-		config        *config.Persistence
-		metricsClient metrics.Client
-		logger        log.Logger
-		datastores    map[storeType]Datastore
-		clusterName   string
-		dc            *p.DynamicConfiguration
+		config          *config.Persistence
+		metricsClient   metrics.Client
+		usageRecorder   p.UsageRecorder
+		latencyRecorder p.LatencyRecorder
+		logger          log.Logger
+		datastores      map[storeType]Datastore
+		clusterName     string
+		dc              *p.DynamicConfiguration
 	}
 
 	storeType int
@@ -134,20 +140,26 @@ var storeTypes = []storeType{
 //
 // The objects returned by this factory enforce ratelimit and maxconns according to
 // given configuration. In addition, all objects will emit metrics automatically
+// and record the usage of domains when usageRecorder is not nil, and the latency of
+// the operations on shards when latencyRecorder is not nil
 func NewFactory(
 	cfg *config.Persistence,
 	persistenceMaxQPS quotas.RPSFunc,
 	clusterName string,
 	metricsClient metrics.Client,
+	usageRecorder p.UsageRecorder,
+	latencyRecorder p.LatencyRecorder,
 	logger log.Logger,
 	dc *p.DynamicConfiguration,
 ) Factory {
 	factory := &factoryImpl{
-		config:        cfg,
-		metricsClient: metricsClient,
-		logger:        logger,
-		clusterName:   clusterName,
-		dc:            dc,
+		config:          cfg,
+		metricsClient:   metricsClient,
+		usageRecorder:   usageRecorder,
+		latencyRecorder: latencyRecorder,
+		logger:          logger,
+		clusterName:     clusterName,
+		dc:              dc,
 	}
 	limiters := buildRatelimiters(cfg, persistenceMaxQPS)
 	factory.init(clusterName, limiters)
@@ -169,7 +181,7 @@ func (f *factoryImpl) NewTaskManager() (p.TaskManager, error) {
 		result = p.NewTaskPersistenceRateLimitedClient(result, ds.ratelimit, f.logger)
 	}
 	if f.metricsClient != nil {
-		result = p.NewTaskPersistenceMetricsClient(result, f.metricsClient, f.logger, f.config)
+		result = p.NewTaskPersistenceMetricsClient(result, f.metricsClient, f.usageRecorder, f.logger, f.config)
 	}
 	return result, nil
 }
@@ -201,7 +213,20 @@ func (f *factoryImpl) NewHistoryManager() (p.HistoryManager, error) {
 	if err != nil {
 		return nil, err
 	}
-	result := p.NewHistoryV2ManagerImpl(store, f.logger, f.config.TransactionSizeLimit)
+	var encryptor encryption.Encryptor
+	var encryptionKeyID dynamicconfig.StringPropertyFnWithDomainFilter
+	var compressionCodec dynamicconfig.StringPropertyFnWithDomainFilter
+	if f.config.Encryption != nil {
+		encryptor, err = encryption.NewEncryptor(f.config.Encryption)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if f.dc != nil {
+		encryptionKeyID = f.dc.HistoryEncryptionKeyID
+		compressionCodec = f.dc.HistoryCompressionCodec
+	}
+	result := p.NewHistoryV2ManagerImpl(store, f.logger, f.config.TransactionSizeLimit, encryptor, encryptionKeyID, compressionCodec)
 	if errorRate := f.config.ErrorInjectionRate(); errorRate != 0 {
 		result = p.NewHistoryPersistenceErrorInjectionClient(result, errorRate, f.logger)
 	}
@@ -209,7 +234,7 @@ func (f *factoryImpl) NewHistoryManager() (p.HistoryManager, error) {
 		result = p.NewHistoryPersistenceRateLimitedClient(result, ds.ratelimit, f.logger)
 	}
 	if f.metricsClient != nil {
-		result = p.NewHistoryPersistenceMetricsClient(result, f.metricsClient, f.logger, f.config)
+		result = p.NewHistoryPersistenceMetricsClient(result, f.metricsClient, f.usageRecorder, f.logger, f.config)
 	}
 	return result, nil
 }
@@ -251,7 +276,7 @@ func (f *factoryImpl) NewExecutionManager(shardID int) (p.ExecutionManager, erro
 		result = p.NewWorkflowExecutionPersistenceRateLimitedClient(result, ds.ratelimit, f.logger)
 	}
 	if f.metricsClient != nil {
-		result = p.NewWorkflowExecutionPersistenceMetricsClient(result, f.metricsClient, f.logger, f.config)
+		result = p.NewWorkflowExecutionPersistenceMetricsClient(result, f.metricsClient, f.usageRecorder, f.latencyRecorder, f.logger, f.config)
 	}
 	return result, nil
 }
@@ -357,8 +382,16 @@ func (f *factoryImpl) newDBVisibilityManager(
 }
 
 func (f *factoryImpl) NewDomainReplicationQueueManager() (p.QueueManager, error) {
+	return f.newQueueManager(p.DomainReplicationQueueType)
+}
+
+func (f *factoryImpl) NewMatchingExpiredTaskQueueManager() (p.QueueManager, error) {
+	return f.newQueueManager(p.MatchingExpiredTaskQueueType)
+}
+
+func (f *factoryImpl) newQueueManager(queueType p.QueueType) (p.QueueManager, error) {
 	ds := f.datastores[storeTypeQueue]
-	store, err := ds.factory.NewQueue(p.DomainReplicationQueueType)
+	store, err := ds.factory.NewQueue(queueType)
 	if err != nil {
 		return nil, err
 	}
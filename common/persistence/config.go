@@ -29,6 +29,8 @@ type (
 	DynamicConfiguration struct {
 		EnableSQLAsyncTransaction                dynamicconfig.BoolPropertyFn
 		EnableCassandraAllConsistencyLevelDelete dynamicconfig.BoolPropertyFn
+		HistoryEncryptionKeyID                   dynamicconfig.StringPropertyFnWithDomainFilter
+		HistoryCompressionCodec                  dynamicconfig.StringPropertyFnWithDomainFilter
 	}
 )
 
@@ -37,5 +39,7 @@ func NewDynamicConfiguration(dc *dynamicconfig.Collection) *DynamicConfiguration
 	return &DynamicConfiguration{
 		EnableSQLAsyncTransaction:                dc.GetBoolProperty(dynamicconfig.EnableSQLAsyncTransaction),
 		EnableCassandraAllConsistencyLevelDelete: dc.GetBoolProperty(dynamicconfig.EnableCassandraAllConsistencyLevelDelete),
+		HistoryEncryptionKeyID:                   dc.GetStringPropertyFilteredByDomain(dynamicconfig.HistoryEncryptionKeyID),
+		HistoryCompressionCodec:                  dc.GetStringPropertyFilteredByDomain(dynamicconfig.HistoryCompressionCodec),
 	}
 }
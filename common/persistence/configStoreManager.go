@@ -22,10 +22,17 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"math"
 	"time"
 
+	"github.com/pborman/uuid"
+
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/types"
 )
 
 type (
@@ -83,5 +90,163 @@ func (m *configStoreManagerImpl) UpdateDynamicConfig(ctx context.Context, reques
 		Values:    blob,
 	}
 
-	return m.persistence.UpdateConfig(ctx, entry)
+	if err := m.persistence.UpdateConfig(ctx, entry); err != nil {
+		return err
+	}
+
+	if request.Change != nil {
+		// the snapshot is already committed at this point, failing to record the change
+		// must not fail the update, otherwise callers would retry an update which succeeded
+		if err := m.recordDynamicConfigChange(ctx, request.Snapshot.Version, entry.Timestamp, request.Change); err != nil {
+			m.logger.Error("Failed to record dynamic config change",
+				tag.Key(request.Change.Name),
+				tag.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+func (m *configStoreManagerImpl) ListDynamicConfigChanges(
+	ctx context.Context,
+	request *ListDynamicConfigChangesRequest,
+) (*ListDynamicConfigChangesResponse, error) {
+	maxVersion := request.MaxVersion
+	if maxVersion <= 0 {
+		maxVersion = math.MaxInt64
+	}
+	entries, err := m.persistence.ListConfigs(ctx, DynamicConfigChange, maxVersion, request.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*DynamicConfigChangeRecord, 0, len(entries))
+	for _, entry := range entries {
+		var change DynamicConfigChangeRecord
+		if err := json.Unmarshal(entry.Values.Data, &change); err != nil {
+			return nil, &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("failed to decode dynamic config change of version %v: %v", entry.Version, err),
+			}
+		}
+		change.Version = entry.Version
+		change.Timestamp = entry.Timestamp
+		changes = append(changes, &change)
+	}
+	return &ListDynamicConfigChangesResponse{Changes: changes}, nil
+}
+
+func (m *configStoreManagerImpl) recordDynamicConfigChange(
+	ctx context.Context,
+	version int64,
+	timestamp time.Time,
+	change *DynamicConfigChangeRecord,
+) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	return m.persistence.UpdateConfig(ctx, &InternalConfigStoreEntry{
+		RowType:   int(DynamicConfigChange),
+		Version:   version,
+		Timestamp: timestamp,
+		Values:    NewDataBlob(data, common.EncodingTypeJSON),
+	})
+}
+
+func (m *configStoreManagerImpl) RecordDomainUsage(ctx context.Context, request *RecordDomainUsageRequest) error {
+	record := request.Record
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	// hosts report independently, so every report gets its own id
+	return m.persistence.InsertDomainUsage(ctx, &InternalDomainUsageReport{
+		Hour:     DomainUsageHour(record.IntervalEnd),
+		ReportID: uuid.New(),
+		Values:   NewDataBlob(data, common.EncodingTypeJSON),
+	}, request.TTL)
+}
+
+func (m *configStoreManagerImpl) ListDomainUsage(ctx context.Context, request *ListDomainUsageRequest) (*ListDomainUsageResponse, error) {
+	reports, err := m.persistence.ListDomainUsage(ctx, DomainUsageHour(request.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*DomainUsageRecord, 0, len(reports))
+	for _, report := range reports {
+		var record DomainUsageRecord
+		if err := json.Unmarshal(report.Values.Data, &record); err != nil {
+			return nil, &InvalidPersistenceRequestError{
+				Msg: fmt.Sprintf("failed to decode domain usage report %v: %v", report.ReportID, err),
+			}
+		}
+		records = append(records, &record)
+	}
+	return &ListDomainUsageResponse{Records: records}, nil
+}
+
+func (m *configStoreManagerImpl) FetchAPIKeys(ctx context.Context) (*FetchAPIKeysResponse, error) {
+	entry, err := m.persistence.FetchConfig(ctx, APIKeys)
+	if _, ok := err.(*types.EntityNotExistsError); ok || (err == nil && entry == nil) {
+		return &FetchAPIKeysResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot APIKeySnapshot
+	if err := json.Unmarshal(entry.Values.Data, &snapshot); err != nil {
+		return nil, &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("failed to decode API keys of version %v: %v", entry.Version, err),
+		}
+	}
+	snapshot.Version = entry.Version
+	return &FetchAPIKeysResponse{Snapshot: &snapshot}, nil
+}
+
+func (m *configStoreManagerImpl) UpdateAPIKeys(ctx context.Context, request *UpdateAPIKeysRequest) error {
+	data, err := json.Marshal(request.Snapshot)
+	if err != nil {
+		return err
+	}
+	return m.persistence.UpdateConfig(ctx, &InternalConfigStoreEntry{
+		RowType:   int(APIKeys),
+		Version:   request.Snapshot.Version,
+		Timestamp: time.Now(),
+		Values:    NewDataBlob(data, common.EncodingTypeJSON),
+	})
+}
+
+func (m *configStoreManagerImpl) FetchResharding(ctx context.Context) (*FetchReshardingResponse, error) {
+	entry, err := m.persistence.FetchConfig(ctx, Resharding)
+	if _, ok := err.(*types.EntityNotExistsError); ok || (err == nil && entry == nil) {
+		return &FetchReshardingResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot ReshardingSnapshot
+	if err := json.Unmarshal(entry.Values.Data, &snapshot); err != nil {
+		return nil, &InvalidPersistenceRequestError{
+			Msg: fmt.Sprintf("failed to decode resharding state of version %v: %v", entry.Version, err),
+		}
+	}
+	snapshot.Version = entry.Version
+	return &FetchReshardingResponse{Snapshot: &snapshot}, nil
+}
+
+func (m *configStoreManagerImpl) UpdateResharding(ctx context.Context, request *UpdateReshardingRequest) error {
+	data, err := json.Marshal(request.Snapshot)
+	if err != nil {
+		return err
+	}
+	return m.persistence.UpdateConfig(ctx, &InternalConfigStoreEntry{
+		RowType:   int(Resharding),
+		Version:   request.Snapshot.Version,
+		Timestamp: time.Now(),
+		Values:    NewDataBlob(data, common.EncodingTypeJSON),
+	})
 }
@@ -64,6 +64,7 @@ type QueueType int
 // Negative numbers are reserved for DLQ
 const (
 	DomainReplicationQueueType QueueType = iota + 1
+	MatchingExpiredTaskQueueType
 )
 
 // Create Workflow Execution Mode
@@ -196,6 +197,7 @@ const UnknownNumRowsAffected = -1
 const (
 	WorkflowBackoffTimeoutTypeRetry = iota
 	WorkflowBackoffTimeoutTypeCron
+	WorkflowBackoffTimeoutTypeCronOverlap
 )
 
 const (
@@ -222,6 +224,12 @@ type ConfigType int
 
 const (
 	DynamicConfig ConfigType = iota
+	// DynamicConfigChange rows keep the change history of dynamic config, one row per snapshot version
+	DynamicConfigChange
+	// APIKeys rows keep the issued API keys, one row per snapshot version
+	APIKeys
+	// Resharding rows keep the state of the history shard count resharding, one row per snapshot version
+	Resharding
 )
 
 type (
@@ -449,6 +457,12 @@ type (
 		Kind        int
 		Expiry      time.Time
 		LastUpdated time.Time
+		// ApproximateBacklogCount and OldestTaskCreatedTime are a snapshot of the backlog
+		// taken by the owner of the task list, they are not updated on every task
+		ApproximateBacklogCount int64
+		OldestTaskCreatedTime   time.Time
+		// Paused is set by an operator to stop dispatching the tasks of the task list
+		Paused bool
 	}
 
 	// TaskInfo describes either activity or decision task
@@ -461,6 +475,9 @@ type (
 		ScheduleToStartTimeout int32
 		Expiry                 time.Time
 		CreatedTime            time.Time
+		// Priority is carried by the task from the workflow which scheduled it and is persisted
+		// with the task. Higher priority tasks are dispatched first.
+		Priority int
 	}
 
 	// TaskKey gives primary key info for a specific task
@@ -1252,6 +1269,19 @@ type (
 		PageToken []byte
 	}
 
+	// GetTaskListRequest contains the request params needed to invoke GetTaskList API
+	GetTaskListRequest struct {
+		DomainID   string
+		DomainName string
+		TaskList   string
+		TaskType   int
+	}
+
+	// GetTaskListResponse is the response from GetTaskList API
+	GetTaskListResponse struct {
+		TaskListInfo *TaskListInfo
+	}
+
 	// ListTaskListResponse is the response from ListTaskList API
 	ListTaskListResponse struct {
 		Items         []TaskListInfo
@@ -1708,6 +1738,8 @@ type (
 	// UpdateDynamicConfigRequest is a request to update dynamic config with snapshot
 	UpdateDynamicConfigRequest struct {
 		Snapshot *DynamicConfigSnapshot
+		// Change is optional, when set it is recorded in the change history under the snapshot version
+		Change *DynamicConfigChangeRecord
 	}
 
 	DynamicConfigSnapshot struct {
@@ -1715,6 +1747,135 @@ type (
 		Values  *types.DynamicConfigBlob
 	}
 
+	// ListDynamicConfigChangesRequest is a request to list the dynamic config change history
+	ListDynamicConfigChangesRequest struct {
+		// MaxVersion is the largest snapshot version returned, zero means latest
+		MaxVersion int64
+		PageSize   int
+	}
+
+	// ListDynamicConfigChangesResponse is a response to ListDynamicConfigChangesRequest,
+	// changes are sorted by version in descending order
+	ListDynamicConfigChangesResponse struct {
+		Changes []*DynamicConfigChangeRecord
+	}
+
+	// DynamicConfigChangeRecord records a change of a dynamic config entry
+	DynamicConfigChangeRecord struct {
+		Version   int64                       `json:"version"`
+		Timestamp time.Time                   `json:"timestamp"`
+		Identity  string                      `json:"identity,omitempty"`
+		Name      string                      `json:"name"`
+		OldValues []*types.DynamicConfigValue `json:"oldValues,omitempty"`
+		NewValues []*types.DynamicConfigValue `json:"newValues,omitempty"`
+	}
+
+	// RecordDomainUsageRequest is a request to record the usage of domains during an interval
+	RecordDomainUsageRequest struct {
+		Record *DomainUsageRecord
+		// TTL is how long the record is kept for, zero means forever
+		TTL time.Duration
+	}
+
+	// ListDomainUsageRequest is a request to list the recorded domain usage
+	ListDomainUsageRequest struct {
+		// Hour is the hour the intervals of the listed records end in, see DomainUsageHour
+		Hour time.Time
+	}
+
+	// ListDomainUsageResponse is a response to ListDomainUsageRequest
+	ListDomainUsageResponse struct {
+		Records []*DomainUsageRecord
+	}
+
+	// DomainUsageRecord is the usage of domains reported by a host for an interval
+	DomainUsageRecord struct {
+		Service       string                  `json:"service"`
+		IntervalStart time.Time               `json:"intervalStart"`
+		IntervalEnd   time.Time               `json:"intervalEnd"`
+		Domains       map[string]*DomainUsage `json:"domains"`
+	}
+
+	// DomainUsage is the resources consumed by a domain
+	DomainUsage struct {
+		// Actions are the billable api calls, ex: starting or signaling a workflow
+		Actions int64 `json:"actions,omitempty"`
+		// PersistenceReadUnits and PersistenceWriteUnits are the number of persistence requests
+		PersistenceReadUnits  int64 `json:"persistenceReadUnits,omitempty"`
+		PersistenceWriteUnits int64 `json:"persistenceWriteUnits,omitempty"`
+		// PayloadBytes is the size of the payloads sent by the clients
+		PayloadBytes   int64 `json:"payloadBytes,omitempty"`
+		TaskDispatches int64 `json:"taskDispatches,omitempty"`
+	}
+
+	// FetchAPIKeysResponse is a response to FetchAPIKeys, the snapshot is nil if no key was ever issued
+	FetchAPIKeysResponse struct {
+		Snapshot *APIKeySnapshot
+	}
+
+	// UpdateAPIKeysRequest is a request to write a new snapshot of the API keys,
+	// the update fails with ConditionFailedError if the version of the snapshot already exists
+	UpdateAPIKeysRequest struct {
+		Snapshot *APIKeySnapshot
+	}
+
+	// APIKeySnapshot is a version of the issued API keys
+	APIKeySnapshot struct {
+		Version int64     `json:"-"`
+		Keys    []*APIKey `json:"keys"`
+	}
+
+	// APIKey is an API key issued to a caller, only the hash of its secret is stored
+	APIKey struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		// Hash is the hex encoded SHA-256 of the secret of the key
+		Hash string `json:"hash"`
+		// Domains are the domains the key can access, * for all the domains
+		Domains []string `json:"domains"`
+		// Permissions are the categories of APIs the key can call: read, write or admin
+		Permissions []string  `json:"permissions"`
+		CreatedTime time.Time `json:"createdTime"`
+		// ExpiryTime is when the key expires, zero if it doesn't
+		ExpiryTime time.Time `json:"expiryTime"`
+		// RevokedTime is when the key was revoked, zero if it wasn't
+		RevokedTime time.Time `json:"revokedTime"`
+	}
+
+	// FetchReshardingResponse is a response to FetchResharding, the snapshot is nil if the shard count was never changed
+	FetchReshardingResponse struct {
+		Snapshot *ReshardingSnapshot
+	}
+
+	// UpdateReshardingRequest is a request to write a new snapshot of the resharding state,
+	// the update fails with ConditionFailedError if the version of the snapshot already exists
+	UpdateReshardingRequest struct {
+		Snapshot *ReshardingSnapshot
+	}
+
+	// ReshardingSnapshot is a version of the state of a history shard count resharding.
+	// The shard count is doubled by splitting each shard s of the FromShardCount shards into the shards s and s+FromShardCount.
+	ReshardingSnapshot struct {
+		Version        int64 `json:"-"`
+		FromShardCount int   `json:"fromShardCount"`
+		ToShardCount   int   `json:"toShardCount"`
+		// SplitShards are the shards whose workflows moving to the new shards were migrated
+		SplitShards []int `json:"splitShards,omitempty"`
+		// PausedShards are the shards being split, history hosts don't own them until the split is done
+		PausedShards []int     `json:"pausedShards,omitempty"`
+		StartedTime  time.Time `json:"startedTime"`
+	}
+
+	// UsageRecorder records the resources consumed by domains
+	UsageRecorder interface {
+		RecordUsage(domainName string, usage DomainUsage)
+	}
+
+	// LatencyRecorder records the latency of the operations on the persistence of shards
+	LatencyRecorder interface {
+		RecordLatency(shardID int, operation string, latency time.Duration)
+	}
+
 	// Closeable is an interface for any entity that supports a close operation to release resources
 	Closeable interface {
 		Close()
@@ -1787,6 +1948,8 @@ type (
 		GetName() string
 		LeaseTaskList(ctx context.Context, request *LeaseTaskListRequest) (*LeaseTaskListResponse, error)
 		UpdateTaskList(ctx context.Context, request *UpdateTaskListRequest) (*UpdateTaskListResponse, error)
+		// GetTaskList returns the task list row without leasing it
+		GetTaskList(ctx context.Context, request *GetTaskListRequest) (*GetTaskListResponse, error)
 		ListTaskList(ctx context.Context, request *ListTaskListRequest) (*ListTaskListResponse, error)
 		DeleteTaskList(ctx context.Context, request *DeleteTaskListRequest) error
 		CreateTasks(ctx context.Context, request *CreateTasksRequest) (*CreateTasksResponse, error)
@@ -1866,6 +2029,13 @@ type (
 		Closeable
 		FetchDynamicConfig(ctx context.Context) (*FetchDynamicConfigResponse, error)
 		UpdateDynamicConfig(ctx context.Context, request *UpdateDynamicConfigRequest) error
+		ListDynamicConfigChanges(ctx context.Context, request *ListDynamicConfigChangesRequest) (*ListDynamicConfigChangesResponse, error)
+		RecordDomainUsage(ctx context.Context, request *RecordDomainUsageRequest) error
+		ListDomainUsage(ctx context.Context, request *ListDomainUsageRequest) (*ListDomainUsageResponse, error)
+		FetchAPIKeys(ctx context.Context) (*FetchAPIKeysResponse, error)
+		UpdateAPIKeys(ctx context.Context, request *UpdateAPIKeysRequest) error
+		FetchResharding(ctx context.Context) (*FetchReshardingResponse, error)
+		UpdateResharding(ctx context.Context, request *UpdateReshardingRequest) error
 		//can add functions for config types other than dynamic config
 	}
 )
@@ -3036,3 +3206,17 @@ func HasMoreRowsToDelete(rowsDeleted, batchSize int) bool {
 	}
 	return true
 }
+
+// DomainUsageHour returns the hour usage records ending at t are listed by
+func DomainUsageHour(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour)
+}
+
+// Add adds the other usage to the usage
+func (u *DomainUsage) Add(other *DomainUsage) {
+	u.Actions += other.Actions
+	u.PersistenceReadUnits += other.PersistenceReadUnits
+	u.PersistenceWriteUnits += other.PersistenceWriteUnits
+	u.PayloadBytes += other.PayloadBytes
+	u.TaskDispatches += other.TaskDispatches
+}
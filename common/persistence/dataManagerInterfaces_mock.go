@@ -149,6 +149,76 @@ func (mr *MockTaskMockRecorder) SetVisibilityTimestamp(timestamp interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVisibilityTimestamp", reflect.TypeOf((*MockTask)(nil).SetVisibilityTimestamp), timestamp)
 }
 
+// MockUsageRecorder is a mock of UsageRecorder interface.
+type MockUsageRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsageRecorderMockRecorder
+}
+
+// MockUsageRecorderMockRecorder is the mock recorder for MockUsageRecorder.
+type MockUsageRecorderMockRecorder struct {
+	mock *MockUsageRecorder
+}
+
+// NewMockUsageRecorder creates a new mock instance.
+func NewMockUsageRecorder(ctrl *gomock.Controller) *MockUsageRecorder {
+	mock := &MockUsageRecorder{ctrl: ctrl}
+	mock.recorder = &MockUsageRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsageRecorder) EXPECT() *MockUsageRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordUsage mocks base method.
+func (m *MockUsageRecorder) RecordUsage(domainName string, usage DomainUsage) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordUsage", domainName, usage)
+}
+
+// RecordUsage indicates an expected call of RecordUsage.
+func (mr *MockUsageRecorderMockRecorder) RecordUsage(domainName, usage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordUsage", reflect.TypeOf((*MockUsageRecorder)(nil).RecordUsage), domainName, usage)
+}
+
+// MockLatencyRecorder is a mock of LatencyRecorder interface.
+type MockLatencyRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockLatencyRecorderMockRecorder
+}
+
+// MockLatencyRecorderMockRecorder is the mock recorder for MockLatencyRecorder.
+type MockLatencyRecorderMockRecorder struct {
+	mock *MockLatencyRecorder
+}
+
+// NewMockLatencyRecorder creates a new mock instance.
+func NewMockLatencyRecorder(ctrl *gomock.Controller) *MockLatencyRecorder {
+	mock := &MockLatencyRecorder{ctrl: ctrl}
+	mock.recorder = &MockLatencyRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLatencyRecorder) EXPECT() *MockLatencyRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordLatency mocks base method.
+func (m *MockLatencyRecorder) RecordLatency(shardID int, operation string, latency time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordLatency", shardID, operation, latency)
+}
+
+// RecordLatency indicates an expected call of RecordLatency.
+func (mr *MockLatencyRecorderMockRecorder) RecordLatency(shardID, operation, latency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLatency", reflect.TypeOf((*MockLatencyRecorder)(nil).RecordLatency), shardID, operation, latency)
+}
+
 // MockCloseable is a mock of Closeable interface.
 type MockCloseable struct {
 	ctrl     *gomock.Controller
@@ -922,6 +992,21 @@ func (mr *MockTaskManagerMockRecorder) GetOrphanTasks(ctx, request interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrphanTasks", reflect.TypeOf((*MockTaskManager)(nil).GetOrphanTasks), ctx, request)
 }
 
+// GetTaskList mocks base method.
+func (m *MockTaskManager) GetTaskList(ctx context.Context, request *GetTaskListRequest) (*GetTaskListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskList", ctx, request)
+	ret0, _ := ret[0].(*GetTaskListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskList indicates an expected call of GetTaskList.
+func (mr *MockTaskManagerMockRecorder) GetTaskList(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskList", reflect.TypeOf((*MockTaskManager)(nil).GetTaskList), ctx, request)
+}
+
 // GetTasks mocks base method.
 func (m *MockTaskManager) GetTasks(ctx context.Context, request *GetTasksRequest) (*GetTasksResponse, error) {
 	m.ctrl.T.Helper()
@@ -1545,6 +1630,36 @@ func (mr *MockConfigStoreManagerMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockConfigStoreManager)(nil).Close))
 }
 
+// FetchAPIKeys mocks base method.
+func (m *MockConfigStoreManager) FetchAPIKeys(ctx context.Context) (*FetchAPIKeysResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchAPIKeys", ctx)
+	ret0, _ := ret[0].(*FetchAPIKeysResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchAPIKeys indicates an expected call of FetchAPIKeys.
+func (mr *MockConfigStoreManagerMockRecorder) FetchAPIKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAPIKeys", reflect.TypeOf((*MockConfigStoreManager)(nil).FetchAPIKeys), ctx)
+}
+
+// FetchResharding mocks base method.
+func (m *MockConfigStoreManager) FetchResharding(ctx context.Context) (*FetchReshardingResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchResharding", ctx)
+	ret0, _ := ret[0].(*FetchReshardingResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchResharding indicates an expected call of FetchResharding.
+func (mr *MockConfigStoreManagerMockRecorder) FetchResharding(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchResharding", reflect.TypeOf((*MockConfigStoreManager)(nil).FetchResharding), ctx)
+}
+
 // FetchDynamicConfig mocks base method.
 func (m *MockConfigStoreManager) FetchDynamicConfig(ctx context.Context) (*FetchDynamicConfigResponse, error) {
 	m.ctrl.T.Helper()
@@ -1560,6 +1675,78 @@ func (mr *MockConfigStoreManagerMockRecorder) FetchDynamicConfig(ctx interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchDynamicConfig", reflect.TypeOf((*MockConfigStoreManager)(nil).FetchDynamicConfig), ctx)
 }
 
+// ListDomainUsage mocks base method.
+func (m *MockConfigStoreManager) ListDomainUsage(ctx context.Context, request *ListDomainUsageRequest) (*ListDomainUsageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDomainUsage", ctx, request)
+	ret0, _ := ret[0].(*ListDomainUsageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDomainUsage indicates an expected call of ListDomainUsage.
+func (mr *MockConfigStoreManagerMockRecorder) ListDomainUsage(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDomainUsage", reflect.TypeOf((*MockConfigStoreManager)(nil).ListDomainUsage), ctx, request)
+}
+
+// ListDynamicConfigChanges mocks base method.
+func (m *MockConfigStoreManager) ListDynamicConfigChanges(ctx context.Context, request *ListDynamicConfigChangesRequest) (*ListDynamicConfigChangesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDynamicConfigChanges", ctx, request)
+	ret0, _ := ret[0].(*ListDynamicConfigChangesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDynamicConfigChanges indicates an expected call of ListDynamicConfigChanges.
+func (mr *MockConfigStoreManagerMockRecorder) ListDynamicConfigChanges(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDynamicConfigChanges", reflect.TypeOf((*MockConfigStoreManager)(nil).ListDynamicConfigChanges), ctx, request)
+}
+
+// RecordDomainUsage mocks base method.
+func (m *MockConfigStoreManager) RecordDomainUsage(ctx context.Context, request *RecordDomainUsageRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordDomainUsage", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordDomainUsage indicates an expected call of RecordDomainUsage.
+func (mr *MockConfigStoreManagerMockRecorder) RecordDomainUsage(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordDomainUsage", reflect.TypeOf((*MockConfigStoreManager)(nil).RecordDomainUsage), ctx, request)
+}
+
+// UpdateAPIKeys mocks base method.
+func (m *MockConfigStoreManager) UpdateAPIKeys(ctx context.Context, request *UpdateAPIKeysRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAPIKeys", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAPIKeys indicates an expected call of UpdateAPIKeys.
+func (mr *MockConfigStoreManagerMockRecorder) UpdateAPIKeys(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKeys", reflect.TypeOf((*MockConfigStoreManager)(nil).UpdateAPIKeys), ctx, request)
+}
+
+// UpdateResharding mocks base method.
+func (m *MockConfigStoreManager) UpdateResharding(ctx context.Context, request *UpdateReshardingRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResharding", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateResharding indicates an expected call of UpdateResharding.
+func (mr *MockConfigStoreManagerMockRecorder) UpdateResharding(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResharding", reflect.TypeOf((*MockConfigStoreManager)(nil).UpdateResharding), ctx, request)
+}
+
 // UpdateDynamicConfig mocks base method.
 func (m *MockConfigStoreManager) UpdateDynamicConfig(ctx context.Context, request *UpdateDynamicConfigRequest) error {
 	m.ctrl.T.Helper()
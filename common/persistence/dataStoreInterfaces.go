@@ -55,6 +55,7 @@ type (
 		GetName() string
 		LeaseTaskList(ctx context.Context, request *LeaseTaskListRequest) (*LeaseTaskListResponse, error)
 		UpdateTaskList(ctx context.Context, request *UpdateTaskListRequest) (*UpdateTaskListResponse, error)
+		GetTaskList(ctx context.Context, request *GetTaskListRequest) (*GetTaskListResponse, error)
 		ListTaskList(ctx context.Context, request *ListTaskListRequest) (*ListTaskListResponse, error)
 		DeleteTaskList(ctx context.Context, request *DeleteTaskListRequest) error
 		CreateTasks(ctx context.Context, request *InternalCreateTasksRequest) (*CreateTasksResponse, error)
@@ -185,6 +186,12 @@ type (
 		Closeable
 		FetchConfig(ctx context.Context, configType ConfigType) (*InternalConfigStoreEntry, error)
 		UpdateConfig(ctx context.Context, value *InternalConfigStoreEntry) error
+		// ListConfigs returns entries of the config type with version <= maxVersion, sorted by version in descending order
+		ListConfigs(ctx context.Context, configType ConfigType, maxVersion int64, pageSize int) ([]*InternalConfigStoreEntry, error)
+		// InsertDomainUsage inserts a domain usage report which expires after ttl, zero ttl means it never expires
+		InsertDomainUsage(ctx context.Context, report *InternalDomainUsageReport, ttl time.Duration) error
+		// ListDomainUsage returns the domain usage reports of the hour
+		ListDomainUsage(ctx context.Context, hour time.Time) ([]*InternalDomainUsageReport, error)
 	}
 
 	InternalConfigStoreEntry struct {
@@ -194,6 +201,13 @@ type (
 		Values    *DataBlob
 	}
 
+	// InternalDomainUsageReport is a report of the domain_usage table, reports are partitioned by hour
+	InternalDomainUsageReport struct {
+		Hour     time.Time
+		ReportID string
+		Values   *DataBlob
+	}
+
 	// Queue is a store to enqueue and get messages
 	Queue interface {
 		Closeable
@@ -884,6 +898,7 @@ type (
 		ScheduleToStartTimeout time.Duration
 		Expiry                 time.Time
 		CreatedTime            time.Time
+		Priority               int
 	}
 
 	// InternalCreateTasksInfo describes a task to be created in InternalCreateTasksRequest
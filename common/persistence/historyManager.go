@@ -29,7 +29,9 @@ import (
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/codec"
+	"github.com/uber/cadence/common/compression"
 	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/encryption"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/types"
@@ -45,6 +47,9 @@ type (
 		thriftEncoder         codec.BinaryEncoder
 		pagingTokenSerializer *jsonHistoryTokenSerializer
 		transactionSizeLimit  dynamicconfig.IntPropertyFn
+		encryptor             encryption.Encryptor
+		encryptionKeyID       dynamicconfig.StringPropertyFnWithDomainFilter
+		compressionCodec      dynamicconfig.StringPropertyFnWithDomainFilter
 	}
 )
 
@@ -64,6 +69,9 @@ func NewHistoryV2ManagerImpl(
 	persistence HistoryStore,
 	logger log.Logger,
 	transactionSizeLimit dynamicconfig.IntPropertyFn,
+	encryptor encryption.Encryptor,
+	encryptionKeyID dynamicconfig.StringPropertyFnWithDomainFilter,
+	compressionCodec dynamicconfig.StringPropertyFnWithDomainFilter,
 ) HistoryManager {
 
 	return &historyV2ManagerImpl{
@@ -73,6 +81,9 @@ func NewHistoryV2ManagerImpl(
 		thriftEncoder:         codec.NewThriftRWEncoder(),
 		pagingTokenSerializer: newJSONHistoryTokenSerializer(),
 		transactionSizeLimit:  transactionSizeLimit,
+		encryptor:             encryptor,
+		encryptionKeyID:       encryptionKeyID,
+		compressionCodec:      compressionCodec,
 	}
 }
 
@@ -229,7 +240,15 @@ func (m *historyV2ManagerImpl) AppendHistoryNodes(
 	if err != nil {
 		return nil, err
 	}
-	size := len(blob.Data)
+	storedBlob, err := m.compressBlob(request.DomainName, blob)
+	if err != nil {
+		return nil, err
+	}
+	storedBlob, err = m.encryptBlob(ctx, request.DomainName, storedBlob)
+	if err != nil {
+		return nil, err
+	}
+	size := len(storedBlob.Data)
 	sizeLimit := m.transactionSizeLimit()
 	if size > sizeLimit {
 		return nil, &TransactionSizeLimitError{
@@ -248,7 +267,7 @@ func (m *historyV2ManagerImpl) AppendHistoryNodes(
 		Info:          request.Info,
 		BranchInfo:    *thrift.ToHistoryBranch(&branch),
 		NodeID:        nodeID,
-		Events:        blob,
+		Events:        storedBlob,
 		TransactionID: request.TransactionID,
 		ShardID:       shardID,
 	}
@@ -427,10 +446,19 @@ func (m *historyV2ManagerImpl) readRawHistoryBranch(
 		return nil, nil, 0, nil, &types.EntityNotExistsError{Message: "Workflow execution history not found."}
 	}
 
-	dataBlobs := resp.History
+	dataBlobs := make([]*DataBlob, 0, len(resp.History))
 	dataSize := 0
 	for _, dataBlob := range resp.History {
 		dataSize += len(dataBlob.Data)
+		dataBlob, err = m.decryptBlob(ctx, dataBlob)
+		if err != nil {
+			return nil, nil, 0, nil, err
+		}
+		dataBlob, err = m.decompressBlob(dataBlob)
+		if err != nil {
+			return nil, nil, 0, nil, err
+		}
+		dataBlobs = append(dataBlobs, dataBlob)
 	}
 
 	token.StoreToken = resp.NextPageToken
@@ -531,6 +559,90 @@ func (m *historyV2ManagerImpl) readHistoryBranch(
 	return historyEvents, historyEventBatches, nextPageToken, dataSize, lastFirstEventID, nil
 }
 
+// encryptBlob encrypts the blob if an encryption key is set for the domain, the inner encoding is kept in the envelope
+func (m *historyV2ManagerImpl) encryptBlob(
+	ctx context.Context,
+	domainName string,
+	blob *DataBlob,
+) (*DataBlob, error) {
+
+	if m.encryptor == nil || m.encryptionKeyID == nil {
+		return blob, nil
+	}
+	keyID := m.encryptionKeyID(domainName)
+	if keyID == "" {
+		return blob, nil
+	}
+	data, err := m.encryptor.Encrypt(ctx, keyID, []byte(blob.Encoding), blob.Data)
+	if err != nil {
+		m.logger.Error("failed to encrypt history events", tag.WorkflowDomainName(domainName), tag.Error(err))
+		return nil, &types.InternalServiceError{Message: fmt.Sprintf("failed to encrypt history events: %v", err)}
+	}
+	return &DataBlob{Encoding: common.EncodingTypeEncrypted, Data: data}, nil
+}
+
+// decryptBlob decrypts the blob if it was encrypted, so that the callers only see the inner encoding
+func (m *historyV2ManagerImpl) decryptBlob(
+	ctx context.Context,
+	blob *DataBlob,
+) (*DataBlob, error) {
+
+	if blob == nil || blob.Encoding != common.EncodingTypeEncrypted {
+		return blob, nil
+	}
+	if m.encryptor == nil {
+		return nil, &types.InternalServiceError{Message: "history events are encrypted but no encryption is configured"}
+	}
+	encoding, data, err := m.encryptor.Decrypt(ctx, blob.Data)
+	if err != nil {
+		m.logger.Error("failed to decrypt history events", tag.Error(err))
+		return nil, &types.InternalServiceError{Message: fmt.Sprintf("failed to decrypt history events: %v", err)}
+	}
+	return &DataBlob{Encoding: common.EncodingType(encoding), Data: data}, nil
+}
+
+// compressBlob compresses the blob with the codec set for the domain, the inner encoding is kept in the frame
+func (m *historyV2ManagerImpl) compressBlob(
+	domainName string,
+	blob *DataBlob,
+) (*DataBlob, error) {
+
+	if m.compressionCodec == nil {
+		return blob, nil
+	}
+	codec := m.compressionCodec(domainName)
+	if codec == "" {
+		return blob, nil
+	}
+	if !compression.IsSupportedCodec(codec) {
+		m.logger.Warn("unsupported history compression codec, history events are stored uncompressed",
+			tag.WorkflowDomainName(domainName), tag.Value(codec))
+		return blob, nil
+	}
+	data, err := compression.Compress([]byte(blob.Encoding), blob.Data)
+	if err != nil {
+		m.logger.Error("failed to compress history events", tag.WorkflowDomainName(domainName), tag.Error(err))
+		return nil, &types.InternalServiceError{Message: fmt.Sprintf("failed to compress history events: %v", err)}
+	}
+	return &DataBlob{Encoding: common.EncodingTypeCompressed, Data: data}, nil
+}
+
+// decompressBlob decompresses the blob if it was compressed, so that the callers only see the inner encoding
+func (m *historyV2ManagerImpl) decompressBlob(
+	blob *DataBlob,
+) (*DataBlob, error) {
+
+	if blob == nil || blob.Encoding != common.EncodingTypeCompressed {
+		return blob, nil
+	}
+	encoding, data, err := compression.Decompress(blob.Data)
+	if err != nil {
+		m.logger.Error("failed to decompress history events", tag.Error(err))
+		return nil, &types.InternalDataInconsistencyError{Message: fmt.Sprintf("failed to decompress history events: %v", err)}
+	}
+	return &DataBlob{Encoding: common.EncodingType(encoding), Data: data}, nil
+}
+
 func (m *historyV2ManagerImpl) deserializeToken(
 	token []byte,
 	defaultLastEventID int64,
@@ -0,0 +1,183 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package persistence
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/compression"
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/encryption"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/types"
+)
+
+// inMemoryHistoryStore keeps the appended nodes of a single branch
+type inMemoryHistoryStore struct {
+	HistoryStore
+	nodes []*DataBlob
+}
+
+func (s *inMemoryHistoryStore) AppendHistoryNodes(_ context.Context, request *InternalAppendHistoryNodesRequest) error {
+	s.nodes = append(s.nodes, request.Events)
+	return nil
+}
+
+func (s *inMemoryHistoryStore) ReadHistoryBranch(_ context.Context, _ *InternalReadHistoryBranchRequest) (*InternalReadHistoryBranchResponse, error) {
+	return &InternalReadHistoryBranchResponse{History: s.nodes}, nil
+}
+
+func TestHistoryManager_Encryption(t *testing.T) {
+	provider, err := encryption.NewLocalProvider(&config.LocalEncryption{
+		Keys: map[string]string{"key1": base64.StdEncoding.EncodeToString(make([]byte, 32))},
+	})
+	require.NoError(t, err)
+	encryptor := encryption.NewEncryptorWithProvider(provider, 0, 0, clock.NewRealTimeSource())
+	keyIDs := map[string]string{"encrypted-domain": "key1"}
+	keyID := func(domain string) string { return keyIDs[domain] }
+
+	for domain, expectedEncoding := range map[string]common.EncodingType{
+		"encrypted-domain": common.EncodingTypeEncrypted,
+		"plain-domain":     common.EncodingTypeThriftRW,
+	} {
+		t.Run(domain, func(t *testing.T) {
+			store := &inMemoryHistoryStore{}
+			manager := NewHistoryV2ManagerImpl(store, loggerimpl.NewNopLogger(), dynamicconfig.GetIntPropertyFn(1024*1024), encryptor, keyID, nil)
+			branchToken, err := NewHistoryBranchToken("tree")
+			require.NoError(t, err)
+
+			events := []*types.HistoryEvent{{
+				ID:        1,
+				Version:   1,
+				EventType: types.EventTypeWorkflowExecutionStarted.Ptr(),
+			}}
+			appendResponse, err := manager.AppendHistoryNodes(context.Background(), &AppendHistoryNodesRequest{
+				IsNewBranch: true,
+				BranchToken: branchToken,
+				Events:      events,
+				Encoding:    common.EncodingTypeThriftRW,
+				ShardID:     common.IntPtr(1),
+				DomainName:  domain,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, common.EncodingTypeThriftRW, appendResponse.DataBlob.Encoding)
+			require.Len(t, store.nodes, 1)
+			assert.Equal(t, expectedEncoding, store.nodes[0].Encoding)
+
+			readRequest := &ReadHistoryBranchRequest{
+				BranchToken: branchToken,
+				MinEventID:  1,
+				MaxEventID:  2,
+				PageSize:    10,
+				ShardID:     common.IntPtr(1),
+			}
+			readResponse, err := manager.ReadHistoryBranch(context.Background(), readRequest)
+			require.NoError(t, err)
+			assert.Equal(t, events, readResponse.HistoryEvents)
+
+			rawResponse, err := manager.ReadRawHistoryBranch(context.Background(), readRequest)
+			require.NoError(t, err)
+			require.Len(t, rawResponse.HistoryEventBlobs, 1)
+			assert.Equal(t, appendResponse.DataBlob, *rawResponse.HistoryEventBlobs[0])
+
+			// the encrypted history cannot be read without encryption
+			manager = NewHistoryV2ManagerImpl(store, loggerimpl.NewNopLogger(), dynamicconfig.GetIntPropertyFn(1024*1024), nil, nil, nil)
+			_, err = manager.ReadHistoryBranch(context.Background(), readRequest)
+			assert.Equal(t, expectedEncoding == common.EncodingTypeEncrypted, err != nil)
+		})
+	}
+}
+
+func TestHistoryManager_Compression(t *testing.T) {
+	provider, err := encryption.NewLocalProvider(&config.LocalEncryption{
+		Keys: map[string]string{"key1": base64.StdEncoding.EncodeToString(make([]byte, 32))},
+	})
+	require.NoError(t, err)
+	encryptor := encryption.NewEncryptorWithProvider(provider, 0, 0, clock.NewRealTimeSource())
+	keyIDs := map[string]string{"compressed-encrypted-domain": "key1"}
+	keyID := func(domain string) string { return keyIDs[domain] }
+	codecs := map[string]string{
+		"compressed-domain":           compression.CodecZstd,
+		"compressed-encrypted-domain": compression.CodecZstd,
+		"unknown-codec-domain":        "lz4",
+	}
+	codec := func(domain string) string { return codecs[domain] }
+
+	for domain, expectedEncoding := range map[string]common.EncodingType{
+		"compressed-domain":           common.EncodingTypeCompressed,
+		"compressed-encrypted-domain": common.EncodingTypeEncrypted,
+		"unknown-codec-domain":        common.EncodingTypeThriftRW,
+		"plain-domain":                common.EncodingTypeThriftRW,
+	} {
+		t.Run(domain, func(t *testing.T) {
+			store := &inMemoryHistoryStore{}
+			manager := NewHistoryV2ManagerImpl(store, loggerimpl.NewNopLogger(), dynamicconfig.GetIntPropertyFn(1024*1024), encryptor, keyID, codec)
+			branchToken, err := NewHistoryBranchToken("tree")
+			require.NoError(t, err)
+
+			events := []*types.HistoryEvent{{
+				ID:        1,
+				Version:   1,
+				EventType: types.EventTypeWorkflowExecutionStarted.Ptr(),
+			}}
+			appendResponse, err := manager.AppendHistoryNodes(context.Background(), &AppendHistoryNodesRequest{
+				IsNewBranch: true,
+				BranchToken: branchToken,
+				Events:      events,
+				Encoding:    common.EncodingTypeThriftRW,
+				ShardID:     common.IntPtr(1),
+				DomainName:  domain,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, common.EncodingTypeThriftRW, appendResponse.DataBlob.Encoding)
+			require.Len(t, store.nodes, 1)
+			assert.Equal(t, expectedEncoding, store.nodes[0].Encoding)
+
+			readRequest := &ReadHistoryBranchRequest{
+				BranchToken: branchToken,
+				MinEventID:  1,
+				MaxEventID:  2,
+				PageSize:    10,
+				ShardID:     common.IntPtr(1),
+			}
+			rawResponse, err := manager.ReadRawHistoryBranch(context.Background(), readRequest)
+			require.NoError(t, err)
+			require.Len(t, rawResponse.HistoryEventBlobs, 1)
+			assert.Equal(t, appendResponse.DataBlob, *rawResponse.HistoryEventBlobs[0])
+
+			// the compressed history can still be read once compression is turned off
+			manager = NewHistoryV2ManagerImpl(store, loggerimpl.NewNopLogger(), dynamicconfig.GetIntPropertyFn(1024*1024), encryptor, keyID, nil)
+			readResponse, err := manager.ReadHistoryBranch(context.Background(), readRequest)
+			require.NoError(t, err)
+			assert.Equal(t, events, readResponse.HistoryEvents)
+		})
+	}
+}
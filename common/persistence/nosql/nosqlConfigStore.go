@@ -23,6 +23,7 @@ package nosql
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
@@ -72,3 +73,35 @@ func (m *nosqlConfigStore) UpdateConfig(ctx context.Context, value *persistence.
 	}
 	return nil
 }
+
+func (m *nosqlConfigStore) ListConfigs(
+	ctx context.Context,
+	configType persistence.ConfigType,
+	maxVersion int64,
+	pageSize int,
+) ([]*persistence.InternalConfigStoreEntry, error) {
+	entries, err := m.db.SelectConfigs(ctx, int(configType), maxVersion, pageSize)
+	if err != nil {
+		return nil, convertCommonErrors(m.db, "ListConfigs", err)
+	}
+	return entries, nil
+}
+
+func (m *nosqlConfigStore) InsertDomainUsage(
+	ctx context.Context,
+	report *persistence.InternalDomainUsageReport,
+	ttl time.Duration,
+) error {
+	if err := m.db.InsertDomainUsage(ctx, report, ttl); err != nil {
+		return convertCommonErrors(m.db, "InsertDomainUsage", err)
+	}
+	return nil
+}
+
+func (m *nosqlConfigStore) ListDomainUsage(ctx context.Context, hour time.Time) ([]*persistence.InternalDomainUsageReport, error) {
+	reports, err := m.db.SelectDomainUsage(ctx, hour)
+	if err != nil {
+		return nil, convertCommonErrors(m.db, "ListDomainUsage", err)
+	}
+	return reports, nil
+}
@@ -123,13 +123,16 @@ func (t *nosqlTaskStore) LeaseTaskList(
 		currTL.RangeID++
 
 		err = t.db.UpdateTaskList(ctx, &nosqlplugin.TaskListRow{
-			DomainID:        request.DomainID,
-			TaskListName:    request.TaskList,
-			TaskListType:    request.TaskType,
-			RangeID:         currTL.RangeID,
-			TaskListKind:    currTL.TaskListKind,
-			AckLevel:        currTL.AckLevel,
-			LastUpdatedTime: now,
+			DomainID:                request.DomainID,
+			TaskListName:            request.TaskList,
+			TaskListType:            request.TaskType,
+			RangeID:                 currTL.RangeID,
+			TaskListKind:            currTL.TaskListKind,
+			AckLevel:                currTL.AckLevel,
+			LastUpdatedTime:         now,
+			ApproximateBacklogCount: currTL.ApproximateBacklogCount,
+			OldestTaskCreatedTime:   currTL.OldestTaskCreatedTime,
+			Paused:                  currTL.Paused,
 		}, currTL.RangeID-1)
 	}
 	if err != nil {
@@ -143,17 +146,46 @@ func (t *nosqlTaskStore) LeaseTaskList(
 		return nil, convertCommonErrors(t.db, "LeaseTaskList", err)
 	}
 	tli := &p.TaskListInfo{
-		DomainID:    request.DomainID,
-		Name:        request.TaskList,
-		TaskType:    request.TaskType,
-		RangeID:     currTL.RangeID,
-		AckLevel:    currTL.AckLevel,
-		Kind:        request.TaskListKind,
-		LastUpdated: now,
+		DomainID:                request.DomainID,
+		Name:                    request.TaskList,
+		TaskType:                request.TaskType,
+		RangeID:                 currTL.RangeID,
+		AckLevel:                currTL.AckLevel,
+		Kind:                    request.TaskListKind,
+		LastUpdated:             now,
+		ApproximateBacklogCount: currTL.ApproximateBacklogCount,
+		OldestTaskCreatedTime:   currTL.OldestTaskCreatedTime,
+		Paused:                  currTL.Paused,
 	}
 	return &p.LeaseTaskListResponse{TaskListInfo: tli}, nil
 }
 
+func (t *nosqlTaskStore) GetTaskList(
+	ctx context.Context,
+	request *p.GetTaskListRequest,
+) (*p.GetTaskListResponse, error) {
+	row, err := t.db.SelectTaskList(ctx, &nosqlplugin.TaskListFilter{
+		DomainID:     request.DomainID,
+		TaskListName: request.TaskList,
+		TaskListType: request.TaskType,
+	})
+	if err != nil {
+		return nil, convertCommonErrors(t.db, "GetTaskList", err)
+	}
+	return &p.GetTaskListResponse{TaskListInfo: &p.TaskListInfo{
+		DomainID:                row.DomainID,
+		Name:                    row.TaskListName,
+		TaskType:                row.TaskListType,
+		RangeID:                 row.RangeID,
+		AckLevel:                row.AckLevel,
+		Kind:                    row.TaskListKind,
+		LastUpdated:             row.LastUpdatedTime,
+		ApproximateBacklogCount: row.ApproximateBacklogCount,
+		OldestTaskCreatedTime:   row.OldestTaskCreatedTime,
+		Paused:                  row.Paused,
+	}}, nil
+}
+
 func (t *nosqlTaskStore) UpdateTaskList(
 	ctx context.Context,
 	request *p.UpdateTaskListRequest,
@@ -161,13 +193,16 @@ func (t *nosqlTaskStore) UpdateTaskList(
 	tli := request.TaskListInfo
 	var err error
 	taskListToUpdate := &nosqlplugin.TaskListRow{
-		DomainID:        tli.DomainID,
-		TaskListName:    tli.Name,
-		TaskListType:    tli.TaskType,
-		RangeID:         tli.RangeID,
-		TaskListKind:    tli.Kind,
-		AckLevel:        tli.AckLevel,
-		LastUpdatedTime: time.Now(),
+		DomainID:                tli.DomainID,
+		TaskListName:            tli.Name,
+		TaskListType:            tli.TaskType,
+		RangeID:                 tli.RangeID,
+		TaskListKind:            tli.Kind,
+		AckLevel:                tli.AckLevel,
+		LastUpdatedTime:         time.Now(),
+		ApproximateBacklogCount: tli.ApproximateBacklogCount,
+		OldestTaskCreatedTime:   tli.OldestTaskCreatedTime,
+		Paused:                  tli.Paused,
 	}
 
 	if tli.Kind == p.TaskListKindSticky { // if task_list is sticky, then update with TTL
@@ -239,6 +274,7 @@ func (t *nosqlTaskStore) CreateTasks(
 			RunID:        t.Execution.GetRunID(),
 			ScheduledID:  t.Data.ScheduleID,
 			CreatedTime:  now,
+			Priority:     t.Data.Priority,
 		}
 		ttl := int(t.Data.ScheduleToStartTimeout.Seconds())
 		tasks = append(tasks, &nosqlplugin.TaskRowForInsert{
@@ -272,6 +308,10 @@ func toTaskListRow(info *p.TaskListInfo) *nosqlplugin.TaskListRow {
 		RangeID:         info.RangeID,
 		AckLevel:        info.AckLevel,
 		LastUpdatedTime: info.LastUpdated,
+
+		ApproximateBacklogCount: info.ApproximateBacklogCount,
+		OldestTaskCreatedTime:   info.OldestTaskCreatedTime,
+		Paused:                  info.Paused,
 	}
 }
 
@@ -319,6 +359,7 @@ func toTaskInfo(t *nosqlplugin.TaskRow) *p.InternalTaskInfo {
 		TaskID:      t.TaskID,
 		ScheduleID:  t.ScheduledID,
 		CreatedTime: t.CreatedTime,
+		Priority:    t.Priority,
 	}
 }
 
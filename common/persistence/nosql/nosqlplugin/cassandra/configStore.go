@@ -22,6 +22,8 @@ package cassandra
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/gocql/gocql"
@@ -35,7 +37,13 @@ const (
 	// version is the clustering key(DESC order) so this query will always return the record with largest version
 	templateSelectLatestConfig = `SELECT row_type, version, timestamp, values, encoding FROM cluster_config WHERE row_type = ? LIMIT 1;`
 
+	templateSelectConfigs = `SELECT row_type, version, timestamp, values, encoding FROM cluster_config WHERE row_type = ? AND version <= ? LIMIT ?;`
+
 	templateInsertConfig = `INSERT INTO cluster_config (row_type, version, timestamp, values, encoding) VALUES (?, ?, ?, ?, ?) IF NOT EXISTS;`
+
+	templateInsertDomainUsage = `INSERT INTO domain_usage (report_hour, report_id, values, encoding) VALUES (?, ?, ?, ?) USING TTL ?;`
+
+	templateSelectDomainUsage = `SELECT report_id, values, encoding FROM domain_usage WHERE report_hour = ?;`
 )
 
 func (db *cdb) InsertConfig(ctx context.Context, row *persistence.InternalConfigStoreEntry) error {
@@ -75,3 +83,76 @@ func (db *cdb) SelectLatestConfig(ctx context.Context, rowType int) (*persistenc
 		},
 	}, err
 }
+
+func (db *cdb) SelectConfigs(ctx context.Context, rowType int, maxVersion int64, pageSize int) ([]*persistence.InternalConfigStoreEntry, error) {
+	// version is an int column, so larger max versions can't be bound but are equivalent to its max value
+	if maxVersion > math.MaxInt32 {
+		maxVersion = math.MaxInt32
+	}
+	query := db.session.Query(templateSelectConfigs, rowType, maxVersion, pageSize).WithContext(ctx)
+	iter := query.Iter()
+	if iter == nil {
+		return nil, fmt.Errorf("SelectConfigs operation failed. Not able to create query iterator")
+	}
+
+	var entries []*persistence.InternalConfigStoreEntry
+	var version int64
+	var timestamp time.Time
+	var data []byte
+	var encoding common.EncodingType
+	for iter.Scan(&rowType, &version, &timestamp, &data, &encoding) {
+		entries = append(entries, &persistence.InternalConfigStoreEntry{
+			RowType:   rowType,
+			Version:   version,
+			Timestamp: timestamp,
+			Values: &persistence.DataBlob{
+				Data:     data,
+				Encoding: encoding,
+			},
+		})
+		data = nil
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (db *cdb) InsertDomainUsage(ctx context.Context, report *persistence.InternalDomainUsageReport, ttl time.Duration) error {
+	query := db.session.Query(templateInsertDomainUsage,
+		report.Hour,
+		report.ReportID,
+		report.Values.Data,
+		report.Values.Encoding,
+		int64(ttl.Seconds()),
+	).WithContext(ctx)
+	return query.Exec()
+}
+
+func (db *cdb) SelectDomainUsage(ctx context.Context, hour time.Time) ([]*persistence.InternalDomainUsageReport, error) {
+	query := db.session.Query(templateSelectDomainUsage, hour).WithContext(ctx)
+	iter := query.Iter()
+	if iter == nil {
+		return nil, fmt.Errorf("SelectDomainUsage operation failed. Not able to create query iterator")
+	}
+
+	var reports []*persistence.InternalDomainUsageReport
+	var reportID string
+	var data []byte
+	var encoding common.EncodingType
+	for iter.Scan(&reportID, &data, &encoding) {
+		reports = append(reports, &persistence.InternalDomainUsageReport{
+			Hour:     hour,
+			ReportID: reportID,
+			Values: &persistence.DataBlob{
+				Data:     data,
+				Encoding: encoding,
+			},
+		})
+		data = nil
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/gocql/gocql"
 
+	"github.com/uber/cadence/common/certificate"
 	"github.com/uber/cadence/environment"
 )
 
@@ -50,7 +51,7 @@ func RegisterClient(c Client) {
 	}
 }
 
-func newCassandraCluster(cfg ClusterConfig) *gocql.ClusterConfig {
+func newCassandraCluster(cfg ClusterConfig) (*gocql.ClusterConfig, error) {
 	hosts := parseHosts(cfg.Hosts)
 	cluster := gocql.NewCluster(hosts...)
 	if cfg.ProtoVersion == 0 {
@@ -78,15 +79,22 @@ func newCassandraCluster(cfg ClusterConfig) *gocql.ClusterConfig {
 	}
 
 	if cfg.TLS != nil && cfg.TLS.Enabled {
+		tlsConfig := &tls.Config{
+			ServerName: cfg.TLS.ServerName,
+		}
+		if cfg.TLS.CertFile != "" && cfg.TLS.KeyFile != "" {
+			// the client certificate is reloaded when its files change, so that it can be rotated without a restart
+			keyPair, err := certificate.LoadKeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.GetClientCertificate = keyPair.GetClientCertificate
+		}
 		cluster.SslOpts = &gocql.SslOptions{
-			CertPath:               cfg.TLS.CertFile,
-			KeyPath:                cfg.TLS.KeyFile,
 			CaPath:                 cfg.TLS.CaFile,
 			EnableHostVerification: cfg.TLS.EnableHostVerification,
 
-			Config: &tls.Config{
-				ServerName: cfg.TLS.ServerName,
-			},
+			Config: tlsConfig,
 		}
 	}
 	if cfg.MaxConns > 0 {
@@ -95,7 +103,7 @@ func newCassandraCluster(cfg ClusterConfig) *gocql.ClusterConfig {
 
 	cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(gocql.RoundRobinHostPolicy())
 
-	return cluster
+	return cluster, nil
 }
 
 // regionHostFilter returns a gocql host filter for the given region name
@@ -52,7 +52,10 @@ const (
 		`type: ?, ` +
 		`ack_level: ?, ` +
 		`kind: ?, ` +
-		`last_updated: ? ` +
+		`last_updated: ?, ` +
+		`approximate_backlog_count: ?, ` +
+		`oldest_task_created_time: ?, ` +
+		`paused: ? ` +
 		`}`
 
 	templateTaskType = `{` +
@@ -60,7 +63,8 @@ const (
 		`workflow_id: ?, ` +
 		`run_id: ?, ` +
 		`schedule_id: ?,` +
-		`created_time: ? ` +
+		`created_time: ?, ` +
+		`priority: ? ` +
 		`}`
 
 	templateCreateTaskQuery = `INSERT INTO tasks (` +
@@ -164,6 +168,10 @@ func (db *cdb) SelectTaskList(ctx context.Context, filter *nosqlplugin.TaskListF
 	ackLevel := tlDB["ack_level"].(int64)
 	taskListKind := tlDB["kind"].(int)
 	lastUpdatedTime := tlDB["last_updated"].(time.Time)
+	// the backlog snapshot is missing from the rows written before it was introduced
+	backlogCount, _ := tlDB["approximate_backlog_count"].(int64)
+	oldestTaskCreatedTime, _ := tlDB["oldest_task_created_time"].(time.Time)
+	paused, _ := tlDB["paused"].(bool)
 
 	return &nosqlplugin.TaskListRow{
 		DomainID:     filter.DomainID,
@@ -174,6 +182,10 @@ func (db *cdb) SelectTaskList(ctx context.Context, filter *nosqlplugin.TaskListF
 		LastUpdatedTime: lastUpdatedTime,
 		AckLevel:        ackLevel,
 		RangeID:         rangeID,
+
+		ApproximateBacklogCount: backlogCount,
+		OldestTaskCreatedTime:   oldestTaskCreatedTime,
+		Paused:                  paused,
 	}, nil
 }
 
@@ -193,6 +205,9 @@ func (db *cdb) InsertTaskList(ctx context.Context, row *nosqlplugin.TaskListRow)
 		0,
 		row.TaskListKind,
 		row.LastUpdatedTime,
+		row.ApproximateBacklogCount,
+		row.OldestTaskCreatedTime,
+		row.Paused,
 	).WithContext(ctx)
 
 	previous := make(map[string]interface{})
@@ -219,6 +234,9 @@ func (db *cdb) UpdateTaskList(
 		row.AckLevel,
 		row.TaskListKind,
 		row.LastUpdatedTime,
+		row.ApproximateBacklogCount,
+		row.OldestTaskCreatedTime,
+		row.Paused,
 		row.DomainID,
 		row.TaskListName,
 		row.TaskListType,
@@ -282,6 +300,9 @@ func (db *cdb) UpdateTaskListWithTTL(
 		row.AckLevel,
 		row.TaskListKind,
 		time.Now(),
+		row.ApproximateBacklogCount,
+		row.OldestTaskCreatedTime,
+		row.Paused,
 		row.DomainID,
 		row.TaskListName,
 		row.TaskListType,
@@ -359,7 +380,8 @@ func (db *cdb) InsertTasks(
 				task.WorkflowID,
 				task.RunID,
 				scheduleID,
-				task.CreatedTime)
+				task.CreatedTime,
+				task.Priority)
 		} else {
 			if ttl > maxCassandraTTL {
 				ttl = maxCassandraTTL
@@ -375,6 +397,7 @@ func (db *cdb) InsertTasks(
 				task.RunID,
 				scheduleID,
 				task.CreatedTime,
+				task.Priority,
 				ttl)
 		}
 	}
@@ -388,6 +411,9 @@ func (db *cdb) InsertTasks(
 		ackLevel,
 		taskListKind,
 		time.Now(),
+		tasklistCondition.ApproximateBacklogCount,
+		tasklistCondition.OldestTaskCreatedTime,
+		tasklistCondition.Paused,
 		domainID,
 		taskListName,
 		taskListType,
@@ -462,6 +488,8 @@ func createTaskInfo(
 			info.ScheduledID = v.(int64)
 		case "created_time":
 			info.CreatedTime = v.(time.Time)
+		case "priority":
+			info.Priority = v.(int)
 		}
 	}
 
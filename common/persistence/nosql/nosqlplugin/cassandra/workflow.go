@@ -671,7 +671,7 @@ func (db *cdb) InsertReplicationDLQTask(ctx context.Context, shardID int, source
 		task.BranchToken,
 		p.EventStoreVersion,
 		task.NewRunBranchToken,
-		defaultVisibilityTimestamp,
+		task.CreationTime.UnixNano(),
 		defaultVisibilityTimestamp,
 		task.TaskID,
 	).WithContext(ctx)
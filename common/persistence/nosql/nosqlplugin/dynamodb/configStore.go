@@ -23,6 +23,7 @@ package dynamodb
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/uber/cadence/common/persistence"
 )
@@ -34,3 +35,15 @@ func (db *ddb) InsertConfig(ctx context.Context, row *persistence.InternalConfig
 func (db *ddb) SelectLatestConfig(ctx context.Context, rowType int) (*persistence.InternalConfigStoreEntry, error) {
 	return nil, errors.New("TODO")
 }
+
+func (db *ddb) SelectConfigs(ctx context.Context, rowType int, maxVersion int64, pageSize int) ([]*persistence.InternalConfigStoreEntry, error) {
+	return nil, errors.New("TODO")
+}
+
+func (db *ddb) InsertDomainUsage(ctx context.Context, report *persistence.InternalDomainUsageReport, ttl time.Duration) error {
+	return errors.New("TODO")
+}
+
+func (db *ddb) SelectDomainUsage(ctx context.Context, hour time.Time) ([]*persistence.InternalDomainUsageReport, error) {
+	return nil, errors.New("TODO")
+}
@@ -527,5 +527,11 @@ type (
 		InsertConfig(ctx context.Context, row *persistence.InternalConfigStoreEntry) error
 		// SelectLatestConfig returns the config entry of the row_type with the largest(latest) version value
 		SelectLatestConfig(ctx context.Context, rowType int) (*persistence.InternalConfigStoreEntry, error)
+		// SelectConfigs returns up to pageSize config entries of the row_type with version <= maxVersion, sorted by version in descending order
+		SelectConfigs(ctx context.Context, rowType int, maxVersion int64, pageSize int) ([]*persistence.InternalConfigStoreEntry, error)
+		// InsertDomainUsage inserts a domain usage report which expires after ttl, zero ttl means it never expires
+		InsertDomainUsage(ctx context.Context, report *persistence.InternalDomainUsageReport, ttl time.Duration) error
+		// SelectDomainUsage returns the domain usage reports of the hour
+		SelectDomainUsage(ctx context.Context, hour time.Time) ([]*persistence.InternalDomainUsageReport, error)
 	}
 )
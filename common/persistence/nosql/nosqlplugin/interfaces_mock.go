@@ -416,6 +416,20 @@ func (mr *MockDBMockRecorder) InsertDomain(ctx, row interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDomain", reflect.TypeOf((*MockDB)(nil).InsertDomain), ctx, row)
 }
 
+// InsertDomainUsage mocks base method.
+func (m *MockDB) InsertDomainUsage(ctx context.Context, report *persistence.InternalDomainUsageReport, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDomainUsage", ctx, report, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertDomainUsage indicates an expected call of InsertDomainUsage.
+func (mr *MockDBMockRecorder) InsertDomainUsage(ctx, report, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDomainUsage", reflect.TypeOf((*MockDB)(nil).InsertDomainUsage), ctx, report, ttl)
+}
+
 // InsertIntoHistoryTreeAndNode mocks base method.
 func (m *MockDB) InsertIntoHistoryTreeAndNode(ctx context.Context, treeRow *HistoryTreeRow, nodeRow *HistoryNodeRow) error {
 	m.ctrl.T.Helper()
@@ -805,6 +819,21 @@ func (mr *MockDBMockRecorder) SelectAllWorkflowExecutions(ctx, shardID, pageToke
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAllWorkflowExecutions", reflect.TypeOf((*MockDB)(nil).SelectAllWorkflowExecutions), ctx, shardID, pageToken, pageSize)
 }
 
+// SelectConfigs mocks base method.
+func (m *MockDB) SelectConfigs(ctx context.Context, rowType int, maxVersion int64, pageSize int) ([]*persistence.InternalConfigStoreEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectConfigs", ctx, rowType, maxVersion, pageSize)
+	ret0, _ := ret[0].([]*persistence.InternalConfigStoreEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectConfigs indicates an expected call of SelectConfigs.
+func (mr *MockDBMockRecorder) SelectConfigs(ctx, rowType, maxVersion, pageSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectConfigs", reflect.TypeOf((*MockDB)(nil).SelectConfigs), ctx, rowType, maxVersion, pageSize)
+}
+
 // SelectCrossClusterTasksOrderByTaskID mocks base method.
 func (m *MockDB) SelectCrossClusterTasksOrderByTaskID(ctx context.Context, shardID, pageSize int, pageToken []byte, targetCluster string, exclusiveMinTaskID, inclusiveMaxTaskID int64) ([]*CrossClusterTask, []byte, error) {
 	m.ctrl.T.Helper()
@@ -866,6 +895,21 @@ func (mr *MockDBMockRecorder) SelectDomainMetadata(ctx interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDomainMetadata", reflect.TypeOf((*MockDB)(nil).SelectDomainMetadata), ctx)
 }
 
+// SelectDomainUsage mocks base method.
+func (m *MockDB) SelectDomainUsage(ctx context.Context, hour time.Time) ([]*persistence.InternalDomainUsageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDomainUsage", ctx, hour)
+	ret0, _ := ret[0].([]*persistence.InternalDomainUsageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDomainUsage indicates an expected call of SelectDomainUsage.
+func (mr *MockDBMockRecorder) SelectDomainUsage(ctx, hour interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDomainUsage", reflect.TypeOf((*MockDB)(nil).SelectDomainUsage), ctx, hour)
+}
+
 // SelectFromHistoryNode mocks base method.
 func (m *MockDB) SelectFromHistoryNode(ctx context.Context, filter *HistoryNodeFilter) ([]*HistoryNodeRow, []byte, error) {
 	m.ctrl.T.Helper()
@@ -1516,6 +1560,20 @@ func (mr *MocktableCRUDMockRecorder) InsertDomain(ctx, row interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDomain", reflect.TypeOf((*MocktableCRUD)(nil).InsertDomain), ctx, row)
 }
 
+// InsertDomainUsage mocks base method.
+func (m *MocktableCRUD) InsertDomainUsage(ctx context.Context, report *persistence.InternalDomainUsageReport, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDomainUsage", ctx, report, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertDomainUsage indicates an expected call of InsertDomainUsage.
+func (mr *MocktableCRUDMockRecorder) InsertDomainUsage(ctx, report, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDomainUsage", reflect.TypeOf((*MocktableCRUD)(nil).InsertDomainUsage), ctx, report, ttl)
+}
+
 // InsertIntoHistoryTreeAndNode mocks base method.
 func (m *MocktableCRUD) InsertIntoHistoryTreeAndNode(ctx context.Context, treeRow *HistoryTreeRow, nodeRow *HistoryNodeRow) error {
 	m.ctrl.T.Helper()
@@ -1835,6 +1893,21 @@ func (mr *MocktableCRUDMockRecorder) SelectAllWorkflowExecutions(ctx, shardID, p
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAllWorkflowExecutions", reflect.TypeOf((*MocktableCRUD)(nil).SelectAllWorkflowExecutions), ctx, shardID, pageToken, pageSize)
 }
 
+// SelectConfigs mocks base method.
+func (m *MocktableCRUD) SelectConfigs(ctx context.Context, rowType int, maxVersion int64, pageSize int) ([]*persistence.InternalConfigStoreEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectConfigs", ctx, rowType, maxVersion, pageSize)
+	ret0, _ := ret[0].([]*persistence.InternalConfigStoreEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectConfigs indicates an expected call of SelectConfigs.
+func (mr *MocktableCRUDMockRecorder) SelectConfigs(ctx, rowType, maxVersion, pageSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectConfigs", reflect.TypeOf((*MocktableCRUD)(nil).SelectConfigs), ctx, rowType, maxVersion, pageSize)
+}
+
 // SelectCrossClusterTasksOrderByTaskID mocks base method.
 func (m *MocktableCRUD) SelectCrossClusterTasksOrderByTaskID(ctx context.Context, shardID, pageSize int, pageToken []byte, targetCluster string, exclusiveMinTaskID, inclusiveMaxTaskID int64) ([]*CrossClusterTask, []byte, error) {
 	m.ctrl.T.Helper()
@@ -1896,6 +1969,21 @@ func (mr *MocktableCRUDMockRecorder) SelectDomainMetadata(ctx interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDomainMetadata", reflect.TypeOf((*MocktableCRUD)(nil).SelectDomainMetadata), ctx)
 }
 
+// SelectDomainUsage mocks base method.
+func (m *MocktableCRUD) SelectDomainUsage(ctx context.Context, hour time.Time) ([]*persistence.InternalDomainUsageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDomainUsage", ctx, hour)
+	ret0, _ := ret[0].([]*persistence.InternalDomainUsageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDomainUsage indicates an expected call of SelectDomainUsage.
+func (mr *MocktableCRUDMockRecorder) SelectDomainUsage(ctx, hour interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDomainUsage", reflect.TypeOf((*MocktableCRUD)(nil).SelectDomainUsage), ctx, hour)
+}
+
 // SelectFromHistoryNode mocks base method.
 func (m *MocktableCRUD) SelectFromHistoryNode(ctx context.Context, filter *HistoryNodeFilter) ([]*HistoryNodeRow, []byte, error) {
 	m.ctrl.T.Helper()
@@ -3539,6 +3627,50 @@ func (mr *MockConfigStoreCRUDMockRecorder) InsertConfig(ctx, row interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertConfig", reflect.TypeOf((*MockConfigStoreCRUD)(nil).InsertConfig), ctx, row)
 }
 
+// InsertDomainUsage mocks base method.
+func (m *MockConfigStoreCRUD) InsertDomainUsage(ctx context.Context, report *persistence.InternalDomainUsageReport, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDomainUsage", ctx, report, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertDomainUsage indicates an expected call of InsertDomainUsage.
+func (mr *MockConfigStoreCRUDMockRecorder) InsertDomainUsage(ctx, report, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDomainUsage", reflect.TypeOf((*MockConfigStoreCRUD)(nil).InsertDomainUsage), ctx, report, ttl)
+}
+
+// SelectConfigs mocks base method.
+func (m *MockConfigStoreCRUD) SelectConfigs(ctx context.Context, rowType int, maxVersion int64, pageSize int) ([]*persistence.InternalConfigStoreEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectConfigs", ctx, rowType, maxVersion, pageSize)
+	ret0, _ := ret[0].([]*persistence.InternalConfigStoreEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectConfigs indicates an expected call of SelectConfigs.
+func (mr *MockConfigStoreCRUDMockRecorder) SelectConfigs(ctx, rowType, maxVersion, pageSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectConfigs", reflect.TypeOf((*MockConfigStoreCRUD)(nil).SelectConfigs), ctx, rowType, maxVersion, pageSize)
+}
+
+// SelectDomainUsage mocks base method.
+func (m *MockConfigStoreCRUD) SelectDomainUsage(ctx context.Context, hour time.Time) ([]*persistence.InternalDomainUsageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDomainUsage", ctx, hour)
+	ret0, _ := ret[0].([]*persistence.InternalDomainUsageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDomainUsage indicates an expected call of SelectDomainUsage.
+func (mr *MockConfigStoreCRUDMockRecorder) SelectDomainUsage(ctx, hour interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDomainUsage", reflect.TypeOf((*MockConfigStoreCRUD)(nil).SelectDomainUsage), ctx, hour)
+}
+
 // SelectLatestConfig mocks base method.
 func (m *MockConfigStoreCRUD) SelectLatestConfig(ctx context.Context, rowType int) (*persistence.InternalConfigStoreEntry, error) {
 	m.ctrl.T.Helper()
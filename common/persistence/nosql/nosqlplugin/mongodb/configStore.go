@@ -68,3 +68,39 @@ func (db *mdb) SelectLatestConfig(ctx context.Context, rowType int) (*persistenc
 		Values:    persistence.NewDataBlob(result.Data, common.EncodingType(result.DataEncoding)),
 	}, nil
 }
+
+func (db *mdb) SelectConfigs(ctx context.Context, rowType int, maxVersion int64, pageSize int) ([]*persistence.InternalConfigStoreEntry, error) {
+	filter := bson.D{{"rowtype", rowType}, {"version", bson.D{{"$lte", maxVersion}}}}
+	queryOptions := options.FindOptions{}
+	queryOptions.SetSort(bson.D{{"version", -1}})
+	queryOptions.SetLimit(int64(pageSize))
+
+	collection := db.dbConn.Collection(cadence.ClusterConfigCollectionName)
+	cursor, err := collection.Find(ctx, filter, &queryOptions)
+	if err != nil {
+		return nil, err
+	}
+	var results []cadence.ClusterConfigCollectionEntry
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*persistence.InternalConfigStoreEntry, 0, len(results))
+	for _, result := range results {
+		entries = append(entries, &persistence.InternalConfigStoreEntry{
+			RowType:   rowType,
+			Version:   result.Version,
+			Timestamp: time.Unix(result.UnixTimestampSeconds, 0),
+			Values:    persistence.NewDataBlob(result.Data, common.EncodingType(result.DataEncoding)),
+		})
+	}
+	return entries, nil
+}
+
+func (db *mdb) InsertDomainUsage(ctx context.Context, report *persistence.InternalDomainUsageReport, ttl time.Duration) error {
+	panic("TODO")
+}
+
+func (db *mdb) SelectDomainUsage(ctx context.Context, hour time.Time) ([]*persistence.InternalDomainUsageReport, error) {
+	panic("TODO")
+}
@@ -151,6 +151,7 @@ type (
 		RunID       string
 		ScheduledID int64
 		CreatedTime time.Time
+		Priority    int
 	}
 
 	// TaskListFilter is for filtering tasklist
@@ -170,6 +171,10 @@ type (
 		TaskListKind    int
 		AckLevel        int64
 		LastUpdatedTime time.Time
+
+		ApproximateBacklogCount int64
+		OldestTaskCreatedTime   time.Time
+		Paused                  bool
 	}
 
 	// ListTaskListResult is the result of list tasklists
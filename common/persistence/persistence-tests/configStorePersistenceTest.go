@@ -24,8 +24,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
 	"os"
 	"testing"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
@@ -175,6 +177,161 @@ func (s *ConfigStorePersistenceSuite) TestFetchLatestVersionSuccess() {
 	s.Equal(int64(3), snapshot.Version)
 }
 
+func (s *ConfigStorePersistenceSuite) TestListDynamicConfigChanges() {
+	if !validDatabaseCheck(s.Config()) {
+		s.T().Skip()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	s.DefaultTestCluster.TearDownTestDatabase()
+	s.DefaultTestCluster.SetupTestDatabase()
+
+	for version := int64(1); version <= 3; version++ {
+		snapshot := generateRandomSnapshot(version)
+		err := s.ConfigStoreManager.UpdateDynamicConfig(ctx, &p.UpdateDynamicConfigRequest{
+			Snapshot: snapshot,
+			Change: &p.DynamicConfigChangeRecord{
+				Identity:  "tester",
+				Name:      "test_parameter",
+				NewValues: snapshot.Values.Entries[0].Values,
+			},
+		})
+		s.Nil(err)
+	}
+
+	resp, err := s.ConfigStoreManager.ListDynamicConfigChanges(ctx, &p.ListDynamicConfigChangesRequest{PageSize: 2})
+	s.Nil(err)
+	s.Len(resp.Changes, 2)
+	s.Equal(int64(3), resp.Changes[0].Version)
+	s.Equal(int64(2), resp.Changes[1].Version)
+	s.Equal("tester", resp.Changes[0].Identity)
+	s.Equal("test_parameter", resp.Changes[0].Name)
+	s.Len(resp.Changes[0].NewValues, 1)
+
+	// max versions out of the range of the version column of some stores must list from the latest change
+	resp, err = s.ConfigStoreManager.ListDynamicConfigChanges(ctx, &p.ListDynamicConfigChangesRequest{MaxVersion: math.MaxInt64, PageSize: 1})
+	s.Nil(err)
+	s.Len(resp.Changes, 1)
+	s.Equal(int64(3), resp.Changes[0].Version)
+
+	resp, err = s.ConfigStoreManager.ListDynamicConfigChanges(ctx, &p.ListDynamicConfigChangesRequest{MaxVersion: 1, PageSize: 2})
+	s.Nil(err)
+	s.Len(resp.Changes, 1)
+	s.Equal(int64(1), resp.Changes[0].Version)
+
+	// the change history must not affect the latest snapshot
+	snapshot, err := s.FetchDynamicConfig(ctx)
+	s.Nil(err)
+	s.Equal(int64(3), snapshot.Version)
+}
+
+func (s *ConfigStorePersistenceSuite) TestRecordDomainUsage() {
+	if !validDatabaseCheck(s.Config()) || isMongoDB(s.Config()) {
+		s.T().Skip()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	s.DefaultTestCluster.TearDownTestDatabase()
+	s.DefaultTestCluster.SetupTestDatabase()
+
+	hour := p.DomainUsageHour(time.Now())
+	for _, service := range []string{"frontend", "history"} {
+		err := s.ConfigStoreManager.RecordDomainUsage(ctx, &p.RecordDomainUsageRequest{
+			Record: &p.DomainUsageRecord{
+				Service:       service,
+				IntervalStart: hour,
+				IntervalEnd:   hour.Add(time.Minute),
+				Domains:       map[string]*p.DomainUsage{"test-domain": {Actions: 1}},
+			},
+			TTL: time.Hour * 24,
+		})
+		s.Nil(err)
+	}
+
+	// both hosts reported the same interval, so both reports are kept
+	resp, err := s.ConfigStoreManager.ListDomainUsage(ctx, &p.ListDomainUsageRequest{Hour: hour.Add(time.Minute * 30)})
+	s.Nil(err)
+	s.Len(resp.Records, 2)
+	services := []string{resp.Records[0].Service, resp.Records[1].Service}
+	s.ElementsMatch([]string{"frontend", "history"}, services)
+	s.Equal(int64(1), resp.Records[0].Domains["test-domain"].Actions)
+
+	resp, err = s.ConfigStoreManager.ListDomainUsage(ctx, &p.ListDomainUsageRequest{Hour: hour.Add(-time.Hour)})
+	s.Nil(err)
+	s.Empty(resp.Records)
+}
+
+func (s *ConfigStorePersistenceSuite) TestAPIKeys() {
+	if !validDatabaseCheck(s.Config()) {
+		s.T().Skip()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	s.DefaultTestCluster.TearDownTestDatabase()
+	s.DefaultTestCluster.SetupTestDatabase()
+
+	resp, err := s.ConfigStoreManager.FetchAPIKeys(ctx)
+	s.Nil(err)
+	s.Nil(resp.Snapshot)
+
+	snapshot := &p.APIKeySnapshot{
+		Version: 1,
+		Keys: []*p.APIKey{{
+			ID:          "test-id",
+			Name:        "test-key",
+			Hash:        "test-hash",
+			Domains:     []string{"test-domain"},
+			Permissions: []string{"read"},
+			CreatedTime: time.Unix(1, 0).UTC(),
+		}},
+	}
+	s.Nil(s.ConfigStoreManager.UpdateAPIKeys(ctx, &p.UpdateAPIKeysRequest{Snapshot: snapshot}))
+	err = s.ConfigStoreManager.UpdateAPIKeys(ctx, &p.UpdateAPIKeysRequest{Snapshot: snapshot})
+	s.IsType(&p.ConditionFailedError{}, err)
+
+	resp, err = s.ConfigStoreManager.FetchAPIKeys(ctx)
+	s.Nil(err)
+	s.Equal(snapshot, resp.Snapshot)
+}
+
+func (s *ConfigStorePersistenceSuite) TestResharding() {
+	if !validDatabaseCheck(s.Config()) {
+		s.T().Skip()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	s.DefaultTestCluster.TearDownTestDatabase()
+	s.DefaultTestCluster.SetupTestDatabase()
+
+	resp, err := s.ConfigStoreManager.FetchResharding(ctx)
+	s.Nil(err)
+	s.Nil(resp.Snapshot)
+
+	snapshot := &p.ReshardingSnapshot{
+		Version:        1,
+		FromShardCount: 4,
+		ToShardCount:   8,
+		SplitShards:    []int{0, 1},
+		PausedShards:   []int{2},
+		StartedTime:    time.Unix(1, 0).UTC(),
+	}
+	s.Nil(s.ConfigStoreManager.UpdateResharding(ctx, &p.UpdateReshardingRequest{Snapshot: snapshot}))
+	err = s.ConfigStoreManager.UpdateResharding(ctx, &p.UpdateReshardingRequest{Snapshot: snapshot})
+	s.IsType(&p.ConditionFailedError{}, err)
+
+	resp, err = s.ConfigStoreManager.FetchResharding(ctx)
+	s.Nil(err)
+	s.Equal(snapshot, resp.Snapshot)
+}
+
 func generateRandomSnapshot(version int64) *p.DynamicConfigSnapshot {
 	data, _ := json.Marshal("test_value")
 
@@ -207,6 +364,7 @@ func validDatabaseCheck(cfg config.Persistence) bool {
 		if datastore.NoSQL != nil {
 			return supportedPlugins[datastore.NoSQL.PluginName]
 		}
+		return datastore.SQL != nil
 	}
 	return false
 }
@@ -225,3 +383,9 @@ func (s *ConfigStorePersistenceSuite) FetchDynamicConfig(ctx context.Context) (*
 func (s *ConfigStorePersistenceSuite) UpdateDynamicConfig(ctx context.Context, snapshot *p.DynamicConfigSnapshot) error {
 	return s.ConfigStoreManager.UpdateDynamicConfig(ctx, &p.UpdateDynamicConfigRequest{Snapshot: snapshot})
 }
+
+// isMongoDB returns whether the default store is MongoDB, which doesn't implement the domain usage table yet
+func isMongoDB(cfg config.Persistence) bool {
+	datastore, ok := cfg.DataStores[cfg.DefaultStore]
+	return ok && datastore.NoSQL != nil && datastore.NoSQL.PluginName == mongodb.PluginName
+}
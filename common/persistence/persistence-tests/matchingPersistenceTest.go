@@ -116,6 +116,49 @@ func (s *MatchingPersistenceSuite) TestCreateTask() {
 	}
 }
 
+// TestCreateTaskWithPriority test
+func (s *MatchingPersistenceSuite) TestCreateTaskWithPriority() {
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	domainID := "7bc2a5e4-8f21-4a3c-9f0e-2c1d3b4a5e6f"
+	taskList := "create-task-with-priority-test"
+	workflowExecution := types.WorkflowExecution{WorkflowID: "create-task-with-priority-test",
+		RunID: "3e4f5a6b-7c8d-4e9f-a0b1-c2d3e4f5a6b7"}
+	leaseResponse, err := s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeDecision,
+	})
+	s.NoError(err)
+
+	taskID := s.GetNextSequenceNumber()
+	_, err = s.TaskMgr.CreateTasks(ctx, &p.CreateTasksRequest{
+		TaskListInfo: leaseResponse.TaskListInfo,
+		Tasks: []*p.CreateTaskInfo{
+			{
+				TaskID:    taskID,
+				Execution: workflowExecution,
+				Data: &p.TaskInfo{
+					DomainID:               domainID,
+					WorkflowID:             workflowExecution.WorkflowID,
+					RunID:                  workflowExecution.RunID,
+					TaskID:                 taskID,
+					ScheduleID:             5,
+					ScheduleToStartTimeout: defaultScheduleToStartTimeout,
+					Priority:               3,
+				},
+			},
+		},
+	})
+	s.NoError(err)
+
+	resp, err := s.GetTasks(ctx, domainID, taskList, p.TaskListTypeDecision, 1)
+	s.NoError(err)
+	s.Equal(1, len(resp.Tasks))
+	s.Equal(3, resp.Tasks[0].Priority)
+}
+
 // TestGetDecisionTasks test
 func (s *MatchingPersistenceSuite) TestGetDecisionTasks() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
@@ -395,6 +438,116 @@ func (s *MatchingPersistenceSuite) TestLeaseAndUpdateTaskListSticky() {
 	s.NoError(err)
 }
 
+// TestGetTaskListBacklogSnapshot test
+func (s *MatchingPersistenceSuite) TestGetTaskListBacklogSnapshot() {
+	domainID := uuid.New()
+	taskList := "backlog-snapshot"
+
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	_, err := s.TaskMgr.GetTaskList(ctx, &p.GetTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeActivity,
+	})
+	s.IsType(&types.EntityNotExistsError{}, err)
+
+	response, err := s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeActivity,
+	})
+	s.NoError(err)
+	s.Zero(response.TaskListInfo.ApproximateBacklogCount)
+	s.True(response.TaskListInfo.OldestTaskCreatedTime.IsZero())
+
+	oldestTaskCreatedTime := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	_, err = s.TaskMgr.UpdateTaskList(ctx, &p.UpdateTaskListRequest{
+		TaskListInfo: &p.TaskListInfo{
+			DomainID:                domainID,
+			Name:                    taskList,
+			TaskType:                p.TaskListTypeActivity,
+			RangeID:                 response.TaskListInfo.RangeID,
+			AckLevel:                10,
+			Kind:                    p.TaskListKindNormal,
+			ApproximateBacklogCount: 5,
+			OldestTaskCreatedTime:   oldestTaskCreatedTime,
+		},
+	})
+	s.NoError(err)
+
+	getResponse, err := s.TaskMgr.GetTaskList(ctx, &p.GetTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeActivity,
+	})
+	s.NoError(err)
+	tli := getResponse.TaskListInfo
+	s.EqualValues(response.TaskListInfo.RangeID, tli.RangeID)
+	s.EqualValues(10, tli.AckLevel)
+	s.EqualValues(5, tli.ApproximateBacklogCount)
+	s.True(oldestTaskCreatedTime.Equal(tli.OldestTaskCreatedTime))
+
+	// the snapshot is kept when the lease is renewed
+	response, err = s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeActivity,
+		RangeID:  tli.RangeID,
+	})
+	s.NoError(err)
+	s.EqualValues(5, response.TaskListInfo.ApproximateBacklogCount)
+	s.True(oldestTaskCreatedTime.Equal(response.TaskListInfo.OldestTaskCreatedTime))
+}
+
+// TestUpdateTaskListPaused test
+func (s *MatchingPersistenceSuite) TestUpdateTaskListPaused() {
+	domainID := uuid.New()
+	taskList := "paused"
+
+	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
+	defer cancel()
+
+	response, err := s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeActivity,
+	})
+	s.NoError(err)
+	s.False(response.TaskListInfo.Paused)
+
+	_, err = s.TaskMgr.UpdateTaskList(ctx, &p.UpdateTaskListRequest{
+		TaskListInfo: &p.TaskListInfo{
+			DomainID: domainID,
+			Name:     taskList,
+			TaskType: p.TaskListTypeActivity,
+			RangeID:  response.TaskListInfo.RangeID,
+			Kind:     p.TaskListKindNormal,
+			Paused:   true,
+		},
+	})
+	s.NoError(err)
+
+	// the paused flag is kept when the lease is renewed
+	response, err = s.TaskMgr.LeaseTaskList(ctx, &p.LeaseTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeActivity,
+		RangeID:  response.TaskListInfo.RangeID,
+	})
+	s.NoError(err)
+	s.True(response.TaskListInfo.Paused)
+
+	getResponse, err := s.TaskMgr.GetTaskList(ctx, &p.GetTaskListRequest{
+		DomainID: domainID,
+		TaskList: taskList,
+		TaskType: p.TaskListTypeActivity,
+	})
+	s.NoError(err)
+	s.True(getResponse.TaskListInfo.Paused)
+}
+
 func (s *MatchingPersistenceSuite) deleteAllTaskList() {
 	ctx, cancel := context.WithTimeout(context.Background(), testContextTimeout)
 	defer cancel()
@@ -196,7 +196,7 @@ func (s *TestBase) Setup() {
 	cfg := s.DefaultTestCluster.Config()
 	scope := tally.NewTestScope(service.History, make(map[string]string))
 	metricsClient := metrics.NewClient(scope, service.GetMetricsServiceIdx(service.History, s.Logger))
-	factory := client.NewFactory(&cfg, nil, clusterName, metricsClient, s.Logger, &s.DynamicConfiguration)
+	factory := client.NewFactory(&cfg, nil, clusterName, metricsClient, nil, nil, s.Logger, &s.DynamicConfiguration)
 
 	s.TaskMgr, err = factory.NewTaskManager()
 	s.fatalOnError("NewTaskManager", err)
@@ -210,7 +210,7 @@ func (s *TestBase) Setup() {
 	s.ShardMgr, err = factory.NewShardManager()
 	s.fatalOnError("NewShardManager", err)
 
-	if cfg.DefaultStoreType() == config.StoreTypeCassandra {
+	if storeType := cfg.DefaultStoreType(); storeType == config.StoreTypeCassandra || storeType == config.StoreTypeSQL {
 		s.ConfigStoreManager, err = factory.NewConfigStoreManager()
 		s.fatalOnError("NewConfigStoreManager", err)
 	}
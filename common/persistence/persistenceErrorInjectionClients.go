@@ -1132,6 +1132,31 @@ func (p *taskErrorInjectionPersistenceClient) LeaseTaskList(
 	return response, persistenceErr
 }
 
+func (p *taskErrorInjectionPersistenceClient) GetTaskList(
+	ctx context.Context,
+	request *GetTaskListRequest,
+) (*GetTaskListResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *GetTaskListResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.GetTaskList(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationGetTaskList,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
 func (p *taskErrorInjectionPersistenceClient) UpdateTaskList(
 	ctx context.Context,
 	request *UpdateTaskListRequest,
@@ -2375,6 +2400,176 @@ func (p *configStoreErrorInjectionPersistenceClient) UpdateDynamicConfig(ctx con
 	return persistenceErr
 }
 
+func (p *configStoreErrorInjectionPersistenceClient) ListDynamicConfigChanges(
+	ctx context.Context,
+	request *ListDynamicConfigChangesRequest,
+) (*ListDynamicConfigChangesResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *ListDynamicConfigChangesResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.ListDynamicConfigChanges(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationListDynamicConfigChanges,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
+func (p *configStoreErrorInjectionPersistenceClient) RecordDomainUsage(
+	ctx context.Context,
+	request *RecordDomainUsageRequest,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.RecordDomainUsage(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationRecordDomainUsage,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *configStoreErrorInjectionPersistenceClient) ListDomainUsage(
+	ctx context.Context,
+	request *ListDomainUsageRequest,
+) (*ListDomainUsageResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *ListDomainUsageResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.ListDomainUsage(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationListDomainUsage,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
+func (p *configStoreErrorInjectionPersistenceClient) FetchAPIKeys(
+	ctx context.Context,
+) (*FetchAPIKeysResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *FetchAPIKeysResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.FetchAPIKeys(ctx)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationFetchAPIKeys,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
+func (p *configStoreErrorInjectionPersistenceClient) UpdateAPIKeys(
+	ctx context.Context,
+	request *UpdateAPIKeysRequest,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.UpdateAPIKeys(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationUpdateAPIKeys,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
+func (p *configStoreErrorInjectionPersistenceClient) FetchResharding(
+	ctx context.Context,
+) (*FetchReshardingResponse, error) {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var response *FetchReshardingResponse
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		response, persistenceErr = p.persistence.FetchResharding(ctx)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationFetchResharding,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return nil, fakeErr
+	}
+	return response, persistenceErr
+}
+
+func (p *configStoreErrorInjectionPersistenceClient) UpdateResharding(
+	ctx context.Context,
+	request *UpdateReshardingRequest,
+) error {
+	fakeErr := generateFakeError(p.errorRate)
+
+	var persistenceErr error
+	var forwardCall bool
+	if forwardCall = shouldForwardCallToPersistence(fakeErr); forwardCall {
+		persistenceErr = p.persistence.UpdateResharding(ctx, request)
+	}
+
+	if fakeErr != nil {
+		p.logger.Error(msgInjectedFakeErr,
+			tag.StoreOperationUpdateResharding,
+			tag.Error(fakeErr),
+			tag.Bool(forwardCall),
+			tag.StoreError(persistenceErr),
+		)
+		return fakeErr
+	}
+	return persistenceErr
+}
+
 func (p *configStoreErrorInjectionPersistenceClient) Close() {
 	p.persistence.Close()
 }
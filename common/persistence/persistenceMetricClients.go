@@ -23,20 +23,37 @@ package persistence
 import (
 	"context"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/slowrequest"
+	"github.com/uber/cadence/common/tracing"
 	"github.com/uber/cadence/common/types"
 )
 
+var (
+	domainTagKey = metrics.DomainTag("").Key()
+	// readOperationPrefixes are the prefixes of the operations which don't modify the persisted data
+	readOperationPrefixes = []string{"Get", "List", "Read", "Is", "Count", "Scan"}
+)
+
 type (
 	persistenceMetricsClientBase struct {
 		metricClient                  metrics.Client
 		logger                        log.Logger
 		enableLatencyHistogramMetrics bool
+		// usageRecorder is optional, it records the requests of domain scoped operations as domain usage
+		usageRecorder UsageRecorder
+		// latencyRecorder is optional, it records the latency of the operations on the shard of the client
+		latencyRecorder LatencyRecorder
+		shardID         int
 	}
 
 	shardPersistenceClient struct {
@@ -110,6 +127,8 @@ func NewShardPersistenceMetricsClient(
 func NewWorkflowExecutionPersistenceMetricsClient(
 	persistence ExecutionManager,
 	metricClient metrics.Client,
+	usageRecorder UsageRecorder,
+	latencyRecorder LatencyRecorder,
 	logger log.Logger,
 	cfg *config.Persistence,
 ) ExecutionManager {
@@ -119,6 +138,9 @@ func NewWorkflowExecutionPersistenceMetricsClient(
 			metricClient:                  metricClient,
 			logger:                        logger.WithTags(tag.ShardID(persistence.GetShardID())),
 			enableLatencyHistogramMetrics: cfg.EnablePersistenceLatencyHistogramMetrics,
+			usageRecorder:                 usageRecorder,
+			latencyRecorder:               latencyRecorder,
+			shardID:                       persistence.GetShardID(),
 		},
 	}
 }
@@ -127,6 +149,7 @@ func NewWorkflowExecutionPersistenceMetricsClient(
 func NewTaskPersistenceMetricsClient(
 	persistence TaskManager,
 	metricClient metrics.Client,
+	usageRecorder UsageRecorder,
 	logger log.Logger,
 	cfg *config.Persistence,
 ) TaskManager {
@@ -136,6 +159,7 @@ func NewTaskPersistenceMetricsClient(
 			metricClient:                  metricClient,
 			logger:                        logger,
 			enableLatencyHistogramMetrics: cfg.EnablePersistenceLatencyHistogramMetrics,
+			usageRecorder:                 usageRecorder,
 		},
 	}
 }
@@ -144,6 +168,7 @@ func NewTaskPersistenceMetricsClient(
 func NewHistoryPersistenceMetricsClient(
 	persistence HistoryManager,
 	metricClient metrics.Client,
+	usageRecorder UsageRecorder,
 	logger log.Logger,
 	cfg *config.Persistence,
 ) HistoryManager {
@@ -153,6 +178,7 @@ func NewHistoryPersistenceMetricsClient(
 			metricClient:                  metricClient,
 			logger:                        logger,
 			enableLatencyHistogramMetrics: cfg.EnablePersistenceLatencyHistogramMetrics,
+			usageRecorder:                 usageRecorder,
 		},
 	}
 }
@@ -293,7 +319,8 @@ func (p *persistenceMetricsClientBase) updateErrorMetric(scope int, err error, m
 	}
 }
 
-func (p *persistenceMetricsClientBase) callWithDomainAndShardScope(scope int, op func() error, domainTag metrics.Tag, shardIDTag metrics.Tag) error {
+func (p *persistenceMetricsClientBase) callWithDomainAndShardScope(ctx context.Context, scope int, op func() error, domainTag metrics.Tag, shardIDTag metrics.Tag) error {
+	span, _ := startPersistenceSpan(ctx, scope)
 	domainMetricsScope := p.metricClient.Scope(scope, domainTag)
 	shardMetricsScope := p.metricClient.Scope(scope, shardIDTag)
 
@@ -303,9 +330,11 @@ func (p *persistenceMetricsClientBase) callWithDomainAndShardScope(scope int, op
 	before := time.Now()
 	err := op()
 	duration := time.Since(before)
+	slowrequest.RecordDependency(ctx, slowrequest.DependencyPersistence, duration)
 
 	domainMetricsScope.RecordTimer(metrics.PersistenceLatencyPerDomain, duration)
 	shardMetricsScope.RecordTimer(metrics.PersistenceLatencyPerShard, duration)
+	p.recordLatency(scope, duration)
 
 	if p.enableLatencyHistogramMetrics {
 		domainMetricsScope.RecordHistogramDuration(metrics.PersistenceLatencyHistogram, duration)
@@ -313,10 +342,13 @@ func (p *persistenceMetricsClientBase) callWithDomainAndShardScope(scope int, op
 	if err != nil {
 		p.updateErrorMetricPerDomain(scope, err, domainMetricsScope)
 	}
+	p.recordUsage(scope, domainTag)
+	tracing.FinishSpan(span, err)
 	return err
 }
 
-func (p *persistenceMetricsClientBase) call(scope int, op func() error, tags ...metrics.Tag) error {
+func (p *persistenceMetricsClientBase) call(ctx context.Context, scope int, op func() error, tags ...metrics.Tag) error {
+	span, _ := startPersistenceSpan(ctx, scope)
 	metricsScope := p.metricClient.Scope(scope, tags...)
 	if len(tags) > 0 {
 		metricsScope.IncCounter(metrics.PersistenceRequestsPerDomain)
@@ -326,11 +358,13 @@ func (p *persistenceMetricsClientBase) call(scope int, op func() error, tags ...
 	before := time.Now()
 	err := op()
 	duration := time.Since(before)
+	slowrequest.RecordDependency(ctx, slowrequest.DependencyPersistence, duration)
 	if len(tags) > 0 {
 		metricsScope.RecordTimer(metrics.PersistenceLatencyPerDomain, duration)
 	} else {
 		metricsScope.RecordTimer(metrics.PersistenceLatency, duration)
 	}
+	p.recordLatency(scope, duration)
 
 	if p.enableLatencyHistogramMetrics {
 		metricsScope.RecordHistogramDuration(metrics.PersistenceLatencyHistogram, duration)
@@ -342,9 +376,52 @@ func (p *persistenceMetricsClientBase) call(scope int, op func() error, tags ...
 			p.updateErrorMetric(scope, err, metricsScope)
 		}
 	}
+	p.recordUsage(scope, tags...)
+	tracing.FinishSpan(span, err)
 	return err
 }
 
+// recordLatency records the latency of the operation for the shard of the client, if any
+func (p *persistenceMetricsClientBase) recordLatency(scope int, latency time.Duration) {
+	if p.latencyRecorder == nil {
+		return
+	}
+	p.latencyRecorder.RecordLatency(p.shardID, metrics.OperationName(metrics.Common, scope), latency)
+}
+
+// recordUsage records a persistence read or write unit for the domain of the operation, if any
+func (p *persistenceMetricsClientBase) recordUsage(scope int, tags ...metrics.Tag) {
+	if p.usageRecorder == nil {
+		return
+	}
+	for _, t := range tags {
+		if t.Key() != domainTagKey {
+			continue
+		}
+		if isReadOperation(metrics.OperationName(metrics.Common, scope)) {
+			p.usageRecorder.RecordUsage(t.Value(), DomainUsage{PersistenceReadUnits: 1})
+		} else {
+			p.usageRecorder.RecordUsage(t.Value(), DomainUsage{PersistenceWriteUnits: 1})
+		}
+		return
+	}
+}
+
+func isReadOperation(operation string) bool {
+	for _, prefix := range readOperationPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func startPersistenceSpan(ctx context.Context, scope int) (opentracing.Span, context.Context) {
+	span, ctx := tracing.StartChildSpan(ctx, "persistence."+metrics.OperationName(metrics.Common, scope), ext.SpanKindRPCClient)
+	ext.Component.Set(span, "persistence")
+	return span, ctx
+}
+
 func (p *shardPersistenceClient) GetName() string {
 	return p.persistence.GetName()
 }
@@ -356,7 +433,7 @@ func (p *shardPersistenceClient) CreateShard(
 	op := func() error {
 		return p.persistence.CreateShard(ctx, request)
 	}
-	return p.call(metrics.PersistenceCreateShardScope, op)
+	return p.call(ctx, metrics.PersistenceCreateShardScope, op)
 }
 
 func (p *shardPersistenceClient) GetShard(
@@ -369,7 +446,7 @@ func (p *shardPersistenceClient) GetShard(
 		resp, err = p.persistence.GetShard(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetShardScope, op)
+	err := p.call(ctx, metrics.PersistenceGetShardScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -383,7 +460,7 @@ func (p *shardPersistenceClient) UpdateShard(
 	op := func() error {
 		return p.persistence.UpdateShard(ctx, request)
 	}
-	return p.call(metrics.PersistenceUpdateShardScope, op)
+	return p.call(ctx, metrics.PersistenceUpdateShardScope, op)
 }
 
 func (p *shardPersistenceClient) Close() {
@@ -408,7 +485,7 @@ func (p *workflowExecutionPersistenceClient) CreateWorkflowExecution(
 		resp, err = p.persistence.CreateWorkflowExecution(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceCreateWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceCreateWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -425,7 +502,7 @@ func (p *workflowExecutionPersistenceClient) GetWorkflowExecution(
 		resp, err = p.persistence.GetWorkflowExecution(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceGetWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -442,7 +519,7 @@ func (p *workflowExecutionPersistenceClient) UpdateWorkflowExecution(
 		resp, err = p.persistence.UpdateWorkflowExecution(ctx, request)
 		return err
 	}
-	err := p.callWithDomainAndShardScope(metrics.PersistenceUpdateWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName), metrics.ShardIDTag(strconv.Itoa(p.GetShardID())))
+	err := p.callWithDomainAndShardScope(ctx, metrics.PersistenceUpdateWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName), metrics.ShardIDTag(strconv.Itoa(p.GetShardID())))
 	if err != nil {
 		return nil, err
 	}
@@ -459,7 +536,7 @@ func (p *workflowExecutionPersistenceClient) ConflictResolveWorkflowExecution(
 		resp, err = p.persistence.ConflictResolveWorkflowExecution(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceConflictResolveWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceConflictResolveWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -473,7 +550,7 @@ func (p *workflowExecutionPersistenceClient) DeleteWorkflowExecution(
 	op := func() error {
 		return p.persistence.DeleteWorkflowExecution(ctx, request)
 	}
-	return p.call(metrics.PersistenceDeleteWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName))
+	return p.call(ctx, metrics.PersistenceDeleteWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName))
 }
 
 func (p *workflowExecutionPersistenceClient) DeleteCurrentWorkflowExecution(
@@ -483,7 +560,7 @@ func (p *workflowExecutionPersistenceClient) DeleteCurrentWorkflowExecution(
 	op := func() error {
 		return p.persistence.DeleteCurrentWorkflowExecution(ctx, request)
 	}
-	return p.call(metrics.PersistenceDeleteCurrentWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName))
+	return p.call(ctx, metrics.PersistenceDeleteCurrentWorkflowExecutionScope, op, metrics.DomainTag(request.DomainName))
 }
 
 func (p *workflowExecutionPersistenceClient) GetCurrentExecution(
@@ -496,7 +573,7 @@ func (p *workflowExecutionPersistenceClient) GetCurrentExecution(
 		resp, err = p.persistence.GetCurrentExecution(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetCurrentExecutionScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceGetCurrentExecutionScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -516,7 +593,7 @@ func (p *workflowExecutionPersistenceClient) ListCurrentExecutions(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceListCurrentExecutionsScope, op)
+	err := p.call(ctx, metrics.PersistenceListCurrentExecutionsScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -533,7 +610,7 @@ func (p *workflowExecutionPersistenceClient) IsWorkflowExecutionExists(
 		resp, err = p.persistence.IsWorkflowExecutionExists(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceIsWorkflowExecutionExistsScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceIsWorkflowExecutionExistsScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -550,7 +627,7 @@ func (p *workflowExecutionPersistenceClient) ListConcreteExecutions(
 		resp, err = p.persistence.ListConcreteExecutions(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceListConcreteExecutionsScope, op)
+	err := p.call(ctx, metrics.PersistenceListConcreteExecutionsScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -570,7 +647,7 @@ func (p *workflowExecutionPersistenceClient) GetTransferTasks(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceGetTransferTasksScope, op)
+	err := p.call(ctx, metrics.PersistenceGetTransferTasksScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -590,7 +667,7 @@ func (p *workflowExecutionPersistenceClient) GetCrossClusterTasks(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceGetCrossClusterTasksScope, op)
+	err := p.call(ctx, metrics.PersistenceGetCrossClusterTasksScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -610,7 +687,7 @@ func (p *workflowExecutionPersistenceClient) GetReplicationTasks(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceGetReplicationTasksScope, op)
+	err := p.call(ctx, metrics.PersistenceGetReplicationTasksScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -624,7 +701,7 @@ func (p *workflowExecutionPersistenceClient) CompleteTransferTask(
 	op := func() error {
 		return p.persistence.CompleteTransferTask(ctx, request)
 	}
-	return p.call(metrics.PersistenceCompleteTransferTaskScope, op)
+	return p.call(ctx, metrics.PersistenceCompleteTransferTaskScope, op)
 }
 
 func (p *workflowExecutionPersistenceClient) RangeCompleteTransferTask(
@@ -637,7 +714,7 @@ func (p *workflowExecutionPersistenceClient) RangeCompleteTransferTask(
 		resp, err = p.persistence.RangeCompleteTransferTask(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceRangeCompleteTransferTaskScope, op)
+	err := p.call(ctx, metrics.PersistenceRangeCompleteTransferTaskScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -651,7 +728,7 @@ func (p *workflowExecutionPersistenceClient) CompleteCrossClusterTask(
 	op := func() error {
 		return p.persistence.CompleteCrossClusterTask(ctx, request)
 	}
-	return p.call(metrics.PersistenceCompleteCrossClusterTaskScope, op)
+	return p.call(ctx, metrics.PersistenceCompleteCrossClusterTaskScope, op)
 }
 
 func (p *workflowExecutionPersistenceClient) RangeCompleteCrossClusterTask(
@@ -664,7 +741,7 @@ func (p *workflowExecutionPersistenceClient) RangeCompleteCrossClusterTask(
 		resp, err = p.persistence.RangeCompleteCrossClusterTask(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceRangeCompleteCrossClusterTaskScope, op)
+	err := p.call(ctx, metrics.PersistenceRangeCompleteCrossClusterTaskScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -678,7 +755,7 @@ func (p *workflowExecutionPersistenceClient) CompleteReplicationTask(
 	op := func() error {
 		return p.persistence.CompleteReplicationTask(ctx, request)
 	}
-	return p.call(metrics.PersistenceCompleteReplicationTaskScope, op)
+	return p.call(ctx, metrics.PersistenceCompleteReplicationTaskScope, op)
 }
 
 func (p *workflowExecutionPersistenceClient) RangeCompleteReplicationTask(
@@ -691,7 +768,7 @@ func (p *workflowExecutionPersistenceClient) RangeCompleteReplicationTask(
 		resp, err = p.persistence.RangeCompleteReplicationTask(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceRangeCompleteReplicationTaskScope, op)
+	err := p.call(ctx, metrics.PersistenceRangeCompleteReplicationTaskScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -705,7 +782,7 @@ func (p *workflowExecutionPersistenceClient) PutReplicationTaskToDLQ(
 	op := func() error {
 		return p.persistence.PutReplicationTaskToDLQ(ctx, request)
 	}
-	return p.call(metrics.PersistencePutReplicationTaskToDLQScope, op, metrics.DomainTag(request.DomainName))
+	return p.call(ctx, metrics.PersistencePutReplicationTaskToDLQScope, op, metrics.DomainTag(request.DomainName))
 }
 
 func (p *workflowExecutionPersistenceClient) GetReplicationTasksFromDLQ(
@@ -721,7 +798,7 @@ func (p *workflowExecutionPersistenceClient) GetReplicationTasksFromDLQ(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceGetReplicationTasksFromDLQScope, op)
+	err := p.call(ctx, metrics.PersistenceGetReplicationTasksFromDLQScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -738,7 +815,7 @@ func (p *workflowExecutionPersistenceClient) GetReplicationDLQSize(
 		resp, err = p.persistence.GetReplicationDLQSize(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetReplicationDLQSizeScope, op)
+	err := p.call(ctx, metrics.PersistenceGetReplicationDLQSizeScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -752,7 +829,7 @@ func (p *workflowExecutionPersistenceClient) DeleteReplicationTaskFromDLQ(
 	op := func() error {
 		return p.persistence.DeleteReplicationTaskFromDLQ(ctx, request)
 	}
-	return p.call(metrics.PersistenceDeleteReplicationTaskFromDLQScope, op)
+	return p.call(ctx, metrics.PersistenceDeleteReplicationTaskFromDLQScope, op)
 }
 
 func (p *workflowExecutionPersistenceClient) RangeDeleteReplicationTaskFromDLQ(
@@ -765,7 +842,7 @@ func (p *workflowExecutionPersistenceClient) RangeDeleteReplicationTaskFromDLQ(
 		resp, err = p.persistence.RangeDeleteReplicationTaskFromDLQ(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceRangeDeleteReplicationTaskFromDLQScope, op)
+	err := p.call(ctx, metrics.PersistenceRangeDeleteReplicationTaskFromDLQScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -779,7 +856,7 @@ func (p *workflowExecutionPersistenceClient) CreateFailoverMarkerTasks(
 	op := func() error {
 		return p.persistence.CreateFailoverMarkerTasks(ctx, request)
 	}
-	return p.call(metrics.PersistenceCreateFailoverMarkerTasksScope, op)
+	return p.call(ctx, metrics.PersistenceCreateFailoverMarkerTasksScope, op)
 }
 
 func (p *workflowExecutionPersistenceClient) GetTimerIndexTasks(
@@ -795,7 +872,7 @@ func (p *workflowExecutionPersistenceClient) GetTimerIndexTasks(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceGetTimerIndexTasksScope, op)
+	err := p.call(ctx, metrics.PersistenceGetTimerIndexTasksScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -809,7 +886,7 @@ func (p *workflowExecutionPersistenceClient) CompleteTimerTask(
 	op := func() error {
 		return p.persistence.CompleteTimerTask(ctx, request)
 	}
-	return p.call(metrics.PersistenceCompleteTimerTaskScope, op)
+	return p.call(ctx, metrics.PersistenceCompleteTimerTaskScope, op)
 }
 
 func (p *workflowExecutionPersistenceClient) RangeCompleteTimerTask(
@@ -822,7 +899,7 @@ func (p *workflowExecutionPersistenceClient) RangeCompleteTimerTask(
 		resp, err = p.persistence.RangeCompleteTimerTask(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceRangeCompleteTimerTaskScope, op)
+	err := p.call(ctx, metrics.PersistenceRangeCompleteTimerTaskScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -847,7 +924,7 @@ func (p *taskPersistenceClient) CreateTasks(
 		resp, err = p.persistence.CreateTasks(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceCreateTaskScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceCreateTaskScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -867,7 +944,7 @@ func (p *taskPersistenceClient) GetTasks(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceGetTasksScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceGetTasksScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -881,7 +958,7 @@ func (p *taskPersistenceClient) CompleteTask(
 	op := func() error {
 		return p.persistence.CompleteTask(ctx, request)
 	}
-	return p.call(metrics.PersistenceCompleteTaskScope, op, metrics.DomainTag(request.DomainName))
+	return p.call(ctx, metrics.PersistenceCompleteTaskScope, op, metrics.DomainTag(request.DomainName))
 }
 
 func (p *taskPersistenceClient) CompleteTasksLessThan(
@@ -894,7 +971,7 @@ func (p *taskPersistenceClient) CompleteTasksLessThan(
 		resp, err = p.persistence.CompleteTasksLessThan(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceCompleteTasksLessThanScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceCompleteTasksLessThanScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -908,7 +985,7 @@ func (p *taskPersistenceClient) GetOrphanTasks(ctx context.Context, request *Get
 		resp, err = p.persistence.GetOrphanTasks(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetOrphanTasksScope, op)
+	err := p.call(ctx, metrics.PersistenceGetOrphanTasksScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -925,7 +1002,24 @@ func (p *taskPersistenceClient) LeaseTaskList(
 		resp, err = p.persistence.LeaseTaskList(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceLeaseTaskListScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceLeaseTaskListScope, op, metrics.DomainTag(request.DomainName))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *taskPersistenceClient) GetTaskList(
+	ctx context.Context,
+	request *GetTaskListRequest,
+) (*GetTaskListResponse, error) {
+	var resp *GetTaskListResponse
+	op := func() error {
+		var err error
+		resp, err = p.persistence.GetTaskList(ctx, request)
+		return err
+	}
+	err := p.call(ctx, metrics.PersistenceGetTaskListScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -942,7 +1036,7 @@ func (p *taskPersistenceClient) ListTaskList(
 		resp, err = p.persistence.ListTaskList(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceListTaskListScope, op)
+	err := p.call(ctx, metrics.PersistenceListTaskListScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -956,7 +1050,7 @@ func (p *taskPersistenceClient) DeleteTaskList(
 	op := func() error {
 		return p.persistence.DeleteTaskList(ctx, request)
 	}
-	return p.call(metrics.PersistenceDeleteTaskListScope, op, metrics.DomainTag(request.DomainName))
+	return p.call(ctx, metrics.PersistenceDeleteTaskListScope, op, metrics.DomainTag(request.DomainName))
 }
 
 func (p *taskPersistenceClient) UpdateTaskList(
@@ -969,7 +1063,7 @@ func (p *taskPersistenceClient) UpdateTaskList(
 		resp, err = p.persistence.UpdateTaskList(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceUpdateTaskListScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceUpdateTaskListScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -994,7 +1088,7 @@ func (p *metadataPersistenceClient) CreateDomain(
 		resp, err = p.persistence.CreateDomain(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceCreateDomainScope, op)
+	err := p.call(ctx, metrics.PersistenceCreateDomainScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1011,7 +1105,7 @@ func (p *metadataPersistenceClient) GetDomain(
 		resp, err = p.persistence.GetDomain(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetDomainScope, op)
+	err := p.call(ctx, metrics.PersistenceGetDomainScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1025,7 +1119,7 @@ func (p *metadataPersistenceClient) UpdateDomain(
 	op := func() error {
 		return p.persistence.UpdateDomain(ctx, request)
 	}
-	return p.call(metrics.PersistenceUpdateDomainScope, op)
+	return p.call(ctx, metrics.PersistenceUpdateDomainScope, op)
 }
 
 func (p *metadataPersistenceClient) DeleteDomain(
@@ -1035,7 +1129,7 @@ func (p *metadataPersistenceClient) DeleteDomain(
 	op := func() error {
 		return p.persistence.DeleteDomain(ctx, request)
 	}
-	return p.call(metrics.PersistenceDeleteDomainScope, op)
+	return p.call(ctx, metrics.PersistenceDeleteDomainScope, op)
 }
 
 func (p *metadataPersistenceClient) DeleteDomainByName(
@@ -1045,7 +1139,7 @@ func (p *metadataPersistenceClient) DeleteDomainByName(
 	op := func() error {
 		return p.persistence.DeleteDomainByName(ctx, request)
 	}
-	return p.call(metrics.PersistenceDeleteDomainByNameScope, op)
+	return p.call(ctx, metrics.PersistenceDeleteDomainByNameScope, op)
 }
 
 func (p *metadataPersistenceClient) ListDomains(
@@ -1061,7 +1155,7 @@ func (p *metadataPersistenceClient) ListDomains(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceListDomainScope, op)
+	err := p.call(ctx, metrics.PersistenceListDomainScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1077,7 +1171,7 @@ func (p *metadataPersistenceClient) GetMetadata(
 		resp, err = p.persistence.GetMetadata(ctx)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetMetadataScope, op)
+	err := p.call(ctx, metrics.PersistenceGetMetadataScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1099,7 +1193,7 @@ func (p *visibilityPersistenceClient) RecordWorkflowExecutionStarted(
 	op := func() error {
 		return p.persistence.RecordWorkflowExecutionStarted(ctx, request)
 	}
-	return p.call(metrics.PersistenceRecordWorkflowExecutionStartedScope, op)
+	return p.call(ctx, metrics.PersistenceRecordWorkflowExecutionStartedScope, op)
 }
 
 func (p *visibilityPersistenceClient) RecordWorkflowExecutionClosed(
@@ -1109,7 +1203,7 @@ func (p *visibilityPersistenceClient) RecordWorkflowExecutionClosed(
 	op := func() error {
 		return p.persistence.RecordWorkflowExecutionClosed(ctx, request)
 	}
-	return p.call(metrics.PersistenceRecordWorkflowExecutionClosedScope, op)
+	return p.call(ctx, metrics.PersistenceRecordWorkflowExecutionClosedScope, op)
 }
 
 func (p *visibilityPersistenceClient) RecordWorkflowExecutionUninitialized(
@@ -1119,7 +1213,7 @@ func (p *visibilityPersistenceClient) RecordWorkflowExecutionUninitialized(
 	op := func() error {
 		return p.persistence.RecordWorkflowExecutionUninitialized(ctx, request)
 	}
-	return p.call(metrics.PersistenceRecordWorkflowExecutionUninitializedScope, op)
+	return p.call(ctx, metrics.PersistenceRecordWorkflowExecutionUninitializedScope, op)
 }
 
 func (p *visibilityPersistenceClient) UpsertWorkflowExecution(
@@ -1129,7 +1223,7 @@ func (p *visibilityPersistenceClient) UpsertWorkflowExecution(
 	op := func() error {
 		return p.persistence.UpsertWorkflowExecution(ctx, request)
 	}
-	return p.call(metrics.PersistenceUpsertWorkflowExecutionScope, op)
+	return p.call(ctx, metrics.PersistenceUpsertWorkflowExecutionScope, op)
 }
 
 func (p *visibilityPersistenceClient) ListOpenWorkflowExecutions(
@@ -1142,7 +1236,7 @@ func (p *visibilityPersistenceClient) ListOpenWorkflowExecutions(
 		resp, err = p.persistence.ListOpenWorkflowExecutions(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceListOpenWorkflowExecutionsScope, op)
+	err := p.call(ctx, metrics.PersistenceListOpenWorkflowExecutionsScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1159,7 +1253,7 @@ func (p *visibilityPersistenceClient) ListClosedWorkflowExecutions(
 		resp, err = p.persistence.ListClosedWorkflowExecutions(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceListClosedWorkflowExecutionsScope, op)
+	err := p.call(ctx, metrics.PersistenceListClosedWorkflowExecutionsScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1176,7 +1270,7 @@ func (p *visibilityPersistenceClient) ListOpenWorkflowExecutionsByType(
 		resp, err = p.persistence.ListOpenWorkflowExecutionsByType(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceListOpenWorkflowExecutionsByTypeScope, op)
+	err := p.call(ctx, metrics.PersistenceListOpenWorkflowExecutionsByTypeScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1193,7 +1287,7 @@ func (p *visibilityPersistenceClient) ListClosedWorkflowExecutionsByType(
 		resp, err = p.persistence.ListClosedWorkflowExecutionsByType(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceListClosedWorkflowExecutionsByTypeScope, op)
+	err := p.call(ctx, metrics.PersistenceListClosedWorkflowExecutionsByTypeScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1210,7 +1304,7 @@ func (p *visibilityPersistenceClient) ListOpenWorkflowExecutionsByWorkflowID(
 		resp, err = p.persistence.ListOpenWorkflowExecutionsByWorkflowID(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceListOpenWorkflowExecutionsByWorkflowIDScope, op)
+	err := p.call(ctx, metrics.PersistenceListOpenWorkflowExecutionsByWorkflowIDScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1227,7 +1321,7 @@ func (p *visibilityPersistenceClient) ListClosedWorkflowExecutionsByWorkflowID(
 		resp, err = p.persistence.ListClosedWorkflowExecutionsByWorkflowID(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceListClosedWorkflowExecutionsByWorkflowIDScope, op)
+	err := p.call(ctx, metrics.PersistenceListClosedWorkflowExecutionsByWorkflowIDScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1244,7 +1338,7 @@ func (p *visibilityPersistenceClient) ListClosedWorkflowExecutionsByStatus(
 		resp, err = p.persistence.ListClosedWorkflowExecutionsByStatus(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceListClosedWorkflowExecutionsByStatusScope, op)
+	err := p.call(ctx, metrics.PersistenceListClosedWorkflowExecutionsByStatusScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1261,7 +1355,7 @@ func (p *visibilityPersistenceClient) GetClosedWorkflowExecution(
 		resp, err = p.persistence.GetClosedWorkflowExecution(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetClosedWorkflowExecutionScope, op)
+	err := p.call(ctx, metrics.PersistenceGetClosedWorkflowExecutionScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1275,7 +1369,7 @@ func (p *visibilityPersistenceClient) DeleteWorkflowExecution(
 	op := func() error {
 		return p.persistence.DeleteWorkflowExecution(ctx, request)
 	}
-	return p.call(metrics.PersistenceVisibilityDeleteWorkflowExecutionScope, op)
+	return p.call(ctx, metrics.PersistenceVisibilityDeleteWorkflowExecutionScope, op)
 }
 
 func (p *visibilityPersistenceClient) DeleteUninitializedWorkflowExecution(
@@ -1285,7 +1379,7 @@ func (p *visibilityPersistenceClient) DeleteUninitializedWorkflowExecution(
 	op := func() error {
 		return p.persistence.DeleteUninitializedWorkflowExecution(ctx, request)
 	}
-	return p.call(metrics.PersistenceVisibilityDeleteUninitializedWorkflowExecutionScope, op)
+	return p.call(ctx, metrics.PersistenceVisibilityDeleteUninitializedWorkflowExecutionScope, op)
 }
 
 func (p *visibilityPersistenceClient) ListWorkflowExecutions(
@@ -1298,7 +1392,7 @@ func (p *visibilityPersistenceClient) ListWorkflowExecutions(
 		resp, err = p.persistence.ListWorkflowExecutions(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceListWorkflowExecutionsScope, op)
+	err := p.call(ctx, metrics.PersistenceListWorkflowExecutionsScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1315,7 +1409,7 @@ func (p *visibilityPersistenceClient) ScanWorkflowExecutions(
 		resp, err = p.persistence.ScanWorkflowExecutions(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceScanWorkflowExecutionsScope, op)
+	err := p.call(ctx, metrics.PersistenceScanWorkflowExecutionsScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1332,7 +1426,7 @@ func (p *visibilityPersistenceClient) CountWorkflowExecutions(
 		resp, err = p.persistence.CountWorkflowExecutions(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceCountWorkflowExecutionsScope, op)
+	err := p.call(ctx, metrics.PersistenceCountWorkflowExecutionsScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1362,7 +1456,7 @@ func (p *historyPersistenceClient) AppendHistoryNodes(
 		resp, err = p.persistence.AppendHistoryNodes(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceAppendHistoryNodesScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceAppendHistoryNodesScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -1384,7 +1478,7 @@ func (p *historyPersistenceClient) ReadHistoryBranch(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceReadHistoryBranchScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceReadHistoryBranchScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -1402,7 +1496,7 @@ func (p *historyPersistenceClient) ReadHistoryBranchByBatch(
 		resp, err = p.persistence.ReadHistoryBranchByBatch(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceReadHistoryBranchScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceReadHistoryBranchScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -1420,7 +1514,7 @@ func (p *historyPersistenceClient) ReadRawHistoryBranch(
 		resp, err = p.persistence.ReadRawHistoryBranch(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceReadHistoryBranchScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceReadHistoryBranchScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -1438,7 +1532,7 @@ func (p *historyPersistenceClient) ForkHistoryBranch(
 		resp, err = p.persistence.ForkHistoryBranch(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceForkHistoryBranchScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceForkHistoryBranchScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -1453,7 +1547,7 @@ func (p *historyPersistenceClient) DeleteHistoryBranch(
 	op := func() error {
 		return p.persistence.DeleteHistoryBranch(ctx, request)
 	}
-	return p.call(metrics.PersistenceDeleteHistoryBranchScope, op, metrics.DomainTag(request.DomainName))
+	return p.call(ctx, metrics.PersistenceDeleteHistoryBranchScope, op, metrics.DomainTag(request.DomainName))
 }
 
 func (p *historyPersistenceClient) GetAllHistoryTreeBranches(
@@ -1469,7 +1563,7 @@ func (p *historyPersistenceClient) GetAllHistoryTreeBranches(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceGetAllHistoryTreeBranchesScope, op)
+	err := p.call(ctx, metrics.PersistenceGetAllHistoryTreeBranchesScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1487,7 +1581,7 @@ func (p *historyPersistenceClient) GetHistoryTree(
 		resp, err = p.persistence.GetHistoryTree(ctx, request)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetHistoryTreeScope, op, metrics.DomainTag(request.DomainName))
+	err := p.call(ctx, metrics.PersistenceGetHistoryTreeScope, op, metrics.DomainTag(request.DomainName))
 	if err != nil {
 		return nil, err
 	}
@@ -1501,7 +1595,7 @@ func (p *queuePersistenceClient) EnqueueMessage(
 	op := func() error {
 		return p.persistence.EnqueueMessage(ctx, message)
 	}
-	return p.call(metrics.PersistenceEnqueueMessageScope, op)
+	return p.call(ctx, metrics.PersistenceEnqueueMessageScope, op)
 }
 
 func (p *queuePersistenceClient) ReadMessages(
@@ -1518,7 +1612,7 @@ func (p *queuePersistenceClient) ReadMessages(
 		}
 		return err
 	}
-	err := p.call(metrics.PersistenceReadQueueMessagesScope, op)
+	err := p.call(ctx, metrics.PersistenceReadQueueMessagesScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1533,7 +1627,7 @@ func (p *queuePersistenceClient) UpdateAckLevel(
 	op := func() error {
 		return p.persistence.UpdateAckLevel(ctx, messageID, clusterName)
 	}
-	return p.call(metrics.PersistenceUpdateAckLevelScope, op)
+	return p.call(ctx, metrics.PersistenceUpdateAckLevelScope, op)
 }
 
 func (p *queuePersistenceClient) GetAckLevels(
@@ -1545,7 +1639,7 @@ func (p *queuePersistenceClient) GetAckLevels(
 		resp, err = p.persistence.GetAckLevels(ctx)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetAckLevelScope, op)
+	err := p.call(ctx, metrics.PersistenceGetAckLevelScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1559,7 +1653,7 @@ func (p *queuePersistenceClient) DeleteMessagesBefore(
 	op := func() error {
 		return p.persistence.DeleteMessagesBefore(ctx, messageID)
 	}
-	return p.call(metrics.PersistenceDeleteQueueMessagesScope, op)
+	return p.call(ctx, metrics.PersistenceDeleteQueueMessagesScope, op)
 }
 
 func (p *queuePersistenceClient) EnqueueMessageToDLQ(
@@ -1569,7 +1663,7 @@ func (p *queuePersistenceClient) EnqueueMessageToDLQ(
 	op := func() error {
 		return p.persistence.EnqueueMessageToDLQ(ctx, message)
 	}
-	return p.call(metrics.PersistenceEnqueueMessageToDLQScope, op)
+	return p.call(ctx, metrics.PersistenceEnqueueMessageToDLQScope, op)
 }
 
 func (p *queuePersistenceClient) ReadMessagesFromDLQ(
@@ -1586,7 +1680,7 @@ func (p *queuePersistenceClient) ReadMessagesFromDLQ(
 		result, token, err = p.persistence.ReadMessagesFromDLQ(ctx, firstMessageID, lastMessageID, pageSize, pageToken)
 		return err
 	}
-	err := p.call(metrics.PersistenceReadQueueMessagesFromDLQScope, op)
+	err := p.call(ctx, metrics.PersistenceReadQueueMessagesFromDLQScope, op)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1600,7 +1694,7 @@ func (p *queuePersistenceClient) DeleteMessageFromDLQ(
 	op := func() error {
 		return p.persistence.DeleteMessageFromDLQ(ctx, messageID)
 	}
-	return p.call(metrics.PersistenceDeleteQueueMessageFromDLQScope, op)
+	return p.call(ctx, metrics.PersistenceDeleteQueueMessageFromDLQScope, op)
 }
 
 func (p *queuePersistenceClient) RangeDeleteMessagesFromDLQ(
@@ -1611,7 +1705,7 @@ func (p *queuePersistenceClient) RangeDeleteMessagesFromDLQ(
 	op := func() error {
 		return p.persistence.RangeDeleteMessagesFromDLQ(ctx, firstMessageID, lastMessageID)
 	}
-	return p.call(metrics.PersistenceRangeDeleteMessagesFromDLQScope, op)
+	return p.call(ctx, metrics.PersistenceRangeDeleteMessagesFromDLQScope, op)
 }
 
 func (p *queuePersistenceClient) UpdateDLQAckLevel(
@@ -1622,7 +1716,7 @@ func (p *queuePersistenceClient) UpdateDLQAckLevel(
 	op := func() error {
 		return p.persistence.UpdateDLQAckLevel(ctx, messageID, clusterName)
 	}
-	return p.call(metrics.PersistenceUpdateDLQAckLevelScope, op)
+	return p.call(ctx, metrics.PersistenceUpdateDLQAckLevelScope, op)
 }
 
 func (p *queuePersistenceClient) GetDLQAckLevels(
@@ -1634,7 +1728,7 @@ func (p *queuePersistenceClient) GetDLQAckLevels(
 		resp, err = p.persistence.GetDLQAckLevels(ctx)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetDLQAckLevelScope, op)
+	err := p.call(ctx, metrics.PersistenceGetDLQAckLevelScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1650,7 +1744,7 @@ func (p *queuePersistenceClient) GetDLQSize(
 		resp, err = p.persistence.GetDLQSize(ctx)
 		return err
 	}
-	err := p.call(metrics.PersistenceGetDLQSizeScope, op)
+	err := p.call(ctx, metrics.PersistenceGetDLQSizeScope, op)
 	if err != nil {
 		return 0, err
 	}
@@ -1668,7 +1762,7 @@ func (p *configStorePersistenceClient) FetchDynamicConfig(ctx context.Context) (
 		resp, err = p.persistence.FetchDynamicConfig(ctx)
 		return err
 	}
-	err := p.call(metrics.PersistenceFetchDynamicConfigScope, op)
+	err := p.call(ctx, metrics.PersistenceFetchDynamicConfigScope, op)
 	if err != nil {
 		return nil, err
 	}
@@ -1679,7 +1773,103 @@ func (p *configStorePersistenceClient) UpdateDynamicConfig(ctx context.Context,
 	op := func() error {
 		return p.persistence.UpdateDynamicConfig(ctx, request)
 	}
-	return p.call(metrics.PersistenceUpdateDynamicConfigScope, op)
+	return p.call(ctx, metrics.PersistenceUpdateDynamicConfigScope, op)
+}
+
+func (p *configStorePersistenceClient) ListDynamicConfigChanges(
+	ctx context.Context,
+	request *ListDynamicConfigChangesRequest,
+) (*ListDynamicConfigChangesResponse, error) {
+	var resp *ListDynamicConfigChangesResponse
+	op := func() error {
+		var err error
+		resp, err = p.persistence.ListDynamicConfigChanges(ctx, request)
+		return err
+	}
+	err := p.call(ctx, metrics.PersistenceListDynamicConfigChangesScope, op)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *configStorePersistenceClient) RecordDomainUsage(
+	ctx context.Context,
+	request *RecordDomainUsageRequest,
+) error {
+	op := func() error {
+		return p.persistence.RecordDomainUsage(ctx, request)
+	}
+	return p.call(ctx, metrics.PersistenceRecordDomainUsageScope, op)
+}
+
+func (p *configStorePersistenceClient) ListDomainUsage(
+	ctx context.Context,
+	request *ListDomainUsageRequest,
+) (*ListDomainUsageResponse, error) {
+	var resp *ListDomainUsageResponse
+	op := func() error {
+		var err error
+		resp, err = p.persistence.ListDomainUsage(ctx, request)
+		return err
+	}
+	err := p.call(ctx, metrics.PersistenceListDomainUsageScope, op)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *configStorePersistenceClient) FetchAPIKeys(
+	ctx context.Context,
+) (*FetchAPIKeysResponse, error) {
+	var resp *FetchAPIKeysResponse
+	op := func() error {
+		var err error
+		resp, err = p.persistence.FetchAPIKeys(ctx)
+		return err
+	}
+	err := p.call(ctx, metrics.PersistenceFetchAPIKeysScope, op)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *configStorePersistenceClient) UpdateAPIKeys(
+	ctx context.Context,
+	request *UpdateAPIKeysRequest,
+) error {
+	op := func() error {
+		return p.persistence.UpdateAPIKeys(ctx, request)
+	}
+	return p.call(ctx, metrics.PersistenceUpdateAPIKeysScope, op)
+}
+
+func (p *configStorePersistenceClient) FetchResharding(
+	ctx context.Context,
+) (*FetchReshardingResponse, error) {
+	var resp *FetchReshardingResponse
+	op := func() error {
+		var err error
+		resp, err = p.persistence.FetchResharding(ctx)
+		return err
+	}
+	err := p.call(ctx, metrics.PersistenceFetchReshardingScope, op)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *configStorePersistenceClient) UpdateResharding(
+	ctx context.Context,
+	request *UpdateReshardingRequest,
+) error {
+	op := func() error {
+		return p.persistence.UpdateResharding(ctx, request)
+	}
+	return p.call(ctx, metrics.PersistenceUpdateReshardingScope, op)
 }
 
 func (p *configStorePersistenceClient) Close() {
@@ -648,6 +648,18 @@ func (p *taskRateLimitedPersistenceClient) LeaseTaskList(
 	return response, err
 }
 
+func (p *taskRateLimitedPersistenceClient) GetTaskList(
+	ctx context.Context,
+	request *GetTaskListRequest,
+) (*GetTaskListResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+
+	response, err := p.persistence.GetTaskList(ctx, request)
+	return response, err
+}
+
 func (p *taskRateLimitedPersistenceClient) UpdateTaskList(
 	ctx context.Context,
 	request *UpdateTaskListRequest,
@@ -1230,6 +1242,74 @@ func (p *configStoreRateLimitedPersistenceClient) UpdateDynamicConfig(ctx contex
 	return p.persistence.UpdateDynamicConfig(ctx, request)
 }
 
+func (p *configStoreRateLimitedPersistenceClient) ListDynamicConfigChanges(
+	ctx context.Context,
+	request *ListDynamicConfigChangesRequest,
+) (*ListDynamicConfigChangesResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+	return p.persistence.ListDynamicConfigChanges(ctx, request)
+}
+
+func (p *configStoreRateLimitedPersistenceClient) RecordDomainUsage(
+	ctx context.Context,
+	request *RecordDomainUsageRequest,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+	return p.persistence.RecordDomainUsage(ctx, request)
+}
+
+func (p *configStoreRateLimitedPersistenceClient) ListDomainUsage(
+	ctx context.Context,
+	request *ListDomainUsageRequest,
+) (*ListDomainUsageResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+	return p.persistence.ListDomainUsage(ctx, request)
+}
+
+func (p *configStoreRateLimitedPersistenceClient) FetchAPIKeys(
+	ctx context.Context,
+) (*FetchAPIKeysResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+	return p.persistence.FetchAPIKeys(ctx)
+}
+
+func (p *configStoreRateLimitedPersistenceClient) UpdateAPIKeys(
+	ctx context.Context,
+	request *UpdateAPIKeysRequest,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+	return p.persistence.UpdateAPIKeys(ctx, request)
+}
+
+func (p *configStoreRateLimitedPersistenceClient) FetchResharding(
+	ctx context.Context,
+) (*FetchReshardingResponse, error) {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return nil, ErrPersistenceLimitExceeded
+	}
+	return p.persistence.FetchResharding(ctx)
+}
+
+func (p *configStoreRateLimitedPersistenceClient) UpdateResharding(
+	ctx context.Context,
+	request *UpdateReshardingRequest,
+) error {
+	if ok := p.rateLimiter.Allow(); !ok {
+		return ErrPersistenceLimitExceeded
+	}
+	return p.persistence.UpdateResharding(ctx, request)
+}
+
 func (p *configStoreRateLimitedPersistenceClient) Close() {
 	p.persistence.Close()
 }
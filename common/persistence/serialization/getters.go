@@ -1380,6 +1380,14 @@ func (t *TaskInfo) GetCreatedTimestamp() time.Time {
 	return time.Unix(0, 0)
 }
 
+// GetPriority internal sql blob getter
+func (t *TaskInfo) GetPriority() (o int32) {
+	if t != nil {
+		return t.Priority
+	}
+	return
+}
+
 // GetKind internal sql blob getter
 func (t *TaskListInfo) GetKind() (o int16) {
 	if t != nil {
@@ -1412,6 +1420,30 @@ func (t *TaskListInfo) GetLastUpdated() time.Time {
 	return time.Unix(0, 0)
 }
 
+// GetApproximateBacklogCount internal sql blob getter
+func (t *TaskListInfo) GetApproximateBacklogCount() (o int64) {
+	if t != nil {
+		return t.ApproximateBacklogCount
+	}
+	return
+}
+
+// GetOldestTaskCreatedTime internal sql blob getter
+func (t *TaskListInfo) GetOldestTaskCreatedTime() (o time.Time) {
+	if t != nil {
+		return t.OldestTaskCreatedTime
+	}
+	return
+}
+
+// GetPaused internal sql blob getter
+func (t *TaskListInfo) GetPaused() (o bool) {
+	if t != nil {
+		return t.Paused
+	}
+	return
+}
+
 // GetDomainID internal sql blob getter
 func (t *TransferTaskInfo) GetDomainID() (o []byte) {
 	if t != nil {
@@ -249,14 +249,18 @@ type (
 		ScheduleID       int64
 		ExpiryTimestamp  time.Time
 		CreatedTimestamp time.Time
+		Priority         int32
 	}
 
 	// TaskListInfo blob in a serialization agnostic format
 	TaskListInfo struct {
-		Kind            int16
-		AckLevel        int64
-		ExpiryTimestamp time.Time
-		LastUpdated     time.Time
+		Kind                    int16
+		AckLevel                int64
+		ExpiryTimestamp         time.Time
+		LastUpdated             time.Time
+		ApproximateBacklogCount int64
+		OldestTaskCreatedTime   time.Time
+		Paused                  bool
 	}
 
 	// TransferTaskInfo blob in a serialization agnostic format
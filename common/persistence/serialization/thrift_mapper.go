@@ -523,6 +523,7 @@ func taskInfoToThrift(info *TaskInfo) *sqlblobs.TaskInfo {
 		ScheduleID:       &info.ScheduleID,
 		ExpiryTimeNanos:  timeToUnixNanoPtr(info.ExpiryTimestamp),
 		CreatedTimeNanos: timeToUnixNanoPtr(info.CreatedTimestamp),
+		Priority:         &info.Priority,
 	}
 }
 
@@ -536,6 +537,7 @@ func taskInfoFromThrift(info *sqlblobs.TaskInfo) *TaskInfo {
 		ScheduleID:       info.GetScheduleID(),
 		ExpiryTimestamp:  timeFromUnixNano(info.GetExpiryTimeNanos()),
 		CreatedTimestamp: timeFromUnixNano(info.GetCreatedTimeNanos()),
+		Priority:         info.GetPriority(),
 	}
 }
 
@@ -544,10 +546,13 @@ func taskListInfoToThrift(info *TaskListInfo) *sqlblobs.TaskListInfo {
 		return nil
 	}
 	return &sqlblobs.TaskListInfo{
-		Kind:             &info.Kind,
-		AckLevel:         &info.AckLevel,
-		ExpiryTimeNanos:  timeToUnixNanoPtr(info.ExpiryTimestamp),
-		LastUpdatedNanos: timeToUnixNanoPtr(info.LastUpdated),
+		Kind:                       &info.Kind,
+		AckLevel:                   &info.AckLevel,
+		ExpiryTimeNanos:            timeToUnixNanoPtr(info.ExpiryTimestamp),
+		LastUpdatedNanos:           timeToUnixNanoPtr(info.LastUpdated),
+		ApproximateBacklogCount:    &info.ApproximateBacklogCount,
+		OldestTaskCreatedTimeNanos: timeToUnixNanoPtr(info.OldestTaskCreatedTime),
+		Paused:                     &info.Paused,
 	}
 }
 
@@ -556,10 +561,13 @@ func taskListInfoFromThrift(info *sqlblobs.TaskListInfo) *TaskListInfo {
 		return nil
 	}
 	return &TaskListInfo{
-		Kind:            info.GetKind(),
-		AckLevel:        info.GetAckLevel(),
-		ExpiryTimestamp: timeFromUnixNano(info.GetExpiryTimeNanos()),
-		LastUpdated:     timeFromUnixNano(info.GetLastUpdatedNanos()),
+		Kind:                    info.GetKind(),
+		AckLevel:                info.GetAckLevel(),
+		ExpiryTimestamp:         timeFromUnixNano(info.GetExpiryTimeNanos()),
+		LastUpdated:             timeFromUnixNano(info.GetLastUpdatedNanos()),
+		ApproximateBacklogCount: info.GetApproximateBacklogCount(),
+		OldestTaskCreatedTime:   timeFromUnixNano(info.GetOldestTaskCreatedTimeNanos()),
+		Paused:                  info.GetPaused(),
 	}
 }
 
@@ -408,6 +408,7 @@ func TestTaskInfo(t *testing.T) {
 		ScheduleID:       int64(rand.Intn(1000)),
 		ExpiryTimestamp:  time.Now(),
 		CreatedTimestamp: time.Now(),
+		Priority:         int32(rand.Intn(10)),
 	}
 	actual := taskInfoFromThrift(taskInfoToThrift(expected))
 	assert.Equal(t, expected.WorkflowID, actual.WorkflowID)
@@ -415,20 +416,31 @@ func TestTaskInfo(t *testing.T) {
 	assert.Equal(t, expected.ScheduleID, actual.ScheduleID)
 	assert.Equal(t, expected.ExpiryTimestamp.Sub(actual.ExpiryTimestamp), time.Duration(0))
 	assert.Equal(t, expected.CreatedTimestamp.Sub(actual.CreatedTimestamp), time.Duration(0))
+	assert.Equal(t, expected.Priority, actual.Priority)
 }
 
 func TestTaskListInfo(t *testing.T) {
 	expected := &TaskListInfo{
-		Kind:            int16(rand.Intn(1000)),
-		AckLevel:        int64(rand.Intn(1000)),
-		ExpiryTimestamp: time.Now(),
-		LastUpdated:     time.Now(),
+		Kind:                    int16(rand.Intn(1000)),
+		AckLevel:                int64(rand.Intn(1000)),
+		ExpiryTimestamp:         time.Now(),
+		LastUpdated:             time.Now(),
+		ApproximateBacklogCount: int64(rand.Intn(1000)),
+		OldestTaskCreatedTime:   time.Now(),
+		Paused:                  true,
 	}
 	actual := taskListInfoFromThrift(taskListInfoToThrift(expected))
 	assert.Equal(t, expected.Kind, actual.Kind)
 	assert.Equal(t, expected.AckLevel, actual.AckLevel)
 	assert.Equal(t, expected.LastUpdated.Sub(actual.LastUpdated), time.Duration(0))
 	assert.Equal(t, expected.ExpiryTimestamp.Sub(actual.ExpiryTimestamp), time.Duration(0))
+	assert.Equal(t, expected.ApproximateBacklogCount, actual.ApproximateBacklogCount)
+	assert.Equal(t, expected.OldestTaskCreatedTime.Sub(actual.OldestTaskCreatedTime), time.Duration(0))
+	assert.Equal(t, expected.Paused, actual.Paused)
+
+	// a task list without backlog has no oldest task
+	actual = taskListInfoFromThrift(taskListInfoToThrift(&TaskListInfo{}))
+	assert.True(t, actual.OldestTaskCreatedTime.IsZero())
 }
 
 func TestTransferTaskInfo(t *testing.T) {
@@ -21,7 +21,6 @@
 package sql
 
 import (
-	"errors"
 	"fmt"
 	"sync"
 
@@ -135,9 +134,13 @@ func (f *Factory) NewQueue(queueType p.QueueType) (p.Queue, error) {
 	return newQueueStore(conn, f.logger, queueType)
 }
 
-// NewConfigStore returns a new config store backed by sql. Not Yet Implemented.
+// NewConfigStore returns a new config store backed by sql
 func (f *Factory) NewConfigStore() (p.ConfigStore, error) {
-	return nil, errors.New("sql config store not yet implemented")
+	conn, err := f.dbConn.get()
+	if err != nil {
+		return nil, err
+	}
+	return NewSQLConfigStore(conn, f.logger)
 }
 
 // Close closes the factory
@@ -0,0 +1,153 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/persistence/serialization"
+	"github.com/uber/cadence/common/persistence/sql/sqlplugin"
+)
+
+type (
+	sqlConfigStore struct {
+		sqlStore
+	}
+)
+
+// NewSQLConfigStore creates a config store backed by the cluster_config table
+func NewSQLConfigStore(
+	db sqlplugin.DB,
+	logger log.Logger,
+) (persistence.ConfigStore, error) {
+	return &sqlConfigStore{
+		sqlStore: sqlStore{
+			db:     db,
+			logger: logger,
+		},
+	}, nil
+}
+
+func (m *sqlConfigStore) FetchConfig(ctx context.Context, configType persistence.ConfigType) (*persistence.InternalConfigStoreEntry, error) {
+	row, err := m.db.SelectLatestConfig(ctx, int(configType))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, convertCommonErrors(m.db, "FetchConfig", "", err)
+	}
+	return &persistence.InternalConfigStoreEntry{
+		RowType:   row.RowType,
+		Version:   row.Version,
+		Timestamp: row.Timestamp,
+		Values:    persistence.NewDataBlob(row.Data, common.EncodingType(row.DataEncoding)),
+	}, nil
+}
+
+func (m *sqlConfigStore) UpdateConfig(ctx context.Context, value *persistence.InternalConfigStoreEntry) error {
+	_, err := m.db.InsertConfig(ctx, &sqlplugin.ClusterConfigRow{
+		RowType:      value.RowType,
+		Version:      value.Version,
+		Timestamp:    value.Timestamp,
+		Data:         value.Values.Data,
+		DataEncoding: string(value.Values.Encoding),
+	})
+	if err != nil {
+		if m.db.IsDupEntryError(err) {
+			return &persistence.ConditionFailedError{Msg: fmt.Sprintf("Version %v already exists. Condition Failed", value.Version)}
+		}
+		return convertCommonErrors(m.db, "UpdateConfig", "", err)
+	}
+	return nil
+}
+
+func (m *sqlConfigStore) ListConfigs(
+	ctx context.Context,
+	configType persistence.ConfigType,
+	maxVersion int64,
+	pageSize int,
+) ([]*persistence.InternalConfigStoreEntry, error) {
+	rows, err := m.db.SelectConfigs(ctx, int(configType), maxVersion, pageSize)
+	if err != nil {
+		return nil, convertCommonErrors(m.db, "ListConfigs", "", err)
+	}
+	entries := make([]*persistence.InternalConfigStoreEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, &persistence.InternalConfigStoreEntry{
+			RowType:   row.RowType,
+			Version:   row.Version,
+			Timestamp: row.Timestamp,
+			Values:    persistence.NewDataBlob(row.Data, common.EncodingType(row.DataEncoding)),
+		})
+	}
+	return entries, nil
+}
+
+func (m *sqlConfigStore) InsertDomainUsage(
+	ctx context.Context,
+	report *persistence.InternalDomainUsageReport,
+	ttl time.Duration,
+) error {
+	_, err := m.db.InsertIntoDomainUsage(ctx, &sqlplugin.DomainUsageRow{
+		ReportHour:   report.Hour,
+		ReportID:     serialization.MustParseUUID(report.ReportID),
+		Data:         report.Values.Data,
+		DataEncoding: string(report.Values.Encoding),
+	})
+	if err != nil {
+		return convertCommonErrors(m.db, "InsertDomainUsage", "", err)
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	// SQL has no row TTL, so the hours which fell out of the retention are deleted on insert
+	if _, err := m.db.DeleteFromDomainUsageBefore(ctx, report.Hour.Add(-ttl)); err != nil {
+		return convertCommonErrors(m.db, "InsertDomainUsage", "", err)
+	}
+	return nil
+}
+
+func (m *sqlConfigStore) ListDomainUsage(
+	ctx context.Context,
+	hour time.Time,
+) ([]*persistence.InternalDomainUsageReport, error) {
+	rows, err := m.db.SelectFromDomainUsage(ctx, hour)
+	if err != nil {
+		return nil, convertCommonErrors(m.db, "ListDomainUsage", "", err)
+	}
+	reports := make([]*persistence.InternalDomainUsageReport, 0, len(rows))
+	for _, row := range rows {
+		reports = append(reports, &persistence.InternalDomainUsageReport{
+			Hour:     row.ReportHour,
+			ReportID: row.ReportID.String(),
+			Values:   persistence.NewDataBlob(row.Data, common.EncodingType(row.DataEncoding)),
+		})
+	}
+	return reports, nil
+}
@@ -174,19 +174,59 @@ func (m *sqlTaskStore) LeaseTaskList(
 			return fmt.Errorf("%v rows affected instead of 1", rowsAffected)
 		}
 		resp = &persistence.LeaseTaskListResponse{TaskListInfo: &persistence.TaskListInfo{
-			DomainID:    request.DomainID,
-			Name:        request.TaskList,
-			TaskType:    request.TaskType,
-			RangeID:     rangeID + 1,
-			AckLevel:    ackLevel,
-			Kind:        request.TaskListKind,
-			LastUpdated: now,
+			DomainID:                request.DomainID,
+			Name:                    request.TaskList,
+			TaskType:                request.TaskType,
+			RangeID:                 rangeID + 1,
+			AckLevel:                ackLevel,
+			Kind:                    request.TaskListKind,
+			LastUpdated:             now,
+			ApproximateBacklogCount: tlInfo.GetApproximateBacklogCount(),
+			OldestTaskCreatedTime:   tlInfo.GetOldestTaskCreatedTime(),
+			Paused:                  tlInfo.GetPaused(),
 		}}
 		return nil
 	})
 	return resp, err
 }
 
+func (m *sqlTaskStore) GetTaskList(
+	ctx context.Context,
+	request *persistence.GetTaskListRequest,
+) (*persistence.GetTaskListResponse, error) {
+	dbShardID := sqlplugin.GetDBShardIDFromDomainIDAndTasklist(request.DomainID, request.TaskList, m.db.GetTotalNumDBShards())
+	domainID := serialization.MustParseUUID(request.DomainID)
+	rows, err := m.db.SelectFromTaskLists(ctx, &sqlplugin.TaskListsFilter{
+		ShardID:  dbShardID,
+		DomainID: &domainID,
+		Name:     &request.TaskList,
+		TaskType: common.Int64Ptr(int64(request.TaskType))})
+	if err == nil && len(rows) == 0 {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, convertCommonErrors(m.db, "GetTaskList", fmt.Sprintf("Failed to get task list %v of type %v.", request.TaskList, request.TaskType), err)
+	}
+
+	tlInfo, err := m.parser.TaskListInfoFromBlob(rows[0].Data, rows[0].DataEncoding)
+	if err != nil {
+		return nil, err
+	}
+	return &persistence.GetTaskListResponse{TaskListInfo: &persistence.TaskListInfo{
+		DomainID:                request.DomainID,
+		Name:                    request.TaskList,
+		TaskType:                request.TaskType,
+		RangeID:                 rows[0].RangeID,
+		AckLevel:                tlInfo.GetAckLevel(),
+		Kind:                    int(tlInfo.GetKind()),
+		Expiry:                  tlInfo.GetExpiryTimestamp(),
+		LastUpdated:             tlInfo.GetLastUpdated(),
+		ApproximateBacklogCount: tlInfo.GetApproximateBacklogCount(),
+		OldestTaskCreatedTime:   tlInfo.GetOldestTaskCreatedTime(),
+		Paused:                  tlInfo.GetPaused(),
+	}}, nil
+}
+
 func (m *sqlTaskStore) UpdateTaskList(
 	ctx context.Context,
 	request *persistence.UpdateTaskListRequest,
@@ -194,10 +234,13 @@ func (m *sqlTaskStore) UpdateTaskList(
 	dbShardID := sqlplugin.GetDBShardIDFromDomainIDAndTasklist(request.TaskListInfo.DomainID, request.TaskListInfo.Name, m.db.GetTotalNumDBShards())
 	domainID := serialization.MustParseUUID(request.TaskListInfo.DomainID)
 	tlInfo := &serialization.TaskListInfo{
-		AckLevel:        request.TaskListInfo.AckLevel,
-		Kind:            int16(request.TaskListInfo.Kind),
-		ExpiryTimestamp: time.Unix(0, 0),
-		LastUpdated:     time.Now(),
+		AckLevel:                request.TaskListInfo.AckLevel,
+		Kind:                    int16(request.TaskListInfo.Kind),
+		ExpiryTimestamp:         time.Unix(0, 0),
+		LastUpdated:             time.Now(),
+		ApproximateBacklogCount: request.TaskListInfo.ApproximateBacklogCount,
+		OldestTaskCreatedTime:   request.TaskListInfo.OldestTaskCreatedTime,
+		Paused:                  request.TaskListInfo.Paused,
 	}
 	if request.TaskListInfo.Kind == persistence.TaskListKindSticky {
 		tlInfo.ExpiryTimestamp = stickyTaskListExpiry()
@@ -325,6 +368,9 @@ func (m *sqlTaskStore) ListTaskList(
 		resp.Items[i].AckLevel = info.GetAckLevel()
 		resp.Items[i].Expiry = info.GetExpiryTimestamp()
 		resp.Items[i].LastUpdated = info.GetLastUpdated()
+		resp.Items[i].ApproximateBacklogCount = info.GetApproximateBacklogCount()
+		resp.Items[i].OldestTaskCreatedTime = info.GetOldestTaskCreatedTime()
+		resp.Items[i].Paused = info.GetPaused()
 	}
 
 	return resp, nil
@@ -392,6 +438,7 @@ func (m *sqlTaskStore) CreateTasks(
 			ScheduleID:       v.Data.ScheduleID,
 			ExpiryTimestamp:  expiryTime,
 			CreatedTimestamp: time.Now(),
+			Priority:         int32(v.Data.Priority),
 		})
 		if err != nil {
 			return nil, err
@@ -478,6 +525,7 @@ func (m *sqlTaskStore) GetTasks(
 			ScheduleID:  info.GetScheduleID(),
 			Expiry:      info.GetExpiryTimestamp(),
 			CreatedTime: info.GetCreatedTimestamp(),
+			Priority:    int(info.GetPriority()),
 		}
 	}
 
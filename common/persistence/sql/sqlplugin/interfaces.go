@@ -568,6 +568,23 @@ type (
 		Data      []byte
 	}
 
+	// ClusterConfigRow represents a row in cluster_config table
+	ClusterConfigRow struct {
+		RowType      int
+		Version      int64
+		Timestamp    time.Time
+		Data         []byte
+		DataEncoding string
+	}
+
+	// DomainUsageRow represents a row in domain_usage table
+	DomainUsageRow struct {
+		ReportHour   time.Time
+		ReportID     serialization.UUID
+		Data         []byte
+		DataEncoding string
+	}
+
 	// tableCRUD defines the API for interacting with the database tables
 	tableCRUD interface {
 		InsertIntoDomain(ctx context.Context, rows *DomainRow) (sql.Result, error)
@@ -799,6 +816,22 @@ type (
 		GetAckLevels(ctx context.Context, queueType persistence.QueueType, forUpdate bool) (map[string]int64, error)
 		GetQueueSize(ctx context.Context, queueType persistence.QueueType) (int64, error)
 
+		// InsertConfig inserts a new version of the config entry of the row type.
+		// Returns a dup entry error if the version already exists
+		InsertConfig(ctx context.Context, row *ClusterConfigRow) (sql.Result, error)
+		// SelectLatestConfig returns the config entry of the row type with the largest version
+		SelectLatestConfig(ctx context.Context, rowType int) (*ClusterConfigRow, error)
+		// SelectConfigs returns up to pageSize config entries of the row type with version <= maxVersion,
+		// sorted by version in descending order
+		SelectConfigs(ctx context.Context, rowType int, maxVersion int64, pageSize int) ([]ClusterConfigRow, error)
+
+		// InsertIntoDomainUsage inserts a domain usage report
+		InsertIntoDomainUsage(ctx context.Context, row *DomainUsageRow) (sql.Result, error)
+		// SelectFromDomainUsage returns the domain usage reports of the hour
+		SelectFromDomainUsage(ctx context.Context, reportHour time.Time) ([]DomainUsageRow, error)
+		// DeleteFromDomainUsageBefore deletes the domain usage reports of the hours before reportHour
+		DeleteFromDomainUsageBefore(ctx context.Context, reportHour time.Time) (sql.Result, error)
+
 		// The follow provide information about the underlying sql crud implementation
 		SupportsTTL() bool
 		MaxAllowedTTL() (*time.Duration, error)
@@ -0,0 +1,120 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/uber/cadence/common/persistence/sql/sqlplugin"
+)
+
+const (
+	templateInsertConfigQuery       = `INSERT INTO cluster_config (row_type, version, timestamp, data, data_encoding) VALUES(:row_type, :version, :timestamp, :data, :data_encoding)`
+	templateSelectLatestConfigQuery = `SELECT row_type, version, timestamp, data, data_encoding FROM cluster_config WHERE row_type = ? ORDER BY version DESC LIMIT 1`
+	templateSelectConfigsQuery      = `SELECT row_type, version, timestamp, data, data_encoding FROM cluster_config WHERE row_type = ? AND version <= ? ORDER BY version DESC LIMIT ?`
+
+	templateInsertDomainUsageQuery = `INSERT INTO domain_usage (report_hour, report_id, data, data_encoding) VALUES(:report_hour, :report_id, :data, :data_encoding)`
+	templateSelectDomainUsageQuery = `SELECT report_hour, report_id, data, data_encoding FROM domain_usage WHERE report_hour = ?`
+	templateDeleteDomainUsageQuery = `DELETE FROM domain_usage WHERE report_hour < ?`
+)
+
+// InsertConfig inserts a new version of the config entry into cluster_config table
+func (mdb *db) InsertConfig(
+	ctx context.Context,
+	row *sqlplugin.ClusterConfigRow,
+) (sql.Result, error) {
+
+	mysqlRow := *row
+	mysqlRow.Timestamp = mdb.converter.ToMySQLDateTime(row.Timestamp)
+	return mdb.driver.NamedExecContext(ctx, sqlplugin.DbDefaultShard, templateInsertConfigQuery, &mysqlRow)
+}
+
+// SelectLatestConfig returns the config entry of the row type with the largest version
+func (mdb *db) SelectLatestConfig(
+	ctx context.Context,
+	rowType int,
+) (*sqlplugin.ClusterConfigRow, error) {
+
+	var row sqlplugin.ClusterConfigRow
+	if err := mdb.driver.GetContext(ctx, sqlplugin.DbDefaultShard, &row, templateSelectLatestConfigQuery, rowType); err != nil {
+		return nil, err
+	}
+	row.Timestamp = mdb.converter.FromMySQLDateTime(row.Timestamp)
+	return &row, nil
+}
+
+// SelectConfigs returns the config entries of the row type with version <= maxVersion, sorted by version in descending order
+func (mdb *db) SelectConfigs(
+	ctx context.Context,
+	rowType int,
+	maxVersion int64,
+	pageSize int,
+) ([]sqlplugin.ClusterConfigRow, error) {
+
+	var rows []sqlplugin.ClusterConfigRow
+	if err := mdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateSelectConfigsQuery, rowType, maxVersion, pageSize); err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Timestamp = mdb.converter.FromMySQLDateTime(rows[i].Timestamp)
+	}
+	return rows, nil
+}
+
+// InsertIntoDomainUsage inserts a domain usage report into domain_usage table
+func (mdb *db) InsertIntoDomainUsage(
+	ctx context.Context,
+	row *sqlplugin.DomainUsageRow,
+) (sql.Result, error) {
+
+	mysqlRow := *row
+	mysqlRow.ReportHour = mdb.converter.ToMySQLDateTime(row.ReportHour)
+	return mdb.driver.NamedExecContext(ctx, sqlplugin.DbDefaultShard, templateInsertDomainUsageQuery, &mysqlRow)
+}
+
+// SelectFromDomainUsage returns the domain usage reports of the hour
+func (mdb *db) SelectFromDomainUsage(
+	ctx context.Context,
+	reportHour time.Time,
+) ([]sqlplugin.DomainUsageRow, error) {
+
+	var rows []sqlplugin.DomainUsageRow
+	if err := mdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateSelectDomainUsageQuery, mdb.converter.ToMySQLDateTime(reportHour)); err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].ReportHour = mdb.converter.FromMySQLDateTime(rows[i].ReportHour)
+	}
+	return rows, nil
+}
+
+// DeleteFromDomainUsageBefore deletes the domain usage reports of the hours before reportHour
+func (mdb *db) DeleteFromDomainUsageBefore(
+	ctx context.Context,
+	reportHour time.Time,
+) (sql.Result, error) {
+
+	return mdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateDeleteDomainUsageQuery, mdb.converter.ToMySQLDateTime(reportHour))
+}
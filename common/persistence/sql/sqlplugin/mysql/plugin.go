@@ -22,10 +22,7 @@ package mysql
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
-	"io/ioutil"
 	"net"
 	"net/url"
 	"strings"
@@ -125,35 +122,11 @@ func registerTLSConfig(cfg *config.SQL) error {
 	}
 
 	// TODO: create a way to set MinVersion and CipherSuites via cfg.
-	tlsConfig := &tls.Config{
-		ServerName:         host,
-		InsecureSkipVerify: !cfg.TLS.EnableHostVerification,
-	}
-
-	if cfg.TLS.CaFile != "" {
-		rootCertPool := x509.NewCertPool()
-		pem, err := ioutil.ReadFile(cfg.TLS.CaFile)
-		if err != nil {
-			return fmt.Errorf("failed to load CA files: %v", err)
-		}
-		if ok := rootCertPool.AppendCertsFromPEM(pem); !ok {
-			return fmt.Errorf("failed to append CA file")
-		}
-		tlsConfig.RootCAs = rootCertPool
-	}
-
-	if cfg.TLS.CertFile != "" && cfg.TLS.KeyFile != "" {
-		clientCert := make([]tls.Certificate, 0, 1)
-		certs, err := tls.LoadX509KeyPair(
-			cfg.TLS.CertFile,
-			cfg.TLS.KeyFile,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to load tls x509 key pair: %v", err)
-		}
-		clientCert = append(clientCert, certs)
-		tlsConfig.Certificates = clientCert
+	tlsConfig, err := cfg.TLS.ToTLSConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load tls config: %v", err)
 	}
+	tlsConfig.ServerName = host
 
 	// In order to use the TLS configuration you need to register it. Once registered you use it by specifying
 	// `tls` in the connect attributes.
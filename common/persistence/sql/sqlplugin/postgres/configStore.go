@@ -0,0 +1,120 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/uber/cadence/common/persistence/sql/sqlplugin"
+)
+
+const (
+	templateInsertConfigQuery       = `INSERT INTO cluster_config (row_type, version, timestamp, data, data_encoding) VALUES(:row_type, :version, :timestamp, :data, :data_encoding)`
+	templateSelectLatestConfigQuery = `SELECT row_type, version, timestamp, data, data_encoding FROM cluster_config WHERE row_type = $1 ORDER BY version DESC LIMIT 1`
+	templateSelectConfigsQuery      = `SELECT row_type, version, timestamp, data, data_encoding FROM cluster_config WHERE row_type = $1 AND version <= $2 ORDER BY version DESC LIMIT $3`
+
+	templateInsertDomainUsageQuery = `INSERT INTO domain_usage (report_hour, report_id, data, data_encoding) VALUES(:report_hour, :report_id, :data, :data_encoding)`
+	templateSelectDomainUsageQuery = `SELECT report_hour, report_id, data, data_encoding FROM domain_usage WHERE report_hour = $1`
+	templateDeleteDomainUsageQuery = `DELETE FROM domain_usage WHERE report_hour < $1`
+)
+
+// InsertConfig inserts a new version of the config entry into cluster_config table
+func (pdb *db) InsertConfig(
+	ctx context.Context,
+	row *sqlplugin.ClusterConfigRow,
+) (sql.Result, error) {
+
+	postgresRow := *row
+	postgresRow.Timestamp = pdb.converter.ToPostgresDateTime(row.Timestamp)
+	return pdb.driver.NamedExecContext(ctx, sqlplugin.DbDefaultShard, templateInsertConfigQuery, &postgresRow)
+}
+
+// SelectLatestConfig returns the config entry of the row type with the largest version
+func (pdb *db) SelectLatestConfig(
+	ctx context.Context,
+	rowType int,
+) (*sqlplugin.ClusterConfigRow, error) {
+
+	var row sqlplugin.ClusterConfigRow
+	if err := pdb.driver.GetContext(ctx, sqlplugin.DbDefaultShard, &row, templateSelectLatestConfigQuery, rowType); err != nil {
+		return nil, err
+	}
+	row.Timestamp = pdb.converter.FromPostgresDateTime(row.Timestamp)
+	return &row, nil
+}
+
+// SelectConfigs returns the config entries of the row type with version <= maxVersion, sorted by version in descending order
+func (pdb *db) SelectConfigs(
+	ctx context.Context,
+	rowType int,
+	maxVersion int64,
+	pageSize int,
+) ([]sqlplugin.ClusterConfigRow, error) {
+
+	var rows []sqlplugin.ClusterConfigRow
+	if err := pdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateSelectConfigsQuery, rowType, maxVersion, pageSize); err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Timestamp = pdb.converter.FromPostgresDateTime(rows[i].Timestamp)
+	}
+	return rows, nil
+}
+
+// InsertIntoDomainUsage inserts a domain usage report into domain_usage table
+func (pdb *db) InsertIntoDomainUsage(
+	ctx context.Context,
+	row *sqlplugin.DomainUsageRow,
+) (sql.Result, error) {
+
+	postgresRow := *row
+	postgresRow.ReportHour = pdb.converter.ToPostgresDateTime(row.ReportHour)
+	return pdb.driver.NamedExecContext(ctx, sqlplugin.DbDefaultShard, templateInsertDomainUsageQuery, &postgresRow)
+}
+
+// SelectFromDomainUsage returns the domain usage reports of the hour
+func (pdb *db) SelectFromDomainUsage(
+	ctx context.Context,
+	reportHour time.Time,
+) ([]sqlplugin.DomainUsageRow, error) {
+
+	var rows []sqlplugin.DomainUsageRow
+	if err := pdb.driver.SelectContext(ctx, sqlplugin.DbDefaultShard, &rows, templateSelectDomainUsageQuery, pdb.converter.ToPostgresDateTime(reportHour)); err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].ReportHour = pdb.converter.FromPostgresDateTime(rows[i].ReportHour)
+	}
+	return rows, nil
+}
+
+// DeleteFromDomainUsageBefore deletes the domain usage reports of the hours before reportHour
+func (pdb *db) DeleteFromDomainUsageBefore(
+	ctx context.Context,
+	reportHour time.Time,
+) (sql.Result, error) {
+
+	return pdb.driver.ExecContext(ctx, sqlplugin.DbDefaultShard, templateDeleteDomainUsageQuery, pdb.converter.ToPostgresDateTime(reportHour))
+}
@@ -97,6 +97,12 @@ func newDB(xdbs []*sqlx.DB, tx *sqlx.Tx, dbShardID int, numDBShards int) (*db, e
 	return db, nil
 }
 
+// NewDB returns an instance of DB on top of connections to a database
+// which understands the postgres dialect, the sqlite plugin builds on it
+func NewDB(xdbs []*sqlx.DB, numDBShards int) (sqlplugin.DB, error) {
+	return newDB(xdbs, nil, sqlplugin.DbShardUndefined, numDBShards)
+}
+
 // BeginTx starts a new transaction and returns a reference to the Tx object
 func (pdb *db) BeginTx(ctx context.Context, dbShardID int) (sqlplugin.Tx, error) {
 	xtx, err := pdb.driver.BeginTxx(ctx, dbShardID, nil)
@@ -85,6 +85,14 @@ func TestPostgresSQLVisibilityPersistenceSuite(t *testing.T) {
 	suite.Run(t, s)
 }
 
+func TestPostgresSQLConfigStorePersistence(t *testing.T) {
+	testflags.RequirePostgres(t)
+	s := new(pt.ConfigStorePersistenceSuite)
+	s.TestBase = pt.NewTestBaseWithSQL(GetTestClusterOption())
+	s.TestBase.Setup()
+	suite.Run(t, s)
+}
+
 // TODO flaky test in buildkite
 // https://github.com/uber/cadence/issues/2877
 /*
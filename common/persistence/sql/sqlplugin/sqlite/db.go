@@ -0,0 +1,70 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sqlite
+
+import (
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/uber/cadence/common/persistence/sql/sqlplugin"
+	"github.com/uber/cadence/common/persistence/sql/sqlplugin/postgres"
+)
+
+type (
+	// db runs the queries of the postgres plugin, only the errors
+	// and the name of the plugin are specific to sqlite
+	db struct {
+		sqlplugin.DB
+	}
+)
+
+var _ sqlplugin.DB = (*db)(nil)
+
+// newDB returns an instance of DB, which is a logical
+// connection to the underlying sqlite database
+func newDB(xdbs []*sqlx.DB, numDBShards int) (*db, error) {
+	pdb, err := postgres.NewDB(xdbs, numDBShards)
+	if err != nil {
+		return nil, err
+	}
+	return &db{DB: pdb}, nil
+}
+
+func (sdb *db) IsDupEntryError(err error) bool {
+	var sqlErr sqlite3.Error
+	return errors.As(err, &sqlErr) &&
+		(sqlErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey || sqlErr.ExtendedCode == sqlite3.ErrConstraintUnique)
+}
+
+func (sdb *db) IsThrottlingError(err error) bool {
+	var sqlErr sqlite3.Error
+	return errors.As(err, &sqlErr) &&
+		(sqlErr.Code == sqlite3.ErrBusy || sqlErr.Code == sqlite3.ErrLocked)
+}
+
+// PluginName returns the name of the sqlite plugin
+func (sdb *db) PluginName() string {
+	return PluginName
+}
@@ -0,0 +1,124 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sqlite
+
+import (
+	"context"
+	gosql "database/sql"
+	"database/sql/driver"
+	"regexp"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// driverName is the name of the database/sql driver which runs the queries
+// of the postgres plugin against sqlite
+const driverName = "cadence-sqlite3"
+
+// timestampFormat keeps the wall clock of timestamps like the TIMESTAMP
+// columns of postgres, the fixed width keeps them ordered when compared as text
+const timestampFormat = "2006-01-02 15:04:05.000000000"
+
+var (
+	bindVarRegex = regexp.MustCompile(`\$(\d+)`)
+	rowLockRegex = regexp.MustCompile(`(?i)\s+FOR\s+(UPDATE|SHARE)\b`)
+)
+
+type (
+	sqliteDriver struct {
+		sqlite3.SQLiteDriver
+	}
+
+	conn struct {
+		*sqlite3.SQLiteConn
+	}
+
+	stmt struct {
+		*sqlite3.SQLiteStmt
+	}
+)
+
+var _ driver.ExecerContext = (*conn)(nil)
+var _ driver.QueryerContext = (*conn)(nil)
+var _ driver.ConnPrepareContext = (*conn)(nil)
+var _ driver.StmtExecContext = (*stmt)(nil)
+var _ driver.StmtQueryContext = (*stmt)(nil)
+
+func init() {
+	gosql.Register(driverName, &sqliteDriver{})
+}
+
+// Open opens a connection to the sqlite database
+func (d *sqliteDriver) Open(dsn string) (driver.Conn, error) {
+	c, err := d.SQLiteDriver.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{SQLiteConn: c.(*sqlite3.SQLiteConn)}, nil
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	s, err := c.SQLiteConn.PrepareContext(ctx, rewriteQuery(query))
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{SQLiteStmt: s.(*sqlite3.SQLiteStmt)}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.SQLiteConn.ExecContext(ctx, rewriteQuery(query), convertArgs(args))
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.SQLiteConn.QueryContext(ctx, rewriteQuery(query), convertArgs(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.SQLiteStmt.ExecContext(ctx, convertArgs(args))
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.SQLiteStmt.QueryContext(ctx, convertArgs(args))
+}
+
+// rewriteQuery translates a postgres query into the sqlite dialect: numbered
+// bind variables use ? instead of $, and row locks are dropped since sqlite
+// transactions hold the lock of the whole database
+func rewriteQuery(query string) string {
+	query = bindVarRegex.ReplaceAllString(query, "?$1")
+	return rowLockRegex.ReplaceAllString(query, "")
+}
+
+func convertArgs(args []driver.NamedValue) []driver.NamedValue {
+	for i := range args {
+		if t, ok := args[i].Value.(time.Time); ok {
+			args[i].Value = t.Format(timestampFormat)
+		}
+	}
+	return args
+}
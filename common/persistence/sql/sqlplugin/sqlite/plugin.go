@@ -0,0 +1,134 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sqlite
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"github.com/iancoleman/strcase"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/persistence/sql"
+	"github.com/uber/cadence/common/persistence/sql/sqldriver"
+	"github.com/uber/cadence/common/persistence/sql/sqlplugin"
+	sqliteschema "github.com/uber/cadence/schema/sqlite"
+)
+
+const (
+	// PluginName is the name of the plugin
+	PluginName = "sqlite"
+	dsnFmt     = "file:%s?%s"
+	fileSuffix = ".db"
+)
+
+var schemaFiles = []string{"cadence/schema.sql", "visibility/schema.sql"}
+
+type plugin struct{}
+
+var _ sqlplugin.Plugin = (*plugin)(nil)
+
+func init() {
+	sql.RegisterPlugin(PluginName, &plugin{})
+}
+
+// CreateDB initialize the db object
+func (p *plugin) CreateDB(cfg *config.SQL) (sqlplugin.DB, error) {
+	conns, err := sqldriver.CreateDBConnections(cfg, func(cfg *config.SQL) (*sqlx.DB, error) {
+		return p.createSingleDBConn(cfg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newDB(conns, cfg.NumShards)
+}
+
+// CreateAdminDB is not supported, sqlite databases are not versioned and
+// get the latest schema when they are opened
+func (p *plugin) CreateAdminDB(cfg *config.SQL) (sqlplugin.AdminDB, error) {
+	return nil, fmt.Errorf("plugin %v doesn't support schema administration", PluginName)
+}
+
+// createSingleDBConn opens the database file <connectAddr>/<databaseName>.db,
+// the file is created with the latest schema if it doesn't exist yet
+func (p *plugin) createSingleDBConn(cfg *config.SQL) (*sqlx.DB, error) {
+	params := url.Values{}
+	// WAL lets readers proceed while a transaction is writing, and taking the
+	// write lock when the transaction begins avoids deadlocks on lock upgrades
+	params.Set("_journal_mode", "WAL")
+	params.Set("_txlock", "immediate")
+	params.Set("_busy_timeout", "10000")
+	for k, v := range cfg.ConnectAttributes {
+		params.Set(k, v)
+	}
+
+	db, err := sqlx.Connect(driverName, buildDSN(cfg, params))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.MaxConnLifetime > 0 {
+		db.SetConnMaxLifetime(cfg.MaxConnLifetime)
+	}
+
+	// Maps struct names in CamelCase to snake without need for db struct tags.
+	db.MapperFunc(strcase.ToSnake)
+
+	if err := setupSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func buildDSN(cfg *config.SQL, params url.Values) string {
+	path := filepath.Join(cfg.ConnectAddr, cfg.DatabaseName+fileSuffix)
+	return fmt.Sprintf(dsnFmt, path, params.Encode())
+}
+
+// setupSchema creates the cadence and visibility tables which don't exist yet
+func setupSchema(db *sqlx.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	for _, file := range schemaFiles {
+		content, err := sqliteschema.SchemaFS.ReadFile(file)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec(string(content)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to set up schema %v: %v", file, err)
+		}
+	}
+	return tx.Commit()
+}
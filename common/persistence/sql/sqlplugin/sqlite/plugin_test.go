@@ -0,0 +1,154 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/persistence/serialization"
+	"github.com/uber/cadence/common/persistence/sql/sqlplugin"
+)
+
+func TestRewriteQuery(t *testing.T) {
+	testCases := []struct {
+		query string
+		want  string
+	}{
+		{
+			query: `SELECT range_id FROM shards WHERE shard_id = $1 FOR UPDATE`,
+			want:  `SELECT range_id FROM shards WHERE shard_id = ?1`,
+		},
+		{
+			query: "SELECT * FROM tasks WHERE domain_id = $1 AND task_id > $2 AND task_id <= $10\n FOR SHARE",
+			want:  `SELECT * FROM tasks WHERE domain_id = ?1 AND task_id > ?2 AND task_id <= ?10`,
+		},
+		{
+			query: `INSERT INTO domains (id, name) VALUES(?, ?)`,
+			want:  `INSERT INTO domains (id, name) VALUES(?, ?)`,
+		},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, rewriteQuery(tc.query))
+	}
+}
+
+func TestDomains(t *testing.T) {
+	db := newTestDB(t, t.TempDir())
+	ctx := context.Background()
+
+	row := &sqlplugin.DomainRow{
+		ID:           serialization.MustParseUUID("5f8ea6e2-3c4d-4a1c-9fbb-9a4e0fcd3b2e"),
+		Name:         "test-domain",
+		Data:         []byte("data"),
+		DataEncoding: "thriftrw",
+	}
+	_, err := db.InsertIntoDomain(ctx, row)
+	require.NoError(t, err)
+	_, err = db.InsertIntoDomain(ctx, row)
+	require.True(t, db.IsDupEntryError(err))
+
+	rows, err := db.SelectFromDomain(ctx, &sqlplugin.DomainFilter{Name: &row.Name})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, *row, rows[0])
+
+	tx, err := db.BeginTx(ctx, sqlplugin.DbDefaultShard)
+	require.NoError(t, err)
+	require.NoError(t, tx.LockDomainMetadata(ctx))
+	_, err = tx.UpdateDomainMetadata(ctx, &sqlplugin.DomainMetadataRow{NotificationVersion: 1})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	metadata, err := db.SelectFromDomainMetadata(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), metadata.NotificationVersion)
+}
+
+func TestTimerTasks(t *testing.T) {
+	db := newTestDB(t, t.TempDir())
+	ctx := context.Background()
+
+	zone := time.FixedZone("test", 5*3600)
+	now := time.Date(2021, 6, 1, 10, 0, 0, 500, zone)
+	rows := []sqlplugin.TimerTasksRow{
+		{ShardID: 1, VisibilityTimestamp: now.Add(time.Second), TaskID: 2, Data: []byte{2}, DataEncoding: "thriftrw"},
+		{ShardID: 1, VisibilityTimestamp: now, TaskID: 1, Data: []byte{1}, DataEncoding: "thriftrw"},
+		{ShardID: 1, VisibilityTimestamp: now.Add(time.Hour), TaskID: 3, Data: []byte{3}, DataEncoding: "thriftrw"},
+	}
+	_, err := db.InsertIntoTimerTasks(ctx, rows)
+	require.NoError(t, err)
+
+	result, err := db.SelectFromTimerTasks(ctx, &sqlplugin.TimerTasksFilter{
+		ShardID:                1,
+		MinVisibilityTimestamp: now.UTC(),
+		MaxVisibilityTimestamp: now.Add(time.Minute),
+		PageSize:               10,
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, int64(1), result[0].TaskID)
+	require.True(t, now.Equal(result[0].VisibilityTimestamp))
+	require.Equal(t, int64(2), result[1].TaskID)
+	require.True(t, now.Add(time.Second).Equal(result[1].VisibilityTimestamp))
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	db := newTestDB(t, dir)
+	_, err := db.InsertIntoDomain(ctx, &sqlplugin.DomainRow{
+		ID:           serialization.MustParseUUID("5f8ea6e2-3c4d-4a1c-9fbb-9a4e0fcd3b2e"),
+		Name:         "test-domain",
+		Data:         []byte("data"),
+		DataEncoding: "thriftrw",
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	db = newTestDB(t, dir)
+	rows, err := db.SelectFromDomain(ctx, &sqlplugin.DomainFilter{PageSize: common.IntPtr(10)})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	metadata, err := db.SelectFromDomainMetadata(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), metadata.NotificationVersion)
+}
+
+func newTestDB(t *testing.T, dir string) sqlplugin.DB {
+	db, err := (&plugin{}).CreateDB(&config.SQL{
+		PluginName:   PluginName,
+		DatabaseName: "cadence",
+		ConnectAddr:  dir,
+		NumShards:    1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
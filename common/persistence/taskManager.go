@@ -61,6 +61,10 @@ func (t *taskManager) UpdateTaskList(ctx context.Context, request *UpdateTaskLis
 	return t.persistence.UpdateTaskList(ctx, request)
 }
 
+func (t *taskManager) GetTaskList(ctx context.Context, request *GetTaskListRequest) (*GetTaskListResponse, error) {
+	return t.persistence.GetTaskList(ctx, request)
+}
+
 func (t *taskManager) ListTaskList(ctx context.Context, request *ListTaskListRequest) (*ListTaskListResponse, error) {
 	return t.persistence.ListTaskList(ctx, request)
 }
@@ -133,6 +137,7 @@ func (t *taskManager) toInternalTaskInfo(taskInfo *TaskInfo) *InternalTaskInfo {
 		ScheduleToStartTimeout: common.SecondsToDuration(int64(taskInfo.ScheduleToStartTimeout)),
 		Expiry:                 taskInfo.Expiry,
 		CreatedTime:            taskInfo.CreatedTime,
+		Priority:               taskInfo.Priority,
 	}
 }
 func (t *taskManager) fromInternalTaskInfo(internalTaskInfo *InternalTaskInfo) *TaskInfo {
@@ -148,5 +153,6 @@ func (t *taskManager) fromInternalTaskInfo(internalTaskInfo *InternalTaskInfo) *
 		ScheduleToStartTimeout: int32(internalTaskInfo.ScheduleToStartTimeout.Seconds()),
 		Expiry:                 internalTaskInfo.Expiry,
 		CreatedTime:            internalTaskInfo.CreatedTime,
+		Priority:               internalTaskInfo.Priority,
 	}
 }
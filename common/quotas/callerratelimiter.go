@@ -0,0 +1,93 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package quotas
+
+import "sync"
+
+type callerKey struct {
+	domain string
+	caller string
+}
+
+// CallerRateLimiter applies a rate limit to each caller of a domain in front of a policy,
+// so that a single caller cannot use up the quota shared by all the callers of its domain
+type CallerRateLimiter struct {
+	policy Policy
+	rps    RPSKeyFunc
+
+	mu       sync.RWMutex
+	limiters map[callerKey]Limiter
+}
+
+// NewCallerRateLimiter returns a new caller rate limiter in front of the given policy.
+// The rps of the callers of a domain is given by the domain name, the callers are not limited if it is not positive.
+func NewCallerRateLimiter(policy Policy, rps RPSKeyFunc) *CallerRateLimiter {
+	return &CallerRateLimiter{
+		policy:   policy,
+		rps:      rps,
+		limiters: make(map[callerKey]Limiter),
+	}
+}
+
+// Allow attempts to allow a request to go through. The method returns
+// immediately with a true or false indicating if the request can make
+// progress
+func (c *CallerRateLimiter) Allow(info Info) bool {
+	if len(info.Domain) == 0 || len(info.Caller) == 0 || c.rps(info.Domain) <= 0 {
+		return c.policy.Allow(info)
+	}
+
+	// take a reservation with the caller limiter first
+	rsv := c.limiterFor(callerKey{domain: info.Domain, caller: info.Caller}).Reserve()
+	if !rsv.OK() {
+		return false
+	}
+	if rsv.Delay() != 0 {
+		rsv.Cancel()
+		return false
+	}
+
+	// the request is still accounted in the limits of the domain and the host
+	if !c.policy.Allow(info) {
+		rsv.Cancel()
+		return false
+	}
+	return true
+}
+
+func (c *CallerRateLimiter) limiterFor(key callerKey) Limiter {
+	c.mu.RLock()
+	limiter, ok := c.limiters[key]
+	c.mu.RUnlock()
+	if ok {
+		return limiter
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if limiter, ok = c.limiters[key]; !ok {
+		limiter = NewDynamicRateLimiter(func() float64 { return c.rps(key.domain) })
+		c.limiters[key] = limiter
+	}
+	return limiter
+}
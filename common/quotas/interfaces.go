@@ -35,6 +35,8 @@ type RPSKeyFunc func(key string) float64
 // Info corresponds to information required to determine rate limits
 type Info struct {
 	Domain string
+	// Caller is the authenticated identity making the request, it is empty if the caller is unknown
+	Caller string
 }
 
 // Limiter corresponds to basic rate limiting functionality.
@@ -94,6 +94,25 @@ func TestMultiStageRateLimitingMultipleDomains(t *testing.T) {
 	check(" after refill")
 }
 
+func TestCallerRateLimiter(t *testing.T) {
+	t.Parallel()
+	policy := NewCallerRateLimiter(newFixedRpsMultiStageRateLimiter(100, 2), func(domain string) float64 {
+		if domain == "unlimited" {
+			return 0
+		}
+		return 1
+	})
+
+	assert.True(t, policy.Allow(Info{Domain: defaultDomain, Caller: "one"}), "1:1 should work")
+	assert.False(t, policy.Allow(Info{Domain: defaultDomain, Caller: "one"}), "1:2 should be limited") // per caller limited
+	assert.True(t, policy.Allow(Info{Domain: defaultDomain, Caller: "two"}), "2:1 should work")
+	// the domain limit still applies to the requests without a caller
+	assert.False(t, policy.Allow(Info{Domain: defaultDomain}), "domain should be limited")
+
+	assert.True(t, policy.Allow(Info{Domain: "unlimited", Caller: "one"}), "unlimited 1:1 should work")
+	assert.True(t, policy.Allow(Info{Domain: "unlimited", Caller: "one"}), "unlimited 1:2 should work")
+}
+
 func BenchmarkRateLimiter(b *testing.B) {
 	rps := float64(defaultRps)
 	limiter := NewRateLimiter(&rps, 2*time.Minute, defaultRps)
@@ -0,0 +1,93 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package quotas
+
+import "sync"
+
+type (
+	// PooledPolicy is the policy of the domains which can be pinned to a resource pool: the requests of the domains
+	// of a pool are allowed by the policy of their pool and the requests of the other domains by the shared policy,
+	// so that the domains of a pool and the other domains don't consume each other's budget
+	PooledPolicy struct {
+		poolOf    func(domain string) string
+		shared    Policy
+		newPolicy func(pool string) Policy
+
+		mu       sync.RWMutex
+		policies map[string]Policy
+	}
+
+	limiterPolicy struct {
+		limiter Limiter
+	}
+)
+
+// NewPooledPolicy creates a policy which allows the requests of a domain with the policy of the pool returned by
+// poolOf, the policy of a pool is created on its first request by newPolicy, an empty pool is the shared policy
+func NewPooledPolicy(poolOf func(domain string) string, shared Policy, newPolicy func(pool string) Policy) *PooledPolicy {
+	return &PooledPolicy{
+		poolOf:    poolOf,
+		shared:    shared,
+		newPolicy: newPolicy,
+		policies:  make(map[string]Policy),
+	}
+}
+
+// NewLimiterPolicy creates a policy which allows all the requests with the limiter, regardless of their domain
+func NewLimiterPolicy(limiter Limiter) Policy {
+	return limiterPolicy{limiter}
+}
+
+// Allow attempts to allow a request to go through with the policy of the pool of its domain
+func (p *PooledPolicy) Allow(info Info) bool {
+	pool := ""
+	if len(info.Domain) > 0 {
+		pool = p.poolOf(info.Domain)
+	}
+	if len(pool) == 0 {
+		return p.shared.Allow(info)
+	}
+	return p.policyOf(pool).Allow(info)
+}
+
+func (p *PooledPolicy) policyOf(pool string) Policy {
+	p.mu.RLock()
+	policy, ok := p.policies[pool]
+	p.mu.RUnlock()
+	if ok {
+		return policy
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if policy, ok := p.policies[pool]; ok {
+		return policy
+	}
+	policy = p.newPolicy(pool)
+	p.policies[pool] = policy
+	return policy
+}
+
+func (p limiterPolicy) Allow(Info) bool {
+	return p.limiter.Allow()
+}
@@ -0,0 +1,76 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package quotas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingPolicy struct {
+	allowed int
+	limit   int
+}
+
+func (p *countingPolicy) Allow(Info) bool {
+	if p.allowed >= p.limit {
+		return false
+	}
+	p.allowed++
+	return true
+}
+
+func TestPooledPolicy(t *testing.T) {
+	pools := map[string]string{"domain-a": "pool-1", "domain-b": "pool-1", "domain-c": "pool-2"}
+	shared := &countingPolicy{limit: 1}
+	created := map[string]*countingPolicy{}
+	policy := NewPooledPolicy(
+		func(domain string) string { return pools[domain] },
+		shared,
+		func(pool string) Policy {
+			created[pool] = &countingPolicy{limit: 2}
+			return created[pool]
+		},
+	)
+
+	// the domains which are not pinned share their budget
+	assert.True(t, policy.Allow(Info{Domain: "domain-x"}))
+	assert.False(t, policy.Allow(Info{}))
+
+	// the domains of a pool share the budget of their pool, which is apart from the shared one
+	assert.True(t, policy.Allow(Info{Domain: "domain-a"}))
+	assert.True(t, policy.Allow(Info{Domain: "domain-b"}))
+	assert.False(t, policy.Allow(Info{Domain: "domain-a"}))
+	assert.True(t, policy.Allow(Info{Domain: "domain-c"}))
+
+	assert.Len(t, created, 2)
+	assert.Equal(t, 1, shared.allowed)
+}
+
+func TestLimiterPolicy(t *testing.T) {
+	rps := 1.0
+	policy := NewLimiterPolicy(NewRateLimiter(&rps, _defaultRPSTTL, 1))
+	assert.True(t, policy.Allow(Info{Domain: "domain-a"}))
+	assert.False(t, policy.Allow(Info{Domain: "domain-b"}))
+}
@@ -0,0 +1,115 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package redaction redacts the sensitive data of the domains, i.e. payloads, search attributes and identities,
+// before it reaches logs and error responses, according to per domain policies.
+package redaction
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+)
+
+const (
+	// FieldPayload is the kind of the payloads of the workflows, e.g. inputs, results and details
+	FieldPayload Field = iota + 1
+	// FieldSearchAttribute is the kind of the values of the search attributes
+	FieldSearchAttribute
+	// FieldIdentity is the kind of the identities of the callers, e.g. worker host names
+	FieldIdentity
+)
+
+const (
+	// PolicyNone keeps the values as is
+	PolicyNone = "none"
+	// PolicyMask replaces the values with a placeholder
+	PolicyMask = "mask"
+	// PolicyHash replaces the values with a prefix of their hash, so that they can be correlated without being revealed
+	PolicyHash = "hash"
+
+	// Masked replaces the values redacted with PolicyMask
+	Masked = "<redacted>"
+
+	hashPrefix = "sha256:"
+	hashSize   = 8
+)
+
+type (
+	// Field is a kind of sensitive data
+	Field int
+
+	// Redactor redacts the sensitive data of a domain, it can be implemented to plug a custom redaction
+	Redactor interface {
+		// Redact returns the value to log or return in an error instead of the value of the field
+		Redact(domainName string, field Field, value []byte) string
+	}
+
+	policyRedactor struct {
+		policies map[Field]dynamicconfig.StringPropertyFnWithDomainFilter
+	}
+
+	nopRedactor struct{}
+)
+
+// NewPolicyRedactor creates a redactor applying the redaction policies of the domains set in dynamic config
+func NewPolicyRedactor(dc *dynamicconfig.Collection) Redactor {
+	return &policyRedactor{
+		policies: map[Field]dynamicconfig.StringPropertyFnWithDomainFilter{
+			FieldPayload:         dc.GetStringPropertyFilteredByDomain(dynamicconfig.PayloadRedaction),
+			FieldSearchAttribute: dc.GetStringPropertyFilteredByDomain(dynamicconfig.SearchAttributeRedaction),
+			FieldIdentity:        dc.GetStringPropertyFilteredByDomain(dynamicconfig.IdentityRedaction),
+		},
+	}
+}
+
+// NewNopRedactor creates a redactor keeping the values as is
+func NewNopRedactor() Redactor {
+	return &nopRedactor{}
+}
+
+// Redact applies the policy of the domain for the field, unknown policies mask the values
+func (r *policyRedactor) Redact(domainName string, field Field, value []byte) string {
+	policy, ok := r.policies[field]
+	if !ok {
+		return string(value)
+	}
+	switch policy(domainName) {
+	case PolicyNone, "":
+		return string(value)
+	case PolicyHash:
+		return Hash(value)
+	default:
+		return Masked
+	}
+}
+
+func (r *nopRedactor) Redact(_ string, _ Field, value []byte) string {
+	return string(value)
+}
+
+// Hash returns the redacted value of PolicyHash
+func Hash(value []byte) string {
+	hash := sha256.Sum256(value)
+	return hashPrefix + hex.EncodeToString(hash[:hashSize])
+}
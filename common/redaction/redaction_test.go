@@ -0,0 +1,88 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package redaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+)
+
+func TestPolicyRedactor(t *testing.T) {
+	value := []byte("secret@host-1")
+	tests := map[string]struct {
+		policy   string
+		field    Field
+		expected string
+	}{
+		"none": {
+			policy:   PolicyNone,
+			field:    FieldIdentity,
+			expected: "secret@host-1",
+		},
+		"mask": {
+			policy:   PolicyMask,
+			field:    FieldIdentity,
+			expected: Masked,
+		},
+		"hash": {
+			policy:   PolicyHash,
+			field:    FieldIdentity,
+			expected: Hash(value),
+		},
+		"unknown policy masks": {
+			policy:   "encrypt",
+			field:    FieldIdentity,
+			expected: Masked,
+		},
+		"policy of another field": {
+			policy:   PolicyMask,
+			field:    FieldPayload,
+			expected: "secret@host-1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dcClient := dynamicconfig.NewInMemoryClient()
+			require.NoError(t, dcClient.UpdateValue(dynamicconfig.IdentityRedaction, test.policy))
+			redactor := NewPolicyRedactor(dynamicconfig.NewCollection(dcClient, log.NewNoop()))
+
+			assert.Equal(t, test.expected, redactor.Redact("domain", test.field, value))
+		})
+	}
+}
+
+func TestNopRedactor(t *testing.T) {
+	assert.Equal(t, "value", NewNopRedactor().Redact("domain", FieldPayload, []byte("value")))
+}
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, Hash([]byte("value")), Hash([]byte("value")))
+	assert.NotEqual(t, Hash([]byte("value")), Hash([]byte("other")))
+	assert.Len(t, Hash([]byte("value")), len(hashPrefix)+2*hashSize)
+}
@@ -0,0 +1,630 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resharding
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/codec"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/types/mapper/thrift"
+)
+
+const (
+	// rangeSizeBits is the number of task IDs of a shard range, it matches the range size of the history service
+	rangeSizeBits = 20
+	// shardOwner is the owner of the shards fenced by the migration
+	shardOwner = "resharding"
+
+	migrationPageSize = 100
+)
+
+var maxTimerTimestamp = time.Unix(0, math.MaxInt64)
+
+type (
+	executionKey struct {
+		domainID   string
+		workflowID string
+		runID      string
+	}
+
+	// SplitResult describes the split of a shard
+	SplitResult struct {
+		SourceShardID int
+		TargetShardID int
+		// Executions are the moved executions, including the closed ones
+		Executions    int
+		TransferTasks int
+		TimerTasks    int
+		// PausedFor is how long the source shard was paused
+		PausedFor time.Duration
+	}
+
+	// shardMigrator moves the executions of a shard which are routed to another shard once the shard count is doubled.
+	// Both shards are fenced by renewing their range, so the history hosts stop writing to them, the executions are
+	// then copied with their history and pending tasks to the target shard before being deleted from the source shard.
+	shardMigrator struct {
+		sourceShardID int
+		targetShardID int
+		moves         func(workflowID string) bool
+
+		shardManager   persistence.ShardManager
+		historyManager persistence.HistoryManager
+		domainManager  persistence.DomainManager
+		source         persistence.ExecutionManager
+		target         persistence.ExecutionManager
+		logger         log.Logger
+
+		encoder       *codec.ThriftRWEncoder
+		domainNames   map[string]string
+		targetRangeID int64
+		nextTaskID    int64
+		maxTaskID     int64
+
+		transferTasks map[executionKey][]*persistence.TransferTaskInfo
+		timerTasks    map[executionKey][]*persistence.TimerTaskInfo
+		result        *SplitResult
+	}
+)
+
+func (m *shardMigrator) migrate(ctx context.Context) error {
+	sourceInfo, err := m.renewRange(ctx, m.sourceShardID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fence shard %v: %v", m.sourceShardID, err)
+	}
+	if len(sourceInfo.ClusterReplicationLevel) > 0 {
+		return fmt.Errorf("shard %v is replicated to other clusters, replication tasks can't be migrated", m.sourceShardID)
+	}
+	targetInfo, err := m.renewRange(ctx, m.targetShardID, sourceInfo)
+	if err != nil {
+		return fmt.Errorf("failed to fence shard %v: %v", m.targetShardID, err)
+	}
+	m.setTargetRange(targetInfo.RangeID)
+
+	if err := m.loadTransferTasks(ctx, sourceInfo); err != nil {
+		return err
+	}
+	if err := m.loadTimerTasks(ctx, sourceInfo); err != nil {
+		return err
+	}
+
+	// the executions are listed before being moved, as deleting them could make the listing skip some of them
+	var executions []executionKey
+	var pageToken []byte
+	for {
+		resp, err := m.source.ListConcreteExecutions(ctx, &persistence.ListConcreteExecutionsRequest{
+			PageSize:  migrationPageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, execution := range resp.Executions {
+			if info := execution.ExecutionInfo; m.moves(info.WorkflowID) {
+				executions = append(executions, executionKey{domainID: info.DomainID, workflowID: info.WorkflowID, runID: info.RunID})
+			}
+		}
+		if pageToken = resp.PageToken; len(pageToken) == 0 {
+			break
+		}
+	}
+
+	for _, execution := range executions {
+		if err := m.moveExecution(ctx, execution); err != nil {
+			return fmt.Errorf("failed to move workflow %v run %v: %v", execution.workflowID, execution.runID, err)
+		}
+	}
+	return nil
+}
+
+// renewRange fences a shard by incrementing its range, a missing shard is created from the source shard
+func (m *shardMigrator) renewRange(
+	ctx context.Context,
+	shardID int,
+	sourceInfo *persistence.ShardInfo,
+) (*persistence.ShardInfo, error) {
+	var shardInfo *persistence.ShardInfo
+	resp, err := m.shardManager.GetShard(ctx, &persistence.GetShardRequest{ShardID: shardID})
+	switch err.(type) {
+	case nil:
+		shardInfo = resp.ShardInfo
+	case *types.EntityNotExistsError:
+		if sourceInfo == nil {
+			return nil, err
+		}
+		shardInfo = &persistence.ShardInfo{
+			ShardID:                   shardID,
+			DomainNotificationVersion: sourceInfo.DomainNotificationVersion,
+		}
+		if err := m.shardManager.CreateShard(ctx, &persistence.CreateShardRequest{ShardInfo: shardInfo}); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	updatedShardInfo := shardInfo.Copy()
+	updatedShardInfo.RangeID++
+	updatedShardInfo.Owner = shardOwner
+	updatedShardInfo.UpdatedAt = time.Now()
+	if err := m.shardManager.UpdateShard(ctx, &persistence.UpdateShardRequest{
+		ShardInfo:       updatedShardInfo,
+		PreviousRangeID: shardInfo.RangeID,
+	}); err != nil {
+		return nil, err
+	}
+	return updatedShardInfo, nil
+}
+
+func (m *shardMigrator) setTargetRange(rangeID int64) {
+	m.targetRangeID = rangeID
+	m.nextTaskID = rangeID << rangeSizeBits
+	m.maxTaskID = (rangeID + 1) << rangeSizeBits
+}
+
+// allocateTaskID returns the next task ID of the target range, the range is renewed once exhausted
+func (m *shardMigrator) allocateTaskID(ctx context.Context) (int64, error) {
+	if m.nextTaskID >= m.maxTaskID {
+		shardInfo, err := m.renewRange(ctx, m.targetShardID, nil)
+		if err != nil {
+			return 0, err
+		}
+		m.setTargetRange(shardInfo.RangeID)
+	}
+	taskID := m.nextTaskID
+	m.nextTaskID++
+	return taskID, nil
+}
+
+// loadTransferTasks reads the pending transfer tasks of the moved executions, the tasks written by the
+// previous owners of the source shard are all below the fenced range
+func (m *shardMigrator) loadTransferTasks(ctx context.Context, sourceInfo *persistence.ShardInfo) error {
+	request := &persistence.GetTransferTasksRequest{
+		ReadLevel:    sourceInfo.TransferAckLevel,
+		MaxReadLevel: sourceInfo.RangeID << rangeSizeBits,
+		BatchSize:    migrationPageSize,
+	}
+	for {
+		resp, err := m.source.GetTransferTasks(ctx, request)
+		if err != nil {
+			return err
+		}
+		for _, task := range resp.Tasks {
+			if m.moves(task.WorkflowID) {
+				key := executionKey{domainID: task.DomainID, workflowID: task.WorkflowID, runID: task.RunID}
+				m.transferTasks[key] = append(m.transferTasks[key], task)
+			}
+		}
+		if request.NextPageToken = resp.NextPageToken; len(request.NextPageToken) == 0 {
+			return nil
+		}
+	}
+}
+
+func (m *shardMigrator) loadTimerTasks(ctx context.Context, sourceInfo *persistence.ShardInfo) error {
+	request := &persistence.GetTimerIndexTasksRequest{
+		MinTimestamp: sourceInfo.TimerAckLevel,
+		MaxTimestamp: maxTimerTimestamp,
+		BatchSize:    migrationPageSize,
+	}
+	for {
+		resp, err := m.source.GetTimerIndexTasks(ctx, request)
+		if err != nil {
+			return err
+		}
+		for _, task := range resp.Timers {
+			if m.moves(task.WorkflowID) {
+				key := executionKey{domainID: task.DomainID, workflowID: task.WorkflowID, runID: task.RunID}
+				m.timerTasks[key] = append(m.timerTasks[key], task)
+			}
+		}
+		if request.NextPageToken = resp.NextPageToken; len(request.NextPageToken) == 0 {
+			return nil
+		}
+	}
+}
+
+// moveExecution copies an execution to the target shard and deletes it from the source shard. The copies
+// left by a previous attempt are overwritten, so a failed migration can be retried.
+func (m *shardMigrator) moveExecution(ctx context.Context, key executionKey) error {
+	domainName, err := m.getDomainName(ctx, key.domainID)
+	if err != nil {
+		return err
+	}
+	resp, err := m.source.GetWorkflowExecution(ctx, &persistence.GetWorkflowExecutionRequest{
+		DomainID:   key.domainID,
+		Execution:  types.WorkflowExecution{WorkflowID: key.workflowID, RunID: key.runID},
+		DomainName: domainName,
+	})
+	if _, ok := err.(*types.EntityNotExistsError); ok {
+		// the execution was deleted by its retention meanwhile
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	state := resp.State
+
+	isCurrent := false
+	currentResp, err := m.source.GetCurrentExecution(ctx, &persistence.GetCurrentExecutionRequest{
+		DomainID:   key.domainID,
+		WorkflowID: key.workflowID,
+		DomainName: domainName,
+	})
+	switch err.(type) {
+	case nil:
+		isCurrent = currentResp.RunID == key.runID
+	case *types.EntityNotExistsError:
+	default:
+		return err
+	}
+
+	if err := m.copyHistory(ctx, key, domainName, state); err != nil {
+		return err
+	}
+	if err := m.copyExecution(ctx, key, domainName, state, isCurrent); err != nil {
+		return err
+	}
+
+	for _, task := range m.transferTasks[key] {
+		if err := m.source.CompleteTransferTask(ctx, &persistence.CompleteTransferTaskRequest{TaskID: task.TaskID}); err != nil {
+			return err
+		}
+	}
+	for _, task := range m.timerTasks[key] {
+		if err := m.source.CompleteTimerTask(ctx, &persistence.CompleteTimerTaskRequest{
+			VisibilityTimestamp: task.VisibilityTimestamp,
+			TaskID:              task.TaskID,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := m.source.DeleteWorkflowExecution(ctx, &persistence.DeleteWorkflowExecutionRequest{
+		DomainID:   key.domainID,
+		WorkflowID: key.workflowID,
+		RunID:      key.runID,
+		DomainName: domainName,
+	}); err != nil {
+		return err
+	}
+	if isCurrent {
+		if err := m.source.DeleteCurrentWorkflowExecution(ctx, &persistence.DeleteCurrentWorkflowExecutionRequest{
+			DomainID:   key.domainID,
+			WorkflowID: key.workflowID,
+			RunID:      key.runID,
+			DomainName: domainName,
+		}); err != nil {
+			return err
+		}
+	}
+
+	m.result.Executions++
+	m.result.TransferTasks += len(m.transferTasks[key])
+	m.result.TimerTasks += len(m.timerTasks[key])
+	return nil
+}
+
+// copyExecution writes the mutable state to the target shard. The executions can only be created running or
+// as zombies, so they are created that way and then updated to their actual state with their pending tasks.
+// The runs which are neither current nor closed are copied as zombies.
+func (m *shardMigrator) copyExecution(
+	ctx context.Context,
+	key executionKey,
+	domainName string,
+	state *persistence.WorkflowMutableState,
+	isCurrent bool,
+) error {
+	if err := m.target.DeleteWorkflowExecution(ctx, &persistence.DeleteWorkflowExecutionRequest{
+		DomainID:   key.domainID,
+		WorkflowID: key.workflowID,
+		RunID:      key.runID,
+		DomainName: domainName,
+	}); err != nil {
+		return err
+	}
+	if isCurrent {
+		if err := m.target.DeleteCurrentWorkflowExecution(ctx, &persistence.DeleteCurrentWorkflowExecutionRequest{
+			DomainID:   key.domainID,
+			WorkflowID: key.workflowID,
+			RunID:      key.runID,
+			DomainName: domainName,
+		}); err != nil {
+			return err
+		}
+	}
+
+	executionInfo := state.ExecutionInfo
+	createdInfo := *executionInfo
+	createMode := persistence.CreateWorkflowModeZombie
+	createdInfo.State = persistence.WorkflowStateZombie
+	createdInfo.CloseStatus = persistence.WorkflowCloseStatusNone
+	if isCurrent {
+		createMode = persistence.CreateWorkflowModeBrandNew
+		if executionInfo.State == persistence.WorkflowStateCompleted {
+			createdInfo.State = persistence.WorkflowStateRunning
+		} else {
+			createdInfo.State = executionInfo.State
+		}
+	}
+
+	snapshot := persistence.WorkflowSnapshot{
+		ExecutionInfo:      &createdInfo,
+		ExecutionStats:     state.ExecutionStats,
+		VersionHistories:   state.VersionHistories,
+		SignalRequestedIDs: make([]string, 0, len(state.SignalRequestedIDs)),
+		Checksum:           state.Checksum,
+	}
+	for _, info := range state.ActivityInfos {
+		snapshot.ActivityInfos = append(snapshot.ActivityInfos, info)
+	}
+	for _, info := range state.TimerInfos {
+		snapshot.TimerInfos = append(snapshot.TimerInfos, info)
+	}
+	for _, info := range state.ChildExecutionInfos {
+		snapshot.ChildExecutionInfos = append(snapshot.ChildExecutionInfos, info)
+	}
+	for _, info := range state.RequestCancelInfos {
+		snapshot.RequestCancelInfos = append(snapshot.RequestCancelInfos, info)
+	}
+	for _, info := range state.SignalInfos {
+		snapshot.SignalInfos = append(snapshot.SignalInfos, info)
+	}
+	for signalRequestedID := range state.SignalRequestedIDs {
+		snapshot.SignalRequestedIDs = append(snapshot.SignalRequestedIDs, signalRequestedID)
+	}
+	if _, err := m.target.CreateWorkflowExecution(ctx, &persistence.CreateWorkflowExecutionRequest{
+		RangeID:             m.targetRangeID,
+		Mode:                createMode,
+		NewWorkflowSnapshot: snapshot,
+		DomainName:          domainName,
+	}); err != nil {
+		return err
+	}
+
+	mutation := persistence.WorkflowMutation{
+		ExecutionInfo:     executionInfo,
+		ExecutionStats:    state.ExecutionStats,
+		VersionHistories:  state.VersionHistories,
+		NewBufferedEvents: state.BufferedEvents,
+		Condition:         executionInfo.NextEventID,
+		Checksum:          state.Checksum,
+	}
+	if !isCurrent && executionInfo.State != persistence.WorkflowStateCompleted {
+		zombieInfo := *executionInfo
+		zombieInfo.State = persistence.WorkflowStateZombie
+		mutation.ExecutionInfo = &zombieInfo
+	}
+	for _, info := range m.transferTasks[key] {
+		task, err := newTransferTask(info)
+		if err != nil {
+			return err
+		}
+		if err := m.setTaskID(ctx, task); err != nil {
+			return err
+		}
+		mutation.TransferTasks = append(mutation.TransferTasks, task)
+	}
+	for _, info := range m.timerTasks[key] {
+		task, err := newTimerTask(info)
+		if err != nil {
+			return err
+		}
+		if err := m.setTaskID(ctx, task); err != nil {
+			return err
+		}
+		mutation.TimerTasks = append(mutation.TimerTasks, task)
+	}
+	updateMode := persistence.UpdateWorkflowModeBypassCurrent
+	if isCurrent {
+		updateMode = persistence.UpdateWorkflowModeUpdateCurrent
+	}
+	_, err := m.target.UpdateWorkflowExecution(ctx, &persistence.UpdateWorkflowExecutionRequest{
+		RangeID:                m.targetRangeID,
+		Mode:                   updateMode,
+		UpdateWorkflowMutation: mutation,
+		DomainName:             domainName,
+	})
+	return err
+}
+
+func (m *shardMigrator) setTaskID(ctx context.Context, task persistence.Task) error {
+	taskID, err := m.allocateTaskID(ctx)
+	if err != nil {
+		return err
+	}
+	task.SetTaskID(taskID)
+	return nil
+}
+
+// copyHistory writes the history branches of the execution to the target shard, which is a noop for the stores
+// whose history isn't partitioned by shard. Each batch is appended to the branch owning it, and the tree of
+// a branch is only written with its last batch, so the branches found in the target tree are fully copied.
+// The history of the source shard is left in place, as it's shared by all the shards in the other stores.
+func (m *shardMigrator) copyHistory(
+	ctx context.Context,
+	key executionKey,
+	domainName string,
+	state *persistence.WorkflowMutableState,
+) error {
+	type branchToCopy struct {
+		token       []byte
+		nextEventID int64
+	}
+	var branches []branchToCopy
+	if state.VersionHistories != nil {
+		for _, versionHistory := range state.VersionHistories.Histories {
+			lastItem, err := versionHistory.GetLastItem()
+			if err != nil {
+				return err
+			}
+			branches = append(branches, branchToCopy{token: versionHistory.GetBranchToken(), nextEventID: lastItem.EventID + 1})
+		}
+	} else {
+		branches = append(branches, branchToCopy{token: state.ExecutionInfo.BranchToken, nextEventID: state.ExecutionInfo.NextEventID})
+	}
+
+	info := persistence.BuildHistoryGarbageCleanupInfo(key.domainID, key.workflowID, key.runID)
+	for _, branch := range branches {
+		if err := m.copyHistoryBranch(ctx, branch.token, branch.nextEventID, info, domainName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *shardMigrator) copyHistoryBranch(
+	ctx context.Context,
+	branchToken []byte,
+	nextEventID int64,
+	info string,
+	domainName string,
+) error {
+	var thriftBranch workflow.HistoryBranch
+	if err := m.encoder.Decode(branchToken, &thriftBranch); err != nil {
+		return err
+	}
+	branch := thrift.ToHistoryBranch(&thriftBranch)
+
+	treeResp, err := m.historyManager.GetHistoryTree(ctx, &persistence.GetHistoryTreeRequest{
+		TreeID:     branch.TreeID,
+		ShardID:    common.IntPtr(m.targetShardID),
+		DomainName: domainName,
+	})
+	if err != nil {
+		return err
+	}
+	for _, copied := range treeResp.Branches {
+		if copied.GetBranchID() == branch.BranchID {
+			return nil
+		}
+	}
+
+	var pending *types.History
+	request := &persistence.ReadHistoryBranchRequest{
+		BranchToken: branchToken,
+		MinEventID:  common.FirstEventID,
+		MaxEventID:  nextEventID,
+		PageSize:    migrationPageSize,
+		ShardID:     common.IntPtr(m.sourceShardID),
+		DomainName:  domainName,
+	}
+	for {
+		resp, err := m.historyManager.ReadHistoryBranchByBatch(ctx, request)
+		if err != nil {
+			return err
+		}
+		for _, batch := range resp.History {
+			if len(batch.Events) == 0 {
+				continue
+			}
+			if pending != nil {
+				if err := m.appendHistory(ctx, branch, pending, false, info, domainName); err != nil {
+					return err
+				}
+			}
+			pending = batch
+		}
+		if request.NextPageToken = resp.NextPageToken; len(request.NextPageToken) == 0 {
+			break
+		}
+	}
+	if pending == nil {
+		return nil
+	}
+	return m.appendHistory(ctx, branch, pending, true, info, domainName)
+}
+
+// appendHistory appends a batch to the branch owning its events, either the branch or one of its ancestors
+func (m *shardMigrator) appendHistory(
+	ctx context.Context,
+	branch *types.HistoryBranch,
+	batch *types.History,
+	isLast bool,
+	info string,
+	domainName string,
+) error {
+	owner := branch
+	firstEventID := batch.Events[0].ID
+	for i, ancestor := range branch.Ancestors {
+		if firstEventID >= ancestor.BeginNodeID && firstEventID < ancestor.EndNodeID {
+			owner = &types.HistoryBranch{
+				TreeID:    branch.TreeID,
+				BranchID:  ancestor.BranchID,
+				Ancestors: branch.Ancestors[:i],
+			}
+			break
+		}
+	}
+	token, err := m.encoder.Encode(thrift.FromHistoryBranch(owner))
+	if err != nil {
+		return err
+	}
+
+	// the transaction IDs of the copied batches increase with their node IDs, and stay below the ones allocated
+	// by the history hosts, whichever branch and retry the batches are copied with. The last batch of the branch
+	// is written with a larger transaction ID than the other copies of its node, as it's written with the tree.
+	isNewBranch := isLast && owner == branch
+	transactionID := firstEventID
+	if isNewBranch {
+		transactionID++
+	}
+	_, err = m.historyManager.AppendHistoryNodes(ctx, &persistence.AppendHistoryNodesRequest{
+		IsNewBranch:   isNewBranch,
+		Info:          info,
+		BranchToken:   token,
+		Events:        batch.Events,
+		TransactionID: transactionID,
+		ShardID:       common.IntPtr(m.targetShardID),
+		DomainName:    domainName,
+	})
+	if _, ok := err.(*persistence.ConditionFailedError); ok && !isNewBranch {
+		// the batch was copied by a previous attempt or with another branch of the tree
+		return nil
+	}
+	return err
+}
+
+func (m *shardMigrator) getDomainName(ctx context.Context, domainID string) (string, error) {
+	if name, ok := m.domainNames[domainID]; ok {
+		return name, nil
+	}
+	resp, err := m.domainManager.GetDomain(ctx, &persistence.GetDomainRequest{ID: domainID})
+	if _, ok := err.(*types.EntityNotExistsError); ok {
+		m.logger.Warn("Moving executions of a deleted domain", tag.WorkflowDomainID(domainID))
+		m.domainNames[domainID] = ""
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	m.domainNames[domainID] = resp.Info.Name
+	return resp.Info.Name, nil
+}
@@ -0,0 +1,254 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resharding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/codec"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/persistence"
+)
+
+const reshardingUpdateMaxAttempts = 5
+
+type (
+	// Resharder doubles the history shard count of a cluster, by splitting its shards one at a time. Each shard is
+	// paused while its executions moving to the new shard are migrated, the history hosts stop owning a paused shard
+	// once they reload the resharding state, so the pause must be longer than the refresh interval of the services.
+	// The replication tasks aren't migrated, the clusters replicating global domains can't be resharded.
+	Resharder struct {
+		store              persistence.ConfigStoreManager
+		shardManager       persistence.ShardManager
+		historyManager     persistence.HistoryManager
+		domainManager      persistence.DomainManager
+		executionManagerFn func(shardID int) (persistence.ExecutionManager, error)
+		propagationDelay   time.Duration
+		timeSource         clock.TimeSource
+		logger             log.Logger
+	}
+)
+
+// NewResharder creates a resharder, the propagation delay is how long to wait for the services to reload the
+// resharding state after a shard is paused
+func NewResharder(
+	store persistence.ConfigStoreManager,
+	shardManager persistence.ShardManager,
+	historyManager persistence.HistoryManager,
+	domainManager persistence.DomainManager,
+	executionManagerFn func(shardID int) (persistence.ExecutionManager, error),
+	propagationDelay time.Duration,
+	timeSource clock.TimeSource,
+	logger log.Logger,
+) *Resharder {
+	return &Resharder{
+		store:              store,
+		shardManager:       shardManager,
+		historyManager:     historyManager,
+		domainManager:      domainManager,
+		executionManagerFn: executionManagerFn,
+		propagationDelay:   propagationDelay,
+		timeSource:         timeSource,
+		logger:             logger,
+	}
+}
+
+// StartResharding starts doubling the shard count, the number of shards is the one the services are configured with.
+// A new resharding can only start once the services are configured with the shard count of the previous one.
+func (r *Resharder) StartResharding(ctx context.Context, numberOfShards int) (*persistence.ReshardingSnapshot, error) {
+	if numberOfShards <= 0 {
+		return nil, fmt.Errorf("invalid number of shards %v", numberOfShards)
+	}
+	return r.update(ctx, func(snapshot *persistence.ReshardingSnapshot) error {
+		switch {
+		case snapshot.FromShardCount == numberOfShards && len(snapshot.SplitShards) < snapshot.FromShardCount:
+			return fmt.Errorf("resharding from %v shards is already started", numberOfShards)
+		case snapshot.FromShardCount == numberOfShards:
+			return fmt.Errorf("resharding from %v shards is done, the services must be configured with %v shards",
+				numberOfShards, snapshot.ToShardCount)
+		case snapshot.ToShardCount != 0 && snapshot.ToShardCount != numberOfShards:
+			return fmt.Errorf("the last resharding was to %v shards, not %v", snapshot.ToShardCount, numberOfShards)
+		}
+		*snapshot = persistence.ReshardingSnapshot{
+			Version:        snapshot.Version,
+			FromShardCount: numberOfShards,
+			ToShardCount:   2 * numberOfShards,
+			StartedTime:    r.timeSource.Now(),
+		}
+		return nil
+	})
+}
+
+// DescribeResharding returns the state of the resharding, nil if the shard count was never changed
+func (r *Resharder) DescribeResharding(ctx context.Context) (*persistence.ReshardingSnapshot, error) {
+	resp, err := r.store.FetchResharding(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Snapshot, nil
+}
+
+// SplitShard moves the executions of a shard which belong to its new shard once the shard count is doubled. The shard
+// is paused until its executions are moved, so the split of a paused shard can be retried after a failure.
+func (r *Resharder) SplitShard(ctx context.Context, shardID int) (*SplitResult, error) {
+	snapshot, err := r.update(ctx, func(snapshot *persistence.ReshardingSnapshot) error {
+		if err := validateSplit(snapshot, shardID); err != nil {
+			return err
+		}
+		if !containsShard(snapshot.PausedShards, shardID) {
+			snapshot.PausedShards = append(snapshot.PausedShards, shardID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	pausedTime := r.timeSource.Now()
+	r.logger.Info("Paused shard for resharding", tag.ShardID(shardID))
+
+	select {
+	case <-time.After(r.propagationDelay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	targetShardID := shardID + snapshot.FromShardCount
+	source, err := r.executionManagerFn(shardID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := r.executionManagerFn(targetShardID)
+	if err != nil {
+		return nil, err
+	}
+	result := &SplitResult{SourceShardID: shardID, TargetShardID: targetShardID}
+	migrator := &shardMigrator{
+		sourceShardID: shardID,
+		targetShardID: targetShardID,
+		moves: func(workflowID string) bool {
+			return common.WorkflowIDToHistoryShard(workflowID, snapshot.ToShardCount) == targetShardID
+		},
+		shardManager:   r.shardManager,
+		historyManager: r.historyManager,
+		domainManager:  r.domainManager,
+		source:         source,
+		target:         target,
+		logger:         r.logger.WithTags(tag.ShardID(shardID)),
+		encoder:        codec.NewThriftRWEncoder(),
+		domainNames:    make(map[string]string),
+		transferTasks:  make(map[executionKey][]*persistence.TransferTaskInfo),
+		timerTasks:     make(map[executionKey][]*persistence.TimerTaskInfo),
+		result:         result,
+	}
+	if err := migrator.migrate(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.update(ctx, func(snapshot *persistence.ReshardingSnapshot) error {
+		if err := validateSplit(snapshot, shardID); err != nil {
+			return err
+		}
+		paused := snapshot.PausedShards[:0]
+		for _, pausedShardID := range snapshot.PausedShards {
+			if pausedShardID != shardID {
+				paused = append(paused, pausedShardID)
+			}
+		}
+		snapshot.PausedShards = paused
+		snapshot.SplitShards = append(snapshot.SplitShards, shardID)
+		sort.Ints(snapshot.SplitShards)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	result.PausedFor = r.timeSource.Now().Sub(pausedTime)
+	r.logger.Info("Split shard for resharding",
+		tag.ShardID(shardID),
+		tag.Number(int64(result.Executions)),
+		tag.Latency(result.PausedFor),
+	)
+	return result, nil
+}
+
+// update writes a new version of the resharding state, the update is retried if another version is written meanwhile
+func (r *Resharder) update(
+	ctx context.Context,
+	updateFn func(snapshot *persistence.ReshardingSnapshot) error,
+) (*persistence.ReshardingSnapshot, error) {
+	var err error
+	for attempt := 0; attempt < reshardingUpdateMaxAttempts; attempt++ {
+		var resp *persistence.FetchReshardingResponse
+		resp, err = r.store.FetchResharding(ctx)
+		if err != nil {
+			return nil, err
+		}
+		snapshot := &persistence.ReshardingSnapshot{}
+		if resp.Snapshot != nil {
+			*snapshot = *resp.Snapshot
+			snapshot.SplitShards = append([]int(nil), resp.Snapshot.SplitShards...)
+			snapshot.PausedShards = append([]int(nil), resp.Snapshot.PausedShards...)
+		}
+		if err := updateFn(snapshot); err != nil {
+			return nil, err
+		}
+		snapshot.Version++
+
+		err = r.store.UpdateResharding(ctx, &persistence.UpdateReshardingRequest{Snapshot: snapshot})
+		if _, ok := err.(*persistence.ConditionFailedError); ok {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return snapshot, nil
+	}
+	return nil, err
+}
+
+func validateSplit(snapshot *persistence.ReshardingSnapshot, shardID int) error {
+	if snapshot.FromShardCount == 0 || len(snapshot.SplitShards) == snapshot.FromShardCount {
+		return fmt.Errorf("resharding is not started")
+	}
+	if shardID < 0 || shardID >= snapshot.FromShardCount {
+		return fmt.Errorf("shard %v is not one of the %v shards being split", shardID, snapshot.FromShardCount)
+	}
+	if containsShard(snapshot.SplitShards, shardID) {
+		return fmt.Errorf("shard %v is already split", shardID)
+	}
+	return nil
+}
+
+func containsShard(shardIDs []int, shardID int) bool {
+	for _, id := range shardIDs {
+		if id == shardID {
+			return true
+		}
+	}
+	return false
+}
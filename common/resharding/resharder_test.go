@@ -0,0 +1,121 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resharding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/persistence"
+)
+
+func TestStartResharding(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		snapshot *persistence.ReshardingSnapshot
+		expected *persistence.ReshardingSnapshot
+		errorMsg string
+	}{
+		"first resharding": {
+			expected: &persistence.ReshardingSnapshot{Version: 1, FromShardCount: 4, ToShardCount: 8, StartedTime: now},
+		},
+		"after a done resharding": {
+			snapshot: &persistence.ReshardingSnapshot{Version: 6, FromShardCount: 2, ToShardCount: 4, SplitShards: []int{0, 1}},
+			expected: &persistence.ReshardingSnapshot{Version: 7, FromShardCount: 4, ToShardCount: 8, StartedTime: now},
+		},
+		"already started": {
+			snapshot: &persistence.ReshardingSnapshot{Version: 2, FromShardCount: 4, ToShardCount: 8, SplitShards: []int{0}},
+			errorMsg: "resharding from 4 shards is already started",
+		},
+		"services not reconfigured": {
+			snapshot: &persistence.ReshardingSnapshot{Version: 5, FromShardCount: 4, ToShardCount: 8, SplitShards: []int{0, 1, 2, 3}},
+			errorMsg: "resharding from 4 shards is done, the services must be configured with 8 shards",
+		},
+		"other shard count": {
+			snapshot: &persistence.ReshardingSnapshot{Version: 3, FromShardCount: 1, ToShardCount: 2, SplitShards: []int{0}},
+			errorMsg: "the last resharding was to 2 shards, not 4",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := persistence.NewMockConfigStoreManager(ctrl)
+			store.EXPECT().FetchResharding(gomock.Any()).Return(&persistence.FetchReshardingResponse{Snapshot: test.snapshot}, nil).Times(1)
+			if test.expected != nil {
+				store.EXPECT().UpdateResharding(gomock.Any(), &persistence.UpdateReshardingRequest{Snapshot: test.expected}).Return(nil).Times(1)
+			}
+			resharder := NewResharder(store, nil, nil, nil, nil, 0, clock.NewEventTimeSource().Update(now), log.NewNoop())
+
+			snapshot, err := resharder.StartResharding(context.Background(), 4)
+			if test.errorMsg != "" {
+				assert.EqualError(t, err, test.errorMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, snapshot)
+		})
+	}
+}
+
+func TestReshardingUpdateRetriesConditionFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := persistence.NewMockConfigStoreManager(ctrl)
+	gomock.InOrder(
+		store.EXPECT().FetchResharding(gomock.Any()).Return(&persistence.FetchReshardingResponse{
+			Snapshot: &persistence.ReshardingSnapshot{Version: 1, FromShardCount: 2, ToShardCount: 4},
+		}, nil),
+		store.EXPECT().UpdateResharding(gomock.Any(), gomock.Any()).Return(&persistence.ConditionFailedError{}),
+		store.EXPECT().FetchResharding(gomock.Any()).Return(&persistence.FetchReshardingResponse{
+			Snapshot: &persistence.ReshardingSnapshot{Version: 2, FromShardCount: 2, ToShardCount: 4, PausedShards: []int{0}},
+		}, nil),
+		store.EXPECT().UpdateResharding(gomock.Any(), gomock.Any()).Return(nil),
+	)
+	resharder := NewResharder(store, nil, nil, nil, nil, 0, clock.NewRealTimeSource(), log.NewNoop())
+
+	snapshot, err := resharder.update(context.Background(), func(snapshot *persistence.ReshardingSnapshot) error {
+		snapshot.PausedShards = append(snapshot.PausedShards, 1)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), snapshot.Version)
+	assert.Equal(t, []int{0, 1}, snapshot.PausedShards)
+}
+
+func TestValidateSplit(t *testing.T) {
+	snapshot := &persistence.ReshardingSnapshot{FromShardCount: 4, ToShardCount: 8, SplitShards: []int{1}}
+
+	assert.NoError(t, validateSplit(snapshot, 0))
+	assert.EqualError(t, validateSplit(snapshot, 1), "shard 1 is already split")
+	assert.EqualError(t, validateSplit(snapshot, 4), "shard 4 is not one of the 4 shards being split")
+	assert.EqualError(t, validateSplit(&persistence.ReshardingSnapshot{}, 0), "resharding is not started")
+}
@@ -0,0 +1,291 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resharding
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/persistence"
+)
+
+const refreshTimeout = 10 * time.Second
+
+type (
+	// Router maps workflows and domains to history shards. While the shard count is being doubled,
+	// the workflows of a shard keep being routed to it until the shard is split.
+	Router interface {
+		common.Daemon
+
+		// WorkflowIDToShard returns the shard of a workflow
+		WorkflowIDToShard(workflowID string) int
+		// DomainIDToShard returns the shard of a domain
+		DomainIDToShard(domainID string) int
+		// ShardIDs returns the shards owned by the history hosts, the paused shards are excluded
+		ShardIDs() []int
+		// IsShardActive checks if a shard can be owned by a history host
+		IsShardActive(shardID int) bool
+		// UpdatedCh is notified when the shards or the routing change, it must only be consumed by one listener
+		UpdatedCh() <-chan struct{}
+	}
+
+	// routing is the shard mapping of a resharding snapshot
+	routing struct {
+		fromShardCount int
+		toShardCount   int
+		split          map[int]struct{}
+		paused         map[int]struct{}
+	}
+
+	staticRouter struct {
+		routing *routing
+	}
+
+	router struct {
+		status          int32
+		numberOfShards  int
+		store           persistence.ConfigStoreManager
+		refreshInterval dynamicconfig.DurationPropertyFn
+		logger          log.Logger
+
+		routing    atomic.Value
+		version    int64
+		updatedCh  chan struct{}
+		shutdownC  chan struct{}
+		shutdownWG sync.WaitGroup
+	}
+)
+
+var _ Router = (*staticRouter)(nil)
+var _ Router = (*router)(nil)
+
+// NewStaticRouter creates a router for a fixed number of shards
+func NewStaticRouter(numberOfShards int) Router {
+	return &staticRouter{routing: newRouting(numberOfShards, nil)}
+}
+
+// NewRouter creates a router following the resharding state of the config store, the state is loaded on start
+// and reloaded at every refresh interval. The snapshots of a resharding from a shard count other than the
+// configured one are ignored, so that the router maps to the new shards once the configured count is doubled.
+func NewRouter(
+	numberOfShards int,
+	store persistence.ConfigStoreManager,
+	refreshInterval dynamicconfig.DurationPropertyFn,
+	logger log.Logger,
+) Router {
+	r := &router{
+		status:          common.DaemonStatusInitialized,
+		numberOfShards:  numberOfShards,
+		store:           store,
+		refreshInterval: refreshInterval,
+		logger:          logger,
+		updatedCh:       make(chan struct{}, 1),
+		shutdownC:       make(chan struct{}),
+	}
+	r.routing.Store(newRouting(numberOfShards, nil))
+	return r
+}
+
+func (r *staticRouter) Start() {}
+
+func (r *staticRouter) Stop() {}
+
+func (r *staticRouter) WorkflowIDToShard(workflowID string) int {
+	return r.routing.workflowIDToShard(workflowID)
+}
+
+func (r *staticRouter) DomainIDToShard(domainID string) int {
+	return r.routing.domainIDToShard(domainID)
+}
+
+func (r *staticRouter) ShardIDs() []int {
+	return r.routing.shardIDs()
+}
+
+func (r *staticRouter) IsShardActive(shardID int) bool {
+	return r.routing.isShardActive(shardID)
+}
+
+func (r *staticRouter) UpdatedCh() <-chan struct{} {
+	return nil
+}
+
+// Start loads the resharding state before returning, so that the shards aren't routed with a stale state
+func (r *router) Start() {
+	if !atomic.CompareAndSwapInt32(&r.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	r.refresh()
+	r.shutdownWG.Add(1)
+	go r.refreshLoop()
+}
+
+func (r *router) Stop() {
+	if !atomic.CompareAndSwapInt32(&r.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+
+	close(r.shutdownC)
+	r.shutdownWG.Wait()
+}
+
+func (r *router) WorkflowIDToShard(workflowID string) int {
+	return r.getRouting().workflowIDToShard(workflowID)
+}
+
+func (r *router) DomainIDToShard(domainID string) int {
+	return r.getRouting().domainIDToShard(domainID)
+}
+
+func (r *router) ShardIDs() []int {
+	return r.getRouting().shardIDs()
+}
+
+func (r *router) IsShardActive(shardID int) bool {
+	return r.getRouting().isShardActive(shardID)
+}
+
+func (r *router) UpdatedCh() <-chan struct{} {
+	return r.updatedCh
+}
+
+func (r *router) getRouting() *routing {
+	return r.routing.Load().(*routing)
+}
+
+func (r *router) refreshLoop() {
+	defer r.shutdownWG.Done()
+
+	timer := time.NewTimer(r.refreshInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			r.refresh()
+			timer.Reset(r.refreshInterval())
+		case <-r.shutdownC:
+			return
+		}
+	}
+}
+
+func (r *router) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	resp, err := r.store.FetchResharding(ctx)
+	if err != nil {
+		// the previous state is used until the next refresh
+		r.logger.Error("Failed to refresh resharding state", tag.Error(err))
+		return
+	}
+	if resp.Snapshot == nil || resp.Snapshot.Version == r.version {
+		return
+	}
+
+	r.version = resp.Snapshot.Version
+	r.routing.Store(newRouting(r.numberOfShards, resp.Snapshot))
+	r.logger.Info("Resharding state updated",
+		tag.CurrentVersion(resp.Snapshot.Version),
+		tag.Number(int64(len(resp.Snapshot.SplitShards))),
+	)
+	select {
+	case r.updatedCh <- struct{}{}:
+	default:
+	}
+}
+
+func newRouting(numberOfShards int, snapshot *persistence.ReshardingSnapshot) *routing {
+	r := &routing{
+		fromShardCount: numberOfShards,
+		toShardCount:   numberOfShards,
+		split:          make(map[int]struct{}),
+		paused:         make(map[int]struct{}),
+	}
+	if snapshot == nil || snapshot.FromShardCount != numberOfShards {
+		return r
+	}
+
+	r.toShardCount = snapshot.ToShardCount
+	for _, shardID := range snapshot.SplitShards {
+		r.split[shardID] = struct{}{}
+	}
+	for _, shardID := range snapshot.PausedShards {
+		r.paused[shardID] = struct{}{}
+	}
+	return r
+}
+
+// workflowIDToShard relies on hash % 2n being either hash % n or hash % n + n,
+// so the workflows of shard s only move to shard s + n when the shard count is doubled
+func (r *routing) workflowIDToShard(workflowID string) int {
+	return r.toShardID(common.WorkflowIDToHistoryShard(workflowID, r.toShardCount))
+}
+
+func (r *routing) domainIDToShard(domainID string) int {
+	return r.toShardID(common.DomainIDToHistoryShard(domainID, r.toShardCount))
+}
+
+func (r *routing) toShardID(shardID int) int {
+	if shardID < r.fromShardCount {
+		return shardID
+	}
+	if _, ok := r.split[shardID-r.fromShardCount]; ok {
+		return shardID
+	}
+	return shardID - r.fromShardCount
+}
+
+func (r *routing) shardIDs() []int {
+	shardIDs := make([]int, 0, r.fromShardCount+len(r.split))
+	for shardID := 0; shardID < r.fromShardCount; shardID++ {
+		if _, ok := r.paused[shardID]; !ok {
+			shardIDs = append(shardIDs, shardID)
+		}
+	}
+	for shardID := range r.split {
+		shardIDs = append(shardIDs, shardID+r.fromShardCount)
+	}
+	sort.Ints(shardIDs)
+	return shardIDs
+}
+
+func (r *routing) isShardActive(shardID int) bool {
+	if shardID < 0 || shardID >= r.toShardCount {
+		return false
+	}
+	if shardID >= r.fromShardCount {
+		_, ok := r.split[shardID-r.fromShardCount]
+		return ok
+	}
+	_, ok := r.paused[shardID]
+	return !ok
+}
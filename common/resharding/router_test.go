@@ -0,0 +1,123 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resharding
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/persistence"
+)
+
+func TestStaticRouter(t *testing.T) {
+	router := NewStaticRouter(4)
+
+	assert.Equal(t, []int{0, 1, 2, 3}, router.ShardIDs())
+	assert.True(t, router.IsShardActive(3))
+	assert.False(t, router.IsShardActive(4))
+	assert.False(t, router.IsShardActive(-1))
+	assert.Nil(t, router.UpdatedCh())
+	for i := 0; i < 100; i++ {
+		workflowID := fmt.Sprintf("workflow-%v", i)
+		assert.Equal(t, common.WorkflowIDToHistoryShard(workflowID, 4), router.WorkflowIDToShard(workflowID))
+		assert.Equal(t, common.DomainIDToHistoryShard(workflowID, 4), router.DomainIDToShard(workflowID))
+	}
+}
+
+func TestRoutingDuringResharding(t *testing.T) {
+	routing := newRouting(4, &persistence.ReshardingSnapshot{
+		Version:        3,
+		FromShardCount: 4,
+		ToShardCount:   8,
+		SplitShards:    []int{0, 2},
+		PausedShards:   []int{1},
+	})
+
+	assert.Equal(t, []int{0, 2, 3, 4, 6}, routing.shardIDs())
+	assert.False(t, routing.isShardActive(1))
+	assert.False(t, routing.isShardActive(5))
+	assert.True(t, routing.isShardActive(6))
+	assert.False(t, routing.isShardActive(8))
+	for i := 0; i < 100; i++ {
+		workflowID := fmt.Sprintf("workflow-%v", i)
+		oldShardID := common.WorkflowIDToHistoryShard(workflowID, 4)
+		newShardID := common.WorkflowIDToHistoryShard(workflowID, 8)
+		switch oldShardID {
+		case 0, 2:
+			assert.Equal(t, newShardID, routing.workflowIDToShard(workflowID))
+		default:
+			assert.Equal(t, oldShardID, routing.workflowIDToShard(workflowID))
+		}
+	}
+}
+
+func TestRoutingIgnoresOtherShardCount(t *testing.T) {
+	// the services are configured with the doubled shard count once the resharding is done
+	routing := newRouting(8, &persistence.ReshardingSnapshot{
+		Version:        5,
+		FromShardCount: 4,
+		ToShardCount:   8,
+		SplitShards:    []int{0, 1, 2, 3},
+	})
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, routing.shardIDs())
+	for i := 0; i < 100; i++ {
+		workflowID := fmt.Sprintf("workflow-%v", i)
+		assert.Equal(t, common.WorkflowIDToHistoryShard(workflowID, 8), routing.workflowIDToShard(workflowID))
+	}
+}
+
+func TestRouterRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := persistence.NewMockConfigStoreManager(ctrl)
+	snapshot := &persistence.ReshardingSnapshot{Version: 1, FromShardCount: 2, ToShardCount: 4}
+	store.EXPECT().FetchResharding(gomock.Any()).Return(&persistence.FetchReshardingResponse{Snapshot: snapshot}, nil).Times(1)
+	router := NewRouter(2, store, dynamicconfig.GetDurationPropertyFn(time.Hour), log.NewNoop()).(*router)
+	router.Start()
+	defer router.Stop()
+	<-router.UpdatedCh()
+	assert.Equal(t, []int{0, 1}, router.ShardIDs())
+
+	// the last state is kept when the refresh fails
+	store.EXPECT().FetchResharding(gomock.Any()).Return(nil, errors.New("failed")).Times(1)
+	router.refresh()
+	assert.Equal(t, []int{0, 1}, router.ShardIDs())
+
+	store.EXPECT().FetchResharding(gomock.Any()).Return(&persistence.FetchReshardingResponse{
+		Snapshot: &persistence.ReshardingSnapshot{Version: 2, FromShardCount: 2, ToShardCount: 4, PausedShards: []int{1}},
+	}, nil).Times(1)
+	router.refresh()
+	<-router.UpdatedCh()
+	assert.Equal(t, []int{0}, router.ShardIDs())
+	assert.False(t, router.IsShardActive(1))
+}
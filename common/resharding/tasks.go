@@ -0,0 +1,154 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resharding
+
+import (
+	"fmt"
+
+	"github.com/uber/cadence/common/persistence"
+)
+
+// newTransferTask rebuilds the task written for a transfer task of the queue, without its task ID
+func newTransferTask(info *persistence.TransferTaskInfo) (persistence.Task, error) {
+	switch info.TaskType {
+	case persistence.TransferTaskTypeActivityTask:
+		return &persistence.ActivityTask{
+			VisibilityTimestamp: info.VisibilityTimestamp,
+			DomainID:            info.TargetDomainID,
+			TaskList:            info.TaskList,
+			ScheduleID:          info.ScheduleID,
+			Version:             info.Version,
+		}, nil
+	case persistence.TransferTaskTypeDecisionTask:
+		return &persistence.DecisionTask{
+			VisibilityTimestamp: info.VisibilityTimestamp,
+			DomainID:            info.TargetDomainID,
+			TaskList:            info.TaskList,
+			ScheduleID:          info.ScheduleID,
+			Version:             info.Version,
+			RecordVisibility:    info.RecordVisibility,
+		}, nil
+	case persistence.TransferTaskTypeCancelExecution:
+		return &persistence.CancelExecutionTask{
+			VisibilityTimestamp:     info.VisibilityTimestamp,
+			TargetDomainID:          info.TargetDomainID,
+			TargetWorkflowID:        info.TargetWorkflowID,
+			TargetRunID:             info.TargetRunID,
+			TargetChildWorkflowOnly: info.TargetChildWorkflowOnly,
+			InitiatedID:             info.ScheduleID,
+			Version:                 info.Version,
+		}, nil
+	case persistence.TransferTaskTypeSignalExecution:
+		return &persistence.SignalExecutionTask{
+			VisibilityTimestamp:     info.VisibilityTimestamp,
+			TargetDomainID:          info.TargetDomainID,
+			TargetWorkflowID:        info.TargetWorkflowID,
+			TargetRunID:             info.TargetRunID,
+			TargetChildWorkflowOnly: info.TargetChildWorkflowOnly,
+			InitiatedID:             info.ScheduleID,
+			Version:                 info.Version,
+		}, nil
+	case persistence.TransferTaskTypeStartChildExecution:
+		return &persistence.StartChildExecutionTask{
+			VisibilityTimestamp: info.VisibilityTimestamp,
+			TargetDomainID:      info.TargetDomainID,
+			TargetWorkflowID:    info.TargetWorkflowID,
+			InitiatedID:         info.ScheduleID,
+			Version:             info.Version,
+		}, nil
+	case persistence.TransferTaskTypeRecordChildExecutionCompleted:
+		return &persistence.RecordChildExecutionCompletedTask{
+			VisibilityTimestamp: info.VisibilityTimestamp,
+			TargetDomainID:      info.TargetDomainID,
+			TargetWorkflowID:    info.TargetWorkflowID,
+			TargetRunID:         info.TargetRunID,
+			Version:             info.Version,
+		}, nil
+	case persistence.TransferTaskTypeApplyParentClosePolicy:
+		return &persistence.ApplyParentClosePolicyTask{
+			VisibilityTimestamp: info.VisibilityTimestamp,
+			TargetDomainIDs:     info.TargetDomainIDs,
+			Version:             info.Version,
+		}, nil
+	case persistence.TransferTaskTypeCloseExecution:
+		return &persistence.CloseExecutionTask{VisibilityTimestamp: info.VisibilityTimestamp, Version: info.Version}, nil
+	case persistence.TransferTaskTypeRecordWorkflowStarted:
+		return &persistence.RecordWorkflowStartedTask{VisibilityTimestamp: info.VisibilityTimestamp, Version: info.Version}, nil
+	case persistence.TransferTaskTypeResetWorkflow:
+		return &persistence.ResetWorkflowTask{VisibilityTimestamp: info.VisibilityTimestamp, Version: info.Version}, nil
+	case persistence.TransferTaskTypeUpsertWorkflowSearchAttributes:
+		return &persistence.UpsertWorkflowSearchAttributesTask{VisibilityTimestamp: info.VisibilityTimestamp, Version: info.Version}, nil
+	case persistence.TransferTaskTypeRecordWorkflowClosed:
+		return &persistence.RecordWorkflowClosedTask{VisibilityTimestamp: info.VisibilityTimestamp, Version: info.Version}, nil
+	default:
+		return nil, fmt.Errorf("unknown transfer task type: %v", info.TaskType)
+	}
+}
+
+// newTimerTask rebuilds the task written for a timer task of the queue, without its task ID
+func newTimerTask(info *persistence.TimerTaskInfo) (persistence.Task, error) {
+	switch info.TaskType {
+	case persistence.TaskTypeDecisionTimeout:
+		return &persistence.DecisionTimeoutTask{
+			VisibilityTimestamp: info.VisibilityTimestamp,
+			EventID:             info.EventID,
+			ScheduleAttempt:     info.ScheduleAttempt,
+			TimeoutType:         info.TimeoutType,
+			Version:             info.Version,
+		}, nil
+	case persistence.TaskTypeActivityTimeout:
+		return &persistence.ActivityTimeoutTask{
+			VisibilityTimestamp: info.VisibilityTimestamp,
+			TimeoutType:         info.TimeoutType,
+			EventID:             info.EventID,
+			Attempt:             info.ScheduleAttempt,
+			Version:             info.Version,
+		}, nil
+	case persistence.TaskTypeUserTimer:
+		return &persistence.UserTimerTask{
+			VisibilityTimestamp: info.VisibilityTimestamp,
+			EventID:             info.EventID,
+			Version:             info.Version,
+		}, nil
+	case persistence.TaskTypeActivityRetryTimer:
+		return &persistence.ActivityRetryTimerTask{
+			VisibilityTimestamp: info.VisibilityTimestamp,
+			EventID:             info.EventID,
+			Version:             info.Version,
+			Attempt:             int32(info.ScheduleAttempt),
+		}, nil
+	case persistence.TaskTypeWorkflowBackoffTimer:
+		return &persistence.WorkflowBackoffTimerTask{
+			VisibilityTimestamp: info.VisibilityTimestamp,
+			EventID:             info.EventID,
+			Version:             info.Version,
+			TimeoutType:         info.TimeoutType,
+		}, nil
+	case persistence.TaskTypeWorkflowTimeout:
+		return &persistence.WorkflowTimeoutTask{VisibilityTimestamp: info.VisibilityTimestamp, Version: info.Version}, nil
+	case persistence.TaskTypeDeleteHistoryEvent:
+		return &persistence.DeleteHistoryEventTask{VisibilityTimestamp: info.VisibilityTimestamp, Version: info.Version}, nil
+	default:
+		return nil, fmt.Errorf("unknown timer task type: %v", info.TaskType)
+	}
+}
@@ -0,0 +1,92 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uber/cadence/common/config"
+	es "github.com/uber/cadence/common/elasticsearch"
+	"github.com/uber/cadence/common/health"
+	"github.com/uber/cadence/common/membership"
+	persistenceClient "github.com/uber/cadence/common/persistence/client"
+)
+
+const (
+	healthDependencyPersistence   = "persistence"
+	healthDependencyMembership    = "membership"
+	healthDependencyElasticSearch = "elasticsearch"
+
+	// dependencies responding slower than this are reported as degraded
+	healthDegradedLatency = time.Second
+)
+
+// newHealthChecks creates the checks of the dependencies all services need, services add their own checks to the registry
+func newHealthChecks(
+	serviceName string,
+	persistenceBean persistenceClient.Bean,
+	membershipResolver membership.Resolver,
+	esClient es.GenericClient,
+	esConfig *config.ElasticSearchConfig,
+) map[string]health.Check {
+	checks := map[string]health.Check{
+		healthDependencyPersistence: health.NewPingCheck(func(ctx context.Context) error {
+			_, err := persistenceBean.GetDomainManager().GetMetadata(ctx)
+			return err
+		}, health.StatusDown, healthDegradedLatency),
+		healthDependencyMembership: newMembershipHealthCheck(serviceName, membershipResolver),
+	}
+	if esClient != nil && esConfig != nil {
+		// visibility is the only feature relying on ElasticSearch, so the service is still usable without it
+		checks[healthDependencyElasticSearch] = health.NewPingCheck(func(ctx context.Context) error {
+			_, err := esClient.CountByQuery(ctx, esConfig.GetVisibilityIndex(), `{"query":{"match_all":{}}}`)
+			return err
+		}, health.StatusDegraded, healthDegradedLatency)
+	}
+	return checks
+}
+
+// newMembershipHealthCheck reports the service as down until the host is part of the hash ring of the service
+func newMembershipHealthCheck(serviceName string, membershipResolver membership.Resolver) health.Check {
+	return func(ctx context.Context) health.Result {
+		self, err := membershipResolver.WhoAmI()
+		if err != nil {
+			return health.Result{Status: health.StatusDown, Message: err.Error()}
+		}
+		members, err := membershipResolver.Members(serviceName)
+		if err != nil {
+			return health.Result{Status: health.StatusDown, Message: err.Error()}
+		}
+		for _, member := range members {
+			if member.GetAddress() == self.GetAddress() {
+				return health.Result{Status: health.StatusUp}
+			}
+		}
+		return health.Result{
+			Status:  health.StatusDown,
+			Message: fmt.Sprintf("host %v has not joined the ring of %v members", self.GetAddress(), len(members)),
+		}
+	}
+}
@@ -29,14 +29,19 @@ import (
 	"github.com/uber/cadence/common/archiver/provider"
 	"github.com/uber/cadence/common/authorization"
 	"github.com/uber/cadence/common/blobstore"
+	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/dynamicconfig"
 	es "github.com/uber/cadence/common/elasticsearch"
+	"github.com/uber/cadence/common/health"
+	"github.com/uber/cadence/common/loadshedding"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/membership"
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/redaction"
+	"github.com/uber/cadence/common/slo"
 )
 
 type (
@@ -50,6 +55,7 @@ type (
 		MetricScope              tally.Scope
 		MembershipResolver       membership.Resolver
 		RPCFactory               common.RPCFactory
+		AdminRPCFactory          common.RPCFactory // NOTE: this can be nil. If nil, the admin APIs are served by the dispatcher of RPCFactory
 		PProfInitializer         common.PProfInitializer
 		PersistenceConfig        config.Persistence
 		ClusterMetadata          cluster.Metadata
@@ -64,7 +70,14 @@ type (
 		PublicClient             workflowserviceclient.Interface
 		ArchivalMetadata         archiver.ArchivalMetadata
 		ArchiverProvider         provider.ArchiverProvider
-		Authorizer               authorization.Authorizer // NOTE: this can be nil. If nil, AccessControlledHandlerImpl will initiate one with config.Authorization
-		AuthorizationConfig      config.Authorization     // NOTE: empty(default) struct will get a authorization.NoopAuthorizer
+		Authorizer               authorization.Authorizer   // NOTE: this can be nil. If nil, AccessControlledHandlerImpl will initiate one with config.Authorization
+		AuthorizationConfig      config.Authorization       // NOTE: empty(default) struct will get a authorization.NoopAuthorizer
+		AdminAuthorizationConfig *config.Authorization      // NOTE: this can be nil. If nil, the admin APIs are authorized with AuthorizationConfig
+		HealthRegistry           *health.Registry           // NOTE: this can be nil. If nil, the health of the service is not reported
+		CacheRegistry            *cache.Registry            // NOTE: this can be nil. If nil, the in-memory caches of the service cannot be introspected
+		Redactor                 redaction.Redactor         // NOTE: this can be nil. If nil, the redaction policies of the domains are read from dynamic config
+		TaskTokenSerializer      common.TaskTokenSerializer // NOTE: this can be nil. If nil, the task tokens are not signed
+		LoadMonitor              *loadshedding.Monitor      // NOTE: this can be nil. If nil, the inbound requests are not shed and a monitor only throttles the tasks
+		SLOTracker               slo.Tracker                // NOTE: this can be nil. If nil, the SLOs of the domains cannot be described, it is only set for the frontend
 	}
 )
@@ -31,6 +31,7 @@ import (
 	"github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/accounting"
 	"github.com/uber/cadence/common/archiver"
 	"github.com/uber/cadence/common/archiver/provider"
 	"github.com/uber/cadence/common/blobstore"
@@ -38,12 +39,17 @@ import (
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/domain"
+	"github.com/uber/cadence/common/heatmap"
+	"github.com/uber/cadence/common/loadshedding"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/membership"
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
 	persistenceClient "github.com/uber/cadence/common/persistence/client"
+	"github.com/uber/cadence/common/redaction"
+	"github.com/uber/cadence/common/resharding"
+	"github.com/uber/cadence/common/watchdog"
 )
 
 type (
@@ -69,6 +75,14 @@ type (
 		GetMessagingClient() messaging.Client
 		GetBlobstoreClient() blobstore.Client
 		GetDomainReplicationQueue() domain.ReplicationQueue
+		GetUsageRecorder() accounting.Recorder
+		GetPersistenceLatencyHeatmap() heatmap.Collector
+		GetWatchdog() *watchdog.Watchdog
+		GetCacheRegistry() *cache.Registry
+		GetRedactor() redaction.Redactor
+		GetTaskTokenSerializer() common.TaskTokenSerializer
+		GetHistoryShardRouter() resharding.Router
+		GetLoadMonitor() *loadshedding.Monitor
 
 		// membership infos
 		GetMembershipResolver() membership.Resolver
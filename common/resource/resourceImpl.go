@@ -35,14 +35,19 @@ import (
 	"github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/accounting"
 	"github.com/uber/cadence/common/archiver"
 	"github.com/uber/cadence/common/archiver/provider"
 	"github.com/uber/cadence/common/blobstore"
 	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/certificate"
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/domain"
 	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/health"
+	"github.com/uber/cadence/common/heatmap"
+	"github.com/uber/cadence/common/loadshedding"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/loggerimpl"
 	"github.com/uber/cadence/common/log/tag"
@@ -52,7 +57,10 @@ import (
 	"github.com/uber/cadence/common/persistence"
 	persistenceClient "github.com/uber/cadence/common/persistence/client"
 	"github.com/uber/cadence/common/quotas"
+	"github.com/uber/cadence/common/redaction"
+	"github.com/uber/cadence/common/resharding"
 	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/watchdog"
 )
 
 type (
@@ -87,6 +95,17 @@ type (
 		archivalMetadata        archiver.ArchivalMetadata
 		archiverProvider        provider.ArchiverProvider
 		domainReplicationQueue  domain.ReplicationQueue
+		usageRecorder           accounting.Recorder
+		usageReporter           common.Daemon
+		historyShardRouter      resharding.Router
+		loadMonitor             *loadshedding.Monitor
+		latencyHeatmap          heatmap.Collector
+		healthRegistry          *health.Registry
+		watchdog                *watchdog.Watchdog
+		cacheRegistry           *cache.Registry
+		redactor                redaction.Redactor
+		taskTokenSerializer     common.TaskTokenSerializer
+		healthChecks            map[string]health.Check
 
 		// membership infos
 
@@ -117,6 +136,7 @@ type (
 
 		pprofInitializer       common.PProfInitializer
 		runtimeMetricsReporter *metrics.RuntimeMetricsReporter
+		certificateReporter    *certificate.Reporter
 		rpcFactory             common.RPCFactory
 	}
 )
@@ -142,22 +162,8 @@ func New(
 		logger,
 		dynamicconfig.ClusterNameFilter(params.ClusterMetadata.GetCurrentClusterName()),
 	)
-	clientBean, err := client.NewClientBean(
-		client.NewRPCClientFactory(
-			params.RPCFactory,
-			membershipResolver,
-			params.MetricsClient,
-			dynamicCollection,
-			numShards,
-			logger,
-		),
-		params.RPCFactory.GetDispatcher(),
-		params.ClusterMetadata,
-	)
-	if err != nil {
-		return nil, err
-	}
-
+	usageRecorder := accounting.NewRecorder(dynamicCollection.GetBoolProperty(dynamicconfig.EnableUsageAccounting))
+	latencyHeatmap := heatmap.NewCollector(dynamicCollection.GetBoolProperty(dynamicconfig.EnablePersistenceLatencyHeatmap), clock.NewRealTimeSource())
 	persistenceBean, err := persistenceClient.NewBeanFromFactory(persistenceClient.NewFactory(
 		&params.PersistenceConfig,
 		quotas.PerMemberDynamic(
@@ -168,6 +174,8 @@ func New(
 		),
 		params.ClusterMetadata.GetCurrentClusterName(),
 		params.MetricsClient,
+		usageRecorder,
+		latencyHeatmap,
 		logger,
 		persistence.NewDynamicConfiguration(dynamicCollection),
 	), &persistenceClient.Params{
@@ -181,14 +189,85 @@ func New(
 		return nil, err
 	}
 
+	// the shards of the workflows follow the resharding state, which is kept in the config store
+	historyShardRouter := resharding.NewStaticRouter(numShards)
+	if configStoreManager := persistenceBean.GetConfigStoreManager(); configStoreManager != nil {
+		historyShardRouter = resharding.NewRouter(
+			numShards,
+			configStoreManager,
+			dynamicCollection.GetDurationProperty(dynamicconfig.ReshardingRefreshInterval),
+			logger,
+		)
+	}
+	clientBean, err := client.NewClientBean(
+		client.NewRPCClientFactory(
+			params.RPCFactory,
+			membershipResolver,
+			params.MetricsClient,
+			dynamicCollection,
+			historyShardRouter,
+			logger,
+		),
+		params.RPCFactory.GetDispatcher(),
+		params.ClusterMetadata,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	domainCache := cache.NewDomainCache(
 		persistenceBean.GetDomainManager(),
 		params.ClusterMetadata,
 		params.MetricsClient,
 		logger,
 	)
+	// logs of the service are throttled per domain once domain ids can be resolved to names
+	logger = loggerimpl.NewDomainThrottledLogger(
+		logger,
+		dynamicCollection.GetMapProperty(dynamicconfig.DomainLogRPS),
+		domainCache.GetDomainName,
+	)
 
 	domainMetricsScopeCache := cache.NewDomainMetricsScopeCache()
+	// usage reports are persisted in the config store, which only some default stores support
+	var usageReporter common.Daemon
+	if configStoreManager := persistenceBean.GetConfigStoreManager(); configStoreManager != nil {
+		usageReporter = accounting.NewReporter(
+			usageRecorder,
+			configStoreManager,
+			params.Name,
+			dynamicCollection.GetDurationProperty(dynamicconfig.UsageAccountingReportInterval),
+			dynamicCollection.GetDurationProperty(dynamicconfig.UsageAccountingRetention),
+			clock.NewRealTimeSource(),
+			logger,
+		)
+	}
+	redactor := params.Redactor
+	if redactor == nil {
+		redactor = redaction.NewPolicyRedactor(dynamicCollection)
+	}
+	taskTokenSerializer := params.TaskTokenSerializer
+	if taskTokenSerializer == nil {
+		taskTokenSerializer = common.NewJSONTaskTokenSerializer()
+	}
+	loadMonitor := params.LoadMonitor
+	if loadMonitor == nil {
+		loadMonitor = loadshedding.NewMonitor(loadshedding.NewConfig(dynamicCollection), params.MetricsClient, logger)
+	}
+	serviceWatchdog := watchdog.NewWatchdog(
+		params.Name,
+		watchdog.NewConfig(dynamicCollection),
+		params.MetricsClient,
+		clock.NewRealTimeSource(),
+		logger,
+	)
+	serviceWatchdog.Register("goroutines", watchdog.NewGoroutineProbe())
+	serviceWatchdog.Register("domain_cache", watchdog.Probe{
+		Value: func() int {
+			_, sizeByID := domainCache.GetCacheSize()
+			return int(sizeByID)
+		},
+	})
 	domainReplicationQueue := domain.NewReplicationQueue(
 		persistenceBean.GetDomainReplicationQueueManager(),
 		params.ClusterMetadata.GetCurrentClusterName(),
@@ -263,6 +342,17 @@ func New(
 		archivalMetadata:        params.ArchivalMetadata,
 		archiverProvider:        params.ArchiverProvider,
 		domainReplicationQueue:  domainReplicationQueue,
+		usageRecorder:           usageRecorder,
+		usageReporter:           usageReporter,
+		historyShardRouter:      historyShardRouter,
+		loadMonitor:             loadMonitor,
+		latencyHeatmap:          latencyHeatmap,
+		healthRegistry:          params.HealthRegistry,
+		watchdog:                serviceWatchdog,
+		cacheRegistry:           params.CacheRegistry,
+		redactor:                redactor,
+		taskTokenSerializer:     taskTokenSerializer,
+		healthChecks:            newHealthChecks(serviceName, persistenceBean, membershipResolver, params.ESClient, params.ESConfig),
 
 		// membership infos
 		membershipResolver: membershipResolver,
@@ -297,7 +387,8 @@ func New(
 			logger,
 			params.InstanceID,
 		),
-		rpcFactory: params.RPCFactory,
+		certificateReporter: certificate.NewReporter(params.MetricScope, logger),
+		rpcFactory:          params.RPCFactory,
 	}
 	return impl, nil
 }
@@ -315,6 +406,7 @@ func (h *Impl) Start() {
 
 	h.metricsScope.Counter(metrics.RestartCount).Inc(1)
 	h.runtimeMetricsReporter.Start()
+	h.certificateReporter.Start()
 
 	if err := h.pprofInitializer.Start(); err != nil {
 		h.logger.WithTags(tag.Error(err)).Fatal("fail to start PProf")
@@ -322,9 +414,13 @@ func (h *Impl) Start() {
 	if err := h.dispatcher.Start(); err != nil {
 		h.logger.WithTags(tag.Error(err)).Fatal("fail to start dispatcher")
 	}
+	h.historyShardRouter.Start()
 	h.membershipResolver.Start()
 	h.domainCache.Start()
 	h.domainMetricsScopeCache.Start()
+	if h.usageReporter != nil {
+		h.usageReporter.Start()
+	}
 
 	hostInfo, err := h.membershipResolver.WhoAmI()
 	if err != nil {
@@ -332,6 +428,15 @@ func (h *Impl) Start() {
 	}
 	h.hostInfo = hostInfo
 
+	for dependency, check := range h.healthChecks {
+		h.healthRegistry.Register(h.serviceName, dependency, check)
+	}
+	h.watchdog.Start()
+	h.loadMonitor.Start()
+	if introspector, ok := h.domainCache.(cache.Introspector); ok {
+		h.cacheRegistry.Add(h.domainCacheName(), introspector)
+	}
+
 	// The service is now started up
 	h.logger.Info("service started")
 	// seed the random generator once for this service
@@ -349,13 +454,22 @@ func (h *Impl) Stop() {
 		return
 	}
 
+	h.healthRegistry.Deregister(h.serviceName)
+	h.cacheRegistry.Remove(h.domainCacheName())
+	h.watchdog.Stop()
+	h.loadMonitor.Stop()
 	h.domainCache.Stop()
 	h.domainMetricsScopeCache.Stop()
+	if h.usageReporter != nil {
+		h.usageReporter.Stop()
+	}
 	h.membershipResolver.Stop()
+	h.historyShardRouter.Stop()
 	if err := h.dispatcher.Stop(); err != nil {
 		h.logger.WithTags(tag.Error(err)).Error("failed to stop dispatcher")
 	}
 	h.runtimeMetricsReporter.Stop()
+	h.certificateReporter.Stop()
 	h.persistenceBean.Close()
 }
 
@@ -406,6 +520,50 @@ func (h *Impl) GetMessagingClient() messaging.Client {
 	return h.messagingClient
 }
 
+// GetWatchdog returns the watchdog tracking the long-lived resources of the service
+func (h *Impl) GetWatchdog() *watchdog.Watchdog {
+	return h.watchdog
+}
+
+// GetCacheRegistry returns the registry of the in-memory caches of the process, it can be nil
+func (h *Impl) GetCacheRegistry() *cache.Registry {
+	return h.cacheRegistry
+}
+
+// GetRedactor returns the redactor of the sensitive data of the domains
+func (h *Impl) GetRedactor() redaction.Redactor {
+	return h.redactor
+}
+
+// GetTaskTokenSerializer returns the serializer of the task tokens returned to the workers
+func (h *Impl) GetTaskTokenSerializer() common.TaskTokenSerializer {
+	return h.taskTokenSerializer
+}
+
+// GetHistoryShardRouter returns the router of the workflows to the history shards
+func (h *Impl) GetHistoryShardRouter() resharding.Router {
+	return h.historyShardRouter
+}
+
+// GetLoadMonitor returns the monitor of the usage of the host, which sets how much load it sheds
+func (h *Impl) GetLoadMonitor() *loadshedding.Monitor {
+	return h.loadMonitor
+}
+
+func (h *Impl) domainCacheName() string {
+	return service.ShortName(h.serviceName) + "/domain"
+}
+
+// GetUsageRecorder returns the recorder of the resources consumed by domains
+func (h *Impl) GetUsageRecorder() accounting.Recorder {
+	return h.usageRecorder
+}
+
+// GetPersistenceLatencyHeatmap returns the collector of the latency of persistence operations by shard
+func (h *Impl) GetPersistenceLatencyHeatmap() heatmap.Collector {
+	return h.latencyHeatmap
+}
+
 // GetBlobstoreClient returns blobstore client
 func (h *Impl) GetBlobstoreClient() blobstore.Client {
 	return h.blobstoreClient
@@ -35,6 +35,8 @@ import (
 	"github.com/uber/cadence/client/frontend"
 	"github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/client/matching"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/accounting"
 	"github.com/uber/cadence/common/archiver"
 	"github.com/uber/cadence/common/archiver/provider"
 	"github.com/uber/cadence/common/blobstore"
@@ -42,6 +44,9 @@ import (
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/domain"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/heatmap"
+	"github.com/uber/cadence/common/loadshedding"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/loggerimpl"
 	"github.com/uber/cadence/common/membership"
@@ -50,6 +55,9 @@ import (
 	"github.com/uber/cadence/common/mocks"
 	"github.com/uber/cadence/common/persistence"
 	persistenceClient "github.com/uber/cadence/common/persistence/client"
+	"github.com/uber/cadence/common/redaction"
+	"github.com/uber/cadence/common/resharding"
+	"github.com/uber/cadence/common/watchdog"
 )
 
 type (
@@ -69,6 +77,10 @@ type (
 		ArchivalMetadata        *archiver.MockArchivalMetadata
 		ArchiverProvider        *provider.MockArchiverProvider
 		BlobstoreClient         *blobstore.MockClient
+		UsageRecorder           accounting.Recorder
+		LatencyHeatmap          heatmap.Collector
+		Watchdog                *watchdog.Watchdog
+		HistoryShardRouter      resharding.Router
 
 		// membership infos
 		MembershipResolver *membership.MockResolver
@@ -101,6 +113,8 @@ var _ Resource = (*Test)(nil)
 
 const (
 	testHostName = "test_host"
+
+	testNumberOfHistoryShards = 1
 )
 
 var (
@@ -161,12 +175,22 @@ func NewTest(
 		DomainCache:             cache.NewMockDomainCache(controller),
 		DomainMetricsScopeCache: cache.NewDomainMetricsScopeCache(),
 		DomainReplicationQueue:  domainReplicationQueue,
-		TimeSource:              clock.NewRealTimeSource(),
-		PayloadSerializer:       persistence.NewPayloadSerializer(),
-		MetricsClient:           metrics.NewClient(scope, serviceMetricsIndex),
-		ArchivalMetadata:        &archiver.MockArchivalMetadata{},
-		ArchiverProvider:        &provider.MockArchiverProvider{},
-		BlobstoreClient:         &blobstore.MockClient{},
+		UsageRecorder:           accounting.NewNoopRecorder(),
+		LatencyHeatmap:          heatmap.NewCollector(dynamicconfig.GetBoolPropertyFn(false), clock.NewRealTimeSource()),
+		Watchdog: watchdog.NewWatchdog(
+			"test",
+			watchdog.NewConfig(dynamicconfig.NewNopCollection()),
+			metrics.NewNoopMetricsClient(),
+			clock.NewRealTimeSource(),
+			logger,
+		),
+		HistoryShardRouter: resharding.NewStaticRouter(testNumberOfHistoryShards),
+		TimeSource:         clock.NewRealTimeSource(),
+		PayloadSerializer:  persistence.NewPayloadSerializer(),
+		MetricsClient:      metrics.NewClient(scope, serviceMetricsIndex),
+		ArchivalMetadata:   &archiver.MockArchivalMetadata{},
+		ArchiverProvider:   &provider.MockArchiverProvider{},
+		BlobstoreClient:    &blobstore.MockClient{},
 
 		// membership infos
 		MembershipResolver: membership.NewMockResolver(controller),
@@ -236,6 +260,46 @@ func (s *Test) GetDomainMetricsScopeCache() cache.DomainMetricsScopeCache {
 	return s.DomainMetricsScopeCache
 }
 
+// GetWatchdog for testing
+func (s *Test) GetWatchdog() *watchdog.Watchdog {
+	return s.Watchdog
+}
+
+// GetCacheRegistry for testing
+func (s *Test) GetCacheRegistry() *cache.Registry {
+	return nil
+}
+
+// GetTaskTokenSerializer for testing
+func (s *Test) GetTaskTokenSerializer() common.TaskTokenSerializer {
+	return common.NewJSONTaskTokenSerializer()
+}
+
+// GetHistoryShardRouter for testing
+func (s *Test) GetHistoryShardRouter() resharding.Router {
+	return s.HistoryShardRouter
+}
+
+// GetLoadMonitor for testing
+func (s *Test) GetLoadMonitor() *loadshedding.Monitor {
+	return nil
+}
+
+// GetRedactor for testing
+func (s *Test) GetRedactor() redaction.Redactor {
+	return redaction.NewNopRedactor()
+}
+
+// GetUsageRecorder for testing
+func (s *Test) GetUsageRecorder() accounting.Recorder {
+	return s.UsageRecorder
+}
+
+// GetPersistenceLatencyHeatmap for testing
+func (s *Test) GetPersistenceLatencyHeatmap() heatmap.Collector {
+	return s.LatencyHeatmap
+}
+
 // GetDomainReplicationQueue for testing
 func (s *Test) GetDomainReplicationQueue() domain.ReplicationQueue {
 	// user should implement this method for test
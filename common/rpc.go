@@ -47,6 +47,20 @@ const (
 	ClientImplHeaderName = "cadence-client-name"
 	// AuthorizationTokenHeaderName refers to the jwt token in the request
 	AuthorizationTokenHeaderName = "cadence-authorization"
+	// APIKeyHeaderName refers to the API key in the request, for the callers which don't have a jwt token
+	APIKeyHeaderName = "cadence-api-key"
+	// ClientIdentityHeaderName refers to the identity of the user or host
+	// sending the request, used for auditing admin operations
+	ClientIdentityHeaderName = "cadence-client-identity"
+	// IsolationGroupHeaderName refers to the isolation group (e.g. zone) of the poller
+	// polling for tasks. Tasks are matched with the pollers of their isolation group first
+	IsolationGroupHeaderName = "cadence-isolation-group"
+	// TaskPriorityHeaderName refers to the priority of the task being added to a task list.
+	// Tasks with a higher priority are dispatched from the task list backlog first
+	TaskPriorityHeaderName = "cadence-task-priority"
+	// TaskPriorityMemoKey is the key of the workflow memo holding the priority
+	// the decision and activity tasks of the workflow are added to matching with
+	TaskPriorityMemoKey = "cadence-task-priority"
 )
 
 type (
@@ -24,9 +24,12 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/dgryski/go-farm"
+	"github.com/pborman/uuid"
 	"go.uber.org/yarpc/api/peer"
 
 	"github.com/uber/cadence/common/log"
@@ -41,6 +44,16 @@ type (
 		currentPeers map[string]struct{}
 		list         peer.List
 		logger       log.Logger
+
+		// subsetSize is the number of peers put in the list, all of them if 0
+		subsetSize int
+		// subsetKey is hashed with the addresses of the peers to rank them, it is random so that the hosts pick
+		// different subsets
+		subsetKey string
+	}
+	// statusList is implemented by the peer lists which report the status of their peers, like the round robin one
+	statusList interface {
+		Peers() []peer.StatusPeer
 	}
 	dnsRefreshResult struct {
 		updates  peer.ListUpdates
@@ -52,7 +65,7 @@ type (
 	}
 )
 
-func newDNSUpdater(list peer.List, dnsPort string, interval time.Duration, logger log.Logger) (*dnsUpdater, error) {
+func newDNSUpdater(list peer.List, dnsPort string, interval time.Duration, subsetSize int, logger log.Logger) (*dnsUpdater, error) {
 	ss := strings.Split(dnsPort, ":")
 	if len(ss) != 2 {
 		return nil, fmt.Errorf("incorrect DNS:Port format")
@@ -64,6 +77,8 @@ func newDNSUpdater(list peer.List, dnsPort string, interval time.Duration, logge
 		dnsAddress:   ss[0],
 		port:         ss[1],
 		currentPeers: make(map[string]struct{}),
+		subsetSize:   subsetSize,
+		subsetKey:    uuid.New(),
 	}, nil
 }
 
@@ -106,6 +121,9 @@ func (d *dnsUpdater) refresh() (*dnsRefreshResult, error) {
 		adr := fmt.Sprintf("%v:%v", ip, d.port)
 		newPeers[adr] = struct{}{}
 	}
+	if d.subsetSize > 0 && len(newPeers) > d.subsetSize {
+		newPeers = d.subset(newPeers)
+	}
 
 	updates := peer.ListUpdates{
 		Additions: make([]peer.Identifier, 0),
@@ -141,6 +159,39 @@ func (d *dnsUpdater) refresh() (*dnsRefreshResult, error) {
 	}, nil
 }
 
+// subset picks the available peers ranked first by the hash of their address, then the unavailable ones if there are
+// not enough available peers, so that the unavailable peers of the current subset are replaced
+func (d *dnsUpdater) subset(addresses map[string]struct{}) map[string]struct{} {
+	unavailable := map[string]bool{}
+	if list, ok := d.list.(statusList); ok {
+		for _, p := range list.Peers() {
+			if p.Status().ConnectionStatus == peer.Unavailable {
+				unavailable[p.Identifier()] = true
+			}
+		}
+	}
+
+	ranked := make([]string, 0, len(addresses))
+	for addr := range addresses {
+		ranked = append(ranked, addr)
+	}
+	rank := func(addr string) uint64 {
+		return farm.Fingerprint64([]byte(d.subsetKey + addr))
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if unavailable[ranked[i]] != unavailable[ranked[j]] {
+			return !unavailable[ranked[i]]
+		}
+		return rank(ranked[i]) < rank(ranked[j])
+	})
+
+	subset := make(map[string]struct{}, d.subsetSize)
+	for _, addr := range ranked[:d.subsetSize] {
+		subset[addr] = struct{}{}
+	}
+	return subset
+}
+
 func (a aPeer) Identifier() string {
 	return a.addrPort
 }
@@ -24,6 +24,7 @@ import (
 	"crypto/tls"
 	"net"
 
+	"github.com/opentracing/opentracing-go"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/transport/grpc"
 	"go.uber.org/yarpc/transport/tchannel"
@@ -48,13 +49,18 @@ func NewFactory(logger log.Logger, p Params) *Factory {
 	// Create TChannel transport
 	// This is here only because ringpop expects tchannel.ChannelTransport,
 	// everywhere else we use regular tchannel.Transport.
+	tracer := p.Tracer
+	if tracer == nil {
+		tracer = opentracing.GlobalTracer()
+	}
 	ch, err := tchannel.NewChannelTransport(
 		tchannel.ServiceName(p.ServiceName),
-		tchannel.ListenAddr(p.TChannelAddress))
+		tchannel.ListenAddr(p.TChannelAddress),
+		tchannel.Tracer(tracer))
 	if err != nil {
 		logger.Fatal("Failed to create transport channel", tag.Error(err))
 	}
-	tchannel, err := tchannel.NewTransport(tchannel.ServiceName(p.ServiceName))
+	tchannel, err := tchannel.NewTransport(tchannel.ServiceName(p.ServiceName), tchannel.Tracer(tracer))
 	if err != nil {
 		logger.Fatal("Failed to create tchannel transport", tag.Error(err))
 	}
@@ -63,12 +69,15 @@ func NewFactory(logger log.Logger, p Params) *Factory {
 	logger.Info("Listening for TChannel requests", tag.Address(p.TChannelAddress))
 
 	// Create gRPC transport
-	var options []grpc.TransportOption
+	options := []grpc.TransportOption{grpc.Tracer(tracer)}
 	if p.GRPCMaxMsgSize > 0 {
 		options = append(options, grpc.ServerMaxRecvMsgSize(p.GRPCMaxMsgSize))
 		options = append(options, grpc.ClientMaxRecvMsgSize(p.GRPCMaxMsgSize))
 	}
 	grpcTransport := grpc.NewTransport(options...)
+	if p.GRPCPool != nil {
+		p.GRPCPool.transportOptions = options
+	}
 	if len(p.GRPCAddress) > 0 {
 		listener, err := net.Listen("tcp", p.GRPCAddress)
 		if err != nil {
@@ -125,8 +134,61 @@ func (d *Factory) GetMaxMessageSize() int {
 	return d.maxMessageSize
 }
 
-func createDialer(transport *grpc.Transport, tlsConfig *tls.Config) *grpc.Dialer {
-	var dialOptions []grpc.DialOption
+// AdminFactory is an implementation of common.RPCFactory interface for the dedicated listener of the admin APIs,
+// its dispatcher only has a gRPC inbound and no outbound
+type AdminFactory struct {
+	maxMessageSize int
+	dispatcher     *yarpc.Dispatcher
+}
+
+// NewAdminFactory builds a new rpc.AdminFactory
+func NewAdminFactory(logger log.Logger, p Params) *AdminFactory {
+	tracer := p.Tracer
+	if tracer == nil {
+		tracer = opentracing.GlobalTracer()
+	}
+	options := []grpc.TransportOption{grpc.Tracer(tracer)}
+	if p.GRPCMaxMsgSize > 0 {
+		options = append(options, grpc.ServerMaxRecvMsgSize(p.GRPCMaxMsgSize))
+	}
+	grpcTransport := grpc.NewTransport(options...)
+	listener, err := net.Listen("tcp", p.GRPCAddress)
+	if err != nil {
+		logger.Fatal("Failed to listen on admin GRPC port", tag.Error(err))
+	}
+
+	var inboundOptions []grpc.InboundOption
+	if p.InboundTLS != nil {
+		inboundOptions = append(inboundOptions, grpc.InboundCredentials(credentials.NewTLS(p.InboundTLS)))
+	}
+	logger.Info("Listening for admin GRPC requests", tag.Address(p.GRPCAddress))
+
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{
+		Name:              p.ServiceName,
+		Inbounds:          yarpc.Inbounds{grpcTransport.NewInbound(listener, inboundOptions...)},
+		InboundMiddleware: p.InboundMiddleware,
+	})
+
+	return &AdminFactory{
+		maxMessageSize: p.GRPCMaxMsgSize,
+		dispatcher:     dispatcher,
+	}
+}
+
+// GetDispatcher return a cached dispatcher
+func (d *AdminFactory) GetDispatcher() *yarpc.Dispatcher {
+	return d.dispatcher
+}
+
+func (d *AdminFactory) GetMaxMessageSize() int {
+	if d.maxMessageSize == 0 {
+		return defaultGRPCSizeLimit
+	}
+	return d.maxMessageSize
+}
+
+func createDialer(transport *grpc.Transport, tlsConfig *tls.Config, options ...grpc.DialOption) *grpc.Dialer {
+	dialOptions := options
 	if tlsConfig != nil {
 		dialOptions = append(dialOptions, grpc.DialerCredentials(credentials.NewTLS(tlsConfig)))
 	}
@@ -23,14 +23,27 @@ package rpc
 import (
 	"context"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/crash"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/loadshedding"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/slo"
+	"github.com/uber/cadence/common/slowrequest"
+	"github.com/uber/cadence/common/types/mapper/proto"
 
 	"go.uber.org/cadence/worker"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/yarpcerrors"
 )
 
 type authOutboundMiddleware struct {
@@ -177,3 +190,268 @@ func (m *HeaderForwardingMiddleware) Call(ctx context.Context, request *transpor
 
 	return out.Call(ctx, request)
 }
+
+// SlowRequestLogMiddleware logs the inbound requests taking longer than the latency threshold of their API,
+// together with the time they spent calling each dependency.
+type SlowRequestLogMiddleware struct {
+	Logger    log.Logger
+	Threshold dynamicconfig.DurationPropertyFn
+}
+
+func (m *SlowRequestLogMiddleware) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter, h transport.UnaryHandler) error {
+	api := apiName(req.Procedure)
+	threshold := m.Threshold(dynamicconfig.APINameFilter(api))
+	if threshold <= 0 {
+		return h.Handle(ctx, req, resw)
+	}
+
+	ctx, breakdown := slowrequest.NewContext(ctx)
+	start := time.Now()
+	err := h.Handle(ctx, req, resw)
+	latency := time.Since(start)
+	if latency <= threshold {
+		return err
+	}
+
+	tags := []tag.Tag{
+		tag.APIName(api),
+		tag.Caller(req.Caller),
+		tag.Latency(latency),
+		tag.LatencyThreshold(threshold),
+	}
+	tags = append(tags, breakdown.Tags()...)
+	if err != nil {
+		tags = append(tags, tag.Error(err))
+	}
+	m.Logger.Warn("Slow request", tags...)
+	return err
+}
+
+// DependencyLatencyMiddleware adds the time spent calling other cadence services to the latency breakdown
+// of the inbound request being handled, see SlowRequestLogMiddleware.
+type DependencyLatencyMiddleware struct{}
+
+func (m *DependencyLatencyMiddleware) Call(ctx context.Context, request *transport.Request, out transport.UnaryOutbound) (*transport.Response, error) {
+	start := time.Now()
+	response, err := out.Call(ctx, request)
+	slowrequest.RecordDependency(ctx, service.ShortName(request.Service), time.Since(start))
+	return response, err
+}
+
+// apiName returns the method name of a procedure, which is the same over thrift and gRPC,
+// e.g. "StartWorkflowExecution" for "uber.cadence.api.v1.WorkflowAPI::StartWorkflowExecution"
+func apiName(procedure string) string {
+	if i := strings.LastIndex(procedure, "::"); i >= 0 {
+		return procedure[i+2:]
+	}
+	return procedure
+}
+
+// PayloadSizeMiddleware records the payload sizes of inbound and outbound requests tagged by API and domain,
+// and warns about the payloads approaching the transport size limit, together with the workflow they are for.
+type PayloadSizeMiddleware struct {
+	MetricsClient metrics.Client
+	Logger        log.Logger
+	// MaxSize is the transport size limit, the gRPC default is used if not set
+	MaxSize int
+	// WarnRatio is the fraction of MaxSize above which payloads are reported, 0 disables the warnings
+	WarnRatio dynamicconfig.FloatPropertyFn
+}
+
+func (m *PayloadSizeMiddleware) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter, h transport.UnaryHandler) error {
+	// the domain and workflow are only known once the request is decoded, handlers annotate the breakdown with them
+	breakdown := slowrequest.FromContext(ctx)
+	if breakdown == nil {
+		ctx, breakdown = slowrequest.NewContext(ctx)
+	}
+
+	writer := &countingResponseWriter{ResponseWriter: resw}
+	err := h.Handle(ctx, req, writer)
+
+	api := apiName(req.Procedure)
+	domain, workflowID := breakdown.Workflow()
+	scope := m.scope(api, domain)
+	if size, ok := payloadSize(req.BodySize, req.Body); ok {
+		scope.RecordHistogramValue(metrics.RPCInboundRequestSize, float64(size))
+	}
+	scope.RecordHistogramValue(metrics.RPCInboundResponseSize, float64(writer.size))
+	m.checkSize(scope, "Inbound response is approaching the transport size limit", writer.size,
+		tag.APIName(api),
+		tag.Caller(req.Caller),
+		tag.WorkflowDomainName(domain),
+		tag.WorkflowID(workflowID),
+	)
+	return err
+}
+
+func (m *PayloadSizeMiddleware) Call(ctx context.Context, request *transport.Request, out transport.UnaryOutbound) (*transport.Response, error) {
+	api := apiName(request.Procedure)
+	var domain, workflowID string
+	if breakdown := slowrequest.FromContext(ctx); breakdown != nil {
+		domain, workflowID = breakdown.Workflow()
+	}
+	scope := m.scope(api, domain)
+	if size, ok := payloadSize(request.BodySize, request.Body); ok {
+		scope.RecordHistogramValue(metrics.RPCOutboundRequestSize, float64(size))
+		m.checkSize(scope, "Outbound request is approaching the transport size limit", size,
+			tag.APIName(api),
+			tag.Service(request.Service),
+			tag.WorkflowDomainName(domain),
+			tag.WorkflowID(workflowID),
+		)
+	}
+
+	response, err := out.Call(ctx, request)
+	if response == nil {
+		return response, err
+	}
+	if response.BodySize > 0 {
+		scope.RecordHistogramValue(metrics.RPCOutboundResponseSize, float64(response.BodySize))
+	} else if response.Body != nil {
+		// the body size is not set on all transports, so it is counted as the response is decoded
+		response.Body = &sizeReportingReadCloser{
+			ReadCloser: response.Body,
+			report: func(size int) {
+				scope.RecordHistogramValue(metrics.RPCOutboundResponseSize, float64(size))
+			},
+		}
+	}
+	return response, err
+}
+
+func (m *PayloadSizeMiddleware) scope(api string, domain string) metrics.Scope {
+	return m.MetricsClient.Scope(metrics.RPCPayloadScope, metrics.APINameTag(api), metrics.DomainTag(domain))
+}
+
+func (m *PayloadSizeMiddleware) checkSize(scope metrics.Scope, msg string, size int, tags ...tag.Tag) {
+	ratio := m.WarnRatio()
+	if ratio <= 0 {
+		return
+	}
+	limit := m.MaxSize
+	if limit <= 0 {
+		limit = defaultGRPCSizeLimit
+	}
+	if float64(size) < float64(limit)*ratio {
+		return
+	}
+
+	scope.IncCounter(metrics.RPCOversizePayloadCounter)
+	m.Logger.Warn(msg, append(tags, tag.PayloadSize(size), tag.PayloadSizeLimit(limit))...)
+}
+
+// payloadSize returns the size of a request body, if known without reading it
+func payloadSize(bodySize int, body io.Reader) (int, bool) {
+	if bodySize > 0 {
+		return bodySize, true
+	}
+	if buffer, ok := body.(interface{ Len() int }); ok {
+		return buffer.Len(), true
+	}
+	return 0, false
+}
+
+type countingResponseWriter struct {
+	transport.ResponseWriter
+	size int
+}
+
+func (w *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.size += n
+	return n, err
+}
+
+// SetApplicationErrorMeta forwards the application error to the wrapped writer, which yarpc upcasts to report it
+func (w *countingResponseWriter) SetApplicationErrorMeta(meta *transport.ApplicationErrorMeta) {
+	if setter, ok := w.ResponseWriter.(transport.ApplicationErrorMetaSetter); ok {
+		setter.SetApplicationErrorMeta(meta)
+	}
+}
+
+type sizeReportingReadCloser struct {
+	io.ReadCloser
+	size   int
+	report func(size int)
+}
+
+func (r *sizeReportingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.size += n
+	return n, err
+}
+
+func (r *sizeReportingReadCloser) Close() error {
+	if r.report != nil {
+		r.report(r.size)
+		r.report = nil
+	}
+	return r.ReadCloser.Close()
+}
+
+// SLOMiddleware accounts the inbound requests in the SLOs of the domain they are for,
+// the requests not made for a domain are not accounted.
+type SLOMiddleware struct {
+	Tracker slo.Tracker
+}
+
+func (m *SLOMiddleware) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter, h transport.UnaryHandler) error {
+	// the domain is only known once the request is decoded, handlers annotate the breakdown with it
+	breakdown := slowrequest.FromContext(ctx)
+	if breakdown == nil {
+		ctx, breakdown = slowrequest.NewContext(ctx)
+	}
+
+	start := time.Now()
+	err := h.Handle(ctx, req, resw)
+	if domain, _ := breakdown.Workflow(); domain != "" {
+		m.Tracker.Record(domain, apiName(req.Procedure), time.Since(start), isServerError(err))
+	}
+	return err
+}
+
+// isServerError returns whether the error is the fault of the server rather than of the request,
+// errors of the request are encoded in the response body over thrift, or carry a client error code over gRPC
+func isServerError(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch yarpcerrors.FromError(err).Code() {
+	case yarpcerrors.CodeUnknown,
+		yarpcerrors.CodeInternal,
+		yarpcerrors.CodeUnavailable,
+		yarpcerrors.CodeDeadlineExceeded,
+		yarpcerrors.CodeDataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// CrashContextMiddleware tracks the inbound requests of the host, to include them in the reports of the captured panics
+type CrashContextMiddleware struct {
+	Reporter *crash.Reporter
+}
+
+func (m *CrashContextMiddleware) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter, h transport.UnaryHandler) error {
+	done := m.Reporter.TrackRequest(ctx, apiName(req.Procedure), req.Caller)
+	defer done()
+	return h.Handle(ctx, req, resw)
+}
+
+// LoadSheddingMiddleware rejects the inbound requests shed by the load shedding of the host, before they are decoded
+type LoadSheddingMiddleware struct {
+	Monitor *loadshedding.Monitor
+}
+
+func (m *LoadSheddingMiddleware) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter, h transport.UnaryHandler) error {
+	if err := m.Monitor.Allow(apiName(req.Procedure)); err != nil {
+		if req.Encoding == protobuf.Encoding {
+			// gRPC callers decode the error into a ServiceBusyError
+			return proto.FromError(err)
+		}
+		// thrift errors are encoded in the response body by the handlers, an unavailable error is retried as well
+		return yarpcerrors.UnavailableErrorf("%v", err.Error())
+	}
+	return h.Handle(ctx, req, resw)
+}
@@ -23,18 +23,34 @@ package rpc
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uber-go/tally"
 	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/yarpcerrors"
 	"go.uber.org/yarpc/yarpctest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/blobstore"
+	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/crash"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/slo"
+	"github.com/uber/cadence/common/slowrequest"
 )
 
 func TestAuthOubboundMiddleware(t *testing.T) {
@@ -172,6 +188,228 @@ func TestHeaderForwardingMiddleware(t *testing.T) {
 	})
 }
 
+func TestSlowRequestLogMiddleware(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	thresholds := map[string]time.Duration{
+		"StartWorkflowExecution": time.Millisecond,
+		"PollForDecisionTask":    time.Hour,
+	}
+	m := SlowRequestLogMiddleware{
+		Logger: loggerimpl.NewLogger(zap.New(core)),
+		Threshold: func(opts ...dynamicconfig.FilterOption) time.Duration {
+			filters := make(map[dynamicconfig.Filter]interface{})
+			for _, opt := range opts {
+				opt(filters)
+			}
+			return thresholds[filters[dynamicconfig.APIName].(string)]
+		},
+	}
+	slowHandler := &fakeSlowHandler{latency: 5 * time.Millisecond}
+
+	err := m.Handle(context.Background(), &transport.Request{Procedure: "WorkflowService::DescribeDomain"}, nil, slowHandler)
+	assert.NoError(t, err)
+	assert.Nil(t, slowrequest.FromContext(slowHandler.ctx), "requests without a threshold are not tracked")
+
+	err = m.Handle(context.Background(), &transport.Request{Procedure: "uber.cadence.api.v1.WorkflowAPI::PollForDecisionTask"}, nil, slowHandler)
+	assert.NoError(t, err)
+	assert.NotNil(t, slowrequest.FromContext(slowHandler.ctx))
+	assert.Zero(t, logs.Len(), "requests below the threshold are not logged")
+
+	err = m.Handle(context.Background(), &transport.Request{Procedure: "uber.cadence.api.v1.WorkflowAPI::StartWorkflowExecution", Caller: "x-caller"}, nil, slowHandler)
+	assert.NoError(t, err)
+	entries := logs.TakeAll()
+	assert.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "StartWorkflowExecution", fields["api-name"])
+	assert.Equal(t, "x-caller", fields["caller"])
+	assert.Equal(t, "test-domain", fields["wf-domain-name"])
+	assert.Equal(t, int64(1), fields["persistence-calls"])
+	assert.Equal(t, int64(1), fields["history-calls"])
+}
+
+func TestDependencyLatencyMiddleware(t *testing.T) {
+	m := DependencyLatencyMiddleware{}
+	_, err := m.Call(context.Background(), &transport.Request{Service: "cadence-history"}, &fakeOutbound{})
+	assert.NoError(t, err)
+
+	ctx, breakdown := slowrequest.NewContext(context.Background())
+	_, err = m.Call(ctx, &transport.Request{Service: "cadence-matching"}, &fakeOutbound{err: assert.AnError})
+	assert.Error(t, err)
+	_, err = m.Call(ctx, &transport.Request{Service: "cadence-matching"}, &fakeOutbound{})
+	assert.NoError(t, err)
+	assert.Contains(t, breakdown.Tags(), tag.DependencyCalls("matching", 2))
+}
+
+func TestPayloadSizeMiddleware(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	testScope := tally.NewTestScope("test", nil)
+	m := PayloadSizeMiddleware{
+		MetricsClient: metrics.NewClient(testScope, metrics.Frontend),
+		Logger:        loggerimpl.NewLogger(zap.New(core)),
+		MaxSize:       100,
+		WarnRatio:     dynamicconfig.GetFloatPropertyFn(0.8),
+	}
+
+	handler := &fakePayloadHandler{response: make([]byte, 10)}
+	resw := &transporttest.FakeResponseWriter{}
+	err := m.Handle(context.Background(), &transport.Request{
+		Procedure: "uber.cadence.api.v1.WorkflowAPI::DescribeWorkflowExecution",
+		Body:      bytes.NewReader(make([]byte, 20)),
+	}, resw, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, resw.Body.Len())
+	assert.Zero(t, logs.Len(), "payloads below the warn ratio are not logged")
+
+	handler.response = make([]byte, 90)
+	resw = &transporttest.FakeResponseWriter{}
+	err = m.Handle(context.Background(), &transport.Request{
+		Procedure: "uber.cadence.api.v1.WorkflowAPI::GetWorkflowExecutionHistory",
+		Caller:    "x-caller",
+	}, resw, handler)
+	assert.NoError(t, err)
+	assert.NotNil(t, resw.ApplicationErrorMeta, "application errors are forwarded to the wrapped writer")
+	entries := logs.TakeAll()
+	assert.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "GetWorkflowExecutionHistory", fields["api-name"])
+	assert.Equal(t, "test-domain", fields["wf-domain-name"])
+	assert.Equal(t, "test-workflow", fields["wf-id"])
+	assert.Equal(t, int64(90), fields["payload-size"])
+
+	_, err = m.Call(context.Background(), &transport.Request{
+		Procedure: "uber.cadence.history.v1.HistoryAPI::RecordActivityTaskStarted",
+		Body:      bytes.NewReader(make([]byte, 30)),
+	}, &fakeOutbound{response: &transport.Response{Body: ioutil.NopCloser(bytes.NewReader(make([]byte, 40)))}})
+	assert.NoError(t, err)
+
+	sizes := map[string]float64{}
+	for _, h := range testScope.Snapshot().Histograms() {
+		for bucket, count := range h.Values() {
+			if count > 0 {
+				sizes[h.Name()+"/"+h.Tags()["api_name"]] = bucket
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"test.rpc_inbound_request_size/DescribeWorkflowExecution":    1 << 10,
+		"test.rpc_inbound_response_size/DescribeWorkflowExecution":   1 << 10,
+		"test.rpc_inbound_response_size/GetWorkflowExecutionHistory": 1 << 10,
+		"test.rpc_outbound_request_size/RecordActivityTaskStarted":   1 << 10,
+	}, sizes, "outbound response size is only known once its body is closed")
+}
+
+func TestSLOMiddleware(t *testing.T) {
+	tracker := slo.NewTracker(
+		&slo.Config{
+			AvailabilityTarget: dynamicconfig.GetFloatPropertyFnFilteredByDomain(0.9),
+			LatencyTarget:      dynamicconfig.GetFloatPropertyFnFilteredByDomain(0),
+			LatencyThreshold:   dynamicconfig.GetDurationPropertyFnFilteredByDomain(time.Second),
+		},
+		metrics.NewNoopMetricsClient(),
+		clock.NewRealTimeSource(),
+	)
+	m := SLOMiddleware{Tracker: tracker}
+
+	for _, handler := range []*fakeDomainHandler{
+		{domain: "test-domain"},
+		{domain: "test-domain", err: yarpcerrors.InvalidArgumentErrorf("bad request")},
+		{domain: "test-domain", err: fmt.Errorf("internal error")},
+		{err: fmt.Errorf("internal error")},
+	} {
+		err := m.Handle(context.Background(), &transport.Request{
+			Procedure: "uber.cadence.api.v1.WorkflowAPI::StartWorkflowExecution",
+		}, &transporttest.FakeResponseWriter{}, handler)
+		assert.Equal(t, handler.err, err)
+	}
+
+	reports := tracker.Report("")
+	assert.Len(t, reports, 1, "requests without a domain are not accounted")
+	assert.Equal(t, "test-domain", reports[0].Domain)
+	assert.Nil(t, reports[0].Latency)
+	assert.Equal(t, int64(3), reports[0].Availability.Requests)
+	assert.Equal(t, int64(1), reports[0].Availability.BadRequests, "only server errors burn the budget")
+}
+
+func TestIsServerError(t *testing.T) {
+	assert.False(t, isServerError(nil))
+	assert.True(t, isServerError(fmt.Errorf("internal error")))
+	assert.True(t, isServerError(yarpcerrors.InternalErrorf("internal error")))
+	assert.True(t, isServerError(yarpcerrors.DeadlineExceededErrorf("timeout")))
+	assert.False(t, isServerError(yarpcerrors.InvalidArgumentErrorf("bad request")))
+	assert.False(t, isServerError(yarpcerrors.NotFoundErrorf("not found")))
+	assert.False(t, isServerError(yarpcerrors.ResourceExhaustedErrorf("rate limited")))
+}
+
+func TestCrashContextMiddleware(t *testing.T) {
+	var report crash.Report
+	sink := &blobstore.MockClient{}
+	sink.On("Put", mock.Anything, mock.Anything).Return(&blobstore.PutResponse{}, nil).Run(func(args mock.Arguments) {
+		request := args.Get(1).(*blobstore.PutRequest)
+		assert.NoError(t, json.Unmarshal(request.Blob.Body, &report))
+	})
+	reporter := crash.NewReporter(sink, metrics.NewNoopMetricsClient(), loggerimpl.NewNopLogger(), "test-host")
+	m := CrashContextMiddleware{Reporter: reporter}
+
+	ctx, _ := slowrequest.NewContext(context.Background())
+	err := m.Handle(ctx, &transport.Request{
+		Procedure: "uber.cadence.api.v1.WorkflowAPI::StartWorkflowExecution",
+		Caller:    "test-caller",
+	}, &transporttest.FakeResponseWriter{}, &fakeDomainHandler{domain: "test-domain", onHandle: func() {
+		reporter.Report(fmt.Errorf("test panic"), "")
+	}})
+	assert.NoError(t, err)
+
+	sink.AssertExpectations(t)
+	assert.Equal(t, []crash.Request{{
+		API:      "StartWorkflowExecution",
+		Caller:   "test-caller",
+		Domain:   "test-domain",
+		Start:    report.Requests[0].Start,
+		InFlight: true,
+	}}, report.Requests)
+}
+
+type fakeDomainHandler struct {
+	domain   string
+	err      error
+	onHandle func()
+}
+
+func (h *fakeDomainHandler) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter) error {
+	slowrequest.SetWorkflow(ctx, h.domain, "")
+	if h.onHandle != nil {
+		h.onHandle()
+	}
+	return h.err
+}
+
+type fakeSlowHandler struct {
+	latency time.Duration
+	ctx     context.Context
+}
+
+func (h *fakeSlowHandler) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter) error {
+	h.ctx = ctx
+	slowrequest.SetWorkflow(ctx, "test-domain", "")
+	slowrequest.RecordDependency(ctx, slowrequest.DependencyPersistence, time.Millisecond)
+	slowrequest.RecordDependency(ctx, "history", time.Millisecond)
+	time.Sleep(h.latency)
+	return nil
+}
+
+type fakePayloadHandler struct {
+	response []byte
+}
+
+func (h *fakePayloadHandler) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter) error {
+	slowrequest.SetWorkflow(ctx, "test-domain", "test-workflow")
+	if len(h.response) > 50 {
+		resw.(transport.ApplicationErrorMetaSetter).SetApplicationErrorMeta(&transport.ApplicationErrorMeta{})
+	}
+	_, err := resw.Write(h.response)
+	return err
+}
+
 type fakeHandler struct {
 	ctx context.Context
 }
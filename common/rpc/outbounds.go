@@ -183,15 +183,20 @@ type directOutbound struct {
 	serviceName string
 	grpcEnabled bool
 	tlsConfig   *tls.Config
+	pool        *GRPCPool
 }
 
-func NewDirectOutbound(serviceName string, grpcEnabled bool, tlsConfig *tls.Config) OutboundsBuilder {
-	return directOutbound{serviceName, grpcEnabled, tlsConfig}
+// NewDirectOutbound builds the outbound of the calls to the peer named by their shard key, the gRPC calls keep
+// a pool of connections to every peer if the pool is not nil, otherwise a peer is dialed while it has pending calls
+func NewDirectOutbound(serviceName string, grpcEnabled bool, tlsConfig *tls.Config, pool *GRPCPool) OutboundsBuilder {
+	return directOutbound{serviceName, grpcEnabled, tlsConfig, pool}
 }
 
 func (o directOutbound) Build(grpc *grpc.Transport, tchannel *tchannel.Transport) (yarpc.Outbounds, error) {
 	var outbound transport.UnaryOutbound
-	if o.grpcEnabled {
+	if o.grpcEnabled && o.pool != nil {
+		outbound = grpc.NewOutbound(newPooledChooser(grpc, o.tlsConfig, o.pool))
+	} else if o.grpcEnabled {
 		directChooser, err := direct.New(direct.Configuration{}, createDialer(grpc, o.tlsConfig))
 		if err != nil {
 			return nil, err
@@ -159,12 +159,12 @@ func TestDirectOutbound(t *testing.T) {
 	grpc := &grpc.Transport{}
 	tchannel := &tchannel.Transport{}
 
-	outbounds, err := NewDirectOutbound("cadence-history", false, nil).Build(grpc, tchannel)
+	outbounds, err := NewDirectOutbound("cadence-history", false, nil, nil).Build(grpc, tchannel)
 	assert.NoError(t, err)
 	assert.Equal(t, "cadence-history", outbounds["cadence-history"].ServiceName)
 	assert.NotNil(t, outbounds["cadence-history"].Unary)
 
-	outbounds, err = NewDirectOutbound("cadence-history", true, nil).Build(grpc, tchannel)
+	outbounds, err = NewDirectOutbound("cadence-history", true, nil, nil).Build(grpc, tchannel)
 	assert.NoError(t, err)
 	assert.Equal(t, "cadence-history", outbounds["cadence-history"].ServiceName)
 	assert.NotNil(t, outbounds["cadence-history"].Unary)
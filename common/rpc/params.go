@@ -27,11 +27,19 @@ import (
 	"regexp"
 	"strconv"
 
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/slo"
 
 	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/middleware"
+	"google.golang.org/grpc/keepalive"
 )
 
 // Params allows to configure rpc.Factory
@@ -48,10 +56,19 @@ type Params struct {
 	OutboundMiddleware yarpc.OutboundMiddleware
 
 	OutboundsBuilder OutboundsBuilder
+
+	// GRPCPool configures the connections of the direct gRPC outbounds, its transport options are set by the factory
+	GRPCPool *GRPCPool
+
+	// Tracer propagates the trace context over inbound and outbound calls, global tracer is used if nil
+	Tracer opentracing.Tracer
+
+	// SLOTracker accounts the inbound requests in the SLOs of their domain, it is only set for the frontend
+	SLOTracker slo.Tracker
 }
 
 // NewParams creates parameters for rpc.Factory from the given config
-func NewParams(serviceName string, config *config.Config, dc *dynamicconfig.Collection) (Params, error) {
+func NewParams(serviceName string, config *config.Config, dc *dynamicconfig.Collection, logger log.Logger, metricsClient metrics.Client) (Params, error) {
 	serviceConfig, err := config.GetServiceConfig(serviceName)
 	if err != nil {
 		return Params{}, err
@@ -62,7 +79,7 @@ func NewParams(serviceName string, config *config.Config, dc *dynamicconfig.Coll
 		return Params{}, fmt.Errorf("get listen IP: %v", err)
 	}
 
-	inboundTLS, err := serviceConfig.RPC.TLS.ToTLSConfig()
+	inboundTLS, err := serviceConfig.RPC.TLS.ToServerTLSConfig()
 	if err != nil {
 		return Params{}, fmt.Errorf("inbound TLS config: %v", err)
 	}
@@ -79,6 +96,14 @@ func NewParams(serviceName string, config *config.Config, dc *dynamicconfig.Coll
 	}
 
 	enableGRPCOutbound := dc.GetBoolProperty(dynamicconfig.EnableGRPCOutbound)()
+	grpcPool := &GRPCPool{
+		ConnectionsPerPeer: dc.GetIntProperty(dynamicconfig.GRPCConnectionsPerPeer)(),
+		KeepAlive: keepalive.ClientParameters{
+			Time:    dc.GetDurationProperty(dynamicconfig.GRPCKeepAliveTime)(),
+			Timeout: dc.GetDurationProperty(dynamicconfig.GRPCKeepAliveTimeout)(),
+		},
+		IdleTimeout: dc.GetDurationProperty(dynamicconfig.GRPCIdleConnectionTimeout)(),
+	}
 
 	publicClientOutbound, err := newPublicClientOutbound(config)
 	if err != nil {
@@ -94,26 +119,61 @@ func NewParams(serviceName string, config *config.Config, dc *dynamicconfig.Coll
 		forwardingRules = config.HeaderForwardingRules
 	}
 
+	payloadSize := &PayloadSizeMiddleware{
+		MetricsClient: metricsClient,
+		Logger:        logger,
+		MaxSize:       serviceConfig.RPC.GRPCMaxMsgSize,
+		WarnRatio:     dc.GetFloat64Property(dynamicconfig.RPCPayloadSizeWarnRatio),
+	}
+
+	inboundMiddleware := []middleware.UnaryInbound{
+		&InboundMetricsMiddleware{},
+		&SlowRequestLogMiddleware{
+			Logger:    logger,
+			Threshold: dc.GetDurationProperty(dynamicconfig.SlowRequestLogThreshold),
+		},
+		payloadSize,
+	}
+	var sloTracker slo.Tracker
+	if serviceName == service.Frontend {
+		sloTracker = slo.NewTracker(
+			&slo.Config{
+				AvailabilityTarget: dc.GetFloat64PropertyFilteredByDomain(dynamicconfig.FrontendSLOAvailabilityTarget),
+				LatencyTarget:      dc.GetFloat64PropertyFilteredByDomain(dynamicconfig.FrontendSLOLatencyTarget),
+				LatencyThreshold:   dc.GetDurationPropertyFilteredByDomain(dynamicconfig.FrontendSLOLatencyThreshold),
+			},
+			metricsClient,
+			clock.NewRealTimeSource(),
+		)
+		inboundMiddleware = append(inboundMiddleware, &SLOMiddleware{Tracker: sloTracker})
+	}
+
 	return Params{
 		ServiceName:     serviceName,
 		TChannelAddress: net.JoinHostPort(listenIP.String(), strconv.Itoa(int(serviceConfig.RPC.Port))),
 		GRPCAddress:     net.JoinHostPort(listenIP.String(), strconv.Itoa(int(serviceConfig.RPC.GRPCPort))),
 		GRPCMaxMsgSize:  serviceConfig.RPC.GRPCMaxMsgSize,
 		OutboundsBuilder: CombineOutbounds(
-			NewDirectOutbound(service.History, enableGRPCOutbound, outboundTLS[service.History]),
-			NewDirectOutbound(service.Matching, enableGRPCOutbound, outboundTLS[service.Matching]),
+			NewDirectOutbound(service.History, enableGRPCOutbound, outboundTLS[service.History], grpcPool),
+			NewDirectOutbound(service.Matching, enableGRPCOutbound, outboundTLS[service.Matching], grpcPool),
 			publicClientOutbound,
 		),
+		GRPCPool:    grpcPool,
 		InboundTLS:  inboundTLS,
 		OutboundTLS: outboundTLS,
 		InboundMiddleware: yarpc.InboundMiddleware{
-			Unary: &InboundMetricsMiddleware{},
+			Unary: yarpc.UnaryInboundMiddleware(inboundMiddleware...),
 		},
 		OutboundMiddleware: yarpc.OutboundMiddleware{
-			Unary: &HeaderForwardingMiddleware{
-				Rules: forwardingRules,
-			},
+			Unary: yarpc.UnaryOutboundMiddleware(
+				&HeaderForwardingMiddleware{
+					Rules: forwardingRules,
+				},
+				&DependencyLatencyMiddleware{},
+				payloadSize,
+			),
 		},
+		SLOTracker: sloTracker,
 	}, nil
 }
 
@@ -168,3 +228,38 @@ func getListenIP(config config.RPC) (net.IP, error) {
 	}
 	return ListenIP()
 }
+
+// NewAdminParams creates parameters for rpc.AdminFactory from the given config and the parameters of the service,
+// nil is returned if the service has no dedicated listener for the admin APIs
+func NewAdminParams(serviceName string, cfg *config.Config, params Params) (*Params, error) {
+	serviceConfig, err := cfg.GetServiceConfig(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	adminConfig := serviceConfig.AdminRPC
+	if adminConfig == nil {
+		return nil, nil
+	}
+
+	listenIP, err := getListenIP(config.RPC{
+		BindOnLocalHost: adminConfig.BindOnLocalHost,
+		BindOnIP:        adminConfig.BindOnIP,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get admin listen IP: %v", err)
+	}
+
+	inboundTLS, err := adminConfig.TLS.ToServerTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("admin inbound TLS config: %v", err)
+	}
+
+	return &Params{
+		ServiceName:       serviceName,
+		GRPCAddress:       net.JoinHostPort(listenIP.String(), strconv.Itoa(int(adminConfig.GRPCPort))),
+		GRPCMaxMsgSize:    params.GRPCMaxMsgSize,
+		InboundTLS:        inboundTLS,
+		InboundMiddleware: params.InboundMiddleware,
+		Tracer:            params.Tracer,
+	}, nil
+}
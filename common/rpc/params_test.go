@@ -28,6 +28,8 @@ import (
 
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/service"
 )
 
@@ -40,39 +42,40 @@ func TestNewParams(t *testing.T) {
 			Services:     map[string]config.Service{"frontend": svc}}
 	}
 
-	_, err := NewParams(serviceName, &config.Config{}, dc)
+	_, err := NewParams(serviceName, &config.Config{}, dc, log.NewNoop(), metrics.NewNoopMetricsClient())
 	assert.EqualError(t, err, "no config section for service: frontend")
 
-	_, err = NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{BindOnLocalHost: true, BindOnIP: "1.2.3.4"}}), dc)
+	_, err = NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{BindOnLocalHost: true, BindOnIP: "1.2.3.4"}}), dc, log.NewNoop(), metrics.NewNoopMetricsClient())
 	assert.EqualError(t, err, "get listen IP: bindOnLocalHost and bindOnIP are mutually exclusive")
 
-	_, err = NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{BindOnIP: "invalidIP"}}), dc)
+	_, err = NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{BindOnIP: "invalidIP"}}), dc, log.NewNoop(), metrics.NewNoopMetricsClient())
 	assert.EqualError(t, err, "get listen IP: unable to parse bindOnIP value or it is not an IPv4 or IPv6 address: invalidIP")
 
-	_, err = NewParams(serviceName, &config.Config{Services: map[string]config.Service{"frontend": {}}}, dc)
+	_, err = NewParams(serviceName, &config.Config{Services: map[string]config.Service{"frontend": {}}}, dc, log.NewNoop(), metrics.NewNoopMetricsClient())
 	assert.EqualError(t, err, "public client outbound: need to provide an endpoint config for PublicClient")
 
-	_, err = NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{BindOnLocalHost: true, TLS: config.TLS{Enabled: true, CertFile: "invalid", KeyFile: "invalid"}}}), dc)
+	_, err = NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{BindOnLocalHost: true, TLS: config.TLS{Enabled: true, CertFile: "invalid", KeyFile: "invalid"}}}), dc, log.NewNoop(), metrics.NewNoopMetricsClient())
 	assert.EqualError(t, err, "inbound TLS config: open invalid: no such file or directory")
 
 	_, err = NewParams(serviceName, &config.Config{Services: map[string]config.Service{
 		"frontend": {RPC: config.RPC{BindOnLocalHost: true}},
 		"history":  {RPC: config.RPC{TLS: config.TLS{Enabled: true, CaFile: "invalid"}}},
-	}}, dc)
+	}}, dc, log.NewNoop(), metrics.NewNoopMetricsClient())
 	assert.EqualError(t, err, "outbound cadence-history TLS config: open invalid: no such file or directory")
 
-	params, err := NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{BindOnLocalHost: true, Port: 1111, GRPCPort: 2222, GRPCMaxMsgSize: 3333}}), dc)
+	params, err := NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{BindOnLocalHost: true, Port: 1111, GRPCPort: 2222, GRPCMaxMsgSize: 3333}}), dc, log.NewNoop(), metrics.NewNoopMetricsClient())
 	assert.NoError(t, err)
 	assert.Equal(t, "127.0.0.1:1111", params.TChannelAddress)
 	assert.Equal(t, "127.0.0.1:2222", params.GRPCAddress)
 	assert.Equal(t, 3333, params.GRPCMaxMsgSize)
+	assert.Equal(t, 1, params.GRPCPool.ConnectionsPerPeer)
 	assert.Nil(t, params.InboundTLS)
 
-	params, err = NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{BindOnIP: "1.2.3.4", GRPCPort: 2222}}), dc)
+	params, err = NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{BindOnIP: "1.2.3.4", GRPCPort: 2222}}), dc, log.NewNoop(), metrics.NewNoopMetricsClient())
 	assert.NoError(t, err)
 	assert.Equal(t, "1.2.3.4:2222", params.GRPCAddress)
 
-	params, err = NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{GRPCPort: 2222, TLS: config.TLS{Enabled: true}}}), dc)
+	params, err = NewParams(serviceName, makeConfig(config.Service{RPC: config.RPC{GRPCPort: 2222, TLS: config.TLS{Enabled: true}}}), dc, log.NewNoop(), metrics.NewNoopMetricsClient())
 	assert.NoError(t, err)
 	ip, port, err := net.SplitHostPort(params.GRPCAddress)
 	assert.NoError(t, err)
@@ -80,3 +83,31 @@ func TestNewParams(t *testing.T) {
 	assert.NotNil(t, net.ParseIP(ip))
 	assert.NotNil(t, params.InboundTLS)
 }
+
+func TestNewAdminParams(t *testing.T) {
+	serviceName := service.Frontend
+	makeConfig := func(adminRPC *config.AdminRPC) *config.Config {
+		return &config.Config{Services: map[string]config.Service{"frontend": {AdminRPC: adminRPC}}}
+	}
+	serviceParams := Params{GRPCMaxMsgSize: 1111}
+
+	_, err := NewAdminParams(serviceName, &config.Config{}, serviceParams)
+	assert.EqualError(t, err, "no config section for service: frontend")
+
+	params, err := NewAdminParams(serviceName, makeConfig(nil), serviceParams)
+	assert.NoError(t, err)
+	assert.Nil(t, params)
+
+	_, err = NewAdminParams(serviceName, makeConfig(&config.AdminRPC{BindOnIP: "invalidIP"}), serviceParams)
+	assert.EqualError(t, err, "get admin listen IP: unable to parse bindOnIP value or it is not an IPv4 or IPv6 address: invalidIP")
+
+	_, err = NewAdminParams(serviceName, makeConfig(&config.AdminRPC{BindOnLocalHost: true, TLS: config.TLS{Enabled: true, CertFile: "invalid", KeyFile: "invalid"}}), serviceParams)
+	assert.EqualError(t, err, "admin inbound TLS config: open invalid: no such file or directory")
+
+	params, err = NewAdminParams(serviceName, makeConfig(&config.AdminRPC{BindOnLocalHost: true, GRPCPort: 2222}), serviceParams)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:2222", params.GRPCAddress)
+	assert.Empty(t, params.TChannelAddress)
+	assert.Equal(t, 1111, params.GRPCMaxMsgSize)
+	assert.Nil(t, params.InboundTLS)
+}
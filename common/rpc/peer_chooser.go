@@ -36,22 +36,25 @@ type (
 		CreatePeerChooser(transport peer.Transport, address string) (peer.Chooser, error)
 	}
 	dnsPeerChooserFactory struct {
-		interval time.Duration
-		logger   log.Logger
+		interval   time.Duration
+		subsetSize int
+		logger     log.Logger
 	}
 )
 
-func NewDNSPeerChooserFactory(interval time.Duration, logger log.Logger) PeerChooserFactory {
+// NewDNSPeerChooserFactory creates peer choosers which refresh their peers by DNS lookup, only a subset of subsetSize
+// peers is used if subsetSize is not 0
+func NewDNSPeerChooserFactory(interval time.Duration, subsetSize int, logger log.Logger) PeerChooserFactory {
 	if interval <= 0 {
 		interval = defaultDNSRefreshInterval
 	}
 
-	return &dnsPeerChooserFactory{interval, logger}
+	return &dnsPeerChooserFactory{interval, subsetSize, logger}
 }
 
 func (f *dnsPeerChooserFactory) CreatePeerChooser(transport peer.Transport, address string) (peer.Chooser, error) {
 	peerList := roundrobin.New(transport)
-	peerListUpdater, err := newDNSUpdater(peerList, address, f.interval, f.logger)
+	peerListUpdater, err := newDNSUpdater(peerList, address, f.interval, f.subsetSize, f.logger)
 	if err != nil {
 		return nil, err
 	}
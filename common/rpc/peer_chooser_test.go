@@ -38,7 +38,7 @@ func TestDNSPeerChooserFactory(t *testing.T) {
 	ctx := context.Background()
 	interval := 100 * time.Millisecond
 
-	factory := NewDNSPeerChooserFactory(interval, logger)
+	factory := NewDNSPeerChooserFactory(interval, 0, logger)
 	peerTransport := &fakePeerTransport{}
 
 	// Ensure invalid address returns error
@@ -76,3 +76,46 @@ func (p *fakePeer) Identifier() string  { return "fakePeer" }
 func (p *fakePeer) Status() peer.Status { return peer.Status{ConnectionStatus: peer.Available} }
 func (p *fakePeer) StartRequest()       {}
 func (p *fakePeer) EndRequest()         {}
+
+func TestDNSUpdater_Subset(t *testing.T) {
+	list := &fakeStatusList{unavailable: map[string]bool{"10.0.0.2:7833": true}}
+	updater, err := newDNSUpdater(list, "localhost:7833", time.Second, 2, log.NewNoop())
+	require.NoError(t, err)
+
+	addresses := map[string]struct{}{"10.0.0.1:7833": {}, "10.0.0.2:7833": {}, "10.0.0.3:7833": {}}
+	subset := updater.subset(addresses)
+	assert.Equal(t, map[string]struct{}{"10.0.0.1:7833": {}, "10.0.0.3:7833": {}}, subset)
+
+	// the subset is stable while the availability of the peers doesn't change
+	assert.Equal(t, subset, updater.subset(addresses))
+
+	list.unavailable = map[string]bool{"10.0.0.1:7833": true, "10.0.0.2:7833": true, "10.0.0.3:7833": true}
+	assert.Len(t, updater.subset(addresses), 2)
+}
+
+type fakeStatusList struct {
+	peer.List
+	unavailable map[string]bool
+}
+
+func (l *fakeStatusList) Peers() []peer.StatusPeer {
+	var peers []peer.StatusPeer
+	for addr, unavailable := range l.unavailable {
+		status := peer.Available
+		if unavailable {
+			status = peer.Unavailable
+		}
+		peers = append(peers, &fakeStatusPeer{addr, status})
+	}
+	return peers
+}
+
+type fakeStatusPeer struct {
+	id     string
+	status peer.ConnectionStatus
+}
+
+func (p *fakeStatusPeer) Identifier() string  { return p.id }
+func (p *fakeStatusPeer) Status() peer.Status { return peer.Status{ConnectionStatus: p.status} }
+func (p *fakeStatusPeer) StartRequest()       {}
+func (p *fakeStatusPeer) EndRequest()         {}
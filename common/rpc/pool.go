@@ -0,0 +1,273 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/peer/hostport"
+	"go.uber.org/yarpc/transport/grpc"
+	"go.uber.org/yarpc/yarpcerrors"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+)
+
+const defaultIdleConnectionTimeout = time.Minute * 10
+
+type (
+	// GRPCPool configures the connections of the outbound gRPC calls to the peers of a service
+	GRPCPool struct {
+		// ConnectionsPerPeer is the number of connections kept to every peer, the calls to a peer are spread over them
+		ConnectionsPerPeer int
+		// KeepAlive pings the idle connections so that the half-dead ones are closed and redialed, disabled if its Time is 0
+		KeepAlive keepalive.ClientParameters
+		// IdleTimeout is how long the connections to a peer are kept after its last call
+		IdleTimeout time.Duration
+
+		// transportOptions are set by the factory, the extra connections of a peer are dialed by extra transports
+		// which have the same options as the transport of the dispatcher
+		transportOptions []grpc.TransportOption
+	}
+
+	// pooledChooser chooses the peer of a call from its shard key like the direct chooser, but keeps the connections
+	// to a peer between its calls and spreads the calls over several connections, each one dialed by its own transport
+	pooledChooser struct {
+		status      int32
+		dialers     []peer.Transport
+		transports  []*grpc.Transport
+		idleTimeout time.Duration
+		timeSource  clock.TimeSource
+
+		sync.RWMutex
+		peers      map[string]*pooledPeer
+		shutdownC  chan struct{}
+		shutdownWG sync.WaitGroup
+	}
+
+	pooledPeer struct {
+		id       peer.Identifier
+		conns    []peer.Peer
+		next     uint32
+		lastUsed int64
+	}
+)
+
+var _ peer.Chooser = (*pooledChooser)(nil)
+
+func (p *GRPCPool) dialOptions() []grpc.DialOption {
+	if p.KeepAlive.Time <= 0 {
+		return nil
+	}
+	return []grpc.DialOption{grpc.KeepaliveParams(p.KeepAlive)}
+}
+
+func newPooledChooser(primary *grpc.Transport, tlsConfig *tls.Config, pool *GRPCPool) *pooledChooser {
+	connections := pool.ConnectionsPerPeer
+	if connections < 1 {
+		connections = 1
+	}
+	idleTimeout := pool.IdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = defaultIdleConnectionTimeout
+	}
+
+	// the dispatcher owns the primary transport, the chooser owns the extra ones
+	var transports []*grpc.Transport
+	dialers := []peer.Transport{createDialer(primary, tlsConfig, pool.dialOptions()...)}
+	for i := 1; i < connections; i++ {
+		transport := grpc.NewTransport(pool.transportOptions...)
+		transports = append(transports, transport)
+		dialers = append(dialers, createDialer(transport, tlsConfig, pool.dialOptions()...))
+	}
+
+	return &pooledChooser{
+		status:      common.DaemonStatusInitialized,
+		dialers:     dialers,
+		transports:  transports,
+		idleTimeout: idleTimeout,
+		timeSource:  clock.NewRealTimeSource(),
+		peers:       make(map[string]*pooledPeer),
+		shutdownC:   make(chan struct{}),
+	}
+}
+
+// Start starts the extra transports and the release of the idle peers
+func (c *pooledChooser) Start() error {
+	if !atomic.CompareAndSwapInt32(&c.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return nil
+	}
+
+	for _, transport := range c.transports {
+		if err := transport.Start(); err != nil {
+			return err
+		}
+	}
+	c.shutdownWG.Add(1)
+	go c.releaseIdlePeersLoop()
+	return nil
+}
+
+// Stop releases all the peers and stops the extra transports
+func (c *pooledChooser) Stop() error {
+	if !atomic.CompareAndSwapInt32(&c.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return nil
+	}
+
+	close(c.shutdownC)
+	c.shutdownWG.Wait()
+
+	c.Lock()
+	var errs error
+	for key, p := range c.peers {
+		errs = multierr.Append(errs, c.release(p))
+		delete(c.peers, key)
+	}
+	c.Unlock()
+
+	for _, transport := range c.transports {
+		errs = multierr.Append(errs, transport.Stop())
+	}
+	return errs
+}
+
+// IsRunning returns whether the chooser is started
+func (c *pooledChooser) IsRunning() bool {
+	return atomic.LoadInt32(&c.status) == common.DaemonStatusStarted
+}
+
+// Choose returns an available connection to the peer of the shard key of the request
+func (c *pooledChooser) Choose(ctx context.Context, req *transport.Request) (peer.Peer, func(error), error) {
+	if req.ShardKey == "" {
+		return nil, nil, yarpcerrors.InvalidArgumentErrorf("pooled chooser requires ShardKey to be non-empty")
+	}
+
+	p, err := c.getOrRetain(req.ShardKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	atomic.StoreInt64(&p.lastUsed, c.timeSource.Now().UnixNano())
+	return p.choose(), func(error) {}, nil
+}
+
+// NotifyStatusChanged implements peer.Subscriber, the status of the connections is checked on every call instead
+func (c *pooledChooser) NotifyStatusChanged(peer.Identifier) {}
+
+func (c *pooledChooser) getOrRetain(key string) (*pooledPeer, error) {
+	c.RLock()
+	p, ok := c.peers[key]
+	c.RUnlock()
+	if ok {
+		return p, nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if p, ok := c.peers[key]; ok {
+		return p, nil
+	}
+
+	p = &pooledPeer{id: hostport.Identify(key)}
+	for _, dialer := range c.dialers {
+		conn, err := dialer.RetainPeer(p.id, c)
+		if err != nil {
+			return nil, multierr.Append(err, c.release(p))
+		}
+		p.conns = append(p.conns, conn)
+	}
+	c.peers[key] = p
+	return p, nil
+}
+
+func (c *pooledChooser) release(p *pooledPeer) error {
+	var errs error
+	for i := range p.conns {
+		errs = multierr.Append(errs, c.dialers[i].ReleasePeer(p.id, c))
+	}
+	return errs
+}
+
+func (c *pooledChooser) releaseIdlePeersLoop() {
+	defer c.shutdownWG.Done()
+
+	ticker := time.NewTicker(c.idleTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.releaseIdlePeers()
+		case <-c.shutdownC:
+			return
+		}
+	}
+}
+
+func (c *pooledChooser) releaseIdlePeers() {
+	idleSince := c.timeSource.Now().Add(-c.idleTimeout).UnixNano()
+
+	c.Lock()
+	defer c.Unlock()
+	for key, p := range c.peers {
+		if atomic.LoadInt64(&p.lastUsed) < idleSince {
+			// the errors are only about the peers the transports don't know anymore, there is nothing left to release
+			_ = c.release(p)
+			delete(c.peers, key)
+		}
+	}
+}
+
+// choose returns the available connections in round robin, or one of the connections if none is available so that
+// the call fails or waits for its connection like it would without a pool
+func (p *pooledPeer) choose() peer.Peer {
+	next := atomic.AddUint32(&p.next, 1)
+	available := 0
+	for _, conn := range p.conns {
+		if conn.Status().ConnectionStatus == peer.Available {
+			available++
+		}
+	}
+	if available == 0 {
+		return p.conns[next%uint32(len(p.conns))]
+	}
+
+	skip := int(next % uint32(available))
+	for _, conn := range p.conns {
+		if conn.Status().ConnectionStatus != peer.Available {
+			continue
+		}
+		if skip == 0 {
+			return conn
+		}
+		skip--
+	}
+	// the status of a connection changed in between, any connection is fine
+	return p.conns[next%uint32(len(p.conns))]
+}
@@ -0,0 +1,146 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/transport/grpc"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+)
+
+type (
+	fakeConnDialer struct {
+		status   peer.ConnectionStatus
+		retained map[string]int
+	}
+	fakeConn struct {
+		id     string
+		dialer *fakeConnDialer
+	}
+)
+
+func newFakeConnDialer(status peer.ConnectionStatus) *fakeConnDialer {
+	return &fakeConnDialer{status: status, retained: map[string]int{}}
+}
+
+func (d *fakeConnDialer) RetainPeer(id peer.Identifier, _ peer.Subscriber) (peer.Peer, error) {
+	d.retained[id.Identifier()]++
+	return &fakeConn{id: id.Identifier(), dialer: d}, nil
+}
+
+func (d *fakeConnDialer) ReleasePeer(id peer.Identifier, _ peer.Subscriber) error {
+	d.retained[id.Identifier()]--
+	return nil
+}
+
+func (c *fakeConn) Identifier() string  { return c.id }
+func (c *fakeConn) Status() peer.Status { return peer.Status{ConnectionStatus: c.dialer.status} }
+func (c *fakeConn) StartRequest()       {}
+func (c *fakeConn) EndRequest()         {}
+
+func newTestPooledChooser(dialers ...peer.Transport) (*pooledChooser, *clock.EventTimeSource) {
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	return &pooledChooser{
+		status:      common.DaemonStatusInitialized,
+		dialers:     dialers,
+		idleTimeout: time.Minute,
+		timeSource:  timeSource,
+		peers:       make(map[string]*pooledPeer),
+		shutdownC:   make(chan struct{}),
+	}, timeSource
+}
+
+func TestPooledChooser_SpreadsCallsOverAvailableConnections(t *testing.T) {
+	dialerA := newFakeConnDialer(peer.Available)
+	dialerB := newFakeConnDialer(peer.Available)
+	dialerC := newFakeConnDialer(peer.Unavailable)
+	chooser, _ := newTestPooledChooser(dialerA, dialerB, dialerC)
+
+	_, _, err := chooser.Choose(context.Background(), &transport.Request{})
+	assert.Error(t, err)
+
+	chosen := map[*fakeConnDialer]int{}
+	for i := 0; i < 6; i++ {
+		p, onFinish, err := chooser.Choose(context.Background(), &transport.Request{ShardKey: "host-1:7833"})
+		require.NoError(t, err)
+		onFinish(nil)
+		assert.Equal(t, "host-1:7833", p.Identifier())
+		chosen[p.(*fakeConn).dialer]++
+	}
+	assert.Equal(t, map[*fakeConnDialer]int{dialerA: 3, dialerB: 3}, chosen)
+
+	// the connections are retained once and kept between the calls
+	assert.Equal(t, 1, dialerA.retained["host-1:7833"])
+	assert.Equal(t, 1, dialerB.retained["host-1:7833"])
+	assert.Equal(t, 1, dialerC.retained["host-1:7833"])
+}
+
+func TestPooledChooser_NoAvailableConnection(t *testing.T) {
+	dialer := newFakeConnDialer(peer.Connecting)
+	chooser, _ := newTestPooledChooser(dialer, dialer)
+
+	p, _, err := chooser.Choose(context.Background(), &transport.Request{ShardKey: "host-1:7833"})
+	require.NoError(t, err)
+	assert.Equal(t, "host-1:7833", p.Identifier())
+}
+
+func TestPooledChooser_ReleasesIdlePeers(t *testing.T) {
+	dialer := newFakeConnDialer(peer.Available)
+	chooser, timeSource := newTestPooledChooser(dialer)
+
+	_, _, err := chooser.Choose(context.Background(), &transport.Request{ShardKey: "host-1:7833"})
+	require.NoError(t, err)
+	timeSource.Update(timeSource.Now().Add(time.Second * 30))
+	_, _, err = chooser.Choose(context.Background(), &transport.Request{ShardKey: "host-2:7833"})
+	require.NoError(t, err)
+
+	timeSource.Update(timeSource.Now().Add(time.Second * 45))
+	chooser.releaseIdlePeers()
+	assert.Equal(t, 0, dialer.retained["host-1:7833"])
+	assert.Equal(t, 1, dialer.retained["host-2:7833"])
+
+	require.NoError(t, chooser.Start())
+	assert.True(t, chooser.IsRunning())
+	require.NoError(t, chooser.Stop())
+	assert.Equal(t, 0, dialer.retained["host-2:7833"])
+}
+
+func TestNewPooledChooser(t *testing.T) {
+	chooser := newPooledChooser(grpc.NewTransport(), nil, &GRPCPool{ConnectionsPerPeer: 3})
+	assert.Len(t, chooser.dialers, 3)
+	assert.Len(t, chooser.transports, 2)
+	assert.Equal(t, defaultIdleConnectionTimeout, chooser.idleTimeout)
+
+	chooser = newPooledChooser(grpc.NewTransport(), nil, &GRPCPool{})
+	assert.Len(t, chooser.dialers, 1)
+	assert.Empty(t, chooser.transports)
+}
@@ -0,0 +1,279 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package secret
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+)
+
+const (
+	referenceKeySeparator = "#"
+
+	vaultRequestTimeout = 10 * time.Second
+	// the leases are renewed when half of their duration has elapsed
+	minLeaseRenewInterval = time.Second
+)
+
+type (
+	// VaultProvider resolves the references to the secrets stored in Vault and renews their leases until it is stopped.
+	// A reference has the form <path>#<key>, where path is the API path of the secret, e.g. secret/data/cadence/sql
+	// for the version 2 of the key/value secrets engine or database/creds/cadence for dynamic database credentials.
+	// The secrets of a path are read once, so the username and password of dynamic credentials match.
+	VaultProvider struct {
+		status    int32
+		address   string
+		token     string
+		tokenFile string
+		client    *http.Client
+		logger    log.Logger
+		stopC     chan struct{}
+		stopWG    sync.WaitGroup
+
+		sync.Mutex
+		secrets map[string]map[string]interface{}
+		leases  map[string]time.Duration
+	}
+
+	vaultSecretResponse struct {
+		LeaseID       string                 `json:"lease_id"`
+		LeaseDuration int64                  `json:"lease_duration"`
+		Renewable     bool                   `json:"renewable"`
+		Data          map[string]interface{} `json:"data"`
+		Errors        []string               `json:"errors"`
+	}
+)
+
+var _ config.SecretProvider = (*VaultProvider)(nil)
+var _ common.Daemon = (*VaultProvider)(nil)
+
+// NewVaultProvider creates a provider of the secrets stored in Vault
+func NewVaultProvider(cfg *config.VaultSecrets, logger log.Logger) (*VaultProvider, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("vault address must be set")
+	}
+	client := http.DefaultClient
+	tlsConfig, err := cfg.TLS.ToTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client = &http.Client{Transport: transport}
+	}
+	return &VaultProvider{
+		status:    common.DaemonStatusInitialized,
+		address:   strings.TrimSuffix(cfg.Address, "/"),
+		token:     cfg.Token,
+		tokenFile: cfg.TokenFile,
+		client:    client,
+		logger:    logger,
+		stopC:     make(chan struct{}),
+		secrets:   make(map[string]map[string]interface{}),
+		leases:    make(map[string]time.Duration),
+	}, nil
+}
+
+// GetSecret returns the value of the key of the secret stored at the path of the reference
+func (p *VaultProvider) GetSecret(reference string) (string, error) {
+	separator := strings.LastIndex(reference, referenceKeySeparator)
+	if separator < 0 {
+		return "", fmt.Errorf("secret reference must have the form <path>#<key>")
+	}
+	path, key := strings.Trim(reference[:separator], "/"), reference[separator+1:]
+
+	p.Lock()
+	defer p.Unlock()
+
+	data, ok := p.secrets[path]
+	if !ok {
+		ctx, cancel := context.WithTimeout(context.Background(), vaultRequestTimeout)
+		defer cancel()
+		response, err := p.call(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return "", err
+		}
+		data = response.Data
+		// the version 2 of the key/value secrets engine nests the secret with its metadata
+		if nested, ok := data["data"].(map[string]interface{}); ok {
+			if _, ok := data["metadata"]; ok {
+				data = nested
+			}
+		}
+		p.secrets[path] = data
+		if response.LeaseID != "" {
+			if response.Renewable {
+				p.leases[response.LeaseID] = time.Duration(response.LeaseDuration) * time.Second
+			} else {
+				p.logger.Warn("secret lease is not renewable, it will expire", tag.Key(path), tag.Dynamic("lease-duration", response.LeaseDuration))
+			}
+		}
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("secret %v has no key %v", path, key)
+	}
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
+
+// Start starts renewing the leases of the secrets
+func (p *VaultProvider) Start() {
+	if !atomic.CompareAndSwapInt32(&p.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+	p.stopWG.Add(1)
+	go p.renewLoop()
+}
+
+// Stop stops renewing the leases of the secrets
+func (p *VaultProvider) Stop() {
+	if !atomic.CompareAndSwapInt32(&p.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+	close(p.stopC)
+	p.stopWG.Wait()
+}
+
+func (p *VaultProvider) renewLoop() {
+	defer p.stopWG.Done()
+
+	timer := time.NewTimer(p.renewInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-p.stopC:
+			return
+		case <-timer.C:
+			p.renewLeases()
+			timer.Reset(p.renewInterval())
+		}
+	}
+}
+
+func (p *VaultProvider) renewLeases() {
+	p.Lock()
+	defer p.Unlock()
+
+	for leaseID, duration := range p.leases {
+		ctx, cancel := context.WithTimeout(context.Background(), vaultRequestTimeout)
+		response, err := p.call(ctx, http.MethodPut, "sys/leases/renew", map[string]interface{}{
+			"lease_id":  leaseID,
+			"increment": int64(duration / time.Second),
+		})
+		cancel()
+		if err != nil {
+			// the renewal is retried until the lease expires
+			p.logger.Error("failed to renew secret lease", tag.Dynamic("lease-id", leaseID), tag.Error(err))
+			continue
+		}
+		p.leases[leaseID] = time.Duration(response.LeaseDuration) * time.Second
+	}
+}
+
+// renewInterval returns half of the shortest lease duration
+func (p *VaultProvider) renewInterval() time.Duration {
+	p.Lock()
+	defer p.Unlock()
+
+	interval := time.Duration(0)
+	for _, duration := range p.leases {
+		if interval == 0 || duration/2 < interval {
+			interval = duration / 2
+		}
+	}
+	switch {
+	case len(p.leases) == 0:
+		// there is nothing to renew, the leases can only be added before the provider is started
+		return time.Hour
+	case interval < minLeaseRenewInterval:
+		return minLeaseRenewInterval
+	default:
+		return interval
+	}
+}
+
+func (p *VaultProvider) call(ctx context.Context, method string, path string, body map[string]interface{}) (*vaultSecretResponse, error) {
+	token := p.token
+	if p.tokenFile != "" {
+		// the token file is read on every call, so that the token can be renewed by an agent
+		content, err := ioutil.ReadFile(p.tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(content))
+	}
+
+	var payload io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		payload = bytes.NewReader(encoded)
+	}
+	url := fmt.Sprintf("%v/v1/%v", p.address, path)
+	request, err := http.NewRequestWithContext(ctx, method, url, payload)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Vault-Token", token)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResponse, err := p.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	var response vaultSecretResponse
+	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("vault %v: %v", httpResponse.Status, err)
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault %v: %v", httpResponse.Status, strings.Join(response.Errors, ", "))
+	}
+	return &response, nil
+}
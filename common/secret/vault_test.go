@@ -0,0 +1,113 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package secret
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log/loggerimpl"
+)
+
+func TestVaultProvider(t *testing.T) {
+	var credsReads, renewals int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/cadence/es":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"data":     map[string]interface{}{"password": "es-password"},
+					"metadata": map[string]interface{}{"version": 1},
+				},
+			})
+		case "/v1/database/creds/cadence":
+			atomic.AddInt32(&credsReads, 1)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_id":       "database/creds/cadence/lease",
+				"lease_duration": 2,
+				"renewable":      true,
+				"data":           map[string]interface{}{"username": "user", "password": "password"},
+			})
+		case "/v1/sys/leases/renew":
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "database/creds/cadence/lease", body["lease_id"])
+			atomic.AddInt32(&renewals, 1)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_id":       "database/creds/cadence/lease",
+				"lease_duration": 2,
+				"renewable":      true,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{}})
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultProvider(&config.VaultSecrets{Address: server.URL, Token: "token"}, loggerimpl.NewNopLogger())
+	require.NoError(t, err)
+
+	password, err := provider.GetSecret("secret/data/cadence/es#password")
+	require.NoError(t, err)
+	assert.Equal(t, "es-password", password)
+
+	username, err := provider.GetSecret("database/creds/cadence#username")
+	require.NoError(t, err)
+	assert.Equal(t, "user", username)
+	password, err = provider.GetSecret("database/creds/cadence#password")
+	require.NoError(t, err)
+	assert.Equal(t, "password", password)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&credsReads), "the secrets of a path are read once")
+
+	_, err = provider.GetSecret("secret/data/cadence/es#username")
+	assert.EqualError(t, err, "secret secret/data/cadence/es has no key username")
+	_, err = provider.GetSecret("secret/data/cadence/es")
+	assert.Error(t, err)
+	_, err = provider.GetSecret("secret/data/unknown#password")
+	assert.Error(t, err)
+
+	provider.Start()
+	defer provider.Stop()
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&renewals) > 0
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func TestNewVaultProvider_MissingAddress(t *testing.T) {
+	_, err := NewVaultProvider(&config.VaultSecrets{}, loggerimpl.NewNopLogger())
+	assert.Error(t, err)
+}
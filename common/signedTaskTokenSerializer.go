@@ -0,0 +1,129 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// TaskTokenSigningKeys are the keys signing the task tokens
+	TaskTokenSigningKeys struct {
+		// Keys are the HMAC keys by ID, the previous keys are kept after a rotation to verify the tokens they signed
+		Keys map[string][]byte
+		// ActiveKeyID is the ID of the key signing the new tokens
+		ActiveKeyID string
+		// TTL is the validity of the new tokens, they don't expire if it is 0
+		TTL time.Duration
+		// RequireSignature rejects the tokens which are not signed, e.g. the tokens issued before the signing was enabled
+		RequireSignature bool
+	}
+
+	signedTaskTokenSerializer struct {
+		TaskTokenSerializer
+		keys *TaskTokenSigningKeys
+		now  func() time.Time
+	}
+)
+
+var (
+	errTaskTokenNotSigned        = &types.BadRequestError{Message: "Task token is not signed."}
+	errTaskTokenInvalidSignature = &types.BadRequestError{Message: "Task token signature is invalid."}
+	errTaskTokenExpired          = &types.BadRequestError{Message: "Task token is expired."}
+)
+
+// NewSignedTaskTokenSerializer creates a TaskTokenSerializer signing the task tokens with HMAC-SHA256,
+// the signature and the expiry of the tokens are verified when they are deserialized.
+// The signed tokens are still JSON serialized, so they can be deserialized without being verified.
+func NewSignedTaskTokenSerializer(keys *TaskTokenSigningKeys, now func() time.Time) (TaskTokenSerializer, error) {
+	if _, ok := keys.Keys[keys.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("active task token signing key %v is not found", keys.ActiveKeyID)
+	}
+	return &signedTaskTokenSerializer{
+		TaskTokenSerializer: NewJSONTaskTokenSerializer(),
+		keys:                keys,
+		now:                 now,
+	}, nil
+}
+
+func (s *signedTaskTokenSerializer) Serialize(token *TaskToken) ([]byte, error) {
+	signed := *token
+	signed.KeyID = s.keys.ActiveKeyID
+	signed.ExpiryTime = 0
+	if s.keys.TTL > 0 {
+		signed.ExpiryTime = s.now().Add(s.keys.TTL).UnixNano()
+	}
+	signature, err := s.sign(&signed)
+	if err != nil {
+		return nil, err
+	}
+	signed.Signature = signature
+	return s.TaskTokenSerializer.Serialize(&signed)
+}
+
+func (s *signedTaskTokenSerializer) Deserialize(data []byte) (*TaskToken, error) {
+	token, err := s.TaskTokenSerializer.Deserialize(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(token.Signature) == 0 {
+		if s.keys.RequireSignature {
+			return nil, errTaskTokenNotSigned
+		}
+		return token, nil
+	}
+	signature, err := s.sign(token)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(signature, token.Signature) {
+		return nil, errTaskTokenInvalidSignature
+	}
+	if token.ExpiryTime > 0 && s.now().UnixNano() > token.ExpiryTime {
+		return nil, errTaskTokenExpired
+	}
+	return token, nil
+}
+
+// sign returns the signature of the token with its key, the signature of the token itself is not signed
+func (s *signedTaskTokenSerializer) sign(token *TaskToken) ([]byte, error) {
+	key, ok := s.keys.Keys[token.KeyID]
+	if !ok {
+		return nil, errTaskTokenInvalidSignature
+	}
+	unsigned := *token
+	unsigned.Signature = nil
+	payload, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return mac.Sum(nil), nil
+}
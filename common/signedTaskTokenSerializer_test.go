@@ -0,0 +1,112 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedTaskTokenSerializer(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+	keys := &TaskTokenSigningKeys{
+		Keys: map[string][]byte{
+			"old": []byte("01234567890123456789012345678901"),
+			"new": []byte("abcdefghijabcdefghijabcdefghijab"),
+		},
+		ActiveKeyID: "old",
+		TTL:         time.Hour,
+	}
+	token := &TaskToken{
+		DomainID:   "domain-id",
+		WorkflowID: "workflow-id",
+		RunID:      "run-id",
+		ScheduleID: 5,
+		ActivityID: "activity-id",
+	}
+
+	serializer, err := NewSignedTaskTokenSerializer(keys, clock)
+	require.NoError(t, err)
+	data, err := serializer.Serialize(token)
+	require.NoError(t, err)
+
+	deserialized, err := serializer.Deserialize(data)
+	require.NoError(t, err)
+	assert.Equal(t, token.WorkflowID, deserialized.WorkflowID)
+	assert.Equal(t, "old", deserialized.KeyID)
+	assert.Equal(t, now.Add(time.Hour).UnixNano(), deserialized.ExpiryTime)
+	assert.Empty(t, token.Signature, "the token to serialize is not modified")
+
+	// the signed tokens are still deserialized by the JSON serializer
+	unverified, err := NewJSONTaskTokenSerializer().Deserialize(data)
+	require.NoError(t, err)
+	assert.Equal(t, deserialized, unverified)
+
+	// the tokens signed by the previous key are accepted after a rotation
+	rotated, err := NewSignedTaskTokenSerializer(&TaskTokenSigningKeys{Keys: keys.Keys, ActiveKeyID: "new"}, clock)
+	require.NoError(t, err)
+	_, err = rotated.Deserialize(data)
+	assert.NoError(t, err)
+	rotatedData, err := rotated.Serialize(token)
+	require.NoError(t, err)
+	deserialized, err = serializer.Deserialize(rotatedData)
+	require.NoError(t, err)
+	assert.Equal(t, "new", deserialized.KeyID)
+	assert.Zero(t, deserialized.ExpiryTime)
+
+	// forged tokens are rejected
+	unverified.WorkflowID = "other-workflow-id"
+	forged, err := NewJSONTaskTokenSerializer().Serialize(unverified)
+	require.NoError(t, err)
+	_, err = serializer.Deserialize(forged)
+	assert.Equal(t, errTaskTokenInvalidSignature, err)
+
+	// tokens signed by removed keys are rejected
+	removed, err := NewSignedTaskTokenSerializer(&TaskTokenSigningKeys{Keys: map[string][]byte{"new": keys.Keys["new"]}, ActiveKeyID: "new"}, clock)
+	require.NoError(t, err)
+	_, err = removed.Deserialize(data)
+	assert.Equal(t, errTaskTokenInvalidSignature, err)
+
+	// expired tokens are rejected
+	now = now.Add(2 * time.Hour)
+	_, err = serializer.Deserialize(data)
+	assert.Equal(t, errTaskTokenExpired, err)
+
+	// unsigned tokens are only accepted if the signature is not required
+	unsigned, err := NewJSONTaskTokenSerializer().Serialize(token)
+	require.NoError(t, err)
+	_, err = serializer.Deserialize(unsigned)
+	assert.NoError(t, err)
+	keys.RequireSignature = true
+	_, err = serializer.Deserialize(unsigned)
+	assert.Equal(t, errTaskTokenNotSigned, err)
+}
+
+func TestNewSignedTaskTokenSerializer_MissingActiveKey(t *testing.T) {
+	_, err := NewSignedTaskTokenSerializer(&TaskTokenSigningKeys{ActiveKeyID: "missing"}, time.Now)
+	assert.Error(t, err)
+}
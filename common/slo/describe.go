@@ -0,0 +1,61 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package slo
+
+import (
+	"github.com/uber/cadence/common/types"
+)
+
+// Describe returns the report of the tracker as served by the admin API, limited to the given domain if it is set
+func Describe(tracker Tracker, domain string) *types.DescribeDomainSLOResponse {
+	if tracker == nil {
+		return &types.DescribeDomainSLOResponse{}
+	}
+
+	reports := tracker.Report(domain)
+	response := &types.DescribeDomainSLOResponse{
+		Domains: make([]*types.DomainSLO, 0, len(reports)),
+	}
+	for _, report := range reports {
+		response.Domains = append(response.Domains, &types.DomainSLO{
+			Domain:       report.Domain,
+			Availability: describeObjective(report.Availability),
+			Latency:      describeObjective(report.Latency),
+		})
+	}
+	return response
+}
+
+func describeObjective(report *ObjectiveReport) *types.SLOObjective {
+	if report == nil {
+		return nil
+	}
+	return &types.SLOObjective{
+		Target:          report.Target,
+		Requests:        report.Requests,
+		BadRequests:     report.BadRequests,
+		BudgetRemaining: report.BudgetRemaining,
+		ShortBurnRate:   report.ShortBurnRate,
+		LongBurnRate:    report.LongBurnRate,
+	}
+}
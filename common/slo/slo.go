@@ -0,0 +1,271 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package slo tracks the latency and availability objectives of the frontend requests of each domain,
+// and computes how fast the error budget of the objectives is burnt, so that the domains can be alerted on separately.
+package slo
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/metrics"
+)
+
+const (
+	// ShortWindow and LongWindow are the windows the burn rates are computed over, a high burn rate over both
+	// windows means the budget is being burnt fast and has been for long enough to be alerted on
+	ShortWindow = 5 * time.Minute
+	LongWindow  = time.Hour
+
+	// the requests are accounted in a ring buffer of slots, the slot of the oldest minute is reused for the current one
+	slotDuration = time.Minute
+	numSlots     = int(LongWindow / slotDuration)
+)
+
+// the long polls wait for tasks or events as long as the caller lets them, so they are not accounted in the latency SLO
+var longPollAPIs = map[string]struct{}{
+	"PollForDecisionTask":         {},
+	"PollForActivityTask":         {},
+	"GetWorkflowExecutionHistory": {},
+}
+
+type (
+	// Tracker accounts the requests of each domain against the SLOs configured for the domain
+	Tracker interface {
+		// Record accounts a request of the domain, failed is set if the request failed with a server error
+		Record(domain string, api string, latency time.Duration, failed bool)
+		// Report returns the state of the SLOs of the given domain, or of all the tracked domains if empty
+		Report(domain string) []DomainReport
+	}
+
+	// Config is the per domain configuration of the SLOs, targets outside of (0, 1) disable the SLO
+	Config struct {
+		AvailabilityTarget dynamicconfig.FloatPropertyFnWithDomainFilter
+		LatencyTarget      dynamicconfig.FloatPropertyFnWithDomainFilter
+		LatencyThreshold   dynamicconfig.DurationPropertyFnWithDomainFilter
+	}
+
+	// DomainReport is the state of the SLOs of a domain, objectives not configured are omitted
+	DomainReport struct {
+		Domain       string           `json:"domain"`
+		Availability *ObjectiveReport `json:"availability,omitempty"`
+		Latency      *ObjectiveReport `json:"latency,omitempty"`
+	}
+
+	// ObjectiveReport is the state of the error budget of an objective, requests are accounted over the long window
+	ObjectiveReport struct {
+		Target      float64 `json:"target"`
+		Requests    int64   `json:"requests"`
+		BadRequests int64   `json:"badRequests"`
+		// BudgetRemaining is the number of bad requests the objective can still tolerate over the long window,
+		// it is negative once the budget is exhausted
+		BudgetRemaining float64 `json:"budgetRemaining"`
+		// burn rates are the ratio of bad requests to the ratio allowed by the target, a burn rate of 1
+		// consumes exactly the budget over the window
+		ShortBurnRate float64 `json:"shortBurnRate"`
+		LongBurnRate  float64 `json:"longBurnRate"`
+	}
+
+	trackerImpl struct {
+		config        *Config
+		metricsClient metrics.Client
+		timeSource    clock.TimeSource
+
+		sync.RWMutex
+		domains map[string]*domainStats
+	}
+
+	domainStats struct {
+		sync.Mutex
+		slots [numSlots]slot
+	}
+
+	slot struct {
+		start           time.Time
+		requests        int64
+		failures        int64
+		latencyRequests int64
+		slowRequests    int64
+	}
+)
+
+var _ Tracker = (*trackerImpl)(nil)
+
+// NewTracker creates a tracker, only the domains with at least one SLO configured are tracked
+func NewTracker(config *Config, metricsClient metrics.Client, timeSource clock.TimeSource) Tracker {
+	return &trackerImpl{
+		config:        config,
+		metricsClient: metricsClient,
+		timeSource:    timeSource,
+		domains:       make(map[string]*domainStats),
+	}
+}
+
+func (t *trackerImpl) Record(domain string, api string, latency time.Duration, failed bool) {
+	availabilityTarget, latencyTarget := t.targets(domain)
+	if availabilityTarget == 0 && latencyTarget == 0 {
+		return
+	}
+
+	now := t.timeSource.Now()
+	stats := t.getOrCreateDomainStats(domain)
+	stats.Lock()
+	defer stats.Unlock()
+
+	start := now.Truncate(slotDuration)
+	s := &stats.slots[start.Unix()/int64(slotDuration/time.Second)%int64(numSlots)]
+	if !s.start.Equal(start) {
+		// the burn rates are emitted once a minute per domain, as the first request of the minute is recorded
+		t.emitMetrics(domain, stats.reportLocked(now, availabilityTarget, latencyTarget))
+		*s = slot{start: start}
+	}
+	s.requests++
+	if failed {
+		s.failures++
+	}
+	if _, ok := longPollAPIs[api]; !ok {
+		s.latencyRequests++
+		if latency > t.config.LatencyThreshold(domain) {
+			s.slowRequests++
+		}
+	}
+}
+
+func (t *trackerImpl) Report(domain string) []DomainReport {
+	now := t.timeSource.Now()
+
+	t.RLock()
+	domains := make(map[string]*domainStats, len(t.domains))
+	for name, stats := range t.domains {
+		if domain == "" || name == domain {
+			domains[name] = stats
+		}
+	}
+	t.RUnlock()
+
+	reports := make([]DomainReport, 0, len(domains))
+	for name, stats := range domains {
+		availabilityTarget, latencyTarget := t.targets(name)
+		stats.Lock()
+		report := stats.reportLocked(now, availabilityTarget, latencyTarget)
+		stats.Unlock()
+		report.Domain = name
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Domain < reports[j].Domain
+	})
+	return reports
+}
+
+func (t *trackerImpl) targets(domain string) (availabilityTarget float64, latencyTarget float64) {
+	return validTarget(t.config.AvailabilityTarget(domain)), validTarget(t.config.LatencyTarget(domain))
+}
+
+func (t *trackerImpl) getOrCreateDomainStats(domain string) *domainStats {
+	t.RLock()
+	stats, ok := t.domains[domain]
+	t.RUnlock()
+	if ok {
+		return stats
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	if stats, ok = t.domains[domain]; !ok {
+		stats = &domainStats{}
+		t.domains[domain] = stats
+	}
+	return stats
+}
+
+func (t *trackerImpl) emitMetrics(domain string, report DomainReport) {
+	scope := t.metricsClient.Scope(metrics.SLOScope, metrics.DomainTag(domain))
+	if report.Availability != nil {
+		scope.UpdateGauge(metrics.SLOAvailabilityShortBurnRateGauge, report.Availability.ShortBurnRate)
+		scope.UpdateGauge(metrics.SLOAvailabilityLongBurnRateGauge, report.Availability.LongBurnRate)
+	}
+	if report.Latency != nil {
+		scope.UpdateGauge(metrics.SLOLatencyShortBurnRateGauge, report.Latency.ShortBurnRate)
+		scope.UpdateGauge(metrics.SLOLatencyLongBurnRateGauge, report.Latency.LongBurnRate)
+	}
+}
+
+// reportLocked computes the report of the objectives with a valid target, the caller must hold the lock
+func (s *domainStats) reportLocked(now time.Time, availabilityTarget float64, latencyTarget float64) DomainReport {
+	var short, long slot
+	for i := range s.slots {
+		age := now.Sub(s.slots[i].start)
+		if age < 0 || age >= LongWindow {
+			continue
+		}
+		long.add(&s.slots[i])
+		if age < ShortWindow {
+			short.add(&s.slots[i])
+		}
+	}
+
+	var report DomainReport
+	if availabilityTarget != 0 {
+		report.Availability = newObjectiveReport(availabilityTarget, short.requests, short.failures, long.requests, long.failures)
+	}
+	if latencyTarget != 0 {
+		report.Latency = newObjectiveReport(latencyTarget, short.latencyRequests, short.slowRequests, long.latencyRequests, long.slowRequests)
+	}
+	return report
+}
+
+func (s *slot) add(other *slot) {
+	s.requests += other.requests
+	s.failures += other.failures
+	s.latencyRequests += other.latencyRequests
+	s.slowRequests += other.slowRequests
+}
+
+func newObjectiveReport(target float64, shortRequests, shortBad, longRequests, longBad int64) *ObjectiveReport {
+	return &ObjectiveReport{
+		Target:          target,
+		Requests:        longRequests,
+		BadRequests:     longBad,
+		BudgetRemaining: (1-target)*float64(longRequests) - float64(longBad),
+		ShortBurnRate:   burnRate(target, shortRequests, shortBad),
+		LongBurnRate:    burnRate(target, longRequests, longBad),
+	}
+}
+
+func burnRate(target float64, requests int64, bad int64) float64 {
+	if requests == 0 {
+		return 0
+	}
+	return float64(bad) / float64(requests) / (1 - target)
+}
+
+func validTarget(target float64) float64 {
+	if target <= 0 || target >= 1 {
+		return 0
+	}
+	return target
+}
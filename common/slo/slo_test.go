@@ -0,0 +1,143 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/metrics"
+)
+
+func newTestConfig() *Config {
+	return &Config{
+		AvailabilityTarget: func(domain string) float64 {
+			if domain == "latency-only" || domain == "untracked" {
+				return 0
+			}
+			return 0.9
+		},
+		LatencyTarget: func(domain string) float64 {
+			if domain == "untracked" {
+				return 0
+			}
+			return 0.5
+		},
+		LatencyThreshold: func(domain string) time.Duration {
+			return 100 * time.Millisecond
+		},
+	}
+}
+
+func TestTrackerReport(t *testing.T) {
+	timeSource := clock.NewEventTimeSource()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeSource.Update(start)
+	tracker := NewTracker(newTestConfig(), metrics.NewNoopMetricsClient(), timeSource)
+
+	// 20 requests an hour ago, 2 of which failed, and 10 requests now, 3 of which failed
+	for i := 0; i < 20; i++ {
+		tracker.Record("test-domain", "StartWorkflowExecution", time.Millisecond, i < 2)
+	}
+	timeSource.Update(start.Add(55 * time.Minute))
+	for i := 0; i < 10; i++ {
+		tracker.Record("test-domain", "SignalWorkflowExecution", time.Second, i < 3)
+	}
+	tracker.Record("test-domain", "PollForDecisionTask", time.Minute, false)
+	tracker.Record("latency-only", "StartWorkflowExecution", time.Millisecond, true)
+	tracker.Record("untracked", "StartWorkflowExecution", time.Millisecond, true)
+
+	reports := tracker.Report("")
+	require.Len(t, reports, 2, "domains without any SLO are not tracked")
+	assert.Equal(t, DomainReport{
+		Domain: "latency-only",
+		Latency: &ObjectiveReport{
+			Target:          0.5,
+			Requests:        1,
+			BudgetRemaining: 0.5,
+		},
+	}, reports[0])
+
+	report := reports[1]
+	assert.Equal(t, "test-domain", report.Domain)
+	assert.Equal(t, int64(31), report.Availability.Requests)
+	assert.Equal(t, int64(5), report.Availability.BadRequests)
+	assert.InDelta(t, 3.1-5, report.Availability.BudgetRemaining, 1e-9)
+	assert.InDelta(t, 3.0/11/0.1, report.Availability.ShortBurnRate, 1e-9)
+	assert.InDelta(t, 5.0/31/0.1, report.Availability.LongBurnRate, 1e-9)
+	assert.Equal(t, int64(30), report.Latency.Requests, "long polls are not accounted in the latency SLO")
+	assert.Equal(t, int64(10), report.Latency.BadRequests)
+	assert.InDelta(t, 1/0.5, report.Latency.ShortBurnRate, 1e-9)
+
+	// the requests fall out of the windows as time passes
+	timeSource.Update(start.Add(65 * time.Minute))
+	report = tracker.Report("test-domain")[0]
+	assert.Equal(t, int64(11), report.Availability.Requests)
+	assert.Zero(t, report.Availability.ShortBurnRate)
+	assert.InDelta(t, 3.0/11/0.1, report.Availability.LongBurnRate, 1e-9)
+}
+
+func TestTrackerEmitsBurnRates(t *testing.T) {
+	timeSource := clock.NewEventTimeSource()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeSource.Update(start)
+	testScope := tally.NewTestScope("test", nil)
+	tracker := NewTracker(newTestConfig(), metrics.NewClient(testScope, metrics.Frontend), timeSource)
+
+	tracker.Record("test-domain", "StartWorkflowExecution", time.Millisecond, true)
+	tracker.Record("test-domain", "StartWorkflowExecution", time.Millisecond, false)
+	timeSource.Update(start.Add(time.Minute))
+	tracker.Record("test-domain", "StartWorkflowExecution", time.Millisecond, false)
+
+	gauges := testScope.Snapshot().Gauges()
+	gauge, ok := gauges["test.slo_availability_burn_rate_short+domain=test-domain,operation=SLO"]
+	require.True(t, ok)
+	assert.InDelta(t, 0.5/0.1, gauge.Value(), 1e-9)
+	gauge, ok = gauges["test.slo_latency_burn_rate_long+domain=test-domain,operation=SLO"]
+	require.True(t, ok)
+	assert.Zero(t, gauge.Value())
+}
+
+func TestDescribe(t *testing.T) {
+	tracker := NewTracker(newTestConfig(), metrics.NewNoopMetricsClient(), clock.NewRealTimeSource())
+	tracker.Record("domain-a", "StartWorkflowExecution", time.Millisecond, false)
+	tracker.Record("latency-only", "StartWorkflowExecution", time.Millisecond, false)
+
+	response := Describe(tracker, "latency-only")
+	require.Len(t, response.Domains, 1)
+	assert.Equal(t, "latency-only", response.Domains[0].Domain)
+	assert.Nil(t, response.Domains[0].Availability)
+	assert.Equal(t, int64(1), response.Domains[0].Latency.Requests)
+
+	response = Describe(tracker, "")
+	require.Len(t, response.Domains, 2)
+	assert.Equal(t, "domain-a", response.Domains[0].Domain)
+	assert.Equal(t, int64(1), response.Domains[0].Availability.Requests)
+
+	assert.Empty(t, Describe(nil, "").Domains)
+}
@@ -0,0 +1,148 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package slowrequest breaks down the latency of inbound requests by the dependencies they call, so that
+// the requests exceeding the latency threshold of their API can be logged with where the time was spent.
+package slowrequest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/log/tag"
+)
+
+const (
+	// DependencyPersistence is the dependency name of the calls to the persistence layer
+	DependencyPersistence = "persistence"
+)
+
+type (
+	// Breakdown accumulates the time an inbound request spent calling each dependency.
+	// Calls made concurrently are all accounted, so the total of the dependencies can exceed the request latency.
+	Breakdown struct {
+		sync.Mutex
+		domain       string
+		workflowID   string
+		dependencies map[string]*dependencyStats
+	}
+
+	dependencyStats struct {
+		calls   int64
+		latency time.Duration
+	}
+
+	contextKey struct{}
+)
+
+// NewContext returns a child context carrying a new latency breakdown
+func NewContext(ctx context.Context) (context.Context, *Breakdown) {
+	breakdown := &Breakdown{
+		dependencies: make(map[string]*dependencyStats),
+	}
+	return context.WithValue(ctx, contextKey{}, breakdown), breakdown
+}
+
+// FromContext returns the latency breakdown of the context, or nil if the request is not tracked
+func FromContext(ctx context.Context) *Breakdown {
+	if ctx == nil {
+		return nil
+	}
+	breakdown, _ := ctx.Value(contextKey{}).(*Breakdown)
+	return breakdown
+}
+
+// RecordDependency adds a call to the dependency to the latency breakdown of the context, if any
+func RecordDependency(ctx context.Context, dependency string, latency time.Duration) {
+	breakdown := FromContext(ctx)
+	if breakdown == nil {
+		return
+	}
+
+	breakdown.Lock()
+	defer breakdown.Unlock()
+
+	stats, ok := breakdown.dependencies[dependency]
+	if !ok {
+		stats = &dependencyStats{}
+		breakdown.dependencies[dependency] = stats
+	}
+	stats.calls++
+	stats.latency += latency
+}
+
+// SetWorkflow annotates the latency breakdown of the context, if any, with the domain and workflow
+// the request is for, empty values are ignored
+func SetWorkflow(ctx context.Context, domain string, workflowID string) {
+	breakdown := FromContext(ctx)
+	if breakdown == nil {
+		return
+	}
+
+	breakdown.Lock()
+	defer breakdown.Unlock()
+
+	if domain != "" {
+		breakdown.domain = domain
+	}
+	if workflowID != "" {
+		breakdown.workflowID = workflowID
+	}
+}
+
+// Workflow returns the domain and workflow the request is for, empty values are unknown
+func (b *Breakdown) Workflow() (domain string, workflowID string) {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.domain, b.workflowID
+}
+
+// Tags returns the log tags of the breakdown, dependencies are sorted by name
+func (b *Breakdown) Tags() []tag.Tag {
+	b.Lock()
+	defer b.Unlock()
+
+	var tags []tag.Tag
+	if b.domain != "" {
+		tags = append(tags, tag.WorkflowDomainName(b.domain))
+	}
+	if b.workflowID != "" {
+		tags = append(tags, tag.WorkflowID(b.workflowID))
+	}
+
+	dependencies := make([]string, 0, len(b.dependencies))
+	for dependency := range b.dependencies {
+		dependencies = append(dependencies, dependency)
+	}
+	sort.Strings(dependencies)
+	for _, dependency := range dependencies {
+		stats := b.dependencies[dependency]
+		tags = append(tags,
+			tag.DependencyLatency(dependency, stats.latency),
+			tag.DependencyCalls(dependency, stats.calls),
+		)
+	}
+	return tags
+}
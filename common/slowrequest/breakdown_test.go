@@ -0,0 +1,58 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package slowrequest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/log/tag"
+)
+
+func TestBreakdown(t *testing.T) {
+	// requests which are not tracked are ignored
+	RecordDependency(context.Background(), DependencyPersistence, time.Second)
+	SetWorkflow(context.Background(), "domain", "workflow")
+	assert.Nil(t, FromContext(context.Background()))
+
+	ctx, breakdown := NewContext(context.Background())
+	assert.Equal(t, breakdown, FromContext(ctx))
+
+	SetWorkflow(ctx, "domain", "")
+	SetWorkflow(ctx, "", "workflow")
+	RecordDependency(ctx, "matching", time.Millisecond)
+	RecordDependency(ctx, DependencyPersistence, time.Millisecond)
+	RecordDependency(ctx, DependencyPersistence, 2*time.Millisecond)
+
+	assert.Equal(t, []tag.Tag{
+		tag.WorkflowDomainName("domain"),
+		tag.WorkflowID("workflow"),
+		tag.DependencyLatency("matching", time.Millisecond),
+		tag.DependencyCalls("matching", 1),
+		tag.DependencyLatency(DependencyPersistence, 3*time.Millisecond),
+		tag.DependencyCalls(DependencyPersistence, 2),
+	}, breakdown.Tags())
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package task
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+)
+
+type (
+	// WeightedFairTaskSchedulerOptions configs weighted fair task scheduler
+	WeightedFairTaskSchedulerOptions struct {
+		// QueueSize is the number of tasks which can be buffered for each key
+		QueueSize   int
+		WorkerCount dynamicconfig.IntPropertyFn
+		RetryPolicy backoff.RetryPolicy
+		// KeyFn returns the key a task is scheduled fairly by, e.g. its shard and domain
+		KeyFn func(task PriorityTask) interface{}
+		// WeightFn returns the number of tasks of a key which are dispatched in a row
+		WeightFn func(key interface{}) int
+	}
+
+	weightedFairTaskSchedulerImpl struct {
+		sync.Mutex
+
+		status       int32
+		queues       map[interface{}]*weightedFairTaskQueue
+		keys         []interface{} // keys with buffered tasks, in round robin order
+		nextKey      int
+		taskCond     *sync.Cond // signaled when a task is buffered
+		spaceCond    *sync.Cond // signaled when a task is dispatched
+		dispatcherWG sync.WaitGroup
+		logger       log.Logger
+		metricsScope metrics.Scope
+		options      *WeightedFairTaskSchedulerOptions
+
+		processor Processor
+	}
+
+	weightedFairTaskQueue struct {
+		tasks  []PriorityTask
+		credit int
+	}
+)
+
+// NewWeightedFairTaskScheduler creates a new scheduler which buffers the tasks by key, and dispatches them
+// round robin across keys, so that the tasks of a key cannot hold all the workers while the others wait
+func NewWeightedFairTaskScheduler(
+	logger log.Logger,
+	metricsClient metrics.Client,
+	options *WeightedFairTaskSchedulerOptions,
+) Scheduler {
+	scheduler := &weightedFairTaskSchedulerImpl{
+		status:       common.DaemonStatusInitialized,
+		queues:       make(map[interface{}]*weightedFairTaskQueue),
+		logger:       logger,
+		metricsScope: metricsClient.Scope(metrics.TaskSchedulerScope),
+		options:      options,
+		processor: NewParallelTaskProcessor(
+			logger,
+			metricsClient,
+			&ParallelTaskProcessorOptions{
+				QueueSize:   wRRTaskProcessorQueueSize,
+				WorkerCount: options.WorkerCount,
+				RetryPolicy: options.RetryPolicy,
+			},
+		),
+	}
+	scheduler.taskCond = sync.NewCond(scheduler)
+	scheduler.spaceCond = sync.NewCond(scheduler)
+	return scheduler
+}
+
+func (w *weightedFairTaskSchedulerImpl) Start() {
+	if !atomic.CompareAndSwapInt32(&w.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	w.processor.Start()
+
+	w.dispatcherWG.Add(1)
+	go w.dispatcher()
+
+	w.logger.Info("Weighted fair task scheduler started.")
+}
+
+func (w *weightedFairTaskSchedulerImpl) Stop() {
+	if !atomic.CompareAndSwapInt32(&w.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+
+	w.Lock()
+	var tasks []PriorityTask
+	for _, queue := range w.queues {
+		tasks = append(tasks, queue.tasks...)
+	}
+	w.queues = make(map[interface{}]*weightedFairTaskQueue)
+	w.keys = nil
+	w.taskCond.Broadcast()
+	w.spaceCond.Broadcast()
+	w.Unlock()
+
+	for _, task := range tasks {
+		task.Nack()
+	}
+
+	w.processor.Stop()
+
+	if success := common.AwaitWaitGroup(&w.dispatcherWG, time.Minute); !success {
+		w.logger.Warn("Weighted fair task scheduler timedout on shutdown.")
+	}
+
+	w.logger.Info("Weighted fair task scheduler shutdown.")
+}
+
+func (w *weightedFairTaskSchedulerImpl) Submit(task PriorityTask) error {
+	w.metricsScope.IncCounter(metrics.PriorityTaskSubmitRequest)
+	sw := w.metricsScope.StartTimer(metrics.PriorityTaskSubmitLatency)
+	defer sw.Stop()
+
+	key := w.options.KeyFn(task)
+
+	w.Lock()
+	defer w.Unlock()
+
+	for !w.isStopped() && w.isFull(key) {
+		w.spaceCond.Wait()
+	}
+	if w.isStopped() {
+		return ErrTaskSchedulerClosed
+	}
+	w.enqueue(key, task)
+	return nil
+}
+
+func (w *weightedFairTaskSchedulerImpl) TrySubmit(
+	task PriorityTask,
+) (bool, error) {
+	key := w.options.KeyFn(task)
+
+	w.Lock()
+	defer w.Unlock()
+
+	if w.isStopped() {
+		return false, ErrTaskSchedulerClosed
+	}
+	if w.isFull(key) {
+		return false, nil
+	}
+	w.metricsScope.IncCounter(metrics.PriorityTaskSubmitRequest)
+	w.enqueue(key, task)
+	return true, nil
+}
+
+func (w *weightedFairTaskSchedulerImpl) dispatcher() {
+	defer w.dispatcherWG.Done()
+
+	for {
+		task, ok := w.dequeue()
+		if !ok {
+			return
+		}
+
+		if err := w.processor.Submit(task); err != nil {
+			w.logger.Error("fail to submit task to processor", tag.Error(err))
+			task.Nack()
+		}
+	}
+}
+
+// enqueue buffers the task, the lock must be held
+func (w *weightedFairTaskSchedulerImpl) enqueue(key interface{}, task PriorityTask) {
+	queue, ok := w.queues[key]
+	if !ok {
+		queue = &weightedFairTaskQueue{}
+		w.queues[key] = queue
+		w.keys = append(w.keys, key)
+	}
+	queue.tasks = append(queue.tasks, task)
+	w.taskCond.Signal()
+}
+
+// dequeue blocks until a task is buffered and returns the next one in the round robin order,
+// or returns false if the scheduler is stopped
+func (w *weightedFairTaskSchedulerImpl) dequeue() (PriorityTask, bool) {
+	w.Lock()
+	defer w.Unlock()
+
+	for !w.isStopped() && len(w.keys) == 0 {
+		w.taskCond.Wait()
+	}
+	if w.isStopped() {
+		return nil, false
+	}
+
+	if w.nextKey >= len(w.keys) {
+		w.nextKey = 0
+	}
+	key := w.keys[w.nextKey]
+	queue := w.queues[key]
+	if queue.credit <= 0 {
+		// the weight is read whenever the key gets its turn, so that changes are picked up right away
+		queue.credit = common.MaxInt(w.options.WeightFn(key), 1)
+	}
+
+	task := queue.tasks[0]
+	queue.tasks[0] = nil
+	queue.tasks = queue.tasks[1:]
+	queue.credit--
+
+	if len(queue.tasks) == 0 {
+		// the following key moves to the current index
+		delete(w.queues, key)
+		w.keys = append(w.keys[:w.nextKey], w.keys[w.nextKey+1:]...)
+	} else if queue.credit == 0 {
+		w.nextKey++
+	}
+	w.spaceCond.Broadcast()
+	return task, true
+}
+
+// isFull returns whether the buffer of the key is full, the lock must be held
+func (w *weightedFairTaskSchedulerImpl) isFull(key interface{}) bool {
+	queue, ok := w.queues[key]
+	return ok && len(queue.tasks) >= w.options.QueueSize
+}
+
+func (w *weightedFairTaskSchedulerImpl) isStopped() bool {
+	return atomic.LoadInt32(&w.status) == common.DaemonStatusStopped
+}
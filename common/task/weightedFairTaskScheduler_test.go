@@ -0,0 +1,184 @@
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+)
+
+type (
+	weightedFairTaskSchedulerSuite struct {
+		*require.Assertions
+		suite.Suite
+
+		controller    *gomock.Controller
+		mockProcessor *MockProcessor
+
+		scheduler *weightedFairTaskSchedulerImpl
+	}
+)
+
+func TestWeightedFairTaskSchedulerSuite(t *testing.T) {
+	s := new(weightedFairTaskSchedulerSuite)
+	suite.Run(t, s)
+}
+
+func (s *weightedFairTaskSchedulerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.controller = gomock.NewController(s.T())
+	s.mockProcessor = NewMockProcessor(s.controller)
+
+	// the key of a task is its priority, and the weight of a key is its value plus one
+	s.scheduler = s.newTestWeightedFairTaskScheduler(2)
+}
+
+func (s *weightedFairTaskSchedulerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *weightedFairTaskSchedulerSuite) TestTrySubmit_QueueFull() {
+	for i := 0; i != 2; i++ {
+		submitted, err := s.scheduler.TrySubmit(s.newMockPriorityTask(0))
+		s.NoError(err)
+		s.True(submitted)
+	}
+
+	submitted, err := s.scheduler.TrySubmit(s.newMockPriorityTask(0))
+	s.NoError(err)
+	s.False(submitted)
+
+	// other keys are not affected
+	submitted, err = s.scheduler.TrySubmit(s.newMockPriorityTask(1))
+	s.NoError(err)
+	s.True(submitted)
+}
+
+func (s *weightedFairTaskSchedulerSuite) TestDispatch_WeightedRoundRobin() {
+	s.scheduler = s.newTestWeightedFairTaskScheduler(10)
+	s.scheduler.processor = s.mockProcessor
+
+	var tasks []*MockPriorityTask
+	for i := 0; i != 6; i++ {
+		tasks = append(tasks, s.newMockPriorityTask(0))
+	}
+	for i := 0; i != 3; i++ {
+		tasks = append(tasks, s.newMockPriorityTask(1))
+	}
+	for _, task := range tasks {
+		s.NoError(s.scheduler.Submit(task))
+	}
+
+	// key 1 has twice the weight of key 0, and the tasks are FIFO within a key
+	expectedOrder := []*MockPriorityTask{
+		tasks[0], tasks[6], tasks[7], tasks[1], tasks[8], tasks[2], tasks[3], tasks[4], tasks[5],
+	}
+	for _, expected := range expectedOrder {
+		task, ok := s.scheduler.dequeue()
+		s.True(ok)
+		s.Equal(expected, task)
+	}
+	s.Empty(s.scheduler.keys)
+	s.Empty(s.scheduler.queues)
+}
+
+func (s *weightedFairTaskSchedulerSuite) TestDispatch_ProcessTasks() {
+	s.scheduler.processor = s.mockProcessor
+
+	done := make(chan struct{})
+	mockTask := s.newMockPriorityTask(0)
+	s.mockProcessor.EXPECT().Start()
+	s.mockProcessor.EXPECT().Submit(newMockPriorityTaskMatcher(mockTask)).DoAndReturn(func(_ Task) error {
+		close(done)
+		return nil
+	})
+	s.mockProcessor.EXPECT().Stop()
+
+	s.scheduler.Start()
+	s.NoError(s.scheduler.Submit(mockTask))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("task is not dispatched")
+	}
+	s.scheduler.Stop()
+}
+
+func (s *weightedFairTaskSchedulerSuite) TestStop_NackBufferedTasks() {
+	s.scheduler.processor = s.mockProcessor
+	s.mockProcessor.EXPECT().Start()
+	s.mockProcessor.EXPECT().Stop()
+
+	// not started, so that the task stays buffered
+	mockTask := s.newMockPriorityTask(0)
+	s.NoError(s.scheduler.Submit(mockTask))
+	mockTask.EXPECT().Nack().Times(1)
+
+	s.scheduler.Start()
+	s.scheduler.Stop()
+
+	s.Equal(ErrTaskSchedulerClosed, s.scheduler.Submit(s.newMockPriorityTask(0)))
+	_, err := s.scheduler.TrySubmit(s.newMockPriorityTask(0))
+	s.Equal(ErrTaskSchedulerClosed, err)
+}
+
+func (s *weightedFairTaskSchedulerSuite) TestSchedulerContract() {
+	testSchedulerContract(s.Assertions, s.controller, s.newTestWeightedFairTaskScheduler(1000))
+}
+
+func (s *weightedFairTaskSchedulerSuite) newMockPriorityTask(
+	priority int,
+) *MockPriorityTask {
+	mockTask := NewMockPriorityTask(s.controller)
+	mockTask.EXPECT().Priority().Return(priority).AnyTimes()
+	return mockTask
+}
+
+func (s *weightedFairTaskSchedulerSuite) newTestWeightedFairTaskScheduler(
+	queueSize int,
+) *weightedFairTaskSchedulerImpl {
+	return NewWeightedFairTaskScheduler(
+		loggerimpl.NewLoggerForTest(s.Suite),
+		metrics.NewClient(tally.NoopScope, metrics.Common),
+		&WeightedFairTaskSchedulerOptions{
+			QueueSize:   queueSize,
+			WorkerCount: dynamicconfig.GetIntPropertyFn(1),
+			RetryPolicy: backoff.NewExponentialRetryPolicy(time.Millisecond),
+			KeyFn: func(task PriorityTask) interface{} {
+				return task.Priority()
+			},
+			WeightFn: func(key interface{}) int {
+				return key.(int) + 1
+			},
+		},
+	).(*weightedFairTaskSchedulerImpl)
+}
@@ -361,6 +361,8 @@ func testSchedulerContract(
 		<-schedulerImpl.shutdownCh
 	case *weightedRoundRobinTaskSchedulerImpl:
 		<-schedulerImpl.shutdownCh
+	case *weightedFairTaskSchedulerImpl:
+		schedulerImpl.dispatcherWG.Wait()
 	default:
 		s.Fail("unknown task scheduler type")
 	}
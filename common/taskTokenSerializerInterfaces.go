@@ -39,6 +39,13 @@ type (
 		ScheduleAttempt int64  `json:"scheduleAttempt"`
 		ActivityID      string `json:"activityId"`
 		ActivityType    string `json:"activityType"`
+		// TaskList is the name of the task list partition an activity task was dispatched from, it is only set
+		// when the partition limits the activities running at once and has to be told when the activity finishes
+		TaskList string `json:"taskList,omitempty"`
+		// KeyID, ExpiryTime and Signature are only set when the task tokens are signed
+		KeyID      string `json:"keyId,omitempty"`
+		ExpiryTime int64  `json:"expiryTime,omitempty"`
+		Signature  []byte `json:"signature,omitempty"`
 	}
 
 	// QueryTaskToken identifies a query task
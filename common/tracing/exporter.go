@@ -0,0 +1,362 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+)
+
+const (
+	otlpTracesPath = "/v1/traces"
+
+	defaultBatchSize     = 512
+	defaultFlushInterval = 5 * time.Second
+	exportTimeout        = 10 * time.Second
+	// max number of finished spans waiting to be exported, further spans are dropped
+	spanQueueSize = 8192
+
+	// span kinds and status codes of the OTLP protocol
+	spanKindInternal  = 1
+	spanKindServer    = 2
+	spanKindClient    = 3
+	spanKindProducer  = 4
+	spanKindConsumer  = 5
+	statusCodeUnset   = 0
+	statusCodeError   = 2
+	instrumentationID = "github.com/uber/cadence"
+)
+
+type (
+	// otlpExporter batches the finished spans and sends them to an OTLP http receiver in the json encoding
+	otlpExporter struct {
+		client        *http.Client
+		url           string
+		headers       map[string]string
+		serviceName   string
+		batchSize     int
+		flushInterval time.Duration
+		logger        log.Logger
+
+		spansC       chan *span
+		doneC        chan struct{}
+		droppedSpans int64
+	}
+
+	otlpExportRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId,omitempty"`
+		Name              string          `json:"name"`
+		Kind              int             `json:"kind"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		Events            []otlpEvent     `json:"events,omitempty"`
+		Status            otlpStatus      `json:"status"`
+	}
+
+	otlpEvent struct {
+		TimeUnixNano string          `json:"timeUnixNano"`
+		Name         string          `json:"name"`
+		Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	}
+
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+
+	otlpAttribute struct {
+		Key   string             `json:"key"`
+		Value otlpAttributeValue `json:"value"`
+	}
+
+	otlpAttributeValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+var _ spanExporter = (*otlpExporter)(nil)
+
+func newOTLPExporter(cfg *config.Tracing, serviceName string, logger log.Logger, doneC chan struct{}) (*otlpExporter, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("endpoint must be set to export traces")
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("trace sample rate must be between 0 and 1, got %v", cfg.SampleRate)
+	}
+	tlsConfig, err := cfg.TLS.ToTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: exportTimeout}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
+	}
+
+	e := &otlpExporter{
+		client:        client,
+		url:           strings.TrimSuffix(cfg.Endpoint, "/") + otlpTracesPath,
+		headers:       cfg.Headers,
+		serviceName:   serviceName,
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		logger:        logger,
+		spansC:        make(chan *span, spanQueueSize),
+		doneC:         doneC,
+	}
+	if e.batchSize <= 0 {
+		e.batchSize = defaultBatchSize
+	}
+	if e.flushInterval <= 0 {
+		e.flushInterval = defaultFlushInterval
+	}
+	go e.run()
+	return e, nil
+}
+
+func (e *otlpExporter) export(s *span) {
+	select {
+	case e.spansC <- s:
+	default:
+		atomic.AddInt64(&e.droppedSpans, 1)
+	}
+}
+
+func (e *otlpExporter) run() {
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*span, 0, e.batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			e.send(batch)
+			batch = make([]*span, 0, e.batchSize)
+		}
+		if dropped := atomic.SwapInt64(&e.droppedSpans, 0); dropped > 0 {
+			e.logger.Warn(fmt.Sprintf("Dropped %v spans as the export queue was full", dropped))
+		}
+	}
+	for {
+		select {
+		case s := <-e.spansC:
+			batch = append(batch, s)
+			if len(batch) >= e.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.doneC:
+			for {
+				select {
+				case s := <-e.spansC:
+					batch = append(batch, s)
+					if len(batch) >= e.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *otlpExporter) send(batch []*span) {
+	payload, err := json.Marshal(e.newExportRequest(batch))
+	if err != nil {
+		e.logger.Error("Failed to encode spans", tag.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(payload))
+	if err != nil {
+		e.logger.Error("Failed to create span export request", tag.Error(err))
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		e.logger.Warn("Failed to export spans", tag.Error(err), tag.Counter(len(batch)))
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		e.logger.Warn("Failed to export spans", tag.Error(fmt.Errorf("status %v", resp.Status)), tag.Counter(len(batch)))
+	}
+}
+
+func (e *otlpExporter) newExportRequest(batch []*span) *otlpExportRequest {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, s := range batch {
+		spans = append(spans, toOTLPSpan(s))
+	}
+	return &otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpAttribute{newOTLPAttribute("service.name", e.serviceName)},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: instrumentationID},
+				Spans: spans,
+			}},
+		}},
+	}
+}
+
+func toOTLPSpan(s *span) otlpSpan {
+	s.Lock()
+	defer s.Unlock()
+
+	result := otlpSpan{
+		TraceID:           hex.EncodeToString(s.context.traceID[:]),
+		SpanID:            hex.EncodeToString(s.context.spanID[:]),
+		Name:              s.operationName,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Status:            otlpStatus{Code: statusCodeUnset},
+	}
+	if s.parentID != (spanID{}) {
+		result.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+
+	for k, v := range s.tags {
+		switch k {
+		case string(ext.SpanKind):
+			result.Kind = toOTLPSpanKind(v)
+		case string(ext.Error):
+			if isError, ok := v.(bool); ok && isError {
+				result.Status.Code = statusCodeError
+			}
+		}
+		result.Attributes = append(result.Attributes, newOTLPAttribute(k, v))
+	}
+
+	for _, record := range s.logs {
+		event := otlpEvent{
+			TimeUnixNano: strconv.FormatInt(record.Timestamp.UnixNano(), 10),
+			Name:         "log",
+		}
+		for _, field := range record.Fields {
+			if field.Key() == "event" {
+				event.Name = fmt.Sprint(field.Value())
+				continue
+			}
+			if err, ok := field.Value().(error); ok {
+				result.Status.Code = statusCodeError
+				result.Status.Message = err.Error()
+			}
+			event.Attributes = append(event.Attributes, newOTLPAttribute(field.Key(), field.Value()))
+		}
+		result.Events = append(result.Events, event)
+	}
+	return result
+}
+
+func toOTLPSpanKind(kind interface{}) int {
+	switch fmt.Sprint(kind) {
+	case string(ext.SpanKindRPCServerEnum):
+		return spanKindServer
+	case string(ext.SpanKindRPCClientEnum):
+		return spanKindClient
+	case string(ext.SpanKindProducerEnum):
+		return spanKindProducer
+	case string(ext.SpanKindConsumerEnum):
+		return spanKindConsumer
+	default:
+		return spanKindInternal
+	}
+}
+
+func newOTLPAttribute(key string, value interface{}) otlpAttribute {
+	attr := otlpAttribute{Key: key}
+	switch v := value.(type) {
+	case string:
+		attr.Value.StringValue = &v
+	case bool:
+		attr.Value.BoolValue = &v
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		i := fmt.Sprint(v)
+		attr.Value.IntValue = &i
+	case float32:
+		f := float64(v)
+		attr.Value.DoubleValue = &f
+	case float64:
+		attr.Value.DoubleValue = &v
+	default:
+		str := fmt.Sprint(v)
+		attr.Value.StringValue = &str
+	}
+	return attr
+}
@@ -0,0 +1,138 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tracing
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// W3C trace context headers, see https://www.w3.org/TR/trace-context/ and https://www.w3.org/TR/baggage/
+const (
+	traceParentHeader  = "traceparent"
+	baggageHeader      = "baggage"
+	traceParentVersion = "00"
+	sampledFlag        = 0x01
+)
+
+func injectTraceContext(sc spanContext, writer opentracing.TextMapWriter) {
+	flags := 0
+	if sc.sampled {
+		flags |= sampledFlag
+	}
+	writer.Set(traceParentHeader, fmt.Sprintf("%s-%x-%x-%02x", traceParentVersion, sc.traceID[:], sc.spanID[:], flags))
+
+	if len(sc.baggage) > 0 {
+		items := make([]string, 0, len(sc.baggage))
+		for k, v := range sc.baggage {
+			items = append(items, url.QueryEscape(k)+"="+url.QueryEscape(v))
+		}
+		writer.Set(baggageHeader, strings.Join(items, ","))
+	}
+}
+
+// extractTraceContext reads the trace context headers, header names are matched case insensitively
+// as some transports (ex: grpc metadata) lower case them
+func extractTraceContext(reader opentracing.TextMapReader) (opentracing.SpanContext, error) {
+	var traceParent, baggage string
+	err := reader.ForeachKey(func(key, val string) error {
+		switch strings.ToLower(key) {
+		case traceParentHeader:
+			traceParent = val
+		case baggageHeader:
+			baggage = val
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if traceParent == "" {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+
+	sc, err := parseTraceParent(traceParent)
+	if err != nil {
+		return nil, err
+	}
+	sc.baggage = parseBaggage(baggage)
+	return sc, nil
+}
+
+func parseTraceParent(value string) (spanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	// future versions may append fields, so only the version 00 fields are read
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == traceParentVersion && len(parts) != 4) {
+		return spanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	var sc spanContext
+	if len(parts[1]) != 2*len(sc.traceID) || len(parts[2]) != 2*len(sc.spanID) || len(parts[3]) != 2 {
+		return spanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+	if _, err := hex.Decode(sc.traceID[:], []byte(parts[1])); err != nil {
+		return spanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+	if _, err := hex.Decode(sc.spanID[:], []byte(parts[2])); err != nil {
+		return spanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return spanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+	if !sc.isValid() {
+		return spanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+	sc.sampled = flags[0]&sampledFlag != 0
+	return sc, nil
+}
+
+// parseBaggage reads the baggage items, invalid items are skipped
+func parseBaggage(value string) map[string]string {
+	if value == "" {
+		return nil
+	}
+	baggage := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		// properties of an item are not supported and dropped
+		item = strings.SplitN(item, ";", 2)[0]
+		kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k, err := url.QueryUnescape(strings.TrimSpace(kv[0]))
+		if err != nil || k == "" {
+			continue
+		}
+		v, err := url.QueryUnescape(strings.TrimSpace(kv[1]))
+		if err != nil {
+			continue
+		}
+		baggage[k] = v
+	}
+	return baggage
+}
@@ -0,0 +1,168 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tracing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+type (
+	traceID [16]byte
+	spanID  [8]byte
+
+	spanContext struct {
+		traceID traceID
+		spanID  spanID
+		sampled bool
+		// baggage is copied on write, so that it can be shared by the contexts of a trace
+		baggage map[string]string
+	}
+
+	span struct {
+		tracer *Tracer
+
+		sync.Mutex
+		context       spanContext
+		parentID      spanID
+		operationName string
+		start         time.Time
+		end           time.Time
+		tags          map[string]interface{}
+		logs          []opentracing.LogRecord
+		finished      bool
+	}
+)
+
+var _ opentracing.Span = (*span)(nil)
+var _ opentracing.SpanContext = spanContext{}
+
+func (c spanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for k, v := range c.baggage {
+		if !handler(k, v) {
+			return
+		}
+	}
+}
+
+func (c spanContext) isValid() bool {
+	return c.traceID != traceID{} && c.spanID != spanID{}
+}
+
+func (s *span) Finish() {
+	s.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+func (s *span) FinishWithOptions(opts opentracing.FinishOptions) {
+	s.Lock()
+	if s.finished {
+		s.Unlock()
+		return
+	}
+	s.finished = true
+	s.end = opts.FinishTime
+	if s.end.IsZero() {
+		s.end = time.Now()
+	}
+	s.logs = append(s.logs, opts.LogRecords...)
+	sampled := s.context.sampled
+	s.Unlock()
+
+	if sampled {
+		s.tracer.exporter.export(s)
+	}
+}
+
+func (s *span) Context() opentracing.SpanContext {
+	s.Lock()
+	defer s.Unlock()
+	return s.context
+}
+
+func (s *span) SetOperationName(operationName string) opentracing.Span {
+	s.Lock()
+	defer s.Unlock()
+	s.operationName = operationName
+	return s
+}
+
+func (s *span) SetTag(key string, value interface{}) opentracing.Span {
+	s.Lock()
+	defer s.Unlock()
+	s.tags[key] = value
+	return s
+}
+
+func (s *span) LogFields(fields ...log.Field) {
+	s.Lock()
+	defer s.Unlock()
+	s.logs = append(s.logs, opentracing.LogRecord{Timestamp: time.Now(), Fields: fields})
+}
+
+func (s *span) LogKV(alternatingKeyValues ...interface{}) {
+	fields, err := log.InterleavedKVToFields(alternatingKeyValues...)
+	if err != nil {
+		fields = []log.Field{log.Error(err)}
+	}
+	s.LogFields(fields...)
+}
+
+func (s *span) SetBaggageItem(restrictedKey, value string) opentracing.Span {
+	s.Lock()
+	defer s.Unlock()
+	baggage := make(map[string]string, len(s.context.baggage)+1)
+	for k, v := range s.context.baggage {
+		baggage[k] = v
+	}
+	baggage[restrictedKey] = value
+	s.context.baggage = baggage
+	return s
+}
+
+func (s *span) BaggageItem(restrictedKey string) string {
+	s.Lock()
+	defer s.Unlock()
+	return s.context.baggage[restrictedKey]
+}
+
+func (s *span) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+func (s *span) LogEvent(event string) {
+	s.LogFields(log.String("event", event))
+}
+
+func (s *span) LogEventWithPayload(event string, payload interface{}) {
+	s.LogFields(log.String("event", event), log.Object("payload", payload))
+}
+
+func (s *span) Log(data opentracing.LogData) {
+	record := data.ToLogRecord()
+	s.Lock()
+	defer s.Unlock()
+	s.logs = append(s.logs, record)
+}
@@ -0,0 +1,188 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package tracing implements an opentracing.Tracer which propagates the span context with W3C trace context
+// headers and exports the spans with the OpenTelemetry protocol (OTLP), so that traces can be collected by any
+// OpenTelemetry collector. It is used by the rpc transports, which propagate the span context across services.
+package tracing
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log"
+)
+
+type (
+	// Tracer creates spans and exports the sampled ones once they are finished
+	Tracer struct {
+		serviceName string
+		sampleRate  float64
+		exporter    spanExporter
+
+		randLock sync.Mutex
+		rand     *rand.Rand
+	}
+
+	spanExporter interface {
+		export(s *span)
+	}
+)
+
+var _ opentracing.Tracer = (*Tracer)(nil)
+
+// NewTracer creates a tracer for the service from the config, a noop tracer is returned if tracing is disabled.
+// The spans buffered in the tracer are exported once doneC is closed.
+func NewTracer(cfg *config.Tracing, serviceName string, logger log.Logger, doneC chan struct{}) (opentracing.Tracer, error) {
+	if cfg == nil || !cfg.Enabled {
+		return opentracing.NoopTracer{}, nil
+	}
+	exporter, err := newOTLPExporter(cfg, serviceName, logger, doneC)
+	if err != nil {
+		return nil, err
+	}
+	return newTracer(serviceName, cfg.SampleRate, exporter), nil
+}
+
+func newTracer(serviceName string, sampleRate float64, exporter spanExporter) *Tracer {
+	return &Tracer{
+		serviceName: serviceName,
+		sampleRate:  sampleRate,
+		exporter:    exporter,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// StartSpan starts a span, which continues the trace of the first ChildOf or else FollowsFrom reference.
+// Without references a new trace is started, which is sampled according to the sample rate.
+func (t *Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	options := opentracing.StartSpanOptions{}
+	for _, opt := range opts {
+		opt.Apply(&options)
+	}
+
+	s := &span{
+		tracer:        t,
+		operationName: operationName,
+		start:         options.StartTime,
+		tags:          make(map[string]interface{}, len(options.Tags)),
+	}
+	if s.start.IsZero() {
+		s.start = time.Now()
+	}
+	for k, v := range options.Tags {
+		s.tags[k] = v
+	}
+
+	if parent, ok := parentContext(options.References); ok {
+		s.context = spanContext{
+			traceID: parent.traceID,
+			sampled: parent.sampled,
+			baggage: parent.baggage,
+		}
+		s.parentID = parent.spanID
+	} else {
+		s.context.traceID = t.newTraceID()
+		s.context.sampled = t.sample()
+	}
+	s.context.spanID = t.newSpanID()
+	return s
+}
+
+// Inject writes the span context as W3C trace context headers, TextMap and HTTPHeaders formats are supported
+func (t *Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	sc, ok := sm.(spanContext)
+	if !ok {
+		return opentracing.ErrInvalidSpanContext
+	}
+	if format != opentracing.TextMap && format != opentracing.HTTPHeaders {
+		return opentracing.ErrUnsupportedFormat
+	}
+	writer, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+	injectTraceContext(sc, writer)
+	return nil
+}
+
+// Extract reads the span context from W3C trace context headers, TextMap and HTTPHeaders formats are supported
+func (t *Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	if format != opentracing.TextMap && format != opentracing.HTTPHeaders {
+		return nil, opentracing.ErrUnsupportedFormat
+	}
+	reader, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return nil, opentracing.ErrInvalidCarrier
+	}
+	return extractTraceContext(reader)
+}
+
+func (t *Tracer) sample() bool {
+	if t.sampleRate <= 0 {
+		return false
+	}
+	if t.sampleRate >= 1 {
+		return true
+	}
+	t.randLock.Lock()
+	defer t.randLock.Unlock()
+	return t.rand.Float64() < t.sampleRate
+}
+
+func (t *Tracer) newTraceID() (id traceID) {
+	t.randLock.Lock()
+	defer t.randLock.Unlock()
+	t.rand.Read(id[:])
+	return id
+}
+
+func (t *Tracer) newSpanID() (id spanID) {
+	t.randLock.Lock()
+	defer t.randLock.Unlock()
+	t.rand.Read(id[:])
+	return id
+}
+
+func parentContext(references []opentracing.SpanReference) (spanContext, bool) {
+	var followsFrom *spanContext
+	for _, ref := range references {
+		sc, ok := ref.ReferencedContext.(spanContext)
+		if !ok || !sc.isValid() {
+			continue
+		}
+		if ref.Type == opentracing.ChildOfRef {
+			return sc, true
+		}
+		if followsFrom == nil {
+			followsFrom = &sc
+		}
+	}
+	if followsFrom != nil {
+		return *followsFrom, true
+	}
+	return spanContext{}, false
+}
@@ -0,0 +1,220 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/config"
+	"github.com/uber/cadence/common/log"
+)
+
+type recordingExporter struct {
+	sync.Mutex
+	spans []*span
+}
+
+func (e *recordingExporter) export(s *span) {
+	e.Lock()
+	defer e.Unlock()
+	e.spans = append(e.spans, s)
+}
+
+func TestNewTracer_Disabled(t *testing.T) {
+	tracer, err := NewTracer(&config.Tracing{}, "frontend", log.NewNoop(), make(chan struct{}))
+	require.NoError(t, err)
+	require.Equal(t, opentracing.NoopTracer{}, tracer)
+}
+
+func TestNewTracer_InvalidConfig(t *testing.T) {
+	_, err := NewTracer(&config.Tracing{Enabled: true}, "frontend", log.NewNoop(), make(chan struct{}))
+	require.Error(t, err)
+
+	_, err = NewTracer(&config.Tracing{Enabled: true, Endpoint: "http://localhost:4318", SampleRate: 2}, "frontend", log.NewNoop(), make(chan struct{}))
+	require.Error(t, err)
+}
+
+func TestTracer_Sampling(t *testing.T) {
+	exporter := &recordingExporter{}
+
+	newTracer("frontend", 0, exporter).StartSpan("op").Finish()
+	require.Empty(t, exporter.spans)
+
+	tracer := newTracer("frontend", 1, exporter)
+	root := tracer.StartSpan("root")
+	child := tracer.StartSpan("child", opentracing.ChildOf(root.Context()))
+	child.Finish()
+	root.Finish()
+	root.Finish()
+	require.Len(t, exporter.spans, 2)
+
+	rootContext := root.Context().(spanContext)
+	childContext := child.Context().(spanContext)
+	require.Equal(t, rootContext.traceID, childContext.traceID)
+	require.NotEqual(t, rootContext.spanID, childContext.spanID)
+	require.Equal(t, rootContext.spanID, exporter.spans[0].parentID)
+}
+
+func TestTracer_Propagation(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := newTracer("history", 1, exporter)
+
+	parent := tracer.StartSpan("parent")
+	parent.SetBaggageItem("domain", "test domain")
+	carrier := opentracing.TextMapCarrier{}
+	require.NoError(t, tracer.Inject(parent.Context(), opentracing.TextMap, carrier))
+	require.Contains(t, carrier, traceParentHeader)
+
+	// grpc metadata keys are lower case while http headers are canonicalized
+	headers := http.Header{}
+	for k, v := range carrier {
+		headers.Set(k, v)
+	}
+	extracted, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(headers))
+	require.NoError(t, err)
+	require.Equal(t, parent.Context(), extracted)
+
+	child := tracer.StartSpan("child", opentracing.ChildOf(extracted))
+	require.Equal(t, "test domain", child.BaggageItem("domain"))
+	require.Equal(t, parent.Context().(spanContext).traceID, child.Context().(spanContext).traceID)
+}
+
+func TestTracer_PropagatesSamplingDecision(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := newTracer("matching", 1, exporter)
+
+	carrier := opentracing.TextMapCarrier{
+		traceParentHeader: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00",
+	}
+	extracted, err := tracer.Extract(opentracing.TextMap, carrier)
+	require.NoError(t, err)
+	tracer.StartSpan("op", opentracing.ChildOf(extracted)).Finish()
+	require.Empty(t, exporter.spans)
+}
+
+func TestTracer_ExtractInvalid(t *testing.T) {
+	tracer := newTracer("matching", 1, &recordingExporter{})
+
+	_, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{})
+	require.Equal(t, opentracing.ErrSpanContextNotFound, err)
+
+	for _, traceParent := range []string{
+		"invalid",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-xyz-01",
+	} {
+		_, err := tracer.Extract(opentracing.TextMap, opentracing.TextMapCarrier{traceParentHeader: traceParent})
+		require.Error(t, err, traceParent)
+	}
+
+	_, err = tracer.Extract(opentracing.Binary, opentracing.TextMapCarrier{})
+	require.Equal(t, opentracing.ErrUnsupportedFormat, err)
+}
+
+func TestStartChildSpan(t *testing.T) {
+	exporter := &recordingExporter{}
+	tracer := newTracer("history", 1, exporter)
+
+	span, ctx := StartChildSpan(context.Background(), "persistence.GetShard")
+	require.Nil(t, opentracing.SpanFromContext(ctx))
+	span.Finish()
+	require.Empty(t, exporter.spans)
+
+	parent := tracer.StartSpan("parent")
+	span, ctx = StartChildSpan(opentracing.ContextWithSpan(context.Background(), parent), "persistence.GetShard")
+	require.Equal(t, span, opentracing.SpanFromContext(ctx))
+	FinishSpan(span, errors.New("shard ownership lost"))
+	require.Len(t, exporter.spans, 1)
+	require.Equal(t, true, exporter.spans[0].tags[string(ext.Error)])
+	require.Equal(t, parent.Context().(spanContext).spanID, exporter.spans[0].parentID)
+}
+
+func TestOTLPExporter(t *testing.T) {
+	requestC := make(chan *otlpExportRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, otlpTracesPath, r.URL.Path)
+		require.Equal(t, "secret", r.Header.Get("Authorization"))
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var request otlpExportRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requestC <- &request
+	}))
+	defer server.Close()
+
+	doneC := make(chan struct{})
+	tracer, err := NewTracer(&config.Tracing{
+		Enabled:       true,
+		Endpoint:      server.URL + "/",
+		Headers:       map[string]string{"Authorization": "secret"},
+		SampleRate:    1,
+		FlushInterval: time.Hour,
+	}, "cadence-matching", log.NewNoop(), doneC)
+	require.NoError(t, err)
+
+	span := tracer.StartSpan("matching.AddTask", ext.SpanKindRPCServer, opentracing.Tag{Key: TagDispatchPath, Value: "sync"})
+	span.LogKV("event", "matched", "attempt", 1)
+	ext.Error.Set(span, true)
+	span.Finish()
+	close(doneC)
+
+	var request *otlpExportRequest
+	select {
+	case request = <-requestC:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "spans were not exported")
+	}
+
+	require.Len(t, request.ResourceSpans, 1)
+	resourceSpans := request.ResourceSpans[0]
+	require.Equal(t, "service.name", resourceSpans.Resource.Attributes[0].Key)
+	require.Equal(t, "cadence-matching", *resourceSpans.Resource.Attributes[0].Value.StringValue)
+	require.Len(t, resourceSpans.ScopeSpans, 1)
+	require.Len(t, resourceSpans.ScopeSpans[0].Spans, 1)
+
+	exported := resourceSpans.ScopeSpans[0].Spans[0]
+	sc := span.Context().(spanContext)
+	require.Equal(t, hex.EncodeToString(sc.traceID[:]), exported.TraceID)
+	require.Equal(t, hex.EncodeToString(sc.spanID[:]), exported.SpanID)
+	require.Empty(t, exported.ParentSpanID)
+	require.Equal(t, "matching.AddTask", exported.Name)
+	require.Equal(t, spanKindServer, exported.Kind)
+	require.Equal(t, statusCodeError, exported.Status.Code)
+	require.Len(t, exported.Events, 1)
+	require.Equal(t, "matched", exported.Events[0].Name)
+	require.Equal(t, "attempt", exported.Events[0].Attributes[0].Key)
+	require.Equal(t, "1", *exported.Events[0].Attributes[0].Value.IntValue)
+}
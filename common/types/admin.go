@@ -301,3 +301,536 @@ type ListDynamicConfigRequest struct {
 type ListDynamicConfigResponse struct {
 	Entries []*DynamicConfigEntry `json:"entries,omitempty"`
 }
+
+// AdminUnloadTaskListRequest is an internal type (TBD...)
+type AdminUnloadTaskListRequest struct {
+	Domain             string        `json:"domain,omitempty"`
+	TaskList           *TaskList     `json:"taskList,omitempty"`
+	TaskListType       *TaskListType `json:"taskListType,omitempty"`
+	BlockReloadSeconds int32         `json:"blockReloadSeconds,omitempty"`
+}
+
+// GetDomain is an internal getter (TBD...)
+func (v *AdminUnloadTaskListRequest) GetDomain() (o string) {
+	if v != nil {
+		return v.Domain
+	}
+	return
+}
+
+// GetTaskList is an internal getter (TBD...)
+func (v *AdminUnloadTaskListRequest) GetTaskList() (o *TaskList) {
+	if v != nil && v.TaskList != nil {
+		return v.TaskList
+	}
+	return
+}
+
+// GetTaskListType is an internal getter (TBD...)
+func (v *AdminUnloadTaskListRequest) GetTaskListType() (o TaskListType) {
+	if v != nil && v.TaskListType != nil {
+		return *v.TaskListType
+	}
+	return
+}
+
+// GetBlockReloadSeconds is an internal getter (TBD...)
+func (v *AdminUnloadTaskListRequest) GetBlockReloadSeconds() (o int32) {
+	if v != nil {
+		return v.BlockReloadSeconds
+	}
+	return
+}
+
+// AdminGetTaskListDrainStatusRequest is an internal type (TBD...)
+type AdminGetTaskListDrainStatusRequest struct {
+	Domain       string        `json:"domain,omitempty"`
+	TaskList     *TaskList     `json:"taskList,omitempty"`
+	TaskListType *TaskListType `json:"taskListType,omitempty"`
+}
+
+// GetDomain is an internal getter (TBD...)
+func (v *AdminGetTaskListDrainStatusRequest) GetDomain() (o string) {
+	if v != nil {
+		return v.Domain
+	}
+	return
+}
+
+// GetTaskList is an internal getter (TBD...)
+func (v *AdminGetTaskListDrainStatusRequest) GetTaskList() (o *TaskList) {
+	if v != nil && v.TaskList != nil {
+		return v.TaskList
+	}
+	return
+}
+
+// GetTaskListType is an internal getter (TBD...)
+func (v *AdminGetTaskListDrainStatusRequest) GetTaskListType() (o TaskListType) {
+	if v != nil && v.TaskListType != nil {
+		return *v.TaskListType
+	}
+	return
+}
+
+// GetTaskListDrainStatusResponse is an internal type (TBD...)
+type GetTaskListDrainStatusResponse struct {
+	Draining   bool                            `json:"draining,omitempty"`
+	Drained    bool                            `json:"drained,omitempty"`
+	Partitions []*TaskListPartitionDrainStatus `json:"partitions,omitempty"`
+}
+
+// GetDraining is an internal getter (TBD...)
+func (v *GetTaskListDrainStatusResponse) GetDraining() (o bool) {
+	if v != nil {
+		return v.Draining
+	}
+	return
+}
+
+// GetDrained is an internal getter (TBD...)
+func (v *GetTaskListDrainStatusResponse) GetDrained() (o bool) {
+	if v != nil {
+		return v.Drained
+	}
+	return
+}
+
+// GetPartitions is an internal getter (TBD...)
+func (v *GetTaskListDrainStatusResponse) GetPartitions() (o []*TaskListPartitionDrainStatus) {
+	if v != nil {
+		return v.Partitions
+	}
+	return
+}
+
+// TaskListPartitionDrainStatus is an internal type (TBD...)
+type TaskListPartitionDrainStatus struct {
+	TaskList string `json:"taskList,omitempty"`
+	Drained  bool   `json:"drained,omitempty"`
+}
+
+// GetTaskList is an internal getter (TBD...)
+func (v *TaskListPartitionDrainStatus) GetTaskList() (o string) {
+	if v != nil {
+		return v.TaskList
+	}
+	return
+}
+
+// GetDrained is an internal getter (TBD...)
+func (v *TaskListPartitionDrainStatus) GetDrained() (o bool) {
+	if v != nil {
+		return v.Drained
+	}
+	return
+}
+
+// AdminPauseTaskListRequest is an internal type (TBD...)
+type AdminPauseTaskListRequest struct {
+	Domain       string        `json:"domain,omitempty"`
+	TaskList     *TaskList     `json:"taskList,omitempty"`
+	TaskListType *TaskListType `json:"taskListType,omitempty"`
+}
+
+// GetDomain is an internal getter (TBD...)
+func (v *AdminPauseTaskListRequest) GetDomain() (o string) {
+	if v != nil {
+		return v.Domain
+	}
+	return
+}
+
+// GetTaskList is an internal getter (TBD...)
+func (v *AdminPauseTaskListRequest) GetTaskList() (o *TaskList) {
+	if v != nil && v.TaskList != nil {
+		return v.TaskList
+	}
+	return
+}
+
+// GetTaskListType is an internal getter (TBD...)
+func (v *AdminPauseTaskListRequest) GetTaskListType() (o TaskListType) {
+	if v != nil && v.TaskListType != nil {
+		return *v.TaskListType
+	}
+	return
+}
+
+// AdminResumeTaskListRequest is an internal type (TBD...)
+type AdminResumeTaskListRequest struct {
+	Domain       string        `json:"domain,omitempty"`
+	TaskList     *TaskList     `json:"taskList,omitempty"`
+	TaskListType *TaskListType `json:"taskListType,omitempty"`
+}
+
+// GetDomain is an internal getter (TBD...)
+func (v *AdminResumeTaskListRequest) GetDomain() (o string) {
+	if v != nil {
+		return v.Domain
+	}
+	return
+}
+
+// GetTaskList is an internal getter (TBD...)
+func (v *AdminResumeTaskListRequest) GetTaskList() (o *TaskList) {
+	if v != nil && v.TaskList != nil {
+		return v.TaskList
+	}
+	return
+}
+
+// GetTaskListType is an internal getter (TBD...)
+func (v *AdminResumeTaskListRequest) GetTaskListType() (o TaskListType) {
+	if v != nil && v.TaskListType != nil {
+		return *v.TaskListType
+	}
+	return
+}
+
+// AdminGetDomainUsageRequest is an internal type (TBD...)
+type AdminGetDomainUsageRequest struct {
+	// Domain limits the response to a single domain, empty means all domains
+	Domain string `json:"domain,omitempty"`
+	// StartTime and EndTime are in unix nanoseconds
+	StartTime int64 `json:"startTime,omitempty"`
+	EndTime   int64 `json:"endTime,omitempty"`
+}
+
+// GetDomain is an internal getter (TBD...)
+func (v *AdminGetDomainUsageRequest) GetDomain() (o string) {
+	if v != nil {
+		return v.Domain
+	}
+	return
+}
+
+// GetStartTime is an internal getter (TBD...)
+func (v *AdminGetDomainUsageRequest) GetStartTime() (o int64) {
+	if v != nil {
+		return v.StartTime
+	}
+	return
+}
+
+// GetEndTime is an internal getter (TBD...)
+func (v *AdminGetDomainUsageRequest) GetEndTime() (o int64) {
+	if v != nil {
+		return v.EndTime
+	}
+	return
+}
+
+// AdminGetDomainUsageResponse is an internal type (TBD...)
+type AdminGetDomainUsageResponse struct {
+	// Domains is keyed by domain name
+	Domains map[string]*DomainUsage `json:"domains,omitempty"`
+}
+
+// GetDomains is an internal getter (TBD...)
+func (v *AdminGetDomainUsageResponse) GetDomains() (o map[string]*DomainUsage) {
+	if v != nil {
+		return v.Domains
+	}
+	return
+}
+
+// DomainUsage is an internal type (TBD...)
+type DomainUsage struct {
+	Actions               int64 `json:"actions,omitempty"`
+	PersistenceReadUnits  int64 `json:"persistenceReadUnits,omitempty"`
+	PersistenceWriteUnits int64 `json:"persistenceWriteUnits,omitempty"`
+	PayloadBytes          int64 `json:"payloadBytes,omitempty"`
+	TaskDispatches        int64 `json:"taskDispatches,omitempty"`
+}
+
+// InvalidateCachesRequest is an internal type (TBD...)
+type InvalidateCachesRequest struct {
+	// HostAddress is the address of the host whose caches are invalidated,
+	// the admin API invalidates the caches of the frontend host serving the request if it is empty
+	HostAddress string `json:"hostAddress,omitempty"`
+	// CacheNamePrefix limits the invalidation to the caches whose name starts with it, e.g. history/execution
+	CacheNamePrefix string `json:"cacheNamePrefix,omitempty"`
+	// Match is the substring of the keys of the invalidated entries
+	Match string `json:"match,omitempty"`
+}
+
+// GetHostAddress is an internal getter (TBD...)
+func (v *InvalidateCachesRequest) GetHostAddress() (o string) {
+	if v != nil {
+		return v.HostAddress
+	}
+	return
+}
+
+// GetCacheNamePrefix is an internal getter (TBD...)
+func (v *InvalidateCachesRequest) GetCacheNamePrefix() (o string) {
+	if v != nil {
+		return v.CacheNamePrefix
+	}
+	return
+}
+
+// GetMatch is an internal getter (TBD...)
+func (v *InvalidateCachesRequest) GetMatch() (o string) {
+	if v != nil {
+		return v.Match
+	}
+	return
+}
+
+// InvalidateCachesResponse is an internal type (TBD...)
+type InvalidateCachesResponse struct {
+	// Invalidated is the number of entries invalidated by cache name
+	Invalidated map[string]int32 `json:"invalidated,omitempty"`
+}
+
+// GetInvalidated is an internal getter (TBD...)
+func (v *InvalidateCachesResponse) GetInvalidated() (o map[string]int32) {
+	if v != nil {
+		return v.Invalidated
+	}
+	return
+}
+
+// DescribeShardOwnershipRequest is an internal type (TBD...)
+type DescribeShardOwnershipRequest struct {
+	// HostAddress is the address of the history host describing the shard ownership
+	HostAddress string `json:"hostAddress,omitempty"`
+}
+
+// GetHostAddress is an internal getter (TBD...)
+func (v *DescribeShardOwnershipRequest) GetHostAddress() (o string) {
+	if v != nil {
+		return v.HostAddress
+	}
+	return
+}
+
+// DescribeShardOwnershipResponse describes the shard to host mapping of the cluster,
+// with acquisition details for the shards owned by the describing host
+type DescribeShardOwnershipResponse struct {
+	NumberOfShards int32                 `json:"numberOfShards,omitempty"`
+	Host           string                `json:"host,omitempty"`
+	Shards         []*ShardOwnership     `json:"shards,omitempty"`
+	Hosts          []*HostShardOwnership `json:"hosts,omitempty"`
+}
+
+// GetNumberOfShards is an internal getter (TBD...)
+func (v *DescribeShardOwnershipResponse) GetNumberOfShards() (o int32) {
+	if v != nil {
+		return v.NumberOfShards
+	}
+	return
+}
+
+// GetHost is an internal getter (TBD...)
+func (v *DescribeShardOwnershipResponse) GetHost() (o string) {
+	if v != nil {
+		return v.Host
+	}
+	return
+}
+
+// GetShards is an internal getter (TBD...)
+func (v *DescribeShardOwnershipResponse) GetShards() (o []*ShardOwnership) {
+	if v != nil {
+		return v.Shards
+	}
+	return
+}
+
+// GetHosts is an internal getter (TBD...)
+func (v *DescribeShardOwnershipResponse) GetHosts() (o []*HostShardOwnership) {
+	if v != nil {
+		return v.Hosts
+	}
+	return
+}
+
+// ShardOwnership describes the owner of a shard, AcquiredAt is only set if the shard is owned by the describing host
+// and RecentSteals counts the ownership changes of the shard involving the describing host within the steal window
+type ShardOwnership struct {
+	ShardID int32  `json:"shardID,omitempty"`
+	Owner   string `json:"owner,omitempty"`
+	// AcquiredAt is in unix nanoseconds
+	AcquiredAt   *int64 `json:"acquiredAt,omitempty"`
+	RecentSteals int32  `json:"recentSteals,omitempty"`
+}
+
+// HostShardOwnership describes the shards owned by a host, Imbalance is the ratio
+// between the number of shards owned by the host and an even share of all shards
+type HostShardOwnership struct {
+	Host      string  `json:"host,omitempty"`
+	NumShards int32   `json:"numShards,omitempty"`
+	Imbalance float64 `json:"imbalance,omitempty"`
+}
+
+// DescribePersistenceLatencyHeatmapRequest is an internal type (TBD...)
+type DescribePersistenceLatencyHeatmapRequest struct {
+	// HostAddress is the address of the history host whose heatmap is returned
+	HostAddress string `json:"hostAddress,omitempty"`
+	// WindowInSeconds limits the heatmap to the operations completed within the window, the longest window is used if zero
+	WindowInSeconds int32 `json:"windowInSeconds,omitempty"`
+}
+
+// GetHostAddress is an internal getter (TBD...)
+func (v *DescribePersistenceLatencyHeatmapRequest) GetHostAddress() (o string) {
+	if v != nil {
+		return v.HostAddress
+	}
+	return
+}
+
+// GetWindowInSeconds is an internal getter (TBD...)
+func (v *DescribePersistenceLatencyHeatmapRequest) GetWindowInSeconds() (o int32) {
+	if v != nil {
+		return v.WindowInSeconds
+	}
+	return
+}
+
+// DescribePersistenceLatencyHeatmapResponse is the persistence latency heatmap of a host,
+// cells are sorted by decreasing p99 latency
+type DescribePersistenceLatencyHeatmapResponse struct {
+	// StartTime and EndTime are in unix nanoseconds
+	StartTime int64                     `json:"startTime,omitempty"`
+	EndTime   int64                     `json:"endTime,omitempty"`
+	Cells     []*PersistenceLatencyCell `json:"cells,omitempty"`
+}
+
+// GetStartTime is an internal getter (TBD...)
+func (v *DescribePersistenceLatencyHeatmapResponse) GetStartTime() (o int64) {
+	if v != nil {
+		return v.StartTime
+	}
+	return
+}
+
+// GetEndTime is an internal getter (TBD...)
+func (v *DescribePersistenceLatencyHeatmapResponse) GetEndTime() (o int64) {
+	if v != nil {
+		return v.EndTime
+	}
+	return
+}
+
+// GetCells is an internal getter (TBD...)
+func (v *DescribePersistenceLatencyHeatmapResponse) GetCells() (o []*PersistenceLatencyCell) {
+	if v != nil {
+		return v.Cells
+	}
+	return
+}
+
+// PersistenceLatencyCell summarizes the latency of a persistence operation on a shard
+type PersistenceLatencyCell struct {
+	ShardID           int32  `json:"shardID,omitempty"`
+	Operation         string `json:"operation,omitempty"`
+	Count             int64  `json:"count,omitempty"`
+	P99InMilliseconds int64  `json:"p99InMilliseconds,omitempty"`
+}
+
+// DescribeWatchdogRequest is an internal type (TBD...)
+type DescribeWatchdogRequest struct {
+	// HostAddress is the address of the host whose watchdog is described,
+	// the admin API describes the watchdog of the frontend host serving the request if it is empty
+	HostAddress string `json:"hostAddress,omitempty"`
+}
+
+// GetHostAddress is an internal getter (TBD...)
+func (v *DescribeWatchdogRequest) GetHostAddress() (o string) {
+	if v != nil {
+		return v.HostAddress
+	}
+	return
+}
+
+// DescribeWatchdogResponse describes the resources tracked by the watchdog of a service, ordered by name
+type DescribeWatchdogResponse struct {
+	Service   string              `json:"service,omitempty"`
+	Resources []*WatchdogResource `json:"resources,omitempty"`
+}
+
+// GetService is an internal getter (TBD...)
+func (v *DescribeWatchdogResponse) GetService() (o string) {
+	if v != nil {
+		return v.Service
+	}
+	return
+}
+
+// GetResources is an internal getter (TBD...)
+func (v *DescribeWatchdogResponse) GetResources() (o []*WatchdogResource) {
+	if v != nil {
+		return v.Resources
+	}
+	return
+}
+
+// WatchdogResource describes a resource tracked by a watchdog, with its largest owners
+type WatchdogResource struct {
+	Name        string                   `json:"name,omitempty"`
+	Value       int64                    `json:"value,omitempty"`
+	LeakSuspect bool                     `json:"leakSuspect,omitempty"`
+	Samples     []*WatchdogSample        `json:"samples,omitempty"`
+	Owners      []*WatchdogResourceOwner `json:"owners,omitempty"`
+}
+
+// WatchdogSample is the number of instances and the load of a resource at a point in time
+type WatchdogSample struct {
+	// Timestamp is in unix nanoseconds
+	Timestamp int64 `json:"timestamp,omitempty"`
+	Value     int64 `json:"value,omitempty"`
+	Load      int64 `json:"load,omitempty"`
+}
+
+// WatchdogResourceOwner is the number of instances of a resource held by an owner
+type WatchdogResourceOwner struct {
+	Name  string `json:"name,omitempty"`
+	Count int64  `json:"count,omitempty"`
+}
+
+// DescribeDomainSLORequest is an internal type (TBD...)
+type DescribeDomainSLORequest struct {
+	// Domain limits the description to the SLOs of the domain, the SLOs of all the tracked domains are described if it is empty
+	Domain string `json:"domain,omitempty"`
+}
+
+// GetDomain is an internal getter (TBD...)
+func (v *DescribeDomainSLORequest) GetDomain() (o string) {
+	if v != nil {
+		return v.Domain
+	}
+	return
+}
+
+// DescribeDomainSLOResponse describes the SLOs of domains as tracked by the frontend host serving the request, ordered by domain
+type DescribeDomainSLOResponse struct {
+	Domains []*DomainSLO `json:"domains,omitempty"`
+}
+
+// GetDomains is an internal getter (TBD...)
+func (v *DescribeDomainSLOResponse) GetDomains() (o []*DomainSLO) {
+	if v != nil {
+		return v.Domains
+	}
+	return
+}
+
+// DomainSLO is the state of the SLOs of a domain, objectives not configured are not set
+type DomainSLO struct {
+	Domain       string        `json:"domain,omitempty"`
+	Availability *SLOObjective `json:"availability,omitempty"`
+	Latency      *SLOObjective `json:"latency,omitempty"`
+}
+
+// SLOObjective is the state of the error budget of an objective over the last hour
+type SLOObjective struct {
+	Target          float64 `json:"target,omitempty"`
+	Requests        int64   `json:"requests,omitempty"`
+	BadRequests     int64   `json:"badRequests,omitempty"`
+	BudgetRemaining float64 `json:"budgetRemaining,omitempty"`
+	ShortBurnRate   float64 `json:"shortBurnRate,omitempty"`
+	LongBurnRate    float64 `json:"longBurnRate,omitempty"`
+}
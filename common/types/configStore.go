@@ -39,3 +39,99 @@ type DynamicConfigFilter struct {
 	Name  string    `json:"name,omitempty"`
 	Value *DataBlob `json:"value,omitempty"`
 }
+
+// ListDynamicConfigChangesRequest is an internal type (TBD...)
+type ListDynamicConfigChangesRequest struct {
+	// MaxVersion is the largest snapshot version returned, zero means latest
+	MaxVersion int64 `json:"maxVersion,omitempty"`
+	PageSize   int32 `json:"pageSize,omitempty"`
+}
+
+// GetMaxVersion is an internal getter (TBD...)
+func (v *ListDynamicConfigChangesRequest) GetMaxVersion() (o int64) {
+	if v != nil {
+		return v.MaxVersion
+	}
+	return
+}
+
+// GetPageSize is an internal getter (TBD...)
+func (v *ListDynamicConfigChangesRequest) GetPageSize() (o int32) {
+	if v != nil {
+		return v.PageSize
+	}
+	return
+}
+
+// ListDynamicConfigChangesResponse is an internal type (TBD...)
+// changes are sorted by version in descending order
+type ListDynamicConfigChangesResponse struct {
+	Changes []*DynamicConfigChange `json:"changes,omitempty"`
+}
+
+// GetChanges is an internal getter (TBD...)
+func (v *ListDynamicConfigChangesResponse) GetChanges() (o []*DynamicConfigChange) {
+	if v != nil {
+		return v.Changes
+	}
+	return
+}
+
+// DynamicConfigChange is a change of a dynamic config entry in the change history
+type DynamicConfigChange struct {
+	Version int64 `json:"version,omitempty"`
+	// Timestamp is in unix nanoseconds
+	Timestamp int64                 `json:"timestamp,omitempty"`
+	Identity  string                `json:"identity,omitempty"`
+	Name      string                `json:"name,omitempty"`
+	OldValues []*DynamicConfigValue `json:"oldValues,omitempty"`
+	NewValues []*DynamicConfigValue `json:"newValues,omitempty"`
+}
+
+// ListEffectiveDynamicConfigRequest is an internal type (TBD...)
+type ListEffectiveDynamicConfigRequest struct {
+	// ConfigNamePrefix restricts the values to the keys with the prefix, all keys are listed if empty
+	ConfigNamePrefix string                 `json:"configNamePrefix,omitempty"`
+	Filters          []*DynamicConfigFilter `json:"filters,omitempty"`
+}
+
+// GetConfigNamePrefix is an internal getter (TBD...)
+func (v *ListEffectiveDynamicConfigRequest) GetConfigNamePrefix() (o string) {
+	if v != nil {
+		return v.ConfigNamePrefix
+	}
+	return
+}
+
+// GetFilters is an internal getter (TBD...)
+func (v *ListEffectiveDynamicConfigRequest) GetFilters() (o []*DynamicConfigFilter) {
+	if v != nil {
+		return v.Filters
+	}
+	return
+}
+
+// ListEffectiveDynamicConfigResponse is an internal type (TBD...)
+// values are sorted by name
+type ListEffectiveDynamicConfigResponse struct {
+	Values []*EffectiveDynamicConfigValue `json:"values,omitempty"`
+}
+
+// GetValues is an internal getter (TBD...)
+func (v *ListEffectiveDynamicConfigResponse) GetValues() (o []*EffectiveDynamicConfigValue) {
+	if v != nil {
+		return v.Values
+	}
+	return
+}
+
+// EffectiveDynamicConfigValue is the value of a dynamic config key in effect for a set of filters
+type EffectiveDynamicConfigValue struct {
+	Name         string    `json:"name,omitempty"`
+	Value        *DataBlob `json:"value,omitempty"`
+	DefaultValue *DataBlob `json:"defaultValue,omitempty"`
+	IsDefault    bool      `json:"isDefault,omitempty"`
+	// MatchedFilters are the filters of the value in effect, nil if unknown or if the default is in effect
+	MatchedFilters []*DynamicConfigFilter `json:"matchedFilters"`
+	Error          string                 `json:"error,omitempty"`
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Uber Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package json maps the errors of the APIs which are not in the IDLs yet and are served with the json encoding.
+// The requests and responses of these APIs are the internal types themselves, only the errors need a mapping
+// so that their type survives both the tchannel and the grpc transports.
+package json
+
+import (
+	"errors"
+
+	"go.uber.org/yarpc/yarpcerrors"
+
+	"github.com/uber/cadence/common/types"
+)
+
+// FromError maps an internal error to a yarpc error, the fields of the error other than its message are dropped
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case *types.AccessDeniedError:
+		return yarpcerrors.Newf(yarpcerrors.CodePermissionDenied, "%s", e.Message)
+	case *types.InternalServiceError:
+		return yarpcerrors.Newf(yarpcerrors.CodeInternal, "%s", e.Message)
+	case *types.EntityNotExistsError:
+		return yarpcerrors.Newf(yarpcerrors.CodeNotFound, "%s", e.Message)
+	case *types.BadRequestError:
+		return yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "%s", e.Message)
+	case *types.DomainNotActiveError:
+		return yarpcerrors.Newf(yarpcerrors.CodeFailedPrecondition, "%s", e.Message)
+	case *types.WorkflowExecutionAlreadyCompletedError:
+		return yarpcerrors.Newf(yarpcerrors.CodeAborted, "%s", e.Message)
+	case *types.LimitExceededError:
+		return yarpcerrors.Newf(yarpcerrors.CodeResourceExhausted, "%s", e.Message)
+	case *types.ServiceBusyError:
+		return yarpcerrors.Newf(yarpcerrors.CodeResourceExhausted, "%s", e.Message)
+	}
+
+	if yarpcerrors.IsStatus(err) {
+		return err
+	}
+	return yarpcerrors.Newf(yarpcerrors.CodeUnknown, "%s", err.Error())
+}
+
+// ToError maps a yarpc error back to the internal error it was mapped from
+func ToError(err error) error {
+	status := yarpcerrors.FromError(err)
+	if status == nil || status.Code() == yarpcerrors.CodeOK {
+		return nil
+	}
+
+	switch status.Code() {
+	case yarpcerrors.CodePermissionDenied:
+		return &types.AccessDeniedError{Message: status.Message()}
+	case yarpcerrors.CodeInternal:
+		return &types.InternalServiceError{Message: status.Message()}
+	case yarpcerrors.CodeNotFound:
+		return &types.EntityNotExistsError{Message: status.Message()}
+	case yarpcerrors.CodeInvalidArgument:
+		return &types.BadRequestError{Message: status.Message()}
+	case yarpcerrors.CodeFailedPrecondition:
+		return &types.DomainNotActiveError{Message: status.Message()}
+	case yarpcerrors.CodeAborted:
+		return &types.WorkflowExecutionAlreadyCompletedError{Message: status.Message()}
+	case yarpcerrors.CodeResourceExhausted:
+		return &types.ServiceBusyError{Message: status.Message()}
+	case yarpcerrors.CodeUnknown:
+		return errors.New(status.Message())
+	}
+
+	// the other errors, e.g. deadline exceeded or a host which doesn't serve the procedure yet, are kept as they are
+	return status
+}
@@ -3655,7 +3655,7 @@ func FromStartWorkflowExecutionRequest(t *types.StartWorkflowExecutionRequest) *
 		CronSchedule:                 t.CronSchedule,
 		Memo:                         FromMemo(t.Memo),
 		SearchAttributes:             FromSearchAttributes(t.SearchAttributes),
-		Header:                       FromHeader(t.Header),
+		Header:                       FromHeader(types.AddFirstRunAtTimestampHeader(t.Header, t.FirstRunAtTimestamp)),
 		DelayStart:                   secondsToDuration(t.DelayStartSeconds),
 		JitterStart:                  secondsToDuration(t.JitterStartSeconds),
 	}
@@ -3665,6 +3665,7 @@ func ToStartWorkflowExecutionRequest(t *apiv1.StartWorkflowExecutionRequest) *ty
 	if t == nil {
 		return nil
 	}
+	header, firstRunAtTimestamp := types.RemoveFirstRunAtTimestampHeader(ToHeader(t.Header))
 	return &types.StartWorkflowExecutionRequest{
 		Domain:                              t.Domain,
 		WorkflowID:                          t.WorkflowId,
@@ -3680,9 +3681,10 @@ func ToStartWorkflowExecutionRequest(t *apiv1.StartWorkflowExecutionRequest) *ty
 		CronSchedule:                        t.CronSchedule,
 		Memo:                                ToMemo(t.Memo),
 		SearchAttributes:                    ToSearchAttributes(t.SearchAttributes),
-		Header:                              ToHeader(t.Header),
+		Header:                              header,
 		DelayStartSeconds:                   durationToSeconds(t.DelayStart),
 		JitterStartSeconds:                  durationToSeconds(t.JitterStart),
+		FirstRunAtTimestamp:                 firstRunAtTimestamp,
 	}
 }
 
@@ -620,6 +620,22 @@ func TestStartWorkflowExecutionRequest(t *testing.T) {
 		assert.Equal(t, item, ToStartWorkflowExecutionRequest(FromStartWorkflowExecutionRequest(item)))
 	}
 }
+func TestStartWorkflowExecutionRequestFirstRunAtTimestamp(t *testing.T) {
+	// the timestamp is carried in the header on the wire, the other fields of the header are kept
+	request := FromStartWorkflowExecutionRequest(&types.StartWorkflowExecutionRequest{
+		Header:              &types.Header{Fields: map[string][]byte{"key": []byte("value")}},
+		FirstRunAtTimestamp: common.Int64Ptr(100),
+	})
+	assert.Equal(t, &apiv1.Header{Fields: map[string]*apiv1.Payload{
+		"key":                           {Data: []byte("value")},
+		types.FirstRunAtTimestampHeader: {Data: []byte("100")},
+	}}, request.Header)
+
+	request.Header.Fields[types.FirstRunAtTimestampHeader] = &apiv1.Payload{Data: []byte("invalid")}
+	assert.Equal(t, &types.StartWorkflowExecutionRequest{
+		Header: &types.Header{Fields: map[string][]byte{"key": []byte("value"), types.FirstRunAtTimestampHeader: []byte("invalid")}},
+	}, ToStartWorkflowExecutionRequest(&apiv1.StartWorkflowExecutionRequest{Header: request.Header}))
+}
 func TestStartWorkflowExecutionResponse(t *testing.T) {
 	for _, item := range []*types.StartWorkflowExecutionResponse{nil, {}, &testdata.StartWorkflowExecutionResponse} {
 		assert.Equal(t, item, ToStartWorkflowExecutionResponse(FromStartWorkflowExecutionResponse(item)))
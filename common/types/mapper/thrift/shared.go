@@ -5251,7 +5251,7 @@ func FromStartWorkflowExecutionRequest(t *types.StartWorkflowExecutionRequest) *
 		CronSchedule:                        &t.CronSchedule,
 		Memo:                                FromMemo(t.Memo),
 		SearchAttributes:                    FromSearchAttributes(t.SearchAttributes),
-		Header:                              FromHeader(t.Header),
+		Header:                              FromHeader(types.AddFirstRunAtTimestampHeader(t.Header, t.FirstRunAtTimestamp)),
 		DelayStartSeconds:                   t.DelayStartSeconds,
 		JitterStartSeconds:                  t.JitterStartSeconds,
 	}
@@ -5262,6 +5262,7 @@ func ToStartWorkflowExecutionRequest(t *shared.StartWorkflowExecutionRequest) *t
 	if t == nil {
 		return nil
 	}
+	header, firstRunAtTimestamp := types.RemoveFirstRunAtTimestampHeader(ToHeader(t.Header))
 	return &types.StartWorkflowExecutionRequest{
 		Domain:                              t.GetDomain(),
 		WorkflowID:                          t.GetWorkflowId(),
@@ -5277,9 +5278,10 @@ func ToStartWorkflowExecutionRequest(t *shared.StartWorkflowExecutionRequest) *t
 		CronSchedule:                        t.GetCronSchedule(),
 		Memo:                                ToMemo(t.Memo),
 		SearchAttributes:                    ToSearchAttributes(t.SearchAttributes),
-		Header:                              ToHeader(t.Header),
+		Header:                              header,
 		DelayStartSeconds:                   t.DelayStartSeconds,
 		JitterStartSeconds:                  t.JitterStartSeconds,
+		FirstRunAtTimestamp:                 firstRunAtTimestamp,
 	}
 }
 
@@ -549,3 +549,185 @@ const (
 	// TaskSourceDbBacklog is an option for TaskSource
 	TaskSourceDbBacklog
 )
+
+// MatchingUnloadTaskListRequest is an internal type (TBD...)
+type MatchingUnloadTaskListRequest struct {
+	DomainUUID         string        `json:"domainUUID,omitempty"`
+	TaskList           *TaskList     `json:"taskList,omitempty"`
+	TaskListType       *TaskListType `json:"taskListType,omitempty"`
+	BlockReloadSeconds int32         `json:"blockReloadSeconds,omitempty"`
+}
+
+// GetDomainUUID is an internal getter (TBD...)
+func (v *MatchingUnloadTaskListRequest) GetDomainUUID() (o string) {
+	if v != nil {
+		return v.DomainUUID
+	}
+	return
+}
+
+// GetTaskList is an internal getter (TBD...)
+func (v *MatchingUnloadTaskListRequest) GetTaskList() (o *TaskList) {
+	if v != nil && v.TaskList != nil {
+		return v.TaskList
+	}
+	return
+}
+
+// GetTaskListType is an internal getter (TBD...)
+func (v *MatchingUnloadTaskListRequest) GetTaskListType() (o TaskListType) {
+	if v != nil && v.TaskListType != nil {
+		return *v.TaskListType
+	}
+	return
+}
+
+// GetBlockReloadSeconds is an internal getter (TBD...)
+func (v *MatchingUnloadTaskListRequest) GetBlockReloadSeconds() (o int32) {
+	if v != nil {
+		return v.BlockReloadSeconds
+	}
+	return
+}
+
+// MatchingPauseTaskListRequest is an internal type (TBD...)
+type MatchingPauseTaskListRequest struct {
+	DomainUUID   string        `json:"domainUUID,omitempty"`
+	TaskList     *TaskList     `json:"taskList,omitempty"`
+	TaskListType *TaskListType `json:"taskListType,omitempty"`
+}
+
+// GetDomainUUID is an internal getter (TBD...)
+func (v *MatchingPauseTaskListRequest) GetDomainUUID() (o string) {
+	if v != nil {
+		return v.DomainUUID
+	}
+	return
+}
+
+// GetTaskList is an internal getter (TBD...)
+func (v *MatchingPauseTaskListRequest) GetTaskList() (o *TaskList) {
+	if v != nil && v.TaskList != nil {
+		return v.TaskList
+	}
+	return
+}
+
+// GetTaskListType is an internal getter (TBD...)
+func (v *MatchingPauseTaskListRequest) GetTaskListType() (o TaskListType) {
+	if v != nil && v.TaskListType != nil {
+		return *v.TaskListType
+	}
+	return
+}
+
+// MatchingResumeTaskListRequest is an internal type (TBD...)
+type MatchingResumeTaskListRequest struct {
+	DomainUUID   string        `json:"domainUUID,omitempty"`
+	TaskList     *TaskList     `json:"taskList,omitempty"`
+	TaskListType *TaskListType `json:"taskListType,omitempty"`
+}
+
+// GetDomainUUID is an internal getter (TBD...)
+func (v *MatchingResumeTaskListRequest) GetDomainUUID() (o string) {
+	if v != nil {
+		return v.DomainUUID
+	}
+	return
+}
+
+// GetTaskList is an internal getter (TBD...)
+func (v *MatchingResumeTaskListRequest) GetTaskList() (o *TaskList) {
+	if v != nil && v.TaskList != nil {
+		return v.TaskList
+	}
+	return
+}
+
+// GetTaskListType is an internal getter (TBD...)
+func (v *MatchingResumeTaskListRequest) GetTaskListType() (o TaskListType) {
+	if v != nil && v.TaskListType != nil {
+		return *v.TaskListType
+	}
+	return
+}
+
+// MatchingRecordActivityTaskFinishedRequest is an internal type (TBD...)
+type MatchingRecordActivityTaskFinishedRequest struct {
+	DomainUUID        string             `json:"domainUUID,omitempty"`
+	TaskList          *TaskList          `json:"taskList,omitempty"`
+	WorkflowExecution *WorkflowExecution `json:"workflowExecution,omitempty"`
+	ScheduleID        int64              `json:"scheduleId,omitempty"`
+	Attempt           int64              `json:"attempt,omitempty"`
+}
+
+// GetDomainUUID is an internal getter (TBD...)
+func (v *MatchingRecordActivityTaskFinishedRequest) GetDomainUUID() (o string) {
+	if v != nil {
+		return v.DomainUUID
+	}
+	return
+}
+
+// GetTaskList is an internal getter (TBD...)
+func (v *MatchingRecordActivityTaskFinishedRequest) GetTaskList() (o *TaskList) {
+	if v != nil && v.TaskList != nil {
+		return v.TaskList
+	}
+	return
+}
+
+// GetWorkflowExecution is an internal getter (TBD...)
+func (v *MatchingRecordActivityTaskFinishedRequest) GetWorkflowExecution() (o *WorkflowExecution) {
+	if v != nil && v.WorkflowExecution != nil {
+		return v.WorkflowExecution
+	}
+	return
+}
+
+// GetScheduleID is an internal getter (TBD...)
+func (v *MatchingRecordActivityTaskFinishedRequest) GetScheduleID() (o int64) {
+	if v != nil {
+		return v.ScheduleID
+	}
+	return
+}
+
+// GetAttempt is an internal getter (TBD...)
+func (v *MatchingRecordActivityTaskFinishedRequest) GetAttempt() (o int64) {
+	if v != nil {
+		return v.Attempt
+	}
+	return
+}
+
+// MatchingGetTaskListDrainStatusRequest is an internal type (TBD...)
+type MatchingGetTaskListDrainStatusRequest struct {
+	DomainUUID   string        `json:"domainUUID,omitempty"`
+	TaskList     *TaskList     `json:"taskList,omitempty"`
+	TaskListType *TaskListType `json:"taskListType,omitempty"`
+}
+
+// GetDomainUUID is an internal getter (TBD...)
+func (v *MatchingGetTaskListDrainStatusRequest) GetDomainUUID() (o string) {
+	if v != nil {
+		return v.DomainUUID
+	}
+	return
+}
+
+// GetTaskList is an internal getter (TBD...)
+func (v *MatchingGetTaskListDrainStatusRequest) GetTaskList() (o *TaskList) {
+	if v != nil && v.TaskList != nil {
+		return v.TaskList
+	}
+	return
+}
+
+// GetTaskListType is an internal getter (TBD...)
+func (v *MatchingGetTaskListDrainStatusRequest) GetTaskListType() (o TaskListType) {
+	if v != nil && v.TaskListType != nil {
+		return *v.TaskListType
+	}
+	return
+}
@@ -391,6 +391,9 @@ type MergeDLQMessagesRequest struct {
 	InclusiveEndMessageID *int64   `json:"inclusiveEndMessageID,omitempty"`
 	MaximumPageSize       int32    `json:"maximumPageSize,omitempty"`
 	NextPageToken         []byte   `json:"nextPageToken,omitempty"`
+	// InclusiveEndTimestamp, if set, limits the messages to those created at or before this unix nano timestamp,
+	// it is not part of the IDL yet
+	InclusiveEndTimestamp *int64 `json:"inclusiveEndTimestamp,omitempty"`
 }
 
 // GetType is an internal getter (TBD...)
@@ -425,6 +428,14 @@ func (v *MergeDLQMessagesRequest) GetInclusiveEndMessageID() (o int64) {
 	return
 }
 
+// GetInclusiveEndTimestamp is an internal getter (TBD...)
+func (v *MergeDLQMessagesRequest) GetInclusiveEndTimestamp() (o int64) {
+	if v != nil && v.InclusiveEndTimestamp != nil {
+		return *v.InclusiveEndTimestamp
+	}
+	return
+}
+
 // GetMaximumPageSize is an internal getter (TBD...)
 func (v *MergeDLQMessagesRequest) GetMaximumPageSize() (o int32) {
 	if v != nil {
@@ -452,6 +463,9 @@ type PurgeDLQMessagesRequest struct {
 	ShardID               int32    `json:"shardID,omitempty"`
 	SourceCluster         string   `json:"sourceCluster,omitempty"`
 	InclusiveEndMessageID *int64   `json:"inclusiveEndMessageID,omitempty"`
+	// InclusiveEndTimestamp, if set, limits the messages to those created at or before this unix nano timestamp,
+	// it is not part of the IDL yet
+	InclusiveEndTimestamp *int64 `json:"inclusiveEndTimestamp,omitempty"`
 }
 
 // GetType is an internal getter (TBD...)
@@ -486,6 +500,14 @@ func (v *PurgeDLQMessagesRequest) GetInclusiveEndMessageID() (o int64) {
 	return
 }
 
+// GetInclusiveEndTimestamp is an internal getter (TBD...)
+func (v *PurgeDLQMessagesRequest) GetInclusiveEndTimestamp() (o int64) {
+	if v != nil && v.InclusiveEndTimestamp != nil {
+		return *v.InclusiveEndTimestamp
+	}
+	return
+}
+
 // ReadDLQMessagesRequest is an internal type (TBD...)
 type ReadDLQMessagesRequest struct {
 	Type                  *DLQType `json:"type,omitempty"`
@@ -2561,6 +2561,14 @@ type Header struct {
 	Fields map[string][]byte `json:"fields,omitempty"`
 }
 
+// GetFields is an internal getter (TBD...)
+func (v *Header) GetFields() (o map[string][]byte) {
+	if v != nil && v.Fields != nil {
+		return v.Fields
+	}
+	return
+}
+
 // History is an internal type (TBD...)
 type History struct {
 	Events []*HistoryEvent `json:"events,omitempty"`
@@ -3929,9 +3937,14 @@ func (v *PollForDecisionTaskResponse) GetNextEventID() (o int64) {
 
 // PollerInfo is an internal type (TBD...)
 type PollerInfo struct {
-	LastAccessTime *int64  `json:"lastAccessTime,omitempty"`
-	Identity       string  `json:"identity,omitempty"`
-	RatePerSecond  float64 `json:"ratePerSecond,omitempty"`
+	LastAccessTime       *int64   `json:"lastAccessTime,omitempty"`
+	Identity             string   `json:"identity,omitempty"`
+	RatePerSecond        float64  `json:"ratePerSecond,omitempty"`
+	BuildID              string   `json:"buildID,omitempty"`
+	ClientImpl           string   `json:"clientImpl,omitempty"`
+	ClientFeatureVersion string   `json:"clientFeatureVersion,omitempty"`
+	Capabilities         []string `json:"capabilities,omitempty"`
+	ConcurrentPollCount  int32    `json:"concurrentPollCount,omitempty"`
 }
 
 // GetLastAccessTime is an internal getter (TBD...)
@@ -3958,6 +3971,46 @@ func (v *PollerInfo) GetRatePerSecond() (o float64) {
 	return
 }
 
+// GetBuildID is an internal getter (TBD...)
+func (v *PollerInfo) GetBuildID() (o string) {
+	if v != nil {
+		return v.BuildID
+	}
+	return
+}
+
+// GetClientImpl is an internal getter (TBD...)
+func (v *PollerInfo) GetClientImpl() (o string) {
+	if v != nil {
+		return v.ClientImpl
+	}
+	return
+}
+
+// GetClientFeatureVersion is an internal getter (TBD...)
+func (v *PollerInfo) GetClientFeatureVersion() (o string) {
+	if v != nil {
+		return v.ClientFeatureVersion
+	}
+	return
+}
+
+// GetCapabilities is an internal getter (TBD...)
+func (v *PollerInfo) GetCapabilities() (o []string) {
+	if v != nil {
+		return v.Capabilities
+	}
+	return
+}
+
+// GetConcurrentPollCount is an internal getter (TBD...)
+func (v *PollerInfo) GetConcurrentPollCount() (o int32) {
+	if v != nil {
+		return v.ConcurrentPollCount
+	}
+	return
+}
+
 // QueryConsistencyLevel is an internal type (TBD...)
 type QueryConsistencyLevel int32
 
@@ -4808,6 +4861,12 @@ type ResetWorkflowExecutionRequest struct {
 	DecisionFinishEventID int64              `json:"decisionFinishEventId,omitempty"`
 	RequestID             string             `json:"requestId,omitempty"`
 	SkipSignalReapply     bool               `json:"skipSignalReapply,omitempty"`
+	// ReapplySignalNames, if not empty, limits the signals re-applied after the reset point to those with these names,
+	// it is not part of the IDL yet
+	ReapplySignalNames []string `json:"reapplySignalNames,omitempty"`
+	// SkipReapplySignalNames are the names of the signals not to re-apply after the reset point,
+	// it is not part of the IDL yet
+	SkipReapplySignalNames []string `json:"skipReapplySignalNames,omitempty"`
 }
 
 // GetDomain is an internal getter (TBD...)
@@ -4858,6 +4917,22 @@ func (v *ResetWorkflowExecutionRequest) GetSkipSignalReapply() (o bool) {
 	return
 }
 
+// GetReapplySignalNames is an internal getter (TBD...)
+func (v *ResetWorkflowExecutionRequest) GetReapplySignalNames() (o []string) {
+	if v != nil {
+		return v.ReapplySignalNames
+	}
+	return
+}
+
+// GetSkipReapplySignalNames is an internal getter (TBD...)
+func (v *ResetWorkflowExecutionRequest) GetSkipReapplySignalNames() (o []string) {
+	if v != nil {
+		return v.SkipReapplySignalNames
+	}
+	return
+}
+
 // ResetWorkflowExecutionResponse is an internal type (TBD...)
 type ResetWorkflowExecutionResponse struct {
 	RunID string `json:"runId,omitempty"`
@@ -5960,6 +6035,9 @@ type StartWorkflowExecutionRequest struct {
 	Header                              *Header                `json:"header,omitempty"`
 	DelayStartSeconds                   *int32                 `json:"delayStartSeconds,omitempty"`
 	JitterStartSeconds                  *int32                 `json:"jitterStartSeconds,omitempty"`
+	// FirstRunAtTimestamp, if set, delays the first decision task until this unix nano timestamp, instead of by
+	// DelayStartSeconds, it is not part of the IDL yet and is carried in the FirstRunAtTimestampHeader of Header
+	FirstRunAtTimestamp *int64 `json:"firstRunAtTimestamp,omitempty"`
 }
 
 // GetDomain is an internal getter (TBD...)
@@ -6010,6 +6088,53 @@ func (v *StartWorkflowExecutionRequest) GetJitterStartSeconds() (o int32) {
 	return
 }
 
+// GetFirstRunAtTimestamp is an internal getter (TBD...)
+func (v *StartWorkflowExecutionRequest) GetFirstRunAtTimestamp() (o int64) {
+	if v != nil && v.FirstRunAtTimestamp != nil {
+		return *v.FirstRunAtTimestamp
+	}
+	return
+}
+
+// FirstRunAtTimestampHeader is the header of the start request carrying FirstRunAtTimestamp over the wire,
+// as the field is not part of the IDL yet
+const FirstRunAtTimestampHeader = "cadence-first-run-at-timestamp"
+
+// AddFirstRunAtTimestampHeader returns a copy of the header with the first run timestamp added to it
+func AddFirstRunAtTimestampHeader(header *Header, firstRunAtTimestamp *int64) *Header {
+	if firstRunAtTimestamp == nil {
+		return header
+	}
+	fields := make(map[string][]byte, len(header.GetFields())+1)
+	for key, value := range header.GetFields() {
+		fields[key] = value
+	}
+	fields[FirstRunAtTimestampHeader] = []byte(strconv.FormatInt(*firstRunAtTimestamp, 10))
+	return &Header{Fields: fields}
+}
+
+// RemoveFirstRunAtTimestampHeader returns a copy of the header without the first run timestamp, and the timestamp
+func RemoveFirstRunAtTimestampHeader(header *Header) (*Header, *int64) {
+	value, ok := header.GetFields()[FirstRunAtTimestampHeader]
+	if !ok {
+		return header, nil
+	}
+	firstRunAtTimestamp, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return header, nil
+	}
+	if len(header.Fields) == 1 {
+		return nil, &firstRunAtTimestamp
+	}
+	fields := make(map[string][]byte, len(header.Fields)-1)
+	for key, value := range header.Fields {
+		if key != FirstRunAtTimestampHeader {
+			fields[key] = value
+		}
+	}
+	return &Header{Fields: fields}, &firstRunAtTimestamp
+}
+
 // GetRequestID is an internal getter (TBD...)
 func (v *StartWorkflowExecutionRequest) GetRequestID() (o string) {
 	if v != nil {
@@ -6209,6 +6334,23 @@ type TaskListStatus struct {
 	AckLevel         int64        `json:"ackLevel,omitempty"`
 	RatePerSecond    float64      `json:"ratePerSecond,omitempty"`
 	TaskIDBlock      *TaskIDBlock `json:"taskIDBlock,omitempty"`
+	// the backlog statistics below are computed by matching over a sliding window, they are not part of the IDL yet
+	// and are only set when the task list is described within the matching service.
+	// EstimatedDrainTimeSeconds is -1 when tasks are added at least as fast as they are dispatched
+	BacklogAgeSeconds         float64 `json:"backlogAgeSeconds,omitempty"`
+	TasksAddedPerSecond       float64 `json:"tasksAddedPerSecond,omitempty"`
+	TasksDispatchedPerSecond  float64 `json:"tasksDispatchedPerSecond,omitempty"`
+	EstimatedDrainTimeSeconds float64 `json:"estimatedDrainTimeSeconds,omitempty"`
+	// Draining is set while the task list rejects new tasks, Drained once all the tasks written to the partition are acked
+	Draining bool `json:"draining,omitempty"`
+	Drained  bool `json:"drained,omitempty"`
+	// Paused is set while an operator stopped the dispatch of the tasks of the task list
+	Paused bool `json:"paused,omitempty"`
+	// average time taken over the same window to match a task with a poller, split by the tasks sync matched
+	// with a poller of the partition, the ones forwarded to and matched on a parent partition and the backlog tasks
+	LocalSyncMatchLatencySeconds     float64 `json:"localSyncMatchLatencySeconds,omitempty"`
+	ForwardedSyncMatchLatencySeconds float64 `json:"forwardedSyncMatchLatencySeconds,omitempty"`
+	BacklogDispatchLatencySeconds    float64 `json:"backlogDispatchLatencySeconds,omitempty"`
 }
 
 // GetBacklogCountHint is an internal getter (TBD...)
@@ -6251,6 +6393,86 @@ func (v *TaskListStatus) GetTaskIDBlock() (o *TaskIDBlock) {
 	return
 }
 
+// GetBacklogAgeSeconds is an internal getter (TBD...)
+func (v *TaskListStatus) GetBacklogAgeSeconds() (o float64) {
+	if v != nil {
+		return v.BacklogAgeSeconds
+	}
+	return
+}
+
+// GetTasksAddedPerSecond is an internal getter (TBD...)
+func (v *TaskListStatus) GetTasksAddedPerSecond() (o float64) {
+	if v != nil {
+		return v.TasksAddedPerSecond
+	}
+	return
+}
+
+// GetTasksDispatchedPerSecond is an internal getter (TBD...)
+func (v *TaskListStatus) GetTasksDispatchedPerSecond() (o float64) {
+	if v != nil {
+		return v.TasksDispatchedPerSecond
+	}
+	return
+}
+
+// GetEstimatedDrainTimeSeconds is an internal getter (TBD...)
+func (v *TaskListStatus) GetEstimatedDrainTimeSeconds() (o float64) {
+	if v != nil {
+		return v.EstimatedDrainTimeSeconds
+	}
+	return
+}
+
+// GetDraining is an internal getter (TBD...)
+func (v *TaskListStatus) GetDraining() (o bool) {
+	if v != nil {
+		return v.Draining
+	}
+	return
+}
+
+// GetDrained is an internal getter (TBD...)
+func (v *TaskListStatus) GetDrained() (o bool) {
+	if v != nil {
+		return v.Drained
+	}
+	return
+}
+
+// GetPaused is an internal getter (TBD...)
+func (v *TaskListStatus) GetPaused() (o bool) {
+	if v != nil {
+		return v.Paused
+	}
+	return
+}
+
+// GetLocalSyncMatchLatencySeconds is an internal getter (TBD...)
+func (v *TaskListStatus) GetLocalSyncMatchLatencySeconds() (o float64) {
+	if v != nil {
+		return v.LocalSyncMatchLatencySeconds
+	}
+	return
+}
+
+// GetForwardedSyncMatchLatencySeconds is an internal getter (TBD...)
+func (v *TaskListStatus) GetForwardedSyncMatchLatencySeconds() (o float64) {
+	if v != nil {
+		return v.ForwardedSyncMatchLatencySeconds
+	}
+	return
+}
+
+// GetBacklogDispatchLatencySeconds is an internal getter (TBD...)
+func (v *TaskListStatus) GetBacklogDispatchLatencySeconds() (o float64) {
+	if v != nil {
+		return v.BacklogDispatchLatencySeconds
+	}
+	return
+}
+
 // TaskListType is an internal type (TBD...)
 type TaskListType int32
 
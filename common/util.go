@@ -92,6 +92,8 @@ const (
 	FailureReasonDecisionBlobSizeExceedsLimit = "DECISION_BLOB_SIZE_EXCEEDS_LIMIT"
 	// FailureReasonSizeExceedsLimit is reason to fail workflow when history size or count exceed limit
 	FailureReasonSizeExceedsLimit = "HISTORY_EXCEEDS_LIMIT"
+	// FailureReasonPendingCountExceedsLimit is reason to fail workflow when its pending activities or child workflows exceed limit
+	FailureReasonPendingCountExceedsLimit = "PENDING_COUNT_EXCEEDS_LIMIT"
 	// FailureReasonTransactionSizeExceedsLimit is the failureReason for when transaction cannot be committed because it exceeds size limit
 	FailureReasonTransactionSizeExceedsLimit = "TRANSACTION_SIZE_EXCEEDS_LIMIT"
 	// FailureReasonDecisionAttemptsExceedsLimit is reason to fail workflow when decision attempts fail too many times
@@ -514,6 +516,16 @@ func ValidateRetryPolicy(policy *types.RetryPolicy) error {
 	return nil
 }
 
+// GetDelayStartSecondsForFirstRunAt returns the delay of a workflow which first runs at the unix nano timestamp,
+// rounded up so that it does not run early. A timestamp in the past means no delay.
+func GetDelayStartSecondsForFirstRunAt(firstRunAtTimestamp int64, now time.Time) int32 {
+	delay := time.Unix(0, firstRunAtTimestamp).Sub(now)
+	if delay <= 0 {
+		return 0
+	}
+	return int32((delay + time.Second - 1) / time.Second)
+}
+
 // CreateHistoryStartWorkflowRequest create a start workflow request for history
 func CreateHistoryStartWorkflowRequest(
 	domainID string,
@@ -526,6 +538,9 @@ func CreateHistoryStartWorkflowRequest(
 	}
 
 	delayStartSeconds := startRequest.GetDelayStartSeconds()
+	if startRequest.FirstRunAtTimestamp != nil {
+		delayStartSeconds = GetDelayStartSecondsForFirstRunAt(startRequest.GetFirstRunAtTimestamp(), now)
+	}
 	jitterStartSeconds := startRequest.GetJitterStartSeconds()
 	firstDecisionTaskBackoffSeconds := delayStartSeconds
 	if len(startRequest.GetCronSchedule()) > 0 {
@@ -893,6 +908,26 @@ func ConvertIntMapToDynamicConfigMapProperty(
 	return dcValue
 }
 
+// GetIntFromDynamicConfigMapProperty returns the value of a key of a map property from dynamic config as an int,
+// 0 if the key is not set or its value is not a number
+func GetIntFromDynamicConfigMapProperty(
+	dcValue map[string]interface{},
+	key string,
+) int {
+	switch value := dcValue[key].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	case int32:
+		return int(value)
+	case int64:
+		return int(value)
+	default:
+		return 0
+	}
+}
+
 // ConvertDynamicConfigMapPropertyToIntMap convert a map property from dynamic config to a map
 // whose type for both key and value are int
 func ConvertDynamicConfigMapPropertyToIntMap(
@@ -89,6 +89,17 @@ func TestConvertDynamicConfigMapPropertyToIntMap(t *testing.T) {
 	}
 }
 
+func TestGetIntFromDynamicConfigMapProperty(t *testing.T) {
+	dcValue := map[string]interface{}{"a": int(1), "b": int32(2), "c": int64(3), "d": float64(4.0), "e": "5"}
+	require.Equal(t, 1, GetIntFromDynamicConfigMapProperty(dcValue, "a"))
+	require.Equal(t, 2, GetIntFromDynamicConfigMapProperty(dcValue, "b"))
+	require.Equal(t, 3, GetIntFromDynamicConfigMapProperty(dcValue, "c"))
+	require.Equal(t, 4, GetIntFromDynamicConfigMapProperty(dcValue, "d"))
+	require.Equal(t, 0, GetIntFromDynamicConfigMapProperty(dcValue, "e"))
+	require.Equal(t, 0, GetIntFromDynamicConfigMapProperty(dcValue, "f"))
+	require.Equal(t, 0, GetIntFromDynamicConfigMapProperty(nil, "a"))
+}
+
 func TestCreateHistoryStartWorkflowRequest_ExpirationTimeWithCron(t *testing.T) {
 	domainID := uuid.New()
 	request := &types.StartWorkflowExecutionRequest{
@@ -255,6 +266,24 @@ func testExpirationTime(t *testing.T, delayStartSeconds int, cronSeconds int, ji
 	)
 }
 
+func TestGetDelayStartSecondsForFirstRunAt(t *testing.T) {
+	now := time.Unix(1000, 0)
+	assert.Equal(t, int32(0), GetDelayStartSecondsForFirstRunAt(now.Add(-time.Minute).UnixNano(), now))
+	assert.Equal(t, int32(0), GetDelayStartSecondsForFirstRunAt(now.UnixNano(), now))
+	assert.Equal(t, int32(60), GetDelayStartSecondsForFirstRunAt(now.Add(time.Minute).UnixNano(), now))
+	assert.Equal(t, int32(61), GetDelayStartSecondsForFirstRunAt(now.Add(time.Minute+time.Millisecond).UnixNano(), now))
+}
+
+func TestCreateHistoryStartWorkflowRequest_FirstRunAt(t *testing.T) {
+	now := time.Now()
+	request := &types.StartWorkflowExecutionRequest{
+		FirstRunAtTimestamp: Int64Ptr(now.Add(100 * time.Second).UnixNano()),
+	}
+	startRequest, err := CreateHistoryStartWorkflowRequest(uuid.New(), request, now)
+	require.NoError(t, err)
+	require.Equal(t, int32(100), startRequest.GetFirstDecisionTaskBackoffSeconds())
+}
+
 func TestCreateHistoryStartWorkflowRequest_ExpirationTimeWithoutCron(t *testing.T) {
 	domainID := uuid.New()
 	request := &types.StartWorkflowExecutionRequest{
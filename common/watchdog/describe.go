@@ -0,0 +1,59 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package watchdog
+
+import (
+	"github.com/uber/cadence/common/types"
+)
+
+// Describe returns the dump of the watchdog as served by the admin API, it is empty if there is no watchdog
+func Describe(w *Watchdog) *types.DescribeWatchdogResponse {
+	if w == nil {
+		return &types.DescribeWatchdogResponse{}
+	}
+
+	dump := w.Dump()
+	response := &types.DescribeWatchdogResponse{
+		Service:   dump.Service,
+		Resources: make([]*types.WatchdogResource, 0, len(dump.Resources)),
+	}
+	for _, r := range dump.Resources {
+		resource := &types.WatchdogResource{
+			Name:        r.Name,
+			Value:       int64(r.Value),
+			LeakSuspect: r.LeakSuspect,
+		}
+		for _, sample := range r.Samples {
+			resource.Samples = append(resource.Samples, &types.WatchdogSample{
+				Timestamp: sample.Time.UnixNano(),
+				Value:     int64(sample.Value),
+				Load:      int64(sample.Load),
+			})
+		}
+		for _, owner := range r.Owners {
+			resource.Owners = append(resource.Owners, &types.WatchdogResourceOwner{Name: owner.Name, Count: int64(owner.Count)})
+		}
+		response.Resources = append(response.Resources, resource)
+	}
+	return response
+}
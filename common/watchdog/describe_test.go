@@ -0,0 +1,79 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package watchdog
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestGoroutineOwners(t *testing.T) {
+	stopC := make(chan struct{})
+	defer close(stopC)
+	var startWG sync.WaitGroup
+	startWG.Add(3)
+	for i := 0; i < 3; i++ {
+		go blockUntilClosed(&startWG, stopC)
+	}
+	startWG.Wait()
+
+	owners := make(map[string]int)
+	for _, owner := range goroutineOwners() {
+		owners[owner.Name] = owner.Count
+	}
+	assert.Equal(t, 3, owners["github.com/uber/cadence/common/watchdog.blockUntilClosed"], owners)
+}
+
+func TestDescribe(t *testing.T) {
+	w := NewWatchdog(
+		"history",
+		NewConfig(dynamicconfig.NewNopCollection()),
+		metrics.NewNoopMetricsClient(),
+		clock.NewRealTimeSource(),
+		loggerimpl.NewNopLogger(),
+	)
+	w.Register("goroutines", NewGoroutineProbe())
+
+	response := Describe(w)
+	assert.Equal(t, "history", response.Service)
+	require.Len(t, response.Resources, 1)
+	assert.Equal(t, "goroutines", response.Resources[0].Name)
+	assert.NotZero(t, response.Resources[0].Value)
+	assert.NotEmpty(t, response.Resources[0].Owners)
+
+	assert.Equal(t, &types.DescribeWatchdogResponse{}, Describe(nil))
+}
+
+func blockUntilClosed(startWG *sync.WaitGroup, stopC chan struct{}) {
+	startWG.Done()
+	<-stopC
+}
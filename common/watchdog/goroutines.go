@@ -0,0 +1,74 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package watchdog
+
+import (
+	"runtime"
+	"strings"
+)
+
+// goroutineExitFunction is the bottom frame of the stack of all goroutines
+const goroutineExitFunction = "runtime.goexit"
+
+// NewGoroutineProbe creates a probe of the goroutines of the process, owned by the function they were started with
+func NewGoroutineProbe() Probe {
+	return Probe{
+		Value:  runtime.NumGoroutine,
+		Owners: goroutineOwners,
+	}
+}
+
+func goroutineOwners() []Owner {
+	// the number of goroutines may grow between the calls, the profile is retried with some headroom
+	records := make([]runtime.StackRecord, runtime.NumGoroutine()+10)
+	n, ok := runtime.GoroutineProfile(records)
+	for !ok {
+		records = make([]runtime.StackRecord, n+10)
+		n, ok = runtime.GoroutineProfile(records)
+	}
+
+	counts := make(map[string]int)
+	for _, record := range records[:n] {
+		counts[goroutineEntryFunction(record.Stack())]++
+	}
+	owners := make([]Owner, 0, len(counts))
+	for name, count := range counts {
+		owners = append(owners, Owner{Name: name, Count: count})
+	}
+	return owners
+}
+
+// goroutineEntryFunction returns the function a goroutine was started with, which is the bottom frame of its stack
+func goroutineEntryFunction(stack []uintptr) string {
+	entry := "unknown"
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" && !strings.HasPrefix(frame.Function, goroutineExitFunction) {
+			entry = frame.Function
+		}
+		if !more {
+			return entry
+		}
+	}
+}
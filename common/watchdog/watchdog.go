@@ -0,0 +1,324 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package watchdog samples the long-lived resources of a host, such as goroutines, task list managers, caches and
+// shard contexts, and reports the ones growing monotonically faster than the load of the host as leak suspects
+package watchdog
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+)
+
+const (
+	// disabledCheckInterval is the interval the sample interval is checked at while the watchdog is disabled
+	disabledCheckInterval = time.Minute
+	// maxOwners is the number of largest owners listed by a dump
+	maxOwners = 20
+)
+
+type (
+	// Probe reads the number of instances of a resource
+	Probe struct {
+		// Value returns the current number of instances of the resource
+		Value func() int
+		// Load optionally returns the current load the resource is expected to grow with, such as the number of task lists
+		// for the goroutines of the task lists. A resource growing with its load is not suspected to leak.
+		Load func() int
+		// Owners optionally lists the number of instances of the resource by owner, such as by domain
+		Owners func() []Owner
+	}
+
+	// Owner is the number of instances of a resource held by an owner
+	Owner struct {
+		Name  string
+		Count int
+	}
+
+	// Config is the config of the watchdog
+	Config struct {
+		SampleInterval  dynamicconfig.DurationPropertyFn
+		WindowSize      dynamicconfig.IntPropertyFn
+		GrowthThreshold dynamicconfig.FloatPropertyFn
+	}
+
+	// Sample is the number of instances and the load of a resource at a point in time
+	Sample struct {
+		Time  time.Time
+		Value int
+		Load  int `json:",omitempty"`
+	}
+
+	// ResourceDump describes a resource tracked by the watchdog
+	ResourceDump struct {
+		Name        string
+		Value       int
+		LeakSuspect bool
+		Samples     []Sample
+		Owners      []Owner `json:",omitempty"`
+	}
+
+	// Dump describes the resources tracked by the watchdog of a service
+	Dump struct {
+		Service   string
+		Resources []ResourceDump
+	}
+
+	// Watchdog periodically samples the resources registered by a service and reports the leak suspects
+	Watchdog struct {
+		status        int32
+		serviceName   string
+		config        *Config
+		metricsClient metrics.Client
+		timeSource    clock.TimeSource
+		logger        log.Logger
+
+		sync.Mutex
+		resources map[string]*resource
+
+		shutdownC  chan struct{}
+		shutdownWG sync.WaitGroup
+	}
+
+	resource struct {
+		probe   Probe
+		samples []Sample
+		suspect bool
+	}
+)
+
+// NewConfig creates the watchdog config from dynamic config
+func NewConfig(dc *dynamicconfig.Collection) *Config {
+	return &Config{
+		SampleInterval:  dc.GetDurationProperty(dynamicconfig.WatchdogSampleInterval),
+		WindowSize:      dc.GetIntProperty(dynamicconfig.WatchdogWindowSize),
+		GrowthThreshold: dc.GetFloat64Property(dynamicconfig.WatchdogGrowthThreshold),
+	}
+}
+
+// NewWatchdog creates a new watchdog for the resources of a service
+func NewWatchdog(
+	serviceName string,
+	config *Config,
+	metricsClient metrics.Client,
+	timeSource clock.TimeSource,
+	logger log.Logger,
+) *Watchdog {
+	return &Watchdog{
+		status:        common.DaemonStatusInitialized,
+		serviceName:   serviceName,
+		config:        config,
+		metricsClient: metricsClient,
+		timeSource:    timeSource,
+		logger:        logger,
+		resources:     make(map[string]*resource),
+		shutdownC:     make(chan struct{}),
+	}
+}
+
+// Register starts tracking a resource, a resource registered with the same name is replaced
+func (w *Watchdog) Register(name string, probe Probe) {
+	if w == nil {
+		return
+	}
+	w.Lock()
+	defer w.Unlock()
+	w.resources[name] = &resource{probe: probe}
+}
+
+// Start starts sampling the resources
+func (w *Watchdog) Start() {
+	if !atomic.CompareAndSwapInt32(&w.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	w.shutdownWG.Add(1)
+	go w.sampleLoop()
+}
+
+// Stop stops sampling the resources
+func (w *Watchdog) Stop() {
+	if !atomic.CompareAndSwapInt32(&w.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+
+	close(w.shutdownC)
+	w.shutdownWG.Wait()
+}
+
+// Dump describes the resources tracked by the watchdog, with their largest owners
+func (w *Watchdog) Dump() Dump {
+	w.Lock()
+	names := make([]string, 0, len(w.resources))
+	resources := make(map[string]ResourceDump, len(w.resources))
+	probes := make(map[string]Probe, len(w.resources))
+	for name, r := range w.resources {
+		names = append(names, name)
+		resources[name] = ResourceDump{
+			Name:        name,
+			LeakSuspect: r.suspect,
+			Samples:     append([]Sample(nil), r.samples...),
+		}
+		probes[name] = r.probe
+	}
+	w.Unlock()
+	sort.Strings(names)
+
+	// the probes are read outside of the lock as they may take the locks of the resource owners
+	dump := Dump{Service: w.serviceName, Resources: make([]ResourceDump, 0, len(names))}
+	for _, name := range names {
+		r := resources[name]
+		r.Value = probes[name].Value()
+		if probes[name].Owners != nil {
+			r.Owners = largestOwners(probes[name].Owners(), maxOwners)
+		}
+		dump.Resources = append(dump.Resources, r)
+	}
+	return dump
+}
+
+func (w *Watchdog) sampleLoop() {
+	defer w.shutdownWG.Done()
+
+	timer := time.NewTimer(w.nextInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			if w.config.SampleInterval() > 0 {
+				w.sample()
+			}
+			timer.Reset(w.nextInterval())
+		case <-w.shutdownC:
+			return
+		}
+	}
+}
+
+func (w *Watchdog) nextInterval() time.Duration {
+	if interval := w.config.SampleInterval(); interval > 0 {
+		return interval
+	}
+	return disabledCheckInterval
+}
+
+func (w *Watchdog) sample() {
+	w.Lock()
+	probes := make(map[string]Probe, len(w.resources))
+	for name, r := range w.resources {
+		probes[name] = r.probe
+	}
+	w.Unlock()
+
+	now := w.timeSource.Now()
+	samples := make(map[string]Sample, len(probes))
+	for name, probe := range probes {
+		s := Sample{Time: now, Value: probe.Value()}
+		if probe.Load != nil {
+			s.Load = probe.Load()
+		}
+		samples[name] = s
+	}
+
+	windowSize := w.config.WindowSize()
+	threshold := w.config.GrowthThreshold()
+	w.Lock()
+	defer w.Unlock()
+	for name, s := range samples {
+		r, ok := w.resources[name]
+		if !ok {
+			continue
+		}
+		r.samples = append(r.samples, s)
+		if len(r.samples) > windowSize {
+			r.samples = r.samples[len(r.samples)-windowSize:]
+		}
+		suspect := isLeakSuspect(r.samples, windowSize, threshold)
+		if suspect && !r.suspect {
+			// the timestamp is the start of the window the resource grew over
+			w.logger.Warn("Resource leak suspected",
+				tag.Name(name),
+				tag.Value(s.Value),
+				tag.Timestamp(r.samples[0].Time),
+			)
+		}
+		r.suspect = suspect
+
+		scope := w.metricsClient.Scope(metrics.WatchdogScope, metrics.WatchdogResourceTag(name))
+		scope.UpdateGauge(metrics.WatchdogResourceGauge, float64(s.Value))
+		if suspect {
+			scope.UpdateGauge(metrics.WatchdogLeakSuspectGauge, 1)
+		} else {
+			scope.UpdateGauge(metrics.WatchdogLeakSuspectGauge, 0)
+		}
+	}
+}
+
+// isLeakSuspect returns true if the resource never decreased over a full window of samples, and grew by more than
+// the threshold beyond the growth of its load
+func isLeakSuspect(samples []Sample, windowSize int, threshold float64) bool {
+	if windowSize < 2 || len(samples) < windowSize {
+		return false
+	}
+	for i := 1; i < len(samples); i++ {
+		if samples[i].Value < samples[i-1].Value {
+			return false
+		}
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	growth := relativeGrowth(first.Value, last.Value)
+	if loadGrowth := relativeGrowth(first.Load, last.Load); loadGrowth > 0 {
+		growth -= loadGrowth
+	}
+	return growth > threshold
+}
+
+func relativeGrowth(from, to int) float64 {
+	base := from
+	if base < 1 {
+		base = 1
+	}
+	return float64(to-from) / float64(base)
+}
+
+func largestOwners(owners []Owner, n int) []Owner {
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].Count != owners[j].Count {
+			return owners[i].Count > owners[j].Count
+		}
+		return owners[i].Name < owners[j].Name
+	})
+	if len(owners) > n {
+		owners = owners[:n]
+	}
+	return owners
+}
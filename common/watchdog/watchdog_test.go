@@ -0,0 +1,170 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package watchdog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/loggerimpl"
+	"github.com/uber/cadence/common/metrics"
+)
+
+func TestIsLeakSuspect(t *testing.T) {
+	samples := func(values ...int) []Sample {
+		result := make([]Sample, 0, len(values))
+		for _, value := range values {
+			result = append(result, Sample{Value: value})
+		}
+		return result
+	}
+
+	tests := map[string]struct {
+		samples  []Sample
+		expected bool
+	}{
+		"window not full": {
+			samples:  samples(10, 20, 30),
+			expected: false,
+		},
+		"monotonic growth": {
+			samples:  samples(10, 12, 12, 16),
+			expected: true,
+		},
+		"growth below threshold": {
+			samples:  samples(10, 11, 12, 14),
+			expected: false,
+		},
+		"decrease in the window": {
+			samples:  samples(10, 20, 15, 30),
+			expected: false,
+		},
+		"growth from zero": {
+			samples:  samples(0, 1, 2, 3),
+			expected: true,
+		},
+		"flat": {
+			samples:  samples(0, 0, 0, 0),
+			expected: false,
+		},
+		"growth with the load": {
+			samples:  []Sample{{Value: 100, Load: 10}, {Value: 150, Load: 15}, {Value: 200, Load: 20}, {Value: 200, Load: 20}},
+			expected: false,
+		},
+		"growth faster than the load": {
+			samples:  []Sample{{Value: 100, Load: 10}, {Value: 150, Load: 11}, {Value: 200, Load: 12}, {Value: 300, Load: 12}},
+			expected: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isLeakSuspect(test.samples, 4, 0.5))
+		})
+	}
+}
+
+func TestSample(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(0, 0))
+	w := NewWatchdog(
+		"test",
+		&Config{
+			SampleInterval:  dynamicconfig.GetDurationPropertyFn(time.Minute),
+			WindowSize:      dynamicconfig.GetIntPropertyFn(3),
+			GrowthThreshold: dynamicconfig.GetFloatPropertyFn(0.5),
+		},
+		metrics.NewClient(scope, metrics.Common),
+		timeSource,
+		loggerimpl.NewNopLogger(),
+	)
+
+	leaking, stable := 10, 10
+	w.Register("leaking", Probe{Value: func() int { return leaking }})
+	w.Register("stable", Probe{
+		Value: func() int { return stable },
+		Owners: func() []Owner {
+			return []Owner{{Name: "small", Count: 1}, {Name: "large", Count: 9}}
+		},
+	})
+
+	for i := 0; i < 4; i++ {
+		w.sample()
+		leaking += 10
+		timeSource.Update(timeSource.Now().Add(time.Minute))
+	}
+
+	dump := w.Dump()
+	assert.Equal(t, "test", dump.Service)
+	require.Len(t, dump.Resources, 2)
+
+	leakingDump := dump.Resources[0]
+	assert.Equal(t, "leaking", leakingDump.Name)
+	assert.Equal(t, 50, leakingDump.Value)
+	assert.True(t, leakingDump.LeakSuspect)
+	require.Len(t, leakingDump.Samples, 3)
+	assert.Equal(t, 20, leakingDump.Samples[0].Value)
+	assert.Equal(t, time.Minute.Nanoseconds(), leakingDump.Samples[0].Time.UnixNano())
+
+	stableDump := dump.Resources[1]
+	assert.Equal(t, "stable", stableDump.Name)
+	assert.False(t, stableDump.LeakSuspect)
+	assert.Equal(t, []Owner{{Name: "large", Count: 9}, {Name: "small", Count: 1}}, stableDump.Owners)
+
+	gauges := make(map[string]float64)
+	for _, gauge := range scope.Snapshot().Gauges() {
+		gauges[gauge.Name()+"/"+gauge.Tags()["watchdog_resource"]] = gauge.Value()
+	}
+	assert.Equal(t, float64(40), gauges["watchdog_resource/leaking"])
+	assert.Equal(t, float64(1), gauges["watchdog_leak_suspect/leaking"])
+	assert.Equal(t, float64(10), gauges["watchdog_resource/stable"])
+	assert.Equal(t, float64(0), gauges["watchdog_leak_suspect/stable"])
+}
+
+func TestStartStop(t *testing.T) {
+	w := NewWatchdog(
+		"test",
+		NewConfig(dynamicconfig.NewNopCollection()),
+		metrics.NewNoopMetricsClient(),
+		clock.NewRealTimeSource(),
+		loggerimpl.NewNopLogger(),
+	)
+	w.Start()
+	w.Stop()
+}
+
+func TestNilWatchdog(t *testing.T) {
+	var w *Watchdog
+	w.Register("goroutines", NewGoroutineProbe())
+}
+
+func TestLargestOwners(t *testing.T) {
+	owners := []Owner{{Name: "b", Count: 2}, {Name: "a", Count: 2}, {Name: "c", Count: 5}, {Name: "d", Count: 1}}
+	assert.Equal(t, []Owner{{Name: "c", Count: 5}, {Name: "a", Count: 2}}, largestOwners(owners, 2))
+}
@@ -22,6 +22,7 @@ package host
 
 import (
 	"go.uber.org/yarpc"
+	yarpcjson "go.uber.org/yarpc/encoding/json"
 
 	"github.com/uber/cadence/.gen/go/admin/adminserviceclient"
 	"github.com/uber/cadence/.gen/go/cadence/workflowserviceclient"
@@ -49,7 +50,8 @@ type HistoryClient interface {
 
 // NewAdminClient creates a client to cadence admin client
 func NewAdminClient(d *yarpc.Dispatcher) AdminClient {
-	return admin.NewThriftClient(adminserviceclient.New(d.ClientConfig(testOutboundName(service.Frontend))))
+	config := d.ClientConfig(testOutboundName(service.Frontend))
+	return admin.NewJSONClient(yarpcjson.New(config), admin.NewThriftClient(adminserviceclient.New(config)))
 }
 
 // NewFrontendClient creates a client to cadence frontend client
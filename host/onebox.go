@@ -819,9 +819,9 @@ func (c *cadenceImpl) newRPCFactory(serviceName string, host membership.HostInfo
 		OutboundsBuilder: rpc.CombineOutbounds(
 			&singleGRPCOutbound{testOutboundName(serviceName), serviceName, grpcAddress},
 			&singleGRPCOutbound{rpc.OutboundPublicClient, service.Frontend, frontendGrpcAddress},
-			rpc.NewCrossDCOutbounds(c.clusterMetadata.GetAllClusterInfo(), rpc.NewDNSPeerChooserFactory(0, c.logger)),
-			rpc.NewDirectOutbound(service.History, true, nil),
-			rpc.NewDirectOutbound(service.Matching, true, nil),
+			rpc.NewCrossDCOutbounds(c.clusterMetadata.GetAllClusterInfo(), rpc.NewDNSPeerChooserFactory(0, 0, c.logger)),
+			rpc.NewDirectOutbound(service.History, true, nil, nil),
+			rpc.NewDirectOutbound(service.Matching, true, nil, nil),
 		),
 	})
 }
@@ -265,3 +265,22 @@ CREATE TABLE queue_metadata (
   data MEDIUMBLOB NOT NULL,
   PRIMARY KEY(queue_type)
 );
+
+CREATE TABLE cluster_config (
+  row_type INT NOT NULL,
+  version BIGINT NOT NULL,
+  --
+  timestamp DATETIME(6) NOT NULL,
+  data MEDIUMBLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (row_type, version)
+);
+
+CREATE TABLE domain_usage (
+  report_hour DATETIME(6) NOT NULL,
+  report_id BINARY(16) NOT NULL,
+  --
+  data MEDIUMBLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (report_hour, report_id)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE domain_usage (
+  report_hour DATETIME(6) NOT NULL,
+  report_id BINARY(16) NOT NULL,
+  --
+  data MEDIUMBLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (report_hour, report_id)
+);
\ No newline at end of file
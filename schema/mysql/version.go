@@ -23,7 +23,7 @@ package mysql
 // NOTE: whenever there is a new data base schema update, plz update the following versions
 
 // Version is the MySQL database release version
-const Version = "0.5"
+const Version = "0.7"
 
 // VisibilityVersion is the MySQL visibility database release version
 const VisibilityVersion = "0.6"
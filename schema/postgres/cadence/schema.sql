@@ -263,3 +263,22 @@ CREATE TABLE queue_metadata (
   data BYTEA NOT NULL,
   PRIMARY KEY(queue_type)
 );
+
+CREATE TABLE cluster_config (
+  row_type INTEGER NOT NULL,
+  version BIGINT NOT NULL,
+  --
+  timestamp TIMESTAMP NOT NULL,
+  data BYTEA NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (row_type, version)
+);
+
+CREATE TABLE domain_usage (
+  report_hour TIMESTAMP NOT NULL,
+  report_id BYTEA NOT NULL,
+  --
+  data BYTEA NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (report_hour, report_id)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE domain_usage (
+  report_hour TIMESTAMP NOT NULL,
+  report_id BYTEA NOT NULL,
+  --
+  data BYTEA NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (report_hour, report_id)
+);
\ No newline at end of file
@@ -0,0 +1,283 @@
+CREATE TABLE IF NOT EXISTS domains(
+  shard_id INTEGER NOT NULL DEFAULT 54321,
+  id BLOB NOT NULL,
+  name VARCHAR(255) UNIQUE NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  is_global BOOLEAN NOT NULL,
+  PRIMARY KEY(shard_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS domain_metadata (
+  notification_version BIGINT NOT NULL
+);
+
+INSERT INTO domain_metadata (notification_version) SELECT 1 WHERE NOT EXISTS (SELECT 1 FROM domain_metadata);
+
+CREATE TABLE IF NOT EXISTS shards (
+  shard_id INTEGER NOT NULL,
+  --
+  range_id BIGINT NOT NULL,
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (shard_id)
+);
+
+CREATE TABLE IF NOT EXISTS transfer_tasks(
+  shard_id INTEGER NOT NULL,
+  task_id BIGINT NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (shard_id, task_id)
+);
+
+CREATE TABLE IF NOT EXISTS cross_cluster_tasks(
+  target_cluster VARCHAR(255) NOT NULL,
+  shard_id INTEGER NOT NULL,
+  task_id BIGINT NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (target_cluster, shard_id, task_id)
+);
+
+CREATE TABLE IF NOT EXISTS executions(
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  run_id BLOB NOT NULL,
+  --
+  next_event_id BIGINT NOT NULL,
+  last_write_version BIGINT NOT NULL,
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (shard_id, domain_id, workflow_id, run_id)
+);
+
+CREATE TABLE IF NOT EXISTS current_executions(
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  --
+  run_id BLOB NOT NULL,
+  create_request_id VARCHAR(64) NOT NULL,
+  state INTEGER NOT NULL,
+  close_status INTEGER NOT NULL,
+  start_version BIGINT NOT NULL,
+  last_write_version BIGINT NOT NULL,
+  PRIMARY KEY (shard_id, domain_id, workflow_id)
+);
+
+CREATE TABLE IF NOT EXISTS buffered_events (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  run_id BLOB NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS buffered_events_by_events_ids ON buffered_events(shard_id, domain_id, workflow_id, run_id);
+
+CREATE TABLE IF NOT EXISTS tasks (
+  domain_id BLOB NOT NULL,
+  task_list_name VARCHAR(255) NOT NULL,
+  task_type SMALLINT NOT NULL, -- {Activity, Decision}
+  task_id BIGINT NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (domain_id, task_list_name, task_type, task_id)
+);
+
+CREATE TABLE IF NOT EXISTS task_lists (
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  task_type SMALLINT NOT NULL, -- {Activity, Decision}
+  --
+  range_id BIGINT NOT NULL,
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (shard_id, domain_id, name, task_type)
+);
+
+CREATE TABLE IF NOT EXISTS replication_tasks (
+  shard_id INTEGER NOT NULL,
+  task_id BIGINT NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (shard_id, task_id)
+);
+
+CREATE TABLE IF NOT EXISTS replication_tasks_dlq (
+  source_cluster_name VARCHAR(255) NOT NULL,
+  shard_id INTEGER NOT NULL,
+  task_id BIGINT NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (source_cluster_name, shard_id, task_id)
+);
+
+CREATE TABLE IF NOT EXISTS timer_tasks (
+  shard_id INTEGER NOT NULL,
+  visibility_timestamp TIMESTAMP NOT NULL,
+  task_id BIGINT NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (shard_id, visibility_timestamp, task_id)
+);
+
+CREATE TABLE IF NOT EXISTS activity_info_maps (
+-- each row corresponds to one key of one map<string, ActivityInfo>
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  run_id BLOB NOT NULL,
+  schedule_id BIGINT NOT NULL,
+--
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16),
+  last_heartbeat_details BLOB,
+  last_heartbeat_updated_time TIMESTAMP NOT NULL,
+  PRIMARY KEY (shard_id, domain_id, workflow_id, run_id, schedule_id)
+);
+
+CREATE TABLE IF NOT EXISTS timer_info_maps (
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  run_id BLOB NOT NULL,
+  timer_id VARCHAR(255) NOT NULL,
+--
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16),
+  PRIMARY KEY (shard_id, domain_id, workflow_id, run_id, timer_id)
+);
+
+CREATE TABLE IF NOT EXISTS child_execution_info_maps (
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  run_id BLOB NOT NULL,
+  initiated_id BIGINT NOT NULL,
+--
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16),
+  PRIMARY KEY (shard_id, domain_id, workflow_id, run_id, initiated_id)
+);
+
+CREATE TABLE IF NOT EXISTS request_cancel_info_maps (
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  run_id BLOB NOT NULL,
+  initiated_id BIGINT NOT NULL,
+--
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16),
+  PRIMARY KEY (shard_id, domain_id, workflow_id, run_id, initiated_id)
+);
+
+CREATE TABLE IF NOT EXISTS signal_info_maps (
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  run_id BLOB NOT NULL,
+  initiated_id BIGINT NOT NULL,
+--
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16),
+  PRIMARY KEY (shard_id, domain_id, workflow_id, run_id, initiated_id)
+);
+
+CREATE TABLE IF NOT EXISTS buffered_replication_task_maps (
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  run_id BLOB NOT NULL,
+  first_event_id BIGINT NOT NULL,
+--
+  version BIGINT NOT NULL,
+  next_event_id BIGINT NOT NULL,
+  history BLOB,
+  history_encoding VARCHAR(16) NOT NULL,
+  new_run_history BLOB,
+  new_run_history_encoding VARCHAR(16) NOT NULL DEFAULT 'json',
+  event_store_version          INTEGER NOT NULL, -- indiciates which version of event store to query
+  new_run_event_store_version  INTEGER NOT NULL, -- indiciates which version of event store to query for new run(continueAsNew)
+  PRIMARY KEY (shard_id, domain_id, workflow_id, run_id, first_event_id)
+);
+
+CREATE TABLE IF NOT EXISTS signals_requested_sets (
+  shard_id INTEGER NOT NULL,
+  domain_id BLOB NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  run_id BLOB NOT NULL,
+  signal_id VARCHAR(64) NOT NULL,
+  --
+  PRIMARY KEY (shard_id, domain_id, workflow_id, run_id, signal_id)
+);
+
+-- history eventsV2: history_node stores history event data
+CREATE TABLE IF NOT EXISTS history_node (
+  shard_id       INTEGER NOT NULL,
+  tree_id        BLOB NOT NULL,
+  branch_id      BLOB NOT NULL,
+  node_id        BIGINT NOT NULL,
+  txn_id         BIGINT NOT NULL,
+  --
+  data           BLOB NOT NULL,
+  data_encoding  VARCHAR(16) NOT NULL,
+  PRIMARY KEY (shard_id, tree_id, branch_id, node_id, txn_id)
+);
+
+-- history eventsV2: history_tree stores branch metadata
+CREATE TABLE IF NOT EXISTS history_tree (
+  shard_id       INTEGER NOT NULL,
+  tree_id        BLOB NOT NULL,
+  branch_id      BLOB NOT NULL,
+  --
+  data           BLOB NOT NULL,
+  data_encoding  VARCHAR(16) NOT NULL,
+  PRIMARY KEY (shard_id, tree_id, branch_id)
+);
+
+CREATE TABLE IF NOT EXISTS queue (
+  queue_type INTEGER NOT NULL,
+  message_id BIGINT NOT NULL,
+  message_payload BLOB NOT NULL,
+  PRIMARY KEY(queue_type, message_id)
+);
+
+CREATE TABLE IF NOT EXISTS queue_metadata (
+  queue_type INTEGER NOT NULL,
+  data BLOB NOT NULL,
+  PRIMARY KEY(queue_type)
+);
+
+CREATE TABLE IF NOT EXISTS cluster_config (
+  row_type INTEGER NOT NULL,
+  version BIGINT NOT NULL,
+  --
+  timestamp TIMESTAMP NOT NULL,
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (row_type, version)
+);
+
+CREATE TABLE IF NOT EXISTS domain_usage (
+  report_hour TIMESTAMP NOT NULL,
+  report_id BLOB NOT NULL,
+  --
+  data BLOB NOT NULL,
+  data_encoding VARCHAR(16) NOT NULL,
+  PRIMARY KEY (report_hour, report_id)
+);
@@ -25,7 +25,6 @@ import (
 
 	"github.com/uber/cadence/common/authorization"
 	"github.com/uber/cadence/common/config"
-	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/resource"
 	"github.com/uber/cadence/common/types"
 )
@@ -42,11 +41,7 @@ var _ AdminHandler = (*AccessControlledWorkflowAdminHandler)(nil)
 // NewAccessControlledAdminHandlerImpl creates frontend handler with authentication support
 func NewAccessControlledAdminHandlerImpl(adminHandler AdminHandler, resource resource.Resource, authorizer authorization.Authorizer, cfg config.Authorization) *AccessControlledWorkflowAdminHandler {
 	if authorizer == nil {
-		var err error
-		authorizer, err = authorization.NewAuthorizer(cfg, resource.GetLogger(), resource.GetDomainCache())
-		if err != nil {
-			resource.GetLogger().Fatal("Error when initiating the Authorizer", tag.Error(err))
-		}
+		authorizer = newAuthorizer(resource, cfg)
 	}
 	return &AccessControlledWorkflowAdminHandler{
 		AdminHandler: adminHandler,
@@ -470,6 +465,70 @@ func (a *AccessControlledWorkflowAdminHandler) ListDynamicConfig(ctx context.Con
 	return a.AdminHandler.ListDynamicConfig(ctx, request)
 }
 
+func (a *AccessControlledWorkflowAdminHandler) UnloadTaskList(ctx context.Context, request *types.AdminUnloadTaskListRequest) error {
+	attr := &authorization.Attributes{
+		APIName:    "UnloadTaskList",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return err
+	}
+	if !isAuthorized {
+		return errUnauthorized
+	}
+
+	return a.AdminHandler.UnloadTaskList(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) GetTaskListDrainStatus(ctx context.Context, request *types.AdminGetTaskListDrainStatusRequest) (*types.GetTaskListDrainStatusResponse, error) {
+	attr := &authorization.Attributes{
+		APIName:    "GetTaskListDrainStatus",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.AdminHandler.GetTaskListDrainStatus(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) PauseTaskList(ctx context.Context, request *types.AdminPauseTaskListRequest) error {
+	attr := &authorization.Attributes{
+		APIName:    "PauseTaskList",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return err
+	}
+	if !isAuthorized {
+		return errUnauthorized
+	}
+
+	return a.AdminHandler.PauseTaskList(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) ResumeTaskList(ctx context.Context, request *types.AdminResumeTaskListRequest) error {
+	attr := &authorization.Attributes{
+		APIName:    "ResumeTaskList",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return err
+	}
+	if !isAuthorized {
+		return errUnauthorized
+	}
+
+	return a.AdminHandler.ResumeTaskList(ctx, request)
+}
+
 func (a *AccessControlledWorkflowAdminHandler) isAuthorized(
 	ctx context.Context,
 	attr *authorization.Attributes,
@@ -481,3 +540,131 @@ func (a *AccessControlledWorkflowAdminHandler) isAuthorized(
 	isAuth := result.Decision == authorization.DecisionAllow
 	return isAuth, nil
 }
+
+func (a *AccessControlledWorkflowAdminHandler) ListDynamicConfigChanges(ctx context.Context, request *types.ListDynamicConfigChangesRequest) (*types.ListDynamicConfigChangesResponse, error) {
+	attr := &authorization.Attributes{
+		APIName:    "ListDynamicConfigChanges",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.AdminHandler.ListDynamicConfigChanges(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) GetDomainUsage(ctx context.Context, request *types.AdminGetDomainUsageRequest) (*types.AdminGetDomainUsageResponse, error) {
+	attr := &authorization.Attributes{
+		APIName:    "GetDomainUsage",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.AdminHandler.GetDomainUsage(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) InvalidateCaches(ctx context.Context, request *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error) {
+	attr := &authorization.Attributes{
+		APIName:    "InvalidateCaches",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.AdminHandler.InvalidateCaches(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) ListEffectiveDynamicConfig(ctx context.Context, request *types.ListEffectiveDynamicConfigRequest) (*types.ListEffectiveDynamicConfigResponse, error) {
+	attr := &authorization.Attributes{
+		APIName:    "ListEffectiveDynamicConfig",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.AdminHandler.ListEffectiveDynamicConfig(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) DescribeShardOwnership(ctx context.Context, request *types.DescribeShardOwnershipRequest) (*types.DescribeShardOwnershipResponse, error) {
+	attr := &authorization.Attributes{
+		APIName:    "DescribeShardOwnership",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.AdminHandler.DescribeShardOwnership(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) DescribePersistenceLatencyHeatmap(ctx context.Context, request *types.DescribePersistenceLatencyHeatmapRequest) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	attr := &authorization.Attributes{
+		APIName:    "DescribePersistenceLatencyHeatmap",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.AdminHandler.DescribePersistenceLatencyHeatmap(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) DescribeWatchdog(ctx context.Context, request *types.DescribeWatchdogRequest) (*types.DescribeWatchdogResponse, error) {
+	attr := &authorization.Attributes{
+		APIName:    "DescribeWatchdog",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.AdminHandler.DescribeWatchdog(ctx, request)
+}
+
+func (a *AccessControlledWorkflowAdminHandler) DescribeDomainSLO(ctx context.Context, request *types.DescribeDomainSLORequest) (*types.DescribeDomainSLOResponse, error) {
+	attr := &authorization.Attributes{
+		APIName:    "DescribeDomainSLO",
+		Permission: authorization.PermissionAdmin,
+	}
+	isAuthorized, err := a.isAuthorized(ctx, attr)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthorized {
+		return nil, errUnauthorized
+	}
+
+	return a.AdminHandler.DescribeDomainSLO(ctx, request)
+}
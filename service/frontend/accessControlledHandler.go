@@ -24,6 +24,7 @@ import (
 	"context"
 
 	"github.com/uber/cadence/common/authorization"
+	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/config"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
@@ -46,11 +47,7 @@ var _ Handler = (*AccessControlledWorkflowHandler)(nil)
 // NewAccessControlledHandlerImpl creates frontend handler with authentication support
 func NewAccessControlledHandlerImpl(wfHandler Handler, resource resource.Resource, authorizer authorization.Authorizer, cfg config.Authorization) *AccessControlledWorkflowHandler {
 	if authorizer == nil {
-		var err error
-		authorizer, err = authorization.NewAuthorizer(cfg, resource.GetLogger(), resource.GetDomainCache())
-		if err != nil {
-			resource.GetLogger().Fatal("Error when initiating the Authorizer", tag.Error(err))
-		}
+		authorizer = newAuthorizer(resource, cfg)
 	}
 	return &AccessControlledWorkflowHandler{
 		Resource:        resource,
@@ -59,6 +56,30 @@ func NewAccessControlledHandlerImpl(wfHandler Handler, resource resource.Resourc
 	}
 }
 
+// newAuthorizer creates the authorizer of the config, in front of which the API keys are validated and
+// the network policy is enforced if enabled
+func newAuthorizer(resource resource.Resource, cfg config.Authorization) authorization.Authorizer {
+	logger := resource.GetLogger()
+	authorizer, err := authorization.NewAuthorizer(cfg, logger, resource.GetDomainCache())
+	if err != nil {
+		logger.Fatal("Error when initiating the Authorizer", tag.Error(err))
+	}
+	if cfg.APIKeys.Enable {
+		configStoreManager := resource.GetPersistenceBean().GetConfigStoreManager()
+		if configStoreManager == nil {
+			logger.Fatal("API keys require a default store supporting the config store")
+		}
+		authorizer = authorization.NewAPIKeyAuthorizer(cfg.APIKeys, configStoreManager, authorizer, clock.NewRealTimeSource(), logger)
+	}
+	if cfg.NetworkPolicy.Enable {
+		authorizer, err = authorization.NewNetworkPolicyAuthorizer(cfg.NetworkPolicy, authorizer, logger)
+		if err != nil {
+			logger.Fatal("Error when initiating the network policy", tag.Error(err))
+		}
+	}
+	return authorizer
+}
+
 // Health callback for for health check
 func (a *AccessControlledWorkflowHandler) Health(ctx context.Context) (*types.HealthStatus, error) {
 	return a.frontendHandler.Health(ctx)
@@ -73,9 +94,10 @@ func (a *AccessControlledWorkflowHandler) CountWorkflowExecutions(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendCountWorkflowExecutionsScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "CountWorkflowExecutions",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "CountWorkflowExecutions",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -97,9 +119,10 @@ func (a *AccessControlledWorkflowHandler) DeprecateDomain(
 	scope := a.getMetricsScopeWithDomainName(metrics.FrontendDeprecateDomainScope, request.GetName())
 
 	attr := &authorization.Attributes{
-		APIName:    "DeprecateDomain",
-		DomainName: request.GetName(),
-		Permission: authorization.PermissionAdmin,
+		APIName:     "DeprecateDomain",
+		DomainName:  request.GetName(),
+		RequestBody: request,
+		Permission:  authorization.PermissionAdmin,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -121,9 +144,10 @@ func (a *AccessControlledWorkflowHandler) DescribeDomain(
 	scope := a.getMetricsScopeWithDomainName(metrics.FrontendDescribeDomainScope, request.GetName())
 
 	attr := &authorization.Attributes{
-		APIName:    "DescribeDomain",
-		DomainName: request.GetName(),
-		Permission: authorization.PermissionRead,
+		APIName:     "DescribeDomain",
+		DomainName:  request.GetName(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -145,9 +169,10 @@ func (a *AccessControlledWorkflowHandler) DescribeTaskList(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendDescribeTaskListScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "DescribeTaskList",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "DescribeTaskList",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -168,9 +193,10 @@ func (a *AccessControlledWorkflowHandler) DescribeWorkflowExecution(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendDescribeWorkflowExecutionScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "DescribeWorkflowExecution",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "DescribeWorkflowExecution",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -199,9 +225,10 @@ func (a *AccessControlledWorkflowHandler) GetWorkflowExecutionHistory(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendGetWorkflowExecutionHistoryScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "GetWorkflowExecutionHistory",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "GetWorkflowExecutionHistory",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -223,9 +250,10 @@ func (a *AccessControlledWorkflowHandler) ListArchivedWorkflowExecutions(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendListArchivedWorkflowExecutionsScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "ListArchivedWorkflowExecutions",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "ListArchivedWorkflowExecutions",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -247,9 +275,10 @@ func (a *AccessControlledWorkflowHandler) ListClosedWorkflowExecutions(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendListClosedWorkflowExecutionsScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "ListClosedWorkflowExecutions",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "ListClosedWorkflowExecutions",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -271,8 +300,9 @@ func (a *AccessControlledWorkflowHandler) ListDomains(
 	scope := a.GetMetricsClient().Scope(metrics.FrontendListDomainsScope)
 
 	attr := &authorization.Attributes{
-		APIName:    "ListDomains",
-		Permission: authorization.PermissionAdmin,
+		APIName:     "ListDomains",
+		Permission:  authorization.PermissionAdmin,
+		RequestBody: request,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -294,9 +324,10 @@ func (a *AccessControlledWorkflowHandler) ListOpenWorkflowExecutions(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendListOpenWorkflowExecutionsScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "ListOpenWorkflowExecutions",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "ListOpenWorkflowExecutions",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -318,9 +349,10 @@ func (a *AccessControlledWorkflowHandler) ListWorkflowExecutions(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendListWorkflowExecutionsScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "ListWorkflowExecutions",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "ListWorkflowExecutions",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -342,10 +374,11 @@ func (a *AccessControlledWorkflowHandler) PollForActivityTask(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendPollForActivityTaskScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "PollForActivityTask",
-		DomainName: request.GetDomain(),
-		TaskList:   request.TaskList,
-		Permission: authorization.PermissionWrite,
+		APIName:     "PollForActivityTask",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		TaskList:    request.TaskList,
+		Permission:  authorization.PermissionWrite,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -367,10 +400,11 @@ func (a *AccessControlledWorkflowHandler) PollForDecisionTask(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendPollForDecisionTaskScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "PollForDecisionTask",
-		DomainName: request.GetDomain(),
-		TaskList:   request.TaskList,
-		Permission: authorization.PermissionWrite,
+		APIName:     "PollForDecisionTask",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		TaskList:    request.TaskList,
+		Permission:  authorization.PermissionWrite,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -392,9 +426,10 @@ func (a *AccessControlledWorkflowHandler) QueryWorkflow(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendQueryWorkflowScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "QueryWorkflow",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "QueryWorkflow",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -440,9 +475,10 @@ func (a *AccessControlledWorkflowHandler) RegisterDomain(
 	scope := a.getMetricsScopeWithDomainName(metrics.FrontendRegisterDomainScope, request.GetName())
 
 	attr := &authorization.Attributes{
-		APIName:    "RegisterDomain",
-		DomainName: request.GetName(),
-		Permission: authorization.PermissionAdmin,
+		APIName:     "RegisterDomain",
+		DomainName:  request.GetName(),
+		RequestBody: request,
+		Permission:  authorization.PermissionAdmin,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -464,9 +500,10 @@ func (a *AccessControlledWorkflowHandler) RequestCancelWorkflowExecution(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendRequestCancelWorkflowExecutionScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "RequestCancelWorkflowExecution",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionWrite,
+		APIName:     "RequestCancelWorkflowExecution",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionWrite,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -488,9 +525,10 @@ func (a *AccessControlledWorkflowHandler) ResetStickyTaskList(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendResetStickyTaskListScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "ResetStickyTaskList",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionWrite,
+		APIName:     "ResetStickyTaskList",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionWrite,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -512,9 +550,10 @@ func (a *AccessControlledWorkflowHandler) ResetWorkflowExecution(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendResetWorkflowExecutionScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "ResetWorkflowExecution",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionWrite,
+		APIName:     "ResetWorkflowExecution",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionWrite,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -604,9 +643,10 @@ func (a *AccessControlledWorkflowHandler) RestartWorkflowExecution(ctx context.C
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendRestartWorkflowExecutionScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "RestartWorkflowExecution",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionWrite,
+		APIName:     "RestartWorkflowExecution",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionWrite,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -628,9 +668,10 @@ func (a *AccessControlledWorkflowHandler) ScanWorkflowExecutions(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendScanWorkflowExecutionsScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "ScanWorkflowExecutions",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "ScanWorkflowExecutions",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -654,6 +695,7 @@ func (a *AccessControlledWorkflowHandler) SignalWithStartWorkflowExecution(
 	attr := &authorization.Attributes{
 		APIName:      "SignalWithStartWorkflowExecution",
 		DomainName:   request.GetDomain(),
+		RequestBody:  request,
 		Permission:   authorization.PermissionWrite,
 		WorkflowType: request.WorkflowType,
 	}
@@ -677,9 +719,10 @@ func (a *AccessControlledWorkflowHandler) SignalWorkflowExecution(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendSignalWorkflowExecutionScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "SignalWorkflowExecution",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionWrite,
+		APIName:     "SignalWorkflowExecution",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionWrite,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -703,6 +746,7 @@ func (a *AccessControlledWorkflowHandler) StartWorkflowExecution(
 	attr := &authorization.Attributes{
 		APIName:      "StartWorkflowExecution",
 		DomainName:   request.GetDomain(),
+		RequestBody:  request,
 		Permission:   authorization.PermissionWrite,
 		WorkflowType: request.WorkflowType,
 	}
@@ -726,9 +770,10 @@ func (a *AccessControlledWorkflowHandler) TerminateWorkflowExecution(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendTerminateWorkflowExecutionScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "TerminateWorkflowExecution",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionWrite,
+		APIName:     "TerminateWorkflowExecution",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionWrite,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -750,9 +795,10 @@ func (a *AccessControlledWorkflowHandler) ListTaskListPartitions(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendListTaskListPartitionsScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "ListTaskListPartitions",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "ListTaskListPartitions",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -774,9 +820,10 @@ func (a *AccessControlledWorkflowHandler) GetTaskListsByDomain(
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendGetTaskListsByDomainScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "GetTaskListsByDomain",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionRead,
+		APIName:     "GetTaskListsByDomain",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionRead,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -794,9 +841,10 @@ func (a *AccessControlledWorkflowHandler) RefreshWorkflowTasks(ctx context.Conte
 	scope := a.getMetricsScopeWithDomain(metrics.FrontendRefreshWorkflowTasksScope, request)
 
 	attr := &authorization.Attributes{
-		APIName:    "RefreshWorkflowTasks",
-		DomainName: request.GetDomain(),
-		Permission: authorization.PermissionWrite,
+		APIName:     "RefreshWorkflowTasks",
+		DomainName:  request.GetDomain(),
+		RequestBody: request,
+		Permission:  authorization.PermissionWrite,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
@@ -818,9 +866,10 @@ func (a *AccessControlledWorkflowHandler) UpdateDomain(
 	scope := a.getMetricsScopeWithDomainName(metrics.FrontendUpdateDomainScope, request.GetName())
 
 	attr := &authorization.Attributes{
-		APIName:    "UpdateDomain",
-		DomainName: request.GetName(),
-		Permission: authorization.PermissionAdmin,
+		APIName:     "UpdateDomain",
+		DomainName:  request.GetName(),
+		RequestBody: request,
+		Permission:  authorization.PermissionAdmin,
 	}
 	isAuthorized, err := a.isAuthorized(ctx, attr, scope)
 	if err != nil {
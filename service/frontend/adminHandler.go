@@ -28,14 +28,19 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pborman/uuid"
+	"go.uber.org/yarpc"
 
 	"github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/accounting"
 	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/client"
 	"github.com/uber/cadence/common/codec"
 	"github.com/uber/cadence/common/definition"
@@ -49,7 +54,9 @@ import (
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/resource"
 	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/slo"
 	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/watchdog"
 	"github.com/uber/cadence/service/history/execution"
 )
 
@@ -57,10 +64,20 @@ var _ AdminHandler = (*adminHandlerImpl)(nil)
 
 const (
 	endMessageID int64 = 1<<63 - 1
+
+	// maxDomainUsageRange bounds the hours a single GetDomainUsage call reads
+	maxDomainUsageRange = 31 * 24 * time.Hour
 )
 
 var (
-	errInvalidFilters = &types.BadRequestError{Message: "Request Filters are invalid, unable to parse."}
+	errInvalidFilters              = &types.BadRequestError{Message: "Request Filters are invalid, unable to parse."}
+	errInvalidPageSize             = &types.BadRequestError{Message: "Invalid PageSize."}
+	errConfigChangeHistoryDisabled = &types.BadRequestError{Message: "Dynamic config change history is only kept when dynamic config is stored in the config store."}
+	errInvalidDomainUsageRange     = &types.BadRequestError{Message: fmt.Sprintf("EndTime must be after StartTime and the range must not exceed %v.", maxDomainUsageRange)}
+	errDomainUsageNotRecorded      = &types.BadRequestError{Message: "Domain usage is only recorded when the default store supports the config store."}
+	errCacheMatchNotSet            = &types.BadRequestError{Message: "Match is required to invalidate cache entries."}
+	errNotHistoryHost              = &types.BadRequestError{Message: "HostAddress must be the address of a history host."}
+	errInvalidHeatmapWindow        = &types.BadRequestError{Message: "WindowInSeconds must not be negative."}
 )
 
 type (
@@ -96,13 +113,24 @@ type (
 		ListDynamicConfig(context.Context, *types.ListDynamicConfigRequest) (*types.ListDynamicConfigResponse, error)
 		DeleteWorkflow(context.Context, *types.AdminDeleteWorkflowRequest) (*types.AdminDeleteWorkflowResponse, error)
 		MaintainCorruptWorkflow(context.Context, *types.AdminMaintainWorkflowRequest) (*types.AdminMaintainWorkflowResponse, error)
+		UnloadTaskList(context.Context, *types.AdminUnloadTaskListRequest) error
+		GetTaskListDrainStatus(context.Context, *types.AdminGetTaskListDrainStatusRequest) (*types.GetTaskListDrainStatusResponse, error)
+		PauseTaskList(context.Context, *types.AdminPauseTaskListRequest) error
+		ResumeTaskList(context.Context, *types.AdminResumeTaskListRequest) error
+		ListDynamicConfigChanges(context.Context, *types.ListDynamicConfigChangesRequest) (*types.ListDynamicConfigChangesResponse, error)
+		GetDomainUsage(context.Context, *types.AdminGetDomainUsageRequest) (*types.AdminGetDomainUsageResponse, error)
+		InvalidateCaches(context.Context, *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error)
+		ListEffectiveDynamicConfig(context.Context, *types.ListEffectiveDynamicConfigRequest) (*types.ListEffectiveDynamicConfigResponse, error)
+		DescribeShardOwnership(context.Context, *types.DescribeShardOwnershipRequest) (*types.DescribeShardOwnershipResponse, error)
+		DescribePersistenceLatencyHeatmap(context.Context, *types.DescribePersistenceLatencyHeatmapRequest) (*types.DescribePersistenceLatencyHeatmapResponse, error)
+		DescribeWatchdog(context.Context, *types.DescribeWatchdogRequest) (*types.DescribeWatchdogResponse, error)
+		DescribeDomainSLO(context.Context, *types.DescribeDomainSLORequest) (*types.DescribeDomainSLOResponse, error)
 	}
 
 	// adminHandlerImpl is an implementation for admin service independent of wire protocol
 	adminHandlerImpl struct {
 		resource.Resource
 
-		numberOfHistoryShards int
 		params                *resource.Params
 		config                *Config
 		domainDLQHandler      domain.DLQMessageHandler
@@ -153,10 +181,9 @@ func NewAdminHandler(
 		resource.GetLogger(),
 	)
 	return &adminHandlerImpl{
-		Resource:              resource,
-		numberOfHistoryShards: params.PersistenceConfig.NumHistoryShards,
-		params:                params,
-		config:                config,
+		Resource: resource,
+		params:   params,
+		config:   config,
 		domainDLQHandler: domain.NewDLQMessageHandler(
 			domainReplicationTaskExecutor,
 			resource.GetDomainReplicationQueue(),
@@ -286,7 +313,7 @@ func (adh *adminHandlerImpl) DescribeWorkflowExecution(
 		return nil, adh.error(err, scope)
 	}
 
-	shardID := common.WorkflowIDToHistoryShard(request.Execution.WorkflowID, adh.numberOfHistoryShards)
+	shardID := adh.GetHistoryShardRouter().WorkflowIDToShard(request.Execution.WorkflowID)
 	shardIDstr := string(rune(shardID)) // originally `string(int_shard_id)`, but changing it will change the ring hashing
 	shardIDForOutput := strconv.Itoa(shardID)
 
@@ -570,6 +597,11 @@ func (adh *adminHandlerImpl) DeleteWorkflow(
 	runID := request.GetExecution().GetRunID()
 	skipErrors := request.GetSkipErrors()
 
+	var (
+		ms         *persistence.WorkflowMutableState
+		domainID   string
+		shardIDInt int
+	)
 	resp, err := adh.DescribeWorkflowExecution(
 		ctx,
 		&types.AdminDescribeWorkflowExecutionRequest{
@@ -579,22 +611,36 @@ func (adh *adminHandlerImpl) DeleteWorkflow(
 				RunID:      runID,
 			},
 		})
-
-	if err != nil {
+	if err == nil {
+		ms = &persistence.WorkflowMutableState{}
+		if err = json.Unmarshal([]byte(resp.GetMutableStateInDatabase()), ms); err != nil {
+			logger.Error(fmt.Sprintf("DeleteWorkflow failed: Cannot unmarshal mutableState: %#v", err))
+			return nil, adh.error(err, scope)
+		}
+		domainID = ms.ExecutionInfo.DomainID
+		if runID == "" {
+			runID = ms.ExecutionInfo.RunID
+		}
+		shardID := resp.GetShardID()
+		if shardIDInt, err = strconv.Atoi(shardID); err != nil {
+			logger.Error(fmt.Sprintf("Cannot convert shardID(%v) to int: %#v", shardID, err))
+			return nil, adh.error(err, scope)
+		}
+	} else {
 		logger.Error("Describe workflow failed", tag.Error(err))
 		if !skipErrors {
 			return nil, adh.error(err, scope)
 		}
+		// The mutable state of a corrupted workflow may not be loadable at all. Its history branches can't be
+		// found without it, but the executions and visibility records can still be deleted by their keys.
+		if runID == "" {
+			return nil, adh.error(&types.BadRequestError{Message: "RunID is required to delete a workflow whose mutable state cannot be loaded."}, scope)
+		}
+		if domainID, err = adh.GetDomainCache().GetDomainID(domainName); err != nil {
+			return nil, adh.error(err, scope)
+		}
+		shardIDInt = adh.GetHistoryShardRouter().WorkflowIDToShard(workflowID)
 	}
-
-	msStr := resp.GetMutableStateInDatabase()
-	ms := persistence.WorkflowMutableState{}
-	err = json.Unmarshal([]byte(msStr), &ms)
-	if err != nil {
-		logger.Error(fmt.Sprintf("DeleteWorkflow failed: Cannot unmarshal mutableState: %#v", err))
-		return nil, adh.error(err, scope)
-	}
-	domainID := ms.ExecutionInfo.DomainID
 	logger = logger.WithTags(
 		tag.WorkflowDomainID(domainID),
 		tag.WorkflowDomainName(domainName),
@@ -602,16 +648,15 @@ func (adh *adminHandlerImpl) DeleteWorkflow(
 		tag.WorkflowRunID(runID),
 	)
 
-	shardID := resp.GetShardID()
-	shardIDInt, err := strconv.Atoi(shardID)
-	if err != nil {
-		logger.Error(fmt.Sprintf("Cannot convert shardID(%v) to int: %#v", shardID, err))
-		return nil, adh.error(err, scope)
-	}
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 
-	deletedFromHistory := adh.deleteWorkflowFromHistory(ctx, logger, shardIDInt, ms)
+	deletedFromHistory := false
+	if ms != nil {
+		deletedFromHistory = adh.deleteWorkflowFromHistory(ctx, logger, shardIDInt, *ms)
+	} else {
+		logger.Warn("Skip deleting history branches as the mutable state cannot be loaded")
+	}
 	deletedFromExecutions := adh.deleteWorkflowFromExecutions(ctx, logger, shardIDInt, domainID, workflowID, runID, scope)
 	deletedFromVisibility := false
 	if deletedFromExecutions {
@@ -699,15 +744,17 @@ func (adh *adminHandlerImpl) DescribeShardDistribution(
 	_, sw := adh.startRequestProfile(ctx, metrics.AdminDescribeShardDistributionScope)
 	defer sw.Stop()
 
-	numShards := adh.config.NumHistoryShards
+	// the shards paused by a resharding aren't owned by any host
+	shardIDs := adh.GetHistoryShardRouter().ShardIDs()
 	resp = &types.DescribeShardDistributionResponse{
-		NumberOfShards: int32(numShards),
+		NumberOfShards: int32(len(shardIDs)),
 		Shards:         make(map[int32]string),
 	}
 
 	offset := int(request.PageID * request.PageSize)
 	nextPageStart := offset + int(request.PageSize)
-	for shardID := offset; shardID < numShards && shardID < nextPageStart; shardID++ {
+	for i := offset; i < len(shardIDs) && i < nextPageStart; i++ {
+		shardID := shardIDs[i]
 		info, err := adh.GetMembershipResolver().Lookup(service.History, string(rune(shardID)))
 		if err != nil {
 			resp.Shards[int32(shardID)] = "unknown"
@@ -820,10 +867,7 @@ func (adh *adminHandlerImpl) GetWorkflowExecutionRawHistoryV2(
 		}, nil
 	}
 	pageSize := int(request.GetMaximumPageSize())
-	shardID := common.WorkflowIDToHistoryShard(
-		execution.GetWorkflowID(),
-		adh.numberOfHistoryShards,
-	)
+	shardID := adh.GetHistoryShardRouter().WorkflowIDToShard(execution.GetWorkflowID())
 
 	rawHistoryResponse, err := adh.GetHistoryManager().ReadRawHistoryBranch(ctx, &persistence.ReadHistoryBranchRequest{
 		BranchToken: targetVersionHistory.GetBranchToken(),
@@ -1663,6 +1707,9 @@ func (adh *adminHandlerImpl) UpdateDynamicConfig(ctx context.Context, request *t
 		return adh.error(err, scope)
 	}
 
+	if auditedClient, ok := adh.params.DynamicConfig.(dc.AuditedClient); ok {
+		return auditedClient.UpdateValueWithIdentity(keyVal, request.ConfigValues, getCallerIdentity(ctx))
+	}
 	return adh.params.DynamicConfig.UpdateValue(keyVal, request.ConfigValues)
 }
 
@@ -1690,6 +1737,9 @@ func (adh *adminHandlerImpl) RestoreDynamicConfig(ctx context.Context, request *
 			return adh.error(errInvalidFilters, scope)
 		}
 	}
+	if auditedClient, ok := adh.params.DynamicConfig.(dc.AuditedClient); ok {
+		return auditedClient.RestoreValueWithIdentity(keyVal, filters, getCallerIdentity(ctx))
+	}
 	return adh.params.DynamicConfig.RestoreValue(keyVal, filters)
 }
 
@@ -1724,6 +1774,455 @@ func (adh *adminHandlerImpl) ListDynamicConfig(ctx context.Context, request *typ
 	}, nil
 }
 
+// ListDynamicConfigChanges lists a page of the change history of dynamic config values, from the latest change to the oldest
+func (adh *adminHandlerImpl) ListDynamicConfigChanges(
+	ctx context.Context,
+	request *types.ListDynamicConfigChangesRequest,
+) (_ *types.ListDynamicConfigChangesResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminListDynamicConfigChangesScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetPageSize() <= 0 {
+		return nil, adh.error(errInvalidPageSize, scope)
+	}
+
+	auditedClient, ok := adh.params.DynamicConfig.(dc.AuditedClient)
+	if !ok {
+		return nil, adh.error(errConfigChangeHistoryDisabled, scope)
+	}
+	response, err := auditedClient.ListChanges(request)
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return response, nil
+}
+
+// ListEffectiveDynamicConfig resolves the value in effect of every dynamic config key for the filters,
+// as seen by the frontend host serving the request
+func (adh *adminHandlerImpl) ListEffectiveDynamicConfig(
+	ctx context.Context,
+	request *types.ListEffectiveDynamicConfigRequest,
+) (_ *types.ListEffectiveDynamicConfigResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminListEffectiveDynamicConfigScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+
+	filters, err := convertFilterListToMap(request.GetFilters())
+	if err != nil {
+		return nil, adh.error(errInvalidFilters, scope)
+	}
+	// numbers are decoded as float64 from json while the int filters are compared with ints
+	for _, filter := range []dc.Filter{dc.TaskType, dc.ShardID} {
+		if value, ok := filters[filter].(float64); ok {
+			filters[filter] = int(value)
+		}
+	}
+
+	collection := dc.NewCollection(
+		adh.params.DynamicConfig,
+		adh.GetLogger(),
+		dc.ClusterNameFilter(adh.GetClusterMetadata().GetCurrentClusterName()),
+	)
+	var values []*types.EffectiveDynamicConfigValue
+	for _, value := range collection.EffectiveValuesWithFilters(filters) {
+		if !strings.HasPrefix(value.Name, request.GetConfigNamePrefix()) {
+			continue
+		}
+		effectiveValue, err := toEffectiveDynamicConfigValue(value)
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+		values = append(values, effectiveValue)
+	}
+	return &types.ListEffectiveDynamicConfigResponse{Values: values}, nil
+}
+
+// GetDomainUsage sums the usage reported by all hosts for the intervals ending in the requested time range
+func (adh *adminHandlerImpl) GetDomainUsage(
+	ctx context.Context,
+	request *types.AdminGetDomainUsageRequest,
+) (_ *types.AdminGetDomainUsageResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminGetDomainUsageScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	start := time.Unix(0, request.GetStartTime())
+	end := time.Unix(0, request.GetEndTime())
+	if !end.After(start) || end.Sub(start) > maxDomainUsageRange {
+		return nil, adh.error(errInvalidDomainUsageRange, scope)
+	}
+
+	configStoreManager := adh.GetPersistenceBean().GetConfigStoreManager()
+	if configStoreManager == nil {
+		return nil, adh.error(errDomainUsageNotRecorded, scope)
+	}
+
+	domains := make(map[string]*types.DomainUsage)
+	for hour := persistence.DomainUsageHour(start); !hour.After(end); hour = hour.Add(time.Hour) {
+		resp, err := configStoreManager.ListDomainUsage(ctx, &persistence.ListDomainUsageRequest{Hour: hour})
+		if err != nil {
+			return nil, adh.error(err, scope)
+		}
+		for domainName, usage := range accounting.AggregateUsage(resp.Records, start, end) {
+			if request.GetDomain() != "" && domainName != request.GetDomain() {
+				continue
+			}
+			total, ok := domains[domainName]
+			if !ok {
+				total = &types.DomainUsage{}
+				domains[domainName] = total
+			}
+			total.Actions += usage.Actions
+			total.PersistenceReadUnits += usage.PersistenceReadUnits
+			total.PersistenceWriteUnits += usage.PersistenceWriteUnits
+			total.PayloadBytes += usage.PayloadBytes
+			total.TaskDispatches += usage.TaskDispatches
+		}
+	}
+	return &types.AdminGetDomainUsageResponse{Domains: domains}, nil
+}
+
+// InvalidateCaches removes the matching entries from the in-memory caches of a host, so that they are reloaded
+// on their next access. The frontend host serving the request is targeted if no host address is set.
+func (adh *adminHandlerImpl) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+) (_ *types.InvalidateCachesResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminInvalidateCachesScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetMatch() == "" {
+		return nil, adh.error(errCacheMatchNotSet, scope)
+	}
+
+	hostService, err := adh.hostService(request.GetHostAddress())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	var response *types.InvalidateCachesResponse
+	switch hostService {
+	case service.History:
+		response, err = adh.GetHistoryClient().InvalidateCaches(ctx, request)
+	case service.Matching:
+		response, err = adh.GetMatchingClient().InvalidateCaches(ctx, request)
+	default:
+		response, err = cache.InvalidateCaches(adh.GetCacheRegistry(), request)
+	}
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return response, nil
+}
+
+// DescribeShardOwnership describes the shard to host mapping and the shard acquisitions and steals
+// seen by a history host
+func (adh *adminHandlerImpl) DescribeShardOwnership(
+	ctx context.Context,
+	request *types.DescribeShardOwnershipRequest,
+) (_ *types.DescribeShardOwnershipResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminDescribeShardOwnershipScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if err := adh.validateHistoryHost(request.GetHostAddress()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	response, err := adh.GetHistoryClient().DescribeShardOwnership(ctx, request)
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return response, nil
+}
+
+// DescribePersistenceLatencyHeatmap returns the shards and persistence operations with the highest latency
+// recently seen by a history host
+func (adh *adminHandlerImpl) DescribePersistenceLatencyHeatmap(
+	ctx context.Context,
+	request *types.DescribePersistenceLatencyHeatmapRequest,
+) (_ *types.DescribePersistenceLatencyHeatmapResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminDescribePersistenceLatencyHeatmapScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetWindowInSeconds() < 0 {
+		return nil, adh.error(errInvalidHeatmapWindow, scope)
+	}
+	if err := adh.validateHistoryHost(request.GetHostAddress()); err != nil {
+		return nil, adh.error(err, scope)
+	}
+
+	response, err := adh.GetHistoryClient().DescribePersistenceLatencyHeatmap(ctx, request)
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return response, nil
+}
+
+// DescribeWatchdog describes the resources tracked by the watchdog of a host, which is the frontend host
+// serving the request if no host address is set
+func (adh *adminHandlerImpl) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+) (_ *types.DescribeWatchdogResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminDescribeWatchdogScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+
+	hostService, err := adh.hostService(request.GetHostAddress())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	var response *types.DescribeWatchdogResponse
+	switch hostService {
+	case service.History:
+		response, err = adh.GetHistoryClient().DescribeWatchdog(ctx, request)
+	case service.Matching:
+		response, err = adh.GetMatchingClient().DescribeWatchdog(ctx, request)
+	default:
+		response = watchdog.Describe(adh.GetWatchdog())
+	}
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return response, nil
+}
+
+// DescribeDomainSLO describes the error budget and burn rates of the SLOs of domains over the last hour,
+// as tracked by the frontend host serving the request
+func (adh *adminHandlerImpl) DescribeDomainSLO(
+	ctx context.Context,
+	request *types.DescribeDomainSLORequest,
+) (_ *types.DescribeDomainSLOResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminDescribeDomainSLOScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+
+	return slo.Describe(adh.params.SLOTracker, request.GetDomain()), nil
+}
+
+// validateHistoryHost checks the address is the one of a history host, for the APIs only served by history hosts
+func (adh *adminHandlerImpl) validateHistoryHost(address string) error {
+	hostService, err := adh.hostService(address)
+	if err != nil {
+		return err
+	}
+	if hostService != service.History {
+		return errNotHistoryHost
+	}
+	return nil
+}
+
+// hostService returns the service of the host with the address, which is the frontend host serving the request
+// if the address is empty. The other frontend hosts can't be targeted as requests can't be routed to them.
+func (adh *adminHandlerImpl) hostService(address string) (string, error) {
+	if address == "" || address == adh.GetHostInfo().GetAddress() {
+		return service.Frontend, nil
+	}
+	for _, hostService := range []string{service.History, service.Matching} {
+		if _, err := adh.GetMembershipResolver().LookupByAddress(hostService, address); err == nil {
+			return hostService, nil
+		}
+	}
+	return "", &types.BadRequestError{Message: fmt.Sprintf(
+		"Host %v is not a history or matching host, the other frontend hosts are targeted by sending the request to them.", address,
+	)}
+}
+
+// UnloadTaskList force-unloads a task list partition from the matching host owning it
+func (adh *adminHandlerImpl) UnloadTaskList(
+	ctx context.Context,
+	request *types.AdminUnloadTaskListRequest,
+) (retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminUnloadTaskListScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return adh.error(errDomainNotSet, scope)
+	}
+	if request.GetTaskList().GetName() == "" {
+		return adh.error(errTaskListNotSet, scope)
+	}
+	if request.TaskListType == nil {
+		return adh.error(errTaskListTypeNotSet, scope)
+	}
+	if request.GetBlockReloadSeconds() < 0 {
+		return adh.error(&types.BadRequestError{Message: "BlockReloadSeconds must not be negative."}, scope)
+	}
+
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return adh.error(err, scope)
+	}
+	err = adh.GetMatchingClient().UnloadTaskList(ctx, &types.MatchingUnloadTaskListRequest{
+		DomainUUID:         domainID,
+		TaskList:           request.TaskList,
+		TaskListType:       request.TaskListType,
+		BlockReloadSeconds: request.BlockReloadSeconds,
+	})
+	if err != nil {
+		return adh.error(err, scope)
+	}
+	return nil
+}
+
+// GetTaskListDrainStatus reports whether a task list in drain mode has no task left in any of its partitions
+func (adh *adminHandlerImpl) GetTaskListDrainStatus(
+	ctx context.Context,
+	request *types.AdminGetTaskListDrainStatusRequest,
+) (_ *types.GetTaskListDrainStatusResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminGetTaskListDrainStatusScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return nil, adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return nil, adh.error(errDomainNotSet, scope)
+	}
+	if request.GetTaskList().GetName() == "" {
+		return nil, adh.error(errTaskListNotSet, scope)
+	}
+	if request.TaskListType == nil {
+		return nil, adh.error(errTaskListTypeNotSet, scope)
+	}
+
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	response, err := adh.GetMatchingClient().GetTaskListDrainStatus(ctx, &types.MatchingGetTaskListDrainStatusRequest{
+		DomainUUID:   domainID,
+		TaskList:     request.TaskList,
+		TaskListType: request.TaskListType,
+	})
+	if err != nil {
+		return nil, adh.error(err, scope)
+	}
+	return response, nil
+}
+
+// PauseTaskList stops the dispatch of the tasks of a task list partition, its pollers receive empty responses
+// and its tasks are kept in the backlog until it is resumed. The state is persisted with the task list.
+func (adh *adminHandlerImpl) PauseTaskList(
+	ctx context.Context,
+	request *types.AdminPauseTaskListRequest,
+) (retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminPauseTaskListScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return adh.error(errDomainNotSet, scope)
+	}
+	if request.GetTaskList().GetName() == "" {
+		return adh.error(errTaskListNotSet, scope)
+	}
+	if request.TaskListType == nil {
+		return adh.error(errTaskListTypeNotSet, scope)
+	}
+
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return adh.error(err, scope)
+	}
+	err = adh.GetMatchingClient().PauseTaskList(ctx, &types.MatchingPauseTaskListRequest{
+		DomainUUID:   domainID,
+		TaskList:     request.TaskList,
+		TaskListType: request.TaskListType,
+	})
+	if err != nil {
+		return adh.error(err, scope)
+	}
+	return nil
+}
+
+// ResumeTaskList resumes the dispatch of the tasks of a paused task list partition
+func (adh *adminHandlerImpl) ResumeTaskList(
+	ctx context.Context,
+	request *types.AdminResumeTaskListRequest,
+) (retError error) {
+
+	defer func() { log.CapturePanic(recover(), adh.GetLogger(), &retError) }()
+	scope, sw := adh.startRequestProfile(ctx, metrics.AdminResumeTaskListScope)
+	defer sw.Stop()
+
+	if request == nil {
+		return adh.error(errRequestNotSet, scope)
+	}
+	if request.GetDomain() == "" {
+		return adh.error(errDomainNotSet, scope)
+	}
+	if request.GetTaskList().GetName() == "" {
+		return adh.error(errTaskListNotSet, scope)
+	}
+	if request.TaskListType == nil {
+		return adh.error(errTaskListTypeNotSet, scope)
+	}
+
+	domainID, err := adh.GetDomainCache().GetDomainID(request.GetDomain())
+	if err != nil {
+		return adh.error(err, scope)
+	}
+	err = adh.GetMatchingClient().ResumeTaskList(ctx, &types.MatchingResumeTaskListRequest{
+		DomainUUID:   domainID,
+		TaskList:     request.TaskList,
+		TaskListType: request.TaskListType,
+	})
+	if err != nil {
+		return adh.error(err, scope)
+	}
+	return nil
+}
+
 func convertFromDataBlob(blob *types.DataBlob) (interface{}, error) {
 	switch *blob.EncodingType {
 	case types.EncodingTypeJSON:
@@ -1735,6 +2234,57 @@ func convertFromDataBlob(blob *types.DataBlob) (interface{}, error) {
 	}
 }
 
+// getCallerIdentity returns the identity sent by the client, falling back to the calling service name
+func getCallerIdentity(ctx context.Context) string {
+	call := yarpc.CallFromContext(ctx)
+	if call == nil {
+		return ""
+	}
+	if identity := call.Header(common.ClientIdentityHeaderName); identity != "" {
+		return identity
+	}
+	return call.Caller()
+}
+
+func toEffectiveDynamicConfigValue(value *dc.EffectiveValue) (*types.EffectiveDynamicConfigValue, error) {
+	toDataBlob := func(v interface{}) (*types.DataBlob, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: data}, nil
+	}
+
+	result := &types.EffectiveDynamicConfigValue{
+		Name:      value.Name,
+		IsDefault: value.IsDefault,
+		Error:     value.Error,
+	}
+	var err error
+	if result.Value, err = toDataBlob(value.Value); err != nil {
+		return nil, err
+	}
+	if result.DefaultValue, err = toDataBlob(value.DefaultValue); err != nil {
+		return nil, err
+	}
+	if value.MatchedFilters != nil {
+		names := make([]string, 0, len(value.MatchedFilters))
+		for name := range value.MatchedFilters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result.MatchedFilters = make([]*types.DynamicConfigFilter, 0, len(names))
+		for _, name := range names {
+			filterValue, err := toDataBlob(value.MatchedFilters[name])
+			if err != nil {
+				return nil, err
+			}
+			result.MatchedFilters = append(result.MatchedFilters, &types.DynamicConfigFilter{Name: name, Value: filterValue})
+		}
+	}
+	return result, nil
+}
+
 func convertFilterListToMap(filters []*types.DynamicConfigFilter) (map[dc.Filter]interface{}, error) {
 	newFilters := make(map[dc.Filter]interface{})
 
@@ -131,6 +131,21 @@ func (mr *MockAdminHandlerMockRecorder) DescribeCluster(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCluster", reflect.TypeOf((*MockAdminHandler)(nil).DescribeCluster), arg0)
 }
 
+// DescribeDomainSLO mocks base method.
+func (m *MockAdminHandler) DescribeDomainSLO(arg0 context.Context, arg1 *types.DescribeDomainSLORequest) (*types.DescribeDomainSLOResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeDomainSLO", arg0, arg1)
+	ret0, _ := ret[0].(*types.DescribeDomainSLOResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDomainSLO indicates an expected call of DescribeDomainSLO.
+func (mr *MockAdminHandlerMockRecorder) DescribeDomainSLO(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDomainSLO", reflect.TypeOf((*MockAdminHandler)(nil).DescribeDomainSLO), arg0, arg1)
+}
+
 // DescribeHistoryHost mocks base method.
 func (m *MockAdminHandler) DescribeHistoryHost(arg0 context.Context, arg1 *types.DescribeHistoryHostRequest) (*types.DescribeHistoryHostResponse, error) {
 	m.ctrl.T.Helper()
@@ -146,6 +161,21 @@ func (mr *MockAdminHandlerMockRecorder) DescribeHistoryHost(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeHistoryHost", reflect.TypeOf((*MockAdminHandler)(nil).DescribeHistoryHost), arg0, arg1)
 }
 
+// DescribePersistenceLatencyHeatmap mocks base method.
+func (m *MockAdminHandler) DescribePersistenceLatencyHeatmap(arg0 context.Context, arg1 *types.DescribePersistenceLatencyHeatmapRequest) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribePersistenceLatencyHeatmap", arg0, arg1)
+	ret0, _ := ret[0].(*types.DescribePersistenceLatencyHeatmapResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribePersistenceLatencyHeatmap indicates an expected call of DescribePersistenceLatencyHeatmap.
+func (mr *MockAdminHandlerMockRecorder) DescribePersistenceLatencyHeatmap(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribePersistenceLatencyHeatmap", reflect.TypeOf((*MockAdminHandler)(nil).DescribePersistenceLatencyHeatmap), arg0, arg1)
+}
+
 // DescribeQueue mocks base method.
 func (m *MockAdminHandler) DescribeQueue(arg0 context.Context, arg1 *types.DescribeQueueRequest) (*types.DescribeQueueResponse, error) {
 	m.ctrl.T.Helper()
@@ -176,6 +206,36 @@ func (mr *MockAdminHandlerMockRecorder) DescribeShardDistribution(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeShardDistribution", reflect.TypeOf((*MockAdminHandler)(nil).DescribeShardDistribution), arg0, arg1)
 }
 
+// DescribeShardOwnership mocks base method.
+func (m *MockAdminHandler) DescribeShardOwnership(arg0 context.Context, arg1 *types.DescribeShardOwnershipRequest) (*types.DescribeShardOwnershipResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeShardOwnership", arg0, arg1)
+	ret0, _ := ret[0].(*types.DescribeShardOwnershipResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeShardOwnership indicates an expected call of DescribeShardOwnership.
+func (mr *MockAdminHandlerMockRecorder) DescribeShardOwnership(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeShardOwnership", reflect.TypeOf((*MockAdminHandler)(nil).DescribeShardOwnership), arg0, arg1)
+}
+
+// DescribeWatchdog mocks base method.
+func (m *MockAdminHandler) DescribeWatchdog(arg0 context.Context, arg1 *types.DescribeWatchdogRequest) (*types.DescribeWatchdogResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeWatchdog", arg0, arg1)
+	ret0, _ := ret[0].(*types.DescribeWatchdogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeWatchdog indicates an expected call of DescribeWatchdog.
+func (mr *MockAdminHandlerMockRecorder) DescribeWatchdog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWatchdog", reflect.TypeOf((*MockAdminHandler)(nil).DescribeWatchdog), arg0, arg1)
+}
+
 // DescribeWorkflowExecution mocks base method.
 func (m *MockAdminHandler) DescribeWorkflowExecution(arg0 context.Context, arg1 *types.AdminDescribeWorkflowExecutionRequest) (*types.AdminDescribeWorkflowExecutionResponse, error) {
 	m.ctrl.T.Helper()
@@ -236,6 +296,21 @@ func (mr *MockAdminHandlerMockRecorder) GetDomainReplicationMessages(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomainReplicationMessages", reflect.TypeOf((*MockAdminHandler)(nil).GetDomainReplicationMessages), arg0, arg1)
 }
 
+// GetDomainUsage mocks base method.
+func (m *MockAdminHandler) GetDomainUsage(arg0 context.Context, arg1 *types.AdminGetDomainUsageRequest) (*types.AdminGetDomainUsageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDomainUsage", arg0, arg1)
+	ret0, _ := ret[0].(*types.AdminGetDomainUsageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDomainUsage indicates an expected call of GetDomainUsage.
+func (mr *MockAdminHandlerMockRecorder) GetDomainUsage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomainUsage", reflect.TypeOf((*MockAdminHandler)(nil).GetDomainUsage), arg0, arg1)
+}
+
 // GetDynamicConfig mocks base method.
 func (m *MockAdminHandler) GetDynamicConfig(arg0 context.Context, arg1 *types.GetDynamicConfigRequest) (*types.GetDynamicConfigResponse, error) {
 	m.ctrl.T.Helper()
@@ -266,6 +341,21 @@ func (mr *MockAdminHandlerMockRecorder) GetReplicationMessages(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicationMessages", reflect.TypeOf((*MockAdminHandler)(nil).GetReplicationMessages), arg0, arg1)
 }
 
+// GetTaskListDrainStatus mocks base method.
+func (m *MockAdminHandler) GetTaskListDrainStatus(arg0 context.Context, arg1 *types.AdminGetTaskListDrainStatusRequest) (*types.GetTaskListDrainStatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskListDrainStatus", arg0, arg1)
+	ret0, _ := ret[0].(*types.GetTaskListDrainStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskListDrainStatus indicates an expected call of GetTaskListDrainStatus.
+func (mr *MockAdminHandlerMockRecorder) GetTaskListDrainStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskListDrainStatus", reflect.TypeOf((*MockAdminHandler)(nil).GetTaskListDrainStatus), arg0, arg1)
+}
+
 // GetWorkflowExecutionRawHistoryV2 mocks base method.
 func (m *MockAdminHandler) GetWorkflowExecutionRawHistoryV2(arg0 context.Context, arg1 *types.GetWorkflowExecutionRawHistoryV2Request) (*types.GetWorkflowExecutionRawHistoryV2Response, error) {
 	m.ctrl.T.Helper()
@@ -281,6 +371,21 @@ func (mr *MockAdminHandlerMockRecorder) GetWorkflowExecutionRawHistoryV2(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowExecutionRawHistoryV2", reflect.TypeOf((*MockAdminHandler)(nil).GetWorkflowExecutionRawHistoryV2), arg0, arg1)
 }
 
+// InvalidateCaches mocks base method.
+func (m *MockAdminHandler) InvalidateCaches(arg0 context.Context, arg1 *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateCaches", arg0, arg1)
+	ret0, _ := ret[0].(*types.InvalidateCachesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvalidateCaches indicates an expected call of InvalidateCaches.
+func (mr *MockAdminHandlerMockRecorder) InvalidateCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCaches", reflect.TypeOf((*MockAdminHandler)(nil).InvalidateCaches), arg0, arg1)
+}
+
 // ListDynamicConfig mocks base method.
 func (m *MockAdminHandler) ListDynamicConfig(arg0 context.Context, arg1 *types.ListDynamicConfigRequest) (*types.ListDynamicConfigResponse, error) {
 	m.ctrl.T.Helper()
@@ -296,6 +401,36 @@ func (mr *MockAdminHandlerMockRecorder) ListDynamicConfig(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDynamicConfig", reflect.TypeOf((*MockAdminHandler)(nil).ListDynamicConfig), arg0, arg1)
 }
 
+// ListDynamicConfigChanges mocks base method.
+func (m *MockAdminHandler) ListDynamicConfigChanges(arg0 context.Context, arg1 *types.ListDynamicConfigChangesRequest) (*types.ListDynamicConfigChangesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDynamicConfigChanges", arg0, arg1)
+	ret0, _ := ret[0].(*types.ListDynamicConfigChangesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDynamicConfigChanges indicates an expected call of ListDynamicConfigChanges.
+func (mr *MockAdminHandlerMockRecorder) ListDynamicConfigChanges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDynamicConfigChanges", reflect.TypeOf((*MockAdminHandler)(nil).ListDynamicConfigChanges), arg0, arg1)
+}
+
+// ListEffectiveDynamicConfig mocks base method.
+func (m *MockAdminHandler) ListEffectiveDynamicConfig(arg0 context.Context, arg1 *types.ListEffectiveDynamicConfigRequest) (*types.ListEffectiveDynamicConfigResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEffectiveDynamicConfig", arg0, arg1)
+	ret0, _ := ret[0].(*types.ListEffectiveDynamicConfigResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEffectiveDynamicConfig indicates an expected call of ListEffectiveDynamicConfig.
+func (mr *MockAdminHandlerMockRecorder) ListEffectiveDynamicConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEffectiveDynamicConfig", reflect.TypeOf((*MockAdminHandler)(nil).ListEffectiveDynamicConfig), arg0, arg1)
+}
+
 // MaintainCorruptWorkflow mocks base method.
 func (m *MockAdminHandler) MaintainCorruptWorkflow(arg0 context.Context, arg1 *types.AdminMaintainWorkflowRequest) (*types.AdminMaintainWorkflowResponse, error) {
 	m.ctrl.T.Helper()
@@ -326,6 +461,20 @@ func (mr *MockAdminHandlerMockRecorder) MergeDLQMessages(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeDLQMessages", reflect.TypeOf((*MockAdminHandler)(nil).MergeDLQMessages), arg0, arg1)
 }
 
+// PauseTaskList mocks base method.
+func (m *MockAdminHandler) PauseTaskList(arg0 context.Context, arg1 *types.AdminPauseTaskListRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseTaskList", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseTaskList indicates an expected call of PauseTaskList.
+func (mr *MockAdminHandlerMockRecorder) PauseTaskList(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseTaskList", reflect.TypeOf((*MockAdminHandler)(nil).PauseTaskList), arg0, arg1)
+}
+
 // PurgeDLQMessages mocks base method.
 func (m *MockAdminHandler) PurgeDLQMessages(arg0 context.Context, arg1 *types.PurgeDLQMessagesRequest) error {
 	m.ctrl.T.Helper()
@@ -454,6 +603,20 @@ func (mr *MockAdminHandlerMockRecorder) RestoreDynamicConfig(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDynamicConfig", reflect.TypeOf((*MockAdminHandler)(nil).RestoreDynamicConfig), arg0, arg1)
 }
 
+// ResumeTaskList mocks base method.
+func (m *MockAdminHandler) ResumeTaskList(arg0 context.Context, arg1 *types.AdminResumeTaskListRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeTaskList", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeTaskList indicates an expected call of ResumeTaskList.
+func (mr *MockAdminHandlerMockRecorder) ResumeTaskList(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeTaskList", reflect.TypeOf((*MockAdminHandler)(nil).ResumeTaskList), arg0, arg1)
+}
+
 // Start mocks base method.
 func (m *MockAdminHandler) Start() {
 	m.ctrl.T.Helper()
@@ -478,6 +641,20 @@ func (mr *MockAdminHandlerMockRecorder) Stop() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockAdminHandler)(nil).Stop))
 }
 
+// UnloadTaskList mocks base method.
+func (m *MockAdminHandler) UnloadTaskList(arg0 context.Context, arg1 *types.AdminUnloadTaskListRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnloadTaskList", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnloadTaskList indicates an expected call of UnloadTaskList.
+func (mr *MockAdminHandlerMockRecorder) UnloadTaskList(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadTaskList", reflect.TypeOf((*MockAdminHandler)(nil).UnloadTaskList), arg0, arg1)
+}
+
 // UpdateDynamicConfig mocks base method.
 func (m *MockAdminHandler) UpdateDynamicConfig(arg0 context.Context, arg1 *types.UpdateDynamicConfigRequest) error {
 	m.ctrl.T.Helper()
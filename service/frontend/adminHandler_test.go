@@ -26,6 +26,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
@@ -45,6 +46,8 @@ import (
 	"github.com/uber/cadence/common/mocks"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/resource"
+	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/slo"
 	"github.com/uber/cadence/common/types"
 )
 
@@ -194,6 +197,139 @@ func (s *adminHandlerSuite) testMaintainCorruptWorkflow(
 	s.Nil(err)
 }
 
+func (s *adminHandlerSuite) TestDeleteWorkflow_MutableStateNotLoadable() {
+	handler := s.handler
+	handler.params = &resource.Params{}
+	ctx := context.Background()
+
+	request := &types.AdminDeleteWorkflowRequest{
+		Domain: s.domainName,
+		Execution: &types.WorkflowExecution{
+			WorkflowID: "someWorkflowID",
+			RunID:      uuid.New(),
+		},
+		SkipErrors: true,
+	}
+
+	hostInfo := membership.NewHostInfo("taskListA:thriftPort")
+	s.mockResolver.EXPECT().Lookup(gomock.Any(), gomock.Any()).Return(hostInfo, nil)
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(s.domainID, nil).Times(2)
+	s.mockDomainCache.EXPECT().GetDomainName(s.domainID).Return(s.domainName, nil).AnyTimes()
+	s.mockHistoryClient.EXPECT().DescribeMutableState(gomock.Any(), gomock.Any()).
+		Return(nil, &types.InternalDataInconsistencyError{Message: "corrupted mutable state"})
+
+	s.mockResource.ExecutionMgr.On("DeleteWorkflowExecution", mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockResource.ExecutionMgr.On("DeleteCurrentWorkflowExecution", mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockResource.VisibilityMgr.On("DeleteWorkflowExecution", mock.Anything, mock.Anything).Return(nil).Once()
+
+	resp, err := handler.DeleteWorkflow(ctx, request)
+	s.NoError(err)
+	s.False(resp.HistoryDeleted)
+	s.True(resp.ExecutionsDeleted)
+	s.True(resp.VisibilityDeleted)
+}
+
+func (s *adminHandlerSuite) TestDeleteWorkflow_MutableStateNotLoadable_NoSkipErrors() {
+	request := &types.AdminDeleteWorkflowRequest{
+		Domain: s.domainName,
+		Execution: &types.WorkflowExecution{
+			WorkflowID: "someWorkflowID",
+			RunID:      uuid.New(),
+		},
+	}
+
+	hostInfo := membership.NewHostInfo("taskListA:thriftPort")
+	s.mockResolver.EXPECT().Lookup(gomock.Any(), gomock.Any()).Return(hostInfo, nil)
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(s.domainID, nil)
+	s.mockHistoryClient.EXPECT().DescribeMutableState(gomock.Any(), gomock.Any()).
+		Return(nil, &types.InternalDataInconsistencyError{Message: "corrupted mutable state"})
+
+	_, err := s.handler.DeleteWorkflow(context.Background(), request)
+	s.Error(err)
+}
+
+func (s *adminHandlerSuite) TestUnloadTaskList() {
+	ctx := context.Background()
+	taskList := &types.TaskList{Name: "some random task list"}
+
+	err := s.handler.UnloadTaskList(ctx, &types.AdminUnloadTaskListRequest{Domain: s.domainName, TaskList: taskList})
+	s.IsType(&types.BadRequestError{}, err)
+
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(s.domainID, nil).Times(1)
+	s.mockResource.MatchingClient.EXPECT().UnloadTaskList(ctx, &types.MatchingUnloadTaskListRequest{
+		DomainUUID:         s.domainID,
+		TaskList:           taskList,
+		TaskListType:       types.TaskListTypeActivity.Ptr(),
+		BlockReloadSeconds: 10,
+	}).Return(nil).Times(1)
+	err = s.handler.UnloadTaskList(ctx, &types.AdminUnloadTaskListRequest{
+		Domain:             s.domainName,
+		TaskList:           taskList,
+		TaskListType:       types.TaskListTypeActivity.Ptr(),
+		BlockReloadSeconds: 10,
+	})
+	s.NoError(err)
+}
+
+func (s *adminHandlerSuite) TestGetTaskListDrainStatus() {
+	ctx := context.Background()
+	taskList := &types.TaskList{Name: "some random task list"}
+
+	_, err := s.handler.GetTaskListDrainStatus(ctx, &types.AdminGetTaskListDrainStatusRequest{Domain: s.domainName, TaskList: taskList})
+	s.IsType(&types.BadRequestError{}, err)
+
+	expected := &types.GetTaskListDrainStatusResponse{
+		Draining:   true,
+		Drained:    true,
+		Partitions: []*types.TaskListPartitionDrainStatus{{TaskList: taskList.Name, Drained: true}},
+	}
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(s.domainID, nil).Times(1)
+	s.mockResource.MatchingClient.EXPECT().GetTaskListDrainStatus(ctx, &types.MatchingGetTaskListDrainStatusRequest{
+		DomainUUID:   s.domainID,
+		TaskList:     taskList,
+		TaskListType: types.TaskListTypeDecision.Ptr(),
+	}).Return(expected, nil).Times(1)
+	response, err := s.handler.GetTaskListDrainStatus(ctx, &types.AdminGetTaskListDrainStatusRequest{
+		Domain:       s.domainName,
+		TaskList:     taskList,
+		TaskListType: types.TaskListTypeDecision.Ptr(),
+	})
+	s.NoError(err)
+	s.Equal(expected, response)
+}
+
+func (s *adminHandlerSuite) TestPauseAndResumeTaskList() {
+	ctx := context.Background()
+	taskList := &types.TaskList{Name: "some random task list"}
+
+	err := s.handler.PauseTaskList(ctx, &types.AdminPauseTaskListRequest{Domain: s.domainName, TaskList: taskList})
+	s.IsType(&types.BadRequestError{}, err)
+	err = s.handler.ResumeTaskList(ctx, &types.AdminResumeTaskListRequest{TaskList: taskList, TaskListType: types.TaskListTypeDecision.Ptr()})
+	s.IsType(&types.BadRequestError{}, err)
+
+	s.mockDomainCache.EXPECT().GetDomainID(s.domainName).Return(s.domainID, nil).Times(2)
+	s.mockResource.MatchingClient.EXPECT().PauseTaskList(ctx, &types.MatchingPauseTaskListRequest{
+		DomainUUID:   s.domainID,
+		TaskList:     taskList,
+		TaskListType: types.TaskListTypeActivity.Ptr(),
+	}).Return(nil).Times(1)
+	s.mockResource.MatchingClient.EXPECT().ResumeTaskList(ctx, &types.MatchingResumeTaskListRequest{
+		DomainUUID:   s.domainID,
+		TaskList:     taskList,
+		TaskListType: types.TaskListTypeActivity.Ptr(),
+	}).Return(nil).Times(1)
+	s.NoError(s.handler.PauseTaskList(ctx, &types.AdminPauseTaskListRequest{
+		Domain:       s.domainName,
+		TaskList:     taskList,
+		TaskListType: types.TaskListTypeActivity.Ptr(),
+	}))
+	s.NoError(s.handler.ResumeTaskList(ctx, &types.AdminResumeTaskListRequest{
+		Domain:       s.domainName,
+		TaskList:     taskList,
+		TaskListType: types.TaskListTypeActivity.Ptr(),
+	}))
+}
+
 func (s *adminHandlerSuite) Test_ConvertIndexedValueTypeToESDataType() {
 	tests := []struct {
 		input    types.IndexedValueType
@@ -710,6 +846,238 @@ func (s *adminHandlerSuite) Test_ConfigStore_InvalidKey() {
 	s.Error(err)
 }
 
+func (s *adminHandlerSuite) Test_ListDynamicConfigChanges() {
+	ctx := context.Background()
+	request := &types.ListDynamicConfigChangesRequest{MaxVersion: 10, PageSize: 2}
+
+	_, err := s.handler.ListDynamicConfigChanges(ctx, &types.ListDynamicConfigChangesRequest{})
+	s.IsType(&types.BadRequestError{}, err)
+
+	s.handler.params.DynamicConfig = dynamicconfig.NewMockClient(s.controller)
+	_, err = s.handler.ListDynamicConfigChanges(ctx, request)
+	s.Equal(errConfigChangeHistoryDisabled, err)
+
+	auditedClient := dynamicconfig.NewMockAuditedClient(s.controller)
+	s.handler.params.DynamicConfig = struct {
+		*dynamicconfig.MockClient
+		*dynamicconfig.MockAuditedClient
+	}{dynamicconfig.NewMockClient(s.controller), auditedClient}
+	expected := &types.ListDynamicConfigChangesResponse{
+		Changes: []*types.DynamicConfigChange{{Version: 10, Identity: "tester", Name: "testGetBoolPropertyKey"}},
+	}
+	auditedClient.EXPECT().ListChanges(request).Return(expected, nil).Times(1)
+	response, err := s.handler.ListDynamicConfigChanges(ctx, request)
+	s.NoError(err)
+	s.Equal(expected, response)
+}
+
+func (s *adminHandlerSuite) Test_ListEffectiveDynamicConfig() {
+	ctx := context.Background()
+	key := dynamicconfig.MatchingNumTasklistReadPartitions
+	request := &types.ListEffectiveDynamicConfigRequest{
+		ConfigNamePrefix: "matching.numTasklist",
+		Filters: []*types.DynamicConfigFilter{
+			{Name: dynamicconfig.DomainName.String(), Value: &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: []byte(`"samples-domain"`)}},
+			{Name: dynamicconfig.TaskType.String(), Value: &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: []byte(`1`)}},
+		},
+	}
+
+	_, err := s.handler.ListEffectiveDynamicConfig(ctx, nil)
+	s.IsType(&types.BadRequestError{}, err)
+
+	dynamicConfig := dynamicconfig.NewMockClient(s.controller)
+	s.handler.params.DynamicConfig = dynamicConfig
+	expectedFilters := map[dynamicconfig.Filter]interface{}{
+		dynamicconfig.DomainName:  "samples-domain",
+		dynamicconfig.TaskType:    1,
+		dynamicconfig.ClusterName: s.mockResource.ClusterMetadata.GetCurrentClusterName(),
+	}
+	dynamicConfig.EXPECT().GetValueWithFilters(gomock.Any(), expectedFilters).
+		DoAndReturn(func(name dynamicconfig.Key, filters map[dynamicconfig.Filter]interface{}) (interface{}, error) {
+			if name == key {
+				return 4, nil
+			}
+			return nil, dynamicconfig.NotFoundError
+		}).AnyTimes()
+
+	response, err := s.handler.ListEffectiveDynamicConfig(ctx, request)
+	s.NoError(err)
+	s.Len(response.GetValues(), 2)
+	readPartitions := response.GetValues()[0]
+	s.Equal(key.String(), readPartitions.Name)
+	s.Equal("4", string(readPartitions.Value.Data))
+	s.Equal("1", string(readPartitions.DefaultValue.Data))
+	s.False(readPartitions.IsDefault)
+	s.Nil(readPartitions.MatchedFilters)
+	writePartitions := response.GetValues()[1]
+	s.Equal(dynamicconfig.MatchingNumTasklistWritePartitions.String(), writePartitions.Name)
+	s.True(writePartitions.IsDefault)
+}
+
+func (s *adminHandlerSuite) Test_GetDomainUsage() {
+	ctx := context.Background()
+	start := time.Date(2020, 1, 1, 0, 30, 0, 0, time.UTC)
+	request := &types.AdminGetDomainUsageRequest{
+		Domain:    "domain-a",
+		StartTime: start.UnixNano(),
+		EndTime:   start.Add(time.Hour).UnixNano(),
+	}
+
+	_, err := s.handler.GetDomainUsage(ctx, &types.AdminGetDomainUsageRequest{StartTime: 2, EndTime: 1})
+	s.Equal(errInvalidDomainUsageRange, err)
+
+	s.mockResource.PersistenceBean.EXPECT().GetConfigStoreManager().Return(nil).Times(1)
+	_, err = s.handler.GetDomainUsage(ctx, request)
+	s.Equal(errDomainUsageNotRecorded, err)
+
+	configStoreManager := persistence.NewMockConfigStoreManager(s.controller)
+	s.mockResource.PersistenceBean.EXPECT().GetConfigStoreManager().Return(configStoreManager).Times(1)
+	configStoreManager.EXPECT().ListDomainUsage(gomock.Any(), &persistence.ListDomainUsageRequest{Hour: start.Add(-time.Minute * 30)}).
+		Return(&persistence.ListDomainUsageResponse{Records: []*persistence.DomainUsageRecord{
+			// ended before the requested range
+			{IntervalEnd: start.Add(-time.Minute), Domains: map[string]*persistence.DomainUsage{"domain-a": {Actions: 100}}},
+			{IntervalEnd: start.Add(time.Minute), Domains: map[string]*persistence.DomainUsage{
+				"domain-a": {Actions: 1},
+				"domain-b": {Actions: 1},
+			}},
+		}}, nil).Times(1)
+	configStoreManager.EXPECT().ListDomainUsage(gomock.Any(), &persistence.ListDomainUsageRequest{Hour: start.Add(time.Minute * 30)}).
+		Return(&persistence.ListDomainUsageResponse{Records: []*persistence.DomainUsageRecord{
+			{IntervalEnd: start.Add(time.Minute * 31), Domains: map[string]*persistence.DomainUsage{"domain-a": {Actions: 2, TaskDispatches: 3}}},
+		}}, nil).Times(1)
+	response, err := s.handler.GetDomainUsage(ctx, request)
+	s.NoError(err)
+	s.Equal(map[string]*types.DomainUsage{"domain-a": {Actions: 3, TaskDispatches: 3}}, response.Domains)
+}
+
+func (s *adminHandlerSuite) Test_InvalidateCaches() {
+	ctx := context.Background()
+	historyHost := membership.NewHostInfo("10.0.0.1:7934")
+	matchingHost := membership.NewHostInfo("10.0.0.2:7935")
+	notFound := errors.New("host not found")
+
+	_, err := s.handler.InvalidateCaches(ctx, &types.InvalidateCachesRequest{})
+	s.Equal(errCacheMatchNotSet, err)
+
+	// the frontend serving the request has no cache registry in tests
+	resp, err := s.handler.InvalidateCaches(ctx, &types.InvalidateCachesRequest{Match: "domain-a"})
+	s.NoError(err)
+	s.Empty(resp.Invalidated)
+
+	request := &types.InvalidateCachesRequest{HostAddress: historyHost.GetAddress(), Match: "domain-a"}
+	expected := &types.InvalidateCachesResponse{Invalidated: map[string]int32{"history/domain": 1}}
+	s.mockResolver.EXPECT().LookupByAddress(service.History, historyHost.GetAddress()).Return(historyHost, nil).Times(1)
+	s.mockHistoryClient.EXPECT().InvalidateCaches(ctx, request).Return(expected, nil).Times(1)
+	resp, err = s.handler.InvalidateCaches(ctx, request)
+	s.NoError(err)
+	s.Equal(expected, resp)
+
+	request = &types.InvalidateCachesRequest{HostAddress: matchingHost.GetAddress(), Match: "domain-a"}
+	s.mockResolver.EXPECT().LookupByAddress(service.History, matchingHost.GetAddress()).Return(membership.HostInfo{}, notFound).Times(1)
+	s.mockResolver.EXPECT().LookupByAddress(service.Matching, matchingHost.GetAddress()).Return(matchingHost, nil).Times(1)
+	s.mockResource.MatchingClient.EXPECT().InvalidateCaches(ctx, request).Return(expected, nil).Times(1)
+	_, err = s.handler.InvalidateCaches(ctx, request)
+	s.NoError(err)
+
+	s.mockResolver.EXPECT().LookupByAddress(gomock.Any(), "10.0.0.3:7933").Return(membership.HostInfo{}, notFound).Times(2)
+	_, err = s.handler.InvalidateCaches(ctx, &types.InvalidateCachesRequest{HostAddress: "10.0.0.3:7933", Match: "domain-a"})
+	s.IsType(&types.BadRequestError{}, err)
+}
+
+func (s *adminHandlerSuite) Test_DescribeShardOwnership() {
+	ctx := context.Background()
+	historyHost := membership.NewHostInfo("10.0.0.1:7934")
+	matchingHost := membership.NewHostInfo("10.0.0.2:7935")
+
+	// the ownership is only known by history hosts
+	_, err := s.handler.DescribeShardOwnership(ctx, &types.DescribeShardOwnershipRequest{})
+	s.Equal(errNotHistoryHost, err)
+
+	s.mockResolver.EXPECT().LookupByAddress(service.History, matchingHost.GetAddress()).Return(membership.HostInfo{}, errors.New("host not found")).Times(1)
+	s.mockResolver.EXPECT().LookupByAddress(service.Matching, matchingHost.GetAddress()).Return(matchingHost, nil).Times(1)
+	_, err = s.handler.DescribeShardOwnership(ctx, &types.DescribeShardOwnershipRequest{HostAddress: matchingHost.GetAddress()})
+	s.Equal(errNotHistoryHost, err)
+
+	request := &types.DescribeShardOwnershipRequest{HostAddress: historyHost.GetAddress()}
+	expected := &types.DescribeShardOwnershipResponse{NumberOfShards: 1, Host: historyHost.Identity()}
+	s.mockResolver.EXPECT().LookupByAddress(service.History, historyHost.GetAddress()).Return(historyHost, nil).Times(1)
+	s.mockHistoryClient.EXPECT().DescribeShardOwnership(ctx, request).Return(expected, nil).Times(1)
+	resp, err := s.handler.DescribeShardOwnership(ctx, request)
+	s.NoError(err)
+	s.Equal(expected, resp)
+}
+
+func (s *adminHandlerSuite) Test_DescribePersistenceLatencyHeatmap() {
+	ctx := context.Background()
+	historyHost := membership.NewHostInfo("10.0.0.1:7934")
+
+	_, err := s.handler.DescribePersistenceLatencyHeatmap(ctx, &types.DescribePersistenceLatencyHeatmapRequest{WindowInSeconds: -1})
+	s.Equal(errInvalidHeatmapWindow, err)
+
+	_, err = s.handler.DescribePersistenceLatencyHeatmap(ctx, &types.DescribePersistenceLatencyHeatmapRequest{})
+	s.Equal(errNotHistoryHost, err)
+
+	request := &types.DescribePersistenceLatencyHeatmapRequest{HostAddress: historyHost.GetAddress(), WindowInSeconds: 300}
+	expected := &types.DescribePersistenceLatencyHeatmapResponse{
+		Cells: []*types.PersistenceLatencyCell{{ShardID: 1, Operation: "GetWorkflowExecution", Count: 1, P99InMilliseconds: 2}},
+	}
+	s.mockResolver.EXPECT().LookupByAddress(service.History, historyHost.GetAddress()).Return(historyHost, nil).Times(1)
+	s.mockHistoryClient.EXPECT().DescribePersistenceLatencyHeatmap(ctx, request).Return(expected, nil).Times(1)
+	resp, err := s.handler.DescribePersistenceLatencyHeatmap(ctx, request)
+	s.NoError(err)
+	s.Equal(expected, resp)
+}
+
+func (s *adminHandlerSuite) Test_DescribeWatchdog() {
+	ctx := context.Background()
+	historyHost := membership.NewHostInfo("10.0.0.1:7934")
+	matchingHost := membership.NewHostInfo("10.0.0.2:7935")
+
+	// the frontend serving the request has no watchdog in tests
+	resp, err := s.handler.DescribeWatchdog(ctx, &types.DescribeWatchdogRequest{})
+	s.NoError(err)
+	s.Empty(resp.Resources)
+
+	request := &types.DescribeWatchdogRequest{HostAddress: historyHost.GetAddress()}
+	expected := &types.DescribeWatchdogResponse{Service: service.History, Resources: []*types.WatchdogResource{{Name: "goroutines", Value: 10}}}
+	s.mockResolver.EXPECT().LookupByAddress(service.History, historyHost.GetAddress()).Return(historyHost, nil).Times(1)
+	s.mockHistoryClient.EXPECT().DescribeWatchdog(ctx, request).Return(expected, nil).Times(1)
+	resp, err = s.handler.DescribeWatchdog(ctx, request)
+	s.NoError(err)
+	s.Equal(expected, resp)
+
+	request = &types.DescribeWatchdogRequest{HostAddress: matchingHost.GetAddress()}
+	s.mockResolver.EXPECT().LookupByAddress(service.History, matchingHost.GetAddress()).Return(membership.HostInfo{}, errors.New("host not found")).Times(1)
+	s.mockResolver.EXPECT().LookupByAddress(service.Matching, matchingHost.GetAddress()).Return(matchingHost, nil).Times(1)
+	s.mockResource.MatchingClient.EXPECT().DescribeWatchdog(ctx, request).Return(expected, nil).Times(1)
+	_, err = s.handler.DescribeWatchdog(ctx, request)
+	s.NoError(err)
+}
+
+func (s *adminHandlerSuite) Test_DescribeDomainSLO() {
+	ctx := context.Background()
+
+	// the SLOs are not tracked without a tracker
+	resp, err := s.handler.DescribeDomainSLO(ctx, &types.DescribeDomainSLORequest{})
+	s.NoError(err)
+	s.Empty(resp.Domains)
+
+	s.handler.params.SLOTracker = slo.NewTracker(&slo.Config{
+		AvailabilityTarget: func(domain string) float64 { return 0.99 },
+		LatencyTarget:      func(domain string) float64 { return 0 },
+		LatencyThreshold:   dynamicconfig.GetDurationPropertyFnFilteredByDomain(time.Second),
+	}, metrics.NewNoopMetricsClient(), s.mockResource.TimeSource)
+	s.handler.params.SLOTracker.Record("domain-a", "StartWorkflowExecution", time.Millisecond, false)
+	s.handler.params.SLOTracker.Record("domain-b", "StartWorkflowExecution", time.Millisecond, true)
+
+	resp, err = s.handler.DescribeDomainSLO(ctx, &types.DescribeDomainSLORequest{Domain: "domain-b"})
+	s.NoError(err)
+	s.Len(resp.Domains, 1)
+	s.Equal("domain-b", resp.Domains[0].Domain)
+	s.Nil(resp.Domains[0].Latency)
+	s.Equal(int64(1), resp.Domains[0].Availability.BadRequests)
+}
+
 func (s *adminHandlerSuite) Test_GetDynamicConfig_NoFilter() {
 	ctx := context.Background()
 	handler := s.handler
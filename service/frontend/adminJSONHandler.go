@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+
+	"go.uber.org/yarpc"
+	yarpcjson "go.uber.org/yarpc/encoding/json"
+
+	"github.com/uber/cadence/client/admin"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/types/mapper/json"
+)
+
+// adminJSONHandler serves the admin APIs which are not in the admin IDL yet with the json encoding
+type adminJSONHandler struct {
+	h AdminHandler
+}
+
+func newAdminJSONHandler(h AdminHandler) adminJSONHandler {
+	return adminJSONHandler{h}
+}
+
+func (j adminJSONHandler) register(dispatcher *yarpc.Dispatcher) {
+	dispatcher.Register(yarpcjson.Procedure(admin.UnloadTaskListProcedure, j.UnloadTaskList))
+	dispatcher.Register(yarpcjson.Procedure(admin.GetTaskListDrainStatusProcedure, j.GetTaskListDrainStatus))
+	dispatcher.Register(yarpcjson.Procedure(admin.PauseTaskListProcedure, j.PauseTaskList))
+	dispatcher.Register(yarpcjson.Procedure(admin.ResumeTaskListProcedure, j.ResumeTaskList))
+	dispatcher.Register(yarpcjson.Procedure(admin.ListDynamicConfigChangesProcedure, j.ListDynamicConfigChanges))
+	dispatcher.Register(yarpcjson.Procedure(admin.GetDomainUsageProcedure, j.GetDomainUsage))
+	dispatcher.Register(yarpcjson.Procedure(admin.InvalidateCachesProcedure, j.InvalidateCaches))
+	dispatcher.Register(yarpcjson.Procedure(admin.ListEffectiveDynamicConfigProcedure, j.ListEffectiveDynamicConfig))
+	dispatcher.Register(yarpcjson.Procedure(admin.DescribeShardOwnershipProcedure, j.DescribeShardOwnership))
+	dispatcher.Register(yarpcjson.Procedure(admin.DescribePersistenceLatencyHeatmapProcedure, j.DescribePersistenceLatencyHeatmap))
+	dispatcher.Register(yarpcjson.Procedure(admin.DescribeWatchdogProcedure, j.DescribeWatchdog))
+	dispatcher.Register(yarpcjson.Procedure(admin.DescribeDomainSLOProcedure, j.DescribeDomainSLO))
+}
+
+func (j adminJSONHandler) UnloadTaskList(ctx context.Context, request *types.AdminUnloadTaskListRequest) (*struct{}, error) {
+	err := j.h.UnloadTaskList(ctx, request)
+	return &struct{}{}, json.FromError(err)
+}
+
+func (j adminJSONHandler) GetTaskListDrainStatus(ctx context.Context, request *types.AdminGetTaskListDrainStatusRequest) (*types.GetTaskListDrainStatusResponse, error) {
+	response, err := j.h.GetTaskListDrainStatus(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j adminJSONHandler) PauseTaskList(ctx context.Context, request *types.AdminPauseTaskListRequest) (*struct{}, error) {
+	err := j.h.PauseTaskList(ctx, request)
+	return &struct{}{}, json.FromError(err)
+}
+
+func (j adminJSONHandler) ResumeTaskList(ctx context.Context, request *types.AdminResumeTaskListRequest) (*struct{}, error) {
+	err := j.h.ResumeTaskList(ctx, request)
+	return &struct{}{}, json.FromError(err)
+}
+
+func (j adminJSONHandler) ListDynamicConfigChanges(ctx context.Context, request *types.ListDynamicConfigChangesRequest) (*types.ListDynamicConfigChangesResponse, error) {
+	response, err := j.h.ListDynamicConfigChanges(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j adminJSONHandler) GetDomainUsage(ctx context.Context, request *types.AdminGetDomainUsageRequest) (*types.AdminGetDomainUsageResponse, error) {
+	response, err := j.h.GetDomainUsage(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j adminJSONHandler) InvalidateCaches(ctx context.Context, request *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error) {
+	response, err := j.h.InvalidateCaches(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j adminJSONHandler) ListEffectiveDynamicConfig(ctx context.Context, request *types.ListEffectiveDynamicConfigRequest) (*types.ListEffectiveDynamicConfigResponse, error) {
+	response, err := j.h.ListEffectiveDynamicConfig(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j adminJSONHandler) DescribeShardOwnership(ctx context.Context, request *types.DescribeShardOwnershipRequest) (*types.DescribeShardOwnershipResponse, error) {
+	response, err := j.h.DescribeShardOwnership(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j adminJSONHandler) DescribePersistenceLatencyHeatmap(ctx context.Context, request *types.DescribePersistenceLatencyHeatmapRequest) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	response, err := j.h.DescribePersistenceLatencyHeatmap(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j adminJSONHandler) DescribeWatchdog(ctx context.Context, request *types.DescribeWatchdogRequest) (*types.DescribeWatchdogResponse, error) {
+	response, err := j.h.DescribeWatchdog(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j adminJSONHandler) DescribeDomainSLO(ctx context.Context, request *types.DescribeDomainSLORequest) (*types.DescribeDomainSLOResponse, error) {
+	response, err := j.h.DescribeDomainSLO(ctx, request)
+	return response, json.FromError(err)
+}
@@ -0,0 +1,275 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pborman/uuid"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	proberWorkflowType    = "cadence-prober-workflow"
+	proberSignalName      = "cadence-prober-signal"
+	proberQueryType       = "cadence-prober-query"
+	proberWorkflowTimeout = 5 * time.Minute
+	proberRequestTimeout  = 10 * time.Second
+	proberPollTimeout     = time.Minute
+	// proberIdleInterval is how often a disabled prober checks whether it was enabled
+	proberIdleInterval = time.Minute
+)
+
+var proberQueryAnswer = []byte("ok")
+
+type (
+	// prober continuously starts, signals, describes and queries synthetic workflows in a dedicated domain
+	// through the local workflow handler, and polls their tasks itself, so that the availability and latency
+	// of the workflow APIs are measured independently of user traffic
+	prober struct {
+		status        int32
+		handler       Handler
+		config        *Config
+		identity      string
+		taskList      string
+		metricsClient metrics.Client
+		logger        log.Logger
+		ctx           context.Context
+		cancel        context.CancelFunc
+		shutdownWG    sync.WaitGroup
+	}
+)
+
+func newProber(
+	handler Handler,
+	config *Config,
+	identity string,
+	metricsClient metrics.Client,
+	logger log.Logger,
+) *prober {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &prober{
+		status:        common.DaemonStatusInitialized,
+		handler:       handler,
+		config:        config,
+		identity:      identity,
+		taskList:      "cadence-prober-" + identity,
+		metricsClient: metricsClient,
+		logger:        logger.WithTags(tag.ComponentProber),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+func (p *prober) Start() {
+	if !atomic.CompareAndSwapInt32(&p.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	p.shutdownWG.Add(2)
+	go p.probeLoop()
+	go p.pollLoop()
+
+	p.logger.Info("Prober started.")
+}
+
+func (p *prober) Stop() {
+	if !atomic.CompareAndSwapInt32(&p.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+
+	p.cancel()
+	p.shutdownWG.Wait()
+	p.logger.Info("Prober stopped.")
+}
+
+func (p *prober) probeLoop() {
+	defer p.shutdownWG.Done()
+
+	for {
+		interval := p.config.ProberInterval()
+		if interval > 0 {
+			p.probe()
+		} else {
+			interval = proberIdleInterval
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// probe exercises the workflow APIs on a new synthetic workflow, the APIs following a failed one are skipped
+func (p *prober) probe() {
+	domain := p.config.ProberDomain()
+	execution := &types.WorkflowExecution{
+		WorkflowID: "cadence-prober-" + uuid.New(),
+	}
+
+	err := p.call("StartWorkflowExecution", func(ctx context.Context) error {
+		resp, err := p.handler.StartWorkflowExecution(ctx, &types.StartWorkflowExecutionRequest{
+			Domain:                              domain,
+			WorkflowID:                          execution.WorkflowID,
+			WorkflowType:                        &types.WorkflowType{Name: proberWorkflowType},
+			TaskList:                            &types.TaskList{Name: p.taskList},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(int32(proberWorkflowTimeout.Seconds())),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(int32(proberRequestTimeout.Seconds())),
+			Identity:                            p.identity,
+			RequestID:                           uuid.New(),
+		})
+		if err == nil {
+			execution.RunID = resp.GetRunID()
+		}
+		return err
+	})
+	if err != nil {
+		return
+	}
+
+	err = p.call("SignalWorkflowExecution", func(ctx context.Context) error {
+		return p.handler.SignalWorkflowExecution(ctx, &types.SignalWorkflowExecutionRequest{
+			Domain:            domain,
+			WorkflowExecution: execution,
+			SignalName:        proberSignalName,
+			Identity:          p.identity,
+			RequestID:         uuid.New(),
+		})
+	})
+	if err != nil {
+		return
+	}
+
+	err = p.call("DescribeWorkflowExecution", func(ctx context.Context) error {
+		_, err := p.handler.DescribeWorkflowExecution(ctx, &types.DescribeWorkflowExecutionRequest{
+			Domain:    domain,
+			Execution: execution,
+		})
+		return err
+	})
+	if err != nil {
+		return
+	}
+
+	_ = p.call("QueryWorkflow", func(ctx context.Context) error {
+		_, err := p.handler.QueryWorkflow(ctx, &types.QueryWorkflowRequest{
+			Domain:    domain,
+			Execution: execution,
+			Query:     &types.WorkflowQuery{QueryType: proberQueryType},
+		})
+		return err
+	})
+}
+
+func (p *prober) call(api string, fn func(ctx context.Context) error) error {
+	scope := p.metricsClient.Scope(metrics.FrontendProberScope, metrics.APINameTag(api))
+	scope.IncCounter(metrics.ProberRequests)
+	sw := scope.StartTimer(metrics.ProberLatency)
+	defer sw.Stop()
+
+	ctx, cancel := context.WithTimeout(p.ctx, proberRequestTimeout)
+	defer cancel()
+	err := fn(ctx)
+	if err != nil && p.ctx.Err() == nil {
+		scope.IncCounter(metrics.ProberFailures)
+		p.logger.Warn("Prober request failed.", tag.APIName(api), tag.Error(err))
+	}
+	return err
+}
+
+// pollLoop acts as the worker of the synthetic workflows: it completes them on their first decision and answers their queries
+func (p *prober) pollLoop() {
+	defer p.shutdownWG.Done()
+
+	for p.ctx.Err() == nil {
+		if p.config.ProberInterval() <= 0 {
+			select {
+			case <-p.ctx.Done():
+			case <-time.After(proberIdleInterval):
+			}
+			continue
+		}
+
+		if err := p.pollAndRespond(); err != nil && p.ctx.Err() == nil {
+			p.logger.Warn("Prober failed to process decision task.", tag.Error(err))
+			select {
+			case <-p.ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+func (p *prober) pollAndRespond() error {
+	ctx, cancel := context.WithTimeout(p.ctx, proberPollTimeout)
+	defer cancel()
+
+	task, err := p.handler.PollForDecisionTask(ctx, &types.PollForDecisionTaskRequest{
+		Domain:   p.config.ProberDomain(),
+		TaskList: &types.TaskList{Name: p.taskList},
+		Identity: p.identity,
+	})
+	if err != nil || len(task.GetTaskToken()) == 0 {
+		// empty poll
+		return err
+	}
+
+	ctx, cancel = context.WithTimeout(p.ctx, proberRequestTimeout)
+	defer cancel()
+
+	if task.Query != nil {
+		return p.handler.RespondQueryTaskCompleted(ctx, &types.RespondQueryTaskCompletedRequest{
+			TaskToken:     task.TaskToken,
+			CompletedType: types.QueryTaskCompletedTypeCompleted.Ptr(),
+			QueryResult:   proberQueryAnswer,
+		})
+	}
+
+	queryResults := make(map[string]*types.WorkflowQueryResult, len(task.Queries))
+	for queryID := range task.Queries {
+		queryResults[queryID] = &types.WorkflowQueryResult{
+			ResultType: types.QueryResultTypeAnswered.Ptr(),
+			Answer:     proberQueryAnswer,
+		}
+	}
+	_, err = p.handler.RespondDecisionTaskCompleted(ctx, &types.RespondDecisionTaskCompletedRequest{
+		TaskToken: task.TaskToken,
+		Decisions: []*types.Decision{{
+			DecisionType: types.DecisionTypeCompleteWorkflowExecution.Ptr(),
+			CompleteWorkflowExecutionDecisionAttributes: &types.CompleteWorkflowExecutionDecisionAttributes{},
+		}},
+		Identity:     p.identity,
+		QueryResults: queryResults,
+	})
+	return err
+}
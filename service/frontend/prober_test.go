@@ -0,0 +1,120 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestProber_Probe(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	handler := NewMockHandler(controller)
+	testScope := tally.NewTestScope("test", nil)
+	p := newTestProber(handler, testScope)
+
+	handler.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *types.StartWorkflowExecutionRequest) (*types.StartWorkflowExecutionResponse, error) {
+			assert.Equal(t, "probe-domain", request.Domain)
+			assert.Equal(t, "cadence-prober-host", request.TaskList.GetName())
+			return &types.StartWorkflowExecutionResponse{RunID: "run"}, nil
+		}).Times(2)
+	handler.EXPECT().SignalWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *types.SignalWorkflowExecutionRequest) error {
+			assert.Equal(t, "run", request.WorkflowExecution.GetRunID())
+			return nil
+		}).Times(2)
+	handler.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(&types.DescribeWorkflowExecutionResponse{}, nil).Times(1)
+	handler.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil, &types.InternalServiceError{}).Times(1)
+	handler.EXPECT().QueryWorkflow(gomock.Any(), gomock.Any()).Return(&types.QueryWorkflowResponse{}, nil).Times(1)
+
+	p.probe()
+	p.probe()
+
+	counters := map[string]int64{}
+	for _, c := range testScope.Snapshot().Counters() {
+		counters[c.Name()+"/"+c.Tags()["api_name"]] = c.Value()
+	}
+	assert.Equal(t, map[string]int64{
+		"test.prober_requests/StartWorkflowExecution":    2,
+		"test.prober_requests/SignalWorkflowExecution":   2,
+		"test.prober_requests/DescribeWorkflowExecution": 2,
+		"test.prober_errors/DescribeWorkflowExecution":   1,
+		"test.prober_requests/QueryWorkflow":             1,
+	}, counters, "the query is skipped once the describe failed")
+}
+
+func TestProber_PollAndRespond(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	handler := NewMockHandler(controller)
+	p := newTestProber(handler, tally.NoopScope)
+
+	handler.EXPECT().PollForDecisionTask(gomock.Any(), gomock.Any()).Return(&types.PollForDecisionTaskResponse{}, nil).Times(1)
+	assert.NoError(t, p.pollAndRespond())
+
+	handler.EXPECT().PollForDecisionTask(gomock.Any(), gomock.Any()).Return(&types.PollForDecisionTaskResponse{
+		TaskToken: []byte("query-task"),
+		Query:     &types.WorkflowQuery{QueryType: proberQueryType},
+	}, nil).Times(1)
+	handler.EXPECT().RespondQueryTaskCompleted(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *types.RespondQueryTaskCompletedRequest) error {
+			assert.Equal(t, []byte("query-task"), request.TaskToken)
+			assert.Equal(t, proberQueryAnswer, request.QueryResult)
+			return nil
+		}).Times(1)
+	assert.NoError(t, p.pollAndRespond())
+
+	handler.EXPECT().PollForDecisionTask(gomock.Any(), gomock.Any()).Return(&types.PollForDecisionTaskResponse{
+		TaskToken: []byte("decision-task"),
+		Queries:   map[string]*types.WorkflowQuery{"query-id": {QueryType: proberQueryType}},
+	}, nil).Times(1)
+	handler.EXPECT().RespondDecisionTaskCompleted(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *types.RespondDecisionTaskCompletedRequest) (*types.RespondDecisionTaskCompletedResponse, error) {
+			assert.Len(t, request.Decisions, 1)
+			assert.Equal(t, types.DecisionTypeCompleteWorkflowExecution, request.Decisions[0].GetDecisionType())
+			assert.Equal(t, proberQueryAnswer, request.QueryResults["query-id"].Answer)
+			return &types.RespondDecisionTaskCompletedResponse{}, nil
+		}).Times(1)
+	assert.NoError(t, p.pollAndRespond())
+}
+
+func newTestProber(handler Handler, scope tally.Scope) *prober {
+	config := &Config{
+		ProberInterval: dynamicconfig.GetDurationPropertyFn(0),
+		ProberDomain:   dynamicconfig.GetStringPropertyFn("probe-domain"),
+	}
+	return newProber(handler, config, "host", metrics.NewClient(scope, metrics.Frontend), log.NewNoop())
+}
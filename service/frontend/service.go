@@ -30,6 +30,7 @@ import (
 	"github.com/uber/cadence/common/client"
 	"github.com/uber/cadence/common/domain"
 	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/resource"
 	"github.com/uber/cadence/common/service"
 )
@@ -60,6 +61,9 @@ type Config struct {
 	GlobalDomainUserRPS               dynamicconfig.IntPropertyFnWithDomainFilter
 	GlobalDomainWorkerRPS             dynamicconfig.IntPropertyFnWithDomainFilter
 	GlobalDomainVisibilityRPS         dynamicconfig.IntPropertyFnWithDomainFilter
+	MaxCallerUserRPSPerInstance       dynamicconfig.IntPropertyFnWithDomainFilter
+	MaxCallerWorkerRPSPerInstance     dynamicconfig.IntPropertyFnWithDomainFilter
+	MaxCallerVisibilityRPSPerInstance dynamicconfig.IntPropertyFnWithDomainFilter
 	EnableClientVersionCheck          dynamicconfig.BoolPropertyFn
 	EnableQueryAttributeValidation    dynamicconfig.BoolPropertyFn
 	DisallowQuery                     dynamicconfig.BoolPropertyFnWithDomainFilter
@@ -114,6 +118,10 @@ type Config struct {
 
 	// Emit signal related metrics with signal name tag. Be aware of cardinality.
 	EmitSignalNameMetricsTag dynamicconfig.BoolPropertyFnWithDomainFilter
+
+	// Synthetic probing of the workflow APIs
+	ProberInterval dynamicconfig.DurationPropertyFn
+	ProberDomain   dynamicconfig.StringPropertyFn
 }
 
 // NewConfig returns new service config with default values
@@ -140,6 +148,9 @@ func NewConfig(dc *dynamicconfig.Collection, numHistoryShards int, isAdvancedVis
 		GlobalDomainUserRPS:                         dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendGlobalDomainUserRPS),
 		GlobalDomainWorkerRPS:                       dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendGlobalDomainWorkerRPS),
 		GlobalDomainVisibilityRPS:                   dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendGlobalDomainVisibilityRPS),
+		MaxCallerUserRPSPerInstance:                 dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendMaxCallerUserRPSPerInstance),
+		MaxCallerWorkerRPSPerInstance:               dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendMaxCallerWorkerRPSPerInstance),
+		MaxCallerVisibilityRPSPerInstance:           dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendMaxCallerVisibilityRPSPerInstance),
 		MaxIDLengthWarnLimit:                        dc.GetIntProperty(dynamicconfig.MaxIDLengthWarnLimit),
 		DomainNameMaxLength:                         dc.GetIntPropertyFilteredByDomain(dynamicconfig.DomainNameMaxLength),
 		IdentityMaxLength:                           dc.GetIntPropertyFilteredByDomain(dynamicconfig.IdentityMaxLength),
@@ -172,6 +183,8 @@ func NewConfig(dc *dynamicconfig.Collection, numHistoryShards int, isAdvancedVis
 		DecisionResultCountLimit:                    dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendDecisionResultCountLimit),
 		EmitSignalNameMetricsTag:                    dc.GetBoolPropertyFilteredByDomain(dynamicconfig.FrontendEmitSignalNameMetricsTag),
 		Lockdown:                                    dc.GetBoolPropertyFilteredByDomain(dynamicconfig.Lockdown),
+		ProberInterval:                              dc.GetDurationProperty(dynamicconfig.FrontendProberInterval),
+		ProberDomain:                                dc.GetStringProperty(dynamicconfig.FrontendProberDomain),
 		domainConfig: domain.Config{
 			MaxBadBinaryCount:      dc.GetIntPropertyFilteredByDomain(dynamicconfig.FrontendMaxBadBinaries),
 			MinRetentionDays:       dc.GetIntProperty(dynamicconfig.MinRetentionDays),
@@ -200,6 +213,7 @@ type Service struct {
 	status       int32
 	handler      *WorkflowHandler
 	adminHandler AdminHandler
+	prober       *prober
 	stopC        chan struct{}
 	config       *Config
 	params       *resource.Params
@@ -284,20 +298,41 @@ func (s *Service) Start() {
 	grpcHandler := newGrpcHandler(handler)
 	grpcHandler.register(s.GetDispatcher())
 
+	// the admin APIs can be served on a dedicated listener with their own authorization
+	adminAuthorizer, adminAuthorizationConfig := s.params.Authorizer, s.params.AuthorizationConfig
+	if s.params.AdminAuthorizationConfig != nil {
+		adminAuthorizer, adminAuthorizationConfig = nil, *s.params.AdminAuthorizationConfig
+	}
+	adminDispatcher := s.GetDispatcher()
+	if s.params.AdminRPCFactory != nil {
+		adminDispatcher = s.params.AdminRPCFactory.GetDispatcher()
+	}
+
 	s.adminHandler = NewAdminHandler(s, s.params, s.config)
-	s.adminHandler = NewAccessControlledAdminHandlerImpl(s.adminHandler, s, s.params.Authorizer, s.params.AuthorizationConfig)
+	s.adminHandler = NewAccessControlledAdminHandlerImpl(s.adminHandler, s, adminAuthorizer, adminAuthorizationConfig)
 
 	adminThriftHandler := NewAdminThriftHandler(s.adminHandler)
-	adminThriftHandler.register(s.GetDispatcher())
+	adminThriftHandler.register(adminDispatcher)
 
 	adminGRPCHandler := newAdminGRPCHandler(s.adminHandler)
-	adminGRPCHandler.register(s.GetDispatcher())
+	adminGRPCHandler.register(adminDispatcher)
+
+	adminJSONHandler := newAdminJSONHandler(s.adminHandler)
+	adminJSONHandler.register(adminDispatcher)
 
 	// must start resource first
 	s.Resource.Start()
+	if s.params.AdminRPCFactory != nil {
+		if err := adminDispatcher.Start(); err != nil {
+			logger.WithTags(tag.Error(err)).Fatal("fail to start admin dispatcher")
+		}
+	}
 	s.handler.Start()
 	s.adminHandler.Start()
 
+	s.prober = newProber(s.handler, s.config, s.GetHostInfo().Identity(), s.GetMetricsClient(), logger)
+	s.prober.Start()
+
 	// base (service is not started in frontend or admin handler) in case of race condition in yarpc registration function
 
 	logger.Info("frontend started")
@@ -327,6 +362,7 @@ func (s *Service) Stop() {
 	s.GetLogger().Info("ShutdownHandler: Waiting for others to discover I am unhealthy")
 	time.Sleep(failureDetectionTime)
 
+	s.prober.Stop()
 	s.handler.Stop()
 	s.adminHandler.Stop()
 
@@ -334,6 +370,11 @@ func (s *Service) Stop() {
 	time.Sleep(requestDrainTime)
 
 	close(s.stopC)
+	if s.params.AdminRPCFactory != nil {
+		if err := s.params.AdminRPCFactory.GetDispatcher().Stop(); err != nil {
+			s.GetLogger().WithTags(tag.Error(err)).Error("failed to stop admin dispatcher")
+		}
+	}
 	s.Resource.Stop()
 	s.params.Logger.Info("frontend stopped")
 }
@@ -25,6 +25,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"sync/atomic"
 	"time"
 
@@ -35,6 +36,7 @@ import (
 
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/archiver"
+	"github.com/uber/cadence/common/authorization"
 	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/client"
@@ -47,8 +49,10 @@ import (
 	"github.com/uber/cadence/common/persistence"
 	persistenceutils "github.com/uber/cadence/common/persistence/persistence-utils"
 	"github.com/uber/cadence/common/quotas"
+	"github.com/uber/cadence/common/redaction"
 	"github.com/uber/cadence/common/resource"
 	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/slowrequest"
 	"github.com/uber/cadence/common/types"
 )
 
@@ -134,11 +138,14 @@ var (
 	errRequestIDNotSet                            = &types.BadRequestError{Message: "RequestId is not set on request."}
 	errWorkflowTypeNotSet                         = &types.BadRequestError{Message: "WorkflowType is not set on request."}
 	errInvalidRetention                           = &types.BadRequestError{Message: "RetentionDays is invalid."}
+	errInvalidExecutionRetention                  = &types.BadRequestError{Message: "A valid retention in days is not set in memo (out of domain retention bounds)."}
 	errInvalidExecutionStartToCloseTimeoutSeconds = &types.BadRequestError{Message: "A valid ExecutionStartToCloseTimeoutSeconds is not set on request."}
 	errInvalidTaskStartToCloseTimeoutSeconds      = &types.BadRequestError{Message: "A valid TaskStartToCloseTimeoutSeconds is not set on request."}
 	errInvalidDelayStartSeconds                   = &types.BadRequestError{Message: "A valid DelayStartSeconds is not set on request."}
 	errInvalidJitterStartSeconds                  = &types.BadRequestError{Message: "A valid JitterStartSeconds is not set on request (negative)."}
 	errInvalidJitterStartSeconds2                 = &types.BadRequestError{Message: "A valid JitterStartSeconds is not set on request (larger than cron duration)."}
+	errInvalidFirstRunAtTimestamp                 = &types.BadRequestError{Message: "A valid FirstRunAtTimestamp is not set on request (too far in the future)."}
+	errDelayStartAndFirstRunAtBothSet             = &types.BadRequestError{Message: "DelayStartSeconds and FirstRunAtTimestamp cannot both be set on request."}
 	errQueryDisallowedForDomain                   = &types.BadRequestError{Message: "Domain is not allowed to query, please contact cadence team to re-enable queries."}
 	errClusterNameNotSet                          = &types.BadRequestError{Message: "Cluster name is not set."}
 	errEmptyReplicationInfo                       = &types.BadRequestError{Message: "Replication task info is not set."}
@@ -172,39 +179,54 @@ func NewWorkflowHandler(
 		Resource:        resource,
 		config:          config,
 		healthStatus:    int32(HealthStatusWarmingUp),
-		tokenSerializer: common.NewJSONTaskTokenSerializer(),
-		userRateLimiter: quotas.NewMultiStageRateLimiter(
-			quotas.NewDynamicRateLimiter(config.UserRPS.AsFloat64()),
-			quotas.NewCollection(func(domain string) quotas.Limiter {
-				return quotas.NewDynamicRateLimiter(quotas.PerMemberDynamic(
-					service.Frontend,
-					config.GlobalDomainUserRPS.AsFloat64(domain),
-					config.MaxDomainUserRPSPerInstance.AsFloat64(domain),
-					resource.GetMembershipResolver(),
-				))
-			}),
+		tokenSerializer: resource.GetTaskTokenSerializer(),
+		userRateLimiter: quotas.NewCallerRateLimiter(
+			quotas.NewMultiStageRateLimiter(
+				quotas.NewDynamicRateLimiter(config.UserRPS.AsFloat64()),
+				quotas.NewCollection(func(domain string) quotas.Limiter {
+					return quotas.NewDynamicRateLimiter(quotas.PerMemberDynamic(
+						service.Frontend,
+						config.GlobalDomainUserRPS.AsFloat64(domain),
+						config.MaxDomainUserRPSPerInstance.AsFloat64(domain),
+						resource.GetMembershipResolver(),
+					))
+				}),
+			),
+			func(domain string) float64 {
+				return float64(config.MaxCallerUserRPSPerInstance(domain))
+			},
 		),
-		workerRateLimiter: quotas.NewMultiStageRateLimiter(
-			quotas.NewDynamicRateLimiter(config.WorkerRPS.AsFloat64()),
-			quotas.NewCollection(func(domain string) quotas.Limiter {
-				return quotas.NewDynamicRateLimiter(quotas.PerMemberDynamic(
-					service.Frontend,
-					config.GlobalDomainWorkerRPS.AsFloat64(domain),
-					config.MaxDomainWorkerRPSPerInstance.AsFloat64(domain),
-					resource.GetMembershipResolver(),
-				))
-			}),
+		workerRateLimiter: quotas.NewCallerRateLimiter(
+			quotas.NewMultiStageRateLimiter(
+				quotas.NewDynamicRateLimiter(config.WorkerRPS.AsFloat64()),
+				quotas.NewCollection(func(domain string) quotas.Limiter {
+					return quotas.NewDynamicRateLimiter(quotas.PerMemberDynamic(
+						service.Frontend,
+						config.GlobalDomainWorkerRPS.AsFloat64(domain),
+						config.MaxDomainWorkerRPSPerInstance.AsFloat64(domain),
+						resource.GetMembershipResolver(),
+					))
+				}),
+			),
+			func(domain string) float64 {
+				return float64(config.MaxCallerWorkerRPSPerInstance(domain))
+			},
 		),
-		visibilityRateLimiter: quotas.NewMultiStageRateLimiter(
-			quotas.NewDynamicRateLimiter(config.VisibilityRPS.AsFloat64()),
-			quotas.NewCollection(func(domain string) quotas.Limiter {
-				return quotas.NewDynamicRateLimiter(quotas.PerMemberDynamic(
-					service.Frontend,
-					config.GlobalDomainVisibilityRPS.AsFloat64(domain),
-					config.MaxDomainVisibilityRPSPerInstance.AsFloat64(domain),
-					resource.GetMembershipResolver(),
-				))
-			}),
+		visibilityRateLimiter: quotas.NewCallerRateLimiter(
+			quotas.NewMultiStageRateLimiter(
+				quotas.NewDynamicRateLimiter(config.VisibilityRPS.AsFloat64()),
+				quotas.NewCollection(func(domain string) quotas.Limiter {
+					return quotas.NewDynamicRateLimiter(quotas.PerMemberDynamic(
+						service.Frontend,
+						config.GlobalDomainVisibilityRPS.AsFloat64(domain),
+						config.MaxDomainVisibilityRPSPerInstance.AsFloat64(domain),
+						resource.GetMembershipResolver(),
+					))
+				}),
+			),
+			func(domain string) float64 {
+				return float64(config.MaxCallerVisibilityRPSPerInstance(domain))
+			},
 		),
 		versionChecker: versionChecker,
 		domainHandler: domain.NewHandler(
@@ -223,6 +245,7 @@ func NewWorkflowHandler(
 		),
 		searchAttributesValidator: validator.NewSearchAttributesValidator(
 			resource.GetLogger(),
+			resource.GetRedactor(),
 			config.EnableQueryAttributeValidation,
 			config.ValidSearchAttributes,
 			config.SearchAttributesNumberOfKeysLimit,
@@ -589,19 +612,11 @@ func (wh *WorkflowHandler) PollForActivityTask(
 		return nil, wh.error(err, scope, tags...)
 	}
 
-	if !common.ValidIDLength(
-		pollRequest.GetIdentity(),
-		scope,
-		idLengthWarnLimit,
-		wh.config.IdentityMaxLength(domainName),
-		metrics.CadenceErrIdentityExceededWarnLimit,
-		domainName,
-		wh.GetLogger(),
-		tag.IDTypeIdentity) {
+	if !wh.validIdentityLength(pollRequest.GetIdentity(), scope, domainName) {
 		return nil, wh.error(errIdentityTooLong, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeWorker, pollRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeWorker, pollRequest); !ok {
 		// pollers exponentially back off up to 10s
 		return nil, wh.error(createServiceBusyError(), scope, tags...)
 	}
@@ -694,15 +709,7 @@ func (wh *WorkflowHandler) PollForDecisionTask(
 		return nil, wh.error(errDomainTooLong, scope, tags...)
 	}
 
-	if !common.ValidIDLength(
-		pollRequest.GetIdentity(),
-		scope,
-		idLengthWarnLimit,
-		wh.config.IdentityMaxLength(domainName),
-		metrics.CadenceErrIdentityExceededWarnLimit,
-		domainName,
-		wh.GetLogger(),
-		tag.IDTypeIdentity) {
+	if !wh.validIdentityLength(pollRequest.GetIdentity(), scope, domainName) {
 		return nil, wh.error(errIdentityTooLong, scope, tags...)
 	}
 
@@ -721,7 +728,7 @@ func (wh *WorkflowHandler) PollForDecisionTask(
 		return nil, wh.error(err, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeWorker, pollRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeWorker, pollRequest); !ok {
 		// pollers exponentially back off up to 10s
 		return nil, wh.error(createServiceBusyError(), scope, tags...)
 	}
@@ -857,7 +864,7 @@ func (wh *WorkflowHandler) RecordActivityTaskHeartbeat(
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, dw)
+	wh.allow(ctx, ratelimitTypeWorker, dw)
 
 	tags := getDomainWfIDRunIDTags(domainName, &types.WorkflowExecution{
 		WorkflowID: taskToken.WorkflowID,
@@ -903,6 +910,7 @@ func (wh *WorkflowHandler) RecordActivityTaskHeartbeat(
 		}
 	}
 
+	wh.recordUsage(domainName, heartbeatRequest.Details)
 	return resp, nil
 }
 
@@ -940,7 +948,7 @@ func (wh *WorkflowHandler) RecordActivityTaskHeartbeatByID(
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, heartbeatRequest)
+	wh.allow(ctx, ratelimitTypeWorker, heartbeatRequest)
 
 	wh.GetLogger().Debug("Received RecordActivityTaskHeartbeatByID")
 	domainID, err := wh.GetDomainCache().GetDomainID(domainName)
@@ -1018,6 +1026,7 @@ func (wh *WorkflowHandler) RecordActivityTaskHeartbeatByID(
 		}
 	}
 
+	wh.recordUsage(domainName, heartbeatRequest.Details)
 	return resp, nil
 }
 
@@ -1067,25 +1076,19 @@ func (wh *WorkflowHandler) RespondActivityTaskCompleted(
 		dw,
 	)
 	defer sw.Stop()
+	// the worker is done with the activity whatever the outcome of the request
+	defer wh.finishRunningActivity(ctx, taskToken)
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, dw)
+	wh.allow(ctx, ratelimitTypeWorker, dw)
 
 	tags := getDomainWfIDRunIDTags(domainName, &types.WorkflowExecution{
 		WorkflowID: taskToken.WorkflowID,
 		RunID:      taskToken.RunID,
 	})
 
-	if !common.ValidIDLength(
-		completeRequest.GetIdentity(),
-		scope,
-		wh.config.MaxIDLengthWarnLimit(),
-		wh.config.IdentityMaxLength(domainName),
-		metrics.CadenceErrIdentityExceededWarnLimit,
-		domainName,
-		wh.GetLogger(),
-		tag.IDTypeIdentity) {
+	if !wh.validIdentityLength(completeRequest.GetIdentity(), scope, domainName) {
 		return wh.error(errIdentityTooLong, scope, tags...)
 	}
 
@@ -1127,9 +1130,42 @@ func (wh *WorkflowHandler) RespondActivityTaskCompleted(
 		}
 	}
 
+	wh.recordUsage(domainName, completeRequest.Result)
 	return nil
 }
 
+// finishRunningActivity tells the task list partition the activity of the task token was dispatched from that
+// the activity is not running anymore, when the partition limits the activities running at once. The slot held
+// by the activity is released at the end of its start to close timeout otherwise, so errors are only logged
+func (wh *WorkflowHandler) finishRunningActivity(ctx context.Context, taskToken *common.TaskToken) {
+	if taskToken.TaskList == "" {
+		return
+	}
+	err := wh.GetMatchingClient().RecordActivityTaskFinished(ctx, &types.MatchingRecordActivityTaskFinishedRequest{
+		DomainUUID: taskToken.DomainID,
+		TaskList: &types.TaskList{
+			Name: taskToken.TaskList,
+			Kind: types.TaskListKindNormal.Ptr(),
+		},
+		WorkflowExecution: &types.WorkflowExecution{
+			WorkflowID: taskToken.WorkflowID,
+			RunID:      taskToken.RunID,
+		},
+		ScheduleID: taskToken.ScheduleID,
+		Attempt:    taskToken.ScheduleAttempt,
+	})
+	if err != nil {
+		wh.GetThrottledLogger().Warn("Failed to record the activity task as finished in matching",
+			tag.WorkflowDomainID(taskToken.DomainID),
+			tag.WorkflowID(taskToken.WorkflowID),
+			tag.WorkflowRunID(taskToken.RunID),
+			tag.WorkflowScheduleID(taskToken.ScheduleID),
+			tag.WorkflowTaskListName(taskToken.TaskList),
+			tag.Error(err),
+		)
+	}
+}
+
 // RespondActivityTaskCompletedByID - response to an activity task
 func (wh *WorkflowHandler) RespondActivityTaskCompletedByID(
 	ctx context.Context,
@@ -1164,7 +1200,7 @@ func (wh *WorkflowHandler) RespondActivityTaskCompletedByID(
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, completeRequest)
+	wh.allow(ctx, ratelimitTypeWorker, completeRequest)
 
 	domainID, err := wh.GetDomainCache().GetDomainID(domainName)
 	if err != nil {
@@ -1184,15 +1220,7 @@ func (wh *WorkflowHandler) RespondActivityTaskCompletedByID(
 		return wh.error(errActivityIDNotSet, scope, tags...)
 	}
 
-	if !common.ValidIDLength(
-		completeRequest.GetIdentity(),
-		scope,
-		wh.config.MaxIDLengthWarnLimit(),
-		wh.config.IdentityMaxLength(domainName),
-		metrics.CadenceErrIdentityExceededWarnLimit,
-		domainName,
-		wh.GetLogger(),
-		tag.IDTypeIdentity) {
+	if !wh.validIdentityLength(completeRequest.GetIdentity(), scope, domainName) {
 		return wh.error(errIdentityTooLong, scope)
 	}
 
@@ -1252,6 +1280,7 @@ func (wh *WorkflowHandler) RespondActivityTaskCompletedByID(
 		}
 	}
 
+	wh.recordUsage(domainName, completeRequest.Result)
 	return nil
 }
 
@@ -1301,25 +1330,19 @@ func (wh *WorkflowHandler) RespondActivityTaskFailed(
 		dw,
 	)
 	defer sw.Stop()
+	// the worker is done with the activity whatever the outcome of the request
+	defer wh.finishRunningActivity(ctx, taskToken)
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, dw)
+	wh.allow(ctx, ratelimitTypeWorker, dw)
 
 	tags := getDomainWfIDRunIDTags(domainName, &types.WorkflowExecution{
 		WorkflowID: taskToken.WorkflowID,
 		RunID:      taskToken.RunID,
 	})
 
-	if !common.ValidIDLength(
-		failedRequest.GetIdentity(),
-		scope,
-		wh.config.MaxIDLengthWarnLimit(),
-		wh.config.IdentityMaxLength(domainName),
-		metrics.CadenceErrIdentityExceededWarnLimit,
-		domainName,
-		wh.GetLogger(),
-		tag.IDTypeIdentity) {
+	if !wh.validIdentityLength(failedRequest.GetIdentity(), scope, domainName) {
 		return wh.error(errIdentityTooLong, scope, tags...)
 	}
 
@@ -1386,7 +1409,7 @@ func (wh *WorkflowHandler) RespondActivityTaskFailedByID(
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, failedRequest)
+	wh.allow(ctx, ratelimitTypeWorker, failedRequest)
 
 	domainID, err := wh.GetDomainCache().GetDomainID(domainName)
 	if err != nil {
@@ -1406,15 +1429,7 @@ func (wh *WorkflowHandler) RespondActivityTaskFailedByID(
 		return wh.error(errActivityIDNotSet, scope, tags...)
 	}
 
-	if !common.ValidIDLength(
-		failedRequest.GetIdentity(),
-		scope,
-		wh.config.MaxIDLengthWarnLimit(),
-		wh.config.IdentityMaxLength(failedRequest.GetDomain()),
-		metrics.CadenceErrIdentityExceededWarnLimit,
-		domainName,
-		wh.GetLogger(),
-		tag.IDTypeIdentity) {
+	if !wh.validIdentityLength(failedRequest.GetIdentity(), scope, domainName) {
 		return wh.error(errIdentityTooLong, scope, tags...)
 	}
 
@@ -1514,25 +1529,19 @@ func (wh *WorkflowHandler) RespondActivityTaskCanceled(
 		dw,
 	)
 	defer sw.Stop()
+	// the worker is done with the activity whatever the outcome of the request
+	defer wh.finishRunningActivity(ctx, taskToken)
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, dw)
+	wh.allow(ctx, ratelimitTypeWorker, dw)
 
 	tags := getDomainWfIDRunIDTags(domainName, &types.WorkflowExecution{
 		WorkflowID: taskToken.WorkflowID,
 		RunID:      taskToken.RunID,
 	})
 
-	if !common.ValidIDLength(
-		cancelRequest.GetIdentity(),
-		scope,
-		wh.config.MaxIDLengthWarnLimit(),
-		wh.config.IdentityMaxLength(domainName),
-		metrics.CadenceErrIdentityExceededWarnLimit,
-		domainName,
-		wh.GetLogger(),
-		tag.IDTypeIdentity) {
+	if !wh.validIdentityLength(cancelRequest.GetIdentity(), scope, domainName) {
 		return wh.error(errIdentityTooLong, scope, tags...)
 	}
 
@@ -1611,7 +1620,7 @@ func (wh *WorkflowHandler) RespondActivityTaskCanceledByID(
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, cancelRequest)
+	wh.allow(ctx, ratelimitTypeWorker, cancelRequest)
 
 	domainID, err := wh.GetDomainCache().GetDomainID(domainName)
 	if err != nil {
@@ -1631,15 +1640,7 @@ func (wh *WorkflowHandler) RespondActivityTaskCanceledByID(
 		return wh.error(errActivityIDNotSet, scope, tags...)
 	}
 
-	if !common.ValidIDLength(
-		cancelRequest.GetIdentity(),
-		scope,
-		wh.config.MaxIDLengthWarnLimit(),
-		wh.config.IdentityMaxLength(cancelRequest.GetDomain()),
-		metrics.CadenceErrIdentityExceededWarnLimit,
-		domainName,
-		wh.GetLogger(),
-		tag.IDTypeIdentity) {
+	if !wh.validIdentityLength(cancelRequest.GetIdentity(), scope, domainName) {
 		return wh.error(errIdentityTooLong, scope, tags...)
 	}
 
@@ -1751,22 +1752,14 @@ func (wh *WorkflowHandler) RespondDecisionTaskCompleted(
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, dw)
+	wh.allow(ctx, ratelimitTypeWorker, dw)
 
 	tags := getDomainWfIDRunIDTags(domainName, &types.WorkflowExecution{
 		WorkflowID: taskToken.WorkflowID,
 		RunID:      taskToken.RunID,
 	})
 
-	if !common.ValidIDLength(
-		completeRequest.GetIdentity(),
-		scope,
-		wh.config.MaxIDLengthWarnLimit(),
-		wh.config.IdentityMaxLength(domainName),
-		metrics.CadenceErrIdentityExceededWarnLimit,
-		domainName,
-		wh.GetLogger(),
-		tag.IDTypeIdentity) {
+	if !wh.validIdentityLength(completeRequest.GetIdentity(), scope, domainName) {
 		return nil, wh.error(errIdentityTooLong, scope, tags...)
 	}
 
@@ -1784,6 +1777,7 @@ func (wh *WorkflowHandler) RespondDecisionTaskCompleted(
 	if err != nil {
 		return nil, wh.normalizeVersionedErrors(ctx, wh.error(err, scope, tags...))
 	}
+	wh.recordUsage(domainName, completeRequest.ExecutionContext)
 
 	completedResp := &types.RespondDecisionTaskCompletedResponse{}
 	completedResp.ActivitiesToDispatchLocally = histResp.ActivitiesToDispatchLocally
@@ -1861,22 +1855,14 @@ func (wh *WorkflowHandler) RespondDecisionTaskFailed(
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, dw)
+	wh.allow(ctx, ratelimitTypeWorker, dw)
 
 	tags := getDomainWfIDRunIDTags(domainName, &types.WorkflowExecution{
 		WorkflowID: taskToken.WorkflowID,
 		RunID:      taskToken.RunID,
 	})
 
-	if !common.ValidIDLength(
-		failedRequest.GetIdentity(),
-		scope,
-		wh.config.MaxIDLengthWarnLimit(),
-		wh.config.IdentityMaxLength(domainName),
-		metrics.CadenceErrIdentityExceededWarnLimit,
-		domainName,
-		wh.GetLogger(),
-		tag.IDTypeIdentity) {
+	if !wh.validIdentityLength(failedRequest.GetIdentity(), scope, domainName) {
 		return wh.error(errIdentityTooLong, scope, tags...)
 	}
 
@@ -1957,7 +1943,7 @@ func (wh *WorkflowHandler) RespondQueryTaskCompleted(
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, dw)
+	wh.allow(ctx, ratelimitTypeWorker, dw)
 
 	sizeLimitError := wh.config.BlobSizeLimitError(domainName)
 	sizeLimitWarn := wh.config.BlobSizeLimitWarn(domainName)
@@ -2033,7 +2019,7 @@ func (wh *WorkflowHandler) StartWorkflowExecution(
 		return nil, wh.error(errDomainNotSet, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, startRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, startRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope, tags...)
 	}
 
@@ -2074,6 +2060,13 @@ func (wh *WorkflowHandler) StartWorkflowExecution(
 		if _, err := backoff.ValidateSchedule(startRequest.GetCronSchedule()); err != nil {
 			return nil, wh.error(err, scope, tags...)
 		}
+		if err := backoff.ValidateCronOverlapPolicy(startRequest.Header); err != nil {
+			return nil, wh.error(err, scope, tags...)
+		}
+	}
+
+	if err := wh.validateExecutionRetention(startRequest.Memo); err != nil {
+		return nil, wh.error(err, scope, tags...)
 	}
 
 	wh.GetLogger().Debug(
@@ -2112,6 +2105,16 @@ func (wh *WorkflowHandler) StartWorkflowExecution(
 		return nil, wh.error(errInvalidDelayStartSeconds, scope, tags...)
 	}
 
+	if startRequest.FirstRunAtTimestamp != nil {
+		if startRequest.GetDelayStartSeconds() > 0 {
+			return nil, wh.error(errDelayStartAndFirstRunAtBothSet, scope, tags...)
+		}
+		// the delay is passed on in seconds as an int32
+		if time.Until(time.Unix(0, startRequest.GetFirstRunAtTimestamp())) > math.MaxInt32*time.Second {
+			return nil, wh.error(errInvalidFirstRunAtTimestamp, scope, tags...)
+		}
+	}
+
 	if startRequest.GetJitterStartSeconds() < 0 {
 		return nil, wh.error(errInvalidJitterStartSeconds, scope, tags...)
 	}
@@ -2191,6 +2194,7 @@ func (wh *WorkflowHandler) StartWorkflowExecution(
 	if err != nil {
 		return nil, wh.error(err, scope, tags...)
 	}
+	wh.recordUsage(domainName, startRequest.Input)
 	return resp, nil
 }
 
@@ -2224,7 +2228,7 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(
 		return nil, wh.error(errDomainNotSet, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, getRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, getRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope, tags...)
 	}
 
@@ -2499,7 +2503,7 @@ func (wh *WorkflowHandler) SignalWorkflowExecution(
 		return wh.error(errDomainNotSet, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, signalRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, signalRequest); !ok {
 		return wh.error(createServiceBusyError(), scope, tags...)
 	}
 
@@ -2577,6 +2581,7 @@ func (wh *WorkflowHandler) SignalWorkflowExecution(
 		return wh.normalizeVersionedErrors(ctx, wh.error(err, scope, tags...))
 	}
 
+	wh.recordUsage(domainName, signalRequest.Input)
 	return nil
 }
 
@@ -2616,7 +2621,7 @@ func (wh *WorkflowHandler) SignalWithStartWorkflowExecution(
 		return nil, wh.error(errDomainNotSet, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, signalWithStartRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, signalWithStartRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope, tags...)
 	}
 
@@ -2713,6 +2718,13 @@ func (wh *WorkflowHandler) SignalWithStartWorkflowExecution(
 		if _, err := backoff.ValidateSchedule(signalWithStartRequest.GetCronSchedule()); err != nil {
 			return nil, wh.error(err, scope, tags...)
 		}
+		if err := backoff.ValidateCronOverlapPolicy(signalWithStartRequest.Header); err != nil {
+			return nil, wh.error(err, scope, tags...)
+		}
+	}
+
+	if err := wh.validateExecutionRetention(signalWithStartRequest.Memo); err != nil {
+		return nil, wh.error(err, scope, tags...)
 	}
 
 	if err := wh.searchAttributesValidator.ValidateSearchAttributes(signalWithStartRequest.SearchAttributes, domainName); err != nil {
@@ -2762,6 +2774,7 @@ func (wh *WorkflowHandler) SignalWithStartWorkflowExecution(
 		return nil, wh.error(err, scope, tags...)
 	}
 
+	wh.recordUsage(domainName, signalWithStartRequest.Input, signalWithStartRequest.SignalInput)
 	return resp, nil
 }
 
@@ -2796,7 +2809,7 @@ func (wh *WorkflowHandler) TerminateWorkflowExecution(
 		return wh.error(errDomainNotSet, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, terminateRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, terminateRequest); !ok {
 		return wh.error(createServiceBusyError(), scope, tags...)
 	}
 
@@ -2817,6 +2830,7 @@ func (wh *WorkflowHandler) TerminateWorkflowExecution(
 		return wh.normalizeVersionedErrors(ctx, wh.error(err, scope, tags...))
 	}
 
+	wh.recordUsage(domainName, terminateRequest.Details)
 	return nil
 }
 
@@ -2851,7 +2865,7 @@ func (wh *WorkflowHandler) ResetWorkflowExecution(
 		return nil, wh.error(errDomainNotSet, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, resetRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, resetRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope, tags...)
 	}
 
@@ -2872,6 +2886,7 @@ func (wh *WorkflowHandler) ResetWorkflowExecution(
 		return nil, wh.error(err, scope, tags...)
 	}
 
+	wh.recordUsage(domainName)
 	return resp, nil
 }
 
@@ -2905,7 +2920,7 @@ func (wh *WorkflowHandler) RequestCancelWorkflowExecution(
 		return wh.error(errDomainNotSet, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, cancelRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, cancelRequest); !ok {
 		return wh.error(createServiceBusyError(), scope, tags...)
 	}
 
@@ -2926,6 +2941,7 @@ func (wh *WorkflowHandler) RequestCancelWorkflowExecution(
 		return wh.normalizeVersionedErrors(ctx, wh.error(err, scope, tags...))
 	}
 
+	wh.recordUsage(domainName)
 	return nil
 }
 
@@ -2955,7 +2971,7 @@ func (wh *WorkflowHandler) ListOpenWorkflowExecutions(
 		return nil, wh.error(errDomainNotSet, scope)
 	}
 
-	if ok := wh.allow(ratelimitTypeVisibility, listRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeVisibility, listRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -3072,7 +3088,7 @@ func (wh *WorkflowHandler) ListArchivedWorkflowExecutions(
 		return nil, wh.error(errDomainNotSet, scope)
 	}
 
-	if ok := wh.allow(ratelimitTypeVisibility, listRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeVisibility, listRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -3164,7 +3180,7 @@ func (wh *WorkflowHandler) ListClosedWorkflowExecutions(
 		return nil, wh.error(errDomainNotSet, scope)
 	}
 
-	if ok := wh.allow(ratelimitTypeVisibility, listRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeVisibility, listRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -3304,7 +3320,7 @@ func (wh *WorkflowHandler) ListWorkflowExecutions(
 		return nil, wh.error(errDomainNotSet, scope)
 	}
 
-	if ok := wh.allow(ratelimitTypeVisibility, listRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeVisibility, listRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -3373,7 +3389,7 @@ func (wh *WorkflowHandler) RestartWorkflowExecution(ctx context.Context, request
 		return nil, wh.error(errDomainNotSet, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, request); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, request); !ok {
 		return nil, wh.error(createServiceBusyError(), scope, tags...)
 	}
 
@@ -3440,7 +3456,7 @@ func (wh *WorkflowHandler) ScanWorkflowExecutions(
 		return nil, wh.error(errDomainNotSet, scope)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, listRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, listRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -3508,7 +3524,7 @@ func (wh *WorkflowHandler) CountWorkflowExecutions(
 		return nil, wh.error(errDomainNotSet, scope)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, countRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, countRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -3593,7 +3609,7 @@ func (wh *WorkflowHandler) ResetStickyTaskList(
 
 	// Count the request in the host RPS,
 	// but we still accept it even if RPS is exceeded
-	wh.allow(ratelimitTypeWorker, resetRequest)
+	wh.allow(ctx, ratelimitTypeWorker, resetRequest)
 
 	if err := validateExecution(wfExecution); err != nil {
 		return nil, wh.error(err, scope, tags...)
@@ -3644,7 +3660,7 @@ func (wh *WorkflowHandler) QueryWorkflow(
 		return nil, wh.error(errDomainNotSet, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, queryRequest); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, queryRequest); !ok {
 		return nil, wh.error(createServiceBusyError(), scope, tags...)
 	}
 
@@ -3693,6 +3709,7 @@ func (wh *WorkflowHandler) QueryWorkflow(
 	if err != nil {
 		return nil, wh.error(err, scope, tags...)
 	}
+	wh.recordUsage(domainName, queryRequest.GetQuery().GetQueryArgs())
 	return hResponse.GetResponse(), nil
 }
 
@@ -3726,7 +3743,7 @@ func (wh *WorkflowHandler) DescribeWorkflowExecution(
 		return nil, wh.error(errDomainNotSet, scope, tags...)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, request); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, request); !ok {
 		return nil, wh.error(createServiceBusyError(), scope, tags...)
 	}
 
@@ -3779,7 +3796,7 @@ func (wh *WorkflowHandler) DescribeTaskList(
 		return nil, wh.error(errDomainNotSet, scope)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, request); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, request); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -3829,7 +3846,7 @@ func (wh *WorkflowHandler) ListTaskListPartitions(
 		return nil, wh.error(errDomainNotSet, scope)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, request); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, request); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -3866,7 +3883,7 @@ func (wh *WorkflowHandler) GetTaskListsByDomain(
 		return nil, wh.error(errDomainNotSet, scope)
 	}
 
-	if ok := wh.allow(ratelimitTypeUser, request); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, request); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -3920,7 +3937,7 @@ func (wh *WorkflowHandler) getRawHistory(
 	branchToken []byte,
 ) ([]*types.DataBlob, []byte, error) {
 	rawHistory := []*types.DataBlob{}
-	shardID := common.WorkflowIDToHistoryShard(execution.WorkflowID, wh.config.NumHistoryShards)
+	shardID := wh.GetHistoryShardRouter().WorkflowIDToShard(execution.WorkflowID)
 
 	resp, err := wh.GetHistoryManager().ReadRawHistoryBranch(ctx, &persistence.ReadHistoryBranchRequest{
 		BranchToken:   branchToken,
@@ -3990,7 +4007,7 @@ func (wh *WorkflowHandler) getHistory(
 	var size int
 
 	isFirstPage := len(nextPageToken) == 0
-	shardID := common.WorkflowIDToHistoryShard(execution.WorkflowID, wh.config.NumHistoryShards)
+	shardID := wh.GetHistoryShardRouter().WorkflowIDToShard(execution.WorkflowID)
 	var err error
 	historyEvents, size, nextPageToken, err := persistenceutils.ReadFullPageV2Events(ctx, wh.GetHistoryManager(), &persistence.ReadHistoryBranchRequest{
 		BranchToken:   branchToken,
@@ -4073,12 +4090,29 @@ func (wh *WorkflowHandler) startRequestProfile(ctx context.Context, scope int) (
 
 // startRequestProfileWithDomain initiates recording of request metrics and returns a domain tagged scope
 func (wh *WorkflowHandler) startRequestProfileWithDomain(ctx context.Context, scope int, d domainGetter) (metrics.Scope, metrics.Stopwatch) {
+	if d != nil {
+		slowrequest.SetWorkflow(ctx, d.GetDomain(), getWorkflowID(d))
+	}
 	metricsScope := getMetricsScopeWithDomain(scope, d, wh.GetMetricsClient()).Tagged(metrics.GetContextTags(ctx)...)
 	sw := metricsScope.StartTimer(metrics.CadenceLatency)
 	metricsScope.IncCounter(metrics.CadenceRequests)
 	return metricsScope, sw
 }
 
+// getWorkflowID returns the workflow ID of the request if it targets a single workflow, or an empty string
+func getWorkflowID(request interface{}) string {
+	switch r := request.(type) {
+	case interface{ GetWorkflowID() string }:
+		return r.GetWorkflowID()
+	case interface {
+		GetWorkflowExecution() *types.WorkflowExecution
+	}:
+		return r.GetWorkflowExecution().GetWorkflowID()
+	default:
+		return ""
+	}
+}
+
 // getDefaultScope returns a default scope to use for request metrics
 func (wh *WorkflowHandler) getDefaultScope(ctx context.Context, scope int) metrics.Scope {
 	return wh.GetMetricsClient().Scope(scope).Tagged(metrics.DomainUnknownTag()).Tagged(metrics.GetContextTags(ctx)...)
@@ -4146,6 +4180,28 @@ func (wh *WorkflowHandler) error(err error, scope metrics.Scope, tagsForErrorLog
 	return frontendInternalServiceError("cadence internal uncategorized error, msg: %v", err.Error())
 }
 
+// validIdentityLength checks the length of the identity of a caller, the identity is redacted in the logs
+// following the redaction policy of the domain
+func (wh *WorkflowHandler) validIdentityLength(identity string, scope metrics.Scope, domainName string) bool {
+	warnLimit := wh.config.MaxIDLengthWarnLimit()
+	valid := common.ValidIDLength(
+		identity,
+		scope,
+		warnLimit,
+		wh.config.IdentityMaxLength(domainName),
+		metrics.CadenceErrIdentityExceededWarnLimit,
+		domainName,
+		nil,
+		tag.IDTypeIdentity)
+	if len(identity) > warnLimit {
+		wh.GetLogger().Warn("ID length exceeds limit.",
+			tag.WorkflowDomainName(domainName),
+			tag.Name(wh.GetRedactor().Redact(domainName, redaction.FieldIdentity, []byte(identity))),
+			tag.IDTypeIdentity)
+	}
+	return valid
+}
+
 func (wh *WorkflowHandler) validateTaskList(t *types.TaskList, scope metrics.Scope, domain string) error {
 	if t == nil || t.GetName() == "" {
 		return errTaskListNotSet
@@ -4165,6 +4221,21 @@ func (wh *WorkflowHandler) validateTaskList(t *types.TaskList, scope metrics.Sco
 	return nil
 }
 
+// validateExecutionRetention validates the retention specified in the memo of a workflow execution
+// at start time, it must be within the bounds of the retention of domains
+func (wh *WorkflowHandler) validateExecutionRetention(memo *types.Memo) error {
+	if _, ok := memo.GetFields()[cache.ExecutionRetentionKey]; !ok {
+		return nil
+	}
+	retentionDays, ok := cache.ParseExecutionRetentionDays(memo.GetFields())
+	if !ok ||
+		retentionDays < int32(wh.config.domainConfig.MinRetentionDays()) ||
+		retentionDays > int32(wh.config.domainConfig.MaxRetentionDays()) {
+		return errInvalidExecutionRetention
+	}
+	return nil
+}
+
 func validateExecution(w *types.WorkflowExecution) error {
 	if w == nil {
 		return errExecutionNotSet
@@ -4493,24 +4564,36 @@ func (wh *WorkflowHandler) isListRequestPageSizeTooLarge(pageSize int32, domain
 		pageSize > int32(wh.config.ESIndexMaxResultWindow())
 }
 
-func (wh *WorkflowHandler) allow(requestType ratelimitType, d domainGetter) bool {
-	domain := ""
+func (wh *WorkflowHandler) allow(ctx context.Context, requestType ratelimitType, d domainGetter) bool {
+	info := quotas.Info{Caller: authorization.GetCallerIdentity(ctx)}
 	if d != nil {
-		domain = d.GetDomain()
+		info.Domain = d.GetDomain()
 	}
 	switch requestType {
 	case ratelimitTypeUser:
-		return wh.userRateLimiter.Allow(quotas.Info{Domain: domain})
+		return wh.userRateLimiter.Allow(info)
 	case ratelimitTypeWorker:
-		return wh.workerRateLimiter.Allow(quotas.Info{Domain: domain})
+		return wh.workerRateLimiter.Allow(info)
 	case ratelimitTypeVisibility:
-		return wh.visibilityRateLimiter.Allow(quotas.Info{Domain: domain})
+		return wh.visibilityRateLimiter.Allow(info)
 	default:
 		wh.GetLogger().Fatal("coding error, unrecognized request ratelimit type value", tag.Value(requestType))
 		panic("unreachable")
 	}
 }
 
+// recordUsage accounts a successful action of the domain together with the size of the payloads it carried
+func (wh *WorkflowHandler) recordUsage(domainName string, payloads ...[]byte) {
+	payloadBytes := 0
+	for _, payload := range payloads {
+		payloadBytes += len(payload)
+	}
+	wh.GetUsageRecorder().RecordUsage(domainName, persistence.DomainUsage{
+		Actions:      1,
+		PayloadBytes: int64(payloadBytes),
+	})
+}
+
 // GetClusterInfo return information about cadence deployment
 func (wh *WorkflowHandler) GetClusterInfo(
 	ctx context.Context,
@@ -4518,7 +4601,7 @@ func (wh *WorkflowHandler) GetClusterInfo(
 	defer func() { log.CapturePanic(recover(), wh.GetLogger(), &err) }()
 
 	scope := wh.getDefaultScope(ctx, metrics.FrontendClientGetClusterInfoScope)
-	if ok := wh.allow(ratelimitTypeUser, nil); !ok {
+	if ok := wh.allow(ctx, ratelimitTypeUser, nil); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
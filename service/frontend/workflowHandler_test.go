@@ -24,6 +24,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
 	"testing"
 	"time"
 
@@ -234,6 +235,36 @@ func (s *workflowHandlerSuite) TestPollForTask_Failed_ContextTimeoutTooShort() {
 	s.Equal(common.ErrContextTimeoutTooShort, err)
 }
 
+func (s *workflowHandlerSuite) TestRespondActivityTaskCompleted_FinishesRunningActivity() {
+	wh := s.getWorkflowHandler(s.newConfig(dc.NewInMemoryClient()))
+	s.mockDomainCache.EXPECT().GetDomainName(s.testDomainID).Return(s.testDomain, nil).AnyTimes()
+	respond := func(token *common.TaskToken) error {
+		taskToken, err := wh.tokenSerializer.Serialize(token)
+		s.NoError(err)
+		return wh.RespondActivityTaskCompleted(context.Background(), &types.RespondActivityTaskCompletedRequest{
+			TaskToken: taskToken,
+			Identity:  "worker",
+		})
+	}
+
+	// the task list is only told about the activities it limits, i.e. when the token has a task list
+	s.mockHistoryClient.EXPECT().RespondActivityTaskCompleted(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	s.NoError(respond(&common.TaskToken{DomainID: s.testDomainID, WorkflowID: "wid", RunID: "rid", ScheduleID: 5}))
+
+	// the slot is released even when the activity can't be completed, as the worker is done with it
+	s.mockHistoryClient.EXPECT().RespondActivityTaskCompleted(gomock.Any(), gomock.Any()).
+		Return(&types.EntityNotExistsError{Message: "workflow closed"}).Times(1)
+	s.mockResource.MatchingClient.EXPECT().RecordActivityTaskFinished(gomock.Any(), &types.MatchingRecordActivityTaskFinishedRequest{
+		DomainUUID:        s.testDomainID,
+		TaskList:          &types.TaskList{Name: "tl", Kind: types.TaskListKindNormal.Ptr()},
+		WorkflowExecution: &types.WorkflowExecution{WorkflowID: "wid", RunID: "rid"},
+		ScheduleID:        5,
+		Attempt:           2,
+	}).Return(nil).Times(1)
+	err := respond(&common.TaskToken{DomainID: s.testDomainID, WorkflowID: "wid", RunID: "rid", ScheduleID: 5, ScheduleAttempt: 2, TaskList: "tl"})
+	s.IsType(&types.EntityNotExistsError{}, err)
+}
+
 func (s *workflowHandlerSuite) TestStartWorkflowExecution_Failed_RequestIdNotSet() {
 	config := s.newConfig(dc.NewInMemoryClient())
 	config.UserRPS = dc.GetIntPropertyFn(10)
@@ -294,6 +325,67 @@ func (s *workflowHandlerSuite) TestStartWorkflowExecution_Failed_BadDelayStartSe
 	s.Equal(errInvalidDelayStartSeconds, err)
 }
 
+func (s *workflowHandlerSuite) TestStartWorkflowExecution_Failed_BadFirstRunAtTimestamp() {
+	config := s.newConfig(dc.NewInMemoryClient())
+	config.UserRPS = dc.GetIntPropertyFn(10)
+	wh := s.getWorkflowHandler(config)
+
+	startWorkflowExecutionRequest := &types.StartWorkflowExecutionRequest{
+		Domain:     s.testDomain,
+		WorkflowID: "workflow-id",
+		WorkflowType: &types.WorkflowType{
+			Name: "workflow-type",
+		},
+		TaskList: &types.TaskList{
+			Name: "task-list",
+		},
+		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(1),
+		RequestID:                           uuid.New(),
+		DelayStartSeconds:                   common.Int32Ptr(10),
+		FirstRunAtTimestamp:                 common.Int64Ptr(time.Now().Add(time.Hour).UnixNano()),
+	}
+	_, err := wh.StartWorkflowExecution(context.Background(), startWorkflowExecutionRequest)
+	s.Error(err)
+	s.Equal(errDelayStartAndFirstRunAtBothSet, err)
+
+	startWorkflowExecutionRequest.DelayStartSeconds = nil
+	startWorkflowExecutionRequest.FirstRunAtTimestamp = common.Int64Ptr(math.MaxInt64)
+	_, err = wh.StartWorkflowExecution(context.Background(), startWorkflowExecutionRequest)
+	s.Error(err)
+	s.Equal(errInvalidFirstRunAtTimestamp, err)
+}
+
+func (s *workflowHandlerSuite) TestStartWorkflowExecution_Failed_InvalidExecutionRetention() {
+	config := s.newConfig(dc.NewInMemoryClient())
+	config.UserRPS = dc.GetIntPropertyFn(10)
+	config.domainConfig.MinRetentionDays = dc.GetIntPropertyFn(1)
+	config.domainConfig.MaxRetentionDays = dc.GetIntPropertyFn(30)
+	wh := s.getWorkflowHandler(config)
+
+	for _, retention := range []string{"0", "31", "invalid-value"} {
+		startWorkflowExecutionRequest := &types.StartWorkflowExecutionRequest{
+			Domain:     s.testDomain,
+			WorkflowID: "workflow-id",
+			WorkflowType: &types.WorkflowType{
+				Name: "workflow-type",
+			},
+			TaskList: &types.TaskList{
+				Name: "task-list",
+			},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(1),
+			RequestID:                           uuid.New(),
+			Memo: &types.Memo{
+				Fields: map[string][]byte{cache.ExecutionRetentionKey: []byte(retention)},
+			},
+		}
+		_, err := wh.StartWorkflowExecution(context.Background(), startWorkflowExecutionRequest)
+		s.Error(err)
+		s.Equal(errInvalidExecutionRetention, err)
+	}
+}
+
 func (s *workflowHandlerSuite) TestStartWorkflowExecution_Failed_StartRequestNotSet() {
 	config := s.newConfig(dc.NewInMemoryClient())
 	config.UserRPS = dc.GetIntPropertyFn(10)
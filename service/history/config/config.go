@@ -34,6 +34,8 @@ type Config struct {
 	NumberOfShards                  int
 	IsAdvancedVisConfigExist        bool
 	RPS                             dynamicconfig.IntPropertyFn
+	DomainResourcePool              dynamicconfig.StringPropertyFnWithDomainFilter
+	ResourcePoolRPS                 dynamicconfig.MapPropertyFn
 	MaxIDLengthWarnLimit            dynamicconfig.IntPropertyFn
 	DomainNameMaxLength             dynamicconfig.IntPropertyFnWithDomainFilter
 	IdentityMaxLength               dynamicconfig.IntPropertyFnWithDomainFilter
@@ -58,6 +60,7 @@ type Config struct {
 	ThrottledLogRPS                 dynamicconfig.IntPropertyFn
 	EnableStickyQuery               dynamicconfig.BoolPropertyFnWithDomainFilter
 	ShutdownDrainDuration           dynamicconfig.DurationPropertyFn
+	EnableShardHandoff              dynamicconfig.BoolPropertyFn
 	WorkflowDeletionJitterRange     dynamicconfig.IntPropertyFnWithDomainFilter
 	MaxResponseSize                 int
 
@@ -78,9 +81,10 @@ type Config struct {
 	EventsCacheGlobalMaxCount     dynamicconfig.IntPropertyFn
 
 	// ShardController settings
-	RangeSizeBits           uint
-	AcquireShardInterval    dynamicconfig.DurationPropertyFn
-	AcquireShardConcurrency dynamicconfig.IntPropertyFn
+	RangeSizeBits              uint
+	AcquireShardInterval       dynamicconfig.DurationPropertyFn
+	AcquireShardConcurrency    dynamicconfig.IntPropertyFn
+	ShardHandoffMaxConcurrency dynamicconfig.IntPropertyFn
 
 	// the artificial delay added to standby cluster's view of active cluster's time
 	StandbyClusterDelay                  dynamicconfig.DurationPropertyFn
@@ -94,8 +98,12 @@ type Config struct {
 	TaskSchedulerShardWorkerCount           dynamicconfig.IntPropertyFn
 	TaskSchedulerQueueSize                  dynamicconfig.IntPropertyFn
 	TaskSchedulerShardQueueSize             dynamicconfig.IntPropertyFn
+	LoadSheddingTaskRPS                     dynamicconfig.IntPropertyFn
+	ResourcePoolTaskWorkerCount             dynamicconfig.MapPropertyFn
 	TaskSchedulerDispatcherCount            dynamicconfig.IntPropertyFn
 	TaskSchedulerRoundRobinWeights          dynamicconfig.MapPropertyFn
+	TaskSchedulerEnableFairScheduling       dynamicconfig.BoolPropertyFn
+	TaskSchedulerDomainWeights              dynamicconfig.MapPropertyFn
 	TaskCriticalRetryCount                  dynamicconfig.IntPropertyFn
 	ActiveTaskRedispatchInterval            dynamicconfig.DurationPropertyFn
 	StandbyTaskRedispatchInterval           dynamicconfig.DurationPropertyFn
@@ -105,19 +113,20 @@ type Config struct {
 	ResurrectionCheckMinDelay               dynamicconfig.DurationPropertyFnWithDomainFilter
 
 	// QueueProcessor settings
-	QueueProcessorEnableSplit                          dynamicconfig.BoolPropertyFn
-	QueueProcessorSplitMaxLevel                        dynamicconfig.IntPropertyFn
-	QueueProcessorEnableRandomSplitByDomainID          dynamicconfig.BoolPropertyFnWithDomainIDFilter
-	QueueProcessorRandomSplitProbability               dynamicconfig.FloatPropertyFn
-	QueueProcessorEnablePendingTaskSplitByDomainID     dynamicconfig.BoolPropertyFnWithDomainIDFilter
-	QueueProcessorPendingTaskSplitThreshold            dynamicconfig.MapPropertyFn
-	QueueProcessorEnableStuckTaskSplitByDomainID       dynamicconfig.BoolPropertyFnWithDomainIDFilter
-	QueueProcessorStuckTaskSplitThreshold              dynamicconfig.MapPropertyFn
-	QueueProcessorSplitLookAheadDurationByDomainID     dynamicconfig.DurationPropertyFnWithDomainIDFilter
-	QueueProcessorPollBackoffInterval                  dynamicconfig.DurationPropertyFn
-	QueueProcessorPollBackoffIntervalJitterCoefficient dynamicconfig.FloatPropertyFn
-	QueueProcessorEnablePersistQueueStates             dynamicconfig.BoolPropertyFn
-	QueueProcessorEnableLoadQueueStates                dynamicconfig.BoolPropertyFn
+	QueueProcessorEnableSplit                           dynamicconfig.BoolPropertyFn
+	QueueProcessorSplitMaxLevel                         dynamicconfig.IntPropertyFn
+	QueueProcessorEnableRandomSplitByDomainID           dynamicconfig.BoolPropertyFnWithDomainIDFilter
+	QueueProcessorRandomSplitProbability                dynamicconfig.FloatPropertyFn
+	QueueProcessorEnablePendingTaskSplitByDomainID      dynamicconfig.BoolPropertyFnWithDomainIDFilter
+	QueueProcessorPendingTaskSplitThreshold             dynamicconfig.MapPropertyFn
+	QueueProcessorEnableStuckTaskSplitByDomainID        dynamicconfig.BoolPropertyFnWithDomainIDFilter
+	QueueProcessorStuckTaskSplitThreshold               dynamicconfig.MapPropertyFn
+	QueueProcessorEnableLowPrioritySplitQueueByDomainID dynamicconfig.BoolPropertyFnWithDomainIDFilter
+	QueueProcessorSplitLookAheadDurationByDomainID      dynamicconfig.DurationPropertyFnWithDomainIDFilter
+	QueueProcessorPollBackoffInterval                   dynamicconfig.DurationPropertyFn
+	QueueProcessorPollBackoffIntervalJitterCoefficient  dynamicconfig.FloatPropertyFn
+	QueueProcessorEnablePersistQueueStates              dynamicconfig.BoolPropertyFn
+	QueueProcessorEnableLoadQueueStates                 dynamicconfig.BoolPropertyFn
 
 	// TimerQueueProcessor settings
 	TimerTaskBatchSize                                dynamicconfig.IntPropertyFn
@@ -199,6 +208,10 @@ type Config struct {
 	MaximumBufferedEventsBatch dynamicconfig.IntPropertyFn
 	MaximumSignalsPerExecution dynamicconfig.IntPropertyFnWithDomainFilter
 
+	// Workflow ID rate limits
+	WorkflowIDSignalRPS dynamicconfig.IntPropertyFnWithDomainFilter
+	WorkflowIDEventsRPS dynamicconfig.IntPropertyFnWithDomainFilter
+
 	// ShardUpdateMinInterval the minimal time interval which the shard info can be updated
 	ShardUpdateMinInterval dynamicconfig.DurationPropertyFn
 	// ShardSyncMinInterval the minimal time interval which the shard info should be sync to remote
@@ -239,9 +252,15 @@ type Config struct {
 	HistorySizeLimitWarn             dynamicconfig.IntPropertyFnWithDomainFilter
 	HistoryCountLimitError           dynamicconfig.IntPropertyFnWithDomainFilter
 	HistoryCountLimitWarn            dynamicconfig.IntPropertyFnWithDomainFilter
-	PendingActivitiesCountLimitError dynamicconfig.IntPropertyFn
-	PendingActivitiesCountLimitWarn  dynamicconfig.IntPropertyFn
+	PendingActivitiesCountLimitError dynamicconfig.IntPropertyFnWithDomainFilter
+	PendingActivitiesCountLimitWarn  dynamicconfig.IntPropertyFnWithDomainFilter
 	PendingActivityValidationEnabled dynamicconfig.BoolPropertyFn
+	// EnableHistorySizeWarningSearchAttribute flags the executions over the history warn limits in visibility
+	EnableHistorySizeWarningSearchAttribute dynamicconfig.BoolPropertyFnWithDomainFilter
+	// EnablePendingCountLimitFailure fails the workflows over the pending activity or child workflow error limits
+	EnablePendingCountLimitFailure        dynamicconfig.BoolPropertyFnWithDomainFilter
+	PendingChildExecutionsCountLimitError dynamicconfig.IntPropertyFnWithDomainFilter
+	PendingChildExecutionsCountLimitWarn  dynamicconfig.IntPropertyFnWithDomainFilter
 
 	// ValidSearchAttributes is legal indexed keys that can be used in list APIs
 	EnableQueryAttributeValidation    dynamicconfig.BoolPropertyFn
@@ -257,6 +276,8 @@ type Config struct {
 	// DecisionHeartbeatTimeout is to timeout behavior of: RespondDecisionTaskComplete with ForceCreateNewDecisionTask == true without any decisions
 	// So that decision will be scheduled to another worker(by clear stickyness)
 	DecisionHeartbeatTimeout dynamicconfig.DurationPropertyFnWithDomainFilter
+	// CronSmoothingWindow is the window over which the runs of cron workflows firing at the same time are spread
+	CronSmoothingWindow dynamicconfig.DurationPropertyFnWithDomainFilter
 	// MaxDecisionStartToCloseSeconds is the StartToCloseSeconds for decision
 	MaxDecisionStartToCloseSeconds           dynamicconfig.IntPropertyFnWithDomainFilter
 	DecisionRetryCriticalAttempts            dynamicconfig.IntPropertyFn
@@ -284,11 +305,16 @@ type Config struct {
 	ReplicationTaskProcessorShardQPS                   dynamicconfig.FloatPropertyFn
 	ReplicationTaskGenerationQPS                       dynamicconfig.FloatPropertyFn
 	EnableReplicationTaskGeneration                    dynamicconfig.BoolPropertyFnWithDomainIDAndWorkflowIDFilter
+	EnableReplicationDLQAutoRepair                     dynamicconfig.BoolPropertyFn
+	ReplicationDLQAutoRepairInterval                   dynamicconfig.DurationPropertyFn
+	ReplicationDLQAutoRepairMaxBackoff                 dynamicconfig.DurationPropertyFn
 	EnableRecordWorkflowExecutionUninitialized         dynamicconfig.BoolPropertyFnWithDomainFilter
 
 	// The following are used by consistent query
 	EnableConsistentQuery         dynamicconfig.BoolPropertyFn
 	EnableConsistentQueryByDomain dynamicconfig.BoolPropertyFnWithDomainFilter
+	EnableConsistentQueryCatchUp  dynamicconfig.BoolPropertyFnWithDomainFilter
+	ConsistentQueryCatchUpTimeout dynamicconfig.DurationPropertyFnWithDomainIDFilter
 	MaxBufferedQueryCount         dynamicconfig.IntPropertyFn
 
 	EnableCrossClusterOperations dynamicconfig.BoolPropertyFnWithDomainFilter
@@ -310,6 +336,8 @@ type Config struct {
 	EnableActivityLocalDispatchByDomain dynamicconfig.BoolPropertyFnWithDomainFilter
 	// Max # of activity tasks to dispatch to matching before creating transfer tasks. This is an performance optimization to skip activity scheduling efforts.
 	MaxActivityCountDispatchByDomain dynamicconfig.IntPropertyFnWithDomainFilter
+	// Max # of activity tasks requested for local dispatch to return to the worker. Activities beyond it are scheduled through matching.
+	MaxActivityCountLocalDispatchByDomain dynamicconfig.IntPropertyFnWithDomainFilter
 
 	ActivityMaxScheduleToStartTimeoutForRetry dynamicconfig.DurationPropertyFnWithDomainFilter
 
@@ -324,6 +352,8 @@ func New(dc *dynamicconfig.Collection, numberOfShards int, maxMessageSize int, s
 		NumberOfShards:                       numberOfShards,
 		IsAdvancedVisConfigExist:             isAdvancedVisConfigExist,
 		RPS:                                  dc.GetIntProperty(dynamicconfig.HistoryRPS),
+		DomainResourcePool:                   dc.GetStringPropertyFilteredByDomain(dynamicconfig.DomainResourcePool),
+		ResourcePoolRPS:                      dc.GetMapProperty(dynamicconfig.HistoryResourcePoolRPS),
 		MaxIDLengthWarnLimit:                 dc.GetIntProperty(dynamicconfig.MaxIDLengthWarnLimit),
 		DomainNameMaxLength:                  dc.GetIntPropertyFilteredByDomain(dynamicconfig.DomainNameMaxLength),
 		IdentityMaxLength:                    dc.GetIntPropertyFilteredByDomain(dynamicconfig.IdentityMaxLength),
@@ -339,6 +369,7 @@ func New(dc *dynamicconfig.Collection, numberOfShards int, maxMessageSize int, s
 		PersistenceMaxQPS:                    dc.GetIntProperty(dynamicconfig.HistoryPersistenceMaxQPS),
 		PersistenceGlobalMaxQPS:              dc.GetIntProperty(dynamicconfig.HistoryPersistenceGlobalMaxQPS),
 		ShutdownDrainDuration:                dc.GetDurationProperty(dynamicconfig.HistoryShutdownDrainDuration),
+		EnableShardHandoff:                   dc.GetBoolProperty(dynamicconfig.EnableShardHandoff),
 		EnableVisibilitySampling:             dc.GetBoolProperty(dynamicconfig.EnableVisibilitySampling),
 		EnableReadFromClosedExecutionV2:      dc.GetBoolProperty(dynamicconfig.EnableReadFromClosedExecutionV2),
 		VisibilityOpenMaxQPS:                 dc.GetIntPropertyFilteredByDomain(dynamicconfig.HistoryVisibilityOpenMaxQPS),
@@ -360,6 +391,7 @@ func New(dc *dynamicconfig.Collection, numberOfShards int, maxMessageSize int, s
 		RangeSizeBits:                        20, // 20 bits for sequencer, 2^20 sequence number for any range
 		AcquireShardInterval:                 dc.GetDurationProperty(dynamicconfig.AcquireShardInterval),
 		AcquireShardConcurrency:              dc.GetIntProperty(dynamicconfig.AcquireShardConcurrency),
+		ShardHandoffMaxConcurrency:           dc.GetIntProperty(dynamicconfig.ShardHandoffMaxConcurrency),
 		StandbyClusterDelay:                  dc.GetDurationProperty(dynamicconfig.StandbyClusterDelay),
 		StandbyTaskMissingEventsResendDelay:  dc.GetDurationProperty(dynamicconfig.StandbyTaskMissingEventsResendDelay),
 		StandbyTaskMissingEventsDiscardDelay: dc.GetDurationProperty(dynamicconfig.StandbyTaskMissingEventsDiscardDelay),
@@ -372,8 +404,12 @@ func New(dc *dynamicconfig.Collection, numberOfShards int, maxMessageSize int, s
 		TaskSchedulerShardWorkerCount:           dc.GetIntProperty(dynamicconfig.TaskSchedulerShardWorkerCount),
 		TaskSchedulerQueueSize:                  dc.GetIntProperty(dynamicconfig.TaskSchedulerQueueSize),
 		TaskSchedulerShardQueueSize:             dc.GetIntProperty(dynamicconfig.TaskSchedulerShardQueueSize),
+		LoadSheddingTaskRPS:                     dc.GetIntProperty(dynamicconfig.HistoryLoadSheddingTaskRPS),
+		ResourcePoolTaskWorkerCount:             dc.GetMapProperty(dynamicconfig.HistoryResourcePoolTaskWorkerCount),
 		TaskSchedulerDispatcherCount:            dc.GetIntProperty(dynamicconfig.TaskSchedulerDispatcherCount),
 		TaskSchedulerRoundRobinWeights:          dc.GetMapProperty(dynamicconfig.TaskSchedulerRoundRobinWeights),
+		TaskSchedulerEnableFairScheduling:       dc.GetBoolProperty(dynamicconfig.TaskSchedulerEnableFairScheduling),
+		TaskSchedulerDomainWeights:              dc.GetMapProperty(dynamicconfig.TaskSchedulerDomainWeights),
 		TaskCriticalRetryCount:                  dc.GetIntProperty(dynamicconfig.TaskCriticalRetryCount),
 		ActiveTaskRedispatchInterval:            dc.GetDurationProperty(dynamicconfig.ActiveTaskRedispatchInterval),
 		StandbyTaskRedispatchInterval:           dc.GetDurationProperty(dynamicconfig.StandbyTaskRedispatchInterval),
@@ -382,19 +418,20 @@ func New(dc *dynamicconfig.Collection, numberOfShards int, maxMessageSize int, s
 		EnableDropStuckTaskByDomainID:           dc.GetBoolPropertyFilteredByDomainID(dynamicconfig.EnableDropStuckTaskByDomainID),
 		ResurrectionCheckMinDelay:               dc.GetDurationPropertyFilteredByDomain(dynamicconfig.ResurrectionCheckMinDelay),
 
-		QueueProcessorEnableSplit:                          dc.GetBoolProperty(dynamicconfig.QueueProcessorEnableSplit),
-		QueueProcessorSplitMaxLevel:                        dc.GetIntProperty(dynamicconfig.QueueProcessorSplitMaxLevel),
-		QueueProcessorEnableRandomSplitByDomainID:          dc.GetBoolPropertyFilteredByDomainID(dynamicconfig.QueueProcessorEnableRandomSplitByDomainID),
-		QueueProcessorRandomSplitProbability:               dc.GetFloat64Property(dynamicconfig.QueueProcessorRandomSplitProbability),
-		QueueProcessorEnablePendingTaskSplitByDomainID:     dc.GetBoolPropertyFilteredByDomainID(dynamicconfig.QueueProcessorEnablePendingTaskSplitByDomainID),
-		QueueProcessorPendingTaskSplitThreshold:            dc.GetMapProperty(dynamicconfig.QueueProcessorPendingTaskSplitThreshold),
-		QueueProcessorEnableStuckTaskSplitByDomainID:       dc.GetBoolPropertyFilteredByDomainID(dynamicconfig.QueueProcessorEnableStuckTaskSplitByDomainID),
-		QueueProcessorStuckTaskSplitThreshold:              dc.GetMapProperty(dynamicconfig.QueueProcessorStuckTaskSplitThreshold),
-		QueueProcessorSplitLookAheadDurationByDomainID:     dc.GetDurationPropertyFilteredByDomainID(dynamicconfig.QueueProcessorSplitLookAheadDurationByDomainID),
-		QueueProcessorPollBackoffInterval:                  dc.GetDurationProperty(dynamicconfig.QueueProcessorPollBackoffInterval),
-		QueueProcessorPollBackoffIntervalJitterCoefficient: dc.GetFloat64Property(dynamicconfig.QueueProcessorPollBackoffIntervalJitterCoefficient),
-		QueueProcessorEnablePersistQueueStates:             dc.GetBoolProperty(dynamicconfig.QueueProcessorEnablePersistQueueStates),
-		QueueProcessorEnableLoadQueueStates:                dc.GetBoolProperty(dynamicconfig.QueueProcessorEnableLoadQueueStates),
+		QueueProcessorEnableSplit:                           dc.GetBoolProperty(dynamicconfig.QueueProcessorEnableSplit),
+		QueueProcessorSplitMaxLevel:                         dc.GetIntProperty(dynamicconfig.QueueProcessorSplitMaxLevel),
+		QueueProcessorEnableRandomSplitByDomainID:           dc.GetBoolPropertyFilteredByDomainID(dynamicconfig.QueueProcessorEnableRandomSplitByDomainID),
+		QueueProcessorRandomSplitProbability:                dc.GetFloat64Property(dynamicconfig.QueueProcessorRandomSplitProbability),
+		QueueProcessorEnablePendingTaskSplitByDomainID:      dc.GetBoolPropertyFilteredByDomainID(dynamicconfig.QueueProcessorEnablePendingTaskSplitByDomainID),
+		QueueProcessorPendingTaskSplitThreshold:             dc.GetMapProperty(dynamicconfig.QueueProcessorPendingTaskSplitThreshold),
+		QueueProcessorEnableStuckTaskSplitByDomainID:        dc.GetBoolPropertyFilteredByDomainID(dynamicconfig.QueueProcessorEnableStuckTaskSplitByDomainID),
+		QueueProcessorStuckTaskSplitThreshold:               dc.GetMapProperty(dynamicconfig.QueueProcessorStuckTaskSplitThreshold),
+		QueueProcessorEnableLowPrioritySplitQueueByDomainID: dc.GetBoolPropertyFilteredByDomainID(dynamicconfig.QueueProcessorEnableLowPrioritySplitQueueByDomainID),
+		QueueProcessorSplitLookAheadDurationByDomainID:      dc.GetDurationPropertyFilteredByDomainID(dynamicconfig.QueueProcessorSplitLookAheadDurationByDomainID),
+		QueueProcessorPollBackoffInterval:                   dc.GetDurationProperty(dynamicconfig.QueueProcessorPollBackoffInterval),
+		QueueProcessorPollBackoffIntervalJitterCoefficient:  dc.GetFloat64Property(dynamicconfig.QueueProcessorPollBackoffIntervalJitterCoefficient),
+		QueueProcessorEnablePersistQueueStates:              dc.GetBoolProperty(dynamicconfig.QueueProcessorEnablePersistQueueStates),
+		QueueProcessorEnableLoadQueueStates:                 dc.GetBoolProperty(dynamicconfig.QueueProcessorEnableLoadQueueStates),
 
 		TimerTaskBatchSize:                                dc.GetIntProperty(dynamicconfig.TimerTaskBatchSize),
 		TimerTaskDeleteBatchSize:                          dc.GetIntProperty(dynamicconfig.TimerTaskDeleteBatchSize),
@@ -466,6 +503,8 @@ func New(dc *dynamicconfig.Collection, numberOfShards int, maxMessageSize int, s
 		HistoryMgrNumConns:              dc.GetIntProperty(dynamicconfig.HistoryMgrNumConns),
 		MaximumBufferedEventsBatch:      dc.GetIntProperty(dynamicconfig.MaximumBufferedEventsBatch),
 		MaximumSignalsPerExecution:      dc.GetIntPropertyFilteredByDomain(dynamicconfig.MaximumSignalsPerExecution),
+		WorkflowIDSignalRPS:             dc.GetIntPropertyFilteredByDomain(dynamicconfig.WorkflowIDSignalRPS),
+		WorkflowIDEventsRPS:             dc.GetIntPropertyFilteredByDomain(dynamicconfig.WorkflowIDEventsRPS),
 		ShardUpdateMinInterval:          dc.GetDurationProperty(dynamicconfig.ShardUpdateMinInterval),
 		ShardSyncMinInterval:            dc.GetDurationProperty(dynamicconfig.ShardSyncMinInterval),
 		ShardSyncTimerJitterCoefficient: dc.GetFloat64Property(dynamicconfig.TransferProcessorMaxPollIntervalJitterCoefficient),
@@ -492,9 +531,13 @@ func New(dc *dynamicconfig.Collection, numberOfShards int, maxMessageSize int, s
 		HistorySizeLimitWarn:             dc.GetIntPropertyFilteredByDomain(dynamicconfig.HistorySizeLimitWarn),
 		HistoryCountLimitError:           dc.GetIntPropertyFilteredByDomain(dynamicconfig.HistoryCountLimitError),
 		HistoryCountLimitWarn:            dc.GetIntPropertyFilteredByDomain(dynamicconfig.HistoryCountLimitWarn),
-		PendingActivitiesCountLimitError: dc.GetIntProperty(dynamicconfig.PendingActivitiesCountLimitError),
-		PendingActivitiesCountLimitWarn:  dc.GetIntProperty(dynamicconfig.PendingActivitiesCountLimitWarn),
+		PendingActivitiesCountLimitError: dc.GetIntPropertyFilteredByDomain(dynamicconfig.PendingActivitiesCountLimitError),
+		PendingActivitiesCountLimitWarn:  dc.GetIntPropertyFilteredByDomain(dynamicconfig.PendingActivitiesCountLimitWarn),
 		PendingActivityValidationEnabled: dc.GetBoolProperty(dynamicconfig.EnablePendingActivityValidation),
+		// the pending child workflow limits are enforced with the pending activity limits
+		PendingChildExecutionsCountLimitError: dc.GetIntPropertyFilteredByDomain(dynamicconfig.PendingChildExecutionsCountLimitError),
+		PendingChildExecutionsCountLimitWarn:  dc.GetIntPropertyFilteredByDomain(dynamicconfig.PendingChildExecutionsCountLimitWarn),
+		EnablePendingCountLimitFailure:        dc.GetBoolPropertyFilteredByDomain(dynamicconfig.EnablePendingCountLimitFailure),
 
 		ThrottledLogRPS:   dc.GetIntProperty(dynamicconfig.HistoryThrottledLogRPS),
 		EnableStickyQuery: dc.GetBoolPropertyFilteredByDomain(dynamicconfig.EnableStickyQuery),
@@ -503,8 +546,10 @@ func New(dc *dynamicconfig.Collection, numberOfShards int, maxMessageSize int, s
 		SearchAttributesNumberOfKeysLimit:        dc.GetIntPropertyFilteredByDomain(dynamicconfig.SearchAttributesNumberOfKeysLimit),
 		SearchAttributesSizeOfValueLimit:         dc.GetIntPropertyFilteredByDomain(dynamicconfig.SearchAttributesSizeOfValueLimit),
 		SearchAttributesTotalSizeLimit:           dc.GetIntPropertyFilteredByDomain(dynamicconfig.SearchAttributesTotalSizeLimit),
+		EnableHistorySizeWarningSearchAttribute:  dc.GetBoolPropertyFilteredByDomain(dynamicconfig.EnableHistorySizeWarningSearchAttribute),
 		StickyTTL:                                dc.GetDurationPropertyFilteredByDomain(dynamicconfig.StickyTTL),
 		DecisionHeartbeatTimeout:                 dc.GetDurationPropertyFilteredByDomain(dynamicconfig.DecisionHeartbeatTimeout),
+		CronSmoothingWindow:                      dc.GetDurationPropertyFilteredByDomain(dynamicconfig.CronSmoothingWindow),
 		DecisionRetryCriticalAttempts:            dc.GetIntProperty(dynamicconfig.DecisionRetryCriticalAttempts),
 		DecisionRetryMaxAttempts:                 dc.GetIntPropertyFilteredByDomain(dynamicconfig.DecisionRetryMaxAttempts),
 		NormalDecisionScheduleToStartMaxAttempts: dc.GetIntPropertyFilteredByDomain(dynamicconfig.NormalDecisionScheduleToStartMaxAttempts),
@@ -529,10 +574,15 @@ func New(dc *dynamicconfig.Collection, numberOfShards int, maxMessageSize int, s
 		ReplicationTaskProcessorShardQPS:                   dc.GetFloat64Property(dynamicconfig.ReplicationTaskProcessorShardQPS),
 		ReplicationTaskGenerationQPS:                       dc.GetFloat64Property(dynamicconfig.ReplicationTaskGenerationQPS),
 		EnableReplicationTaskGeneration:                    dc.GetBoolPropertyFilteredByDomainIDAndWorkflowID(dynamicconfig.EnableReplicationTaskGeneration),
+		EnableReplicationDLQAutoRepair:                     dc.GetBoolProperty(dynamicconfig.EnableReplicationDLQAutoRepair),
+		ReplicationDLQAutoRepairInterval:                   dc.GetDurationProperty(dynamicconfig.ReplicationDLQAutoRepairInterval),
+		ReplicationDLQAutoRepairMaxBackoff:                 dc.GetDurationProperty(dynamicconfig.ReplicationDLQAutoRepairMaxBackoff),
 		EnableRecordWorkflowExecutionUninitialized:         dc.GetBoolPropertyFilteredByDomain(dynamicconfig.EnableRecordWorkflowExecutionUninitialized),
 
 		EnableConsistentQuery:                 dc.GetBoolProperty(dynamicconfig.EnableConsistentQuery),
 		EnableConsistentQueryByDomain:         dc.GetBoolPropertyFilteredByDomain(dynamicconfig.EnableConsistentQueryByDomain),
+		EnableConsistentQueryCatchUp:          dc.GetBoolPropertyFilteredByDomain(dynamicconfig.EnableConsistentQueryCatchUp),
+		ConsistentQueryCatchUpTimeout:         dc.GetDurationPropertyFilteredByDomainID(dynamicconfig.ConsistentQueryCatchUpTimeout),
 		EnableCrossClusterOperations:          dc.GetBoolPropertyFilteredByDomain(dynamicconfig.EnableCrossClusterOperations),
 		MaxBufferedQueryCount:                 dc.GetIntProperty(dynamicconfig.MaxBufferedQueryCount),
 		MutableStateChecksumGenProbability:    dc.GetIntPropertyFilteredByDomain(dynamicconfig.MutableStateChecksumGenProbability),
@@ -545,8 +595,9 @@ func New(dc *dynamicconfig.Collection, numberOfShards int, maxMessageSize int, s
 		NotifyFailoverMarkerTimerJitterCoefficient: dc.GetFloat64Property(dynamicconfig.NotifyFailoverMarkerTimerJitterCoefficient),
 		EnableGracefulFailover:                     dc.GetBoolProperty(dynamicconfig.EnableGracefulFailover),
 
-		EnableActivityLocalDispatchByDomain: dc.GetBoolPropertyFilteredByDomain(dynamicconfig.EnableActivityLocalDispatchByDomain),
-		MaxActivityCountDispatchByDomain:    dc.GetIntPropertyFilteredByDomain(dynamicconfig.MaxActivityCountDispatchByDomain),
+		EnableActivityLocalDispatchByDomain:   dc.GetBoolPropertyFilteredByDomain(dynamicconfig.EnableActivityLocalDispatchByDomain),
+		MaxActivityCountDispatchByDomain:      dc.GetIntPropertyFilteredByDomain(dynamicconfig.MaxActivityCountDispatchByDomain),
+		MaxActivityCountLocalDispatchByDomain: dc.GetIntPropertyFilteredByDomain(dynamicconfig.MaxActivityCountLocalDispatchByDomain),
 
 		ActivityMaxScheduleToStartTimeoutForRetry: dc.GetDurationPropertyFilteredByDomain(dynamicconfig.ActivityMaxScheduleToStartTimeoutForRetry),
 
@@ -34,6 +34,7 @@ import (
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/redaction"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/history/config"
 	"github.com/uber/cadence/service/history/execution"
@@ -58,6 +59,16 @@ type (
 		historyCountLimitWarn  int
 		historyCountLimitError int
 
+		pendingActivitiesCountLimitWarn       int
+		pendingActivitiesCountLimitError      int
+		pendingChildExecutionsCountLimitWarn  int
+		pendingChildExecutionsCountLimitError int
+		// pendingCountLimitFailure fails the executions over the pending count error limits
+		pendingCountLimitFailure bool
+
+		// historySizeWarningSearchAttribute flags the executions over the warn limits in visibility
+		historySizeWarningSearchAttribute bool
+
 		completedID    int64
 		mutableState   execution.MutableState
 		executionStats *persistence.ExecutionStats
@@ -71,6 +82,7 @@ func newAttrValidator(
 	metricsClient metrics.Client,
 	config *config.Config,
 	logger log.Logger,
+	redactor redaction.Redactor,
 ) *attrValidator {
 	return &attrValidator{
 		config:        config,
@@ -79,6 +91,7 @@ func newAttrValidator(
 		logger:        logger,
 		searchAttributesValidator: validator.NewSearchAttributesValidator(
 			logger,
+			redactor,
 			config.EnableQueryAttributeValidation,
 			config.ValidSearchAttributes,
 			config.SearchAttributesNumberOfKeysLimit,
@@ -95,6 +108,12 @@ func newWorkflowSizeChecker(
 	historySizeLimitError int,
 	historyCountLimitWarn int,
 	historyCountLimitError int,
+	pendingActivitiesCountLimitWarn int,
+	pendingActivitiesCountLimitError int,
+	pendingChildExecutionsCountLimitWarn int,
+	pendingChildExecutionsCountLimitError int,
+	pendingCountLimitFailure bool,
+	historySizeWarningSearchAttribute bool,
 	completedID int64,
 	mutableState execution.MutableState,
 	executionStats *persistence.ExecutionStats,
@@ -113,6 +132,14 @@ func newWorkflowSizeChecker(
 		executionStats:         executionStats,
 		metricsScope:           metricsScope,
 		logger:                 logger,
+
+		pendingActivitiesCountLimitWarn:       pendingActivitiesCountLimitWarn,
+		pendingActivitiesCountLimitError:      pendingActivitiesCountLimitError,
+		pendingChildExecutionsCountLimitWarn:  pendingChildExecutionsCountLimitWarn,
+		pendingChildExecutionsCountLimitError: pendingChildExecutionsCountLimitError,
+		pendingCountLimitFailure:              pendingCountLimitFailure,
+
+		historySizeWarningSearchAttribute: historySizeWarningSearchAttribute,
 	}
 }
 
@@ -182,12 +209,72 @@ func (c *workflowSizeChecker) failWorkflowSizeExceedsLimit() (bool, error) {
 			tag.WorkflowRunID(executionInfo.RunID),
 			tag.WorkflowHistorySize(historySize),
 			tag.WorkflowEventCount(historyCount))
+
+		if historySize > c.historySizeLimitWarn {
+			c.metricsScope.IncCounter(metrics.HistorySizeWarnLimitExceededCounter)
+		}
+		if historyCount > c.historyCountLimitWarn {
+			c.metricsScope.IncCounter(metrics.HistoryCountWarnLimitExceededCounter)
+		}
+		if c.historySizeWarningSearchAttribute {
+			flagged, err := c.mutableState.SetHistorySizeWarning()
+			if err != nil {
+				return false, err
+			}
+			if flagged {
+				c.metricsScope.IncCounter(metrics.HistorySizeWarningFlaggedCounter)
+			}
+		}
 		return false, nil
 	}
 
 	return false, nil
 }
 
+// failWorkflowPendingCountExceedsLimit fails the workflow if it has more pending activities or child workflows
+// than the error limits, so that its mutable state doesn't keep on growing
+func (c *workflowSizeChecker) failWorkflowPendingCountExceedsLimit() (bool, error) {
+	activityCount := len(c.mutableState.GetPendingActivityInfos())
+	childCount := len(c.mutableState.GetPendingChildExecutionInfos())
+
+	if activityCount > c.pendingActivitiesCountLimitError || childCount > c.pendingChildExecutionsCountLimitError {
+		executionInfo := c.mutableState.GetExecutionInfo()
+		c.logger.Error("pending activity or child workflow count exceeds error limit.",
+			tag.WorkflowDomainID(executionInfo.DomainID),
+			tag.WorkflowID(executionInfo.WorkflowID),
+			tag.WorkflowRunID(executionInfo.RunID),
+			tag.WorkflowPendingActivityCount(activityCount),
+			tag.WorkflowPendingChildCount(childCount))
+		c.metricsScope.IncCounter(metrics.PendingCountErrorLimitExceededCounter)
+		if !c.pendingCountLimitFailure {
+			return false, nil
+		}
+
+		attributes := &types.FailWorkflowExecutionDecisionAttributes{
+			Reason: common.StringPtr(common.FailureReasonPendingCountExceedsLimit),
+			Details: []byte(fmt.Sprintf(
+				"Workflow has %v pending activities and %v pending child workflows, over the limits of %v and %v.",
+				activityCount,
+				childCount,
+				c.pendingActivitiesCountLimitError,
+				c.pendingChildExecutionsCountLimitError,
+			)),
+		}
+		if _, err := c.mutableState.AddFailWorkflowEvent(c.completedID, attributes); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if activityCount > c.pendingActivitiesCountLimitWarn {
+		c.metricsScope.IncCounter(metrics.PendingActivityCountWarnLimitExceededCounter)
+	}
+	if childCount > c.pendingChildExecutionsCountLimitWarn {
+		c.metricsScope.IncCounter(metrics.PendingChildExecutionCountWarnLimitExceededCounter)
+	}
+	return false, nil
+}
+
 func (v *attrValidator) validateActivityScheduleAttributes(
 	domainID string,
 	targetDomainID string,
@@ -703,6 +790,13 @@ func (v *attrValidator) validateStartChildExecutionAttributes(
 		return err
 	}
 
+	if err := v.validateChildTargetDomain(
+		domainID,
+		targetDomainID,
+	); err != nil {
+		return err
+	}
+
 	if attributes == nil {
 		return &types.BadRequestError{Message: "StartChildWorkflowExecutionDecisionAttributes is not set on decision."}
 	}
@@ -759,6 +853,9 @@ func (v *attrValidator) validateStartChildExecutionAttributes(
 		if _, err := backoff.ValidateSchedule(attributes.GetCronSchedule()); err != nil {
 			return err
 		}
+		if err := backoff.ValidateCronOverlapPolicy(attributes.Header); err != nil {
+			return err
+		}
 	}
 
 	// Inherit tasklist from parent workflow execution if not provided on decision
@@ -882,6 +979,30 @@ func (v *attrValidator) validateCrossDomainCall(
 	return v.createCrossDomainCallError(sourceDomainEntry, targetDomainEntry)
 }
 
+// validateChildTargetDomain makes sure a child workflow is not started in a domain which no longer accepts
+// new workflows, otherwise the start child transfer task would keep failing
+func (v *attrValidator) validateChildTargetDomain(
+	domainID string,
+	targetDomainID string,
+) error {
+
+	if domainID == targetDomainID {
+		return nil
+	}
+
+	targetDomainEntry, err := v.domainCache.GetDomainByID(targetDomainID)
+	if err != nil {
+		return err
+	}
+	if targetDomainEntry.GetInfo().Status != persistence.DomainStatusRegistered {
+		return &types.BadRequestError{Message: fmt.Sprintf(
+			"cannot start child workflow in domain %v as it is deprecated",
+			targetDomainEntry.GetInfo().Name,
+		)}
+	}
+	return nil
+}
+
 func (v *attrValidator) createCrossDomainCallError(
 	domainEntry *cache.DomainCacheEntry,
 	targetDomainEntry *cache.DomainCacheEntry,
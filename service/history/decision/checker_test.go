@@ -27,6 +27,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
 
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/cache"
@@ -36,9 +37,11 @@ import (
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/redaction"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/history/config"
 	"github.com/uber/cadence/service/history/constants"
+	"github.com/uber/cadence/service/history/execution"
 )
 
 type (
@@ -105,6 +108,7 @@ func (s *attrValidatorSuite) SetupTest() {
 		metrics.NewNoopMetricsClient(),
 		config,
 		log.NewNoop(),
+		redaction.NewNopRedactor(),
 	)
 }
 
@@ -535,6 +539,35 @@ func (s *attrValidatorSuite) TestValidateCrossDomainCall_GlobalToGlobal_DiffDoma
 	s.IsType(&types.BadRequestError{}, err)
 }
 
+func (s *attrValidatorSuite) TestValidateChildTargetDomain_SameDomain() {
+	err := s.validator.validateChildTargetDomain(s.testDomainID, s.testDomainID)
+	s.Nil(err)
+}
+
+func (s *attrValidatorSuite) TestValidateChildTargetDomain_Registered() {
+	targetDomainEntry := cache.NewLocalDomainCacheEntryForTest(
+		&persistence.DomainInfo{Name: s.testTargetDomainID, Status: persistence.DomainStatusRegistered},
+		nil,
+		cluster.TestCurrentClusterName,
+	)
+	s.mockDomainCache.EXPECT().GetDomainByID(s.testTargetDomainID).Return(targetDomainEntry, nil).Times(1)
+
+	err := s.validator.validateChildTargetDomain(s.testDomainID, s.testTargetDomainID)
+	s.Nil(err)
+}
+
+func (s *attrValidatorSuite) TestValidateChildTargetDomain_Deprecated() {
+	targetDomainEntry := cache.NewLocalDomainCacheEntryForTest(
+		&persistence.DomainInfo{Name: s.testTargetDomainID, Status: persistence.DomainStatusDeprecated},
+		nil,
+		cluster.TestCurrentClusterName,
+	)
+	s.mockDomainCache.EXPECT().GetDomainByID(s.testTargetDomainID).Return(targetDomainEntry, nil).Times(1)
+
+	err := s.validator.validateChildTargetDomain(s.testDomainID, s.testTargetDomainID)
+	s.IsType(&types.BadRequestError{}, err)
+}
+
 func (s *attrValidatorSuite) TestValidateTaskListName() {
 	taskList := func(name string) *types.TaskList {
 		kind := types.TaskListKindNormal
@@ -756,3 +789,133 @@ func (s *attrValidatorSuite) TestValidateActivityScheduleAttributes_WithRetryPol
 	s.Nil(err)
 	s.Equal(expectedAttributesAfterValidation, attributes)
 }
+
+func TestWorkflowSizeChecker_WarnLimit(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mutableState := execution.NewMockMutableState(controller)
+	mutableState.EXPECT().GetNextEventID().Return(int64(101)).AnyTimes()
+	mutableState.EXPECT().GetExecutionInfo().Return(&persistence.WorkflowExecutionInfo{}).AnyTimes()
+	mutableState.EXPECT().SetHistorySizeWarning().Return(true, nil).Times(1)
+	mutableState.EXPECT().SetHistorySizeWarning().Return(false, nil).Times(1)
+
+	testScope := tally.NewTestScope("test", nil)
+	checker := newWorkflowSizeChecker(
+		1024, 2048,
+		1024, 2048,
+		50, 200,
+		512, 1024,
+		512, 1024,
+		false,
+		true,
+		1,
+		mutableState,
+		&persistence.ExecutionStats{HistorySize: 100},
+		metrics.NewClient(testScope, metrics.History).Scope(metrics.HistoryRespondDecisionTaskCompletedScope),
+		log.NewNoop(),
+	)
+
+	for i := 0; i < 2; i++ {
+		failed, err := checker.failWorkflowSizeExceedsLimit()
+		require.NoError(t, err)
+		require.False(t, failed)
+	}
+
+	counters := map[string]int64{}
+	for _, c := range testScope.Snapshot().Counters() {
+		counters[c.Name()] = c.Value()
+	}
+	require.Equal(t, int64(2), counters["test.history_count_warn_limit_exceeded"])
+	require.Equal(t, int64(1), counters["test.history_size_warning_flagged"])
+	require.NotContains(t, counters, "test.history_size_warn_limit_exceeded")
+}
+
+func TestWorkflowSizeChecker_PendingCountLimit(t *testing.T) {
+	pendingActivities := func(count int) map[int64]*persistence.ActivityInfo {
+		activities := make(map[int64]*persistence.ActivityInfo, count)
+		for i := 0; i < count; i++ {
+			activities[int64(i)] = &persistence.ActivityInfo{}
+		}
+		return activities
+	}
+	pendingChildren := func(count int) map[int64]*persistence.ChildExecutionInfo {
+		children := make(map[int64]*persistence.ChildExecutionInfo, count)
+		for i := 0; i < count; i++ {
+			children[int64(i)] = &persistence.ChildExecutionInfo{}
+		}
+		return children
+	}
+
+	for name, tc := range map[string]struct {
+		activityCount    int
+		childCount       int
+		failureEnabled   bool
+		expectedFailed   bool
+		expectedCounters []string
+	}{
+		"under warn limits": {
+			activityCount: 1,
+			childCount:    1,
+		},
+		"over warn limits": {
+			activityCount:    3,
+			childCount:       3,
+			expectedCounters: []string{"test.pending_activity_count_warn_limit_exceeded", "test.pending_child_execution_count_warn_limit_exceeded"},
+		},
+		"over child error limit, failure disabled": {
+			activityCount:    1,
+			childCount:       5,
+			expectedCounters: []string{"test.pending_count_error_limit_exceeded"},
+		},
+		"over activity error limit, failure enabled": {
+			activityCount:    5,
+			childCount:       1,
+			failureEnabled:   true,
+			expectedFailed:   true,
+			expectedCounters: []string{"test.pending_count_error_limit_exceeded"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			mutableState := execution.NewMockMutableState(controller)
+			mutableState.EXPECT().GetExecutionInfo().Return(&persistence.WorkflowExecutionInfo{}).AnyTimes()
+			mutableState.EXPECT().GetPendingActivityInfos().Return(pendingActivities(tc.activityCount)).Times(1)
+			mutableState.EXPECT().GetPendingChildExecutionInfos().Return(pendingChildren(tc.childCount)).Times(1)
+			if tc.expectedFailed {
+				mutableState.EXPECT().AddFailWorkflowEvent(int64(1), &types.FailWorkflowExecutionDecisionAttributes{
+					Reason:  common.StringPtr(common.FailureReasonPendingCountExceedsLimit),
+					Details: []byte("Workflow has 5 pending activities and 1 pending child workflows, over the limits of 4 and 4."),
+				}).Return(&types.HistoryEvent{}, nil).Times(1)
+			}
+
+			testScope := tally.NewTestScope("test", nil)
+			checker := newWorkflowSizeChecker(
+				1024, 2048,
+				1024, 2048,
+				50, 200,
+				2, 4,
+				2, 4,
+				tc.failureEnabled,
+				false,
+				1,
+				mutableState,
+				&persistence.ExecutionStats{HistorySize: 100},
+				metrics.NewClient(testScope, metrics.History).Scope(metrics.HistoryRespondDecisionTaskCompletedScope),
+				log.NewNoop(),
+			)
+
+			failed, err := checker.failWorkflowPendingCountExceedsLimit()
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedFailed, failed)
+
+			var counters []string
+			for _, c := range testScope.Snapshot().Counters() {
+				counters = append(counters, c.Name())
+			}
+			require.ElementsMatch(t, tc.expectedCounters, counters)
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"go.uber.org/yarpc"
 
 	"github.com/uber/cadence/common"
@@ -35,6 +36,7 @@ import (
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/tracing"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/history/config"
 	"github.com/uber/cadence/service/history/execution"
@@ -94,6 +96,7 @@ func NewHandler(
 			shard.GetMetricsClient(),
 			config,
 			logger,
+			shard.GetService().GetRedactor(),
 		),
 		versionChecker: client.NewVersionChecker(),
 	}
@@ -150,7 +153,10 @@ func (handler *handlerImpl) HandleDecisionTaskScheduled(
 func (handler *handlerImpl) HandleDecisionTaskStarted(
 	ctx context.Context,
 	req *types.RecordDecisionTaskStartedRequest,
-) (*types.RecordDecisionTaskStartedResponse, error) {
+) (_ *types.RecordDecisionTaskStartedResponse, retError error) {
+
+	span, ctx := startDecisionSpan(ctx, "history.DecisionTaskStarted", req.DomainUUID, req.WorkflowExecution)
+	defer func() { tracing.FinishSpan(span, retError) }()
 
 	domainEntry, err := handler.getActiveDomainByID(req.DomainUUID)
 	if err != nil {
@@ -287,6 +293,9 @@ func (handler *handlerImpl) HandleDecisionTaskCompleted(
 	req *types.HistoryRespondDecisionTaskCompletedRequest,
 ) (resp *types.HistoryRespondDecisionTaskCompletedResponse, retError error) {
 
+	span, ctx := startDecisionSpan(ctx, "history.DecisionTaskCompleted", req.DomainUUID, nil)
+	defer func() { tracing.FinishSpan(span, retError) }()
+
 	domainEntry, err := handler.getActiveDomainByID(req.DomainUUID)
 	if err != nil {
 		return nil, err
@@ -428,6 +437,12 @@ Update_History_Loop:
 				handler.config.HistorySizeLimitError(domainName),
 				handler.config.HistoryCountLimitWarn(domainName),
 				handler.config.HistoryCountLimitError(domainName),
+				handler.config.PendingActivitiesCountLimitWarn(domainName),
+				handler.config.PendingActivitiesCountLimitError(domainName),
+				handler.config.PendingChildExecutionsCountLimitWarn(domainName),
+				handler.config.PendingChildExecutionsCountLimitError(domainName),
+				handler.config.EnablePendingCountLimitFailure(domainName),
+				handler.config.EnableHistorySizeWarningSearchAttribute(domainName),
 				completedEvent.ID,
 				msBuilder,
 				executionStats,
@@ -888,3 +903,19 @@ func (handler *handlerImpl) failDecisionHelper(
 func (handler *handlerImpl) getActiveDomainByID(id string) (*cache.DomainCacheEntry, error) {
 	return cache.GetActiveDomainByID(handler.shard.GetDomainCache(), handler.shard.GetClusterMetadata().GetCurrentClusterName(), id)
 }
+
+// startDecisionSpan starts a span for the decision round trip if the request is part of a trace
+func startDecisionSpan(
+	ctx context.Context,
+	operationName string,
+	domainID string,
+	execution *types.WorkflowExecution,
+) (opentracing.Span, context.Context) {
+	span, ctx := tracing.StartChildSpan(ctx, operationName)
+	span.SetTag(tracing.TagDomainID, domainID)
+	if execution != nil {
+		span.SetTag(tracing.TagWorkflowID, execution.GetWorkflowID())
+		span.SetTag(tracing.TagRunID, execution.GetRunID())
+	}
+	return span, ctx
+}
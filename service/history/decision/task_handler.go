@@ -72,7 +72,8 @@ type (
 		metricsClient metrics.Client
 		config        *config.Config
 
-		activityCountToDispatch int
+		activityCountToDispatch        int
+		activityCountToDispatchLocally int
 	}
 
 	decisionResult struct {
@@ -120,7 +121,8 @@ func newDecisionTaskHandler(
 		metricsClient: metricsClient,
 		config:        config,
 
-		activityCountToDispatch: config.MaxActivityCountDispatchByDomain(domainEntry.GetInfo().Name),
+		activityCountToDispatch:        config.MaxActivityCountDispatchByDomain(domainEntry.GetInfo().Name),
+		activityCountToDispatchLocally: config.MaxActivityCountLocalDispatchByDomain(domainEntry.GetInfo().Name),
 	}
 }
 
@@ -136,6 +138,12 @@ func (handler *taskHandlerImpl) handleDecisions(
 		return nil, err
 	}
 
+	// overall pending activity / child workflow count check
+	failWorkflow, err = handler.sizeLimitChecker.failWorkflowPendingCountExceedsLimit()
+	if err != nil || failWorkflow {
+		return nil, err
+	}
+
 	var results []*decisionResult
 	for _, decision := range decisions {
 		result, err := handler.handleDecisionWithResult(ctx, decision)
@@ -255,11 +263,20 @@ func (handler *taskHandlerImpl) handleDecisionScheduleActivity(
 		return nil, err
 	}
 
+	// only the first activities requesting local dispatch are returned to the worker,
+	// the rest are scheduled through matching so that they can be picked up by other workers
+	if attr.RequestLocalDispatch && handler.activityCountToDispatchLocally <= 0 {
+		attr.RequestLocalDispatch = false
+	}
+
 	event, ai, activityDispatchInfo, dispatched, started, err := handler.mutableState.AddActivityTaskScheduledEvent(
 		ctx, handler.decisionTaskCompletedID, attr, handler.activityCountToDispatch > 0)
 	if dispatched {
 		handler.activityCountToDispatch--
 	}
+	if activityDispatchInfo != nil {
+		handler.activityCountToDispatchLocally--
+	}
 	switch err.(type) {
 	case nil:
 		if activityDispatchInfo != nil || started {
@@ -857,6 +874,13 @@ func (handler *taskHandlerImpl) handleDecisionStartChildWorkflow(
 	if attr.GetDomain() != "" {
 		targetDomainEntry, err := handler.domainCache.GetDomain(attr.GetDomain())
 		if err != nil {
+			if _, ok := err.(*types.EntityNotExistsError); ok {
+				// retrying the decision won't help, let the worker know about the bad domain name
+				return handler.handlerFailDecision(
+					types.DecisionTaskFailedCauseBadStartChildExecutionAttributes,
+					fmt.Sprintf("Target domain %v of child workflow does not exist.", attr.GetDomain()),
+				)
+			}
 			return &types.InternalServiceError{
 				Message: fmt.Sprintf("Unable to schedule child execution across domain %v.", attr.GetDomain()),
 			}
@@ -81,6 +81,7 @@ var (
 )
 
 var _ Cache = (*cacheImpl)(nil)
+var _ cache.Introspector = (*cacheImpl)(nil)
 
 // NewGlobalCache creates a new global events cache
 func NewGlobalCache(
@@ -275,3 +276,13 @@ func (e *cacheImpl) getHistoryEventFromStore(
 
 	return nil, errEventNotFoundInBatch
 }
+
+// Describe returns the statistics and the most accessed events of the cache
+func (e *cacheImpl) Describe(maxEntries int) *cache.Description {
+	return e.Cache.(cache.Introspector).Describe(maxEntries)
+}
+
+// Invalidate removes the events whose domain, workflow or run matches from the cache
+func (e *cacheImpl) Invalidate(match string) int {
+	return e.Cache.(cache.Introspector).Invalidate(match)
+}
@@ -204,6 +204,17 @@ func (c *Cache) GetOrCreateWorkflowExecution(
 	)
 }
 
+// Describe returns the statistics and the most accessed workflow execution contexts of the cache
+func (c *Cache) Describe(maxEntries int) *cache.Description {
+	return c.Cache.(cache.Introspector).Describe(maxEntries)
+}
+
+// Invalidate removes the workflow execution contexts whose domain, workflow or run matches from the cache,
+// contexts currently in use are kept and can be invalidated again once released
+func (c *Cache) Invalidate(match string) int {
+	return c.Cache.(cache.Introspector).Invalidate(match)
+}
+
 func (c *Cache) getOrCreateWorkflowExecutionInternal(
 	ctx context.Context,
 	domainID string,
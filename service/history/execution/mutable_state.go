@@ -211,6 +211,7 @@ type (
 		ReplicateWorkflowExecutionTimedoutEvent(int64, *types.HistoryEvent) error
 		SetCurrentBranchToken(branchToken []byte) error
 		SetHistoryBuilder(hBuilder *HistoryBuilder)
+		SetHistorySizeWarning() (bool, error)
 		SetHistoryTree(treeID string) error
 		SetVersionHistories(*persistence.VersionHistories) error
 		UpdateActivity(*persistence.ActivityInfo) error
@@ -1102,11 +1102,22 @@ func (e *mutableStateBuilder) GetCronBackoffDuration(
 		e.logError("unable to find workflow start event", tag.ErrorTypeInvalidHistoryAction)
 		return backoff.NoBackoff, err
 	}
-	firstDecisionTaskBackoff :=
-		time.Duration(workflowStartEvent.GetWorkflowExecutionStartedEventAttributes().GetFirstDecisionTaskBackoffSeconds()) * time.Second
+	startAttributes := workflowStartEvent.GetWorkflowExecutionStartedEventAttributes()
+	firstDecisionTaskBackoff := time.Duration(startAttributes.GetFirstDecisionTaskBackoffSeconds()) * time.Second
 	executionTime = executionTime.Add(firstDecisionTaskBackoff)
-	jitterStartSeconds := workflowStartEvent.GetWorkflowExecutionStartedEventAttributes().GetJitterStartSeconds()
-	return backoff.GetBackoffForNextSchedule(sched, executionTime, e.timeSource.Now(), jitterStartSeconds)
+	jitterStartSeconds := startAttributes.GetJitterStartSeconds()
+	var header *types.Header
+	if startAttributes != nil {
+		header = startAttributes.Header
+	}
+	overlapPolicy := backoff.GetCronOverlapPolicy(header)
+	now := e.timeSource.Now()
+	backoffDuration, err := backoff.GetBackoffForNextScheduleWithOverlapPolicy(sched, executionTime, now, jitterStartSeconds, overlapPolicy)
+	if err != nil {
+		return backoff.NoBackoff, err
+	}
+	smoothingWindow := e.config.CronSmoothingWindow(e.GetDomainEntry().GetInfo().Name)
+	return backoffDuration + backoff.GetSmoothingOffset(sched, now.Add(backoffDuration), e.executionInfo.WorkflowID, smoothingWindow), nil
 }
 
 // GetSignalInfo get details about a signal request that is currently in progress.
@@ -1836,7 +1847,7 @@ func (e *mutableStateBuilder) ReplicateWorkflowExecutionStartedEvent(
 		event.GetPrevAutoResetPoints(),
 		event.GetContinuedExecutionRunID(),
 		startEvent.GetTimestamp(),
-		e.domainEntry.GetRetentionDays(e.executionInfo.WorkflowID),
+		e.domainEntry.GetExecutionRetentionDays(e.executionInfo.WorkflowID, event.Memo.GetFields()),
 	)
 
 	if event.Memo != nil {
@@ -2008,6 +2019,27 @@ func (e *mutableStateBuilder) addBinaryCheckSumIfNotExists(
 	return nil
 }
 
+// SetHistorySizeWarning flags the execution in its search attributes as over the history size or count warn limits,
+// it returns false if the execution was already flagged
+func (e *mutableStateBuilder) SetHistorySizeWarning() (bool, error) {
+	exeInfo := e.executionInfo
+	if _, ok := exeInfo.SearchAttributes[definition.HistorySizeWarning]; ok {
+		return false, nil
+	}
+	bytes, err := json.Marshal(true)
+	if err != nil {
+		return false, err
+	}
+	if exeInfo.SearchAttributes == nil {
+		exeInfo.SearchAttributes = make(map[string][]byte)
+	}
+	exeInfo.SearchAttributes[definition.HistorySizeWarning] = bytes
+	if common.IsAdvancedVisibilityWritingEnabled(e.shard.GetConfig().AdvancedVisibilityWritingMode(), e.shard.GetConfig().IsAdvancedVisConfigExist) {
+		return true, e.taskGenerator.GenerateWorkflowSearchAttrTasks()
+	}
+	return true, nil
+}
+
 // TODO: we will release the restriction when reset API allow those pending
 func (e *mutableStateBuilder) CheckResettable() error {
 	if len(e.GetPendingChildExecutionInfos()) > 0 {
@@ -2149,10 +2181,11 @@ func (e *mutableStateBuilder) AddActivityTaskScheduledEvent(
 	}
 
 	pendingActivitiesCount := len(e.pendingActivityInfoIDs)
+	domainName := e.GetDomainEntry().GetInfo().Name
 
-	if pendingActivitiesCount >= e.config.PendingActivitiesCountLimitError() {
+	if pendingActivitiesCount >= e.config.PendingActivitiesCountLimitError(domainName) {
 		e.logger.Error("Pending activity count exceeds error limit",
-			tag.WorkflowDomainName(e.GetDomainEntry().GetInfo().Name),
+			tag.WorkflowDomainName(domainName),
 			tag.WorkflowID(e.executionInfo.WorkflowID),
 			tag.WorkflowRunID(e.executionInfo.RunID),
 			tag.Number(int64(pendingActivitiesCount)))
@@ -2160,9 +2193,9 @@ func (e *mutableStateBuilder) AddActivityTaskScheduledEvent(
 		if e.config.PendingActivityValidationEnabled() {
 			return nil, nil, nil, false, false, ErrTooManyPendingActivities
 		}
-	} else if pendingActivitiesCount >= e.config.PendingActivitiesCountLimitWarn() && !e.pendingActivityWarningSent {
+	} else if pendingActivitiesCount >= e.config.PendingActivitiesCountLimitWarn(domainName) && !e.pendingActivityWarningSent {
 		e.logger.Warn("Pending activity count exceeds warn limit",
-			tag.WorkflowDomainName(e.GetDomainEntry().GetInfo().Name),
+			tag.WorkflowDomainName(domainName),
 			tag.WorkflowID(e.executionInfo.WorkflowID),
 			tag.WorkflowRunID(e.executionInfo.RunID),
 			tag.Number(int64(pendingActivitiesCount)))
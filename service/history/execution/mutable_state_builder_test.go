@@ -107,7 +107,7 @@ func (s *mutableStateSuite) TearDownTest() {
 }
 
 func (s *mutableStateSuite) TestErrorReturnedWhenSchedulingTooManyPendingActivities() {
-	for i := 0; i < s.msBuilder.config.PendingActivitiesCountLimitError(); i++ {
+	for i := 0; i < s.msBuilder.config.PendingActivitiesCountLimitError(constants.TestDomainName); i++ {
 		s.msBuilder.pendingActivityInfoIDs[int64(i)] = &persistence.ActivityInfo{}
 	}
 
@@ -532,6 +532,17 @@ func (s *mutableStateSuite) TestMergeMapOfByteArray() {
 	s.Equal(2, len(resultMap))
 }
 
+func (s *mutableStateSuite) TestSetHistorySizeWarning() {
+	flagged, err := s.msBuilder.SetHistorySizeWarning()
+	s.NoError(err)
+	s.True(flagged)
+	s.Equal([]byte("true"), s.msBuilder.GetExecutionInfo().SearchAttributes[definition.HistorySizeWarning])
+
+	flagged, err = s.msBuilder.SetHistorySizeWarning()
+	s.NoError(err)
+	s.False(flagged)
+}
+
 func (s *mutableStateSuite) TestEventReapplied() {
 	runID := uuid.New()
 	eventID := int64(1)
@@ -2434,6 +2434,21 @@ func (mr *MockMutableStateMockRecorder) SetHistoryBuilder(hBuilder interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHistoryBuilder", reflect.TypeOf((*MockMutableState)(nil).SetHistoryBuilder), hBuilder)
 }
 
+// SetHistorySizeWarning mocks base method.
+func (m *MockMutableState) SetHistorySizeWarning() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHistorySizeWarning")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetHistorySizeWarning indicates an expected call of SetHistorySizeWarning.
+func (mr *MockMutableStateMockRecorder) SetHistorySizeWarning() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHistorySizeWarning", reflect.TypeOf((*MockMutableState)(nil).SetHistorySizeWarning))
+}
+
 // SetHistoryTree mocks base method.
 func (m *MockMutableState) SetHistoryTree(treeID string) error {
 	m.ctrl.T.Helper()
@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/persistence"
@@ -159,6 +160,25 @@ func (r *mutableStateTaskGeneratorImpl) GenerateWorkflowStartTasks(
 		Version:             startVersion,
 	})
 
+	// with the cancel-running overlap policy, a cron workflow still running
+	// at the next fire of its schedule is closed and a new run is started
+	if len(executionInfo.CronSchedule) != 0 &&
+		backoff.GetCronOverlapPolicy(attr.Header) == backoff.CronOverlapPolicyCancelRunning {
+		sched, err := backoff.ValidateSchedule(executionInfo.CronSchedule)
+		if err != nil {
+			return err
+		}
+		nextScheduleTimestamp := sched.Next(startTime.Add(firstDecisionDelayDuration).In(time.UTC))
+		if !nextScheduleTimestamp.IsZero() && nextScheduleTimestamp.Before(workflowTimeoutTimestamp) {
+			r.mutableState.AddTimerTasks(&persistence.WorkflowBackoffTimerTask{
+				// TaskID is set by shard
+				VisibilityTimestamp: nextScheduleTimestamp,
+				TimeoutType:         persistence.WorkflowBackoffTimeoutTypeCronOverlap,
+				Version:             startVersion,
+			})
+		}
+	}
+
 	return nil
 }
 
@@ -237,7 +257,7 @@ func (r *mutableStateTaskGeneratorImpl) GenerateWorkflowCloseTasks(
 	domainEntry, err := r.domainCache.GetDomainByID(executionInfo.DomainID)
 	switch err.(type) {
 	case nil:
-		retentionInDays = domainEntry.GetRetentionDays(executionInfo.WorkflowID)
+		retentionInDays = domainEntry.GetExecutionRetentionDays(executionInfo.WorkflowID, executionInfo.Memo)
 	case *types.EntityNotExistsError:
 		// domain is not accessible, use default value above
 	default:
@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/persistence"
@@ -127,6 +128,116 @@ func (s *mutableStateTaskGeneratorSuite) TestIsCrossClusterTask() {
 	}
 }
 
+func (s *mutableStateTaskGeneratorSuite) TestGenerateWorkflowStartTasks_CronOverlap() {
+	startTime := time.Date(2018, 12, 17, 8, 0, 0, 0, time.UTC)
+	version := int64(123)
+	newStartEvent := func(policy string) *types.HistoryEvent {
+		return &types.HistoryEvent{
+			EventType: types.EventTypeWorkflowExecutionStarted.Ptr(),
+			Timestamp: common.Int64Ptr(startTime.UnixNano()),
+			Version:   version,
+			WorkflowExecutionStartedEventAttributes: &types.WorkflowExecutionStartedEventAttributes{
+				Header: &types.Header{Fields: map[string][]byte{backoff.CronOverlapPolicyHeader: []byte(policy)}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		msg             string
+		cronSchedule    string
+		workflowTimeout int32
+		policy          string
+		expectedTimers  []persistence.Task
+	}{
+		{
+			msg:             "skip",
+			cronSchedule:    "0 * * * *",
+			workflowTimeout: 86400,
+			policy:          string(backoff.CronOverlapPolicySkip),
+			expectedTimers: []persistence.Task{
+				&persistence.WorkflowTimeoutTask{VisibilityTimestamp: startTime.Add(24 * time.Hour), Version: version},
+			},
+		},
+		{
+			msg:             "cancel running",
+			cronSchedule:    "0 * * * *",
+			workflowTimeout: 86400,
+			policy:          string(backoff.CronOverlapPolicyCancelRunning),
+			expectedTimers: []persistence.Task{
+				&persistence.WorkflowTimeoutTask{VisibilityTimestamp: startTime.Add(24 * time.Hour), Version: version},
+				&persistence.WorkflowBackoffTimerTask{
+					VisibilityTimestamp: startTime.Add(time.Hour),
+					TimeoutType:         persistence.WorkflowBackoffTimeoutTypeCronOverlap,
+					Version:             version,
+				},
+			},
+		},
+		{
+			msg:             "cancel running, times out before next fire",
+			cronSchedule:    "0 * * * *",
+			workflowTimeout: 600,
+			policy:          string(backoff.CronOverlapPolicyCancelRunning),
+			expectedTimers: []persistence.Task{
+				&persistence.WorkflowTimeoutTask{VisibilityTimestamp: startTime.Add(10 * time.Minute), Version: version},
+			},
+		},
+		{
+			msg:             "cancel running, not a cron workflow",
+			workflowTimeout: 86400,
+			policy:          string(backoff.CronOverlapPolicyCancelRunning),
+			expectedTimers: []persistence.Task{
+				&persistence.WorkflowTimeoutTask{VisibilityTimestamp: startTime.Add(24 * time.Hour), Version: version},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.msg, func() {
+			s.mockMutableState.EXPECT().GetExecutionInfo().Return(&persistence.WorkflowExecutionInfo{
+				DomainID:        constants.TestDomainID,
+				CronSchedule:    tc.cronSchedule,
+				WorkflowTimeout: tc.workflowTimeout,
+			}).Times(1)
+			var timers []persistence.Task
+			s.mockMutableState.EXPECT().AddTimerTasks(gomock.Any()).Do(func(tasks ...persistence.Task) {
+				timers = append(timers, tasks...)
+			}).Times(len(tc.expectedTimers))
+
+			err := s.taskGenerator.GenerateWorkflowStartTasks(startTime, newStartEvent(tc.policy))
+			s.NoError(err)
+			s.Equal(tc.expectedTimers, timers)
+		})
+	}
+}
+
+func (s *mutableStateTaskGeneratorSuite) TestGenerateWorkflowCloseTasks_ExecutionRetention() {
+	now := time.Now()
+	version := int64(123)
+	closeEvent := &types.HistoryEvent{
+		EventType: types.EventTypeWorkflowExecutionCompleted.Ptr(),
+		Timestamp: common.Int64Ptr(now.UnixNano()),
+		Version:   version,
+	}
+
+	s.mockMutableState.EXPECT().GetExecutionInfo().Return(&persistence.WorkflowExecutionInfo{
+		DomainID:   constants.TestDomainID,
+		WorkflowID: constants.TestWorkflowID,
+		RunID:      constants.TestRunID,
+		Memo:       map[string][]byte{cache.ExecutionRetentionKey: []byte("90")},
+	}).AnyTimes()
+	s.mockMutableState.EXPECT().HasParentExecution().Return(false).AnyTimes()
+	s.mockMutableState.EXPECT().GetPendingChildExecutionInfos().Return(nil).AnyTimes()
+	s.mockMutableState.EXPECT().AddTransferTasks(gomock.Any()).Times(1)
+	s.mockMutableState.EXPECT().AddCrossClusterTasks(gomock.Any()).Times(1)
+	s.mockMutableState.EXPECT().AddTimerTasks(&persistence.DeleteHistoryEventTask{
+		VisibilityTimestamp: time.Unix(0, closeEvent.GetTimestamp()).Add(90 * 24 * time.Hour),
+		Version:             version,
+	}).Times(1)
+
+	err := s.taskGenerator.GenerateWorkflowCloseTasks(closeEvent, 0)
+	s.NoError(err)
+}
+
 func (s *mutableStateTaskGeneratorSuite) TestGenerateWorkflowCloseTasks_JitteredDeletion() {
 	now := time.Now()
 	version := int64(123)
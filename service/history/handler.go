@@ -40,6 +40,7 @@ import (
 	"go.uber.org/yarpc/yarpcerrors"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/definition"
 	"github.com/uber/cadence/common/future"
 	"github.com/uber/cadence/common/log"
@@ -49,6 +50,7 @@ import (
 	"github.com/uber/cadence/common/quotas"
 	"github.com/uber/cadence/common/service"
 	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/watchdog"
 	"github.com/uber/cadence/service/history/config"
 	"github.com/uber/cadence/service/history/engine"
 	"github.com/uber/cadence/service/history/events"
@@ -57,9 +59,15 @@ import (
 	"github.com/uber/cadence/service/history/resource"
 	"github.com/uber/cadence/service/history/shard"
 	"github.com/uber/cadence/service/history/task"
+	"github.com/uber/cadence/service/history/workflowcache"
 )
 
-const shardOwnershipTransferDelay = 5 * time.Second
+const (
+	shardOwnershipTransferDelay = 5 * time.Second
+
+	workflowIDCacheTTL      = time.Minute
+	workflowIDCacheMaxCount = 10000
+)
 
 type (
 	// Handler interface for history service
@@ -70,6 +78,10 @@ type (
 		Health(context.Context) (*types.HealthStatus, error)
 		CloseShard(context.Context, *types.CloseShardRequest) error
 		DescribeHistoryHost(context.Context, *types.DescribeHistoryHostRequest) (*types.DescribeHistoryHostResponse, error)
+		InvalidateCaches(context.Context, *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error)
+		DescribeShardOwnership(context.Context, *types.DescribeShardOwnershipRequest) (*types.DescribeShardOwnershipResponse, error)
+		DescribePersistenceLatencyHeatmap(context.Context, *types.DescribePersistenceLatencyHeatmapRequest) (*types.DescribePersistenceLatencyHeatmapResponse, error)
+		DescribeWatchdog(context.Context, *types.DescribeWatchdogRequest) (*types.DescribeWatchdogResponse, error)
 		DescribeMutableState(context.Context, *types.DescribeMutableStateRequest) (*types.DescribeMutableStateResponse, error)
 		DescribeQueue(context.Context, *types.DescribeQueueRequest) (*types.DescribeQueueResponse, error)
 		DescribeWorkflowExecution(context.Context, *types.HistoryDescribeWorkflowExecutionRequest) (*types.DescribeWorkflowExecutionResponse, error)
@@ -123,7 +135,8 @@ type (
 		startWG                  sync.WaitGroup
 		config                   *config.Config
 		historyEventNotifier     events.Notifier
-		rateLimiter              quotas.Limiter
+		rateLimiter              quotas.Policy
+		workflowIDCache          workflowcache.WFCache
 		crossClusterTaskFetchers task.Fetchers
 		replicationTaskFetchers  replication.TaskFetchers
 		queueTaskProcessor       task.Processor
@@ -144,6 +157,7 @@ var (
 	errTimestampNotSet         = &types.BadRequestError{Message: "Timestamp not set on request."}
 	errInvalidTaskType         = &types.BadRequestError{Message: "Invalid task type"}
 	errHistoryHostThrottle     = &types.ServiceBusyError{Message: "History host rps exceeded"}
+	errWorkflowIDThrottle      = &types.ServiceBusyError{Message: "Workflow ID rps exceeded"}
 	errShuttingDown            = &types.InternalServiceError{Message: "Shutting down"}
 )
 
@@ -156,7 +170,26 @@ func NewHandler(
 		Resource:        resource,
 		config:          config,
 		tokenSerializer: common.NewJSONTaskTokenSerializer(),
-		rateLimiter:     quotas.NewDynamicRateLimiter(config.RPS.AsFloat64()),
+		rateLimiter: quotas.NewPooledPolicy(
+			cache.ResourcePoolByDomainID(resource.GetDomainCache(), config.DomainResourcePool),
+			quotas.NewLimiterPolicy(quotas.NewDynamicRateLimiter(config.RPS.AsFloat64())),
+			func(pool string) quotas.Policy {
+				return quotas.NewLimiterPolicy(quotas.NewDynamicRateLimiter(func() float64 {
+					if rps := common.GetIntFromDynamicConfigMapProperty(config.ResourcePoolRPS(), pool); rps > 0 {
+						return float64(rps)
+					}
+					return float64(config.RPS())
+				}))
+			},
+		),
+		workflowIDCache: workflowcache.New(workflowcache.Params{
+			TTL:         workflowIDCacheTTL,
+			MaxCount:    workflowIDCacheMaxCount,
+			DomainCache: resource.GetDomainCache(),
+			SignalRPS:   config.WorkflowIDSignalRPS,
+			EventsRPS:   config.WorkflowIDEventsRPS,
+			Logger:      resource.GetLogger(),
+		}),
 	}
 
 	// prevent us from trying to serve requests before shard controller is started and ready
@@ -204,6 +237,8 @@ func (h *handlerImpl) Start() {
 		h.config,
 		h.GetLogger(),
 		h.GetMetricsClient(),
+		h.GetLoadMonitor(),
+		h.GetDomainCache(),
 	)
 	if err != nil {
 		h.GetLogger().Fatal("Creating priority task processor failed", tag.Error(err))
@@ -233,10 +268,23 @@ func (h *handlerImpl) Start() {
 	}
 
 	h.controller.Start()
+	h.registerWatchdogProbes()
 
 	h.startWG.Done()
 }
 
+// registerWatchdogProbes tracks the shard contexts and the events cache, the shards owned by the host
+// are the load of its goroutines as every shard runs its own queue processors
+func (h *handlerImpl) registerWatchdogProbes() {
+	h.GetWatchdog().Register("shard_contexts", watchdog.Probe{Value: h.controller.NumShards})
+	if eventCache, ok := h.GetEventCache().(interface{ Size() int }); ok {
+		h.GetWatchdog().Register("events_cache", watchdog.Probe{Value: eventCache.Size})
+	}
+	goroutines := watchdog.NewGoroutineProbe()
+	goroutines.Load = h.controller.NumShards
+	h.GetWatchdog().Register("goroutines", goroutines)
+}
+
 // Stop stops the handler
 func (h *handlerImpl) Stop() {
 	h.prepareToShutDown()
@@ -252,6 +300,14 @@ func (h *handlerImpl) Stop() {
 func (h *handlerImpl) PrepareToStop(remainingTime time.Duration) time.Duration {
 	h.GetLogger().Info("ShutdownHandler: Initiating shardController shutdown")
 	h.controller.PrepareToStop()
+	if h.config.EnableShardHandoff() {
+		h.GetLogger().Info("ShutdownHandler: Handing shards off to their new owners")
+		startTime := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), remainingTime)
+		h.controller.HandoffShards(ctx)
+		cancel()
+		remainingTime = common.MaxDuration(remainingTime-time.Since(startTime), 0)
+	}
 	h.GetLogger().Info("ShutdownHandler: Waiting for traffic to drain")
 	remainingTime = common.SleepWithMinDuration(shardOwnershipTransferDelay, remainingTime)
 	h.GetLogger().Info("ShutdownHandler: No longer taking rpc requests")
@@ -311,7 +367,7 @@ func (h *handlerImpl) RecordActivityTaskHeartbeat(
 		return nil, h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -370,7 +426,7 @@ func (h *handlerImpl) RecordActivityTaskStarted(
 		return nil, h.error(errDomainNotSet, scope, domainID, workflowID)
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, workflowID)
 	}
 
@@ -416,7 +472,7 @@ func (h *handlerImpl) RecordDecisionTaskStarted(
 		return nil, h.error(errDomainNotSet, scope, domainID, workflowID)
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, workflowID)
 	}
 
@@ -460,7 +516,7 @@ func (h *handlerImpl) RespondActivityTaskCompleted(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -507,7 +563,7 @@ func (h *handlerImpl) RespondActivityTaskFailed(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -554,7 +610,7 @@ func (h *handlerImpl) RespondActivityTaskCanceled(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -601,7 +657,7 @@ func (h *handlerImpl) RespondDecisionTaskCompleted(
 		return nil, h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -627,6 +683,10 @@ func (h *handlerImpl) RespondDecisionTaskCompleted(
 	}
 	workflowID := token.WorkflowID
 
+	if ok := h.workflowIDCache.AllowEvents(domainID, workflowID, len(completeRequest.Decisions)); !ok {
+		return nil, h.error(errWorkflowIDThrottle, scope, domainID, workflowID)
+	}
+
 	engine, err1 := h.controller.GetEngine(workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
@@ -657,7 +717,7 @@ func (h *handlerImpl) RespondDecisionTaskFailed(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -723,7 +783,7 @@ func (h *handlerImpl) StartWorkflowExecution(
 		return nil, h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -775,6 +835,69 @@ func (h *handlerImpl) DescribeHistoryHost(
 	return resp, nil
 }
 
+// InvalidateCaches removes the matching entries from the in-memory caches of the host, so that they are reloaded
+func (h *handlerImpl) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+) (resp *types.InvalidateCachesResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), h.GetLogger(), &retError) }()
+	h.startWG.Wait()
+
+	return cache.InvalidateCaches(h.GetCacheRegistry(), request)
+}
+
+// DescribeShardOwnership describes the shard to host mapping and the shard acquisitions and steals seen by the host
+func (h *handlerImpl) DescribeShardOwnership(
+	ctx context.Context,
+	request *types.DescribeShardOwnershipRequest,
+) (resp *types.DescribeShardOwnershipResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), h.GetLogger(), &retError) }()
+	h.startWG.Wait()
+
+	return h.controller.DescribeOwnership(), nil
+}
+
+// DescribePersistenceLatencyHeatmap returns the shards and persistence operations of the host with the highest
+// latency over the requested window
+func (h *handlerImpl) DescribePersistenceLatencyHeatmap(
+	ctx context.Context,
+	request *types.DescribePersistenceLatencyHeatmapRequest,
+) (resp *types.DescribePersistenceLatencyHeatmapResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), h.GetLogger(), &retError) }()
+	h.startWG.Wait()
+
+	snapshot := h.GetPersistenceLatencyHeatmap().Snapshot(time.Duration(request.GetWindowInSeconds()) * time.Second)
+	resp = &types.DescribePersistenceLatencyHeatmapResponse{
+		StartTime: snapshot.Start.UnixNano(),
+		EndTime:   snapshot.End.UnixNano(),
+		Cells:     make([]*types.PersistenceLatencyCell, 0, len(snapshot.Cells)),
+	}
+	for _, cell := range snapshot.Cells {
+		resp.Cells = append(resp.Cells, &types.PersistenceLatencyCell{
+			ShardID:           int32(cell.ShardID),
+			Operation:         cell.Operation,
+			Count:             cell.Count,
+			P99InMilliseconds: cell.P99.Milliseconds(),
+		})
+	}
+	return resp, nil
+}
+
+// DescribeWatchdog describes the resources tracked by the watchdog of the host
+func (h *handlerImpl) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+) (resp *types.DescribeWatchdogResponse, retError error) {
+
+	defer func() { log.CapturePanic(recover(), h.GetLogger(), &retError) }()
+	h.startWG.Wait()
+
+	return watchdog.Describe(h.GetWatchdog()), nil
+}
+
 // RemoveTask returns information about the internal states of a history host
 func (h *handlerImpl) RemoveTask(
 	ctx context.Context,
@@ -934,7 +1057,7 @@ func (h *handlerImpl) GetMutableState(
 		return nil, h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -969,7 +1092,7 @@ func (h *handlerImpl) PollMutableState(
 		return nil, h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1004,7 +1127,7 @@ func (h *handlerImpl) DescribeWorkflowExecution(
 		return nil, h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1043,7 +1166,7 @@ func (h *handlerImpl) RequestCancelWorkflowExecution(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1090,12 +1213,16 @@ func (h *handlerImpl) SignalWorkflowExecution(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
 	workflowExecution := wrappedRequest.SignalRequest.WorkflowExecution
 	workflowID := workflowExecution.GetWorkflowID()
+	if ok := h.workflowIDCache.AllowSignal(domainID, workflowID); !ok {
+		return h.error(errWorkflowIDThrottle, scope, domainID, workflowID)
+	}
+
 	engine, err1 := h.controller.GetEngine(workflowID)
 	if err1 != nil {
 		return h.error(err1, scope, domainID, workflowID)
@@ -1134,12 +1261,16 @@ func (h *handlerImpl) SignalWithStartWorkflowExecution(
 		return nil, h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
 	signalWithStartRequest := wrappedRequest.SignalWithStartRequest
 	workflowID := signalWithStartRequest.GetWorkflowID()
+	if ok := h.workflowIDCache.AllowSignal(domainID, workflowID); !ok {
+		return nil, h.error(errWorkflowIDThrottle, scope, domainID, workflowID)
+	}
+
 	engine, err1 := h.controller.GetEngine(workflowID)
 	if err1 != nil {
 		return nil, h.error(err1, scope, domainID, workflowID)
@@ -1191,7 +1322,7 @@ func (h *handlerImpl) RemoveSignalMutableState(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1232,7 +1363,7 @@ func (h *handlerImpl) TerminateWorkflowExecution(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1273,7 +1404,7 @@ func (h *handlerImpl) ResetWorkflowExecution(
 		return nil, h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1312,7 +1443,7 @@ func (h *handlerImpl) QueryWorkflow(
 		return nil, h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1354,7 +1485,7 @@ func (h *handlerImpl) ScheduleDecisionTask(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1399,7 +1530,7 @@ func (h *handlerImpl) RecordChildExecutionCompleted(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1449,7 +1580,7 @@ func (h *handlerImpl) ResetStickyTaskList(
 		return nil, h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return nil, h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1488,7 +1619,7 @@ func (h *handlerImpl) ReplicateEventsV2(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
@@ -1523,7 +1654,7 @@ func (h *handlerImpl) SyncShardStatus(
 		return errShuttingDown
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{}); !ok {
 		return h.error(errHistoryHostThrottle, scope, "", "")
 	}
 
@@ -1570,7 +1701,7 @@ func (h *handlerImpl) SyncActivity(
 		return h.error(errDomainNotSet, scope, domainID, "")
 	}
 
-	if ok := h.rateLimiter.Allow(); !ok {
+	if ok := h.rateLimiter.Allow(quotas.Info{Domain: domainID}); !ok {
 		return h.error(errHistoryHostThrottle, scope, domainID, "")
 	}
 
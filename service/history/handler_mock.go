@@ -118,6 +118,21 @@ func (mr *MockHandlerMockRecorder) DescribeMutableState(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMutableState", reflect.TypeOf((*MockHandler)(nil).DescribeMutableState), arg0, arg1)
 }
 
+// DescribePersistenceLatencyHeatmap mocks base method.
+func (m *MockHandler) DescribePersistenceLatencyHeatmap(arg0 context.Context, arg1 *types.DescribePersistenceLatencyHeatmapRequest) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribePersistenceLatencyHeatmap", arg0, arg1)
+	ret0, _ := ret[0].(*types.DescribePersistenceLatencyHeatmapResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribePersistenceLatencyHeatmap indicates an expected call of DescribePersistenceLatencyHeatmap.
+func (mr *MockHandlerMockRecorder) DescribePersistenceLatencyHeatmap(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribePersistenceLatencyHeatmap", reflect.TypeOf((*MockHandler)(nil).DescribePersistenceLatencyHeatmap), arg0, arg1)
+}
+
 // DescribeQueue mocks base method.
 func (m *MockHandler) DescribeQueue(arg0 context.Context, arg1 *types.DescribeQueueRequest) (*types.DescribeQueueResponse, error) {
 	m.ctrl.T.Helper()
@@ -133,6 +148,36 @@ func (mr *MockHandlerMockRecorder) DescribeQueue(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeQueue", reflect.TypeOf((*MockHandler)(nil).DescribeQueue), arg0, arg1)
 }
 
+// DescribeShardOwnership mocks base method.
+func (m *MockHandler) DescribeShardOwnership(arg0 context.Context, arg1 *types.DescribeShardOwnershipRequest) (*types.DescribeShardOwnershipResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeShardOwnership", arg0, arg1)
+	ret0, _ := ret[0].(*types.DescribeShardOwnershipResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeShardOwnership indicates an expected call of DescribeShardOwnership.
+func (mr *MockHandlerMockRecorder) DescribeShardOwnership(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeShardOwnership", reflect.TypeOf((*MockHandler)(nil).DescribeShardOwnership), arg0, arg1)
+}
+
+// DescribeWatchdog mocks base method.
+func (m *MockHandler) DescribeWatchdog(arg0 context.Context, arg1 *types.DescribeWatchdogRequest) (*types.DescribeWatchdogResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeWatchdog", arg0, arg1)
+	ret0, _ := ret[0].(*types.DescribeWatchdogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeWatchdog indicates an expected call of DescribeWatchdog.
+func (mr *MockHandlerMockRecorder) DescribeWatchdog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWatchdog", reflect.TypeOf((*MockHandler)(nil).DescribeWatchdog), arg0, arg1)
+}
+
 // DescribeWorkflowExecution mocks base method.
 func (m *MockHandler) DescribeWorkflowExecution(arg0 context.Context, arg1 *types.HistoryDescribeWorkflowExecutionRequest) (*types.DescribeWorkflowExecutionResponse, error) {
 	m.ctrl.T.Helper()
@@ -238,6 +283,21 @@ func (mr *MockHandlerMockRecorder) Health(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockHandler)(nil).Health), arg0)
 }
 
+// InvalidateCaches mocks base method.
+func (m *MockHandler) InvalidateCaches(arg0 context.Context, arg1 *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateCaches", arg0, arg1)
+	ret0, _ := ret[0].(*types.InvalidateCachesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvalidateCaches indicates an expected call of InvalidateCaches.
+func (mr *MockHandlerMockRecorder) InvalidateCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCaches", reflect.TypeOf((*MockHandler)(nil).InvalidateCaches), arg0, arg1)
+}
+
 // MergeDLQMessages mocks base method.
 func (m *MockHandler) MergeDLQMessages(arg0 context.Context, arg1 *types.MergeDLQMessagesRequest) (*types.MergeDLQMessagesResponse, error) {
 	m.ctrl.T.Helper()
@@ -26,12 +26,14 @@ import (
 	"math/rand"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/uber/cadence/common/cluster"
+	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log/loggerimpl"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/types"
@@ -39,6 +41,7 @@ import (
 	"github.com/uber/cadence/service/history/engine"
 	"github.com/uber/cadence/service/history/resource"
 	"github.com/uber/cadence/service/history/shard"
+	"github.com/uber/cadence/service/history/workflowcache"
 )
 
 type (
@@ -114,6 +117,33 @@ func (s *handlerSuite) TestGetCrossClusterTasks() {
 	}
 }
 
+func (s *handlerSuite) TestSignalWorkflowExecution_WorkflowIDThrottled() {
+	domainID := "test-domain-id"
+	workflowID := "test-workflow-id"
+	s.mockResource.DomainCache.EXPECT().GetDomainName(domainID).Return("test-domain", nil).AnyTimes()
+	s.handler.workflowIDCache = workflowcache.New(workflowcache.Params{
+		TTL:         time.Minute,
+		MaxCount:    10,
+		DomainCache: s.mockResource.DomainCache,
+		SignalRPS:   dynamicconfig.GetIntPropertyFilteredByDomain(1),
+		EventsRPS:   dynamicconfig.GetIntPropertyFilteredByDomain(0),
+		Logger:      s.mockResource.Logger,
+	})
+
+	request := &types.HistorySignalWorkflowExecutionRequest{
+		DomainUUID: domainID,
+		SignalRequest: &types.SignalWorkflowExecutionRequest{
+			WorkflowExecution: &types.WorkflowExecution{WorkflowID: workflowID},
+		},
+	}
+	s.mockShardController.EXPECT().GetEngine(workflowID).Return(s.mockEngine, nil).Times(1)
+	s.mockEngine.EXPECT().SignalWorkflowExecution(gomock.Any(), request).Return(nil).Times(1)
+
+	s.NoError(s.handler.SignalWorkflowExecution(context.Background(), request))
+	err := s.handler.SignalWorkflowExecution(context.Background(), request)
+	s.IsType(&types.ServiceBusyError{}, err)
+}
+
 func (s *handlerSuite) TestRespondCrossClusterTaskCompleted_FetchNewTask() {
 	s.testRespondCrossClusterTaskCompleted(true)
 }
@@ -27,6 +27,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/pborman/uuid"
@@ -37,6 +38,7 @@ import (
 	hc "github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/client"
 	"github.com/uber/cadence/common/clock"
@@ -124,6 +126,7 @@ type (
 		clientChecker              client.VersionChecker
 		replicationDLQHandler      replication.DLQHandler
 		failoverMarkerNotifier     failover.MarkerNotifier
+		queryResenders             map[string]cndc.HistoryResender
 	}
 )
 
@@ -180,7 +183,7 @@ func NewEngineWithShardContext(
 		historyV2Mgr:         historyV2Manager,
 		executionManager:     executionManager,
 		visibilityMgr:        visibilityMgr,
-		tokenSerializer:      common.NewJSONTaskTokenSerializer(),
+		tokenSerializer:      shard.GetService().GetTaskTokenSerializer(),
 		executionCache:       executionCache,
 		logger:               logger.WithTags(tag.ComponentHistoryEngine),
 		throttledLogger:      shard.GetThrottledLogger().WithTags(tag.ComponentHistoryEngine),
@@ -220,6 +223,7 @@ func NewEngineWithShardContext(
 		clientChecker:          client.NewVersionChecker(),
 		failoverMarkerNotifier: failoverMarkerNotifier,
 		replicationHydrator:    replicationHydrator,
+		queryResenders:         make(map[string]cndc.HistoryResender),
 		replicationAckManager: replication.NewTaskAckManager(
 			shard.GetShardID(),
 			shard,
@@ -336,6 +340,15 @@ func NewEngineWithShardContext(
 			shard.GetLogger(),
 		)
 		replicationTaskExecutors[sourceCluster] = replicationTaskExecutor
+		// queries use the raw client, they should fail fast rather than retry when the source cluster is unavailable
+		historyEngImpl.queryResenders[sourceCluster] = cndc.NewHistoryResender(
+			shard.GetDomainCache(),
+			adminClient,
+			resendFunc,
+			config.ConsistentQueryCatchUpTimeout,
+			nil,
+			shard.GetLogger(),
+		)
 
 		replicationTaskProcessor := replication.NewTaskProcessor(
 			shard,
@@ -384,6 +397,7 @@ func (e *historyEngineImpl) Start() {
 		e.failoverMarkerNotifier.Start()
 	}
 
+	e.registerCaches()
 }
 
 // Stop the service.
@@ -408,11 +422,32 @@ func (e *historyEngineImpl) Stop() {
 	}
 
 	e.failoverMarkerNotifier.Stop()
+	e.deregisterCaches()
 
 	// unset the failover callback
 	e.shard.GetDomainCache().UnregisterDomainChangeCallback(e.shard.GetShardID())
 }
 
+// registerCaches makes the caches of the shard introspectable, the events cache is only owned
+// by the shard when the global events cache is disabled
+func (e *historyEngineImpl) registerCaches() {
+	registry := e.shard.GetService().GetCacheRegistry()
+	registry.Add(e.cacheName("execution"), e.executionCache)
+	if introspector, ok := e.shard.GetEventsCache().(cache.Introspector); ok && !e.config.EventsCacheGlobalEnable() {
+		registry.Add(e.cacheName("events"), introspector)
+	}
+}
+
+func (e *historyEngineImpl) deregisterCaches() {
+	registry := e.shard.GetService().GetCacheRegistry()
+	registry.Remove(e.cacheName("execution"))
+	registry.Remove(e.cacheName("events"))
+}
+
+func (e *historyEngineImpl) cacheName(cacheType string) string {
+	return fmt.Sprintf("history/%v/shard-%v", cacheType, e.shard.GetShardID())
+}
+
 func (e *historyEngineImpl) registerDomainFailoverCallback() {
 
 	// NOTE: READ BEFORE MODIFICATION
@@ -623,6 +658,7 @@ func (e *historyEngineImpl) startWorkflowHelper(
 		return nil, err
 	}
 	e.overrideStartWorkflowExecutionRequest(domainEntry, request, metricsScope)
+	e.smoothCronWorkflowStart(domainEntry, startRequest)
 
 	workflowID := request.GetWorkflowID()
 	domainID := domainEntry.GetInfo().ID
@@ -1169,6 +1205,12 @@ func (e *historyEngineImpl) QueryWorkflow(
 	if err != nil {
 		return nil, err
 	}
+	if request.GetRequest().GetQueryConsistencyLevel() == types.QueryConsistencyLevelStrong {
+		mutableStateResp, err = e.catchUpReplicationForQuery(ctx, request.GetDomainUUID(), mutableStateResp, scope)
+		if err != nil {
+			return nil, err
+		}
+	}
 	req := request.GetRequest()
 	if !mutableStateResp.GetIsWorkflowRunning() && req.QueryRejectCondition != nil {
 		notOpenReject := req.GetQueryRejectCondition() == types.QueryRejectConditionNotOpen
@@ -1310,6 +1352,76 @@ func (e *historyEngineImpl) QueryWorkflow(
 	}
 }
 
+// catchUpReplicationForQuery makes sure that a strongly consistent query does not miss the events which were written
+// in the previously active cluster right before a failover, but are not replicated yet. The events are fetched from
+// the cluster which last updated the workflow, unless the workflow was updated in the current cluster since the
+// failover, in which case it already has all the events of the previously active cluster.
+func (e *historyEngineImpl) catchUpReplicationForQuery(
+	ctx context.Context,
+	domainID string,
+	mutableStateResp *types.GetMutableStateResponse,
+	scope metrics.Scope,
+) (*types.GetMutableStateResponse, error) {
+
+	domainEntry, err := e.shard.GetDomainCache().GetDomainByID(domainID)
+	if err != nil {
+		return nil, err
+	}
+	if !domainEntry.IsGlobalDomain() ||
+		!e.config.EnableConsistentQueryCatchUp(domainEntry.GetInfo().Name) ||
+		mutableStateResp.VersionHistories == nil {
+		return mutableStateResp, nil
+	}
+	if isActive, _ := domainEntry.IsActiveIn(e.currentClusterName); !isActive {
+		// history is only updated through replication in a passive cluster
+		return mutableStateResp, nil
+	}
+
+	currentVersionHistory, err := persistence.NewVersionHistoriesFromInternalType(
+		mutableStateResp.VersionHistories,
+	).GetCurrentVersionHistory()
+	if err != nil {
+		return nil, err
+	}
+	lastItem, err := currentVersionHistory.GetLastItem()
+	if err != nil {
+		return nil, err
+	}
+	if lastItem.Version >= domainEntry.GetFailoverVersion() {
+		return mutableStateResp, nil
+	}
+
+	sourceCluster := e.clusterMetadata.ClusterNameForFailoverVersion(lastItem.Version)
+	resender, ok := e.queryResenders[sourceCluster]
+	if !ok {
+		return mutableStateResp, nil
+	}
+
+	scope.IncCounter(metrics.QueryReplicationCatchUpCount)
+	execution := mutableStateResp.Execution
+	if err := resender.SendSingleWorkflowHistory(
+		domainID,
+		execution.GetWorkflowID(),
+		execution.GetRunID(),
+		common.Int64Ptr(lastItem.EventID),
+		common.Int64Ptr(lastItem.Version),
+		nil,
+		nil,
+	); err != nil {
+		e.logger.Warn("Failed to catch up with the previously active cluster for strongly consistent query",
+			tag.WorkflowDomainID(domainID),
+			tag.WorkflowID(execution.GetWorkflowID()),
+			tag.WorkflowRunID(execution.GetRunID()),
+			tag.SourceCluster(sourceCluster),
+			tag.Error(err),
+		)
+		return nil, &types.InternalServiceError{
+			Message: fmt.Sprintf("cannot verify that the workflow is up to date with cluster %v: %v", sourceCluster, err),
+		}
+	}
+	return e.getMutableState(ctx, domainID, *execution)
+}
+
 func (e *historyEngineImpl) queryDirectlyThroughMatching(
 	ctx context.Context,
 	msResp *types.GetMutableStateResponse,
@@ -2966,7 +3078,11 @@ func (e *historyEngineImpl) ResetWorkflowExecution(
 		),
 		request.GetReason(),
 		nil,
-		request.GetSkipSignalReapply(),
+		reset.ReapplyOptions{
+			SkipSignals:        request.GetSkipSignalReapply(),
+			SignalNames:        request.GetReapplySignalNames(),
+			ExcludeSignalNames: request.GetSkipReapplySignalNames(),
+		},
 	); err != nil {
 		return nil, err
 	}
@@ -3249,6 +3365,39 @@ func (e *historyEngineImpl) overrideStartWorkflowExecutionRequest(
 	}
 }
 
+// smoothCronWorkflowStart delays the first run of a cron workflow by its offset within
+// the smoothing window of the domain, the following runs are delayed when continuing as new
+func (e *historyEngineImpl) smoothCronWorkflowStart(
+	domainEntry *cache.DomainCacheEntry,
+	startRequest *types.HistoryStartWorkflowExecutionRequest,
+) {
+
+	request := startRequest.StartRequest
+	if request.GetCronSchedule() == "" {
+		return
+	}
+	smoothingWindow := e.config.CronSmoothingWindow(domainEntry.GetInfo().Name)
+	if smoothingWindow <= 0 {
+		return
+	}
+	sched, err := backoff.ValidateSchedule(request.GetCronSchedule())
+	if err != nil {
+		// the schedule was validated when computing the first decision task backoff
+		return
+	}
+
+	firstDecisionTaskBackoff := time.Duration(startRequest.GetFirstDecisionTaskBackoffSeconds()) * time.Second
+	fireTime := e.shard.GetTimeSource().Now().Add(firstDecisionTaskBackoff)
+	offset := backoff.GetSmoothingOffset(sched, fireTime, request.GetWorkflowID(), smoothingWindow)
+	if offset == 0 {
+		return
+	}
+	startRequest.FirstDecisionTaskBackoffSeconds = common.Int32Ptr(int32((firstDecisionTaskBackoff + offset) / time.Second))
+	if startRequest.ExpirationTimestamp != nil {
+		startRequest.ExpirationTimestamp = common.Int64Ptr(startRequest.GetExpirationTimestamp() + offset.Nanoseconds())
+	}
+}
+
 func getScheduleID(
 	activityID string,
 	mutableState execution.MutableState,
@@ -3535,7 +3684,7 @@ func (e *historyEngineImpl) ReapplyEvents(
 					),
 					ndc.EventsReapplicationResetWorkflowReason,
 					toReapplyEvents,
-					false,
+					reset.ReapplyOptions{},
 				); err != nil {
 					return nil, err
 				}
@@ -3603,10 +3752,19 @@ func (e *historyEngineImpl) PurgeDLQMessages(
 	request *types.PurgeDLQMessagesRequest,
 ) error {
 
+	inclusiveEndMessageID, err := e.getDLQInclusiveEndMessageID(
+		ctx,
+		request.GetSourceCluster(),
+		request.InclusiveEndMessageID,
+		request.InclusiveEndTimestamp,
+	)
+	if err != nil {
+		return err
+	}
 	return e.replicationDLQHandler.PurgeMessages(
 		ctx,
 		request.GetSourceCluster(),
-		request.GetInclusiveEndMessageID(),
+		inclusiveEndMessageID,
 	)
 }
 
@@ -3615,10 +3773,19 @@ func (e *historyEngineImpl) MergeDLQMessages(
 	request *types.MergeDLQMessagesRequest,
 ) (*types.MergeDLQMessagesResponse, error) {
 
+	inclusiveEndMessageID, err := e.getDLQInclusiveEndMessageID(
+		ctx,
+		request.GetSourceCluster(),
+		request.InclusiveEndMessageID,
+		request.InclusiveEndTimestamp,
+	)
+	if err != nil {
+		return nil, err
+	}
 	token, err := e.replicationDLQHandler.MergeMessages(
 		ctx,
 		request.GetSourceCluster(),
-		request.GetInclusiveEndMessageID(),
+		inclusiveEndMessageID,
 		int(request.GetMaximumPageSize()),
 		request.GetNextPageToken(),
 	)
@@ -3630,6 +3797,30 @@ func (e *historyEngineImpl) MergeDLQMessages(
 	}, nil
 }
 
+// getDLQInclusiveEndMessageID narrows the message ID range of a DLQ request down to the messages created
+// at or before the end timestamp, when one is given
+func (e *historyEngineImpl) getDLQInclusiveEndMessageID(
+	ctx context.Context,
+	sourceCluster string,
+	inclusiveEndMessageID *int64,
+	inclusiveEndTimestamp *int64,
+) (int64, error) {
+
+	if inclusiveEndTimestamp == nil {
+		return common.Int64Default(inclusiveEndMessageID), nil
+	}
+	lastMessageID := int64(math.MaxInt64)
+	if inclusiveEndMessageID != nil {
+		lastMessageID = *inclusiveEndMessageID
+	}
+	return e.replicationDLQHandler.GetLastMessageIDBefore(
+		ctx,
+		sourceCluster,
+		lastMessageID,
+		*inclusiveEndTimestamp,
+	)
+}
+
 func (e *historyEngineImpl) RefreshWorkflowTasks(
 	ctx context.Context,
 	domainUUID string,
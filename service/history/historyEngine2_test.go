@@ -35,8 +35,10 @@ import (
 	"github.com/uber-go/tally"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/loggerimpl"
 	"github.com/uber/cadence/common/log/tag"
@@ -1050,6 +1052,45 @@ func (s *engine2Suite) TestStartWorkflowExecution_BrandNew() {
 	s.NotNil(resp.RunID)
 }
 
+func (s *engine2Suite) TestSmoothCronWorkflowStart() {
+	cronSchedule := "0 * * * *"
+	workflowID := "workflowID"
+	newStartRequest := func() *types.HistoryStartWorkflowExecutionRequest {
+		return &types.HistoryStartWorkflowExecutionRequest{
+			DomainUUID: constants.TestDomainID,
+			StartRequest: &types.StartWorkflowExecutionRequest{
+				WorkflowID:   workflowID,
+				CronSchedule: cronSchedule,
+			},
+			FirstDecisionTaskBackoffSeconds: common.Int32Ptr(60),
+			ExpirationTimestamp:             common.Int64Ptr(1000),
+		}
+	}
+
+	// disabled by default
+	startRequest := newStartRequest()
+	s.historyEngine.smoothCronWorkflowStart(constants.TestLocalDomainEntry, startRequest)
+	s.Equal(newStartRequest(), startRequest)
+
+	s.config.CronSmoothingWindow = dynamicconfig.GetDurationPropertyFnFilteredByDomain(10 * time.Minute)
+	sched, err := backoff.ValidateSchedule(cronSchedule)
+	s.NoError(err)
+	fireTime := s.historyEngine.shard.GetTimeSource().Now().Add(time.Minute)
+	offset := backoff.GetSmoothingOffset(sched, fireTime, workflowID, 10*time.Minute)
+	s.NotZero(offset)
+
+	startRequest = newStartRequest()
+	s.historyEngine.smoothCronWorkflowStart(constants.TestLocalDomainEntry, startRequest)
+	s.Equal(int32(60+offset/time.Second), startRequest.GetFirstDecisionTaskBackoffSeconds())
+	s.Equal(1000+offset.Nanoseconds(), startRequest.GetExpirationTimestamp())
+
+	// not a cron workflow
+	startRequest = newStartRequest()
+	startRequest.StartRequest.CronSchedule = ""
+	s.historyEngine.smoothCronWorkflowStart(constants.TestLocalDomainEntry, startRequest)
+	s.Equal(int32(60), startRequest.GetFirstDecisionTaskBackoffSeconds())
+}
+
 func (s *engine2Suite) TestStartWorkflowExecution_StillRunning_Dedup() {
 	domainID := constants.TestDomainID
 	workflowID := "workflowID"
@@ -47,6 +47,7 @@ import (
 	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log/loggerimpl"
 	"github.com/uber/cadence/common/mocks"
+	cndc "github.com/uber/cadence/common/ndc"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/common/types/mapper/thrift"
@@ -785,6 +786,103 @@ func (s *engineSuite) TestQueryWorkflow_DecisionTaskDispatch_Complete() {
 	waitGroup.Wait()
 }
 
+func (s *engineSuite) TestCatchUpReplicationForQuery() {
+	domainID := uuid.New()
+	workflowExecution := types.WorkflowExecution{
+		WorkflowID: "TestCatchUpReplicationForQuery",
+		RunID:      constants.TestRunID,
+	}
+	alternativeVersion := cluster.TestAlternativeClusterInitialFailoverVersion + cluster.TestFailoverVersionIncrement*5
+	newEntry := func(activeCluster string, failoverVersion int64) *cache.DomainCacheEntry {
+		return cache.NewGlobalDomainCacheEntryForTest(
+			&persistence.DomainInfo{ID: domainID, Name: "global-domain"},
+			&persistence.DomainConfig{Retention: 1},
+			&persistence.DomainReplicationConfig{
+				ActiveClusterName: activeCluster,
+				Clusters: []*persistence.ClusterReplicationConfig{
+					{ClusterName: cluster.TestCurrentClusterName},
+					{ClusterName: cluster.TestAlternativeClusterName},
+				},
+			},
+			failoverVersion,
+		)
+	}
+	// the workflow was last updated by the alternative cluster, which then failed over to the current cluster
+	domainEntry := newEntry(cluster.TestCurrentClusterName, constants.TestVersion+cluster.TestFailoverVersionIncrement)
+	s.mockDomainCache.EXPECT().GetDomainByID(domainID).Return(domainEntry, nil).AnyTimes()
+	s.mockDomainCache.EXPECT().GetDomainName(domainID).Return("global-domain", nil).AnyTimes()
+
+	newMutableStateResp := func(lastVersion int64) *types.GetMutableStateResponse {
+		return &types.GetMutableStateResponse{
+			Execution: &workflowExecution,
+			VersionHistories: &types.VersionHistories{
+				Histories: []*types.VersionHistory{{
+					BranchToken: []byte{1},
+					Items:       []*types.VersionHistoryItem{{EventID: 5, Version: lastVersion}},
+				}},
+			},
+		}
+	}
+	mockResender := cndc.NewMockHistoryResender(s.controller)
+	s.mockHistoryEngine.queryResenders = map[string]cndc.HistoryResender{
+		cluster.TestAlternativeClusterName: mockResender,
+	}
+	scope := s.mockHistoryEngine.metricsClient.Scope(0)
+	ctx := context.Background()
+
+	// disabled for the domain
+	s.mockHistoryEngine.config.EnableConsistentQueryCatchUp = dynamicconfig.GetBoolPropertyFnFilteredByDomain(false)
+	mutableStateResp := newMutableStateResp(alternativeVersion)
+	resp, err := s.mockHistoryEngine.catchUpReplicationForQuery(ctx, domainID, mutableStateResp, scope)
+	s.NoError(err)
+	s.Equal(mutableStateResp, resp)
+
+	// the workflow was updated in the current cluster since failover
+	s.mockHistoryEngine.config.EnableConsistentQueryCatchUp = dynamicconfig.GetBoolPropertyFnFilteredByDomain(true)
+	mutableStateResp = newMutableStateResp(domainEntry.GetFailoverVersion())
+	resp, err = s.mockHistoryEngine.catchUpReplicationForQuery(ctx, domainID, mutableStateResp, scope)
+	s.NoError(err)
+	s.Equal(mutableStateResp, resp)
+
+	// the cluster which last updated the workflow cannot be reached
+	mockResender.EXPECT().SendSingleWorkflowHistory(
+		domainID,
+		workflowExecution.WorkflowID,
+		workflowExecution.RunID,
+		common.Int64Ptr(5),
+		common.Int64Ptr(alternativeVersion),
+		nil,
+		nil,
+	).Return(errors.New("some random error")).Times(1)
+	_, err = s.mockHistoryEngine.catchUpReplicationForQuery(ctx, domainID, newMutableStateResp(alternativeVersion), scope)
+	s.IsType(&types.InternalServiceError{}, err)
+
+	// the mutable state is reloaded once the missing events are replicated
+	msBuilder := execution.NewMutableStateBuilderWithVersionHistoriesWithEventV2(
+		s.mockHistoryEngine.shard,
+		loggerimpl.NewLoggerForTest(s.Suite),
+		alternativeVersion,
+		workflowExecution.GetRunID(),
+		newEntry(cluster.TestAlternativeClusterName, alternativeVersion),
+	)
+	test.AddWorkflowExecutionStartedEvent(msBuilder, workflowExecution, "wType", "testTaskList", []byte("input"), 100, 200, "identity")
+	test.AddDecisionTaskScheduledEvent(msBuilder)
+	ms := execution.CreatePersistenceMutableState(msBuilder)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything, mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: ms}, nil).Once()
+	mockResender.EXPECT().SendSingleWorkflowHistory(
+		domainID,
+		workflowExecution.WorkflowID,
+		workflowExecution.RunID,
+		common.Int64Ptr(5),
+		common.Int64Ptr(alternativeVersion),
+		nil,
+		nil,
+	).Return(nil).Times(1)
+	resp, err = s.mockHistoryEngine.catchUpReplicationForQuery(ctx, domainID, newMutableStateResp(alternativeVersion), scope)
+	s.NoError(err)
+	s.Equal(msBuilder.GetNextEventID(), resp.GetNextEventID())
+}
+
 func (s *engineSuite) TestQueryWorkflow_DecisionTaskDispatch_Unblocked() {
 	workflowExecution := types.WorkflowExecution{
 		WorkflowID: "TestQueryWorkflow_DecisionTaskDispatch_Unblocked",
@@ -1752,6 +1850,75 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedSingleActivityScheduledDec
 	s.Equal(int32(5), *activity1Attributes.HeartbeatTimeoutSeconds)
 }
 
+func (s *engineSuite) TestRespondDecisionTaskCompletedActivityLocalDispatch() {
+
+	we := types.WorkflowExecution{
+		WorkflowID: "wId",
+		RunID:      constants.TestRunID,
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: "wId",
+		RunID:      we.GetRunID(),
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	msBuilder := execution.NewMutableStateBuilderWithEventV2(
+		s.mockHistoryEngine.shard,
+		loggerimpl.NewLoggerForTest(s.Suite),
+		we.GetRunID(),
+		constants.TestLocalDomainEntry,
+	)
+	test.AddWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := test.AddDecisionTaskScheduledEvent(msBuilder)
+	test.AddDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	var decisions []*types.Decision
+	for _, activityID := range []string{"activity1", "activity2"} {
+		decisions = append(decisions, &types.Decision{
+			DecisionType: types.DecisionTypeScheduleActivityTask.Ptr(),
+			ScheduleActivityTaskDecisionAttributes: &types.ScheduleActivityTaskDecisionAttributes{
+				ActivityID:                    activityID,
+				ActivityType:                  &types.ActivityType{Name: "activity_type1"},
+				TaskList:                      &types.TaskList{Name: tl},
+				ScheduleToCloseTimeoutSeconds: common.Int32Ptr(100),
+				ScheduleToStartTimeoutSeconds: common.Int32Ptr(10),
+				StartToCloseTimeoutSeconds:    common.Int32Ptr(50),
+				HeartbeatTimeoutSeconds:       common.Int32Ptr(5),
+				RequestLocalDispatch:          true,
+			},
+		})
+	}
+
+	ms := execution.CreatePersistenceMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything, mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.Anything, mock.Anything).Return(&persistence.AppendHistoryNodesResponse{}, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything, mock.Anything).Return(&persistence.UpdateWorkflowExecutionResponse{MutableStateUpdateSessionStats: &persistence.MutableStateUpdateSessionStats{}}, nil).Once()
+
+	resp, err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &types.HistoryRespondDecisionTaskCompletedRequest{
+		DomainUUID: constants.TestDomainID,
+		CompleteRequest: &types.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	// only the first activity is returned for local dispatch, the second one is scheduled through matching
+	s.Len(resp.ActivitiesToDispatchLocally, 1)
+	s.NotNil(resp.ActivitiesToDispatchLocally["activity1"])
+	executionBuilder := s.getBuilder(constants.TestDomainID, we)
+	activity1, ok := executionBuilder.GetActivityByActivityID("activity1")
+	s.True(ok)
+	s.NotEqual(common.EmptyEventID, activity1.StartedID)
+	activity2, ok := executionBuilder.GetActivityByActivityID("activity2")
+	s.True(ok)
+	s.Equal(common.EmptyEventID, activity2.StartedID)
+}
+
 func (s *engineSuite) TestRespondDecisionTaskCompleted_DecisionHeartbeatTimeout() {
 
 	we := types.WorkflowExecution{
@@ -0,0 +1,68 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+
+	"go.uber.org/yarpc"
+	yarpcjson "go.uber.org/yarpc/encoding/json"
+
+	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/types/mapper/json"
+)
+
+// jsonHandler serves the history APIs which are not in the history IDL yet with the json encoding
+type jsonHandler struct {
+	h Handler
+}
+
+func newJSONHandler(h Handler) jsonHandler {
+	return jsonHandler{h}
+}
+
+func (j jsonHandler) register(dispatcher *yarpc.Dispatcher) {
+	dispatcher.Register(yarpcjson.Procedure(history.InvalidateCachesProcedure, j.InvalidateCaches))
+	dispatcher.Register(yarpcjson.Procedure(history.DescribeShardOwnershipProcedure, j.DescribeShardOwnership))
+	dispatcher.Register(yarpcjson.Procedure(history.DescribePersistenceLatencyHeatmapProcedure, j.DescribePersistenceLatencyHeatmap))
+	dispatcher.Register(yarpcjson.Procedure(history.DescribeWatchdogProcedure, j.DescribeWatchdog))
+}
+
+func (j jsonHandler) InvalidateCaches(ctx context.Context, request *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error) {
+	response, err := j.h.InvalidateCaches(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j jsonHandler) DescribeShardOwnership(ctx context.Context, request *types.DescribeShardOwnershipRequest) (*types.DescribeShardOwnershipResponse, error) {
+	response, err := j.h.DescribeShardOwnership(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j jsonHandler) DescribePersistenceLatencyHeatmap(ctx context.Context, request *types.DescribePersistenceLatencyHeatmapRequest) (*types.DescribePersistenceLatencyHeatmapResponse, error) {
+	response, err := j.h.DescribePersistenceLatencyHeatmap(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j jsonHandler) DescribeWatchdog(ctx context.Context, request *types.DescribeWatchdogRequest) (*types.DescribeWatchdogResponse, error) {
+	response, err := j.h.DescribeWatchdog(ctx, request)
+	return response, json.FromError(err)
+}
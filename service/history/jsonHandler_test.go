@@ -0,0 +1,64 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/yarpc/yarpcerrors"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestJSONHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := NewMockHandler(ctrl)
+	jh := newJSONHandler(h)
+	ctx := context.Background()
+	internalErr := &types.InternalServiceError{Message: "test"}
+	expectedErr := yarpcerrors.InternalErrorf("test")
+
+	t.Run("InvalidateCaches", func(t *testing.T) {
+		h.EXPECT().InvalidateCaches(ctx, &types.InvalidateCachesRequest{}).Return(nil, internalErr).Times(1)
+		_, err := jh.InvalidateCaches(ctx, &types.InvalidateCachesRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("DescribeShardOwnership", func(t *testing.T) {
+		h.EXPECT().DescribeShardOwnership(ctx, &types.DescribeShardOwnershipRequest{}).Return(nil, internalErr).Times(1)
+		_, err := jh.DescribeShardOwnership(ctx, &types.DescribeShardOwnershipRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("DescribePersistenceLatencyHeatmap", func(t *testing.T) {
+		h.EXPECT().DescribePersistenceLatencyHeatmap(ctx, &types.DescribePersistenceLatencyHeatmapRequest{}).Return(nil, internalErr).Times(1)
+		_, err := jh.DescribePersistenceLatencyHeatmap(ctx, &types.DescribePersistenceLatencyHeatmapRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("DescribeWatchdog", func(t *testing.T) {
+		h.EXPECT().DescribeWatchdog(ctx, &types.DescribeWatchdogRequest{}).Return(nil, internalErr).Times(1)
+		_, err := jh.DescribeWatchdog(ctx, &types.DescribeWatchdogRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+}
@@ -353,7 +353,7 @@ func (r *transactionManagerImpl) backfillWorkflowEventsReapply(
 			targetWorkflow,
 			EventsReapplicationResetWorkflowReason,
 			targetWorkflowEvents.Events,
-			false,
+			reset.ReapplyOptions{},
 		); err != nil {
 			return 0, execution.TransactionPolicyActive, err
 		}
@@ -40,6 +40,10 @@ const (
 	warnPendingTasks = 2000
 )
 
+var (
+	lowTaskPriority = common.GetTaskPriority(common.LowPriorityClass, common.DefaultPrioritySubclass)
+)
+
 type (
 	updateMaxReadLevelFn          func() task.Key
 	updateClusterAckLevelFn       func(task.Key) error // TODO: deprecate this in favor of updateProcessingQueueStatesFn
@@ -47,34 +51,35 @@ type (
 	queueShutdownFn               func() error
 
 	queueProcessorOptions struct {
-		BatchSize                            dynamicconfig.IntPropertyFn
-		DeleteBatchSize                      dynamicconfig.IntPropertyFn
-		MaxPollRPS                           dynamicconfig.IntPropertyFn
-		MaxPollInterval                      dynamicconfig.DurationPropertyFn
-		MaxPollIntervalJitterCoefficient     dynamicconfig.FloatPropertyFn
-		UpdateAckInterval                    dynamicconfig.DurationPropertyFn
-		UpdateAckIntervalJitterCoefficient   dynamicconfig.FloatPropertyFn
-		RedispatchInterval                   dynamicconfig.DurationPropertyFn
-		RedispatchIntervalJitterCoefficient  dynamicconfig.FloatPropertyFn
-		MaxRedispatchQueueSize               dynamicconfig.IntPropertyFn
-		MaxStartJitterInterval               dynamicconfig.DurationPropertyFn
-		SplitQueueInterval                   dynamicconfig.DurationPropertyFn
-		SplitQueueIntervalJitterCoefficient  dynamicconfig.FloatPropertyFn
-		EnableSplit                          dynamicconfig.BoolPropertyFn
-		SplitMaxLevel                        dynamicconfig.IntPropertyFn
-		EnableRandomSplitByDomainID          dynamicconfig.BoolPropertyFnWithDomainIDFilter
-		RandomSplitProbability               dynamicconfig.FloatPropertyFn
-		EnablePendingTaskSplitByDomainID     dynamicconfig.BoolPropertyFnWithDomainIDFilter
-		PendingTaskSplitThreshold            dynamicconfig.MapPropertyFn
-		EnableStuckTaskSplitByDomainID       dynamicconfig.BoolPropertyFnWithDomainIDFilter
-		StuckTaskSplitThreshold              dynamicconfig.MapPropertyFn
-		SplitLookAheadDurationByDomainID     dynamicconfig.DurationPropertyFnWithDomainIDFilter
-		PollBackoffInterval                  dynamicconfig.DurationPropertyFn
-		PollBackoffIntervalJitterCoefficient dynamicconfig.FloatPropertyFn
-		EnablePersistQueueStates             dynamicconfig.BoolPropertyFn
-		EnableLoadQueueStates                dynamicconfig.BoolPropertyFn
-		EnableValidator                      dynamicconfig.BoolPropertyFn
-		ValidationInterval                   dynamicconfig.DurationPropertyFn
+		BatchSize                             dynamicconfig.IntPropertyFn
+		DeleteBatchSize                       dynamicconfig.IntPropertyFn
+		MaxPollRPS                            dynamicconfig.IntPropertyFn
+		MaxPollInterval                       dynamicconfig.DurationPropertyFn
+		MaxPollIntervalJitterCoefficient      dynamicconfig.FloatPropertyFn
+		UpdateAckInterval                     dynamicconfig.DurationPropertyFn
+		UpdateAckIntervalJitterCoefficient    dynamicconfig.FloatPropertyFn
+		RedispatchInterval                    dynamicconfig.DurationPropertyFn
+		RedispatchIntervalJitterCoefficient   dynamicconfig.FloatPropertyFn
+		MaxRedispatchQueueSize                dynamicconfig.IntPropertyFn
+		MaxStartJitterInterval                dynamicconfig.DurationPropertyFn
+		SplitQueueInterval                    dynamicconfig.DurationPropertyFn
+		SplitQueueIntervalJitterCoefficient   dynamicconfig.FloatPropertyFn
+		EnableSplit                           dynamicconfig.BoolPropertyFn
+		SplitMaxLevel                         dynamicconfig.IntPropertyFn
+		EnableRandomSplitByDomainID           dynamicconfig.BoolPropertyFnWithDomainIDFilter
+		RandomSplitProbability                dynamicconfig.FloatPropertyFn
+		EnablePendingTaskSplitByDomainID      dynamicconfig.BoolPropertyFnWithDomainIDFilter
+		PendingTaskSplitThreshold             dynamicconfig.MapPropertyFn
+		EnableStuckTaskSplitByDomainID        dynamicconfig.BoolPropertyFnWithDomainIDFilter
+		StuckTaskSplitThreshold               dynamicconfig.MapPropertyFn
+		SplitLookAheadDurationByDomainID      dynamicconfig.DurationPropertyFnWithDomainIDFilter
+		EnableLowPrioritySplitQueueByDomainID dynamicconfig.BoolPropertyFnWithDomainIDFilter
+		PollBackoffInterval                   dynamicconfig.DurationPropertyFn
+		PollBackoffIntervalJitterCoefficient  dynamicconfig.FloatPropertyFn
+		EnablePersistQueueStates              dynamicconfig.BoolPropertyFn
+		EnableLoadQueueStates                 dynamicconfig.BoolPropertyFn
+		EnableValidator                       dynamicconfig.BoolPropertyFn
+		ValidationInterval                    dynamicconfig.DurationPropertyFn
 		// MaxPendingTaskSize is used in cross cluster queue to limit the pending task count
 		MaxPendingTaskSize dynamicconfig.IntPropertyFn
 		MetricScope        int
@@ -463,6 +468,20 @@ func (p *processorBase) getProcessingQueueStates() *ActionResult {
 func (p *processorBase) submitTask(
 	task task.Task,
 ) (bool, error) {
+	return p.submitTaskForLevel(task, defaultProcessingQueueLevel)
+}
+
+// submitTaskForLevel submits a task loaded by a processing queue of the given level. Tasks loaded
+// by queues split from the default level are processed at low priority, so that a domain with a burst
+// of tasks moved to its own queue does not block tasks of other domains in the shard.
+func (p *processorBase) submitTaskForLevel(
+	task task.Task,
+	level int,
+) (bool, error) {
+	if level != defaultProcessingQueueLevel && p.options.EnableLowPrioritySplitQueueByDomainID(task.GetDomainID()) {
+		task.SetPriority(lowTaskPriority)
+	}
+
 	submitted, err := p.taskProcessor.TrySubmit(task)
 	if err != nil {
 		select {
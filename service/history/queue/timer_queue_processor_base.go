@@ -348,7 +348,7 @@ func (t *timerQueueProcessorBase) processQueueCollections(levels map[int]struct{
 
 			task := t.taskInitializer(taskInfo)
 			tasks[newTimerTaskKey(taskInfo.GetVisibilityTimestamp(), taskInfo.GetTaskID())] = task
-			submitted, err := t.submitTask(task)
+			submitted, err := t.submitTaskForLevel(task, level)
 			if err != nil {
 				// only err here is due to the fact that processor has been shutdown
 				// return instead of continue
@@ -669,6 +669,7 @@ func newTimerQueueProcessorOptions(
 		options.EnableStuckTaskSplitByDomainID = config.QueueProcessorEnableStuckTaskSplitByDomainID
 		options.StuckTaskSplitThreshold = config.QueueProcessorStuckTaskSplitThreshold
 		options.SplitLookAheadDurationByDomainID = config.QueueProcessorSplitLookAheadDurationByDomainID
+		options.EnableLowPrioritySplitQueueByDomainID = config.QueueProcessorEnableLowPrioritySplitQueueByDomainID
 
 		options.EnablePersistQueueStates = config.QueueProcessorEnablePersistQueueStates
 		options.EnableLoadQueueStates = config.QueueProcessorEnableLoadQueueStates
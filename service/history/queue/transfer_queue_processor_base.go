@@ -425,7 +425,7 @@ func (t *transferQueueProcessorBase) processQueueCollections() {
 
 			task := t.taskInitializer(taskInfo)
 			tasks[newTransferTaskKey(taskInfo.GetTaskID())] = task
-			submitted, err := t.submitTask(task)
+			submitted, err := t.submitTaskForLevel(task, level)
 			if err != nil {
 				// only err here is due to the fact that processor has been shutdown
 				// return instead of continue
@@ -599,6 +599,7 @@ func newTransferQueueProcessorOptions(
 		options.EnableStuckTaskSplitByDomainID = config.QueueProcessorEnableStuckTaskSplitByDomainID
 		options.StuckTaskSplitThreshold = config.QueueProcessorStuckTaskSplitThreshold
 		options.SplitLookAheadDurationByDomainID = config.QueueProcessorSplitLookAheadDurationByDomainID
+		options.EnableLowPrioritySplitQueueByDomainID = config.QueueProcessorEnableLowPrioritySplitQueueByDomainID
 
 		options.EnablePersistQueueStates = config.QueueProcessorEnablePersistQueueStates
 		options.EnableLoadQueueStates = config.QueueProcessorEnableLoadQueueStates
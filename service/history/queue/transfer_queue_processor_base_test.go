@@ -30,6 +30,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/uber-go/tally"
 
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/loggerimpl"
@@ -366,6 +367,64 @@ func (s *transferQueueProcessorBaseSuite) TestProcessQueueCollections_WithNextPa
 	}
 }
 
+func (s *transferQueueProcessorBaseSuite) TestProcessQueueCollections_SplitQueue_LowPriority() {
+	queueLevel := defaultProcessingQueueLevel + 1
+	ackLevel := newTransferTaskKey(0)
+	maxLevel := newTransferTaskKey(1000)
+	processingQueueStates := []ProcessingQueueState{
+		NewProcessingQueueState(
+			queueLevel,
+			ackLevel,
+			maxLevel,
+			NewDomainFilter(map[string]struct{}{"testDomain1": {}, "testDomain2": {}}, false),
+		),
+	}
+	updateMaxReadLevel := func() task.Key {
+		return newTransferTaskKey(10000)
+	}
+	taskInfos := []*persistence.TransferTaskInfo{
+		{
+			TaskID:   1,
+			DomainID: "testDomain1",
+		},
+		{
+			TaskID:   10,
+			DomainID: "testDomain2",
+		},
+	}
+	mockExecutionManager := s.mockShard.Resource.ExecutionMgr
+	mockExecutionManager.On("GetTransferTasks", mock.Anything, &persistence.GetTransferTasksRequest{
+		ReadLevel:    ackLevel.(transferTaskKey).taskID,
+		MaxReadLevel: maxLevel.(transferTaskKey).taskID,
+		BatchSize:    s.mockShard.GetConfig().TransferTaskBatchSize(),
+	}).Return(&persistence.GetTransferTasksResponse{
+		Tasks:         taskInfos,
+		NextPageToken: nil,
+	}, nil).Once()
+
+	priorities := make(map[string]int)
+	s.mockTaskProcessor.EXPECT().TrySubmit(gomock.Any()).DoAndReturn(func(t task.Task) (bool, error) {
+		priorities[t.GetDomainID()] = t.Priority()
+		return true, nil
+	}).Times(2)
+
+	processorBase := s.newTestTransferQueueProcessorBase(
+		processingQueueStates,
+		updateMaxReadLevel,
+		nil,
+		nil,
+		nil,
+	)
+	processorBase.options.EnableLowPrioritySplitQueueByDomainID = func(domainID string) bool {
+		return domainID == "testDomain1"
+	}
+
+	processorBase.processQueueCollections()
+
+	s.Equal(lowTaskPriority, priorities["testDomain1"])
+	s.Equal(common.NoPriority, priorities["testDomain2"])
+}
+
 func (s *transferQueueProcessorBaseSuite) TestReadTasks_NoNextPage() {
 	readLevel := newTransferTaskKey(3)
 	maxReadLevel := newTransferTaskKey(100)
@@ -22,6 +22,7 @@ package replication
 
 import (
 	"context"
+	"math"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -39,6 +40,8 @@ import (
 
 const (
 	defaultBeginningMessageID = -1
+
+	dlqAutoRepairPageSize = 100
 )
 
 var (
@@ -73,6 +76,14 @@ type (
 			pageSize int,
 			pageToken []byte,
 		) ([]byte, error)
+		// GetLastMessageIDBefore returns the ID of the last message up to lastMessageID created at or before the end
+		// timestamp, in unix nanos, so that a range of messages can be purged or merged by time
+		GetLastMessageIDBefore(
+			ctx context.Context,
+			sourceCluster string,
+			lastMessageID int64,
+			endTimestamp int64,
+		) (int64, error)
 	}
 
 	dlqHandlerImpl struct {
@@ -85,6 +96,15 @@ type (
 
 		mu           sync.Mutex
 		latestCounts map[string]int64
+
+		// the following fields are only accessed by the auto repair loop
+		retryStates map[string]map[int64]*dlqRetryState // source cluster -> task ID -> retry state
+		domainSizes map[string]map[string]int64         // source cluster -> domain ID -> DLQ size
+	}
+
+	dlqRetryState struct {
+		attempt     int
+		nextAttempt time.Time
 	}
 )
 
@@ -106,6 +126,8 @@ func NewDLQHandler(
 		logger:        shard.GetLogger(),
 		metricsClient: shard.GetMetricsClient(),
 		done:          make(chan struct{}),
+		retryStates:   make(map[string]map[int64]*dlqRetryState),
+		domainSizes:   make(map[string]map[string]int64),
 	}
 }
 
@@ -116,6 +138,7 @@ func (r *dlqHandlerImpl) Start() {
 	}
 
 	go r.emitDLQSizeMetricsLoop()
+	go r.autoRepairLoop()
 	r.logger.Info("DLQ handler started.")
 }
 
@@ -190,6 +213,208 @@ func (r *dlqHandlerImpl) emitDLQSizeMetricsLoop() {
 	}
 }
 
+func (r *dlqHandlerImpl) autoRepairLoop() {
+	getInterval := func() time.Duration {
+		return backoff.JitDuration(
+			r.shard.GetConfig().ReplicationDLQAutoRepairInterval(),
+			dlqMetricsEmitTimerCoefficient,
+		)
+	}
+
+	timer := time.NewTimer(getInterval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if r.shard.GetConfig().EnableReplicationDLQAutoRepair() {
+				r.repairMessages(context.Background())
+			}
+			timer.Reset(getInterval())
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// repairMessages scans the DLQ of each source cluster to emit its size by domain, and retries the messages whose
+// backoff expired. The messages which are applied, or which don't exist in the source cluster anymore, are deleted.
+func (r *dlqHandlerImpl) repairMessages(ctx context.Context) {
+	for sourceCluster := range r.taskExecutors {
+		if err := r.repairMessagesFromCluster(ctx, sourceCluster); err != nil {
+			r.logger.Warn("Failed to repair replication DLQ messages", tag.SourceCluster(sourceCluster), tag.Error(err))
+		}
+	}
+}
+
+func (r *dlqHandlerImpl) repairMessagesFromCluster(ctx context.Context, sourceCluster string) error {
+	retryStates := make(map[int64]*dlqRetryState)
+	domainSizes := make(map[string]int64)
+	now := r.shard.GetTimeSource().Now()
+
+	var pageToken []byte
+	for {
+		resp, err := r.shard.GetExecutionManager().GetReplicationTasksFromDLQ(
+			ctx,
+			&persistence.GetReplicationTasksFromDLQRequest{
+				SourceClusterName: sourceCluster,
+				GetReplicationTasksRequest: persistence.GetReplicationTasksRequest{
+					ReadLevel:     defaultBeginningMessageID,
+					MaxReadLevel:  math.MaxInt64,
+					BatchSize:     dlqAutoRepairPageSize,
+					NextPageToken: pageToken,
+				},
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		var dueTasks []*persistence.ReplicationTaskInfo
+		for _, task := range resp.Tasks {
+			state, ok := r.retryStates[sourceCluster][task.TaskID]
+			if ok && state.nextAttempt.After(now) {
+				retryStates[task.TaskID] = state
+				domainSizes[task.DomainID]++
+				continue
+			}
+			dueTasks = append(dueTasks, task)
+		}
+
+		for _, task := range r.retryMessages(ctx, sourceCluster, dueTasks) {
+			state, ok := r.retryStates[sourceCluster][task.TaskID]
+			if !ok {
+				state = &dlqRetryState{}
+			}
+			state.nextAttempt = now.Add(r.getRetryBackoff(state.attempt))
+			state.attempt++
+			retryStates[task.TaskID] = state
+			domainSizes[task.DomainID]++
+		}
+
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+
+	r.retryStates[sourceCluster] = retryStates
+	r.emitDomainSizes(sourceCluster, domainSizes)
+	return nil
+}
+
+// retryMessages applies the messages again, deletes those which don't need to be retried, and returns the others
+func (r *dlqHandlerImpl) retryMessages(
+	ctx context.Context,
+	sourceCluster string,
+	tasks []*persistence.ReplicationTaskInfo,
+) []*persistence.ReplicationTaskInfo {
+	if len(tasks) == 0 {
+		return nil
+	}
+	scope := r.metricsClient.Scope(
+		metrics.ReplicationDLQStatsScope,
+		metrics.SourceClusterTag(sourceCluster),
+		metrics.InstanceTag(strconv.Itoa(r.shard.GetShardID())),
+	)
+
+	remoteAdminClient := r.shard.GetService().GetClientBean().GetRemoteAdminClient(sourceCluster)
+	if remoteAdminClient == nil {
+		return tasks
+	}
+	taskInfos := make([]*types.ReplicationTaskInfo, 0, len(tasks))
+	for _, task := range tasks {
+		taskInfos = append(taskInfos, &types.ReplicationTaskInfo{
+			DomainID:     task.GetDomainID(),
+			WorkflowID:   task.GetWorkflowID(),
+			RunID:        task.GetRunID(),
+			TaskType:     int16(task.GetTaskType()),
+			TaskID:       task.GetTaskID(),
+			Version:      task.GetVersion(),
+			FirstEventID: task.FirstEventID,
+			NextEventID:  task.NextEventID,
+			ScheduledID:  task.ScheduledID,
+		})
+	}
+	response, err := remoteAdminClient.GetDLQReplicationMessages(
+		ctx,
+		&types.GetDLQReplicationMessagesRequest{
+			TaskInfos: taskInfos,
+		},
+	)
+	if err != nil {
+		r.logger.Warn("Failed to hydrate replication DLQ messages", tag.SourceCluster(sourceCluster), tag.Error(err))
+		scope.AddCounter(metrics.ReplicationDLQRepairFailedCounter, int64(len(tasks)))
+		return tasks
+	}
+	replicationTasks := make(map[int64]*types.ReplicationTask, len(response.ReplicationTasks))
+	for _, task := range response.ReplicationTasks {
+		replicationTasks[task.SourceTaskID] = task
+	}
+
+	var failedTasks []*persistence.ReplicationTaskInfo
+	for _, task := range tasks {
+		// as for a merge, a message which doesn't exist in the source cluster anymore is deleted
+		if replicationTask, ok := replicationTasks[task.TaskID]; ok {
+			if _, err := r.taskExecutors[sourceCluster].execute(replicationTask, true); err != nil {
+				scope.IncCounter(metrics.ReplicationDLQRepairFailedCounter)
+				failedTasks = append(failedTasks, task)
+				continue
+			}
+		}
+
+		if err := r.shard.GetExecutionManager().DeleteReplicationTaskFromDLQ(
+			ctx,
+			&persistence.DeleteReplicationTaskFromDLQRequest{
+				SourceClusterName: sourceCluster,
+				TaskID:            task.TaskID,
+			},
+		); err != nil {
+			r.logger.Warn("Failed to delete repaired replication DLQ message",
+				tag.SourceCluster(sourceCluster), tag.TaskID(task.TaskID), tag.Error(err))
+			failedTasks = append(failedTasks, task)
+			continue
+		}
+		scope.IncCounter(metrics.ReplicationDLQRepairedCounter)
+	}
+	return failedTasks
+}
+
+func (r *dlqHandlerImpl) getRetryBackoff(attempt int) time.Duration {
+	config := r.shard.GetConfig()
+	interval := config.ReplicationDLQAutoRepairInterval()
+	policy := backoff.NewExponentialRetryPolicy(interval)
+	policy.SetMaximumInterval(common.MaxDuration(config.ReplicationDLQAutoRepairMaxBackoff(), interval))
+	policy.SetExpirationInterval(backoff.NoInterval)
+	return policy.ComputeNextDelay(0, attempt)
+}
+
+func (r *dlqHandlerImpl) emitDomainSizes(sourceCluster string, domainSizes map[string]int64) {
+	shardID := strconv.Itoa(r.shard.GetShardID())
+	for domainID := range r.domainSizes[sourceCluster] {
+		if _, ok := domainSizes[domainID]; !ok {
+			// reset the gauge of the domains whose messages are all gone
+			domainSizes[domainID] = 0
+		}
+	}
+	for domainID, size := range domainSizes {
+		domainName, err := r.shard.GetDomainCache().GetDomainName(domainID)
+		if err != nil {
+			domainName = domainID
+		}
+		r.metricsClient.Scope(
+			metrics.ReplicationDLQStatsScope,
+			metrics.SourceClusterTag(sourceCluster),
+			metrics.InstanceTag(shardID),
+			metrics.DomainTag(domainName),
+		).UpdateGauge(metrics.ReplicationDLQDomainSize, float64(size))
+		if size == 0 {
+			delete(domainSizes, domainID)
+		}
+	}
+	r.domainSizes[sourceCluster] = domainSizes
+}
+
 func (r *dlqHandlerImpl) ReadMessages(
 	ctx context.Context,
 	sourceCluster string,
@@ -286,6 +511,48 @@ func (r *dlqHandlerImpl) PurgeMessages(
 	return nil
 }
 
+// GetLastMessageIDBefore pages through the messages in order until the first one created after the end timestamp.
+// The messages of a shard are ordered by task ID, which follows their creation time in the source cluster.
+func (r *dlqHandlerImpl) GetLastMessageIDBefore(
+	ctx context.Context,
+	sourceCluster string,
+	lastMessageID int64,
+	endTimestamp int64,
+) (int64, error) {
+
+	result := int64(defaultBeginningMessageID)
+	var pageToken []byte
+	for {
+		resp, err := r.shard.GetExecutionManager().GetReplicationTasksFromDLQ(
+			ctx,
+			&persistence.GetReplicationTasksFromDLQRequest{
+				SourceClusterName: sourceCluster,
+				GetReplicationTasksRequest: persistence.GetReplicationTasksRequest{
+					ReadLevel:     defaultBeginningMessageID,
+					MaxReadLevel:  lastMessageID,
+					BatchSize:     dlqAutoRepairPageSize,
+					NextPageToken: pageToken,
+				},
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, task := range resp.Tasks {
+			if task.CreationTime > endTimestamp {
+				return result, nil
+			}
+			result = task.TaskID
+		}
+
+		pageToken = resp.NextPageToken
+		if len(pageToken) == 0 {
+			return result, nil
+		}
+	}
+}
+
 func (r *dlqHandlerImpl) MergeMessages(
 	ctx context.Context,
 	sourceCluster string,
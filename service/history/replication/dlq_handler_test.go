@@ -22,7 +22,10 @@ package replication
 
 import (
 	"context"
+	"errors"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
@@ -32,6 +35,7 @@ import (
 
 	"github.com/uber/cadence/client"
 	"github.com/uber/cadence/client/admin"
+	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/mocks"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/types"
@@ -221,6 +225,104 @@ func (s *dlqHandlerSuite) TestMergeMessages_OK() {
 	s.Equal(1, len(s.taskExecutor.executedTasks))
 }
 
+func (s *dlqHandlerSuite) TestGetLastMessageIDBefore() {
+	ctx := context.Background()
+	lastMessageID := int64(3)
+
+	s.executionManager.On("GetReplicationTasksFromDLQ", mock.Anything, &persistence.GetReplicationTasksFromDLQRequest{
+		SourceClusterName: s.sourceCluster,
+		GetReplicationTasksRequest: persistence.GetReplicationTasksRequest{
+			ReadLevel:    -1,
+			MaxReadLevel: lastMessageID,
+			BatchSize:    dlqAutoRepairPageSize,
+		},
+	}).Return(&persistence.GetReplicationTasksFromDLQResponse{
+		Tasks: []*persistence.ReplicationTaskInfo{
+			{TaskID: 1, CreationTime: 10},
+			{TaskID: 2, CreationTime: 20},
+			{TaskID: 3, CreationTime: 30},
+		},
+	}, nil)
+
+	messageID, err := s.messageHandler.GetLastMessageIDBefore(ctx, s.sourceCluster, lastMessageID, 25)
+	s.NoError(err)
+	s.Equal(int64(2), messageID)
+
+	messageID, err = s.messageHandler.GetLastMessageIDBefore(ctx, s.sourceCluster, lastMessageID, 30)
+	s.NoError(err)
+	s.Equal(int64(3), messageID)
+
+	messageID, err = s.messageHandler.GetLastMessageIDBefore(ctx, s.sourceCluster, lastMessageID, 5)
+	s.NoError(err)
+	s.Equal(int64(-1), messageID)
+}
+
+func (s *dlqHandlerSuite) TestRepairMessages() {
+	ctx := context.Background()
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	s.mockShard.Resource.TimeSource = timeSource
+	s.mockShard.Resource.DomainCache.EXPECT().GetDomainName(gomock.Any()).Return("test-domain", nil).AnyTimes()
+	s.mockClientBean.EXPECT().GetRemoteAdminClient(s.sourceCluster).Return(s.adminClient).AnyTimes()
+
+	failingTask := &persistence.ReplicationTaskInfo{DomainID: uuid.New(), TaskID: 1}
+	missingTask := &persistence.ReplicationTaskInfo{DomainID: uuid.New(), TaskID: 2}
+	readRequest := &persistence.GetReplicationTasksFromDLQRequest{
+		SourceClusterName: s.sourceCluster,
+		GetReplicationTasksRequest: persistence.GetReplicationTasksRequest{
+			ReadLevel:    -1,
+			MaxReadLevel: math.MaxInt64,
+			BatchSize:    dlqAutoRepairPageSize,
+		},
+	}
+	s.executionManager.On("GetReplicationTasksFromDLQ", mock.Anything, readRequest).Return(&persistence.GetReplicationTasksFromDLQResponse{
+		Tasks: []*persistence.ReplicationTaskInfo{failingTask, missingTask},
+	}, nil).Once()
+	s.executionManager.On("GetReplicationTasksFromDLQ", mock.Anything, readRequest).Return(&persistence.GetReplicationTasksFromDLQResponse{
+		Tasks: []*persistence.ReplicationTaskInfo{failingTask},
+	}, nil).Times(2)
+	s.adminClient.EXPECT().GetDLQReplicationMessages(ctx, gomock.Any()).Return(&types.GetDLQReplicationMessagesResponse{
+		ReplicationTasks: []*types.ReplicationTask{{SourceTaskID: failingTask.TaskID}},
+	}, nil).Times(2)
+	s.executionManager.On("DeleteReplicationTaskFromDLQ", mock.Anything, &persistence.DeleteReplicationTaskFromDLQRequest{
+		SourceClusterName: s.sourceCluster,
+		TaskID:            missingTask.TaskID,
+	}).Return(nil).Once()
+	s.executionManager.On("DeleteReplicationTaskFromDLQ", mock.Anything, &persistence.DeleteReplicationTaskFromDLQRequest{
+		SourceClusterName: s.sourceCluster,
+		TaskID:            failingTask.TaskID,
+	}).Return(nil).Once()
+
+	// the task missing in the source cluster is deleted, the failing one is kept with a backoff
+	s.taskExecutor.err = errors.New("some random error")
+	s.messageHandler.repairMessages(ctx)
+	s.Len(s.taskExecutor.executedTasks, 1)
+	s.Equal(1, s.messageHandler.retryStates[s.sourceCluster][failingTask.TaskID].attempt)
+	s.Equal(map[string]int64{failingTask.DomainID: 1}, s.messageHandler.domainSizes[s.sourceCluster])
+
+	// the failing task is not retried before its backoff expires
+	s.messageHandler.repairMessages(ctx)
+	s.Len(s.taskExecutor.executedTasks, 1)
+
+	s.taskExecutor.err = nil
+	timeSource.Update(timeSource.Now().Add(s.config.ReplicationDLQAutoRepairInterval()))
+	s.messageHandler.repairMessages(ctx)
+	s.Len(s.taskExecutor.executedTasks, 2)
+	s.Empty(s.messageHandler.retryStates[s.sourceCluster])
+	s.Empty(s.messageHandler.domainSizes[s.sourceCluster])
+}
+
+func (s *dlqHandlerSuite) TestGetRetryBackoff() {
+	// the backoff is jittered down by up to 20%
+	interval := s.config.ReplicationDLQAutoRepairInterval()
+	maxBackoff := s.config.ReplicationDLQAutoRepairMaxBackoff()
+	for attempt, expected := range []time.Duration{interval, 2 * interval, 4 * interval} {
+		backoff := s.messageHandler.getRetryBackoff(attempt)
+		s.True(backoff <= expected && backoff >= expected*8/10, "attempt %v got backoff %v", attempt, backoff)
+	}
+	backoff := s.messageHandler.getRetryBackoff(100)
+	s.True(backoff <= maxBackoff && backoff >= maxBackoff*8/10, "got backoff %v", backoff)
+}
+
 type fakeTaskExecutor struct {
 	scope int
 	err   error
@@ -515,6 +515,8 @@ func (p *taskProcessorImpl) generateDLQRequest(
 				TaskID:      replicationTask.GetSourceTaskID(),
 				TaskType:    persistence.ReplicationTaskTypeSyncActivity,
 				ScheduledID: taskAttributes.GetScheduledID(),
+				// the creation time in the source cluster lets the DLQ be purged or merged by time
+				CreationTime: replicationTask.GetCreationTime(),
 			},
 			DomainName: domainName,
 		}, nil
@@ -547,6 +549,7 @@ func (p *taskProcessorImpl) generateDLQRequest(
 				FirstEventID: events[0].ID,
 				NextEventID:  events[len(events)-1].ID + 1,
 				Version:      events[0].Version,
+				CreationTime: replicationTask.GetCreationTime(),
 			},
 			DomainName: domainName,
 		}, nil
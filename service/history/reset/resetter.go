@@ -55,7 +55,7 @@ type (
 			currentWorkflow execution.Workflow,
 			resetReason string,
 			additionalReapplyEvents []*types.HistoryEvent,
-			skipSignalReapply bool,
+			reapplyOptions ReapplyOptions,
 		) error
 	}
 
@@ -70,6 +70,16 @@ type (
 	}
 
 	nDCStateRebuilderProvider func() execution.StateRebuilder
+
+	// ReapplyOptions controls which events after the reset point are re-applied to the reset workflow,
+	// right now only signals are eligible for re-application. The zero value re-applies all of them.
+	ReapplyOptions struct {
+		SkipSignals bool
+		// SignalNames, if not empty, limits the re-applied signals to those with these names
+		SignalNames []string
+		// ExcludeSignalNames are the names of the signals not to re-apply
+		ExcludeSignalNames []string
+	}
 )
 
 var _ WorkflowResetter = (*workflowResetterImpl)(nil)
@@ -107,7 +117,7 @@ func (r *workflowResetterImpl) ResetWorkflow(
 	currentWorkflow execution.Workflow,
 	resetReason string,
 	additionalReapplyEvents []*types.HistoryEvent,
-	skipSignalReapply bool,
+	reapplyOptions ReapplyOptions,
 ) (retError error) {
 
 	domainEntry, err := r.domainCache.GetDomainByID(domainID)
@@ -143,7 +153,7 @@ func (r *workflowResetterImpl) ResetWorkflow(
 		resetWorkflowVersion,
 		resetReason,
 		additionalReapplyEvents,
-		skipSignalReapply,
+		reapplyOptions,
 	)
 	if err != nil {
 		return err
@@ -172,7 +182,7 @@ func (r *workflowResetterImpl) prepareResetWorkflow(
 	resetWorkflowVersion int64,
 	resetReason string,
 	additionalReapplyEvents []*types.HistoryEvent,
-	skipSignalReapply bool,
+	reapplyOptions ReapplyOptions,
 ) (execution.Workflow, error) {
 
 	resetWorkflow, err := r.replayResetWorkflow(
@@ -223,7 +233,7 @@ func (r *workflowResetterImpl) prepareResetWorkflow(
 	// TODO right now only signals are eligible for reapply, so we can directly skip the whole reapply process
 	// for the sake of performance. In the future, if there are other events that need to be reapplied, remove this check
 	// For example, we may want to re-apply activity/timer results for https://github.com/uber/cadence/issues/2934
-	if !skipSignalReapply {
+	if !reapplyOptions.SkipSignals {
 		if err := r.reapplyResetAndContinueAsNewWorkflowEvents(
 			ctx,
 			resetMutableState,
@@ -233,6 +243,7 @@ func (r *workflowResetterImpl) prepareResetWorkflow(
 			baseBranchToken,
 			baseRebuildLastEventID+1,
 			baseNextEventID,
+			reapplyOptions,
 		); err != nil {
 			return nil, err
 		}
@@ -240,7 +251,7 @@ func (r *workflowResetterImpl) prepareResetWorkflow(
 	}
 
 	// NOTE: this is reapplying events that are passing into the API that we shouldn't skip
-	if err := r.reapplyEvents(resetMutableState, additionalReapplyEvents); err != nil {
+	if err := r.reapplyEvents(resetMutableState, additionalReapplyEvents, ReapplyOptions{}); err != nil {
 		return nil, err
 	}
 
@@ -456,6 +467,7 @@ func (r *workflowResetterImpl) reapplyResetAndContinueAsNewWorkflowEvents(
 	baseBranchToken []byte,
 	baseRebuildNextEventID int64,
 	baseNextEventID int64,
+	reapplyOptions ReapplyOptions,
 ) error {
 
 	// TODO change this logic to fetching all workflow [baseWorkflow, currentWorkflow]
@@ -471,6 +483,7 @@ func (r *workflowResetterImpl) reapplyResetAndContinueAsNewWorkflowEvents(
 		baseRebuildNextEventID,
 		baseNextEventID,
 		baseBranchToken,
+		reapplyOptions,
 	); err != nil {
 		return err
 	}
@@ -516,6 +529,7 @@ func (r *workflowResetterImpl) reapplyResetAndContinueAsNewWorkflowEvents(
 			common.FirstEventID,
 			nextWorkflowNextEventID,
 			nextWorkflowBranchToken,
+			reapplyOptions,
 		); err != nil {
 			return err
 		}
@@ -529,6 +543,7 @@ func (r *workflowResetterImpl) reapplyWorkflowEvents(
 	firstEventID int64,
 	nextEventID int64,
 	branchToken []byte,
+	reapplyOptions ReapplyOptions,
 ) (string, error) {
 
 	// TODO change this logic to fetching all workflow [baseWorkflow, currentWorkflow]
@@ -558,7 +573,7 @@ func (r *workflowResetterImpl) reapplyWorkflowEvents(
 			return "", err
 		}
 		lastEvents = batch.(*types.History).Events
-		if err := r.reapplyEvents(mutableState, lastEvents); err != nil {
+		if err := r.reapplyEvents(mutableState, lastEvents, reapplyOptions); err != nil {
 			return "", err
 		}
 	}
@@ -575,12 +590,16 @@ func (r *workflowResetterImpl) reapplyWorkflowEvents(
 func (r *workflowResetterImpl) reapplyEvents(
 	mutableState execution.MutableState,
 	events []*types.HistoryEvent,
+	reapplyOptions ReapplyOptions,
 ) error {
 
 	for _, event := range events {
 		switch event.GetEventType() {
 		case types.EventTypeWorkflowExecutionSignaled:
 			attr := event.GetWorkflowExecutionSignaledEventAttributes()
+			if !reapplyOptions.shouldReapplySignal(attr.GetSignalName()) {
+				continue
+			}
 			if _, err := mutableState.AddWorkflowExecutionSignaled(
 				attr.GetSignalName(),
 				attr.GetInput(),
@@ -595,6 +614,29 @@ func (r *workflowResetterImpl) reapplyEvents(
 	return nil
 }
 
+func (o ReapplyOptions) shouldReapplySignal(
+	signalName string,
+) bool {
+
+	if o.SkipSignals {
+		return false
+	}
+	for _, name := range o.ExcludeSignalNames {
+		if name == signalName {
+			return false
+		}
+	}
+	if len(o.SignalNames) == 0 {
+		return true
+	}
+	for _, name := range o.SignalNames {
+		if name == signalName {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *workflowResetterImpl) getPaginationFn(
 	ctx context.Context,
 	firstEventID int64,
@@ -473,6 +473,7 @@ func (s *workflowResetterSuite) TestReapplyContinueAsNewWorkflowEvents() {
 		baseBranchToken,
 		baseFirstEventID,
 		baseNextEventID,
+		ReapplyOptions{},
 	)
 	s.NoError(err)
 }
@@ -533,6 +534,7 @@ func (s *workflowResetterSuite) TestReapplyWorkflowEvents() {
 		firstEventID,
 		nextEventID,
 		branchToken,
+		ReapplyOptions{},
 	)
 	s.NoError(err)
 	s.Equal(newRunID, nextRunID)
@@ -636,10 +638,72 @@ func (s *workflowResetterSuite) TestReapplyEvents() {
 		}
 	}
 
-	err := s.workflowResetter.reapplyEvents(mutableState, events)
+	err := s.workflowResetter.reapplyEvents(mutableState, events, ReapplyOptions{})
 	s.NoError(err)
 }
 
+func (s *workflowResetterSuite) TestReapplyEvents_FilterSignals() {
+	newSignalEvent := func(eventID int64, signalName string) *types.HistoryEvent {
+		return &types.HistoryEvent{
+			ID:        eventID,
+			EventType: types.EventTypeWorkflowExecutionSignaled.Ptr(),
+			WorkflowExecutionSignaledEventAttributes: &types.WorkflowExecutionSignaledEventAttributes{
+				SignalName: signalName,
+				Input:      []byte("some random signal input"),
+				Identity:   "some random signal identity",
+			},
+		}
+	}
+	events := []*types.HistoryEvent{
+		newSignalEvent(101, "signal-a"),
+		newSignalEvent(102, "signal-b"),
+		newSignalEvent(103, "signal-c"),
+	}
+
+	testCases := []struct {
+		options  ReapplyOptions
+		expected []string
+	}{
+		{
+			options:  ReapplyOptions{},
+			expected: []string{"signal-a", "signal-b", "signal-c"},
+		},
+		{
+			options:  ReapplyOptions{SkipSignals: true},
+			expected: nil,
+		},
+		{
+			options:  ReapplyOptions{SignalNames: []string{"signal-a", "signal-c"}},
+			expected: []string{"signal-a", "signal-c"},
+		},
+		{
+			options:  ReapplyOptions{ExcludeSignalNames: []string{"signal-b"}},
+			expected: []string{"signal-a", "signal-c"},
+		},
+		{
+			options: ReapplyOptions{
+				SignalNames:        []string{"signal-a", "signal-b"},
+				ExcludeSignalNames: []string{"signal-b"},
+			},
+			expected: []string{"signal-a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		mutableState := execution.NewMockMutableState(s.controller)
+		for _, signalName := range tc.expected {
+			mutableState.EXPECT().AddWorkflowExecutionSignaled(
+				signalName,
+				[]byte("some random signal input"),
+				"some random signal identity",
+			).Return(&types.HistoryEvent{}, nil).Times(1)
+		}
+
+		err := s.workflowResetter.reapplyEvents(mutableState, events, tc.options)
+		s.NoError(err)
+	}
+}
+
 func (s *workflowResetterSuite) TestPagination() {
 	firstEventID := common.FirstEventID
 	nextEventID := int64(101)
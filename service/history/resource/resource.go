@@ -24,6 +24,7 @@ import (
 	"sync/atomic"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/resource"
 	"github.com/uber/cadence/common/service"
@@ -31,6 +32,9 @@ import (
 	"github.com/uber/cadence/service/history/events"
 )
 
+// globalEventCacheName is the name of the events cache shared by all shards in the cache registry
+const globalEventCacheName = "history/events"
+
 // Resource is the interface which expose common history resources
 type Resource interface {
 	resource.Resource
@@ -56,6 +60,9 @@ func (h *resourceImpl) Start() {
 	}
 
 	h.Resource.Start()
+	if introspector, ok := h.eventCache.(cache.Introspector); ok {
+		h.GetCacheRegistry().Add(globalEventCacheName, introspector)
+	}
 	h.GetLogger().Info("history resource started", tag.LifeCycleStarted)
 }
 
@@ -70,6 +77,7 @@ func (h *resourceImpl) Stop() {
 		return
 	}
 
+	h.GetCacheRegistry().Remove(globalEventCacheName)
 	h.Resource.Stop()
 	h.GetLogger().Info("history resource stopped", tag.LifeCycleStopped)
 }
@@ -21,14 +21,18 @@
 package history
 
 import (
+	"context"
+	"fmt"
 	"sync/atomic"
 	"time"
 
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/health"
 	"github.com/uber/cadence/common/log/tag"
 	commonResource "github.com/uber/cadence/common/resource"
 	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/history/config"
 	"github.com/uber/cadence/service/history/resource"
 )
@@ -97,15 +101,32 @@ func (s *Service) Start() {
 	grpcHandler := newGRPCHandler(s.handler)
 	grpcHandler.register(s.GetDispatcher())
 
+	jsonHandler := newJSONHandler(s.handler)
+	jsonHandler.register(s.GetDispatcher())
+
 	// must start resource first
 	s.Resource.Start()
 	s.handler.Start()
+	s.params.HealthRegistry.Register(service.History, "shards", s.shardsHealthCheck)
 
 	logger.Info("history started")
 
 	<-s.stopC
 }
 
+// shardsHealthCheck reports the host as degraded while it doesn't own any shard, e.g. while shards are being
+// acquired after a restart, as requests can only be served for the workflows of the shards it owns
+func (s *Service) shardsHealthCheck(ctx context.Context) health.Result {
+	resp, err := s.handler.DescribeHistoryHost(ctx, &types.DescribeHistoryHostRequest{})
+	if err != nil {
+		return health.Result{Status: health.StatusDown, Message: err.Error()}
+	}
+	if resp.NumberOfShards == 0 {
+		return health.Result{Status: health.StatusDegraded, Message: "no shard acquired"}
+	}
+	return health.Result{Status: health.StatusUp, Message: fmt.Sprintf("%v shards acquired", resp.NumberOfShards)}
+}
+
 // Stop stops the service
 func (s *Service) Stop() {
 	if !atomic.CompareAndSwapInt32(&s.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
@@ -116,6 +137,7 @@ func (s *Service) Stop() {
 	// 1. remove self from the membership ring
 	// 2. wait for other members to discover we are going down
 	// 3. stop acquiring new shards (periodically or based on other membership changes)
+	//    and, if enabled, hand the shards off to their new owners with their ack levels persisted
 	// 4. wait for shard ownership to transfer (and inflight requests to drain) while still accepting new requests
 	// 5. Reject all requests arriving at rpc handler to avoid taking on more work except for RespondXXXCompleted and
 	//    RecordXXStarted APIs - for these APIs, most of the work is already one and rejecting at last stage is
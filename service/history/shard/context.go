@@ -74,6 +74,10 @@ type (
 		SetCurrentTime(cluster string, currentTime time.Time)
 		GetCurrentTime(cluster string) time.Time
 		GetLastUpdatedTime() time.Time
+		// FlushShardInfo persists the shard info, including the ack levels of the queues, even if it was recently persisted
+		FlushShardInfo() error
+		// DrainWrites rejects the writes started from now on, and waits for the in flight ones to finish
+		DrainWrites(ctx context.Context) error
 		GetTimerMaxReadLevel(cluster string) time.Time
 
 		GetTransferAckLevel() int64
@@ -150,6 +154,10 @@ type (
 		throttledLogger  log.Logger
 		engine           engine.Engine
 
+		// the writes hold the drain lock in read mode, so that a handoff can wait for them to finish
+		drainLock sync.RWMutex
+		draining  int32
+
 		sync.RWMutex
 		lastUpdated               time.Time
 		shardInfo                 *persistence.ShardInfo
@@ -585,6 +593,10 @@ func (s *contextImpl) CreateWorkflowExecution(
 	if s.isClosed() {
 		return nil, ErrShardClosed
 	}
+	if err := s.startWrite(); err != nil {
+		return nil, err
+	}
+	defer s.endWrite()
 
 	ctx, cancel, err := s.ensureMinContextTimeout(ctx)
 	if err != nil {
@@ -681,6 +693,10 @@ func (s *contextImpl) UpdateWorkflowExecution(
 	if s.isClosed() {
 		return nil, ErrShardClosed
 	}
+	if err := s.startWrite(); err != nil {
+		return nil, err
+	}
+	defer s.endWrite()
 	ctx, cancel, err := s.ensureMinContextTimeout(ctx)
 	if err != nil {
 		return nil, err
@@ -784,6 +800,10 @@ func (s *contextImpl) ConflictResolveWorkflowExecution(
 	if s.isClosed() {
 		return nil, ErrShardClosed
 	}
+	if err := s.startWrite(); err != nil {
+		return nil, err
+	}
+	defer s.endWrite()
 
 	ctx, cancel, err := s.ensureMinContextTimeout(ctx)
 	if err != nil {
@@ -920,6 +940,10 @@ func (s *contextImpl) AppendHistoryV2Events(
 	if s.isClosed() {
 		return nil, ErrShardClosed
 	}
+	if err := s.startWrite(); err != nil {
+		return nil, err
+	}
+	defer s.endWrite()
 
 	domainName, err := s.GetDomainCache().GetDomainName(domainID)
 	if err != nil {
@@ -988,6 +1012,40 @@ func (s *contextImpl) isClosed() bool {
 	return atomic.LoadInt32(&s.closed) != 0
 }
 
+func (s *contextImpl) DrainWrites(ctx context.Context) error {
+	drainedCh := make(chan struct{})
+	go func() {
+		s.drainLock.Lock()
+		atomic.StoreInt32(&s.draining, 1)
+		s.drainLock.Unlock()
+		close(drainedCh)
+	}()
+
+	select {
+	case <-drainedCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *contextImpl) startWrite() error {
+	s.drainLock.RLock()
+	if atomic.LoadInt32(&s.draining) != 0 {
+		s.drainLock.RUnlock()
+		// the shard is being handed off, the caller is redirected to the new owner
+		return &persistence.ShardOwnershipLostError{
+			ShardID: s.shardID,
+			Msg:     fmt.Sprintf("Shard %v is being handed off", s.shardID),
+		}
+	}
+	return nil
+}
+
+func (s *contextImpl) endWrite() {
+	s.drainLock.RUnlock()
+}
+
 func (s *contextImpl) closeShard() {
 	if !atomic.CompareAndSwapInt32(&s.closed, 0, 1) {
 		return
@@ -1085,6 +1143,13 @@ func (s *contextImpl) updateShardInfoLocked() error {
 	return s.persistShardInfoLocked(false)
 }
 
+func (s *contextImpl) FlushShardInfo() error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.forceUpdateShardInfoLocked()
+}
+
 func (s *contextImpl) forceUpdateShardInfoLocked() error {
 	return s.persistShardInfoLocked(true)
 }
@@ -147,6 +147,37 @@ func (s *contextTestSuite) TestRenewRangeLockedRetriesExceeded() {
 	s.Error(err)
 }
 
+func (s *contextTestSuite) TestDrainWrites() {
+	domainID := "test-domain-id"
+	s.mockResource.DomainCache.EXPECT().GetDomainName(domainID).Return("test-domain", nil).Times(1)
+	appendStartedCh := make(chan struct{})
+	appendReleaseCh := make(chan struct{})
+	s.mockResource.HistoryMgr.On("AppendHistoryNodes", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		close(appendStartedCh)
+		<-appendReleaseCh
+	}).Return(&persistence.AppendHistoryNodesResponse{}, nil).Once()
+
+	appendDoneCh := make(chan error, 1)
+	go func() {
+		_, err := s.context.AppendHistoryV2Events(context.Background(), &persistence.AppendHistoryNodesRequest{}, domainID, types.WorkflowExecution{})
+		appendDoneCh <- err
+	}()
+	<-appendStartedCh
+
+	// the in flight write doesn't finish before the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	s.Equal(context.DeadlineExceeded, s.context.DrainWrites(ctx))
+
+	close(appendReleaseCh)
+	s.NoError(<-appendDoneCh)
+	s.NoError(s.context.DrainWrites(context.Background()))
+
+	// the writes started after the drain are redirected to the new owner
+	_, err := s.context.AppendHistoryV2Events(context.Background(), &persistence.AppendHistoryNodesRequest{}, domainID, types.WorkflowExecution{})
+	s.IsType(&persistence.ShardOwnershipLostError{}, err)
+}
+
 func (s *contextTestSuite) TestReplicateFailoverMarkersSuccess() {
 	s.mockResource.ExecutionMgr.On("CreateFailoverMarkerTasks", mock.Anything, mock.Anything).Once().Return(nil)
 
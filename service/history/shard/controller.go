@@ -23,6 +23,7 @@
 package shard
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -32,6 +33,7 @@ import (
 
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/membership"
@@ -49,6 +51,9 @@ const (
 
 var (
 	errShardIDOutOfBoundary = &workflow.BadRequestError{Message: "shard ID is out of boundary"}
+	errShardPaused          = &types.ServiceBusyError{Message: "shard is paused by a resharding"}
+
+	shardHandoffRetryPolicy = backoff.NewExponentialRetryPolicy(100 * time.Millisecond)
 )
 
 type (
@@ -63,6 +68,8 @@ type (
 
 		// PrepareToStop starts the graceful shutdown process for controller
 		PrepareToStop()
+		// HandoffShards hands the shards off to their new owners, once this host is evicted from the ring
+		HandoffShards(ctx context.Context)
 
 		GetEngine(workflowID string) (engine.Engine, error)
 		GetEngineForShard(shardID int) (engine.Engine, error)
@@ -73,6 +80,7 @@ type (
 		Status() int32
 		NumShards() int
 		ShardIDs() []int32
+		DescribeOwnership() *types.DescribeShardOwnershipResponse
 	}
 
 	controller struct {
@@ -91,6 +99,9 @@ type (
 
 		sync.RWMutex
 		historyShards map[int]*historyShardsItem
+
+		ownershipLock    sync.Mutex
+		ownershipHistory map[int]*shardOwnershipHistory
 	}
 
 	historyShardsItemStatus int
@@ -105,8 +116,9 @@ type (
 		engineFactory   EngineFactory
 
 		sync.RWMutex
-		status historyShardsItemStatus
-		engine engine.Engine
+		status  historyShardsItemStatus
+		engine  engine.Engine
+		context Context
 	}
 )
 
@@ -129,6 +141,7 @@ func NewShardController(
 		membershipUpdateCh: make(chan *membership.ChangedEvent, 10),
 		engineFactory:      factory,
 		historyShards:      make(map[int]*historyShardsItem),
+		ownershipHistory:   make(map[int]*shardOwnershipHistory),
 		shutdownCh:         make(chan struct{}),
 		logger:             resource.GetLogger().WithTags(tag.ComponentShardController, tag.Address(hostAddress)),
 		throttledLogger:    resource.GetThrottledLogger().WithTags(tag.ComponentShardController, tag.Address(hostAddress)),
@@ -196,8 +209,95 @@ func (c *controller) PrepareToStop() {
 	atomic.StoreInt32(&c.shuttingDown, 1)
 }
 
+// HandoffShards closes the shards whose new owner is another host, and asks the new owner to load each of them,
+// so that it doesn't have to wait for this host to shut down to acquire them. The in flight writes of a shard
+// are drained and the ack levels of its queues are persisted before closing it, so that the new owner doesn't
+// process again the tasks processed here. At most ShardHandoffMaxConcurrency shards are handed off at once.
+func (c *controller) HandoffShards(ctx context.Context) {
+	c.RLock()
+	shardIDs := make([]int, 0, len(c.historyShards))
+	for shardID := range c.historyShards {
+		shardIDs = append(shardIDs, shardID)
+	}
+	c.RUnlock()
+
+	concurrency := common.MaxInt(c.config.ShardHandoffMaxConcurrency(), 1)
+	shardIDCh := make(chan int, concurrency)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for shardID := range shardIDCh {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := c.handoffShard(ctx, shardID); err != nil {
+					c.metricsScope.IncCounter(metrics.ShardHandoffFailedCounter)
+					c.logger.Warn("Failed to hand shard off", tag.Error(err), tag.ShardID(shardID))
+				}
+			}
+		}()
+	}
+	for _, shardID := range shardIDs {
+		shardIDCh <- shardID
+	}
+	close(shardIDCh)
+	wg.Wait()
+}
+
+func (c *controller) handoffShard(ctx context.Context, shardID int) error {
+	info, err := c.GetMembershipResolver().Lookup(service.History, string(rune(shardID)))
+	if err != nil {
+		return err
+	}
+	if info.Identity() == c.GetHostInfo().Identity() {
+		// the ring doesn't know this host is evicted yet, the shard is closed on shutdown
+		return nil
+	}
+
+	c.RLock()
+	shardItem, ok := c.historyShards[shardID]
+	c.RUnlock()
+	if !ok {
+		return nil
+	}
+	if _, err := c.removeHistoryShardItem(shardID, shardItem); err != nil {
+		// the shard was closed meanwhile
+		return nil
+	}
+	sw := c.metricsScope.StartTimer(metrics.ShardHandoffDrainLatency)
+	if err := shardItem.drainWrites(ctx); err != nil {
+		// the writes still in flight fail once the new owner acquires the shard
+		c.logger.Warn("Failed to drain the writes of the shard before handing it off", tag.Error(err), tag.ShardID(shardID))
+	}
+	sw.Stop()
+	if err := shardItem.stopEngineAndFlush(); err != nil {
+		return err
+	}
+	c.metricsScope.IncCounter(metrics.ShardHandoffCounter)
+
+	// describing a queue of the shard makes its new owner acquire it and start its engine
+	throttleRetry := backoff.NewThrottleRetry(
+		backoff.WithRetryPolicy(shardHandoffRetryPolicy),
+		backoff.WithRetryableError(func(err error) bool {
+			// the new owner may not know this host is evicted yet
+			_, ok := err.(*types.ShardOwnershipLostError)
+			return ok
+		}),
+	)
+	return throttleRetry.Do(ctx, func() error {
+		_, err := c.GetHistoryClient().DescribeQueue(ctx, &types.DescribeQueueRequest{
+			ShardID:     int32(shardID),
+			ClusterName: c.GetClusterMetadata().GetCurrentClusterName(),
+			Type:        common.Int32Ptr(int32(common.TaskTypeTransfer)),
+		})
+		return err
+	})
+}
+
 func (c *controller) GetEngine(workflowID string) (engine.Engine, error) {
-	shardID := c.config.GetShardID(workflowID)
+	shardID := c.GetHistoryShardRouter().WorkflowIDToShard(workflowID)
 	return c.GetEngineForShard(shardID)
 }
 
@@ -208,7 +308,7 @@ func (c *controller) GetEngineForShard(shardID int) (engine.Engine, error) {
 	if err != nil {
 		return nil, err
 	}
-	return item.getOrCreateEngine(c.shardClosedCallback)
+	return item.getOrCreateEngine(c.recordShardAcquired, c.shardClosedCallback)
 }
 
 func (c *controller) RemoveEngineForShard(shardID int) {
@@ -258,16 +358,17 @@ func (c *controller) removeEngineForShard(shardID int, shardItem *historyShardsI
 func (c *controller) shardClosedCallback(shardID int, shardItem *historyShardsItem) {
 	c.metricsScope.IncCounter(metrics.ShardClosedCounter)
 	c.logger.Info("Shard controller state changed", tag.LifeCycleStopping, tag.ComponentShard, tag.ShardID(shardID))
+	c.recordShardLost(shardID)
 	c.removeEngineForShard(shardID, shardItem)
 }
 
 func (c *controller) getOrCreateHistoryShardItem(shardID int) (*historyShardsItem, error) {
-	if shardID >= c.config.NumberOfShards || shardID < 0 { // zero based shard ID
-		c.logger.Error(fmt.Sprintf("Received shard ID: %v is larger than supported shard number %v",
-			shardID,
-			c.config.NumberOfShards,
-		),
-		)
+	if !c.GetHistoryShardRouter().IsShardActive(shardID) {
+		if shardID >= 0 && shardID < c.config.NumberOfShards {
+			// the shard is paused while its workflows are moved by a resharding, the request can be retried
+			return nil, errShardPaused
+		}
+		c.logger.Error(fmt.Sprintf("Received shard ID: %v is not an active shard", shardID))
 		return nil, errShardIDOutOfBoundary
 	}
 
@@ -352,6 +453,7 @@ func (c *controller) removeHistoryShardItem(shardID int, shardItem *historyShard
 //	a. Ring membership change
 //	b. Periodic ticker
 //	c. ShardOwnershipLostError and subsequent ShardClosedEvents from engine
+//	d. Resharding state change
 func (c *controller) shardManagementPump() {
 
 	defer c.shutdownWG.Done()
@@ -367,6 +469,9 @@ func (c *controller) shardManagementPump() {
 			return
 		case <-acquireTicker.C:
 			c.acquireShards()
+		case <-c.GetHistoryShardRouter().UpdatedCh():
+			c.logger.Info("Resharding state changed")
+			c.acquireShards()
 		case changedEvent := <-c.membershipUpdateCh:
 			c.metricsScope.IncCounter(metrics.MembershipChangedCounter)
 
@@ -412,7 +517,8 @@ func (c *controller) acquireShards() {
 		}()
 	}
 	// Submit tasks to the channel.
-	for shardID := 0; shardID < c.config.NumberOfShards; shardID++ {
+	shardIDs := c.GetHistoryShardRouter().ShardIDs()
+	for _, shardID := range shardIDs {
 		shardActionCh <- shardID
 		if c.isShuttingDown() {
 			return
@@ -421,8 +527,36 @@ func (c *controller) acquireShards() {
 	close(shardActionCh)
 	// Wait until all shards are processed.
 	wg.Wait()
+	c.releaseInactiveShards()
 
 	c.metricsScope.UpdateGauge(metrics.NumShardsGauge, float64(c.NumShards()))
+	if numHosts, err := c.GetMembershipResolver().MemberCount(service.History); err == nil {
+		c.metricsScope.UpdateGauge(metrics.ShardImbalanceGauge, shardImbalance(c.NumShards(), len(shardIDs), numHosts))
+	}
+}
+
+// releaseInactiveShards closes the shards paused by a resharding, the ack levels of their queues are persisted
+// so that the tasks left are the only ones moved with the workflows of the shards
+func (c *controller) releaseInactiveShards() {
+	c.RLock()
+	shardItems := make(map[int]*historyShardsItem)
+	for shardID, shardItem := range c.historyShards {
+		if !c.GetHistoryShardRouter().IsShardActive(shardID) {
+			shardItems[shardID] = shardItem
+		}
+	}
+	c.RUnlock()
+
+	for shardID, shardItem := range shardItems {
+		if _, err := c.removeHistoryShardItem(shardID, shardItem); err != nil {
+			// the shard was closed meanwhile
+			continue
+		}
+		if err := shardItem.stopEngineAndFlush(); err != nil {
+			c.logger.Warn("Failed to flush paused shard", tag.Error(err), tag.ShardID(shardID))
+		}
+		c.logger.Info("Shard released for resharding", tag.ShardID(shardID))
+	}
 }
 
 func (c *controller) doShutdown() {
@@ -440,6 +574,7 @@ func (c *controller) isShuttingDown() bool {
 }
 
 func (i *historyShardsItem) getOrCreateEngine(
+	acquiredCallback func(shardID int, stolen bool),
 	closeCallback func(int, *historyShardsItem),
 ) (engine.Engine, error) {
 	i.RLock()
@@ -466,10 +601,12 @@ func (i *historyShardsItem) getOrCreateEngine(
 			i.GetMetricsClient().RecordTimer(metrics.ShardInfoScope, metrics.ShardItemAcquisitionLatency,
 				context.GetCurrentTime(i.GetClusterMetadata().GetCurrentClusterName()).Sub(context.GetLastUpdatedTime()))
 		}
+		i.context = context
 		i.engine = i.engineFactory.CreateEngine(context)
 		i.engine.Start()
 		i.logger.Info("Shard engine state changed", tag.LifeCycleStarted, tag.ComponentShardEngine)
 		i.status = historyShardsItemStatusStarted
+		acquiredCallback(i.shardID, context.PreviousShardOwnerWasDifferent())
 		return i.engine, nil
 	case historyShardsItemStatusStarted:
 		return i.engine, nil
@@ -491,6 +628,7 @@ func (i *historyShardsItem) stopEngine() {
 		i.logger.Info("Shard engine state changed", tag.LifeCycleStopping, tag.ComponentShardEngine)
 		i.engine.Stop()
 		i.engine = nil
+		i.context = nil
 		i.logger.Info("Shard engine state changed", tag.LifeCycleStopped, tag.ComponentShardEngine)
 		i.status = historyShardsItemStatusStopped
 	case historyShardsItemStatusStopped:
@@ -500,6 +638,31 @@ func (i *historyShardsItem) stopEngine() {
 	}
 }
 
+// drainWrites rejects the new writes to the shard, and waits for the in flight ones to finish
+func (i *historyShardsItem) drainWrites(ctx context.Context) error {
+	i.RLock()
+	shardContext := i.context
+	i.RUnlock()
+
+	if shardContext == nil {
+		return nil
+	}
+	return shardContext.DrainWrites(ctx)
+}
+
+// stopEngineAndFlush stops the engine, then persists the shard info with the ack levels of its stopped queues
+func (i *historyShardsItem) stopEngineAndFlush() error {
+	i.RLock()
+	shardContext := i.context
+	i.RUnlock()
+
+	i.stopEngine()
+	if shardContext == nil {
+		return nil
+	}
+	return shardContext.FlushShardInfo()
+}
+
 func (i *historyShardsItem) isValid() bool {
 	i.RLock()
 	defer i.RUnlock()
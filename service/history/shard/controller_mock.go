@@ -27,10 +27,12 @@
 package shard
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
 
+	types "github.com/uber/cadence/common/types"
 	engine "github.com/uber/cadence/service/history/engine"
 )
 
@@ -94,6 +96,20 @@ func (m *MockController) EXPECT() *MockControllerMockRecorder {
 	return m.recorder
 }
 
+// DescribeOwnership mocks base method.
+func (m *MockController) DescribeOwnership() *types.DescribeShardOwnershipResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeOwnership")
+	ret0, _ := ret[0].(*types.DescribeShardOwnershipResponse)
+	return ret0
+}
+
+// DescribeOwnership indicates an expected call of DescribeOwnership.
+func (mr *MockControllerMockRecorder) DescribeOwnership() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeOwnership", reflect.TypeOf((*MockController)(nil).DescribeOwnership))
+}
+
 // GetEngine mocks base method.
 func (m *MockController) GetEngine(workflowID string) (engine.Engine, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +140,18 @@ func (mr *MockControllerMockRecorder) GetEngineForShard(shardID interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEngineForShard", reflect.TypeOf((*MockController)(nil).GetEngineForShard), shardID)
 }
 
+// HandoffShards mocks base method.
+func (m *MockController) HandoffShards(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HandoffShards", ctx)
+}
+
+// HandoffShards indicates an expected call of HandoffShards.
+func (mr *MockControllerMockRecorder) HandoffShards(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandoffShards", reflect.TypeOf((*MockController)(nil).HandoffShards), ctx)
+}
+
 // NumShards mocks base method.
 func (m *MockController) NumShards() int {
 	m.ctrl.T.Helper()
@@ -21,6 +21,7 @@
 package shard
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -34,6 +35,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log"
@@ -42,6 +44,7 @@ import (
 	"github.com/uber/cadence/common/metrics"
 	mmocks "github.com/uber/cadence/common/mocks"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/resharding"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/history/config"
 	"github.com/uber/cadence/service/history/engine"
@@ -100,6 +103,7 @@ func (s *controllerSuite) TearDownTest() {
 func (s *controllerSuite) TestAcquireShardSuccess() {
 	numShards := 10
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = resharding.NewStaticRouter(numShards)
 
 	replicationAck := int64(201)
 	currentClusterTransferAck := int64(210)
@@ -172,6 +176,7 @@ func (s *controllerSuite) TestAcquireShardSuccess() {
 		}
 	}
 
+	s.mockMembershipResolver.EXPECT().MemberCount(service.History).Return(4, nil).Times(1)
 	s.shardController.acquireShards()
 	count := 0
 	for _, shardID := range myShards {
@@ -184,6 +189,7 @@ func (s *controllerSuite) TestAcquireShardSuccess() {
 func (s *controllerSuite) TestAcquireShardsConcurrently() {
 	numShards := 10
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = resharding.NewStaticRouter(numShards)
 	s.config.AcquireShardConcurrency = func(opts ...dynamicconfig.FilterOption) int {
 		return 10
 	}
@@ -259,6 +265,7 @@ func (s *controllerSuite) TestAcquireShardsConcurrently() {
 		}
 	}
 
+	s.mockMembershipResolver.EXPECT().MemberCount(service.History).Return(4, nil).Times(1)
 	s.shardController.acquireShards()
 	count := 0
 	for _, shardID := range myShards {
@@ -271,10 +278,12 @@ func (s *controllerSuite) TestAcquireShardsConcurrently() {
 func (s *controllerSuite) TestAcquireShardLookupFailure() {
 	numShards := 2
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = resharding.NewStaticRouter(numShards)
 	for shardID := 0; shardID < numShards; shardID++ {
 		s.mockMembershipResolver.EXPECT().Lookup(service.History, string(rune(shardID))).Return(membership.HostInfo{}, errors.New("ring failure")).Times(1)
 	}
 
+	s.mockMembershipResolver.EXPECT().MemberCount(service.History).Return(0, errors.New("ring failure")).Times(1)
 	s.shardController.acquireShards()
 	for shardID := 0; shardID < numShards; shardID++ {
 		s.mockMembershipResolver.EXPECT().Lookup(service.History, string(rune(shardID))).Return(membership.HostInfo{}, errors.New("ring failure")).Times(1)
@@ -285,6 +294,7 @@ func (s *controllerSuite) TestAcquireShardLookupFailure() {
 func (s *controllerSuite) TestAcquireShardRenewSuccess() {
 	numShards := 2
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = resharding.NewStaticRouter(numShards)
 
 	replicationAck := int64(201)
 	currentClusterTransferAck := int64(210)
@@ -349,6 +359,7 @@ func (s *controllerSuite) TestAcquireShardRenewSuccess() {
 		}).Return(nil).Once()
 	}
 
+	s.mockMembershipResolver.EXPECT().MemberCount(service.History).Return(1, nil).Times(2)
 	s.shardController.acquireShards()
 
 	for shardID := 0; shardID < numShards; shardID++ {
@@ -364,6 +375,7 @@ func (s *controllerSuite) TestAcquireShardRenewSuccess() {
 func (s *controllerSuite) TestAcquireShardRenewLookupFailed() {
 	numShards := 2
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = resharding.NewStaticRouter(numShards)
 
 	replicationAck := int64(201)
 	currentClusterTransferAck := int64(210)
@@ -428,6 +440,7 @@ func (s *controllerSuite) TestAcquireShardRenewLookupFailed() {
 		}).Return(nil).Once()
 	}
 
+	s.mockMembershipResolver.EXPECT().MemberCount(service.History).Return(1, nil).Times(2)
 	s.shardController.acquireShards()
 
 	for shardID := 0; shardID < numShards; shardID++ {
@@ -443,6 +456,7 @@ func (s *controllerSuite) TestAcquireShardRenewLookupFailed() {
 func (s *controllerSuite) TestHistoryEngineClosed() {
 	numShards := 4
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = resharding.NewStaticRouter(numShards)
 	s.shardController = NewShardController(s.mockResource, s.mockEngineFactory, s.config).(*controller)
 	historyEngines := make(map[int]*engine.MockEngine)
 	for shardID := 0; shardID < numShards; shardID++ {
@@ -453,6 +467,7 @@ func (s *controllerSuite) TestHistoryEngineClosed() {
 
 	s.mockMembershipResolver.EXPECT().Subscribe(service.History, shardControllerMembershipUpdateListenerName,
 		gomock.Any()).Return(nil).AnyTimes()
+	s.mockMembershipResolver.EXPECT().MemberCount(service.History).Return(1, nil).AnyTimes()
 	s.shardController.Start()
 	var workerWG sync.WaitGroup
 	for w := 0; w < 10; w++ {
@@ -528,6 +543,7 @@ func (s *controllerSuite) TestHistoryEngineClosed() {
 func (s *controllerSuite) TestShardControllerClosed() {
 	numShards := 4
 	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = resharding.NewStaticRouter(numShards)
 	s.shardController = NewShardController(s.mockResource, s.mockEngineFactory, s.config).(*controller)
 	historyEngines := make(map[int]*engine.MockEngine)
 	for shardID := 0; shardID < numShards; shardID++ {
@@ -537,6 +553,7 @@ func (s *controllerSuite) TestShardControllerClosed() {
 	}
 
 	s.mockMembershipResolver.EXPECT().Subscribe(service.History, shardControllerMembershipUpdateListenerName, gomock.Any()).Return(nil).AnyTimes()
+	s.mockMembershipResolver.EXPECT().MemberCount(service.History).Return(1, nil).AnyTimes()
 	s.shardController.Start()
 
 	var workerWG sync.WaitGroup
@@ -570,8 +587,48 @@ func (s *controllerSuite) TestShardControllerClosed() {
 	workerWG.Wait()
 }
 
+func (s *controllerSuite) TestHandoffShards() {
+	numShards := 2
+	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = resharding.NewStaticRouter(numShards)
+	s.shardController = NewShardController(s.mockResource, s.mockEngineFactory, s.config).(*controller)
+	historyEngines := make(map[int]*engine.MockEngine)
+	for shardID := 0; shardID < numShards; shardID++ {
+		mockEngine := engine.NewMockEngine(s.controller)
+		historyEngines[shardID] = mockEngine
+		s.setupMocksForAcquireShard(shardID, mockEngine, 5, 6)
+	}
+	s.mockMembershipResolver.EXPECT().MemberCount(service.History).Return(1, nil).AnyTimes()
+	s.shardController.acquireShards()
+	s.Equal(numShards, s.shardController.NumShards())
+
+	// shard 0 is owned by another host once this host is evicted, the ring doesn't know it for shard 1 yet
+	otherHost := membership.NewHostInfo("other-host:7934")
+	s.mockMembershipResolver.EXPECT().Lookup(service.History, string(rune(0))).Return(otherHost, nil).Times(1)
+	s.mockMembershipResolver.EXPECT().Lookup(service.History, string(rune(1))).Return(s.hostInfo, nil).Times(1)
+	historyEngines[0].EXPECT().Stop().Times(1)
+	s.mockShardManager.On("UpdateShard", mock.Anything, mock.MatchedBy(func(request *persistence.UpdateShardRequest) bool {
+		return request.ShardInfo.ShardID == 0 && request.PreviousRangeID == 6
+	})).Return(nil).Once()
+	describeRequest := &types.DescribeQueueRequest{
+		ShardID:     0,
+		ClusterName: cluster.TestCurrentClusterName,
+		Type:        common.Int32Ptr(int32(common.TaskTypeTransfer)),
+	}
+	gomock.InOrder(
+		s.mockResource.HistoryClient.EXPECT().DescribeQueue(gomock.Any(), describeRequest).Return(nil, &types.ShardOwnershipLostError{}).Times(1),
+		s.mockResource.HistoryClient.EXPECT().DescribeQueue(gomock.Any(), describeRequest).Return(&types.DescribeQueueResponse{}, nil).Times(1),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.shardController.HandoffShards(ctx)
+	s.Equal([]int32{1}, s.shardController.ShardIDs())
+}
+
 func (s *controllerSuite) TestGetOrCreateHistoryShardItem_InvalidShardID_Error() {
 	s.config.NumberOfShards = 4
+	s.mockResource.HistoryShardRouter = resharding.NewStaticRouter(4)
 	s.shardController = NewShardController(s.mockResource, s.mockEngineFactory, s.config).(*controller)
 
 	eng, err := s.shardController.GetEngineForShard(-1)
@@ -583,6 +640,31 @@ func (s *controllerSuite) TestGetOrCreateHistoryShardItem_InvalidShardID_Error()
 	s.Error(err)
 }
 
+func (s *controllerSuite) TestGetOrCreateHistoryShardItem_PausedShard_Error() {
+	s.config.NumberOfShards = 4
+	configStore := persistence.NewMockConfigStoreManager(s.controller)
+	configStore.EXPECT().FetchResharding(gomock.Any()).Return(&persistence.FetchReshardingResponse{
+		Snapshot: &persistence.ReshardingSnapshot{
+			Version:        1,
+			FromShardCount: 4,
+			ToShardCount:   8,
+			SplitShards:    []int{0},
+			PausedShards:   []int{1},
+		},
+	}, nil)
+	router := resharding.NewRouter(4, configStore, dynamicconfig.GetDurationPropertyFn(time.Hour), s.logger)
+	router.Start()
+	defer router.Stop()
+	s.mockResource.HistoryShardRouter = router
+	s.shardController = NewShardController(s.mockResource, s.mockEngineFactory, s.config).(*controller)
+
+	_, err := s.shardController.GetEngineForShard(1)
+	s.IsType(&types.ServiceBusyError{}, err)
+
+	_, err = s.shardController.GetEngineForShard(5)
+	s.Equal(errShardIDOutOfBoundary, err)
+}
+
 func (s *controllerSuite) setupMocksForAcquireShard(shardID int, mockEngine *engine.MockEngine, currentRangeID,
 	newRangeID int64) {
 
@@ -0,0 +1,150 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package shard
+
+import (
+	"sort"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/types"
+)
+
+// shardStealWindow is the duration for which steals of a shard are counted as recent
+const shardStealWindow = time.Hour
+
+const unknownShardOwner = "unknown"
+
+// shardOwnershipHistory records the acquisitions and losses of a shard by the host
+type shardOwnershipHistory struct {
+	acquiredAt time.Time
+	owned      bool
+	steals     []time.Time
+}
+
+// DescribeOwnership looks up the owners of all shards from the membership ring, the shards paused by a resharding
+// aren't owned by any host
+func (c *controller) DescribeOwnership() *types.DescribeShardOwnershipResponse {
+	now := c.GetTimeSource().Now()
+	host := c.GetHostInfo().Identity()
+	shardIDs := c.GetHistoryShardRouter().ShardIDs()
+	ownership := &types.DescribeShardOwnershipResponse{
+		NumberOfShards: int32(len(shardIDs)),
+		Host:           host,
+		Shards:         make([]*types.ShardOwnership, 0, len(shardIDs)),
+	}
+
+	c.ownershipLock.Lock()
+	defer c.ownershipLock.Unlock()
+
+	shardsByHost := make(map[string]int)
+	for _, shardID := range shardIDs {
+		shard := &types.ShardOwnership{ShardID: int32(shardID), Owner: unknownShardOwner}
+		if info, err := c.GetMembershipResolver().Lookup(service.History, string(rune(shardID))); err == nil {
+			shard.Owner = info.Identity()
+			shardsByHost[shard.Owner]++
+		}
+		if history, ok := c.ownershipHistory[shardID]; ok {
+			shard.RecentSteals = int32(history.recentSteals(now))
+			if history.owned {
+				shard.AcquiredAt = common.Int64Ptr(history.acquiredAt.UnixNano())
+			}
+		}
+		ownership.Shards = append(ownership.Shards, shard)
+	}
+
+	for owner, numShards := range shardsByHost {
+		ownership.Hosts = append(ownership.Hosts, &types.HostShardOwnership{
+			Host:      owner,
+			NumShards: int32(numShards),
+			Imbalance: shardImbalance(numShards, len(shardIDs), len(shardsByHost)),
+		})
+	}
+	sort.Slice(ownership.Hosts, func(i, j int) bool {
+		return ownership.Hosts[i].Host < ownership.Hosts[j].Host
+	})
+	return ownership
+}
+
+// recordShardAcquired is called once the host acquired a shard
+func (c *controller) recordShardAcquired(shardID int, stolen bool) {
+	now := c.GetTimeSource().Now()
+	c.ownershipLock.Lock()
+	defer c.ownershipLock.Unlock()
+
+	history := c.getOrCreateOwnershipHistoryLocked(shardID)
+	history.acquiredAt = now
+	history.owned = true
+	if stolen {
+		history.addSteal(now)
+		c.metricsScope.IncCounter(metrics.ShardStolenCounter)
+	}
+}
+
+// recordShardLost is called once the host closed a shard after its ownership was lost
+func (c *controller) recordShardLost(shardID int) {
+	now := c.GetTimeSource().Now()
+	c.ownershipLock.Lock()
+	defer c.ownershipLock.Unlock()
+
+	history := c.getOrCreateOwnershipHistoryLocked(shardID)
+	if history.owned {
+		history.owned = false
+		history.addSteal(now)
+	}
+}
+
+func (c *controller) getOrCreateOwnershipHistoryLocked(shardID int) *shardOwnershipHistory {
+	history, ok := c.ownershipHistory[shardID]
+	if !ok {
+		history = &shardOwnershipHistory{}
+		c.ownershipHistory[shardID] = history
+	}
+	return history
+}
+
+func (h *shardOwnershipHistory) addSteal(now time.Time) {
+	h.steals = append(h.steals, now)
+	// drop the steals which are outside of the window to keep the history bounded
+	h.steals = h.steals[len(h.steals)-h.recentSteals(now):]
+}
+
+func (h *shardOwnershipHistory) recentSteals(now time.Time) int {
+	count := 0
+	for _, steal := range h.steals {
+		if now.Sub(steal) <= shardStealWindow {
+			count++
+		}
+	}
+	return count
+}
+
+// shardImbalance returns the ratio between the shards owned by a host and an even share of all shards
+func shardImbalance(numShards int, totalShards int, numHosts int) float64 {
+	if totalShards <= 0 || numHosts <= 0 {
+		return 0
+	}
+	return float64(numShards) * float64(numHosts) / float64(totalShards)
+}
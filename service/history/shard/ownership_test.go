@@ -0,0 +1,94 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package shard
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/resharding"
+	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/types"
+)
+
+func TestShardImbalance(t *testing.T) {
+	assert.Equal(t, 1.0, shardImbalance(4, 16, 4))
+	assert.Equal(t, 2.0, shardImbalance(8, 16, 4))
+	assert.Equal(t, 0.5, shardImbalance(2, 16, 4))
+	assert.Equal(t, 0.0, shardImbalance(2, 16, 0))
+	assert.Equal(t, 0.0, shardImbalance(2, 0, 4))
+}
+
+func TestShardOwnershipHistory_RecentSteals(t *testing.T) {
+	now := time.Now()
+	history := &shardOwnershipHistory{}
+	history.addSteal(now.Add(-2 * shardStealWindow))
+	history.addSteal(now.Add(-time.Minute))
+	assert.Equal(t, 1, history.recentSteals(now))
+
+	history.addSteal(now)
+	assert.Equal(t, 2, history.recentSteals(now))
+	assert.Len(t, history.steals, 2)
+	assert.Equal(t, 0, history.recentSteals(now.Add(2*shardStealWindow)))
+}
+
+func (s *controllerSuite) TestDescribeOwnership() {
+	numShards := 4
+	s.config.NumberOfShards = numShards
+	s.mockResource.HistoryShardRouter = resharding.NewStaticRouter(numShards)
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	s.mockResource.TimeSource = timeSource
+
+	otherHost := membership.NewHostInfo("other-host")
+	s.mockMembershipResolver.EXPECT().Lookup(service.History, string(rune(0))).Return(s.hostInfo, nil).Times(1)
+	s.mockMembershipResolver.EXPECT().Lookup(service.History, string(rune(1))).Return(s.hostInfo, nil).Times(1)
+	s.mockMembershipResolver.EXPECT().Lookup(service.History, string(rune(2))).Return(otherHost, nil).Times(1)
+	s.mockMembershipResolver.EXPECT().Lookup(service.History, string(rune(3))).Return(membership.HostInfo{}, errors.New("ring failure")).Times(1)
+
+	acquiredAt := timeSource.Now()
+	s.shardController.recordShardAcquired(0, false)
+	s.shardController.recordShardAcquired(1, true)
+	s.shardController.recordShardAcquired(2, true)
+	timeSource.Update(acquiredAt.Add(time.Minute))
+	s.shardController.recordShardLost(2)
+
+	ownership := s.shardController.DescribeOwnership()
+	s.Equal(int32(numShards), ownership.NumberOfShards)
+	s.Equal(s.hostInfo.Identity(), ownership.Host)
+	s.Equal([]*types.ShardOwnership{
+		{ShardID: 0, Owner: s.hostInfo.Identity(), AcquiredAt: common.Int64Ptr(acquiredAt.UnixNano())},
+		{ShardID: 1, Owner: s.hostInfo.Identity(), AcquiredAt: common.Int64Ptr(acquiredAt.UnixNano()), RecentSteals: 1},
+		{ShardID: 2, Owner: otherHost.Identity(), RecentSteals: 2},
+		{ShardID: 3, Owner: unknownShardOwner},
+	}, ownership.Shards)
+	s.ElementsMatch([]*types.HostShardOwnership{
+		{Host: s.hostInfo.Identity(), NumShards: 2, Imbalance: 1},
+		{Host: otherHost.Identity(), NumShards: 1, Imbalance: 0.5},
+	}, ownership.Hosts)
+}
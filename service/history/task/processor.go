@@ -27,9 +27,13 @@ import (
 	"sync/atomic"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/loadshedding"
 	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/quotas"
 	"github.com/uber/cadence/common/task"
 	"github.com/uber/cadence/service/history/config"
 	"github.com/uber/cadence/service/history/shard"
@@ -48,12 +52,26 @@ type (
 		priorityAssigner PriorityAssigner
 		hostScheduler    task.Scheduler
 		shardSchedulers  map[shard.Context]task.Scheduler
+		poolSchedulers   map[string]task.Scheduler
+		// fairScheduler replaces the shard schedulers when fair scheduling is enabled
+		fairScheduler task.Scheduler
 
 		status        int32
 		options       *schedulerOptions
 		shardOptions  *schedulerOptions
 		logger        log.Logger
 		metricsClient metrics.Client
+
+		loadMonitor       *loadshedding.Monitor
+		throttledTaskRate quotas.Limiter
+
+		config       *config.Config
+		resourcePool func(domainID string) string
+	}
+
+	fairSchedulingKey struct {
+		shardID  int
+		domainID string
 	}
 )
 
@@ -67,6 +85,8 @@ func NewProcessor(
 	config *config.Config,
 	logger log.Logger,
 	metricsClient metrics.Client,
+	loadMonitor *loadshedding.Monitor,
+	domainCache cache.DomainCache,
 ) (Processor, error) {
 	options, err := newSchedulerOptions(
 		config.TaskSchedulerType(),
@@ -98,15 +118,28 @@ func NewProcessor(
 		return nil, err
 	}
 
+	var fairScheduler task.Scheduler
+	if config.TaskSchedulerEnableFairScheduling() {
+		fairScheduler = createFairTaskScheduler(config, domainCache, logger, metricsClient)
+	}
+
 	return &processorImpl{
 		priorityAssigner: priorityAssigner,
 		hostScheduler:    scheduler,
+		fairScheduler:    fairScheduler,
 		shardSchedulers:  make(map[shard.Context]task.Scheduler),
+		poolSchedulers:   make(map[string]task.Scheduler),
 		status:           common.DaemonStatusInitialized,
-		options:          options,
-		shardOptions:     shardOptions,
-		logger:           logger,
-		metricsClient:    metricsClient,
+		loadMonitor:      loadMonitor,
+		throttledTaskRate: quotas.NewDynamicRateLimiter(func() float64 {
+			return float64(config.LoadSheddingTaskRPS())
+		}),
+		options:       options,
+		shardOptions:  shardOptions,
+		logger:        logger,
+		metricsClient: metricsClient,
+		config:        config,
+		resourcePool:  cache.ResourcePoolByDomainID(domainCache, config.DomainResourcePool),
 	}, nil
 }
 
@@ -116,6 +149,9 @@ func (p *processorImpl) Start() {
 	}
 
 	p.hostScheduler.Start()
+	if p.fairScheduler != nil {
+		p.fairScheduler.Start()
+	}
 
 	p.logger.Info("Queue task processor started.")
 }
@@ -126,6 +162,9 @@ func (p *processorImpl) Stop() {
 	}
 
 	p.hostScheduler.Stop()
+	if p.fairScheduler != nil {
+		p.fairScheduler.Stop()
+	}
 
 	p.Lock()
 	defer p.Unlock()
@@ -135,6 +174,11 @@ func (p *processorImpl) Stop() {
 		scheduler.Stop()
 	}
 
+	for pool, scheduler := range p.poolSchedulers {
+		delete(p.poolSchedulers, pool)
+		scheduler.Stop()
+	}
+
 	p.logger.Info("Queue task processor stopped.")
 }
 
@@ -162,6 +206,14 @@ func (p *processorImpl) Submit(
 		return err
 	}
 
+	if pool := p.resourcePool(task.GetDomainID()); pool != "" {
+		poolScheduler, err := p.getOrCreatePoolTaskScheduler(pool)
+		if err != nil {
+			return err
+		}
+		return poolScheduler.Submit(task)
+	}
+
 	submitted, err := p.hostScheduler.TrySubmit(task)
 	if err != nil {
 		return err
@@ -171,6 +223,10 @@ func (p *processorImpl) Submit(
 		return nil
 	}
 
+	if p.fairScheduler != nil {
+		return p.fairScheduler.Submit(task)
+	}
+
 	shardScheduler, err := p.getOrCreateShardTaskScheduler(task.GetShard())
 	if err != nil {
 		return err
@@ -191,6 +247,23 @@ func (p *processorImpl) TrySubmit(
 		return false, err
 	}
 
+	// when the host is overloaded, only let a trickle of tasks through;
+	// the rest are handed back to the caller and redispatched with backoff
+	if p.loadMonitor.ThrottleTasks() && !p.throttledTaskRate.Allow() {
+		p.metricsClient.IncCounter(metrics.LoadSheddingScope, metrics.LoadSheddingThrottledTaskCounter)
+		return false, nil
+	}
+
+	// the tasks of the domains pinned to a resource pool are only processed by the scheduler of their pool,
+	// so that they neither wait for nor hold the workers of the other domains
+	if pool := p.resourcePool(task.GetDomainID()); pool != "" {
+		poolScheduler, err := p.getOrCreatePoolTaskScheduler(pool)
+		if err != nil {
+			return false, err
+		}
+		return poolScheduler.TrySubmit(task)
+	}
+
 	submitted, err := p.hostScheduler.TrySubmit(task)
 	if err != nil {
 		return false, err
@@ -200,6 +273,12 @@ func (p *processorImpl) TrySubmit(
 		return true, nil
 	}
 
+	// the tasks which don't fit in the host scheduler wait in the fair scheduler, where a few busy shards or domains
+	// cannot take the workers of the others
+	if p.fairScheduler != nil {
+		return p.fairScheduler.TrySubmit(task)
+	}
+
 	shardScheduler, err := p.getOrCreateShardTaskScheduler(task.GetShard())
 	if err != nil {
 		return false, err
@@ -252,6 +331,58 @@ func (p *processorImpl) getOrCreateShardTaskScheduler(
 	return scheduler, nil
 }
 
+func (p *processorImpl) getOrCreatePoolTaskScheduler(
+	pool string,
+) (task.Scheduler, error) {
+	p.RLock()
+	if scheduler, ok := p.poolSchedulers[pool]; ok {
+		p.RUnlock()
+		return scheduler, nil
+	}
+	p.RUnlock()
+
+	p.Lock()
+	if scheduler, ok := p.poolSchedulers[pool]; ok {
+		p.Unlock()
+		return scheduler, nil
+	}
+
+	if !p.isRunning() {
+		p.Unlock()
+		return nil, errTaskProcessorNotRunning
+	}
+
+	options, err := newSchedulerOptions(
+		p.config.TaskSchedulerType(),
+		p.config.TaskSchedulerQueueSize(),
+		func(...dynamicconfig.FilterOption) int {
+			if workerCount := common.GetIntFromDynamicConfigMapProperty(p.config.ResourcePoolTaskWorkerCount(), pool); workerCount > 0 {
+				return workerCount
+			}
+			return p.config.TaskSchedulerWorkerCount()
+		},
+		p.config.TaskSchedulerDispatcherCount(),
+		p.config.TaskSchedulerRoundRobinWeights,
+	)
+	if err != nil {
+		p.Unlock()
+		return nil, err
+	}
+
+	scheduler, err := createTaskScheduler(options, p.logger.WithTags(tag.Name(pool)), p.metricsClient)
+	if err != nil {
+		p.Unlock()
+		return nil, err
+	}
+
+	p.poolSchedulers[pool] = scheduler
+	p.Unlock()
+
+	// don't hold the lock while starting the scheduler
+	scheduler.Start()
+	return scheduler, nil
+}
+
 func (p *processorImpl) isRunning() bool {
 	return atomic.LoadInt32(&p.status) == common.DaemonStatusStarted
 }
@@ -315,3 +446,37 @@ func createTaskScheduler(
 
 	return scheduler, err
 }
+
+func createFairTaskScheduler(
+	config *config.Config,
+	domainCache cache.DomainCache,
+	logger log.Logger,
+	metricsClient metrics.Client,
+) task.Scheduler {
+	return task.NewWeightedFairTaskScheduler(
+		logger,
+		metricsClient,
+		&task.WeightedFairTaskSchedulerOptions{
+			QueueSize:   config.TaskSchedulerShardQueueSize(),
+			WorkerCount: config.TaskSchedulerWorkerCount,
+			RetryPolicy: common.CreateTaskProcessingRetryPolicy(),
+			KeyFn: func(t task.PriorityTask) interface{} {
+				historyTask, ok := t.(Task)
+				if !ok {
+					return fairSchedulingKey{}
+				}
+				return fairSchedulingKey{
+					shardID:  historyTask.GetShard().GetShardID(),
+					domainID: historyTask.GetDomainID(),
+				}
+			},
+			WeightFn: func(key interface{}) int {
+				domainName, err := domainCache.GetDomainName(key.(fairSchedulingKey).domainID)
+				if err != nil {
+					return 1
+				}
+				return common.GetIntFromDynamicConfigMapProperty(config.TaskSchedulerDomainWeights(), domainName)
+			},
+		},
+	)
+}
@@ -23,6 +23,7 @@ package task
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -36,6 +37,7 @@ import (
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/task"
 	"github.com/uber/cadence/service/history/config"
+	"github.com/uber/cadence/service/history/constants"
 	"github.com/uber/cadence/service/history/shard"
 )
 
@@ -73,6 +75,7 @@ func (s *queueTaskProcessorSuite) SetupTest() {
 		config.NewForTest(),
 	)
 	s.mockPriorityAssigner = NewMockPriorityAssigner(s.controller)
+	s.mockShard.Resource.DomainCache.EXPECT().GetDomainName(constants.TestDomainID).Return(constants.TestDomainName, nil).AnyTimes()
 
 	s.metricsClient = metrics.NewClient(tally.NoopScope, metrics.History)
 	s.logger = loggerimpl.NewLoggerForTest(s.Suite)
@@ -162,6 +165,7 @@ func (s *queueTaskProcessorSuite) TestStartStop() {
 
 func (s *queueTaskProcessorSuite) TestSubmit() {
 	mockTask := NewMockTask(s.controller)
+	mockTask.EXPECT().GetDomainID().Return(constants.TestDomainID).AnyTimes()
 	mockTask.EXPECT().GetShard().Return(s.mockShard).Times(1)
 	s.mockPriorityAssigner.EXPECT().Assign(NewMockTaskMatcher(mockTask)).Return(nil).Times(1)
 
@@ -191,6 +195,7 @@ func (s *queueTaskProcessorSuite) TestTrySubmit_AssignPriorityFailed() {
 
 func (s *queueTaskProcessorSuite) TestTrySubmit_Fail() {
 	mockTask := NewMockTask(s.controller)
+	mockTask.EXPECT().GetDomainID().Return(constants.TestDomainID).AnyTimes()
 	s.mockPriorityAssigner.EXPECT().Assign(NewMockTaskMatcher(mockTask)).Return(nil).Times(1)
 
 	errTrySubmit := errors.New("some randome error")
@@ -204,6 +209,107 @@ func (s *queueTaskProcessorSuite) TestTrySubmit_Fail() {
 	s.False(submitted)
 }
 
+func (s *queueTaskProcessorSuite) TestSubmit_ResourcePool() {
+	s.processor.resourcePool = func(domainID string) string {
+		s.Equal(constants.TestDomainID, domainID)
+		return "pool-a"
+	}
+
+	mockTask := NewMockTask(s.controller)
+	mockTask.EXPECT().GetDomainID().Return(constants.TestDomainID).Times(2)
+	s.mockPriorityAssigner.EXPECT().Assign(NewMockTaskMatcher(mockTask)).Return(nil).Times(2)
+
+	// the host scheduler is not used for the domains of a pool
+	s.processor.hostScheduler = task.NewMockScheduler(s.controller)
+	mockPoolScheduler := task.NewMockScheduler(s.controller)
+	mockPoolScheduler.EXPECT().Submit(NewMockTaskMatcher(mockTask)).Return(nil).Times(1)
+	mockPoolScheduler.EXPECT().TrySubmit(NewMockTaskMatcher(mockTask)).Return(true, nil).Times(1)
+	s.processor.poolSchedulers["pool-a"] = mockPoolScheduler
+
+	s.NoError(s.processor.Submit(mockTask))
+	submitted, err := s.processor.TrySubmit(mockTask)
+	s.NoError(err)
+	s.True(submitted)
+}
+
+func (s *queueTaskProcessorSuite) TestGetOrCreatePoolTaskScheduler() {
+	_, err := s.processor.getOrCreatePoolTaskScheduler("pool-a")
+	s.Equal(errTaskProcessorNotRunning, err)
+
+	s.processor.Start()
+	scheduler, err := s.processor.getOrCreatePoolTaskScheduler("pool-a")
+	s.NoError(err)
+	sameScheduler, err := s.processor.getOrCreatePoolTaskScheduler("pool-a")
+	s.NoError(err)
+	s.Equal(scheduler, sameScheduler)
+	s.Len(s.processor.poolSchedulers, 1)
+
+	s.processor.Stop()
+	s.Empty(s.processor.poolSchedulers)
+}
+
+func (s *queueTaskProcessorSuite) TestSubmit_FairScheduler() {
+	mockTask := NewMockTask(s.controller)
+	mockTask.EXPECT().GetDomainID().Return(constants.TestDomainID).AnyTimes()
+	s.mockPriorityAssigner.EXPECT().Assign(NewMockTaskMatcher(mockTask)).Return(nil).Times(2)
+
+	mockScheduler := task.NewMockScheduler(s.controller)
+	mockScheduler.EXPECT().TrySubmit(NewMockTaskMatcher(mockTask)).Return(false, nil).Times(2)
+	mockFairScheduler := task.NewMockScheduler(s.controller)
+	mockFairScheduler.EXPECT().Submit(NewMockTaskMatcher(mockTask)).Return(nil).Times(1)
+	mockFairScheduler.EXPECT().TrySubmit(NewMockTaskMatcher(mockTask)).Return(true, nil).Times(1)
+
+	s.processor.hostScheduler = mockScheduler
+	s.processor.fairScheduler = mockFairScheduler
+
+	s.NoError(s.processor.Submit(mockTask))
+	submitted, err := s.processor.TrySubmit(mockTask)
+	s.NoError(err)
+	s.True(submitted)
+	// the shard schedulers are not used along with the fair scheduler
+	s.Empty(s.processor.shardSchedulers)
+}
+
+func (s *queueTaskProcessorSuite) TestFairScheduler_ProcessTask() {
+	config := config.NewForTest()
+	config.TaskSchedulerEnableFairScheduling = dynamicconfig.GetBoolPropertyFn(true)
+	config.TaskSchedulerDomainWeights = dynamicconfig.GetMapPropertyFn(map[string]interface{}{constants.TestDomainName: 5})
+	processor, err := NewProcessor(
+		s.mockPriorityAssigner,
+		config,
+		s.logger,
+		s.metricsClient,
+		nil,
+		s.mockShard.Resource.DomainCache,
+	)
+	s.NoError(err)
+	processorImpl := processor.(*processorImpl)
+	s.NotNil(processorImpl.fairScheduler)
+
+	mockScheduler := task.NewMockScheduler(s.controller)
+	mockScheduler.EXPECT().Start().Times(1)
+	mockScheduler.EXPECT().Stop().Times(1)
+	processorImpl.hostScheduler = mockScheduler
+
+	done := make(chan struct{})
+	mockTask := NewMockTask(s.controller)
+	mockTask.EXPECT().GetDomainID().Return(constants.TestDomainID).AnyTimes()
+	mockTask.EXPECT().GetShard().Return(s.mockShard).AnyTimes()
+	mockTask.EXPECT().Execute().Return(nil).Times(1)
+	mockTask.EXPECT().Ack().Do(func() { close(done) }).Times(1)
+	s.mockPriorityAssigner.EXPECT().Assign(NewMockTaskMatcher(mockTask)).Return(nil).Times(1)
+	mockScheduler.EXPECT().TrySubmit(NewMockTaskMatcher(mockTask)).Return(false, nil).Times(1)
+
+	processor.Start()
+	defer processor.Stop()
+	s.NoError(processor.Submit(mockTask))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.Fail("task is not processed by the fair scheduler")
+	}
+}
+
 func (s *queueTaskProcessorSuite) TestNewSchedulerOptions_UnknownSchedulerType() {
 	options, err := newSchedulerOptions(0, 100, dynamicconfig.GetIntPropertyFn(10), 1, nil)
 	s.Error(err)
@@ -218,6 +324,8 @@ func (s *queueTaskProcessorSuite) newTestQueueTaskProcessor() *processorImpl {
 		config,
 		s.logger,
 		s.metricsClient,
+		nil,
+		s.mockShard.Resource.DomainCache,
 	)
 	s.NoError(err)
 	return processor.(*processorImpl)
@@ -25,6 +25,8 @@ import (
 	"fmt"
 	"time"
 
+	"go.uber.org/yarpc"
+
 	"github.com/uber/cadence/common"
 
 	"github.com/uber/cadence/common/log"
@@ -114,31 +116,37 @@ type (
 
 	pushActivityToMatchingInfo struct {
 		activityScheduleToStartTimeout int32
+		callOptions                    []yarpc.CallOption
 	}
 
 	pushDecisionToMatchingInfo struct {
 		decisionScheduleToStartTimeout int32
 		tasklist                       types.TaskList
+		callOptions                    []yarpc.CallOption
 	}
 )
 
 func newPushActivityToMatchingInfo(
 	activityScheduleToStartTimeout int32,
+	callOptions []yarpc.CallOption,
 ) *pushActivityToMatchingInfo {
 
 	return &pushActivityToMatchingInfo{
 		activityScheduleToStartTimeout: activityScheduleToStartTimeout,
+		callOptions:                    callOptions,
 	}
 }
 
 func newPushDecisionToMatchingInfo(
 	decisionScheduleToStartTimeout int32,
 	tasklist types.TaskList,
+	callOptions []yarpc.CallOption,
 ) *pushDecisionToMatchingInfo {
 
 	return &pushDecisionToMatchingInfo{
 		decisionScheduleToStartTimeout: decisionScheduleToStartTimeout,
 		tasklist:                       tasklist,
+		callOptions:                    callOptions,
 	}
 }
 
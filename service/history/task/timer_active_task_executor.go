@@ -40,6 +40,8 @@ import (
 
 const (
 	scanWorkflowTimeout = 30 * time.Second
+
+	cronOverlapReason = "cadenceInternal:CronOverlap"
 )
 
 var (
@@ -496,9 +498,13 @@ func (t *timerActiveTaskExecutor) executeWorkflowBackoffTimerTask(
 		return nil
 	}
 
-	if task.TimeoutType == persistence.WorkflowBackoffTimeoutTypeRetry {
+	switch task.TimeoutType {
+	case persistence.WorkflowBackoffTimeoutTypeRetry:
 		t.metricsClient.IncCounter(metrics.TimerActiveTaskWorkflowBackoffTimerScope, metrics.WorkflowRetryBackoffTimerCount)
-	} else {
+	case persistence.WorkflowBackoffTimeoutTypeCronOverlap:
+		t.metricsClient.IncCounter(metrics.TimerActiveTaskWorkflowBackoffTimerScope, metrics.WorkflowCronOverlapTimerCount)
+		return t.cancelRunningCronWorkflow(ctx, wfContext, mutableState, task)
+	default:
 		t.metricsClient.IncCounter(metrics.TimerActiveTaskWorkflowBackoffTimerScope, metrics.WorkflowCronBackoffTimerCount)
 	}
 
@@ -511,6 +517,82 @@ func (t *timerActiveTaskExecutor) executeWorkflowBackoffTimerTask(
 	return t.updateWorkflowExecution(ctx, wfContext, mutableState, true)
 }
 
+// cancelRunningCronWorkflow closes a cron workflow still running at the next fire
+// of its schedule, and starts the new run right away
+func (t *timerActiveTaskExecutor) cancelRunningCronWorkflow(
+	ctx context.Context,
+	wfContext execution.Context,
+	mutableState execution.MutableState,
+	task *persistence.TimerTaskInfo,
+) error {
+
+	startVersion, err := mutableState.GetStartVersion()
+	if err != nil {
+		return err
+	}
+	ok, err := verifyTaskVersion(t.shard, t.logger, task.DomainID, startVersion, task.Version, task)
+	if err != nil || !ok {
+		return err
+	}
+
+	// ignore event id
+	if isCanceled, _ := mutableState.IsCancelRequested(); isCanceled {
+		// the workflow is being canceled, which ends the cron schedule
+		return nil
+	}
+
+	startEvent, err := mutableState.GetStartEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	startAttributes := startEvent.WorkflowExecutionStartedEventAttributes
+	continueAsNewAttributes := &types.ContinueAsNewWorkflowExecutionDecisionAttributes{
+		WorkflowType:                        startAttributes.WorkflowType,
+		TaskList:                            startAttributes.TaskList,
+		Input:                               startAttributes.Input,
+		ExecutionStartToCloseTimeoutSeconds: startAttributes.ExecutionStartToCloseTimeoutSeconds,
+		TaskStartToCloseTimeoutSeconds:      startAttributes.TaskStartToCloseTimeoutSeconds,
+		BackoffStartIntervalInSeconds:       common.Int32Ptr(0),
+		RetryPolicy:                         startAttributes.RetryPolicy,
+		Initiator:                           types.ContinueAsNewInitiatorCronSchedule.Ptr(),
+		FailureReason:                       common.StringPtr(cronOverlapReason),
+		LastCompletionResult:                startAttributes.LastCompletionResult,
+		CronSchedule:                        mutableState.GetExecutionInfo().CronSchedule,
+		Header:                              startAttributes.Header,
+		Memo:                                startAttributes.Memo,
+		SearchAttributes:                    startAttributes.SearchAttributes,
+		JitterStartSeconds:                  startAttributes.JitterStartSeconds,
+	}
+	newMutableState, err := retryWorkflow(
+		ctx,
+		mutableState,
+		mutableState.GetNextEventID(),
+		startAttributes.GetParentWorkflowDomain(),
+		continueAsNewAttributes,
+	)
+	if err != nil {
+		return err
+	}
+
+	newExecutionInfo := newMutableState.GetExecutionInfo()
+	return wfContext.UpdateWorkflowExecutionWithNewAsActive(
+		ctx,
+		t.shard.GetTimeSource().Now(),
+		execution.NewContext(
+			newExecutionInfo.DomainID,
+			types.WorkflowExecution{
+				WorkflowID: newExecutionInfo.WorkflowID,
+				RunID:      newExecutionInfo.RunID,
+			},
+			t.shard,
+			t.shard.GetExecutionManager(),
+			t.logger,
+		),
+		newMutableState,
+	)
+}
+
 func (t *timerActiveTaskExecutor) executeActivityRetryTimerTask(
 	ctx context.Context,
 	task *persistence.TimerTaskInfo,
@@ -1012,6 +1012,41 @@ func (s *timerActiveTaskExecutorSuite) TestWorkflowBackoffTimer_Noop() {
 	s.NoError(err)
 }
 
+func (s *timerActiveTaskExecutorSuite) TestWorkflowBackoffTimer_CronOverlap() {
+
+	workflowExecution, mutableState, decisionCompletionID, err := test.SetupWorkflowWithCompletedDecision(s.mockShard, s.domainID)
+	s.NoError(err)
+
+	executionInfo := mutableState.GetExecutionInfo()
+	executionInfo.StartTimestamp = s.now
+	executionInfo.CronSchedule = "* * * * *"
+
+	timerTask := s.newTimerTaskFromInfo(&persistence.TimerTaskInfo{
+		Version:             s.version,
+		DomainID:            s.domainID,
+		WorkflowID:          workflowExecution.GetWorkflowID(),
+		RunID:               workflowExecution.GetRunID(),
+		TaskID:              int64(100),
+		TaskType:            persistence.TaskTypeWorkflowBackoffTimer,
+		TimeoutType:         persistence.WorkflowBackoffTimeoutTypeCronOverlap,
+		VisibilityTimestamp: s.now,
+	})
+
+	persistenceMutableState, err := test.CreatePersistenceMutableState(mutableState, decisionCompletionID, mutableState.GetCurrentVersion())
+	s.NoError(err)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything, mock.Anything).Return(&persistence.GetWorkflowExecutionResponse{State: persistenceMutableState}, nil)
+	// one for current workflow, one for new
+	s.mockHistoryV2Mgr.On("AppendHistoryNodes", mock.Anything, mock.Anything).Return(&persistence.AppendHistoryNodesResponse{}, nil).Times(2)
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything, mock.Anything).Return(&persistence.UpdateWorkflowExecutionResponse{MutableStateUpdateSessionStats: &persistence.MutableStateUpdateSessionStats{}}, nil).Once()
+
+	err = s.timerActiveTaskExecutor.Execute(timerTask, true)
+	s.NoError(err)
+
+	state, closeStatus := s.getMutableStateFromCache(s.domainID, workflowExecution.GetWorkflowID(), workflowExecution.GetRunID()).GetWorkflowStateCloseStatus()
+	s.Equal(persistence.WorkflowStateCompleted, state)
+	s.Equal(persistence.WorkflowCloseStatusContinuedAsNew, closeStatus)
+}
+
 func (s *timerActiveTaskExecutorSuite) TestActivityRetryTimer_Fire() {
 
 	workflowExecution, mutableState, decisionCompletionID, err := test.SetupWorkflowWithCompletedDecision(s.mockShard, s.domainID)
@@ -322,6 +322,20 @@ func (t *timerStandbyTaskExecutor) executeWorkflowBackoffTimerTask(
 
 	actionFn := func(ctx context.Context, wfContext execution.Context, mutableState execution.MutableState) (interface{}, error) {
 
+		if timerTask.TimeoutType == persistence.WorkflowBackoffTimeoutTypeCronOverlap {
+			// active cluster will close the run and start a new one,
+			// standby cluster should wait for the new run to be replicated
+			startVersion, err := mutableState.GetStartVersion()
+			if err != nil {
+				return nil, err
+			}
+			ok, err := verifyTaskVersion(t.shard, t.logger, timerTask.DomainID, startVersion, timerTask.Version, timerTask)
+			if err != nil || !ok {
+				return nil, err
+			}
+			return getHistoryResendInfo(mutableState)
+		}
+
 		if mutableState.HasProcessedOrPendingDecision() {
 			// if there is one decision already been processed
 			// or has pending decision, meaning workflow has already running
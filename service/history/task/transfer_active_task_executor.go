@@ -38,6 +38,7 @@ import (
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/tracing"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/history/config"
 	"github.com/uber/cadence/service/history/execution"
@@ -157,6 +158,9 @@ func (t *transferActiveTaskExecutor) processActivityTask(
 	task *persistence.TransferTaskInfo,
 ) (retError error) {
 
+	span, ctx := startTransferTaskSpan(ctx, "history.TransferActivityTask", task)
+	defer func() { tracing.FinishSpan(span, retError) }()
+
 	wfContext, release, err := t.executionCache.GetOrCreateWorkflowExecutionWithTimeout(
 		task.DomainID,
 		getWorkflowExecution(task),
@@ -189,10 +193,11 @@ func (t *transferActiveTaskExecutor) processActivityTask(
 	}
 
 	timeout := common.MinInt32(ai.ScheduleToStartTimeout, common.MaxTaskTimeout)
+	callOptions := matchingCallOptions(mutableState.GetExecutionInfo())
 	// release the context lock since we no longer need mutable state builder and
 	// the rest of logic is making RPC call, which takes time.
 	release(nil)
-	return t.pushActivity(ctx, task, timeout)
+	return t.pushActivity(ctx, task, timeout, callOptions)
 }
 
 func (t *transferActiveTaskExecutor) processDecisionTask(
@@ -200,6 +205,9 @@ func (t *transferActiveTaskExecutor) processDecisionTask(
 	task *persistence.TransferTaskInfo,
 ) (retError error) {
 
+	span, ctx := startTransferTaskSpan(ctx, "history.TransferDecisionTask", task)
+	defer func() { tracing.FinishSpan(span, retError) }()
+
 	wfContext, release, err := t.executionCache.GetOrCreateWorkflowExecutionWithTimeout(
 		task.DomainID,
 		getWorkflowExecution(task),
@@ -234,6 +242,7 @@ func (t *transferActiveTaskExecutor) processDecisionTask(
 	executionInfo := mutableState.GetExecutionInfo()
 	workflowTimeout := executionInfo.WorkflowTimeout
 	decisionTimeout := common.MinInt32(workflowTimeout, common.MaxTaskTimeout)
+	callOptions := matchingCallOptions(executionInfo)
 
 	// NOTE: previously this section check whether mutable state has enabled
 	// sticky decision, if so convert the decision to a sticky decision.
@@ -261,7 +270,7 @@ func (t *transferActiveTaskExecutor) processDecisionTask(
 	// release the context lock since we no longer need mutable state builder and
 	// the rest of logic is making RPC call, which takes time.
 	release(nil)
-	err = t.pushDecision(ctx, task, taskList, decisionTimeout)
+	err = t.pushDecision(ctx, task, taskList, decisionTimeout, callOptions)
 	if _, ok := err.(*types.StickyWorkerUnavailableError); ok {
 		// sticky worker is unavailable, switch to non-sticky task list
 		taskList = &types.TaskList{
@@ -273,7 +282,7 @@ func (t *transferActiveTaskExecutor) processDecisionTask(
 		// There is no need to reset sticky, because if this task is picked by new worker, the new worker will reset
 		// the sticky queue to a new one. However, if worker is completely down, that schedule_to_start timeout task
 		// will re-create a new non-sticky task and reset sticky.
-		err = t.pushDecision(ctx, task, taskList, decisionTimeout)
+		err = t.pushDecision(ctx, task, taskList, decisionTimeout, callOptions)
 	}
 	return err
 }
@@ -1829,7 +1838,7 @@ func (t *transferActiveTaskExecutor) resetWorkflow(
 		),
 		reason,
 		nil,
-		false,
+		reset.ReapplyOptions{},
 	)
 
 	switch err.(type) {
@@ -22,16 +22,24 @@ package task
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"go.uber.org/yarpc"
+
 	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/tracing"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/history/config"
 	"github.com/uber/cadence/service/history/execution"
@@ -89,6 +97,7 @@ func (t *transferTaskExecutorBase) pushActivity(
 	ctx context.Context,
 	task *persistence.TransferTaskInfo,
 	activityScheduleToStartTimeout int32,
+	callOptions []yarpc.CallOption,
 ) error {
 
 	ctx, cancel := context.WithTimeout(ctx, taskRPCCallTimeout)
@@ -108,7 +117,7 @@ func (t *transferTaskExecutorBase) pushActivity(
 		TaskList:                      &types.TaskList{Name: task.TaskList},
 		ScheduleID:                    task.ScheduleID,
 		ScheduleToStartTimeoutSeconds: common.Int32Ptr(activityScheduleToStartTimeout),
-	})
+	}, callOptions...)
 }
 
 func (t *transferTaskExecutorBase) pushDecision(
@@ -116,6 +125,7 @@ func (t *transferTaskExecutorBase) pushDecision(
 	task *persistence.TransferTaskInfo,
 	tasklist *types.TaskList,
 	decisionScheduleToStartTimeout int32,
+	callOptions []yarpc.CallOption,
 ) error {
 
 	ctx, cancel := context.WithTimeout(ctx, taskRPCCallTimeout)
@@ -134,7 +144,25 @@ func (t *transferTaskExecutorBase) pushDecision(
 		TaskList:                      tasklist,
 		ScheduleID:                    task.ScheduleID,
 		ScheduleToStartTimeoutSeconds: common.Int32Ptr(decisionScheduleToStartTimeout),
-	})
+	}, callOptions...)
+}
+
+// getTaskPriority returns the priority set in the memo of the workflow, 0 if it isn't set or isn't a number.
+// The decision and activity tasks of the workflow are dispatched before the tasks with a lower priority
+func getTaskPriority(executionInfo *persistence.WorkflowExecutionInfo) int {
+	priority, err := strconv.Atoi(strings.Trim(strings.TrimSpace(string(executionInfo.Memo[common.TaskPriorityMemoKey])), `"`))
+	if err != nil {
+		return 0
+	}
+	return priority
+}
+
+// matchingCallOptions returns the headers the decision and activity tasks of the workflow are added to matching with
+func matchingCallOptions(executionInfo *persistence.WorkflowExecutionInfo) []yarpc.CallOption {
+	if priority := getTaskPriority(executionInfo); priority != 0 {
+		return []yarpc.CallOption{yarpc.WithHeader(common.TaskPriorityHeaderName, strconv.Itoa(priority))}
+	}
+	return nil
 }
 
 func (t *transferTaskExecutorBase) recordWorkflowStarted(
@@ -294,11 +322,14 @@ func (t *transferTaskExecutorBase) recordWorkflowClosed(
 
 	if err == nil {
 		// retention in domain config is in days, convert to seconds
-		retentionSeconds = int64(domainEntry.GetRetentionDays(workflowID)) * int64(secondsInDay)
+		retentionSeconds = int64(domainEntry.GetExecutionRetentionDays(workflowID, visibilityMemo.GetFields())) * int64(secondsInDay)
 		domain = domainEntry.GetInfo().Name
-		// if sampled for longer retention is enabled, only record those sampled events
+		// if sampled for longer retention is enabled, only record those sampled events,
+		// and those with a retention specified at start time
+		_, hasExecutionRetention := cache.ParseExecutionRetentionDays(visibilityMemo.GetFields())
 		if domainEntry.IsSampledForLongerRetentionEnabled(workflowID) &&
-			!domainEntry.IsSampledForLongerRetention(workflowID) {
+			!domainEntry.IsSampledForLongerRetention(workflowID) &&
+			!hasExecutionRetention {
 			recordWorkflowClose = false
 		}
 
@@ -415,3 +446,18 @@ func isWorkflowNotExistError(err error) bool {
 	_, ok := err.(*types.EntityNotExistsError)
 	return ok
 }
+
+// startTransferTaskSpan starts a new trace for the task, the trace context is propagated
+// to matching when the task is pushed to the task list
+func startTransferTaskSpan(
+	ctx context.Context,
+	operationName string,
+	task *persistence.TransferTaskInfo,
+) (opentracing.Span, context.Context) {
+	span, ctx := tracing.StartSpan(ctx, operationName, ext.SpanKindProducer)
+	span.SetTag(tracing.TagDomainID, task.DomainID)
+	span.SetTag(tracing.TagWorkflowID, task.WorkflowID)
+	span.SetTag(tracing.TagRunID, task.RunID)
+	span.SetTag(tracing.TagTaskList, task.TaskList)
+	return span, ctx
+}
@@ -0,0 +1,155 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package workflowcache
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+)
+
+type (
+	// WFCache rate limits the requests made against a single workflow ID, so a runaway
+	// workflow cannot exhaust the resources of the shard it lives on
+	WFCache interface {
+		// AllowSignal returns false if the workflow has been signaled too often
+		AllowSignal(domainID string, workflowID string) bool
+		// AllowEvents returns false if the workflow has generated too many history events
+		AllowEvents(domainID string, workflowID string, count int) bool
+	}
+
+	// Params contains the dependencies and configs of the cache
+	Params struct {
+		TTL         time.Duration
+		MaxCount    int
+		DomainCache cache.DomainCache
+		SignalRPS   dynamicconfig.IntPropertyFnWithDomainFilter
+		EventsRPS   dynamicconfig.IntPropertyFnWithDomainFilter
+		Logger      log.Logger
+	}
+
+	wfCache struct {
+		lru         cache.Cache
+		domainCache cache.DomainCache
+		signalRPS   dynamicconfig.IntPropertyFnWithDomainFilter
+		eventsRPS   dynamicconfig.IntPropertyFnWithDomainFilter
+		logger      log.Logger
+	}
+
+	limitType int
+
+	cacheKey struct {
+		domainID   string
+		workflowID string
+		limitType  limitType
+	}
+)
+
+const (
+	limitTypeSignal limitType = iota
+	limitTypeEvents
+)
+
+// New creates a new workflow ID rate limiting cache
+func New(params Params) WFCache {
+	return &wfCache{
+		lru: cache.New(&cache.Options{
+			TTL:      params.TTL,
+			MaxCount: params.MaxCount,
+			Pin:      false,
+		}),
+		domainCache: params.DomainCache,
+		signalRPS:   params.SignalRPS,
+		eventsRPS:   params.EventsRPS,
+		logger:      params.Logger,
+	}
+}
+
+func (c *wfCache) AllowSignal(domainID string, workflowID string) bool {
+	return c.allowN(domainID, workflowID, limitTypeSignal, c.signalRPS, 1)
+}
+
+func (c *wfCache) AllowEvents(domainID string, workflowID string, count int) bool {
+	return c.allowN(domainID, workflowID, limitTypeEvents, c.eventsRPS, count)
+}
+
+func (c *wfCache) allowN(
+	domainID string,
+	workflowID string,
+	limitType limitType,
+	rpsFn dynamicconfig.IntPropertyFnWithDomainFilter,
+	n int,
+) bool {
+	if n <= 0 {
+		return true
+	}
+	rps, ok := c.getRPS(domainID, rpsFn)
+	if !ok {
+		return true
+	}
+
+	if n > rps {
+		// the burst is the RPS, so a larger batch would never be allowed
+		n = rps
+	}
+
+	limiter := c.getLimiter(cacheKey{domainID: domainID, workflowID: workflowID, limitType: limitType}, rps)
+	if limiter.Limit() != rate.Limit(rps) {
+		limiter.SetLimit(rate.Limit(rps))
+		limiter.SetBurst(rps)
+	}
+	return limiter.AllowN(time.Now(), n)
+}
+
+// getRPS returns the limit configured for the domain, or false if the limit is disabled
+func (c *wfCache) getRPS(
+	domainID string,
+	rpsFn dynamicconfig.IntPropertyFnWithDomainFilter,
+) (int, bool) {
+	domainName, err := c.domainCache.GetDomainName(domainID)
+	if err != nil {
+		// fail open, the request will be rejected later if the domain is really not there
+		c.logger.Warn("Failed to get domain name for workflow ID rate limit", tag.WorkflowDomainID(domainID), tag.Error(err))
+		return 0, false
+	}
+	rps := rpsFn(domainName)
+	return rps, rps > 0
+}
+
+func (c *wfCache) getLimiter(key cacheKey, rps int) *rate.Limiter {
+	if limiter, ok := c.lru.Get(key).(*rate.Limiter); ok {
+		return limiter
+	}
+
+	limiter, err := c.lru.PutIfNotExist(key, rate.NewLimiter(rate.Limit(rps), rps))
+	if err != nil {
+		// the cache is unpinned so this should not happen, use a limiter which is not shared
+		return rate.NewLimiter(rate.Limit(rps), rps)
+	}
+	return limiter.(*rate.Limiter)
+}
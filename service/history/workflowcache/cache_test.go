@@ -0,0 +1,98 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package workflowcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	testDomainID   = "test-domain-id"
+	testDomainName = "test-domain"
+)
+
+func newTestCache(t *testing.T, signalRPS int, eventsRPS int) WFCache {
+	domainCache := cache.NewMockDomainCache(gomock.NewController(t))
+	domainCache.EXPECT().GetDomainName(testDomainID).Return(testDomainName, nil).AnyTimes()
+
+	return New(Params{
+		TTL:         time.Minute,
+		MaxCount:    10,
+		DomainCache: domainCache,
+		SignalRPS:   dynamicconfig.GetIntPropertyFilteredByDomain(signalRPS),
+		EventsRPS:   dynamicconfig.GetIntPropertyFilteredByDomain(eventsRPS),
+		Logger:      log.NewNoop(),
+	})
+}
+
+func TestAllowSignal(t *testing.T) {
+	wfCache := newTestCache(t, 2, 0)
+
+	assert.True(t, wfCache.AllowSignal(testDomainID, "wid"))
+	assert.True(t, wfCache.AllowSignal(testDomainID, "wid"))
+	assert.False(t, wfCache.AllowSignal(testDomainID, "wid"))
+
+	// other workflows are not affected
+	assert.True(t, wfCache.AllowSignal(testDomainID, "other-wid"))
+	// neither are the events of the same workflow
+	assert.True(t, wfCache.AllowEvents(testDomainID, "wid", 100))
+}
+
+func TestAllowEvents(t *testing.T) {
+	wfCache := newTestCache(t, 0, 10)
+
+	assert.True(t, wfCache.AllowEvents(testDomainID, "wid", 6))
+	assert.False(t, wfCache.AllowEvents(testDomainID, "wid", 6))
+	assert.True(t, wfCache.AllowEvents(testDomainID, "wid", 4))
+	assert.True(t, wfCache.AllowEvents(testDomainID, "wid", 0))
+
+	// a batch larger than the limit is allowed once the limiter is full
+	assert.True(t, wfCache.AllowEvents(testDomainID, "other-wid", 20))
+	assert.False(t, wfCache.AllowEvents(testDomainID, "other-wid", 1))
+}
+
+func TestAllow_DomainNotFound(t *testing.T) {
+	domainCache := cache.NewMockDomainCache(gomock.NewController(t))
+	domainCache.EXPECT().GetDomainName(testDomainID).Return("", &types.EntityNotExistsError{}).AnyTimes()
+	wfCache := New(Params{
+		TTL:         time.Minute,
+		MaxCount:    10,
+		DomainCache: domainCache,
+		SignalRPS:   dynamicconfig.GetIntPropertyFilteredByDomain(1),
+		EventsRPS:   dynamicconfig.GetIntPropertyFilteredByDomain(1),
+		Logger:      log.NewNoop(),
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, wfCache.AllowSignal(testDomainID, "wid"))
+	}
+}
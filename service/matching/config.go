@@ -26,6 +26,7 @@ import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/persistence"
 )
 
 type (
@@ -38,7 +39,10 @@ type (
 		WorkerRPS               dynamicconfig.IntPropertyFn
 		DomainUserRPS           dynamicconfig.IntPropertyFnWithDomainFilter
 		DomainWorkerRPS         dynamicconfig.IntPropertyFnWithDomainFilter
+		DomainResourcePool      dynamicconfig.StringPropertyFnWithDomainFilter
+		ResourcePoolRPS         dynamicconfig.MapPropertyFn
 		ShutdownDrainDuration   dynamicconfig.DurationPropertyFn
+		EnableTaskListHandoff   dynamicconfig.BoolPropertyFn
 
 		// taskListManager configuration
 		RangeSize                    int64
@@ -46,6 +50,8 @@ type (
 		UpdateAckInterval            dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
 		IdleTasklistCheckInterval    dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
 		MaxTasklistIdleTime          dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
+		PollerHistoryTTL             dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
+		PollerHistoryMaxSize         dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 		NumTasklistWritePartitions   dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 		NumTasklistReadPartitions    dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 		ForwarderMaxOutstandingPolls dynamicconfig.IntPropertyFnWithTaskListInfoFilters
@@ -57,6 +63,42 @@ type (
 		LongPollExpirationInterval dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
 		MinTaskThrottlingBurstSize dynamicconfig.IntPropertyFnWithTaskListInfoFilters
 		MaxTaskDeleteBatchSize     dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		// Buffering of the deletes of completed tasks
+		CompletedTaskDeleteBatchSize dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		TaskDeleteFlushInterval      dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
+		// Operator override of the task dispatch rate provided by pollers, 0 means no override
+		TaskDispatchRPS dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		// Max number of activities of a task list running at once, 0 means no limit
+		MaxConcurrentActivities dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		// Time a task waits for a poller of its isolation group before it is offered to any poller
+		IsolationGroupSpilloverDelay dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
+		// Isolation groups of the cluster and the isolation group the tasks of a domain are matched in first
+		AllIsolationGroups   dynamicconfig.ListPropertyFn
+		DomainIsolationGroup dynamicconfig.StringPropertyFnWithDomainFilter
+		// Adaptive taskReader batch size and backoff on empty reads
+		EnableAdaptiveTaskRead  dynamicconfig.BoolPropertyFnWithTaskListInfoFilters
+		MaxGetTasksBatchSize    dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		MaxEmptyTaskReadBackoff dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
+		// Automatic scaling of the number of partitions of a task list
+		EnablePartitionAutoScaling dynamicconfig.BoolPropertyFnWithTaskListInfoFilters
+		PartitionScaleInterval     dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
+		PartitionScaleUpRPS        dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		PartitionScaleDownRPS      dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		MinTaskListPartitions      dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		MaxTaskListPartitions      dynamicconfig.IntPropertyFnWithTaskListInfoFilters
+		// Rejects the new tasks of a task list while its backlog is consumed
+		TaskListDrainMode dynamicconfig.BoolPropertyFnWithTaskListInfoFilters
+		// Keeps the tasks of a standby domain in the backlog until the domain fails over to the current cluster
+		EnableStandbyTaskBuffering dynamicconfig.BoolPropertyFnWithDomainFilter
+		// Writes the expired tasks to the dead letter queue instead of dropping them
+		EnableExpiredTaskDeadLetter dynamicconfig.BoolPropertyFnWithDomainFilter
+		ExpiredTaskLogSampleRate    dynamicconfig.FloatPropertyFnWithDomainFilter
+		// Dispatches the backlog in round robin across workflows
+		EnableWorkflowFairDispatch dynamicconfig.BoolPropertyFnWithTaskListInfoFilters
+		// Holds the backlog dispatch while a query waits for a poller
+		EnableQueryPollerReservation dynamicconfig.BoolPropertyFnWithTaskListInfoFilters
+		// Makes the task list ephemeral, its tasks are never persisted
+		EnableEphemeralTaskList dynamicconfig.BoolPropertyFnWithTaskListInfoFilters
 
 		// taskWriter configuration
 		OutstandingTaskAppendsThreshold dynamicconfig.IntPropertyFnWithTaskListInfoFilters
@@ -68,7 +110,11 @@ type (
 		EnableDebugMode             bool // note that this value is initialized once on service start
 		EnableTaskInfoLogByDomainID dynamicconfig.BoolPropertyFnWithDomainIDFilter
 
-		ActivityTaskSyncMatchWaitTime dynamicconfig.DurationPropertyFnWithDomainFilter
+		ActivityTaskSyncMatchWaitTime  dynamicconfig.DurationPropertyFnWithDomainFilter
+		EphemeralTaskSyncMatchWaitTime dynamicconfig.DurationPropertyFnWithTaskListInfoFilters
+
+		// used to subscribe to changes of the properties above
+		dynamicConfig *dynamicconfig.Collection
 	}
 
 	forwarderConfig struct {
@@ -88,8 +134,44 @@ type (
 		UpdateAckInterval          func() time.Duration
 		IdleTasklistCheckInterval  func() time.Duration
 		MaxTasklistIdleTime        func() time.Duration
+		PollerHistoryTTL           func() time.Duration
+		PollerHistoryMaxSize       func() int
 		MinTaskThrottlingBurstSize func() int
 		MaxTaskDeleteBatchSize     func() int
+		TaskDispatchRPS            func() int
+		// Max number of activities running at once across the task list partitions, always 0 for decision task lists
+		MaxConcurrentActivities func() int
+		// Buffering of the deletes of completed tasks
+		CompletedTaskDeleteBatchSize func() int
+		TaskDeleteFlushInterval      func() time.Duration
+		// Time a task waits for a poller of its isolation group before it is offered to any poller
+		IsolationGroupSpilloverDelay func() time.Duration
+		// Isolation groups the tasks and the pollers are matched in, the other groups are ignored
+		AllIsolationGroups func() []string
+		// Adaptive taskReader batch size and backoff on empty reads
+		EnableAdaptiveTaskRead  func() bool
+		MaxGetTasksBatchSize    func() int
+		MaxEmptyTaskReadBackoff func() time.Duration
+		// Automatic scaling of the number of partitions of a task list
+		EnablePartitionAutoScaling func() bool
+		PartitionScaleInterval     func() time.Duration
+		PartitionScaleUpRPS        func() int
+		PartitionScaleDownRPS      func() int
+		MinTaskListPartitions      func() int
+		MaxTaskListPartitions      func() int
+		// Rejects the new tasks of a task list while its backlog is consumed
+		DrainMode func() bool
+		// Handling of the tasks expired in the backlog
+		EnableExpiredTaskDeadLetter func() bool
+		ExpiredTaskLogSampleRate    func() float64
+		// Dispatches the backlog in round robin across workflows
+		EnableWorkflowFairDispatch func() bool
+		// Holds the backlog dispatch while a query waits for a poller
+		EnableQueryPollerReservation func() bool
+		// Makes the task list ephemeral, the same value applies to all its partitions
+		EnableEphemeralTaskList func() bool
+		// Time the tasks of ephemeral task lists wait to be sync matched
+		EphemeralTaskSyncMatchWaitTime func() time.Duration
 		// taskWriter configuration
 		OutstandingTaskAppendsThreshold func() int
 		MaxTaskBatchSize                func() int
@@ -108,14 +190,41 @@ func NewConfig(dc *dynamicconfig.Collection) *Config {
 		WorkerRPS:                       dc.GetIntProperty(dynamicconfig.MatchingWorkerRPS),
 		DomainUserRPS:                   dc.GetIntPropertyFilteredByDomain(dynamicconfig.MatchingDomainUserRPS),
 		DomainWorkerRPS:                 dc.GetIntPropertyFilteredByDomain(dynamicconfig.MatchingDomainWorkerRPS),
+		DomainResourcePool:              dc.GetStringPropertyFilteredByDomain(dynamicconfig.DomainResourcePool),
+		ResourcePoolRPS:                 dc.GetMapProperty(dynamicconfig.MatchingResourcePoolRPS),
 		RangeSize:                       100000,
 		GetTasksBatchSize:               dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingGetTasksBatchSize),
 		UpdateAckInterval:               dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingUpdateAckInterval),
 		IdleTasklistCheckInterval:       dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingIdleTasklistCheckInterval),
 		MaxTasklistIdleTime:             dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MaxTasklistIdleTime),
+		PollerHistoryTTL:                dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingPollerHistoryTTL),
+		PollerHistoryMaxSize:            dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingPollerHistoryMaxSize),
 		LongPollExpirationInterval:      dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingLongPollExpirationInterval),
+		IsolationGroupSpilloverDelay:    dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingIsolationGroupSpilloverDelay),
+		AllIsolationGroups:              dc.GetListProperty(dynamicconfig.AllIsolationGroups),
+		DomainIsolationGroup:            dc.GetStringPropertyFilteredByDomain(dynamicconfig.MatchingDomainIsolationGroup),
+		EnableAdaptiveTaskRead:          dc.GetBoolPropertyFilteredByTaskListInfo(dynamicconfig.MatchingEnableAdaptiveTaskRead),
+		MaxGetTasksBatchSize:            dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMaxGetTasksBatchSize),
+		MaxEmptyTaskReadBackoff:         dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMaxEmptyTaskReadBackoff),
+		EnablePartitionAutoScaling:      dc.GetBoolPropertyFilteredByTaskListInfo(dynamicconfig.MatchingEnablePartitionAutoScaling),
+		PartitionScaleInterval:          dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingPartitionScaleInterval),
+		PartitionScaleUpRPS:             dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingPartitionScaleUpRPS),
+		PartitionScaleDownRPS:           dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingPartitionScaleDownRPS),
+		MinTaskListPartitions:           dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMinTaskListPartitions),
+		MaxTaskListPartitions:           dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMaxTaskListPartitions),
+		TaskListDrainMode:               dc.GetBoolPropertyFilteredByTaskListInfo(dynamicconfig.MatchingTaskListDrainMode),
+		EnableStandbyTaskBuffering:      dc.GetBoolPropertyFilteredByDomain(dynamicconfig.MatchingEnableStandbyTaskBuffering),
+		EnableExpiredTaskDeadLetter:     dc.GetBoolPropertyFilteredByDomain(dynamicconfig.MatchingEnableExpiredTaskDeadLetter),
+		ExpiredTaskLogSampleRate:        dc.GetFloat64PropertyFilteredByDomain(dynamicconfig.MatchingExpiredTaskLogSampleRate),
+		EnableWorkflowFairDispatch:      dc.GetBoolPropertyFilteredByTaskListInfo(dynamicconfig.MatchingEnableWorkflowFairDispatch),
+		EnableQueryPollerReservation:    dc.GetBoolPropertyFilteredByTaskListInfo(dynamicconfig.MatchingEnableQueryPollerReservation),
+		EnableEphemeralTaskList:         dc.GetBoolPropertyFilteredByTaskListInfo(dynamicconfig.MatchingEnableEphemeralTaskList),
 		MinTaskThrottlingBurstSize:      dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMinTaskThrottlingBurstSize),
 		MaxTaskDeleteBatchSize:          dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMaxTaskDeleteBatchSize),
+		CompletedTaskDeleteBatchSize:    dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingCompletedTaskDeleteBatchSize),
+		TaskDeleteFlushInterval:         dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingTaskDeleteFlushInterval),
+		TaskDispatchRPS:                 dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingTaskDispatchRPS),
+		MaxConcurrentActivities:         dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingTaskListMaxConcurrentActivities),
 		OutstandingTaskAppendsThreshold: dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingOutstandingTaskAppendsThreshold),
 		MaxTaskBatchSize:                dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingMaxTaskBatchSize),
 		ThrottledLogRPS:                 dc.GetIntProperty(dynamicconfig.MatchingThrottledLogRPS),
@@ -126,9 +235,12 @@ func NewConfig(dc *dynamicconfig.Collection) *Config {
 		ForwarderMaxRatePerSecond:       dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingForwarderMaxRatePerSecond),
 		ForwarderMaxChildrenPerNode:     dc.GetIntPropertyFilteredByTaskListInfo(dynamicconfig.MatchingForwarderMaxChildrenPerNode),
 		ShutdownDrainDuration:           dc.GetDurationProperty(dynamicconfig.MatchingShutdownDrainDuration),
+		EnableTaskListHandoff:           dc.GetBoolProperty(dynamicconfig.MatchingEnableTaskListHandoff),
 		EnableDebugMode:                 dc.GetBoolProperty(dynamicconfig.EnableDebugMode)(),
 		EnableTaskInfoLogByDomainID:     dc.GetBoolPropertyFilteredByDomainID(dynamicconfig.MatchingEnableTaskInfoLogByDomainID),
 		ActivityTaskSyncMatchWaitTime:   dc.GetDurationPropertyFilteredByDomain(dynamicconfig.MatchingActivityTaskSyncMatchWaitTime),
+		EphemeralTaskSyncMatchWaitTime:  dc.GetDurationPropertyFilteredByTaskListInfo(dynamicconfig.MatchingEphemeralTaskSyncMatchWaitTime),
+		dynamicConfig:                   dc,
 	}
 }
 
@@ -139,6 +251,9 @@ func newTaskListConfig(id *taskListID, config *Config, domainCache cache.DomainC
 	}
 
 	taskListName := id.name
+	// the partition counts and the drain mode are set for the task list as a whole,
+	// so all the partitions read them with the root name
+	rootTaskListName := id.GetRoot()
 	taskType := id.taskType
 	return &taskListConfig{
 		RangeSize: config.RangeSize,
@@ -154,6 +269,12 @@ func newTaskListConfig(id *taskListID, config *Config, domainCache cache.DomainC
 		MaxTasklistIdleTime: func() time.Duration {
 			return config.MaxTasklistIdleTime(domainName, taskListName, taskType)
 		},
+		PollerHistoryTTL: func() time.Duration {
+			return config.PollerHistoryTTL(domainName, taskListName, taskType)
+		},
+		PollerHistoryMaxSize: func() int {
+			return config.PollerHistoryMaxSize(domainName, taskListName, taskType)
+		},
 		MinTaskThrottlingBurstSize: func() int {
 			return config.MinTaskThrottlingBurstSize(domainName, taskListName, taskType)
 		},
@@ -163,9 +284,84 @@ func newTaskListConfig(id *taskListID, config *Config, domainCache cache.DomainC
 		LongPollExpirationInterval: func() time.Duration {
 			return config.LongPollExpirationInterval(domainName, taskListName, taskType)
 		},
+		IsolationGroupSpilloverDelay: func() time.Duration {
+			return config.IsolationGroupSpilloverDelay(domainName, taskListName, taskType)
+		},
+		AllIsolationGroups: func() []string {
+			var isolationGroups []string
+			for _, isolationGroup := range config.AllIsolationGroups() {
+				if group, ok := isolationGroup.(string); ok {
+					isolationGroups = append(isolationGroups, group)
+				}
+			}
+			return isolationGroups
+		},
+		EnableAdaptiveTaskRead: func() bool {
+			return config.EnableAdaptiveTaskRead(domainName, taskListName, taskType)
+		},
+		MaxGetTasksBatchSize: func() int {
+			return config.MaxGetTasksBatchSize(domainName, taskListName, taskType)
+		},
+		MaxEmptyTaskReadBackoff: func() time.Duration {
+			return config.MaxEmptyTaskReadBackoff(domainName, taskListName, taskType)
+		},
+		EnablePartitionAutoScaling: func() bool {
+			return config.EnablePartitionAutoScaling(domainName, taskListName, taskType)
+		},
+		PartitionScaleInterval: func() time.Duration {
+			return config.PartitionScaleInterval(domainName, taskListName, taskType)
+		},
+		PartitionScaleUpRPS: func() int {
+			return config.PartitionScaleUpRPS(domainName, taskListName, taskType)
+		},
+		PartitionScaleDownRPS: func() int {
+			return config.PartitionScaleDownRPS(domainName, taskListName, taskType)
+		},
+		MinTaskListPartitions: func() int {
+			return common.MaxInt(1, config.MinTaskListPartitions(domainName, taskListName, taskType))
+		},
+		MaxTaskListPartitions: func() int {
+			return common.MaxInt(1, config.MaxTaskListPartitions(domainName, taskListName, taskType))
+		},
+		DrainMode: func() bool {
+			return config.TaskListDrainMode(domainName, rootTaskListName, taskType)
+		},
+		EnableExpiredTaskDeadLetter: func() bool {
+			return config.EnableExpiredTaskDeadLetter(domainName)
+		},
+		ExpiredTaskLogSampleRate: func() float64 {
+			return config.ExpiredTaskLogSampleRate(domainName)
+		},
+		EnableWorkflowFairDispatch: func() bool {
+			return config.EnableWorkflowFairDispatch(domainName, taskListName, taskType)
+		},
+		EnableQueryPollerReservation: func() bool {
+			return config.EnableQueryPollerReservation(domainName, taskListName, taskType)
+		},
+		EnableEphemeralTaskList: func() bool {
+			return config.EnableEphemeralTaskList(domainName, rootTaskListName, taskType)
+		},
+		EphemeralTaskSyncMatchWaitTime: func() time.Duration {
+			return config.EphemeralTaskSyncMatchWaitTime(domainName, taskListName, taskType)
+		},
 		MaxTaskDeleteBatchSize: func() int {
 			return config.MaxTaskDeleteBatchSize(domainName, taskListName, taskType)
 		},
+		CompletedTaskDeleteBatchSize: func() int {
+			return config.CompletedTaskDeleteBatchSize(domainName, taskListName, taskType)
+		},
+		TaskDeleteFlushInterval: func() time.Duration {
+			return config.TaskDeleteFlushInterval(domainName, taskListName, taskType)
+		},
+		TaskDispatchRPS: func() int {
+			return config.TaskDispatchRPS(domainName, taskListName, taskType)
+		},
+		MaxConcurrentActivities: func() int {
+			if taskType != persistence.TaskListTypeActivity {
+				return 0
+			}
+			return config.MaxConcurrentActivities(domainName, rootTaskListName, taskType)
+		},
 		OutstandingTaskAppendsThreshold: func() int {
 			return config.OutstandingTaskAppendsThreshold(domainName, taskListName, taskType)
 		},
@@ -173,10 +369,10 @@ func newTaskListConfig(id *taskListID, config *Config, domainCache cache.DomainC
 			return config.MaxTaskBatchSize(domainName, taskListName, taskType)
 		},
 		NumWritePartitions: func() int {
-			return common.MaxInt(1, config.NumTasklistWritePartitions(domainName, taskListName, taskType))
+			return common.MaxInt(1, config.NumTasklistWritePartitions(domainName, rootTaskListName, taskType))
 		},
 		NumReadPartitions: func() int {
-			return common.MaxInt(1, config.NumTasklistReadPartitions(domainName, taskListName, taskType))
+			return common.MaxInt(1, config.NumTasklistReadPartitions(domainName, rootTaskListName, taskType))
 		},
 		forwarderConfig: forwarderConfig{
 			ForwarderMaxOutstandingPolls: func() int {
@@ -24,6 +24,7 @@ import (
 	"context"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
@@ -40,6 +41,8 @@ type (
 		taskType     int
 		rangeID      int64
 		ackLevel     int64
+		backlog      backlogSnapshot
+		paused       bool
 		store        persistence.TaskManager
 		logger       log.Logger
 	}
@@ -47,6 +50,12 @@ type (
 		rangeID  int64
 		ackLevel int64
 	}
+	// backlogSnapshot is the approximate backlog persisted along with the task list state,
+	// so that the backlog can be described by hosts which don't own the task list
+	backlogSnapshot struct {
+		count                 int64
+		oldestTaskCreatedTime time.Time
+	}
 )
 
 // newTaskListDB returns an instance of an object that represents
@@ -96,11 +105,16 @@ func (db *taskListDB) RenewLease() (taskListState, error) {
 	}
 	db.ackLevel = resp.TaskListInfo.AckLevel
 	db.rangeID = resp.TaskListInfo.RangeID
+	db.backlog = backlogSnapshot{
+		count:                 resp.TaskListInfo.ApproximateBacklogCount,
+		oldestTaskCreatedTime: resp.TaskListInfo.OldestTaskCreatedTime,
+	}
+	db.paused = resp.TaskListInfo.Paused
 	return taskListState{rangeID: db.rangeID, ackLevel: db.ackLevel}, nil
 }
 
-// UpdateState updates the taskList state with the given value
-func (db *taskListDB) UpdateState(ackLevel int64) error {
+// UpdateState updates the taskList state with the given values
+func (db *taskListDB) UpdateState(ackLevel int64, backlog backlogSnapshot) error {
 	db.Lock()
 	defer db.Unlock()
 	_, err := db.store.UpdateTaskList(context.Background(), &persistence.UpdateTaskListRequest{
@@ -111,11 +125,48 @@ func (db *taskListDB) UpdateState(ackLevel int64) error {
 			AckLevel: ackLevel,
 			RangeID:  db.rangeID,
 			Kind:     db.taskListKind,
+
+			ApproximateBacklogCount: backlog.count,
+			OldestTaskCreatedTime:   backlog.oldestTaskCreatedTime,
+			Paused:                  db.paused,
 		},
 		DomainName: db.domainName,
 	})
 	if err == nil {
 		db.ackLevel = ackLevel
+		db.backlog = backlog
+	}
+	return err
+}
+
+// IsPaused returns whether the dispatch of the tasks of the task list is paused in persistence
+func (db *taskListDB) IsPaused() bool {
+	db.Lock()
+	defer db.Unlock()
+	return db.paused
+}
+
+// UpdatePaused persists whether the dispatch of the tasks of the task list is paused
+func (db *taskListDB) UpdatePaused(paused bool) error {
+	db.Lock()
+	defer db.Unlock()
+	_, err := db.store.UpdateTaskList(context.Background(), &persistence.UpdateTaskListRequest{
+		TaskListInfo: &persistence.TaskListInfo{
+			DomainID: db.domainID,
+			Name:     db.taskListName,
+			TaskType: db.taskType,
+			AckLevel: db.ackLevel,
+			RangeID:  db.rangeID,
+			Kind:     db.taskListKind,
+
+			ApproximateBacklogCount: db.backlog.count,
+			OldestTaskCreatedTime:   db.backlog.oldestTaskCreatedTime,
+			Paused:                  paused,
+		},
+		DomainName: db.domainName,
+	})
+	if err == nil {
+		db.paused = paused
 	}
 	return err
 }
@@ -132,6 +183,10 @@ func (db *taskListDB) CreateTasks(tasks []*persistence.CreateTaskInfo) (*persist
 			AckLevel: db.ackLevel,
 			RangeID:  db.rangeID,
 			Kind:     db.taskListKind,
+
+			ApproximateBacklogCount: db.backlog.count,
+			OldestTaskCreatedTime:   db.backlog.oldestTaskCreatedTime,
+			Paused:                  db.paused,
 		},
 		Tasks:      tasks,
 		DomainName: db.domainName,
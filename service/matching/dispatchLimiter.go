@@ -0,0 +1,142 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/clock"
+)
+
+type (
+	// dispatchLimiter bounds the number of activities of a task list partition which are running at once. A poller
+	// takes a slot before it waits for a task, the slot is released when no activity is started with it, otherwise
+	// it is held by the started activity until matching is told that the activity finished. As matching may never
+	// be told, e.g. when the worker crashes, the slot of an activity is also released once its start to close
+	// timeout expires
+	dispatchLimiter struct {
+		sync.Mutex
+		timeSource clock.TimeSource
+		limit      int // 0 means no limit
+		pending    int // slots taken by the polls which have not started an activity yet
+		// expiry time of the slots held by the running activities, only tracked when there is a limit
+		running map[runningActivityKey]time.Time
+		// closed and replaced whenever a slot may have become available
+		releaseC chan struct{}
+	}
+
+	// runningActivityKey identifies the attempt of an activity holding a slot
+	runningActivityKey struct {
+		workflowID string
+		runID      string
+		scheduleID int64
+		attempt    int64
+	}
+)
+
+func newDispatchLimiter(timeSource clock.TimeSource) *dispatchLimiter {
+	return &dispatchLimiter{
+		timeSource: timeSource,
+		running:    make(map[runningActivityKey]time.Time),
+		releaseC:   make(chan struct{}),
+	}
+}
+
+// tryAcquire takes a slot when one is available, otherwise it returns a channel which is closed when a slot
+// may have become available, along with the time left until the slot of the next running activity expires
+func (l *dispatchLimiter) tryAcquire() (bool, <-chan struct{}, time.Duration) {
+	l.Lock()
+	defer l.Unlock()
+	if l.limit > 0 && l.pending+len(l.running) >= l.limit {
+		nextExpiry := l.expireLocked()
+		if l.pending+len(l.running) >= l.limit {
+			return false, l.releaseC, nextExpiry
+		}
+	}
+	l.pending++
+	return true, nil, 0
+}
+
+// release gives back a slot which was not used to start an activity
+func (l *dispatchLimiter) release() {
+	l.Lock()
+	defer l.Unlock()
+	l.pending--
+	l.notifyLocked()
+}
+
+// start hands a slot over to the activity started with it until the activity finishes or its timeout expires,
+// it returns false when the slot is released instead, as the activities are not limited
+func (l *dispatchLimiter) start(key runningActivityKey, timeout time.Duration) bool {
+	l.Lock()
+	defer l.Unlock()
+	l.pending--
+	if l.limit <= 0 {
+		l.notifyLocked()
+		return false
+	}
+	l.running[key] = l.timeSource.Now().Add(timeout)
+	return true
+}
+
+// finish releases the slot held by a running activity, if any
+func (l *dispatchLimiter) finish(key runningActivityKey) {
+	l.Lock()
+	defer l.Unlock()
+	if _, ok := l.running[key]; ok {
+		delete(l.running, key)
+		l.notifyLocked()
+	}
+}
+
+// setLimit updates the max number of running activities, the slots
+// already taken are kept when the limit is lowered
+func (l *dispatchLimiter) setLimit(limit int) {
+	l.Lock()
+	defer l.Unlock()
+	if l.limit != limit {
+		l.limit = limit
+		l.notifyLocked()
+	}
+}
+
+// expireLocked releases the slots of the running activities whose timeout expired, it returns
+// the time left until the next slot expires, 0 when no activity is running
+func (l *dispatchLimiter) expireLocked() time.Duration {
+	now := l.timeSource.Now()
+	var nextExpiry time.Duration
+	for key, expiry := range l.running {
+		if !expiry.After(now) {
+			delete(l.running, key)
+			continue
+		}
+		if left := expiry.Sub(now); nextExpiry == 0 || left < nextExpiry {
+			nextExpiry = left
+		}
+	}
+	return nextExpiry
+}
+
+func (l *dispatchLimiter) notifyLocked() {
+	close(l.releaseC)
+	l.releaseC = make(chan struct{})
+}
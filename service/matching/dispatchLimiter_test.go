@@ -0,0 +1,120 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/clock"
+)
+
+func TestDispatchLimiter(t *testing.T) {
+	limiter := newDispatchLimiter(clock.NewEventTimeSource().Update(time.Now()))
+	// no limit by default, and the slots of the started activities are released right away
+	ok, _, _ := limiter.tryAcquire()
+	require.True(t, ok)
+	require.False(t, limiter.start(runningActivityKey{scheduleID: 1}, time.Minute))
+	for i := 0; i < 3; i++ {
+		ok, _, _ = limiter.tryAcquire()
+		require.True(t, ok)
+	}
+
+	limiter.setLimit(3)
+	var releaseC <-chan struct{}
+	ok, releaseC, _ = limiter.tryAcquire()
+	require.False(t, ok)
+	select {
+	case <-releaseC:
+		require.Fail(t, "no slot was released")
+	default:
+	}
+
+	limiter.release()
+	<-releaseC
+	ok, _, _ = limiter.tryAcquire()
+	require.True(t, ok)
+
+	// lowering the limit keeps the slots already taken
+	limiter.setLimit(1)
+	limiter.release()
+	ok, _, _ = limiter.tryAcquire()
+	require.False(t, ok)
+	limiter.release()
+	limiter.release()
+	ok, _, _ = limiter.tryAcquire()
+	require.True(t, ok)
+
+	// raising the limit wakes up the waiters
+	ok, releaseC, _ = limiter.tryAcquire()
+	require.False(t, ok)
+	limiter.setLimit(0)
+	<-releaseC
+	ok, _, _ = limiter.tryAcquire()
+	require.True(t, ok)
+}
+
+func TestDispatchLimiterHoldsSlotsOfRunningActivities(t *testing.T) {
+	timeSource := clock.NewEventTimeSource().Update(time.Now())
+	limiter := newDispatchLimiter(timeSource)
+	limiter.setLimit(2)
+	activity1 := runningActivityKey{workflowID: "wf", runID: "run", scheduleID: 5, attempt: 0}
+	activity2 := runningActivityKey{workflowID: "wf", runID: "run", scheduleID: 5, attempt: 1}
+
+	for _, key := range []runningActivityKey{activity1, activity2} {
+		ok, _, _ := limiter.tryAcquire()
+		require.True(t, ok)
+		require.True(t, limiter.start(key, time.Duration(key.attempt+1)*time.Minute))
+	}
+
+	// the started activities keep their slots, the next one expires with the timeout of activity1
+	ok, releaseC, nextExpiry := limiter.tryAcquire()
+	require.False(t, ok)
+	require.Equal(t, time.Minute, nextExpiry)
+
+	// finishing an activity which doesn't hold a slot is a no-op
+	limiter.finish(runningActivityKey{workflowID: "wf", runID: "run", scheduleID: 6})
+	select {
+	case <-releaseC:
+		require.Fail(t, "no slot was released")
+	default:
+	}
+
+	limiter.finish(activity2)
+	<-releaseC
+	ok, _, _ = limiter.tryAcquire()
+	require.True(t, ok)
+	limiter.release()
+
+	// the slot of an activity which is never finished is released once its timeout expires
+	ok, _, _ = limiter.tryAcquire()
+	require.True(t, ok)
+	require.True(t, limiter.start(activity2, 2*time.Minute))
+	ok, _, _ = limiter.tryAcquire()
+	require.False(t, ok)
+	timeSource.Update(timeSource.Now().Add(time.Minute))
+	ok, _, _ = limiter.tryAcquire()
+	require.True(t, ok)
+	ok, _, _ = limiter.tryAcquire()
+	require.False(t, ok)
+}
@@ -23,9 +23,15 @@ package matching
 import (
 	"context"
 	"errors"
+	"strconv"
+	"sync"
 	"sync/atomic"
+	"time"
+
+	"go.uber.org/yarpc"
 
 	"github.com/uber/cadence/client/matching"
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/quotas"
 	"github.com/uber/cadence/common/types"
@@ -57,6 +63,17 @@ type (
 		// todo: implement a rate limiter that automatically
 		// adjusts rate based on ServiceBusy errors from API calls
 		limiter *quotas.DynamicRateLimiter
+
+		// the forwarding rate is shared fairly by the sources of the forwarded tasks, that is
+		// this partition and its child partitions, so that a hot child partition cannot use up
+		// the rate of its siblings. A source is active until it forwarded no task for a while
+		sourcesLock   sync.Mutex
+		sources       map[string]*forwarderSource // keyed by child partition name, empty for this partition
+		activeSources int32
+	}
+	forwarderSource struct {
+		limiter      *quotas.DynamicRateLimiter
+		lastForwardT time.Time
 	}
 	// ForwarderReqToken is the token that must be acquired before
 	// making forwarder API calls. This type contains the state
@@ -73,6 +90,9 @@ var (
 	errForwarderSlowDown   = errors.New("limit exceeded")
 )
 
+// time after which a source that forwarded no task stops sharing the forwarding rate
+const forwarderSourceIdleTimeout = 10 * time.Second
+
 // noopForwarderTokenC refers to a token channel that blocks forever
 var noopForwarderTokenC <-chan *ForwarderReqToken = make(chan *ForwarderReqToken)
 
@@ -101,6 +121,7 @@ func newForwarder(
 		outstandingTasksLimit: int32(cfg.ForwarderMaxOutstandingTasks()),
 		outstandingPollsLimit: int32(cfg.ForwarderMaxOutstandingPolls()),
 		limiter:               quotas.NewDynamicRateLimiter(rpsFunc),
+		sources:               make(map[string]*forwarderSource),
 	}
 	fwdr.addReqToken.Store(newForwarderReqToken(cfg.ForwarderMaxOutstandingTasks()))
 	fwdr.pollReqToken.Store(newForwarderReqToken(cfg.ForwarderMaxOutstandingPolls()))
@@ -118,11 +139,15 @@ func (fwdr *Forwarder) ForwardTask(ctx context.Context, task *InternalTask) erro
 		return errNoParent
 	}
 
-	if !fwdr.limiter.Allow() {
+	if !fwdr.sourceLimiter(task.forwardedFrom).Allow() || !fwdr.limiter.Allow() {
 		return errForwarderSlowDown
 	}
 
 	var err error
+	var opts []yarpc.CallOption
+	if task.event.Priority != 0 {
+		opts = append(opts, yarpc.WithHeader(common.TaskPriorityHeaderName, strconv.Itoa(task.event.Priority)))
+	}
 
 	switch fwdr.taskListID.taskType {
 	case persistence.TaskListTypeDecision:
@@ -137,7 +162,7 @@ func (fwdr *Forwarder) ForwardTask(ctx context.Context, task *InternalTask) erro
 			ScheduleToStartTimeoutSeconds: &task.event.ScheduleToStartTimeout,
 			Source:                        &task.source,
 			ForwardedFrom:                 fwdr.taskListID.name,
-		})
+		}, opts...)
 	case persistence.TaskListTypeActivity:
 		err = fwdr.client.AddActivityTask(ctx, &types.AddActivityTaskRequest{
 			DomainUUID:       fwdr.taskListID.domainID,
@@ -151,7 +176,7 @@ func (fwdr *Forwarder) ForwardTask(ctx context.Context, task *InternalTask) erro
 			ScheduleToStartTimeoutSeconds: &task.event.ScheduleToStartTimeout,
 			Source:                        &task.source,
 			ForwardedFrom:                 fwdr.taskListID.name,
-		})
+		}, opts...)
 	default:
 		return errInvalidTaskListType
 	}
@@ -200,6 +225,13 @@ func (fwdr *Forwarder) ForwardPoll(ctx context.Context) (*InternalTask, error) {
 
 	pollerID, _ := ctx.Value(pollerIDKey).(string)
 	identity, _ := ctx.Value(identityKey).(string)
+	buildID, _ := ctx.Value(buildIDKey).(string)
+	// the parent partition only receives forwarded polls when workers poll this partition,
+	// so the rate limit set by the workers is passed along for it to be applied on all the partitions
+	var taskListMetadata *types.TaskListMetadata
+	if maxDispatch, _ := ctx.Value(maxDispatchKey).(*float64); maxDispatch != nil {
+		taskListMetadata = &types.TaskListMetadata{MaxTasksPerSecond: maxDispatch}
+	}
 
 	switch fwdr.taskListID.taskType {
 	case persistence.TaskListTypeDecision:
@@ -211,7 +243,8 @@ func (fwdr *Forwarder) ForwardPoll(ctx context.Context) (*InternalTask, error) {
 					Name: name,
 					Kind: &fwdr.taskListKind,
 				},
-				Identity: identity,
+				Identity:       identity,
+				BinaryChecksum: buildID,
 			},
 			ForwardedFrom: fwdr.taskListID.name,
 		})
@@ -228,7 +261,8 @@ func (fwdr *Forwarder) ForwardPoll(ctx context.Context) (*InternalTask, error) {
 					Name: name,
 					Kind: &fwdr.taskListKind,
 				},
-				Identity: identity,
+				Identity:         identity,
+				TaskListMetadata: taskListMetadata,
 			},
 			ForwardedFrom: fwdr.taskListID.name,
 		})
@@ -257,6 +291,36 @@ func (fwdr *Forwarder) PollReqTokenC() <-chan *ForwarderReqToken {
 	return fwdr.pollReqToken.Load().(*ForwarderReqToken).ch
 }
 
+// sourceLimiter returns the rate limiter of a source of forwarded tasks, its rate is the fair
+// share of the forwarding rate among the active sources
+func (fwdr *Forwarder) sourceLimiter(forwardedFrom string) *quotas.DynamicRateLimiter {
+	fwdr.sourcesLock.Lock()
+	defer fwdr.sourcesLock.Unlock()
+
+	now := time.Now()
+	for name, source := range fwdr.sources {
+		if name != forwardedFrom && now.Sub(source.lastForwardT) > forwarderSourceIdleTimeout {
+			delete(fwdr.sources, name)
+		}
+	}
+	source, ok := fwdr.sources[forwardedFrom]
+	if !ok {
+		source = &forwarderSource{}
+		fwdr.sources[forwardedFrom] = source
+	}
+	atomic.StoreInt32(&fwdr.activeSources, int32(len(fwdr.sources)))
+	if source.limiter == nil {
+		// created once the source is counted as the initial rate is its share
+		source.limiter = quotas.NewDynamicRateLimiter(fwdr.sourceRPS)
+	}
+	source.lastForwardT = now
+	return source.limiter
+}
+
+func (fwdr *Forwarder) sourceRPS() float64 {
+	return float64(fwdr.cfg.ForwarderMaxRatePerSecond()) / float64(atomic.LoadInt32(&fwdr.activeSources))
+}
+
 func (fwdr *Forwarder) refreshTokenC(value *atomic.Value, curr *int32, maxLimit int32) {
 	currLimit := atomic.LoadInt32(curr)
 	if currLimit != maxLimit {
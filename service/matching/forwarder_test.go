@@ -103,6 +103,24 @@ func (t *ForwarderTestSuite) TestForwardDecisionTask() {
 	t.Equal(t.taskList.name, request.GetForwardedFrom())
 }
 
+func (t *ForwarderTestSuite) TestForwardTaskWithPriority() {
+	t.usingTasklistPartition(persistence.TaskListTypeDecision)
+
+	var options []yarpc.CallOption
+	t.client.EXPECT().AddDecisionTask(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(arg0 context.Context, arg1 *types.AddDecisionTaskRequest, option ...yarpc.CallOption) {
+			options = option
+		},
+	).Return(nil).Times(1)
+
+	taskInfo := t.newTaskInfo()
+	taskInfo.Priority = 3
+	task := newInternalTask(taskInfo, nil, types.TaskSourceHistory, "", false, nil)
+	t.NoError(t.fwdr.ForwardTask(context.Background(), task))
+	// the priority of the task is kept by the parent partition
+	t.Equal([]yarpc.CallOption{yarpc.WithHeader(common.TaskPriorityHeaderName, "3")}, options)
+}
+
 func (t *ForwarderTestSuite) TestForwardActivityTask() {
 	t.usingTasklistPartition(persistence.TaskListTypeActivity)
 
@@ -141,6 +159,26 @@ func (t *ForwarderTestSuite) TestForwardTaskRateExceeded() {
 	t.Equal(errForwarderSlowDown, t.fwdr.ForwardTask(context.Background(), task))
 }
 
+func (t *ForwarderTestSuite) TestForwardTaskRateSharedByChildPartitions() {
+	t.usingTasklistPartition(persistence.TaskListTypeActivity)
+	t.cfg.ForwarderMaxRatePerSecond = func() int { return 4 }
+	t.fwdr = newForwarder(t.cfg, t.taskList, types.TaskListKindNormal, t.client)
+
+	t.client.EXPECT().AddActivityTask(gomock.Any(), gomock.Any()).Return(nil).Times(4)
+	coldTask := newInternalTask(t.newTaskInfo(), nil, types.TaskSourceHistory, "/__cadence_sys/tl0/21", false, nil)
+	hotTask := newInternalTask(t.newTaskInfo(), nil, types.TaskSourceHistory, "/__cadence_sys/tl0/22", false, nil)
+	t.NoError(t.fwdr.ForwardTask(context.Background(), coldTask))
+
+	// the hot child partition is limited to its share of the rate
+	t.NoError(t.fwdr.ForwardTask(context.Background(), hotTask))
+	t.NoError(t.fwdr.ForwardTask(context.Background(), hotTask))
+	t.Equal(errForwarderSlowDown, t.fwdr.ForwardTask(context.Background(), hotTask))
+
+	// which leaves room for its sibling
+	t.NoError(t.fwdr.ForwardTask(context.Background(), coldTask))
+	t.Equal(errForwarderSlowDown, t.fwdr.ForwardTask(context.Background(), coldTask))
+}
+
 func (t *ForwarderTestSuite) TestForwardQueryTaskError() {
 	task := newInternalQueryTask("id1", &types.MatchingQueryWorkflowRequest{})
 	_, err := t.fwdr.ForwardQueryTask(context.Background(), task)
@@ -230,6 +268,7 @@ func (t *ForwarderTestSuite) TestForwardPollForActivity() {
 	pollerID := uuid.New()
 	ctx := context.WithValue(context.Background(), pollerIDKey, pollerID)
 	ctx = context.WithValue(ctx, identityKey, "id1")
+	ctx = context.WithValue(ctx, maxDispatchKey, common.Float64Ptr(100))
 	resp := &types.PollForActivityTaskResponse{}
 
 	var request *types.MatchingPollForActivityTaskRequest
@@ -248,6 +287,7 @@ func (t *ForwarderTestSuite) TestForwardPollForActivity() {
 	t.Equal("id1", request.GetPollRequest().GetIdentity())
 	t.Equal(t.taskList.Parent(20), request.GetPollRequest().GetTaskList().GetName())
 	t.Equal(t.fwdr.taskListKind, request.GetPollRequest().GetTaskList().GetKind())
+	t.Equal(common.Float64Ptr(100), request.GetPollRequest().TaskListMetadata.MaxTasksPerSecond)
 	t.Equal(resp, task.pollForActivityResponse())
 	t.Nil(task.pollForDecisionResponse())
 }
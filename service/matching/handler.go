@@ -29,10 +29,12 @@ import (
 
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/quotas"
 	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/watchdog"
 )
 
 var _ Handler = (*handlerImpl)(nil)
@@ -53,6 +55,13 @@ type (
 		PollForDecisionTask(context.Context, *types.MatchingPollForDecisionTaskRequest) (*types.MatchingPollForDecisionTaskResponse, error)
 		QueryWorkflow(context.Context, *types.MatchingQueryWorkflowRequest) (*types.QueryWorkflowResponse, error)
 		RespondQueryTaskCompleted(context.Context, *types.MatchingRespondQueryTaskCompletedRequest) error
+		UnloadTaskList(context.Context, *types.MatchingUnloadTaskListRequest) error
+		GetTaskListDrainStatus(context.Context, *types.MatchingGetTaskListDrainStatusRequest) (*types.GetTaskListDrainStatusResponse, error)
+		PauseTaskList(context.Context, *types.MatchingPauseTaskListRequest) error
+		ResumeTaskList(context.Context, *types.MatchingResumeTaskListRequest) error
+		RecordActivityTaskFinished(context.Context, *types.MatchingRecordActivityTaskFinishedRequest) error
+		InvalidateCaches(context.Context, *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error)
+		DescribeWatchdog(context.Context, *types.DescribeWatchdogRequest) (*types.DescribeWatchdogResponse, error)
 	}
 
 	// handlerImpl is an implementation for matching service independent of wire protocol
@@ -65,6 +74,8 @@ type (
 		logger            log.Logger
 		throttledLogger   log.Logger
 		domainCache       cache.DomainCache
+		cacheRegistry     *cache.Registry
+		watchdog          *watchdog.Watchdog
 	}
 )
 
@@ -77,48 +88,63 @@ func NewHandler(
 	engine Engine,
 	config *Config,
 	domainCache cache.DomainCache,
+	cacheRegistry *cache.Registry,
+	serviceWatchdog *watchdog.Watchdog,
 	metricsClient metrics.Client,
 	logger log.Logger,
 	throttledLogger log.Logger,
 ) Handler {
 	handler := &handlerImpl{
-		metricsClient: metricsClient,
-		userRateLimiter: quotas.NewMultiStageRateLimiter(
-			quotas.NewDynamicRateLimiter(config.UserRPS.AsFloat64()),
-			quotas.NewCollection(quotas.DynamicRateLimiterFactory(
-				func(domain string) float64 {
-					domainRPS := float64(config.DomainUserRPS(domain))
-					if domainRPS > 0 {
-						return domainRPS
-					}
-					// if domain rps not set, use host rps to keep the old behavior
-					return float64(config.UserRPS())
-				})),
-		),
-		workerRateLimiter: quotas.NewMultiStageRateLimiter(
-			quotas.NewDynamicRateLimiter(config.WorkerRPS.AsFloat64()),
-			quotas.NewCollection(quotas.DynamicRateLimiterFactory(
-				func(domain string) float64 {
-					domainRPS := float64(config.DomainWorkerRPS(domain))
-					if domainRPS > 0 {
-						return domainRPS
-					}
-					// if domain rps not set, use host rps to keep the old behavior
-					return float64(config.WorkerRPS())
-				})),
-		),
-		engine:          engine,
-		logger:          logger,
-		throttledLogger: throttledLogger,
-		domainCache:     domainCache,
+		metricsClient:     metricsClient,
+		userRateLimiter:   newPooledRateLimiter(config, config.UserRPS, config.DomainUserRPS),
+		workerRateLimiter: newPooledRateLimiter(config, config.WorkerRPS, config.DomainWorkerRPS),
+		engine:            engine,
+		logger:            logger,
+		throttledLogger:   throttledLogger,
+		domainCache:       domainCache,
+		cacheRegistry:     cacheRegistry,
+		watchdog:          serviceWatchdog,
 	}
 	// prevent us from trying to serve requests before matching engine is started and ready
 	handler.startWG.Add(1)
 	return handler
 }
 
+// newPooledRateLimiter limits the requests of a domain with the rps of its domain, then with the rps of the host or
+// with the rps of the resource pool of the domain if it is pinned to one
+func newPooledRateLimiter(
+	config *Config,
+	hostRPS dynamicconfig.IntPropertyFn,
+	domainRPS dynamicconfig.IntPropertyFnWithDomainFilter,
+) quotas.Policy {
+	domainLimiters := quotas.NewCollection(quotas.DynamicRateLimiterFactory(
+		func(domain string) float64 {
+			if rps := float64(domainRPS(domain)); rps > 0 {
+				return rps
+			}
+			// if domain rps not set, use host rps to keep the old behavior
+			return float64(hostRPS())
+		}))
+	return quotas.NewPooledPolicy(
+		config.DomainResourcePool,
+		quotas.NewMultiStageRateLimiter(quotas.NewDynamicRateLimiter(hostRPS.AsFloat64()), domainLimiters),
+		func(pool string) quotas.Policy {
+			return quotas.NewMultiStageRateLimiter(
+				quotas.NewDynamicRateLimiter(func() float64 {
+					if rps := common.GetIntFromDynamicConfigMapProperty(config.ResourcePoolRPS(), pool); rps > 0 {
+						return float64(rps)
+					}
+					return float64(hostRPS())
+				}),
+				domainLimiters,
+			)
+		},
+	)
+}
+
 // Start starts the handler
 func (h *handlerImpl) Start() {
+	h.engine.Start()
 	h.startWG.Done()
 }
 
@@ -455,6 +481,139 @@ func (h *handlerImpl) GetTaskListsByDomain(
 	return response, hCtx.handleErr(err)
 }
 
+// UnloadTaskList force-unloads a task list manager from this host
+func (h *handlerImpl) UnloadTaskList(
+	ctx context.Context,
+	request *types.MatchingUnloadTaskListRequest,
+) (retError error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &retError) }()
+
+	domainName := h.domainName(request.GetDomainUUID())
+	hCtx := h.newHandlerContext(
+		ctx,
+		domainName,
+		request.GetTaskList(),
+		metrics.MatchingUnloadTaskListScope,
+	)
+
+	sw := hCtx.startProfiling(&h.startWG)
+	defer sw.Stop()
+
+	err := h.engine.UnloadTaskList(hCtx, request)
+	return hCtx.handleErr(err)
+}
+
+// GetTaskListDrainStatus reports whether a task list in drain mode has no task left in any of its partitions
+func (h *handlerImpl) GetTaskListDrainStatus(
+	ctx context.Context,
+	request *types.MatchingGetTaskListDrainStatusRequest,
+) (resp *types.GetTaskListDrainStatusResponse, retError error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &retError) }()
+
+	domainName := h.domainName(request.GetDomainUUID())
+	hCtx := h.newHandlerContext(
+		ctx,
+		domainName,
+		request.GetTaskList(),
+		metrics.MatchingGetTaskListDrainStatusScope,
+	)
+
+	sw := hCtx.startProfiling(&h.startWG)
+	defer sw.Stop()
+
+	response, err := h.engine.GetTaskListDrainStatus(hCtx, request)
+	return response, hCtx.handleErr(err)
+}
+
+// PauseTaskList stops the dispatch of the tasks of a task list partition until it is resumed
+func (h *handlerImpl) PauseTaskList(
+	ctx context.Context,
+	request *types.MatchingPauseTaskListRequest,
+) (retError error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &retError) }()
+
+	domainName := h.domainName(request.GetDomainUUID())
+	hCtx := h.newHandlerContext(
+		ctx,
+		domainName,
+		request.GetTaskList(),
+		metrics.MatchingPauseTaskListScope,
+	)
+
+	sw := hCtx.startProfiling(&h.startWG)
+	defer sw.Stop()
+
+	err := h.engine.PauseTaskList(hCtx, request)
+	return hCtx.handleErr(err)
+}
+
+// ResumeTaskList resumes the dispatch of the tasks of a paused task list partition
+func (h *handlerImpl) ResumeTaskList(
+	ctx context.Context,
+	request *types.MatchingResumeTaskListRequest,
+) (retError error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &retError) }()
+
+	domainName := h.domainName(request.GetDomainUUID())
+	hCtx := h.newHandlerContext(
+		ctx,
+		domainName,
+		request.GetTaskList(),
+		metrics.MatchingResumeTaskListScope,
+	)
+
+	sw := hCtx.startProfiling(&h.startWG)
+	defer sw.Stop()
+
+	err := h.engine.ResumeTaskList(hCtx, request)
+	return hCtx.handleErr(err)
+}
+
+// RecordActivityTaskFinished releases the slot held by an activity dispatched from a task list partition
+// which limits the activities running at once, once the worker responded with the result of the activity
+func (h *handlerImpl) RecordActivityTaskFinished(
+	ctx context.Context,
+	request *types.MatchingRecordActivityTaskFinishedRequest,
+) (retError error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &retError) }()
+
+	domainName := h.domainName(request.GetDomainUUID())
+	hCtx := h.newHandlerContext(
+		ctx,
+		domainName,
+		request.GetTaskList(),
+		metrics.MatchingRecordActivityTaskFinishedScope,
+	)
+
+	sw := hCtx.startProfiling(&h.startWG)
+	defer sw.Stop()
+
+	err := h.engine.RecordActivityTaskFinished(hCtx, request)
+	return hCtx.handleErr(err)
+}
+
+// InvalidateCaches removes the matching entries from the in-memory caches of the host, so that they are reloaded
+func (h *handlerImpl) InvalidateCaches(
+	ctx context.Context,
+	request *types.InvalidateCachesRequest,
+) (resp *types.InvalidateCachesResponse, retError error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &retError) }()
+	h.startWG.Wait()
+
+	return cache.InvalidateCaches(h.cacheRegistry, request)
+}
+
+// DescribeWatchdog describes the resources tracked by the watchdog of the host
+func (h *handlerImpl) DescribeWatchdog(
+	ctx context.Context,
+	request *types.DescribeWatchdogRequest,
+) (resp *types.DescribeWatchdogResponse, retError error) {
+	defer func() { log.CapturePanic(recover(), h.logger, &retError) }()
+	h.startWG.Wait()
+
+	return watchdog.Describe(h.watchdog), nil
+}
+
 func (h *handlerImpl) domainName(id string) string {
 	domainName, err := h.domainCache.GetDomainName(id)
 	if err != nil {
@@ -115,6 +115,36 @@ func (mr *MockHandlerMockRecorder) DescribeTaskList(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTaskList", reflect.TypeOf((*MockHandler)(nil).DescribeTaskList), arg0, arg1)
 }
 
+// DescribeWatchdog mocks base method.
+func (m *MockHandler) DescribeWatchdog(arg0 context.Context, arg1 *types.DescribeWatchdogRequest) (*types.DescribeWatchdogResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeWatchdog", arg0, arg1)
+	ret0, _ := ret[0].(*types.DescribeWatchdogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeWatchdog indicates an expected call of DescribeWatchdog.
+func (mr *MockHandlerMockRecorder) DescribeWatchdog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWatchdog", reflect.TypeOf((*MockHandler)(nil).DescribeWatchdog), arg0, arg1)
+}
+
+// GetTaskListDrainStatus mocks base method.
+func (m *MockHandler) GetTaskListDrainStatus(arg0 context.Context, arg1 *types.MatchingGetTaskListDrainStatusRequest) (*types.GetTaskListDrainStatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskListDrainStatus", arg0, arg1)
+	ret0, _ := ret[0].(*types.GetTaskListDrainStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskListDrainStatus indicates an expected call of GetTaskListDrainStatus.
+func (mr *MockHandlerMockRecorder) GetTaskListDrainStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskListDrainStatus", reflect.TypeOf((*MockHandler)(nil).GetTaskListDrainStatus), arg0, arg1)
+}
+
 // GetTaskListsByDomain mocks base method.
 func (m *MockHandler) GetTaskListsByDomain(arg0 context.Context, arg1 *types.GetTaskListsByDomainRequest) (*types.GetTaskListsByDomainResponse, error) {
 	m.ctrl.T.Helper()
@@ -145,6 +175,21 @@ func (mr *MockHandlerMockRecorder) Health(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockHandler)(nil).Health), arg0)
 }
 
+// InvalidateCaches mocks base method.
+func (m *MockHandler) InvalidateCaches(arg0 context.Context, arg1 *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateCaches", arg0, arg1)
+	ret0, _ := ret[0].(*types.InvalidateCachesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvalidateCaches indicates an expected call of InvalidateCaches.
+func (mr *MockHandlerMockRecorder) InvalidateCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateCaches", reflect.TypeOf((*MockHandler)(nil).InvalidateCaches), arg0, arg1)
+}
+
 // ListTaskListPartitions mocks base method.
 func (m *MockHandler) ListTaskListPartitions(arg0 context.Context, arg1 *types.MatchingListTaskListPartitionsRequest) (*types.ListTaskListPartitionsResponse, error) {
 	m.ctrl.T.Helper()
@@ -160,6 +205,20 @@ func (mr *MockHandlerMockRecorder) ListTaskListPartitions(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskListPartitions", reflect.TypeOf((*MockHandler)(nil).ListTaskListPartitions), arg0, arg1)
 }
 
+// PauseTaskList mocks base method.
+func (m *MockHandler) PauseTaskList(arg0 context.Context, arg1 *types.MatchingPauseTaskListRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseTaskList", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseTaskList indicates an expected call of PauseTaskList.
+func (mr *MockHandlerMockRecorder) PauseTaskList(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseTaskList", reflect.TypeOf((*MockHandler)(nil).PauseTaskList), arg0, arg1)
+}
+
 // PollForActivityTask mocks base method.
 func (m *MockHandler) PollForActivityTask(arg0 context.Context, arg1 *types.MatchingPollForActivityTaskRequest) (*types.PollForActivityTaskResponse, error) {
 	m.ctrl.T.Helper()
@@ -205,6 +264,20 @@ func (mr *MockHandlerMockRecorder) QueryWorkflow(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryWorkflow", reflect.TypeOf((*MockHandler)(nil).QueryWorkflow), arg0, arg1)
 }
 
+// RecordActivityTaskFinished mocks base method.
+func (m *MockHandler) RecordActivityTaskFinished(arg0 context.Context, arg1 *types.MatchingRecordActivityTaskFinishedRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordActivityTaskFinished", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordActivityTaskFinished indicates an expected call of RecordActivityTaskFinished.
+func (mr *MockHandlerMockRecorder) RecordActivityTaskFinished(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordActivityTaskFinished", reflect.TypeOf((*MockHandler)(nil).RecordActivityTaskFinished), arg0, arg1)
+}
+
 // RespondQueryTaskCompleted mocks base method.
 func (m *MockHandler) RespondQueryTaskCompleted(arg0 context.Context, arg1 *types.MatchingRespondQueryTaskCompletedRequest) error {
 	m.ctrl.T.Helper()
@@ -219,6 +292,20 @@ func (mr *MockHandlerMockRecorder) RespondQueryTaskCompleted(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondQueryTaskCompleted", reflect.TypeOf((*MockHandler)(nil).RespondQueryTaskCompleted), arg0, arg1)
 }
 
+// ResumeTaskList mocks base method.
+func (m *MockHandler) ResumeTaskList(arg0 context.Context, arg1 *types.MatchingResumeTaskListRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeTaskList", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeTaskList indicates an expected call of ResumeTaskList.
+func (mr *MockHandlerMockRecorder) ResumeTaskList(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeTaskList", reflect.TypeOf((*MockHandler)(nil).ResumeTaskList), arg0, arg1)
+}
+
 // Start mocks base method.
 func (m *MockHandler) Start() {
 	m.ctrl.T.Helper()
@@ -242,3 +329,17 @@ func (mr *MockHandlerMockRecorder) Stop() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockHandler)(nil).Stop))
 }
+
+// UnloadTaskList mocks base method.
+func (m *MockHandler) UnloadTaskList(arg0 context.Context, arg1 *types.MatchingUnloadTaskListRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnloadTaskList", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnloadTaskList indicates an expected call of UnloadTaskList.
+func (mr *MockHandlerMockRecorder) UnloadTaskList(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnloadTaskList", reflect.TypeOf((*MockHandler)(nil).UnloadTaskList), arg0, arg1)
+}
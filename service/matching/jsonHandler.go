@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+
+	"go.uber.org/yarpc"
+	yarpcjson "go.uber.org/yarpc/encoding/json"
+
+	"github.com/uber/cadence/client/matching"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/common/types/mapper/json"
+)
+
+// jsonHandler serves the matching APIs which are not in the matching IDL yet with the json encoding
+type jsonHandler struct {
+	h Handler
+}
+
+func newJSONHandler(h Handler) jsonHandler {
+	return jsonHandler{h}
+}
+
+func (j jsonHandler) register(dispatcher *yarpc.Dispatcher) {
+	dispatcher.Register(yarpcjson.Procedure(matching.UnloadTaskListProcedure, j.UnloadTaskList))
+	dispatcher.Register(yarpcjson.Procedure(matching.GetTaskListDrainStatusProcedure, j.GetTaskListDrainStatus))
+	dispatcher.Register(yarpcjson.Procedure(matching.PauseTaskListProcedure, j.PauseTaskList))
+	dispatcher.Register(yarpcjson.Procedure(matching.ResumeTaskListProcedure, j.ResumeTaskList))
+	dispatcher.Register(yarpcjson.Procedure(matching.RecordActivityTaskFinishedProcedure, j.RecordActivityTaskFinished))
+	dispatcher.Register(yarpcjson.Procedure(matching.InvalidateCachesProcedure, j.InvalidateCaches))
+	dispatcher.Register(yarpcjson.Procedure(matching.DescribeWatchdogProcedure, j.DescribeWatchdog))
+}
+
+func (j jsonHandler) UnloadTaskList(ctx context.Context, request *types.MatchingUnloadTaskListRequest) (*struct{}, error) {
+	err := j.h.UnloadTaskList(ctx, request)
+	return &struct{}{}, json.FromError(err)
+}
+
+func (j jsonHandler) GetTaskListDrainStatus(ctx context.Context, request *types.MatchingGetTaskListDrainStatusRequest) (*types.GetTaskListDrainStatusResponse, error) {
+	response, err := j.h.GetTaskListDrainStatus(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j jsonHandler) PauseTaskList(ctx context.Context, request *types.MatchingPauseTaskListRequest) (*struct{}, error) {
+	err := j.h.PauseTaskList(ctx, request)
+	return &struct{}{}, json.FromError(err)
+}
+
+func (j jsonHandler) ResumeTaskList(ctx context.Context, request *types.MatchingResumeTaskListRequest) (*struct{}, error) {
+	err := j.h.ResumeTaskList(ctx, request)
+	return &struct{}{}, json.FromError(err)
+}
+
+func (j jsonHandler) RecordActivityTaskFinished(ctx context.Context, request *types.MatchingRecordActivityTaskFinishedRequest) (*struct{}, error) {
+	err := j.h.RecordActivityTaskFinished(ctx, request)
+	return &struct{}{}, json.FromError(err)
+}
+
+func (j jsonHandler) InvalidateCaches(ctx context.Context, request *types.InvalidateCachesRequest) (*types.InvalidateCachesResponse, error) {
+	response, err := j.h.InvalidateCaches(ctx, request)
+	return response, json.FromError(err)
+}
+
+func (j jsonHandler) DescribeWatchdog(ctx context.Context, request *types.DescribeWatchdogRequest) (*types.DescribeWatchdogResponse, error) {
+	response, err := j.h.DescribeWatchdog(ctx, request)
+	return response, json.FromError(err)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/yarpc/yarpcerrors"
+
+	"github.com/uber/cadence/common/types"
+)
+
+func TestJSONHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := NewMockHandler(ctrl)
+	jh := newJSONHandler(h)
+	ctx := context.Background()
+	internalErr := &types.InternalServiceError{Message: "test"}
+	expectedErr := yarpcerrors.InternalErrorf("test")
+
+	t.Run("UnloadTaskList", func(t *testing.T) {
+		h.EXPECT().UnloadTaskList(ctx, &types.MatchingUnloadTaskListRequest{}).Return(internalErr).Times(1)
+		_, err := jh.UnloadTaskList(ctx, &types.MatchingUnloadTaskListRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("GetTaskListDrainStatus", func(t *testing.T) {
+		h.EXPECT().GetTaskListDrainStatus(ctx, &types.MatchingGetTaskListDrainStatusRequest{}).Return(nil, internalErr).Times(1)
+		_, err := jh.GetTaskListDrainStatus(ctx, &types.MatchingGetTaskListDrainStatusRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("PauseTaskList", func(t *testing.T) {
+		h.EXPECT().PauseTaskList(ctx, &types.MatchingPauseTaskListRequest{}).Return(internalErr).Times(1)
+		_, err := jh.PauseTaskList(ctx, &types.MatchingPauseTaskListRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("ResumeTaskList", func(t *testing.T) {
+		h.EXPECT().ResumeTaskList(ctx, &types.MatchingResumeTaskListRequest{}).Return(internalErr).Times(1)
+		_, err := jh.ResumeTaskList(ctx, &types.MatchingResumeTaskListRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("RecordActivityTaskFinished", func(t *testing.T) {
+		h.EXPECT().RecordActivityTaskFinished(ctx, &types.MatchingRecordActivityTaskFinishedRequest{}).Return(internalErr).Times(1)
+		_, err := jh.RecordActivityTaskFinished(ctx, &types.MatchingRecordActivityTaskFinishedRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("InvalidateCaches", func(t *testing.T) {
+		h.EXPECT().InvalidateCaches(ctx, &types.InvalidateCachesRequest{}).Return(nil, internalErr).Times(1)
+		_, err := jh.InvalidateCaches(ctx, &types.InvalidateCachesRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("DescribeWatchdog", func(t *testing.T) {
+		h.EXPECT().DescribeWatchdog(ctx, &types.DescribeWatchdogRequest{}).Return(nil, internalErr).Times(1)
+		_, err := jh.DescribeWatchdog(ctx, &types.DescribeWatchdogRequest{})
+		assert.Equal(t, expectedErr, err)
+	})
+}
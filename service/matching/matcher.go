@@ -23,10 +23,16 @@ package matching
 import (
 	"context"
 	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.uber.org/yarpc"
 	"golang.org/x/time/rate"
 
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/quotas"
 	"github.com/uber/cadence/common/types"
@@ -43,12 +49,42 @@ type TaskMatcher struct {
 	// are interested in queryTasks but not others. Example is when domain is
 	// not active in a cluster
 	queryTaskC chan *InternalTask
+	// when the query poller reservation is enabled, the backlog is not dispatched while a
+	// query task waits for a local poller, so that the next poller picks the query up
+	// instead of being kept busy by the backlog
+	queryGate                    *queryGate
+	enableQueryPollerReservation func() bool
+	// synchronous task channels to match the tasks of an isolation group with
+	// the pollers of the same group, keyed by isolation group. Pollers of a
+	// group consume both from the channel of their group and from taskC.
+	// Only the configured isolation groups have a channel, and the channel of
+	// a group is removed once no poller or task uses it
+	isolatedTaskC   map[string]*isolatedTaskC
+	isolationLock   sync.Mutex
+	isolationGroups func() []string
+	// time a task waits for a poller of its isolation group before it spills over to taskC
+	isolationGroupSpilloverDelay func() time.Duration
 	// ratelimiter that limits the rate at which tasks can be dispatched to consumers
 	limiter *quotas.RateLimiter
+	// limits the number of activities running at once, the limit of the task list is divided across its partitions
+	dispatchLimiter         *dispatchLimiter
+	maxConcurrentActivities func() int
 
 	fwdr          *Forwarder
 	scope         metrics.Scope // domain metric scope
 	numPartitions func() int    // number of task list partitions
+	// time taken to match tasks, split by local sync matches, forwarded sync matches and backlog dispatches
+	latencies *matchLatencies
+
+	// number of local pollers blocked waiting for a task, a sustained non-zero value
+	// together with poll timeouts means there is no work rather than too few pollers
+	waitingPollers int32
+}
+
+// isolatedTaskC is the task channel of an isolation group along with the number of pollers and tasks using it
+type isolatedTaskC struct {
+	taskC chan *InternalTask
+	refs  int
 }
 
 const (
@@ -60,8 +96,8 @@ var errTasklistThrottled = errors.New("cannot add to tasklist, limit exceeded")
 
 // newTaskMatcher returns an task matcher instance. The returned instance can be
 // used by task producers and consumers to find a match. Both sync matches and non-sync
-// matches should use this implementation
-func newTaskMatcher(config *taskListConfig, fwdr *Forwarder, scope metrics.Scope) *TaskMatcher {
+// matches should use this implementation. The match latencies are emitted to latencyScope
+func newTaskMatcher(config *taskListConfig, fwdr *Forwarder, scope metrics.Scope, latencyScope metrics.Scope) *TaskMatcher {
 	dPtr := _defaultTaskDispatchRPS
 	limiter := quotas.NewRateLimiter(&dPtr, _defaultTaskDispatchRPSTTL, config.MinTaskThrottlingBurstSize())
 	return &TaskMatcher{
@@ -70,7 +106,17 @@ func newTaskMatcher(config *taskListConfig, fwdr *Forwarder, scope metrics.Scope
 		fwdr:          fwdr,
 		taskC:         make(chan *InternalTask),
 		queryTaskC:    make(chan *InternalTask),
+		isolatedTaskC: make(map[string]*isolatedTaskC),
 		numPartitions: config.NumReadPartitions,
+		latencies:     newMatchLatencies(clock.NewRealTimeSource(), latencyScope),
+
+		queryGate:                    newQueryGate(),
+		enableQueryPollerReservation: config.EnableQueryPollerReservation,
+
+		isolationGroups:              config.AllIsolationGroups,
+		isolationGroupSpilloverDelay: config.IsolationGroupSpilloverDelay,
+		dispatchLimiter:              newDispatchLimiter(clock.NewRealTimeSource()),
+		maxConcurrentActivities:      config.MaxConcurrentActivities,
 	}
 }
 
@@ -92,6 +138,11 @@ func newTaskMatcher(config *taskListConfig, fwdr *Forwarder, scope metrics.Scope
 // waiting for a token until the provided context timeout. Rate limits are
 // not enforced for forwarded tasks from child partition.
 //
+// Isolation group:
+// When the task has an isolation group, this method might block up to
+// the isolation group spillover delay waiting for a poller of the same
+// group before offering the task to any poller.
+//
 // Forwarded tasks that originated from db backlog:
 // When this method is called with a task that is forwarded from a
 // remote partition and if (1) this task list is root (2) task
@@ -114,12 +165,23 @@ func (tm *TaskMatcher) Offer(ctx context.Context, task *InternalTask) (bool, err
 		}
 	}
 
+	startT := time.Now()
+	if tm.offerToIsolationGroup(ctx, task) {
+		if task.responseC != nil {
+			err = <-task.responseC
+			tm.recordLatency(localSyncMatch, startT, err)
+			return true, err
+		}
+		return false, nil
+	}
+
 	select {
 	case tm.taskC <- task: // poller picked up the task
 		if task.responseC != nil {
 			// if there is a response channel, block until resp is received
 			// and return error if the response contains error
 			err = <-task.responseC
+			tm.recordLatency(localSyncMatch, startT, err)
 			return true, err
 		}
 		return false, nil
@@ -131,6 +193,7 @@ func (tm *TaskMatcher) Offer(ctx context.Context, task *InternalTask) (bool, err
 			if err := tm.fwdr.ForwardTask(ctx, task); err == nil {
 				// task was remotely sync matched on the parent partition
 				token.release()
+				tm.recordLatency(forwardedSyncMatch, startT, nil)
 				return true, nil
 			}
 			token.release()
@@ -140,7 +203,11 @@ func (tm *TaskMatcher) Offer(ctx context.Context, task *InternalTask) (bool, err
 				task.isForwarded() { // task came from a child partition
 				// a forwarded backlog task from a child partition, block trying
 				// to match with a poller until ctx timeout
-				return tm.offerOrTimeout(ctx, task)
+				matched, err := tm.offerOrTimeout(ctx, task)
+				if matched {
+					tm.recordLatency(localSyncMatch, startT, err)
+				}
+				return matched, err
 			}
 		}
 
@@ -154,22 +221,97 @@ func (tm *TaskMatcher) Offer(ctx context.Context, task *InternalTask) (bool, err
 }
 
 func (tm *TaskMatcher) offerOrTimeout(ctx context.Context, task *InternalTask) (bool, error) {
+	if tm.offerToIsolationGroup(ctx, task) {
+		return tm.awaitOfferResponse(ctx, task)
+	}
 	select {
 	case tm.taskC <- task: // poller picked up the task
-		if task.responseC != nil {
-			select {
-			case err := <-task.responseC:
-				return true, err
-			case <-ctx.Done():
-				return false, nil
-			}
-		}
-		return task.activityTaskDispatchInfo != nil, nil
+		return tm.awaitOfferResponse(ctx, task)
 	case <-ctx.Done():
 		return false, nil
 	}
 }
 
+func (tm *TaskMatcher) awaitOfferResponse(ctx context.Context, task *InternalTask) (bool, error) {
+	if task.responseC != nil {
+		select {
+		case err := <-task.responseC:
+			return true, err
+		case <-ctx.Done():
+			return false, nil
+		}
+	}
+	return task.activityTaskDispatchInfo != nil, nil
+}
+
+// offerToIsolationGroup offers the task to the pollers of its isolation group, waiting up to
+// the spillover delay for one of them. Returns false if the task has no isolation group or
+// wasn't picked up, in which case it is to be offered to any poller
+func (tm *TaskMatcher) offerToIsolationGroup(ctx context.Context, task *InternalTask) bool {
+	taskC, release := tm.acquireIsolatedTaskC(task.isolationGroup)
+	defer release()
+	if taskC == nil {
+		return false
+	}
+	select {
+	case taskC <- task:
+		tm.scope.IncCounter(metrics.IsolationGroupMatchPerTaskListCounter)
+		return true
+	default:
+	}
+
+	if delay := tm.isolationGroupSpilloverDelay(); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case taskC <- task:
+			tm.scope.IncCounter(metrics.IsolationGroupMatchPerTaskListCounter)
+			return true
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
+	tm.scope.IncCounter(metrics.IsolationSpilloverPerTaskListCounter)
+	return false
+}
+
+// acquireIsolatedTaskC returns the task channel of the isolation group, nil if the group is empty or
+// isn't one of the configured isolation groups. The returned function must be called once the channel
+// is no longer used, the channel is removed when neither a poller nor a task uses it anymore
+func (tm *TaskMatcher) acquireIsolatedTaskC(isolationGroup string) (chan *InternalTask, func()) {
+	if !tm.isValidIsolationGroup(isolationGroup) {
+		return nil, func() {}
+	}
+	tm.isolationLock.Lock()
+	defer tm.isolationLock.Unlock()
+	entry, ok := tm.isolatedTaskC[isolationGroup]
+	if !ok {
+		entry = &isolatedTaskC{taskC: make(chan *InternalTask)}
+		tm.isolatedTaskC[isolationGroup] = entry
+	}
+	entry.refs++
+	return entry.taskC, func() {
+		tm.isolationLock.Lock()
+		defer tm.isolationLock.Unlock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(tm.isolatedTaskC, isolationGroup)
+		}
+	}
+}
+
+func (tm *TaskMatcher) isValidIsolationGroup(isolationGroup string) bool {
+	if isolationGroup == "" {
+		return false
+	}
+	for _, group := range tm.isolationGroups() {
+		if group == isolationGroup {
+			return true
+		}
+	}
+	return false
+}
+
 // OfferQuery will either match task to local poller or will forward query task.
 // Local match is always attempted before forwarding is attempted. If local match occurs
 // response and error are both nil, if forwarding occurs then response or error is returned.
@@ -181,6 +323,10 @@ func (tm *TaskMatcher) OfferQuery(ctx context.Context, task *InternalTask) (*typ
 	default:
 	}
 
+	if tm.enableQueryPollerReservation() {
+		tm.queryGate.enter()
+		defer tm.queryGate.leave()
+	}
 	fwdrTokenC := tm.fwdrAddReqTokenC()
 
 	for {
@@ -208,17 +354,25 @@ func (tm *TaskMatcher) OfferQuery(ctx context.Context, task *InternalTask) (*typ
 }
 
 // MustOffer blocks until a consumer is found to handle this task
+// While a query task waits for a local poller, the task is not offered
+// so that the query is matched with the next poller
 // Returns error only when context is canceled or the ratelimit is set to zero (allow nothing)
 // The passed in context MUST NOT have a deadline associated with it
 func (tm *TaskMatcher) MustOffer(ctx context.Context, task *InternalTask) error {
 	if _, err := tm.ratelimit(ctx); err != nil {
 		return err
 	}
+	startT := time.Now()
 
+	queryPendingC, err := tm.waitQueriesMatched(ctx)
+	if err != nil {
+		return err
+	}
 	// attempt a match with local poller first. When that
 	// doesn't succeed, try both local match and remote match
 	select {
 	case tm.taskC <- task:
+		tm.recordLatency(backlogDispatch, startT, nil)
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
@@ -229,7 +383,13 @@ forLoop:
 	for {
 		select {
 		case tm.taskC <- task:
+			tm.recordLatency(backlogDispatch, startT, nil)
 			return nil
+		case <-queryPendingC:
+			if queryPendingC, err = tm.waitQueriesMatched(ctx); err != nil {
+				return err
+			}
+			continue forLoop
 		case token := <-tm.fwdrAddReqTokenC():
 			childCtx, cancel := context.WithDeadline(ctx, time.Now().Add(time.Second*2))
 			err := tm.fwdr.ForwardTask(childCtx, task)
@@ -242,6 +402,7 @@ forLoop:
 				select {
 				case tm.taskC <- task:
 					cancel()
+					tm.recordLatency(backlogDispatch, startT, nil)
 					return nil
 				case <-childCtx.Done():
 				case <-ctx.Done():
@@ -256,6 +417,7 @@ forLoop:
 			// in turn dispatched the task to a poller. Make sure we delete the
 			// task from the database
 			task.finish(nil)
+			tm.recordLatency(backlogDispatch, startT, nil)
 			return nil
 		case <-ctx.Done():
 			return ctx.Err()
@@ -263,31 +425,131 @@ forLoop:
 	}
 }
 
+// waitQueriesMatched blocks while query tasks wait for a local poller, it returns
+// a channel closed once a query task is pending again
+func (tm *TaskMatcher) waitQueriesMatched(ctx context.Context) (<-chan struct{}, error) {
+	pendingC, clearC := tm.queryGate.wait()
+	select {
+	case <-clearC:
+		return pendingC, nil
+	default:
+	}
+	select {
+	case <-clearC:
+		pendingC, _ = tm.queryGate.wait()
+		return pendingC, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // Poll blocks until a task is found or context deadline is exceeded
 // On success, the returned task could be a query task or a regular task
+// When the poll request has an isolation group, the tasks of this group
+// are polled in addition to the tasks without or spilled from a group
+// When the max number of running activities is reached, only query tasks
+// are polled until an activity finishes. The returned activity task holds a
+// slot of the limiter, which is handed over to the activity when it starts
 // Returns ErrNoTasks when context deadline is exceeded
 func (tm *TaskMatcher) Poll(ctx context.Context) (*InternalTask, error) {
+	if task, err := tm.waitDispatchSlot(ctx); task != nil || err != nil {
+		return task, err
+	}
+	task, err := tm.pollTask(ctx)
+	if err != nil || task.isQuery() || task.isStarted() {
+		// query tasks are not limited and the tasks started by a parent partition
+		// are limited there
+		tm.dispatchLimiter.release()
+		return task, err
+	}
+	task.dispatchLimiter = tm.dispatchLimiter
+	return task, nil
+}
+
+func (tm *TaskMatcher) pollTask(ctx context.Context) (*InternalTask, error) {
+	isolatedTaskC, release := tm.acquireIsolatedTaskC(isolationGroupFromContext(ctx))
+	defer release()
 	// try local match first without blocking until context timeout
-	if task, err := tm.pollNonBlocking(ctx, tm.taskC, tm.queryTaskC); err == nil {
+	if task, err := tm.pollNonBlocking(ctx, isolatedTaskC, tm.taskC, tm.queryTaskC); err == nil {
 		return task, nil
 	}
 	// there is no local poller available to pickup this task. Now block waiting
 	// either for a local poller or a forwarding token to be available. When a
 	// forwarding token becomes available, send this poll to a parent partition
-	return tm.pollOrForward(ctx, tm.taskC, tm.queryTaskC)
+	return tm.pollOrForward(ctx, isolatedTaskC, tm.taskC, tm.queryTaskC)
 }
 
 // PollForQuery blocks until a *query* task is found or context deadline is exceeded
 // Returns ErrNoTasks when context deadline is exceeded
 func (tm *TaskMatcher) PollForQuery(ctx context.Context) (*InternalTask, error) {
 	// try local match first without blocking until context timeout
-	if task, err := tm.pollNonBlocking(ctx, nil, tm.queryTaskC); err == nil {
+	if task, err := tm.pollNonBlocking(ctx, nil, nil, tm.queryTaskC); err == nil {
 		return task, nil
 	}
 	// there is no local poller available to pickup this task. Now block waiting
 	// either for a local poller or a forwarding token to be available. When a
 	// forwarding token becomes available, send this poll to a parent partition
-	return tm.pollOrForward(ctx, nil, tm.queryTaskC)
+	return tm.pollOrForward(ctx, nil, nil, tm.queryTaskC)
+}
+
+// FinishActivity releases the slot held by a running activity which was dispatched from this partition
+func (tm *TaskMatcher) FinishActivity(key runningActivityKey) {
+	tm.dispatchLimiter.finish(key)
+}
+
+// waitDispatchSlot blocks until a dispatch slot is taken, returning a query task
+// matched in the meantime or ErrNoTasks when context deadline is exceeded
+func (tm *TaskMatcher) waitDispatchSlot(ctx context.Context) (*InternalTask, error) {
+	tm.dispatchLimiter.setLimit(tm.partitionMaxConcurrentActivities())
+	var expiryTimer *time.Timer
+	defer func() {
+		if expiryTimer != nil {
+			expiryTimer.Stop()
+		}
+	}()
+	for {
+		ok, releaseC, nextExpiry := tm.dispatchLimiter.tryAcquire()
+		if ok {
+			return nil, nil
+		}
+		var expiryC <-chan time.Time
+		if nextExpiry > 0 {
+			if expiryTimer == nil {
+				expiryTimer = time.NewTimer(nextExpiry)
+			} else {
+				if !expiryTimer.Stop() {
+					select {
+					case <-expiryTimer.C:
+					default:
+					}
+				}
+				expiryTimer.Reset(nextExpiry)
+			}
+			expiryC = expiryTimer.C
+		}
+		select {
+		case <-releaseC:
+		case <-expiryC:
+		case task := <-tm.queryTaskC:
+			tm.scope.IncCounter(metrics.PollSuccessWithSyncPerTaskListCounter)
+			tm.scope.IncCounter(metrics.PollSuccessPerTaskListCounter)
+			return task, nil
+		case <-ctx.Done():
+			tm.scope.IncCounter(metrics.PollTimeoutPerTaskListCounter)
+			return nil, ErrNoTasks
+		}
+	}
+}
+
+// partitionMaxConcurrentActivities returns the max number of running activities of this partition,
+// that is the limit of the task list divided equally across all partitions
+func (tm *TaskMatcher) partitionMaxConcurrentActivities() int {
+	limit := tm.maxConcurrentActivities()
+	if limit <= 0 {
+		return 0
+	}
+	nPartitions := tm.numPartitions()
+	return (limit + nPartitions - 1) / nPartitions
 }
 
 // UpdateRatelimit updates the task dispatch rate
@@ -311,10 +573,20 @@ func (tm *TaskMatcher) Rate() float64 {
 
 func (tm *TaskMatcher) pollOrForward(
 	ctx context.Context,
+	isolatedTaskC <-chan *InternalTask,
 	taskC <-chan *InternalTask,
 	queryTaskC <-chan *InternalTask,
 ) (*InternalTask, error) {
+	tm.updateWaitingPollers(1)
+	defer tm.updateWaitingPollers(-1)
+
 	select {
+	case task := <-isolatedTaskC:
+		if task.responseC != nil {
+			tm.scope.IncCounter(metrics.PollSuccessWithSyncPerTaskListCounter)
+		}
+		tm.scope.IncCounter(metrics.PollSuccessPerTaskListCounter)
+		return task, nil
 	case task := <-taskC:
 		if task.responseC != nil {
 			tm.scope.IncCounter(metrics.PollSuccessWithSyncPerTaskListCounter)
@@ -334,16 +606,23 @@ func (tm *TaskMatcher) pollOrForward(
 			return task, nil
 		}
 		token.release()
-		return tm.poll(ctx, taskC, queryTaskC)
+		return tm.poll(ctx, isolatedTaskC, taskC, queryTaskC)
 	}
 }
 
 func (tm *TaskMatcher) poll(
 	ctx context.Context,
+	isolatedTaskC <-chan *InternalTask,
 	taskC <-chan *InternalTask,
 	queryTaskC <-chan *InternalTask,
 ) (*InternalTask, error) {
 	select {
+	case task := <-isolatedTaskC:
+		if task.responseC != nil {
+			tm.scope.IncCounter(metrics.PollSuccessWithSyncPerTaskListCounter)
+		}
+		tm.scope.IncCounter(metrics.PollSuccessPerTaskListCounter)
+		return task, nil
 	case task := <-taskC:
 		if task.responseC != nil {
 			tm.scope.IncCounter(metrics.PollSuccessWithSyncPerTaskListCounter)
@@ -362,10 +641,17 @@ func (tm *TaskMatcher) poll(
 
 func (tm *TaskMatcher) pollNonBlocking(
 	ctx context.Context,
+	isolatedTaskC <-chan *InternalTask,
 	taskC <-chan *InternalTask,
 	queryTaskC <-chan *InternalTask,
 ) (*InternalTask, error) {
 	select {
+	case task := <-isolatedTaskC:
+		if task.responseC != nil {
+			tm.scope.IncCounter(metrics.PollSuccessWithSyncPerTaskListCounter)
+		}
+		tm.scope.IncCounter(metrics.PollSuccessPerTaskListCounter)
+		return task, nil
 	case task := <-taskC:
 		if task.responseC != nil {
 			tm.scope.IncCounter(metrics.PollSuccessWithSyncPerTaskListCounter)
@@ -381,6 +667,28 @@ func (tm *TaskMatcher) pollNonBlocking(
 	}
 }
 
+// recordLatency records the time taken by a match of the given kind started at startT,
+// the matches failed by the poller are not recorded
+func (tm *TaskMatcher) recordLatency(kind matchLatencyKind, startT time.Time, err error) {
+	if err == nil {
+		tm.latencies.record(kind, time.Since(startT))
+	}
+}
+
+// matchLatency returns the average latency of the matches of the given kind over the last minute
+func (tm *TaskMatcher) matchLatency(kind matchLatencyKind) time.Duration {
+	return tm.latencies.average(kind)
+}
+
+func (tm *TaskMatcher) getWaitingPollers() int32 {
+	return atomic.LoadInt32(&tm.waitingPollers)
+}
+
+func (tm *TaskMatcher) updateWaitingPollers(delta int32) {
+	waiting := atomic.AddInt32(&tm.waitingPollers, delta)
+	tm.scope.UpdateGauge(metrics.PollersWaitingPerTaskListGauge, float64(waiting))
+}
+
 func (tm *TaskMatcher) fwdrPollReqTokenC() <-chan *ForwarderReqToken {
 	if tm.fwdr == nil {
 		return noopForwarderTokenC
@@ -426,3 +734,27 @@ func (tm *TaskMatcher) ratelimit(ctx context.Context) (*rate.Reservation, error)
 func (tm *TaskMatcher) isForwardingAllowed() bool {
 	return tm.fwdr != nil
 }
+
+// isolationGroupFromContext returns the isolation group of the inbound call
+// made with the context, empty if the call doesn't have any
+func isolationGroupFromContext(ctx context.Context) string {
+	call := yarpc.CallFromContext(ctx)
+	if call == nil {
+		return ""
+	}
+	return call.Header(common.IsolationGroupHeaderName)
+}
+
+// taskPriorityFromContext returns the priority of the task added with the inbound call
+// made with the context, 0 if the call doesn't have any or it isn't a number
+func taskPriorityFromContext(ctx context.Context) int {
+	call := yarpc.CallFromContext(ctx)
+	if call == nil {
+		return 0
+	}
+	priority, err := strconv.Atoi(call.Header(common.TaskPriorityHeaderName))
+	if err != nil {
+		return 0
+	}
+	return priority
+}
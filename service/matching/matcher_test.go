@@ -29,7 +29,9 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
 	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/yarpctest"
 
 	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
@@ -70,18 +72,109 @@ func (t *MatcherTestSuite) SetupTest() {
 	}
 	t.cfg = tlCfg
 	t.fwdr = newForwarder(&t.cfg.forwarderConfig, t.taskList, types.TaskListKindNormal, t.client)
-	t.matcher = newTaskMatcher(tlCfg, t.fwdr, metrics.NoopScope(metrics.Matching))
+	t.matcher = newTaskMatcher(tlCfg, t.fwdr, metrics.NoopScope(metrics.Matching), metrics.NoopScope(metrics.Matching))
 
 	rootTaskList := newTestTaskListID(t.taskList.domainID, t.taskList.Parent(20), persistence.TaskListTypeDecision)
 	rootTasklistCfg, err := newTaskListConfig(rootTaskList, cfg, t.newDomainCache())
 	t.NoError(err)
-	t.rootMatcher = newTaskMatcher(rootTasklistCfg, nil, metrics.NoopScope(metrics.Matching))
+	t.rootMatcher = newTaskMatcher(rootTasklistCfg, nil, metrics.NoopScope(metrics.Matching), metrics.NoopScope(metrics.Matching))
 }
 
 func (t *MatcherTestSuite) TearDownTest() {
 	t.controller.Finish()
 }
 
+func (t *MatcherTestSuite) TestUpdateRatelimitDividedAcrossPartitions() {
+	cfg := NewConfig(dynamicconfig.NewNopCollection())
+	// the partition count is only set for the root name
+	cfg.NumTasklistReadPartitions = func(domain string, taskList string, taskType int) int {
+		if taskList == "tl0" {
+			return 4
+		}
+		return 1
+	}
+	for _, name := range []string{t.taskList.name, t.taskList.Parent(20)} {
+		taskList := newTestTaskListID(t.taskList.domainID, name, persistence.TaskListTypeDecision)
+		tlCfg, err := newTaskListConfig(taskList, cfg, t.newDomainCache())
+		t.NoError(err)
+		matcher := newTaskMatcher(tlCfg, nil, metrics.NoopScope(metrics.Matching), metrics.NoopScope(metrics.Matching))
+		matcher.UpdateRatelimit(common.Float64Ptr(40))
+		t.Equal(10.0, matcher.Rate(), name)
+	}
+}
+
+func (t *MatcherTestSuite) TestPollLimitedByMaxConcurrentActivities() {
+	t.rootMatcher.maxConcurrentActivities = func() int { return 1 }
+	offer := func() *InternalTask {
+		task := newInternalTask(t.newTaskInfo(), nil, types.TaskSourceDbBacklog, "", false, nil)
+		go func() {
+			t.NoError(t.rootMatcher.MustOffer(context.Background(), task))
+		}()
+		return task
+	}
+	poll := func(timeout time.Duration) (*InternalTask, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return t.rootMatcher.Poll(ctx)
+	}
+
+	// the slot of a task which doesn't start an activity is released when the task is finished
+	task1 := offer()
+	polled, err := poll(time.Second)
+	t.NoError(err)
+	t.Equal(task1, polled)
+	polled.finish(&types.InternalServiceError{Message: "failed to start"})
+	task1 = offer()
+	polled, err = poll(time.Second)
+	t.NoError(err)
+	t.Equal(task1, polled)
+
+	// the slot is held by the activity once it is started, until it finishes
+	t.True(polled.markActivityStarted(0, time.Minute))
+	polled.finish(nil)
+	task2 := offer()
+	_, err = poll(50 * time.Millisecond)
+	t.Equal(ErrNoTasks, err)
+
+	// query tasks are not limited
+	query := newInternalQueryTask(uuid.New(), &types.MatchingQueryWorkflowRequest{})
+	go func() {
+		_, err := t.rootMatcher.OfferQuery(context.Background(), query)
+		t.NoError(err)
+	}()
+	polled, err = poll(time.Second)
+	t.NoError(err)
+	t.Equal(query, polled)
+	polled.finish(nil)
+
+	t.rootMatcher.FinishActivity(runningActivityKey{
+		workflowID: task1.event.WorkflowID,
+		runID:      task1.event.RunID,
+		scheduleID: task1.event.ScheduleID,
+	})
+	polled, err = poll(time.Second)
+	t.NoError(err)
+	t.Equal(task2, polled)
+	polled.finish(nil)
+}
+
+func (t *MatcherTestSuite) TestPollNotLimitedWithoutMaxConcurrentActivities() {
+	for i := 0; i < 3; i++ {
+		task := newInternalTask(t.newTaskInfo(), nil, types.TaskSourceDbBacklog, "", false, nil)
+		go func() {
+			t.NoError(t.rootMatcher.MustOffer(context.Background(), task))
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		polled, err := t.rootMatcher.Poll(ctx)
+		cancel()
+		t.NoError(err)
+		t.Equal(task, polled)
+		// the activities are not tracked, so the token is not given a task list to tell when they finish
+		t.False(polled.markActivityStarted(0, time.Minute))
+		polled.finish(nil)
+	}
+}
+
 func (t *MatcherTestSuite) TestLocalSyncMatch() {
 	// force disable remote forwarding
 	<-t.fwdr.AddReqTokenC()
@@ -101,6 +194,92 @@ func (t *MatcherTestSuite) TestLocalSyncMatch() {
 	wait()
 	t.NoError(err)
 	t.True(syncMatch)
+	t.NotZero(t.matcher.matchLatency(localSyncMatch))
+	t.Zero(t.matcher.matchLatency(forwardedSyncMatch))
+}
+
+func (t *MatcherTestSuite) TestIsolationGroupSyncMatch() {
+	// force disable remote forwarding
+	<-t.fwdr.AddReqTokenC()
+	<-t.fwdr.PollReqTokenC()
+	t.matcher.isolationGroupSpilloverDelay = func() time.Duration { return time.Second }
+	t.matcher.isolationGroups = func() []string { return []string{"zone-a", "zone-b"} }
+
+	wait := ensureAsyncReady(time.Second, func(ctx context.Context) {
+		ctx = yarpctest.ContextWithCall(ctx, &yarpctest.Call{Headers: map[string]string{common.IsolationGroupHeaderName: "zone-a"}})
+		task, err := t.matcher.Poll(ctx)
+		if err == nil {
+			task.finish(nil)
+		}
+	})
+
+	task := newInternalTask(t.newTaskInfo(), nil, types.TaskSourceHistory, "", true, nil)
+	task.isolationGroup = "zone-a"
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	start := time.Now()
+	syncMatch, err := t.matcher.Offer(ctx, task)
+	cancel()
+	wait()
+	t.NoError(err)
+	t.True(syncMatch)
+	t.Less(time.Since(start), 500*time.Millisecond, "task should be matched with the poller of its group without waiting for the spillover")
+	t.Empty(t.matcher.isolatedTaskC, "the channel of the group should be removed once unused")
+}
+
+func (t *MatcherTestSuite) TestIsolationGroupSpillover() {
+	// force disable remote forwarding
+	<-t.fwdr.AddReqTokenC()
+	<-t.fwdr.PollReqTokenC()
+	spilloverDelay := 100 * time.Millisecond
+	t.matcher.isolationGroupSpilloverDelay = func() time.Duration { return spilloverDelay }
+	t.matcher.isolationGroups = func() []string { return []string{"zone-a", "zone-b"} }
+
+	wait := ensureAsyncReady(time.Second, func(ctx context.Context) {
+		ctx = yarpctest.ContextWithCall(ctx, &yarpctest.Call{Headers: map[string]string{common.IsolationGroupHeaderName: "zone-b"}})
+		task, err := t.matcher.Poll(ctx)
+		if err == nil {
+			task.finish(nil)
+		}
+	})
+
+	task := newInternalTask(t.newTaskInfo(), nil, types.TaskSourceHistory, "", true, nil)
+	task.isolationGroup = "zone-a"
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	start := time.Now()
+	syncMatch, err := t.matcher.Offer(ctx, task)
+	cancel()
+	wait()
+	t.NoError(err)
+	t.True(syncMatch)
+	t.GreaterOrEqual(time.Since(start), spilloverDelay, "task should wait for a poller of its group before spilling over")
+}
+
+func (t *MatcherTestSuite) TestIsolationGroupNotConfigured() {
+	// force disable remote forwarding
+	<-t.fwdr.AddReqTokenC()
+	<-t.fwdr.PollReqTokenC()
+	t.matcher.isolationGroupSpilloverDelay = func() time.Duration { return time.Second }
+	t.matcher.isolationGroups = func() []string { return []string{"zone-a"} }
+
+	wait := ensureAsyncReady(time.Second, func(ctx context.Context) {
+		ctx = yarpctest.ContextWithCall(ctx, &yarpctest.Call{Headers: map[string]string{common.IsolationGroupHeaderName: "zone-x"}})
+		task, err := t.matcher.Poll(ctx)
+		if err == nil {
+			task.finish(nil)
+		}
+	})
+
+	task := newInternalTask(t.newTaskInfo(), nil, types.TaskSourceHistory, "", true, nil)
+	task.isolationGroup = "zone-x"
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	start := time.Now()
+	syncMatch, err := t.matcher.Offer(ctx, task)
+	cancel()
+	wait()
+	t.NoError(err)
+	t.True(syncMatch)
+	t.Less(time.Since(start), 500*time.Millisecond, "a group which isn't configured should be ignored")
+	t.Empty(t.matcher.isolatedTaskC)
 }
 
 func (t *MatcherTestSuite) TestRemoteSyncMatch() {
@@ -173,6 +352,10 @@ func (t *MatcherTestSuite) testRemoteSyncMatch(taskSource types.TaskSource) {
 	t.True(remoteSyncMatch)
 	t.Equal(t.taskList.name, req.GetForwardedFrom())
 	t.Equal(t.taskList.Parent(20), req.GetTaskList().GetName())
+	// the task is matched locally on the root partition, which is a forwarded match for the child
+	t.NotZero(t.matcher.matchLatency(forwardedSyncMatch))
+	t.Zero(t.matcher.matchLatency(localSyncMatch))
+	t.NotZero(t.rootMatcher.matchLatency(localSyncMatch))
 }
 
 func (t *MatcherTestSuite) TestSyncMatchFailure() {
@@ -315,6 +498,56 @@ func (t *MatcherTestSuite) TestMustOfferLocalMatch() {
 	cancel()
 	wait()
 	t.NoError(err)
+	t.NotZero(t.matcher.matchLatency(backlogDispatch))
+}
+
+func (t *MatcherTestSuite) TestMustOfferHeldWhileQueryPending() {
+	// force disable remote forwarding
+	<-t.fwdr.AddReqTokenC()
+	<-t.fwdr.PollReqTokenC()
+	t.matcher.enableQueryPollerReservation = func() bool { return true }
+
+	queryDone := make(chan error, 1)
+	go func() {
+		query := newInternalQueryTask(uuid.New(), &types.MatchingQueryWorkflowRequest{})
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err := t.matcher.OfferQuery(ctx, query)
+		queryDone <- err
+	}()
+	t.Eventually(func() bool {
+		pendingC, _ := t.matcher.queryGate.wait()
+		select {
+		case <-pendingC:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+
+	offerDone := make(chan error, 1)
+	go func() {
+		task := newInternalTask(t.newTaskInfo(), nil, types.TaskSourceDbBacklog, "", false, nil)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		offerDone <- t.matcher.MustOffer(ctx, task)
+	}()
+	// give the backlog task the chance to race the query for the poller
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	task, err := t.matcher.Poll(ctx)
+	t.NoError(err)
+	t.True(task.isQuery())
+	task.finish(nil)
+	t.NoError(<-queryDone)
+
+	task, err = t.matcher.Poll(ctx)
+	t.NoError(err)
+	t.False(task.isQuery())
+	task.finish(nil)
+	t.NoError(<-offerDone)
 }
 
 func (t *MatcherTestSuite) TestMustOfferRemoteMatch() {
@@ -401,6 +634,33 @@ func (t *MatcherTestSuite) TestRemotePoll() {
 	t.True(task.isStarted())
 }
 
+func (t *MatcherTestSuite) TestPollWaitingPollersGauge() {
+	scope := tally.NewTestScope("test", nil)
+	matcher := newTaskMatcher(t.cfg, nil, metrics.NewClient(scope, metrics.Matching).Scope(metrics.MatchingTaskListMgrScope), metrics.NoopScope(metrics.Matching))
+	waitingPollers := func() float64 {
+		gauge, ok := scope.Snapshot().Gauges()["test.pollers_waiting_per_tl+operation=TaskListMgr"]
+		if !ok {
+			// the gauge isn't emitted until the poller starts waiting
+			return 0
+		}
+		return gauge.Value()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := matcher.Poll(ctx)
+		t.Equal(ErrNoTasks, err)
+	}()
+
+	t.Eventually(func() bool { return waitingPollers() == 1 }, time.Second, time.Millisecond)
+	<-done
+	t.Equal(float64(0), waitingPollers())
+	t.Equal(int64(1), scope.Snapshot().Counters()["test.poll_timeouts_per_tl+operation=TaskListMgr"].Value())
+}
+
 func (t *MatcherTestSuite) TestRemotePollForQuery() {
 	pollToken := <-t.fwdr.PollReqTokenC()
 
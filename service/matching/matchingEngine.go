@@ -42,6 +42,7 @@ import (
 	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/client"
+	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
 	"github.com/uber/cadence/common/membership"
@@ -58,8 +59,10 @@ const _stickyPollerUnavailableWindow = 10 * time.Second
 // TODO: Switch implementation from lock/channel based to a partitioned agent
 // to simplify code and reduce possibility of synchronization errors.
 type (
-	pollerIDCtxKey string
-	identityCtxKey string
+	pollerIDCtxKey    string
+	identityCtxKey    string
+	maxDispatchCtxKey string
+	buildIDCtxKey     string
 
 	queryResult struct {
 		workerResponse *types.MatchingRespondQueryTaskCompletedRequest
@@ -85,11 +88,20 @@ type (
 		metricsClient        metrics.Client
 		taskListsLock        sync.RWMutex                   // locks mutation of taskLists
 		taskLists            map[taskListID]taskListManager // Convert to LRU cache
+		reloadBlockedUntil   map[taskListID]time.Time       // task lists which must not be reloaded before the given time
 		config               *Config
 		lockableQueryTaskMap lockableQueryTaskMap
 		domainCache          cache.DomainCache
 		versionChecker       client.VersionChecker
 		membershipResolver   membership.Resolver
+		usageRecorder        persistence.UsageRecorder
+		// dead letter queue of the tasks that expired in the task list backlogs
+		expiredTaskQueue persistence.QueueManager
+		// writes the partition counts of the task lists scaled automatically
+		dynamicConfigClient dynamicconfig.Client
+		// active cluster of the global domains as of the last domain change notification
+		domainActiveClustersLock sync.Mutex
+		domainActiveClusters     map[string]string
 	}
 )
 
@@ -105,8 +117,13 @@ var (
 	ErrNoTasks    = errors.New("no tasks")
 	errPumpClosed = errors.New("task list pump closed its channel")
 
-	pollerIDKey pollerIDCtxKey = "pollerID"
-	identityKey identityCtxKey = "identity"
+	errTaskListReloadBlocked = &types.ServiceBusyError{Message: "Task list was unloaded by an operator and cannot be reloaded yet"}
+	errTaskListDraining      = &types.ServiceBusyError{Message: "Task list is draining and does not accept new tasks"}
+
+	pollerIDKey    pollerIDCtxKey    = "pollerID"
+	identityKey    identityCtxKey    = "identity"
+	maxDispatchKey maxDispatchCtxKey = "maxDispatchPerSecond"
+	buildIDKey     buildIDCtxKey     = "buildID"
 
 	_stickyPollerUnavailableError = &types.StickyWorkerUnavailableError{Message: "sticky worker is unavailable, please use non-sticky task list."}
 )
@@ -123,13 +140,18 @@ func NewEngine(taskManager persistence.TaskManager,
 	metricsClient metrics.Client,
 	domainCache cache.DomainCache,
 	resolver membership.Resolver,
+	usageRecorder persistence.UsageRecorder,
+	expiredTaskQueue persistence.QueueManager,
+	tokenSerializer common.TaskTokenSerializer,
+	dynamicConfigClient dynamicconfig.Client,
 ) Engine {
 	return &matchingEngineImpl{
 		taskManager:          taskManager,
 		clusterMetadata:      clusterMetadata,
 		historyService:       historyService,
-		tokenSerializer:      common.NewJSONTaskTokenSerializer(),
+		tokenSerializer:      tokenSerializer,
 		taskLists:            make(map[taskListID]taskListManager),
+		reloadBlockedUntil:   make(map[taskListID]time.Time),
 		logger:               logger.WithTags(tag.ComponentMatchingEngine),
 		metricsClient:        metricsClient,
 		matchingClient:       matchingClient,
@@ -138,20 +160,102 @@ func NewEngine(taskManager persistence.TaskManager,
 		domainCache:          domainCache,
 		versionChecker:       client.NewVersionChecker(),
 		membershipResolver:   resolver,
+		usageRecorder:        usageRecorder,
+		expiredTaskQueue:     expiredTaskQueue,
+		dynamicConfigClient:  dynamicConfigClient,
+		domainActiveClusters: make(map[string]string),
 	}
 }
 
+// the domain cache keys its change callbacks by shard, matching registers a single callback
+const domainChangeCallbackID = 0
+
+// number of task lists handed off concurrently on shutdown
+const taskListHandoffConcurrency = 16
+
 func (e *matchingEngineImpl) Start() {
-	// As task lists are initialized lazily nothing is done on startup at this point.
+	// As task lists are initialized lazily only the domain failover callback is set up on startup,
+	// it releases the tasks kept in the backlog of the domains failing over to the current cluster.
+	// No task list is loaded yet so catching up with the past domain changes is a no-op.
+	e.domainCache.RegisterDomainChangeCallback(
+		domainChangeCallbackID,
+		0,
+		func() {},
+		e.domainChangeCallback,
+	)
 }
 
 func (e *matchingEngineImpl) Stop() {
+	e.domainCache.UnregisterDomainChangeCallback(domainChangeCallbackID)
 	// Executes Stop() on each task list outside of lock
 	for _, l := range e.getTaskLists(math.MaxInt32) {
 		l.Stop()
 	}
 }
 
+func (e *matchingEngineImpl) domainChangeCallback(nextDomains []*cache.DomainCacheEntry) {
+	failoverDomainIDs := e.updateDomainActiveClusters(nextDomains)
+	if len(failoverDomainIDs) == 0 {
+		return
+	}
+
+	for _, tlMgr := range e.getTaskLists(math.MaxInt32) {
+		if _, ok := failoverDomainIDs[tlMgr.TaskListID().domainID]; ok {
+			tlMgr.ReleaseStandbyTasks()
+		}
+	}
+}
+
+// updateDomainActiveClusters records the active cluster of the changed global domains and returns
+// the IDs of the domains which failed over to the current cluster since the previous notification
+func (e *matchingEngineImpl) updateDomainActiveClusters(nextDomains []*cache.DomainCacheEntry) map[string]struct{} {
+	currentClusterName := e.clusterMetadata.GetCurrentClusterName()
+	failoverDomainIDs := map[string]struct{}{}
+
+	e.domainActiveClustersLock.Lock()
+	defer e.domainActiveClustersLock.Unlock()
+	for _, nextDomain := range nextDomains {
+		if !nextDomain.IsGlobalDomain() {
+			continue
+		}
+		domainID := nextDomain.GetInfo().ID
+		nextActiveCluster := nextDomain.GetReplicationConfig().ActiveClusterName
+		prevActiveCluster, ok := e.domainActiveClusters[domainID]
+		e.domainActiveClusters[domainID] = nextActiveCluster
+		if nextActiveCluster == currentClusterName && (!ok || prevActiveCluster != currentClusterName) {
+			failoverDomainIDs[domainID] = struct{}{}
+		}
+	}
+	return failoverDomainIDs
+}
+
+func (e *matchingEngineImpl) getTaskListCount() int {
+	e.taskListsLock.RLock()
+	defer e.taskListsLock.RUnlock()
+	return len(e.taskLists)
+}
+
+// getTaskListCountByDomain returns the number of loaded task lists by domain name, the domain ID is used
+// if the domain name cannot be resolved
+func (e *matchingEngineImpl) getTaskListCountByDomain() map[string]int {
+	e.taskListsLock.RLock()
+	counts := make(map[string]int)
+	for id := range e.taskLists {
+		counts[id.domainID]++
+	}
+	e.taskListsLock.RUnlock()
+
+	byName := make(map[string]int, len(counts))
+	for domainID, count := range counts {
+		name, err := e.domainCache.GetDomainName(domainID)
+		if err != nil {
+			name = domainID
+		}
+		byName[name] += count
+	}
+	return byName
+}
+
 func (e *matchingEngineImpl) getTaskLists(maxCount int) (lists []taskListManager) {
 	e.taskListsLock.RLock()
 	defer e.taskListsLock.RUnlock()
@@ -197,6 +301,13 @@ func (e *matchingEngineImpl) getTaskListManager(
 		e.taskListsLock.Unlock()
 		return result, nil
 	}
+	if blockedUntil, ok := e.reloadBlockedUntil[*taskList]; ok {
+		if time.Now().Before(blockedUntil) {
+			e.taskListsLock.Unlock()
+			return nil, errTaskListReloadBlocked
+		}
+		delete(e.reloadBlockedUntil, *taskList)
+	}
 
 	// common tagged logger
 	logger := e.logger.WithTags(
@@ -236,9 +347,10 @@ func (e *matchingEngineImpl) getTaskListByDomainLocked(
 	for tl, tlm := range e.taskLists {
 		if tlm.GetTaskListKind() == types.TaskListKindNormal && tl.domainID == domainID {
 			if types.TaskListType(tl.taskType) == types.TaskListTypeDecision {
-				decisionTaskListMap[tl.baseName] = tlm.DescribeTaskList(false)
+				mergeTaskListSummary(decisionTaskListMap, tl.baseName, tlm.DescribeTaskList(true))
+			} else {
+				mergeTaskListSummary(activityTaskListMap, tl.baseName, tlm.DescribeTaskList(true))
 			}
-			activityTaskListMap[tl.baseName] = tlm.DescribeTaskList(false)
 		}
 	}
 
@@ -248,6 +360,35 @@ func (e *matchingEngineImpl) getTaskListByDomainLocked(
 	}
 }
 
+// mergeTaskListSummary adds the pollers and the backlog hint of a task list partition to the summary
+// of its task list, a poller polling several partitions is listed once
+func mergeTaskListSummary(
+	summaries map[string]*types.DescribeTaskListResponse,
+	name string,
+	partition *types.DescribeTaskListResponse,
+) {
+	summary, ok := summaries[name]
+	if !ok {
+		summary = &types.DescribeTaskListResponse{TaskListStatus: &types.TaskListStatus{}}
+		summaries[name] = summary
+	}
+	for _, poller := range partition.GetPollers() {
+		if !containsPoller(summary.Pollers, poller.GetIdentity()) {
+			summary.Pollers = append(summary.Pollers, poller)
+		}
+	}
+	summary.TaskListStatus.BacklogCountHint += partition.GetTaskListStatus().GetBacklogCountHint()
+}
+
+func containsPoller(pollers []*types.PollerInfo, identity string) bool {
+	for _, poller := range pollers {
+		if poller.GetIdentity() == identity {
+			return true
+		}
+	}
+	return false
+}
+
 // For use in tests
 func (e *matchingEngineImpl) updateTaskList(taskList *taskListID, mgr taskListManager) {
 	e.taskListsLock.Lock()
@@ -315,12 +456,14 @@ func (e *matchingEngineImpl) AddDecisionTask(
 		ScheduleID:             request.GetScheduleID(),
 		ScheduleToStartTimeout: request.GetScheduleToStartTimeoutSeconds(),
 		CreatedTime:            time.Now(),
+		Priority:               taskPriorityFromContext(hCtx.Context),
 	}
 	return tlMgr.AddTask(hCtx.Context, addTaskParams{
-		execution:     request.Execution,
-		taskInfo:      taskInfo,
-		source:        request.GetSource(),
-		forwardedFrom: request.GetForwardedFrom(),
+		execution:      request.Execution,
+		taskInfo:       taskInfo,
+		source:         request.GetSource(),
+		forwardedFrom:  request.GetForwardedFrom(),
+		isolationGroup: e.domainIsolationGroup(domainID),
 	})
 }
 
@@ -363,6 +506,7 @@ func (e *matchingEngineImpl) AddActivityTask(
 		ScheduleID:             request.GetScheduleID(),
 		ScheduleToStartTimeout: request.GetScheduleToStartTimeoutSeconds(),
 		CreatedTime:            time.Now(),
+		Priority:               taskPriorityFromContext(hCtx.Context),
 	}
 	return tlMgr.AddTask(hCtx.Context, addTaskParams{
 		execution:                request.Execution,
@@ -370,9 +514,19 @@ func (e *matchingEngineImpl) AddActivityTask(
 		source:                   request.GetSource(),
 		forwardedFrom:            request.GetForwardedFrom(),
 		activityTaskDispatchInfo: request.ActivityTaskDispatchInfo,
+		isolationGroup:           e.domainIsolationGroup(taskInfo.DomainID),
 	})
 }
 
+// domainIsolationGroup returns the isolation group the tasks of the domain are matched in first, empty if there is none
+func (e *matchingEngineImpl) domainIsolationGroup(domainID string) string {
+	domainName, err := e.domainCache.GetDomainName(domainID)
+	if err != nil {
+		return ""
+	}
+	return e.config.DomainIsolationGroup(domainName)
+}
+
 // PollForDecisionTask tries to get the decision task using exponential backoff.
 func (e *matchingEngineImpl) PollForDecisionTask(
 	hCtx *handlerContext,
@@ -402,6 +556,7 @@ pollLoop:
 		// long-poll when frontend calls CancelOutstandingPoll API
 		pollerCtx := context.WithValue(hCtx.Context, pollerIDKey, pollerID)
 		pollerCtx = context.WithValue(pollerCtx, identityKey, request.GetIdentity())
+		pollerCtx = context.WithValue(pollerCtx, buildIDKey, request.GetBinaryChecksum())
 		task, err := e.getTask(pollerCtx, taskList, nil, taskListKind)
 		if err != nil {
 			// TODO: Is empty poll the best reply for errPumpClosed?
@@ -446,6 +601,7 @@ pollLoop:
 				WorkflowExecutionTaskList: mutableStateResp.TaskList,
 				BranchToken:               mutableStateResp.CurrentBranchToken,
 			}
+			e.recordTaskDispatch(domainID)
 			return e.createPollForDecisionTaskResponse(task, resp, hCtx.scope), nil
 		}
 
@@ -471,6 +627,8 @@ pollLoop:
 			continue pollLoop
 		}
 		task.finish(nil)
+		e.recordTaskDispatch(domainID)
+		e.emitScheduleToStartLatency(hCtx.scope, task)
 		return e.createPollForDecisionTaskResponse(task, resp, hCtx.scope), nil
 	}
 }
@@ -511,6 +669,8 @@ pollLoop:
 		// long-poll when frontend calls CancelOutstandingPoll API
 		pollerCtx := context.WithValue(hCtx.Context, pollerIDKey, pollerID)
 		pollerCtx = context.WithValue(pollerCtx, identityKey, request.GetIdentity())
+		// the rate limit is forwarded along with the poll so that the parent partitions learn it as well
+		pollerCtx = context.WithValue(pollerCtx, maxDispatchKey, maxDispatch)
 		taskListKind := request.TaskList.Kind
 		task, err := e.getTask(pollerCtx, taskList, maxDispatch, taskListKind)
 		if err != nil {
@@ -528,8 +688,12 @@ pollLoop:
 			return task.pollForActivityResponse(), nil
 		}
 		if task.activityTaskDispatchInfo != nil {
+			dispatchInfo := task.activityTaskDispatchInfo
+			runningTaskList := startedActivityTaskList(task, taskListName, common.Int64Default(dispatchInfo.Attempt), dispatchInfo.ScheduledEvent)
 			task.finish(nil)
-			return e.createSyncMatchPollForActivityTaskResponse(task, task.activityTaskDispatchInfo), nil
+			e.recordTaskDispatch(domainID)
+			e.emitScheduleToStartLatency(hCtx.scope, task)
+			return e.createSyncMatchPollForActivityTaskResponse(task, dispatchInfo, runningTaskList), nil
 		}
 
 		resp, err := e.recordActivityTaskStarted(hCtx.Context, request, task)
@@ -553,14 +717,18 @@ pollLoop:
 
 			continue pollLoop
 		}
+		runningTaskList := startedActivityTaskList(task, taskListName, resp.GetAttempt(), resp.ScheduledEvent)
 		task.finish(nil)
-		return e.createPollForActivityTaskResponse(task, resp, hCtx.scope), nil
+		e.recordTaskDispatch(domainID)
+		e.emitScheduleToStartLatency(hCtx.scope, task)
+		return e.createPollForActivityTaskResponse(task, resp, runningTaskList, hCtx.scope), nil
 	}
 }
 
 func (e *matchingEngineImpl) createSyncMatchPollForActivityTaskResponse(
 	task *InternalTask,
 	activityTaskDispatchInfo *types.ActivityTaskDispatchInfo,
+	runningTaskList string,
 ) *types.PollForActivityTaskResponse {
 
 	scheduledEvent := activityTaskDispatchInfo.ScheduledEvent
@@ -587,6 +755,7 @@ func (e *matchingEngineImpl) createSyncMatchPollForActivityTaskResponse(
 		ScheduleAttempt: common.Int64Default(activityTaskDispatchInfo.Attempt),
 		ActivityID:      attributes.GetActivityID(),
 		ActivityType:    attributes.GetActivityType().GetName(),
+		TaskList:        runningTaskList,
 	}
 
 	response.TaskToken, _ = e.tokenSerializer.Serialize(token)
@@ -725,6 +894,22 @@ func (e *matchingEngineImpl) DescribeTaskList(
 		return nil, err
 	}
 
+	if !e.isTaskListLoaded(taskList) {
+		owned, err := e.isTaskListOwned(taskList)
+		if err != nil {
+			return nil, err
+		}
+		if !owned {
+			// don't load the task list on a host which doesn't own it, it would steal the lease from its owner
+			return e.describeTaskListFromSnapshot(
+				hCtx.Context,
+				taskList,
+				request.GetDescRequest().GetDomain(),
+				request.DescRequest.GetIncludeTaskListStatus(),
+			)
+		}
+	}
+
 	tlMgr, err := e.getTaskListManager(taskList, taskListKind)
 	if err != nil {
 		return nil, err
@@ -733,6 +918,156 @@ func (e *matchingEngineImpl) DescribeTaskList(
 	return tlMgr.DescribeTaskList(request.DescRequest.GetIncludeTaskListStatus()), nil
 }
 
+func (e *matchingEngineImpl) isTaskListLoaded(taskList *taskListID) bool {
+	e.taskListsLock.RLock()
+	defer e.taskListsLock.RUnlock()
+	_, ok := e.taskLists[*taskList]
+	return ok
+}
+
+func (e *matchingEngineImpl) isTaskListOwned(taskList *taskListID) (bool, error) {
+	self, err := e.membershipResolver.WhoAmI()
+	if err != nil {
+		return false, err
+	}
+	owner, err := e.membershipResolver.Lookup(service.Matching, taskList.name)
+	if err != nil {
+		return false, err
+	}
+	return owner.Identity() == self.Identity(), nil
+}
+
+// describeTaskListFromSnapshot describes a task list which is not loaded by this host from the backlog snapshot
+// persisted by its owner. The snapshot is only as recent as the last ack level update, and there are no pollers.
+func (e *matchingEngineImpl) describeTaskListFromSnapshot(
+	ctx context.Context,
+	taskList *taskListID,
+	domainName string,
+	includeTaskListStatus bool,
+) (*types.DescribeTaskListResponse, error) {
+	response := &types.DescribeTaskListResponse{Pollers: []*types.PollerInfo{}}
+	if !includeTaskListStatus {
+		return response, nil
+	}
+
+	resp, err := e.taskManager.GetTaskList(ctx, &persistence.GetTaskListRequest{
+		DomainID:   taskList.domainID,
+		DomainName: domainName,
+		TaskList:   taskList.name,
+		TaskType:   taskList.taskType,
+	})
+	switch err.(type) {
+	case nil:
+	case *types.EntityNotExistsError:
+		// the task list was never written to, so its backlog is empty
+		response.TaskListStatus = &types.TaskListStatus{}
+		return response, nil
+	default:
+		return nil, err
+	}
+
+	info := resp.TaskListInfo
+	var backlogAge time.Duration
+	if !info.OldestTaskCreatedTime.IsZero() {
+		backlogAge = time.Since(info.OldestTaskCreatedTime)
+	}
+	response.TaskListStatus = &types.TaskListStatus{
+		AckLevel:          info.AckLevel,
+		BacklogCountHint:  info.ApproximateBacklogCount,
+		BacklogAgeSeconds: backlogAge.Seconds(),
+		Paused:            info.Paused,
+	}
+	return response, nil
+}
+
+// GetTaskListDrainStatus reports whether the task list rejects new tasks and whether each of its partitions has no
+// task left, the task list is drained once it is in drain mode and all its partitions are drained. The drain mode is
+// set with the matching.taskListDrainMode dynamic config, the partitions are looked up wherever they are owned.
+func (e *matchingEngineImpl) GetTaskListDrainStatus(
+	hCtx *handlerContext,
+	request *types.MatchingGetTaskListDrainStatusRequest,
+) (*types.GetTaskListDrainStatusResponse, error) {
+	domainID := request.GetDomainUUID()
+	domainName, err := e.domainCache.GetDomainName(domainID)
+	if err != nil {
+		return nil, err
+	}
+	taskType := persistence.TaskListTypeDecision
+	if request.GetTaskListType() == types.TaskListTypeActivity {
+		taskType = persistence.TaskListTypeActivity
+	}
+	id, err := newTaskListID(domainID, request.GetTaskList().GetName(), taskType)
+	if err != nil {
+		return nil, err
+	}
+
+	root := id.GetRoot()
+	response := &types.GetTaskListDrainStatusResponse{
+		Draining: e.config.TaskListDrainMode(domainName, root, taskType),
+	}
+	// partitions removed from the write partitions are still read until they are drained
+	numPartitions := common.MaxInt(
+		e.config.NumTasklistReadPartitions(domainName, root, taskType),
+		e.config.NumTasklistWritePartitions(domainName, root, taskType),
+	)
+	response.Drained = response.Draining
+	for partition := 0; partition < common.MaxInt(1, numPartitions); partition++ {
+		name := id.mkName(partition)
+		drained, err := e.isPartitionDrained(hCtx.Context, domainID, domainName, name, taskType)
+		if err != nil {
+			return nil, err
+		}
+		response.Partitions = append(response.Partitions, &types.TaskListPartitionDrainStatus{TaskList: name, Drained: drained})
+		response.Drained = response.Drained && drained
+	}
+	return response, nil
+}
+
+// isPartitionDrained returns true if the given task list partition has no task left. The backlog count of a partition
+// only covers the tasks loaded in memory, so the tasks above its ack level are also looked up in the database.
+func (e *matchingEngineImpl) isPartitionDrained(
+	ctx context.Context,
+	domainID string,
+	domainName string,
+	taskListName string,
+	taskType int,
+) (bool, error) {
+	taskListType := types.TaskListTypeDecision
+	if taskType == persistence.TaskListTypeActivity {
+		taskListType = types.TaskListTypeActivity
+	}
+	resp, err := e.matchingClient.DescribeTaskList(ctx, &types.MatchingDescribeTaskListRequest{
+		DomainUUID: domainID,
+		DescRequest: &types.DescribeTaskListRequest{
+			Domain:                domainName,
+			TaskList:              &types.TaskList{Name: taskListName, Kind: types.TaskListKindNormal.Ptr()},
+			TaskListType:          &taskListType,
+			IncludeTaskListStatus: true,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	status := resp.GetTaskListStatus()
+	if status.GetBacklogCountHint() > 0 {
+		return false, nil
+	}
+
+	tasks, err := e.taskManager.GetTasks(ctx, &persistence.GetTasksRequest{
+		DomainID:     domainID,
+		TaskList:     taskListName,
+		TaskType:     taskType,
+		ReadLevel:    status.GetAckLevel(),
+		MaxReadLevel: common.Int64Ptr(math.MaxInt64),
+		BatchSize:    1,
+		DomainName:   domainName,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(tasks.Tasks) == 0, nil
+}
+
 func (e *matchingEngineImpl) ListTaskListPartitions(
 	hCtx *handlerContext,
 	request *types.MatchingListTaskListPartitionsRequest,
@@ -791,6 +1126,126 @@ func (e *matchingEngineImpl) GetTaskListsByDomain(
 	return e.getTaskListByDomainLocked(domainID), nil
 }
 
+// UnloadTaskList evicts the task list manager for the given task list partition from this host and
+// stops it, which persists its ack level. The next request reloads the task list and re-acquires the
+// lease, unless BlockReloadSeconds is set, in which case requests fail with a retryable error until
+// the block expires. Unloading with BlockReloadSeconds of zero lifts any existing block.
+func (e *matchingEngineImpl) UnloadTaskList(
+	hCtx *handlerContext,
+	request *types.MatchingUnloadTaskListRequest,
+) error {
+	taskListType := persistence.TaskListTypeDecision
+	if request.GetTaskListType() == types.TaskListTypeActivity {
+		taskListType = persistence.TaskListTypeActivity
+	}
+	taskList, err := newTaskListID(request.GetDomainUUID(), request.GetTaskList().GetName(), taskListType)
+	if err != nil {
+		return err
+	}
+
+	e.taskListsLock.Lock()
+	if blockSeconds := request.GetBlockReloadSeconds(); blockSeconds > 0 {
+		e.reloadBlockedUntil[*taskList] = time.Now().Add(time.Duration(blockSeconds) * time.Second)
+	} else {
+		delete(e.reloadBlockedUntil, *taskList)
+	}
+	tlMgr, ok := e.taskLists[*taskList]
+	if ok {
+		delete(e.taskLists, *taskList)
+	}
+	numTaskLists := len(e.taskLists)
+	e.taskListsLock.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	e.logger.Info("Task list manager unloaded by request",
+		tag.WorkflowTaskListName(taskList.name),
+		tag.WorkflowTaskListType(taskList.taskType),
+		tag.WorkflowDomainID(taskList.domainID),
+		tag.LifeCycleStopping,
+	)
+	tlMgr.Stop()
+	e.metricsClient.Scope(metrics.MatchingTaskListMgrScope).UpdateGauge(
+		metrics.TaskListManagersGauge,
+		float64(numTaskLists),
+	)
+	return nil
+}
+
+// PauseTaskList stops the dispatch of the tasks of a task list partition until it is resumed, its pollers receive
+// empty responses and its tasks are kept in the backlog
+func (e *matchingEngineImpl) PauseTaskList(
+	hCtx *handlerContext,
+	request *types.MatchingPauseTaskListRequest,
+) error {
+	return e.setTaskListPaused(request.GetDomainUUID(), request.GetTaskList(), request.GetTaskListType(), true)
+}
+
+// ResumeTaskList resumes the dispatch of the tasks of a paused task list partition
+func (e *matchingEngineImpl) ResumeTaskList(
+	hCtx *handlerContext,
+	request *types.MatchingResumeTaskListRequest,
+) error {
+	return e.setTaskListPaused(request.GetDomainUUID(), request.GetTaskList(), request.GetTaskListType(), false)
+}
+
+// RecordActivityTaskFinished releases the slot held by a running activity of a task list partition, the slots
+// are not tracked anymore once the partition is unloaded, so the partition is not loaded to release one
+func (e *matchingEngineImpl) RecordActivityTaskFinished(
+	hCtx *handlerContext,
+	request *types.MatchingRecordActivityTaskFinishedRequest,
+) error {
+	taskListID, err := newTaskListID(request.GetDomainUUID(), request.GetTaskList().GetName(), persistence.TaskListTypeActivity)
+	if err != nil {
+		return err
+	}
+	e.taskListsLock.RLock()
+	tlMgr, ok := e.taskLists[*taskListID]
+	e.taskListsLock.RUnlock()
+	if !ok {
+		return nil
+	}
+	tlMgr.FinishActivity(runningActivityKey{
+		workflowID: request.GetWorkflowExecution().GetWorkflowID(),
+		runID:      request.GetWorkflowExecution().GetRunID(),
+		scheduleID: request.GetScheduleID(),
+		attempt:    request.GetAttempt(),
+	})
+	return nil
+}
+
+func (e *matchingEngineImpl) setTaskListPaused(
+	domainID string,
+	taskList *types.TaskList,
+	taskListType types.TaskListType,
+	paused bool,
+) error {
+	taskType := persistence.TaskListTypeDecision
+	if taskListType == types.TaskListTypeActivity {
+		taskType = persistence.TaskListTypeActivity
+	}
+	taskListID, err := newTaskListID(domainID, taskList.GetName(), taskType)
+	if err != nil {
+		return err
+	}
+	tlMgr, err := e.getTaskListManager(taskListID, taskList.Kind)
+	if err != nil {
+		return err
+	}
+	if err := tlMgr.SetPaused(paused); err != nil {
+		return err
+	}
+	e.logger.Info("Task list dispatch paused state changed by request",
+		tag.WorkflowTaskListName(taskListID.name),
+		tag.WorkflowTaskListType(taskListID.taskType),
+		tag.WorkflowDomainID(taskListID.domainID),
+		tag.Value(paused),
+	)
+	return nil
+}
+
 func (e *matchingEngineImpl) getHostInfo(partitionKey string) (string, error) {
 	host, err := e.membershipResolver.Lookup(service.Matching, partitionKey)
 	if err != nil {
@@ -854,6 +1309,98 @@ func (e *matchingEngineImpl) unloadTaskList(tlMgr taskListManager) {
 	tlMgr.Stop()
 }
 
+// HandoffTaskLists unloads the task lists whose new owner is another host, once this host is evicted from the ring,
+// and asks the new owner to load the ones with pollers or a backlog, so that it doesn't wait for their next request
+// to acquire their lease and rebuild their state. The tasks being added are written to persistence and the ack level
+// is persisted before a task list is unloaded.
+func (e *matchingEngineImpl) HandoffTaskLists(ctx context.Context) {
+	self, err := e.membershipResolver.WhoAmI()
+	if err != nil {
+		e.logger.Warn("Failed to hand task lists off", tag.Error(err))
+		return
+	}
+
+	tlMgrCh := make(chan taskListManager, taskListHandoffConcurrency)
+	var wg sync.WaitGroup
+	wg.Add(taskListHandoffConcurrency)
+	for i := 0; i < taskListHandoffConcurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for tlMgr := range tlMgrCh {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := e.handoffTaskList(ctx, self, tlMgr); err != nil {
+					id := tlMgr.TaskListID()
+					e.metricsClient.IncCounter(metrics.MatchingTaskListMgrScope, metrics.TaskListHandoffFailedCounter)
+					e.logger.Warn("Failed to hand task list off",
+						tag.Error(err),
+						tag.WorkflowTaskListName(id.name),
+						tag.WorkflowTaskListType(id.taskType),
+						tag.WorkflowDomainID(id.domainID),
+					)
+				}
+			}
+		}()
+	}
+	for _, tlMgr := range e.getTaskLists(math.MaxInt32) {
+		tlMgrCh <- tlMgr
+	}
+	close(tlMgrCh)
+	wg.Wait()
+}
+
+func (e *matchingEngineImpl) handoffTaskList(ctx context.Context, self membership.HostInfo, tlMgr taskListManager) error {
+	id := tlMgr.TaskListID()
+	owner, err := e.membershipResolver.Lookup(service.Matching, id.name)
+	if err != nil {
+		return err
+	}
+	if owner.Identity() == self.Identity() {
+		// the ring doesn't know this host is evicted yet, the task list is stopped on shutdown
+		return nil
+	}
+
+	kind := tlMgr.GetTaskListKind()
+	desc := tlMgr.DescribeTaskList(true)
+	// sticky task lists are only polled by the worker owning the sticky cache, they are loaded on its next poll
+	isHot := kind != types.TaskListKindSticky &&
+		(len(desc.GetPollers()) > 0 || desc.GetTaskListStatus().GetBacklogCountHint() > 0)
+	if err := tlMgr.FlushWrites(ctx); err != nil {
+		return err
+	}
+	e.unloadTaskList(tlMgr)
+	e.metricsClient.IncCounter(metrics.MatchingTaskListMgrScope, metrics.TaskListHandoffCounter)
+	if !isHot {
+		return nil
+	}
+
+	// describing the task list makes its new owner load it
+	taskListType := types.TaskListTypeDecision
+	if id.taskType == persistence.TaskListTypeActivity {
+		taskListType = types.TaskListTypeActivity
+	}
+	_, err = e.matchingClient.DescribeTaskList(ctx, &types.MatchingDescribeTaskListRequest{
+		DomainUUID: id.domainID,
+		DescRequest: &types.DescribeTaskListRequest{
+			TaskList:     &types.TaskList{Name: id.name, Kind: &kind},
+			TaskListType: &taskListType,
+		},
+	})
+	return err
+}
+
+// emitScheduleToStartLatency records the time a task waited since it was added to matching until it was dispatched
+// to a poller, split by whether it was matched synchronously or dispatched from the task list backlog
+func (e *matchingEngineImpl) emitScheduleToStartLatency(scope metrics.Scope, task *InternalTask) {
+	latency := time.Since(task.event.CreatedTime)
+	if task.responseC != nil {
+		scope.RecordHistogramDuration(metrics.ScheduleToStartSyncPerTaskList, latency)
+	} else {
+		scope.RecordHistogramDuration(metrics.ScheduleToStartBufferedPerTaskList, latency)
+	}
+}
+
 // Populate the decision task response based on context and scheduled/started events.
 func (e *matchingEngineImpl) createPollForDecisionTaskResponse(
 	task *InternalTask,
@@ -897,6 +1444,7 @@ func (e *matchingEngineImpl) createPollForDecisionTaskResponse(
 func (e *matchingEngineImpl) createPollForActivityTaskResponse(
 	task *InternalTask,
 	historyResponse *types.RecordActivityTaskStartedResponse,
+	runningTaskList string,
 	scope metrics.Scope,
 ) *types.PollForActivityTaskResponse {
 
@@ -934,6 +1482,7 @@ func (e *matchingEngineImpl) createPollForActivityTaskResponse(
 		ScheduleAttempt: historyResponse.GetAttempt(),
 		ActivityID:      attributes.GetActivityID(),
 		ActivityType:    attributes.GetActivityType().GetName(),
+		TaskList:        runningTaskList,
 	}
 
 	response.TaskToken, _ = e.tokenSerializer.Serialize(token)
@@ -944,6 +1493,16 @@ func (e *matchingEngineImpl) createPollForActivityTaskResponse(
 	return response
 }
 
+// startedActivityTaskList returns the name of the task list partition whose slot is held by the activity started
+// by the task until it finishes, empty when the partition doesn't limit the activities running at once
+func startedActivityTaskList(task *InternalTask, taskListName string, attempt int64, scheduledEvent *types.HistoryEvent) string {
+	timeout := time.Duration(scheduledEvent.GetActivityTaskScheduledEventAttributes().GetStartToCloseTimeoutSeconds()) * time.Second
+	if !task.markActivityStarted(attempt, timeout) {
+		return ""
+	}
+	return taskListName
+}
+
 func (e *matchingEngineImpl) recordDecisionTaskStarted(
 	ctx context.Context,
 	pollReq *types.PollForDecisionTaskRequest,
@@ -1016,6 +1575,17 @@ func (e *matchingEngineImpl) emitForwardedFromStats(
 	}
 }
 
+func (e *matchingEngineImpl) recordTaskDispatch(domainID string) {
+	if e.usageRecorder == nil {
+		return
+	}
+	domainName, err := e.domainCache.GetDomainName(domainID)
+	if err != nil {
+		return
+	}
+	e.usageRecorder.RecordUsage(domainName, persistence.DomainUsage{TaskDispatches: 1})
+}
+
 func (e *matchingEngineImpl) emitInfoOrDebugLog(
 	domainID string,
 	msg string,
@@ -20,12 +20,19 @@
 
 package matching
 
-import "github.com/uber/cadence/common/types"
+import (
+	"context"
+
+	"github.com/uber/cadence/common/types"
+)
 
 type (
 	// Engine exposes interfaces for clients to poll for activity and decision tasks.
 	Engine interface {
+		Start()
 		Stop()
+		// HandoffTaskLists hands the task lists off to their new owners, once this host is evicted from the ring
+		HandoffTaskLists(ctx context.Context)
 		AddDecisionTask(hCtx *handlerContext, request *types.AddDecisionTaskRequest) (syncMatch bool, err error)
 		AddActivityTask(hCtx *handlerContext, request *types.AddActivityTaskRequest) (syncMatch bool, err error)
 		PollForDecisionTask(hCtx *handlerContext, request *types.MatchingPollForDecisionTaskRequest) (*types.MatchingPollForDecisionTaskResponse, error)
@@ -36,5 +43,10 @@ type (
 		DescribeTaskList(hCtx *handlerContext, request *types.MatchingDescribeTaskListRequest) (*types.DescribeTaskListResponse, error)
 		ListTaskListPartitions(hCtx *handlerContext, request *types.MatchingListTaskListPartitionsRequest) (*types.ListTaskListPartitionsResponse, error)
 		GetTaskListsByDomain(hCtx *handlerContext, request *types.GetTaskListsByDomainRequest) (*types.GetTaskListsByDomainResponse, error)
+		UnloadTaskList(hCtx *handlerContext, request *types.MatchingUnloadTaskListRequest) error
+		GetTaskListDrainStatus(hCtx *handlerContext, request *types.MatchingGetTaskListDrainStatusRequest) (*types.GetTaskListDrainStatusResponse, error)
+		PauseTaskList(hCtx *handlerContext, request *types.MatchingPauseTaskListRequest) error
+		ResumeTaskList(hCtx *handlerContext, request *types.MatchingResumeTaskListRequest) error
+		RecordActivityTaskFinished(hCtx *handlerContext, request *types.MatchingRecordActivityTaskFinishedRequest) error
 	}
 )
@@ -36,6 +36,7 @@ import (
 	"go.uber.org/yarpc"
 
 	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/cluster"
@@ -43,10 +44,12 @@ import (
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/loggerimpl"
 	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/membership"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/mocks"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/quotas"
+	"github.com/uber/cadence/common/service"
 	"github.com/uber/cadence/common/types"
 
 	"github.com/davecgh/go-spew/spew"
@@ -113,6 +116,8 @@ func (s *matchingEngineSuite) SetupTest() {
 	s.mockDomainCache = cache.NewMockDomainCache(s.controller)
 	s.mockDomainCache.EXPECT().GetDomainByID(gomock.Any()).Return(cache.CreateDomainCacheEntry(matchingTestDomainName), nil).AnyTimes()
 	s.mockDomainCache.EXPECT().GetDomainName(gomock.Any()).Return(matchingTestDomainName, nil).AnyTimes()
+	s.mockDomainCache.EXPECT().RegisterDomainChangeCallback(domainChangeCallbackID, gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	s.mockDomainCache.EXPECT().UnregisterDomainChangeCallback(domainChangeCallbackID).AnyTimes()
 	s.handlerContext = newHandlerContext(
 		context.Background(),
 		matchingTestDomainName,
@@ -135,7 +140,14 @@ func (s *matchingEngineSuite) TearDownTest() {
 func (s *matchingEngineSuite) newMatchingEngine(
 	config *Config, taskMgr persistence.TaskManager,
 ) *matchingEngineImpl {
-	return newMatchingEngine(config, taskMgr, s.mockHistoryClient, s.logger, s.mockDomainCache)
+	e := newMatchingEngine(config, taskMgr, s.mockHistoryClient, s.logger, s.mockDomainCache)
+	// this host owns all the task lists unless a test replaces the resolver
+	self := membership.NewHostInfo("self")
+	resolver := membership.NewMockResolver(s.controller)
+	resolver.EXPECT().WhoAmI().Return(self, nil).AnyTimes()
+	resolver.EXPECT().Lookup(service.Matching, gomock.Any()).Return(self, nil).AnyTimes()
+	e.membershipResolver = resolver
+	return e
 }
 
 func newMatchingEngine(
@@ -143,15 +155,17 @@ func newMatchingEngine(
 	logger log.Logger, mockDomainCache cache.DomainCache,
 ) *matchingEngineImpl {
 	return &matchingEngineImpl{
-		taskManager:     taskMgr,
-		clusterMetadata: cluster.GetTestClusterMetadata(true),
-		historyService:  mockHistoryClient,
-		taskLists:       make(map[taskListID]taskListManager),
-		logger:          logger,
-		metricsClient:   metrics.NewClient(tally.NoopScope, metrics.Matching),
-		tokenSerializer: common.NewJSONTaskTokenSerializer(),
-		config:          config,
-		domainCache:     mockDomainCache,
+		taskManager:          taskMgr,
+		clusterMetadata:      cluster.GetTestClusterMetadata(true),
+		historyService:       mockHistoryClient,
+		taskLists:            make(map[taskListID]taskListManager),
+		reloadBlockedUntil:   make(map[taskListID]time.Time),
+		logger:               logger,
+		metricsClient:        metrics.NewClient(tally.NoopScope, metrics.Matching),
+		tokenSerializer:      common.NewJSONTaskTokenSerializer(),
+		config:               config,
+		domainCache:          mockDomainCache,
+		domainActiveClusters: make(map[string]string),
 	}
 }
 
@@ -210,6 +224,208 @@ func (s *matchingEngineSuite) TestOnlyUnloadMatchingInstance() {
 		"Unload call with matching incarnation should have caused unload")
 }
 
+func (s *matchingEngineSuite) TestGetTaskListsByDomain() {
+	domainID := uuid.New()
+	tlKind := types.TaskListKindNormal
+	getTaskListManager := func(domainID string, name string, taskType int) *taskListManagerImpl {
+		tlm, err := s.matchingEngine.getTaskListManager(newTestTaskListID(domainID, name, taskType), &tlKind)
+		s.Require().NoError(err)
+		return tlm.(*taskListManagerImpl)
+	}
+
+	decision := getTaskListManager(domainID, "makeToast", persistence.TaskListTypeDecision)
+	decision.pollerHistory.updatePollerInfo("worker1", newPollerInfo(context.Background(), nil))
+	activity := getTaskListManager(domainID, "makeToast", persistence.TaskListTypeActivity)
+	activity.pollerHistory.updatePollerInfo("worker1", newPollerInfo(context.Background(), nil))
+	s.NoError(activity.taskAckManager.ReadItem(1))
+	partition := getTaskListManager(domainID, "/__cadence_sys/makeToast/1", persistence.TaskListTypeActivity)
+	partition.pollerHistory.updatePollerInfo("worker1", newPollerInfo(context.Background(), nil))
+	partition.pollerHistory.updatePollerInfo("worker2", newPollerInfo(context.Background(), nil))
+	s.NoError(partition.taskAckManager.ReadItem(1))
+	s.NoError(partition.taskAckManager.ReadItem(2))
+	getTaskListManager(uuid.New(), "otherDomainToast", persistence.TaskListTypeActivity)
+
+	s.mockDomainCache.EXPECT().GetDomainID(matchingTestDomainName).Return(domainID, nil)
+	resp, err := s.matchingEngine.GetTaskListsByDomain(s.handlerContext, &types.GetTaskListsByDomainRequest{Domain: matchingTestDomainName})
+	s.NoError(err)
+
+	s.Len(resp.GetDecisionTaskListMap(), 1)
+	s.Len(resp.GetDecisionTaskListMap()["makeToast"].GetPollers(), 1)
+	s.Zero(resp.GetDecisionTaskListMap()["makeToast"].GetTaskListStatus().GetBacklogCountHint())
+	// the partitions of the task list are merged and a poller polling both is listed once
+	s.Len(resp.GetActivityTaskListMap(), 1)
+	s.Len(resp.GetActivityTaskListMap()["makeToast"].GetPollers(), 2)
+	s.Equal(int64(3), resp.GetActivityTaskListMap()["makeToast"].GetTaskListStatus().GetBacklogCountHint())
+}
+
+func (s *matchingEngineSuite) TestHandoffTaskLists() {
+	domainID := uuid.New()
+	tlKind := types.TaskListKindNormal
+	getTaskListManager := func(name string) *taskListManagerImpl {
+		tlm, err := s.matchingEngine.getTaskListManager(newTestTaskListID(domainID, name, persistence.TaskListTypeActivity), &tlKind)
+		s.Require().NoError(err)
+		return tlm.(*taskListManagerImpl)
+	}
+	hot := getTaskListManager("hot")
+	hot.pollerHistory.updatePollerInfo("worker1", newPollerInfo(context.Background(), nil))
+	getTaskListManager("cold")
+	owned := getTaskListManager("owned")
+
+	self := membership.NewHostInfo("self")
+	other := membership.NewHostInfo("other")
+	resolver := membership.NewMockResolver(s.controller)
+	resolver.EXPECT().WhoAmI().Return(self, nil)
+	resolver.EXPECT().Lookup(service.Matching, "hot").Return(other, nil)
+	resolver.EXPECT().Lookup(service.Matching, "cold").Return(other, nil)
+	resolver.EXPECT().Lookup(service.Matching, "owned").Return(self, nil)
+	s.matchingEngine.membershipResolver = resolver
+	matchingClient := matching.NewMockClient(s.controller)
+	s.matchingEngine.matchingClient = matchingClient
+	// only the task list with pollers is loaded by its new owner right away
+	matchingClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *types.MatchingDescribeTaskListRequest, _ ...yarpc.CallOption) (*types.DescribeTaskListResponse, error) {
+			s.Equal(domainID, request.GetDomainUUID())
+			s.Equal("hot", request.GetDescRequest().GetTaskList().GetName())
+			s.Equal(types.TaskListTypeActivity, request.GetDescRequest().GetTaskListType())
+			return &types.DescribeTaskListResponse{}, nil
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.matchingEngine.HandoffTaskLists(ctx)
+
+	taskLists := s.matchingEngine.getTaskLists(100)
+	s.Len(taskLists, 1)
+	s.Equal(owned, taskLists[0])
+	s.EqualValues(1, atomic.LoadInt32(&hot.stopped))
+}
+
+func (s *matchingEngineSuite) TestHandoffTaskListsWaitsForPendingWrites() {
+	tlKind := types.TaskListKindNormal
+	tlm, err := s.matchingEngine.getTaskListManager(newTestTaskListID(uuid.New(), "makeToast", persistence.TaskListTypeActivity), &tlKind)
+	s.Require().NoError(err)
+	taskWriter := tlm.(*taskListManagerImpl).taskWriter
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.NoError(tlm.FlushWrites(ctx))
+	atomic.AddInt64(&taskWriter.pendingAppends, 1)
+	s.Equal(context.DeadlineExceeded, tlm.FlushWrites(ctx))
+	atomic.AddInt64(&taskWriter.pendingAppends, -1)
+}
+
+func (s *matchingEngineSuite) TestUnloadTaskList() {
+	domainID := uuid.New()
+	taskListID := newTestTaskListID(domainID, "makeToast", persistence.TaskListTypeActivity)
+	tlKind := types.TaskListKindNormal
+	tlm, err := s.matchingEngine.getTaskListManager(taskListID, &tlKind)
+	s.Require().NoError(err)
+
+	activityType := types.TaskListTypeActivity
+	request := &types.MatchingUnloadTaskListRequest{
+		DomainUUID:         domainID,
+		TaskList:           &types.TaskList{Name: "makeToast"},
+		TaskListType:       &activityType,
+		BlockReloadSeconds: 60,
+	}
+	s.NoError(s.matchingEngine.UnloadTaskList(s.handlerContext, request))
+	s.Empty(s.matchingEngine.getTaskLists(100))
+
+	_, err = s.matchingEngine.getTaskListManager(taskListID, &tlKind)
+	s.Equal(errTaskListReloadBlocked, err)
+
+	// unloading an already unloaded task list without a block lifts the block
+	request.BlockReloadSeconds = 0
+	s.NoError(s.matchingEngine.UnloadTaskList(s.handlerContext, request))
+
+	got, err := s.matchingEngine.getTaskListManager(taskListID, &tlKind)
+	s.Require().NoError(err)
+	s.NotSame(tlm, got)
+}
+
+func (s *matchingEngineSuite) TestDescribeTaskListOnNonOwner() {
+	domainID := "domainId"
+	tlType := types.TaskListTypeActivity
+	oldest := time.Now().Add(-time.Minute)
+	tlm := s.taskManager.getTaskListManager(newTestTaskListID(domainID, "backlogged", persistence.TaskListTypeActivity))
+	tlm.Lock()
+	tlm.rangeID = 1
+	tlm.ackLevel = 10
+	tlm.backlog = backlogSnapshot{count: 5, oldestTaskCreatedTime: oldest}
+	tlm.Unlock()
+
+	resolver := membership.NewMockResolver(s.controller)
+	resolver.EXPECT().WhoAmI().Return(membership.NewHostInfo("self"), nil).AnyTimes()
+	resolver.EXPECT().Lookup(service.Matching, gomock.Any()).Return(membership.NewHostInfo("other"), nil).AnyTimes()
+	s.matchingEngine.membershipResolver = resolver
+
+	describe := func(name string) *types.DescribeTaskListResponse {
+		resp, err := s.matchingEngine.DescribeTaskList(s.handlerContext, &types.MatchingDescribeTaskListRequest{
+			DomainUUID: domainID,
+			DescRequest: &types.DescribeTaskListRequest{
+				TaskList:              &types.TaskList{Name: name},
+				TaskListType:          &tlType,
+				IncludeTaskListStatus: true,
+			},
+		})
+		s.Require().NoError(err)
+		return resp
+	}
+
+	status := describe("backlogged").GetTaskListStatus()
+	s.Equal(int64(10), status.GetAckLevel())
+	s.Equal(int64(5), status.GetBacklogCountHint())
+	s.InDelta(time.Minute.Seconds(), status.GetBacklogAgeSeconds(), 5)
+
+	// a task list which was never written to has no backlog
+	resp := describe("unknown")
+	s.Empty(resp.GetPollers())
+	s.Zero(resp.GetTaskListStatus().GetBacklogCountHint())
+
+	// the task lists are not loaded by the host which doesn't own them
+	s.Zero(s.matchingEngine.getTaskListCount())
+}
+
+func (s *matchingEngineSuite) TestPauseAndResumeTaskList() {
+	domainID := "domainId"
+	taskList := &types.TaskList{Name: "paused"}
+	tlType := types.TaskListTypeActivity
+	isPaused := func() bool {
+		resp, err := s.matchingEngine.DescribeTaskList(s.handlerContext, &types.MatchingDescribeTaskListRequest{
+			DomainUUID: domainID,
+			DescRequest: &types.DescribeTaskListRequest{
+				TaskList:              taskList,
+				TaskListType:          &tlType,
+				IncludeTaskListStatus: true,
+			},
+		})
+		s.Require().NoError(err)
+		return resp.GetTaskListStatus().GetPaused()
+	}
+
+	s.NoError(s.matchingEngine.PauseTaskList(s.handlerContext, &types.MatchingPauseTaskListRequest{
+		DomainUUID:   domainID,
+		TaskList:     taskList,
+		TaskListType: &tlType,
+	}))
+	s.True(isPaused())
+
+	// the paused state is kept when the task list is reloaded
+	s.NoError(s.matchingEngine.UnloadTaskList(s.handlerContext, &types.MatchingUnloadTaskListRequest{
+		DomainUUID:   domainID,
+		TaskList:     taskList,
+		TaskListType: &tlType,
+	}))
+	s.True(isPaused())
+
+	s.NoError(s.matchingEngine.ResumeTaskList(s.handlerContext, &types.MatchingResumeTaskListRequest{
+		DomainUUID:   domainID,
+		TaskList:     taskList,
+		TaskListType: &tlType,
+	}))
+	s.False(isPaused())
+}
+
 func (s *matchingEngineSuite) TestPollForDecisionTasks() {
 	s.PollForDecisionTasksResultTest()
 }
@@ -605,6 +821,71 @@ func (s *matchingEngineSuite) TestAddThenConsumeActivities() {
 	s.True(expectedRange <= s.taskManager.getTaskListManager(tlID).rangeID)
 }
 
+func (s *matchingEngineSuite) TestMaxConcurrentActivities() {
+	s.matchingEngine.config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(50 * time.Millisecond)
+	s.matchingEngine.config.MaxConcurrentActivities = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(1)
+
+	domainID := "domainId"
+	workflowExecution := &types.WorkflowExecution{RunID: "run1", WorkflowID: "workflow1"}
+	taskList := &types.TaskList{Name: "limited"}
+	for _, scheduleID := range []int64{3, 6} {
+		_, err := s.matchingEngine.AddActivityTask(s.handlerContext, &types.AddActivityTaskRequest{
+			SourceDomainUUID:              domainID,
+			DomainUUID:                    domainID,
+			Execution:                     workflowExecution,
+			ScheduleID:                    scheduleID,
+			TaskList:                      taskList,
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(100),
+		})
+		s.NoError(err)
+	}
+
+	s.mockHistoryClient.EXPECT().RecordActivityTaskStarted(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, taskRequest *types.RecordActivityTaskStartedRequest, option ...yarpc.CallOption) (*types.RecordActivityTaskStartedResponse, error) {
+			return &types.RecordActivityTaskStartedResponse{
+				ScheduledEvent: newActivityTaskScheduledEvent(taskRequest.ScheduleID, 0,
+					&types.ScheduleActivityTaskDecisionAttributes{
+						ActivityID:                 "activityId1",
+						TaskList:                   taskList,
+						ActivityType:               &types.ActivityType{Name: "activity1"},
+						StartToCloseTimeoutSeconds: common.Int32Ptr(50),
+					}),
+				StartedTimestamp: common.Int64Ptr(time.Now().UnixNano()),
+			}, nil
+		}).Times(2)
+	poll := func() *types.PollForActivityTaskResponse {
+		result, err := s.matchingEngine.PollForActivityTask(s.handlerContext, &types.MatchingPollForActivityTaskRequest{
+			DomainUUID:  domainID,
+			PollRequest: &types.PollForActivityTaskRequest{TaskList: taskList, Identity: "nobody"},
+		})
+		s.NoError(err)
+		return result
+	}
+
+	result := poll()
+	s.NotEmpty(result.TaskToken)
+	token, err := s.matchingEngine.tokenSerializer.Deserialize(result.TaskToken)
+	s.NoError(err)
+	// the token tells the partition to release the slot of the activity to when it finishes
+	s.Equal(taskList.Name, token.TaskList)
+
+	// the second activity is not dispatched while the first one is running
+	s.Empty(poll().TaskToken)
+
+	s.NoError(s.matchingEngine.RecordActivityTaskFinished(s.handlerContext, &types.MatchingRecordActivityTaskFinishedRequest{
+		DomainUUID:        token.DomainID,
+		TaskList:          &types.TaskList{Name: token.TaskList},
+		WorkflowExecution: &types.WorkflowExecution{WorkflowID: token.WorkflowID, RunID: token.RunID},
+		ScheduleID:        token.ScheduleID,
+		Attempt:           token.ScheduleAttempt,
+	}))
+	result = poll()
+	s.NotEmpty(result.TaskToken)
+	token, err = s.matchingEngine.tokenSerializer.Deserialize(result.TaskToken)
+	s.NoError(err)
+	s.Equal(int64(6), token.ScheduleID)
+}
+
 func (s *matchingEngineSuite) TestSyncMatchActivities() {
 	// Set a short long poll expiration so we don't have to wait too long for 0 throttling cases
 	s.matchingEngine.config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(50 * time.Millisecond)
@@ -626,6 +907,14 @@ func (s *matchingEngineSuite) TestSyncMatchActivities() {
 	// So we can get snapshots
 	scope := tally.NewTestScope("test", nil)
 	s.matchingEngine.metricsClient = metrics.NewClient(scope, metrics.Matching)
+	s.handlerContext = newHandlerContext(
+		context.Background(),
+		matchingTestDomainName,
+		&types.TaskList{Name: matchingTestTaskList, Kind: &tlKind},
+		s.matchingEngine.metricsClient,
+		metrics.MatchingTaskListMgrScope,
+		s.logger,
+	)
 
 	dispatchTTL := time.Nanosecond
 	dPtr := _defaultTaskDispatchRPS
@@ -752,6 +1041,20 @@ func (s *matchingEngineSuite) TestSyncMatchActivities() {
 	s.Equal(1, int(syncCtr.Value()))                         // Check times zero rps is set = throttle counter
 	s.EqualValues(1, s.taskManager.getCreateTaskCount(tlID)) // Check times zero rps is set = Tasks stored in persistence
 	s.EqualValues(0, s.taskManager.getTaskCount(tlID))
+
+	syncHistograms, bufferedHistograms := 0, 0
+	for _, h := range scope.Snapshot().Histograms() {
+		switch h.Name() {
+		case "test.schedule_to_start_sync_per_tl":
+			syncHistograms++
+			s.Contains(h.Tags(), "tasklist")
+		case "test.schedule_to_start_buffered_per_tl":
+			bufferedHistograms++
+		}
+	}
+	s.True(syncHistograms > 0)     // sync matched tasks record the sync path latency
+	s.True(bufferedHistograms > 0) // the task written to persistence at zero rps records the buffered path latency
+
 	expectedRange := int64(initialRangeID + taskCount/rangeSize)
 	if taskCount%rangeSize > 0 {
 		expectedRange++
@@ -1473,8 +1776,8 @@ func (s *matchingEngineSuite) TestTaskListManagerGetTaskBatch() {
 
 	// wait until all tasks are read by the task pump and enqeued into the in-memory buffer
 	// at the end of this step, ackManager readLevel will also be equal to the buffer size
-	expectedBufSize := common.MinInt(cap(tlMgr.taskReader.taskBuffer), taskCount)
-	s.True(s.awaitCondition(func() bool { return len(tlMgr.taskReader.taskBuffer) == expectedBufSize }, time.Second))
+	expectedBufSize := common.MinInt(tlMgr.taskReader.taskBuffer.cap(), taskCount)
+	s.True(s.awaitCondition(func() bool { return tlMgr.taskReader.taskBuffer.len() == expectedBufSize }, time.Second))
 
 	// stop all goroutines that read / write tasks in the background
 	// remainder of this test works with the in-memory buffer
@@ -1566,6 +1869,53 @@ func (s *matchingEngineSuite) TestTaskListManagerGetTaskBatch_ReadBatchDone() {
 	s.NoError(err)
 }
 
+func (s *matchingEngineSuite) TestDomainIsolationGroup() {
+	s.matchingEngine.config.DomainIsolationGroup = func(domain string) string {
+		if domain == matchingTestDomainName {
+			return "zone-a"
+		}
+		return ""
+	}
+	s.Equal("zone-a", s.matchingEngine.domainIsolationGroup(uuid.New()))
+}
+
+func (s *matchingEngineSuite) TestGetTaskListDrainStatus() {
+	domainID := uuid.New()
+	s.matchingEngine.config.NumTasklistReadPartitions = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(2)
+	s.matchingEngine.config.TaskListDrainMode = func(domain string, taskList string, taskType int) bool {
+		return taskList == "drain-tl" && taskType == persistence.TaskListTypeActivity
+	}
+	matchingClient := matching.NewMockClient(s.controller)
+	s.matchingEngine.matchingClient = matchingClient
+	matchingClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(&types.DescribeTaskListResponse{
+		TaskListStatus: &types.TaskListStatus{AckLevel: 10},
+	}, nil).Times(4)
+
+	// the second partition has a task left above its ack level
+	partition := newTestTaskListID(domainID, "/__cadence_sys/drain-tl/1", persistence.TaskListTypeActivity)
+	s.taskManager.getTaskListManager(partition).tasks.Put(int64(11), &persistence.TaskInfo{TaskID: 11})
+	request := &types.MatchingGetTaskListDrainStatusRequest{
+		DomainUUID:   domainID,
+		TaskList:     &types.TaskList{Name: "drain-tl"},
+		TaskListType: types.TaskListTypeActivity.Ptr(),
+	}
+	response, err := s.matchingEngine.GetTaskListDrainStatus(s.handlerContext, request)
+	s.NoError(err)
+	s.Equal(&types.GetTaskListDrainStatusResponse{
+		Draining: true,
+		Drained:  false,
+		Partitions: []*types.TaskListPartitionDrainStatus{
+			{TaskList: "drain-tl", Drained: true},
+			{TaskList: "/__cadence_sys/drain-tl/1", Drained: false},
+		},
+	}, response)
+
+	s.taskManager.getTaskListManager(partition).tasks.Remove(int64(11))
+	response, err = s.matchingEngine.GetTaskListDrainStatus(s.handlerContext, request)
+	s.NoError(err)
+	s.True(response.Drained)
+}
+
 func (s *matchingEngineSuite) TestTaskExpiryAndCompletion() {
 	runID := uuid.New()
 	workflowID := uuid.New()
@@ -1619,9 +1969,9 @@ func (s *matchingEngineSuite) TestTaskExpiryAndCompletion() {
 
 		// wait until all tasks are loaded by into in-memory buffers by task list manager
 		// the buffer size should be one less than expected because dispatcher will dequeue the head
-		s.True(s.awaitCondition(func() bool { return len(tlMgr.taskReader.taskBuffer) >= (taskCount/2 - 1) }, time.Second))
+		s.True(s.awaitCondition(func() bool { return tlMgr.taskReader.taskBuffer.len() >= (taskCount/2 - 1) }, time.Second))
 
-		maxTimeBetweenTaskDeletes = tc.maxTimeBtwnDeletes
+		s.matchingEngine.config.TaskDeleteFlushInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(tc.maxTimeBtwnDeletes)
 		s.matchingEngine.config.MaxTaskDeleteBatchSize = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(tc.batchSize)
 
 		s.setupRecordActivityTaskStartedMock(tl)
@@ -1748,6 +2098,8 @@ type testTaskListManager struct {
 	sync.Mutex
 	rangeID         int64
 	ackLevel        int64
+	backlog         backlogSnapshot
+	paused          bool
 	createTaskCount int
 	tasks           *treemap.Map
 }
@@ -1796,6 +2148,39 @@ func (m *testTaskManager) LeaseTaskList(
 			TaskType: request.TaskType,
 			RangeID:  tlm.rangeID,
 			Kind:     request.TaskListKind,
+
+			ApproximateBacklogCount: tlm.backlog.count,
+			OldestTaskCreatedTime:   tlm.backlog.oldestTaskCreatedTime,
+			Paused:                  tlm.paused,
+		},
+	}, nil
+}
+
+// GetTaskList provides a mock function with given fields: ctx, request
+func (m *testTaskManager) GetTaskList(
+	_ context.Context,
+	request *persistence.GetTaskListRequest,
+) (*persistence.GetTaskListResponse, error) {
+	m.Lock()
+	tlm, ok := m.taskLists[*newTestTaskListID(request.DomainID, request.TaskList, request.TaskType)]
+	m.Unlock()
+	if !ok {
+		return nil, &types.EntityNotExistsError{Message: "task list not found"}
+	}
+
+	tlm.Lock()
+	defer tlm.Unlock()
+	return &persistence.GetTaskListResponse{
+		TaskListInfo: &persistence.TaskListInfo{
+			AckLevel: tlm.ackLevel,
+			DomainID: request.DomainID,
+			Name:     request.TaskList,
+			TaskType: request.TaskType,
+			RangeID:  tlm.rangeID,
+
+			ApproximateBacklogCount: tlm.backlog.count,
+			OldestTaskCreatedTime:   tlm.backlog.oldestTaskCreatedTime,
+			Paused:                  tlm.paused,
 		},
 	}, nil
 }
@@ -1818,6 +2203,11 @@ func (m *testTaskManager) UpdateTaskList(
 		}
 	}
 	tlm.ackLevel = tli.AckLevel
+	tlm.backlog = backlogSnapshot{
+		count:                 tli.ApproximateBacklogCount,
+		oldestTaskCreatedTime: tli.OldestTaskCreatedTime,
+	}
+	tlm.paused = tli.Paused
 	return &persistence.UpdateTaskListResponse{}, nil
 }
 
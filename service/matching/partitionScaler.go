@@ -0,0 +1,205 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/types"
+)
+
+const (
+	// partitionScalerIdentity is recorded in the dynamic config change history for the partition counts it writes
+	partitionScalerIdentity = "cadence-matching-partition-scaler"
+	// partitionScalerRequestTimeout bounds the lookup of the backlog of a partition being removed
+	partitionScalerRequestTimeout = 5 * time.Second
+)
+
+var errConditionalUpdateNotSupported = errors.New("dynamic config client doesn't support conditional updates")
+
+type (
+	// partitionScaler adjusts the number of read and write partitions of a task list from the load of its
+	// root partition. There is no room for the partition counts in the task list info of the persistence,
+	// so they are written to the dynamic config store where the matching clients of all the services and
+	// the other partitions already read them from.
+	partitionScaler struct {
+		tlMgr  *taskListManagerImpl
+		client dynamicconfig.Client
+		logger log.Logger
+	}
+)
+
+func newPartitionScaler(tlMgr *taskListManagerImpl, client dynamicconfig.Client) *partitionScaler {
+	return &partitionScaler{
+		tlMgr:  tlMgr,
+		client: client,
+		logger: tlMgr.logger,
+	}
+}
+
+func (s *partitionScaler) Start() {
+	go s.run()
+}
+
+func (s *partitionScaler) run() {
+	timer := time.NewTimer(s.tlMgr.config.PartitionScaleInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.tlMgr.shutdownCh:
+			return
+		case <-timer.C:
+			if s.tlMgr.config.EnablePartitionAutoScaling() {
+				if err := s.scale(); err != nil {
+					s.logger.Warn("Failed to scale task list partitions", tag.Error(err))
+				}
+			}
+			timer.Reset(s.tlMgr.config.PartitionScaleInterval())
+		}
+	}
+}
+
+// scale adds a partition when the load of the root partition is above the scale up rate and removes one when
+// the load spread over one partition less is still below the scale down rate and no task had to be persisted.
+// A partition is added to the read partitions before the write partitions so that it is polled before tasks
+// are written to it, and it is removed from the write partitions first and from the read partitions only
+// once its backlog is drained.
+func (s *partitionScaler) scale() error {
+	config := s.tlMgr.config
+	numRead := config.NumReadPartitions()
+	numWrite := config.NumWritePartitions()
+	minPartitions := config.MinTaskListPartitions()
+	maxPartitions := common.MaxInt(minPartitions, config.MaxTaskListPartitions())
+
+	if numRead > numWrite {
+		ctx, cancel := context.WithTimeout(context.Background(), partitionScalerRequestTimeout)
+		defer cancel()
+		id := s.tlMgr.taskListID
+		drained, err := s.tlMgr.engine.isPartitionDrained(ctx, id.domainID, s.tlMgr.domainName, id.mkName(numRead-1), id.taskType)
+		if err != nil || !drained {
+			return err
+		}
+		return s.updatePartitionCount(dynamicconfig.MatchingNumTasklistReadPartitions, numRead-1)
+	}
+
+	stats := s.tlMgr.stats
+	added := stats.addedPerSecond()
+	load := math.Max(added, stats.polledPerSecond())
+	switch {
+	case numWrite < maxPartitions && load > float64(config.PartitionScaleUpRPS()):
+		if err := s.updatePartitionCount(dynamicconfig.MatchingNumTasklistReadPartitions, numWrite+1); err != nil {
+			return err
+		}
+		return s.updatePartitionCount(dynamicconfig.MatchingNumTasklistWritePartitions, numWrite+1)
+	case numWrite > minPartitions && stats.syncMatchedPerSecond() >= added &&
+		load*float64(numWrite)/float64(numWrite-1) < float64(config.PartitionScaleDownRPS()):
+		return s.updatePartitionCount(dynamicconfig.MatchingNumTasklistWritePartitions, numWrite-1)
+	}
+	return nil
+}
+
+// updatePartitionCount writes the partition count of the task list, the values of the same config for
+// other task lists or filters are kept. The count is written with a conditional update, so that the counts
+// written concurrently by the root partitions of other task lists aren't lost.
+func (s *partitionScaler) updatePartitionCount(key dynamicconfig.IntKey, count int) error {
+	client, ok := s.client.(dynamicconfig.ConditionalClient)
+	if !ok {
+		return errConditionalUpdateNotSupported
+	}
+	filters, err := s.filters()
+	if err != nil {
+		return err
+	}
+	value, err := newJSONDataBlob(count)
+	if err != nil {
+		return err
+	}
+
+	err = client.UpdateValueFromCurrent(key, func(current []*types.DynamicConfigValue) ([]*types.DynamicConfigValue, error) {
+		values := make([]*types.DynamicConfigValue, 0, len(current)+1)
+		for _, v := range current {
+			if !sameDynamicConfigFilters(v.Filters, filters) {
+				values = append(values, v)
+			}
+		}
+		return append(values, &types.DynamicConfigValue{Value: value, Filters: filters}), nil
+	}, partitionScalerIdentity)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Task list partitions scaled", tag.Key(key.String()), tag.Counter(count))
+	return nil
+}
+
+func (s *partitionScaler) filters() ([]*types.DynamicConfigFilter, error) {
+	filterValues := []struct {
+		filter dynamicconfig.Filter
+		value  interface{}
+	}{
+		{dynamicconfig.DomainName, s.tlMgr.domainName},
+		{dynamicconfig.TaskListName, s.tlMgr.taskListID.name},
+		{dynamicconfig.TaskType, s.tlMgr.taskListID.taskType},
+	}
+	filters := make([]*types.DynamicConfigFilter, 0, len(filterValues))
+	for _, f := range filterValues {
+		value, err := newJSONDataBlob(f.value)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, &types.DynamicConfigFilter{Name: f.filter.String(), Value: value})
+	}
+	return filters, nil
+}
+
+func newJSONDataBlob(value interface{}) (*types.DataBlob, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: data}, nil
+}
+
+func sameDynamicConfigFilters(a []*types.DynamicConfigFilter, b []*types.DynamicConfigFilter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	decode := func(filters []*types.DynamicConfigFilter) map[string]interface{} {
+		decoded := make(map[string]interface{}, len(filters))
+		for _, filter := range filters {
+			var value interface{}
+			if filter.Value != nil {
+				_ = json.Unmarshal(filter.Value.Data, &value)
+			}
+			decoded[filter.Name] = value
+		}
+		return decoded
+	}
+	return reflect.DeepEqual(decode(a), decode(b))
+}
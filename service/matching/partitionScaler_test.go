@@ -0,0 +1,199 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/client/matching"
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+func createTestPartitionScaler(
+	controller *gomock.Controller,
+	numRead int,
+	numWrite int,
+) (*partitionScaler, *dynamicconfig.MockConditionalClient) {
+	cfg := defaultTestConfig()
+	cfg.NumTasklistReadPartitions = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(numRead)
+	cfg.NumTasklistWritePartitions = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(numWrite)
+	cfg.PartitionScaleUpRPS = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(10)
+	cfg.PartitionScaleDownRPS = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(5)
+	cfg.MaxTaskListPartitions = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(4)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+
+	// all the events of the tests are recorded in the same second
+	tlm.stats = newTaskListStats(clock.NewEventTimeSource().Update(time.Unix(1600000000, 0)))
+	client := dynamicconfig.NewMockConditionalClient(controller)
+	return newPartitionScaler(tlm, conditionalTestClient{dynamicconfig.NewMockClient(controller), client}), client
+}
+
+type conditionalTestClient struct {
+	*dynamicconfig.MockClient
+	*dynamicconfig.MockConditionalClient
+}
+
+// expectPartitionCountUpdate expects the partition count of the test task list to be written along with
+// the value of another task list which must be kept
+func expectPartitionCountUpdate(t *testing.T, client *dynamicconfig.MockConditionalClient, key dynamicconfig.IntKey, count int) {
+	otherValue := &types.DynamicConfigValue{
+		Value: &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: []byte("3")},
+		Filters: []*types.DynamicConfigFilter{
+			{Name: dynamicconfig.TaskListName.String(), Value: &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: []byte(`"other"`)}},
+		},
+	}
+	oldValue := &types.DynamicConfigValue{
+		Value: &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: []byte("1")},
+		Filters: []*types.DynamicConfigFilter{
+			{Name: dynamicconfig.DomainName.String(), Value: &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: []byte(`"domainName"`)}},
+			{Name: dynamicconfig.TaskListName.String(), Value: &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: []byte(`"tl"`)}},
+			{Name: dynamicconfig.TaskType.String(), Value: &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: []byte("1")}},
+		},
+	}
+	client.EXPECT().UpdateValueFromCurrent(key, gomock.Any(), partitionScalerIdentity).DoAndReturn(
+		func(_ dynamicconfig.Key, update func([]*types.DynamicConfigValue) ([]*types.DynamicConfigValue, error), _ string) error {
+			values, err := update([]*types.DynamicConfigValue{otherValue, oldValue})
+			require.NoError(t, err)
+			require.Len(t, values, 2)
+			assert.Equal(t, otherValue, values[0])
+			assert.True(t, sameDynamicConfigFilters(oldValue.Filters, values[1].Filters))
+			var written int
+			require.NoError(t, json.Unmarshal(values[1].Value.Data, &written))
+			assert.Equal(t, count, written)
+			return nil
+		})
+}
+
+func TestPartitionScaler_ScaleUp(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scaler, client := createTestPartitionScaler(controller, 1, 1)
+	for i := 0; i < 20; i++ {
+		scaler.tlMgr.stats.recordAdded()
+	}
+	expectPartitionCountUpdate(t, client, dynamicconfig.MatchingNumTasklistReadPartitions, 2)
+	expectPartitionCountUpdate(t, client, dynamicconfig.MatchingNumTasklistWritePartitions, 2)
+	require.NoError(t, scaler.scale())
+}
+
+func TestPartitionScaler_NotScaledAboveMaxPartitions(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scaler, _ := createTestPartitionScaler(controller, 4, 4)
+	for i := 0; i < 20; i++ {
+		scaler.tlMgr.stats.recordPolled()
+	}
+	require.NoError(t, scaler.scale())
+}
+
+func TestPartitionScaler_ScaleDown(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scaler, client := createTestPartitionScaler(controller, 2, 2)
+	scaler.tlMgr.stats.recordAdded()
+	scaler.tlMgr.stats.recordSyncMatched()
+	expectPartitionCountUpdate(t, client, dynamicconfig.MatchingNumTasklistWritePartitions, 1)
+	require.NoError(t, scaler.scale())
+}
+
+func TestPartitionScaler_NotScaledDownWithBacklog(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	// the task was persisted instead of being sync matched
+	scaler, _ := createTestPartitionScaler(controller, 2, 2)
+	scaler.tlMgr.stats.recordAdded()
+	require.NoError(t, scaler.scale())
+}
+
+func TestPartitionScaler_ReadPartitionRemovedOnceDrained(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scaler, client := createTestPartitionScaler(controller, 2, 1)
+	matchingClient := matching.NewMockClient(controller)
+	scaler.tlMgr.engine.matchingClient = matchingClient
+	partition := newTestTaskListID("domain", scaler.tlMgr.taskListID.mkName(1), persistence.TaskListTypeActivity)
+	taskManager := scaler.tlMgr.engine.taskManager.(*testTaskManager)
+
+	matchingClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, request *types.MatchingDescribeTaskListRequest, _ ...interface{}) (*types.DescribeTaskListResponse, error) {
+			assert.Equal(t, partition.name, request.DescRequest.TaskList.GetName())
+			assert.Equal(t, types.TaskListTypeActivity, request.DescRequest.GetTaskListType())
+			return &types.DescribeTaskListResponse{
+				TaskListStatus: &types.TaskListStatus{BacklogCountHint: 0, AckLevel: 10},
+			}, nil
+		}).Times(3)
+
+	// a task above the ack level is left in the database
+	taskManager.getTaskListManager(partition).tasks.Put(int64(11), &persistence.TaskInfo{TaskID: 11})
+	require.NoError(t, scaler.scale())
+
+	// tasks up to the ack level are completed
+	taskManager.getTaskListManager(partition).tasks.Remove(int64(11))
+	taskManager.getTaskListManager(partition).tasks.Put(int64(10), &persistence.TaskInfo{TaskID: 10})
+	expectPartitionCountUpdate(t, client, dynamicconfig.MatchingNumTasklistReadPartitions, 1)
+	require.NoError(t, scaler.scale())
+
+	// the write partitions are not scaled while a read partition is being removed
+	for i := 0; i < 20; i++ {
+		scaler.tlMgr.stats.recordAdded()
+	}
+	client.EXPECT().UpdateValueFromCurrent(dynamicconfig.MatchingNumTasklistReadPartitions, gomock.Any(), gomock.Any()).Return(nil)
+	require.NoError(t, scaler.scale())
+}
+
+func TestPartitionScaler_ReadPartitionKeptWithBacklog(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scaler, _ := createTestPartitionScaler(controller, 2, 1)
+	matchingClient := matching.NewMockClient(controller)
+	scaler.tlMgr.engine.matchingClient = matchingClient
+	matchingClient.EXPECT().DescribeTaskList(gomock.Any(), gomock.Any()).Return(&types.DescribeTaskListResponse{
+		TaskListStatus: &types.TaskListStatus{BacklogCountHint: 5},
+	}, nil)
+	require.NoError(t, scaler.scale())
+}
+
+func TestPartitionScaler_ConditionalUpdateNotSupported(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scaler, _ := createTestPartitionScaler(controller, 1, 1)
+	scaler.client = dynamicconfig.NewMockClient(controller)
+	for i := 0; i < 20; i++ {
+		scaler.tlMgr.stats.recordAdded()
+	}
+	assert.Equal(t, errConditionalUpdateNotSupported, scaler.scale())
+}
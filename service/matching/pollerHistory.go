@@ -21,17 +21,23 @@
 package matching
 
 import (
+	"context"
+	"sync"
 	"time"
 
+	"go.uber.org/yarpc"
+
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/client"
 	"github.com/uber/cadence/common/types"
 )
 
 const (
-	pollerHistoryInitSize    = 0
-	pollerHistoryInitMaxSize = 5000
-	pollerHistoryTTL         = 5 * time.Minute
+	pollerHistoryInitSize = 0
+
+	// capability of the pollers whose client handles the WorkflowExecutionAlreadyCompletedError
+	workflowExecutionAlreadyCompletedErrorCapability = "workflowExecutionAlreadyCompletedError"
 )
 
 type (
@@ -39,6 +45,11 @@ type (
 
 	pollerInfo struct {
 		ratePerSecond float64
+		// binary checksum reported by decision pollers
+		buildID        string
+		clientImpl     string
+		featureVersion string
+		capabilities   []string
 	}
 )
 
@@ -47,6 +58,10 @@ type pollerHistory struct {
 	// pollers map[pollerID]pollerInfo
 	history cache.Cache
 
+	// number of outstanding polls by poller identity
+	concurrentPollsLock sync.Mutex
+	concurrentPolls     map[pollerIdentity]int32
+
 	// OnHistoryUpdatedFunc is a function called when the poller history was updated
 	onHistoryUpdatedFunc HistoryUpdatedFunc
 }
@@ -54,31 +69,73 @@ type pollerHistory struct {
 // HistoryUpdatedFunc is a type for notifying applications when the poller history was updated
 type HistoryUpdatedFunc func()
 
-func newPollerHistory(historyUpdatedFunc HistoryUpdatedFunc) *pollerHistory {
+func newPollerHistory(ttl time.Duration, maxSize int, historyUpdatedFunc HistoryUpdatedFunc) *pollerHistory {
 	opts := &cache.Options{
 		InitialCapacity: pollerHistoryInitSize,
-		TTL:             pollerHistoryTTL,
+		TTL:             ttl,
 		Pin:             false,
-		MaxCount:        pollerHistoryInitMaxSize,
+		MaxCount:        maxSize,
 	}
 
 	return &pollerHistory{
 		history:              cache.New(opts),
+		concurrentPolls:      make(map[pollerIdentity]int32),
 		onHistoryUpdatedFunc: historyUpdatedFunc,
 	}
 }
 
-func (pollers *pollerHistory) updatePollerInfo(id pollerIdentity, ratePerSecond *float64) {
+// newPollerInfo returns the info of the poller making the poll request of the context,
+// the client info is read from the headers forwarded by the frontend
+func newPollerInfo(ctx context.Context, ratePerSecond *float64) *pollerInfo {
 	rps := _defaultTaskDispatchRPS
 	if ratePerSecond != nil {
 		rps = *ratePerSecond
 	}
-	pollers.history.Put(id, &pollerInfo{ratePerSecond: rps})
+	info := &pollerInfo{ratePerSecond: rps}
+	info.buildID, _ = ctx.Value(buildIDKey).(string)
+
+	call := yarpc.CallFromContext(ctx)
+	info.clientImpl = call.Header(common.ClientImplHeaderName)
+	info.featureVersion = call.Header(common.FeatureVersionHeaderName)
+	featureFlags := client.GetFeatureFlagsFromHeader(call)
+	if featureFlags.GetWorkflowExecutionAlreadyCompletedErrorEnabled() {
+		info.capabilities = append(info.capabilities, workflowExecutionAlreadyCompletedErrorCapability)
+	}
+	return info
+}
+
+func (pollers *pollerHistory) updatePollerInfo(id pollerIdentity, info *pollerInfo) {
+	pollers.history.Put(id, info)
 	if pollers.onHistoryUpdatedFunc != nil {
 		pollers.onHistoryUpdatedFunc()
 	}
 }
 
+// startPoll records the poller info and counts the poll as outstanding until endPoll is called
+func (pollers *pollerHistory) startPoll(id pollerIdentity, info *pollerInfo) {
+	pollers.concurrentPollsLock.Lock()
+	pollers.concurrentPolls[id]++
+	pollers.concurrentPollsLock.Unlock()
+	pollers.updatePollerInfo(id, info)
+}
+
+// endPoll records the poller info again to update the last access time of the poller
+func (pollers *pollerHistory) endPoll(id pollerIdentity, info *pollerInfo) {
+	pollers.concurrentPollsLock.Lock()
+	pollers.concurrentPolls[id]--
+	if pollers.concurrentPolls[id] <= 0 {
+		delete(pollers.concurrentPolls, id)
+	}
+	pollers.concurrentPollsLock.Unlock()
+	pollers.updatePollerInfo(id, info)
+}
+
+func (pollers *pollerHistory) getConcurrentPollCount(id pollerIdentity) int32 {
+	pollers.concurrentPollsLock.Lock()
+	defer pollers.concurrentPollsLock.Unlock()
+	return pollers.concurrentPolls[id]
+}
+
 func (pollers *pollerHistory) getPollerInfo(earliestAccessTime time.Time) []*types.PollerInfo {
 	var result []*types.PollerInfo
 
@@ -92,9 +149,14 @@ func (pollers *pollerHistory) getPollerInfo(earliestAccessTime time.Time) []*typ
 		lastAccessTime := entry.CreateTime()
 		if earliestAccessTime.Before(lastAccessTime) {
 			result = append(result, &types.PollerInfo{
-				Identity:       string(key),
-				LastAccessTime: common.Int64Ptr(lastAccessTime.UnixNano()),
-				RatePerSecond:  value.ratePerSecond,
+				Identity:             string(key),
+				LastAccessTime:       common.Int64Ptr(lastAccessTime.UnixNano()),
+				RatePerSecond:        value.ratePerSecond,
+				BuildID:              value.buildID,
+				ClientImpl:           value.clientImpl,
+				ClientFeatureVersion: value.featureVersion,
+				Capabilities:         value.capabilities,
+				ConcurrentPollCount:  pollers.getConcurrentPollCount(key),
 			})
 		}
 	}
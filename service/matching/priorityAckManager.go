@@ -0,0 +1,108 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/messaging"
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	// priorityAckManager tracks the read and ack levels of the tasks of each priority on top of the
+	// ack manager of the whole task list. The ack level of the whole task list is the one persisted
+	// and garbage collected up to, the levels of a priority tell how far its backlog is dispatched.
+	// A priority is tracked only while it has tasks read but not acked yet.
+	priorityAckManager struct {
+		messaging.AckManager
+		logger log.Logger
+
+		sync.Mutex
+		levels map[int]messaging.AckManager
+	}
+
+	// priorityLevel is the state of the tasks of a priority
+	priorityLevel struct {
+		Priority     int
+		ReadLevel    int64
+		AckLevel     int64
+		BacklogCount int64
+	}
+)
+
+func newPriorityAckManager(logger log.Logger) *priorityAckManager {
+	return &priorityAckManager{
+		AckManager: messaging.NewAckManager(logger),
+		logger:     logger,
+		levels:     make(map[int]messaging.AckManager),
+	}
+}
+
+// ReadTask registers the task as read for the task list and for its priority
+func (m *priorityAckManager) ReadTask(task *persistence.TaskInfo) error {
+	if err := m.ReadItem(task.TaskID); err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	level, ok := m.levels[task.Priority]
+	if !ok {
+		level = messaging.NewAckManager(m.logger)
+		m.levels[task.Priority] = level
+	}
+	return level.ReadItem(task.TaskID)
+}
+
+// AckTask acks the task for its priority and for the task list, and returns the new ack level of the task list
+func (m *priorityAckManager) AckTask(task *persistence.TaskInfo) int64 {
+	m.Lock()
+	if level, ok := m.levels[task.Priority]; ok {
+		level.AckItem(task.TaskID)
+		if level.GetBacklogCount() == 0 {
+			delete(m.levels, task.Priority)
+		}
+	} else {
+		m.logger.Warn("Completion for a task of an untracked priority", tag.TaskID(task.TaskID))
+	}
+	m.Unlock()
+	return m.AckItem(task.TaskID)
+}
+
+// GetPriorityLevels returns the levels of the priorities with tasks read but not acked yet, highest priority first
+func (m *priorityAckManager) GetPriorityLevels() []priorityLevel {
+	m.Lock()
+	defer m.Unlock()
+	result := make([]priorityLevel, 0, len(m.levels))
+	for priority, level := range m.levels {
+		result = append(result, priorityLevel{
+			Priority:     priority,
+			ReadLevel:    level.GetReadLevel(),
+			AckLevel:     level.GetAckLevel(),
+			BacklogCount: level.GetBacklogCount(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Priority > result[j].Priority })
+	return result
+}
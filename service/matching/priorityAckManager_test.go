@@ -0,0 +1,71 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/persistence"
+)
+
+func TestPriorityAckManager(t *testing.T) {
+	m := newPriorityAckManager(log.NewNoop())
+	m.SetAckLevel(100)
+
+	tasks := []*persistence.TaskInfo{
+		{TaskID: 101, Priority: 1},
+		{TaskID: 102, Priority: 0},
+		{TaskID: 103, Priority: 1},
+		{TaskID: 104, Priority: 0},
+	}
+	for _, task := range tasks {
+		require.NoError(t, m.ReadTask(task))
+	}
+	assert.Equal(t, int64(104), m.GetReadLevel())
+	assert.Equal(t, int64(4), m.GetBacklogCount())
+
+	// acking the tasks of the higher priority moves its ack level, while the ack level of
+	// the task list is held back by the tasks of the lower priority
+	assert.Equal(t, int64(101), m.AckTask(tasks[0]))
+	assert.Equal(t, int64(101), m.AckTask(tasks[2]))
+	assert.Equal(t, []priorityLevel{
+		{Priority: 0, ReadLevel: 104, AckLevel: 101, BacklogCount: 2},
+	}, m.GetPriorityLevels())
+
+	assert.Equal(t, int64(103), m.AckTask(tasks[1]))
+	assert.Equal(t, []priorityLevel{
+		{Priority: 0, ReadLevel: 104, AckLevel: 102, BacklogCount: 1},
+	}, m.GetPriorityLevels())
+
+	assert.Equal(t, int64(104), m.AckTask(tasks[3]))
+	assert.Empty(t, m.GetPriorityLevels())
+	assert.Equal(t, int64(0), m.GetBacklogCount())
+
+	// a priority is tracked again once it has tasks read
+	require.NoError(t, m.ReadTask(&persistence.TaskInfo{TaskID: 105, Priority: 1}))
+	assert.Equal(t, []priorityLevel{
+		{Priority: 1, ReadLevel: 105, AckLevel: 104, BacklogCount: 1},
+	}, m.GetPriorityLevels())
+}
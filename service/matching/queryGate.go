@@ -0,0 +1,70 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import "sync"
+
+// queryGate tracks the query tasks waiting for a local poller, the dispatch of the backlog
+// is held while a query is pending so that the next poller is reserved for the query
+type queryGate struct {
+	sync.Mutex
+	pending int
+	// closed once a query is pending, replaced when no query is pending anymore
+	pendingC chan struct{}
+	// closed once no query is pending, replaced when a query becomes pending
+	clearC chan struct{}
+}
+
+func newQueryGate() *queryGate {
+	g := &queryGate{
+		pendingC: make(chan struct{}),
+		clearC:   make(chan struct{}),
+	}
+	close(g.clearC)
+	return g
+}
+
+func (g *queryGate) enter() {
+	g.Lock()
+	defer g.Unlock()
+	if g.pending == 0 {
+		close(g.pendingC)
+		g.clearC = make(chan struct{})
+	}
+	g.pending++
+}
+
+func (g *queryGate) leave() {
+	g.Lock()
+	defer g.Unlock()
+	g.pending--
+	if g.pending == 0 {
+		close(g.clearC)
+		g.pendingC = make(chan struct{})
+	}
+}
+
+// wait returns a channel closed once a query is pending and a channel closed once no query is pending
+func (g *queryGate) wait() (<-chan struct{}, <-chan struct{}) {
+	g.Lock()
+	defer g.Unlock()
+	return g.pendingC, g.clearC
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryGate(t *testing.T) {
+	gate := newQueryGate()
+	pendingC, clearC := gate.wait()
+	requireClosed(t, clearC)
+	requireOpen(t, pendingC)
+
+	gate.enter()
+	requireClosed(t, pendingC)
+	pendingC, clearC = gate.wait()
+	requireClosed(t, pendingC)
+	requireOpen(t, clearC)
+
+	gate.enter()
+	gate.leave()
+	requireOpen(t, clearC)
+	gate.leave()
+	requireClosed(t, clearC)
+	pendingC, clearC = gate.wait()
+	requireOpen(t, pendingC)
+	requireClosed(t, clearC)
+}
+
+func requireClosed(t *testing.T, c <-chan struct{}) {
+	select {
+	case <-c:
+	default:
+		require.Fail(t, "channel is not closed")
+	}
+}
+
+func requireOpen(t *testing.T, c <-chan struct{}) {
+	select {
+	case <-c:
+		require.Fail(t, "channel is closed")
+	default:
+	}
+}
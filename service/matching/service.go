@@ -21,6 +21,7 @@
 package matching
 
 import (
+	"context"
 	"sync/atomic"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/resource"
 	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/watchdog"
 )
 
 // Service represents the cadence-matching service
@@ -38,6 +40,9 @@ type Service struct {
 	handler Handler
 	stopC   chan struct{}
 	config  *Config
+	engine  Engine
+	// dynamic config client the partition counts of task lists are written to
+	dynamicConfigClient dynamicconfig.Client
 }
 
 // NewService builds a new cadence-matching service
@@ -68,10 +73,11 @@ func NewService(
 	}
 
 	return &Service{
-		Resource: serviceResource,
-		status:   common.DaemonStatusInitialized,
-		config:   serviceConfig,
-		stopC:    make(chan struct{}),
+		Resource:            serviceResource,
+		status:              common.DaemonStatusInitialized,
+		config:              serviceConfig,
+		stopC:               make(chan struct{}),
+		dynamicConfigClient: params.DynamicConfig,
 	}, nil
 }
 
@@ -94,9 +100,15 @@ func (s *Service) Start() {
 		s.GetMetricsClient(),
 		s.GetDomainCache(),
 		s.GetMembershipResolver(),
+		s.GetUsageRecorder(),
+		s.GetPersistenceBean().GetMatchingExpiredTaskQueueManager(),
+		s.GetTaskTokenSerializer(),
+		s.dynamicConfigClient,
 	)
 
-	s.handler = NewHandler(engine, s.config, s.GetDomainCache(), s.GetMetricsClient(), s.GetLogger(), s.GetThrottledLogger())
+	s.engine = engine
+	s.handler = NewHandler(engine, s.config, s.GetDomainCache(), s.GetCacheRegistry(), s.GetWatchdog(), s.GetMetricsClient(), s.GetLogger(), s.GetThrottledLogger())
+	s.registerWatchdogProbes(engine.(*matchingEngineImpl))
 
 	thriftHandler := NewThriftHandler(s.handler)
 	thriftHandler.register(s.GetDispatcher())
@@ -104,6 +116,9 @@ func (s *Service) Start() {
 	grpcHandler := newGRPCHandler(s.handler)
 	grpcHandler.register(s.GetDispatcher())
 
+	jsonHandler := newJSONHandler(s.handler)
+	jsonHandler.register(s.GetDispatcher())
+
 	// must start base service first
 	s.Resource.Start()
 	s.handler.Start()
@@ -113,6 +128,25 @@ func (s *Service) Start() {
 	<-s.stopC
 }
 
+// registerWatchdogProbes tracks the task lists loaded by the engine, which are also the load of the goroutines
+// as every task list runs its own pumps and pollers
+func (s *Service) registerWatchdogProbes(engine *matchingEngineImpl) {
+	s.GetWatchdog().Register("tasklist_managers", watchdog.Probe{
+		Value: engine.getTaskListCount,
+		Owners: func() []watchdog.Owner {
+			counts := engine.getTaskListCountByDomain()
+			owners := make([]watchdog.Owner, 0, len(counts))
+			for domainName, count := range counts {
+				owners = append(owners, watchdog.Owner{Name: domainName, Count: count})
+			}
+			return owners
+		},
+	})
+	goroutines := watchdog.NewGoroutineProbe()
+	goroutines.Load = engine.getTaskListCount
+	s.GetWatchdog().Register("goroutines", goroutines)
+}
+
 // Stop stops the service
 func (s *Service) Stop() {
 	if !atomic.CompareAndSwapInt32(&s.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
@@ -124,6 +158,12 @@ func (s *Service) Stop() {
 	s.GetMembershipResolver().EvictSelf()
 	s.GetLogger().Info("ShutdownHandler: Waiting for others to discover I am unhealthy")
 	time.Sleep(s.config.ShutdownDrainDuration())
+	if s.engine != nil && s.config.EnableTaskListHandoff() {
+		s.GetLogger().Info("ShutdownHandler: Handing task lists off to their new owners")
+		ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownDrainDuration())
+		s.engine.HandoffTaskLists(ctx)
+		cancel()
+	}
 
 	close(s.stopC)
 
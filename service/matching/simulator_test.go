@@ -0,0 +1,313 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// simulation is a synthetic AddTask/Poll traffic pattern replayed against a task list manager
+	// backed by the in-memory test task manager
+	simulation struct {
+		name     string
+		duration time.Duration // duration of the add phase, the backlog is drained afterwards
+		addRPS   int           // rate at which the tasks are added
+		pollers  int           // number of concurrent pollers
+		// time a poller spends on a task before polling again
+		pollerProcessingTime time.Duration
+		// time the pollers wait after the start of the add phase before they begin polling
+		pollerStartDelay time.Duration
+		drainTimeout     time.Duration
+		config           func(*Config)
+	}
+
+	// simulationReport summarizes the dispatch of the tasks of a simulation
+	simulationReport struct {
+		added           int64
+		syncMatched     int64
+		dispatched      int64
+		addErrors       int64
+		latencyP50      time.Duration
+		latencyP99      time.Duration
+		latencyMax      time.Duration
+		maxBacklog      int64
+		backlogAtAddEnd int64
+		drainTime       time.Duration
+	}
+
+	simulator struct {
+		sim  simulation
+		tlm  *taskListManagerImpl
+		stop chan struct{}
+
+		added       int64
+		syncMatched int64
+		dispatched  int64
+		addErrors   int64
+		maxBacklog  int64
+
+		sync.Mutex
+		addTimes  map[int64]time.Time
+		latencies []time.Duration
+	}
+)
+
+func (r *simulationReport) syncMatchRatio() float64 {
+	if r.added == 0 {
+		return 0
+	}
+	return float64(r.syncMatched) / float64(r.added)
+}
+
+func (r *simulationReport) String() string {
+	return fmt.Sprintf(
+		"added=%d dispatched=%d addErrors=%d syncMatchRatio=%.2f latency(p50=%v p99=%v max=%v) backlog(max=%d atAddEnd=%d) drainTime=%v",
+		r.added, r.dispatched, r.addErrors, r.syncMatchRatio(),
+		r.latencyP50, r.latencyP99, r.latencyMax,
+		r.maxBacklog, r.backlogAtAddEnd, r.drainTime,
+	)
+}
+
+func runSimulation(t *testing.T, sim simulation) *simulationReport {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	if sim.config != nil {
+		sim.config(cfg)
+	}
+	s := &simulator{
+		sim:      sim,
+		tlm:      createTestTaskListManagerWithConfig(controller, cfg),
+		stop:     make(chan struct{}),
+		addTimes: make(map[int64]time.Time),
+	}
+	require.NoError(t, s.tlm.Start())
+	defer s.tlm.Stop()
+
+	var pollersWG sync.WaitGroup
+	for i := 0; i < sim.pollers; i++ {
+		pollersWG.Add(1)
+		go func() {
+			defer pollersWG.Done()
+			s.poll()
+		}()
+	}
+	samplerDone := make(chan struct{})
+	go func() {
+		defer close(samplerDone)
+		s.sampleBacklog()
+	}()
+
+	s.generate()
+	report := &simulationReport{backlogAtAddEnd: s.backlog()}
+	drainStart := time.Now()
+	drainTimeout := sim.drainTimeout
+	if drainTimeout == 0 {
+		drainTimeout = 10 * time.Second
+	}
+	for s.backlog() > 0 && time.Since(drainStart) < drainTimeout {
+		time.Sleep(10 * time.Millisecond)
+	}
+	report.drainTime = time.Since(drainStart)
+	close(s.stop)
+	pollersWG.Wait()
+	<-samplerDone
+
+	report.added = atomic.LoadInt64(&s.added)
+	report.syncMatched = atomic.LoadInt64(&s.syncMatched)
+	report.dispatched = atomic.LoadInt64(&s.dispatched)
+	report.addErrors = atomic.LoadInt64(&s.addErrors)
+	report.maxBacklog = atomic.LoadInt64(&s.maxBacklog)
+	s.Lock()
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	report.latencyP50 = percentile(s.latencies, 0.5)
+	report.latencyP99 = percentile(s.latencies, 0.99)
+	report.latencyMax = percentile(s.latencies, 1)
+	s.Unlock()
+
+	t.Logf("simulation %v: %v", sim.name, report)
+	return report
+}
+
+// generate adds the tasks at the rate of the simulation until the end of the add phase
+func (s *simulator) generate() {
+	ticker := time.NewTicker(time.Second / time.Duration(s.sim.addRPS))
+	defer ticker.Stop()
+	deadline := time.Now().Add(s.sim.duration)
+	for scheduleID := int64(1); time.Now().Before(deadline); scheduleID++ {
+		<-ticker.C
+		s.Lock()
+		s.addTimes[scheduleID] = time.Now()
+		s.Unlock()
+		atomic.AddInt64(&s.added, 1)
+		syncMatch, err := s.tlm.AddTask(context.Background(), addTaskParams{
+			execution: &types.WorkflowExecution{WorkflowID: "wid", RunID: "rid"},
+			taskInfo: &persistence.TaskInfo{
+				DomainID:               "domain",
+				WorkflowID:             "wid",
+				RunID:                  "rid",
+				ScheduleID:             scheduleID,
+				ScheduleToStartTimeout: 100,
+				CreatedTime:            time.Now(),
+			},
+		})
+		switch {
+		case err != nil:
+			atomic.AddInt64(&s.addErrors, 1)
+			atomic.AddInt64(&s.added, -1)
+		case syncMatch:
+			atomic.AddInt64(&s.syncMatched, 1)
+		}
+	}
+}
+
+func (s *simulator) poll() {
+	select {
+	case <-time.After(s.sim.pollerStartDelay):
+	case <-s.stop:
+		return
+	}
+	for {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		task, err := s.tlm.GetTask(context.Background(), nil)
+		if err != nil {
+			continue
+		}
+		now := time.Now()
+		s.Lock()
+		if addTime, ok := s.addTimes[task.event.ScheduleID]; ok {
+			s.latencies = append(s.latencies, now.Sub(addTime))
+			delete(s.addTimes, task.event.ScheduleID)
+		}
+		s.Unlock()
+		task.finish(nil)
+		atomic.AddInt64(&s.dispatched, 1)
+		if s.sim.pollerProcessingTime > 0 {
+			time.Sleep(s.sim.pollerProcessingTime)
+		}
+	}
+}
+
+// sampleBacklog tracks the peak of the tasks added but not dispatched yet
+func (s *simulator) sampleBacklog() {
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if backlog := s.backlog(); backlog > atomic.LoadInt64(&s.maxBacklog) {
+				atomic.StoreInt64(&s.maxBacklog, backlog)
+			}
+		}
+	}
+}
+
+func (s *simulator) backlog() int64 {
+	return atomic.LoadInt64(&s.added) - atomic.LoadInt64(&s.dispatched)
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+func TestMatchingSimulator(t *testing.T) {
+	tests := []struct {
+		sim    simulation
+		verify func(*testing.T, *simulationReport)
+	}{
+		{
+			sim: simulation{
+				name:     "pollers keep up with the adds",
+				duration: 500 * time.Millisecond,
+				addRPS:   200,
+				pollers:  4,
+			},
+			verify: func(t *testing.T, r *simulationReport) {
+				require.Greater(t, r.syncMatched, int64(0))
+			},
+		},
+		{
+			sim: simulation{
+				name:                 "slow pollers build a backlog",
+				duration:             500 * time.Millisecond,
+				addRPS:               200,
+				pollers:              1,
+				pollerProcessingTime: 10 * time.Millisecond,
+			},
+			verify: func(t *testing.T, r *simulationReport) {
+				require.Greater(t, r.maxBacklog, int64(0))
+			},
+		},
+		{
+			sim: simulation{
+				name:             "pollers join after a burst of adds",
+				duration:         300 * time.Millisecond,
+				addRPS:           200,
+				pollers:          4,
+				pollerStartDelay: 300 * time.Millisecond,
+			},
+			verify: func(t *testing.T, r *simulationReport) {
+				require.Greater(t, r.added-r.syncMatched, int64(0))
+				require.Greater(t, r.maxBacklog, int64(0))
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.sim.name, func(t *testing.T) {
+			report := runSimulation(t, tc.sim)
+			require.Zero(t, report.addErrors)
+			require.Greater(t, report.added, int64(0))
+			// every task added is dispatched exactly once
+			require.Equal(t, report.added, report.dispatched)
+			tc.verify(t, report)
+		})
+	}
+}
@@ -21,6 +21,8 @@
 package matching
 
 import (
+	"time"
+
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/types"
 )
@@ -55,6 +57,8 @@ type (
 		responseC                chan error // non-nil only where there is a caller waiting for response (sync-match)
 		backlogCountHint         int64
 		activityTaskDispatchInfo *types.ActivityTaskDispatchInfo
+		isolationGroup           string           // non-empty when the task is matched with the pollers of this isolation group first
+		dispatchLimiter          *dispatchLimiter // non-nil when the task holds a slot of the limiter of the running activities
 	}
 )
 
@@ -147,10 +151,32 @@ func (task *InternalTask) pollForActivityResponse() *types.PollForActivityTaskRe
 	return nil
 }
 
+// markActivityStarted hands the slot of the limiter held by an activity task over to the activity it started,
+// the slot is then held until the activity finishes or its start to close timeout expires. It returns false
+// when the task didn't hold a slot or the slot is released, as the running activities are not limited.
+// This method should be called before finish
+func (task *InternalTask) markActivityStarted(attempt int64, startToCloseTimeout time.Duration) bool {
+	if task.dispatchLimiter == nil {
+		return false
+	}
+	limiter := task.dispatchLimiter
+	task.dispatchLimiter = nil
+	return limiter.start(runningActivityKey{
+		workflowID: task.event.WorkflowID,
+		runID:      task.event.RunID,
+		scheduleID: task.event.ScheduleID,
+		attempt:    attempt,
+	}, startToCloseTimeout)
+}
+
 // finish marks a task as finished. Should be called after a poller picks up a task
 // and marks it as started. If the task is unable to marked as started, then this
 // method should be called with a non-nil error argument.
 func (task *InternalTask) finish(err error) {
+	if task.dispatchLimiter != nil {
+		task.dispatchLimiter.release()
+		task.dispatchLimiter = nil
+	}
 	switch {
 	case task.responseC != nil:
 		task.responseC <- err
@@ -0,0 +1,194 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"sync"
+
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	// taskBuffer holds the tasks loaded from persistence until they are dispatched to a poller.
+	// Tasks are kept in one queue per priority and the highest priority queue is always
+	// dispatched first.
+	taskBuffer struct {
+		sync.Mutex
+		levels  map[int]*taskLevel
+		size    int
+		slots   chan struct{} // bounds the number of buffered tasks
+		notifyC chan struct{} // signals the consumer that a task was added or the buffer was closed
+		closed  bool
+		// when true, the tasks of a priority are dispatched in round robin across their workflow IDs
+		// so that a single workflow with many tasks cannot hold back the other workflows of the task list
+		fairDispatch func() bool
+	}
+
+	// taskLevel holds the tasks of a priority in one FIFO queue per key. The key is the workflow
+	// ID when the dispatch is fair across workflows and empty otherwise.
+	taskLevel struct {
+		queues map[string][]*persistence.TaskInfo
+		keys   []string // keys with buffered tasks, in the order they are served
+		next   int      // index in keys of the next queue to serve
+	}
+)
+
+func newTaskBuffer(capacity int, fairDispatch func() bool) *taskBuffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &taskBuffer{
+		levels:       make(map[int]*taskLevel),
+		slots:        make(chan struct{}, capacity),
+		notifyC:      make(chan struct{}, 1),
+		fairDispatch: fairDispatch,
+	}
+}
+
+// put adds a task to the buffer, blocking while the buffer is full. It returns
+// false if shutdownC is closed before the task could be added.
+func (b *taskBuffer) put(task *persistence.TaskInfo, shutdownC <-chan struct{}) bool {
+	select {
+	case b.slots <- struct{}{}:
+	case <-shutdownC:
+		return false
+	}
+	key := ""
+	if b.fairDispatch != nil && b.fairDispatch() {
+		key = task.WorkflowID
+	}
+	b.Lock()
+	level, ok := b.levels[task.Priority]
+	if !ok {
+		level = &taskLevel{queues: make(map[string][]*persistence.TaskInfo)}
+		b.levels[task.Priority] = level
+	}
+	level.push(key, task)
+	b.size++
+	b.Unlock()
+	b.notify()
+	return true
+}
+
+// get removes and returns the next task with the highest priority, blocking while
+// the buffer is empty. It returns false once the buffer is closed and drained or
+// when shutdownC is closed.
+func (b *taskBuffer) get(shutdownC <-chan struct{}) (*persistence.TaskInfo, bool) {
+	for {
+		b.Lock()
+		if priority, ok := b.highestPriorityLocked(); ok {
+			level := b.levels[priority]
+			task := level.pop()
+			if len(level.keys) == 0 {
+				delete(b.levels, priority)
+			}
+			b.size--
+			b.Unlock()
+			<-b.slots
+			return task, true
+		}
+		closed := b.closed
+		b.Unlock()
+		if closed {
+			return nil, false
+		}
+
+		select {
+		case <-b.notifyC:
+		case <-shutdownC:
+			return nil, false
+		}
+	}
+}
+
+// close marks the buffer as closed, get returns false once all buffered tasks are consumed.
+func (b *taskBuffer) close() {
+	b.Lock()
+	b.closed = true
+	b.Unlock()
+	b.notify()
+}
+
+// hasHigherPriority returns true if the buffer holds a task with a priority higher than the given one.
+func (b *taskBuffer) hasHigherPriority(priority int) bool {
+	b.Lock()
+	defer b.Unlock()
+	highest, ok := b.highestPriorityLocked()
+	return ok && highest > priority
+}
+
+func (b *taskBuffer) len() int {
+	b.Lock()
+	defer b.Unlock()
+	return b.size
+}
+
+func (b *taskBuffer) cap() int {
+	return cap(b.slots)
+}
+
+func (b *taskBuffer) highestPriorityLocked() (int, bool) {
+	found := false
+	highest := 0
+	for priority := range b.levels {
+		if !found || priority > highest {
+			highest = priority
+			found = true
+		}
+	}
+	return highest, found
+}
+
+func (b *taskBuffer) notify() {
+	select {
+	case b.notifyC <- struct{}{}:
+	default: // channel already has an event, don't block
+	}
+}
+
+func (l *taskLevel) push(key string, task *persistence.TaskInfo) {
+	tasks, ok := l.queues[key]
+	if !ok {
+		// a new key is served after the keys already waiting
+		l.keys = append(l.keys, key)
+	}
+	l.queues[key] = append(tasks, task)
+}
+
+// pop removes the oldest task of the next key and moves to the following key,
+// it must only be called when the level holds at least one task
+func (l *taskLevel) pop() *persistence.TaskInfo {
+	key := l.keys[l.next]
+	tasks := l.queues[key]
+	task := tasks[0]
+	tasks[0] = nil
+	if len(tasks) == 1 {
+		delete(l.queues, key)
+		l.keys = append(l.keys[:l.next], l.keys[l.next+1:]...)
+	} else {
+		l.queues[key] = tasks[1:]
+		l.next++
+	}
+	if l.next >= len(l.keys) {
+		l.next = 0
+	}
+	return task
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uber/cadence/common/persistence"
+)
+
+func TestTaskBuffer_HighestPriorityFirst(t *testing.T) {
+	buffer := newTaskBuffer(10, nil)
+	shutdownC := make(chan struct{})
+
+	for i, priority := range []int{0, 5, 0, 10, 5} {
+		require.True(t, buffer.put(&persistence.TaskInfo{TaskID: int64(i), Priority: priority}, shutdownC))
+	}
+	assert.Equal(t, 5, buffer.len())
+	assert.True(t, buffer.hasHigherPriority(5))
+	assert.False(t, buffer.hasHigherPriority(10))
+
+	var taskIDs []int64
+	for i := 0; i < 5; i++ {
+		task, ok := buffer.get(shutdownC)
+		require.True(t, ok)
+		taskIDs = append(taskIDs, task.TaskID)
+	}
+	// tasks with the same priority are dispatched in the order they were added
+	assert.Equal(t, []int64{3, 1, 4, 0, 2}, taskIDs)
+	assert.Equal(t, 0, buffer.len())
+	assert.False(t, buffer.hasHigherPriority(-1))
+}
+
+func TestTaskBuffer_FairDispatchAcrossWorkflows(t *testing.T) {
+	fair := true
+	buffer := newTaskBuffer(20, func() bool { return fair })
+	shutdownC := make(chan struct{})
+
+	tasks := []*persistence.TaskInfo{
+		{TaskID: 1, WorkflowID: "A"},
+		{TaskID: 2, WorkflowID: "A"},
+		{TaskID: 3, WorkflowID: "A"},
+		{TaskID: 4, WorkflowID: "A"},
+		{TaskID: 5, WorkflowID: "B"},
+		{TaskID: 6, WorkflowID: "B"},
+		{TaskID: 7, WorkflowID: "C"},
+		{TaskID: 8, WorkflowID: "C", Priority: 5},
+	}
+	for _, task := range tasks {
+		require.True(t, buffer.put(task, shutdownC))
+	}
+
+	var taskIDs []int64
+	for i := 0; i < len(tasks); i++ {
+		task, ok := buffer.get(shutdownC)
+		require.True(t, ok)
+		taskIDs = append(taskIDs, task.TaskID)
+		if task.TaskID == 5 {
+			// a workflow that shows up while the others are served joins the end of the current round
+			require.True(t, buffer.put(&persistence.TaskInfo{TaskID: 9, WorkflowID: "D"}, shutdownC))
+		}
+	}
+	task, ok := buffer.get(shutdownC)
+	require.True(t, ok)
+	taskIDs = append(taskIDs, task.TaskID)
+	// the priority is still honored first, then the workflows take turns
+	assert.Equal(t, []int64{8, 1, 5, 7, 9, 2, 6, 3, 4}, taskIDs)
+
+	// tasks are dispatched in the order they were added once the fair dispatch is disabled
+	fair = false
+	for _, task := range tasks[:4] {
+		require.True(t, buffer.put(task, shutdownC))
+	}
+	require.True(t, buffer.put(&persistence.TaskInfo{TaskID: 5, WorkflowID: "B"}, shutdownC))
+	taskIDs = nil
+	for i := 0; i < 5; i++ {
+		task, ok := buffer.get(shutdownC)
+		require.True(t, ok)
+		taskIDs = append(taskIDs, task.TaskID)
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, taskIDs)
+	assert.Equal(t, 0, buffer.len())
+}
+
+func TestTaskBuffer_PutBlocksWhenFull(t *testing.T) {
+	buffer := newTaskBuffer(1, nil)
+	assert.Equal(t, 1, buffer.cap())
+	shutdownC := make(chan struct{})
+	require.True(t, buffer.put(&persistence.TaskInfo{TaskID: 1}, shutdownC))
+
+	added := make(chan bool)
+	go func() {
+		added <- buffer.put(&persistence.TaskInfo{TaskID: 2}, shutdownC)
+	}()
+	select {
+	case <-added:
+		t.Fatal("put should block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	task, ok := buffer.get(shutdownC)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), task.TaskID)
+	assert.True(t, <-added)
+
+	// a blocked put returns false on shutdown
+	go func() {
+		added <- buffer.put(&persistence.TaskInfo{TaskID: 3}, shutdownC)
+	}()
+	close(shutdownC)
+	assert.False(t, <-added)
+}
+
+func TestTaskBuffer_GetAfterClose(t *testing.T) {
+	buffer := newTaskBuffer(10, nil)
+	shutdownC := make(chan struct{})
+	require.True(t, buffer.put(&persistence.TaskInfo{TaskID: 1}, shutdownC))
+
+	got := make(chan bool)
+	buffer.close()
+	go func() {
+		_, ok := buffer.get(shutdownC)
+		got <- ok
+		_, ok = buffer.get(shutdownC)
+		got <- ok
+	}()
+	// buffered tasks are still returned once the buffer is closed
+	assert.True(t, <-got)
+	assert.False(t, <-got)
+}
+
+func TestTaskBuffer_GetUnblocksOnPut(t *testing.T) {
+	buffer := newTaskBuffer(10, nil)
+	shutdownC := make(chan struct{})
+
+	got := make(chan *persistence.TaskInfo)
+	go func() {
+		task, _ := buffer.get(shutdownC)
+		got <- task
+	}()
+	time.Sleep(10 * time.Millisecond)
+	require.True(t, buffer.put(&persistence.TaskInfo{TaskID: 7}, shutdownC))
+	assert.Equal(t, int64(7), (<-got).TaskID)
+
+	go func() {
+		task, _ := buffer.get(shutdownC)
+		got <- task
+	}()
+	close(shutdownC)
+	assert.Nil(t, <-got)
+}
@@ -23,17 +23,25 @@ package matching
 import (
 	"sync/atomic"
 	"time"
+
+	"github.com/uber/cadence/common/messaging"
 )
 
 type taskGC struct {
-	lock           int64
-	db             *taskListDB
-	ackLevel       int64
-	lastDeleteTime time.Time
-	config         *taskListConfig
+	lock                    int64
+	db                      *taskListDB
+	ackManager              messaging.AckManager
+	ackLevel                int64
+	lastDeleteTime          time.Time
+	lastCompletedDeleteTime time.Time
+	lastAckLevel            int64
+	lastAckLevelMovedAt     time.Time
+	config                  *taskListConfig
 }
 
-var maxTimeBetweenTaskDeletes = time.Second
+// maxAckLevelStallTime is the time after which the tasks completed above an ack level which
+// doesn't move are deleted one by one, as they are held back by a task which isn't completed
+var maxAckLevelStallTime = 10 * time.Second
 
 // newTaskGC returns an instance of a task garbage collector object
 // taskGC internally maintains a delete cursor and attempts to delete
@@ -42,12 +50,22 @@ var maxTimeBetweenTaskDeletes = time.Second
 // In order for the taskGC to actually delete tasks when Run() is called, one of
 // two conditions must be met
 //   - Size Threshold: More than MaxDeleteBatchSize tasks are waiting to be deleted (rough estimation)
-//   - Time Threshold: Time since previous delete was attempted exceeds maxTimeBetweenTaskDeletes
+//   - Time Threshold: Time since previous delete was attempted exceeds TaskDeleteFlushInterval
+//
+// The tasks completed above the ack level are buffered and deleted one by one instead when the ack level
+// hasn't moved for maxAckLevelStallTime, under the same conditions with CompletedTaskDeleteBatchSize.
 //
 // Finally, the Run() method is safe to be called from multiple threads. The underlying
 // implementation will make sure only one caller executes Run() and others simply bail out
-func newTaskGC(db *taskListDB, config *taskListConfig) *taskGC {
-	return &taskGC{db: db, config: config}
+func newTaskGC(db *taskListDB, ackManager messaging.AckManager, config *taskListConfig) *taskGC {
+	now := time.Now()
+	return &taskGC{
+		db:                      db,
+		ackManager:              ackManager,
+		config:                  config,
+		lastCompletedDeleteTime: now,
+		lastAckLevelMovedAt:     now,
+	}
 }
 
 // Run deletes a batch of completed tasks, if its possible to do so
@@ -67,6 +85,12 @@ func (tgc *taskGC) tryDeleteNextBatch(ackLevel int64, ignoreTimeCond bool) {
 		return
 	}
 	defer tgc.unlock()
+	if ackLevel != tgc.lastAckLevel {
+		tgc.lastAckLevel = ackLevel
+		tgc.lastAckLevelMovedAt = time.Now()
+	} else if ignoreTimeCond || time.Since(tgc.lastAckLevelMovedAt) > maxAckLevelStallTime {
+		tgc.deleteCompletedTasks(ignoreTimeCond)
+	}
 	batchSize := tgc.config.MaxTaskDeleteBatchSize()
 	if !tgc.checkPrecond(ackLevel, batchSize, ignoreTimeCond) {
 		return
@@ -81,12 +105,34 @@ func (tgc *taskGC) tryDeleteNextBatch(ackLevel int64, ignoreTimeCond bool) {
 	}
 }
 
+// deleteCompletedTasks deletes a batch of the tasks completed above the ack level one by one,
+// they can't be deleted with the tasks below the ack level until the task holding it back completes
+func (tgc *taskGC) deleteCompletedTasks(ignoreTimeCond bool) {
+	batchSize := tgc.config.CompletedTaskDeleteBatchSize()
+	taskIDs := tgc.ackManager.GetCompletedItems(batchSize)
+	if len(taskIDs) == 0 {
+		return
+	}
+	if len(taskIDs) < batchSize && !ignoreTimeCond && time.Since(tgc.lastCompletedDeleteTime) <= tgc.config.TaskDeleteFlushInterval() {
+		return
+	}
+	tgc.lastCompletedDeleteTime = time.Now()
+	deleted := make([]int64, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		if err := tgc.db.CompleteTask(taskID); err != nil {
+			break
+		}
+		deleted = append(deleted, taskID)
+	}
+	tgc.ackManager.RemoveCompletedItems(deleted)
+}
+
 func (tgc *taskGC) checkPrecond(ackLevel int64, batchSize int, ignoreTimeCond bool) bool {
 	backlog := ackLevel - tgc.ackLevel
 	if backlog >= int64(batchSize) {
 		return true
 	}
-	return backlog > 0 && (ignoreTimeCond || time.Since(tgc.lastDeleteTime) > maxTimeBetweenTaskDeletes)
+	return backlog > 0 && (ignoreTimeCond || time.Since(tgc.lastDeleteTime) > tgc.config.TaskDeleteFlushInterval())
 }
 
 func (tgc *taskGC) tryLock() bool {
@@ -27,6 +27,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -37,9 +38,9 @@ import (
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
-	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/tracing"
 	"github.com/uber/cadence/common/types"
 )
 
@@ -61,6 +62,7 @@ type (
 		source                   types.TaskSource
 		forwardedFrom            string
 		activityTaskDispatchInfo *types.ActivityTaskDispatchInfo
+		isolationGroup           string
 	}
 
 	taskListManager interface {
@@ -88,12 +90,21 @@ type (
 		String() string
 		GetTaskListKind() types.TaskListKind
 		TaskListID() *taskListID
+		// ReleaseStandbyTasks resumes dispatching the backlog kept while the domain was standby
+		ReleaseStandbyTasks()
+		// FlushWrites waits until the tasks being added to the backlog are written to persistence
+		FlushWrites(ctx context.Context) error
+		// SetPaused pauses or resumes the dispatch of the tasks, the state is persisted with the task list
+		SetPaused(paused bool) error
+		// FinishActivity releases the slot held by an activity dispatched from the task list, if any
+		FinishActivity(key runningActivityKey)
 	}
 
 	// Single task list in memory state
 	taskListManagerImpl struct {
 		taskListID     *taskListID
 		taskListKind   types.TaskListKind // sticky taskList has different process in persistence
+		ephemeral      bool               // the tasks of ephemeral task lists are only sync matched and never persisted
 		config         *taskListConfig
 		db             *taskListDB
 		engine         *matchingEngineImpl
@@ -101,8 +112,9 @@ type (
 		taskReader     *taskReader // reads tasks from db and async matches it with poller
 		liveness       *liveness
 		taskGC         *taskGC
-		taskAckManager messaging.AckManager // tracks ackLevel for delivered messages
-		matcher        *TaskMatcher         // for matching a task producer with a poller
+		taskAckManager *priorityAckManager // tracks ackLevel for delivered messages, overall and per priority
+		stats          *taskListStats      // tracks add and dispatch rates and the age of the backlog
+		matcher        *TaskMatcher        // for matching a task producer with a poller
 		domainCache    cache.DomainCache
 		logger         log.Logger
 		scope          metrics.Scope
@@ -119,6 +131,15 @@ type (
 		shutdownCh           chan struct{}  // Delivers stop to the pump that populates taskBuffer
 		startWG              sync.WaitGroup // ensures that background processes do not start until setup is ready
 		stopped              int32
+		// cancels the dynamic config subscriptions of this task list
+		unsubscribe []func()
+
+		// adjusts the number of partitions from the load, only set on the root partition of normal task lists
+		partitionScaler *partitionScaler
+
+		// resumedCh is closed when the task list is resumed, it is only set while the dispatch of the tasks is paused
+		pauseLock sync.Mutex
+		resumedCh chan struct{}
 	}
 )
 
@@ -129,7 +150,11 @@ const (
 
 var _ taskListManager = (*taskListManagerImpl)(nil)
 
-var errRemoteSyncMatchFailed = &types.RemoteSyncMatchedError{Message: "remote sync match failed"}
+var (
+	errRemoteSyncMatchFailed   = &types.RemoteSyncMatchedError{Message: "remote sync match failed"}
+	errEphemeralTaskNotMatched = &types.ServiceBusyError{Message: "no poller is available for the task of the ephemeral task list"}
+	errEphemeralTaskListPaused = &types.BadRequestError{Message: "ephemeral task lists cannot be paused"}
+)
 
 func newTaskListManager(
 	e *matchingEngineImpl,
@@ -154,16 +179,19 @@ func newTaskListManager(
 	scope := newPerTaskListScope(domainName, taskList.name, *taskListKind, e.metricsClient, metrics.MatchingTaskListMgrScope)
 	db := newTaskListDB(e.taskManager, taskList.domainID, domainName, taskList.name, taskList.taskType, int(*taskListKind), e.logger)
 
+	taskAckManager := newPriorityAckManager(e.logger)
 	tlMgr := &taskListManagerImpl{
 		domainCache:         e.domainCache,
 		engine:              e,
 		shutdownCh:          make(chan struct{}),
 		taskListID:          taskList,
 		taskListKind:        *taskListKind,
+		ephemeral:           *taskListKind == types.TaskListKindNormal && taskListConfig.EnableEphemeralTaskList(),
 		logger:              e.logger.WithTags(tag.WorkflowTaskListName(taskList.name), tag.WorkflowTaskListType(taskList.taskType)),
 		db:                  db,
-		taskAckManager:      messaging.NewAckManager(e.logger),
-		taskGC:              newTaskGC(db, taskListConfig),
+		taskAckManager:      taskAckManager,
+		stats:               newTaskListStats(clock.NewRealTimeSource()),
+		taskGC:              newTaskGC(db, taskAckManager, taskListConfig),
 		config:              taskListConfig,
 		outstandingPollsMap: make(map[string]context.CancelFunc),
 		domainName:          domainName,
@@ -173,7 +201,7 @@ func newTaskListManager(
 	taskListTypeMetricScope := tlMgr.scope.Tagged(
 		getTaskListTypeTag(taskList.taskType),
 	)
-	tlMgr.pollerHistory = newPollerHistory(func() {
+	tlMgr.pollerHistory = newPollerHistory(taskListConfig.PollerHistoryTTL(), taskListConfig.PollerHistoryMaxSize(), func() {
 		taskListTypeMetricScope.UpdateGauge(metrics.PollerPerTaskListCounter,
 			float64(len(tlMgr.pollerHistory.getPollerInfo(time.Time{}))))
 	})
@@ -184,7 +212,23 @@ func newTaskListManager(
 	if tlMgr.isFowardingAllowed(taskList, *taskListKind) {
 		fwdr = newForwarder(&taskListConfig.forwarderConfig, taskList, *taskListKind, e.matchingClient)
 	}
-	tlMgr.matcher = newTaskMatcher(taskListConfig, fwdr, tlMgr.scope)
+	matchLatencyScope := tlMgr.scope.Tagged(
+		metrics.TaskListKindTag(strings.ToLower(taskListKind.String())),
+		metrics.TaskListPartitionTag(taskList.IsRoot()),
+	)
+	tlMgr.matcher = newTaskMatcher(taskListConfig, fwdr, tlMgr.scope, matchLatencyScope)
+	if taskList.IsRoot() && *taskListKind == types.TaskListKindNormal && e.dynamicConfigClient != nil {
+		tlMgr.partitionScaler = newPartitionScaler(tlMgr, e.dynamicConfigClient)
+	}
+	// apply operator overrides of the dispatch rate right away instead of waiting for the next poll
+	tlMgr.unsubscribe = []func(){
+		config.dynamicConfig.SubscribeIntProperty(taskListConfig.TaskDispatchRPS, func(int) {
+			tlMgr.applyTaskDispatchRPSOverride()
+		}),
+		config.dynamicConfig.SubscribeIntProperty(taskListConfig.NumReadPartitions, func(int) {
+			tlMgr.applyTaskDispatchRPSOverride()
+		}),
+	}
 	tlMgr.startWG.Add(1)
 	return tlMgr, nil
 }
@@ -195,11 +239,21 @@ func (c *taskListManagerImpl) Start() error {
 	defer c.startWG.Done()
 
 	c.liveness.Start()
+	if c.ephemeral {
+		// ephemeral task lists have no backlog, there is no range to lease nor tasks to read
+		return nil
+	}
 	if err := c.taskWriter.Start(); err != nil {
 		c.Stop()
 		return err
 	}
+	if c.db.IsPaused() {
+		c.setPaused(true)
+	}
 	c.taskReader.Start()
+	if c.partitionScaler != nil {
+		c.partitionScaler.Start()
+	}
 
 	return nil
 }
@@ -210,10 +264,15 @@ func (c *taskListManagerImpl) Stop() {
 		return
 	}
 	c.engine.removeTaskListManager(c)
+	for _, unsubscribe := range c.unsubscribe {
+		unsubscribe()
+	}
 	close(c.shutdownCh)
 	c.liveness.Stop()
 	c.taskWriter.Stop()
-	c.taskReader.Stop()
+	if !c.ephemeral {
+		c.taskReader.Stop()
+	}
 	c.logger.Info("Task list manager state changed", tag.LifeCycleStopped)
 }
 
@@ -234,15 +293,39 @@ func (c *taskListManagerImpl) handleErr(err error) error {
 
 // AddTask adds a task to the task list. This method will first attempt a synchronous
 // match with a poller. When there are no pollers or if rate limit is exceeded, task will
-// be written to database and later asynchronously matched with a poller
+// be written to database and later asynchronously matched with a poller. The tasks of
+// ephemeral task lists are never written to database, adding them fails when they are not sync matched
 func (c *taskListManagerImpl) AddTask(ctx context.Context, params addTaskParams) (bool, error) {
 	c.startWG.Wait()
+	if c.config.DrainMode() {
+		return false, errTaskListDraining
+	}
 	if params.forwardedFrom == "" {
 		// request sent by history service
 		c.liveness.markAlive(time.Now())
 	}
+	span, ctx := tracing.StartChildSpan(ctx, "matching.AddTask")
+	span.SetTag(tracing.TagTaskList, c.taskListID.name)
+	span.SetTag(tracing.TagForwardedFrom, params.forwardedFrom)
 	var syncMatch bool
-	_, err := c.executeWithRetry(func() (interface{}, error) {
+	var err error
+	defer func() {
+		if syncMatch {
+			span.SetTag(tracing.TagDispatchPath, "sync")
+		} else {
+			span.SetTag(tracing.TagDispatchPath, "async")
+		}
+		tracing.FinishSpan(span, err)
+	}()
+	if c.ephemeral {
+		syncMatch, err = c.addEphemeralTask(ctx, params)
+		if err == nil {
+			c.stats.recordAdded()
+			c.stats.recordSyncMatched()
+		}
+		return syncMatch, err
+	}
+	_, err = c.executeWithRetry(func() (interface{}, error) {
 		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
@@ -265,10 +348,13 @@ func (c *taskListManagerImpl) AddTask(ctx context.Context, params addTaskParams)
 			return r, err
 		}
 
-		// active task, try sync match first
-		syncMatch, err = c.trySyncMatch(ctx, params)
-		if syncMatch {
-			return &persistence.CreateTasksResponse{}, err
+		// active task, try sync match first unless higher priority tasks are waiting in the
+		// backlog, in which case the task goes through the backlog so that those are dispatched first
+		if !c.taskReader.taskBuffer.hasHigherPriority(params.taskInfo.Priority) {
+			syncMatch, err = c.trySyncMatch(ctx, params)
+			if syncMatch {
+				return &persistence.CreateTasksResponse{}, err
+			}
 		}
 		if params.activityTaskDispatchInfo != nil {
 			return false, errRemoteSyncMatchFailed
@@ -290,6 +376,10 @@ func (c *taskListManagerImpl) AddTask(ctx context.Context, params addTaskParams)
 			tag.WorkflowTaskListType(c.taskListID.taskType),
 		)
 	} else {
+		c.stats.recordAdded()
+		if syncMatch {
+			c.stats.recordSyncMatched()
+		}
 		c.taskReader.Signal()
 	}
 
@@ -324,10 +414,23 @@ func (c *taskListManagerImpl) GetTask(
 	maxDispatchPerSecond *float64,
 ) (*InternalTask, error) {
 	c.liveness.markAlive(time.Now())
+	c.stats.recordPolled()
+	span, ctx := tracing.StartChildSpan(ctx, "matching.GetTask")
+	span.SetTag(tracing.TagTaskList, c.taskListID.name)
 	task, err := c.getTask(ctx, maxDispatchPerSecond)
 	if err != nil {
+		tracing.FinishSpan(span, err)
 		return nil, err
 	}
+	if task.isQuery() || task.responseC != nil {
+		span.SetTag(tracing.TagDispatchPath, "sync")
+	} else {
+		span.SetTag(tracing.TagDispatchPath, "async")
+	}
+	span.Finish()
+	if !task.isQuery() {
+		c.stats.recordDispatched()
+	}
 	task.domainName = c.domainName
 	task.backlogCountHint = c.taskAckManager.GetBacklogCount()
 	return task, nil
@@ -340,6 +443,16 @@ func (c *taskListManagerImpl) getTask(ctx context.Context, maxDispatchPerSecond
 	// returned to the handler before a context timeout error is generated.
 	childCtx, cancel := c.newChildContext(ctx, c.config.LongPollExpirationInterval(), returnEmptyTaskTimeBudget)
 	defer cancel()
+	// The poll returns an empty response as soon as the task list is stopped, e.g. unloaded for being idle,
+	// as a stopped task list doesn't match the poll with the tasks added afterwards. The liveness is stopped
+	// along with the task list.
+	go func() {
+		select {
+		case <-c.liveness.shutdownChan:
+			cancel()
+		case <-childCtx.Done():
+		}
+	}()
 
 	pollerID, ok := ctx.Value(pollerIDKey).(string)
 	if ok && pollerID != "" {
@@ -357,11 +470,20 @@ func (c *taskListManagerImpl) getTask(ctx context.Context, maxDispatchPerSecond
 
 	identity, ok := ctx.Value(identityKey).(string)
 	if ok && identity != "" {
-		c.pollerHistory.updatePollerInfo(pollerIdentity(identity), maxDispatchPerSecond)
-		defer func() {
-			// to update timestamp of this poller when long poll ends
-			c.pollerHistory.updatePollerInfo(pollerIdentity(identity), maxDispatchPerSecond)
-		}()
+		info := newPollerInfo(ctx, maxDispatchPerSecond)
+		c.pollerHistory.startPoll(pollerIdentity(identity), info)
+		// to update timestamp of this poller when long poll ends
+		defer c.pollerHistory.endPoll(pollerIdentity(identity), info)
+	}
+
+	// the poll is held while the task list is paused so that the tasks stay in the backlog,
+	// it gets an empty response unless the task list is resumed in the meantime
+	if resumedCh := c.getResumedCh(); resumedCh != nil {
+		select {
+		case <-resumedCh:
+		case <-childCtx.Done():
+			return nil, ErrNoTasks
+		}
 	}
 
 	domainEntry, err := c.domainCache.GetDomainByID(c.taskListID.domainID)
@@ -373,8 +495,11 @@ func (c *taskListManagerImpl) getTask(ctx context.Context, maxDispatchPerSecond
 	// poller, which lives inside the client side worker. There is
 	// one rateLimiter for this entire task list and as we get polls,
 	// we update the ratelimiter rps if it has changed from the last
-	// value. Last poller wins if different pollers provide different values
-	c.matcher.UpdateRatelimit(maxDispatchPerSecond)
+	// value. Last poller wins if different pollers provide different values.
+	// An operator override of the rate in dynamic config takes precedence over pollers
+	if !c.applyTaskDispatchRPSOverride() {
+		c.matcher.UpdateRatelimit(maxDispatchPerSecond)
+	}
 
 	if _, err := domainEntry.IsActiveIn(c.engine.clusterMetadata.GetCurrentClusterName()); err != nil {
 		return c.matcher.PollForQuery(childCtx)
@@ -383,6 +508,82 @@ func (c *taskListManagerImpl) getTask(ctx context.Context, maxDispatchPerSecond
 	return c.matcher.Poll(childCtx)
 }
 
+// SetPaused pauses or resumes the dispatch of the tasks of the task list. While paused, pollers receive empty
+// responses and the tasks are kept in the backlog. The state is persisted so that it survives the task list being reloaded.
+func (c *taskListManagerImpl) SetPaused(paused bool) error {
+	c.startWG.Wait()
+	if c.ephemeral {
+		return errEphemeralTaskListPaused
+	}
+	c.pauseLock.Lock()
+	defer c.pauseLock.Unlock()
+	if err := c.db.UpdatePaused(paused); err != nil {
+		return c.handleErr(err)
+	}
+	c.setPausedLocked(paused)
+	return nil
+}
+
+// FinishActivity releases the slot held by an activity dispatched from the task list, if any
+func (c *taskListManagerImpl) FinishActivity(key runningActivityKey) {
+	c.matcher.FinishActivity(key)
+}
+
+func (c *taskListManagerImpl) setPaused(paused bool) {
+	c.pauseLock.Lock()
+	defer c.pauseLock.Unlock()
+	c.setPausedLocked(paused)
+}
+
+func (c *taskListManagerImpl) setPausedLocked(paused bool) {
+	switch {
+	case paused && c.resumedCh == nil:
+		c.resumedCh = make(chan struct{})
+	case !paused && c.resumedCh != nil:
+		close(c.resumedCh)
+		c.resumedCh = nil
+	}
+}
+
+// getResumedCh returns the channel closed when the task list is resumed, or nil if the task list is not paused
+func (c *taskListManagerImpl) getResumedCh() chan struct{} {
+	c.pauseLock.Lock()
+	defer c.pauseLock.Unlock()
+	return c.resumedCh
+}
+
+// ReleaseStandbyTasks signals the task reader to load the backlog kept while the domain was standby,
+// it is called when the domain fails over to the current cluster
+func (c *taskListManagerImpl) ReleaseStandbyTasks() {
+	c.taskReader.Signal()
+}
+
+// isBufferingStandbyTasks returns whether the tasks of the task list are kept in the backlog
+// because standby task buffering is enabled and the domain is standby in the current cluster
+func (c *taskListManagerImpl) isBufferingStandbyTasks() bool {
+	domainEntry, err := c.domainCache.GetDomainByID(c.taskListID.domainID)
+	if err != nil {
+		return false
+	}
+	if !c.engine.config.EnableStandbyTaskBuffering(domainEntry.GetInfo().Name) {
+		return false
+	}
+	_, err = domainEntry.IsActiveIn(c.engine.clusterMetadata.GetCurrentClusterName())
+	return err != nil
+}
+
+// applyTaskDispatchRPSOverride updates the dispatch rate if it is overridden in dynamic config
+// and returns whether it is. Without an override the rate provided by the next poller is used.
+func (c *taskListManagerImpl) applyTaskDispatchRPSOverride() bool {
+	rps := c.config.TaskDispatchRPS()
+	if rps <= 0 {
+		return false
+	}
+	overrideRPS := float64(rps)
+	c.matcher.UpdateRatelimit(&overrideRPS)
+	return true
+}
+
 // GetAllPollerInfo returns all pollers that polled from this tasklist in last few minutes
 func (c *taskListManagerImpl) GetAllPollerInfo() []*types.PollerInfo {
 	return c.pollerHistory.getPollerInfo(time.Time{})
@@ -414,7 +615,7 @@ func (c *taskListManagerImpl) CancelPoller(pollerID string) {
 
 // DescribeTaskList returns information about the target tasklist, right now this API returns the
 // pollers which polled this tasklist in last few minutes and status of tasklist's ackManager
-// (readLevel, ackLevel, backlogCountHint and taskIDBlock).
+// (readLevel, ackLevel, backlogCountHint and taskIDBlock) along with the backlog statistics.
 func (c *taskListManagerImpl) DescribeTaskList(includeTaskListStatus bool) *types.DescribeTaskListResponse {
 	response := &types.DescribeTaskListResponse{Pollers: c.GetAllPollerInfo()}
 	if !includeTaskListStatus {
@@ -422,20 +623,41 @@ func (c *taskListManagerImpl) DescribeTaskList(includeTaskListStatus bool) *type
 	}
 
 	taskIDBlock := rangeIDToTaskIDBlock(c.db.RangeID(), c.config.RangeSize)
+	backlogCount := c.taskAckManager.GetBacklogCount()
+	addedPerSecond := c.stats.addedPerSecond()
+	dispatchedPerSecond := c.stats.dispatchedPerSecond()
 	response.TaskListStatus = &types.TaskListStatus{
 		ReadLevel:        c.taskAckManager.GetReadLevel(),
 		AckLevel:         c.taskAckManager.GetAckLevel(),
-		BacklogCountHint: c.taskAckManager.GetBacklogCount(),
+		BacklogCountHint: backlogCount,
 		RatePerSecond:    c.matcher.Rate(),
 		TaskIDBlock: &types.TaskIDBlock{
 			StartID: taskIDBlock.start,
 			EndID:   taskIDBlock.end,
 		},
+		BacklogAgeSeconds:                c.stats.backlogAge().Seconds(),
+		TasksAddedPerSecond:              addedPerSecond,
+		TasksDispatchedPerSecond:         dispatchedPerSecond,
+		EstimatedDrainTimeSeconds:        estimateDrainTime(backlogCount, addedPerSecond, dispatchedPerSecond),
+		Draining:                         c.config.DrainMode(),
+		Drained:                          c.isDrained(),
+		Paused:                           c.getResumedCh() != nil,
+		LocalSyncMatchLatencySeconds:     c.matcher.matchLatency(localSyncMatch).Seconds(),
+		ForwardedSyncMatchLatencySeconds: c.matcher.matchLatency(forwardedSyncMatch).Seconds(),
+		BacklogDispatchLatencySeconds:    c.matcher.matchLatency(backlogDispatch).Seconds(),
 	}
 
 	return response
 }
 
+// isDrained returns whether the task list is in drain mode and all the tasks written to it were acked,
+// that is all of them were read and none is outstanding as the ack level only moves with acked tasks
+func (c *taskListManagerImpl) isDrained() bool {
+	return c.config.DrainMode() &&
+		c.taskAckManager.GetBacklogCount() == 0 &&
+		c.taskAckManager.GetReadLevel() >= c.taskWriter.GetMaxReadLevel()
+}
+
 func (c *taskListManagerImpl) String() string {
 	buf := new(bytes.Buffer)
 	if c.taskListID.taskType == persistence.TaskListTypeActivity {
@@ -449,6 +671,9 @@ func (c *taskListManagerImpl) String() string {
 	fmt.Fprintf(buf, "TaskIDBlock=%+v\n", rangeIDToTaskIDBlock(rangeID, c.config.RangeSize))
 	fmt.Fprintf(buf, "AckLevel=%v\n", c.taskAckManager.GetAckLevel())
 	fmt.Fprintf(buf, "MaxReadLevel=%v\n", c.taskAckManager.GetReadLevel())
+	for _, level := range c.taskAckManager.GetPriorityLevels() {
+		fmt.Fprintf(buf, "Priority=%v AckLevel=%v MaxReadLevel=%v Backlog=%v\n", level.Priority, level.AckLevel, level.ReadLevel, level.BacklogCount)
+	}
 
 	return buf.String()
 }
@@ -457,6 +682,10 @@ func (c *taskListManagerImpl) GetTaskListKind() types.TaskListKind {
 	return c.taskListKind
 }
 
+func (c *taskListManagerImpl) FlushWrites(ctx context.Context) error {
+	return c.taskWriter.flush(ctx)
+}
+
 func (c *taskListManagerImpl) TaskListID() *taskListID {
 	return c.taskListID
 }
@@ -492,7 +721,8 @@ func (c *taskListManagerImpl) completeTask(task *persistence.TaskInfo, err error
 		}
 		c.taskReader.Signal()
 	}
-	ackLevel := c.taskAckManager.AckItem(task.TaskID)
+	c.stats.recordAcked(task.TaskID)
+	ackLevel := c.taskAckManager.AckTask(task)
 	c.taskGC.Run(ackLevel)
 }
 
@@ -514,13 +744,35 @@ func (c *taskListManagerImpl) executeWithRetry(
 	return
 }
 
+// addEphemeralTask offers the task of an ephemeral task list to the pollers for a short
+// time, the task is not retried as it cannot be persisted when no poller picks it up
+func (c *taskListManagerImpl) addEphemeralTask(ctx context.Context, params addTaskParams) (bool, error) {
+	domainEntry, err := c.domainCache.GetDomainByID(params.taskInfo.DomainID)
+	if err != nil {
+		return false, err
+	}
+	if _, err := domainEntry.IsActiveIn(c.engine.clusterMetadata.GetCurrentClusterName()); err != nil {
+		// standby tasks are only dispatched once the domain becomes active, they cannot wait in memory
+		return false, errEphemeralTaskNotMatched
+	}
+
+	matched, err := c.trySyncMatch(ctx, params)
+	if matched || err != nil {
+		return matched, err
+	}
+	return false, errEphemeralTaskNotMatched
+}
+
 func (c *taskListManagerImpl) trySyncMatch(ctx context.Context, params addTaskParams) (bool, error) {
 	task := newInternalTask(params.taskInfo, c.completeTask, params.source, params.forwardedFrom, true, params.activityTaskDispatchInfo)
+	task.isolationGroup = params.isolationGroup
 	childCtx := ctx
 	cancel := func() {}
 	waitTime := maxSyncMatchWaitTime
 	if params.activityTaskDispatchInfo != nil {
 		waitTime = c.engine.config.ActivityTaskSyncMatchWaitTime(params.activityTaskDispatchInfo.WorkflowDomain)
+	} else if c.ephemeral {
+		waitTime = c.config.EphemeralTaskSyncMatchWaitTime()
 	}
 	if !task.isForwarded() {
 		// when task is forwarded from another matching host, we trust the context as is
@@ -531,6 +783,11 @@ func (c *taskListManagerImpl) trySyncMatch(ctx context.Context, params addTaskPa
 	var err error
 	if params.activityTaskDispatchInfo != nil {
 		matched, err = c.matcher.offerOrTimeout(childCtx, task)
+	} else if c.ephemeral && !task.isForwarded() {
+		// ephemeral tasks cannot fall back to the backlog, they wait for a local poller instead
+		if matched, err = c.matcher.Offer(childCtx, task); !matched && err == nil {
+			matched, err = c.matcher.offerOrTimeout(childCtx, task)
+		}
 	} else {
 		matched, err = c.matcher.Offer(childCtx, task)
 	}
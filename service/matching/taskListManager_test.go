@@ -22,6 +22,8 @@ package matching
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -31,12 +33,17 @@ import (
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/yarpctest"
 
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/loggerimpl"
 	"github.com/uber/cadence/common/log/tag"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/types"
 )
@@ -46,12 +53,12 @@ func TestDeliverBufferTasks(t *testing.T) {
 	defer controller.Finish()
 
 	tests := []func(tlm *taskListManagerImpl){
-		func(tlm *taskListManagerImpl) { close(tlm.taskReader.taskBuffer) },
+		func(tlm *taskListManagerImpl) { tlm.taskReader.taskBuffer.close() },
 		func(tlm *taskListManagerImpl) { close(tlm.taskReader.dispatcherShutdownC) },
 		func(tlm *taskListManagerImpl) {
 			rps := 0.1
 			tlm.matcher.UpdateRatelimit(&rps)
-			tlm.taskReader.taskBuffer <- &persistence.TaskInfo{}
+			tlm.taskReader.taskBuffer.put(&persistence.TaskInfo{}, tlm.shutdownCh)
 			_, err := tlm.matcher.ratelimit(context.Background()) // consume the token
 			assert.NoError(t, err)
 			tlm.taskReader.cancelFunc()
@@ -76,7 +83,7 @@ func TestDeliverBufferTasks_NoPollers(t *testing.T) {
 	defer controller.Finish()
 
 	tlm := createTestTaskListManager(controller)
-	tlm.taskReader.taskBuffer <- &persistence.TaskInfo{}
+	tlm.taskReader.taskBuffer.put(&persistence.TaskInfo{}, tlm.shutdownCh)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -135,11 +142,260 @@ func TestReadLevelForAllExpiredTasksInBatch(t *testing.T) {
 	require.Equal(t, int64(14), tlm.taskAckManager.GetReadLevel())
 }
 
+func TestExpiredTasksWrittenToDeadLetter(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.EnableExpiredTaskDeadLetter = func(domain string) bool { return domain == "domainName" }
+	cfg.ExpiredTaskLogSampleRate = func(string) float64 { return 1 }
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	queue := persistence.NewMockQueueManager(controller)
+	tlm.engine.expiredTaskQueue = queue
+
+	var records []*expiredTaskRecord
+	queue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, payload []byte) error {
+		record := &expiredTaskRecord{}
+		require.NoError(t, json.Unmarshal(payload, record))
+		records = append(records, record)
+		return nil
+	}).Times(1)
+	queue.EXPECT().EnqueueMessageToDLQ(gomock.Any(), gomock.Any()).Return(errors.New("some random error")).Times(1)
+
+	require.True(t, tlm.taskReader.addTasksToBuffer([]*persistence.TaskInfo{
+		{
+			DomainID:    "domain",
+			WorkflowID:  "wid",
+			TaskID:      11,
+			Expiry:      time.Now().Add(-time.Minute),
+			CreatedTime: time.Now().Add(-time.Hour),
+		},
+		{
+			DomainID:    "domain",
+			WorkflowID:  "wid",
+			TaskID:      12,
+			Expiry:      time.Now().Add(time.Hour),
+			CreatedTime: time.Now(),
+		},
+		{
+			DomainID:    "domain",
+			WorkflowID:  "wid",
+			TaskID:      13,
+			Expiry:      time.Now().Add(-time.Minute),
+			CreatedTime: time.Now().Add(-time.Hour),
+		},
+	}))
+	// a failure to write to the dead letter queue does not block the task reader
+	require.Equal(t, int64(13), tlm.taskAckManager.GetReadLevel())
+	require.Len(t, records, 1)
+	require.Equal(t, "domainName", records[0].DomainName)
+	require.Equal(t, "tl", records[0].TaskListName)
+	require.Equal(t, persistence.TaskListTypeActivity, records[0].TaskListType)
+	require.Equal(t, int64(11), records[0].Task.TaskID)
+	require.Equal(t, "wid", records[0].Task.WorkflowID)
+
+	// expired tasks are dropped when the dead letter queue is disabled
+	cfg.EnableExpiredTaskDeadLetter = func(string) bool { return false }
+	tlm = createTestTaskListManagerWithConfig(controller, cfg)
+	tlm.engine.expiredTaskQueue = queue
+	require.True(t, tlm.taskReader.addTasksToBuffer([]*persistence.TaskInfo{
+		{
+			TaskID:      21,
+			Expiry:      time.Now().Add(-time.Minute),
+			CreatedTime: time.Now().Add(-time.Hour),
+		},
+	}))
+	require.Equal(t, int64(21), tlm.taskAckManager.GetReadLevel())
+}
+
+func TestAddTasksToBufferTracksPriority(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tlm := createTestTaskListManager(controller)
+	require.True(t, tlm.taskReader.addTasksToBuffer([]*persistence.TaskInfo{
+		{
+			DomainID:    "domain",
+			TaskID:      11,
+			CreatedTime: time.Now(),
+		},
+		{
+			DomainID:    "domain",
+			TaskID:      12,
+			CreatedTime: time.Now(),
+			Priority:    5,
+		},
+	}))
+	assert.Equal(t, []priorityLevel{
+		{Priority: 5, ReadLevel: 12, AckLevel: 11, BacklogCount: 1},
+		{Priority: 0, ReadLevel: 11, AckLevel: 10, BacklogCount: 1},
+	}, tlm.taskAckManager.GetPriorityLevels())
+
+	// the task read with the higher priority is dispatched first
+	task, ok := tlm.taskReader.taskBuffer.get(tlm.shutdownCh)
+	require.True(t, ok)
+	assert.Equal(t, int64(12), task.TaskID)
+	assert.Equal(t, int64(10), tlm.taskAckManager.AckTask(task))
+	assert.Equal(t, []priorityLevel{
+		{Priority: 0, ReadLevel: 11, AckLevel: 10, BacklogCount: 1},
+	}, tlm.taskAckManager.GetPriorityLevels())
+}
+
+func TestAdaptiveGetTasksBatchSize(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.EnableAdaptiveTaskRead = func(string, string, int) bool { return true }
+	cfg.GetTasksBatchSize = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(10)
+	cfg.MaxGetTasksBatchSize = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(35)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	tr := tlm.taskReader
+
+	require.Equal(t, 10, tr.getTasksBatchSize(1000))
+	require.Equal(t, 10, tr.getTasksBatchSize(1000), "batch size should not grow without waiting pollers")
+
+	tlm.matcher.updateWaitingPollers(1)
+	require.Equal(t, 20, tr.getTasksBatchSize(1000))
+	require.Equal(t, 35, tr.getTasksBatchSize(1000))
+	require.Equal(t, 35, tr.getTasksBatchSize(1000))
+
+	// shallow backlog, the batch size goes back to the configured one
+	require.Equal(t, 17, tr.getTasksBatchSize(5))
+	require.Equal(t, 10, tr.getTasksBatchSize(5))
+
+	// the configured batch size is used as is when the adaptive task read is disabled
+	cfg.EnableAdaptiveTaskRead = func(string, string, int) bool { return false }
+	tlm = createTestTaskListManagerWithConfig(controller, cfg)
+	tlm.matcher.updateWaitingPollers(1)
+	require.Equal(t, 10, tlm.taskReader.getTasksBatchSize(1000))
+	require.Equal(t, 10, tlm.taskReader.getTasksBatchSize(1000))
+}
+
+func TestWaitEmptyReadBackoff(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.EnableAdaptiveTaskRead = func(string, string, int) bool { return true }
+	cfg.MaxEmptyTaskReadBackoff = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(50 * time.Millisecond)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	tr := tlm.taskReader
+
+	start := time.Now()
+	require.True(t, tr.waitEmptyReadBackoff())
+	require.Less(t, time.Since(start), 50*time.Millisecond, "no backoff without empty reads")
+
+	tr.emptyReads = 3
+	tr.emptyReadMaxReadLevel = tlm.taskWriter.GetMaxReadLevel()
+	start = time.Now()
+	require.True(t, tr.waitEmptyReadBackoff())
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond, "backoff is capped to the max backoff")
+
+	cfg.MaxEmptyTaskReadBackoff = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(time.Minute)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(tlm.shutdownCh)
+	}()
+	require.False(t, tr.waitEmptyReadBackoff())
+}
+
+func TestGetTaskBatchCountsEmptyReads(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.EnableAdaptiveTaskRead = func(string, string, int) bool { return true }
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	tr := tlm.taskReader
+
+	// a read which goes up to the max read level without finding any task is an empty read
+	tlm.taskWriter.maxReadLevel = 5
+	tasks, readLevel, isReadBatchDone, err := tr.getTaskBatch()
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+	require.Equal(t, int64(5), readLevel)
+	require.True(t, isReadBatchDone)
+	require.Equal(t, 1, tr.emptyReads)
+
+	// a read which finds tasks resets the empty reads
+	_, err = tlm.db.CreateTasks([]*persistence.CreateTaskInfo{
+		{TaskID: 7, Data: &persistence.TaskInfo{TaskID: 7, CreatedTime: time.Now()}},
+	})
+	require.NoError(t, err)
+	tlm.taskAckManager.SetReadLevel(readLevel)
+	tlm.taskWriter.maxReadLevel = 10
+	tasks, _, _, err = tr.getTaskBatch()
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, 0, tr.emptyReads)
+}
+
+func TestNewTasksResetEmptyReadBackoff(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.EnableAdaptiveTaskRead = func(string, string, int) bool { return true }
+	cfg.MaxEmptyTaskReadBackoff = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(time.Minute)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	tr := tlm.taskReader
+
+	// several reads up to the max read level without finding any task
+	for maxReadLevel := int64(5); maxReadLevel <= 15; maxReadLevel += 5 {
+		tlm.taskWriter.maxReadLevel = maxReadLevel
+		_, readLevel, _, err := tr.getTaskBatch()
+		require.NoError(t, err)
+		tlm.taskAckManager.SetReadLevel(readLevel)
+	}
+	require.Equal(t, 3, tr.emptyReads)
+
+	// a task written afterwards is read without waiting out the backoff
+	_, err := tlm.db.CreateTasks([]*persistence.CreateTaskInfo{
+		{TaskID: 17, Data: &persistence.TaskInfo{TaskID: 17, CreatedTime: time.Now()}},
+	})
+	require.NoError(t, err)
+	tlm.taskWriter.maxReadLevel = 20
+	start := time.Now()
+	require.True(t, tr.waitEmptyReadBackoff())
+	require.Less(t, time.Since(start), emptyTaskReadInitialBackoff)
+	require.Equal(t, 0, tr.emptyReads)
+	tasks, _, _, err := tr.getTaskBatch()
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+}
+
+func TestTaskBufferHoldsMaxBatch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.EnableAdaptiveTaskRead = func(string, string, int) bool { return true }
+	cfg.GetTasksBatchSize = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(10)
+	cfg.MaxGetTasksBatchSize = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(40)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	tr := tlm.taskReader
+	require.Equal(t, 39, tr.taskBuffer.cap())
+
+	// the max batch size is raised past the buffer capacity after the task list is loaded,
+	// the batch size is still bounded by what the buffer holds
+	cfg.MaxGetTasksBatchSize = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(100)
+	tlm.matcher.updateWaitingPollers(1)
+	require.Equal(t, 10, tr.getTasksBatchSize(1000))
+	require.Equal(t, 20, tr.getTasksBatchSize(1000))
+	require.Equal(t, 40, tr.getTasksBatchSize(1000))
+	require.Equal(t, 40, tr.getTasksBatchSize(1000))
+}
+
 func createTestTaskListManager(controller *gomock.Controller) *taskListManagerImpl {
 	return createTestTaskListManagerWithConfig(controller, defaultTestConfig())
 }
 
 func createTestTaskListManagerWithConfig(controller *gomock.Controller, cfg *Config) *taskListManagerImpl {
+	return createTestTaskListManagerWithKind(controller, cfg, types.TaskListKindNormal)
+}
+
+func createTestTaskListManagerWithKind(controller *gomock.Controller, cfg *Config, tlKind types.TaskListKind) *taskListManagerImpl {
 	logger, err := loggerimpl.NewDevelopment()
 	if err != nil {
 		panic(err)
@@ -154,7 +410,6 @@ func createTestTaskListManagerWithConfig(controller *gomock.Controller, cfg *Con
 	tl := "tl"
 	dID := "domain"
 	tlID := newTestTaskListID(dID, tl, persistence.TaskListTypeActivity)
-	tlKind := types.TaskListKindNormal
 	tlMgr, err := newTaskListManager(me, tlID, &tlKind, cfg)
 	if err != nil {
 		logger.Fatal("error when createTestTaskListManager", tag.Error(err))
@@ -210,7 +465,7 @@ func TestDescribeTaskList(t *testing.T) {
 	require.Equal(t, tlm.config.RangeSize, taskIDBlock.GetEndID())
 
 	// Add a poller and complete all tasks
-	tlm.pollerHistory.updatePollerInfo(pollerIdentity(PollerIdentity), nil)
+	tlm.pollerHistory.updatePollerInfo(pollerIdentity(PollerIdentity), newPollerInfo(context.Background(), nil))
 	for i := int64(0); i < taskCount; i++ {
 		tlm.taskAckManager.AckItem(startTaskID + i)
 	}
@@ -222,7 +477,7 @@ func TestDescribeTaskList(t *testing.T) {
 	require.True(t, descResp.Pollers[0].GetRatePerSecond() > (_defaultTaskDispatchRPS-1))
 
 	rps := 5.0
-	tlm.pollerHistory.updatePollerInfo(pollerIdentity(PollerIdentity), &rps)
+	tlm.pollerHistory.updatePollerInfo(pollerIdentity(PollerIdentity), newPollerInfo(context.Background(), &rps))
 	descResp = tlm.DescribeTaskList(includeTaskStatus)
 	require.Equal(t, 1, len(descResp.GetPollers()))
 	require.Equal(t, PollerIdentity, descResp.Pollers[0].GetIdentity())
@@ -234,6 +489,226 @@ func TestDescribeTaskList(t *testing.T) {
 	require.Zero(t, taskListStatus.GetBacklogCountHint())
 }
 
+func TestDescribeTaskListPollerInfo(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tlm := createTestTaskListManager(controller)
+	tlMgrStartWithoutNotifyEvent(tlm)
+	defer tlm.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = yarpctest.ContextWithCall(ctx, &yarpctest.Call{Headers: map[string]string{
+		common.ClientImplHeaderName:         "uber-go",
+		common.FeatureVersionHeaderName:     "1.7.0",
+		common.ClientFeatureFlagsHeaderName: `{"WorkflowExecutionAlreadyCompletedErrorEnabled":true}`,
+	}})
+	ctx = context.WithValue(ctx, identityKey, "worker1")
+	ctx = context.WithValue(ctx, buildIDKey, "build1")
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tlm.GetTask(ctx, nil)
+			assert.Error(t, err)
+		}()
+	}
+
+	require.Eventually(t, func() bool {
+		pollers := tlm.DescribeTaskList(false).GetPollers()
+		return len(pollers) == 1 && pollers[0].GetConcurrentPollCount() == 2
+	}, time.Second, 10*time.Millisecond)
+	poller := tlm.DescribeTaskList(false).GetPollers()[0]
+	require.Equal(t, "worker1", poller.GetIdentity())
+	require.Equal(t, "build1", poller.GetBuildID())
+	require.Equal(t, "uber-go", poller.GetClientImpl())
+	require.Equal(t, "1.7.0", poller.GetClientFeatureVersion())
+	require.Equal(t, []string{workflowExecutionAlreadyCompletedErrorCapability}, poller.GetCapabilities())
+
+	// the poller is kept once its polls ended
+	cancel()
+	wg.Wait()
+	pollers := tlm.DescribeTaskList(false).GetPollers()
+	require.Len(t, pollers, 1)
+	require.Zero(t, pollers[0].GetConcurrentPollCount())
+}
+
+func TestDescribeTaskListBacklogStats(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Now()
+	timeSource := clock.NewEventTimeSource().Update(now)
+	tlm := createTestTaskListManager(controller)
+	tlm.stats = newTaskListStats(timeSource)
+	tlm.matcher.latencies = newMatchLatencies(timeSource, metrics.NoopScope(metrics.Matching))
+	tlm.taskAckManager.SetAckLevel(0)
+
+	for i := int64(1); i <= 4; i++ {
+		require.NoError(t, tlm.taskAckManager.ReadItem(i))
+		tlm.stats.recordRead(i, now.Add(-time.Duration(5-i)*time.Second))
+		tlm.stats.recordAdded()
+	}
+	taskListStatus := tlm.DescribeTaskList(true).GetTaskListStatus()
+	require.NotNil(t, taskListStatus)
+	require.Equal(t, 4.0, taskListStatus.GetBacklogAgeSeconds())
+	require.Equal(t, 4.0, taskListStatus.GetTasksAddedPerSecond())
+	require.Zero(t, taskListStatus.GetTasksDispatchedPerSecond())
+	require.Equal(t, float64(notDrainingBacklog), taskListStatus.GetEstimatedDrainTimeSeconds())
+	require.Zero(t, taskListStatus.GetLocalSyncMatchLatencySeconds())
+
+	tlm.matcher.latencies.record(localSyncMatch, 100*time.Millisecond)
+	tlm.matcher.latencies.record(backlogDispatch, 2*time.Second)
+	taskListStatus = tlm.DescribeTaskList(true).GetTaskListStatus()
+	require.Equal(t, 0.1, taskListStatus.GetLocalSyncMatchLatencySeconds())
+	require.Zero(t, taskListStatus.GetForwardedSyncMatchLatencySeconds())
+	require.Equal(t, 2.0, taskListStatus.GetBacklogDispatchLatencySeconds())
+
+	// complete the oldest tasks while dispatching faster than tasks are added
+	timeSource.Update(now.Add(time.Second))
+	for i := int64(1); i <= 2; i++ {
+		tlm.completeTask(&persistence.TaskInfo{TaskID: i}, nil)
+	}
+	for i := 0; i < 12; i++ {
+		tlm.stats.recordDispatched()
+	}
+	taskListStatus = tlm.DescribeTaskList(true).GetTaskListStatus()
+	require.Equal(t, int64(2), taskListStatus.GetBacklogCountHint())
+	require.Equal(t, 3.0, taskListStatus.GetBacklogAgeSeconds())
+	require.Equal(t, 2.0, taskListStatus.GetTasksAddedPerSecond())
+	require.Equal(t, 6.0, taskListStatus.GetTasksDispatchedPerSecond())
+	require.Equal(t, 0.5, taskListStatus.GetEstimatedDrainTimeSeconds())
+}
+
+func TestPersistAckLevelWritesBacklogSnapshot(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Now()
+	tlm := createTestTaskListManager(controller)
+	_, err := tlm.db.RenewLease()
+	require.NoError(t, err)
+	tlm.taskAckManager.SetAckLevel(0)
+	tlm.taskWriter.maxReadLevel = 5
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(t, tlm.taskAckManager.ReadItem(i))
+		tlm.stats.recordRead(i, now.Add(-time.Duration(4-i)*time.Second))
+	}
+	tlm.taskAckManager.AckItem(1)
+	tlm.stats.recordAcked(1)
+	require.NoError(t, tlm.taskReader.persistAckLevel())
+
+	tm := tlm.engine.taskManager.(*testTaskManager)
+	resp, err := tm.GetTaskList(context.Background(), &persistence.GetTaskListRequest{
+		DomainID: tlm.taskListID.domainID,
+		TaskList: tlm.taskListID.name,
+		TaskType: tlm.taskListID.taskType,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), resp.TaskListInfo.AckLevel)
+	// tasks 2 and 3 are loaded but not acked, tasks 4 and 5 are not read yet
+	require.Equal(t, int64(4), resp.TaskListInfo.ApproximateBacklogCount)
+	require.True(t, now.Add(-2*time.Second).Equal(resp.TaskListInfo.OldestTaskCreatedTime))
+
+	// the snapshot is kept when the lease is renewed
+	state, err := tlm.db.RenewLease()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), state.ackLevel)
+	require.Equal(t, int64(4), tlm.db.backlog.count)
+}
+
+func TestTaskGCDeletesTasksCompletedAfterStuckTask(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	defer func(stallTime time.Duration) { maxAckLevelStallTime = stallTime }(maxAckLevelStallTime)
+	cfg := defaultTestConfig()
+	cfg.MaxTaskDeleteBatchSize = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(2)
+	cfg.CompletedTaskDeleteBatchSize = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(2)
+	cfg.TaskDeleteFlushInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(time.Minute)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	tm := tlm.engine.taskManager.(*testTaskManager)
+
+	var tasks []*persistence.CreateTaskInfo
+	for taskID := int64(1); taskID <= 5; taskID++ {
+		tasks = append(tasks, &persistence.CreateTaskInfo{
+			Execution: types.WorkflowExecution{WorkflowID: "wid", RunID: "rid"},
+			Data:      &persistence.TaskInfo{ScheduleID: taskID},
+			TaskID:    taskID,
+		})
+	}
+	_, err := tm.CreateTasks(context.Background(), &persistence.CreateTasksRequest{
+		TaskListInfo: &persistence.TaskListInfo{DomainID: tlm.taskListID.domainID, Name: tlm.taskListID.name, TaskType: tlm.taskListID.taskType},
+		Tasks:        tasks,
+	})
+	require.NoError(t, err)
+	tlm.taskAckManager.SetAckLevel(0)
+	for taskID := int64(1); taskID <= 5; taskID++ {
+		require.NoError(t, tlm.taskAckManager.ReadTask(&persistence.TaskInfo{TaskID: taskID}))
+	}
+
+	// task 1 is stuck, the tasks completed after it are kept until the ack level stalls
+	tlm.completeTask(&persistence.TaskInfo{TaskID: 2}, nil)
+	tlm.completeTask(&persistence.TaskInfo{TaskID: 3}, nil)
+	require.Equal(t, 5, tm.getTaskCount(tlm.taskListID))
+
+	maxAckLevelStallTime = 0
+	tlm.completeTask(&persistence.TaskInfo{TaskID: 4}, nil)
+	tlm.completeTask(&persistence.TaskInfo{TaskID: 5}, nil)
+	require.Equal(t, 1, tm.getTaskCount(tlm.taskListID))
+	require.Zero(t, tlm.taskAckManager.GetAckLevel())
+
+	tlm.completeTask(&persistence.TaskInfo{TaskID: 1}, nil)
+	require.Equal(t, int64(5), tlm.taskAckManager.GetAckLevel())
+	require.Zero(t, tm.getTaskCount(tlm.taskListID))
+}
+
+func TestTaskGCFlushesCompletedTasksOnInterval(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	defer func(stallTime time.Duration) { maxAckLevelStallTime = stallTime }(maxAckLevelStallTime)
+	maxAckLevelStallTime = 0
+	cfg := defaultTestConfig()
+	cfg.CompletedTaskDeleteBatchSize = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(10)
+	cfg.TaskDeleteFlushInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(time.Minute)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	tm := tlm.engine.taskManager.(*testTaskManager)
+
+	var tasks []*persistence.CreateTaskInfo
+	for taskID := int64(1); taskID <= 4; taskID++ {
+		tasks = append(tasks, &persistence.CreateTaskInfo{
+			Execution: types.WorkflowExecution{WorkflowID: "wid", RunID: "rid"},
+			Data:      &persistence.TaskInfo{ScheduleID: taskID},
+			TaskID:    taskID,
+		})
+	}
+	_, err := tm.CreateTasks(context.Background(), &persistence.CreateTasksRequest{
+		TaskListInfo: &persistence.TaskListInfo{DomainID: tlm.taskListID.domainID, Name: tlm.taskListID.name, TaskType: tlm.taskListID.taskType},
+		Tasks:        tasks,
+	})
+	require.NoError(t, err)
+	tlm.taskAckManager.SetAckLevel(0)
+	for taskID := int64(1); taskID <= 4; taskID++ {
+		require.NoError(t, tlm.taskAckManager.ReadTask(&persistence.TaskInfo{TaskID: taskID}))
+	}
+
+	// the completed tasks are buffered until the batch is full or the flush interval passed
+	tlm.completeTask(&persistence.TaskInfo{TaskID: 2}, nil)
+	require.Equal(t, 4, tm.getTaskCount(tlm.taskListID))
+	cfg.TaskDeleteFlushInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(0)
+	tlm.completeTask(&persistence.TaskInfo{TaskID: 3}, nil)
+	require.Equal(t, 2, tm.getTaskCount(tlm.taskListID))
+
+	// the buffer is flushed right away when the task list is unloaded
+	cfg.TaskDeleteFlushInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(time.Minute)
+	tlm.completeTask(&persistence.TaskInfo{TaskID: 4}, nil)
+	require.Equal(t, 2, tm.getTaskCount(tlm.taskListID))
+	tlm.taskGC.RunNow(tlm.taskAckManager.GetAckLevel())
+	require.Equal(t, 1, tm.getTaskCount(tlm.taskListID))
+}
+
 func tlMgrStartWithoutNotifyEvent(tlm *taskListManagerImpl) {
 	// mimic tlm.Start() but avoid calling notifyEvent
 	tlm.liveness.Start()
@@ -354,3 +829,314 @@ func TestAddTaskStandby(t *testing.T) {
 	require.Error(t, err) // should not persist the task
 	require.False(t, syncMatch)
 }
+
+func TestGetTaskReturnsEmptyOnIdleUnload(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.IdleTasklistCheckInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(200 * time.Millisecond)
+	cfg.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(time.Minute)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	require.NoError(t, tlm.Start())
+	defer tlm.Stop()
+
+	// the poll is not held for the whole long poll interval as the task list is unloaded in the meantime
+	start := time.Now()
+	_, err := tlm.GetTask(context.Background(), nil)
+	require.Equal(t, ErrNoTasks, err)
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+	require.Equal(t, common.DaemonStatusStopped, atomic.LoadInt32(&tlm.liveness.status))
+}
+
+func TestPauseTaskList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(100 * time.Millisecond)
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	require.NoError(t, tlm.Start())
+	require.NoError(t, tlm.SetPaused(true))
+	require.True(t, tlm.DescribeTaskList(true).GetTaskListStatus().GetPaused())
+
+	syncMatch, err := tlm.AddTask(context.Background(), addTaskParams{
+		execution: &types.WorkflowExecution{WorkflowID: "wid", RunID: "rid"},
+		taskInfo: &persistence.TaskInfo{
+			DomainID:               "domainId",
+			WorkflowID:             "wid",
+			RunID:                  "rid",
+			ScheduleID:             2,
+			ScheduleToStartTimeout: 5,
+			CreatedTime:            time.Now(),
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, syncMatch)
+	// the task is kept in the backlog while the task list is paused
+	_, err = tlm.GetTask(context.Background(), nil)
+	require.Equal(t, ErrNoTasks, err)
+	tlm.Stop()
+
+	// the task list is still paused once reloaded
+	tlKind := types.TaskListKindNormal
+	reloaded, err := newTaskListManager(tlm.engine, tlm.taskListID, &tlKind, cfg)
+	require.NoError(t, err)
+	tlm = reloaded.(*taskListManagerImpl)
+	require.NoError(t, tlm.Start())
+	defer tlm.Stop()
+	require.True(t, tlm.DescribeTaskList(true).GetTaskListStatus().GetPaused())
+	_, err = tlm.GetTask(context.Background(), nil)
+	require.Equal(t, ErrNoTasks, err)
+
+	require.NoError(t, tlm.SetPaused(false))
+	require.False(t, tlm.DescribeTaskList(true).GetTaskListStatus().GetPaused())
+	task, err := tlm.GetTask(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), task.event.ScheduleID)
+	task.finish(nil)
+}
+
+func TestPauseEphemeralTaskList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.EnableEphemeralTaskList = func(string, string, int) bool { return true }
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	require.NoError(t, tlm.Start())
+	defer tlm.Stop()
+	require.Equal(t, errEphemeralTaskListPaused, tlm.SetPaused(true))
+}
+
+func TestAddTaskEphemeral(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.EphemeralTaskSyncMatchWaitTime = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(10 * time.Millisecond)
+	cfg.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskListInfo(time.Second)
+	cfg.EnableEphemeralTaskList = func(string, string, int) bool { return true }
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	require.NoError(t, tlm.Start())
+	defer tlm.Stop()
+	tm := tlm.engine.taskManager.(*testTaskManager)
+
+	addTaskParam := addTaskParams{
+		execution: &types.WorkflowExecution{WorkflowID: "wid", RunID: "rid"},
+		taskInfo: &persistence.TaskInfo{
+			DomainID:               "domainId",
+			WorkflowID:             "wid",
+			RunID:                  "rid",
+			ScheduleID:             2,
+			ScheduleToStartTimeout: 5,
+			CreatedTime:            time.Now(),
+		},
+	}
+
+	// without pollers the task fails fast instead of being persisted
+	syncMatch, err := tlm.AddTask(context.Background(), addTaskParam)
+	require.Equal(t, errEphemeralTaskNotMatched, err)
+	require.False(t, syncMatch)
+
+	pollDone := make(chan error, 1)
+	go func() {
+		task, err := tlm.GetTask(context.Background(), nil)
+		if err == nil {
+			task.finish(nil)
+		}
+		pollDone <- err
+	}()
+	// retry until the poller waits for tasks
+	require.Eventually(t, func() bool {
+		syncMatch, err = tlm.AddTask(context.Background(), addTaskParam)
+		return syncMatch
+	}, time.Second, time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, <-pollDone)
+
+	// the task list is neither leased nor written to
+	require.Zero(t, tm.getTaskListManager(tlm.taskListID).rangeID)
+	require.Zero(t, tm.getCreateTaskCount(tlm.taskListID))
+}
+
+func TestStandbyTaskBuffering(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	newDomainEntry := func(activeClusterName string) *cache.DomainCacheEntry {
+		return cache.NewGlobalDomainCacheEntryForTest(
+			&persistence.DomainInfo{ID: "domain", Name: "domainName"},
+			&persistence.DomainConfig{Retention: 1},
+			&persistence.DomainReplicationConfig{
+				ActiveClusterName: activeClusterName,
+				Clusters: []*persistence.ClusterReplicationConfig{
+					{ClusterName: cluster.TestCurrentClusterName},
+					{ClusterName: cluster.TestAlternativeClusterName},
+				},
+			},
+			1234,
+		)
+	}
+	var domainLock sync.Mutex
+	domainEntry := newDomainEntry(cluster.TestAlternativeClusterName)
+	mockDomainCache := cache.NewMockDomainCache(controller)
+	mockDomainCache.EXPECT().GetDomainByID("domain").DoAndReturn(func(string) (*cache.DomainCacheEntry, error) {
+		domainLock.Lock()
+		defer domainLock.Unlock()
+		return domainEntry, nil
+	}).AnyTimes()
+	mockDomainCache.EXPECT().GetDomainName("domain").Return("domainName", nil).AnyTimes()
+
+	enabled := true
+	cfg := defaultTestConfig()
+	cfg.EnableStandbyTaskBuffering = func(string) bool { return enabled }
+	me := newMatchingEngine(cfg, newTestTaskManager(log.NewNoop()), nil, log.NewNoop(), mockDomainCache)
+	tlID := newTestTaskListID("domain", "tl", persistence.TaskListTypeActivity)
+	tlKind := types.TaskListKindNormal
+	mgr, err := newTaskListManager(me, tlID, &tlKind, cfg)
+	require.NoError(t, err)
+	tlm := mgr.(*taskListManagerImpl)
+	me.taskLists[*tlID] = tlm
+
+	require.True(t, tlm.isBufferingStandbyTasks())
+	enabled = false
+	require.False(t, tlm.isBufferingStandbyTasks())
+	enabled = true
+
+	require.NoError(t, tlm.Start())
+	defer tlm.Stop()
+	syncMatch, err := tlm.AddTask(context.Background(), addTaskParams{
+		execution: &types.WorkflowExecution{WorkflowID: "wid", RunID: "rid"},
+		taskInfo:  &persistence.TaskInfo{DomainID: "domain", WorkflowID: "wid", RunID: "rid", ScheduleID: 2, ScheduleToStartTimeout: 100},
+	})
+	require.NoError(t, err)
+	require.False(t, syncMatch)
+
+	// the standby task is kept in the backlog
+	time.Sleep(50 * time.Millisecond)
+	require.Less(t, tlm.taskAckManager.GetReadLevel(), tlm.taskWriter.GetMaxReadLevel())
+
+	// the backlog is loaded once the domain fails over to the current cluster
+	domainLock.Lock()
+	domainEntry = newDomainEntry(cluster.TestCurrentClusterName)
+	domainLock.Unlock()
+	require.False(t, tlm.isBufferingStandbyTasks())
+	me.domainChangeCallback([]*cache.DomainCacheEntry{domainEntry})
+	require.Eventually(t, func() bool {
+		return tlm.taskAckManager.GetReadLevel() == tlm.taskWriter.GetMaxReadLevel()
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestDomainChangeReleasesStandbyTasksOnFailover(t *testing.T) {
+	me := newMatchingEngine(defaultTestConfig(), newTestTaskManager(log.NewNoop()), nil, log.NewNoop(), nil)
+	newDomainEntry := func(id string, activeClusterName string) *cache.DomainCacheEntry {
+		return cache.NewGlobalDomainCacheEntryForTest(
+			&persistence.DomainInfo{ID: id, Name: id},
+			&persistence.DomainConfig{Retention: 1},
+			&persistence.DomainReplicationConfig{
+				ActiveClusterName: activeClusterName,
+				Clusters: []*persistence.ClusterReplicationConfig{
+					{ClusterName: cluster.TestCurrentClusterName},
+					{ClusterName: cluster.TestAlternativeClusterName},
+				},
+			},
+			1234,
+		)
+	}
+
+	failoverDomainIDs := me.updateDomainActiveClusters([]*cache.DomainCacheEntry{
+		newDomainEntry("active", cluster.TestCurrentClusterName),
+		newDomainEntry("standby", cluster.TestAlternativeClusterName),
+	})
+	require.Equal(t, map[string]struct{}{"active": {}}, failoverDomainIDs)
+
+	// the domains which are still active or standby are not failing over
+	failoverDomainIDs = me.updateDomainActiveClusters([]*cache.DomainCacheEntry{
+		newDomainEntry("active", cluster.TestCurrentClusterName),
+		newDomainEntry("standby", cluster.TestAlternativeClusterName),
+	})
+	require.Empty(t, failoverDomainIDs)
+
+	failoverDomainIDs = me.updateDomainActiveClusters([]*cache.DomainCacheEntry{
+		newDomainEntry("active", cluster.TestAlternativeClusterName),
+		newDomainEntry("standby", cluster.TestCurrentClusterName),
+	})
+	require.Equal(t, map[string]struct{}{"standby": {}}, failoverDomainIDs)
+}
+
+func TestAddTaskInDrainMode(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	draining := true
+	cfg := defaultTestConfig()
+	cfg.TaskListDrainMode = func(string, string, int) bool { return draining }
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	// the pumps are not started as the tasks are rejected before reaching them
+	tlm.startWG.Done()
+
+	syncMatch, err := tlm.AddTask(context.Background(), addTaskParams{
+		execution: &types.WorkflowExecution{WorkflowID: "wid", RunID: "rid"},
+		taskInfo:  &persistence.TaskInfo{DomainID: "domainId", WorkflowID: "wid", RunID: "rid", ScheduleID: 2},
+	})
+	require.Equal(t, errTaskListDraining, err)
+	require.False(t, syncMatch)
+
+	// the task list is drained once the tasks written to it are acked
+	atomic.StoreInt64(&tlm.taskWriter.maxReadLevel, 5)
+	tlm.taskAckManager.SetAckLevel(4)
+	require.NoError(t, tlm.taskAckManager.ReadItem(5))
+	status := tlm.DescribeTaskList(true).GetTaskListStatus()
+	require.True(t, status.GetDraining())
+	require.False(t, status.GetDrained())
+
+	tlm.completeTask(&persistence.TaskInfo{TaskID: 5}, nil)
+	status = tlm.DescribeTaskList(true).GetTaskListStatus()
+	require.True(t, status.GetDraining())
+	require.True(t, status.GetDrained())
+
+	draining = false
+	status = tlm.DescribeTaskList(true).GetTaskListStatus()
+	require.False(t, status.GetDraining())
+	require.False(t, status.GetDrained())
+}
+
+func TestTaskDispatchRPSOverride(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := NewConfig(dynamicconfig.NewNopCollection())
+	cfg.TaskDispatchRPS = dynamicconfig.GetIntPropertyFilteredByTaskListInfo(5)
+
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	tlMgrStartWithoutNotifyEvent(tlm)
+	defer tlm.Stop()
+
+	pollerRPS := 100.0
+	ctx, cancel := context.WithTimeout(context.Background(), time.Microsecond)
+	_, _ = tlm.GetTask(ctx, &pollerRPS)
+	cancel()
+	require.Equal(t, 5.0, tlm.matcher.limiter.Limit())
+}
+
+func TestTaskDispatchRPSOverride_AppliedOnConfigChange(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	client := dynamicconfig.NewInMemoryClient()
+	cfg := NewConfig(dynamicconfig.NewCollection(client, log.NewNoop()))
+
+	tlm := createTestTaskListManagerWithConfig(controller, cfg)
+	tlMgrStartWithoutNotifyEvent(tlm)
+	defer tlm.Stop()
+
+	pollerRPS := 100.0
+	ctx, cancel := context.WithTimeout(context.Background(), time.Microsecond)
+	_, _ = tlm.GetTask(ctx, &pollerRPS)
+	cancel()
+	require.Equal(t, 100.0, tlm.matcher.limiter.Limit())
+
+	require.NoError(t, client.UpdateValue(dynamicconfig.MatchingTaskDispatchRPS, 5))
+	require.Equal(t, 5.0, tlm.matcher.limiter.Limit())
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/metrics"
+)
+
+const (
+	// taskListStatsWindow is the sliding window over which the add and dispatch rates of a task list are computed
+	taskListStatsWindow = time.Minute
+	// notDrainingBacklog is reported as the drain time of a backlog which is growing or not being dispatched
+	notDrainingBacklog = -1
+)
+
+const (
+	// localSyncMatch is a task added to the partition and matched with one of its pollers
+	localSyncMatch matchLatencyKind = iota
+	// forwardedSyncMatch is a task added to the partition and matched on a parent partition
+	forwardedSyncMatch
+	// backlogDispatch is a backlog task matched with a poller of the partition or of a parent partition
+	backlogDispatch
+	numMatchLatencyKinds
+)
+
+var matchLatencyMetrics = [numMatchLatencyKinds]int{
+	localSyncMatch:     metrics.LocalSyncMatchLatencyPerTaskList,
+	forwardedSyncMatch: metrics.ForwardedSyncMatchLatencyPerTaskList,
+	backlogDispatch:    metrics.BacklogDispatchLatencyPerTaskList,
+}
+
+type (
+	// taskListStats tracks the rates at which tasks are added to, sync matched on and dispatched from a task list
+	// and at which it is polled, along with the creation time of the backlog tasks which are loaded but not acked yet
+	taskListStats struct {
+		sync.Mutex
+		timeSource  clock.TimeSource
+		added       *rateCounter
+		syncMatched *rateCounter
+		dispatched  *rateCounter
+		polled      *rateCounter
+		unacked     map[int64]time.Time
+	}
+
+	matchLatencyKind int
+
+	// matchLatencies emits the time taken to match the tasks of a partition with a poller, split by
+	// where the match happened, and tracks its average over the sliding window of the task list stats
+	matchLatencies struct {
+		sync.Mutex
+		timeSource clock.TimeSource
+		scope      metrics.Scope
+		counts     [numMatchLatencyKinds]*rateCounter
+		totals     [numMatchLatencyKinds]*rateCounter // sum of the latencies in nanoseconds
+	}
+
+	// rateCounter counts events in per second buckets over a sliding window
+	rateCounter struct {
+		createdAt time.Time
+		counts    []int64
+		seconds   []int64
+	}
+)
+
+func newTaskListStats(timeSource clock.TimeSource) *taskListStats {
+	now := timeSource.Now()
+	return &taskListStats{
+		timeSource:  timeSource,
+		added:       newRateCounter(now, taskListStatsWindow),
+		syncMatched: newRateCounter(now, taskListStatsWindow),
+		dispatched:  newRateCounter(now, taskListStatsWindow),
+		polled:      newRateCounter(now, taskListStatsWindow),
+		unacked:     make(map[int64]time.Time),
+	}
+}
+
+func (s *taskListStats) recordAdded() {
+	s.Lock()
+	defer s.Unlock()
+	s.added.add(s.timeSource.Now(), 1)
+}
+
+func (s *taskListStats) recordSyncMatched() {
+	s.Lock()
+	defer s.Unlock()
+	s.syncMatched.add(s.timeSource.Now(), 1)
+}
+
+func (s *taskListStats) recordPolled() {
+	s.Lock()
+	defer s.Unlock()
+	s.polled.add(s.timeSource.Now(), 1)
+}
+
+func (s *taskListStats) recordDispatched() {
+	s.Lock()
+	defer s.Unlock()
+	s.dispatched.add(s.timeSource.Now(), 1)
+}
+
+// recordRead is called when a backlog task is loaded from the database
+func (s *taskListStats) recordRead(taskID int64, createdTime time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	s.unacked[taskID] = createdTime
+}
+
+// recordAcked is called when a backlog task is completed
+func (s *taskListStats) recordAcked(taskID int64) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.unacked, taskID)
+}
+
+// addedPerSecond returns the rate at which tasks were added over the sliding window
+func (s *taskListStats) addedPerSecond() float64 {
+	s.Lock()
+	defer s.Unlock()
+	return s.added.rate(s.timeSource.Now())
+}
+
+// syncMatchedPerSecond returns the rate at which added tasks were matched with a poller without being persisted
+func (s *taskListStats) syncMatchedPerSecond() float64 {
+	s.Lock()
+	defer s.Unlock()
+	return s.syncMatched.rate(s.timeSource.Now())
+}
+
+// polledPerSecond returns the rate at which pollers polled the task list over the sliding window
+func (s *taskListStats) polledPerSecond() float64 {
+	s.Lock()
+	defer s.Unlock()
+	return s.polled.rate(s.timeSource.Now())
+}
+
+// dispatchedPerSecond returns the rate at which tasks were dispatched over the sliding window
+func (s *taskListStats) dispatchedPerSecond() float64 {
+	s.Lock()
+	defer s.Unlock()
+	return s.dispatched.rate(s.timeSource.Now())
+}
+
+// backlogAge returns the age of the oldest backlog task which is loaded but not acked,
+// or zero if there is no such task
+func (s *taskListStats) backlogAge() time.Duration {
+	oldest := s.oldestBacklogTaskCreatedTime()
+	if oldest.IsZero() {
+		return 0
+	}
+	return s.timeSource.Now().Sub(oldest)
+}
+
+// oldestBacklogTaskCreatedTime returns the created time of the oldest backlog task which is
+// loaded but not acked, or zero time if there is no such task
+func (s *taskListStats) oldestBacklogTaskCreatedTime() time.Time {
+	s.Lock()
+	defer s.Unlock()
+	var oldest time.Time
+	for _, createdTime := range s.unacked {
+		if oldest.IsZero() || createdTime.Before(oldest) {
+			oldest = createdTime
+		}
+	}
+	return oldest
+}
+
+// estimateDrainTime returns the time it takes to dispatch the backlog at the current net rate of the
+// task list, or notDrainingBacklog if tasks are added at least as fast as they are dispatched
+func estimateDrainTime(backlog int64, addedPerSecond float64, dispatchedPerSecond float64) float64 {
+	if backlog <= 0 {
+		return 0
+	}
+	drainRate := dispatchedPerSecond - addedPerSecond
+	if drainRate <= 0 {
+		return notDrainingBacklog
+	}
+	return float64(backlog) / drainRate
+}
+
+func newMatchLatencies(timeSource clock.TimeSource, scope metrics.Scope) *matchLatencies {
+	now := timeSource.Now()
+	l := &matchLatencies{
+		timeSource: timeSource,
+		scope:      scope,
+	}
+	for kind := range l.counts {
+		l.counts[kind] = newRateCounter(now, taskListStatsWindow)
+		l.totals[kind] = newRateCounter(now, taskListStatsWindow)
+	}
+	return l
+}
+
+func (l *matchLatencies) record(kind matchLatencyKind, latency time.Duration) {
+	l.scope.RecordHistogramDuration(matchLatencyMetrics[kind], latency)
+	l.Lock()
+	defer l.Unlock()
+	now := l.timeSource.Now()
+	l.counts[kind].add(now, 1)
+	l.totals[kind].add(now, int64(latency))
+}
+
+// average returns the average latency of the matches of the given kind over the sliding window,
+// or zero if there was no such match
+func (l *matchLatencies) average(kind matchLatencyKind) time.Duration {
+	l.Lock()
+	defer l.Unlock()
+	now := l.timeSource.Now()
+	count := l.counts[kind].sum(now)
+	if count == 0 {
+		return 0
+	}
+	return time.Duration(l.totals[kind].sum(now) / count)
+}
+
+func newRateCounter(now time.Time, window time.Duration) *rateCounter {
+	numBuckets := int(window / time.Second)
+	if numBuckets < 1 {
+		numBuckets = 1
+	}
+	return &rateCounter{
+		createdAt: now,
+		counts:    make([]int64, numBuckets),
+		seconds:   make([]int64, numBuckets),
+	}
+}
+
+func (c *rateCounter) add(now time.Time, n int64) {
+	second := now.Unix()
+	idx := int(second % int64(len(c.counts)))
+	if c.seconds[idx] != second {
+		c.seconds[idx] = second
+		c.counts[idx] = 0
+	}
+	c.counts[idx] += n
+}
+
+// sum returns the number of events over the window
+func (c *rateCounter) sum(now time.Time) int64 {
+	second := now.Unix()
+	window := int64(len(c.counts))
+	var total int64
+	for idx, bucketSecond := range c.seconds {
+		if second-bucketSecond < window {
+			total += c.counts[idx]
+		}
+	}
+	return total
+}
+
+// rate returns the events per second over the window, a counter younger than
+// the window is averaged over its lifetime instead
+func (c *rateCounter) rate(now time.Time) float64 {
+	window := int64(len(c.counts))
+	total := c.sum(now)
+	elapsed := int64(now.Sub(c.createdAt)/time.Second) + 1
+	if elapsed < window {
+		window = elapsed
+	}
+	return float64(total) / float64(window)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber-go/tally"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/metrics"
+)
+
+func TestTaskListStats_Rates(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	timeSource := clock.NewEventTimeSource().Update(now)
+	stats := newTaskListStats(timeSource)
+
+	for i := 0; i < 10; i++ {
+		timeSource.Update(now.Add(time.Duration(i) * time.Second))
+		stats.recordAdded()
+		stats.recordAdded()
+		stats.recordSyncMatched()
+		stats.recordPolled()
+		stats.recordDispatched()
+	}
+	// the rates of a task list younger than the window are averaged over its lifetime
+	assert.Equal(t, 2.0, stats.addedPerSecond())
+	assert.Equal(t, 1.0, stats.dispatchedPerSecond())
+	assert.Equal(t, 1.0, stats.syncMatchedPerSecond())
+	assert.Equal(t, 1.0, stats.polledPerSecond())
+
+	// events older than the window are dropped
+	timeSource.Update(now.Add(taskListStatsWindow + 5*time.Second))
+	stats.recordDispatched()
+	assert.InDelta(t, 8.0/60, stats.addedPerSecond(), 0.001)
+	assert.InDelta(t, 5.0/60, stats.dispatchedPerSecond(), 0.001)
+
+	timeSource.Update(now.Add(10 * taskListStatsWindow))
+	assert.Zero(t, stats.addedPerSecond())
+	assert.Zero(t, stats.dispatchedPerSecond())
+}
+
+func TestTaskListStats_BacklogAge(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	timeSource := clock.NewEventTimeSource().Update(now)
+	stats := newTaskListStats(timeSource)
+	assert.Zero(t, stats.backlogAge())
+
+	stats.recordRead(2, now.Add(-time.Minute))
+	stats.recordRead(3, now.Add(-time.Second))
+	assert.Equal(t, time.Minute, stats.backlogAge())
+
+	stats.recordAcked(2)
+	assert.Equal(t, time.Second, stats.backlogAge())
+	stats.recordAcked(3)
+	assert.Zero(t, stats.backlogAge())
+}
+
+func TestEstimateDrainTime(t *testing.T) {
+	assert.Zero(t, estimateDrainTime(0, 10, 5))
+	assert.Equal(t, float64(notDrainingBacklog), estimateDrainTime(100, 10, 5))
+	assert.Equal(t, float64(notDrainingBacklog), estimateDrainTime(100, 5, 5))
+	assert.Equal(t, 20.0, estimateDrainTime(100, 5, 10))
+}
+
+func TestMatchLatencies(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	timeSource := clock.NewEventTimeSource().Update(now)
+	scope := tally.NewTestScope("test", nil)
+	latencies := newMatchLatencies(timeSource, metrics.NewClient(scope, metrics.Matching).Scope(metrics.MatchingTaskListMgrScope))
+	assert.Zero(t, latencies.average(localSyncMatch))
+
+	latencies.record(localSyncMatch, 10*time.Millisecond)
+	latencies.record(localSyncMatch, 30*time.Millisecond)
+	latencies.record(forwardedSyncMatch, 100*time.Millisecond)
+	assert.Equal(t, 20*time.Millisecond, latencies.average(localSyncMatch))
+	assert.Equal(t, 100*time.Millisecond, latencies.average(forwardedSyncMatch))
+	assert.Zero(t, latencies.average(backlogDispatch))
+
+	histograms := scope.Snapshot().Histograms()
+	assert.Contains(t, histograms, "test.local_syncmatch_latency_per_tl+operation=TaskListMgr")
+	assert.Contains(t, histograms, "test.forwarded_syncmatch_latency_per_tl+operation=TaskListMgr")
+	assert.NotContains(t, histograms, "test.backlog_dispatch_latency_per_tl+operation=TaskListMgr")
+
+	// matches older than the window are dropped
+	timeSource.Update(now.Add(taskListStatsWindow))
+	latencies.record(localSyncMatch, 40*time.Millisecond)
+	assert.Equal(t, 40*time.Millisecond, latencies.average(localSyncMatch))
+	assert.Zero(t, latencies.average(forwardedSyncMatch))
+}
@@ -24,14 +24,16 @@ package matching
 
 import (
 	"context"
+	"encoding/json"
+	"math/rand"
 	"runtime"
 	"sync/atomic"
 	"time"
 
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/log"
 	"github.com/uber/cadence/common/log/tag"
-	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/types"
@@ -39,17 +41,24 @@ import (
 
 var epochStartTime = time.Unix(0, 0)
 
+// initial backoff after an empty read from persistence when the adaptive task read is enabled,
+// doubled on each consecutive empty read up to MaxEmptyTaskReadBackoff
+const emptyTaskReadInitialBackoff = 100 * time.Millisecond
+
+// timeout of writing an expired task to the dead letter queue, the task is dropped if it cannot be written in time
+const expiredTaskDeadLetterTimeout = 2 * time.Second
+
 type (
 	taskReader struct {
-		taskBuffer     chan *persistence.TaskInfo // tasks loaded from persistence
-		notifyC        chan struct{}              // Used as signal to notify pump of new tasks
+		taskBuffer     *taskBuffer   // tasks loaded from persistence
+		notifyC        chan struct{} // Used as signal to notify pump of new tasks
 		tlMgr          *taskListManagerImpl
 		taskListID     *taskListID
 		config         *taskListConfig
 		db             *taskListDB
 		taskWriter     *taskWriter
 		taskGC         *taskGC
-		taskAckManager messaging.AckManager
+		taskAckManager *priorityAckManager
 		// The cancel objects are to cancel the ratelimiter Wait in dispatchBufferedTasks. The ideal
 		// approach is to use request-scoped contexts and use a unique one for each call to Wait. However
 		// in order to cancel it on shutdown, we need a new goroutine for each call that would wait on
@@ -63,8 +72,22 @@ type (
 		stopped             int64 // set to 1 if the reader is stopped or is shutting down
 		logger              log.Logger
 		scope               metrics.Scope
+		expiredTaskScope    metrics.Scope
 		throttleRetry       *backoff.ThrottleRetry
 		handleErr           func(error) error
+		// adaptive task read state, only accessed by the getTasks pump
+		batchSize             int   // batch size of the last read from persistence
+		emptyReads            int   // number of consecutive reads from persistence that returned no task before reaching the max read level
+		emptyReadMaxReadLevel int64 // max read level reached by the last empty read
+	}
+
+	// expiredTaskRecord is the payload of an expired task written to the dead letter queue
+	expiredTaskRecord struct {
+		DomainName   string                `json:"domainName"`
+		TaskListName string                `json:"taskListName"`
+		TaskListType int                   `json:"taskListType"`
+		ExpiredAt    time.Time             `json:"expiredAt"`
+		Task         *persistence.TaskInfo `json:"task"`
 	}
 )
 
@@ -82,12 +105,12 @@ func newTaskReader(tlMgr *taskListManagerImpl) *taskReader {
 		cancelFunc:          cancel,
 		notifyC:             make(chan struct{}, 1),
 		dispatcherShutdownC: make(chan struct{}),
-		// we always dequeue the head of the buffer and try to dispatch it to a poller
-		// so allocate one less than desired target buffer size
-		taskBuffer: make(chan *persistence.TaskInfo, tlMgr.config.GetTasksBatchSize()-1),
-		logger:     tlMgr.logger,
-		scope:      tlMgr.scope,
-		handleErr:  tlMgr.handleErr,
+		taskBuffer:          newTaskBuffer(taskBufferCapacity(tlMgr.config), tlMgr.config.EnableWorkflowFairDispatch),
+		logger:              tlMgr.logger,
+		scope:               tlMgr.scope,
+		expiredTaskScope: newPerTaskListScope(tlMgr.domainName, tlMgr.taskListID.name, tlMgr.taskListKind,
+			tlMgr.engine.metricsClient, metrics.MatchingTaskListExpiredTasksScope).Tagged(getTaskListTypeTag(tlMgr.taskListID.taskType)),
+		handleErr: tlMgr.handleErr,
 		throttleRetry: backoff.NewThrottleRetry(
 			backoff.WithRetryPolicy(persistenceOperationRetryPolicy),
 			backoff.WithRetryableError(persistence.IsTransientError),
@@ -95,6 +118,18 @@ func newTaskReader(tlMgr *taskListManagerImpl) *taskReader {
 	}
 }
 
+// taskBufferCapacity returns the capacity of the task buffer, which holds a whole batch of tasks read from
+// persistence. When the adaptive task read is enabled, a batch can grow up to MaxGetTasksBatchSize
+func taskBufferCapacity(config *taskListConfig) int {
+	capacity := config.GetTasksBatchSize()
+	if config.EnableAdaptiveTaskRead() {
+		capacity = common.MaxInt(capacity, config.MaxGetTasksBatchSize())
+	}
+	// we always dequeue the head of the buffer and try to dispatch it to a poller
+	// so allocate one less than desired target buffer size
+	return capacity - 1
+}
+
 func (tr *taskReader) Start() {
 	tr.Signal()
 	go tr.dispatchBufferedTasks()
@@ -125,34 +160,32 @@ func (tr *taskReader) Signal() {
 func (tr *taskReader) dispatchBufferedTasks() {
 dispatchLoop:
 	for {
-		select {
-		case taskInfo, ok := <-tr.taskBuffer:
-			if !ok { // Task list getTasks pump is shutdown
-				break dispatchLoop
+		// highest priority task is dequeued first, get returns false when
+		// the getTasks pump or the dispatcher is shutdown
+		taskInfo, ok := tr.taskBuffer.get(tr.dispatcherShutdownC)
+		if !ok {
+			break dispatchLoop
+		}
+		task := newInternalTask(taskInfo, tr.tlMgr.completeTask, types.TaskSourceDbBacklog, "", false, nil)
+		for {
+			err := tr.tlMgr.DispatchTask(tr.cancelCtx, task)
+			if err == nil {
+				break
 			}
-			task := newInternalTask(taskInfo, tr.tlMgr.completeTask, types.TaskSourceDbBacklog, "", false, nil)
-			for {
-				err := tr.tlMgr.DispatchTask(tr.cancelCtx, task)
-				if err == nil {
-					break
-				}
-				if err == context.Canceled {
-					tr.logger.Info("Tasklist manager context is cancelled, shutting down")
-					break dispatchLoop
-				}
-				// this should never happen unless there is a bug - don't drop the task
-				tr.scope.IncCounter(metrics.BufferThrottlePerTaskListCounter)
-				tr.logger.Error("taskReader: unexpected error dispatching task", tag.Error(err))
-				runtime.Gosched()
+			if err == context.Canceled {
+				tr.logger.Info("Tasklist manager context is cancelled, shutting down")
+				break dispatchLoop
 			}
-		case <-tr.dispatcherShutdownC:
-			break dispatchLoop
+			// this should never happen unless there is a bug - don't drop the task
+			tr.scope.IncCounter(metrics.BufferThrottlePerTaskListCounter)
+			tr.logger.Error("taskReader: unexpected error dispatching task", tag.Error(err))
+			runtime.Gosched()
 		}
 	}
 }
 
 func (tr *taskReader) getTasksPump() {
-	defer close(tr.taskBuffer)
+	defer tr.taskBuffer.close()
 
 	updateAckTimer := time.NewTimer(tr.config.UpdateAckInterval())
 	defer updateAckTimer.Stop()
@@ -163,6 +196,14 @@ getTasksPumpLoop:
 			break getTasksPumpLoop
 		case <-tr.notifyC:
 			{
+				if tr.tlMgr.isBufferingStandbyTasks() {
+					// the tasks of a standby domain stay in the backlog until the domain fails over,
+					// the reader is signaled then or on the next ack level update
+					continue getTasksPumpLoop
+				}
+				if !tr.waitEmptyReadBackoff() {
+					break getTasksPumpLoop
+				}
 				tasks, readLevel, isReadBatchDone, err := tr.getTaskBatch()
 				if err != nil {
 					tr.Signal() // re-enqueue the event
@@ -202,10 +243,10 @@ getTasksPumpLoop:
 
 }
 
-func (tr *taskReader) getTaskBatchWithRange(readLevel int64, maxReadLevel int64) ([]*persistence.TaskInfo, error) {
+func (tr *taskReader) getTaskBatchWithRange(readLevel int64, maxReadLevel int64, batchSize int) ([]*persistence.TaskInfo, error) {
 	var response *persistence.GetTasksResponse
 	op := func() (err error) {
-		response, err = tr.db.GetTasks(readLevel, maxReadLevel, tr.config.GetTasksBatchSize())
+		response, err = tr.db.GetTasks(readLevel, maxReadLevel, batchSize)
 		return
 	}
 	err := tr.throttleRetry.Do(context.Background(), op)
@@ -227,6 +268,10 @@ func (tr *taskReader) getTaskBatch() ([]*persistence.TaskInfo, int64, bool, erro
 	var tasks []*persistence.TaskInfo
 	readLevel := tr.taskAckManager.GetReadLevel()
 	maxReadLevel := tr.taskWriter.GetMaxReadLevel()
+	if readLevel >= maxReadLevel {
+		return tasks, readLevel, readLevel == maxReadLevel, nil
+	}
+	batchSize := tr.getTasksBatchSize(maxReadLevel - readLevel)
 
 	// counter i is used to break and let caller check whether tasklist is still alive and need resume read.
 	for i := 0; i < 10 && readLevel < maxReadLevel; i++ {
@@ -234,19 +279,77 @@ func (tr *taskReader) getTaskBatch() ([]*persistence.TaskInfo, int64, bool, erro
 		if upper > maxReadLevel {
 			upper = maxReadLevel
 		}
-		tasks, err := tr.getTaskBatchWithRange(readLevel, upper)
+		tasks, err := tr.getTaskBatchWithRange(readLevel, upper, batchSize)
 		if err != nil {
 			return nil, readLevel, true, err
 		}
 		// return as long as it grabs any tasks
 		if len(tasks) > 0 {
+			tr.emptyReads = 0
 			return tasks, upper, true, nil
 		}
 		readLevel = upper
 	}
+	if readLevel == maxReadLevel {
+		// the reads went up to the max read level without finding any task, back off before
+		// the next read rather than polling persistence while there is nothing to read
+		tr.emptyReads++
+		tr.emptyReadMaxReadLevel = maxReadLevel
+	}
 	return tasks, readLevel, readLevel == maxReadLevel, nil // caller will update readLevel when no task grabbed
 }
 
+// getTasksBatchSize returns the batch size of the next read from persistence. When the adaptive task read
+// is enabled, the batch size doubles up to MaxGetTasksBatchSize while the backlog is deeper than the batch
+// size and pollers are waiting for tasks, and halves back to GetTasksBatchSize otherwise. The batch size
+// never grows past what the task buffer holds, so that adding a batch to the buffer doesn't block the pump.
+func (tr *taskReader) getTasksBatchSize(backlog int64) int {
+	batchSize := tr.config.GetTasksBatchSize()
+	if tr.config.EnableAdaptiveTaskRead() {
+		maxBatchSize := common.MaxInt(batchSize, common.MinInt(tr.config.MaxGetTasksBatchSize(), tr.taskBuffer.cap()+1))
+		if tr.batchSize > 0 && backlog > int64(tr.batchSize) && tr.tlMgr.matcher.getWaitingPollers() > 0 {
+			batchSize = common.MinInt(tr.batchSize*2, maxBatchSize)
+		} else if tr.batchSize > batchSize {
+			batchSize = common.MinInt(common.MaxInt(tr.batchSize/2, batchSize), maxBatchSize)
+		}
+	}
+	if batchSize != tr.batchSize {
+		tr.batchSize = batchSize
+		scope := tr.scope.Tagged(getTaskListTypeTag(tr.taskListID.taskType))
+		scope.UpdateGauge(metrics.GetTasksBatchSizePerTaskListGauge, float64(batchSize))
+	}
+	return batchSize
+}
+
+// waitEmptyReadBackoff blocks the getTasks pump when the adaptive task read is enabled and consecutive reads
+// from persistence went up to the max read level without finding any task, e.g. for a task list which is
+// signaled often but rarely has anything to read. The backoff is reset once new tasks are written past the
+// max read level of the last empty read. Returns false if the task list is shutdown while waiting.
+func (tr *taskReader) waitEmptyReadBackoff() bool {
+	if tr.emptyReads > 0 && tr.taskWriter.GetMaxReadLevel() != tr.emptyReadMaxReadLevel {
+		tr.emptyReads = 0
+	}
+	if !tr.config.EnableAdaptiveTaskRead() || tr.emptyReads == 0 {
+		return true
+	}
+	backoff := tr.config.MaxEmptyTaskReadBackoff()
+	if shift := tr.emptyReads - 1; shift < 16 && emptyTaskReadInitialBackoff<<uint(shift) < backoff {
+		backoff = emptyTaskReadInitialBackoff << uint(shift)
+	}
+	if backoff <= 0 {
+		return true
+	}
+	tr.scope.IncCounter(metrics.EmptyTaskReadBackoffPerTaskListCounter)
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-tr.tlMgr.shutdownCh:
+		return false
+	}
+}
+
 func (tr *taskReader) isTaskExpired(t *persistence.TaskInfo, now time.Time) bool {
 	return t.Expiry.After(epochStartTime) && time.Now().After(t.Expiry)
 }
@@ -255,7 +358,7 @@ func (tr *taskReader) addTasksToBuffer(tasks []*persistence.TaskInfo) bool {
 	now := time.Now()
 	for _, t := range tasks {
 		if tr.isTaskExpired(t, now) {
-			tr.scope.IncCounter(metrics.ExpiredTasksPerTaskListCounter)
+			tr.handleExpiredTask(t, now)
 			// Also increment readLevel for expired tasks otherwise it could result in
 			// looping over the same tasks if all tasks read in the batch are expired
 			tr.taskAckManager.SetReadLevel(t.TaskID)
@@ -268,19 +371,61 @@ func (tr *taskReader) addTasksToBuffer(tasks []*persistence.TaskInfo) bool {
 	return true
 }
 
+// handleExpiredTask records the metrics of a task skipped because it expired in the backlog,
+// and writes it to the dead letter queue when enabled for the domain instead of dropping it
+func (tr *taskReader) handleExpiredTask(task *persistence.TaskInfo, now time.Time) {
+	tr.scope.IncCounter(metrics.ExpiredTasksPerTaskListCounter)
+	tr.expiredTaskScope.IncCounter(metrics.ExpiredTasksPerTaskListCounter)
+	if !task.CreatedTime.IsZero() {
+		tr.expiredTaskScope.RecordTimer(metrics.ExpiredTaskAgePerTaskList, now.Sub(task.CreatedTime))
+	}
+
+	if rate := tr.config.ExpiredTaskLogSampleRate(); rate > 0 && rand.Float64() < rate {
+		tr.logger.Info("Skipped task expired in the backlog",
+			tag.WorkflowDomainID(task.DomainID),
+			tag.WorkflowID(task.WorkflowID),
+			tag.WorkflowRunID(task.RunID),
+			tag.WorkflowScheduleID(task.ScheduleID),
+			tag.TaskID(task.TaskID),
+			tag.Timestamp(task.Expiry),
+		)
+	}
+
+	queue := tr.tlMgr.engine.expiredTaskQueue
+	if queue == nil || !tr.config.EnableExpiredTaskDeadLetter() {
+		return
+	}
+	if err := tr.writeExpiredTaskToDeadLetter(queue, task, now); err != nil {
+		tr.expiredTaskScope.IncCounter(metrics.ExpiredTasksDeadLetterFailuresPerTaskListCounter)
+		tr.logger.Warn("Failed to write expired task to the dead letter queue", tag.TaskID(task.TaskID), tag.Error(err))
+		return
+	}
+	tr.expiredTaskScope.IncCounter(metrics.ExpiredTasksDeadLetteredPerTaskListCounter)
+}
+
+func (tr *taskReader) writeExpiredTaskToDeadLetter(queue persistence.QueueManager, task *persistence.TaskInfo, now time.Time) error {
+	payload, err := json.Marshal(&expiredTaskRecord{
+		DomainName:   tr.tlMgr.domainName,
+		TaskListName: tr.taskListID.name,
+		TaskListType: tr.taskListID.taskType,
+		ExpiredAt:    now,
+		Task:         task,
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(tr.cancelCtx, expiredTaskDeadLetterTimeout)
+	defer cancel()
+	return queue.EnqueueMessageToDLQ(ctx, payload)
+}
+
 func (tr *taskReader) addSingleTaskToBuffer(task *persistence.TaskInfo) bool {
-	err := tr.taskAckManager.ReadItem(task.TaskID)
+	err := tr.taskAckManager.ReadTask(task)
 	if err != nil {
 		tr.logger.Fatal("critical bug when adding item to ackManager", tag.Error(err))
 	}
-	for {
-		select {
-		case tr.taskBuffer <- task:
-			return true
-		case <-tr.tlMgr.shutdownCh:
-			return false
-		}
-	}
+	tr.tlMgr.stats.recordRead(task.TaskID, task.CreatedTime)
+	return tr.taskBuffer.put(task, tr.tlMgr.shutdownCh)
 }
 
 func (tr *taskReader) persistAckLevel() error {
@@ -289,10 +434,15 @@ func (tr *taskReader) persistAckLevel() error {
 		maxReadLevel := tr.taskWriter.GetMaxReadLevel()
 		scope := tr.scope.Tagged(getTaskListTypeTag(tr.taskListID.taskType))
 		// note: this metrics is only an estimation for the lag. taskID in DB may not be continuous,
-		// especially when task list ownership changes.
-		scope.UpdateGauge(metrics.TaskLagPerTaskListGauge, float64(maxReadLevel-ackLevel))
+		// especially when task list ownership changes. The tasks which are read are counted
+		// by the backlog so that the tasks completed after a stuck task are not included.
+		lag := maxReadLevel - tr.taskAckManager.GetReadLevel() + tr.taskAckManager.GetBacklogCount()
+		scope.UpdateGauge(metrics.TaskLagPerTaskListGauge, float64(lag))
 
-		return tr.db.UpdateState(ackLevel)
+		return tr.db.UpdateState(ackLevel, backlogSnapshot{
+			count:                 lag,
+			oldestTaskCreatedTime: tr.tlMgr.stats.oldestBacklogTaskCreatedTime(),
+		})
 	}
 	return nil
 }
@@ -26,6 +26,7 @@ import (
 	"errors"
 	"fmt"
 	"sync/atomic"
+	"time"
 
 	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/log"
@@ -64,6 +65,7 @@ type (
 		taskIDBlock    taskIDBlock
 		maxReadLevel   int64
 		stopped        int64 // set to 1 if the writer is stopped or is shutting down
+		pendingAppends int64 // number of appendTask calls waiting for their tasks to be written
 		logger         log.Logger
 		scope          metrics.Scope
 		stopCh         chan struct{} // shutdown signal for all routines in this class
@@ -75,6 +77,9 @@ type (
 // errShutdown indicates that the task list is shutting down
 var errShutdown = errors.New("task list shutting down")
 
+// interval at which flush checks whether the pending appends are written
+const taskWriterFlushCheckInterval = 10 * time.Millisecond
+
 func newTaskWriter(tlMgr *taskListManagerImpl) *taskWriter {
 	return &taskWriter{
 		tlMgr:          tlMgr,
@@ -126,6 +131,8 @@ func (w *taskWriter) appendTask(execution *types.WorkflowExecution,
 	if w.isStopped() {
 		return nil, errShutdown
 	}
+	atomic.AddInt64(&w.pendingAppends, 1)
+	defer atomic.AddInt64(&w.pendingAppends, -1)
 
 	ch := make(chan *writeTaskResponse)
 	req := &writeTaskRequest{
@@ -149,6 +156,21 @@ func (w *taskWriter) appendTask(execution *types.WorkflowExecution,
 	}
 }
 
+// flush waits until the pending appends are written, it returns the context error if they are
+// still pending when the context is done
+func (w *taskWriter) flush(ctx context.Context) error {
+	ticker := time.NewTicker(taskWriterFlushCheckInterval)
+	defer ticker.Stop()
+	for atomic.LoadInt64(&w.pendingAppends) > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
 func (w *taskWriter) GetMaxReadLevel() int64 {
 	return atomic.LoadInt64(&w.maxReadLevel)
 }
@@ -77,10 +77,28 @@ const (
 	BatchTypeSignal = "signal"
 	// BatchTypeReplicate is batch type for replicating workflows
 	BatchTypeReplicate = "replicate"
+	// BatchTypeReset is batch type for resetting workflows
+	BatchTypeReset = "reset"
 )
 
 // AllBatchTypes is the batch types we supported
-var AllBatchTypes = []string{BatchTypeTerminate, BatchTypeCancel, BatchTypeSignal, BatchTypeReplicate}
+var AllBatchTypes = []string{BatchTypeTerminate, BatchTypeCancel, BatchTypeSignal, BatchTypeReplicate, BatchTypeReset}
+
+const (
+	// ResetTypeFirstDecisionCompleted resets workflows to their first completed decision
+	ResetTypeFirstDecisionCompleted = "FirstDecisionCompleted"
+	// ResetTypeLastDecisionCompleted resets workflows to their last completed decision
+	ResetTypeLastDecisionCompleted = "LastDecisionCompleted"
+	// ResetTypeDecisionCompletedTime resets workflows to their first decision completed at or after a timestamp
+	ResetTypeDecisionCompletedTime = "DecisionCompletedTime"
+	// ResetTypeEventID resets workflows to a given decision finish event ID
+	ResetTypeEventID = "EventID"
+)
+
+// AllResetTypes is the reset types we supported for BatchTypeReset
+var AllResetTypes = []string{ResetTypeFirstDecisionCompleted, ResetTypeLastDecisionCompleted, ResetTypeDecisionCompletedTime, ResetTypeEventID}
+
+var errNoResetPoint = &types.BadRequestError{Message: "no completed decision to reset the workflow to"}
 
 type (
 	// TerminateParams is the parameters for terminating workflow
@@ -111,6 +129,19 @@ type (
 		TargetCluster string
 	}
 
+	// ResetParams is the parameters for resetting workflows
+	ResetParams struct {
+		// one of AllResetTypes
+		ResetType string
+		// DecisionFinishEventID to reset to, only for ResetTypeEventID
+		EventID int64
+		// timestamp in unix nanoseconds, only for ResetTypeDecisionCompletedTime
+		EarliestTime      int64
+		SkipSignalReapply bool
+		// resolve the reset point of each workflow without resetting it
+		DryRun bool
+	}
+
 	// BatchParams is the parameters for batch operation workflow
 	BatchParams struct {
 		// Target domain to execute batch operation
@@ -131,6 +162,8 @@ type (
 		SignalParams SignalParams
 		// ReplicateParams is params only for BatchTypeReplicate
 		ReplicateParams ReplicateParams
+		// ResetParams is params only for BatchTypeReset
+		ResetParams ResetParams
 		// RPS of processing. Default to DefaultRPS
 		// TODO we will implement smarter way than this static rate limiter: https://github.com/uber/cadence/issues/2138
 		RPS int
@@ -224,6 +257,23 @@ func validateParams(params BatchParams) error {
 			return fmt.Errorf("must provide target cluster")
 		}
 		return nil
+	case BatchTypeReset:
+		switch params.ResetParams.ResetType {
+		case ResetTypeFirstDecisionCompleted, ResetTypeLastDecisionCompleted:
+			return nil
+		case ResetTypeDecisionCompletedTime:
+			if params.ResetParams.EarliestTime <= 0 {
+				return fmt.Errorf("must provide earliest time")
+			}
+			return nil
+		case ResetTypeEventID:
+			if params.ResetParams.EventID <= 0 {
+				return fmt.Errorf("must provide event ID")
+			}
+			return nil
+		default:
+			return fmt.Errorf("not supported reset type: %v", params.ResetParams.ResetType)
+		}
 	case BatchTypeCancel:
 		fallthrough
 	case BatchTypeTerminate:
@@ -447,13 +497,18 @@ func startTaskProcessor(
 							RemoteCluster: batchParams.ReplicateParams.SourceCluster,
 						})
 					})
+			case BatchTypeReset:
+				err = processTask(ctx, limiter, task, batchParams, client, common.BoolPtr(false),
+					func(workflowID, runID string) error {
+						return resetWorkflow(ctx, client, batchParams, workflowID, runID, requestID)
+					})
 			}
 			if err != nil {
 				batcher.metricsClient.IncCounter(metrics.BatcherScope, metrics.BatcherProcessorFailures)
 				getActivityLogger(ctx).Error("Failed to process batch operation task", tag.Error(err))
 
 				_, ok := batchParams._nonRetryableErrors[err.Error()]
-				if ok || task.attempts >= batchParams.AttemptsOnRetryableError {
+				if ok || err == errNoResetPoint || task.attempts >= batchParams.AttemptsOnRetryableError {
 					respCh <- err
 				} else {
 					// put back to the channel if less than attemptsOnError
@@ -527,6 +582,92 @@ func processTask(
 	return nil
 }
 
+func resetWorkflow(
+	ctx context.Context,
+	client frontend.Client,
+	batchParams BatchParams,
+	workflowID string,
+	runID string,
+	requestID string,
+) error {
+	eventID, err := getResetEventID(ctx, client, batchParams.DomainName, workflowID, runID, batchParams.ResetParams)
+	if err != nil {
+		return err
+	}
+	if batchParams.ResetParams.DryRun {
+		getActivityLogger(ctx).Info("Dry run of batch reset",
+			tag.WorkflowID(workflowID),
+			tag.WorkflowRunID(runID),
+			tag.WorkflowEventID(eventID))
+		return nil
+	}
+	_, err = client.ResetWorkflowExecution(ctx, &types.ResetWorkflowExecutionRequest{
+		Domain: batchParams.DomainName,
+		WorkflowExecution: &types.WorkflowExecution{
+			WorkflowID: workflowID,
+			RunID:      runID,
+		},
+		Reason:                batchParams.Reason,
+		DecisionFinishEventID: eventID,
+		RequestID:             requestID,
+		SkipSignalReapply:     batchParams.ResetParams.SkipSignalReapply,
+	})
+	return err
+}
+
+// getResetEventID returns the DecisionFinishEventID to reset the workflow run to
+func getResetEventID(
+	ctx context.Context,
+	client frontend.Client,
+	domain string,
+	workflowID string,
+	runID string,
+	params ResetParams,
+) (int64, error) {
+	if params.ResetType == ResetTypeEventID {
+		return params.EventID, nil
+	}
+
+	req := &types.GetWorkflowExecutionHistoryRequest{
+		Domain: domain,
+		Execution: &types.WorkflowExecution{
+			WorkflowID: workflowID,
+			RunID:      runID,
+		},
+		MaximumPageSize: 1000,
+	}
+	var eventID int64
+	for {
+		resp, err := client.GetWorkflowExecutionHistory(ctx, req)
+		if err != nil {
+			return 0, err
+		}
+		for _, e := range resp.GetHistory().GetEvents() {
+			if e.GetEventType() != types.EventTypeDecisionTaskCompleted {
+				continue
+			}
+			switch params.ResetType {
+			case ResetTypeFirstDecisionCompleted:
+				return e.ID, nil
+			case ResetTypeDecisionCompletedTime:
+				if e.GetTimestamp() >= params.EarliestTime {
+					return e.ID, nil
+				}
+			case ResetTypeLastDecisionCompleted:
+				eventID = e.ID
+			}
+		}
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		req.NextPageToken = resp.NextPageToken
+	}
+	if eventID == 0 {
+		return 0, errNoResetPoint
+	}
+	return eventID, nil
+}
+
 func isDone(ctx context.Context) bool {
 	select {
 	case <-ctx.Done():
@@ -28,6 +28,7 @@ import (
 
 	"github.com/urfave/cli"
 
+	"github.com/uber/cadence/common/diagnostics"
 	"github.com/uber/cadence/common/reconciliation/invariant"
 	"github.com/uber/cadence/service/worker/scanner/executions"
 )
@@ -89,6 +90,31 @@ func newAdminWorkflowCommands() []cli.Command {
 				AdminDescribeWorkflow(c)
 			},
 		},
+		{
+			Name:    "timers",
+			Aliases: []string{"tm"},
+			Usage:   "List user and internal timers of workflow execution and flag timers whose tasks are missing from the timer queue",
+			Flags: append(
+				getDBFlags(),
+				cli.StringFlag{
+					Name:  FlagWorkflowIDWithAlias,
+					Usage: "WorkflowID",
+				},
+				cli.StringFlag{
+					Name:  FlagRunIDWithAlias,
+					Usage: "RunID",
+				},
+				cli.IntFlag{
+					Name:  FlagBatchSize,
+					Value: 1000,
+					Usage: "Number of timer tasks to read from the shard per page",
+				},
+				getFormatFlag(),
+			),
+			Action: func(c *cli.Context) {
+				AdminWorkflowTimers(c)
+			},
+		},
 		{
 			Name:    "refresh-tasks",
 			Aliases: []string{"rt"},
@@ -195,6 +221,49 @@ func newAdminShardManagementCommands() []cli.Command {
 				AdminDescribeShardDistribution(c)
 			},
 		},
+		{
+			Name:    "ownership",
+			Aliases: []string{"o"},
+			Usage:   "Describe shard ownership, the shards owned by each host and the shard acquisitions and steals seen by a history host",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagAddressWithAlias,
+					Usage: "RPC address of the history host describing the ownership",
+				},
+				cli.BoolFlag{
+					Name:  FlagAllWithAlias,
+					Usage: "List the owners of all shards instead of only the shards owned or recently stolen by the host",
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				AdminDescribeShardOwnership(c)
+			},
+		},
+		{
+			Name:    "heatmap",
+			Aliases: []string{"hm"},
+			Usage:   "List the shards and persistence operations with the highest p99 latency recently seen by a history host",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagAddressWithAlias,
+					Usage: "RPC address of the history host",
+				},
+				cli.StringFlag{
+					Name:  FlagWindow,
+					Usage: "only include the operations completed within this duration, e.g. 5m, defaults to the last 15m",
+				},
+				cli.IntFlag{
+					Name:  FlagTop,
+					Usage: "only show this many shard and operation pairs, 0 means all",
+					Value: 20,
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				AdminShardLatencyHeatmap(c)
+			},
+		},
 		{
 			Name:    "setRangeID",
 			Aliases: []string{"srid"},
@@ -258,6 +327,42 @@ func newAdminShardManagementCommands() []cli.Command {
 				AdminRemoveTask(c)
 			},
 		},
+		{
+			Name:    "pendingTasks",
+			Aliases: []string{"pt"},
+			Usage:   "Report pending transfer, overdue timer and replication task counts for a range of shards, most backlogged first",
+			Flags: append(getDBFlags(),
+				cli.IntFlag{
+					Name:  FlagLowerShardBound,
+					Usage: "first shard to report on",
+					Value: 0,
+				},
+				cli.IntFlag{
+					Name:  FlagUpperShardBound,
+					Usage: "last shard to report on",
+					Value: 16383,
+				},
+				cli.IntFlag{
+					Name:  FlagBatchSize,
+					Usage: "page size used to read task queues",
+					Value: 1000,
+				},
+				cli.IntFlag{
+					Name:  FlagMaxTaskCount,
+					Usage: "stop counting a queue after this many tasks, 0 means no limit",
+					Value: 100000,
+				},
+				cli.IntFlag{
+					Name:  FlagTop,
+					Usage: "only show this many shards, 0 means all",
+					Value: 20,
+				},
+				getFormatFlag(),
+			),
+			Action: func(c *cli.Context) {
+				AdminShardPendingTasks(c)
+			},
+		},
 		{
 			Name:  "timers",
 			Usage: "get scheduled timers for a given time range",
@@ -468,6 +573,43 @@ func newAdminDomainCommands() []cli.Command {
 				newDomainCLI(c, false).ListDomains(c)
 			},
 		},
+		{
+			Name:  "usage",
+			Usage: "Show the resources consumed by domains in a time range",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagDomain,
+					Usage: "Optional. Only show the usage of the domain",
+				},
+				cli.StringFlag{
+					Name: FlagEarliestTimeWithAlias,
+					Usage: "Start of the time range, defaults to 24 hours ago. Supported formats are '2006-01-02T15:04:05+07:00', " +
+						"raw UnixNano and time range (N<duration>), for example '15m' implies 15 minutes ago",
+				},
+				cli.StringFlag{
+					Name:  FlagLatestTimeWithAlias,
+					Usage: "End of the time range, defaults to now. Supports the same formats as " + FlagEarliestTime,
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				AdminDomainUsage(c)
+			},
+		},
+		{
+			Name:  "slo",
+			Usage: "Show the error budget and burn rates of the SLOs of domains, as tracked by the frontend host serving the request",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagDomain,
+					Usage: "Optional. Only show the SLOs of the domain",
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				AdminDescribeDomainSLO(c)
+			},
+		},
 	}
 }
 
@@ -695,6 +837,184 @@ func newAdminTaskListCommands() []cli.Command {
 				AdminListTaskList(c)
 			},
 		},
+		{
+			Name:    "set-config",
+			Aliases: []string{"sc"},
+			Usage:   "Override a matching dynamic config (partitions, forwarder limits, dispatch rps) for a tasklist",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Usage: "Optional TaskList type [decision|activity], applies to both types if not set",
+				},
+				cli.StringFlag{
+					Name:  FlagDynamicConfigName,
+					Usage: "Dynamic config name",
+				},
+				cli.StringFlag{
+					Name:  FlagDynamicConfigValue,
+					Usage: "Dynamic config value in json format",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminSetTaskListConfig(c)
+			},
+		},
+		{
+			Name:    "clear-config",
+			Aliases: []string{"cc"},
+			Usage:   "Remove a matching dynamic config override of a tasklist",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Usage: "Optional TaskList type [decision|activity], applies to both types if not set",
+				},
+				cli.StringFlag{
+					Name:  FlagDynamicConfigName,
+					Usage: "Dynamic config name",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminClearTaskListConfig(c)
+			},
+		},
+		{
+			Name:    "list-config",
+			Aliases: []string{"lc"},
+			Usage:   "List matching dynamic config overrides of a tasklist",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminListTaskListConfig(c)
+			},
+		},
+		{
+			Name:    "unload",
+			Aliases: []string{"ul"},
+			Usage:   "Force-unload a tasklist from the matching host owning it, it is reloaded by its next request",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+				cli.IntFlag{
+					Name:  FlagBlockReloadSeconds,
+					Usage: "Optional. Reject the requests to the tasklist for the number of seconds instead of reloading it right away",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminUnloadTaskList(c)
+			},
+		},
+		{
+			Name:    "pause",
+			Aliases: []string{"ps"},
+			Usage:   "Stop dispatching the tasks of a tasklist partition, its pollers receive empty responses until it is resumed",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminPauseTaskList(c)
+			},
+		},
+		{
+			Name:    "resume",
+			Aliases: []string{"rs"},
+			Usage:   "Resume dispatching the tasks of a paused tasklist partition",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminResumeTaskList(c)
+			},
+		},
+		{
+			Name:    "drain",
+			Aliases: []string{"dr"},
+			Usage:   "Reject the new tasks of a tasklist while its pollers consume its backlog",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Usage: "Optional TaskList type [decision|activity], applies to both types if not set",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminDrainTaskList(c)
+			},
+		},
+		{
+			Name:    "undrain",
+			Aliases: []string{"udr"},
+			Usage:   "Accept the new tasks of a tasklist in drain mode again",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Usage: "Optional TaskList type [decision|activity], applies to both types if not set",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminUndrainTaskList(c)
+			},
+		},
+		{
+			Name:    "drain-status",
+			Aliases: []string{"ds"},
+			Usage:   "Report whether a tasklist in drain mode has no task left in any of its partitions",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminDrainTaskListStatus(c)
+			},
+		},
 	}
 }
 
@@ -1294,5 +1614,336 @@ func newAdminConfigStoreCommands() []cli.Command {
 				AdminListConfigKeys(c)
 			},
 		},
+		{
+			Name:    "effective",
+			Aliases: []string{"e"},
+			Usage:   "List the Dynamic Config Values in effect for a domain, tasklist or shard. Domain is taken from the global domain option",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "Optional. TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Usage: "Optional. TaskList type [decision|activity]",
+				},
+				cli.IntFlag{
+					Name:  FlagShardIDWithAlias,
+					Usage: "Optional. Shard ID",
+				},
+				cli.StringFlag{
+					Name:  FlagWorkflowIDWithAlias,
+					Usage: "Optional. Workflow ID",
+				},
+				cli.StringFlag{
+					Name:  FlagWorkflowType,
+					Usage: "Optional. Workflow type",
+				},
+				cli.StringFlag{
+					Name:  FlagDynamicConfigName,
+					Usage: "Optional. Only list the parameters with the name prefix",
+				},
+				cli.BoolFlag{
+					Name:  FlagDynamicConfigOverridden,
+					Usage: "Only list the parameters which are not using their default value",
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				AdminListEffectiveDynamicConfig(c)
+			},
+		},
+		{
+			Name:    "history",
+			Aliases: []string{"h"},
+			Usage:   "List the change history of Dynamic Config Values",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagDynamicConfigName,
+					Usage: "Optional. Only list changes of the Dynamic Config parameter",
+				},
+				cli.Int64Flag{
+					Name:  FlagDynamicConfigVersion,
+					Usage: "Optional. Only list changes at or before the version",
+				},
+				cli.IntFlag{
+					Name:  FlagPageSizeWithAlias,
+					Value: 20,
+					Usage: "Maximum number of changes to list",
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				AdminDynamicConfigHistory(c)
+			},
+		},
+		{
+			Name:    "rollback",
+			Aliases: []string{"rb"},
+			Usage:   "Roll back a Dynamic Config parameter to its values at a version of the change history",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:     FlagDynamicConfigName,
+					Usage:    "Name of Dynamic Config parameter to roll back",
+					Required: true,
+				},
+				cli.Int64Flag{
+					Name:     FlagDynamicConfigVersion,
+					Usage:    "Version of the change history to roll back to",
+					Required: true,
+				},
+				cli.IntFlag{
+					Name:  FlagPageSizeWithAlias,
+					Value: 100,
+					Usage: "Page size used to scan the change history",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminRollbackDynamicConfig(c)
+			},
+		},
+	}
+}
+
+func newAdminDiagnosticsCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:    "collect",
+			Aliases: []string{"c"},
+			Usage:   "Enable profiles and verbose logging on a host for a bounded duration and download the collected artifacts as a zip archive",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagAddressWithAlias,
+					Value: "localhost:7936",
+					Usage: "pprof address of the host, diagnostics are only served on localhost and require an admin token (--jwt)",
+				},
+				cli.IntFlag{
+					Name:  FlagDiagnosticsSeconds,
+					Value: int(diagnostics.DefaultDuration.Seconds()),
+					Usage: "Duration of the collection in seconds",
+				},
+				cli.StringFlag{
+					Name:  FlagDiagnosticsProfiles,
+					Usage: "Optional. Comma separated profiles to collect [cpu|trace|mutex|block|heap|allocs|goroutine|threadcreate], all profiles by default",
+				},
+				cli.BoolFlag{
+					Name:  FlagVerboseLogging,
+					Usage: "Capture the logs of all levels, including debug, for the duration of the collection",
+				},
+				cli.StringFlag{
+					Name:  FlagOutputFilenameWithAlias,
+					Usage: "Optional. Output file, diagnostics-<timestamp>.zip by default",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminCollectDiagnostics(c)
+			},
+		},
+	}
+}
+
+func newAdminCacheCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:    "describe",
+			Aliases: []string{"d"},
+			Usage:   "Show the stats and the most read entries of the in-memory caches of a host",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagAddressWithAlias,
+					Value: "localhost:7936",
+					Usage: "pprof address of the host, the caches are only served on localhost",
+				},
+				cli.StringFlag{
+					Name:  FlagNameWithAlias,
+					Usage: "Optional. Only show the caches whose name starts with the prefix, e.g. history/execution",
+				},
+				cli.IntFlag{
+					Name:  FlagTop,
+					Value: 10,
+					Usage: "Number of entries to show per cache, by number of hits",
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				AdminDescribeCaches(c)
+			},
+		},
+		{
+			Name:    "invalidate",
+			Aliases: []string{"i"},
+			Usage:   "Remove the entries whose key contains the match from the in-memory caches of a host, they are reloaded on their next access",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagAddressWithAlias,
+					Usage: "Optional. RPC address of the history or matching host whose caches are invalidated, the frontend host serving the request by default",
+				},
+				cli.StringFlag{
+					Name:  FlagNameWithAlias,
+					Usage: "Optional. Only invalidate the caches whose name starts with the prefix, e.g. frontend/domain",
+				},
+				cli.StringFlag{
+					Name:  FlagMatch,
+					Usage: "Substring of the keys of the entries to invalidate, e.g. a domain name or a workflow ID",
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				AdminInvalidateCaches(c)
+			},
+		},
+	}
+}
+
+func newAdminAPIKeyCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:    "issue",
+			Aliases: []string{"i"},
+			Usage:   "Issue an API key scoped to domains and permissions, the key is only shown once",
+			Flags: append(getDBFlags(),
+				cli.StringFlag{
+					Name:  FlagNameWithAlias,
+					Usage: "Name of the caller the key is issued to",
+				},
+				cli.StringSliceFlag{
+					Name:  FlagAPIKeyDomains,
+					Usage: "Domains the key can access, * for all the domains. Can be specified multiple times",
+				},
+				cli.StringSliceFlag{
+					Name:  FlagAPIKeyPermissions,
+					Usage: "Permissions of the key: read, write or admin. Can be specified multiple times",
+				},
+				cli.DurationFlag{
+					Name:  FlagAPIKeyTTL,
+					Usage: "Optional. Duration after which the key expires, e.g. 720h, the key doesn't expire by default",
+				},
+			),
+			Action: func(c *cli.Context) {
+				AdminIssueAPIKey(c)
+			},
+		},
+		{
+			Name:    "revoke",
+			Aliases: []string{"r"},
+			Usage:   "Revoke an API key, the frontend hosts deny it once they refreshed their keys",
+			Flags: append(getDBFlags(),
+				cli.StringFlag{
+					Name:  FlagAPIKeyID,
+					Usage: "ID of the key",
+				},
+			),
+			Action: func(c *cli.Context) {
+				AdminRevokeAPIKey(c)
+			},
+		},
+		{
+			Name:    "list",
+			Aliases: []string{"l"},
+			Usage:   "List the issued API keys",
+			Flags:   append(getDBFlags(), getFormatFlag()),
+			Action: func(c *cli.Context) {
+				AdminListAPIKeys(c)
+			},
+		},
+	}
+}
+
+func newAdminReshardingCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:    "start",
+			Aliases: []string{"st"},
+			Usage:   "Start doubling the shard count, the services keep routing the workflows to the current shards until they are split",
+			Flags: append(getDBFlags(),
+				cli.IntFlag{
+					Name:  FlagNumberOfShards,
+					Usage: "NumberOfShards the services are configured with (see config for numHistoryShards)",
+				},
+			),
+			Action: func(c *cli.Context) {
+				AdminStartResharding(c)
+			},
+		},
+		{
+			Name:    "split",
+			Aliases: []string{"sp"},
+			Usage:   "Split a shard, or all the shards left, by pausing it while its workflows moving to the new shard are migrated",
+			Flags: append(getDBFlags(),
+				cli.IntFlag{
+					Name:  FlagShardID,
+					Usage: "Optional. ID of the shard to split, all the shards left are split by default",
+				},
+				cli.DurationFlag{
+					Name:  FlagPropagationDelay,
+					Usage: "How long to wait for the services to stop owning a paused shard, it must exceed system.reshardingRefreshInterval",
+					Value: 30 * time.Second,
+				},
+			),
+			Action: func(c *cli.Context) {
+				AdminSplitShard(c)
+			},
+		},
+		{
+			Name:    "describe",
+			Aliases: []string{"d"},
+			Usage:   "Describe the state of the resharding",
+			Flags:   getDBFlags(),
+			Action: func(c *cli.Context) {
+				AdminDescribeResharding(c)
+			},
+		},
+	}
+}
+
+func newAdminFeatureFlagCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:    "set",
+			Aliases: []string{"s"},
+			Usage:   "Roll out a feature flag to a percentage of domains and workflows, of the domain if the domain is set",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagFeatureFlag,
+					Usage: "Feature flag name",
+				},
+				cli.IntFlag{
+					Name:  FlagRolloutPercentage,
+					Usage: "Rollout percentage between 0 and 100",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminSetFeatureFlag(c)
+			},
+		},
+		{
+			Name:    "clear",
+			Aliases: []string{"c"},
+			Usage:   "Remove the rollout of a feature flag, of the domain if the domain is set",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagFeatureFlag,
+					Usage: "Feature flag name",
+				},
+			},
+			Action: func(c *cli.Context) {
+				AdminClearFeatureFlag(c)
+			},
+		},
+		{
+			Name:    "list",
+			Aliases: []string{"l"},
+			Usage:   "List the rollouts of feature flags",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagFeatureFlag,
+					Usage: "Optional feature flag name to list the rollouts of",
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				AdminListFeatureFlags(c)
+			},
+		},
 	}
 }
@@ -0,0 +1,116 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/uber/cadence/common/authorization"
+)
+
+// APIKeyRow is a row of the API keys table
+type APIKeyRow struct {
+	ID          string    `header:"ID"`
+	Name        string    `header:"Name"`
+	Domains     string    `header:"Domains"`
+	Permissions string    `header:"Permissions"`
+	CreatedTime time.Time `header:"Created"`
+	ExpiryTime  time.Time `header:"Expiry"`
+	RevokedTime time.Time `header:"Revoked"`
+}
+
+// AdminIssueAPIKey issues an API key in the config store database and prints it
+func AdminIssueAPIKey(c *cli.Context) {
+	name := getRequiredOption(c, FlagName)
+	domains := c.StringSlice(FlagAPIKeyDomains)
+	if len(domains) == 0 {
+		ErrorAndExit("Option "+FlagAPIKeyDomains+" is required", nil)
+	}
+	permissions := c.StringSlice(FlagAPIKeyPermissions)
+	if len(permissions) == 0 {
+		ErrorAndExit("Option "+FlagAPIKeyPermissions+" is required", nil)
+	}
+
+	configStoreManager := initializeConfigStoreManager(c)
+	defer configStoreManager.Close()
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	apiKey, key, err := authorization.IssueAPIKey(ctx, configStoreManager, name, domains, permissions, c.Duration(FlagAPIKeyTTL), time.Now())
+	if err != nil {
+		ErrorAndExit("Failed to issue API key", err)
+	}
+	fmt.Printf("Issued API key %v, it can't be shown again:\n%v\n", key.ID, apiKey)
+}
+
+// AdminRevokeAPIKey revokes an API key in the config store database
+func AdminRevokeAPIKey(c *cli.Context) {
+	id := getRequiredOption(c, FlagAPIKeyID)
+
+	configStoreManager := initializeConfigStoreManager(c)
+	defer configStoreManager.Close()
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	if err := authorization.RevokeAPIKey(ctx, configStoreManager, id, time.Now()); err != nil {
+		ErrorAndExit("Failed to revoke API key", err)
+	}
+	fmt.Printf("Revoked API key %v\n", id)
+}
+
+// AdminListAPIKeys lists the API keys of the config store database
+func AdminListAPIKeys(c *cli.Context) {
+	configStoreManager := initializeConfigStoreManager(c)
+	defer configStoreManager.Close()
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	resp, err := configStoreManager.FetchAPIKeys(ctx)
+	if err != nil {
+		ErrorAndExit("Failed to list API keys", err)
+	}
+
+	var rows []APIKeyRow
+	if resp.Snapshot != nil {
+		for _, key := range resp.Snapshot.Keys {
+			rows = append(rows, APIKeyRow{
+				ID:          key.ID,
+				Name:        key.Name,
+				Domains:     strings.Join(key.Domains, ","),
+				Permissions: strings.Join(key.Permissions, ","),
+				CreatedTime: key.CreatedTime,
+				ExpiryTime:  key.ExpiryTime,
+				RevokedTime: key.RevokedTime,
+			})
+		}
+	}
+	Render(c, rows, RenderOptions{
+		DefaultTemplate: templateTable,
+		Color:           true,
+		Border:          true,
+	})
+}
@@ -0,0 +1,159 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// CacheRow is used to render the stats of an in-memory cache
+	CacheRow struct {
+		Name    string  `header:"Cache"`
+		Size    int     `header:"Size"`
+		Hits    int64   `header:"Hits"`
+		Misses  int64   `header:"Misses"`
+		HitRate float64 `header:"Hit Rate"`
+	}
+
+	// CacheEntryRow is used to render an entry of an in-memory cache
+	CacheEntryRow struct {
+		Cache string        `header:"Cache"`
+		Key   string        `header:"Key"`
+		Hits  int64         `header:"Hits"`
+		Age   time.Duration `header:"Age"`
+		Idle  time.Duration `header:"Idle"`
+	}
+
+	// CacheInvalidatedRow is used to render the number of entries invalidated in an in-memory cache
+	CacheInvalidatedRow struct {
+		Name        string `header:"Cache"`
+		Invalidated int32  `header:"Invalidated"`
+	}
+)
+
+// AdminDescribeCaches shows the stats and the most read entries of the in-memory caches of a host,
+// as served on its pprof port
+func AdminDescribeCaches(c *cli.Context) {
+	query := url.Values{}
+	query.Set("top", strconv.Itoa(c.Int(FlagTop)))
+	if name := c.String(FlagName); name != "" {
+		query.Set("name", name)
+	}
+
+	var descriptions []*cache.Description
+	doCacheRequest(c, query, "describe caches", &descriptions)
+
+	caches := make([]CacheRow, 0, len(descriptions))
+	entries := []CacheEntryRow{}
+	for _, description := range descriptions {
+		caches = append(caches, CacheRow{
+			Name:    description.Name,
+			Size:    description.Size,
+			Hits:    description.Hits,
+			Misses:  description.Misses,
+			HitRate: description.HitRate,
+		})
+		for _, entry := range description.Entries {
+			entries = append(entries, CacheEntryRow{
+				Cache: description.Name,
+				Key:   entry.Key,
+				Hits:  entry.Hits,
+				Age:   entry.Age.Round(time.Second),
+				Idle:  entry.Idle.Round(time.Second),
+			})
+		}
+	}
+	Render(c, caches, RenderOptions{DefaultTemplate: templateTable, Color: true})
+	if len(entries) > 0 {
+		fmt.Println()
+		Render(c, entries, RenderOptions{DefaultTemplate: templateTable, Color: true})
+	}
+}
+
+// AdminInvalidateCaches removes the entries whose key contains the match from the in-memory caches of a host,
+// so that they are reloaded on their next access
+func AdminInvalidateCaches(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+
+	request := &types.InvalidateCachesRequest{
+		HostAddress:     c.String(FlagAddress),
+		CacheNamePrefix: c.String(FlagName),
+		Match:           getRequiredOption(c, FlagMatch),
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	response, err := adminClient.InvalidateCaches(ctx, request)
+	if err != nil {
+		ErrorAndExit("Failed to invalidate caches", err)
+	}
+
+	rows := make([]CacheInvalidatedRow, 0, len(response.GetInvalidated()))
+	for name, invalidated := range response.GetInvalidated() {
+		rows = append(rows, CacheInvalidatedRow{Name: name, Invalidated: invalidated})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Name < rows[j].Name
+	})
+	Render(c, rows, RenderOptions{DefaultTemplate: templateTable, Color: true})
+}
+
+func doCacheRequest(c *cli.Context, query url.Values, operation string, result interface{}) {
+	requestURL := url.URL{
+		Scheme:   "http",
+		Host:     c.String(FlagAddress),
+		Path:     cache.IntrospectionHandlerPath,
+		RawQuery: query.Encode(),
+	}
+	ctx, cancel := newContext(c)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		ErrorAndExit("Failed to create request", err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		ErrorAndExit(fmt.Sprintf("Failed to %v", operation), err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(response.Body)
+		ErrorAndExit(fmt.Sprintf("Failed to %v: %v %s", operation, response.Status, body), nil)
+	}
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		ErrorAndExit(fmt.Sprintf("Failed to decode the response to %v", operation), err)
+	}
+}
@@ -236,10 +236,11 @@ func AdminDeleteWorkflow(c *cli.Context) {
 			},
 			SkipErrors: skipError,
 		}
-		_, err := adminClient.DeleteWorkflow(ctx, request)
+		resp, err := adminClient.DeleteWorkflow(ctx, request)
 		if err != nil {
-			ErrorAndExit("Operation AdminMaintainCorruptWorkflow failed.", err)
+			ErrorAndExit("Operation AdminDeleteWorkflow failed.", err)
 		}
+		prettyPrintJSONObject(resp)
 		return
 	}
 
@@ -513,6 +514,159 @@ func AdminDescribeShardDistribution(c *cli.Context) {
 	Render(c, table, opts)
 }
 
+// ShardHostRow is used to render the number of shards owned by a history host
+type ShardHostRow struct {
+	Host      string  `header:"Host"`
+	NumShards int     `header:"Shards"`
+	Imbalance float64 `header:"Imbalance"`
+}
+
+// ShardOwnershipRow is used to render the owner of a shard
+type ShardOwnershipRow struct {
+	ShardID      int    `header:"ShardID"`
+	Owner        string `header:"Owner"`
+	AcquiredAt   string `header:"Acquired At"`
+	RecentSteals int    `header:"Recent Steals"`
+}
+
+// AdminDescribeShardOwnership describes the shard to host mapping, the shards owned by each host and
+// the acquisitions and steals of shards seen by a history host
+func AdminDescribeShardOwnership(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	ownership, err := adminClient.DescribeShardOwnership(ctx, &types.DescribeShardOwnershipRequest{
+		HostAddress: getRequiredOption(c, FlagAddress),
+	})
+	if err != nil {
+		ErrorAndExit("Failed to describe shard ownership", err)
+	}
+
+	fmt.Printf("Total Number of Shards: %d, described by host %v\n", ownership.NumberOfShards, ownership.Host)
+	hosts := make([]ShardHostRow, 0, len(ownership.Hosts))
+	for _, host := range ownership.Hosts {
+		hosts = append(hosts, ShardHostRow{Host: host.Host, NumShards: int(host.NumShards), Imbalance: host.Imbalance})
+	}
+	opts := RenderOptions{DefaultTemplate: templateTable, Color: true}
+	Render(c, hosts, opts)
+
+	// by default only the shards owned or recently stolen by the describing host are listed
+	shards := []ShardOwnershipRow{}
+	for _, s := range ownership.Shards {
+		if !c.Bool(FlagAll) && s.AcquiredAt == nil && s.RecentSteals == 0 {
+			continue
+		}
+		row := ShardOwnershipRow{ShardID: int(s.ShardID), Owner: s.Owner, RecentSteals: int(s.RecentSteals)}
+		if s.AcquiredAt != nil {
+			row.AcquiredAt = time.Unix(0, *s.AcquiredAt).Format(time.RFC3339)
+		}
+		shards = append(shards, row)
+	}
+	Render(c, shards, opts)
+}
+
+// ShardLatencyRow is used to render the latency of a persistence operation on a shard
+type ShardLatencyRow struct {
+	ShardID   int    `header:"ShardID"`
+	Operation string `header:"Operation"`
+	Count     int64  `header:"Count"`
+	P99       string `header:"P99"`
+}
+
+// AdminShardLatencyHeatmap lists the shards and persistence operations with the highest latency
+// over a recent window, as collected by a history host
+func AdminShardLatencyHeatmap(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+
+	request := &types.DescribePersistenceLatencyHeatmapRequest{
+		HostAddress: getRequiredOption(c, FlagAddress),
+	}
+	if window := c.String(FlagWindow); window != "" {
+		duration, err := time.ParseDuration(window)
+		if err != nil {
+			ErrorAndExit("Invalid window", err)
+		}
+		request.WindowInSeconds = int32(duration.Seconds())
+	}
+	ctx, cancel := newContext(c)
+	defer cancel()
+	heatmap, err := adminClient.DescribePersistenceLatencyHeatmap(ctx, request)
+	if err != nil {
+		ErrorAndExit("Failed to get persistence latency heatmap", err)
+	}
+
+	fmt.Printf("Persistence latency from %v to %v\n",
+		time.Unix(0, heatmap.GetStartTime()).Format(time.RFC3339),
+		time.Unix(0, heatmap.GetEndTime()).Format(time.RFC3339),
+	)
+	cells := heatmap.GetCells()
+	if top := c.Int(FlagTop); top > 0 && len(cells) > top {
+		cells = cells[:top]
+	}
+	rows := make([]ShardLatencyRow, 0, len(cells))
+	for _, cell := range cells {
+		rows = append(rows, ShardLatencyRow{
+			ShardID:   int(cell.ShardID),
+			Operation: cell.Operation,
+			Count:     cell.Count,
+			P99:       (time.Duration(cell.P99InMilliseconds) * time.Millisecond).String(),
+		})
+	}
+	Render(c, rows, RenderOptions{DefaultTemplate: templateTable, Color: true})
+}
+
+// DomainSLORow is used to render the state of an SLO of a domain
+type DomainSLORow struct {
+	Domain          string  `header:"Domain"`
+	Objective       string  `header:"Objective"`
+	Target          float64 `header:"Target"`
+	Requests        int64   `header:"Requests"`
+	BadRequests     int64   `header:"Bad Requests"`
+	BudgetRemaining float64 `header:"Budget Remaining"`
+	ShortBurnRate   float64 `header:"Burn Rate 5m"`
+	LongBurnRate    float64 `header:"Burn Rate 1h"`
+}
+
+// AdminDescribeDomainSLO shows the error budget and burn rates of the SLOs of domains over the last hour,
+// as tracked by the frontend host serving the request
+func AdminDescribeDomainSLO(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	response, err := adminClient.DescribeDomainSLO(ctx, &types.DescribeDomainSLORequest{
+		Domain: c.String(FlagDomain),
+	})
+	if err != nil {
+		ErrorAndExit("Failed to describe domain SLOs", err)
+	}
+
+	rows := []DomainSLORow{}
+	for _, domain := range response.GetDomains() {
+		if domain.Availability != nil {
+			rows = append(rows, newDomainSLORow(domain.Domain, "availability", domain.Availability))
+		}
+		if domain.Latency != nil {
+			rows = append(rows, newDomainSLORow(domain.Domain, "latency", domain.Latency))
+		}
+	}
+	Render(c, rows, RenderOptions{DefaultTemplate: templateTable, Color: true})
+}
+
+func newDomainSLORow(domain string, objective string, report *types.SLOObjective) DomainSLORow {
+	return DomainSLORow{
+		Domain:          domain,
+		Objective:       objective,
+		Target:          report.Target,
+		Requests:        report.Requests,
+		BadRequests:     report.BadRequests,
+		BudgetRemaining: report.BudgetRemaining,
+		ShortBurnRate:   report.ShortBurnRate,
+		LongBurnRate:    report.LongBurnRate,
+	}
+}
+
 // AdminDescribeHistoryHost describes history host
 func AdminDescribeHistoryHost(c *cli.Context) {
 	adminClient := cFactory.ServerAdminClient(c)
@@ -24,7 +24,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/uber/cadence/client/admin"
 	"github.com/uber/cadence/common/dynamicconfig"
 	"github.com/uber/cadence/common/types"
 
@@ -360,3 +363,226 @@ func parseInputFilterArray(inputFilters []string) ([]*types.DynamicConfigFilter,
 
 	return parsedFilters, nil
 }
+
+// DynamicConfigChangeRow is a row of the dynamic config change history
+type DynamicConfigChangeRow struct {
+	Version   int64     `header:"Version"`
+	Timestamp time.Time `header:"Time"`
+	Identity  string    `header:"Identity"`
+	Name      string    `header:"Name"`
+	OldValues string    `header:"Old Values"`
+	NewValues string    `header:"New Values"`
+}
+
+// AdminDynamicConfigHistory lists the change history of dynamic config values
+func AdminDynamicConfigHistory(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+
+	name := c.String(FlagDynamicConfigName)
+	pageSize := c.Int(FlagPageSize)
+
+	var rows []DynamicConfigChangeRow
+	iterateDynamicConfigChanges(c, adminClient, c.Int64(FlagDynamicConfigVersion), pageSize, func(change *types.DynamicConfigChange) bool {
+		if name != "" && change.Name != name {
+			return true
+		}
+		rows = append(rows, DynamicConfigChangeRow{
+			Version:   change.Version,
+			Timestamp: time.Unix(0, change.Timestamp),
+			Identity:  change.Identity,
+			Name:      change.Name,
+			OldValues: formatDynamicConfigValues(change.OldValues),
+			NewValues: formatDynamicConfigValues(change.NewValues),
+		})
+		return len(rows) < pageSize
+	})
+
+	Render(c, rows, RenderOptions{
+		DefaultTemplate: templateTable,
+		Color:           true,
+		Border:          true,
+	})
+}
+
+// AdminRollbackDynamicConfig sets the values of a dynamic config back to the values it had at the given version of the change history
+func AdminRollbackDynamicConfig(c *cli.Context) {
+	name := getRequiredOption(c, FlagDynamicConfigName)
+	version := c.Int64(FlagDynamicConfigVersion)
+	if version <= 0 {
+		ErrorAndExit(fmt.Sprintf("Option %s must be a positive version number", FlagDynamicConfigVersion), nil)
+	}
+
+	adminClient := cFactory.ServerAdminClient(c)
+
+	var target *types.DynamicConfigChange
+	iterateDynamicConfigChanges(c, adminClient, version, c.Int(FlagPageSize), func(change *types.DynamicConfigChange) bool {
+		if change.Name == name {
+			target = change
+		}
+		return target == nil
+	})
+	if target == nil {
+		ErrorAndExit(fmt.Sprintf("No change of %q is recorded at or before version %v", name, version), nil)
+	}
+
+	promptFn(fmt.Sprintf("Rolling back %q to %v as of version %v, changed by %q at %v. Continue? Y/N",
+		name, formatDynamicConfigValues(target.NewValues), target.Version, target.Identity, time.Unix(0, target.Timestamp)))
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	err := adminClient.UpdateDynamicConfig(ctx, &types.UpdateDynamicConfigRequest{
+		ConfigName:   name,
+		ConfigValues: target.NewValues,
+	})
+	if err != nil {
+		ErrorAndExit("Failed to roll back dynamic config", err)
+	}
+	fmt.Printf("Dynamic config %q rolled back to version %v\n", name, target.Version)
+}
+
+// iterateDynamicConfigChanges calls fn for the changes at or before maxVersion from the latest to the oldest,
+// until fn returns false or the history is exhausted
+func iterateDynamicConfigChanges(
+	c *cli.Context,
+	adminClient admin.Client,
+	maxVersion int64,
+	pageSize int,
+	fn func(*types.DynamicConfigChange) bool,
+) {
+	for {
+		ctx, cancel := newContext(c)
+		resp, err := adminClient.ListDynamicConfigChanges(ctx, &types.ListDynamicConfigChangesRequest{
+			MaxVersion: maxVersion,
+			PageSize:   int32(pageSize),
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit("Failed to list dynamic config changes", err)
+		}
+		for _, change := range resp.GetChanges() {
+			if !fn(change) {
+				return
+			}
+		}
+		if len(resp.GetChanges()) < pageSize {
+			return
+		}
+		maxVersion = resp.Changes[len(resp.Changes)-1].Version - 1
+		if maxVersion <= 0 {
+			return
+		}
+	}
+}
+
+func formatDynamicConfigValues(values []*types.DynamicConfigValue) string {
+	if len(values) == 0 {
+		return "<removed>"
+	}
+	cliValues := make([]*cliValue, 0, len(values))
+	for _, value := range values {
+		cliValue, err := convertToInputValue(value)
+		if err != nil {
+			return fmt.Sprintf("<invalid value: %v>", err)
+		}
+		cliValues = append(cliValues, cliValue)
+	}
+	data, err := json.Marshal(cliValues)
+	if err != nil {
+		return fmt.Sprintf("<invalid value: %v>", err)
+	}
+	return string(data)
+}
+
+// EffectiveConfigRow is a row of the effective dynamic config values of a host
+type EffectiveConfigRow struct {
+	Name         string `header:"Name"`
+	Value        string `header:"Value"`
+	DefaultValue string `header:"Default"`
+	Source       string `header:"Source"`
+}
+
+// AdminListEffectiveDynamicConfig lists the dynamic config values in effect for a set of filters,
+// as seen by the frontend host serving the request
+func AdminListEffectiveDynamicConfig(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+
+	filters := map[dynamicconfig.Filter]interface{}{}
+	if c.GlobalIsSet(FlagDomain) {
+		filters[dynamicconfig.DomainName] = c.GlobalString(FlagDomain)
+	}
+	for flag, filter := range map[string]dynamicconfig.Filter{
+		FlagTaskList:     dynamicconfig.TaskListName,
+		FlagWorkflowID:   dynamicconfig.WorkflowID,
+		FlagWorkflowType: dynamicconfig.WorkflowType,
+	} {
+		if c.IsSet(flag) {
+			filters[filter] = c.String(flag)
+		}
+	}
+	if c.IsSet(FlagShardID) {
+		filters[dynamicconfig.ShardID] = c.Int(FlagShardID)
+	}
+	if c.IsSet(FlagTaskListType) {
+		filters[dynamicconfig.TaskType] = int(strToTaskListType(c.String(FlagTaskListType)))
+	}
+
+	request := &types.ListEffectiveDynamicConfigRequest{
+		ConfigNamePrefix: c.String(FlagDynamicConfigName),
+	}
+	for filter, value := range filters {
+		dcFilter, err := convertFromInputFilter(&cliFilter{Name: filter.String(), Value: value})
+		if err != nil {
+			ErrorAndExit("Failed to encode filter", err)
+		}
+		request.Filters = append(request.Filters, dcFilter)
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	resp, err := adminClient.ListEffectiveDynamicConfig(ctx, request)
+	if err != nil {
+		ErrorAndExit("Failed to get effective dynamic config", err)
+	}
+
+	rows := make([]EffectiveConfigRow, 0, len(resp.GetValues()))
+	for _, value := range resp.GetValues() {
+		if c.Bool(FlagDynamicConfigOverridden) && value.IsDefault {
+			continue
+		}
+		rows = append(rows, EffectiveConfigRow{
+			Name:         value.Name,
+			Value:        formatDataBlob(value.Value),
+			DefaultValue: formatDataBlob(value.DefaultValue),
+			Source:       effectiveValueSource(value),
+		})
+	}
+	Render(c, rows, RenderOptions{
+		DefaultTemplate: templateTable,
+		Color:           true,
+	})
+}
+
+func formatDataBlob(blob *types.DataBlob) string {
+	if blob == nil {
+		return ""
+	}
+	return string(blob.Data)
+}
+
+func effectiveValueSource(value *types.EffectiveDynamicConfigValue) string {
+	switch {
+	case value.Error != "":
+		return "default (" + value.Error + ")"
+	case value.IsDefault:
+		return "default"
+	case value.MatchedFilters == nil:
+		return "override"
+	case len(value.MatchedFilters) == 0:
+		return "override (no filters)"
+	}
+	filters := make([]string, 0, len(value.MatchedFilters))
+	for _, filter := range value.MatchedFilters {
+		filters = append(filters, fmt.Sprintf("%v=%v", filter.Name, formatDataBlob(filter.Value)))
+	}
+	return "override (" + strings.Join(filters, ", ") + ")"
+}
@@ -0,0 +1,99 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/diagnostics"
+)
+
+// AdminCollectDiagnostics enables profiles and verbose logging on a host for a bounded duration
+// and downloads the collected artifacts, as served by the host on its pprof port
+func AdminCollectDiagnostics(c *cli.Context) {
+	seconds := c.Int(FlagDiagnosticsSeconds)
+	if seconds <= 0 || time.Duration(seconds)*time.Second > diagnostics.MaxDuration {
+		ErrorAndExit(fmt.Sprintf("Option %v must be between 1 and %v", FlagDiagnosticsSeconds, int(diagnostics.MaxDuration.Seconds())), nil)
+	}
+	outputFile := c.String(FlagOutputFilename)
+	if outputFile == "" {
+		outputFile = fmt.Sprintf("diagnostics-%v.zip", time.Now().Unix())
+	}
+
+	query := url.Values{}
+	query.Set("seconds", strconv.Itoa(seconds))
+	if c.IsSet(FlagDiagnosticsProfiles) {
+		query.Set("profiles", c.String(FlagDiagnosticsProfiles))
+	}
+	query.Set("verbose", strconv.FormatBool(c.Bool(FlagVerboseLogging)))
+	requestURL := url.URL{
+		Scheme:   "http",
+		Host:     c.String(FlagAddress),
+		Path:     diagnostics.HandlerPath,
+		RawQuery: query.Encode(),
+	}
+
+	// the request is served once the collection is done
+	ctx := populateContextFromCLIContext(context.Background(), c)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(seconds)*time.Second+defaultContextTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		ErrorAndExit("Failed to create request", err)
+	}
+	if token, ok := ctx.Value(CtxKeyJWT).(string); ok && token != "" {
+		request.Header.Set(common.AuthorizationTokenHeaderName, token)
+	}
+
+	fmt.Printf("Collecting diagnostics from %v for %v seconds\n", requestURL.Host, seconds)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		ErrorAndExit("Failed to collect diagnostics", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(response.Body)
+		ErrorAndExit(fmt.Sprintf("Failed to collect diagnostics: %v %s", response.Status, body), nil)
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		ErrorAndExit("Failed to create output file", err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, response.Body); err != nil {
+		ErrorAndExit("Failed to write output file", err)
+	}
+	fmt.Printf("Diagnostics written to %v\n", outputFile)
+}
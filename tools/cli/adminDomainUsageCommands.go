@@ -0,0 +1,84 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"sort"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/uber/cadence/common/types"
+)
+
+// DomainUsageRow is a row of the domain usage table
+type DomainUsageRow struct {
+	Domain                string `header:"Domain"`
+	Actions               int64  `header:"Actions"`
+	PersistenceReadUnits  int64  `header:"Persistence Reads"`
+	PersistenceWriteUnits int64  `header:"Persistence Writes"`
+	PayloadBytes          int64  `header:"Payload Bytes"`
+	TaskDispatches        int64  `header:"Task Dispatches"`
+}
+
+// AdminDomainUsage shows the resources consumed by domains in a time range
+func AdminDomainUsage(c *cli.Context) {
+	start := parseTime(c.String(FlagEarliestTime), time.Now().Add(-24*time.Hour).UnixNano())
+	end := parseTime(c.String(FlagLatestTime), time.Now().UnixNano())
+	if end <= start {
+		ErrorAndExit("Option "+FlagLatestTime+" must be after "+FlagEarliestTime, nil)
+	}
+
+	adminClient := cFactory.ServerAdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+	resp, err := adminClient.GetDomainUsage(ctx, &types.AdminGetDomainUsageRequest{
+		Domain:    c.String(FlagDomain),
+		StartTime: start,
+		EndTime:   end,
+	})
+	if err != nil {
+		ErrorAndExit("Failed to get domain usage", err)
+	}
+
+	var rows []DomainUsageRow
+	for name, usage := range resp.GetDomains() {
+		rows = append(rows, DomainUsageRow{
+			Domain:                name,
+			Actions:               usage.Actions,
+			PersistenceReadUnits:  usage.PersistenceReadUnits,
+			PersistenceWriteUnits: usage.PersistenceWriteUnits,
+			PayloadBytes:          usage.PayloadBytes,
+			TaskDispatches:        usage.TaskDispatches,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Domain < rows[j].Domain
+	})
+
+	Render(c, rows, RenderOptions{
+		DefaultTemplate: templateTable,
+		Color:           true,
+		Border:          true,
+	})
+}
@@ -0,0 +1,142 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/urfave/cli"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// FeatureFlagRow is a row of the feature flag rollout listing
+	FeatureFlagRow struct {
+		Flag       string `header:"Flag"`
+		Domain     string `header:"Domain"`
+		Percentage string `header:"Rollout Percentage"`
+	}
+)
+
+const allDomains = "*"
+
+// AdminSetFeatureFlag sets the rollout percentage of a feature flag, for a domain if the domain is set
+// and for all domains without a domain specific rollout otherwise
+func AdminSetFeatureFlag(c *cli.Context) {
+	flag := getRequiredOption(c, FlagFeatureFlag)
+	percentage := c.Int(FlagRolloutPercentage)
+	if !c.IsSet(FlagRolloutPercentage) || percentage < 0 || percentage > 100 {
+		ErrorAndExit(fmt.Sprintf("Option %s must be set to a value between 0 and 100.", FlagRolloutPercentage), nil)
+	}
+	newValue, err := convertFromInputValue(&cliValue{Value: percentage, Filters: getFeatureFlagFilters(c, flag)})
+	if err != nil {
+		ErrorAndExit("Failed to encode feature flag rollout.", err)
+	}
+
+	name := dynamicconfig.FeatureFlagRolloutPercentage.String()
+	values, _ := removeDynamicConfigValue(getDynamicConfigValues(c, name), newValue.Filters)
+	updateDynamicConfigValues(c, name, append(values, newValue))
+	fmt.Printf("Feature flag %q rolled out to %v%% of %s\n", flag, percentage, featureFlagScope(c))
+}
+
+// AdminClearFeatureFlag removes the rollout of a feature flag, for a domain if the domain is set
+func AdminClearFeatureFlag(c *cli.Context) {
+	flag := getRequiredOption(c, FlagFeatureFlag)
+	filters, err := convertFromInputValue(&cliValue{Filters: getFeatureFlagFilters(c, flag)})
+	if err != nil {
+		ErrorAndExit("Failed to encode feature flag filters.", err)
+	}
+
+	name := dynamicconfig.FeatureFlagRolloutPercentage.String()
+	values, removed := removeDynamicConfigValue(getDynamicConfigValues(c, name), filters.Filters)
+	if !removed {
+		fmt.Printf("No rollout of feature flag %q found for %s\n", flag, featureFlagScope(c))
+		return
+	}
+	updateDynamicConfigValues(c, name, values)
+	fmt.Printf("Feature flag %q cleared for %s\n", flag, featureFlagScope(c))
+}
+
+// AdminListFeatureFlags lists the rollouts of feature flags
+func AdminListFeatureFlags(c *cli.Context) {
+	name := dynamicconfig.FeatureFlagRolloutPercentage.String()
+	cliEntry, err := convertToInputEntry(&types.DynamicConfigEntry{
+		Name:   name,
+		Values: getDynamicConfigValues(c, name),
+	})
+	if err != nil {
+		ErrorAndExit("Failed to decode feature flag rollouts.", err)
+	}
+
+	rows := []FeatureFlagRow{}
+	for _, value := range cliEntry.Values {
+		filters := make(map[string]interface{}, len(value.Filters))
+		for _, filter := range value.Filters {
+			filters[filter.Name] = filter.Value
+		}
+		flag, ok := filters[dynamicconfig.FeatureFlagName.String()].(string)
+		if !ok || (c.IsSet(FlagFeatureFlag) && flag != c.String(FlagFeatureFlag)) {
+			continue
+		}
+		domain, ok := filters[dynamicconfig.DomainName.String()].(string)
+		if !ok {
+			domain = allDomains
+		}
+		rows = append(rows, FeatureFlagRow{
+			Flag:       flag,
+			Domain:     domain,
+			Percentage: fmt.Sprint(value.Value),
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Flag != rows[j].Flag {
+			return rows[i].Flag < rows[j].Flag
+		}
+		return rows[i].Domain < rows[j].Domain
+	})
+
+	Render(c, rows, RenderOptions{
+		DefaultTemplate: templateTable,
+		Color:           true,
+	})
+}
+
+func getFeatureFlagFilters(c *cli.Context, flag string) []*cliFilter {
+	filters := []*cliFilter{
+		{Name: dynamicconfig.FeatureFlagName.String(), Value: flag},
+	}
+	if c.GlobalIsSet(FlagDomain) {
+		filters = append(filters, &cliFilter{Name: dynamicconfig.DomainName.String(), Value: c.GlobalString(FlagDomain)})
+	}
+	return filters
+}
+
+func featureFlagScope(c *cli.Context) string {
+	if c.GlobalIsSet(FlagDomain) {
+		return fmt.Sprintf("domain %q", c.GlobalString(FlagDomain))
+	}
+	return "all domains"
+}
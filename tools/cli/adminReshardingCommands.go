@@ -0,0 +1,139 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/urfave/cli"
+
+	"github.com/uber/cadence/common/clock"
+	"github.com/uber/cadence/common/log"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/resharding"
+)
+
+// AdminStartResharding starts doubling the history shard count of the cluster
+func AdminStartResharding(c *cli.Context) {
+	numberOfShards := getRequiredIntOption(c, FlagNumberOfShards)
+
+	resharder := newResharder(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+	snapshot, err := resharder.StartResharding(ctx, numberOfShards)
+	if err != nil {
+		ErrorAndExit("Failed to start resharding", err)
+	}
+	fmt.Printf("Started resharding from %v to %v shards, split the shards then configure the services with %v shards\n",
+		snapshot.FromShardCount, snapshot.ToShardCount, snapshot.ToShardCount)
+}
+
+// AdminSplitShard splits a history shard, or all the shards which aren't split yet
+func AdminSplitShard(c *cli.Context) {
+	resharder := newResharder(c)
+
+	var shardIDs []int
+	if c.IsSet(FlagShardID) {
+		shardIDs = []int{c.Int(FlagShardID)}
+	} else {
+		ctx, cancel := newContext(c)
+		snapshot, err := resharder.DescribeResharding(ctx)
+		cancel()
+		if err != nil {
+			ErrorAndExit("Failed to describe resharding", err)
+		}
+		shardIDs = remainingShards(snapshot)
+	}
+
+	for _, shardID := range shardIDs {
+		ctx, cancel := newIndefiniteContext(c)
+		result, err := resharder.SplitShard(ctx, shardID)
+		cancel()
+		if err != nil {
+			ErrorAndExit(fmt.Sprintf("Failed to split shard %v, the split can be retried", shardID), err)
+		}
+		fmt.Printf("Split shard %v to shard %v: moved %v executions, %v transfer tasks and %v timer tasks, paused for %v\n",
+			result.SourceShardID, result.TargetShardID, result.Executions, result.TransferTasks, result.TimerTasks, result.PausedFor)
+	}
+}
+
+// AdminDescribeResharding describes the state of the resharding
+func AdminDescribeResharding(c *cli.Context) {
+	resharder := newResharder(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+	snapshot, err := resharder.DescribeResharding(ctx)
+	if err != nil {
+		ErrorAndExit("Failed to describe resharding", err)
+	}
+	if snapshot == nil {
+		fmt.Println("The shard count was never changed")
+		return
+	}
+	prettyPrintJSONObject(snapshot)
+	if remaining := len(remainingShards(snapshot)); remaining > 0 {
+		fmt.Printf("%v shards left to split\n", remaining)
+	} else {
+		fmt.Printf("All the shards are split, the services must be configured with %v shards\n", snapshot.ToShardCount)
+	}
+}
+
+func newResharder(c *cli.Context) *resharding.Resharder {
+	return resharding.NewResharder(
+		initializeConfigStoreManager(c),
+		initializeShardManager(c),
+		initializeHistoryManager(c),
+		initializeDomainManager(c),
+		func(shardID int) (persistence.ExecutionManager, error) {
+			return initializeExecutionStore(c, shardID), nil
+		},
+		c.Duration(FlagPropagationDelay),
+		clock.NewRealTimeSource(),
+		log.NewNoop(),
+	)
+}
+
+// remainingShards returns the shards which aren't split yet, the paused ones first so that a failed split is resumed
+func remainingShards(snapshot *persistence.ReshardingSnapshot) []int {
+	if snapshot == nil {
+		return nil
+	}
+	shardIDs := append([]int{}, snapshot.PausedShards...)
+	sort.Ints(shardIDs)
+	for shardID := 0; shardID < snapshot.FromShardCount; shardID++ {
+		if !containsShard(snapshot.SplitShards, shardID) && !containsShard(snapshot.PausedShards, shardID) {
+			shardIDs = append(shardIDs, shardID)
+		}
+	}
+	return shardIDs
+}
+
+func containsShard(shardIDs []int, shardID int) bool {
+	for _, id := range shardIDs {
+		if id == shardID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	// ShardPendingTasksRow is a row of the pending tasks report
+	ShardPendingTasksRow struct {
+		ShardID     int    `header:"Shard ID"`
+		Owner       string `header:"Owner"`
+		Transfer    string `header:"Transfer"`
+		Timer       string `header:"Timer"`
+		Replication string `header:"Replication"`
+		Total       int    `header:"Total"`
+	}
+
+	shardPendingTasks struct {
+		shardID     int
+		owner       string
+		transfer    int
+		timer       int
+		replication int
+	}
+
+	// pendingTaskCounter reads one page of a shard task queue and returns the number of tasks in it
+	pendingTaskCounter func(ctx context.Context, token []byte) (count int, nextToken []byte, err error)
+)
+
+// AdminShardPendingTasks reports the number of pending transfer, timer and replication tasks
+// for a range of shards, sorted by the most backlogged shards first
+func AdminShardPendingTasks(c *cli.Context) {
+	lowerShardBound := c.Int(FlagLowerShardBound)
+	upperShardBound := c.Int(FlagUpperShardBound)
+	if lowerShardBound > upperShardBound {
+		ErrorAndExit(fmt.Sprintf("%v must not be greater than %v", FlagLowerShardBound, FlagUpperShardBound), nil)
+	}
+	batchSize := c.Int(FlagBatchSize)
+	maxCount := c.Int(FlagMaxTaskCount)
+	top := c.Int(FlagTop)
+
+	shardManager := initializeShardManager(c)
+	defer shardManager.Close()
+
+	results := make([]shardPendingTasks, 0, upperShardBound-lowerShardBound+1)
+	for shardID := lowerShardBound; shardID <= upperShardBound; shardID++ {
+		results = append(results, countShardPendingTasks(c, shardManager, shardID, batchSize, maxCount))
+	}
+
+	Render(c, newShardPendingTasksTable(results, top, maxCount), RenderOptions{
+		DefaultTemplate: templateTable,
+		Color:           true,
+	})
+}
+
+func countShardPendingTasks(
+	c *cli.Context,
+	shardManager persistence.ShardManager,
+	shardID int,
+	batchSize int,
+	maxCount int,
+) shardPendingTasks {
+	ctx, cancel := newContext(c)
+	resp, err := shardManager.GetShard(ctx, &persistence.GetShardRequest{ShardID: shardID})
+	cancel()
+	if err != nil {
+		ErrorAndExit(fmt.Sprintf("Failed to get shard %v.", shardID), err)
+	}
+	shardInfo := resp.ShardInfo
+
+	executionManager := initializeExecutionStore(c, shardID)
+	defer executionManager.Close()
+
+	transferCounter := func(ctx context.Context, token []byte) (int, []byte, error) {
+		resp, err := executionManager.GetTransferTasks(ctx, &persistence.GetTransferTasksRequest{
+			ReadLevel:     shardInfo.TransferAckLevel,
+			MaxReadLevel:  math.MaxInt64,
+			BatchSize:     batchSize,
+			NextPageToken: token,
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+		return len(resp.Tasks), resp.NextPageToken, nil
+	}
+	timerCounter := func(ctx context.Context, token []byte) (int, []byte, error) {
+		resp, err := executionManager.GetTimerIndexTasks(ctx, &persistence.GetTimerIndexTasksRequest{
+			MinTimestamp:  shardInfo.TimerAckLevel,
+			MaxTimestamp:  time.Now(),
+			BatchSize:     batchSize,
+			NextPageToken: token,
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+		return len(resp.Timers), resp.NextPageToken, nil
+	}
+	replicationCounter := func(ctx context.Context, token []byte) (int, []byte, error) {
+		resp, err := executionManager.GetReplicationTasks(ctx, &persistence.GetReplicationTasksRequest{
+			ReadLevel:     shardInfo.ReplicationAckLevel,
+			MaxReadLevel:  math.MaxInt64,
+			BatchSize:     batchSize,
+			NextPageToken: token,
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+		return len(resp.Tasks), resp.NextPageToken, nil
+	}
+
+	return shardPendingTasks{
+		shardID:     shardID,
+		owner:       shardInfo.Owner,
+		transfer:    countPendingTasks(c, shardID, "transfer", transferCounter, maxCount),
+		timer:       countPendingTasks(c, shardID, "timer", timerCounter, maxCount),
+		replication: countPendingTasks(c, shardID, "replication", replicationCounter, maxCount),
+	}
+}
+
+func countPendingTasks(
+	c *cli.Context,
+	shardID int,
+	queueName string,
+	counter pendingTaskCounter,
+	maxCount int,
+) int {
+	throttleRetry := backoff.NewThrottleRetry(
+		backoff.WithRetryPolicy(common.CreatePersistenceRetryPolicy()),
+		backoff.WithRetryableError(func(err error) bool {
+			return persistence.IsTransientError(err) || common.IsContextTimeoutError(err)
+		}),
+	)
+
+	total := 0
+	var token []byte
+	for {
+		var count int
+		op := func() error {
+			ctx, cancel := newContext(c)
+			defer cancel()
+
+			var err error
+			count, token, err = counter(ctx, token)
+			return err
+		}
+		if err := throttleRetry.Do(context.Background(), op); err != nil {
+			ErrorAndExit(fmt.Sprintf("Failed to read %v tasks for shard %v.", queueName, shardID), err)
+		}
+
+		total += count
+		if len(token) == 0 || (maxCount > 0 && total >= maxCount) {
+			return total
+		}
+	}
+}
+
+func newShardPendingTasksTable(results []shardPendingTasks, top int, maxCount int) []ShardPendingTasksRow {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].total() > results[j].total()
+	})
+	if top > 0 && len(results) > top {
+		results = results[:top]
+	}
+
+	formatCount := func(count int) string {
+		if maxCount > 0 && count >= maxCount {
+			return strconv.Itoa(count) + "+"
+		}
+		return strconv.Itoa(count)
+	}
+
+	table := make([]ShardPendingTasksRow, 0, len(results))
+	for _, result := range results {
+		table = append(table, ShardPendingTasksRow{
+			ShardID:     result.shardID,
+			Owner:       result.owner,
+			Transfer:    formatCount(result.transfer),
+			Timer:       formatCount(result.timer),
+			Replication: formatCount(result.replication),
+			Total:       result.total(),
+		})
+	}
+	return table
+}
+
+func (s shardPendingTasks) total() int {
+	return s.transfer + s.timer + s.replication
+}
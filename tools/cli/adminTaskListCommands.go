@@ -32,9 +32,10 @@ import (
 
 type (
 	TaskListRow struct {
-		Name        string `header:"Task List Name"`
-		Type        string `header:"Type"`
-		PollerCount int    `header:"Poller Count"`
+		Name             string `header:"Task List Name"`
+		Type             string `header:"Type"`
+		PollerCount      int    `header:"Poller Count"`
+		BacklogCountHint int64  `header:"Backlog Count Hint"`
 	}
 	TaskListStatusRow struct {
 		ReadLevel int64 `header:"Read Level"`
@@ -43,6 +44,10 @@ type (
 		StartID   int64 `header:"Lease Start TaskID"`
 		EndID     int64 `header:"Lease End TaskID"`
 	}
+	DrainPartitionRow struct {
+		Partition string `header:"Partition"`
+		Drained   bool   `header:"Drained"`
+	}
 )
 
 // AdminDescribeTaskList displays poller and status information of task list.
@@ -102,14 +107,129 @@ func AdminListTaskList(c *cli.Context) {
 	fmt.Println("Task Lists for domain " + domain + ":")
 	table := []TaskListRow{}
 	for name, taskList := range response.GetDecisionTaskListMap() {
-		table = append(table, TaskListRow{name, "Decision", len(taskList.GetPollers())})
+		table = append(table, TaskListRow{name, "Decision", len(taskList.GetPollers()), taskList.GetTaskListStatus().GetBacklogCountHint()})
 	}
 	for name, taskList := range response.GetActivityTaskListMap() {
-		table = append(table, TaskListRow{name, "Activity", len(taskList.GetPollers())})
+		table = append(table, TaskListRow{name, "Activity", len(taskList.GetPollers()), taskList.GetTaskListStatus().GetBacklogCountHint()})
 	}
 	RenderTable(os.Stdout, table, RenderOptions{Color: true, Border: true})
 }
 
+// AdminUnloadTaskList force-unloads a task list from the matching host owning it, so that a wedged task list is
+// recovered without restarting the host
+func AdminUnloadTaskList(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := types.TaskListTypeDecision
+	if strings.ToLower(c.String(FlagTaskListType)) == "activity" {
+		taskListType = types.TaskListTypeActivity
+	}
+	blockReloadSeconds := c.Int(FlagBlockReloadSeconds)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	err := adminClient.UnloadTaskList(ctx, &types.AdminUnloadTaskListRequest{
+		Domain:             domain,
+		TaskList:           &types.TaskList{Name: taskList},
+		TaskListType:       &taskListType,
+		BlockReloadSeconds: int32(blockReloadSeconds),
+	})
+	if err != nil {
+		ErrorAndExit("Operation UnloadTaskList failed.", err)
+	}
+
+	fmt.Printf("Unloaded %v tasklist %v of domain %v", strings.ToLower(taskListType.String()), taskList, domain)
+	if blockReloadSeconds > 0 {
+		fmt.Printf(", reload blocked for %v seconds", blockReloadSeconds)
+	}
+	fmt.Println()
+}
+
+// AdminPauseTaskList stops the dispatch of the tasks of a task list partition. The pollers receive empty responses
+// and the tasks are kept in the backlog until it is resumed.
+func AdminPauseTaskList(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := types.TaskListTypeDecision
+	if strings.ToLower(c.String(FlagTaskListType)) == "activity" {
+		taskListType = types.TaskListTypeActivity
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	err := adminClient.PauseTaskList(ctx, &types.AdminPauseTaskListRequest{
+		Domain:       domain,
+		TaskList:     &types.TaskList{Name: taskList},
+		TaskListType: &taskListType,
+	})
+	if err != nil {
+		ErrorAndExit("Operation PauseTaskList failed.", err)
+	}
+	fmt.Printf("Paused %v tasklist %v of domain %v\n", strings.ToLower(taskListType.String()), taskList, domain)
+}
+
+// AdminResumeTaskList resumes the dispatch of the tasks of a paused task list partition
+func AdminResumeTaskList(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := types.TaskListTypeDecision
+	if strings.ToLower(c.String(FlagTaskListType)) == "activity" {
+		taskListType = types.TaskListTypeActivity
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	err := adminClient.ResumeTaskList(ctx, &types.AdminResumeTaskListRequest{
+		Domain:       domain,
+		TaskList:     &types.TaskList{Name: taskList},
+		TaskListType: &taskListType,
+	})
+	if err != nil {
+		ErrorAndExit("Operation ResumeTaskList failed.", err)
+	}
+	fmt.Printf("Resumed %v tasklist %v of domain %v\n", strings.ToLower(taskListType.String()), taskList, domain)
+}
+
+// AdminDrainTaskListStatus reports whether a task list in drain mode has no task left in any of its partitions
+func AdminDrainTaskListStatus(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := types.TaskListTypeDecision
+	if strings.ToLower(c.String(FlagTaskListType)) == "activity" {
+		taskListType = types.TaskListTypeActivity
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	status, err := adminClient.GetTaskListDrainStatus(ctx, &types.AdminGetTaskListDrainStatusRequest{
+		Domain:       domain,
+		TaskList:     &types.TaskList{Name: taskList},
+		TaskListType: &taskListType,
+	})
+	if err != nil {
+		ErrorAndExit("Operation GetTaskListDrainStatus failed.", err)
+	}
+
+	typeName := strings.ToLower(taskListType.String())
+	switch {
+	case status.GetDrained():
+		fmt.Printf("%v tasklist %v of domain %v is drained\n", typeName, taskList, domain)
+	case status.GetDraining():
+		fmt.Printf("%v tasklist %v of domain %v is draining\n", typeName, taskList, domain)
+	default:
+		fmt.Printf("%v tasklist %v of domain %v is not in drain mode\n", typeName, taskList, domain)
+	}
+	table := []DrainPartitionRow{}
+	for _, partition := range status.GetPartitions() {
+		table = append(table, DrainPartitionRow{Partition: partition.GetTaskList(), Drained: partition.GetDrained()})
+	}
+	RenderTable(os.Stdout, table, RenderOptions{Color: true})
+}
+
 func printTaskListStatus(taskListStatus *types.TaskListStatus) {
 	table := []TaskListStatusRow{{
 		ReadLevel: taskListStatus.GetReadLevel(),
@@ -0,0 +1,281 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	"github.com/uber/cadence/common/dynamicconfig"
+	"github.com/uber/cadence/common/types"
+)
+
+type (
+	// TaskListConfigRow is a row of the task list config overrides listing
+	TaskListConfigRow struct {
+		Name  string `header:"Name"`
+		Type  string `header:"Type"`
+		Value string `header:"Value"`
+	}
+)
+
+// taskListConfigKeys are the matching knobs which can be overridden per task list at runtime
+var taskListConfigKeys = []dynamicconfig.Key{
+	dynamicconfig.MatchingNumTasklistReadPartitions,
+	dynamicconfig.MatchingNumTasklistWritePartitions,
+	dynamicconfig.MatchingForwarderMaxOutstandingPolls,
+	dynamicconfig.MatchingForwarderMaxOutstandingTasks,
+	dynamicconfig.MatchingForwarderMaxRatePerSecond,
+	dynamicconfig.MatchingForwarderMaxChildrenPerNode,
+	dynamicconfig.MatchingTaskDispatchRPS,
+	dynamicconfig.MatchingTaskListDrainMode,
+}
+
+// AdminSetTaskListConfig sets a dynamic config override for a task list.
+// The override is stored in the dynamic config store, other values of the same config are kept.
+func AdminSetTaskListConfig(c *cli.Context) {
+	name := getTaskListConfigName(c)
+	filters := getTaskListConfigFilters(c)
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(getRequiredOption(c, FlagDynamicConfigValue)), &value); err != nil {
+		ErrorAndExit("Failed to parse config value as json.", err)
+	}
+	setTaskListConfig(c, name, filters, value)
+	fmt.Printf("Task list config %q set to %v\n", name, value)
+}
+
+// AdminClearTaskListConfig removes the dynamic config override of a task list
+func AdminClearTaskListConfig(c *cli.Context) {
+	name := getTaskListConfigName(c)
+	if !clearTaskListConfig(c, name, getTaskListConfigFilters(c)) {
+		fmt.Printf("No override of task list config %q found\n", name)
+		return
+	}
+	fmt.Printf("Task list config %q cleared\n", name)
+}
+
+// AdminDrainTaskList puts a task list in drain mode, matching rejects its new tasks while pollers consume its backlog
+func AdminDrainTaskList(c *cli.Context) {
+	setTaskListConfig(c, dynamicconfig.MatchingTaskListDrainMode.String(), getTaskListConfigFilters(c), true)
+	fmt.Println("Task list is draining, new tasks are rejected")
+}
+
+// AdminUndrainTaskList takes a task list out of drain mode
+func AdminUndrainTaskList(c *cli.Context) {
+	if !clearTaskListConfig(c, dynamicconfig.MatchingTaskListDrainMode.String(), getTaskListConfigFilters(c)) {
+		fmt.Println("Task list is not in drain mode")
+		return
+	}
+	fmt.Println("Task list is out of drain mode, new tasks are accepted")
+}
+
+func setTaskListConfig(c *cli.Context, name string, filters []*cliFilter, value interface{}) {
+	newValue, err := convertFromInputValue(&cliValue{Value: value, Filters: filters})
+	if err != nil {
+		ErrorAndExit("Failed to encode config value.", err)
+	}
+
+	values, _ := removeDynamicConfigValue(getDynamicConfigValues(c, name), newValue.Filters)
+	updateDynamicConfigValues(c, name, append(values, newValue))
+}
+
+func clearTaskListConfig(c *cli.Context, name string, filters []*cliFilter) bool {
+	encoded, err := convertFromInputValue(&cliValue{Filters: filters})
+	if err != nil {
+		ErrorAndExit("Failed to encode config filters.", err)
+	}
+
+	values, removed := removeDynamicConfigValue(getDynamicConfigValues(c, name), encoded.Filters)
+	if removed {
+		updateDynamicConfigValues(c, name, values)
+	}
+	return removed
+}
+
+// AdminListTaskListConfig lists the dynamic config overrides of a task list
+func AdminListTaskListConfig(c *cli.Context) {
+	adminClient := cFactory.ServerAdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	resp, err := adminClient.ListDynamicConfig(ctx, &types.ListDynamicConfigRequest{})
+	if err != nil {
+		ErrorAndExit("Failed to list dynamic config values.", err)
+	}
+
+	rows := []TaskListConfigRow{}
+	for _, entry := range resp.Entries {
+		if !isTaskListConfigKey(entry.Name) {
+			continue
+		}
+		cliEntry, err := convertToInputEntry(entry)
+		if err != nil {
+			ErrorAndExit(fmt.Sprintf("Failed to decode values of %q.", entry.Name), err)
+		}
+		for _, value := range cliEntry.Values {
+			filters := make(map[string]interface{}, len(value.Filters))
+			for _, filter := range value.Filters {
+				filters[filter.Name] = filter.Value
+			}
+			if filters[dynamicconfig.DomainName.String()] != domain || filters[dynamicconfig.TaskListName.String()] != taskList {
+				continue
+			}
+			taskType := "all"
+			if t, ok := filters[dynamicconfig.TaskType.String()]; ok {
+				taskType = types.TaskListType(int32(t.(float64))).String()
+			}
+			rows = append(rows, TaskListConfigRow{
+				Name:  entry.Name,
+				Type:  taskType,
+				Value: fmt.Sprint(value.Value),
+			})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Name != rows[j].Name {
+			return rows[i].Name < rows[j].Name
+		}
+		return rows[i].Type < rows[j].Type
+	})
+
+	Render(c, rows, RenderOptions{
+		DefaultTemplate: templateTable,
+		Color:           true,
+	})
+}
+
+func getTaskListConfigName(c *cli.Context) string {
+	name := getRequiredOption(c, FlagDynamicConfigName)
+	if !isTaskListConfigKey(name) {
+		keyNames := make([]string, 0, len(taskListConfigKeys))
+		for _, key := range taskListConfigKeys {
+			keyNames = append(keyNames, key.String())
+		}
+		ErrorAndExit(fmt.Sprintf("%q cannot be overridden per task list, supported configs: %v", name, strings.Join(keyNames, ", ")), nil)
+	}
+	return name
+}
+
+func isTaskListConfigKey(name string) bool {
+	for _, key := range taskListConfigKeys {
+		if key.String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func getTaskListConfigFilters(c *cli.Context) []*cliFilter {
+	filters := []*cliFilter{
+		{Name: dynamicconfig.DomainName.String(), Value: getRequiredGlobalOption(c, FlagDomain)},
+		{Name: dynamicconfig.TaskListName.String(), Value: getRequiredOption(c, FlagTaskList)},
+	}
+	if c.IsSet(FlagTaskListType) {
+		taskListType := types.TaskListTypeDecision
+		switch strings.ToLower(c.String(FlagTaskListType)) {
+		case "decision":
+		case "activity":
+			taskListType = types.TaskListTypeActivity
+		default:
+			ErrorAndExit(fmt.Sprintf("Invalid task list type %q, valid values are [decision|activity].", c.String(FlagTaskListType)), nil)
+		}
+		filters = append(filters, &cliFilter{Name: dynamicconfig.TaskType.String(), Value: int(taskListType)})
+	}
+	return filters
+}
+
+func getDynamicConfigValues(c *cli.Context, name string) []*types.DynamicConfigValue {
+	adminClient := cFactory.ServerAdminClient(c)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	resp, err := adminClient.ListDynamicConfig(ctx, &types.ListDynamicConfigRequest{ConfigName: name})
+	if err != nil {
+		ErrorAndExit(fmt.Sprintf("Failed to get values of %q.", name), err)
+	}
+	// unknown names return all entries, so the entries are filtered by name
+	for _, entry := range resp.Entries {
+		if entry.Name == name {
+			return entry.Values
+		}
+	}
+	return nil
+}
+
+func updateDynamicConfigValues(c *cli.Context, name string, values []*types.DynamicConfigValue) {
+	adminClient := cFactory.ServerAdminClient(c)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	err := adminClient.UpdateDynamicConfig(ctx, &types.UpdateDynamicConfigRequest{
+		ConfigName:   name,
+		ConfigValues: values,
+	})
+	if err != nil {
+		ErrorAndExit(fmt.Sprintf("Failed to update %q.", name), err)
+	}
+}
+
+// removeDynamicConfigValue removes the value with exactly the given filters, values for
+// broader or narrower filters (ex: domain wide values) are kept
+func removeDynamicConfigValue(
+	values []*types.DynamicConfigValue,
+	filters []*types.DynamicConfigFilter,
+) ([]*types.DynamicConfigValue, bool) {
+	result := make([]*types.DynamicConfigValue, 0, len(values))
+	removed := false
+	for _, value := range values {
+		if sameDynamicConfigFilters(value.Filters, filters) {
+			removed = true
+			continue
+		}
+		result = append(result, value)
+	}
+	return result, removed
+}
+
+func sameDynamicConfigFilters(a []*types.DynamicConfigFilter, b []*types.DynamicConfigFilter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	decode := func(filters []*types.DynamicConfigFilter) map[string]interface{} {
+		decoded := make(map[string]interface{}, len(filters))
+		for _, filter := range filters {
+			var value interface{}
+			if filter.Value != nil {
+				_ = json.Unmarshal(filter.Value.Data, &value)
+			}
+			decoded[filter.Name] = value
+		}
+		return decoded
+	}
+	return reflect.DeepEqual(decode(a), decode(b))
+}
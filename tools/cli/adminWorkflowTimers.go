@@ -0,0 +1,323 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/types"
+	"github.com/uber/cadence/service/history/execution"
+)
+
+const (
+	workflowTimerStatusOK         = "OK"
+	workflowTimerStatusNotCreated = "NOT CREATED"
+	workflowTimerStatusLost       = "LOST"
+	workflowTimerStatusQueued     = "QUEUED"
+
+	// timer tasks may be scheduled slightly after the fire time recorded in mutable state,
+	// so the queue is scanned a bit past the latest expected timer
+	workflowTimerScanMargin = 24 * time.Hour
+)
+
+type (
+	// WorkflowTimerRow is a row of the workflow timers report
+	WorkflowTimerRow struct {
+		Kind        string    `header:"Kind"`
+		TimerID     string    `header:"Timer ID"`
+		EventID     int64     `header:"Event ID"`
+		TimeoutType string    `header:"Timeout Type"`
+		FireTime    time.Time `header:"Fire Time"`
+		TaskCreated bool      `header:"Task Created"`
+		InQueue     bool      `header:"In Queue"`
+		Status      string    `header:"Status"`
+	}
+
+	// workflowTimer is a timer that mutable state expects to have a task in the timer queue
+	workflowTimer struct {
+		kind        string
+		timerID     string
+		taskType    int
+		eventID     int64
+		timeoutType *types.TimeoutType
+		fireTime    time.Time
+		created     bool
+	}
+)
+
+// AdminWorkflowTimers lists user and internal timers of a workflow execution together with their
+// fire times, and cross-checks them against the timer queue of the owning shard.
+// Timers that mutable state marks as created but which have no task in the queue are flagged as lost.
+func AdminWorkflowTimers(c *cli.Context) {
+	resp := describeMutableState(c)
+	ms := persistence.WorkflowMutableState{}
+	if err := json.Unmarshal([]byte(resp.GetMutableStateInDatabase()), &ms); err != nil {
+		ErrorAndExit("Failed to decode mutable state.", err)
+	}
+	if ms.ExecutionInfo == nil {
+		ErrorAndExit("Mutable state has no execution info.", nil)
+	}
+	shardID, err := strconv.Atoi(resp.GetShardID())
+	if err != nil {
+		ErrorAndExit("Failed to parse shard ID.", err)
+	}
+
+	timers := getWorkflowTimers(&ms)
+	maxTimestamp := time.Now()
+	for _, timer := range timers {
+		if timer.fireTime.After(maxTimestamp) {
+			maxTimestamp = timer.fireTime
+		}
+	}
+	tasks := loadWorkflowTimerTasks(c, shardID, ms.ExecutionInfo, maxTimestamp.Add(workflowTimerScanMargin))
+
+	Render(c, newWorkflowTimerRows(timers, tasks), RenderOptions{
+		DefaultTemplate: templateTable,
+		Color:           true,
+	})
+}
+
+// getWorkflowTimers returns all timers which mutable state expects to fire, mirroring how
+// the history service creates timer tasks for them
+func getWorkflowTimers(ms *persistence.WorkflowMutableState) []workflowTimer {
+	var timers []workflowTimer
+	info := ms.ExecutionInfo
+
+	for _, timerInfo := range ms.TimerInfos {
+		timers = append(timers, workflowTimer{
+			kind:     "UserTimer",
+			timerID:  timerInfo.TimerID,
+			taskType: persistence.TaskTypeUserTimer,
+			eventID:  timerInfo.StartedID,
+			fireTime: timerInfo.ExpiryTime,
+			created:  timerInfo.TaskStatus == execution.TimerTaskStatusCreated,
+		})
+	}
+
+	for _, ai := range ms.ActivityInfos {
+		activityTimer := func(timerType execution.TimerType, fireTime time.Time) {
+			timeoutType := execution.TimerTypeToInternal(timerType)
+			timers = append(timers, workflowTimer{
+				kind:        "ActivityTimeout",
+				timerID:     ai.ActivityID,
+				taskType:    persistence.TaskTypeActivityTimeout,
+				eventID:     ai.ScheduleID,
+				timeoutType: &timeoutType,
+				fireTime:    fireTime,
+				created:     ai.TimerTaskStatus&execution.TimerTypeToTimerMask(timerType) > 0,
+			})
+		}
+
+		if ai.ScheduleToCloseTimeout > 0 {
+			activityTimer(execution.TimerTypeScheduleToClose, ai.ScheduledTime.Add(common.SecondsToDuration(int64(ai.ScheduleToCloseTimeout))))
+		}
+		if ai.StartedID == common.EmptyEventID {
+			if ai.ScheduleToStartTimeout > 0 {
+				activityTimer(execution.TimerTypeScheduleToStart, ai.ScheduledTime.Add(common.SecondsToDuration(int64(ai.ScheduleToStartTimeout))))
+			}
+			continue
+		}
+		if ai.StartToCloseTimeout > 0 {
+			activityTimer(execution.TimerTypeStartToClose, ai.StartedTime.Add(common.SecondsToDuration(int64(ai.StartToCloseTimeout))))
+		}
+		if ai.HeartbeatTimeout > 0 {
+			lastHeartbeat := ai.StartedTime
+			if ai.LastHeartBeatUpdatedTime.After(lastHeartbeat) {
+				lastHeartbeat = ai.LastHeartBeatUpdatedTime
+			}
+			activityTimer(execution.TimerTypeHeartbeat, lastHeartbeat.Add(common.SecondsToDuration(int64(ai.HeartbeatTimeout))))
+		}
+	}
+
+	if info.State == persistence.WorkflowStateCompleted {
+		return timers
+	}
+
+	if info.DecisionStartedID != common.EmptyEventID && info.DecisionTimeout > 0 {
+		timeoutType := types.TimeoutTypeStartToClose
+		timers = append(timers, workflowTimer{
+			kind:        "DecisionTimeout",
+			taskType:    persistence.TaskTypeDecisionTimeout,
+			eventID:     info.DecisionScheduleID,
+			timeoutType: &timeoutType,
+			fireTime:    time.Unix(0, info.DecisionStartedTimestamp).Add(common.SecondsToDuration(int64(info.DecisionTimeout))),
+			created:     true,
+		})
+	}
+	if info.WorkflowTimeout > 0 {
+		timers = append(timers, workflowTimer{
+			kind:     "WorkflowTimeout",
+			taskType: persistence.TaskTypeWorkflowTimeout,
+			fireTime: info.StartTimestamp.Add(common.SecondsToDuration(int64(info.WorkflowTimeout))),
+			created:  true,
+		})
+	}
+	return timers
+}
+
+func loadWorkflowTimerTasks(
+	c *cli.Context,
+	shardID int,
+	info *persistence.WorkflowExecutionInfo,
+	maxTimestamp time.Time,
+) []*persistence.TimerTaskInfo {
+	executionManager := initializeExecutionStore(c, shardID)
+	defer executionManager.Close()
+
+	throttleRetry := backoff.NewThrottleRetry(
+		backoff.WithRetryPolicy(common.CreatePersistenceRetryPolicy()),
+		backoff.WithRetryableError(func(err error) bool {
+			return persistence.IsTransientError(err) || common.IsContextTimeoutError(err)
+		}),
+	)
+
+	var tasks []*persistence.TimerTaskInfo
+	var token []byte
+	for {
+		var resp *persistence.GetTimerIndexTasksResponse
+		op := func() error {
+			ctx, cancel := newContext(c)
+			defer cancel()
+
+			var err error
+			resp, err = executionManager.GetTimerIndexTasks(ctx, &persistence.GetTimerIndexTasksRequest{
+				MinTimestamp:  time.Unix(0, 0),
+				MaxTimestamp:  maxTimestamp,
+				BatchSize:     c.Int(FlagBatchSize),
+				NextPageToken: token,
+			})
+			return err
+		}
+		if err := throttleRetry.Do(context.Background(), op); err != nil {
+			ErrorAndExit(fmt.Sprintf("Failed to read timer tasks for shard %v.", shardID), err)
+		}
+
+		for _, task := range resp.Timers {
+			if task.DomainID == info.DomainID && task.WorkflowID == info.WorkflowID && task.RunID == info.RunID {
+				tasks = append(tasks, task)
+			}
+		}
+		token = resp.NextPageToken
+		if len(token) == 0 {
+			return tasks
+		}
+	}
+}
+
+func newWorkflowTimerRows(timers []workflowTimer, tasks []*persistence.TimerTaskInfo) []WorkflowTimerRow {
+	matched := make(map[*persistence.TimerTaskInfo]struct{}, len(tasks))
+	rows := make([]WorkflowTimerRow, 0, len(timers)+len(tasks))
+	for _, timer := range timers {
+		row := WorkflowTimerRow{
+			Kind:        timer.kind,
+			TimerID:     timer.timerID,
+			EventID:     timer.eventID,
+			FireTime:    timer.fireTime,
+			TaskCreated: timer.created,
+		}
+		if timer.timeoutType != nil {
+			row.TimeoutType = timer.timeoutType.String()
+		}
+		for _, task := range tasks {
+			if _, ok := matched[task]; !ok && timer.matches(task) {
+				matched[task] = struct{}{}
+				row.InQueue = true
+				break
+			}
+		}
+		switch {
+		case row.InQueue:
+			row.Status = workflowTimerStatusOK
+		case timer.created:
+			row.Status = workflowTimerStatusLost
+		default:
+			row.Status = workflowTimerStatusNotCreated
+		}
+		rows = append(rows, row)
+	}
+
+	// remaining tasks, e.g. retry and backoff timers, have no counterpart in mutable state
+	for _, task := range tasks {
+		if _, ok := matched[task]; ok {
+			continue
+		}
+		rows = append(rows, WorkflowTimerRow{
+			Kind:        timerTaskTypeName(task.TaskType),
+			EventID:     task.EventID,
+			FireTime:    task.VisibilityTimestamp,
+			TaskCreated: true,
+			InQueue:     true,
+			Status:      workflowTimerStatusQueued,
+		})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].FireTime.Before(rows[j].FireTime)
+	})
+	return rows
+}
+
+func (t workflowTimer) matches(task *persistence.TimerTaskInfo) bool {
+	if task.TaskType != t.taskType {
+		return false
+	}
+	switch t.taskType {
+	case persistence.TaskTypeWorkflowTimeout:
+		return true
+	case persistence.TaskTypeUserTimer:
+		return task.EventID == t.eventID
+	default:
+		return task.EventID == t.eventID && task.TimeoutType == int(*t.timeoutType)
+	}
+}
+
+func timerTaskTypeName(taskType int) string {
+	switch taskType {
+	case persistence.TaskTypeDecisionTimeout:
+		return "DecisionTimeout"
+	case persistence.TaskTypeActivityTimeout:
+		return "ActivityTimeout"
+	case persistence.TaskTypeUserTimer:
+		return "UserTimer"
+	case persistence.TaskTypeWorkflowTimeout:
+		return "WorkflowTimeout"
+	case persistence.TaskTypeDeleteHistoryEvent:
+		return "DeleteHistoryEvent"
+	case persistence.TaskTypeActivityRetryTimer:
+		return "ActivityRetryTimer"
+	case persistence.TaskTypeWorkflowBackoffTimer:
+		return "WorkflowBackoffTimer"
+	default:
+		return strconv.Itoa(taskType)
+	}
+}
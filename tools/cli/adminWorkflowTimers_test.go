@@ -0,0 +1,85 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/service/history/execution"
+)
+
+func TestNewWorkflowTimerRows(t *testing.T) {
+	now := time.Now()
+	ms := &persistence.WorkflowMutableState{
+		ExecutionInfo: &persistence.WorkflowExecutionInfo{
+			State:             persistence.WorkflowStateRunning,
+			StartTimestamp:    now,
+			WorkflowTimeout:   3600,
+			DecisionStartedID: common.EmptyEventID,
+		},
+		TimerInfos: map[string]*persistence.TimerInfo{
+			"created": {TimerID: "created", StartedID: 5, ExpiryTime: now.Add(time.Minute), TaskStatus: execution.TimerTaskStatusCreated},
+			"lost":    {TimerID: "lost", StartedID: 6, ExpiryTime: now.Add(-time.Minute), TaskStatus: execution.TimerTaskStatusCreated},
+			"pending": {TimerID: "pending", StartedID: 7, ExpiryTime: now.Add(time.Hour), TaskStatus: execution.TimerTaskStatusNone},
+		},
+		ActivityInfos: map[int64]*persistence.ActivityInfo{
+			8: {
+				ActivityID:             "activity",
+				ScheduleID:             8,
+				StartedID:              common.EmptyEventID,
+				ScheduledTime:          now,
+				ScheduleToStartTimeout: 10,
+				TimerTaskStatus:        execution.TimerTaskStatusCreatedScheduleToStart,
+			},
+		},
+	}
+	tasks := []*persistence.TimerTaskInfo{
+		{TaskType: persistence.TaskTypeUserTimer, EventID: 5, VisibilityTimestamp: now.Add(time.Minute)},
+		{TaskType: persistence.TaskTypeActivityTimeout, EventID: 8, TimeoutType: int(execution.TimerTypeScheduleToStart), VisibilityTimestamp: now.Add(10 * time.Second)},
+		{TaskType: persistence.TaskTypeWorkflowTimeout, VisibilityTimestamp: now.Add(time.Hour)},
+		{TaskType: persistence.TaskTypeDeleteHistoryEvent, VisibilityTimestamp: now.Add(2 * time.Hour)},
+	}
+
+	rows := newWorkflowTimerRows(getWorkflowTimers(ms), tasks)
+
+	statuses := make(map[string]string)
+	for _, row := range rows {
+		statuses[row.Kind+"/"+row.TimerID] = row.Status
+	}
+	assert.Equal(t, map[string]string{
+		"UserTimer/created":        workflowTimerStatusOK,
+		"UserTimer/lost":           workflowTimerStatusLost,
+		"UserTimer/pending":        workflowTimerStatusNotCreated,
+		"ActivityTimeout/activity": workflowTimerStatusOK,
+		"WorkflowTimeout/":         workflowTimerStatusOK,
+		"DeleteHistoryEvent/":      workflowTimerStatusQueued,
+	}, statuses)
+	for i := 1; i < len(rows); i++ {
+		assert.False(t, rows[i].FireTime.Before(rows[i-1].FireTime))
+	}
+}
@@ -181,6 +181,36 @@ func NewCliApp() *cli.App {
 					Usage:       "Run admin operation on config store",
 					Subcommands: newAdminConfigStoreCommands(),
 				},
+				{
+					Name:        "feature-flag",
+					Aliases:     []string{"ff"},
+					Usage:       "Run admin operation on feature flag rollouts",
+					Subcommands: newAdminFeatureFlagCommands(),
+				},
+				{
+					Name:        "diagnostics",
+					Aliases:     []string{"diag"},
+					Usage:       "Collect profiles and verbose logs of a running host",
+					Subcommands: newAdminDiagnosticsCommands(),
+				},
+				{
+					Name:        "cache",
+					Aliases:     []string{"ca"},
+					Usage:       "Describe and invalidate the in-memory caches of a running host",
+					Subcommands: newAdminCacheCommands(),
+				},
+				{
+					Name:        "apikey",
+					Aliases:     []string{"ak"},
+					Usage:       "Issue and revoke the API keys of the callers which can't use OAuth or client certificates",
+					Subcommands: newAdminAPIKeyCommands(),
+				},
+				{
+					Name:        "reshard",
+					Aliases:     []string{"rs"},
+					Usage:       "Double the history shard count of a running cluster, one shard at a time",
+					Subcommands: newAdminReshardingCommands(),
+				},
 			},
 		},
 		{
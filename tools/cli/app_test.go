@@ -21,6 +21,7 @@
 package cli
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/suite"
 	"github.com/urfave/cli"
+	"go.uber.org/yarpc"
 
 	"github.com/uber/cadence/client/admin"
 	"github.com/uber/cadence/client/frontend"
@@ -364,6 +366,19 @@ func (s *cliAppSuite) TestStartWorkflow() {
 	s.Nil(err)
 }
 
+func (s *cliAppSuite) TestStartWorkflow_FirstRunAt() {
+	resp := &types.StartWorkflowExecutionResponse{RunID: uuid.New()}
+	firstRunAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.serverFrontendClient.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *types.StartWorkflowExecutionRequest, _ ...yarpc.CallOption) (*types.StartWorkflowExecutionResponse, error) {
+			s.Equal(firstRunAt.UnixNano(), request.GetFirstRunAtTimestamp())
+			s.Nil(request.DelayStartSeconds)
+			return resp, nil
+		}).Times(1)
+	err := s.app.Run([]string{"", "--do", domainName, "workflow", "start", "-tl", "testTaskList", "-wt", "testWorkflowType", "-et", "60", "-w", "wid", "--first_run_at", "2030-01-02T03:04:05Z"})
+	s.Nil(err)
+}
+
 func (s *cliAppSuite) TestStartWorkflow_Failed() {
 	resp := &types.StartWorkflowExecutionResponse{RunID: uuid.New()}
 	s.serverFrontendClient.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any()).Return(resp, &types.BadRequestError{"faked error"})
@@ -458,6 +473,91 @@ func (s *cliAppSuite) TestQueryWorkflow_Failed() {
 	s.Equal(1, errorCode)
 }
 
+func (s *cliAppSuite) TestDescribeActivity() {
+	state := types.PendingActivityStateScheduled
+	describeResp := &types.DescribeWorkflowExecutionResponse{
+		WorkflowExecutionInfo: &types.WorkflowExecutionInfo{},
+		PendingActivities: []*types.PendingActivityInfo{
+			{
+				ActivityID:         "aid",
+				State:              &state,
+				Attempt:            2,
+				HeartbeatDetails:   []byte("{\"progress\": 10}\n"),
+				ScheduledTimestamp: common.Int64Ptr(time.Now().Add(time.Minute).UnixNano()),
+			},
+		},
+	}
+	s.serverFrontendClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(describeResp, nil)
+	errorCode := s.RunErrorExitCode([]string{"", "--do", domainName, "workflow", "activity", "describe", "-w", "wid", "-aid", "aid"})
+	s.Equal(0, errorCode)
+}
+
+func (s *cliAppSuite) TestDescribeActivity_NotPending() {
+	describeResp := &types.DescribeWorkflowExecutionResponse{
+		WorkflowExecutionInfo: &types.WorkflowExecutionInfo{},
+	}
+	s.serverFrontendClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(describeResp, nil)
+	errorCode := s.RunErrorExitCode([]string{"", "--do", domainName, "workflow", "activity", "describe", "-w", "wid", "-aid", "aid"})
+	s.Equal(1, errorCode)
+}
+
+func (s *cliAppSuite) TestQueryWorkflowBatch() {
+	scanResp := &types.ListWorkflowExecutionsResponse{
+		Executions: []*types.WorkflowExecutionInfo{
+			{Execution: &types.WorkflowExecution{WorkflowID: "wid1", RunID: uuid.New()}},
+			{Execution: &types.WorkflowExecution{WorkflowID: "wid2", RunID: uuid.New()}},
+		},
+	}
+	s.serverFrontendClient.EXPECT().ScanWorkflowExecutions(gomock.Any(), gomock.Any()).Return(scanResp, nil)
+	s.serverFrontendClient.EXPECT().QueryWorkflow(gomock.Any(), gomock.Any()).Return(&types.QueryWorkflowResponse{QueryResult: []byte("query-result")}, nil)
+	s.serverFrontendClient.EXPECT().QueryWorkflow(gomock.Any(), gomock.Any()).Return(nil, &types.BadRequestError{Message: "faked error"})
+	errorCode := s.RunErrorExitCode([]string{"", "--do", domainName, "workflow", "query-batch", "-q", "WorkflowType='test'", "-qt", "query-type-test"})
+	s.Equal(0, errorCode)
+}
+
+func (s *cliAppSuite) TestListPendingActivities() {
+	scanResp := &types.ListWorkflowExecutionsResponse{
+		Executions: []*types.WorkflowExecutionInfo{
+			{Execution: &types.WorkflowExecution{WorkflowID: "wid1", RunID: uuid.New()}},
+			{Execution: &types.WorkflowExecution{WorkflowID: "wid2", RunID: uuid.New()}},
+		},
+	}
+	started := types.PendingActivityStateStarted
+	scheduled := types.PendingActivityStateScheduled
+	describeResp := &types.DescribeWorkflowExecutionResponse{
+		WorkflowExecutionInfo: &types.WorkflowExecutionInfo{Execution: scanResp.Executions[0].Execution},
+		PendingActivities: []*types.PendingActivityInfo{
+			{
+				ActivityID:             "aid1",
+				ActivityType:           &types.ActivityType{Name: "async-activity"},
+				State:                  &started,
+				LastStartedTimestamp:   common.Int64Ptr(time.Now().Add(-2 * time.Hour).UnixNano()),
+				LastHeartbeatTimestamp: common.Int64Ptr(time.Now().Add(-time.Hour).UnixNano()),
+			},
+			{
+				ActivityID:           "aid2",
+				State:                &started,
+				LastStartedTimestamp: common.Int64Ptr(time.Now().UnixNano()),
+			},
+			{
+				ActivityID: "aid3",
+				State:      &scheduled,
+			},
+		},
+	}
+	s.serverFrontendClient.EXPECT().ScanWorkflowExecutions(gomock.Any(), gomock.Any()).Return(scanResp, nil)
+	s.serverFrontendClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(describeResp, nil)
+	s.serverFrontendClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil, &types.EntityNotExistsError{})
+	errorCode := s.RunErrorExitCode([]string{"", "--do", domainName, "workflow", "activity", "list-pending", "--min_age", "1h"})
+	s.Equal(0, errorCode)
+}
+
+func (s *cliAppSuite) TestListPendingActivities_Execution() {
+	s.serverFrontendClient.EXPECT().DescribeWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil, &types.EntityNotExistsError{})
+	errorCode := s.RunErrorExitCode([]string{"", "--do", domainName, "workflow", "activity", "list-pending", "-w", "wid"})
+	s.Equal(1, errorCode)
+}
+
 var (
 	closeStatus = types.WorkflowExecutionCloseStatusCompleted
 
@@ -615,6 +715,85 @@ func (s *cliAppSuite) TestAdminAddSearchAttribute() {
 	s.Nil(err)
 }
 
+func (s *cliAppSuite) TestAdminSetTaskListConfig() {
+	jsonBlob := func(data string) *types.DataBlob {
+		return &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: []byte(data)}
+	}
+	domainWide := &types.DynamicConfigValue{
+		Value:   jsonBlob("2"),
+		Filters: []*types.DynamicConfigFilter{{Name: "domainName", Value: jsonBlob(`"` + domainName + `"`)}},
+	}
+	existing := &types.DynamicConfigValue{
+		Value: jsonBlob("4"),
+		Filters: []*types.DynamicConfigFilter{
+			{Name: "domainName", Value: jsonBlob(`"` + domainName + `"`)},
+			{Name: "taskListName", Value: jsonBlob(`"test-tl"`)},
+			{Name: "taskType", Value: jsonBlob("1")},
+		},
+	}
+	s.serverAdminClient.EXPECT().ListDynamicConfig(gomock.Any(), &types.ListDynamicConfigRequest{ConfigName: "matching.taskDispatchRPS"}).
+		Return(&types.ListDynamicConfigResponse{Entries: []*types.DynamicConfigEntry{
+			{Name: "matching.taskDispatchRPS", Values: []*types.DynamicConfigValue{domainWide, existing}},
+		}}, nil)
+	s.serverAdminClient.EXPECT().UpdateDynamicConfig(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *types.UpdateDynamicConfigRequest, _ ...yarpc.CallOption) error {
+			s.Equal("matching.taskDispatchRPS", request.ConfigName)
+			s.Len(request.ConfigValues, 2)
+			s.Equal(domainWide, request.ConfigValues[0])
+			s.Equal("10", string(request.ConfigValues[1].Value.Data))
+			s.Len(request.ConfigValues[1].Filters, 3)
+			return nil
+		})
+
+	err := s.app.Run([]string{"", "--do", domainName, "admin", "tl", "set-config", "--tl", "test-tl", "--tlt", "activity", "--name", "matching.taskDispatchRPS", "--value", "10"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminClearTaskListConfig_NotFound() {
+	s.serverAdminClient.EXPECT().ListDynamicConfig(gomock.Any(), gomock.Any()).Return(&types.ListDynamicConfigResponse{}, nil)
+	err := s.app.Run([]string{"", "--do", domainName, "admin", "tl", "clear-config", "--tl", "test-tl", "--name", "matching.taskDispatchRPS"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminSetFeatureFlag() {
+	jsonBlob := func(data string) *types.DataBlob {
+		return &types.DataBlob{EncodingType: types.EncodingTypeJSON.Ptr(), Data: []byte(data)}
+	}
+	allDomains := &types.DynamicConfigValue{
+		Value:   jsonBlob("10"),
+		Filters: []*types.DynamicConfigFilter{{Name: "featureFlag", Value: jsonBlob(`"test-flag"`)}},
+	}
+	existing := &types.DynamicConfigValue{
+		Value: jsonBlob("20"),
+		Filters: []*types.DynamicConfigFilter{
+			{Name: "featureFlag", Value: jsonBlob(`"test-flag"`)},
+			{Name: "domainName", Value: jsonBlob(`"` + domainName + `"`)},
+		},
+	}
+	s.serverAdminClient.EXPECT().ListDynamicConfig(gomock.Any(), &types.ListDynamicConfigRequest{ConfigName: "system.featureFlagRolloutPercentage"}).
+		Return(&types.ListDynamicConfigResponse{Entries: []*types.DynamicConfigEntry{
+			{Name: "system.featureFlagRolloutPercentage", Values: []*types.DynamicConfigValue{allDomains, existing}},
+		}}, nil)
+	s.serverAdminClient.EXPECT().UpdateDynamicConfig(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *types.UpdateDynamicConfigRequest, _ ...yarpc.CallOption) error {
+			s.Equal("system.featureFlagRolloutPercentage", request.ConfigName)
+			s.Len(request.ConfigValues, 2)
+			s.Equal(allDomains, request.ConfigValues[0])
+			s.Equal("50", string(request.ConfigValues[1].Value.Data))
+			s.Len(request.ConfigValues[1].Filters, 2)
+			return nil
+		})
+
+	err := s.app.Run([]string{"", "--do", domainName, "admin", "ff", "set", "--flag", "test-flag", "--percentage", "50"})
+	s.Nil(err)
+}
+
+func (s *cliAppSuite) TestAdminClearFeatureFlag_NotFound() {
+	s.serverAdminClient.EXPECT().ListDynamicConfig(gomock.Any(), gomock.Any()).Return(&types.ListDynamicConfigResponse{}, nil)
+	err := s.app.Run([]string{"", "admin", "ff", "clear", "--flag", "test-flag"})
+	s.Nil(err)
+}
+
 func (s *cliAppSuite) TestAdminFailover() {
 	resp := &types.StartWorkflowExecutionResponse{RunID: uuid.New()}
 	s.serverFrontendClient.EXPECT().StartWorkflowExecution(gomock.Any(), gomock.Any(), gomock.Any()).Return(resp, nil)
@@ -0,0 +1,84 @@
+// The MIT License (MIT)
+
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// DataConverter decodes payloads stored by workers, such as activity heartbeat details or
+// failure details, into a human readable form. A custom implementation can be installed
+// with SetDataConverter when payloads are not JSON encoded.
+type DataConverter interface {
+	FromData(payload []byte) (string, error)
+}
+
+// jsonDataConverter decodes payloads written by the JSON data converters of the cadence clients,
+// which encode each value as a JSON document separated by a newline
+type jsonDataConverter struct{}
+
+// SetDataConverter is used to set the DataConverter global
+func SetDataConverter(converter DataConverter) {
+	dataConverter = converter
+}
+
+// NewJSONDataConverter creates a DataConverter for JSON encoded payloads
+func NewJSONDataConverter() DataConverter {
+	return &jsonDataConverter{}
+}
+
+func (dc *jsonDataConverter) FromData(payload []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	var values []string
+	for {
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, string(encoded))
+	}
+	return strings.Join(values, " "), nil
+}
+
+// decodePayload decodes the payload with the configured DataConverter, falling back to the raw
+// payload when it cannot be decoded
+func decodePayload(payload []byte) *string {
+	if payload == nil {
+		return nil
+	}
+	decoded, err := dataConverter.FromData(payload)
+	if err != nil {
+		decoded = string(payload)
+	}
+	return &decoded
+}
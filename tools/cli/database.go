@@ -185,6 +185,15 @@ func initializeDomainManager(c *cli.Context) persistence.DomainManager {
 	return domainManager
 }
 
+func initializeConfigStoreManager(c *cli.Context) persistence.ConfigStoreManager {
+	factory := getPersistenceFactory(c)
+	configStoreManager, err := factory.NewConfigStoreManager()
+	if err != nil {
+		ErrorAndExit("Failed to initialize config store manager", err)
+	}
+	return configStoreManager
+}
+
 var persistenceFactory client.Factory
 
 func getPersistenceFactory(c *cli.Context) client.Factory {
@@ -226,6 +235,8 @@ func initPersistenceFactory(c *cli.Context) client.Factory {
 		func() float64 { return rps },
 		cfg.ClusterGroupMetadata.CurrentClusterName,
 		metrics.NewNoopMetricsClient(),
+		nil,
+		nil,
 		log.NewNoop(),
 		&persistence.DynamicConfiguration{
 			EnableSQLAsyncTransaction: dynamicconfig.GetBoolPropertyFn(false),
@@ -108,7 +108,8 @@ const (
 )
 
 var (
-	cFactory ClientFactory
+	cFactory      ClientFactory
+	dataConverter DataConverter = NewJSONDataConverter()
 
 	colorRed     = color.New(color.FgRed).SprintFunc()
 	colorMagenta = color.New(color.FgMagenta).SprintFunc()
@@ -34,6 +34,7 @@ import (
 	"github.com/urfave/cli"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
+	yarpcjson "go.uber.org/yarpc/encoding/json"
 	"go.uber.org/zap"
 
 	"crypto/tls"
@@ -125,9 +126,9 @@ func (b *clientFactory) ServerAdminClient(c *cli.Context) admin.Client {
 	b.ensureDispatcher(c)
 	clientConfig := b.dispatcher.ClientConfig(cadenceFrontendService)
 	if c.GlobalString(FlagTransport) == grpcTransport {
-		return admin.NewGRPCClient(adminv1.NewAdminAPIYARPCClient(clientConfig))
+		return admin.NewJSONClient(yarpcjson.New(clientConfig), admin.NewGRPCClient(adminv1.NewAdminAPIYARPCClient(clientConfig)))
 	}
-	return admin.NewThriftClient(serverAdmin.New(clientConfig))
+	return admin.NewJSONClient(yarpcjson.New(clientConfig), admin.NewThriftClient(serverAdmin.New(clientConfig)))
 }
 
 // ElasticSearchClient builds an ElasticSearch client
@@ -210,7 +211,8 @@ func (vm *versionMiddleware) Call(ctx context.Context, request *transport.Reques
 	request.Headers = request.Headers.
 		With(common.ClientImplHeaderName, cc.CLI).
 		With(common.FeatureVersionHeaderName, cc.SupportedCLIVersion).
-		With(common.ClientFeatureFlagsHeaderName, cc.FeatureFlagsHeader(cc.DefaultCLIFeatureFlags))
+		With(common.ClientFeatureFlagsHeaderName, cc.FeatureFlagsHeader(cc.DefaultCLIFeatureFlags)).
+		With(common.ClientIdentityHeaderName, getCliUserIdentity())
 	if jwtKey, ok := ctx.Value(CtxKeyJWT).(string); ok {
 		request.Headers = request.Headers.With(common.AuthorizationTokenHeaderName, jwtKey)
 	}
@@ -69,6 +69,7 @@ const (
 	FlagWorkflowIDReusePolicy             = "workflowidreusepolicy"
 	FlagWorkflowIDReusePolicyAlias        = FlagWorkflowIDReusePolicy + ", wrp"
 	FlagCronSchedule                      = "cron"
+	FlagCronOverlapPolicy                 = "cron_overlap_policy"
 	FlagWorkflowType                      = "workflow_type"
 	FlagWorkflowTypeWithAlias             = FlagWorkflowType + ", wt"
 	FlagWorkflowStatus                    = "status"
@@ -213,6 +214,8 @@ const (
 	FlagResetPointsOnly                   = "reset_points_only"
 	FlagResetBadBinaryChecksum            = "reset_bad_binary_checksum"
 	FlagSkipSignalReapply                 = "skip_signal_reapply"
+	FlagReapplySignalNames                = "reapply_signal_names"
+	FlagSkipReapplySignalNames            = "skip_reapply_signal_names"
 	FlagListQuery                         = "query"
 	FlagListQueryWithAlias                = FlagListQuery + ", q"
 	FlagExcludeWorkflowIDByQuery          = "exclude_query"
@@ -252,6 +255,15 @@ const (
 	FlagReportRate                        = "report_rate"
 	FlagLowerShardBound                   = "lower_shard_bound"
 	FlagUpperShardBound                   = "upper_shard_bound"
+	FlagMaxTaskCount                      = "max_task_count"
+	FlagTop                               = "top"
+	FlagWindow                            = "window"
+	FlagMatch                             = "match"
+	FlagAPIKeyID                          = "id"
+	FlagAPIKeyDomains                     = "domains"
+	FlagAPIKeyPermissions                 = "permissions"
+	FlagAPIKeyTTL                         = "ttl"
+	FlagPropagationDelay                  = "propagation_delay"
 	FlagInputDirectory                    = "input_directory"
 	FlagSkipHistoryChecks                 = "skip_history_checks"
 	FlagFailoverType                      = "failover_type"
@@ -284,6 +296,7 @@ const (
 	FlagBucketSize                        = "bucket_size"
 	DelayStartSeconds                     = "delay_start_seconds"
 	JitterStartSeconds                    = "jitter_start_seconds"
+	FlagFirstRunAt                        = "first_run_at"
 	FlagConnectionAttributes              = "conn_attrs"
 	FlagJWT                               = "jwt"
 	FlagJWTPrivateKey                     = "jwt-private-key"
@@ -291,9 +304,18 @@ const (
 	FlagDynamicConfigName                 = "name"
 	FlagDynamicConfigFilter               = "filter"
 	FlagDynamicConfigValue                = "value"
+	FlagDynamicConfigVersion              = "version"
+	FlagDynamicConfigOverridden           = "overridden"
+	FlagFeatureFlag                       = "flag"
+	FlagRolloutPercentage                 = "percentage"
+	FlagDiagnosticsSeconds                = "seconds"
+	FlagDiagnosticsProfiles               = "profiles"
+	FlagVerboseLogging                    = "verbose"
 	FlagTransport                         = "transport"
 	FlagTransportWithAlias                = FlagTransport + ", t"
 	FlagFormat                            = "format"
+	FlagMinAge                            = "min_age"
+	FlagBlockReloadSeconds                = "block_reload_seconds"
 )
 
 var flagsForExecution = []cli.Flag{
@@ -392,7 +414,20 @@ func getFlagsForStart() []cli.Flag {
 				"\t│ │ │ ┌───────────── month (1 - 12) \n" +
 				"\t│ │ │ │ ┌───────────── day of the week (0 - 6) (Sunday to Saturday) \n" +
 				"\t│ │ │ │ │ \n" +
-				"\t* * * * *",
+				"\t* * * * *\n" +
+				"Or a calendar based schedule in JSON format, with seconds granularity, intervals, exclusions and time zone, e.g.\n" +
+				"\t{\"calendars\": [{\"second\": \"30\", \"minute\": \"0\", \"hour\": \"9-17\", \"dayOfWeek\": \"1-5\"}], " +
+				"\"intervals\": [{\"every\": \"90m\", \"offset\": \"15m\"}], " +
+				"\"exclude\": [{\"month\": \"12\", \"dayOfMonth\": \"25\"}], \"timezone\": \"America/New_York\"}",
+		},
+		cli.IntFlag{
+			Name:  FlagRetentionDaysWithAlias,
+			Usage: "Optional retention in days of the workflow execution, within the bounds of domain retention. Default to the retention of the domain",
+		},
+		cli.StringFlag{
+			Name: FlagCronOverlapPolicy,
+			Usage: "Optional policy for the fires of the cron schedule happening while a run is still open. " +
+				"Available options: skip (default), buffer-one, cancel-running",
 		},
 		cli.IntFlag{
 			Name: FlagWorkflowIDReusePolicyAlias,
@@ -477,6 +512,11 @@ func getFlagsForStart() []cli.Flag {
 			Name:  JitterStartSeconds,
 			Usage: "Optional workflow start jitter in seconds. If set, workflow start will be jittered between 0-n seconds (after delay)",
 		},
+		cli.StringFlag{
+			Name: FlagFirstRunAt,
+			Usage: "Optional time of the first run of the workflow, in UTC format '2006-01-02T15:04:05Z' or raw UnixNano. " +
+				"If set workflow start will be delayed until then, it cannot be used with " + DelayStartSeconds,
+		},
 	}
 }
 
@@ -727,6 +767,34 @@ func getFlagsForQuery() []cli.Flag {
 	}
 }
 
+func getFlagsForQueryBatch() []cli.Flag {
+	flags := []cli.Flag{
+		cli.StringFlag{
+			Name:  FlagListQueryWithAlias,
+			Usage: "SQL like query selecting the workflow executions to query",
+		},
+		cli.IntFlag{
+			Name:  FlagPageSizeWithAlias,
+			Value: 2000,
+			Usage: "Page size for each Scan API call",
+		},
+		cli.IntFlag{
+			Name:  FlagConcurrency,
+			Value: 10,
+			Usage: "Number of queries to run in parallel",
+		},
+		getFormatFlag(),
+	}
+	for _, flag := range getFlagsForQuery() {
+		switch flag.GetName() {
+		case FlagWorkflowIDWithAlias, FlagRunIDWithAlias:
+		default:
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
 // all flags of query except QueryType
 func getFlagsForStack() []cli.Flag {
 	flags := getFlagsForQuery()
@@ -31,6 +31,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"os/user"
 	"reflect"
 	"regexp"
 	"runtime/debug"
@@ -796,6 +797,15 @@ func getCliIdentity() string {
 	return fmt.Sprintf("cadence-cli@%s", getHostName())
 }
 
+// getCliUserIdentity returns the identity of the user running the CLI, used for auditing admin operations
+func getCliUserIdentity() string {
+	userName := "unknown"
+	if u, err := user.Current(); err == nil {
+		userName = u.Username
+	}
+	return fmt.Sprintf("%s@%s", userName, getHostName())
+}
+
 func getHostName() string {
 	hostName, err := os.Hostname()
 	if err != nil {
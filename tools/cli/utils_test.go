@@ -92,3 +92,14 @@ func Test_ParseIntMultiRange(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONDataConverter(t *testing.T) {
+	converter := NewJSONDataConverter()
+
+	decoded, err := converter.FromData([]byte("{\"progress\":  10}\n\"step\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"progress":10} "step"`, decoded)
+
+	_, err = converter.FromData([]byte("not json"))
+	assert.Error(t, err)
+}
@@ -169,6 +169,15 @@ func newWorkflowCommands() []cli.Command {
 				QueryWorkflow(c)
 			},
 		},
+		{
+			Name:        "query-batch",
+			Usage:       "query all workflow executions matching a list query",
+			Description: "queries are run in parallel and the results of all executions are printed together",
+			Flags:       getFlagsForQueryBatch(),
+			Action: func(c *cli.Context) {
+				QueryWorkflowBatch(c)
+			},
+		},
 		{
 			Name:  "stack",
 			Usage: "query workflow execution with __stack_trace as query type",
@@ -260,6 +269,14 @@ func newWorkflowCommands() []cli.Command {
 					Name:  FlagSkipSignalReapply,
 					Usage: "whether or not skipping signals reapply after the reset point",
 				},
+				cli.StringSliceFlag{
+					Name:  FlagReapplySignalNames,
+					Usage: "Optional names of the only signals to reapply after the reset point, eg s1,s2..,sn",
+				},
+				cli.StringSliceFlag{
+					Name:  FlagSkipReapplySignalNames,
+					Usage: "Optional names of the signals not to reapply after the reset point, eg s1,s2..,sn",
+				},
 			},
 			Action: func(c *cli.Context) {
 				ResetWorkflow(c)
@@ -342,6 +359,14 @@ func newWorkflowCommands() []cli.Command {
 					Name:  FlagSkipSignalReapply,
 					Usage: "whether or not skipping signals reapply after the reset point",
 				},
+				cli.StringSliceFlag{
+					Name:  FlagReapplySignalNames,
+					Usage: "Optional names of the only signals to reapply after the reset point, eg s1,s2..,sn",
+				},
+				cli.StringSliceFlag{
+					Name:  FlagSkipReapplySignalNames,
+					Usage: "Optional names of the signals not to reapply after the reset point, eg s1,s2..,sn",
+				},
 				cli.StringFlag{
 					Name: FlagEarliestTimeWithAlias,
 					Usage: "EarliestTime of decision start time, required for resetType of DecisionCompletedTime." +
@@ -395,6 +420,67 @@ func newActivityCommands() []cli.Command {
 				CompleteActivity(c)
 			},
 		},
+		{
+			Name:    "describe",
+			Aliases: []string{"desc"},
+			Usage:   "show heartbeat details, attempts and last failure of a pending activity",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagWorkflowIDWithAlias,
+					Usage: "WorkflowID",
+				},
+				cli.StringFlag{
+					Name:  FlagRunIDWithAlias,
+					Usage: "RunID",
+				},
+				cli.StringFlag{
+					Name:  FlagActivityIDWithAlias,
+					Usage: "The activityID to describe",
+				},
+			},
+			Action: func(c *cli.Context) {
+				DescribeActivity(c)
+			},
+		},
+		{
+			Name:        "list-pending",
+			Aliases:     []string{"lp"},
+			Usage:       "list the started activities waiting for their completion, e.g. by task token",
+			Description: "activities of the given workflow, or of all the workflows matching the list query, the oldest first",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagWorkflowIDWithAlias,
+					Usage: "WorkflowID, all the workflows matching the list query are scanned if not set",
+				},
+				cli.StringFlag{
+					Name:  FlagRunIDWithAlias,
+					Usage: "RunID",
+				},
+				cli.StringFlag{
+					Name:  FlagListQueryWithAlias,
+					Value: "CloseTime = missing",
+					Usage: "SQL like query selecting the workflow executions to scan",
+				},
+				cli.DurationFlag{
+					Name:  FlagMinAge,
+					Usage: "Only list the activities started for at least this duration, e.g. 1h",
+				},
+				cli.IntFlag{
+					Name:  FlagPageSizeWithAlias,
+					Value: 2000,
+					Usage: "Page size for each Scan API call",
+				},
+				cli.IntFlag{
+					Name:  FlagConcurrency,
+					Value: 10,
+					Usage: "Number of workflow executions to describe in parallel",
+				},
+				getFormatFlag(),
+			},
+			Action: func(c *cli.Context) {
+				ListPendingActivities(c)
+			},
+		},
 		{
 			Name:  "fail",
 			Usage: "fail an activity",
@@ -512,6 +598,27 @@ func newBatchCommands() []cli.Command {
 					Name:  FlagTargetClusterWithAlias,
 					Usage: "Required for batch replicate",
 				},
+				cli.StringFlag{
+					Name:  FlagResetType,
+					Usage: "Required for batch reset, where to reset. Support one of these: " + strings.Join(batcher.AllResetTypes, ","),
+				},
+				cli.Int64Flag{
+					Name:  FlagEventIDWithAlias,
+					Usage: "DecisionFinishEventID to reset to, required for batch reset with resetType of EventID",
+				},
+				cli.StringFlag{
+					Name: FlagEarliestTimeWithAlias,
+					Usage: "Required for batch reset with resetType of DecisionCompletedTime, workflows are reset to the first decision " +
+						"that completed at or after it. Supported formats are '2006-01-02T15:04:05+07:00', raw UnixNano and time range (N<duration>)",
+				},
+				cli.BoolFlag{
+					Name:  FlagSkipSignalReapply,
+					Usage: "Optional for batch reset, whether or not skipping signals reapply after the reset point",
+				},
+				cli.BoolFlag{
+					Name:  FlagDryRun,
+					Usage: "Optional for batch reset, only resolve the reset point of each workflow without resetting it",
+				},
 				cli.IntFlag{
 					Name:  FlagRPS,
 					Value: batcher.DefaultRPS,
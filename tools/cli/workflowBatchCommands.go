@@ -172,6 +172,16 @@ func StartBatchJob(c *cli.Context) {
 		sourceCluster = getRequiredOption(c, FlagSourceCluster)
 		targetCluster = getRequiredOption(c, FlagTargetCluster)
 	}
+	var resetParams batcher.ResetParams
+	if batchType == batcher.BatchTypeReset {
+		resetParams = batcher.ResetParams{
+			ResetType:         getRequiredOption(c, FlagResetType),
+			EventID:           c.Int64(FlagEventID),
+			EarliestTime:      parseTime(c.String(FlagEarliestTime), 0),
+			SkipSignalReapply: c.Bool(FlagSkipSignalReapply),
+			DryRun:            c.Bool(FlagDryRun),
+		}
+	}
 	rps := c.Int(FlagRPS)
 	pageSize := c.Int(FlagPageSize)
 	concurrency := c.Int(FlagConcurrency)
@@ -226,6 +236,7 @@ func StartBatchJob(c *cli.Context) {
 			SourceCluster: sourceCluster,
 			TargetCluster: targetCluster,
 		},
+		ResetParams:              resetParams,
 		RPS:                      rps,
 		Concurrency:              concurrency,
 		PageSize:                 pageSize,
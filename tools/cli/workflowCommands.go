@@ -32,6 +32,7 @@ import (
 	"math/rand"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -43,6 +44,8 @@ import (
 
 	"github.com/uber/cadence/client/frontend"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/clock"
 	"github.com/uber/cadence/common/types"
 	"github.com/uber/cadence/service/history/execution"
@@ -337,12 +340,28 @@ func constructStartWorkflowRequest(c *cli.Context) *types.StartWorkflowExecution
 		startRequest.JitterStartSeconds = common.Int32Ptr(int32(c.Int(JitterStartSeconds)))
 	}
 
+	if c.IsSet(FlagFirstRunAt) {
+		startRequest.FirstRunAtTimestamp = common.Int64Ptr(parseTime(c.String(FlagFirstRunAt), 0))
+	}
+
 	headerFields := processHeader(c)
+	if c.IsSet(FlagCronOverlapPolicy) {
+		if headerFields == nil {
+			headerFields = map[string][]byte{}
+		}
+		headerFields[backoff.CronOverlapPolicyHeader] = []byte(c.String(FlagCronOverlapPolicy))
+	}
 	if len(headerFields) != 0 {
 		startRequest.Header = &types.Header{Fields: headerFields}
 	}
 
 	memoFields := processMemo(c)
+	if c.IsSet(FlagRetentionDays) {
+		if memoFields == nil {
+			memoFields = map[string][]byte{}
+		}
+		memoFields[cache.ExecutionRetentionKey] = []byte(strconv.Itoa(c.Int(FlagRetentionDays)))
+	}
 	if len(memoFields) != 0 {
 		startRequest.Memo = &types.Memo{Fields: memoFields}
 	}
@@ -586,6 +605,9 @@ func SignalWithStartWorkflowExecution(c *cli.Context) {
 }
 
 func constructSignalWithStartWorkflowRequest(c *cli.Context) *types.SignalWithStartWorkflowExecutionRequest {
+	if c.IsSet(FlagFirstRunAt) {
+		ErrorAndExit("Option "+FlagFirstRunAt+" is not supported by signal with start", nil)
+	}
 	startRequest := constructStartWorkflowRequest(c)
 
 	return &types.SignalWithStartWorkflowExecutionRequest{
@@ -635,16 +657,31 @@ func queryWorkflowHelper(c *cli.Context, queryType string) {
 	domain := getRequiredGlobalOption(c, FlagDomain)
 	wid := getRequiredOption(c, FlagWorkflowID)
 	rid := c.String(FlagRunID)
-	input := processJSONInput(c)
 
 	tcCtx, cancel := newContext(c)
 	defer cancel()
+	queryRequest := newQueryWorkflowRequest(c, domain, queryType)
+	queryRequest.Execution = &types.WorkflowExecution{WorkflowID: wid, RunID: rid}
+	queryResponse, err := serviceClient.QueryWorkflow(tcCtx, queryRequest)
+	if err != nil {
+		ErrorAndExit("Query workflow failed.", err)
+		return
+	}
+
+	if queryResponse.QueryRejected != nil {
+		fmt.Printf("Query was rejected, workflow is in state: %v\n", *queryResponse.QueryRejected.CloseStatus)
+	} else {
+		// assume it is json encoded
+		fmt.Print(string(queryResponse.QueryResult))
+	}
+}
+
+// newQueryWorkflowRequest builds a query request from the input, reject condition and consistency level flags,
+// the execution to query is left to the caller
+func newQueryWorkflowRequest(c *cli.Context, domain string, queryType string) *types.QueryWorkflowRequest {
+	input := processJSONInput(c)
 	queryRequest := &types.QueryWorkflowRequest{
 		Domain: domain,
-		Execution: &types.WorkflowExecution{
-			WorkflowID: wid,
-			RunID:      rid,
-		},
 		Query: &types.WorkflowQuery{
 			QueryType: queryType,
 		},
@@ -676,18 +713,113 @@ func queryWorkflowHelper(c *cli.Context, queryType string) {
 		}
 		queryRequest.QueryConsistencyLevel = &consistencyLevel
 	}
-	queryResponse, err := serviceClient.QueryWorkflow(tcCtx, queryRequest)
-	if err != nil {
-		ErrorAndExit("Query workflow failed.", err)
-		return
+	return queryRequest
+}
+
+// BatchQueryRow is a row of the query-batch output
+type BatchQueryRow struct {
+	WorkflowID string `header:"Workflow ID"`
+	RunID      string `header:"Run ID"`
+	Result     string `header:"Result"`
+	Error      string `header:"Error"`
+}
+
+// QueryWorkflowBatch runs a query against all workflow executions matching a list query and prints the results
+func QueryWorkflowBatch(c *cli.Context) {
+	serviceClient := cFactory.ServerFrontendClient(c)
+
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	queryType := getRequiredOption(c, FlagQueryType)
+	listQuery := getRequiredOption(c, FlagListQuery)
+	queryRequest := newQueryWorkflowRequest(c, domain, queryType)
+
+	var mu sync.Mutex
+	var table []BatchQueryRow
+	scanWorkflowExecutionsInParallel(c, serviceClient, listQuery, func(execution *types.WorkflowExecution) {
+		row := queryWorkflowForBatch(c, serviceClient, queryRequest, execution)
+		mu.Lock()
+		table = append(table, row)
+		mu.Unlock()
+	})
+
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].WorkflowID != table[j].WorkflowID {
+			return table[i].WorkflowID < table[j].WorkflowID
+		}
+		return table[i].RunID < table[j].RunID
+	})
+	Render(c, table, RenderOptions{DefaultTemplate: templateTable, Color: true, Border: true})
+}
+
+// scanWorkflowExecutionsInParallel calls fn for every workflow execution matching the list query, from as many
+// goroutines as the concurrency flag
+func scanWorkflowExecutionsInParallel(
+	c *cli.Context,
+	serviceClient frontend.Client,
+	listQuery string,
+	fn func(*types.WorkflowExecution),
+) {
+	pageSize := c.Int(FlagPageSize)
+	if pageSize <= 0 {
+		pageSize = defaultPageSizeForScan
+	}
+	concurrency := c.Int(FlagConcurrency)
+	if concurrency <= 0 {
+		concurrency = 1
 	}
 
-	if queryResponse.QueryRejected != nil {
-		fmt.Printf("Query was rejected, workflow is in state: %v\n", *queryResponse.QueryRejected.CloseStatus)
-	} else {
-		// assume it is json encoded
-		fmt.Print(string(queryResponse.QueryResult))
+	executions := make(chan *types.WorkflowExecution)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for execution := range executions {
+				fn(execution)
+			}
+		}()
+	}
+
+	var nextPageToken []byte
+	for {
+		page, token := scanWorkflowExecutions(serviceClient, pageSize, nextPageToken, listQuery, c)
+		for _, info := range page {
+			executions <- info.Execution
+		}
+		nextPageToken = token
+		if len(nextPageToken) == 0 {
+			break
+		}
 	}
+	close(executions)
+	wg.Wait()
+}
+
+func queryWorkflowForBatch(
+	c *cli.Context,
+	serviceClient frontend.Client,
+	queryRequest *types.QueryWorkflowRequest,
+	execution *types.WorkflowExecution,
+) BatchQueryRow {
+	row := BatchQueryRow{
+		WorkflowID: execution.GetWorkflowID(),
+		RunID:      execution.GetRunID(),
+	}
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+	request := *queryRequest
+	request.Execution = execution
+	queryResponse, err := serviceClient.QueryWorkflow(ctx, &request)
+	switch {
+	case err != nil:
+		row.Error = err.Error()
+	case queryResponse.QueryRejected != nil:
+		row.Error = fmt.Sprintf("query was rejected, workflow is in state: %v", queryResponse.QueryRejected.CloseStatus)
+	default:
+		row.Result = strings.TrimSpace(string(queryResponse.QueryResult))
+	}
+	return row
 }
 
 // ListWorkflow list workflow executions based on filters
@@ -1425,10 +1557,12 @@ func ResetWorkflow(c *cli.Context) {
 			WorkflowID: wid,
 			RunID:      resetBaseRunID,
 		},
-		Reason:                fmt.Sprintf("%v:%v", getCurrentUserFromEnv(), reason),
-		DecisionFinishEventID: decisionFinishID,
-		RequestID:             uuid.New(),
-		SkipSignalReapply:     c.Bool(FlagSkipSignalReapply),
+		Reason:                 fmt.Sprintf("%v:%v", getCurrentUserFromEnv(), reason),
+		DecisionFinishEventID:  decisionFinishID,
+		RequestID:              uuid.New(),
+		SkipSignalReapply:      c.Bool(FlagSkipSignalReapply),
+		ReapplySignalNames:     c.StringSlice(FlagReapplySignalNames),
+		SkipReapplySignalNames: c.StringSlice(FlagSkipReapplySignalNames),
 	})
 	if err != nil {
 		ErrorAndExit("reset failed", err)
@@ -1467,15 +1601,17 @@ func processResets(c *cli.Context, domain string, wes chan types.WorkflowExecuti
 }
 
 type batchResetParamsType struct {
-	reason               string
-	skipCurrentOpen      bool
-	skipCurrentCompleted bool
-	nonDeterministicOnly bool
-	skipBaseNotCurrent   bool
-	dryRun               bool
-	resetType            string
-	decisionOffset       int
-	skipSignalReapply    bool
+	reason                 string
+	skipCurrentOpen        bool
+	skipCurrentCompleted   bool
+	nonDeterministicOnly   bool
+	skipBaseNotCurrent     bool
+	dryRun                 bool
+	resetType              string
+	decisionOffset         int
+	skipSignalReapply      bool
+	reapplySignalNames     []string
+	skipReapplySignalNames []string
 }
 
 // ResetInBatch resets workflow in batch
@@ -1506,15 +1642,17 @@ func ResetInBatch(c *cli.Context) {
 	}
 
 	batchResetParams := batchResetParamsType{
-		reason:               getRequiredOption(c, FlagReason),
-		skipCurrentOpen:      c.Bool(FlagSkipCurrentOpen),
-		skipCurrentCompleted: c.Bool(FlagSkipCurrentCompleted),
-		nonDeterministicOnly: c.Bool(FlagNonDeterministicOnly),
-		skipBaseNotCurrent:   c.Bool(FlagSkipBaseIsNotCurrent),
-		dryRun:               c.Bool(FlagDryRun),
-		resetType:            resetType,
-		decisionOffset:       decisionOffset,
-		skipSignalReapply:    c.Bool(FlagSkipSignalReapply),
+		reason:                 getRequiredOption(c, FlagReason),
+		skipCurrentOpen:        c.Bool(FlagSkipCurrentOpen),
+		skipCurrentCompleted:   c.Bool(FlagSkipCurrentCompleted),
+		nonDeterministicOnly:   c.Bool(FlagNonDeterministicOnly),
+		skipBaseNotCurrent:     c.Bool(FlagSkipBaseIsNotCurrent),
+		dryRun:                 c.Bool(FlagDryRun),
+		resetType:              resetType,
+		decisionOffset:         decisionOffset,
+		skipSignalReapply:      c.Bool(FlagSkipSignalReapply),
+		reapplySignalNames:     c.StringSlice(FlagReapplySignalNames),
+		skipReapplySignalNames: c.StringSlice(FlagSkipReapplySignalNames),
 	}
 
 	if inFileName == "" && query == "" {
@@ -1708,10 +1846,12 @@ func doReset(c *cli.Context, domain, wid, rid string, params batchResetParamsTyp
 				WorkflowID: wid,
 				RunID:      resetBaseRunID,
 			},
-			DecisionFinishEventID: decisionFinishID,
-			RequestID:             uuid.New(),
-			Reason:                fmt.Sprintf("%v:%v", getCurrentUserFromEnv(), params.reason),
-			SkipSignalReapply:     params.skipSignalReapply,
+			DecisionFinishEventID:  decisionFinishID,
+			RequestID:              uuid.New(),
+			Reason:                 fmt.Sprintf("%v:%v", getCurrentUserFromEnv(), params.reason),
+			SkipSignalReapply:      params.skipSignalReapply,
+			ReapplySignalNames:     params.reapplySignalNames,
+			SkipReapplySignalNames: params.skipReapplySignalNames,
 		})
 
 		if err != nil {
@@ -2047,6 +2187,176 @@ func CompleteActivity(c *cli.Context) {
 	}
 }
 
+// activityDescription is the output of DescribeActivity with decoded payloads and datetime instead of raw time
+type activityDescription struct {
+	ActivityID             string
+	ActivityType           *types.ActivityType
+	State                  *types.PendingActivityState
+	Attempt                int32
+	MaximumAttempts        int32   `json:",omitempty"`
+	ScheduledTimestamp     *string `json:",omitempty"`
+	NextRetryTimestamp     *string `json:",omitempty"`
+	LastStartedTimestamp   *string `json:",omitempty"`
+	LastHeartbeatTimestamp *string `json:",omitempty"`
+	HeartbeatDetails       *string `json:",omitempty"`
+	ExpirationTimestamp    *string `json:",omitempty"`
+	LastFailureReason      *string `json:",omitempty"`
+	LastFailureDetails     *string `json:",omitempty"`
+	LastWorkerIdentity     string  `json:",omitempty"`
+}
+
+// DescribeActivity prints the heartbeat details, attempts and last failure of a pending activity
+func DescribeActivity(c *cli.Context) {
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	wid := getRequiredOption(c, FlagWorkflowID)
+	rid := c.String(FlagRunID)
+	activityID := getRequiredOption(c, FlagActivityID)
+
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	frontendClient := cFactory.ServerFrontendClient(c)
+	resp, err := frontendClient.DescribeWorkflowExecution(ctx, &types.DescribeWorkflowExecutionRequest{
+		Domain: domain,
+		Execution: &types.WorkflowExecution{
+			WorkflowID: wid,
+			RunID:      rid,
+		},
+	})
+	if err != nil {
+		ErrorAndExit("Describe workflow execution failed", err)
+	}
+
+	for _, pa := range resp.PendingActivities {
+		if pa.GetActivityID() == activityID {
+			prettyPrintJSONObject(newActivityDescription(pa))
+			return
+		}
+	}
+	ErrorAndExit(fmt.Sprintf("Activity %v is not pending in workflow %v", activityID, wid), nil)
+}
+
+func newActivityDescription(pa *types.PendingActivityInfo) *activityDescription {
+	description := &activityDescription{
+		ActivityID:             pa.ActivityID,
+		ActivityType:           pa.ActivityType,
+		State:                  pa.State,
+		Attempt:                pa.Attempt,
+		MaximumAttempts:        pa.MaximumAttempts,
+		ScheduledTimestamp:     timestampPtrToStringPtr(pa.ScheduledTimestamp, false),
+		LastStartedTimestamp:   timestampPtrToStringPtr(pa.LastStartedTimestamp, false),
+		LastHeartbeatTimestamp: timestampPtrToStringPtr(pa.LastHeartbeatTimestamp, false),
+		HeartbeatDetails:       decodePayload(pa.HeartbeatDetails),
+		ExpirationTimestamp:    timestampPtrToStringPtr(pa.ExpirationTimestamp, false),
+		LastFailureReason:      pa.LastFailureReason,
+		LastFailureDetails:     decodePayload(pa.LastFailureDetails),
+		LastWorkerIdentity:     pa.LastWorkerIdentity,
+	}
+	// a scheduled activity which already has attempts is waiting for its retry timer,
+	// which fires at the scheduled time of the next attempt
+	if pa.GetState() == types.PendingActivityStateScheduled && pa.Attempt > 0 {
+		description.NextRetryTimestamp = description.ScheduledTimestamp
+	}
+	return description
+}
+
+// PendingActivityRow is a row of the activity list-pending output
+type PendingActivityRow struct {
+	WorkflowID    string `header:"Workflow ID"`
+	RunID         string `header:"Run ID"`
+	ActivityID    string `header:"Activity ID"`
+	ActivityType  string `header:"Activity Type"`
+	Attempt       int32  `header:"Attempt"`
+	Started       string `header:"Started"`
+	Age           string `header:"Age"`
+	LastHeartbeat string `header:"Last Heartbeat"`
+	Worker        string `header:"Worker"`
+}
+
+// ListPendingActivities prints the started activities of a workflow execution, or of all the executions matching a
+// list query, which are waiting for their completion. The activities completed asynchronously stay started until
+// their task token is used, so the oldest ones are the candidates for lost task tokens.
+func ListPendingActivities(c *cli.Context) {
+	serviceClient := cFactory.ServerFrontendClient(c)
+
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	minAge := c.Duration(FlagMinAge)
+	now := time.Now()
+
+	type pendingActivity struct {
+		startedTime int64
+		row         PendingActivityRow
+	}
+	var mu sync.Mutex
+	var pending []pendingActivity
+	describe := func(execution *types.WorkflowExecution) {
+		ctx, cancel := newContext(c)
+		defer cancel()
+		resp, err := serviceClient.DescribeWorkflowExecution(ctx, &types.DescribeWorkflowExecutionRequest{
+			Domain:    domain,
+			Execution: execution,
+		})
+		if err != nil {
+			if c.IsSet(FlagWorkflowID) {
+				ErrorAndExit("Describe workflow execution failed", err)
+			}
+			// the execution may have been deleted since it was listed
+			fmt.Fprintf(os.Stderr, "Failed to describe workflow %v, run %v: %v\n", execution.GetWorkflowID(), execution.GetRunID(), err)
+			return
+		}
+
+		for _, pa := range resp.PendingActivities {
+			if pa.GetState() != types.PendingActivityStateStarted {
+				continue
+			}
+			startedTime := common.Int64Default(pa.LastStartedTimestamp)
+			started := time.Unix(0, startedTime)
+			if now.Sub(started) < minAge {
+				continue
+			}
+			lastHeartbeat := "never"
+			if pa.GetLastHeartbeatTimestamp() > 0 {
+				lastHeartbeat = now.Sub(time.Unix(0, pa.GetLastHeartbeatTimestamp())).Round(time.Second).String() + " ago"
+			}
+			mu.Lock()
+			pending = append(pending, pendingActivity{
+				startedTime: startedTime,
+				row: PendingActivityRow{
+					WorkflowID:    resp.WorkflowExecutionInfo.GetExecution().GetWorkflowID(),
+					RunID:         resp.WorkflowExecutionInfo.GetExecution().GetRunID(),
+					ActivityID:    pa.GetActivityID(),
+					ActivityType:  pa.ActivityType.GetName(),
+					Attempt:       pa.GetAttempt(),
+					Started:       convertTime(startedTime, false),
+					Age:           now.Sub(started).Round(time.Second).String(),
+					LastHeartbeat: lastHeartbeat,
+					Worker:        pa.GetLastWorkerIdentity(),
+				},
+			})
+			mu.Unlock()
+		}
+	}
+
+	if c.IsSet(FlagWorkflowID) {
+		describe(&types.WorkflowExecution{
+			WorkflowID: c.String(FlagWorkflowID),
+			RunID:      c.String(FlagRunID),
+		})
+	} else {
+		scanWorkflowExecutionsInParallel(c, serviceClient, c.String(FlagListQuery), describe)
+	}
+
+	// the oldest activities first
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].startedTime < pending[j].startedTime
+	})
+	table := make([]PendingActivityRow, 0, len(pending))
+	for _, activity := range pending {
+		table = append(table, activity.row)
+	}
+	Render(c, table, RenderOptions{DefaultTemplate: templateTable, Color: true, Border: true})
+}
+
 // FailActivity fails an activity
 func FailActivity(c *cli.Context) {
 	domain := getRequiredGlobalOption(c, FlagDomain)
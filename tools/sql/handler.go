@@ -30,6 +30,7 @@ import (
 	"github.com/uber/cadence/common/config"
 	mysql_db "github.com/uber/cadence/common/persistence/sql/sqlplugin/mysql"
 	postgres_db "github.com/uber/cadence/common/persistence/sql/sqlplugin/postgres"
+	sqlite_db "github.com/uber/cadence/common/persistence/sql/sqlplugin/sqlite"
 	"github.com/uber/cadence/schema/mysql"
 	"github.com/uber/cadence/schema/postgres"
 	cliflag "github.com/uber/cadence/tools/common/flag"
@@ -42,8 +43,9 @@ func VerifyCompatibleVersion(
 	cfg config.Persistence,
 ) error {
 
+	// sqlite databases aren't versioned, they are created with the latest schema
 	ds, ok := cfg.DataStores[cfg.DefaultStore]
-	if ok && ds.SQL != nil {
+	if ok && ds.SQL != nil && ds.SQL.PluginName != sqlite_db.PluginName {
 		expectedVersion := mysql.Version
 		switch ds.SQL.PluginName {
 		case mysql_db.PluginName:
@@ -57,7 +59,7 @@ func VerifyCompatibleVersion(
 		}
 	}
 	ds, ok = cfg.DataStores[cfg.VisibilityStore]
-	if ok && ds.SQL != nil {
+	if ok && ds.SQL != nil && ds.SQL.PluginName != sqlite_db.PluginName {
 		expectedVersion := mysql.VisibilityVersion
 		switch ds.SQL.PluginName {
 		case mysql_db.PluginName: